@@ -0,0 +1,165 @@
+// services/chat-service/cmd/hubbench/main.go
+//
+// hubbench measures WebSocket hub throughput without any network: it registers fake clients,
+// spreads them over rooms and publishes to random rooms while other clients join and leave.
+// Sharding only pays off with several cores. Compare shard counts with e.g.
+//
+//	go run ./cmd/hubbench -clients 50000 -rooms 2000 -shards 1,8,32,128
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"math/rand"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/services/chat-service/internal/server"
+)
+
+type result struct {
+	shards    int
+	published int64
+	delivered int64
+	churn     int64
+	elapsed   time.Duration
+}
+
+func main() {
+	var (
+		clients    = flag.Int("clients", 50000, "number of connected clients")
+		rooms      = flag.Int("rooms", 2000, "number of rooms clients are spread over")
+		shardList  = flag.String("shards", "1,32", "comma separated shard counts to compare")
+		publishers = flag.Int("publishers", runtime.NumCPU()*2, "goroutines publishing messages")
+		churners   = flag.Int("churners", runtime.NumCPU(), "goroutines joining and leaving rooms")
+		duration   = flag.Duration("duration", 5*time.Second, "how long each run lasts")
+	)
+	flag.Parse()
+
+	// The hub logs every join and leave, which would dominate the measurement
+	log.SetOutput(io.Discard)
+
+	fmt.Printf("%d clients in %d rooms, %d publishers, %d churners, %s per run\n\n",
+		*clients, *rooms, *publishers, *churners, *duration)
+	fmt.Printf("%8s %14s %16s %14s\n", "shards", "publishes/s", "deliveries/s", "joins/s")
+
+	for _, field := range strings.Split(*shardList, ",") {
+		shards, err := strconv.Atoi(strings.TrimSpace(field))
+		if err != nil || shards <= 0 {
+			fmt.Printf("invalid shard count %q\n", field)
+			return
+		}
+
+		r := run(shards, *clients, *rooms, *publishers, *churners, *duration)
+		seconds := r.elapsed.Seconds()
+		fmt.Printf("%8d %14.0f %16.0f %14.0f\n", r.shards,
+			float64(r.published)/seconds, float64(r.delivered)/seconds, float64(r.churn)/seconds)
+	}
+}
+
+func run(shards, clientCount, roomCount, publishers, churners int, duration time.Duration) result {
+	hub := server.NewWebSocketHub(shards)
+	roomIDs := make([]string, roomCount)
+	for i := range roomIDs {
+		roomIDs[i] = fmt.Sprintf("room-%d", i)
+	}
+
+	var delivered int64
+	var drain sync.WaitGroup
+	clients := make([]*server.Client, clientCount)
+	for i := range clients {
+		client := &server.Client{
+			Send:     make(chan *websocket.PreparedMessage, 256),
+			Hub:      hub,
+			UserID:   fmt.Sprintf("user-%d", i),
+			Username: fmt.Sprintf("user-%d", i),
+			Rooms:    make(map[string]bool),
+		}
+		hub.RegisterClient(client)
+		hub.JoinRoom(client, roomIDs[i%roomCount])
+		clients[i] = client
+
+		// Stands in for the client's write pump
+		drain.Add(1)
+		go func() {
+			defer drain.Done()
+			for range client.Send {
+				atomic.AddInt64(&delivered, 1)
+			}
+		}()
+	}
+	atomic.StoreInt64(&delivered, 0)
+
+	var published, churn int64
+	stop := make(chan struct{})
+	var workers sync.WaitGroup
+	message := []byte(`{"type":"message","content":"hello"}`)
+
+	for i := 0; i < publishers; i++ {
+		workers.Add(1)
+		go func(seed int64) {
+			defer workers.Done()
+			rng := rand.New(rand.NewSource(seed))
+			for {
+				select {
+				case <-stop:
+					return
+				default:
+				}
+				hub.BroadcastToRoom(roomIDs[rng.Intn(roomCount)], message)
+				atomic.AddInt64(&published, 1)
+			}
+		}(int64(i))
+	}
+
+	for i := 0; i < churners; i++ {
+		workers.Add(1)
+		go func(seed int64) {
+			defer workers.Done()
+			rng := rand.New(rand.NewSource(seed))
+			for {
+				select {
+				case <-stop:
+					return
+				default:
+				}
+				// Each churner owns its own clients, and never takes one out of its home room
+				index := (rng.Intn(clientCount/churners)*churners + int(seed)) % clientCount
+				room := rng.Intn(roomCount)
+				if room == index%roomCount {
+					continue
+				}
+				hub.JoinRoom(clients[index], roomIDs[room])
+				hub.LeaveRoom(clients[index], roomIDs[room])
+				atomic.AddInt64(&churn, 1)
+			}
+		}(int64(publishers + i))
+	}
+
+	start := time.Now()
+	time.Sleep(duration)
+	close(stop)
+	workers.Wait()
+	elapsed := time.Since(start)
+
+	for _, client := range clients {
+		hub.UnregisterClient(client)
+	}
+	drain.Wait()
+
+	return result{
+		shards:    shards,
+		published: published,
+		delivered: atomic.LoadInt64(&delivered),
+		churn:     churn,
+		elapsed:   elapsed,
+	}
+}
@@ -0,0 +1,134 @@
+// services/chat-service/cmd/loadgen/chat.go
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// chatMessage is the part of the messages clients send and receive loadgen looks at
+type chatMessage struct {
+	Type       string `json:"type"`
+	ChatroomID string `json:"chatroom_id,omitempty"`
+	UserID     string `json:"user_id,omitempty"`
+	Content    string `json:"content,omitempty"`
+}
+
+// chatClient joins a room and sends messages to it, timing how long each takes to come back
+// from the hub, which delivers messages to their sender too
+type chatClient struct {
+	index  int
+	userID string
+	room   string
+	stats  *recorder
+
+	mu      sync.Mutex
+	pending map[string]time.Time // sent messages by content
+}
+
+func (c *chatClient) run(ctx context.Context, endpoint string, interval time.Duration) {
+	target, err := url.Parse(endpoint)
+	if err != nil {
+		c.stats.observe("ws_connect", 0, err)
+		return
+	}
+	query := target.Query()
+	query.Set("user_id", c.userID)
+	target.RawQuery = query.Encode()
+
+	start := time.Now()
+	conn, _, err := websocket.DefaultDialer.DialContext(ctx, target.String(), nil)
+	if err != nil {
+		if ctx.Err() == nil {
+			c.stats.observe("ws_connect", 0, err)
+		}
+		return
+	}
+	c.stats.observe("ws_connect", time.Since(start), nil)
+
+	c.pending = make(map[string]time.Time)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		c.read(conn)
+	}()
+	// The reader is done with the stats before the report is printed
+	defer func() {
+		conn.Close()
+		<-done
+	}()
+
+	if err := conn.WriteJSON(chatMessage{Type: "join", ChatroomID: c.room}); err != nil {
+		c.stats.observe("chat_join", 0, err)
+		return
+	}
+
+	// Clients start at random points of the interval, so messages don't arrive in waves
+	sleep(ctx, time.Duration(rand.Int63n(int64(interval))))
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for seq := 0; ctx.Err() == nil; seq++ {
+		content := fmt.Sprintf("loadgen %d %d", c.index, seq)
+		c.mu.Lock()
+		c.pending[content] = time.Now()
+		c.mu.Unlock()
+
+		if err := conn.WriteJSON(chatMessage{Type: "message", ChatroomID: c.room, Content: content}); err != nil {
+			c.stats.observe("chat_roundtrip", 0, err)
+			return
+		}
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+		case <-done:
+			return
+		}
+	}
+
+	// Give the last messages a moment to come back before counting them lost
+	conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""))
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+	}
+
+	c.mu.Lock()
+	c.stats.count("chat_lost", int64(len(c.pending)))
+	c.mu.Unlock()
+}
+
+// read receives until the connection closes
+func (c *chatClient) read(conn *websocket.Conn) {
+	received := int64(0)
+	defer func() { c.stats.count("chat_received", received) }()
+
+	for {
+		_, data, err := conn.ReadMessage()
+		if err != nil {
+			return
+		}
+
+		var message chatMessage
+		if err := json.Unmarshal(data, &message); err != nil || message.Type != "message" {
+			continue
+		}
+		received++
+
+		c.mu.Lock()
+		sentAt, ok := c.pending[message.Content]
+		delete(c.pending, message.Content)
+		c.mu.Unlock()
+		if ok {
+			c.stats.observe("chat_roundtrip", time.Since(sentAt), nil)
+		}
+	}
+}
@@ -0,0 +1,93 @@
+// services/chat-service/cmd/loadgen/latency.go
+package main
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"sync"
+	"time"
+)
+
+// operation is what the latencies of one step of the load were measured for
+type operation struct {
+	latencies []time.Duration
+	errors    int64
+}
+
+// recorder collects the latencies of every operation the load runs
+type recorder struct {
+	mu         sync.Mutex
+	operations map[string]*operation
+	order      []string // operations in the order they were first seen
+	counters   map[string]int64
+}
+
+func newRecorder() *recorder {
+	return &recorder{operations: make(map[string]*operation), counters: make(map[string]int64)}
+}
+
+func (r *recorder) operation(name string) *operation {
+	op, ok := r.operations[name]
+	if !ok {
+		op = &operation{}
+		r.operations[name] = op
+		r.order = append(r.order, name)
+	}
+	return op
+}
+
+// observe records an operation that took latency, or failed when err isn't nil
+func (r *recorder) observe(name string, latency time.Duration, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	op := r.operation(name)
+	if err != nil {
+		op.errors++
+		return
+	}
+	op.latencies = append(op.latencies, latency)
+}
+
+// count adds to a counter that has no latency, e.g. messages received
+func (r *recorder) count(name string, n int64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.counters[name] += n
+}
+
+func (r *recorder) report(w io.Writer, elapsed time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	fmt.Fprintf(w, "%-16s %8s %8s %10s %10s %10s %10s %10s\n", "operation", "ok", "errors", "ops/s", "p50", "p90", "p99", "max")
+	for _, name := range r.order {
+		op := r.operations[name]
+		slices.Sort(op.latencies)
+		fmt.Fprintf(w, "%-16s %8d %8d %10.1f %10s %10s %10s %10s\n", name, len(op.latencies), op.errors,
+			float64(len(op.latencies))/elapsed.Seconds(),
+			percentile(op.latencies, 50), percentile(op.latencies, 90), percentile(op.latencies, 99), percentile(op.latencies, 100))
+	}
+
+	if len(r.counters) > 0 {
+		fmt.Fprintln(w)
+	}
+	names := make([]string, 0, len(r.counters))
+	for name := range r.counters {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	for _, name := range names {
+		fmt.Fprintf(w, "%-16s %8d %10.1f/s\n", name, r.counters[name], float64(r.counters[name])/elapsed.Seconds())
+	}
+}
+
+// percentile picks the nearest rank from sorted latencies
+func percentile(sorted []time.Duration, p int) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := (len(sorted)*p + 99) / 100
+	return sorted[max(rank, 1)-1].Round(10 * time.Microsecond)
+}
@@ -0,0 +1,135 @@
+// services/chat-service/cmd/loadgen/main.go
+//
+// loadgen puts running services under load over the network: streams go live and end through
+// the stream management service's RTMP callbacks, like a media server would drive them, while
+// chat clients connected over WebSocket join rooms and send messages. It reports latency
+// percentiles of every step, so runs before and after a change can be compared, e.g.
+//
+//	go run ./cmd/loadgen -stream-keys @keys.txt -streams 50 -users @users.txt -chat-clients 2000
+//
+// Stream keys and users must exist, the services check them with the user service. Each
+// stream uses its own key. Chat clients share the users round robin, so MAX_CONNECTIONS_PER_USER
+// and MAX_CONNECTIONS_PER_IP of the chat service must allow as many connections.
+package main
+
+import (
+	"bufio"
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+)
+
+func main() {
+	var (
+		streamURL    = flag.String("stream-url", "http://localhost:8084", "stream management service to send RTMP callbacks to")
+		streamKeys   = flag.String("stream-keys", "", "comma separated stream keys, or @file with one per line")
+		streams      = flag.Int("streams", 10, "streams going live and ending at the same time")
+		hold         = flag.Duration("hold", 5*time.Second, "how long a stream stays live before it ends")
+		mediaServer  = flag.String("media-server", "", "media server ID callbacks are signed as, unsigned when empty")
+		secret       = flag.String("secret", "", "secret of the media server in RTMP_CALLBACK_SECRETS")
+		chatURL      = flag.String("chat-url", "ws://localhost:8081/ws", "chat service WebSocket endpoint")
+		users        = flag.String("users", "", "comma separated user IDs chat clients connect as, or @file with one per line")
+		chatClients  = flag.Int("chat-clients", 100, "chat clients connected at the same time")
+		rooms        = flag.String("rooms", "loadgen", "comma separated chatroom IDs clients are spread over")
+		sendInterval = flag.Duration("send-interval", time.Second, "how often each chat client sends a message")
+		duration     = flag.Duration("duration", time.Minute, "how long the run lasts")
+	)
+	flag.Parse()
+
+	keys, err := readList(*streamKeys)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "invalid -stream-keys: %v\n", err)
+		os.Exit(2)
+	}
+	userIDs, err := readList(*users)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "invalid -users: %v\n", err)
+		os.Exit(2)
+	}
+	roomIDs, _ := readList(*rooms)
+
+	if len(keys) < *streams {
+		fmt.Fprintf(os.Stderr, "%d streams need as many stream keys, got %d\n", *streams, len(keys))
+		os.Exit(2)
+	}
+	if *chatClients > 0 && (len(userIDs) == 0 || len(roomIDs) == 0) {
+		fmt.Fprintln(os.Stderr, "chat clients need -users and -rooms")
+		os.Exit(2)
+	}
+	if *sendInterval <= 0 {
+		fmt.Fprintln(os.Stderr, "-send-interval must be positive")
+		os.Exit(2)
+	}
+
+	// Stops on Ctrl-C too, and still reports what was measured
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+	ctx, cancelRun := context.WithTimeout(ctx, *duration)
+	defer cancelRun()
+
+	fmt.Printf("%d streams held %s, %d chat clients in %d rooms sending every %s, for %s\n\n",
+		*streams, *hold, *chatClients, len(roomIDs), *sendInterval, *duration)
+
+	stats := newRecorder()
+	start := time.Now()
+	var wg sync.WaitGroup
+
+	callbacks := &rtmpClient{baseURL: strings.TrimSuffix(*streamURL, "/"), serverID: *mediaServer, secret: *secret}
+	for i := 0; i < *streams; i++ {
+		wg.Add(1)
+		go func(worker int) {
+			defer wg.Done()
+			runStream(ctx, callbacks, stats, worker, keys[worker], *hold)
+		}(i)
+	}
+
+	for i := 0; i < *chatClients; i++ {
+		wg.Add(1)
+		go func(index int) {
+			defer wg.Done()
+			client := &chatClient{
+				index:  index,
+				userID: userIDs[index%len(userIDs)],
+				room:   roomIDs[index%len(roomIDs)],
+				stats:  stats,
+			}
+			client.run(ctx, *chatURL, *sendInterval)
+		}(i)
+	}
+
+	wg.Wait()
+	stats.report(os.Stdout, time.Since(start))
+}
+
+// readList splits a comma separated flag, or reads the file named after an @ line by line
+func readList(value string) ([]string, error) {
+	var items []string
+	if path, ok := strings.CutPrefix(value, "@"); ok {
+		file, err := os.Open(path)
+		if err != nil {
+			return nil, err
+		}
+		defer file.Close()
+
+		scanner := bufio.NewScanner(file)
+		for scanner.Scan() {
+			if line := strings.TrimSpace(scanner.Text()); line != "" && !strings.HasPrefix(line, "#") {
+				items = append(items, line)
+			}
+		}
+		return items, scanner.Err()
+	}
+
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items, nil
+}
@@ -0,0 +1,115 @@
+// services/chat-service/cmd/loadgen/streams.go
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// rtmpClient sends the callbacks a media server sends to the stream management service
+type rtmpClient struct {
+	baseURL  string
+	serverID string
+	secret   string
+	http     http.Client
+}
+
+// callback posts a callback, signed the way the stream management service's
+// rtmp_signature.go checks when the client has a media server
+func (c *rtmpClient) callback(ctx context.Context, path string, body map[string]string) error {
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+path, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	if c.serverID != "" {
+		timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+		mac := hmac.New(sha256.New, []byte(c.secret))
+		fmt.Fprintf(mac, "%s\n%s\n%s\n", http.MethodPost, req.URL.RequestURI(), timestamp)
+		mac.Write(payload)
+
+		req.Header.Set("X-Media-Server", c.serverID)
+		req.Header.Set("X-Timestamp", timestamp)
+		req.Header.Set("X-Signature", hex.EncodeToString(mac.Sum(nil)))
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		var response struct {
+			Error string `json:"error"`
+		}
+		json.NewDecoder(resp.Body).Decode(&response)
+		return fmt.Errorf("POST %s answered %d: %s", path, resp.StatusCode, response.Error)
+	}
+	io.Copy(io.Discard, resp.Body)
+	return nil
+}
+
+// runStream takes a stream live and ends it again until ctx is done, each cycle as its own
+// publishing connection
+func runStream(ctx context.Context, callbacks *rtmpClient, stats *recorder, worker int, streamKey string, hold time.Duration) {
+	for cycle := 0; ctx.Err() == nil; cycle++ {
+		body := map[string]string{
+			"name":      streamKey,
+			"addr":      "127.0.0.1",
+			"app":       "live",
+			"client_id": fmt.Sprintf("loadgen-%d-%d-%d", worker, cycle, time.Now().UnixNano()),
+		}
+
+		if !timed(ctx, stats, "rtmp_auth", func() error { return callbacks.callback(ctx, "/rtmp/auth", body) }) {
+			sleep(ctx, hold)
+			continue
+		}
+		if !timed(ctx, stats, "rtmp_started", func() error { return callbacks.callback(ctx, "/rtmp/started", body) }) {
+			sleep(ctx, hold)
+			continue
+		}
+
+		sleep(ctx, hold)
+
+		// Runs after the run ended too, so no stream is left live
+		endCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 30*time.Second)
+		timed(endCtx, stats, "rtmp_ended", func() error { return callbacks.callback(endCtx, "/rtmp/ended", body) })
+		cancel()
+	}
+}
+
+// timed records how long call took. Calls cut short by the end of the run aren't counted.
+func timed(ctx context.Context, stats *recorder, name string, call func() error) bool {
+	start := time.Now()
+	err := call()
+	if err != nil && ctx.Err() != nil {
+		return false
+	}
+	stats.observe(name, time.Since(start), err)
+	return err == nil
+}
+
+func sleep(ctx context.Context, d time.Duration) {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+	case <-ctx.Done():
+	}
+}
@@ -10,6 +10,7 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
 	"time"
 
@@ -18,8 +19,12 @@ import (
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/dynamodb"
 	"github.com/gorilla/mux"
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/reflection"
+	"google.golang.org/grpc/status"
 
 	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/services/chat-service/internal/config"
 	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/services/chat-service/internal/migration"
@@ -28,13 +33,19 @@ import (
 	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/services/chat-service/internal/service"
 	chatpb "github.com/Saoudyahya/Live-Streaming-Platform-Architecture/services/chat-service/pkg/proto/chat"
 	userpb "github.com/Saoudyahya/Live-Streaming-Platform-Architecture/services/chat-service/pkg/proto/user"
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/shared/go/pkg/apperrors"
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/shared/go/pkg/discovery"
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/shared/go/pkg/eventbus"
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/shared/go/pkg/events"
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/shared/go/pkg/identity"
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/shared/go/pkg/preflight"
 )
 
 // Enhanced cleanup functionality
 func forceCleanupTables(client *dynamodb.DynamoDB, cfg *config.DynamoDBConfig) error {
 	log.Println("🧹 Force cleaning up all tables...")
 
-	tables := []string{cfg.ChatroomTable, cfg.MessageTable}
+	tables := []string{cfg.ChatroomTable, cfg.MessageTable, cfg.AutomodTable, cfg.RollupTable, cfg.VODCommentTable, cfg.DedupeTable}
 
 	for _, tableName := range tables {
 		log.Printf("Attempting to delete table: %s", tableName)
@@ -123,14 +134,62 @@ func waitForDynamoDB(client *dynamodb.DynamoDB, maxRetries int) error {
 	return fmt.Errorf("DynamoDB not ready after %d attempts", maxRetries)
 }
 
+// buildPreflightChecks lists the dependencies verified at startup. The chat service can't
+// serve any request without them, so every check is required.
+func buildPreflightChecks(cfg *config.Config, client *dynamodb.DynamoDB, userClient userpb.UserServiceClient) []preflight.Check {
+	return []preflight.Check{
+		{
+			Name:     "dynamodb",
+			Feature:  "chatrooms and messages",
+			Required: true,
+			Hint:     "provision the tables listed by --plan, or start without --skip-tables to create them",
+			Run: func() error {
+				if problems := migration.NewDynamoDBMigrator(client, &cfg.DynamoDB).VerifyTables(); len(problems) > 0 {
+					return fmt.Errorf("%s", strings.Join(problems, "; "))
+				}
+				return nil
+			},
+		},
+		{
+			Name:     "redis",
+			Feature:  "message cache and presence",
+			Required: true,
+			Hint:     fmt.Sprintf("check that Redis is running at %s (REDIS_ADDRESS)", cfg.Redis.Address),
+			Run: func() error {
+				return repository.PingRedis(cfg.Redis)
+			},
+		},
+		{
+			Name:     "user-service",
+			Feature:  "user lookups",
+			Required: true,
+			Hint:     fmt.Sprintf("check that the user service is reachable at %s (USER_SERVICE_ADDRESS)", cfg.UserService.Address),
+			Run: func() error {
+				ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+				defer cancel()
+
+				// Any answer, even NotFound, means the service is up
+				_, err := userClient.GetUser(ctx, &userpb.GetUserRequest{UserId: "preflight"})
+				switch status.Code(err) {
+				case codes.Unavailable, codes.DeadlineExceeded:
+					return fmt.Errorf("user service did not respond: %w", err)
+				}
+				return nil
+			},
+		},
+	}
+}
+
 func main() {
 	// Parse command line flags
 	var (
 		forceCleanup = flag.Bool("force-cleanup", false, "Force delete and recreate all tables")
 		cleanup      = flag.Bool("cleanup", false, "Delete existing tables before starting")
 		listOnly     = flag.Bool("list-tables", false, "List all tables and exit")
-		skipTables   = flag.Bool("skip-tables", false, "Skip table creation/migration")
+		skipTables   = flag.Bool("skip-tables", false, "Skip table creation/migration and only verify the existing tables")
 		verbose      = flag.Bool("verbose", false, "Enable verbose logging")
+		plan         = flag.Bool("plan", false, "Print the DynamoDB tables this service requires and exit")
+		planFormat   = flag.String("plan-format", "json", "Output format for --plan: json or cloudformation")
 	)
 	flag.Parse()
 
@@ -155,6 +214,17 @@ func main() {
 
 	// Load configuration
 	cfg := config.Load()
+
+	// Plan mode only describes the required tables, it never connects to DynamoDB
+	if *plan {
+		output, err := migration.NewDynamoDBMigrator(nil, &cfg.DynamoDB).Plan().Render(*planFormat)
+		if err != nil {
+			log.Fatalf("❌ Failed to render plan: %v", err)
+		}
+		fmt.Println(string(output))
+		return
+	}
+
 	log.Printf("📁 Configuration loaded: Region=%s, Tables=[%s, %s]",
 		cfg.DynamoDB.Region, cfg.DynamoDB.ChatroomTable, cfg.DynamoDB.MessageTable)
 
@@ -231,6 +301,39 @@ func main() {
 		return
 	}
 
+	// The user a request acts for comes in from other services and is passed on to the user
+	// service along with the request ID
+	identities := identity.NewPropagator(cfg.Server.IdentitySecret)
+	if !identities.Signed() {
+		log.Println("⚠️ IDENTITY_PROPAGATION_SECRET is not set, users passed in by other services are looked up again instead of trusted and service-only endpoints reject every call")
+	}
+
+	// Initialize user service client
+	log.Printf("🔗 Connecting to user service at %s...", cfg.UserService.Address)
+	userConn, err := grpc.Dial(discovery.Target(cfg.UserService.Address), append(discovery.DialOptions(discovery.Config{
+		ConsulAddr:      cfg.UserService.ConsulAddr,
+		ConsulToken:     cfg.UserService.ConsulToken,
+		RefreshInterval: cfg.UserService.RefreshInterval,
+		SubsetSize:      cfg.UserService.SubsetSize,
+		HealthCheck:     cfg.UserService.HealthCheck,
+	}), grpc.WithInsecure(), grpc.WithChainUnaryInterceptor(identities.UnaryClientInterceptor()))...)
+	if err != nil {
+		log.Fatalf("❌ Failed to connect to user service: %v", err)
+	}
+	defer userConn.Close()
+
+	userClient := userpb.NewUserServiceClient(userConn)
+
+	// Verify dependencies up front instead of failing on the first request
+	report := preflight.Run(cfg.Server.PreflightMode, buildPreflightChecks(cfg, dynamoClient, userClient))
+	if err := report.Err(); err != nil {
+		if cfg.Server.PreflightMode == preflight.ModeStrict {
+			log.Fatalf("❌ %v", err)
+		}
+		log.Printf("⚠️  %v", err)
+		log.Println("⚠️  Continuing because PREFLIGHT_MODE is not strict")
+	}
+
 	// Initialize repositories
 	log.Println("🔧 Initializing repositories...")
 	dynamoRepo, err := repository.NewDynamoDBRepository(cfg.DynamoDB)
@@ -243,24 +346,33 @@ func main() {
 		log.Fatalf("❌ Failed to initialize Redis repository: %v", err)
 	}
 
-	// Initialize user service client
-	log.Printf("🔗 Connecting to user service at %s...", cfg.UserService.Address)
-	userConn, err := grpc.Dial(cfg.UserService.Address, grpc.WithInsecure())
-	if err != nil {
-		log.Fatalf("❌ Failed to connect to user service: %v", err)
-	}
-	defer userConn.Close()
-
-	userClient := userpb.NewUserServiceClient(userConn)
-
 	// Initialize chat service
 	log.Println("💬 Initializing chat service...")
-	chatService := service.NewChatService(dynamoRepo, redisRepo, userClient)
+	automod := service.NewAutomod(dynamoRepo, redisRepo, cfg.Automod.CacheTTL)
+	automodCtx, stopAutomod := context.WithCancel(context.Background())
+	defer stopAutomod()
+	go automod.Run(automodCtx)
+	// Activity is summed into hourly rollups for broadcaster dashboards
+	rollups := service.NewChatRollups(cfg.Rollups, dynamoRepo, redisRepo)
+	rollupCtx, stopRollups := context.WithCancel(context.Background())
+	rollupsDone := make(chan struct{})
+	go func() {
+		rollups.Run(rollupCtx)
+		close(rollupsDone)
+	}()
+	// Old rollups are downsampled and their chatters anonymized
+	retention := service.NewRollupRetention(cfg.Retention, dynamoRepo)
+	retentionCtx, stopRetention := context.WithCancel(context.Background())
+	defer stopRetention()
+	if cfg.Retention.Interval > 0 {
+		go retention.Run(retentionCtx)
+	}
+	chatService := service.NewChatService(dynamoRepo, redisRepo, userClient, identities, automod, rollups)
 
 	// Create gRPC server with enhanced setup
 	log.Println("🔧 Setting up gRPC server with reflection...")
 	grpcServer := grpc.NewServer(
-		grpc.UnaryInterceptor(server.LoggingInterceptor),
+		grpc.ChainUnaryInterceptor(identities.UnaryServerInterceptor(), server.LoggingInterceptor, apperrors.UnaryServerInterceptor()),
 		// Add any additional interceptors here if needed
 		grpc.MaxRecvMsgSize(4*1024*1024), // 4MB max message size
 		grpc.MaxSendMsgSize(4*1024*1024), // 4MB max message size
@@ -275,16 +387,78 @@ func main() {
 
 	// Create WebSocket hub
 	log.Println("🌐 Setting up WebSocket hub...")
-	wsHub := server.NewWebSocketHub()
+	wsHub := server.NewWebSocketHub(cfg.WebSocket.HubShards)
+	squadRouter := service.NewSquadRouter(redisRepo)
+	wsHub.SetRouter(squadRouter)
+	wsHub.SetMessageFilter(automod)
+	wsHub.AddMessageObserver(rollups)
+	wsHub.AddMessageObserver(chatService.Projection())
+
+	// Messages are delivered at once and scored afterwards, flagged ones are taken back
+	moderationCtx, stopModeration := context.WithCancel(context.Background())
+	defer stopModeration()
+	if cfg.Moderation.ScorerURL != "" {
+		scorer := service.NewHTTPMessageScorer(cfg.Moderation.ScorerURL, cfg.Moderation.Timeout)
+		moderation := service.NewModerationPipeline(cfg.Moderation, scorer, wsHub, dynamoRepo, redisRepo, rollups)
+		moderation.Start(moderationCtx)
+		chatService.SetModeration(moderation)
+		wsHub.AddMessageObserver(moderation)
+		log.Printf("🛡️  Async moderation enabled, threshold %.2f", cfg.Moderation.Threshold)
+	}
+	if cfg.WebSocket.JoinSnapshotMessages > 0 {
+		wsHub.SetSnapshotter(service.NewJoinSnapshotter(cfg.WebSocket.JoinSnapshotMessages, chatService))
+	}
 	go wsHub.Run()
 
 	// Initialize WebSocket handler
-	wsHandler := service.NewWebSocketHandler(chatService, wsHub, userClient)
+	wsHandler := service.NewWebSocketHandler(cfg.WebSocket, chatService, wsHub, userClient, identities)
+	alertHandler := service.NewStreamAlertHandler(wsHub, dynamoRepo)
+	raidHandler := service.NewStreamRaidHandler(wsHub)
+
+	// Events of the stream management service are taken off the event bus
+	eventsCtx, stopEvents := context.WithCancel(context.Background())
+	eventsDone := make(chan struct{})
+	if cfg.Events.NATSURL == "" {
+		close(eventsDone)
+	} else {
+		natsConn, js, err := connectEventBus(cfg.Events)
+		if err != nil {
+			log.Fatalf("❌ Failed to connect to the event bus: %v", err)
+		}
+		defer natsConn.Close()
+
+		deduper := events.NewDynamoDBDeduper(dynamoClient, cfg.DynamoDB.DedupeTable, cfg.Events.ConsumerName, cfg.Events.DedupeLease, cfg.Events.DedupeRetention)
+		streamEvents, err := service.NewStreamEventConsumer(js, cfg.Events.ConsumerName, deduper)
+		if err != nil {
+			log.Fatalf("❌ Failed to create stream event consumer: %v", err)
+		}
+		streamEvents.Handle("stream_health_alert", alertHandler.HandleAlertEvent)
+		streamEvents.Handle("raid", raidHandler.HandleRaidEvent)
+		go func() {
+			defer close(eventsDone)
+			if err := streamEvents.Run(eventsCtx); err != nil {
+				log.Printf("❌ Stopped consuming stream events: %v", err)
+			}
+		}()
+	}
 
 	// Setup HTTP server for WebSocket connections
 	log.Println("🔧 Setting up HTTP server...")
 	router := mux.NewRouter()
+	router.Use(identities.Middleware)
 	router.HandleFunc("/ws", wsHandler.HandleWebSocket)
+	// Endpoints the stream management service pushes to
+	internal := server.RequireService(identities)
+	router.Handle("/squads/{id}/route", internal(http.HandlerFunc(squadRouter.HandlePutRoute))).Methods(http.MethodPut)
+	router.Handle("/squads/{id}/route", internal(http.HandlerFunc(squadRouter.HandleDeleteRoute))).Methods(http.MethodDelete)
+	router.Handle("/streams/{id}/alerts", internal(http.HandlerFunc(alertHandler.HandlePostAlert))).Methods(http.MethodPost)
+	router.Handle("/streams/{id}/raid", internal(http.HandlerFunc(raidHandler.HandlePostRaid))).Methods(http.MethodPost)
+	router.HandleFunc("/automod/dictionaries/{scope}", automod.HandleGetDictionary).Methods(http.MethodGet)
+	trustAndSafety := server.RequireRole(identities, identity.RoleTrustAndSafety, identity.RoleAdmin)
+	router.Handle("/automod/dictionaries/{scope}", trustAndSafety(http.HandlerFunc(automod.HandlePutDictionary))).Methods(http.MethodPut)
+	router.Handle("/chatrooms/{id}/activity", chatService.RequireChatroomOwner(http.HandlerFunc(rollups.HandleGetActivity))).Methods(http.MethodGet)
+	admins := server.RequireRole(identities, identity.RoleAdmin)
+	router.Handle("/retention/report", admins(http.HandlerFunc(retention.HandleGetReport))).Methods(http.MethodGet)
 	router.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Content-Type", "application/json")
 		w.WriteHeader(http.StatusOK)
@@ -344,7 +518,53 @@ func main() {
 	}
 
 	grpcServer.GracefulStop()
+	stopEvents()
+	<-eventsDone
 	wsHub.Close()
 
+	// Flush the activity not written yet
+	stopRollups()
+	<-rollupsDone
+
 	log.Println("✅ Servers stopped gracefully")
 }
+
+// connectEventBus connects to NATS, creating the chat domain's stream dead letters go to when
+// configured to. The connection keeps reconnecting, to a server that isn't up yet too.
+func connectEventBus(cfg config.EventsConfig) (*nats.Conn, jetstream.JetStream, error) {
+	opts := []nats.Option{
+		nats.Name("chat-service"),
+		nats.RetryOnFailedConnect(true),
+		nats.MaxReconnects(-1),
+		nats.DisconnectErrHandler(func(_ *nats.Conn, err error) {
+			log.Printf("⚠️  Disconnected from NATS: %v", err)
+		}),
+		nats.ReconnectHandler(func(conn *nats.Conn) {
+			log.Printf("🔌 Reconnected to NATS at %s", conn.ConnectedUrl())
+		}),
+	}
+	if cfg.NATSCredentialsFile != "" {
+		opts = append(opts, nats.UserCredentials(cfg.NATSCredentialsFile))
+	}
+
+	conn, err := nats.Connect(cfg.NATSURL, opts...)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to connect to NATS at %s: %w", cfg.NATSURL, err)
+	}
+	js, err := jetstream.New(conn)
+	if err != nil {
+		conn.Close()
+		return nil, nil, fmt.Errorf("failed to set up JetStream: %w", err)
+	}
+
+	if cfg.CreateStream {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		if _, err := eventbus.EnsureStream(ctx, js, eventbus.DomainChat, eventbus.StreamOptions{}); err != nil {
+			conn.Close()
+			return nil, nil, err
+		}
+	}
+
+	return conn, js, nil
+}
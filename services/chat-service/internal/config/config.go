@@ -3,6 +3,9 @@ package config
 
 import (
 	"os"
+	"strconv"
+	"strings"
+	"time"
 )
 
 type Config struct {
@@ -10,19 +13,40 @@ type Config struct {
 	DynamoDB    DynamoDBConfig
 	Redis       RedisConfig
 	UserService UserServiceConfig
+	WebSocket   WebSocketConfig
+	Automod     AutomodConfig
+	Moderation  ModerationConfig
+	Rollups     RollupConfig
+	Retention   RetentionConfig
+	Events      EventsConfig
 }
 
 type ServerConfig struct {
-	GRPCPort string
-	HTTPPort string
+	GRPCPort      string
+	HTTPPort      string
+	PreflightMode string // strict, degrade or off
+
+	// IdentitySecret signs the users passed between services, those passed in are only
+	// trusted when signed with it. Without it every user passed in is trusted.
+	IdentitySecret string
 }
 
 type DynamoDBConfig struct {
 	Region          string
 	ChatroomTable   string
 	MessageTable    string
+	AutomodTable    string
+	RollupTable     string
+	VODCommentTable string
+	DedupeTable     string // events the stream event consumer handled
 	AccessKeyID     string
 	SecretAccessKey string
+
+	// Global tables, reads go to Region first and fall back to the other regions in order.
+	// Writing locally lets concurrent changes to a room in two regions drop one another, e.g.
+	// a member joining in one region and another leaving elsewhere.
+	Regions     []string
+	WriteRegion string // region all writes go to, or "local" for Region
 }
 
 type RedisConfig struct {
@@ -32,21 +56,83 @@ type RedisConfig struct {
 }
 
 type UserServiceConfig struct {
-	Address string
+	Address string // host:port resolved through DNS, or consul:///<service>
+
+	// Calls are balanced round robin over the replicas the address resolves to
+	ConsulAddr      string // HTTP API of the Consul agent for consul:/// addresses
+	ConsulToken     string
+	RefreshInterval time.Duration // how often DNS names are resolved again
+	SubsetSize      int           // replicas connected to, 0 connects to all of them
+	HealthCheck     bool          // skip replicas whose gRPC health service isn't serving
+}
+
+type WebSocketConfig struct {
+	MaxConnectionsPerUser int           // 0 disables the limit, it only applies to signed in users
+	MaxConnectionsPerIP   int           // 0 disables the limit
+	ConnectionCountTTL    time.Duration // counters of crashed instances are forgotten after this
+	TrustProxyHeaders     bool          // take the client IP from X-Forwarded-For
+	HubShards             int           // independently locked partitions of the hub
+	JoinSnapshotMessages  int           // recent messages sent to a client joining a room, 0 sends no snapshot
+}
+
+type AutomodConfig struct {
+	// CacheTTL bounds how long a replica serves a dictionary if it misses an invalidation
+	CacheTTL time.Duration
+}
+
+type ModerationConfig struct {
+	ScorerURL string        // ML scoring service, empty turns async moderation off
+	Threshold float64       // messages scoring at least this are removed
+	Timeout   time.Duration // per scoring request
+	Workers   int           // concurrent scoring requests
+	QueueSize int           // messages waiting to be scored, newer ones skip scoring when full
+}
+
+type RollupConfig struct {
+	FlushInterval time.Duration // how long activity is summed in memory before it is written
+	QueueSize     int           // events waiting to be summed, newer ones are dropped when full
+}
+
+type RetentionConfig struct {
+	Interval       time.Duration // how often rollups are downsampled and anonymized, 0 turns retention off
+	RawRetention   time.Duration // hourly chatter and emote rollups are folded into daily ones after this
+	AnonymizeAfter time.Duration // daily chatter rollups lose their user after this, 0 keeps users
+}
+
+// EventsConfig is the NATS JetStream event bus the events of the stream management service
+// are consumed from. Without NATS_URL nothing is consumed and only what the stream management
+// service pushes over HTTP is relayed, it pushes only when it doesn't publish to NATS.
+type EventsConfig struct {
+	NATSURL             string // comma separated servers of a cluster
+	NATSCredentialsFile string // user credentials, for servers with decentralized auth
+	ConsumerName        string // durable consumer the replicas share
+	CreateStream        bool   // create or update the CHAT_EVENTS stream dead letters go to instead of expecting it
+
+	// Handled events are remembered in DynamoDB.DedupeTable, so redelivered ones aren't relayed twice
+	DedupeLease     time.Duration // how long a replica handling an event keeps others off it
+	DedupeRetention time.Duration // how long handled events are remembered, beyond the stream's max age
 }
 
 func Load() *Config {
 	return &Config{
 		Server: ServerConfig{
-			GRPCPort: getEnv("GRPC_PORT", ":8080"),
-			HTTPPort: getEnv("HTTP_PORT", ":8081"),
+			GRPCPort:       getEnv("GRPC_PORT", ":8080"),
+			HTTPPort:       getEnv("HTTP_PORT", ":8081"),
+			PreflightMode:  getEnv("PREFLIGHT_MODE", "degrade"),
+			IdentitySecret: getEnv("IDENTITY_PROPAGATION_SECRET", ""),
 		},
 		DynamoDB: DynamoDBConfig{
 			Region:          getEnv("AWS_REGION", "us-west-2"),
 			ChatroomTable:   getEnv("DYNAMODB_CHATROOM_TABLE", "chatrooms"),
 			MessageTable:    getEnv("DYNAMODB_MESSAGE_TABLE", "messages"),
+			AutomodTable:    getEnv("DYNAMODB_AUTOMOD_TABLE", "automod_dictionaries"),
+			RollupTable:     getEnv("DYNAMODB_ROLLUP_TABLE", "chat_rollups"),
+			VODCommentTable: getEnv("DYNAMODB_VOD_COMMENT_TABLE", "vod_comments"),
+			DedupeTable:     getEnv("DYNAMODB_DEDUPE_TABLE", "chat_event_dedupe"),
 			AccessKeyID:     getEnv("AWS_ACCESS_KEY_ID", ""),
 			SecretAccessKey: getEnv("AWS_SECRET_ACCESS_KEY", ""),
+			Regions:         getEnvAsSlice("DYNAMODB_REGIONS"),
+			WriteRegion:     getEnv("DYNAMODB_WRITE_REGION", "local"),
 		},
 		Redis: RedisConfig{
 			Address:  getEnv("REDIS_ADDRESS", "localhost:6379"),
@@ -54,7 +140,47 @@ func Load() *Config {
 			DB:       0,
 		},
 		UserService: UserServiceConfig{
-			Address: getEnv("USER_SERVICE_ADDRESS", "localhost:8082"),
+			Address:         getEnv("USER_SERVICE_ADDRESS", "localhost:8082"),
+			ConsulAddr:      getEnv("CONSUL_HTTP_ADDR", "http://localhost:8500"),
+			ConsulToken:     getEnv("CONSUL_HTTP_TOKEN", ""),
+			RefreshInterval: getEnvAsDuration("SERVICE_DISCOVERY_REFRESH", 30*time.Second),
+			SubsetSize:      getEnvAsInt("USER_SERVICE_SUBSET_SIZE", 0),
+			HealthCheck:     getEnv("USER_SERVICE_HEALTH_CHECK", "true") == "true",
+		},
+		WebSocket: WebSocketConfig{
+			MaxConnectionsPerUser: getEnvAsInt("WS_MAX_CONNECTIONS_PER_USER", 5),
+			MaxConnectionsPerIP:   getEnvAsInt("WS_MAX_CONNECTIONS_PER_IP", 20),
+			ConnectionCountTTL:    getEnvAsDuration("WS_CONNECTION_COUNT_TTL", 6*time.Hour),
+			TrustProxyHeaders:     getEnv("WS_TRUST_PROXY_HEADERS", "false") == "true",
+			HubShards:             getEnvAsInt("WS_HUB_SHARDS", 32),
+			JoinSnapshotMessages:  getEnvAsInt("WS_JOIN_SNAPSHOT_MESSAGES", 50),
+		},
+		Automod: AutomodConfig{
+			CacheTTL: getEnvAsDuration("AUTOMOD_CACHE_TTL", 5*time.Minute),
+		},
+		Moderation: ModerationConfig{
+			ScorerURL: getEnv("MODERATION_SCORER_URL", ""),
+			Threshold: getEnvAsFloat("MODERATION_THRESHOLD", 0.9),
+			Timeout:   getEnvAsDuration("MODERATION_TIMEOUT", 2*time.Second),
+			Workers:   getEnvAsInt("MODERATION_WORKERS", 4),
+			QueueSize: getEnvAsInt("MODERATION_QUEUE_SIZE", 1024),
+		},
+		Rollups: RollupConfig{
+			FlushInterval: getEnvAsDuration("ROLLUP_FLUSH_INTERVAL", 30*time.Second),
+			QueueSize:     getEnvAsInt("ROLLUP_QUEUE_SIZE", 4096),
+		},
+		Retention: RetentionConfig{
+			Interval:       getEnvAsDuration("RETENTION_INTERVAL", 24*time.Hour),
+			RawRetention:   getEnvAsDuration("RETENTION_RAW_ROLLUPS", 30*24*time.Hour),
+			AnonymizeAfter: getEnvAsDuration("RETENTION_ANONYMIZE_AFTER", 90*24*time.Hour),
+		},
+		Events: EventsConfig{
+			NATSURL:             getEnv("NATS_URL", ""),
+			NATSCredentialsFile: getEnv("NATS_CREDENTIALS_FILE", ""),
+			ConsumerName:        getEnv("EVENT_CONSUMER_NAME", "chat-service"),
+			CreateStream:        getEnv("NATS_CREATE_STREAM", "false") == "true",
+			DedupeLease:         getEnvAsDuration("EVENT_DEDUPE_LEASE", time.Minute),
+			DedupeRetention:     getEnvAsDuration("EVENT_DEDUPE_RETENTION", 8*24*time.Hour),
 		},
 	}
 }
@@ -65,3 +191,35 @@ func getEnv(key, defaultValue string) string {
 	}
 	return defaultValue
 }
+
+func getEnvAsInt(key string, defaultValue int) int {
+	if value, err := strconv.Atoi(os.Getenv(key)); err == nil {
+		return value
+	}
+	return defaultValue
+}
+
+func getEnvAsFloat(key string, defaultValue float64) float64 {
+	if value, err := strconv.ParseFloat(os.Getenv(key), 64); err == nil {
+		return value
+	}
+	return defaultValue
+}
+
+func getEnvAsDuration(key string, defaultValue time.Duration) time.Duration {
+	if value, err := time.ParseDuration(os.Getenv(key)); err == nil {
+		return value
+	}
+	return defaultValue
+}
+
+// getEnvAsSlice parses a comma separated list
+func getEnvAsSlice(key string) []string {
+	var result []string
+	for _, entry := range strings.Split(os.Getenv(key), ",") {
+		if entry = strings.TrimSpace(entry); entry != "" {
+			result = append(result, entry)
+		}
+	}
+	return result
+}
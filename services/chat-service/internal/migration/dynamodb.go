@@ -10,6 +10,7 @@ import (
 	"github.com/aws/aws-sdk-go/service/dynamodb"
 
 	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/services/chat-service/internal/config"
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/shared/go/pkg/events"
 )
 
 type DynamoDBMigrator struct {
@@ -37,25 +38,65 @@ func (m *DynamoDBMigrator) CreateTables() error {
 		return fmt.Errorf("failed to create messages table: %w", err)
 	}
 
+	// Create Automod dictionaries table
+	if err := m.createTable(m.automodTableDefinition()); err != nil {
+		return fmt.Errorf("failed to create automod dictionaries table: %w", err)
+	}
+
+	// Create chat rollups table
+	if err := m.createTable(m.rollupTableDefinition()); err != nil {
+		return fmt.Errorf("failed to create chat rollups table: %w", err)
+	}
+
+	// Create VOD comments table
+	if err := m.createTable(m.vodCommentTableDefinition()); err != nil {
+		return fmt.Errorf("failed to create vod comments table: %w", err)
+	}
+
+	// Create event dedupe table, whose claims expire
+	if err := m.createTable(events.DedupeTableDefinition(m.config.DedupeTable)); err != nil {
+		return fmt.Errorf("failed to create event dedupe table: %w", err)
+	}
+	if err := m.enableTTL(); err != nil {
+		return err
+	}
+
 	log.Println("All DynamoDB tables created successfully!")
 	return nil
 }
 
-func (m *DynamoDBMigrator) createChatroomsTable() error {
-	tableName := m.config.ChatroomTable
+// TableDefinitions returns the tables this service needs. It is the single source of
+// truth used both for table creation and for the provisioning plan.
+func (m *DynamoDBMigrator) TableDefinitions() []*dynamodb.CreateTableInput {
+	return []*dynamodb.CreateTableInput{
+		m.chatroomsTableDefinition(),
+		m.messagesTableDefinition(),
+		m.automodTableDefinition(),
+		m.rollupTableDefinition(),
+		m.vodCommentTableDefinition(),
+		events.DedupeTableDefinition(m.config.DedupeTable),
+	}
+}
 
-	// Check if table already exists
-	_, err := m.db.DescribeTable(&dynamodb.DescribeTableInput{
-		TableName: aws.String(tableName),
-	})
-	if err == nil {
-		log.Printf("Table %s already exists, skipping creation", tableName)
-		return nil
+// TTLAttributes maps tables to the attribute DynamoDB expires their items by
+func (m *DynamoDBMigrator) TTLAttributes() map[string]string {
+	return map[string]string{
+		m.config.DedupeTable: events.DedupeTTLAttribute,
 	}
+}
 
-	log.Printf("Creating table %s...", tableName)
+func (m *DynamoDBMigrator) createChatroomsTable() error {
+	return m.createTable(m.chatroomsTableDefinition())
+}
+
+func (m *DynamoDBMigrator) createMessagesTable() error {
+	return m.createTable(m.messagesTableDefinition())
+}
+
+func (m *DynamoDBMigrator) chatroomsTableDefinition() *dynamodb.CreateTableInput {
+	tableName := m.config.ChatroomTable
 
-	input := &dynamodb.CreateTableInput{
+	return &dynamodb.CreateTableInput{
 		TableName: aws.String(tableName),
 		KeySchema: []*dynamodb.KeySchemaElement{
 			{
@@ -89,31 +130,12 @@ func (m *DynamoDBMigrator) createChatroomsTable() error {
 			},
 		},
 	}
-
-	_, err = m.db.CreateTable(input)
-	if err != nil {
-		return fmt.Errorf("failed to create table %s: %w", tableName, err)
-	}
-
-	// Wait for table to be active
-	return m.waitForTableActive(tableName)
 }
 
-func (m *DynamoDBMigrator) createMessagesTable() error {
+func (m *DynamoDBMigrator) messagesTableDefinition() *dynamodb.CreateTableInput {
 	tableName := m.config.MessageTable
 
-	// Check if table already exists
-	_, err := m.db.DescribeTable(&dynamodb.DescribeTableInput{
-		TableName: aws.String(tableName),
-	})
-	if err == nil {
-		log.Printf("Table %s already exists, skipping creation", tableName)
-		return nil
-	}
-
-	log.Printf("Creating table %s...", tableName)
-
-	input := &dynamodb.CreateTableInput{
+	return &dynamodb.CreateTableInput{
 		TableName: aws.String(tableName),
 		KeySchema: []*dynamodb.KeySchemaElement{
 			{
@@ -155,6 +177,118 @@ func (m *DynamoDBMigrator) createMessagesTable() error {
 			},
 		},
 	}
+}
+
+// automodTableDefinition holds one dictionary per scope, "global" or a chatroom ID
+func (m *DynamoDBMigrator) automodTableDefinition() *dynamodb.CreateTableInput {
+	return &dynamodb.CreateTableInput{
+		TableName: aws.String(m.config.AutomodTable),
+		KeySchema: []*dynamodb.KeySchemaElement{
+			{
+				AttributeName: aws.String("scope"),
+				KeyType:       aws.String("HASH"), // Partition key
+			},
+		},
+		AttributeDefinitions: []*dynamodb.AttributeDefinition{
+			{
+				AttributeName: aws.String("scope"),
+				AttributeType: aws.String("S"), // String
+			},
+		},
+		BillingMode: aws.String("PAY_PER_REQUEST"),
+	}
+}
+
+// rollupTableDefinition holds the hourly activity of each chatroom: one item per hour, sorted
+// by "h#<hour>", one per chatter and hour, sorted by "u#<hour>#<user ID>", and one per emote
+// and hour, sorted by "e#<hour>#<emote>"
+func (m *DynamoDBMigrator) rollupTableDefinition() *dynamodb.CreateTableInput {
+	return &dynamodb.CreateTableInput{
+		TableName: aws.String(m.config.RollupTable),
+		KeySchema: []*dynamodb.KeySchemaElement{
+			{
+				AttributeName: aws.String("chatroom_id"),
+				KeyType:       aws.String("HASH"), // Partition key
+			},
+			{
+				AttributeName: aws.String("bucket"),
+				KeyType:       aws.String("RANGE"), // Sort key
+			},
+		},
+		AttributeDefinitions: []*dynamodb.AttributeDefinition{
+			{
+				AttributeName: aws.String("chatroom_id"),
+				AttributeType: aws.String("S"), // String
+			},
+			{
+				AttributeName: aws.String("bucket"),
+				AttributeType: aws.String("S"), // String
+			},
+		},
+		BillingMode: aws.String("PAY_PER_REQUEST"),
+	}
+}
+
+// vodCommentTableDefinition holds the comments of each VOD sorted by their position in the
+// recording, with an index to find a comment by its ID
+func (m *DynamoDBMigrator) vodCommentTableDefinition() *dynamodb.CreateTableInput {
+	return &dynamodb.CreateTableInput{
+		TableName: aws.String(m.config.VODCommentTable),
+		KeySchema: []*dynamodb.KeySchemaElement{
+			{
+				AttributeName: aws.String("vod_id"),
+				KeyType:       aws.String("HASH"), // Partition key
+			},
+			{
+				AttributeName: aws.String("position_key"),
+				KeyType:       aws.String("RANGE"), // Sort key
+			},
+		},
+		AttributeDefinitions: []*dynamodb.AttributeDefinition{
+			{
+				AttributeName: aws.String("vod_id"),
+				AttributeType: aws.String("S"), // String
+			},
+			{
+				AttributeName: aws.String("position_key"),
+				AttributeType: aws.String("S"), // String
+			},
+			{
+				AttributeName: aws.String("id"),
+				AttributeType: aws.String("S"), // String
+			},
+		},
+		BillingMode: aws.String("PAY_PER_REQUEST"),
+		GlobalSecondaryIndexes: []*dynamodb.GlobalSecondaryIndex{
+			{
+				IndexName: aws.String("id-index"),
+				KeySchema: []*dynamodb.KeySchemaElement{
+					{
+						AttributeName: aws.String("id"),
+						KeyType:       aws.String("HASH"),
+					},
+				},
+				Projection: &dynamodb.Projection{
+					ProjectionType: aws.String("ALL"),
+				},
+			},
+		},
+	}
+}
+
+func (m *DynamoDBMigrator) createTable(input *dynamodb.CreateTableInput) error {
+	tableName := aws.StringValue(input.TableName)
+
+	// Check if table already exists
+	_, err := m.db.DescribeTable(&dynamodb.DescribeTableInput{
+		TableName: aws.String(tableName),
+	})
+	if err == nil {
+		log.Printf("Table %s already exists, skipping creation", tableName)
+		return nil
+	}
+
+	log.Printf("Creating table %s...", tableName)
 
 	_, err = m.db.CreateTable(input)
 	if err != nil {
@@ -165,6 +299,37 @@ func (m *DynamoDBMigrator) createMessagesTable() error {
 	return m.waitForTableActive(tableName)
 }
 
+// enableTTL turns on expiry by an attribute for the tables in TTLAttributes
+func (m *DynamoDBMigrator) enableTTL() error {
+	for tableName, attribute := range m.TTLAttributes() {
+		result, err := m.db.DescribeTimeToLive(&dynamodb.DescribeTimeToLiveInput{
+			TableName: aws.String(tableName),
+		})
+		if err != nil {
+			return fmt.Errorf("failed to describe TTL of table %s: %w", tableName, err)
+		}
+		if description := result.TimeToLiveDescription; description != nil {
+			switch aws.StringValue(description.TimeToLiveStatus) {
+			case dynamodb.TimeToLiveStatusEnabled, dynamodb.TimeToLiveStatusEnabling:
+				continue
+			}
+		}
+
+		_, err = m.db.UpdateTimeToLive(&dynamodb.UpdateTimeToLiveInput{
+			TableName: aws.String(tableName),
+			TimeToLiveSpecification: &dynamodb.TimeToLiveSpecification{
+				AttributeName: aws.String(attribute),
+				Enabled:       aws.Bool(true),
+			},
+		})
+		if err != nil {
+			return fmt.Errorf("failed to enable TTL on table %s: %w", tableName, err)
+		}
+		log.Printf("TTL enabled on table %s by %s", tableName, attribute)
+	}
+	return nil
+}
+
 func (m *DynamoDBMigrator) waitForTableActive(tableName string) error {
 	log.Printf("Waiting for table %s to become active...", tableName)
 
@@ -196,7 +361,7 @@ func (m *DynamoDBMigrator) waitForTableActive(tableName string) error {
 func (m *DynamoDBMigrator) ForceCleanup() error {
 	log.Println("🧹 Force cleaning up all tables...")
 
-	tables := []string{m.config.ChatroomTable, m.config.MessageTable}
+	tables := []string{m.config.ChatroomTable, m.config.MessageTable, m.config.AutomodTable, m.config.RollupTable, m.config.VODCommentTable, m.config.DedupeTable}
 
 	for _, tableName := range tables {
 		log.Printf("Attempting to delete table: %s", tableName)
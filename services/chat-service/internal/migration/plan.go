@@ -0,0 +1,239 @@
+// services/chat-service/internal/migration/plan.go
+package migration
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+)
+
+// Plan is a machine-readable description of the AWS resources the service expects,
+// so they can be provisioned outside the app (Terraform, CloudFormation, ...)
+type Plan struct {
+	Service string      `json:"service"`
+	Region  string      `json:"region"`
+	Tables  []TableSpec `json:"dynamodb_tables"`
+}
+
+type TableSpec struct {
+	Name                   string          `json:"name"`
+	BillingMode            string          `json:"billing_mode"`
+	Attributes             []AttributeSpec `json:"attributes"`
+	HashKey                string          `json:"hash_key"`
+	RangeKey               string          `json:"range_key,omitempty"`
+	GlobalSecondaryIndexes []IndexSpec     `json:"global_secondary_indexes,omitempty"`
+	TTLAttribute           string          `json:"ttl_attribute,omitempty"`
+}
+
+type AttributeSpec struct {
+	Name string `json:"name"`
+	Type string `json:"type"` // S, N or B
+}
+
+type IndexSpec struct {
+	Name           string `json:"name"`
+	HashKey        string `json:"hash_key"`
+	RangeKey       string `json:"range_key,omitempty"`
+	ProjectionType string `json:"projection_type"`
+}
+
+// Plan describes every DynamoDB table the chat service uses. It doesn't need a live connection.
+func (m *DynamoDBMigrator) Plan() *Plan {
+	plan := &Plan{
+		Service: "chat-service",
+		Region:  m.config.Region,
+	}
+
+	ttlAttributes := m.TTLAttributes()
+	for _, table := range m.TableDefinitions() {
+		spec := tableSpecFromInput(table)
+		spec.TTLAttribute = ttlAttributes[spec.Name]
+		plan.Tables = append(plan.Tables, spec)
+	}
+
+	return plan
+}
+
+// VerifyTables checks that every table exists, is active and carries the expected
+// key schema and GSIs. It returns one entry per problem found.
+func (m *DynamoDBMigrator) VerifyTables() []string {
+	var problems []string
+
+	for _, expected := range m.TableDefinitions() {
+		tableName := aws.StringValue(expected.TableName)
+
+		result, err := m.db.DescribeTable(&dynamodb.DescribeTableInput{
+			TableName: aws.String(tableName),
+		})
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("table '%s' is missing or not accessible: %v", tableName, err))
+			continue
+		}
+
+		table := result.Table
+		if status := aws.StringValue(table.TableStatus); status != dynamodb.TableStatusActive {
+			problems = append(problems, fmt.Sprintf("table '%s' is %s, expected ACTIVE", tableName, status))
+		}
+
+		expectedSpec := tableSpecFromInput(expected)
+		hashKey, rangeKey := splitKeySchema(table.KeySchema)
+		if hashKey != expectedSpec.HashKey || rangeKey != expectedSpec.RangeKey {
+			problems = append(problems, fmt.Sprintf("table '%s' is keyed on (%s, %s), expected (%s, %s)",
+				tableName, hashKey, rangeKey, expectedSpec.HashKey, expectedSpec.RangeKey))
+		}
+
+		actualIndexes := make(map[string]*dynamodb.GlobalSecondaryIndexDescription)
+		for _, index := range table.GlobalSecondaryIndexes {
+			actualIndexes[aws.StringValue(index.IndexName)] = index
+		}
+
+		for _, index := range expectedSpec.GlobalSecondaryIndexes {
+			actual, ok := actualIndexes[index.Name]
+			if !ok {
+				problems = append(problems, fmt.Sprintf("table '%s' is missing GSI '%s' on (%s, %s)",
+					tableName, index.Name, index.HashKey, index.RangeKey))
+				continue
+			}
+			hashKey, rangeKey := splitKeySchema(actual.KeySchema)
+			if hashKey != index.HashKey || rangeKey != index.RangeKey {
+				problems = append(problems, fmt.Sprintf("GSI '%s' on table '%s' is keyed on (%s, %s), expected (%s, %s)",
+					index.Name, tableName, hashKey, rangeKey, index.HashKey, index.RangeKey))
+			}
+		}
+	}
+
+	return problems
+}
+
+// Render outputs the plan in the requested format: "json" or "cloudformation"
+func (p *Plan) Render(format string) ([]byte, error) {
+	switch strings.ToLower(format) {
+	case "", "json":
+		return json.MarshalIndent(p, "", "  ")
+	case "cloudformation", "cfn":
+		return json.MarshalIndent(p.cloudFormationTemplate(), "", "  ")
+	default:
+		return nil, fmt.Errorf("unknown plan format %q (expected json or cloudformation)", format)
+	}
+}
+
+func tableSpecFromInput(input *dynamodb.CreateTableInput) TableSpec {
+	spec := TableSpec{
+		Name:        aws.StringValue(input.TableName),
+		BillingMode: aws.StringValue(input.BillingMode),
+	}
+	spec.HashKey, spec.RangeKey = splitKeySchema(input.KeySchema)
+
+	for _, attr := range input.AttributeDefinitions {
+		spec.Attributes = append(spec.Attributes, AttributeSpec{
+			Name: aws.StringValue(attr.AttributeName),
+			Type: aws.StringValue(attr.AttributeType),
+		})
+	}
+
+	for _, index := range input.GlobalSecondaryIndexes {
+		indexSpec := IndexSpec{
+			Name:           aws.StringValue(index.IndexName),
+			ProjectionType: aws.StringValue(index.Projection.ProjectionType),
+		}
+		indexSpec.HashKey, indexSpec.RangeKey = splitKeySchema(index.KeySchema)
+		spec.GlobalSecondaryIndexes = append(spec.GlobalSecondaryIndexes, indexSpec)
+	}
+
+	return spec
+}
+
+func splitKeySchema(schema []*dynamodb.KeySchemaElement) (hashKey, rangeKey string) {
+	for _, key := range schema {
+		switch aws.StringValue(key.KeyType) {
+		case dynamodb.KeyTypeHash:
+			hashKey = aws.StringValue(key.AttributeName)
+		case dynamodb.KeyTypeRange:
+			rangeKey = aws.StringValue(key.AttributeName)
+		}
+	}
+	return hashKey, rangeKey
+}
+
+// cloudFormationTemplate converts the plan into a CloudFormation template
+func (p *Plan) cloudFormationTemplate() map[string]interface{} {
+	resources := make(map[string]interface{})
+
+	for _, table := range p.Tables {
+		var attributes []map[string]string
+		for _, attr := range table.Attributes {
+			attributes = append(attributes, map[string]string{
+				"AttributeName": attr.Name,
+				"AttributeType": attr.Type,
+			})
+		}
+
+		properties := map[string]interface{}{
+			"TableName":            table.Name,
+			"BillingMode":          table.BillingMode,
+			"AttributeDefinitions": attributes,
+			"KeySchema":            cfnKeySchema(table.HashKey, table.RangeKey),
+		}
+
+		var indexes []map[string]interface{}
+		for _, index := range table.GlobalSecondaryIndexes {
+			indexes = append(indexes, map[string]interface{}{
+				"IndexName":  index.Name,
+				"KeySchema":  cfnKeySchema(index.HashKey, index.RangeKey),
+				"Projection": map[string]string{"ProjectionType": index.ProjectionType},
+			})
+		}
+		if len(indexes) > 0 {
+			properties["GlobalSecondaryIndexes"] = indexes
+		}
+		if table.TTLAttribute != "" {
+			properties["TimeToLiveSpecification"] = map[string]interface{}{
+				"AttributeName": table.TTLAttribute,
+				"Enabled":       true,
+			}
+		}
+
+		resources[cfnLogicalID(table.Name)+"Table"] = map[string]interface{}{
+			"Type":       "AWS::DynamoDB::Table",
+			"Properties": properties,
+		}
+	}
+
+	return map[string]interface{}{
+		"AWSTemplateFormatVersion": "2010-09-09",
+		"Description":              fmt.Sprintf("Resources required by the %s", p.Service),
+		"Resources":                resources,
+	}
+}
+
+func cfnKeySchema(hashKey, rangeKey string) []map[string]string {
+	schema := []map[string]string{
+		{"AttributeName": hashKey, "KeyType": dynamodb.KeyTypeHash},
+	}
+	if rangeKey != "" {
+		schema = append(schema, map[string]string{"AttributeName": rangeKey, "KeyType": dynamodb.KeyTypeRange})
+	}
+	return schema
+}
+
+// cfnLogicalID turns a resource name like "chat-messages" into "ChatMessages"
+func cfnLogicalID(name string) string {
+	var id strings.Builder
+	upperNext := true
+	for _, r := range name {
+		if (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') {
+			if upperNext {
+				id.WriteString(strings.ToUpper(string(r)))
+			} else {
+				id.WriteRune(r)
+			}
+			upperNext = false
+			continue
+		}
+		upperNext = true
+	}
+	return id.String()
+}
@@ -0,0 +1,34 @@
+package models
+
+import "time"
+
+// AutomodScopeGlobal is the scope of the platform-wide dictionary, room dictionaries are
+// scoped by chatroom ID
+const AutomodScopeGlobal = "global"
+
+type AutomodAction string
+
+const (
+	// AutomodActionBlock rejects messages with a banned term
+	AutomodActionBlock AutomodAction = "block"
+	// AutomodActionMask replaces banned terms with asterisks
+	AutomodActionMask AutomodAction = "mask"
+)
+
+// AutomodDictionary is a list of banned terms. A room's dictionary adds to the global one,
+// can allow terms the global one bans and can override its action.
+type AutomodDictionary struct {
+	Scope        string        `json:"scope" dynamodbav:"scope"`
+	BannedTerms  []string      `json:"banned_terms" dynamodbav:"banned_terms"`
+	AllowedTerms []string      `json:"allowed_terms,omitempty" dynamodbav:"allowed_terms,omitempty"`
+	Action       AutomodAction `json:"action,omitempty" dynamodbav:"action,omitempty"`
+	Version      int64         `json:"version" dynamodbav:"version"` // bumped on every update
+	UpdatedBy    string        `json:"updated_by,omitempty" dynamodbav:"updated_by,omitempty"`
+	UpdatedAt    time.Time     `json:"updated_at" dynamodbav:"updated_at"`
+}
+
+// AutomodInvalidation tells the other replicas a dictionary changed
+type AutomodInvalidation struct {
+	Scope   string `json:"scope"`
+	Version int64  `json:"version"`
+}
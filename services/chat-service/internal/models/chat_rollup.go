@@ -0,0 +1,59 @@
+package models
+
+import "time"
+
+// ChatActivityType is what a ChatActivity counts as
+type ChatActivityType string
+
+const (
+	// ChatActivityMessage is a message delivered to a room
+	ChatActivityMessage ChatActivityType = "message"
+	// ChatActivityModeration is a message automod blocked or moderation took back
+	ChatActivityModeration ChatActivityType = "moderation"
+)
+
+// ChatActivity is one event summed into the hourly rollups of a room
+type ChatActivity struct {
+	Type       ChatActivityType
+	ChatroomID string
+	UserID     string
+	Username   string
+	At         time.Time
+}
+
+// ChatRollup is the activity of a room during one hour
+type ChatRollup struct {
+	ChatroomID        string    `json:"chatroom_id" dynamodbav:"chatroom_id"`
+	Hour              time.Time `json:"hour" dynamodbav:"hour"`
+	Messages          int64     `json:"messages" dynamodbav:"messages"`
+	UniqueChatters    int64     `json:"unique_chatters" dynamodbav:"unique_chatters"`
+	ModerationActions int64     `json:"moderation_actions" dynamodbav:"moderation_actions"`
+}
+
+// ChatterRollup is how many messages a user sent to a room, during one hour or, once summed,
+// over a period
+type ChatterRollup struct {
+	ChatroomID string    `json:"chatroom_id" dynamodbav:"chatroom_id"`
+	Hour       time.Time `json:"hour" dynamodbav:"hour"`
+	UserID     string    `json:"user_id" dynamodbav:"user_id"`
+	Username   string    `json:"username" dynamodbav:"username"`
+	Messages   int64     `json:"messages" dynamodbav:"messages"`
+}
+
+// EmoteRollup is how many times an emote was used in a room during one hour
+type EmoteRollup struct {
+	ChatroomID string    `json:"chatroom_id" dynamodbav:"chatroom_id"`
+	Hour       time.Time `json:"hour" dynamodbav:"hour"`
+	Name       string    `json:"name" dynamodbav:"name"`
+	Count      int64     `json:"count" dynamodbav:"count"`
+}
+
+// RetentionReport is what a retention run did to the rollup table
+type RetentionReport struct {
+	StartedAt      time.Time `json:"started_at"`
+	FinishedAt     time.Time `json:"finished_at"`
+	Downsampled    int64     `json:"downsampled"`     // hourly chatter and emote rollups folded into daily ones
+	Anonymized     int64     `json:"anonymized"`      // daily chatter rollups that lost their user
+	Failed         int64     `json:"failed"`          // rollups that could not be taken, or were lost after
+	BytesReclaimed int64     `json:"bytes_reclaimed"` // approximate, the size of removed rollups less the ones created
+}
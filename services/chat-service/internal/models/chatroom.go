@@ -11,4 +11,7 @@ type Chatroom struct {
 	MemberIDs   []string  `json:"member_ids" dynamodbav:"member_ids"`
 	CreatedAt   time.Time `json:"created_at" dynamodbav:"created_at"`
 	UpdatedAt   time.Time `json:"updated_at" dynamodbav:"updated_at"`
+
+	// PinnedMessages are copies of the pinned messages, oldest pin first
+	PinnedMessages []*Message `json:"pinned_messages,omitempty" dynamodbav:"pinned_messages,omitempty"`
 }
@@ -0,0 +1,7 @@
+package models
+
+// ModerationVerdict is the ML scoring service's opinion of a message
+type ModerationVerdict struct {
+	Score  float64  `json:"score"`  // 0 is harmless, 1 certainly abusive
+	Labels []string `json:"labels"` // e.g. harassment, spam
+}
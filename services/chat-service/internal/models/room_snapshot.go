@@ -0,0 +1,19 @@
+package models
+
+import "time"
+
+// RoomSnapshot is the denormalized room page kept in Redis, so a page load is a single read
+// instead of one per section
+type RoomSnapshot struct {
+	Chatroom       *Chatroom    `json:"chatroom"` // settings only, MemberIDs is left empty
+	MemberCount    int64        `json:"member_count"`
+	PinnedMessages []*Message   `json:"pinned_messages"`
+	TopEmotes      []EmoteCount `json:"top_emotes"`
+	RecentMessages []*Message   `json:"recent_messages"`
+	UpdatedAt      time.Time    `json:"updated_at"`
+}
+
+type EmoteCount struct {
+	Name  string `json:"name"`
+	Count int64  `json:"count"`
+}
@@ -0,0 +1,21 @@
+package models
+
+import "time"
+
+type SquadChatMode string
+
+const (
+	// SquadChatMerged delivers a message sent in any squad room to all of them
+	SquadChatMerged SquadChatMode = "merged"
+	// SquadChatSideBySide keeps the rooms separate, clients join each one
+	SquadChatSideBySide SquadChatMode = "side_by_side"
+)
+
+// SquadRoute ties the chat rooms of a squad stream together. The stream management
+// service owns squads and keeps their routes up to date.
+type SquadRoute struct {
+	SquadID     string        `json:"squad_id"`
+	Mode        SquadChatMode `json:"mode"`
+	ChatroomIDs []string      `json:"chatroom_ids"`
+	UpdatedAt   time.Time     `json:"updated_at"`
+}
@@ -0,0 +1,26 @@
+package models
+
+import (
+	"fmt"
+	"time"
+)
+
+// VODComment is a comment on a recording, pinned to a position of its timeline
+type VODComment struct {
+	VODID       string    `json:"vod_id" dynamodbav:"vod_id"`
+	PositionKey string    `json:"-" dynamodbav:"position_key"`
+	ID          string    `json:"id" dynamodbav:"id"`
+	ChatroomID  string    `json:"chatroom_id" dynamodbav:"chatroom_id"`
+	UserID      string    `json:"user_id" dynamodbav:"user_id"`
+	Username    string    `json:"username" dynamodbav:"username"`
+	PositionMs  int64     `json:"position_ms" dynamodbav:"position_ms"`
+	Content     string    `json:"content" dynamodbav:"content"`
+	CreatedAt   time.Time `json:"created_at" dynamodbav:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at" dynamodbav:"updated_at"`
+	IsEdited    bool      `json:"is_edited" dynamodbav:"is_edited"`
+}
+
+// VODCommentPositionKey sorts comments by position, then by ID for comments at the same position
+func VODCommentPositionKey(positionMs int64, commentID string) string {
+	return fmt.Sprintf("%012d#%s", positionMs, commentID)
+}
@@ -2,18 +2,21 @@ package repository
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
+	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/credentials"
-	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/dynamodb"
 	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
 	"github.com/aws/aws-sdk-go/service/dynamodb/expression"
+	"github.com/google/uuid"
 
 	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/services/chat-service/internal/config"
 	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/services/chat-service/internal/models"
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/shared/go/pkg/globaltables"
 )
 
 type DynamoDBRepository interface {
@@ -21,16 +24,55 @@ type DynamoDBRepository interface {
 	GetChatroom(ctx context.Context, chatroomID string) (*models.Chatroom, error)
 	AddMemberToChatroom(ctx context.Context, chatroomID, userID string) error
 	RemoveMemberFromChatroom(ctx context.Context, chatroomID, userID string) error
+	SetPinnedMessages(ctx context.Context, chatroomID string, messages []*models.Message) error
 	IsUserMemberOfChatroom(ctx context.Context, chatroomID, userID string) (bool, error)
 	GetUserChatrooms(ctx context.Context, userID string) ([]*models.Chatroom, error)
 	CreateMessage(ctx context.Context, message *models.Message) error
+	GetMessage(ctx context.Context, messageID string) (*models.Message, error)
+	DeleteMessage(ctx context.Context, messageID string) error
 	GetMessages(ctx context.Context, chatroomID string, limit int, cursor string) ([]*models.Message, error)
+	GetAutomodDictionary(ctx context.Context, scope string) (*models.AutomodDictionary, error)
+	PutAutomodDictionary(ctx context.Context, dictionary *models.AutomodDictionary) error
+	AddChatRollup(ctx context.Context, rollup *models.ChatRollup) error
+	AddChatterRollup(ctx context.Context, rollup *models.ChatterRollup) error
+	GetChatRollups(ctx context.Context, chatroomID string, from, to time.Time) ([]*models.ChatRollup, error)
+	GetChatterRollups(ctx context.Context, chatroomID string, from, to time.Time) ([]*models.ChatterRollup, error)
+	AddEmoteRollup(ctx context.Context, rollup *models.EmoteRollup) error
+	GetEmoteRollups(ctx context.Context, chatroomID string, from, to time.Time) ([]*models.EmoteRollup, error)
+	CreateVODComment(ctx context.Context, comment *models.VODComment) error
+	GetVODComment(ctx context.Context, commentID string) (*models.VODComment, error)
+	UpdateVODComment(ctx context.Context, comment *models.VODComment) error
+	DeleteVODComment(ctx context.Context, comment *models.VODComment) error
+	GetVODComments(ctx context.Context, vodID string, fromMs, toMs int64, limit int, cursor string) ([]*models.VODComment, string, error)
+	ScanChatterRollups(ctx context.Context, daily bool, before time.Time, fn func(*models.ChatterRollup) error) error
+	ScanEmoteRollups(ctx context.Context, before time.Time, fn func(*models.EmoteRollup) error) error
+	TakeChatterRollup(ctx context.Context, rollup *models.ChatterRollup, daily bool) (*models.ChatterRollup, int64, error)
+	TakeEmoteRollup(ctx context.Context, rollup *models.EmoteRollup) (*models.EmoteRollup, int64, error)
+	AddDailyChatterRollup(ctx context.Context, rollup *models.ChatterRollup) (int64, error)
+	AddDailyEmoteRollup(ctx context.Context, rollup *models.EmoteRollup) (int64, error)
+	PutAnonymousChatterRollup(ctx context.Context, rollup *models.ChatterRollup) (int64, error)
 }
 
+// ErrAutomodVersionConflict is returned when a dictionary changed since the version an update
+// was based on
+var ErrAutomodVersionConflict = errors.New("automod dictionary was updated concurrently")
+
+// ErrVODCommentNotFound is returned when a VOD comment doesn't exist or was deleted
+var ErrVODCommentNotFound = errors.New("vod comment not found")
+
+// rollupHourLayout formats the hour of rollup sort keys, so they sort chronologically
+const rollupHourLayout = "2006-01-02T15"
+
+// rollupDayLayout formats the day of downsampled rollup sort keys
+const rollupDayLayout = "2006-01-02"
+
 type dynamoDBRepository struct {
-	db            *dynamodb.DynamoDB
+	db            *globaltables.Client
 	chatroomTable string
 	messageTable  string
+	automodTable  string
+	rollupTable   string
+	commentTable  string
 }
 
 func NewDynamoDBRepository(cfg config.DynamoDBConfig) (DynamoDBRepository, error) {
@@ -52,15 +94,23 @@ func NewDynamoDBRepository(cfg config.DynamoDBConfig) (DynamoDBRepository, error
 		awsConfig.Endpoint = aws.String(endpoint)
 	}
 
-	sess, err := session.NewSession(awsConfig)
+	// Reads go to the local replica first, writes to the configured write region
+	db, err := globaltables.New(globaltables.Config{
+		LocalRegion: cfg.Region,
+		Regions:     cfg.Regions,
+		WriteRegion: cfg.WriteRegion,
+	}, awsConfig)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create AWS session: %w", err)
+		return nil, fmt.Errorf("failed to create DynamoDB client: %w", err)
 	}
 
 	return &dynamoDBRepository{
-		db:            dynamodb.New(sess),
+		db:            db,
 		chatroomTable: cfg.ChatroomTable,
 		messageTable:  cfg.MessageTable,
+		automodTable:  cfg.AutomodTable,
+		rollupTable:   cfg.RollupTable,
+		commentTable:  cfg.VODCommentTable,
 	}, nil
 }
 
@@ -171,6 +221,36 @@ func (r *dynamoDBRepository) RemoveMemberFromChatroom(ctx context.Context, chatr
 	return nil
 }
 
+func (r *dynamoDBRepository) SetPinnedMessages(ctx context.Context, chatroomID string, messages []*models.Message) error {
+	pinned, err := dynamodbattribute.Marshal(messages)
+	if err != nil {
+		return fmt.Errorf("failed to marshal pinned messages: %w", err)
+	}
+
+	updateExpr := expression.Set(expression.Name("pinned_messages"), expression.Value(pinned))
+	expr, err := expression.NewBuilder().WithUpdate(updateExpr).Build()
+	if err != nil {
+		return fmt.Errorf("failed to build update expression: %w", err)
+	}
+
+	_, err = r.db.UpdateItemWithContext(ctx, &dynamodb.UpdateItemInput{
+		TableName: aws.String(r.chatroomTable),
+		Key: map[string]*dynamodb.AttributeValue{
+			"id": {
+				S: aws.String(chatroomID),
+			},
+		},
+		UpdateExpression:          expr.Update(),
+		ExpressionAttributeNames:  expr.Names(),
+		ExpressionAttributeValues: expr.Values(),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to set pinned messages: %w", err)
+	}
+
+	return nil
+}
+
 func (r *dynamoDBRepository) IsUserMemberOfChatroom(ctx context.Context, chatroomID, userID string) (bool, error) {
 	chatroom, err := r.GetChatroom(ctx, chatroomID)
 	if err != nil {
@@ -235,6 +315,32 @@ func (r *dynamoDBRepository) CreateMessage(ctx context.Context, message *models.
 	return nil
 }
 
+func (r *dynamoDBRepository) GetMessage(ctx context.Context, messageID string) (*models.Message, error) {
+	result, err := r.db.GetItemWithContext(ctx, &dynamodb.GetItemInput{
+		TableName: aws.String(r.messageTable),
+		Key: map[string]*dynamodb.AttributeValue{
+			"id": {
+				S: aws.String(messageID),
+			},
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get message: %w", err)
+	}
+
+	if result.Item == nil {
+		return nil, fmt.Errorf("message not found")
+	}
+
+	var message models.Message
+	err = dynamodbattribute.UnmarshalMap(result.Item, &message)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unmarshal message: %w", err)
+	}
+
+	return &message, nil
+}
+
 func (r *dynamoDBRepository) GetMessages(ctx context.Context, chatroomID string, limit int, cursor string) ([]*models.Message, error) {
 	// This requires a GSI on chatroom_id sorted by created_at
 	// For now, using a simplified scan approach
@@ -269,3 +375,689 @@ func (r *dynamoDBRepository) GetMessages(ctx context.Context, chatroomID string,
 
 	return messages, nil
 }
+
+func (r *dynamoDBRepository) DeleteMessage(ctx context.Context, messageID string) error {
+	_, err := r.db.DeleteItemWithContext(ctx, &dynamodb.DeleteItemInput{
+		TableName: aws.String(r.messageTable),
+		Key: map[string]*dynamodb.AttributeValue{
+			"id": {
+				S: aws.String(messageID),
+			},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to delete message: %w", err)
+	}
+
+	return nil
+}
+
+// GetAutomodDictionary returns nil if the scope has no dictionary
+func (r *dynamoDBRepository) GetAutomodDictionary(ctx context.Context, scope string) (*models.AutomodDictionary, error) {
+	result, err := r.db.GetItemWithContext(ctx, &dynamodb.GetItemInput{
+		TableName: aws.String(r.automodTable),
+		Key: map[string]*dynamodb.AttributeValue{
+			"scope": {
+				S: aws.String(scope),
+			},
+		},
+		ConsistentRead: aws.Bool(true),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get automod dictionary: %w", err)
+	}
+
+	if result.Item == nil {
+		return nil, nil
+	}
+
+	var dictionary models.AutomodDictionary
+	if err := dynamodbattribute.UnmarshalMap(result.Item, &dictionary); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal automod dictionary: %w", err)
+	}
+
+	return &dictionary, nil
+}
+
+// PutAutomodDictionary stores the next version of a dictionary. dictionary.Version must be the
+// version the update was based on, 0 for a new dictionary, and is bumped on success.
+func (r *dynamoDBRepository) PutAutomodDictionary(ctx context.Context, dictionary *models.AutomodDictionary) error {
+	next := *dictionary
+	next.Version = dictionary.Version + 1
+
+	item, err := dynamodbattribute.MarshalMap(next)
+	if err != nil {
+		return fmt.Errorf("failed to marshal automod dictionary: %w", err)
+	}
+
+	condition := expression.AttributeNotExists(expression.Name("scope"))
+	if dictionary.Version > 0 {
+		condition = expression.Name("version").Equal(expression.Value(dictionary.Version))
+	}
+	expr, err := expression.NewBuilder().WithCondition(condition).Build()
+	if err != nil {
+		return fmt.Errorf("failed to build condition expression: %w", err)
+	}
+
+	_, err = r.db.PutItemWithContext(ctx, &dynamodb.PutItemInput{
+		TableName:                 aws.String(r.automodTable),
+		Item:                      item,
+		ConditionExpression:       expr.Condition(),
+		ExpressionAttributeNames:  expr.Names(),
+		ExpressionAttributeValues: expr.Values(),
+	})
+	if err != nil {
+		var conditionErr *dynamodb.ConditionalCheckFailedException
+		if errors.As(err, &conditionErr) {
+			return ErrAutomodVersionConflict
+		}
+		return fmt.Errorf("failed to put automod dictionary: %w", err)
+	}
+
+	dictionary.Version = next.Version
+	return nil
+}
+
+// AddChatRollup adds a room's activity to the rollup of its hour. Counters are added, so
+// every replica can flush its own share. Unique chatters are a count of the whole hour
+// instead and only replace a lower count.
+func (r *dynamoDBRepository) AddChatRollup(ctx context.Context, rollup *models.ChatRollup) error {
+	hour := rollup.Hour.UTC().Truncate(time.Hour)
+	key := rollupKey(rollup.ChatroomID, "h#"+hour.Format(rollupHourLayout))
+
+	update := expression.Set(expression.Name("hour"), expression.Value(hour)).
+		Add(expression.Name("messages"), expression.Value(rollup.Messages)).
+		Add(expression.Name("moderation_actions"), expression.Value(rollup.ModerationActions))
+	expr, err := expression.NewBuilder().WithUpdate(update).Build()
+	if err != nil {
+		return fmt.Errorf("failed to build update expression: %w", err)
+	}
+
+	_, err = r.db.UpdateItemWithContext(ctx, &dynamodb.UpdateItemInput{
+		TableName:                 aws.String(r.rollupTable),
+		Key:                       key,
+		UpdateExpression:          expr.Update(),
+		ExpressionAttributeNames:  expr.Names(),
+		ExpressionAttributeValues: expr.Values(),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to add chat rollup: %w", err)
+	}
+
+	if rollup.UniqueChatters == 0 {
+		return nil
+	}
+
+	// Replicas flush in any order, a count read earlier must not overwrite a later one
+	condition := expression.Or(
+		expression.AttributeNotExists(expression.Name("unique_chatters")),
+		expression.Name("unique_chatters").LessThan(expression.Value(rollup.UniqueChatters)),
+	)
+	expr, err = expression.NewBuilder().
+		WithUpdate(expression.Set(expression.Name("unique_chatters"), expression.Value(rollup.UniqueChatters))).
+		WithCondition(condition).
+		Build()
+	if err != nil {
+		return fmt.Errorf("failed to build update expression: %w", err)
+	}
+
+	_, err = r.db.UpdateItemWithContext(ctx, &dynamodb.UpdateItemInput{
+		TableName:                 aws.String(r.rollupTable),
+		Key:                       key,
+		UpdateExpression:          expr.Update(),
+		ConditionExpression:       expr.Condition(),
+		ExpressionAttributeNames:  expr.Names(),
+		ExpressionAttributeValues: expr.Values(),
+	})
+	if err != nil {
+		var conditionErr *dynamodb.ConditionalCheckFailedException
+		if errors.As(err, &conditionErr) {
+			return nil
+		}
+		return fmt.Errorf("failed to set unique chatters: %w", err)
+	}
+
+	return nil
+}
+
+// AddChatterRollup adds a user's messages to their rollup of the hour in a room
+func (r *dynamoDBRepository) AddChatterRollup(ctx context.Context, rollup *models.ChatterRollup) error {
+	hour := rollup.Hour.UTC().Truncate(time.Hour)
+
+	update := expression.Set(expression.Name("hour"), expression.Value(hour)).
+		Set(expression.Name("user_id"), expression.Value(rollup.UserID)).
+		Set(expression.Name("username"), expression.Value(rollup.Username)).
+		Add(expression.Name("messages"), expression.Value(rollup.Messages))
+	expr, err := expression.NewBuilder().WithUpdate(update).Build()
+	if err != nil {
+		return fmt.Errorf("failed to build update expression: %w", err)
+	}
+
+	_, err = r.db.UpdateItemWithContext(ctx, &dynamodb.UpdateItemInput{
+		TableName:                 aws.String(r.rollupTable),
+		Key:                       rollupKey(rollup.ChatroomID, "u#"+hour.Format(rollupHourLayout)+"#"+rollup.UserID),
+		UpdateExpression:          expr.Update(),
+		ExpressionAttributeNames:  expr.Names(),
+		ExpressionAttributeValues: expr.Values(),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to add chatter rollup: %w", err)
+	}
+
+	return nil
+}
+
+// GetChatRollups returns a room's hourly rollups from the hour of from to the hour of to,
+// oldest first. Hours without activity have no rollup.
+func (r *dynamoDBRepository) GetChatRollups(ctx context.Context, chatroomID string, from, to time.Time) ([]*models.ChatRollup, error) {
+	items, err := r.queryRollups(ctx, chatroomID,
+		"h#"+from.UTC().Format(rollupHourLayout),
+		"h#"+to.UTC().Format(rollupHourLayout))
+	if err != nil {
+		return nil, err
+	}
+
+	rollups := make([]*models.ChatRollup, 0, len(items))
+	for _, item := range items {
+		var rollup models.ChatRollup
+		if err := dynamodbattribute.UnmarshalMap(item, &rollup); err != nil {
+			continue // Skip invalid items
+		}
+		rollups = append(rollups, &rollup)
+	}
+	return rollups, nil
+}
+
+// GetChatterRollups returns the hourly rollups of every user who chatted in a room from the
+// hour of from to the hour of to
+func (r *dynamoDBRepository) GetChatterRollups(ctx context.Context, chatroomID string, from, to time.Time) ([]*models.ChatterRollup, error) {
+	// "$" sorts right after "#", so the range ends after every user of the last hour
+	items, err := r.queryRollups(ctx, chatroomID,
+		"u#"+from.UTC().Format(rollupHourLayout),
+		"u#"+to.UTC().Format(rollupHourLayout)+"$")
+	if err != nil {
+		return nil, err
+	}
+
+	rollups := make([]*models.ChatterRollup, 0, len(items))
+	for _, item := range items {
+		var rollup models.ChatterRollup
+		if err := dynamodbattribute.UnmarshalMap(item, &rollup); err != nil {
+			continue // Skip invalid items
+		}
+		rollups = append(rollups, &rollup)
+	}
+	return rollups, nil
+}
+
+// AddEmoteRollup adds to the uses of an emote during an hour in a room
+func (r *dynamoDBRepository) AddEmoteRollup(ctx context.Context, rollup *models.EmoteRollup) error {
+	hour := rollup.Hour.UTC().Truncate(time.Hour)
+
+	update := expression.Set(expression.Name("hour"), expression.Value(hour)).
+		Set(expression.Name("name"), expression.Value(rollup.Name)).
+		Add(expression.Name("count"), expression.Value(rollup.Count))
+	expr, err := expression.NewBuilder().WithUpdate(update).Build()
+	if err != nil {
+		return fmt.Errorf("failed to build update expression: %w", err)
+	}
+
+	_, err = r.db.UpdateItemWithContext(ctx, &dynamodb.UpdateItemInput{
+		TableName:                 aws.String(r.rollupTable),
+		Key:                       rollupKey(rollup.ChatroomID, "e#"+hour.Format(rollupHourLayout)+"#"+rollup.Name),
+		UpdateExpression:          expr.Update(),
+		ExpressionAttributeNames:  expr.Names(),
+		ExpressionAttributeValues: expr.Values(),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to add emote rollup: %w", err)
+	}
+
+	return nil
+}
+
+// GetEmoteRollups returns the hourly uses of every emote used in a room from the hour of from
+// to the hour of to
+func (r *dynamoDBRepository) GetEmoteRollups(ctx context.Context, chatroomID string, from, to time.Time) ([]*models.EmoteRollup, error) {
+	items, err := r.queryRollups(ctx, chatroomID,
+		"e#"+from.UTC().Format(rollupHourLayout),
+		"e#"+to.UTC().Format(rollupHourLayout)+"$")
+	if err != nil {
+		return nil, err
+	}
+
+	rollups := make([]*models.EmoteRollup, 0, len(items))
+	for _, item := range items {
+		var rollup models.EmoteRollup
+		if err := dynamodbattribute.UnmarshalMap(item, &rollup); err != nil {
+			continue // Skip invalid items
+		}
+		rollups = append(rollups, &rollup)
+	}
+	return rollups, nil
+}
+
+func (r *dynamoDBRepository) CreateVODComment(ctx context.Context, comment *models.VODComment) error {
+	item, err := dynamodbattribute.MarshalMap(comment)
+	if err != nil {
+		return fmt.Errorf("failed to marshal vod comment: %w", err)
+	}
+
+	_, err = r.db.PutItemWithContext(ctx, &dynamodb.PutItemInput{
+		TableName: aws.String(r.commentTable),
+		Item:      item,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to put vod comment item: %w", err)
+	}
+
+	return nil
+}
+
+// GetVODComment finds a comment by ID through the id index
+func (r *dynamoDBRepository) GetVODComment(ctx context.Context, commentID string) (*models.VODComment, error) {
+	keyCond := expression.Key("id").Equal(expression.Value(commentID))
+	expr, err := expression.NewBuilder().WithKeyCondition(keyCond).Build()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build key condition: %w", err)
+	}
+
+	result, err := r.db.QueryWithContext(ctx, &dynamodb.QueryInput{
+		TableName:                 aws.String(r.commentTable),
+		IndexName:                 aws.String("id-index"),
+		KeyConditionExpression:    expr.KeyCondition(),
+		ExpressionAttributeNames:  expr.Names(),
+		ExpressionAttributeValues: expr.Values(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to query vod comment: %w", err)
+	}
+
+	if len(result.Items) == 0 {
+		return nil, ErrVODCommentNotFound
+	}
+
+	var comment models.VODComment
+	if err := dynamodbattribute.UnmarshalMap(result.Items[0], &comment); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal vod comment: %w", err)
+	}
+
+	return &comment, nil
+}
+
+// UpdateVODComment saves the content of a comment, failing with ErrVODCommentNotFound if it
+// was deleted meanwhile
+func (r *dynamoDBRepository) UpdateVODComment(ctx context.Context, comment *models.VODComment) error {
+	update := expression.Set(expression.Name("content"), expression.Value(comment.Content)).
+		Set(expression.Name("updated_at"), expression.Value(comment.UpdatedAt)).
+		Set(expression.Name("is_edited"), expression.Value(comment.IsEdited))
+	condition := expression.AttributeExists(expression.Name("id"))
+	expr, err := expression.NewBuilder().WithUpdate(update).WithCondition(condition).Build()
+	if err != nil {
+		return fmt.Errorf("failed to build update expression: %w", err)
+	}
+
+	_, err = r.db.UpdateItemWithContext(ctx, &dynamodb.UpdateItemInput{
+		TableName:                 aws.String(r.commentTable),
+		Key:                       vodCommentKey(comment),
+		UpdateExpression:          expr.Update(),
+		ConditionExpression:       expr.Condition(),
+		ExpressionAttributeNames:  expr.Names(),
+		ExpressionAttributeValues: expr.Values(),
+	})
+	if err != nil {
+		var conditionErr *dynamodb.ConditionalCheckFailedException
+		if errors.As(err, &conditionErr) {
+			return ErrVODCommentNotFound
+		}
+		return fmt.Errorf("failed to update vod comment: %w", err)
+	}
+
+	return nil
+}
+
+func (r *dynamoDBRepository) DeleteVODComment(ctx context.Context, comment *models.VODComment) error {
+	_, err := r.db.DeleteItemWithContext(ctx, &dynamodb.DeleteItemInput{
+		TableName: aws.String(r.commentTable),
+		Key:       vodCommentKey(comment),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to delete vod comment: %w", err)
+	}
+
+	return nil
+}
+
+// GetVODComments returns a page of the comments of a VOD between two positions in timeline
+// order, with the cursor of the next page. toMs <= 0 reads to the end of the recording.
+func (r *dynamoDBRepository) GetVODComments(ctx context.Context, vodID string, fromMs, toMs int64, limit int, cursor string) ([]*models.VODComment, string, error) {
+	from := fmt.Sprintf("%012d", fromMs)
+	keyCond := expression.Key("vod_id").Equal(expression.Value(vodID))
+	if toMs > 0 {
+		// "$" sorts after the "#" separating the position from the ID
+		keyCond = keyCond.And(expression.Key("position_key").Between(expression.Value(from), expression.Value(fmt.Sprintf("%012d$", toMs))))
+	} else {
+		keyCond = keyCond.And(expression.Key("position_key").GreaterThanEqual(expression.Value(from)))
+	}
+	expr, err := expression.NewBuilder().WithKeyCondition(keyCond).Build()
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to build key condition: %w", err)
+	}
+
+	input := &dynamodb.QueryInput{
+		TableName:                 aws.String(r.commentTable),
+		KeyConditionExpression:    expr.KeyCondition(),
+		ExpressionAttributeNames:  expr.Names(),
+		ExpressionAttributeValues: expr.Values(),
+		Limit:                     aws.Int64(int64(limit)),
+	}
+	if cursor != "" {
+		input.ExclusiveStartKey = vodCommentKey(&models.VODComment{VODID: vodID, PositionKey: cursor})
+	}
+
+	result, err := r.db.QueryWithContext(ctx, input)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to query vod comments: %w", err)
+	}
+
+	comments := make([]*models.VODComment, 0, len(result.Items))
+	for _, item := range result.Items {
+		var comment models.VODComment
+		if err := dynamodbattribute.UnmarshalMap(item, &comment); err != nil {
+			continue // Skip invalid items
+		}
+		comments = append(comments, &comment)
+	}
+
+	nextCursor := ""
+	if last := result.LastEvaluatedKey["position_key"]; last != nil {
+		nextCursor = aws.StringValue(last.S)
+	}
+	return comments, nextCursor, nil
+}
+
+// ScanChatterRollups calls fn for every hourly, or daily, chatter rollup of an hour before
+// before. Daily rollups that were anonymized already are skipped. Scanning stops at the first
+// error fn returns.
+func (r *dynamoDBRepository) ScanChatterRollups(ctx context.Context, daily bool, before time.Time, fn func(*models.ChatterRollup) error) error {
+	filter := expression.Name("bucket").BeginsWith(chatterBucketPrefix(daily)).
+		And(expression.Name("hour").LessThan(expression.Value(before.UTC())))
+	if daily {
+		filter = filter.And(expression.Name("user_id").NotEqual(expression.Value("")))
+	}
+
+	return r.scanRollups(ctx, filter, func(item map[string]*dynamodb.AttributeValue) error {
+		var rollup models.ChatterRollup
+		if err := dynamodbattribute.UnmarshalMap(item, &rollup); err != nil {
+			return nil // Skip invalid items
+		}
+		return fn(&rollup)
+	})
+}
+
+// ScanEmoteRollups calls fn for every hourly emote rollup of an hour before before. Scanning
+// stops at the first error fn returns.
+func (r *dynamoDBRepository) ScanEmoteRollups(ctx context.Context, before time.Time, fn func(*models.EmoteRollup) error) error {
+	filter := expression.Name("bucket").BeginsWith("e#").
+		And(expression.Name("hour").LessThan(expression.Value(before.UTC())))
+
+	return r.scanRollups(ctx, filter, func(item map[string]*dynamodb.AttributeValue) error {
+		var rollup models.EmoteRollup
+		if err := dynamodbattribute.UnmarshalMap(item, &rollup); err != nil {
+			return nil // Skip invalid items
+		}
+		return fn(&rollup)
+	})
+}
+
+// TakeChatterRollup deletes an hourly, or daily, chatter rollup and returns it as it was
+// stored along with its approximate size. It returns nil when the rollup is already gone, so
+// replicas never both take the same one.
+func (r *dynamoDBRepository) TakeChatterRollup(ctx context.Context, rollup *models.ChatterRollup, daily bool) (*models.ChatterRollup, int64, error) {
+	item, err := r.takeRollup(ctx, rollupKey(rollup.ChatroomID, chatterBucket(rollup.Hour, rollup.UserID, daily)))
+	if err != nil || item == nil {
+		return nil, 0, err
+	}
+
+	var taken models.ChatterRollup
+	if err := dynamodbattribute.UnmarshalMap(item, &taken); err != nil {
+		return nil, 0, fmt.Errorf("failed to unmarshal chatter rollup: %w", err)
+	}
+	return &taken, itemSize(item), nil
+}
+
+// TakeEmoteRollup deletes an hourly emote rollup and returns it as it was stored along with
+// its approximate size, or nil when it is already gone
+func (r *dynamoDBRepository) TakeEmoteRollup(ctx context.Context, rollup *models.EmoteRollup) (*models.EmoteRollup, int64, error) {
+	hour := rollup.Hour.UTC().Truncate(time.Hour)
+	item, err := r.takeRollup(ctx, rollupKey(rollup.ChatroomID, "e#"+hour.Format(rollupHourLayout)+"#"+rollup.Name))
+	if err != nil || item == nil {
+		return nil, 0, err
+	}
+
+	var taken models.EmoteRollup
+	if err := dynamodbattribute.UnmarshalMap(item, &taken); err != nil {
+		return nil, 0, fmt.Errorf("failed to unmarshal emote rollup: %w", err)
+	}
+	return &taken, itemSize(item), nil
+}
+
+// AddDailyChatterRollup adds a user's messages to their rollup of the day in a room. It
+// returns the approximate size of the rollup if this created it, 0 if it was added to.
+func (r *dynamoDBRepository) AddDailyChatterRollup(ctx context.Context, rollup *models.ChatterRollup) (int64, error) {
+	day := rollup.Hour.UTC().Truncate(24 * time.Hour)
+
+	daily := *rollup
+	daily.Hour = day
+	update := expression.Set(expression.Name("hour"), expression.Value(day)).
+		Set(expression.Name("user_id"), expression.Value(rollup.UserID)).
+		Set(expression.Name("username"), expression.Value(rollup.Username)).
+		Add(expression.Name("messages"), expression.Value(rollup.Messages))
+
+	return r.addDailyRollup(ctx, rollupKey(rollup.ChatroomID, chatterBucket(day, rollup.UserID, true)), update, &daily)
+}
+
+// AddDailyEmoteRollup adds to the uses of an emote during a day in a room. It returns the
+// approximate size of the rollup if this created it, 0 if it was added to.
+func (r *dynamoDBRepository) AddDailyEmoteRollup(ctx context.Context, rollup *models.EmoteRollup) (int64, error) {
+	day := rollup.Hour.UTC().Truncate(24 * time.Hour)
+
+	daily := *rollup
+	daily.Hour = day
+	update := expression.Set(expression.Name("hour"), expression.Value(day)).
+		Set(expression.Name("name"), expression.Value(rollup.Name)).
+		Add(expression.Name("count"), expression.Value(rollup.Count))
+
+	return r.addDailyRollup(ctx, rollupKey(rollup.ChatroomID, "de#"+day.Format(rollupDayLayout)+"#"+rollup.Name), update, &daily)
+}
+
+// PutAnonymousChatterRollup stores a daily chatter rollup under a random ID instead of the
+// user's, without their user ID or name. It returns the approximate size of the rollup.
+func (r *dynamoDBRepository) PutAnonymousChatterRollup(ctx context.Context, rollup *models.ChatterRollup) (int64, error) {
+	day := rollup.Hour.UTC().Truncate(24 * time.Hour)
+	anonymous := &models.ChatterRollup{
+		ChatroomID: rollup.ChatroomID,
+		Hour:       day,
+		Messages:   rollup.Messages,
+	}
+
+	item, err := dynamodbattribute.MarshalMap(anonymous)
+	if err != nil {
+		return 0, fmt.Errorf("failed to marshal chatter rollup: %w", err)
+	}
+	item["bucket"] = &dynamodb.AttributeValue{S: aws.String(chatterBucket(day, "anon-"+uuid.New().String(), true))}
+
+	_, err = r.db.PutItemWithContext(ctx, &dynamodb.PutItemInput{
+		TableName: aws.String(r.rollupTable),
+		Item:      item,
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to put anonymous chatter rollup: %w", err)
+	}
+
+	return itemSize(item), nil
+}
+
+// scanRollups calls fn for every rollup item matching filter
+func (r *dynamoDBRepository) scanRollups(ctx context.Context, filter expression.ConditionBuilder, fn func(map[string]*dynamodb.AttributeValue) error) error {
+	expr, err := expression.NewBuilder().WithFilter(filter).Build()
+	if err != nil {
+		return fmt.Errorf("failed to build filter expression: %w", err)
+	}
+
+	input := &dynamodb.ScanInput{
+		TableName:                 aws.String(r.rollupTable),
+		FilterExpression:          expr.Filter(),
+		ExpressionAttributeNames:  expr.Names(),
+		ExpressionAttributeValues: expr.Values(),
+	}
+
+	var fnErr error
+	err = r.db.ScanPagesWithContext(ctx, input, func(page *dynamodb.ScanOutput, lastPage bool) bool {
+		for _, item := range page.Items {
+			if fnErr = fn(item); fnErr != nil {
+				return false
+			}
+		}
+		return true
+	})
+	if err != nil {
+		return fmt.Errorf("failed to scan chat rollups: %w", err)
+	}
+	return fnErr
+}
+
+// takeRollup deletes a rollup item and returns it, or nil if it didn't exist
+func (r *dynamoDBRepository) takeRollup(ctx context.Context, key map[string]*dynamodb.AttributeValue) (map[string]*dynamodb.AttributeValue, error) {
+	result, err := r.db.DeleteItemWithContext(ctx, &dynamodb.DeleteItemInput{
+		TableName:           aws.String(r.rollupTable),
+		Key:                 key,
+		ConditionExpression: aws.String("attribute_exists(bucket)"),
+		ReturnValues:        aws.String(dynamodb.ReturnValueAllOld),
+	})
+	if err != nil {
+		var conditionErr *dynamodb.ConditionalCheckFailedException
+		if errors.As(err, &conditionErr) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to delete chat rollup: %w", err)
+	}
+	return result.Attributes, nil
+}
+
+// addDailyRollup applies update to a daily rollup. It returns the approximate size of rollup,
+// the rollup as created, if the update created it.
+func (r *dynamoDBRepository) addDailyRollup(ctx context.Context, key map[string]*dynamodb.AttributeValue, update expression.UpdateBuilder, rollup interface{}) (int64, error) {
+	expr, err := expression.NewBuilder().WithUpdate(update).Build()
+	if err != nil {
+		return 0, fmt.Errorf("failed to build update expression: %w", err)
+	}
+
+	result, err := r.db.UpdateItemWithContext(ctx, &dynamodb.UpdateItemInput{
+		TableName:                 aws.String(r.rollupTable),
+		Key:                       key,
+		UpdateExpression:          expr.Update(),
+		ExpressionAttributeNames:  expr.Names(),
+		ExpressionAttributeValues: expr.Values(),
+		ReturnValues:              aws.String(dynamodb.ReturnValueUpdatedOld),
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to add daily rollup: %w", err)
+	}
+	if len(result.Attributes) > 0 {
+		return 0, nil // added to an existing rollup
+	}
+
+	item, err := dynamodbattribute.MarshalMap(rollup)
+	if err != nil {
+		return 0, nil
+	}
+	for name, value := range key {
+		item[name] = value
+	}
+	return itemSize(item), nil
+}
+
+// itemSize approximates the storage an item takes, from the length of its attribute names
+// and values
+func itemSize(item map[string]*dynamodb.AttributeValue) int64 {
+	var size int64
+	for name, value := range item {
+		size += int64(len(name))
+		switch {
+		case value.S != nil:
+			size += int64(len(*value.S))
+		case value.N != nil:
+			size += int64(len(*value.N))
+		case value.B != nil:
+			size += int64(len(value.B))
+		default:
+			size++
+		}
+	}
+	return size
+}
+
+func chatterBucketPrefix(daily bool) string {
+	if daily {
+		return "du#"
+	}
+	return "u#"
+}
+
+// chatterBucket is the sort key of a user's hourly, or daily, rollup in a room
+func chatterBucket(hour time.Time, userID string, daily bool) string {
+	if daily {
+		return "du#" + hour.UTC().Format(rollupDayLayout) + "#" + userID
+	}
+	return "u#" + hour.UTC().Truncate(time.Hour).Format(rollupHourLayout) + "#" + userID
+}
+
+// queryRollups reads every rollup item of a room with a bucket between from and to
+func (r *dynamoDBRepository) queryRollups(ctx context.Context, chatroomID, from, to string) ([]map[string]*dynamodb.AttributeValue, error) {
+	keyCond := expression.Key("chatroom_id").Equal(expression.Value(chatroomID)).
+		And(expression.Key("bucket").Between(expression.Value(from), expression.Value(to)))
+	expr, err := expression.NewBuilder().WithKeyCondition(keyCond).Build()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build key condition: %w", err)
+	}
+
+	input := &dynamodb.QueryInput{
+		TableName:                 aws.String(r.rollupTable),
+		KeyConditionExpression:    expr.KeyCondition(),
+		ExpressionAttributeNames:  expr.Names(),
+		ExpressionAttributeValues: expr.Values(),
+	}
+
+	var items []map[string]*dynamodb.AttributeValue
+	err = r.db.QueryPagesWithContext(ctx, input, func(page *dynamodb.QueryOutput, lastPage bool) bool {
+		items = append(items, page.Items...)
+		return true
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to query chat rollups: %w", err)
+	}
+	return items, nil
+}
+
+func rollupKey(chatroomID, bucket string) map[string]*dynamodb.AttributeValue {
+	return map[string]*dynamodb.AttributeValue{
+		"chatroom_id": {
+			S: aws.String(chatroomID),
+		},
+		"bucket": {
+			S: aws.String(bucket),
+		},
+	}
+}
+
+func vodCommentKey(comment *models.VODComment) map[string]*dynamodb.AttributeValue {
+	return map[string]*dynamodb.AttributeValue{
+		"vod_id": {
+			S: aws.String(comment.VODID),
+		},
+		"position_key": {
+			S: aws.String(comment.PositionKey),
+		},
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/go-redis/redis/v8"
@@ -18,11 +19,89 @@ type RedisRepository interface {
 	RemoveUserFromChatroom(ctx context.Context, userID, chatroomID string) error
 	CacheMessage(ctx context.Context, message *models.Message) error
 	GetCachedMessages(ctx context.Context, chatroomID string, limit int) ([]*models.Message, error)
+	RemoveCachedMessage(ctx context.Context, message *models.Message) error
 	SetUserOnline(ctx context.Context, userID string) error
 	SetUserOffline(ctx context.Context, userID string) error
 	IsUserOnline(ctx context.Context, userID string) (bool, error)
+	SetSquadRoute(ctx context.Context, route *models.SquadRoute, ttl time.Duration) error
+	GetSquadRoute(ctx context.Context, squadID string) (*models.SquadRoute, error)
+	GetChatroomSquad(ctx context.Context, chatroomID string) (string, error)
+	DeleteSquadRoute(ctx context.Context, squadID string) error
+	SetRoomPage(ctx context.Context, chatroom *models.Chatroom, ttl time.Duration) error
+	IncrRoomMemberCount(ctx context.Context, chatroomID string, delta int64) error
+	IncrRoomEmotes(ctx context.Context, chatroomID string, emotes map[string]int64, ttl time.Duration) error
+	DeleteRoomPage(ctx context.Context, chatroomID string) error
+	GetRoomSnapshot(ctx context.Context, chatroomID string, recentLimit, emoteLimit int) (*models.RoomSnapshot, error)
+	AcquireConnectionSlot(ctx context.Context, scope, id string, limit int, ttl time.Duration) (bool, error)
+	ReleaseConnectionSlot(ctx context.Context, scope, id string) error
+	PublishAutomodInvalidation(ctx context.Context, invalidation *models.AutomodInvalidation) error
+	SubscribeAutomodInvalidations(ctx context.Context) <-chan *models.AutomodInvalidation
+	AddRollupChatters(ctx context.Context, chatroomID string, hour time.Time, userIDs []string) (int64, error)
+	SeedRoomEmotes(ctx context.Context, chatroomID string, emotes []models.EmoteCount, ttl time.Duration) error
+	PopPendingEmotes(ctx context.Context, maxRooms int) ([]*models.EmoteRollup, error)
+	RestorePendingEmotes(ctx context.Context, rollups []*models.EmoteRollup) error
 }
 
+// automodChannel carries dictionary changes to every replica
+const automodChannel = "automod:invalidations"
+
+// pendingEmotesKey is the set of room hours with emote counts not flushed to DynamoDB yet,
+// as "<chatroom ID>/<hour>"
+const pendingEmotesKey = "emotes:pending"
+
+// pendingEmotesTTL keeps unflushed counts around if no replica flushes them for a while
+const pendingEmotesTTL = 48 * time.Hour
+
+// acquireSlot counts a connection unless that would go over the limit
+var acquireSlot = redis.NewScript(`
+local count = redis.call('INCR', KEYS[1])
+if count > tonumber(ARGV[1]) then
+	redis.call('DECR', KEYS[1])
+	return 0
+end
+redis.call('PEXPIRE', KEYS[1], ARGV[2])
+return 1
+`)
+
+// releaseSlot uncounts a connection, dropping the counter when it reaches zero so it never
+// goes negative after expiring
+var releaseSlot = redis.NewScript(`
+if redis.call('DECR', KEYS[1]) <= 0 then
+	redis.call('DEL', KEYS[1])
+end
+return 1
+`)
+
+// incrIfExists bumps a counter of a room page only if the page is there, so an update never
+// creates a page with nothing but a counter in it
+var incrIfExists = redis.NewScript(`
+if redis.call('EXISTS', KEYS[1]) == 1 then
+	redis.call('HINCRBY', KEYS[1], ARGV[1], ARGV[2])
+	redis.call('HSET', KEYS[1], 'updated_at', ARGV[3])
+	return 1
+end
+return 0
+`)
+
+// takeHash reads a hash and deletes it in one step, so counts added meanwhile are never lost
+var takeHash = redis.NewScript(`
+local fields = redis.call('HGETALL', KEYS[1])
+redis.call('DEL', KEYS[1])
+return fields
+`)
+
+// seedIfMissing fills a sorted set only if it doesn't exist, so counts are never added twice
+var seedIfMissing = redis.NewScript(`
+if redis.call('EXISTS', KEYS[1]) == 1 then
+	return 0
+end
+for i = 2, #ARGV, 2 do
+	redis.call('ZADD', KEYS[1], ARGV[i], ARGV[i + 1])
+end
+redis.call('PEXPIRE', KEYS[1], ARGV[1])
+return 1
+`)
+
 type redisRepository struct {
 	client *redis.Client
 }
@@ -48,6 +127,25 @@ func NewRedisRepository(cfg config.RedisConfig) (RedisRepository, error) {
 	}, nil
 }
 
+// PingRedis checks that Redis is reachable without keeping a connection open
+func PingRedis(cfg config.RedisConfig) error {
+	client := redis.NewClient(&redis.Options{
+		Addr:     cfg.Address,
+		Password: cfg.Password,
+		DB:       cfg.DB,
+	})
+	defer client.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := client.Ping(ctx).Err(); err != nil {
+		return fmt.Errorf("failed to ping Redis at %s: %w", cfg.Address, err)
+	}
+
+	return nil
+}
+
 func (r *redisRepository) AddUserToChatroom(ctx context.Context, userID, chatroomID string) error {
 	key := fmt.Sprintf("user:%s:chatrooms", userID)
 	return r.client.SAdd(ctx, key, chatroomID).Err()
@@ -82,6 +180,29 @@ func (r *redisRepository) CacheMessage(ctx context.Context, message *models.Mess
 	return nil
 }
 
+// RemoveCachedMessage drops a message from its room's cache. Messages are cached by value, so
+// the ones sharing its timestamp are looked through for its ID.
+func (r *redisRepository) RemoveCachedMessage(ctx context.Context, message *models.Message) error {
+	key := fmt.Sprintf("chatroom:%s:messages", message.ChatroomID)
+	score := strconv.FormatInt(message.CreatedAt.Unix(), 10)
+
+	members, err := r.client.ZRangeByScore(ctx, key, &redis.ZRangeBy{Min: score, Max: score}).Result()
+	if err != nil {
+		return fmt.Errorf("failed to get cached messages: %w", err)
+	}
+
+	for _, member := range members {
+		var cached models.Message
+		if err := json.Unmarshal([]byte(member), &cached); err != nil || cached.ID != message.ID {
+			continue
+		}
+		if err := r.client.ZRem(ctx, key, member).Err(); err != nil {
+			return fmt.Errorf("failed to remove cached message: %w", err)
+		}
+	}
+	return nil
+}
+
 func (r *redisRepository) GetCachedMessages(ctx context.Context, chatroomID string, limit int) ([]*models.Message, error) {
 	key := fmt.Sprintf("chatroom:%s:messages", chatroomID)
 
@@ -131,3 +252,364 @@ func (r *redisRepository) IsUserOnline(ctx context.Context, userID string) (bool
 
 	return online, nil
 }
+
+func (r *redisRepository) SetSquadRoute(ctx context.Context, route *models.SquadRoute, ttl time.Duration) error {
+	previous, err := r.GetSquadRoute(ctx, route.SquadID)
+	if err != nil {
+		return err
+	}
+
+	routeJSON, err := json.Marshal(route)
+	if err != nil {
+		return fmt.Errorf("failed to marshal squad route: %w", err)
+	}
+
+	pipe := r.client.TxPipeline()
+	if previous != nil {
+		// Rooms that left the squad go back to normal
+		for _, chatroomID := range previous.ChatroomIDs {
+			pipe.Del(ctx, fmt.Sprintf("chatroom:%s:squad", chatroomID))
+		}
+	}
+	pipe.Set(ctx, fmt.Sprintf("squad:%s:route", route.SquadID), routeJSON, ttl)
+	for _, chatroomID := range route.ChatroomIDs {
+		pipe.Set(ctx, fmt.Sprintf("chatroom:%s:squad", chatroomID), route.SquadID, ttl)
+	}
+
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("failed to set squad route: %w", err)
+	}
+	return nil
+}
+
+// GetSquadRoute returns nil if the squad has no route
+func (r *redisRepository) GetSquadRoute(ctx context.Context, squadID string) (*models.SquadRoute, error) {
+	result, err := r.client.Get(ctx, fmt.Sprintf("squad:%s:route", squadID)).Result()
+	if err == redis.Nil {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get squad route: %w", err)
+	}
+
+	var route models.SquadRoute
+	if err := json.Unmarshal([]byte(result), &route); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal squad route: %w", err)
+	}
+	return &route, nil
+}
+
+// GetChatroomSquad returns the squad a chat room belongs to, or "" if it isn't in one
+func (r *redisRepository) GetChatroomSquad(ctx context.Context, chatroomID string) (string, error) {
+	squadID, err := r.client.Get(ctx, fmt.Sprintf("chatroom:%s:squad", chatroomID)).Result()
+	if err == redis.Nil {
+		return "", nil
+	}
+	return squadID, err
+}
+
+func (r *redisRepository) DeleteSquadRoute(ctx context.Context, squadID string) error {
+	route, err := r.GetSquadRoute(ctx, squadID)
+	if err != nil || route == nil {
+		return err
+	}
+
+	keys := []string{fmt.Sprintf("squad:%s:route", squadID)}
+	for _, chatroomID := range route.ChatroomIDs {
+		keys = append(keys, fmt.Sprintf("chatroom:%s:squad", chatroomID))
+	}
+	return r.client.Del(ctx, keys...).Err()
+}
+
+// SetRoomPage writes the settings, member count and pins of a room page. Recent messages come
+// from the message cache and emotes are counted separately.
+func (r *redisRepository) SetRoomPage(ctx context.Context, chatroom *models.Chatroom, ttl time.Duration) error {
+	settings := *chatroom
+	settings.MemberIDs = nil
+	settings.PinnedMessages = nil
+
+	settingsJSON, err := json.Marshal(settings)
+	if err != nil {
+		return fmt.Errorf("failed to marshal room settings: %w", err)
+	}
+	pinnedJSON, err := json.Marshal(chatroom.PinnedMessages)
+	if err != nil {
+		return fmt.Errorf("failed to marshal pinned messages: %w", err)
+	}
+
+	key := fmt.Sprintf("chatroom:%s:page", chatroom.ID)
+	pipe := r.client.TxPipeline()
+	pipe.Del(ctx, key)
+	pipe.HSet(ctx, key,
+		"settings", settingsJSON,
+		"pinned", pinnedJSON,
+		"member_count", len(chatroom.MemberIDs),
+		"updated_at", time.Now().Unix(),
+	)
+	pipe.Expire(ctx, key, ttl)
+
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("failed to set room page: %w", err)
+	}
+	return nil
+}
+
+func (r *redisRepository) IncrRoomMemberCount(ctx context.Context, chatroomID string, delta int64) error {
+	key := fmt.Sprintf("chatroom:%s:page", chatroomID)
+	if err := incrIfExists.Run(ctx, r.client, []string{key}, "member_count", delta, time.Now().Unix()).Err(); err != nil {
+		return fmt.Errorf("failed to update room member count: %w", err)
+	}
+	return nil
+}
+
+// IncrRoomEmotes counts emotes on the room page and in the room's pending counts of the
+// current hour, which are flushed to DynamoDB
+func (r *redisRepository) IncrRoomEmotes(ctx context.Context, chatroomID string, emotes map[string]int64, ttl time.Duration) error {
+	key := fmt.Sprintf("chatroom:%s:emotes", chatroomID)
+	hour := time.Now().UTC().Format(rollupHourLayout)
+	pendingKey := pendingEmotesHashKey(chatroomID, hour)
+
+	pipe := r.client.Pipeline()
+	for name, count := range emotes {
+		pipe.ZIncrBy(ctx, key, float64(count), name)
+		pipe.HIncrBy(ctx, pendingKey, name, count)
+	}
+	pipe.Expire(ctx, key, ttl)
+	pipe.Expire(ctx, pendingKey, pendingEmotesTTL)
+	// Added after the counts, so a flush that takes the room hour sees them
+	pipe.SAdd(ctx, pendingEmotesKey, chatroomID+"/"+hour)
+
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("failed to count room emotes: %w", err)
+	}
+	return nil
+}
+
+func (r *redisRepository) DeleteRoomPage(ctx context.Context, chatroomID string) error {
+	return r.client.Del(ctx, fmt.Sprintf("chatroom:%s:page", chatroomID)).Err()
+}
+
+// GetRoomSnapshot reads a whole room page in one round trip. It returns nil if the page
+// hasn't been built yet or expired.
+func (r *redisRepository) GetRoomSnapshot(ctx context.Context, chatroomID string, recentLimit, emoteLimit int) (*models.RoomSnapshot, error) {
+	pipe := r.client.Pipeline()
+	pageCmd := pipe.HGetAll(ctx, fmt.Sprintf("chatroom:%s:page", chatroomID))
+	emotesCmd := pipe.ZRevRangeWithScores(ctx, fmt.Sprintf("chatroom:%s:emotes", chatroomID), 0, int64(emoteLimit-1))
+	messagesCmd := pipe.ZRevRange(ctx, fmt.Sprintf("chatroom:%s:messages", chatroomID), 0, int64(recentLimit-1))
+	if _, err := pipe.Exec(ctx); err != nil && err != redis.Nil {
+		return nil, fmt.Errorf("failed to get room snapshot: %w", err)
+	}
+
+	page := pageCmd.Val()
+	if len(page) == 0 {
+		return nil, nil
+	}
+
+	snapshot := &models.RoomSnapshot{
+		PinnedMessages: []*models.Message{},
+		TopEmotes:      []models.EmoteCount{},
+		RecentMessages: []*models.Message{},
+	}
+	if err := json.Unmarshal([]byte(page["settings"]), &snapshot.Chatroom); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal room settings: %w", err)
+	}
+	if err := json.Unmarshal([]byte(page["pinned"]), &snapshot.PinnedMessages); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal pinned messages: %w", err)
+	}
+	snapshot.MemberCount, _ = strconv.ParseInt(page["member_count"], 10, 64)
+	if updatedAt, err := strconv.ParseInt(page["updated_at"], 10, 64); err == nil {
+		snapshot.UpdatedAt = time.Unix(updatedAt, 0)
+	}
+
+	for _, emote := range emotesCmd.Val() {
+		snapshot.TopEmotes = append(snapshot.TopEmotes, models.EmoteCount{
+			Name:  emote.Member.(string),
+			Count: int64(emote.Score),
+		})
+	}
+
+	for _, messageJSON := range messagesCmd.Val() {
+		var message models.Message
+		if err := json.Unmarshal([]byte(messageJSON), &message); err != nil {
+			continue // Skip invalid messages
+		}
+		snapshot.RecentMessages = append(snapshot.RecentMessages, &message)
+	}
+
+	return snapshot, nil
+}
+
+// AcquireConnectionSlot counts a WebSocket connection of a user or IP ("user" or "ip" scope)
+// and reports false if the limit is already reached. The counter expires after ttl without
+// new connections, in case an instance died without releasing its slots.
+func (r *redisRepository) AcquireConnectionSlot(ctx context.Context, scope, id string, limit int, ttl time.Duration) (bool, error) {
+	key := fmt.Sprintf("ws:%s:%s:connections", scope, id)
+	acquired, err := acquireSlot.Run(ctx, r.client, []string{key}, limit, ttl.Milliseconds()).Int()
+	if err != nil {
+		return false, fmt.Errorf("failed to acquire connection slot: %w", err)
+	}
+	return acquired == 1, nil
+}
+
+func (r *redisRepository) ReleaseConnectionSlot(ctx context.Context, scope, id string) error {
+	key := fmt.Sprintf("ws:%s:%s:connections", scope, id)
+	if err := releaseSlot.Run(ctx, r.client, []string{key}).Err(); err != nil {
+		return fmt.Errorf("failed to release connection slot: %w", err)
+	}
+	return nil
+}
+
+func (r *redisRepository) PublishAutomodInvalidation(ctx context.Context, invalidation *models.AutomodInvalidation) error {
+	payload, err := json.Marshal(invalidation)
+	if err != nil {
+		return fmt.Errorf("failed to marshal automod invalidation: %w", err)
+	}
+	if err := r.client.Publish(ctx, automodChannel, payload).Err(); err != nil {
+		return fmt.Errorf("failed to publish automod invalidation: %w", err)
+	}
+	return nil
+}
+
+// SubscribeAutomodInvalidations delivers dictionary changes until ctx is done. The client
+// resubscribes by itself after a dropped connection, changes published meanwhile are lost.
+func (r *redisRepository) SubscribeAutomodInvalidations(ctx context.Context) <-chan *models.AutomodInvalidation {
+	pubsub := r.client.Subscribe(ctx, automodChannel)
+	invalidations := make(chan *models.AutomodInvalidation)
+
+	go func() {
+		defer close(invalidations)
+		defer pubsub.Close()
+
+		messages := pubsub.Channel()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case message, ok := <-messages:
+				if !ok {
+					return
+				}
+				var invalidation models.AutomodInvalidation
+				if err := json.Unmarshal([]byte(message.Payload), &invalidation); err != nil {
+					continue // Skip invalid payloads
+				}
+				select {
+				case invalidations <- &invalidation:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+
+	return invalidations
+}
+
+// AddRollupChatters adds users to the chatters of a room's hour and returns how many distinct
+// users chatted in it. Every replica adds to the same HyperLogLog, so the count holds across
+// replicas without keeping the users.
+func (r *redisRepository) AddRollupChatters(ctx context.Context, chatroomID string, hour time.Time, userIDs []string) (int64, error) {
+	key := fmt.Sprintf("chatroom:%s:chatters:%s", chatroomID, hour.UTC().Format(rollupHourLayout))
+
+	members := make([]interface{}, len(userIDs))
+	for i, userID := range userIDs {
+		members[i] = userID
+	}
+
+	pipe := r.client.Pipeline()
+	pipe.PFAdd(ctx, key, members...)
+	count := pipe.PFCount(ctx, key)
+	// Flushes of the hour's last events happen after it ended
+	pipe.Expire(ctx, key, 2*time.Hour)
+
+	if _, err := pipe.Exec(ctx); err != nil {
+		return 0, fmt.Errorf("failed to count rollup chatters: %w", err)
+	}
+	return count.Val(), nil
+}
+
+// SeedRoomEmotes fills the emote counts of a room page whose counts expired. Counts that are
+// already there are left alone.
+func (r *redisRepository) SeedRoomEmotes(ctx context.Context, chatroomID string, emotes []models.EmoteCount, ttl time.Duration) error {
+	if len(emotes) == 0 {
+		return nil
+	}
+
+	args := make([]interface{}, 0, 1+2*len(emotes))
+	args = append(args, ttl.Milliseconds())
+	for _, emote := range emotes {
+		args = append(args, emote.Count, emote.Name)
+	}
+
+	key := fmt.Sprintf("chatroom:%s:emotes", chatroomID)
+	if err := seedIfMissing.Run(ctx, r.client, []string{key}, args...).Err(); err != nil {
+		return fmt.Errorf("failed to seed room emotes: %w", err)
+	}
+	return nil
+}
+
+// PopPendingEmotes takes the pending emote counts of up to maxRooms room hours. The caller
+// owns them from then on and must restore them if it can't flush them.
+func (r *redisRepository) PopPendingEmotes(ctx context.Context, maxRooms int) ([]*models.EmoteRollup, error) {
+	members, err := r.client.SPopN(ctx, pendingEmotesKey, int64(maxRooms)).Result()
+	if err != nil && err != redis.Nil {
+		return nil, fmt.Errorf("failed to pop pending emotes: %w", err)
+	}
+
+	var rollups []*models.EmoteRollup
+	for _, member := range members {
+		separator := strings.LastIndex(member, "/")
+		if separator < 0 {
+			continue // Skip invalid members
+		}
+		chatroomID, hourText := member[:separator], member[separator+1:]
+		hour, err := time.Parse(rollupHourLayout, hourText)
+		if err != nil {
+			continue // Skip invalid members
+		}
+
+		fields, err := takeHash.Run(ctx, r.client, []string{pendingEmotesHashKey(chatroomID, hourText)}).StringSlice()
+		if err != nil {
+			return rollups, fmt.Errorf("failed to take pending emotes of chatroom %s: %w", chatroomID, err)
+		}
+		for i := 0; i+1 < len(fields); i += 2 {
+			count, err := strconv.ParseInt(fields[i+1], 10, 64)
+			if err != nil || count <= 0 {
+				continue
+			}
+			rollups = append(rollups, &models.EmoteRollup{
+				ChatroomID: chatroomID,
+				Hour:       hour,
+				Name:       fields[i],
+				Count:      count,
+			})
+		}
+	}
+
+	return rollups, nil
+}
+
+// RestorePendingEmotes puts back counts taken by PopPendingEmotes that couldn't be flushed
+func (r *redisRepository) RestorePendingEmotes(ctx context.Context, rollups []*models.EmoteRollup) error {
+	if len(rollups) == 0 {
+		return nil
+	}
+
+	pipe := r.client.Pipeline()
+	for _, rollup := range rollups {
+		hour := rollup.Hour.UTC().Format(rollupHourLayout)
+		pendingKey := pendingEmotesHashKey(rollup.ChatroomID, hour)
+		pipe.HIncrBy(ctx, pendingKey, rollup.Name, rollup.Count)
+		pipe.Expire(ctx, pendingKey, pendingEmotesTTL)
+		pipe.SAdd(ctx, pendingEmotesKey, rollup.ChatroomID+"/"+hour)
+	}
+
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("failed to restore pending emotes: %w", err)
+	}
+	return nil
+}
+
+func pendingEmotesHashKey(chatroomID, hour string) string {
+	return fmt.Sprintf("chatroom:%s:emotes:%s", chatroomID, hour)
+}
@@ -1,11 +1,19 @@
 package server
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"hash/fnv"
 	"log"
 	"net/http"
 	"sync"
+	"time"
 
+	"github.com/google/uuid"
 	"github.com/gorilla/websocket"
+
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/services/chat-service/internal/models"
 )
 
 var upgrader = websocket.Upgrader{
@@ -20,169 +28,507 @@ var upgrader = websocket.Upgrader{
 
 // Client represents a WebSocket client
 type Client struct {
-	Conn     *websocket.Conn // Exported
-	Send     chan []byte     // Exported
-	Hub      *Hub            // Exported
-	UserID   string          // Exported
-	Username string          // Exported
-	Rooms    map[string]bool // Exported
+	Conn     *websocket.Conn                 // Exported
+	Send     chan *websocket.PreparedMessage // Exported, frames are shared by every recipient
+	Hub      *Hub                            // Exported
+	UserID   string                          // Exported
+	Username string                          // Exported
+	Rooms    map[string]bool                 // Exported
+	OnClose  func()                          // called once the connection ends, e.g. to release its quota
+	Verified bool                            // UserID was signed in by the gateway, not just named by the client
+
+	roomsMutex sync.Mutex   // guards Rooms, which the hub's shards update concurrently
+	sendMutex  sync.RWMutex // keeps Send from being closed during a send
+	closed     bool
+}
+
+// RoomRoute lists the rooms a room's messages are delivered to
+type RoomRoute struct {
+	SquadID string   `json:"squad_id"`
+	Mode    string   `json:"mode"` // merged or side_by_side
+	Rooms   []string `json:"chatroom_ids"`
+}
+
+// RoomRouter links rooms together, e.g. the chats of a squad stream. Route returns nil
+// for rooms that aren't linked.
+type RoomRouter interface {
+	Route(roomID string) *RoomRoute
 }
 
-// Hub maintains active WebSocket connections
+// RoomSnapshotter builds the frame a client gets when it joins a room, so it can show the
+// room at once instead of fetching its history first. A nil frame sends nothing.
+type RoomSnapshotter interface {
+	JoinSnapshot(ctx context.Context, roomID, userID string) ([]byte, error)
+}
+
+// MessageFilter screens what clients send to a room. It returns the content to deliver, or
+// blocked to drop the message.
+type MessageFilter interface {
+	Moderate(ctx context.Context, roomID, content string) (string, bool)
+}
+
+// MessageObserver is told about every message clients send to a room once it was delivered,
+// e.g. to moderate it after the fact, and about the ones the filter blocked
+type MessageObserver interface {
+	MessageDelivered(message *models.Message)
+	MessageBlocked(roomID, userID string)
+}
+
+// joinSnapshotTimeout bounds how long a join waits for its snapshot, the client joins
+// without one after that
+const joinSnapshotTimeout = 2 * time.Second
+
+// roomMessage is a chat message as delivered to the clients of a room
+type roomMessage struct {
+	Type       string `json:"type"`
+	ID         string `json:"id"`
+	ChatroomID string `json:"chatroom_id"`
+	UserID     string `json:"user_id"`
+	Username   string `json:"username"`
+	Content    string `json:"content"`
+	SentAt     int64  `json:"sent_at"`
+}
+
+// encodeBuffers are reused to serialize outbound messages
+var encodeBuffers = sync.Pool{
+	New: func() interface{} { return new(bytes.Buffer) },
+}
+
+// encodeMessage serializes an outbound message into a pooled buffer and returns an exactly
+// sized copy, which the prepared frames keep referencing
+func encodeMessage(v interface{}) ([]byte, error) {
+	buf := encodeBuffers.Get().(*bytes.Buffer)
+	defer func() {
+		buf.Reset()
+		encodeBuffers.Put(buf)
+	}()
+
+	if err := json.NewEncoder(buf).Encode(v); err != nil {
+		return nil, err
+	}
+	return append([]byte(nil), bytes.TrimSuffix(buf.Bytes(), []byte("\n"))...), nil
+}
+
+// prepare frames a message once so fanning it out costs no per-client serialization or
+// compression. It returns nil if the message can't be framed.
+func prepare(message []byte) *websocket.PreparedMessage {
+	prepared, err := websocket.NewPreparedMessage(websocket.TextMessage, message)
+	if err != nil {
+		log.Printf("Failed to prepare message: %v", err)
+		return nil
+	}
+	return prepared
+}
+
+// inboundMessage is what clients send over the socket
+type inboundMessage struct {
+	Type       string `json:"type"` // join, leave or message
+	ChatroomID string `json:"chatroom_id"`
+	Content    string `json:"content"`
+}
+
+// defaultHubShards is used when NewWebSocketHub isn't given a shard count
+const defaultHubShards = 32
+
+// Hub maintains active WebSocket connections. Rooms and users are spread over shards with
+// their own locks, so joins, leaves and broadcasts in different rooms don't wait on each
+// other. A room lives in the shard its ID hashes to, a client in the shard of its user ID.
 type Hub struct {
-	clients    map[*Client]bool
-	broadcast  chan []byte
-	register   chan *Client
-	unregister chan *Client
-	rooms      map[string]map[*Client]bool
-	mutex      sync.RWMutex
+	shards    []*hubShard
+	broadcast chan []byte
+	done      chan struct{}
+	router    RoomRouter
+	snapshots RoomSnapshotter
+	filter    MessageFilter
+	observers []MessageObserver
+}
+
+type hubShard struct {
+	mutex   sync.RWMutex
+	rooms   map[string]map[*Client]bool
+	clients map[*Client]bool
 }
 
-// NewWebSocketHub creates a new WebSocket hub
-func NewWebSocketHub() *Hub {
-	return &Hub{
-		clients:    make(map[*Client]bool),
-		broadcast:  make(chan []byte),
-		register:   make(chan *Client),
-		unregister: make(chan *Client),
-		rooms:      make(map[string]map[*Client]bool),
+// NewWebSocketHub creates a new WebSocket hub split into the given number of shards
+func NewWebSocketHub(shards int) *Hub {
+	if shards <= 0 {
+		shards = defaultHubShards
+	}
+
+	h := &Hub{
+		shards:    make([]*hubShard, shards),
+		broadcast: make(chan []byte, 256),
+		done:      make(chan struct{}),
+	}
+	for i := range h.shards {
+		h.shards[i] = &hubShard{
+			rooms:   make(map[string]map[*Client]bool),
+			clients: make(map[*Client]bool),
+		}
 	}
+	return h
 }
 
-// Run starts the WebSocket hub
+// shard returns the shard a room or user ID belongs to
+func (h *Hub) shard(id string) *hubShard {
+	hash := fnv.New32a()
+	hash.Write([]byte(id))
+	return h.shards[hash.Sum32()%uint32(len(h.shards))]
+}
+
+// Run delivers messages sent with Broadcast until the hub is closed
 func (h *Hub) Run() {
 	for {
 		select {
-		case client := <-h.register:
-			h.registerClient(client)
-
-		case client := <-h.unregister:
-			h.unregisterClient(client)
-
 		case message := <-h.broadcast:
 			h.broadcastMessage(message)
+		case <-h.done:
+			return
 		}
 	}
 }
 
 // Close gracefully shuts down the hub
 func (h *Hub) Close() {
-	h.mutex.Lock()
-	defer h.mutex.Unlock()
+	close(h.done)
 
-	for client := range h.clients {
-		close(client.Send)
-		client.Conn.Close()
+	for _, shard := range h.shards {
+		shard.mutex.Lock()
+		for client := range shard.clients {
+			client.close()
+			client.Conn.Close()
+		}
+		shard.mutex.Unlock()
 	}
 }
 
 func (h *Hub) registerClient(client *Client) {
-	h.mutex.Lock()
-	defer h.mutex.Unlock()
+	shard := h.shard(client.UserID)
+	shard.mutex.Lock()
+	shard.clients[client] = true
+	shard.mutex.Unlock()
 
-	h.clients[client] = true
 	log.Printf("Client registered: %s (%s)", client.Username, client.UserID)
 }
 
 func (h *Hub) unregisterClient(client *Client) {
-	h.mutex.Lock()
-	defer h.mutex.Unlock()
-
-	if _, ok := h.clients[client]; ok {
-		delete(h.clients, client)
-		close(client.Send)
-
-		// Remove from all rooms
-		for roomID := range client.Rooms {
-			if room, exists := h.rooms[roomID]; exists {
-				delete(room, client)
-				if len(room) == 0 {
-					delete(h.rooms, roomID)
-				}
-			}
-		}
+	shard := h.shard(client.UserID)
+	shard.mutex.Lock()
+	_, ok := shard.clients[client]
+	delete(shard.clients, client)
+	shard.mutex.Unlock()
+	if !ok {
+		return
+	}
 
-		log.Printf("Client unregistered: %s (%s)", client.Username, client.UserID)
+	// Remove from all rooms
+	for _, roomID := range client.roomIDs() {
+		h.removeFromRoom(client, roomID)
 	}
+	client.close()
+
+	log.Printf("Client unregistered: %s (%s)", client.Username, client.UserID)
 }
 
 func (h *Hub) broadcastMessage(message []byte) {
-	h.mutex.RLock()
-	defer h.mutex.RUnlock()
+	prepared := prepare(message)
+	if prepared == nil {
+		return
+	}
 
-	for client := range h.clients {
-		select {
-		case client.Send <- message:
-		default:
-			close(client.Send)
-			delete(h.clients, client)
+	for _, shard := range h.shards {
+		shard.mutex.RLock()
+		for client := range shard.clients {
+			client.trySend(prepared)
 		}
+		shard.mutex.RUnlock()
 	}
 }
 
 // JoinRoom adds a client to a specific chat room
 func (h *Hub) JoinRoom(client *Client, roomID string) {
-	h.mutex.Lock()
-	defer h.mutex.Unlock()
+	h.joinRoom(client, roomID, nil)
+}
 
-	if h.rooms[roomID] == nil {
-		h.rooms[roomID] = make(map[*Client]bool)
+// JoinRoomWithSnapshot adds a client to a room with the room's snapshot as the first frame it
+// gets from it. Messages sent while the snapshot is read are only in the snapshot if they
+// were already cached.
+func (h *Hub) JoinRoomWithSnapshot(client *Client, roomID string) {
+	var snapshot *websocket.PreparedMessage
+	if h.snapshots != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), joinSnapshotTimeout)
+		frame, err := h.snapshots.JoinSnapshot(ctx, roomID, client.UserID)
+		cancel()
+		if err != nil {
+			log.Printf("Failed to get join snapshot of room %s: %v", roomID, err)
+		} else if frame != nil {
+			snapshot = prepare(frame)
+		}
 	}
 
-	h.rooms[roomID][client] = true
-	client.Rooms[roomID] = true
+	h.joinRoom(client, roomID, snapshot)
+}
+
+// joinRoom queues first while holding the room's shard lock, so no live message of the room
+// can get ahead of it
+func (h *Hub) joinRoom(client *Client, roomID string, first *websocket.PreparedMessage) {
+	shard := h.shard(roomID)
+	shard.mutex.Lock()
+	if shard.rooms[roomID] == nil {
+		shard.rooms[roomID] = make(map[*Client]bool)
+	}
+	shard.rooms[roomID][client] = true
+	if first != nil && !client.trySend(first) {
+		log.Printf("Dropping join snapshot for slow client %s", client.Username)
+	}
+	shard.mutex.Unlock()
+
+	client.setRoom(roomID, true)
 
 	log.Printf("Client %s joined room %s", client.Username, roomID)
 }
 
 // LeaveRoom removes a client from a specific chat room
 func (h *Hub) LeaveRoom(client *Client, roomID string) {
-	h.mutex.Lock()
-	defer h.mutex.Unlock()
+	h.removeFromRoom(client, roomID)
+	client.setRoom(roomID, false)
+
+	log.Printf("Client %s left room %s", client.Username, roomID)
+}
+
+func (h *Hub) removeFromRoom(client *Client, roomID string) {
+	shard := h.shard(roomID)
+	shard.mutex.Lock()
+	defer shard.mutex.Unlock()
 
-	if room, exists := h.rooms[roomID]; exists {
+	if room, exists := shard.rooms[roomID]; exists {
 		delete(room, client)
 		if len(room) == 0 {
-			delete(h.rooms, roomID)
+			delete(shard.rooms, roomID)
 		}
 	}
+}
 
-	delete(client.Rooms, roomID)
+// BroadcastToRoom sends a message to all clients in a specific room. Clients whose send
+// buffer is full miss the message.
+func (h *Hub) BroadcastToRoom(roomID string, message []byte) {
+	prepared := prepare(message)
+	if prepared == nil {
+		return
+	}
 
-	log.Printf("Client %s left room %s", client.Username, roomID)
+	shard := h.shard(roomID)
+	shard.mutex.RLock()
+	defer shard.mutex.RUnlock()
+
+	for client := range shard.rooms[roomID] {
+		if !client.trySend(prepared) {
+			log.Printf("Dropping message for slow client %s", client.Username)
+		}
+	}
 }
 
-// BroadcastToRoom sends a message to all clients in a specific room
-func (h *Hub) BroadcastToRoom(roomID string, message []byte) {
-	h.mutex.RLock()
-	defer h.mutex.RUnlock()
-
-	if room, exists := h.rooms[roomID]; exists {
-		for client := range room {
-			select {
-			case client.Send <- message:
-			default:
-				close(client.Send)
-				delete(h.clients, client)
-				delete(room, client)
-			}
+// SetRouter installs the router used by Publish
+func (h *Hub) SetRouter(router RoomRouter) {
+	h.router = router
+}
+
+// SetSnapshotter installs what builds the snapshots of JoinRoomWithSnapshot
+func (h *Hub) SetSnapshotter(snapshots RoomSnapshotter) {
+	h.snapshots = snapshots
+}
+
+func (h *Hub) moderate(roomID, content string) (string, bool) {
+	if h.filter == nil {
+		return content, false
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	return h.filter.Moderate(ctx, roomID, content)
+}
+
+// AddMessageObserver adds to what is told about delivered messages. Observers must be added
+// before Run.
+func (h *Hub) AddMessageObserver(observer MessageObserver) {
+	h.observers = append(h.observers, observer)
+}
+
+// SetMessageFilter installs the filter messages sent over the socket go through
+func (h *Hub) SetMessageFilter(filter MessageFilter) {
+	h.filter = filter
+}
+
+// route returns the route of a room, or nil if no router is installed or the room isn't linked
+func (h *Hub) route(roomID string) *RoomRoute {
+	if h.router == nil {
+		return nil
+	}
+	return h.router.Route(roomID)
+}
+
+// Publish sends a message to a room, and to every linked room when its squad chat is merged.
+// Clients in several of those rooms get the message once.
+func (h *Hub) Publish(roomID string, message []byte) {
+	route := h.route(roomID)
+	if route == nil || route.Mode != "merged" {
+		h.BroadcastToRoom(roomID, message)
+		return
+	}
+
+	prepared := prepare(message)
+	if prepared == nil {
+		return
+	}
+
+	recipients := make(map[*Client]bool)
+	for _, linked := range append([]string{roomID}, route.Rooms...) {
+		shard := h.shard(linked)
+		shard.mutex.RLock()
+		for client := range shard.rooms[linked] {
+			recipients[client] = true
+		}
+		shard.mutex.RUnlock()
+	}
+
+	for client := range recipients {
+		if !client.trySend(prepared) {
+			log.Printf("Dropping message for slow client %s", client.Username)
+		}
+	}
+}
+
+// SendToUser sends a message to every verified connection of a user, whichever rooms they
+// are in. Anyone can connect naming a user, so unverified ones never get what is meant for the
+// user alone. It returns the number of connections the message was queued for.
+func (h *Hub) SendToUser(userID string, message []byte) int {
+	prepared := prepare(message)
+	if prepared == nil {
+		return 0
+	}
+
+	shard := h.shard(userID)
+	shard.mutex.RLock()
+	defer shard.mutex.RUnlock()
+
+	sent := 0
+	for client := range shard.clients {
+		if client.UserID != userID || !client.Verified {
+			continue
+		}
+		if client.trySend(prepared) {
+			sent++
+		} else {
+			log.Printf("Dropping message for slow client %s", client.Username)
+		}
+	}
+	return sent
+}
+
+// PublishToUsers sends a message to the verified clients in a room that belong to one of the
+// given users, e.g. a room's moderators
+func (h *Hub) PublishToUsers(roomID string, userIDs []string, message []byte) {
+	prepared := prepare(message)
+	if prepared == nil {
+		return
+	}
+
+	allowed := make(map[string]bool, len(userIDs))
+	for _, userID := range userIDs {
+		allowed[userID] = true
+	}
+
+	shard := h.shard(roomID)
+	shard.mutex.RLock()
+	defer shard.mutex.RUnlock()
+
+	for client := range shard.rooms[roomID] {
+		if !allowed[client.UserID] || !client.Verified {
+			continue
+		}
+		if !client.trySend(prepared) {
+			log.Printf("Dropping message for slow client %s", client.Username)
 		}
 	}
 }
 
 // RegisterClient registers a new client with the hub
 func (h *Hub) RegisterClient(client *Client) {
-	h.register <- client
+	h.registerClient(client)
 }
 
 // UnregisterClient unregisters a client from the hub
 func (h *Hub) UnregisterClient(client *Client) {
-	h.unregister <- client
+	h.unregisterClient(client)
 }
 
 // Broadcast sends a message to all connected clients
 func (h *Hub) Broadcast(message []byte) {
-	h.broadcast <- message
+	select {
+	case h.broadcast <- message:
+	case <-h.done:
+	}
+}
+
+// trySend queues a message without blocking. It reports false if the client's buffer is
+// full or the client is gone.
+func (c *Client) trySend(message *websocket.PreparedMessage) bool {
+	if message == nil {
+		return false
+	}
+
+	c.sendMutex.RLock()
+	defer c.sendMutex.RUnlock()
+
+	if c.closed {
+		return false
+	}
+	select {
+	case c.Send <- message:
+		return true
+	default:
+		return false
+	}
+}
+
+// close closes the send channel once, after which trySend drops messages. Sends from other
+// shards may still hold the client, so the channel is never closed under them.
+func (c *Client) close() {
+	c.sendMutex.Lock()
+	defer c.sendMutex.Unlock()
+
+	if !c.closed {
+		c.closed = true
+		close(c.Send)
+	}
+}
+
+func (c *Client) setRoom(roomID string, joined bool) {
+	c.roomsMutex.Lock()
+	defer c.roomsMutex.Unlock()
+
+	if joined {
+		c.Rooms[roomID] = true
+	} else {
+		delete(c.Rooms, roomID)
+	}
+}
+
+func (c *Client) inRoom(roomID string) bool {
+	c.roomsMutex.Lock()
+	defer c.roomsMutex.Unlock()
+
+	return c.Rooms[roomID]
+}
+
+func (c *Client) roomIDs() []string {
+	c.roomsMutex.Lock()
+	defer c.roomsMutex.Unlock()
+
+	ids := make([]string, 0, len(c.Rooms))
+	for roomID := range c.Rooms {
+		ids = append(ids, roomID)
+	}
+	return ids
 }
 
 // ReadPump handles messages from the WebSocket connection
@@ -190,6 +536,9 @@ func (c *Client) ReadPump() {
 	defer func() {
 		c.Hub.UnregisterClient(c)
 		c.Conn.Close()
+		if c.OnClose != nil {
+			c.OnClose()
+		}
 	}()
 
 	for {
@@ -204,9 +553,71 @@ func (c *Client) ReadPump() {
 		// Handle incoming message
 		log.Printf("Received message from %s: %s", c.Username, string(message))
 
-		// Echo message back to the room (simplified)
-		// In practice, you'd parse the message and handle different types
-		c.Hub.Broadcast(message)
+		var inbound inboundMessage
+		if err := json.Unmarshal(message, &inbound); err != nil || inbound.ChatroomID == "" {
+			// Not addressed to a room, echo to everyone (simplified)
+			c.Hub.Broadcast(message)
+			continue
+		}
+
+		c.handleRoomMessage(inbound)
+	}
+}
+
+func (c *Client) handleRoomMessage(inbound inboundMessage) {
+	switch inbound.Type {
+	case "join":
+		c.Hub.JoinRoomWithSnapshot(c, inbound.ChatroomID)
+
+		// Tell the client about the squad so it can show the other rooms next to this one
+		if route := c.Hub.route(inbound.ChatroomID); route != nil {
+			if notice, err := json.Marshal(map[string]interface{}{"type": "squad", "data": route}); err == nil {
+				c.trySend(prepare(notice))
+			}
+		}
+
+	case "leave":
+		c.Hub.LeaveRoom(c, inbound.ChatroomID)
+
+	case "message":
+		if !c.inRoom(inbound.ChatroomID) {
+			return
+		}
+		content, blocked := c.Hub.moderate(inbound.ChatroomID, inbound.Content)
+		if blocked {
+			for _, observer := range c.Hub.observers {
+				observer.MessageBlocked(inbound.ChatroomID, c.UserID)
+			}
+			if notice, err := json.Marshal(map[string]interface{}{"type": "message_blocked", "chatroom_id": inbound.ChatroomID}); err == nil {
+				c.trySend(prepare(notice))
+			}
+			return
+		}
+		message := &models.Message{
+			ID:         uuid.New().String(),
+			ChatroomID: inbound.ChatroomID,
+			UserID:     c.UserID,
+			Username:   c.Username,
+			Content:    content,
+			Type:       models.MessageTypeText,
+			CreatedAt:  time.Now(),
+		}
+		outbound, err := encodeMessage(roomMessage{
+			Type:       "message",
+			ID:         message.ID,
+			ChatroomID: message.ChatroomID,
+			UserID:     message.UserID,
+			Username:   message.Username,
+			Content:    message.Content,
+			SentAt:     message.CreatedAt.Unix(),
+		})
+		if err != nil {
+			return
+		}
+		c.Hub.Publish(inbound.ChatroomID, outbound)
+		for _, observer := range c.Hub.observers {
+			observer.MessageDelivered(message)
+		}
 	}
 }
 
@@ -222,7 +633,7 @@ func (c *Client) WritePump() {
 				return
 			}
 
-			if err := c.Conn.WriteMessage(websocket.TextMessage, message); err != nil {
+			if err := c.Conn.WritePreparedMessage(message); err != nil {
 				log.Printf("WebSocket write error: %v", err)
 				return
 			}
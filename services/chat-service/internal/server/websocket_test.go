@@ -0,0 +1,123 @@
+package server
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/gorilla/websocket"
+)
+
+func newTestClient(hub *Hub, userID string, verified bool) *Client {
+	client := &Client{
+		Send:     make(chan *websocket.PreparedMessage, 2),
+		Hub:      hub,
+		UserID:   userID,
+		Username: userID,
+		Rooms:    make(map[string]bool),
+		Verified: verified,
+	}
+	hub.registerClient(client)
+	return client
+}
+
+func TestTargetedMessagesSkipUnverifiedClients(t *testing.T) {
+	log.SetOutput(io.Discard)
+	defer log.SetOutput(os.Stderr)
+
+	hub := NewWebSocketHub(4)
+	verified := newTestClient(hub, "user-1", true)
+	claimed := newTestClient(hub, "user-1", false)
+	hub.JoinRoom(verified, "room-1")
+	hub.JoinRoom(claimed, "room-1")
+
+	if sent := hub.SendToUser("user-1", []byte(`{"type":"stream_health_alert"}`)); sent != 1 {
+		t.Errorf("SendToUser queued %d messages, want 1", sent)
+	}
+	hub.PublishToUsers("room-1", []string{"user-1"}, []byte(`{"type":"system"}`))
+
+	if queued := len(verified.Send); queued != 2 {
+		t.Errorf("verified client has %d messages queued, want 2", queued)
+	}
+	if queued := len(claimed.Send); queued != 0 {
+		t.Errorf("unverified client has %d messages queued, want none", queued)
+	}
+}
+
+// benchmarkRooms is how many rooms the benchmark's clients are spread over
+const benchmarkRooms = 100
+
+// BenchmarkHubBroadcast broadcasts to rooms from many goroutines while clients keep joining and
+// leaving rooms, as they do on a busy instance. One shard is a hub behind a single mutex.
+func BenchmarkHubBroadcast(b *testing.B) {
+	log.SetOutput(io.Discard)
+	defer log.SetOutput(os.Stderr)
+
+	for _, shards := range []int{1, 8, defaultHubShards} {
+		for _, clients := range []int{100, 1000, 10000, 50000} {
+			b.Run(fmt.Sprintf("shards=%d/clients=%d", shards, clients), func(b *testing.B) {
+				benchmarkHubBroadcast(b, shards, clients)
+			})
+		}
+	}
+}
+
+func benchmarkHubBroadcast(b *testing.B, shards, clients int) {
+	hub := NewWebSocketHub(shards)
+
+	var wg sync.WaitGroup
+	members := make([]*Client, clients)
+	for i := range members {
+		client := &Client{
+			Send:     make(chan *websocket.PreparedMessage, 256),
+			Hub:      hub,
+			UserID:   fmt.Sprintf("user-%d", i),
+			Username: fmt.Sprintf("user-%d", i),
+			Rooms:    make(map[string]bool),
+		}
+		hub.registerClient(client)
+		hub.JoinRoom(client, benchmarkRoom(i))
+		members[i] = client
+
+		// Stands in for the write pump
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range client.Send {
+			}
+		}()
+	}
+
+	message := []byte(`{"type":"message","content":"benchmark"}`)
+	var next atomic.Int64
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			n := int(next.Add(1))
+			// One operation in ten has a client leave its room and join it again
+			if n%10 == 0 {
+				client := members[n%clients]
+				hub.LeaveRoom(client, benchmarkRoom(n%clients))
+				hub.JoinRoom(client, benchmarkRoom(n%clients))
+				continue
+			}
+			hub.BroadcastToRoom(benchmarkRoom(n), message)
+		}
+	})
+	b.StopTimer()
+
+	for _, client := range members {
+		hub.unregisterClient(client)
+	}
+	wg.Wait()
+}
+
+func benchmarkRoom(i int) string {
+	return fmt.Sprintf("room-%d", i%benchmarkRooms)
+}
@@ -0,0 +1,361 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/gorilla/mux"
+
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/services/chat-service/internal/models"
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/services/chat-service/internal/repository"
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/shared/go/pkg/apperrors"
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/shared/go/pkg/identity"
+)
+
+// Automod filters chat messages with the banned term dictionaries stored in DynamoDB. Each
+// replica caches the dictionaries it uses, tagged with their version, and drops them when
+// another replica publishes a newer version, so an update applies platform-wide within
+// moments. The cache TTL catches invalidations lost while Redis was unreachable.
+type Automod struct {
+	dynamoRepo repository.DynamoDBRepository
+	redisRepo  repository.RedisRepository
+	cacheTTL   time.Duration
+
+	mutex        sync.Mutex
+	dictionaries map[string]*cachedDictionary
+	filters      map[string]*automodFilter // by room scope, "" for rooms without a dictionary
+}
+
+type cachedDictionary struct {
+	dictionary *models.AutomodDictionary // nil when the scope has no dictionary
+	expires    time.Time
+}
+
+// automodFilter is the compiled combination of the global dictionary and one room's
+type automodFilter struct {
+	globalVersion int64
+	roomVersion   int64
+	action        models.AutomodAction
+	pattern       *regexp.Regexp // nil when nothing is banned
+}
+
+type automodDictionaryRequest struct {
+	BannedTerms  []string             `json:"banned_terms"`
+	AllowedTerms []string             `json:"allowed_terms"`
+	Action       models.AutomodAction `json:"action"`
+	Version      int64                `json:"version"` // version the update is based on, 0 to create
+}
+
+func NewAutomod(dynamoRepo repository.DynamoDBRepository, redisRepo repository.RedisRepository, cacheTTL time.Duration) *Automod {
+	return &Automod{
+		dynamoRepo:   dynamoRepo,
+		redisRepo:    redisRepo,
+		cacheTTL:     cacheTTL,
+		dictionaries: make(map[string]*cachedDictionary),
+		filters:      make(map[string]*automodFilter),
+	}
+}
+
+// Run applies invalidations published by other replicas until ctx is done, and forgets
+// expired dictionaries of rooms no longer in use
+func (a *Automod) Run(ctx context.Context) {
+	invalidations := a.redisRepo.SubscribeAutomodInvalidations(ctx)
+	ticker := time.NewTicker(a.cacheTTL)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case invalidation, ok := <-invalidations:
+			if !ok {
+				return
+			}
+			a.invalidate(invalidation.Scope, invalidation.Version)
+		case <-ticker.C:
+			a.sweep()
+		}
+	}
+}
+
+// Moderate returns the message content with banned terms masked, or blocked when the message
+// must be rejected. Messages go through unfiltered if the dictionaries can't be loaded.
+func (a *Automod) Moderate(ctx context.Context, chatroomID, content string) (string, bool) {
+	filter, err := a.filter(ctx, chatroomID)
+	if err != nil {
+		log.Printf("Failed to load automod dictionaries for chatroom %s: %v", chatroomID, err)
+		return content, false
+	}
+	if filter.pattern == nil {
+		return content, false
+	}
+
+	matches := matchTerms(filter.pattern, content)
+	if len(matches) == 0 {
+		return content, false
+	}
+	if filter.action != models.AutomodActionMask {
+		return content, true
+	}
+
+	var masked strings.Builder
+	last := 0
+	for _, match := range matches {
+		masked.WriteString(content[last:match[0]])
+		masked.WriteString(strings.Repeat("*", utf8.RuneCountInString(content[match[0]:match[1]])))
+		last = match[1]
+	}
+	masked.WriteString(content[last:])
+	return masked.String(), false
+}
+
+// filter returns the compiled filter of a room, rebuilding it when either dictionary changed.
+// Without a room only the global dictionary applies.
+func (a *Automod) filter(ctx context.Context, chatroomID string) (*automodFilter, error) {
+	global, err := a.dictionary(ctx, models.AutomodScopeGlobal)
+	if err != nil {
+		return nil, err
+	}
+	var room *models.AutomodDictionary
+	if chatroomID != "" {
+		room, err = a.dictionary(ctx, chatroomID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	key, globalVersion, roomVersion := "", dictionaryVersion(global), dictionaryVersion(room)
+	if room != nil {
+		key = chatroomID
+	}
+
+	a.mutex.Lock()
+	cached := a.filters[key]
+	a.mutex.Unlock()
+	if cached != nil && cached.globalVersion == globalVersion && cached.roomVersion == roomVersion {
+		return cached, nil
+	}
+
+	filter := compileFilter(global, room)
+	a.mutex.Lock()
+	a.filters[key] = filter
+	a.mutex.Unlock()
+	return filter, nil
+}
+
+// dictionary returns a scope's dictionary from the cache or DynamoDB. A stale copy is used if
+// DynamoDB can't be reached.
+func (a *Automod) dictionary(ctx context.Context, scope string) (*models.AutomodDictionary, error) {
+	a.mutex.Lock()
+	cached := a.dictionaries[scope]
+	a.mutex.Unlock()
+	if cached != nil && time.Now().Before(cached.expires) {
+		return cached.dictionary, nil
+	}
+
+	dictionary, err := a.dynamoRepo.GetAutomodDictionary(ctx, scope)
+	if err != nil {
+		if cached != nil {
+			log.Printf("Using stale automod dictionary %s: %v", scope, err)
+			return cached.dictionary, nil
+		}
+		return nil, err
+	}
+
+	a.mutex.Lock()
+	a.dictionaries[scope] = &cachedDictionary{dictionary: dictionary, expires: time.Now().Add(a.cacheTTL)}
+	a.mutex.Unlock()
+	return dictionary, nil
+}
+
+// invalidate drops a cached dictionary older than version. Room filters built from it are
+// rebuilt on their next use since their versions no longer match.
+func (a *Automod) invalidate(scope string, version int64) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	cached := a.dictionaries[scope]
+	if cached == nil || dictionaryVersion(cached.dictionary) >= version {
+		return
+	}
+	delete(a.dictionaries, scope)
+	if scope != models.AutomodScopeGlobal {
+		delete(a.filters, scope)
+	}
+	log.Printf("Automod dictionary %s invalidated, now at version %d", scope, version)
+}
+
+func (a *Automod) sweep() {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	now := time.Now()
+	for scope, cached := range a.dictionaries {
+		if now.After(cached.expires) {
+			delete(a.dictionaries, scope)
+			delete(a.filters, scope)
+		}
+	}
+}
+
+// HandleGetDictionary handles GET /automod/dictionaries/{scope}
+func (a *Automod) HandleGetDictionary(w http.ResponseWriter, req *http.Request) {
+	scope := mux.Vars(req)["scope"]
+
+	dictionary, err := a.dynamoRepo.GetAutomodDictionary(req.Context(), scope)
+	if err != nil {
+		apperrors.WriteHTTP(w, req, apperrors.Internal(err, "failed to get automod dictionary"))
+		return
+	}
+	if dictionary == nil {
+		apperrors.WriteHTTP(w, req, apperrors.NotFound("automod dictionary not found"))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(dictionary)
+}
+
+// HandlePutDictionary handles PUT /automod/dictionaries/{scope}. The body's version must be
+// the current one, so concurrent edits by trust and safety don't overwrite each other. The
+// change is attributed to the signed in user.
+func (a *Automod) HandlePutDictionary(w http.ResponseWriter, req *http.Request) {
+	scope := mux.Vars(req)["scope"]
+
+	var body automodDictionaryRequest
+	if err := json.NewDecoder(req.Body).Decode(&body); err != nil {
+		apperrors.WriteHTTP(w, req, apperrors.InvalidRequest("invalid request body"))
+		return
+	}
+	switch body.Action {
+	case "", models.AutomodActionBlock, models.AutomodActionMask:
+	default:
+		apperrors.WriteHTTP(w, req, apperrors.InvalidRequest("action must be block or mask"))
+		return
+	}
+	if scope == models.AutomodScopeGlobal && body.Action == "" {
+		body.Action = models.AutomodActionBlock
+	}
+
+	dictionary := &models.AutomodDictionary{
+		Scope:        scope,
+		BannedTerms:  normalizeTerms(body.BannedTerms),
+		AllowedTerms: normalizeTerms(body.AllowedTerms),
+		Action:       body.Action,
+		Version:      body.Version,
+		UpdatedBy:    identity.UserID(req.Context()),
+		UpdatedAt:    time.Now(),
+	}
+	if err := a.dynamoRepo.PutAutomodDictionary(req.Context(), dictionary); err != nil {
+		if errors.Is(err, repository.ErrAutomodVersionConflict) {
+			apperrors.WriteHTTP(w, req, apperrors.Conflict("automod dictionary changed since the given version"))
+			return
+		}
+		apperrors.WriteHTTP(w, req, apperrors.Internal(err, "failed to put automod dictionary"))
+		return
+	}
+
+	a.invalidate(scope, dictionary.Version)
+	invalidation := &models.AutomodInvalidation{Scope: scope, Version: dictionary.Version}
+	if err := a.redisRepo.PublishAutomodInvalidation(req.Context(), invalidation); err != nil {
+		log.Printf("Failed to publish automod invalidation, other replicas catch up within %s: %v", a.cacheTTL, err)
+	}
+
+	log.Printf("Automod dictionary %s updated to version %d with %d banned terms", scope, dictionary.Version, len(dictionary.BannedTerms))
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(dictionary)
+}
+
+// compileFilter combines the global dictionary with a room's: the room's banned terms are
+// added, its allowed terms removed, and its action wins when set
+func compileFilter(global, room *models.AutomodDictionary) *automodFilter {
+	filter := &automodFilter{
+		globalVersion: dictionaryVersion(global),
+		roomVersion:   dictionaryVersion(room),
+		action:        models.AutomodActionBlock,
+	}
+
+	banned := make(map[string]bool)
+	for _, dictionary := range []*models.AutomodDictionary{global, room} {
+		if dictionary == nil {
+			continue
+		}
+		for _, term := range dictionary.BannedTerms {
+			banned[term] = true
+		}
+		if dictionary.Action != "" {
+			filter.action = dictionary.Action
+		}
+	}
+	if room != nil {
+		for _, term := range room.AllowedTerms {
+			delete(banned, term)
+		}
+	}
+	if len(banned) == 0 {
+		return filter
+	}
+
+	// Longest first, so a term wins over the shorter terms it contains
+	terms := make([]string, 0, len(banned))
+	for term := range banned {
+		terms = append(terms, regexp.QuoteMeta(term))
+	}
+	sort.Slice(terms, func(i, j int) bool {
+		if len(terms[i]) != len(terms[j]) {
+			return len(terms[i]) > len(terms[j])
+		}
+		return terms[i] < terms[j]
+	})
+	filter.pattern = regexp.MustCompile(`(?i)(?:` + strings.Join(terms, "|") + `)`)
+	return filter
+}
+
+// matchTerms returns the matches of banned terms that are whole words. RE2 has no lookaround
+// and \b only knows ASCII, so word boundaries are checked by hand.
+func matchTerms(pattern *regexp.Regexp, content string) [][]int {
+	var matches [][]int
+	for _, match := range pattern.FindAllStringIndex(content, -1) {
+		before, _ := utf8.DecodeLastRuneInString(content[:match[0]])
+		after, _ := utf8.DecodeRuneInString(content[match[1]:])
+		if isWordRune(before) || isWordRune(after) {
+			continue
+		}
+		matches = append(matches, match)
+	}
+	return matches
+}
+
+func isWordRune(r rune) bool {
+	return r != utf8.RuneError && (unicode.IsLetter(r) || unicode.IsNumber(r) || r == '_')
+}
+
+// normalizeTerms lowercases, trims and dedupes terms
+func normalizeTerms(terms []string) []string {
+	seen := make(map[string]bool, len(terms))
+	normalized := make([]string, 0, len(terms))
+	for _, term := range terms {
+		term = strings.ToLower(strings.TrimSpace(term))
+		if term == "" || seen[term] {
+			continue
+		}
+		seen[term] = true
+		normalized = append(normalized, term)
+	}
+	return normalized
+}
+
+func dictionaryVersion(dictionary *models.AutomodDictionary) int64 {
+	if dictionary == nil {
+		return 0
+	}
+	return dictionary.Version
+}
@@ -0,0 +1,388 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/services/chat-service/internal/config"
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/services/chat-service/internal/models"
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/services/chat-service/internal/repository"
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/shared/go/pkg/apperrors"
+)
+
+const (
+	// defaultRollupPeriod is the period dashboards get when they don't ask for one
+	defaultRollupPeriod = 24 * time.Hour
+	// maxRollupPeriod bounds how many hours a single request reads
+	maxRollupPeriod = 30 * 24 * time.Hour
+
+	defaultTopChatters = 10
+	maxTopChatters     = 100
+	maxTopEmotes       = 100
+
+	// emoteFlushBatch is how many room hours of emote counts are taken from Redis at once
+	emoteFlushBatch = 100
+)
+
+// ChatRollups sums chat activity into hourly rollups per room, so broadcaster dashboards read
+// a few items instead of scanning the message table. Activity is summed in memory and
+// flushed periodically, counters are added in DynamoDB so every replica flushes its share.
+// Emote counts are summed in Redis by the room projection and flushed along with it.
+type ChatRollups struct {
+	dynamoRepo repository.DynamoDBRepository
+	redisRepo  repository.RedisRepository
+	config     config.RollupConfig
+
+	activity chan *models.ChatActivity
+}
+
+type roomHour struct {
+	chatroomID string
+	hour       time.Time
+}
+
+// pendingRollup is the activity of a room's hour since the last flush
+type pendingRollup struct {
+	messages          int64
+	moderationActions int64
+	chatters          map[string]*models.ChatterRollup // by user ID
+}
+
+func NewChatRollups(cfg config.RollupConfig, dynamoRepo repository.DynamoDBRepository, redisRepo repository.RedisRepository) *ChatRollups {
+	return &ChatRollups{
+		dynamoRepo: dynamoRepo,
+		redisRepo:  redisRepo,
+		config:     cfg,
+		activity:   make(chan *models.ChatActivity, cfg.QueueSize),
+	}
+}
+
+// Run sums activity and flushes it every flush interval until ctx is done, then flushes what
+// is left
+func (r *ChatRollups) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.config.FlushInterval)
+	defer ticker.Stop()
+
+	pending := make(map[roomHour]*pendingRollup)
+	for {
+		select {
+		case activity := <-r.activity:
+			r.add(pending, activity)
+		case <-ticker.C:
+			r.flush(ctx, pending)
+			pending = make(map[roomHour]*pendingRollup)
+			r.flushEmotes(ctx)
+		case <-ctx.Done():
+			// Take what was queued before the shutdown
+		drain:
+			for {
+				select {
+				case activity := <-r.activity:
+					r.add(pending, activity)
+				default:
+					break drain
+				}
+			}
+			flushCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			r.flush(flushCtx, pending)
+			r.flushEmotes(flushCtx)
+			cancel()
+			return
+		}
+	}
+}
+
+// Record queues activity to be summed. Activity is dropped when the queue is full, rollups
+// are statistics and must never slow down chat.
+func (r *ChatRollups) Record(activity *models.ChatActivity) {
+	if r == nil {
+		return
+	}
+
+	select {
+	case r.activity <- activity:
+	default:
+		log.Printf("Rollup queue full, %s activity in chatroom %s is not counted", activity.Type, activity.ChatroomID)
+	}
+}
+
+// MessageSent counts a message delivered to a room
+func (r *ChatRollups) MessageSent(message *models.Message) {
+	if message.Type == models.MessageTypeSystem {
+		return
+	}
+	r.Record(&models.ChatActivity{
+		Type:       models.ChatActivityMessage,
+		ChatroomID: message.ChatroomID,
+		UserID:     message.UserID,
+		Username:   message.Username,
+		At:         message.CreatedAt,
+	})
+}
+
+// MessageModerated counts a message blocked by automod or taken back by moderation
+func (r *ChatRollups) MessageModerated(chatroomID, userID string) {
+	r.Record(&models.ChatActivity{
+		Type:       models.ChatActivityModeration,
+		ChatroomID: chatroomID,
+		UserID:     userID,
+		At:         time.Now(),
+	})
+}
+
+// MessageDelivered implements server.MessageObserver for messages sent over WebSocket
+func (r *ChatRollups) MessageDelivered(message *models.Message) {
+	r.MessageSent(message)
+}
+
+// MessageBlocked implements server.MessageObserver for messages sent over WebSocket
+func (r *ChatRollups) MessageBlocked(roomID, userID string) {
+	r.MessageModerated(roomID, userID)
+}
+
+func (r *ChatRollups) add(pending map[roomHour]*pendingRollup, activity *models.ChatActivity) {
+	key := roomHour{chatroomID: activity.ChatroomID, hour: activity.At.UTC().Truncate(time.Hour)}
+	rollup := pending[key]
+	if rollup == nil {
+		rollup = &pendingRollup{chatters: make(map[string]*models.ChatterRollup)}
+		pending[key] = rollup
+	}
+
+	switch activity.Type {
+	case models.ChatActivityMessage:
+		rollup.messages++
+		chatter := rollup.chatters[activity.UserID]
+		if chatter == nil {
+			chatter = &models.ChatterRollup{
+				ChatroomID: activity.ChatroomID,
+				Hour:       key.hour,
+				UserID:     activity.UserID,
+			}
+			rollup.chatters[activity.UserID] = chatter
+		}
+		chatter.Username = activity.Username
+		chatter.Messages++
+	case models.ChatActivityModeration:
+		rollup.moderationActions++
+	}
+}
+
+// flush writes pending activity. Activity that fails to be written is lost, the next flush
+// only carries newer activity.
+func (r *ChatRollups) flush(ctx context.Context, pending map[roomHour]*pendingRollup) {
+	for key, hour := range pending {
+		rollup := &models.ChatRollup{
+			ChatroomID:        key.chatroomID,
+			Hour:              key.hour,
+			Messages:          hour.messages,
+			ModerationActions: hour.moderationActions,
+		}
+
+		if len(hour.chatters) > 0 {
+			userIDs := make([]string, 0, len(hour.chatters))
+			for userID := range hour.chatters {
+				userIDs = append(userIDs, userID)
+			}
+			count, err := r.redisRepo.AddRollupChatters(ctx, key.chatroomID, key.hour, userIDs)
+			if err != nil {
+				log.Printf("Failed to count chatters of chatroom %s: %v", key.chatroomID, err)
+			}
+			rollup.UniqueChatters = count
+		}
+
+		if err := r.dynamoRepo.AddChatRollup(ctx, rollup); err != nil {
+			log.Printf("Failed to write rollup of chatroom %s: %v", key.chatroomID, err)
+			continue
+		}
+		for _, chatter := range hour.chatters {
+			if err := r.dynamoRepo.AddChatterRollup(ctx, chatter); err != nil {
+				log.Printf("Failed to write rollup of user %s in chatroom %s: %v", chatter.UserID, key.chatroomID, err)
+			}
+		}
+	}
+}
+
+// flushEmotes moves the emote counts pending in Redis to DynamoDB. Counts that fail to be
+// written are put back for the next flush.
+func (r *ChatRollups) flushEmotes(ctx context.Context) {
+	for {
+		rollups, err := r.redisRepo.PopPendingEmotes(ctx, emoteFlushBatch)
+		if err != nil {
+			log.Printf("Failed to take pending emote counts: %v", err)
+		}
+
+		var failed []*models.EmoteRollup
+		for _, rollup := range rollups {
+			if err := r.dynamoRepo.AddEmoteRollup(ctx, rollup); err != nil {
+				log.Printf("Failed to write emote rollup of chatroom %s: %v", rollup.ChatroomID, err)
+				failed = append(failed, rollup)
+			}
+		}
+		if len(failed) > 0 {
+			if err := r.redisRepo.RestorePendingEmotes(ctx, failed); err != nil {
+				log.Printf("Lost %d emote counts: %v", len(failed), err)
+			}
+			return
+		}
+
+		if err != nil || len(rollups) == 0 {
+			return
+		}
+	}
+}
+
+// TopChatters returns the users who sent the most messages to a room over the period ending
+// now, most messages first
+func (r *ChatRollups) TopChatters(ctx context.Context, chatroomID string, period time.Duration, limit int) ([]*models.ChatterRollup, error) {
+	from, to := rollupRange(period)
+	hourly, err := r.dynamoRepo.GetChatterRollups(ctx, chatroomID, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	totals := make(map[string]*models.ChatterRollup)
+	for _, rollup := range hourly {
+		total := totals[rollup.UserID]
+		if total == nil {
+			total = &models.ChatterRollup{ChatroomID: chatroomID, Hour: from, UserID: rollup.UserID}
+			totals[rollup.UserID] = total
+		}
+		// Rollups are read oldest first, the latest username wins
+		total.Username = rollup.Username
+		total.Messages += rollup.Messages
+	}
+
+	chatters := make([]*models.ChatterRollup, 0, len(totals))
+	for _, total := range totals {
+		chatters = append(chatters, total)
+	}
+	sort.Slice(chatters, func(i, j int) bool {
+		if chatters[i].Messages != chatters[j].Messages {
+			return chatters[i].Messages > chatters[j].Messages
+		}
+		return chatters[i].UserID < chatters[j].UserID
+	})
+
+	if len(chatters) > limit {
+		chatters = chatters[:limit]
+	}
+	return chatters, nil
+}
+
+// TopEmotes returns the emotes used the most in a room over the period ending now, most
+// used first. Uses since the last flush aren't counted yet.
+func (r *ChatRollups) TopEmotes(ctx context.Context, chatroomID string, period time.Duration, limit int) ([]models.EmoteCount, error) {
+	from, to := rollupRange(period)
+	return topEmotes(ctx, r.dynamoRepo, chatroomID, from, to, limit)
+}
+
+// Activity returns the hourly rollups of a room over the period ending now, oldest first
+func (r *ChatRollups) Activity(ctx context.Context, chatroomID string, period time.Duration) ([]*models.ChatRollup, error) {
+	from, to := rollupRange(period)
+	return r.dynamoRepo.GetChatRollups(ctx, chatroomID, from, to)
+}
+
+// HandleGetActivity handles GET /chatrooms/{id}/activity?period_hours=N, the chat summary
+// the stream service shows on creator dashboards
+func (r *ChatRollups) HandleGetActivity(w http.ResponseWriter, req *http.Request) {
+	chatroomID := mux.Vars(req)["id"]
+
+	hours := 0
+	if value := req.URL.Query().Get("period_hours"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil {
+			apperrors.WriteHTTP(w, req, apperrors.InvalidRequest("invalid period_hours"))
+			return
+		}
+		hours = parsed
+	}
+	period, ok := rollupPeriod(int32(hours))
+	if !ok {
+		apperrors.WriteHTTP(w, req, apperrors.InvalidRequest(fmt.Sprintf("period_hours must be at most %d", int(maxRollupPeriod/time.Hour))))
+		return
+	}
+
+	buckets, err := r.Activity(req.Context(), chatroomID, period)
+	if err != nil {
+		apperrors.WriteHTTP(w, req, apperrors.Internal(err, "failed to get chat activity"))
+		return
+	}
+	chatters, err := r.TopChatters(req.Context(), chatroomID, period, defaultTopChatters)
+	if err != nil {
+		apperrors.WriteHTTP(w, req, apperrors.Internal(err, "failed to get top chatters"))
+		return
+	}
+
+	var totalMessages, totalModerationActions int64
+	for _, bucket := range buckets {
+		totalMessages += bucket.Messages
+		totalModerationActions += bucket.ModerationActions
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"chatroom_id":              chatroomID,
+		"period_hours":             int(period / time.Hour),
+		"buckets":                  buckets,
+		"total_messages":           totalMessages,
+		"total_moderation_actions": totalModerationActions,
+		"top_chatters":             chatters,
+	})
+}
+
+// rollupPeriod returns the period of a request in hours, false if it is too long
+func rollupPeriod(hours int32) (time.Duration, bool) {
+	if hours <= 0 {
+		return defaultRollupPeriod, true
+	}
+	period := time.Duration(hours) * time.Hour
+	return period, period <= maxRollupPeriod
+}
+
+// rollupRange returns the first and last hour of a period ending now, the current hour counts
+// as one
+func rollupRange(period time.Duration) (time.Time, time.Time) {
+	to := time.Now().UTC().Truncate(time.Hour)
+	hours := int((period + time.Hour - 1) / time.Hour)
+	if hours < 1 {
+		hours = 1
+	}
+	return to.Add(-time.Duration(hours-1) * time.Hour), to
+}
+
+// topEmotes sums the hourly emote rollups of a room between the hours of from and to
+func topEmotes(ctx context.Context, dynamoRepo repository.DynamoDBRepository, chatroomID string, from, to time.Time, limit int) ([]models.EmoteCount, error) {
+	hourly, err := dynamoRepo.GetEmoteRollups(ctx, chatroomID, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	totals := make(map[string]int64)
+	for _, rollup := range hourly {
+		totals[rollup.Name] += rollup.Count
+	}
+
+	emotes := make([]models.EmoteCount, 0, len(totals))
+	for name, count := range totals {
+		emotes = append(emotes, models.EmoteCount{Name: name, Count: count})
+	}
+	sort.Slice(emotes, func(i, j int) bool {
+		if emotes[i].Count != emotes[j].Count {
+			return emotes[i].Count > emotes[j].Count
+		}
+		return emotes[i].Name < emotes[j].Name
+	})
+
+	if len(emotes) > limit {
+		emotes = emotes[:limit]
+	}
+	return emotes, nil
+}
@@ -4,16 +4,19 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"net/http"
 	"time"
 
 	"github.com/google/uuid"
-	"google.golang.org/grpc/codes"
+	"github.com/gorilla/mux"
 
 	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/services/chat-service/internal/models"
 	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/services/chat-service/internal/repository"
 	chatpb "github.com/Saoudyahya/Live-Streaming-Platform-Architecture/services/chat-service/pkg/proto/chat"
 	commonpb "github.com/Saoudyahya/Live-Streaming-Platform-Architecture/services/chat-service/pkg/proto/common"
 	userpb "github.com/Saoudyahya/Live-Streaming-Platform-Architecture/services/chat-service/pkg/proto/user"
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/shared/go/pkg/apperrors"
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/shared/go/pkg/identity"
 )
 
 type ChatService struct {
@@ -21,43 +24,69 @@ type ChatService struct {
 	dynamoRepo repository.DynamoDBRepository
 	redisRepo  repository.RedisRepository
 	userClient userpb.UserServiceClient
+	identities *identity.Propagator
+	projection *RoomProjection
+	automod    *Automod
+	rollups    *ChatRollups
+	moderation *ModerationPipeline // nil when async moderation is off
 }
 
 func NewChatService(
 	dynamoRepo repository.DynamoDBRepository,
 	redisRepo repository.RedisRepository,
 	userClient userpb.UserServiceClient,
+	identities *identity.Propagator,
+	automod *Automod,
+	rollups *ChatRollups,
 ) *ChatService {
 	return &ChatService{
 		dynamoRepo: dynamoRepo,
 		redisRepo:  redisRepo,
 		userClient: userClient,
+		identities: identities,
+		projection: NewRoomProjection(dynamoRepo, redisRepo),
+		automod:    automod,
+		rollups:    rollups,
 	}
 }
 
+// RequireChatroomOwner only lets through the user who created the chatroom of the route,
+// usually its broadcaster, admins and platform services such as stream-management's chat
+// summary. Callers can only be trusted when they are signed, so without
+// IDENTITY_PROPAGATION_SECRET every request is rejected.
+func (s *ChatService) RequireChatroomOwner(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, ok := s.identities.RequestService(r); ok {
+			next.ServeHTTP(w, r)
+			return
+		}
+		id := identity.FromContext(r.Context())
+		if !s.identities.Signed() || !id.Authenticated() {
+			apperrors.WriteHTTP(w, r, apperrors.Unauthorized("Authentication required"))
+			return
+		}
+		if !id.HasRole(identity.RoleAdmin) {
+			chatroom, err := s.dynamoRepo.GetChatroom(r.Context(), mux.Vars(r)["id"])
+			if err != nil {
+				apperrors.WriteHTTP(w, r, apperrors.NotFound("Chatroom not found"))
+				return
+			}
+			if chatroom.CreatorID != id.UserID {
+				log.Printf("Rejected %s %s from user %s, who doesn't own the chatroom", r.Method, r.URL.Path, id.UserID)
+				apperrors.WriteHTTP(w, r, apperrors.Forbidden("Only the chatroom's owner can call this endpoint"))
+				return
+			}
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
 func (s *ChatService) CreateChatroom(ctx context.Context, req *chatpb.CreateChatroomRequest) (*chatpb.CreateChatroomResponse, error) {
 	// Validate user exists
-	userResp, err := s.userClient.GetUser(ctx, &userpb.GetUserRequest{
-		UserId: req.CreatorId,
-	})
+	_, err := resolveUser(ctx, s.userClient, s.identities, req.CreatorId)
 	if err != nil {
-		log.Printf("Failed to validate user %s: %v", req.CreatorId, err)
-		return &chatpb.CreateChatroomResponse{
-			Status: &commonpb.Status{
-				Code:    int32(codes.Internal),
-				Message: "Failed to validate user",
-				Success: false,
-			},
-		}, nil
-	}
-
-	if !userResp.Status.Success {
 		return &chatpb.CreateChatroomResponse{
-			Status: &commonpb.Status{
-				Code:    int32(codes.NotFound),
-				Message: "User not found",
-				Success: false,
-			},
+			Status: errorStatus(err),
 		}, nil
 	}
 
@@ -77,11 +106,7 @@ func (s *ChatService) CreateChatroom(ctx context.Context, req *chatpb.CreateChat
 	if err != nil {
 		log.Printf("Failed to create chatroom: %v", err)
 		return &chatpb.CreateChatroomResponse{
-			Status: &commonpb.Status{
-				Code:    int32(codes.Internal),
-				Message: "Failed to create chatroom",
-				Success: false,
-			},
+			Status: errorStatus(apperrors.Internal(err, "Failed to create chatroom")),
 		}, nil
 	}
 
@@ -90,29 +115,20 @@ func (s *ChatService) CreateChatroom(ctx context.Context, req *chatpb.CreateChat
 	if err != nil {
 		log.Printf("Failed to add user to chatroom in Redis: %v", err)
 	}
+	s.projection.ChatroomCreated(ctx, chatroom)
 
 	return &chatpb.CreateChatroomResponse{
-		Status: &commonpb.Status{
-			Code:    int32(codes.OK),
-			Message: "Chatroom created successfully",
-			Success: true,
-		},
+		Status:   okStatus("Chatroom created successfully"),
 		Chatroom: chatroomToProto(chatroom),
 	}, nil
 }
 
 func (s *ChatService) JoinChatroom(ctx context.Context, req *chatpb.JoinChatroomRequest) (*chatpb.JoinChatroomResponse, error) {
 	// Validate user exists
-	userResp, err := s.userClient.GetUser(ctx, &userpb.GetUserRequest{
-		UserId: req.UserId,
-	})
-	if err != nil || !userResp.Status.Success {
+	user, err := resolveUser(ctx, s.userClient, s.identities, req.UserId)
+	if err != nil {
 		return &chatpb.JoinChatroomResponse{
-			Status: &commonpb.Status{
-				Code:    int32(codes.NotFound),
-				Message: "User not found",
-				Success: false,
-			},
+			Status: errorStatus(err),
 		}, nil
 	}
 
@@ -120,11 +136,7 @@ func (s *ChatService) JoinChatroom(ctx context.Context, req *chatpb.JoinChatroom
 	chatroom, err := s.dynamoRepo.GetChatroom(ctx, req.ChatroomId)
 	if err != nil {
 		return &chatpb.JoinChatroomResponse{
-			Status: &commonpb.Status{
-				Code:    int32(codes.NotFound),
-				Message: "Chatroom not found",
-				Success: false,
-			},
+			Status: errorStatus(apperrors.NotFound("Chatroom not found")),
 		}, nil
 	}
 
@@ -132,11 +144,7 @@ func (s *ChatService) JoinChatroom(ctx context.Context, req *chatpb.JoinChatroom
 	for _, memberID := range chatroom.MemberIDs {
 		if memberID == req.UserId {
 			return &chatpb.JoinChatroomResponse{
-				Status: &commonpb.Status{
-					Code:    int32(codes.AlreadyExists),
-					Message: "User is already a member",
-					Success: false,
-				},
+				Status: errorStatus(apperrors.Conflict("User is already a member")),
 			}, nil
 		}
 	}
@@ -146,11 +154,7 @@ func (s *ChatService) JoinChatroom(ctx context.Context, req *chatpb.JoinChatroom
 	if err != nil {
 		log.Printf("Failed to add member to chatroom: %v", err)
 		return &chatpb.JoinChatroomResponse{
-			Status: &commonpb.Status{
-				Code:    int32(codes.Internal),
-				Message: "Failed to join chatroom",
-				Success: false,
-			},
+			Status: errorStatus(apperrors.Internal(err, "Failed to join chatroom")),
 		}, nil
 	}
 
@@ -159,6 +163,7 @@ func (s *ChatService) JoinChatroom(ctx context.Context, req *chatpb.JoinChatroom
 	if err != nil {
 		log.Printf("Failed to add user to chatroom in Redis: %v", err)
 	}
+	s.projection.MemberJoined(ctx, req.ChatroomId)
 
 	// Send system message
 	systemMessage := &models.Message{
@@ -166,7 +171,7 @@ func (s *ChatService) JoinChatroom(ctx context.Context, req *chatpb.JoinChatroom
 		ChatroomID: req.ChatroomId,
 		UserID:     "system",
 		Username:   "System",
-		Content:    fmt.Sprintf("%s joined the chatroom", userResp.User.Username),
+		Content:    fmt.Sprintf("%s joined the chatroom", user.Username),
 		Type:       models.MessageTypeSystem,
 		CreatedAt:  time.Now(),
 		IsEdited:   false,
@@ -178,26 +183,16 @@ func (s *ChatService) JoinChatroom(ctx context.Context, req *chatpb.JoinChatroom
 	}
 
 	return &chatpb.JoinChatroomResponse{
-		Status: &commonpb.Status{
-			Code:    int32(codes.OK),
-			Message: "Successfully joined chatroom",
-			Success: true,
-		},
+		Status: okStatus("Successfully joined chatroom"),
 	}, nil
 }
 
 func (s *ChatService) LeaveChatroom(ctx context.Context, req *chatpb.LeaveChatroomRequest) (*chatpb.LeaveChatroomResponse, error) {
 	// Validate user exists
-	userResp, err := s.userClient.GetUser(ctx, &userpb.GetUserRequest{
-		UserId: req.UserId,
-	})
-	if err != nil || !userResp.Status.Success {
+	user, err := resolveUser(ctx, s.userClient, s.identities, req.UserId)
+	if err != nil {
 		return &chatpb.LeaveChatroomResponse{
-			Status: &commonpb.Status{
-				Code:    int32(codes.NotFound),
-				Message: "User not found",
-				Success: false,
-			},
+			Status: errorStatus(err),
 		}, nil
 	}
 
@@ -206,11 +201,7 @@ func (s *ChatService) LeaveChatroom(ctx context.Context, req *chatpb.LeaveChatro
 	if err != nil {
 		log.Printf("Failed to remove member from chatroom: %v", err)
 		return &chatpb.LeaveChatroomResponse{
-			Status: &commonpb.Status{
-				Code:    int32(codes.Internal),
-				Message: "Failed to leave chatroom",
-				Success: false,
-			},
+			Status: errorStatus(apperrors.Internal(err, "Failed to leave chatroom")),
 		}, nil
 	}
 
@@ -219,6 +210,7 @@ func (s *ChatService) LeaveChatroom(ctx context.Context, req *chatpb.LeaveChatro
 	if err != nil {
 		log.Printf("Failed to remove user from chatroom in Redis: %v", err)
 	}
+	s.projection.MemberLeft(ctx, req.ChatroomId)
 
 	// Send system message
 	systemMessage := &models.Message{
@@ -226,7 +218,7 @@ func (s *ChatService) LeaveChatroom(ctx context.Context, req *chatpb.LeaveChatro
 		ChatroomID: req.ChatroomId,
 		UserID:     "system",
 		Username:   "System",
-		Content:    fmt.Sprintf("%s left the chatroom", userResp.User.Username),
+		Content:    fmt.Sprintf("%s left the chatroom", user.Username),
 		Type:       models.MessageTypeSystem,
 		CreatedAt:  time.Now(),
 		IsEdited:   false,
@@ -238,26 +230,16 @@ func (s *ChatService) LeaveChatroom(ctx context.Context, req *chatpb.LeaveChatro
 	}
 
 	return &chatpb.LeaveChatroomResponse{
-		Status: &commonpb.Status{
-			Code:    int32(codes.OK),
-			Message: "Successfully left chatroom",
-			Success: true,
-		},
+		Status: okStatus("Successfully left chatroom"),
 	}, nil
 }
 
 func (s *ChatService) SendMessage(ctx context.Context, req *chatpb.SendMessageRequest) (*chatpb.SendMessageResponse, error) {
 	// Validate user exists
-	userResp, err := s.userClient.GetUser(ctx, &userpb.GetUserRequest{
-		UserId: req.UserId,
-	})
-	if err != nil || !userResp.Status.Success {
+	user, err := resolveUser(ctx, s.userClient, s.identities, req.UserId)
+	if err != nil {
 		return &chatpb.SendMessageResponse{
-			Status: &commonpb.Status{
-				Code:    int32(codes.NotFound),
-				Message: "User not found",
-				Success: false,
-			},
+			Status: errorStatus(err),
 		}, nil
 	}
 
@@ -266,21 +248,21 @@ func (s *ChatService) SendMessage(ctx context.Context, req *chatpb.SendMessageRe
 	if err != nil {
 		log.Printf("Failed to check chatroom membership: %v", err)
 		return &chatpb.SendMessageResponse{
-			Status: &commonpb.Status{
-				Code:    int32(codes.Internal),
-				Message: "Failed to validate membership",
-				Success: false,
-			},
+			Status: errorStatus(apperrors.Internal(err, "Failed to validate membership")),
 		}, nil
 	}
 
 	if !isMember {
 		return &chatpb.SendMessageResponse{
-			Status: &commonpb.Status{
-				Code:    int32(codes.PermissionDenied),
-				Message: "User is not a member of this chatroom",
-				Success: false,
-			},
+			Status: errorStatus(apperrors.Forbidden("User is not a member of this chatroom")),
+		}, nil
+	}
+
+	content, blocked := s.automod.Moderate(ctx, req.ChatroomId, req.Content)
+	if blocked {
+		s.rollups.MessageModerated(req.ChatroomId, req.UserId)
+		return &chatpb.SendMessageResponse{
+			Status: errorStatus(apperrors.InvalidRequest("Message contains a banned term")),
 		}, nil
 	}
 
@@ -289,8 +271,8 @@ func (s *ChatService) SendMessage(ctx context.Context, req *chatpb.SendMessageRe
 		ID:         uuid.New().String(),
 		ChatroomID: req.ChatroomId,
 		UserID:     req.UserId,
-		Username:   userResp.User.Username,
-		Content:    req.Content,
+		Username:   user.Username,
+		Content:    content,
 		Type:       messageTypeFromProto(req.Type),
 		CreatedAt:  time.Now(),
 		IsEdited:   false,
@@ -300,11 +282,7 @@ func (s *ChatService) SendMessage(ctx context.Context, req *chatpb.SendMessageRe
 	if err != nil {
 		log.Printf("Failed to create message: %v", err)
 		return &chatpb.SendMessageResponse{
-			Status: &commonpb.Status{
-				Code:    int32(codes.Internal),
-				Message: "Failed to send message",
-				Success: false,
-			},
+			Status: errorStatus(apperrors.Internal(err, "Failed to send message")),
 		}, nil
 	}
 
@@ -313,40 +291,31 @@ func (s *ChatService) SendMessage(ctx context.Context, req *chatpb.SendMessageRe
 	if err != nil {
 		log.Printf("Failed to cache message in Redis: %v", err)
 	}
+	s.projection.MessageSent(ctx, message)
+	s.rollups.MessageSent(message)
+	if s.moderation != nil {
+		s.moderation.Submit(message)
+	}
 
 	return &chatpb.SendMessageResponse{
-		Status: &commonpb.Status{
-			Code:    int32(codes.OK),
-			Message: "Message sent successfully",
-			Success: true,
-		},
+		Status:  okStatus("Message sent successfully"),
 		Message: messageToProto(message),
 	}, nil
 }
 
 func (s *ChatService) GetMessages(ctx context.Context, req *chatpb.GetMessagesRequest) (*chatpb.GetMessagesResponse, error) {
 	// Validate user exists and is member of chatroom
-	userResp, err := s.userClient.GetUser(ctx, &userpb.GetUserRequest{
-		UserId: req.UserId,
-	})
-	if err != nil || !userResp.Status.Success {
+	_, err := resolveUser(ctx, s.userClient, s.identities, req.UserId)
+	if err != nil {
 		return &chatpb.GetMessagesResponse{
-			Status: &commonpb.Status{
-				Code:    int32(codes.NotFound),
-				Message: "User not found",
-				Success: false,
-			},
+			Status: errorStatus(err),
 		}, nil
 	}
 
 	isMember, err := s.dynamoRepo.IsUserMemberOfChatroom(ctx, req.ChatroomId, req.UserId)
 	if err != nil || !isMember {
 		return &chatpb.GetMessagesResponse{
-			Status: &commonpb.Status{
-				Code:    int32(codes.PermissionDenied),
-				Message: "User is not a member of this chatroom",
-				Success: false,
-			},
+			Status: errorStatus(apperrors.Forbidden("User is not a member of this chatroom")),
 		}, nil
 	}
 
@@ -359,11 +328,7 @@ func (s *ChatService) GetMessages(ctx context.Context, req *chatpb.GetMessagesRe
 		if err != nil {
 			log.Printf("Failed to get messages from DynamoDB: %v", err)
 			return &chatpb.GetMessagesResponse{
-				Status: &commonpb.Status{
-					Code:    int32(codes.Internal),
-					Message: "Failed to retrieve messages",
-					Success: false,
-				},
+				Status: errorStatus(apperrors.Internal(err, "Failed to retrieve messages")),
 			}, nil
 		}
 	}
@@ -374,11 +339,7 @@ func (s *ChatService) GetMessages(ctx context.Context, req *chatpb.GetMessagesRe
 	}
 
 	return &chatpb.GetMessagesResponse{
-		Status: &commonpb.Status{
-			Code:    int32(codes.OK),
-			Message: "Messages retrieved successfully",
-			Success: true,
-		},
+		Status:     okStatus("Messages retrieved successfully"),
 		Messages:   protoMessages,
 		NextCursor: "", // Implement pagination cursor logic
 	}, nil
@@ -386,16 +347,10 @@ func (s *ChatService) GetMessages(ctx context.Context, req *chatpb.GetMessagesRe
 
 func (s *ChatService) GetChatrooms(ctx context.Context, req *chatpb.GetChatroomsRequest) (*chatpb.GetChatroomsResponse, error) {
 	// Validate user exists
-	userResp, err := s.userClient.GetUser(ctx, &userpb.GetUserRequest{
-		UserId: req.UserId,
-	})
-	if err != nil || !userResp.Status.Success {
+	_, err := resolveUser(ctx, s.userClient, s.identities, req.UserId)
+	if err != nil {
 		return &chatpb.GetChatroomsResponse{
-			Status: &commonpb.Status{
-				Code:    int32(codes.NotFound),
-				Message: "User not found",
-				Success: false,
-			},
+			Status: errorStatus(err),
 		}, nil
 	}
 
@@ -404,11 +359,7 @@ func (s *ChatService) GetChatrooms(ctx context.Context, req *chatpb.GetChatrooms
 	if err != nil {
 		log.Printf("Failed to get user chatrooms: %v", err)
 		return &chatpb.GetChatroomsResponse{
-			Status: &commonpb.Status{
-				Code:    int32(codes.Internal),
-				Message: "Failed to retrieve chatrooms",
-				Success: false,
-			},
+			Status: errorStatus(apperrors.Internal(err, "Failed to retrieve chatrooms")),
 		}, nil
 	}
 
@@ -418,15 +369,215 @@ func (s *ChatService) GetChatrooms(ctx context.Context, req *chatpb.GetChatrooms
 	}
 
 	return &chatpb.GetChatroomsResponse{
-		Status: &commonpb.Status{
-			Code:    int32(codes.OK),
-			Message: "Chatrooms retrieved successfully",
-			Success: true,
-		},
+		Status:    okStatus("Chatrooms retrieved successfully"),
 		Chatrooms: protoChatrooms,
 	}, nil
 }
 
+func (s *ChatService) PinMessage(ctx context.Context, req *chatpb.PinMessageRequest) (*chatpb.PinMessageResponse, error) {
+	chatroom, err := s.dynamoRepo.GetChatroom(ctx, req.ChatroomId)
+	if err != nil {
+		return &chatpb.PinMessageResponse{
+			Status: errorStatus(apperrors.NotFound("Chatroom not found")),
+		}, nil
+	}
+
+	// Only the room's creator moderates it
+	if chatroom.CreatorID != req.UserId {
+		return &chatpb.PinMessageResponse{
+			Status: errorStatus(apperrors.Forbidden("Only the chatroom creator can pin messages")),
+		}, nil
+	}
+
+	pinned := make([]*models.Message, 0, len(chatroom.PinnedMessages)+1)
+	for _, message := range chatroom.PinnedMessages {
+		if message.ID != req.MessageId {
+			pinned = append(pinned, message)
+		}
+	}
+
+	if req.Pinned {
+		message, err := s.dynamoRepo.GetMessage(ctx, req.MessageId)
+		if err != nil || message.ChatroomID != req.ChatroomId {
+			return &chatpb.PinMessageResponse{
+				Status: errorStatus(apperrors.NotFound("Message not found")),
+			}, nil
+		}
+
+		pinned = append(pinned, message)
+		if len(pinned) > maxPinnedMessages {
+			pinned = pinned[len(pinned)-maxPinnedMessages:] // the oldest pin makes room
+		}
+	}
+
+	err = s.dynamoRepo.SetPinnedMessages(ctx, req.ChatroomId, pinned)
+	if err != nil {
+		log.Printf("Failed to set pinned messages: %v", err)
+		return &chatpb.PinMessageResponse{
+			Status: errorStatus(apperrors.Internal(err, "Failed to pin message")),
+		}, nil
+	}
+	s.projection.PinsChanged(ctx, req.ChatroomId)
+
+	return &chatpb.PinMessageResponse{
+		Status: okStatus("Pinned messages updated successfully"),
+	}, nil
+}
+
+// GetRoomSnapshot returns everything a room page shows in one call. Public rooms don't need a
+// user; private rooms are only shown to their members.
+func (s *ChatService) GetRoomSnapshot(ctx context.Context, req *chatpb.GetRoomSnapshotRequest) (*chatpb.GetRoomSnapshotResponse, error) {
+	snapshot, err := s.projection.Snapshot(ctx, req.ChatroomId, int(req.RecentLimit))
+	if err != nil {
+		log.Printf("Failed to get room snapshot: %v", err)
+		return &chatpb.GetRoomSnapshotResponse{
+			Status: errorStatus(apperrors.NotFound("Chatroom not found")),
+		}, nil
+	}
+
+	if snapshot.Chatroom.IsPrivate {
+		isMember, err := s.dynamoRepo.IsUserMemberOfChatroom(ctx, req.ChatroomId, req.UserId)
+		if err != nil || !isMember {
+			return &chatpb.GetRoomSnapshotResponse{
+				Status: errorStatus(apperrors.Forbidden("User is not a member of this chatroom")),
+			}, nil
+		}
+	}
+
+	return &chatpb.GetRoomSnapshotResponse{
+		Status:   okStatus("Room snapshot retrieved successfully"),
+		Snapshot: roomSnapshotToProto(snapshot),
+	}, nil
+}
+
+// GetTopChatters returns the users who sent the most messages to a room, read from the
+// hourly rollups
+func (s *ChatService) GetTopChatters(ctx context.Context, req *chatpb.GetTopChattersRequest) (*chatpb.GetTopChattersResponse, error) {
+	period, ok := rollupPeriod(req.PeriodHours)
+	if !ok {
+		return &chatpb.GetTopChattersResponse{
+			Status: errorStatus(apperrors.InvalidRequest(fmt.Sprintf("period_hours must be at most %d", int(maxRollupPeriod/time.Hour)))),
+		}, nil
+	}
+
+	limit := int(req.Limit)
+	if limit <= 0 {
+		limit = defaultTopChatters
+	} else if limit > maxTopChatters {
+		limit = maxTopChatters
+	}
+
+	chatters, err := s.rollups.TopChatters(ctx, req.ChatroomId, period, limit)
+	if err != nil {
+		log.Printf("Failed to get top chatters: %v", err)
+		return &chatpb.GetTopChattersResponse{
+			Status: errorStatus(apperrors.Internal(err, "Failed to get top chatters")),
+		}, nil
+	}
+
+	protoChatters := make([]*chatpb.TopChatter, len(chatters))
+	for i, chatter := range chatters {
+		protoChatters[i] = &chatpb.TopChatter{
+			UserId:   chatter.UserID,
+			Username: chatter.Username,
+			Messages: chatter.Messages,
+		}
+	}
+
+	return &chatpb.GetTopChattersResponse{
+		Status:   okStatus("Top chatters retrieved successfully"),
+		Chatters: protoChatters,
+	}, nil
+}
+
+// GetTopEmotes returns the emotes used the most in a room, so broadcasters can see which of
+// their emotes earn their slot
+func (s *ChatService) GetTopEmotes(ctx context.Context, req *chatpb.GetTopEmotesRequest) (*chatpb.GetTopEmotesResponse, error) {
+	period, ok := rollupPeriod(req.PeriodHours)
+	if !ok {
+		return &chatpb.GetTopEmotesResponse{
+			Status: errorStatus(apperrors.InvalidRequest(fmt.Sprintf("period_hours must be at most %d", int(maxRollupPeriod/time.Hour)))),
+		}, nil
+	}
+
+	limit := int(req.Limit)
+	if limit <= 0 {
+		limit = topEmoteCount
+	} else if limit > maxTopEmotes {
+		limit = maxTopEmotes
+	}
+
+	emotes, err := s.rollups.TopEmotes(ctx, req.ChatroomId, period, limit)
+	if err != nil {
+		log.Printf("Failed to get top emotes: %v", err)
+		return &chatpb.GetTopEmotesResponse{
+			Status: errorStatus(apperrors.Internal(err, "Failed to get top emotes")),
+		}, nil
+	}
+
+	protoEmotes := make([]*chatpb.EmoteCount, len(emotes))
+	for i, emote := range emotes {
+		protoEmotes[i] = &chatpb.EmoteCount{
+			Name:  emote.Name,
+			Count: emote.Count,
+		}
+	}
+
+	return &chatpb.GetTopEmotesResponse{
+		Status: okStatus("Top emotes retrieved successfully"),
+		Emotes: protoEmotes,
+	}, nil
+}
+
+// GetChatActivity returns the hourly activity of a room for its broadcaster's dashboard
+func (s *ChatService) GetChatActivity(ctx context.Context, req *chatpb.GetChatActivityRequest) (*chatpb.GetChatActivityResponse, error) {
+	period, ok := rollupPeriod(req.PeriodHours)
+	if !ok {
+		return &chatpb.GetChatActivityResponse{
+			Status: errorStatus(apperrors.InvalidRequest(fmt.Sprintf("period_hours must be at most %d", int(maxRollupPeriod/time.Hour)))),
+		}, nil
+	}
+
+	rollups, err := s.rollups.Activity(ctx, req.ChatroomId, period)
+	if err != nil {
+		log.Printf("Failed to get chat activity: %v", err)
+		return &chatpb.GetChatActivityResponse{
+			Status: errorStatus(apperrors.Internal(err, "Failed to get chat activity")),
+		}, nil
+	}
+
+	resp := &chatpb.GetChatActivityResponse{
+		Status:  okStatus("Chat activity retrieved successfully"),
+		Buckets: make([]*chatpb.ChatActivityBucket, len(rollups)),
+	}
+	for i, rollup := range rollups {
+		resp.Buckets[i] = &chatpb.ChatActivityBucket{
+			Hour: &commonpb.Timestamp{
+				Seconds: rollup.Hour.Unix(),
+			},
+			Messages:          rollup.Messages,
+			UniqueChatters:    rollup.UniqueChatters,
+			ModerationActions: rollup.ModerationActions,
+		}
+		resp.TotalMessages += rollup.Messages
+		resp.TotalModerationActions += rollup.ModerationActions
+	}
+
+	return resp, nil
+}
+
+// Projection returns the room projection, which also counts emotes of messages sent over
+// WebSocket
+func (s *ChatService) Projection() *RoomProjection {
+	return s.projection
+}
+
+// SetModeration installs the pipeline sent messages are scored by. The pipeline needs the
+// WebSocket hub, which is created after the service.
+func (s *ChatService) SetModeration(moderation *ModerationPipeline) {
+	s.moderation = moderation
+}
+
 // Helper functions for proto conversion
 func chatroomToProto(chatroom *models.Chatroom) *chatpb.Chatroom {
 	return &chatpb.Chatroom{
@@ -447,6 +598,38 @@ func chatroomToProto(chatroom *models.Chatroom) *chatpb.Chatroom {
 	}
 }
 
+func roomSnapshotToProto(snapshot *models.RoomSnapshot) *chatpb.RoomSnapshot {
+	pinned := make([]*chatpb.Message, len(snapshot.PinnedMessages))
+	for i, msg := range snapshot.PinnedMessages {
+		pinned[i] = messageToProto(msg)
+	}
+
+	recent := make([]*chatpb.Message, len(snapshot.RecentMessages))
+	for i, msg := range snapshot.RecentMessages {
+		recent[i] = messageToProto(msg)
+	}
+
+	emotes := make([]*chatpb.EmoteCount, len(snapshot.TopEmotes))
+	for i, emote := range snapshot.TopEmotes {
+		emotes[i] = &chatpb.EmoteCount{
+			Name:  emote.Name,
+			Count: emote.Count,
+		}
+	}
+
+	return &chatpb.RoomSnapshot{
+		Chatroom:       chatroomToProto(snapshot.Chatroom),
+		MemberCount:    snapshot.MemberCount,
+		PinnedMessages: pinned,
+		TopEmotes:      emotes,
+		RecentMessages: recent,
+		UpdatedAt: &commonpb.Timestamp{
+			Seconds: snapshot.UpdatedAt.Unix(),
+			Nanos:   int32(snapshot.UpdatedAt.Nanosecond()),
+		},
+	}
+}
+
 func messageToProto(message *models.Message) *chatpb.Message {
 	return &chatpb.Message{
 		Id:         message.ID,
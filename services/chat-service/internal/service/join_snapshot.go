@@ -0,0 +1,102 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/services/chat-service/internal/models"
+)
+
+// JoinSnapshotter sends a client joining a room over WebSocket the room's recent messages,
+// pins and modes in one frame, read from the room page in Redis
+type JoinSnapshotter struct {
+	projection     *RoomProjection
+	chatService    *ChatService
+	recentMessages int
+}
+
+// joinSnapshotFrame is what the client gets before the room's live messages. Messages are
+// oldest first, like they are shown.
+type joinSnapshotFrame struct {
+	Type       string           `json:"type"` // always snapshot
+	ChatroomID string           `json:"chatroom_id"`
+	Data       joinSnapshotData `json:"data"`
+}
+
+type joinSnapshotData struct {
+	Modes          roomModes         `json:"modes"`
+	MemberCount    int64             `json:"member_count"`
+	PinnedMessages []snapshotMessage `json:"pinned_messages"`
+	RecentMessages []snapshotMessage `json:"recent_messages"`
+}
+
+type roomModes struct {
+	Private bool `json:"private"`
+}
+
+// snapshotMessage is a message in the shape of live room messages, plus its ID so clients can
+// drop duplicates
+type snapshotMessage struct {
+	ID       string `json:"id"`
+	UserID   string `json:"user_id"`
+	Username string `json:"username"`
+	Content  string `json:"content"`
+	SentAt   int64  `json:"sent_at"`
+}
+
+func NewJoinSnapshotter(recentMessages int, chatService *ChatService) *JoinSnapshotter {
+	return &JoinSnapshotter{
+		projection:     chatService.projection,
+		chatService:    chatService,
+		recentMessages: recentMessages,
+	}
+}
+
+// JoinSnapshot returns nil for private rooms the user isn't a member of
+func (j *JoinSnapshotter) JoinSnapshot(ctx context.Context, roomID, userID string) ([]byte, error) {
+	snapshot, err := j.projection.Snapshot(ctx, roomID, j.recentMessages)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get room snapshot: %w", err)
+	}
+
+	if snapshot.Chatroom.IsPrivate {
+		isMember, err := j.chatService.dynamoRepo.IsUserMemberOfChatroom(ctx, roomID, userID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check chatroom membership: %w", err)
+		}
+		if !isMember {
+			return nil, nil
+		}
+	}
+
+	frame := joinSnapshotFrame{
+		Type:       "snapshot",
+		ChatroomID: roomID,
+		Data: joinSnapshotData{
+			Modes:          roomModes{Private: snapshot.Chatroom.IsPrivate},
+			MemberCount:    snapshot.MemberCount,
+			PinnedMessages: make([]snapshotMessage, 0, len(snapshot.PinnedMessages)),
+			RecentMessages: make([]snapshotMessage, 0, len(snapshot.RecentMessages)),
+		},
+	}
+	for _, message := range snapshot.PinnedMessages {
+		frame.Data.PinnedMessages = append(frame.Data.PinnedMessages, toSnapshotMessage(message))
+	}
+	// The cache returns the newest message first
+	for i := len(snapshot.RecentMessages) - 1; i >= 0; i-- {
+		frame.Data.RecentMessages = append(frame.Data.RecentMessages, toSnapshotMessage(snapshot.RecentMessages[i]))
+	}
+
+	return json.Marshal(frame)
+}
+
+func toSnapshotMessage(message *models.Message) snapshotMessage {
+	return snapshotMessage{
+		ID:       message.ID,
+		UserID:   message.UserID,
+		Username: message.Username,
+		Content:  message.Content,
+		SentAt:   message.CreatedAt.Unix(),
+	}
+}
@@ -0,0 +1,221 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/services/chat-service/internal/config"
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/services/chat-service/internal/models"
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/services/chat-service/internal/repository"
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/services/chat-service/internal/server"
+)
+
+// MessageScorer rates how abusive a message is. Implementations wrap an ML model.
+type MessageScorer interface {
+	Score(ctx context.Context, message *models.Message) (*models.ModerationVerdict, error)
+}
+
+// HTTPMessageScorer posts messages to a scoring service that answers with a
+// models.ModerationVerdict
+type HTTPMessageScorer struct {
+	url    string
+	client *http.Client
+}
+
+type scoreRequest struct {
+	MessageID  string `json:"message_id"`
+	ChatroomID string `json:"chatroom_id"`
+	UserID     string `json:"user_id"`
+	Content    string `json:"content"`
+}
+
+func NewHTTPMessageScorer(url string, timeout time.Duration) *HTTPMessageScorer {
+	return &HTTPMessageScorer{
+		url:    url,
+		client: &http.Client{Timeout: timeout},
+	}
+}
+
+func (s *HTTPMessageScorer) Score(ctx context.Context, message *models.Message) (*models.ModerationVerdict, error) {
+	body, err := json.Marshal(scoreRequest{
+		MessageID:  message.ID,
+		ChatroomID: message.ChatroomID,
+		UserID:     message.UserID,
+		Content:    message.Content,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal score request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create score request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to score message: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("scoring service returned %s", resp.Status)
+	}
+
+	var verdict models.ModerationVerdict
+	if err := json.NewDecoder(resp.Body).Decode(&verdict); err != nil {
+		return nil, fmt.Errorf("failed to decode verdict: %w", err)
+	}
+	return &verdict, nil
+}
+
+// ModerationPipeline scores messages after they were delivered, so the model never adds to
+// chat latency, and takes back the ones scoring over the threshold: stored messages are
+// deleted and every client in the room is told to remove the message.
+type ModerationPipeline struct {
+	scorer     MessageScorer
+	hub        *server.Hub
+	dynamoRepo repository.DynamoDBRepository
+	redisRepo  repository.RedisRepository
+	rollups    *ChatRollups
+	config     config.ModerationConfig
+
+	queue chan moderationJob
+}
+
+type moderationJob struct {
+	message *models.Message
+	stored  bool // false for messages only sent over WebSocket, there is nothing to delete
+	comment *models.VODComment
+}
+
+func NewModerationPipeline(cfg config.ModerationConfig, scorer MessageScorer, hub *server.Hub, dynamoRepo repository.DynamoDBRepository, redisRepo repository.RedisRepository, rollups *ChatRollups) *ModerationPipeline {
+	return &ModerationPipeline{
+		scorer:     scorer,
+		hub:        hub,
+		dynamoRepo: dynamoRepo,
+		redisRepo:  redisRepo,
+		rollups:    rollups,
+		config:     cfg,
+		queue:      make(chan moderationJob, cfg.QueueSize),
+	}
+}
+
+// Start runs the scoring workers until ctx is done
+func (p *ModerationPipeline) Start(ctx context.Context) {
+	workers := p.config.Workers
+	if workers <= 0 {
+		workers = 1
+	}
+
+	for i := 0; i < workers; i++ {
+		go func() {
+			for {
+				select {
+				case job := <-p.queue:
+					p.moderate(ctx, job)
+				case <-ctx.Done():
+					return
+				}
+			}
+		}()
+	}
+}
+
+// Submit queues a stored message for scoring. Messages are skipped when the queue is full,
+// a backlog would only remove them long after everyone read them.
+func (p *ModerationPipeline) Submit(message *models.Message) {
+	p.enqueue(moderationJob{message: message, stored: true})
+}
+
+// SubmitComment queues a VOD comment for scoring, flagged comments are deleted
+func (p *ModerationPipeline) SubmitComment(comment *models.VODComment) {
+	p.enqueue(moderationJob{
+		message: &models.Message{
+			ID:         comment.ID,
+			ChatroomID: comment.ChatroomID,
+			UserID:     comment.UserID,
+			Username:   comment.Username,
+			Content:    comment.Content,
+			Type:       models.MessageTypeText,
+			CreatedAt:  comment.UpdatedAt,
+		},
+		comment: comment,
+	})
+}
+
+// MessageDelivered implements server.MessageObserver for messages sent over WebSocket
+func (p *ModerationPipeline) MessageDelivered(message *models.Message) {
+	p.enqueue(moderationJob{message: message})
+}
+
+// MessageBlocked implements server.MessageObserver, blocked messages were never delivered so
+// there is nothing to score
+func (p *ModerationPipeline) MessageBlocked(roomID, userID string) {}
+
+func (p *ModerationPipeline) enqueue(job moderationJob) {
+	if job.message.Type == models.MessageTypeSystem {
+		return
+	}
+
+	select {
+	case p.queue <- job:
+	default:
+		log.Printf("Moderation queue full, message %s in chatroom %s is not scored", job.message.ID, job.message.ChatroomID)
+	}
+}
+
+func (p *ModerationPipeline) moderate(ctx context.Context, job moderationJob) {
+	message := job.message
+
+	scoreCtx, cancel := context.WithTimeout(ctx, p.config.Timeout)
+	verdict, err := p.scorer.Score(scoreCtx, message)
+	cancel()
+	if err != nil {
+		log.Printf("Failed to score message %s: %v", message.ID, err)
+		return
+	}
+	if verdict.Score < p.config.Threshold {
+		return
+	}
+
+	if comment := job.comment; comment != nil {
+		if err := p.dynamoRepo.DeleteVODComment(ctx, comment); err != nil {
+			log.Printf("Failed to delete VOD comment %s flagged by moderation: %v", comment.ID, err)
+			return
+		}
+		log.Printf("Removed comment %s from user %s on VOD %s, moderation score %.2f %v",
+			comment.ID, comment.UserID, comment.VODID, verdict.Score, verdict.Labels)
+		return
+	}
+
+	if job.stored {
+		if err := p.dynamoRepo.DeleteMessage(ctx, message.ID); err != nil {
+			log.Printf("Failed to delete message %s flagged by moderation: %v", message.ID, err)
+		}
+		if err := p.redisRepo.RemoveCachedMessage(ctx, message); err != nil {
+			log.Printf("Failed to remove message %s from cache: %v", message.ID, err)
+		}
+	}
+
+	deletion, err := json.Marshal(map[string]interface{}{
+		"type":        "message_deleted",
+		"chatroom_id": message.ChatroomID,
+		"message_id":  message.ID,
+		"reason":      "moderation",
+	})
+	if err != nil {
+		return
+	}
+	p.hub.Publish(message.ChatroomID, deletion)
+	p.rollups.MessageModerated(message.ChatroomID, message.UserID)
+
+	log.Printf("Removed message %s from user %s in chatroom %s, moderation score %.2f %v",
+		message.ID, message.UserID, message.ChatroomID, verdict.Score, verdict.Labels)
+}
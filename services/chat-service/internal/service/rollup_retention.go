@@ -0,0 +1,179 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/services/chat-service/internal/config"
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/services/chat-service/internal/models"
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/services/chat-service/internal/repository"
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/shared/go/pkg/apperrors"
+)
+
+// RollupRetention keeps the rollup table from growing forever and from holding who chatted for
+// longer than needed. Hourly chatter and emote rollups past the raw retention are folded into
+// daily ones, dashboards never read that far back. Daily chatter rollups past the
+// anonymization period keep their message counts under a random ID instead of the user's.
+// Room rollups are kept as they are. Every replica runs retention, a rollup is only ever
+// taken by one of them.
+type RollupRetention struct {
+	dynamoRepo repository.DynamoDBRepository
+	config     config.RetentionConfig
+
+	mu   sync.Mutex
+	last *models.RetentionReport
+}
+
+func NewRollupRetention(cfg config.RetentionConfig, dynamoRepo repository.DynamoDBRepository) *RollupRetention {
+	return &RollupRetention{
+		dynamoRepo: dynamoRepo,
+		config:     cfg,
+	}
+}
+
+// Run applies retention every interval until ctx is done
+func (r *RollupRetention) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.config.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case now := <-ticker.C:
+			r.Apply(ctx, now)
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// Apply downsamples and anonymizes the rollups that are old enough at now and reports what it
+// did
+func (r *RollupRetention) Apply(ctx context.Context, now time.Time) *models.RetentionReport {
+	report := &models.RetentionReport{StartedAt: now.UTC()}
+
+	rawCutoff := now.UTC().Add(-r.config.RawRetention).Truncate(time.Hour)
+	if err := r.downsampleChatters(ctx, rawCutoff, report); err != nil {
+		log.Printf("Failed to downsample chatter rollups: %v", err)
+	}
+	if err := r.downsampleEmotes(ctx, rawCutoff, report); err != nil {
+		log.Printf("Failed to downsample emote rollups: %v", err)
+	}
+
+	// Hourly rollups still name their users, so they are never anonymized before they are
+	// downsampled
+	if r.config.AnonymizeAfter > 0 {
+		after := r.config.AnonymizeAfter
+		if after < r.config.RawRetention {
+			after = r.config.RawRetention
+		}
+		cutoff := now.UTC().Add(-after).Truncate(24 * time.Hour)
+		if err := r.anonymizeChatters(ctx, cutoff, report); err != nil {
+			log.Printf("Failed to anonymize chatter rollups: %v", err)
+		}
+	}
+
+	report.FinishedAt = time.Now().UTC()
+	log.Printf("🧹 Rollup retention: %d downsampled, %d anonymized, %d failed, ~%d bytes reclaimed in %s",
+		report.Downsampled, report.Anonymized, report.Failed, report.BytesReclaimed, report.FinishedAt.Sub(report.StartedAt).Round(time.Millisecond))
+
+	r.mu.Lock()
+	r.last = report
+	r.mu.Unlock()
+	return report
+}
+
+func (r *RollupRetention) downsampleChatters(ctx context.Context, before time.Time, report *models.RetentionReport) error {
+	return r.dynamoRepo.ScanChatterRollups(ctx, false, before, func(rollup *models.ChatterRollup) error {
+		taken, size, err := r.dynamoRepo.TakeChatterRollup(ctx, rollup, false)
+		if err != nil {
+			log.Printf("Failed to take chatter rollup of chatroom %s: %v", rollup.ChatroomID, err)
+			report.Failed++
+			return ctx.Err()
+		}
+		if taken == nil {
+			return nil // another replica took it
+		}
+
+		added, err := r.dynamoRepo.AddDailyChatterRollup(ctx, taken)
+		if err != nil {
+			// The hour is gone already, its messages are lost from the daily rollup
+			log.Printf("Failed to add chatter rollup of chatroom %s to its day: %v", taken.ChatroomID, err)
+			report.Failed++
+			report.BytesReclaimed += size
+			return ctx.Err()
+		}
+		report.Downsampled++
+		report.BytesReclaimed += size - added
+		return ctx.Err()
+	})
+}
+
+func (r *RollupRetention) downsampleEmotes(ctx context.Context, before time.Time, report *models.RetentionReport) error {
+	return r.dynamoRepo.ScanEmoteRollups(ctx, before, func(rollup *models.EmoteRollup) error {
+		taken, size, err := r.dynamoRepo.TakeEmoteRollup(ctx, rollup)
+		if err != nil {
+			log.Printf("Failed to take emote rollup of chatroom %s: %v", rollup.ChatroomID, err)
+			report.Failed++
+			return ctx.Err()
+		}
+		if taken == nil {
+			return nil // another replica took it
+		}
+
+		added, err := r.dynamoRepo.AddDailyEmoteRollup(ctx, taken)
+		if err != nil {
+			log.Printf("Failed to add emote rollup of chatroom %s to its day: %v", taken.ChatroomID, err)
+			report.Failed++
+			report.BytesReclaimed += size
+			return ctx.Err()
+		}
+		report.Downsampled++
+		report.BytesReclaimed += size - added
+		return ctx.Err()
+	})
+}
+
+func (r *RollupRetention) anonymizeChatters(ctx context.Context, before time.Time, report *models.RetentionReport) error {
+	return r.dynamoRepo.ScanChatterRollups(ctx, true, before, func(rollup *models.ChatterRollup) error {
+		taken, size, err := r.dynamoRepo.TakeChatterRollup(ctx, rollup, true)
+		if err != nil {
+			log.Printf("Failed to take daily chatter rollup of chatroom %s: %v", rollup.ChatroomID, err)
+			report.Failed++
+			return ctx.Err()
+		}
+		if taken == nil {
+			return nil // another replica took it
+		}
+
+		added, err := r.dynamoRepo.PutAnonymousChatterRollup(ctx, taken)
+		if err != nil {
+			log.Printf("Failed to store anonymous chatter rollup of chatroom %s: %v", taken.ChatroomID, err)
+			report.Failed++
+			report.BytesReclaimed += size
+			return ctx.Err()
+		}
+		report.Anonymized++
+		report.BytesReclaimed += size - added
+		return ctx.Err()
+	})
+}
+
+// HandleGetReport handles GET /retention/report with the report of this replica's last run.
+// The route is for admins only.
+func (r *RollupRetention) HandleGetReport(w http.ResponseWriter, req *http.Request) {
+	r.mu.Lock()
+	report := r.last
+	r.mu.Unlock()
+
+	if report == nil {
+		apperrors.WriteHTTP(w, req, apperrors.NotFound("retention has not run yet"))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(report)
+}
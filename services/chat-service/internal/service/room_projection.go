@@ -0,0 +1,158 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"regexp"
+	"time"
+
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/services/chat-service/internal/models"
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/services/chat-service/internal/repository"
+)
+
+const (
+	// roomPageTTL drops the pages of inactive rooms, they are rebuilt on the next load
+	roomPageTTL = 24 * time.Hour
+	// defaultRecentMessages is used when a snapshot request doesn't set a limit. The message
+	// cache keeps at most 100.
+	defaultRecentMessages = 50
+	topEmoteCount         = 10
+	maxPinnedMessages     = 5
+)
+
+// emotePattern matches emote codes like :PogChamp: in message content
+var emotePattern = regexp.MustCompile(`:([A-Za-z0-9_]{2,32}):`)
+
+// RoomProjection keeps the Redis room page of each chat room up to date from chat events, so
+// loading a room page is a single GetRoomSnapshot call instead of one call per section.
+// Updates are best effort: a failed one is logged and the page is dropped, the next load
+// rebuilds it from DynamoDB.
+type RoomProjection struct {
+	dynamoRepo repository.DynamoDBRepository
+	redisRepo  repository.RedisRepository
+}
+
+func NewRoomProjection(dynamoRepo repository.DynamoDBRepository, redisRepo repository.RedisRepository) *RoomProjection {
+	return &RoomProjection{
+		dynamoRepo: dynamoRepo,
+		redisRepo:  redisRepo,
+	}
+}
+
+func (p *RoomProjection) ChatroomCreated(ctx context.Context, chatroom *models.Chatroom) {
+	if err := p.redisRepo.SetRoomPage(ctx, chatroom, roomPageTTL); err != nil {
+		log.Printf("Failed to build room page for chatroom %s: %v", chatroom.ID, err)
+	}
+}
+
+func (p *RoomProjection) MemberJoined(ctx context.Context, chatroomID string) {
+	if err := p.redisRepo.IncrRoomMemberCount(ctx, chatroomID, 1); err != nil {
+		log.Printf("Failed to update room page for chatroom %s: %v", chatroomID, err)
+		p.invalidate(ctx, chatroomID)
+	}
+}
+
+// MemberLeft drops the page rather than decrementing its count, since leaving doesn't check
+// that the user was a member
+func (p *RoomProjection) MemberLeft(ctx context.Context, chatroomID string) {
+	p.invalidate(ctx, chatroomID)
+}
+
+// MessageSent counts the emotes of a message. The message itself is already in the message
+// cache the page reads its recent messages from.
+func (p *RoomProjection) MessageSent(ctx context.Context, message *models.Message) {
+	if message.Type == models.MessageTypeSystem {
+		return
+	}
+
+	emotes := countEmotes(message.Content)
+	if len(emotes) == 0 {
+		return
+	}
+	if err := p.redisRepo.IncrRoomEmotes(ctx, message.ChatroomID, emotes, roomPageTTL); err != nil {
+		log.Printf("Failed to count emotes for chatroom %s: %v", message.ChatroomID, err)
+	}
+}
+
+func (p *RoomProjection) PinsChanged(ctx context.Context, chatroomID string) {
+	p.invalidate(ctx, chatroomID)
+}
+
+// Snapshot returns the page of a room, rebuilding it from DynamoDB if it isn't in Redis
+func (p *RoomProjection) Snapshot(ctx context.Context, chatroomID string, recentLimit int) (*models.RoomSnapshot, error) {
+	if recentLimit <= 0 || recentLimit > 100 {
+		recentLimit = defaultRecentMessages
+	}
+
+	snapshot, err := p.redisRepo.GetRoomSnapshot(ctx, chatroomID, recentLimit, topEmoteCount)
+	if err != nil {
+		log.Printf("Failed to read room page for chatroom %s: %v", chatroomID, err)
+	}
+	if snapshot != nil {
+		return snapshot, nil
+	}
+
+	chatroom, err := p.dynamoRepo.GetChatroom(ctx, chatroomID)
+	if err != nil {
+		return nil, err
+	}
+	if err := p.redisRepo.SetRoomPage(ctx, chatroom, roomPageTTL); err != nil {
+		return nil, fmt.Errorf("failed to rebuild room page: %w", err)
+	}
+	p.seedEmotes(ctx, chatroomID)
+	log.Printf("Rebuilt room page for chatroom %s", chatroomID)
+
+	snapshot, err = p.redisRepo.GetRoomSnapshot(ctx, chatroomID, recentLimit, topEmoteCount)
+	if err != nil {
+		return nil, err
+	}
+	if snapshot == nil {
+		return nil, fmt.Errorf("room page for chatroom %s expired while rebuilding", chatroomID)
+	}
+	return snapshot, nil
+}
+
+// MessageDelivered implements server.MessageObserver, so emotes sent over WebSocket count too
+func (p *RoomProjection) MessageDelivered(message *models.Message) {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	p.MessageSent(ctx, message)
+}
+
+// MessageBlocked implements server.MessageObserver, blocked messages don't count
+func (p *RoomProjection) MessageBlocked(roomID, userID string) {}
+
+// seedEmotes fills the page's emote counts from the rollups of the last roomPageTTL if they
+// expired with the page
+func (p *RoomProjection) seedEmotes(ctx context.Context, chatroomID string) {
+	to := time.Now().UTC().Truncate(time.Hour)
+	emotes, err := topEmotes(ctx, p.dynamoRepo, chatroomID, to.Add(-roomPageTTL), to, topEmoteCount)
+	if err != nil {
+		log.Printf("Failed to read emote rollups for chatroom %s: %v", chatroomID, err)
+		return
+	}
+	if err := p.redisRepo.SeedRoomEmotes(ctx, chatroomID, emotes, roomPageTTL); err != nil {
+		log.Printf("Failed to seed emotes for chatroom %s: %v", chatroomID, err)
+	}
+}
+
+func (p *RoomProjection) invalidate(ctx context.Context, chatroomID string) {
+	if err := p.redisRepo.DeleteRoomPage(ctx, chatroomID); err != nil {
+		log.Printf("Failed to drop room page for chatroom %s: %v", chatroomID, err)
+	}
+}
+
+// countEmotes returns how many times each emote code appears in a message
+func countEmotes(content string) map[string]int64 {
+	matches := emotePattern.FindAllStringSubmatch(content, -1)
+	if len(matches) == 0 {
+		return nil
+	}
+
+	emotes := make(map[string]int64, len(matches))
+	for _, match := range matches {
+		emotes[match[1]]++
+	}
+	return emotes
+}
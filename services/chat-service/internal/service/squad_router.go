@@ -0,0 +1,148 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/services/chat-service/internal/models"
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/services/chat-service/internal/repository"
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/services/chat-service/internal/server"
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/shared/go/pkg/apperrors"
+)
+
+const (
+	// squadRouteTTL keeps routes of squads the stream service forgot about from lingering
+	squadRouteTTL = 12 * time.Hour
+	// routeCacheTTL is how long an instance trusts its copy of a room's route
+	routeCacheTTL = 5 * time.Second
+)
+
+type cachedRoute struct {
+	route   *server.RoomRoute
+	expires time.Time
+}
+
+// SquadRouter links the chat rooms of squad streams. Routes are stored in Redis so every
+// instance routes the same way, with a short local cache to keep lookups off the hot path.
+type SquadRouter struct {
+	redisRepo repository.RedisRepository
+
+	mutex sync.Mutex
+	cache map[string]cachedRoute
+}
+
+type squadRouteRequest struct {
+	Mode        models.SquadChatMode `json:"mode"`
+	ChatroomIDs []string             `json:"chatroom_ids"`
+}
+
+func NewSquadRouter(redisRepo repository.RedisRepository) *SquadRouter {
+	return &SquadRouter{
+		redisRepo: redisRepo,
+		cache:     make(map[string]cachedRoute),
+	}
+}
+
+// Route implements server.RoomRouter
+func (r *SquadRouter) Route(roomID string) *server.RoomRoute {
+	r.mutex.Lock()
+	cached, ok := r.cache[roomID]
+	r.mutex.Unlock()
+	if ok && time.Now().Before(cached.expires) {
+		return cached.route
+	}
+
+	route, err := r.lookup(roomID)
+	if err != nil {
+		log.Printf("Failed to look up squad route of room %s: %v", roomID, err)
+		return nil
+	}
+
+	r.mutex.Lock()
+	r.cache[roomID] = cachedRoute{route: route, expires: time.Now().Add(routeCacheTTL)}
+	r.mutex.Unlock()
+
+	return route
+}
+
+func (r *SquadRouter) lookup(roomID string) (*server.RoomRoute, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	squadID, err := r.redisRepo.GetChatroomSquad(ctx, roomID)
+	if err != nil || squadID == "" {
+		return nil, err
+	}
+
+	squadRoute, err := r.redisRepo.GetSquadRoute(ctx, squadID)
+	if err != nil || squadRoute == nil {
+		return nil, err
+	}
+
+	return &server.RoomRoute{
+		SquadID: squadRoute.SquadID,
+		Mode:    string(squadRoute.Mode),
+		Rooms:   squadRoute.ChatroomIDs,
+	}, nil
+}
+
+// HandlePutRoute handles PUT /squads/{id}/route
+func (r *SquadRouter) HandlePutRoute(w http.ResponseWriter, req *http.Request) {
+	squadID := mux.Vars(req)["id"]
+
+	var body squadRouteRequest
+	if err := json.NewDecoder(req.Body).Decode(&body); err != nil {
+		apperrors.WriteHTTP(w, req, apperrors.InvalidRequest("invalid request body"))
+		return
+	}
+	if body.Mode != models.SquadChatMerged && body.Mode != models.SquadChatSideBySide {
+		apperrors.WriteHTTP(w, req, apperrors.InvalidRequest("mode must be merged or side_by_side"))
+		return
+	}
+	if len(body.ChatroomIDs) == 0 {
+		apperrors.WriteHTTP(w, req, apperrors.InvalidRequest("chatroom_ids is required"))
+		return
+	}
+
+	route := &models.SquadRoute{
+		SquadID:     squadID,
+		Mode:        body.Mode,
+		ChatroomIDs: body.ChatroomIDs,
+		UpdatedAt:   time.Now(),
+	}
+	if err := r.redisRepo.SetSquadRoute(req.Context(), route, squadRouteTTL); err != nil {
+		apperrors.WriteHTTP(w, req, apperrors.Internal(err, "failed to set squad route"))
+		return
+	}
+	r.invalidate()
+
+	log.Printf("Squad %s chat routed %s across %d rooms", squadID, body.Mode, len(body.ChatroomIDs))
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(route)
+}
+
+// HandleDeleteRoute handles DELETE /squads/{id}/route
+func (r *SquadRouter) HandleDeleteRoute(w http.ResponseWriter, req *http.Request) {
+	squadID := mux.Vars(req)["id"]
+
+	if err := r.redisRepo.DeleteSquadRoute(req.Context(), squadID); err != nil {
+		apperrors.WriteHTTP(w, req, apperrors.Internal(err, "failed to delete squad route"))
+		return
+	}
+	r.invalidate()
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// invalidate drops this instance's cache, others catch up within routeCacheTTL
+func (r *SquadRouter) invalidate() {
+	r.mutex.Lock()
+	r.cache = make(map[string]cachedRoute)
+	r.mutex.Unlock()
+}
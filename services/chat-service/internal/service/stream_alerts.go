@@ -0,0 +1,151 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/services/chat-service/internal/repository"
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/services/chat-service/internal/server"
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/shared/go/pkg/apperrors"
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/shared/go/pkg/events"
+)
+
+// StreamAlertHandler relays stream health alerts from the stream service to the broadcaster:
+// their dashboard gets the alert and their chat room gets a system message only its
+// moderators see
+type StreamAlertHandler struct {
+	hub        *server.Hub
+	dynamoRepo repository.DynamoDBRepository
+}
+
+type streamAlertRequest struct {
+	UserID     string    `json:"user_id"`     // broadcaster
+	ChatroomID string    `json:"chatroom_id"` // defaults to the stream ID
+	Kind       string    `json:"kind"`
+	Severity   string    `json:"severity"`
+	Message    string    `json:"message"`
+	Value      float64   `json:"value"`
+	Threshold  float64   `json:"threshold"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+func NewStreamAlertHandler(hub *server.Hub, dynamoRepo repository.DynamoDBRepository) *StreamAlertHandler {
+	return &StreamAlertHandler{
+		hub:        hub,
+		dynamoRepo: dynamoRepo,
+	}
+}
+
+// HandlePostAlert handles POST /streams/{id}/alerts
+func (h *StreamAlertHandler) HandlePostAlert(w http.ResponseWriter, req *http.Request) {
+	streamID := mux.Vars(req)["id"]
+
+	var body streamAlertRequest
+	if err := json.NewDecoder(req.Body).Decode(&body); err != nil {
+		apperrors.WriteHTTP(w, req, apperrors.InvalidRequest("invalid request body"))
+		return
+	}
+	if body.UserID == "" || body.Message == "" {
+		apperrors.WriteHTTP(w, req, apperrors.InvalidRequest("user_id and message are required"))
+		return
+	}
+	if body.ChatroomID == "" {
+		body.ChatroomID = streamID
+	}
+	if body.CreatedAt.IsZero() {
+		body.CreatedAt = time.Now()
+	}
+
+	dashboards, err := h.relay(req.Context(), streamID, body)
+	if err != nil {
+		apperrors.WriteHTTP(w, req, apperrors.Internal(err, "failed to encode alert"))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(map[string]interface{}{"delivered": dashboards})
+}
+
+// HandleAlertEvent relays a stream_health_alert event taken off the event bus
+func (h *StreamAlertHandler) HandleAlertEvent(ctx context.Context, envelope *events.Envelope) error {
+	var event struct {
+		StreamID  string  `json:"stream_id"`
+		UserID    int64   `json:"user_id"`
+		Kind      string  `json:"kind"`
+		Severity  string  `json:"severity"`
+		Message   string  `json:"message"`
+		Value     float64 `json:"value"`
+		Threshold float64 `json:"threshold"`
+	}
+	if err := json.Unmarshal(envelope.Data, &event); err != nil {
+		return fmt.Errorf("failed to decode %s event: %w", envelope.EventType, err)
+	}
+
+	_, err := h.relay(ctx, event.StreamID, streamAlertRequest{
+		UserID:     strconv.FormatInt(event.UserID, 10),
+		ChatroomID: event.StreamID, // a stream's chat room shares its ID
+		Kind:       event.Kind,
+		Severity:   event.Severity,
+		Message:    event.Message,
+		Value:      event.Value,
+		Threshold:  event.Threshold,
+		CreatedAt:  time.Unix(envelope.Timestamp, 0),
+	})
+	return err
+}
+
+// relay sends an alert to the broadcaster's dashboards and their room's moderators. It
+// returns how many dashboard connections got it.
+func (h *StreamAlertHandler) relay(ctx context.Context, streamID string, body streamAlertRequest) (int, error) {
+	alert, err := json.Marshal(map[string]interface{}{
+		"type": "stream_alert",
+		"data": map[string]interface{}{
+			"stream_id":  streamID,
+			"kind":       body.Kind,
+			"severity":   body.Severity,
+			"message":    body.Message,
+			"value":      body.Value,
+			"threshold":  body.Threshold,
+			"created_at": body.CreatedAt.Unix(),
+		},
+	})
+	if err != nil {
+		return 0, err
+	}
+	dashboards := h.hub.SendToUser(body.UserID, alert)
+
+	system, err := json.Marshal(map[string]interface{}{
+		"type":        "system",
+		"chatroom_id": body.ChatroomID,
+		"visibility":  "moderators",
+		"content":     body.Message,
+		"sent_at":     body.CreatedAt.Unix(),
+	})
+	if err != nil {
+		return dashboards, err
+	}
+	h.hub.PublishToUsers(body.ChatroomID, h.moderators(ctx, body.ChatroomID, body.UserID), system)
+
+	log.Printf("Stream %s health alert (%s) sent to %d dashboard connection(s) of user %s", streamID, body.Kind, dashboards, body.UserID)
+	return dashboards, nil
+}
+
+// moderators lists the users allowed to see system messages of a room: the broadcaster and
+// the room's creator
+func (h *StreamAlertHandler) moderators(ctx context.Context, chatroomID, broadcasterID string) []string {
+	moderators := []string{broadcasterID}
+
+	chatroom, err := h.dynamoRepo.GetChatroom(ctx, chatroomID)
+	if err == nil && chatroom != nil && chatroom.CreatorID != broadcasterID {
+		moderators = append(moderators, chatroom.CreatorID)
+	}
+	return moderators
+}
@@ -0,0 +1,97 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"time"
+
+	"github.com/nats-io/nats.go/jetstream"
+
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/shared/go/pkg/eventbus"
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/shared/go/pkg/events"
+)
+
+// deadLetterTimeout bounds how long writing a dead letter may hold up the consumer
+const deadLetterTimeout = 5 * time.Second
+
+// StreamEventHandler handles one event type of the stream management service
+type StreamEventHandler func(ctx context.Context, envelope *events.Envelope) error
+
+// StreamEventConsumer takes the events of the stream management service off the event bus.
+// Each record is an envelope whose payload is validated against the schema version it was
+// published with, records that don't match a known schema are dead-lettered unhandled.
+type StreamEventConsumer struct {
+	js       jetstream.JetStream
+	name     string
+	consumer *events.Consumer
+	handlers map[string]StreamEventHandler
+}
+
+// NewStreamEventConsumer creates a consumer durable under name, replicas sharing the name
+// share the events. Dead letters go to platform.chat.dead_letter, events deduper remembers as
+// handled are skipped when JetStream delivers them again.
+func NewStreamEventConsumer(js jetstream.JetStream, name string, deduper events.Deduper) (*StreamEventConsumer, error) {
+	registry, err := events.NewRegistry()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load event schemas: %w", err)
+	}
+
+	deadLetters := events.StreamDeadLetters{Writer: jetStreamWriter{js: js, subject: eventbus.ChatEventSubject("dead_letter")}}
+	return &StreamEventConsumer{
+		js:       js,
+		name:     name,
+		consumer: events.NewConsumer(registry, name, deadLetters).WithDeduper(deduper),
+		handlers: make(map[string]StreamEventHandler),
+	}, nil
+}
+
+// Handle registers the handler of an event type, before Run
+func (c *StreamEventConsumer) Handle(eventType string, handler StreamEventHandler) {
+	c.handlers[eventType] = handler
+}
+
+// Run hands events to their handlers until ctx is done
+func (c *StreamEventConsumer) Run(ctx context.Context) error {
+	eventTypes := make([]string, 0, len(c.handlers))
+	for eventType := range c.handlers {
+		eventTypes = append(eventTypes, eventType)
+	}
+	sort.Strings(eventTypes)
+	log.Printf("📡 Consuming %v events of %s as %s", eventTypes, eventbus.StreamName(eventbus.DomainStream), c.name)
+
+	return eventbus.Consume(ctx, c.js, eventbus.DomainStream, eventbus.ConsumerOptions{
+		Name:       c.name,
+		EventTypes: eventTypes,
+	}, c.handle)
+}
+
+// handle passes a message to the validating consumer. It only fails when a dead letter
+// couldn't be written, so JetStream delivers the event again.
+func (c *StreamEventConsumer) handle(ctx context.Context, msg jetstream.Msg) error {
+	return c.consumer.Handle(msg.Data(), func(envelope *events.Envelope) error {
+		handler, ok := c.handlers[envelope.EventType]
+		if !ok {
+			// The subject didn't match the event type of the envelope
+			return fmt.Errorf("no handler for %s events", envelope.EventType)
+		}
+		return handler(ctx, envelope)
+	})
+}
+
+// jetStreamWriter publishes records on a subject, for the dead letters of the chat service
+type jetStreamWriter struct {
+	js      jetstream.JetStream
+	subject string
+}
+
+func (w jetStreamWriter) PutRecord(data string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), deadLetterTimeout)
+	defer cancel()
+
+	if _, err := w.js.Publish(ctx, w.subject, []byte(data)); err != nil {
+		return fmt.Errorf("failed to publish to %s: %w", w.subject, err)
+	}
+	return nil
+}
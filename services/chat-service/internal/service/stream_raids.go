@@ -0,0 +1,152 @@
+// services/chat-service/internal/service/stream_raids.go
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/services/chat-service/internal/server"
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/shared/go/pkg/apperrors"
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/shared/go/pkg/events"
+)
+
+// StreamRaidHandler relays raids from the stream service, taken off the event bus or pushed
+// over HTTP: players in the raiding room get a raid message telling them where to go, and
+// both rooms get a system message announcing it
+type StreamRaidHandler struct {
+	hub *server.Hub
+}
+
+type streamRaidRequest struct {
+	RaidID         string    `json:"raid_id"`
+	FromChatroomID string    `json:"from_chatroom_id"` // defaults to the stream ID
+	FromUserID     string    `json:"from_user_id"`
+	FromTitle      string    `json:"from_title"`
+	ToStreamID     string    `json:"to_stream_id"`
+	ToChatroomID   string    `json:"to_chatroom_id"` // defaults to the target stream ID
+	ToUserID       string    `json:"to_user_id"`
+	ToTitle        string    `json:"to_title"`
+	ViewerCount    int       `json:"viewer_count"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+func NewStreamRaidHandler(hub *server.Hub) *StreamRaidHandler {
+	return &StreamRaidHandler{hub: hub}
+}
+
+// HandlePostRaid handles POST /streams/{id}/raid
+func (h *StreamRaidHandler) HandlePostRaid(w http.ResponseWriter, req *http.Request) {
+	streamID := mux.Vars(req)["id"]
+
+	var body streamRaidRequest
+	if err := json.NewDecoder(req.Body).Decode(&body); err != nil {
+		apperrors.WriteHTTP(w, req, apperrors.InvalidRequest("invalid request body"))
+		return
+	}
+	if body.RaidID == "" || body.ToStreamID == "" {
+		apperrors.WriteHTTP(w, req, apperrors.InvalidRequest("raid_id and to_stream_id are required"))
+		return
+	}
+	if body.FromChatroomID == "" {
+		body.FromChatroomID = streamID
+	}
+	if body.ToChatroomID == "" {
+		body.ToChatroomID = body.ToStreamID
+	}
+	if body.CreatedAt.IsZero() {
+		body.CreatedAt = time.Now()
+	}
+
+	if err := h.relay(streamID, body); err != nil {
+		apperrors.WriteHTTP(w, req, apperrors.Internal(err, "failed to encode raid"))
+		return
+	}
+	w.WriteHeader(http.StatusAccepted)
+}
+
+// HandleRaidEvent relays a raid event taken off the event bus. Version 1 events carry no
+// titles, their raids are announced without them.
+func (h *StreamRaidHandler) HandleRaidEvent(ctx context.Context, envelope *events.Envelope) error {
+	var event struct {
+		RaidID       string `json:"raid_id"`
+		FromStreamID string `json:"from_stream_id"`
+		FromUserID   int64  `json:"from_user_id"`
+		FromTitle    string `json:"from_title"`
+		ToStreamID   string `json:"to_stream_id"`
+		ToUserID     int64  `json:"to_user_id"`
+		ToTitle      string `json:"to_title"`
+		ViewerCount  int    `json:"viewer_count"`
+	}
+	if err := json.Unmarshal(envelope.Data, &event); err != nil {
+		return fmt.Errorf("failed to decode %s event: %w", envelope.EventType, err)
+	}
+
+	// A stream's chat room shares its ID
+	return h.relay(event.FromStreamID, streamRaidRequest{
+		RaidID:         event.RaidID,
+		FromChatroomID: event.FromStreamID,
+		FromUserID:     strconv.FormatInt(event.FromUserID, 10),
+		FromTitle:      event.FromTitle,
+		ToStreamID:     event.ToStreamID,
+		ToChatroomID:   event.ToStreamID,
+		ToUserID:       strconv.FormatInt(event.ToUserID, 10),
+		ToTitle:        event.ToTitle,
+		ViewerCount:    event.ViewerCount,
+		CreatedAt:      time.Unix(envelope.Timestamp, 0),
+	})
+}
+
+// relay sends players in the raiding room where to go and announces the raid in both rooms
+func (h *StreamRaidHandler) relay(streamID string, body streamRaidRequest) error {
+	// Players switch to the target stream when they see this
+	redirect, err := json.Marshal(map[string]interface{}{
+		"type": "raid",
+		"data": map[string]interface{}{
+			"raid_id":        body.RaidID,
+			"from_stream_id": streamID,
+			"to_stream_id":   body.ToStreamID,
+			"to_user_id":     body.ToUserID,
+			"to_title":       body.ToTitle,
+			"viewer_count":   body.ViewerCount,
+			"created_at":     body.CreatedAt.Unix(),
+		},
+	})
+	if err != nil {
+		return err
+	}
+	h.hub.BroadcastToRoom(body.FromChatroomID, redirect)
+
+	announcements := map[string]string{
+		body.FromChatroomID: fmt.Sprintf("Raiding %s with %d viewers!", orDefault(body.ToTitle, "another stream"), body.ViewerCount),
+		body.ToChatroomID:   fmt.Sprintf("%s is raiding with %d viewers!", orDefault(body.FromTitle, "A stream"), body.ViewerCount),
+	}
+	for chatroomID, content := range announcements {
+		system, err := json.Marshal(map[string]interface{}{
+			"type":        "system",
+			"chatroom_id": chatroomID,
+			"content":     content,
+			"sent_at":     body.CreatedAt.Unix(),
+		})
+		if err != nil {
+			return err
+		}
+		h.hub.BroadcastToRoom(chatroomID, system)
+	}
+
+	log.Printf("Raid %s from room %s to room %s announced (%d viewers)", body.RaidID, body.FromChatroomID, body.ToChatroomID, body.ViewerCount)
+	return nil
+}
+
+func orDefault(value, fallback string) string {
+	if value == "" {
+		return fallback
+	}
+	return value
+}
@@ -0,0 +1,29 @@
+package service
+
+import (
+	"context"
+	"log"
+
+	userpb "github.com/Saoudyahya/Live-Streaming-Platform-Architecture/services/chat-service/pkg/proto/user"
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/shared/go/pkg/apperrors"
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/shared/go/pkg/identity"
+)
+
+// resolveUser returns the user a request names. When it is the user the request was sent for,
+// the identity the caller signed already says who they are and the user service isn't asked
+// again. Unsigned identities are never trusted, anyone could send the headers.
+func resolveUser(ctx context.Context, client userpb.UserServiceClient, identities *identity.Propagator, userID string) (*userpb.User, error) {
+	if id := identity.FromContext(ctx); identities.Signed() && id.UserID == userID && id.Username != "" {
+		return &userpb.User{Id: id.UserID, Username: id.Username}, nil
+	}
+
+	resp, err := client.GetUser(ctx, &userpb.GetUserRequest{UserId: userID})
+	if err != nil {
+		log.Printf("Failed to look up user %s: %v", userID, err)
+		return nil, apperrors.Unavailable(err, "Could not look up user")
+	}
+	if !resp.GetStatus().GetSuccess() || resp.GetUser() == nil {
+		return nil, apperrors.NotFound("User not found")
+	}
+	return resp.GetUser(), nil
+}
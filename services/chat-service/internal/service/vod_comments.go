@@ -0,0 +1,229 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/services/chat-service/internal/models"
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/services/chat-service/internal/repository"
+	chatpb "github.com/Saoudyahya/Live-Streaming-Platform-Architecture/services/chat-service/pkg/proto/chat"
+	commonpb "github.com/Saoudyahya/Live-Streaming-Platform-Architecture/services/chat-service/pkg/proto/common"
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/shared/go/pkg/apperrors"
+)
+
+const (
+	maxVODCommentLength = 500 // runes
+
+	defaultVODCommentPage = 50
+	maxVODCommentPage     = 200
+)
+
+// PostVODComment comments on a VOD at a position of the recording. Comments go through the
+// automod dictionaries of the recorded stream's room, then are scored like chat messages.
+func (s *ChatService) PostVODComment(ctx context.Context, req *chatpb.PostVODCommentRequest) (*chatpb.PostVODCommentResponse, error) {
+	if req.VodId == "" || req.PositionMs < 0 {
+		return &chatpb.PostVODCommentResponse{
+			Status: errorStatus(apperrors.InvalidRequest("vod_id is required and position_ms can't be negative")),
+		}, nil
+	}
+	if message, ok := validateVODComment(req.Content); !ok {
+		return &chatpb.PostVODCommentResponse{
+			Status: errorStatus(apperrors.InvalidRequest(message)),
+		}, nil
+	}
+
+	user, err := resolveUser(ctx, s.userClient, s.identities, req.UserId)
+	if err != nil {
+		return &chatpb.PostVODCommentResponse{
+			Status: errorStatus(err),
+		}, nil
+	}
+
+	content, blocked := s.automod.Moderate(ctx, req.ChatroomId, req.Content)
+	if blocked {
+		return &chatpb.PostVODCommentResponse{
+			Status: errorStatus(apperrors.InvalidRequest("Comment contains a banned term")),
+		}, nil
+	}
+
+	now := time.Now()
+	comment := &models.VODComment{
+		VODID:      req.VodId,
+		ID:         uuid.New().String(),
+		ChatroomID: req.ChatroomId,
+		UserID:     req.UserId,
+		Username:   user.Username,
+		PositionMs: req.PositionMs,
+		Content:    content,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}
+	comment.PositionKey = models.VODCommentPositionKey(comment.PositionMs, comment.ID)
+
+	if err := s.dynamoRepo.CreateVODComment(ctx, comment); err != nil {
+		log.Printf("Failed to create VOD comment: %v", err)
+		return &chatpb.PostVODCommentResponse{
+			Status: errorStatus(apperrors.Internal(err, "Failed to post comment")),
+		}, nil
+	}
+	if s.moderation != nil {
+		s.moderation.SubmitComment(comment)
+	}
+
+	return &chatpb.PostVODCommentResponse{
+		Status:  okStatus("Comment posted successfully"),
+		Comment: vodCommentToProto(comment),
+	}, nil
+}
+
+// EditVODComment replaces the content of a comment, the new content is moderated again
+func (s *ChatService) EditVODComment(ctx context.Context, req *chatpb.EditVODCommentRequest) (*chatpb.EditVODCommentResponse, error) {
+	if message, ok := validateVODComment(req.Content); !ok {
+		return &chatpb.EditVODCommentResponse{
+			Status: errorStatus(apperrors.InvalidRequest(message)),
+		}, nil
+	}
+
+	comment, err := s.authorVODComment(ctx, req.CommentId, req.UserId)
+	if err != nil {
+		return &chatpb.EditVODCommentResponse{Status: errorStatus(err)}, nil
+	}
+
+	content, blocked := s.automod.Moderate(ctx, comment.ChatroomID, req.Content)
+	if blocked {
+		return &chatpb.EditVODCommentResponse{
+			Status: errorStatus(apperrors.InvalidRequest("Comment contains a banned term")),
+		}, nil
+	}
+
+	comment.Content = content
+	comment.UpdatedAt = time.Now()
+	comment.IsEdited = true
+	if err := s.dynamoRepo.UpdateVODComment(ctx, comment); err != nil {
+		if errors.Is(err, repository.ErrVODCommentNotFound) {
+			return &chatpb.EditVODCommentResponse{
+				Status: errorStatus(apperrors.NotFound("Comment not found")),
+			}, nil
+		}
+		log.Printf("Failed to update VOD comment %s: %v", comment.ID, err)
+		return &chatpb.EditVODCommentResponse{
+			Status: errorStatus(apperrors.Internal(err, "Failed to edit comment")),
+		}, nil
+	}
+	if s.moderation != nil {
+		s.moderation.SubmitComment(comment)
+	}
+
+	return &chatpb.EditVODCommentResponse{
+		Status:  okStatus("Comment edited successfully"),
+		Comment: vodCommentToProto(comment),
+	}, nil
+}
+
+func (s *ChatService) DeleteVODComment(ctx context.Context, req *chatpb.DeleteVODCommentRequest) (*chatpb.DeleteVODCommentResponse, error) {
+	comment, err := s.authorVODComment(ctx, req.CommentId, req.UserId)
+	if err != nil {
+		return &chatpb.DeleteVODCommentResponse{Status: errorStatus(err)}, nil
+	}
+
+	if err := s.dynamoRepo.DeleteVODComment(ctx, comment); err != nil {
+		log.Printf("Failed to delete VOD comment %s: %v", comment.ID, err)
+		return &chatpb.DeleteVODCommentResponse{
+			Status: errorStatus(apperrors.Internal(err, "Failed to delete comment")),
+		}, nil
+	}
+
+	return &chatpb.DeleteVODCommentResponse{
+		Status: okStatus("Comment deleted successfully"),
+	}, nil
+}
+
+// GetVODComments pages through the comments of a VOD in timeline order
+func (s *ChatService) GetVODComments(ctx context.Context, req *chatpb.GetVODCommentsRequest) (*chatpb.GetVODCommentsResponse, error) {
+	if req.VodId == "" || req.FromPositionMs < 0 || (req.ToPositionMs > 0 && req.ToPositionMs < req.FromPositionMs) {
+		return &chatpb.GetVODCommentsResponse{
+			Status: errorStatus(apperrors.InvalidRequest("vod_id is required and positions must form a valid range")),
+		}, nil
+	}
+
+	limit := int(req.Limit)
+	if limit <= 0 {
+		limit = defaultVODCommentPage
+	} else if limit > maxVODCommentPage {
+		limit = maxVODCommentPage
+	}
+
+	comments, nextCursor, err := s.dynamoRepo.GetVODComments(ctx, req.VodId, req.FromPositionMs, req.ToPositionMs, limit, req.Cursor)
+	if err != nil {
+		log.Printf("Failed to get VOD comments: %v", err)
+		return &chatpb.GetVODCommentsResponse{
+			Status: errorStatus(apperrors.Internal(err, "Failed to get comments")),
+		}, nil
+	}
+
+	protoComments := make([]*chatpb.VODComment, len(comments))
+	for i, comment := range comments {
+		protoComments[i] = vodCommentToProto(comment)
+	}
+
+	return &chatpb.GetVODCommentsResponse{
+		Status:     okStatus("Comments retrieved successfully"),
+		Comments:   protoComments,
+		NextCursor: nextCursor,
+	}, nil
+}
+
+// authorVODComment loads a comment for its author, or returns the error to answer with
+func (s *ChatService) authorVODComment(ctx context.Context, commentID, userID string) (*models.VODComment, error) {
+	comment, err := s.dynamoRepo.GetVODComment(ctx, commentID)
+	if err != nil {
+		if errors.Is(err, repository.ErrVODCommentNotFound) {
+			return nil, apperrors.NotFound("Comment not found")
+		}
+		log.Printf("Failed to get VOD comment %s: %v", commentID, err)
+		return nil, apperrors.Internal(err, "Failed to get comment")
+	}
+
+	if comment.UserID != userID {
+		return nil, apperrors.Forbidden("Only the author can change a comment")
+	}
+	return comment, nil
+}
+
+// validateVODComment returns why content can't be a comment
+func validateVODComment(content string) (string, bool) {
+	if strings.TrimSpace(content) == "" {
+		return "content is required", false
+	}
+	if utf8.RuneCountInString(content) > maxVODCommentLength {
+		return fmt.Sprintf("content must be at most %d characters", maxVODCommentLength), false
+	}
+	return "", true
+}
+
+func vodCommentToProto(comment *models.VODComment) *chatpb.VODComment {
+	return &chatpb.VODComment{
+		Id:         comment.ID,
+		VodId:      comment.VODID,
+		UserId:     comment.UserID,
+		Username:   comment.Username,
+		PositionMs: comment.PositionMs,
+		Content:    comment.Content,
+		CreatedAt: &commonpb.Timestamp{
+			Seconds: comment.CreatedAt.Unix(),
+			Nanos:   int32(comment.CreatedAt.Nanosecond()),
+		},
+		UpdatedAt: &commonpb.Timestamp{
+			Seconds: comment.UpdatedAt.Unix(),
+			Nanos:   int32(comment.UpdatedAt.Nanosecond()),
+		},
+		IsEdited: comment.IsEdited,
+	}
+}
@@ -1,19 +1,34 @@
 package service
 
 import (
+	"context"
+	"encoding/json"
 	"log"
+	"net"
 	"net/http"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/gorilla/websocket"
 
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/services/chat-service/internal/config"
 	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/services/chat-service/internal/server"
 	userpb "github.com/Saoudyahya/Live-Streaming-Platform-Architecture/services/chat-service/pkg/proto/user"
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/shared/go/pkg/apperrors"
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/shared/go/pkg/identity"
 )
 
+// CloseConnectionLimit is the close code of connections refused because the user or IP
+// already has too many open. The close reason is a JSON object naming the limit.
+const CloseConnectionLimit = 4029
+
 type WebSocketHandler struct {
 	chatService *ChatService
 	hub         *server.Hub
 	userClient  userpb.UserServiceClient
+	identities  *identity.Propagator
+	config      config.WebSocketConfig
 }
 
 type WebSocketMessage struct {
@@ -26,50 +41,89 @@ type WebSocketMessage struct {
 var upgrader = websocket.Upgrader{
 	ReadBufferSize:  1024,
 	WriteBufferSize: 1024,
+	// Idle connections hold no write buffer, most of a large room is idle at any moment
+	WriteBufferPool: &sync.Pool{},
 	CheckOrigin: func(r *http.Request) bool {
 		return true // Allow all origins in development
 	},
 }
 
-func NewWebSocketHandler(chatService *ChatService, hub *server.Hub, userClient userpb.UserServiceClient) *WebSocketHandler {
+func NewWebSocketHandler(cfg config.WebSocketConfig, chatService *ChatService, hub *server.Hub, userClient userpb.UserServiceClient, identities *identity.Propagator) *WebSocketHandler {
 	return &WebSocketHandler{
 		chatService: chatService,
 		hub:         hub,
 		userClient:  userClient,
+		identities:  identities,
+		config:      cfg,
 	}
 }
 
+// HandleWebSocket handles /ws. Users the gateway signed in connect as themselves, anonymous
+// clients name a user with ?user_id= and are only held to the per-IP limit, since anyone
+// could use up the per-user limit of a user_id they don't own.
 func (h *WebSocketHandler) HandleWebSocket(w http.ResponseWriter, r *http.Request) {
-	// Extract user info from query parameters or headers
-	// In production, validate JWT token
-	userID := r.URL.Query().Get("user_id")
+	userID, verified := h.verifiedUser(r)
+	requested := r.URL.Query().Get("user_id")
+	switch {
+	case !verified:
+		userID = requested
+	case requested != "" && requested != userID:
+		apperrors.WriteHTTP(w, r, apperrors.Forbidden("user_id is not the signed in user"))
+		return
+	}
 	if userID == "" {
-		http.Error(w, "user_id is required", http.StatusBadRequest)
+		apperrors.WriteHTTP(w, r, apperrors.InvalidRequest("user_id is required"))
+		return
+	}
+
+	// Check the IP before looking the user up, so a flood of connections costs nothing upstream
+	clientIP := h.clientIP(r)
+	if !h.acquireSlot(r.Context(), "ip", clientIP, h.config.MaxConnectionsPerIP) {
+		h.refuse(w, r, "ip", h.config.MaxConnectionsPerIP)
 		return
 	}
+	releaseIP := func() { h.releaseSlot("ip", clientIP, h.config.MaxConnectionsPerIP) }
 
 	// Validate user exists
-	userResp, err := h.userClient.GetUser(r.Context(), &userpb.GetUserRequest{
-		UserId: userID,
-	})
-	if err != nil || !userResp.Status.Success {
-		http.Error(w, "Invalid user", http.StatusUnauthorized)
+	user, err := resolveUser(r.Context(), h.userClient, h.identities, userID)
+	if err != nil {
+		releaseIP()
+		if apperrors.CodeOf(err) == apperrors.CodeNotFound {
+			err = apperrors.Unauthorized("Invalid user")
+		}
+		apperrors.WriteHTTP(w, r, err)
 		return
 	}
 
+	release := releaseIP
+	if verified {
+		if !h.acquireSlot(r.Context(), "user", userID, h.config.MaxConnectionsPerUser) {
+			releaseIP()
+			h.refuse(w, r, "user", h.config.MaxConnectionsPerUser)
+			return
+		}
+		release = func() {
+			releaseIP()
+			h.releaseSlot("user", userID, h.config.MaxConnectionsPerUser)
+		}
+	}
+
 	conn, err := upgrader.Upgrade(w, r, nil)
 	if err != nil {
+		release()
 		log.Printf("WebSocket upgrade error: %v", err)
 		return
 	}
 
 	client := &server.Client{
 		Conn:     conn,
-		Send:     make(chan []byte, 256),
+		Send:     make(chan *websocket.PreparedMessage, 256),
 		Hub:      h.hub,
 		UserID:   userID,
-		Username: userResp.User.Username,
+		Username: user.Username,
 		Rooms:    make(map[string]bool),
+		OnClose:  release,
+		Verified: verified,
 	}
 
 	// Register client using the hub's method
@@ -79,3 +133,77 @@ func (h *WebSocketHandler) HandleWebSocket(w http.ResponseWriter, r *http.Reques
 	go client.WritePump()
 	go client.ReadPump()
 }
+
+// verifiedUser returns the user a request was signed for by another service. Unsigned users
+// are never trusted, anyone could send the headers.
+func (h *WebSocketHandler) verifiedUser(r *http.Request) (string, bool) {
+	id := identity.FromContext(r.Context())
+	if !h.identities.Signed() || !id.Authenticated() {
+		return "", false
+	}
+	return id.UserID, true
+}
+
+// acquireSlot counts a connection against a limit. Connections are let through when Redis
+// can't be reached, so an outage doesn't take chat down with it.
+func (h *WebSocketHandler) acquireSlot(ctx context.Context, scope, id string, limit int) bool {
+	if limit <= 0 {
+		return true
+	}
+
+	acquired, err := h.chatService.redisRepo.AcquireConnectionSlot(ctx, scope, id, limit, h.config.ConnectionCountTTL)
+	if err != nil {
+		log.Printf("Failed to check %s connection limit for %s: %v", scope, id, err)
+		return true
+	}
+	return acquired
+}
+
+func (h *WebSocketHandler) releaseSlot(scope, id string, limit int) {
+	if limit <= 0 {
+		return
+	}
+
+	// The request context is gone by the time a connection closes
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	if err := h.chatService.redisRepo.ReleaseConnectionSlot(ctx, scope, id); err != nil {
+		log.Printf("Failed to release %s connection slot for %s: %v", scope, id, err)
+	}
+}
+
+// refuse upgrades the connection only to close it with CloseConnectionLimit, since browsers
+// don't expose the HTTP status of a failed handshake
+func (h *WebSocketHandler) refuse(w http.ResponseWriter, r *http.Request, scope string, limit int) {
+	log.Printf("Refused WebSocket connection: %s connection limit of %d reached", scope, limit)
+
+	conn, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+
+	reason, _ := json.Marshal(map[string]interface{}{
+		"error": "connection_limit",
+		"scope": scope,
+		"limit": limit,
+	})
+	conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(CloseConnectionLimit, string(reason)), time.Now().Add(time.Second))
+}
+
+// clientIP returns the address a connection comes from, or the first X-Forwarded-For entry
+// when the service runs behind a trusted proxy
+func (h *WebSocketHandler) clientIP(r *http.Request) string {
+	if h.config.TrustProxyHeaders {
+		if forwarded := r.Header.Get("X-Forwarded-For"); forwarded != "" {
+			return strings.TrimSpace(strings.Split(forwarded, ",")[0])
+		}
+	}
+
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
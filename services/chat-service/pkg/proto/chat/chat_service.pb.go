@@ -730,6 +730,1439 @@ func (x *GetChatroomsResponse) GetChatrooms() []*Chatroom {
 	return nil
 }
 
+type PinMessageRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ChatroomId    string                 `protobuf:"bytes,1,opt,name=chatroom_id,json=chatroomId,proto3" json:"chatroom_id,omitempty"`
+	UserId        string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	MessageId     string                 `protobuf:"bytes,3,opt,name=message_id,json=messageId,proto3" json:"message_id,omitempty"`
+	Pinned        bool                   `protobuf:"varint,4,opt,name=pinned,proto3" json:"pinned,omitempty"` // false unpins
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PinMessageRequest) Reset() {
+	*x = PinMessageRequest{}
+	mi := &file_chat_chat_service_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PinMessageRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PinMessageRequest) ProtoMessage() {}
+
+func (x *PinMessageRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_chat_chat_service_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PinMessageRequest.ProtoReflect.Descriptor instead.
+func (*PinMessageRequest) Descriptor() ([]byte, []int) {
+	return file_chat_chat_service_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *PinMessageRequest) GetChatroomId() string {
+	if x != nil {
+		return x.ChatroomId
+	}
+	return ""
+}
+
+func (x *PinMessageRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *PinMessageRequest) GetMessageId() string {
+	if x != nil {
+		return x.MessageId
+	}
+	return ""
+}
+
+func (x *PinMessageRequest) GetPinned() bool {
+	if x != nil {
+		return x.Pinned
+	}
+	return false
+}
+
+type PinMessageResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Status        *common.Status         `protobuf:"bytes,1,opt,name=status,proto3" json:"status,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PinMessageResponse) Reset() {
+	*x = PinMessageResponse{}
+	mi := &file_chat_chat_service_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PinMessageResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PinMessageResponse) ProtoMessage() {}
+
+func (x *PinMessageResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_chat_chat_service_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PinMessageResponse.ProtoReflect.Descriptor instead.
+func (*PinMessageResponse) Descriptor() ([]byte, []int) {
+	return file_chat_chat_service_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *PinMessageResponse) GetStatus() *common.Status {
+	if x != nil {
+		return x.Status
+	}
+	return nil
+}
+
+type GetRoomSnapshotRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ChatroomId    string                 `protobuf:"bytes,1,opt,name=chatroom_id,json=chatroomId,proto3" json:"chatroom_id,omitempty"`
+	UserId        string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	RecentLimit   int32                  `protobuf:"varint,3,opt,name=recent_limit,json=recentLimit,proto3" json:"recent_limit,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetRoomSnapshotRequest) Reset() {
+	*x = GetRoomSnapshotRequest{}
+	mi := &file_chat_chat_service_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetRoomSnapshotRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetRoomSnapshotRequest) ProtoMessage() {}
+
+func (x *GetRoomSnapshotRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_chat_chat_service_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetRoomSnapshotRequest.ProtoReflect.Descriptor instead.
+func (*GetRoomSnapshotRequest) Descriptor() ([]byte, []int) {
+	return file_chat_chat_service_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *GetRoomSnapshotRequest) GetChatroomId() string {
+	if x != nil {
+		return x.ChatroomId
+	}
+	return ""
+}
+
+func (x *GetRoomSnapshotRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *GetRoomSnapshotRequest) GetRecentLimit() int32 {
+	if x != nil {
+		return x.RecentLimit
+	}
+	return 0
+}
+
+type GetRoomSnapshotResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Status        *common.Status         `protobuf:"bytes,1,opt,name=status,proto3" json:"status,omitempty"`
+	Snapshot      *RoomSnapshot          `protobuf:"bytes,2,opt,name=snapshot,proto3" json:"snapshot,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetRoomSnapshotResponse) Reset() {
+	*x = GetRoomSnapshotResponse{}
+	mi := &file_chat_chat_service_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetRoomSnapshotResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetRoomSnapshotResponse) ProtoMessage() {}
+
+func (x *GetRoomSnapshotResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_chat_chat_service_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetRoomSnapshotResponse.ProtoReflect.Descriptor instead.
+func (*GetRoomSnapshotResponse) Descriptor() ([]byte, []int) {
+	return file_chat_chat_service_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *GetRoomSnapshotResponse) GetStatus() *common.Status {
+	if x != nil {
+		return x.Status
+	}
+	return nil
+}
+
+func (x *GetRoomSnapshotResponse) GetSnapshot() *RoomSnapshot {
+	if x != nil {
+		return x.Snapshot
+	}
+	return nil
+}
+
+// RoomSnapshot is everything a room page needs in one read. The chatroom's member_ids are
+// left out, member_count replaces them.
+type RoomSnapshot struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Chatroom       *Chatroom              `protobuf:"bytes,1,opt,name=chatroom,proto3" json:"chatroom,omitempty"`
+	MemberCount    int64                  `protobuf:"varint,2,opt,name=member_count,json=memberCount,proto3" json:"member_count,omitempty"`
+	PinnedMessages []*Message             `protobuf:"bytes,3,rep,name=pinned_messages,json=pinnedMessages,proto3" json:"pinned_messages,omitempty"`
+	TopEmotes      []*EmoteCount          `protobuf:"bytes,4,rep,name=top_emotes,json=topEmotes,proto3" json:"top_emotes,omitempty"`
+	RecentMessages []*Message             `protobuf:"bytes,5,rep,name=recent_messages,json=recentMessages,proto3" json:"recent_messages,omitempty"`
+	UpdatedAt      *common.Timestamp      `protobuf:"bytes,6,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *RoomSnapshot) Reset() {
+	*x = RoomSnapshot{}
+	mi := &file_chat_chat_service_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RoomSnapshot) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RoomSnapshot) ProtoMessage() {}
+
+func (x *RoomSnapshot) ProtoReflect() protoreflect.Message {
+	mi := &file_chat_chat_service_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RoomSnapshot.ProtoReflect.Descriptor instead.
+func (*RoomSnapshot) Descriptor() ([]byte, []int) {
+	return file_chat_chat_service_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *RoomSnapshot) GetChatroom() *Chatroom {
+	if x != nil {
+		return x.Chatroom
+	}
+	return nil
+}
+
+func (x *RoomSnapshot) GetMemberCount() int64 {
+	if x != nil {
+		return x.MemberCount
+	}
+	return 0
+}
+
+func (x *RoomSnapshot) GetPinnedMessages() []*Message {
+	if x != nil {
+		return x.PinnedMessages
+	}
+	return nil
+}
+
+func (x *RoomSnapshot) GetTopEmotes() []*EmoteCount {
+	if x != nil {
+		return x.TopEmotes
+	}
+	return nil
+}
+
+func (x *RoomSnapshot) GetRecentMessages() []*Message {
+	if x != nil {
+		return x.RecentMessages
+	}
+	return nil
+}
+
+func (x *RoomSnapshot) GetUpdatedAt() *common.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+type EmoteCount struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Count         int64                  `protobuf:"varint,2,opt,name=count,proto3" json:"count,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *EmoteCount) Reset() {
+	*x = EmoteCount{}
+	mi := &file_chat_chat_service_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EmoteCount) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EmoteCount) ProtoMessage() {}
+
+func (x *EmoteCount) ProtoReflect() protoreflect.Message {
+	mi := &file_chat_chat_service_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EmoteCount.ProtoReflect.Descriptor instead.
+func (*EmoteCount) Descriptor() ([]byte, []int) {
+	return file_chat_chat_service_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *EmoteCount) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *EmoteCount) GetCount() int64 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+// GetTopChattersRequest reads the hourly rollups of the period ending now. period_hours
+// defaults to 24 and is at most 720, limit defaults to 10 and is at most 100.
+type GetTopChattersRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ChatroomId    string                 `protobuf:"bytes,1,opt,name=chatroom_id,json=chatroomId,proto3" json:"chatroom_id,omitempty"`
+	PeriodHours   int32                  `protobuf:"varint,2,opt,name=period_hours,json=periodHours,proto3" json:"period_hours,omitempty"`
+	Limit         int32                  `protobuf:"varint,3,opt,name=limit,proto3" json:"limit,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetTopChattersRequest) Reset() {
+	*x = GetTopChattersRequest{}
+	mi := &file_chat_chat_service_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetTopChattersRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetTopChattersRequest) ProtoMessage() {}
+
+func (x *GetTopChattersRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_chat_chat_service_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetTopChattersRequest.ProtoReflect.Descriptor instead.
+func (*GetTopChattersRequest) Descriptor() ([]byte, []int) {
+	return file_chat_chat_service_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *GetTopChattersRequest) GetChatroomId() string {
+	if x != nil {
+		return x.ChatroomId
+	}
+	return ""
+}
+
+func (x *GetTopChattersRequest) GetPeriodHours() int32 {
+	if x != nil {
+		return x.PeriodHours
+	}
+	return 0
+}
+
+func (x *GetTopChattersRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+type GetTopChattersResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Status        *common.Status         `protobuf:"bytes,1,opt,name=status,proto3" json:"status,omitempty"`
+	Chatters      []*TopChatter          `protobuf:"bytes,2,rep,name=chatters,proto3" json:"chatters,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetTopChattersResponse) Reset() {
+	*x = GetTopChattersResponse{}
+	mi := &file_chat_chat_service_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetTopChattersResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetTopChattersResponse) ProtoMessage() {}
+
+func (x *GetTopChattersResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_chat_chat_service_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetTopChattersResponse.ProtoReflect.Descriptor instead.
+func (*GetTopChattersResponse) Descriptor() ([]byte, []int) {
+	return file_chat_chat_service_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *GetTopChattersResponse) GetStatus() *common.Status {
+	if x != nil {
+		return x.Status
+	}
+	return nil
+}
+
+func (x *GetTopChattersResponse) GetChatters() []*TopChatter {
+	if x != nil {
+		return x.Chatters
+	}
+	return nil
+}
+
+type TopChatter struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Username      string                 `protobuf:"bytes,2,opt,name=username,proto3" json:"username,omitempty"`
+	Messages      int64                  `protobuf:"varint,3,opt,name=messages,proto3" json:"messages,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TopChatter) Reset() {
+	*x = TopChatter{}
+	mi := &file_chat_chat_service_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TopChatter) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TopChatter) ProtoMessage() {}
+
+func (x *TopChatter) ProtoReflect() protoreflect.Message {
+	mi := &file_chat_chat_service_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TopChatter.ProtoReflect.Descriptor instead.
+func (*TopChatter) Descriptor() ([]byte, []int) {
+	return file_chat_chat_service_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *TopChatter) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *TopChatter) GetUsername() string {
+	if x != nil {
+		return x.Username
+	}
+	return ""
+}
+
+func (x *TopChatter) GetMessages() int64 {
+	if x != nil {
+		return x.Messages
+	}
+	return 0
+}
+
+// GetTopEmotesRequest reads the hourly rollups of the period ending now, uses from the last
+// flush interval aren't counted yet. period_hours defaults to 24 and is at most 720, limit
+// defaults to 10 and is at most 100.
+type GetTopEmotesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ChatroomId    string                 `protobuf:"bytes,1,opt,name=chatroom_id,json=chatroomId,proto3" json:"chatroom_id,omitempty"`
+	PeriodHours   int32                  `protobuf:"varint,2,opt,name=period_hours,json=periodHours,proto3" json:"period_hours,omitempty"`
+	Limit         int32                  `protobuf:"varint,3,opt,name=limit,proto3" json:"limit,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetTopEmotesRequest) Reset() {
+	*x = GetTopEmotesRequest{}
+	mi := &file_chat_chat_service_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetTopEmotesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetTopEmotesRequest) ProtoMessage() {}
+
+func (x *GetTopEmotesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_chat_chat_service_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetTopEmotesRequest.ProtoReflect.Descriptor instead.
+func (*GetTopEmotesRequest) Descriptor() ([]byte, []int) {
+	return file_chat_chat_service_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *GetTopEmotesRequest) GetChatroomId() string {
+	if x != nil {
+		return x.ChatroomId
+	}
+	return ""
+}
+
+func (x *GetTopEmotesRequest) GetPeriodHours() int32 {
+	if x != nil {
+		return x.PeriodHours
+	}
+	return 0
+}
+
+func (x *GetTopEmotesRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+type GetTopEmotesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Status        *common.Status         `protobuf:"bytes,1,opt,name=status,proto3" json:"status,omitempty"`
+	Emotes        []*EmoteCount          `protobuf:"bytes,2,rep,name=emotes,proto3" json:"emotes,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetTopEmotesResponse) Reset() {
+	*x = GetTopEmotesResponse{}
+	mi := &file_chat_chat_service_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetTopEmotesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetTopEmotesResponse) ProtoMessage() {}
+
+func (x *GetTopEmotesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_chat_chat_service_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetTopEmotesResponse.ProtoReflect.Descriptor instead.
+func (*GetTopEmotesResponse) Descriptor() ([]byte, []int) {
+	return file_chat_chat_service_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *GetTopEmotesResponse) GetStatus() *common.Status {
+	if x != nil {
+		return x.Status
+	}
+	return nil
+}
+
+func (x *GetTopEmotesResponse) GetEmotes() []*EmoteCount {
+	if x != nil {
+		return x.Emotes
+	}
+	return nil
+}
+
+// GetChatActivityRequest reads the hourly rollups of the period ending now. period_hours
+// defaults to 24 and is at most 720.
+type GetChatActivityRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ChatroomId    string                 `protobuf:"bytes,1,opt,name=chatroom_id,json=chatroomId,proto3" json:"chatroom_id,omitempty"`
+	PeriodHours   int32                  `protobuf:"varint,2,opt,name=period_hours,json=periodHours,proto3" json:"period_hours,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetChatActivityRequest) Reset() {
+	*x = GetChatActivityRequest{}
+	mi := &file_chat_chat_service_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetChatActivityRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetChatActivityRequest) ProtoMessage() {}
+
+func (x *GetChatActivityRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_chat_chat_service_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetChatActivityRequest.ProtoReflect.Descriptor instead.
+func (*GetChatActivityRequest) Descriptor() ([]byte, []int) {
+	return file_chat_chat_service_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *GetChatActivityRequest) GetChatroomId() string {
+	if x != nil {
+		return x.ChatroomId
+	}
+	return ""
+}
+
+func (x *GetChatActivityRequest) GetPeriodHours() int32 {
+	if x != nil {
+		return x.PeriodHours
+	}
+	return 0
+}
+
+// GetChatActivityResponse lists the hours with activity, oldest first. Unique chatters can't
+// be summed across hours, so there is no total for them.
+type GetChatActivityResponse struct {
+	state                  protoimpl.MessageState `protogen:"open.v1"`
+	Status                 *common.Status         `protobuf:"bytes,1,opt,name=status,proto3" json:"status,omitempty"`
+	Buckets                []*ChatActivityBucket  `protobuf:"bytes,2,rep,name=buckets,proto3" json:"buckets,omitempty"`
+	TotalMessages          int64                  `protobuf:"varint,3,opt,name=total_messages,json=totalMessages,proto3" json:"total_messages,omitempty"`
+	TotalModerationActions int64                  `protobuf:"varint,4,opt,name=total_moderation_actions,json=totalModerationActions,proto3" json:"total_moderation_actions,omitempty"`
+	unknownFields          protoimpl.UnknownFields
+	sizeCache              protoimpl.SizeCache
+}
+
+func (x *GetChatActivityResponse) Reset() {
+	*x = GetChatActivityResponse{}
+	mi := &file_chat_chat_service_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetChatActivityResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetChatActivityResponse) ProtoMessage() {}
+
+func (x *GetChatActivityResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_chat_chat_service_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetChatActivityResponse.ProtoReflect.Descriptor instead.
+func (*GetChatActivityResponse) Descriptor() ([]byte, []int) {
+	return file_chat_chat_service_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *GetChatActivityResponse) GetStatus() *common.Status {
+	if x != nil {
+		return x.Status
+	}
+	return nil
+}
+
+func (x *GetChatActivityResponse) GetBuckets() []*ChatActivityBucket {
+	if x != nil {
+		return x.Buckets
+	}
+	return nil
+}
+
+func (x *GetChatActivityResponse) GetTotalMessages() int64 {
+	if x != nil {
+		return x.TotalMessages
+	}
+	return 0
+}
+
+func (x *GetChatActivityResponse) GetTotalModerationActions() int64 {
+	if x != nil {
+		return x.TotalModerationActions
+	}
+	return 0
+}
+
+// ChatActivityBucket is one hour of a room's activity. Moderation actions are messages
+// blocked by automod or taken back by moderation.
+type ChatActivityBucket struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	Hour              *common.Timestamp      `protobuf:"bytes,1,opt,name=hour,proto3" json:"hour,omitempty"`
+	Messages          int64                  `protobuf:"varint,2,opt,name=messages,proto3" json:"messages,omitempty"`
+	UniqueChatters    int64                  `protobuf:"varint,3,opt,name=unique_chatters,json=uniqueChatters,proto3" json:"unique_chatters,omitempty"`
+	ModerationActions int64                  `protobuf:"varint,4,opt,name=moderation_actions,json=moderationActions,proto3" json:"moderation_actions,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *ChatActivityBucket) Reset() {
+	*x = ChatActivityBucket{}
+	mi := &file_chat_chat_service_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ChatActivityBucket) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ChatActivityBucket) ProtoMessage() {}
+
+func (x *ChatActivityBucket) ProtoReflect() protoreflect.Message {
+	mi := &file_chat_chat_service_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ChatActivityBucket.ProtoReflect.Descriptor instead.
+func (*ChatActivityBucket) Descriptor() ([]byte, []int) {
+	return file_chat_chat_service_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *ChatActivityBucket) GetHour() *common.Timestamp {
+	if x != nil {
+		return x.Hour
+	}
+	return nil
+}
+
+func (x *ChatActivityBucket) GetMessages() int64 {
+	if x != nil {
+		return x.Messages
+	}
+	return 0
+}
+
+func (x *ChatActivityBucket) GetUniqueChatters() int64 {
+	if x != nil {
+		return x.UniqueChatters
+	}
+	return 0
+}
+
+func (x *ChatActivityBucket) GetModerationActions() int64 {
+	if x != nil {
+		return x.ModerationActions
+	}
+	return 0
+}
+
+// PostVODCommentRequest comments on a VOD at a position of the recording. chatroom_id is the
+// room of the recorded stream, its automod dictionary applies on top of the global one.
+type PostVODCommentRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	VodId         string                 `protobuf:"bytes,1,opt,name=vod_id,json=vodId,proto3" json:"vod_id,omitempty"`
+	ChatroomId    string                 `protobuf:"bytes,2,opt,name=chatroom_id,json=chatroomId,proto3" json:"chatroom_id,omitempty"`
+	UserId        string                 `protobuf:"bytes,3,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	PositionMs    int64                  `protobuf:"varint,4,opt,name=position_ms,json=positionMs,proto3" json:"position_ms,omitempty"`
+	Content       string                 `protobuf:"bytes,5,opt,name=content,proto3" json:"content,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PostVODCommentRequest) Reset() {
+	*x = PostVODCommentRequest{}
+	mi := &file_chat_chat_service_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PostVODCommentRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PostVODCommentRequest) ProtoMessage() {}
+
+func (x *PostVODCommentRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_chat_chat_service_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PostVODCommentRequest.ProtoReflect.Descriptor instead.
+func (*PostVODCommentRequest) Descriptor() ([]byte, []int) {
+	return file_chat_chat_service_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *PostVODCommentRequest) GetVodId() string {
+	if x != nil {
+		return x.VodId
+	}
+	return ""
+}
+
+func (x *PostVODCommentRequest) GetChatroomId() string {
+	if x != nil {
+		return x.ChatroomId
+	}
+	return ""
+}
+
+func (x *PostVODCommentRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *PostVODCommentRequest) GetPositionMs() int64 {
+	if x != nil {
+		return x.PositionMs
+	}
+	return 0
+}
+
+func (x *PostVODCommentRequest) GetContent() string {
+	if x != nil {
+		return x.Content
+	}
+	return ""
+}
+
+type PostVODCommentResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Status        *common.Status         `protobuf:"bytes,1,opt,name=status,proto3" json:"status,omitempty"`
+	Comment       *VODComment            `protobuf:"bytes,2,opt,name=comment,proto3" json:"comment,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PostVODCommentResponse) Reset() {
+	*x = PostVODCommentResponse{}
+	mi := &file_chat_chat_service_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PostVODCommentResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PostVODCommentResponse) ProtoMessage() {}
+
+func (x *PostVODCommentResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_chat_chat_service_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PostVODCommentResponse.ProtoReflect.Descriptor instead.
+func (*PostVODCommentResponse) Descriptor() ([]byte, []int) {
+	return file_chat_chat_service_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *PostVODCommentResponse) GetStatus() *common.Status {
+	if x != nil {
+		return x.Status
+	}
+	return nil
+}
+
+func (x *PostVODCommentResponse) GetComment() *VODComment {
+	if x != nil {
+		return x.Comment
+	}
+	return nil
+}
+
+// EditVODCommentRequest replaces the content of a comment, only its author can edit it
+type EditVODCommentRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	CommentId     string                 `protobuf:"bytes,1,opt,name=comment_id,json=commentId,proto3" json:"comment_id,omitempty"`
+	UserId        string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Content       string                 `protobuf:"bytes,3,opt,name=content,proto3" json:"content,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *EditVODCommentRequest) Reset() {
+	*x = EditVODCommentRequest{}
+	mi := &file_chat_chat_service_proto_msgTypes[28]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EditVODCommentRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EditVODCommentRequest) ProtoMessage() {}
+
+func (x *EditVODCommentRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_chat_chat_service_proto_msgTypes[28]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EditVODCommentRequest.ProtoReflect.Descriptor instead.
+func (*EditVODCommentRequest) Descriptor() ([]byte, []int) {
+	return file_chat_chat_service_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *EditVODCommentRequest) GetCommentId() string {
+	if x != nil {
+		return x.CommentId
+	}
+	return ""
+}
+
+func (x *EditVODCommentRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *EditVODCommentRequest) GetContent() string {
+	if x != nil {
+		return x.Content
+	}
+	return ""
+}
+
+type EditVODCommentResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Status        *common.Status         `protobuf:"bytes,1,opt,name=status,proto3" json:"status,omitempty"`
+	Comment       *VODComment            `protobuf:"bytes,2,opt,name=comment,proto3" json:"comment,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *EditVODCommentResponse) Reset() {
+	*x = EditVODCommentResponse{}
+	mi := &file_chat_chat_service_proto_msgTypes[29]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EditVODCommentResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EditVODCommentResponse) ProtoMessage() {}
+
+func (x *EditVODCommentResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_chat_chat_service_proto_msgTypes[29]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EditVODCommentResponse.ProtoReflect.Descriptor instead.
+func (*EditVODCommentResponse) Descriptor() ([]byte, []int) {
+	return file_chat_chat_service_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *EditVODCommentResponse) GetStatus() *common.Status {
+	if x != nil {
+		return x.Status
+	}
+	return nil
+}
+
+func (x *EditVODCommentResponse) GetComment() *VODComment {
+	if x != nil {
+		return x.Comment
+	}
+	return nil
+}
+
+// DeleteVODCommentRequest deletes a comment, only its author can delete it
+type DeleteVODCommentRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	CommentId     string                 `protobuf:"bytes,1,opt,name=comment_id,json=commentId,proto3" json:"comment_id,omitempty"`
+	UserId        string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteVODCommentRequest) Reset() {
+	*x = DeleteVODCommentRequest{}
+	mi := &file_chat_chat_service_proto_msgTypes[30]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteVODCommentRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteVODCommentRequest) ProtoMessage() {}
+
+func (x *DeleteVODCommentRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_chat_chat_service_proto_msgTypes[30]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteVODCommentRequest.ProtoReflect.Descriptor instead.
+func (*DeleteVODCommentRequest) Descriptor() ([]byte, []int) {
+	return file_chat_chat_service_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *DeleteVODCommentRequest) GetCommentId() string {
+	if x != nil {
+		return x.CommentId
+	}
+	return ""
+}
+
+func (x *DeleteVODCommentRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+type DeleteVODCommentResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Status        *common.Status         `protobuf:"bytes,1,opt,name=status,proto3" json:"status,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteVODCommentResponse) Reset() {
+	*x = DeleteVODCommentResponse{}
+	mi := &file_chat_chat_service_proto_msgTypes[31]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteVODCommentResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteVODCommentResponse) ProtoMessage() {}
+
+func (x *DeleteVODCommentResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_chat_chat_service_proto_msgTypes[31]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteVODCommentResponse.ProtoReflect.Descriptor instead.
+func (*DeleteVODCommentResponse) Descriptor() ([]byte, []int) {
+	return file_chat_chat_service_proto_rawDescGZIP(), []int{31}
+}
+
+func (x *DeleteVODCommentResponse) GetStatus() *common.Status {
+	if x != nil {
+		return x.Status
+	}
+	return nil
+}
+
+// GetVODCommentsRequest pages through the comments of a VOD in timeline order, starting at
+// from_position_ms. to_position_ms bounds the page when set, so players can load the comments
+// of the next part of the recording. cursor is the next_cursor of the previous page.
+type GetVODCommentsRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	VodId          string                 `protobuf:"bytes,1,opt,name=vod_id,json=vodId,proto3" json:"vod_id,omitempty"`
+	FromPositionMs int64                  `protobuf:"varint,2,opt,name=from_position_ms,json=fromPositionMs,proto3" json:"from_position_ms,omitempty"`
+	ToPositionMs   int64                  `protobuf:"varint,3,opt,name=to_position_ms,json=toPositionMs,proto3" json:"to_position_ms,omitempty"`
+	Limit          int32                  `protobuf:"varint,4,opt,name=limit,proto3" json:"limit,omitempty"`
+	Cursor         string                 `protobuf:"bytes,5,opt,name=cursor,proto3" json:"cursor,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *GetVODCommentsRequest) Reset() {
+	*x = GetVODCommentsRequest{}
+	mi := &file_chat_chat_service_proto_msgTypes[32]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetVODCommentsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetVODCommentsRequest) ProtoMessage() {}
+
+func (x *GetVODCommentsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_chat_chat_service_proto_msgTypes[32]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetVODCommentsRequest.ProtoReflect.Descriptor instead.
+func (*GetVODCommentsRequest) Descriptor() ([]byte, []int) {
+	return file_chat_chat_service_proto_rawDescGZIP(), []int{32}
+}
+
+func (x *GetVODCommentsRequest) GetVodId() string {
+	if x != nil {
+		return x.VodId
+	}
+	return ""
+}
+
+func (x *GetVODCommentsRequest) GetFromPositionMs() int64 {
+	if x != nil {
+		return x.FromPositionMs
+	}
+	return 0
+}
+
+func (x *GetVODCommentsRequest) GetToPositionMs() int64 {
+	if x != nil {
+		return x.ToPositionMs
+	}
+	return 0
+}
+
+func (x *GetVODCommentsRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *GetVODCommentsRequest) GetCursor() string {
+	if x != nil {
+		return x.Cursor
+	}
+	return ""
+}
+
+// GetVODCommentsResponse has an empty next_cursor on the last page
+type GetVODCommentsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Status        *common.Status         `protobuf:"bytes,1,opt,name=status,proto3" json:"status,omitempty"`
+	Comments      []*VODComment          `protobuf:"bytes,2,rep,name=comments,proto3" json:"comments,omitempty"`
+	NextCursor    string                 `protobuf:"bytes,3,opt,name=next_cursor,json=nextCursor,proto3" json:"next_cursor,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetVODCommentsResponse) Reset() {
+	*x = GetVODCommentsResponse{}
+	mi := &file_chat_chat_service_proto_msgTypes[33]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetVODCommentsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetVODCommentsResponse) ProtoMessage() {}
+
+func (x *GetVODCommentsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_chat_chat_service_proto_msgTypes[33]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetVODCommentsResponse.ProtoReflect.Descriptor instead.
+func (*GetVODCommentsResponse) Descriptor() ([]byte, []int) {
+	return file_chat_chat_service_proto_rawDescGZIP(), []int{33}
+}
+
+func (x *GetVODCommentsResponse) GetStatus() *common.Status {
+	if x != nil {
+		return x.Status
+	}
+	return nil
+}
+
+func (x *GetVODCommentsResponse) GetComments() []*VODComment {
+	if x != nil {
+		return x.Comments
+	}
+	return nil
+}
+
+func (x *GetVODCommentsResponse) GetNextCursor() string {
+	if x != nil {
+		return x.NextCursor
+	}
+	return ""
+}
+
+type VODComment struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	VodId         string                 `protobuf:"bytes,2,opt,name=vod_id,json=vodId,proto3" json:"vod_id,omitempty"`
+	UserId        string                 `protobuf:"bytes,3,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Username      string                 `protobuf:"bytes,4,opt,name=username,proto3" json:"username,omitempty"`
+	PositionMs    int64                  `protobuf:"varint,5,opt,name=position_ms,json=positionMs,proto3" json:"position_ms,omitempty"`
+	Content       string                 `protobuf:"bytes,6,opt,name=content,proto3" json:"content,omitempty"`
+	CreatedAt     *common.Timestamp      `protobuf:"bytes,7,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt     *common.Timestamp      `protobuf:"bytes,8,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	IsEdited      bool                   `protobuf:"varint,9,opt,name=is_edited,json=isEdited,proto3" json:"is_edited,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *VODComment) Reset() {
+	*x = VODComment{}
+	mi := &file_chat_chat_service_proto_msgTypes[34]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *VODComment) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*VODComment) ProtoMessage() {}
+
+func (x *VODComment) ProtoReflect() protoreflect.Message {
+	mi := &file_chat_chat_service_proto_msgTypes[34]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use VODComment.ProtoReflect.Descriptor instead.
+func (*VODComment) Descriptor() ([]byte, []int) {
+	return file_chat_chat_service_proto_rawDescGZIP(), []int{34}
+}
+
+func (x *VODComment) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *VODComment) GetVodId() string {
+	if x != nil {
+		return x.VodId
+	}
+	return ""
+}
+
+func (x *VODComment) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *VODComment) GetUsername() string {
+	if x != nil {
+		return x.Username
+	}
+	return ""
+}
+
+func (x *VODComment) GetPositionMs() int64 {
+	if x != nil {
+		return x.PositionMs
+	}
+	return 0
+}
+
+func (x *VODComment) GetContent() string {
+	if x != nil {
+		return x.Content
+	}
+	return ""
+}
+
+func (x *VODComment) GetCreatedAt() *common.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *VODComment) GetUpdatedAt() *common.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+func (x *VODComment) GetIsEdited() bool {
+	if x != nil {
+		return x.IsEdited
+	}
+	return false
+}
+
 type Chatroom struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
@@ -746,7 +2179,7 @@ type Chatroom struct {
 
 func (x *Chatroom) Reset() {
 	*x = Chatroom{}
-	mi := &file_chat_chat_service_proto_msgTypes[12]
+	mi := &file_chat_chat_service_proto_msgTypes[35]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -758,7 +2191,7 @@ func (x *Chatroom) String() string {
 func (*Chatroom) ProtoMessage() {}
 
 func (x *Chatroom) ProtoReflect() protoreflect.Message {
-	mi := &file_chat_chat_service_proto_msgTypes[12]
+	mi := &file_chat_chat_service_proto_msgTypes[35]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -771,7 +2204,7 @@ func (x *Chatroom) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Chatroom.ProtoReflect.Descriptor instead.
 func (*Chatroom) Descriptor() ([]byte, []int) {
-	return file_chat_chat_service_proto_rawDescGZIP(), []int{12}
+	return file_chat_chat_service_proto_rawDescGZIP(), []int{35}
 }
 
 func (x *Chatroom) GetId() string {
@@ -846,7 +2279,7 @@ type Message struct {
 
 func (x *Message) Reset() {
 	*x = Message{}
-	mi := &file_chat_chat_service_proto_msgTypes[13]
+	mi := &file_chat_chat_service_proto_msgTypes[36]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -858,7 +2291,7 @@ func (x *Message) String() string {
 func (*Message) ProtoMessage() {}
 
 func (x *Message) ProtoReflect() protoreflect.Message {
-	mi := &file_chat_chat_service_proto_msgTypes[13]
+	mi := &file_chat_chat_service_proto_msgTypes[36]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -871,7 +2304,7 @@ func (x *Message) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Message.ProtoReflect.Descriptor instead.
 func (*Message) Descriptor() ([]byte, []int) {
-	return file_chat_chat_service_proto_rawDescGZIP(), []int{13}
+	return file_chat_chat_service_proto_rawDescGZIP(), []int{36}
 }
 
 func (x *Message) GetId() string {
@@ -981,7 +2414,122 @@ const file_chat_chat_service_proto_rawDesc = "" +
 	"\auser_id\x18\x01 \x01(\tR\x06userId\"l\n" +
 	"\x14GetChatroomsResponse\x12&\n" +
 	"\x06status\x18\x01 \x01(\v2\x0e.common.StatusR\x06status\x12,\n" +
-	"\tchatrooms\x18\x02 \x03(\v2\x0e.chat.ChatroomR\tchatrooms\"\x91\x02\n" +
+	"\tchatrooms\x18\x02 \x03(\v2\x0e.chat.ChatroomR\tchatrooms\"\x84\x01\n" +
+	"\x11PinMessageRequest\x12\x1f\n" +
+	"\vchatroom_id\x18\x01 \x01(\tR\n" +
+	"chatroomId\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\tR\x06userId\x12\x1d\n" +
+	"\n" +
+	"message_id\x18\x03 \x01(\tR\tmessageId\x12\x16\n" +
+	"\x06pinned\x18\x04 \x01(\bR\x06pinned\"<\n" +
+	"\x12PinMessageResponse\x12&\n" +
+	"\x06status\x18\x01 \x01(\v2\x0e.common.StatusR\x06status\"u\n" +
+	"\x16GetRoomSnapshotRequest\x12\x1f\n" +
+	"\vchatroom_id\x18\x01 \x01(\tR\n" +
+	"chatroomId\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\tR\x06userId\x12!\n" +
+	"\frecent_limit\x18\x03 \x01(\x05R\vrecentLimit\"q\n" +
+	"\x17GetRoomSnapshotResponse\x12&\n" +
+	"\x06status\x18\x01 \x01(\v2\x0e.common.StatusR\x06status\x12.\n" +
+	"\bsnapshot\x18\x02 \x01(\v2\x12.chat.RoomSnapshotR\bsnapshot\"\xb0\x02\n" +
+	"\fRoomSnapshot\x12*\n" +
+	"\bchatroom\x18\x01 \x01(\v2\x0e.chat.ChatroomR\bchatroom\x12!\n" +
+	"\fmember_count\x18\x02 \x01(\x03R\vmemberCount\x126\n" +
+	"\x0fpinned_messages\x18\x03 \x03(\v2\r.chat.MessageR\x0epinnedMessages\x12/\n" +
+	"\n" +
+	"top_emotes\x18\x04 \x03(\v2\x10.chat.EmoteCountR\ttopEmotes\x126\n" +
+	"\x0frecent_messages\x18\x05 \x03(\v2\r.chat.MessageR\x0erecentMessages\x120\n" +
+	"\n" +
+	"updated_at\x18\x06 \x01(\v2\x11.common.TimestampR\tupdatedAt\"6\n" +
+	"\n" +
+	"EmoteCount\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x14\n" +
+	"\x05count\x18\x02 \x01(\x03R\x05count\"q\n" +
+	"\x15GetTopChattersRequest\x12\x1f\n" +
+	"\vchatroom_id\x18\x01 \x01(\tR\n" +
+	"chatroomId\x12!\n" +
+	"\fperiod_hours\x18\x02 \x01(\x05R\vperiodHours\x12\x14\n" +
+	"\x05limit\x18\x03 \x01(\x05R\x05limit\"n\n" +
+	"\x16GetTopChattersResponse\x12&\n" +
+	"\x06status\x18\x01 \x01(\v2\x0e.common.StatusR\x06status\x12,\n" +
+	"\bchatters\x18\x02 \x03(\v2\x10.chat.TopChatterR\bchatters\"]\n" +
+	"\n" +
+	"TopChatter\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x1a\n" +
+	"\busername\x18\x02 \x01(\tR\busername\x12\x1a\n" +
+	"\bmessages\x18\x03 \x01(\x03R\bmessages\"o\n" +
+	"\x13GetTopEmotesRequest\x12\x1f\n" +
+	"\vchatroom_id\x18\x01 \x01(\tR\n" +
+	"chatroomId\x12!\n" +
+	"\fperiod_hours\x18\x02 \x01(\x05R\vperiodHours\x12\x14\n" +
+	"\x05limit\x18\x03 \x01(\x05R\x05limit\"h\n" +
+	"\x14GetTopEmotesResponse\x12&\n" +
+	"\x06status\x18\x01 \x01(\v2\x0e.common.StatusR\x06status\x12(\n" +
+	"\x06emotes\x18\x02 \x03(\v2\x10.chat.EmoteCountR\x06emotes\"\\\n" +
+	"\x16GetChatActivityRequest\x12\x1f\n" +
+	"\vchatroom_id\x18\x01 \x01(\tR\n" +
+	"chatroomId\x12!\n" +
+	"\fperiod_hours\x18\x02 \x01(\x05R\vperiodHours\"\xd6\x01\n" +
+	"\x17GetChatActivityResponse\x12&\n" +
+	"\x06status\x18\x01 \x01(\v2\x0e.common.StatusR\x06status\x122\n" +
+	"\abuckets\x18\x02 \x03(\v2\x18.chat.ChatActivityBucketR\abuckets\x12%\n" +
+	"\x0etotal_messages\x18\x03 \x01(\x03R\rtotalMessages\x128\n" +
+	"\x18total_moderation_actions\x18\x04 \x01(\x03R\x16totalModerationActions\"\xaf\x01\n" +
+	"\x12ChatActivityBucket\x12%\n" +
+	"\x04hour\x18\x01 \x01(\v2\x11.common.TimestampR\x04hour\x12\x1a\n" +
+	"\bmessages\x18\x02 \x01(\x03R\bmessages\x12'\n" +
+	"\x0funique_chatters\x18\x03 \x01(\x03R\x0euniqueChatters\x12-\n" +
+	"\x12moderation_actions\x18\x04 \x01(\x03R\x11moderationActions\"\xa3\x01\n" +
+	"\x15PostVODCommentRequest\x12\x15\n" +
+	"\x06vod_id\x18\x01 \x01(\tR\x05vodId\x12\x1f\n" +
+	"\vchatroom_id\x18\x02 \x01(\tR\n" +
+	"chatroomId\x12\x17\n" +
+	"\auser_id\x18\x03 \x01(\tR\x06userId\x12\x1f\n" +
+	"\vposition_ms\x18\x04 \x01(\x03R\n" +
+	"positionMs\x12\x18\n" +
+	"\acontent\x18\x05 \x01(\tR\acontent\"l\n" +
+	"\x16PostVODCommentResponse\x12&\n" +
+	"\x06status\x18\x01 \x01(\v2\x0e.common.StatusR\x06status\x12*\n" +
+	"\acomment\x18\x02 \x01(\v2\x10.chat.VODCommentR\acomment\"i\n" +
+	"\x15EditVODCommentRequest\x12\x1d\n" +
+	"\n" +
+	"comment_id\x18\x01 \x01(\tR\tcommentId\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\tR\x06userId\x12\x18\n" +
+	"\acontent\x18\x03 \x01(\tR\acontent\"l\n" +
+	"\x16EditVODCommentResponse\x12&\n" +
+	"\x06status\x18\x01 \x01(\v2\x0e.common.StatusR\x06status\x12*\n" +
+	"\acomment\x18\x02 \x01(\v2\x10.chat.VODCommentR\acomment\"Q\n" +
+	"\x17DeleteVODCommentRequest\x12\x1d\n" +
+	"\n" +
+	"comment_id\x18\x01 \x01(\tR\tcommentId\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\tR\x06userId\"B\n" +
+	"\x18DeleteVODCommentResponse\x12&\n" +
+	"\x06status\x18\x01 \x01(\v2\x0e.common.StatusR\x06status\"\xac\x01\n" +
+	"\x15GetVODCommentsRequest\x12\x15\n" +
+	"\x06vod_id\x18\x01 \x01(\tR\x05vodId\x12(\n" +
+	"\x10from_position_ms\x18\x02 \x01(\x03R\x0efromPositionMs\x12$\n" +
+	"\x0eto_position_ms\x18\x03 \x01(\x03R\ftoPositionMs\x12\x14\n" +
+	"\x05limit\x18\x04 \x01(\x05R\x05limit\x12\x16\n" +
+	"\x06cursor\x18\x05 \x01(\tR\x06cursor\"\x8f\x01\n" +
+	"\x16GetVODCommentsResponse\x12&\n" +
+	"\x06status\x18\x01 \x01(\v2\x0e.common.StatusR\x06status\x12,\n" +
+	"\bcomments\x18\x02 \x03(\v2\x10.chat.VODCommentR\bcomments\x12\x1f\n" +
+	"\vnext_cursor\x18\x03 \x01(\tR\n" +
+	"nextCursor\"\xa4\x02\n" +
+	"\n" +
+	"VODComment\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x15\n" +
+	"\x06vod_id\x18\x02 \x01(\tR\x05vodId\x12\x17\n" +
+	"\auser_id\x18\x03 \x01(\tR\x06userId\x12\x1a\n" +
+	"\busername\x18\x04 \x01(\tR\busername\x12\x1f\n" +
+	"\vposition_ms\x18\x05 \x01(\x03R\n" +
+	"positionMs\x12\x18\n" +
+	"\acontent\x18\x06 \x01(\tR\acontent\x120\n" +
+	"\n" +
+	"created_at\x18\a \x01(\v2\x11.common.TimestampR\tcreatedAt\x120\n" +
+	"\n" +
+	"updated_at\x18\b \x01(\v2\x11.common.TimestampR\tupdatedAt\x12\x1b\n" +
+	"\tis_edited\x18\t \x01(\bR\bisEdited\"\x91\x02\n" +
 	"\bChatroom\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
 	"\x04name\x18\x02 \x01(\tR\x04name\x12 \n" +
@@ -1012,14 +2560,24 @@ const file_chat_chat_service_proto_rawDesc = "" +
 	"\x05IMAGE\x10\x01\x12\b\n" +
 	"\x04FILE\x10\x02\x12\n" +
 	"\n" +
-	"\x06SYSTEM\x10\x032\xba\x03\n" +
+	"\x06SYSTEM\x10\x032\xe9\b\n" +
 	"\vChatService\x12K\n" +
 	"\x0eCreateChatroom\x12\x1b.chat.CreateChatroomRequest\x1a\x1c.chat.CreateChatroomResponse\x12E\n" +
 	"\fJoinChatroom\x12\x19.chat.JoinChatroomRequest\x1a\x1a.chat.JoinChatroomResponse\x12H\n" +
 	"\rLeaveChatroom\x12\x1a.chat.LeaveChatroomRequest\x1a\x1b.chat.LeaveChatroomResponse\x12B\n" +
 	"\vSendMessage\x12\x18.chat.SendMessageRequest\x1a\x19.chat.SendMessageResponse\x12B\n" +
 	"\vGetMessages\x12\x18.chat.GetMessagesRequest\x1a\x19.chat.GetMessagesResponse\x12E\n" +
-	"\fGetChatrooms\x12\x19.chat.GetChatroomsRequest\x1a\x1a.chat.GetChatroomsResponseB\xad\x01\n" +
+	"\fGetChatrooms\x12\x19.chat.GetChatroomsRequest\x1a\x1a.chat.GetChatroomsResponse\x12?\n" +
+	"\n" +
+	"PinMessage\x12\x17.chat.PinMessageRequest\x1a\x18.chat.PinMessageResponse\x12N\n" +
+	"\x0fGetRoomSnapshot\x12\x1c.chat.GetRoomSnapshotRequest\x1a\x1d.chat.GetRoomSnapshotResponse\x12K\n" +
+	"\x0eGetTopChatters\x12\x1b.chat.GetTopChattersRequest\x1a\x1c.chat.GetTopChattersResponse\x12N\n" +
+	"\x0fGetChatActivity\x12\x1c.chat.GetChatActivityRequest\x1a\x1d.chat.GetChatActivityResponse\x12E\n" +
+	"\fGetTopEmotes\x12\x19.chat.GetTopEmotesRequest\x1a\x1a.chat.GetTopEmotesResponse\x12K\n" +
+	"\x0ePostVODComment\x12\x1b.chat.PostVODCommentRequest\x1a\x1c.chat.PostVODCommentResponse\x12K\n" +
+	"\x0eEditVODComment\x12\x1b.chat.EditVODCommentRequest\x1a\x1c.chat.EditVODCommentResponse\x12Q\n" +
+	"\x10DeleteVODComment\x12\x1d.chat.DeleteVODCommentRequest\x1a\x1e.chat.DeleteVODCommentResponse\x12K\n" +
+	"\x0eGetVODComments\x12\x1b.chat.GetVODCommentsRequest\x1a\x1c.chat.GetVODCommentsResponseB\xad\x01\n" +
 	"\bcom.chatB\x10ChatServiceProtoP\x01Z_github.com/Saoudyahya/Live-Streaming-Platform-Architecture/services/chat-service/pkg/proto/chat\xa2\x02\x03CXX\xaa\x02\x04Chat\xca\x02\x04Chat\xe2\x02\x10Chat\\GPBMetadata\xea\x02\x04Chatb\x06proto3"
 
 var (
@@ -1035,59 +2593,124 @@ func file_chat_chat_service_proto_rawDescGZIP() []byte {
 }
 
 var file_chat_chat_service_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
-var file_chat_chat_service_proto_msgTypes = make([]protoimpl.MessageInfo, 14)
+var file_chat_chat_service_proto_msgTypes = make([]protoimpl.MessageInfo, 37)
 var file_chat_chat_service_proto_goTypes = []any{
-	(MessageType)(0),               // 0: chat.MessageType
-	(*CreateChatroomRequest)(nil),  // 1: chat.CreateChatroomRequest
-	(*CreateChatroomResponse)(nil), // 2: chat.CreateChatroomResponse
-	(*JoinChatroomRequest)(nil),    // 3: chat.JoinChatroomRequest
-	(*JoinChatroomResponse)(nil),   // 4: chat.JoinChatroomResponse
-	(*LeaveChatroomRequest)(nil),   // 5: chat.LeaveChatroomRequest
-	(*LeaveChatroomResponse)(nil),  // 6: chat.LeaveChatroomResponse
-	(*SendMessageRequest)(nil),     // 7: chat.SendMessageRequest
-	(*SendMessageResponse)(nil),    // 8: chat.SendMessageResponse
-	(*GetMessagesRequest)(nil),     // 9: chat.GetMessagesRequest
-	(*GetMessagesResponse)(nil),    // 10: chat.GetMessagesResponse
-	(*GetChatroomsRequest)(nil),    // 11: chat.GetChatroomsRequest
-	(*GetChatroomsResponse)(nil),   // 12: chat.GetChatroomsResponse
-	(*Chatroom)(nil),               // 13: chat.Chatroom
-	(*Message)(nil),                // 14: chat.Message
-	(*common.Status)(nil),          // 15: common.Status
-	(*common.Timestamp)(nil),       // 16: common.Timestamp
+	(MessageType)(0),                 // 0: chat.MessageType
+	(*CreateChatroomRequest)(nil),    // 1: chat.CreateChatroomRequest
+	(*CreateChatroomResponse)(nil),   // 2: chat.CreateChatroomResponse
+	(*JoinChatroomRequest)(nil),      // 3: chat.JoinChatroomRequest
+	(*JoinChatroomResponse)(nil),     // 4: chat.JoinChatroomResponse
+	(*LeaveChatroomRequest)(nil),     // 5: chat.LeaveChatroomRequest
+	(*LeaveChatroomResponse)(nil),    // 6: chat.LeaveChatroomResponse
+	(*SendMessageRequest)(nil),       // 7: chat.SendMessageRequest
+	(*SendMessageResponse)(nil),      // 8: chat.SendMessageResponse
+	(*GetMessagesRequest)(nil),       // 9: chat.GetMessagesRequest
+	(*GetMessagesResponse)(nil),      // 10: chat.GetMessagesResponse
+	(*GetChatroomsRequest)(nil),      // 11: chat.GetChatroomsRequest
+	(*GetChatroomsResponse)(nil),     // 12: chat.GetChatroomsResponse
+	(*PinMessageRequest)(nil),        // 13: chat.PinMessageRequest
+	(*PinMessageResponse)(nil),       // 14: chat.PinMessageResponse
+	(*GetRoomSnapshotRequest)(nil),   // 15: chat.GetRoomSnapshotRequest
+	(*GetRoomSnapshotResponse)(nil),  // 16: chat.GetRoomSnapshotResponse
+	(*RoomSnapshot)(nil),             // 17: chat.RoomSnapshot
+	(*EmoteCount)(nil),               // 18: chat.EmoteCount
+	(*GetTopChattersRequest)(nil),    // 19: chat.GetTopChattersRequest
+	(*GetTopChattersResponse)(nil),   // 20: chat.GetTopChattersResponse
+	(*TopChatter)(nil),               // 21: chat.TopChatter
+	(*GetTopEmotesRequest)(nil),      // 22: chat.GetTopEmotesRequest
+	(*GetTopEmotesResponse)(nil),     // 23: chat.GetTopEmotesResponse
+	(*GetChatActivityRequest)(nil),   // 24: chat.GetChatActivityRequest
+	(*GetChatActivityResponse)(nil),  // 25: chat.GetChatActivityResponse
+	(*ChatActivityBucket)(nil),       // 26: chat.ChatActivityBucket
+	(*PostVODCommentRequest)(nil),    // 27: chat.PostVODCommentRequest
+	(*PostVODCommentResponse)(nil),   // 28: chat.PostVODCommentResponse
+	(*EditVODCommentRequest)(nil),    // 29: chat.EditVODCommentRequest
+	(*EditVODCommentResponse)(nil),   // 30: chat.EditVODCommentResponse
+	(*DeleteVODCommentRequest)(nil),  // 31: chat.DeleteVODCommentRequest
+	(*DeleteVODCommentResponse)(nil), // 32: chat.DeleteVODCommentResponse
+	(*GetVODCommentsRequest)(nil),    // 33: chat.GetVODCommentsRequest
+	(*GetVODCommentsResponse)(nil),   // 34: chat.GetVODCommentsResponse
+	(*VODComment)(nil),               // 35: chat.VODComment
+	(*Chatroom)(nil),                 // 36: chat.Chatroom
+	(*Message)(nil),                  // 37: chat.Message
+	(*common.Status)(nil),            // 38: common.Status
+	(*common.Timestamp)(nil),         // 39: common.Timestamp
 }
 var file_chat_chat_service_proto_depIdxs = []int32{
-	15, // 0: chat.CreateChatroomResponse.status:type_name -> common.Status
-	13, // 1: chat.CreateChatroomResponse.chatroom:type_name -> chat.Chatroom
-	15, // 2: chat.JoinChatroomResponse.status:type_name -> common.Status
-	15, // 3: chat.LeaveChatroomResponse.status:type_name -> common.Status
+	38, // 0: chat.CreateChatroomResponse.status:type_name -> common.Status
+	36, // 1: chat.CreateChatroomResponse.chatroom:type_name -> chat.Chatroom
+	38, // 2: chat.JoinChatroomResponse.status:type_name -> common.Status
+	38, // 3: chat.LeaveChatroomResponse.status:type_name -> common.Status
 	0,  // 4: chat.SendMessageRequest.type:type_name -> chat.MessageType
-	15, // 5: chat.SendMessageResponse.status:type_name -> common.Status
-	14, // 6: chat.SendMessageResponse.message:type_name -> chat.Message
-	15, // 7: chat.GetMessagesResponse.status:type_name -> common.Status
-	14, // 8: chat.GetMessagesResponse.messages:type_name -> chat.Message
-	15, // 9: chat.GetChatroomsResponse.status:type_name -> common.Status
-	13, // 10: chat.GetChatroomsResponse.chatrooms:type_name -> chat.Chatroom
-	16, // 11: chat.Chatroom.created_at:type_name -> common.Timestamp
-	16, // 12: chat.Chatroom.updated_at:type_name -> common.Timestamp
-	0,  // 13: chat.Message.type:type_name -> chat.MessageType
-	16, // 14: chat.Message.created_at:type_name -> common.Timestamp
-	1,  // 15: chat.ChatService.CreateChatroom:input_type -> chat.CreateChatroomRequest
-	3,  // 16: chat.ChatService.JoinChatroom:input_type -> chat.JoinChatroomRequest
-	5,  // 17: chat.ChatService.LeaveChatroom:input_type -> chat.LeaveChatroomRequest
-	7,  // 18: chat.ChatService.SendMessage:input_type -> chat.SendMessageRequest
-	9,  // 19: chat.ChatService.GetMessages:input_type -> chat.GetMessagesRequest
-	11, // 20: chat.ChatService.GetChatrooms:input_type -> chat.GetChatroomsRequest
-	2,  // 21: chat.ChatService.CreateChatroom:output_type -> chat.CreateChatroomResponse
-	4,  // 22: chat.ChatService.JoinChatroom:output_type -> chat.JoinChatroomResponse
-	6,  // 23: chat.ChatService.LeaveChatroom:output_type -> chat.LeaveChatroomResponse
-	8,  // 24: chat.ChatService.SendMessage:output_type -> chat.SendMessageResponse
-	10, // 25: chat.ChatService.GetMessages:output_type -> chat.GetMessagesResponse
-	12, // 26: chat.ChatService.GetChatrooms:output_type -> chat.GetChatroomsResponse
-	21, // [21:27] is the sub-list for method output_type
-	15, // [15:21] is the sub-list for method input_type
-	15, // [15:15] is the sub-list for extension type_name
-	15, // [15:15] is the sub-list for extension extendee
-	0,  // [0:15] is the sub-list for field type_name
+	38, // 5: chat.SendMessageResponse.status:type_name -> common.Status
+	37, // 6: chat.SendMessageResponse.message:type_name -> chat.Message
+	38, // 7: chat.GetMessagesResponse.status:type_name -> common.Status
+	37, // 8: chat.GetMessagesResponse.messages:type_name -> chat.Message
+	38, // 9: chat.GetChatroomsResponse.status:type_name -> common.Status
+	36, // 10: chat.GetChatroomsResponse.chatrooms:type_name -> chat.Chatroom
+	38, // 11: chat.PinMessageResponse.status:type_name -> common.Status
+	38, // 12: chat.GetRoomSnapshotResponse.status:type_name -> common.Status
+	17, // 13: chat.GetRoomSnapshotResponse.snapshot:type_name -> chat.RoomSnapshot
+	36, // 14: chat.RoomSnapshot.chatroom:type_name -> chat.Chatroom
+	37, // 15: chat.RoomSnapshot.pinned_messages:type_name -> chat.Message
+	18, // 16: chat.RoomSnapshot.top_emotes:type_name -> chat.EmoteCount
+	37, // 17: chat.RoomSnapshot.recent_messages:type_name -> chat.Message
+	39, // 18: chat.RoomSnapshot.updated_at:type_name -> common.Timestamp
+	38, // 19: chat.GetTopChattersResponse.status:type_name -> common.Status
+	21, // 20: chat.GetTopChattersResponse.chatters:type_name -> chat.TopChatter
+	38, // 21: chat.GetTopEmotesResponse.status:type_name -> common.Status
+	18, // 22: chat.GetTopEmotesResponse.emotes:type_name -> chat.EmoteCount
+	38, // 23: chat.GetChatActivityResponse.status:type_name -> common.Status
+	26, // 24: chat.GetChatActivityResponse.buckets:type_name -> chat.ChatActivityBucket
+	39, // 25: chat.ChatActivityBucket.hour:type_name -> common.Timestamp
+	38, // 26: chat.PostVODCommentResponse.status:type_name -> common.Status
+	35, // 27: chat.PostVODCommentResponse.comment:type_name -> chat.VODComment
+	38, // 28: chat.EditVODCommentResponse.status:type_name -> common.Status
+	35, // 29: chat.EditVODCommentResponse.comment:type_name -> chat.VODComment
+	38, // 30: chat.DeleteVODCommentResponse.status:type_name -> common.Status
+	38, // 31: chat.GetVODCommentsResponse.status:type_name -> common.Status
+	35, // 32: chat.GetVODCommentsResponse.comments:type_name -> chat.VODComment
+	39, // 33: chat.VODComment.created_at:type_name -> common.Timestamp
+	39, // 34: chat.VODComment.updated_at:type_name -> common.Timestamp
+	39, // 35: chat.Chatroom.created_at:type_name -> common.Timestamp
+	39, // 36: chat.Chatroom.updated_at:type_name -> common.Timestamp
+	0,  // 37: chat.Message.type:type_name -> chat.MessageType
+	39, // 38: chat.Message.created_at:type_name -> common.Timestamp
+	1,  // 39: chat.ChatService.CreateChatroom:input_type -> chat.CreateChatroomRequest
+	3,  // 40: chat.ChatService.JoinChatroom:input_type -> chat.JoinChatroomRequest
+	5,  // 41: chat.ChatService.LeaveChatroom:input_type -> chat.LeaveChatroomRequest
+	7,  // 42: chat.ChatService.SendMessage:input_type -> chat.SendMessageRequest
+	9,  // 43: chat.ChatService.GetMessages:input_type -> chat.GetMessagesRequest
+	11, // 44: chat.ChatService.GetChatrooms:input_type -> chat.GetChatroomsRequest
+	13, // 45: chat.ChatService.PinMessage:input_type -> chat.PinMessageRequest
+	15, // 46: chat.ChatService.GetRoomSnapshot:input_type -> chat.GetRoomSnapshotRequest
+	19, // 47: chat.ChatService.GetTopChatters:input_type -> chat.GetTopChattersRequest
+	24, // 48: chat.ChatService.GetChatActivity:input_type -> chat.GetChatActivityRequest
+	22, // 49: chat.ChatService.GetTopEmotes:input_type -> chat.GetTopEmotesRequest
+	27, // 50: chat.ChatService.PostVODComment:input_type -> chat.PostVODCommentRequest
+	29, // 51: chat.ChatService.EditVODComment:input_type -> chat.EditVODCommentRequest
+	31, // 52: chat.ChatService.DeleteVODComment:input_type -> chat.DeleteVODCommentRequest
+	33, // 53: chat.ChatService.GetVODComments:input_type -> chat.GetVODCommentsRequest
+	2,  // 54: chat.ChatService.CreateChatroom:output_type -> chat.CreateChatroomResponse
+	4,  // 55: chat.ChatService.JoinChatroom:output_type -> chat.JoinChatroomResponse
+	6,  // 56: chat.ChatService.LeaveChatroom:output_type -> chat.LeaveChatroomResponse
+	8,  // 57: chat.ChatService.SendMessage:output_type -> chat.SendMessageResponse
+	10, // 58: chat.ChatService.GetMessages:output_type -> chat.GetMessagesResponse
+	12, // 59: chat.ChatService.GetChatrooms:output_type -> chat.GetChatroomsResponse
+	14, // 60: chat.ChatService.PinMessage:output_type -> chat.PinMessageResponse
+	16, // 61: chat.ChatService.GetRoomSnapshot:output_type -> chat.GetRoomSnapshotResponse
+	20, // 62: chat.ChatService.GetTopChatters:output_type -> chat.GetTopChattersResponse
+	25, // 63: chat.ChatService.GetChatActivity:output_type -> chat.GetChatActivityResponse
+	23, // 64: chat.ChatService.GetTopEmotes:output_type -> chat.GetTopEmotesResponse
+	28, // 65: chat.ChatService.PostVODComment:output_type -> chat.PostVODCommentResponse
+	30, // 66: chat.ChatService.EditVODComment:output_type -> chat.EditVODCommentResponse
+	32, // 67: chat.ChatService.DeleteVODComment:output_type -> chat.DeleteVODCommentResponse
+	34, // 68: chat.ChatService.GetVODComments:output_type -> chat.GetVODCommentsResponse
+	54, // [54:69] is the sub-list for method output_type
+	39, // [39:54] is the sub-list for method input_type
+	39, // [39:39] is the sub-list for extension type_name
+	39, // [39:39] is the sub-list for extension extendee
+	0,  // [0:39] is the sub-list for field type_name
 }
 
 func init() { file_chat_chat_service_proto_init() }
@@ -1101,7 +2724,7 @@ func file_chat_chat_service_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_chat_chat_service_proto_rawDesc), len(file_chat_chat_service_proto_rawDesc)),
 			NumEnums:      1,
-			NumMessages:   14,
+			NumMessages:   37,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
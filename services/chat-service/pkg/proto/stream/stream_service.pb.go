@@ -79,6 +79,58 @@ func (StreamStatus) EnumDescriptor() ([]byte, []int) {
 	return file_stream_stream_service_proto_rawDescGZIP(), []int{0}
 }
 
+type HealthStatus int32
+
+const (
+	HealthStatus_HEALTH_UNKNOWN  HealthStatus = 0
+	HealthStatus_HEALTH_GOOD     HealthStatus = 1
+	HealthStatus_HEALTH_DEGRADED HealthStatus = 2
+	HealthStatus_HEALTH_CRITICAL HealthStatus = 3
+)
+
+// Enum value maps for HealthStatus.
+var (
+	HealthStatus_name = map[int32]string{
+		0: "HEALTH_UNKNOWN",
+		1: "HEALTH_GOOD",
+		2: "HEALTH_DEGRADED",
+		3: "HEALTH_CRITICAL",
+	}
+	HealthStatus_value = map[string]int32{
+		"HEALTH_UNKNOWN":  0,
+		"HEALTH_GOOD":     1,
+		"HEALTH_DEGRADED": 2,
+		"HEALTH_CRITICAL": 3,
+	}
+)
+
+func (x HealthStatus) Enum() *HealthStatus {
+	p := new(HealthStatus)
+	*p = x
+	return p
+}
+
+func (x HealthStatus) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (HealthStatus) Descriptor() protoreflect.EnumDescriptor {
+	return file_stream_stream_service_proto_enumTypes[1].Descriptor()
+}
+
+func (HealthStatus) Type() protoreflect.EnumType {
+	return &file_stream_stream_service_proto_enumTypes[1]
+}
+
+func (x HealthStatus) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use HealthStatus.Descriptor instead.
+func (HealthStatus) EnumDescriptor() ([]byte, []int) {
+	return file_stream_stream_service_proto_rawDescGZIP(), []int{1}
+}
+
 // Stream key validation (called by media server)
 type ValidateStreamKeyRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
@@ -141,12 +193,15 @@ func (x *ValidateStreamKeyRequest) GetAppName() string {
 }
 
 type ValidateStreamKeyResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Status        *common.Status         `protobuf:"bytes,1,opt,name=status,proto3" json:"status,omitempty"`
-	IsValid       bool                   `protobuf:"varint,2,opt,name=is_valid,json=isValid,proto3" json:"is_valid,omitempty"`
-	UserId        int64                  `protobuf:"varint,3,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
-	Username      string                 `protobuf:"bytes,4,opt,name=username,proto3" json:"username,omitempty"`
-	Permissions   *StreamPermissions     `protobuf:"bytes,5,opt,name=permissions,proto3" json:"permissions,omitempty"`
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	Status      *common.Status         `protobuf:"bytes,1,opt,name=status,proto3" json:"status,omitempty"`
+	IsValid     bool                   `protobuf:"varint,2,opt,name=is_valid,json=isValid,proto3" json:"is_valid,omitempty"`
+	UserId      int64                  `protobuf:"varint,3,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Username    string                 `protobuf:"bytes,4,opt,name=username,proto3" json:"username,omitempty"`
+	Permissions *StreamPermissions     `protobuf:"bytes,5,opt,name=permissions,proto3" json:"permissions,omitempty"`
+	// Renditions the transcoder produces besides the source, highest bitrate first
+	QualityLadder []*LadderRendition `protobuf:"bytes,6,rep,name=quality_ladder,json=qualityLadder,proto3" json:"quality_ladder,omitempty"`
+	QualityTier   string             `protobuf:"bytes,7,opt,name=quality_tier,json=qualityTier,proto3" json:"quality_tier,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -216,6 +271,20 @@ func (x *ValidateStreamKeyResponse) GetPermissions() *StreamPermissions {
 	return nil
 }
 
+func (x *ValidateStreamKeyResponse) GetQualityLadder() []*LadderRendition {
+	if x != nil {
+		return x.QualityLadder
+	}
+	return nil
+}
+
+func (x *ValidateStreamKeyResponse) GetQualityTier() string {
+	if x != nil {
+		return x.QualityTier
+	}
+	return ""
+}
+
 type StreamPermissions struct {
 	state              protoimpl.MessageState `protogen:"open.v1"`
 	CanStream          bool                   `protobuf:"varint,1,opt,name=can_stream,json=canStream,proto3" json:"can_stream,omitempty"`
@@ -284,6 +353,82 @@ func (x *StreamPermissions) GetMaxDurationMinutes() int32 {
 	return 0
 }
 
+type LadderRendition struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Width         int32                  `protobuf:"varint,2,opt,name=width,proto3" json:"width,omitempty"`
+	Height        int32                  `protobuf:"varint,3,opt,name=height,proto3" json:"height,omitempty"`
+	BitrateKbps   int32                  `protobuf:"varint,4,opt,name=bitrate_kbps,json=bitrateKbps,proto3" json:"bitrate_kbps,omitempty"`
+	Fps           int32                  `protobuf:"varint,5,opt,name=fps,proto3" json:"fps,omitempty"` // the source frame rate when 0
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LadderRendition) Reset() {
+	*x = LadderRendition{}
+	mi := &file_stream_stream_service_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LadderRendition) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LadderRendition) ProtoMessage() {}
+
+func (x *LadderRendition) ProtoReflect() protoreflect.Message {
+	mi := &file_stream_stream_service_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LadderRendition.ProtoReflect.Descriptor instead.
+func (*LadderRendition) Descriptor() ([]byte, []int) {
+	return file_stream_stream_service_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *LadderRendition) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *LadderRendition) GetWidth() int32 {
+	if x != nil {
+		return x.Width
+	}
+	return 0
+}
+
+func (x *LadderRendition) GetHeight() int32 {
+	if x != nil {
+		return x.Height
+	}
+	return 0
+}
+
+func (x *LadderRendition) GetBitrateKbps() int32 {
+	if x != nil {
+		return x.BitrateKbps
+	}
+	return 0
+}
+
+func (x *LadderRendition) GetFps() int32 {
+	if x != nil {
+		return x.Fps
+	}
+	return 0
+}
+
 // Stream management
 type CreateStreamRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
@@ -292,13 +437,14 @@ type CreateStreamRequest struct {
 	Title         string                 `protobuf:"bytes,3,opt,name=title,proto3" json:"title,omitempty"`
 	Description   string                 `protobuf:"bytes,4,opt,name=description,proto3" json:"description,omitempty"`
 	Metadata      *StreamMetadata        `protobuf:"bytes,5,opt,name=metadata,proto3" json:"metadata,omitempty"`
+	Language      string                 `protobuf:"bytes,6,opt,name=language,proto3" json:"language,omitempty"` // BCP-47, e.g. en or pt-BR
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *CreateStreamRequest) Reset() {
 	*x = CreateStreamRequest{}
-	mi := &file_stream_stream_service_proto_msgTypes[3]
+	mi := &file_stream_stream_service_proto_msgTypes[4]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -310,7 +456,7 @@ func (x *CreateStreamRequest) String() string {
 func (*CreateStreamRequest) ProtoMessage() {}
 
 func (x *CreateStreamRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_stream_stream_service_proto_msgTypes[3]
+	mi := &file_stream_stream_service_proto_msgTypes[4]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -323,7 +469,7 @@ func (x *CreateStreamRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use CreateStreamRequest.ProtoReflect.Descriptor instead.
 func (*CreateStreamRequest) Descriptor() ([]byte, []int) {
-	return file_stream_stream_service_proto_rawDescGZIP(), []int{3}
+	return file_stream_stream_service_proto_rawDescGZIP(), []int{4}
 }
 
 func (x *CreateStreamRequest) GetUserId() int64 {
@@ -361,6 +507,13 @@ func (x *CreateStreamRequest) GetMetadata() *StreamMetadata {
 	return nil
 }
 
+func (x *CreateStreamRequest) GetLanguage() string {
+	if x != nil {
+		return x.Language
+	}
+	return ""
+}
+
 type CreateStreamResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Status        *common.Status         `protobuf:"bytes,1,opt,name=status,proto3" json:"status,omitempty"`
@@ -372,7 +525,7 @@ type CreateStreamResponse struct {
 
 func (x *CreateStreamResponse) Reset() {
 	*x = CreateStreamResponse{}
-	mi := &file_stream_stream_service_proto_msgTypes[4]
+	mi := &file_stream_stream_service_proto_msgTypes[5]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -384,7 +537,7 @@ func (x *CreateStreamResponse) String() string {
 func (*CreateStreamResponse) ProtoMessage() {}
 
 func (x *CreateStreamResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_stream_stream_service_proto_msgTypes[4]
+	mi := &file_stream_stream_service_proto_msgTypes[5]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -397,7 +550,7 @@ func (x *CreateStreamResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use CreateStreamResponse.ProtoReflect.Descriptor instead.
 func (*CreateStreamResponse) Descriptor() ([]byte, []int) {
-	return file_stream_stream_service_proto_rawDescGZIP(), []int{4}
+	return file_stream_stream_service_proto_rawDescGZIP(), []int{5}
 }
 
 func (x *CreateStreamResponse) GetStatus() *common.Status {
@@ -434,7 +587,7 @@ type UpdateStreamRequest struct {
 
 func (x *UpdateStreamRequest) Reset() {
 	*x = UpdateStreamRequest{}
-	mi := &file_stream_stream_service_proto_msgTypes[5]
+	mi := &file_stream_stream_service_proto_msgTypes[6]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -446,7 +599,7 @@ func (x *UpdateStreamRequest) String() string {
 func (*UpdateStreamRequest) ProtoMessage() {}
 
 func (x *UpdateStreamRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_stream_stream_service_proto_msgTypes[5]
+	mi := &file_stream_stream_service_proto_msgTypes[6]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -459,7 +612,7 @@ func (x *UpdateStreamRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use UpdateStreamRequest.ProtoReflect.Descriptor instead.
 func (*UpdateStreamRequest) Descriptor() ([]byte, []int) {
-	return file_stream_stream_service_proto_rawDescGZIP(), []int{5}
+	return file_stream_stream_service_proto_rawDescGZIP(), []int{6}
 }
 
 func (x *UpdateStreamRequest) GetStreamId() string {
@@ -507,7 +660,7 @@ type UpdateStreamResponse struct {
 
 func (x *UpdateStreamResponse) Reset() {
 	*x = UpdateStreamResponse{}
-	mi := &file_stream_stream_service_proto_msgTypes[6]
+	mi := &file_stream_stream_service_proto_msgTypes[7]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -519,7 +672,7 @@ func (x *UpdateStreamResponse) String() string {
 func (*UpdateStreamResponse) ProtoMessage() {}
 
 func (x *UpdateStreamResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_stream_stream_service_proto_msgTypes[6]
+	mi := &file_stream_stream_service_proto_msgTypes[7]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -532,7 +685,7 @@ func (x *UpdateStreamResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use UpdateStreamResponse.ProtoReflect.Descriptor instead.
 func (*UpdateStreamResponse) Descriptor() ([]byte, []int) {
-	return file_stream_stream_service_proto_rawDescGZIP(), []int{6}
+	return file_stream_stream_service_proto_rawDescGZIP(), []int{7}
 }
 
 func (x *UpdateStreamResponse) GetStatus() *common.Status {
@@ -558,7 +711,7 @@ type GetStreamRequest struct {
 
 func (x *GetStreamRequest) Reset() {
 	*x = GetStreamRequest{}
-	mi := &file_stream_stream_service_proto_msgTypes[7]
+	mi := &file_stream_stream_service_proto_msgTypes[8]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -570,7 +723,7 @@ func (x *GetStreamRequest) String() string {
 func (*GetStreamRequest) ProtoMessage() {}
 
 func (x *GetStreamRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_stream_stream_service_proto_msgTypes[7]
+	mi := &file_stream_stream_service_proto_msgTypes[8]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -583,7 +736,7 @@ func (x *GetStreamRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetStreamRequest.ProtoReflect.Descriptor instead.
 func (*GetStreamRequest) Descriptor() ([]byte, []int) {
-	return file_stream_stream_service_proto_rawDescGZIP(), []int{7}
+	return file_stream_stream_service_proto_rawDescGZIP(), []int{8}
 }
 
 func (x *GetStreamRequest) GetStreamId() string {
@@ -603,7 +756,7 @@ type GetStreamResponse struct {
 
 func (x *GetStreamResponse) Reset() {
 	*x = GetStreamResponse{}
-	mi := &file_stream_stream_service_proto_msgTypes[8]
+	mi := &file_stream_stream_service_proto_msgTypes[9]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -615,7 +768,7 @@ func (x *GetStreamResponse) String() string {
 func (*GetStreamResponse) ProtoMessage() {}
 
 func (x *GetStreamResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_stream_stream_service_proto_msgTypes[8]
+	mi := &file_stream_stream_service_proto_msgTypes[9]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -628,7 +781,7 @@ func (x *GetStreamResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetStreamResponse.ProtoReflect.Descriptor instead.
 func (*GetStreamResponse) Descriptor() ([]byte, []int) {
-	return file_stream_stream_service_proto_rawDescGZIP(), []int{8}
+	return file_stream_stream_service_proto_rawDescGZIP(), []int{9}
 }
 
 func (x *GetStreamResponse) GetStatus() *common.Status {
@@ -645,29 +798,30 @@ func (x *GetStreamResponse) GetStream() *Stream {
 	return nil
 }
 
-type GetActiveStreamsRequest struct {
+// GetStreamByKeyRequest looks up a stream by its stream key, for callers that only know the
+// key (media server, chat)
+type GetStreamByKeyRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Limit         int32                  `protobuf:"varint,1,opt,name=limit,proto3" json:"limit,omitempty"`
-	Cursor        string                 `protobuf:"bytes,2,opt,name=cursor,proto3" json:"cursor,omitempty"`
+	StreamKey     string                 `protobuf:"bytes,1,opt,name=stream_key,json=streamKey,proto3" json:"stream_key,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetActiveStreamsRequest) Reset() {
-	*x = GetActiveStreamsRequest{}
-	mi := &file_stream_stream_service_proto_msgTypes[9]
+func (x *GetStreamByKeyRequest) Reset() {
+	*x = GetStreamByKeyRequest{}
+	mi := &file_stream_stream_service_proto_msgTypes[10]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetActiveStreamsRequest) String() string {
+func (x *GetStreamByKeyRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetActiveStreamsRequest) ProtoMessage() {}
+func (*GetStreamByKeyRequest) ProtoMessage() {}
 
-func (x *GetActiveStreamsRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_stream_stream_service_proto_msgTypes[9]
+func (x *GetStreamByKeyRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_stream_stream_service_proto_msgTypes[10]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -678,50 +832,42 @@ func (x *GetActiveStreamsRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetActiveStreamsRequest.ProtoReflect.Descriptor instead.
-func (*GetActiveStreamsRequest) Descriptor() ([]byte, []int) {
-	return file_stream_stream_service_proto_rawDescGZIP(), []int{9}
-}
-
-func (x *GetActiveStreamsRequest) GetLimit() int32 {
-	if x != nil {
-		return x.Limit
-	}
-	return 0
+// Deprecated: Use GetStreamByKeyRequest.ProtoReflect.Descriptor instead.
+func (*GetStreamByKeyRequest) Descriptor() ([]byte, []int) {
+	return file_stream_stream_service_proto_rawDescGZIP(), []int{10}
 }
 
-func (x *GetActiveStreamsRequest) GetCursor() string {
+func (x *GetStreamByKeyRequest) GetStreamKey() string {
 	if x != nil {
-		return x.Cursor
+		return x.StreamKey
 	}
 	return ""
 }
 
-type GetActiveStreamsResponse struct {
+type GetStreamByKeyResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Status        *common.Status         `protobuf:"bytes,1,opt,name=status,proto3" json:"status,omitempty"`
-	Streams       []*Stream              `protobuf:"bytes,2,rep,name=streams,proto3" json:"streams,omitempty"`
-	NextCursor    string                 `protobuf:"bytes,3,opt,name=next_cursor,json=nextCursor,proto3" json:"next_cursor,omitempty"`
-	TotalCount    int32                  `protobuf:"varint,4,opt,name=total_count,json=totalCount,proto3" json:"total_count,omitempty"`
+	Stream        *Stream                `protobuf:"bytes,2,opt,name=stream,proto3" json:"stream,omitempty"`
+	Session       *StreamSession         `protobuf:"bytes,3,opt,name=session,proto3" json:"session,omitempty"` // unset when nobody publishes with the key
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetActiveStreamsResponse) Reset() {
-	*x = GetActiveStreamsResponse{}
-	mi := &file_stream_stream_service_proto_msgTypes[10]
+func (x *GetStreamByKeyResponse) Reset() {
+	*x = GetStreamByKeyResponse{}
+	mi := &file_stream_stream_service_proto_msgTypes[11]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetActiveStreamsResponse) String() string {
+func (x *GetStreamByKeyResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetActiveStreamsResponse) ProtoMessage() {}
+func (*GetStreamByKeyResponse) ProtoMessage() {}
 
-func (x *GetActiveStreamsResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_stream_stream_service_proto_msgTypes[10]
+func (x *GetStreamByKeyResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_stream_stream_service_proto_msgTypes[11]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -732,63 +878,61 @@ func (x *GetActiveStreamsResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetActiveStreamsResponse.ProtoReflect.Descriptor instead.
-func (*GetActiveStreamsResponse) Descriptor() ([]byte, []int) {
-	return file_stream_stream_service_proto_rawDescGZIP(), []int{10}
+// Deprecated: Use GetStreamByKeyResponse.ProtoReflect.Descriptor instead.
+func (*GetStreamByKeyResponse) Descriptor() ([]byte, []int) {
+	return file_stream_stream_service_proto_rawDescGZIP(), []int{11}
 }
 
-func (x *GetActiveStreamsResponse) GetStatus() *common.Status {
+func (x *GetStreamByKeyResponse) GetStatus() *common.Status {
 	if x != nil {
 		return x.Status
 	}
 	return nil
 }
 
-func (x *GetActiveStreamsResponse) GetStreams() []*Stream {
+func (x *GetStreamByKeyResponse) GetStream() *Stream {
 	if x != nil {
-		return x.Streams
+		return x.Stream
 	}
 	return nil
 }
 
-func (x *GetActiveStreamsResponse) GetNextCursor() string {
-	if x != nil {
-		return x.NextCursor
-	}
-	return ""
-}
-
-func (x *GetActiveStreamsResponse) GetTotalCount() int32 {
+func (x *GetStreamByKeyResponse) GetSession() *StreamSession {
 	if x != nil {
-		return x.TotalCount
+		return x.Session
 	}
-	return 0
+	return nil
 }
 
-type EndStreamRequest struct {
-	state           protoimpl.MessageState `protogen:"open.v1"`
-	StreamId        string                 `protobuf:"bytes,1,opt,name=stream_id,json=streamId,proto3" json:"stream_id,omitempty"`
-	DurationSeconds int64                  `protobuf:"varint,2,opt,name=duration_seconds,json=durationSeconds,proto3" json:"duration_seconds,omitempty"`
-	RecordingPath   string                 `protobuf:"bytes,3,opt,name=recording_path,json=recordingPath,proto3" json:"recording_path,omitempty"`
-	unknownFields   protoimpl.UnknownFields
-	sizeCache       protoimpl.SizeCache
+// StreamSession is what the media server callbacks recorded about the publisher
+type StreamSession struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	ClientId       string                 `protobuf:"bytes,1,opt,name=client_id,json=clientId,proto3" json:"client_id,omitempty"`
+	ClientIp       string                 `protobuf:"bytes,2,opt,name=client_ip,json=clientIp,proto3" json:"client_ip,omitempty"`
+	AppName        string                 `protobuf:"bytes,3,opt,name=app_name,json=appName,proto3" json:"app_name,omitempty"`
+	IngestProtocol string                 `protobuf:"bytes,4,opt,name=ingest_protocol,json=ingestProtocol,proto3" json:"ingest_protocol,omitempty"`
+	StartedAt      *common.Timestamp      `protobuf:"bytes,5,opt,name=started_at,json=startedAt,proto3" json:"started_at,omitempty"`
+	Reconnecting   bool                   `protobuf:"varint,6,opt,name=reconnecting,proto3" json:"reconnecting,omitempty"` // the broadcaster dropped and has until the grace period ends
+	DisconnectedAt *common.Timestamp      `protobuf:"bytes,7,opt,name=disconnected_at,json=disconnectedAt,proto3" json:"disconnected_at,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
 }
 
-func (x *EndStreamRequest) Reset() {
-	*x = EndStreamRequest{}
-	mi := &file_stream_stream_service_proto_msgTypes[11]
+func (x *StreamSession) Reset() {
+	*x = StreamSession{}
+	mi := &file_stream_stream_service_proto_msgTypes[12]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *EndStreamRequest) String() string {
+func (x *StreamSession) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*EndStreamRequest) ProtoMessage() {}
+func (*StreamSession) ProtoMessage() {}
 
-func (x *EndStreamRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_stream_stream_service_proto_msgTypes[11]
+func (x *StreamSession) ProtoReflect() protoreflect.Message {
+	mi := &file_stream_stream_service_proto_msgTypes[12]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -799,54 +943,84 @@ func (x *EndStreamRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use EndStreamRequest.ProtoReflect.Descriptor instead.
-func (*EndStreamRequest) Descriptor() ([]byte, []int) {
-	return file_stream_stream_service_proto_rawDescGZIP(), []int{11}
+// Deprecated: Use StreamSession.ProtoReflect.Descriptor instead.
+func (*StreamSession) Descriptor() ([]byte, []int) {
+	return file_stream_stream_service_proto_rawDescGZIP(), []int{12}
 }
 
-func (x *EndStreamRequest) GetStreamId() string {
+func (x *StreamSession) GetClientId() string {
 	if x != nil {
-		return x.StreamId
+		return x.ClientId
 	}
 	return ""
 }
 
-func (x *EndStreamRequest) GetDurationSeconds() int64 {
+func (x *StreamSession) GetClientIp() string {
 	if x != nil {
-		return x.DurationSeconds
+		return x.ClientIp
 	}
-	return 0
+	return ""
 }
 
-func (x *EndStreamRequest) GetRecordingPath() string {
+func (x *StreamSession) GetAppName() string {
 	if x != nil {
-		return x.RecordingPath
+		return x.AppName
 	}
 	return ""
 }
 
-type EndStreamResponse struct {
+func (x *StreamSession) GetIngestProtocol() string {
+	if x != nil {
+		return x.IngestProtocol
+	}
+	return ""
+}
+
+func (x *StreamSession) GetStartedAt() *common.Timestamp {
+	if x != nil {
+		return x.StartedAt
+	}
+	return nil
+}
+
+func (x *StreamSession) GetReconnecting() bool {
+	if x != nil {
+		return x.Reconnecting
+	}
+	return false
+}
+
+func (x *StreamSession) GetDisconnectedAt() *common.Timestamp {
+	if x != nil {
+		return x.DisconnectedAt
+	}
+	return nil
+}
+
+// GetStreamsBatchRequest takes up to 100 IDs. Streams come back in request order, without
+// health; unknown IDs are listed in missing_ids.
+type GetStreamsBatchRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Status        *common.Status         `protobuf:"bytes,1,opt,name=status,proto3" json:"status,omitempty"`
+	StreamIds     []string               `protobuf:"bytes,1,rep,name=stream_ids,json=streamIds,proto3" json:"stream_ids,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *EndStreamResponse) Reset() {
-	*x = EndStreamResponse{}
-	mi := &file_stream_stream_service_proto_msgTypes[12]
+func (x *GetStreamsBatchRequest) Reset() {
+	*x = GetStreamsBatchRequest{}
+	mi := &file_stream_stream_service_proto_msgTypes[13]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *EndStreamResponse) String() string {
+func (x *GetStreamsBatchRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*EndStreamResponse) ProtoMessage() {}
+func (*GetStreamsBatchRequest) ProtoMessage() {}
 
-func (x *EndStreamResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_stream_stream_service_proto_msgTypes[12]
+func (x *GetStreamsBatchRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_stream_stream_service_proto_msgTypes[13]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -857,9 +1031,464 @@ func (x *EndStreamResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use EndStreamResponse.ProtoReflect.Descriptor instead.
-func (*EndStreamResponse) Descriptor() ([]byte, []int) {
-	return file_stream_stream_service_proto_rawDescGZIP(), []int{12}
+// Deprecated: Use GetStreamsBatchRequest.ProtoReflect.Descriptor instead.
+func (*GetStreamsBatchRequest) Descriptor() ([]byte, []int) {
+	return file_stream_stream_service_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *GetStreamsBatchRequest) GetStreamIds() []string {
+	if x != nil {
+		return x.StreamIds
+	}
+	return nil
+}
+
+type GetStreamsBatchResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Status        *common.Status         `protobuf:"bytes,1,opt,name=status,proto3" json:"status,omitempty"`
+	Streams       []*Stream              `protobuf:"bytes,2,rep,name=streams,proto3" json:"streams,omitempty"`
+	MissingIds    []string               `protobuf:"bytes,3,rep,name=missing_ids,json=missingIds,proto3" json:"missing_ids,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetStreamsBatchResponse) Reset() {
+	*x = GetStreamsBatchResponse{}
+	mi := &file_stream_stream_service_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetStreamsBatchResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetStreamsBatchResponse) ProtoMessage() {}
+
+func (x *GetStreamsBatchResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_stream_stream_service_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetStreamsBatchResponse.ProtoReflect.Descriptor instead.
+func (*GetStreamsBatchResponse) Descriptor() ([]byte, []int) {
+	return file_stream_stream_service_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *GetStreamsBatchResponse) GetStatus() *common.Status {
+	if x != nil {
+		return x.Status
+	}
+	return nil
+}
+
+func (x *GetStreamsBatchResponse) GetStreams() []*Stream {
+	if x != nil {
+		return x.Streams
+	}
+	return nil
+}
+
+func (x *GetStreamsBatchResponse) GetMissingIds() []string {
+	if x != nil {
+		return x.MissingIds
+	}
+	return nil
+}
+
+// GetActiveStreams only lists public streams
+type GetActiveStreamsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Limit         int32                  `protobuf:"varint,1,opt,name=limit,proto3" json:"limit,omitempty"`
+	Cursor        string                 `protobuf:"bytes,2,opt,name=cursor,proto3" json:"cursor,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetActiveStreamsRequest) Reset() {
+	*x = GetActiveStreamsRequest{}
+	mi := &file_stream_stream_service_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetActiveStreamsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetActiveStreamsRequest) ProtoMessage() {}
+
+func (x *GetActiveStreamsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_stream_stream_service_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetActiveStreamsRequest.ProtoReflect.Descriptor instead.
+func (*GetActiveStreamsRequest) Descriptor() ([]byte, []int) {
+	return file_stream_stream_service_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *GetActiveStreamsRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *GetActiveStreamsRequest) GetCursor() string {
+	if x != nil {
+		return x.Cursor
+	}
+	return ""
+}
+
+type GetActiveStreamsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Status        *common.Status         `protobuf:"bytes,1,opt,name=status,proto3" json:"status,omitempty"`
+	Streams       []*Stream              `protobuf:"bytes,2,rep,name=streams,proto3" json:"streams,omitempty"`
+	NextCursor    string                 `protobuf:"bytes,3,opt,name=next_cursor,json=nextCursor,proto3" json:"next_cursor,omitempty"`
+	TotalCount    int32                  `protobuf:"varint,4,opt,name=total_count,json=totalCount,proto3" json:"total_count,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetActiveStreamsResponse) Reset() {
+	*x = GetActiveStreamsResponse{}
+	mi := &file_stream_stream_service_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetActiveStreamsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetActiveStreamsResponse) ProtoMessage() {}
+
+func (x *GetActiveStreamsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_stream_stream_service_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetActiveStreamsResponse.ProtoReflect.Descriptor instead.
+func (*GetActiveStreamsResponse) Descriptor() ([]byte, []int) {
+	return file_stream_stream_service_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *GetActiveStreamsResponse) GetStatus() *common.Status {
+	if x != nil {
+		return x.Status
+	}
+	return nil
+}
+
+func (x *GetActiveStreamsResponse) GetStreams() []*Stream {
+	if x != nil {
+		return x.Streams
+	}
+	return nil
+}
+
+func (x *GetActiveStreamsResponse) GetNextCursor() string {
+	if x != nil {
+		return x.NextCursor
+	}
+	return ""
+}
+
+func (x *GetActiveStreamsResponse) GetTotalCount() int32 {
+	if x != nil {
+		return x.TotalCount
+	}
+	return 0
+}
+
+// SearchStreams filters and sorts the listed live streams, the same search as
+// GET /api/v1/streams/search
+type SearchStreamsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Query         string                 `protobuf:"bytes,1,opt,name=query,proto3" json:"query,omitempty"` // matched against title, category and tags
+	Category      string                 `protobuf:"bytes,2,opt,name=category,proto3" json:"category,omitempty"`
+	MinViewers    int32                  `protobuf:"varint,3,opt,name=min_viewers,json=minViewers,proto3" json:"min_viewers,omitempty"`
+	MaxViewers    int32                  `protobuf:"varint,4,opt,name=max_viewers,json=maxViewers,proto3" json:"max_viewers,omitempty"` // 0 for no upper bound
+	Sort          string                 `protobuf:"bytes,5,opt,name=sort,proto3" json:"sort,omitempty"`                                // viewers (default) or recent
+	Limit         int32                  `protobuf:"varint,6,opt,name=limit,proto3" json:"limit,omitempty"`
+	Cursor        string                 `protobuf:"bytes,7,opt,name=cursor,proto3" json:"cursor,omitempty"`
+	Language      string                 `protobuf:"bytes,8,opt,name=language,proto3" json:"language,omitempty"` // BCP-47, "en" also matches "en-US"
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SearchStreamsRequest) Reset() {
+	*x = SearchStreamsRequest{}
+	mi := &file_stream_stream_service_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SearchStreamsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SearchStreamsRequest) ProtoMessage() {}
+
+func (x *SearchStreamsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_stream_stream_service_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SearchStreamsRequest.ProtoReflect.Descriptor instead.
+func (*SearchStreamsRequest) Descriptor() ([]byte, []int) {
+	return file_stream_stream_service_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *SearchStreamsRequest) GetQuery() string {
+	if x != nil {
+		return x.Query
+	}
+	return ""
+}
+
+func (x *SearchStreamsRequest) GetCategory() string {
+	if x != nil {
+		return x.Category
+	}
+	return ""
+}
+
+func (x *SearchStreamsRequest) GetMinViewers() int32 {
+	if x != nil {
+		return x.MinViewers
+	}
+	return 0
+}
+
+func (x *SearchStreamsRequest) GetMaxViewers() int32 {
+	if x != nil {
+		return x.MaxViewers
+	}
+	return 0
+}
+
+func (x *SearchStreamsRequest) GetSort() string {
+	if x != nil {
+		return x.Sort
+	}
+	return ""
+}
+
+func (x *SearchStreamsRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *SearchStreamsRequest) GetCursor() string {
+	if x != nil {
+		return x.Cursor
+	}
+	return ""
+}
+
+func (x *SearchStreamsRequest) GetLanguage() string {
+	if x != nil {
+		return x.Language
+	}
+	return ""
+}
+
+type SearchStreamsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Status        *common.Status         `protobuf:"bytes,1,opt,name=status,proto3" json:"status,omitempty"`
+	Streams       []*Stream              `protobuf:"bytes,2,rep,name=streams,proto3" json:"streams,omitempty"`
+	NextCursor    string                 `protobuf:"bytes,3,opt,name=next_cursor,json=nextCursor,proto3" json:"next_cursor,omitempty"`
+	TotalCount    int32                  `protobuf:"varint,4,opt,name=total_count,json=totalCount,proto3" json:"total_count,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SearchStreamsResponse) Reset() {
+	*x = SearchStreamsResponse{}
+	mi := &file_stream_stream_service_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SearchStreamsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SearchStreamsResponse) ProtoMessage() {}
+
+func (x *SearchStreamsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_stream_stream_service_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SearchStreamsResponse.ProtoReflect.Descriptor instead.
+func (*SearchStreamsResponse) Descriptor() ([]byte, []int) {
+	return file_stream_stream_service_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *SearchStreamsResponse) GetStatus() *common.Status {
+	if x != nil {
+		return x.Status
+	}
+	return nil
+}
+
+func (x *SearchStreamsResponse) GetStreams() []*Stream {
+	if x != nil {
+		return x.Streams
+	}
+	return nil
+}
+
+func (x *SearchStreamsResponse) GetNextCursor() string {
+	if x != nil {
+		return x.NextCursor
+	}
+	return ""
+}
+
+func (x *SearchStreamsResponse) GetTotalCount() int32 {
+	if x != nil {
+		return x.TotalCount
+	}
+	return 0
+}
+
+type EndStreamRequest struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	StreamId        string                 `protobuf:"bytes,1,opt,name=stream_id,json=streamId,proto3" json:"stream_id,omitempty"`
+	DurationSeconds int64                  `protobuf:"varint,2,opt,name=duration_seconds,json=durationSeconds,proto3" json:"duration_seconds,omitempty"`
+	RecordingPath   string                 `protobuf:"bytes,3,opt,name=recording_path,json=recordingPath,proto3" json:"recording_path,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *EndStreamRequest) Reset() {
+	*x = EndStreamRequest{}
+	mi := &file_stream_stream_service_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EndStreamRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EndStreamRequest) ProtoMessage() {}
+
+func (x *EndStreamRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_stream_stream_service_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EndStreamRequest.ProtoReflect.Descriptor instead.
+func (*EndStreamRequest) Descriptor() ([]byte, []int) {
+	return file_stream_stream_service_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *EndStreamRequest) GetStreamId() string {
+	if x != nil {
+		return x.StreamId
+	}
+	return ""
+}
+
+func (x *EndStreamRequest) GetDurationSeconds() int64 {
+	if x != nil {
+		return x.DurationSeconds
+	}
+	return 0
+}
+
+func (x *EndStreamRequest) GetRecordingPath() string {
+	if x != nil {
+		return x.RecordingPath
+	}
+	return ""
+}
+
+type EndStreamResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Status        *common.Status         `protobuf:"bytes,1,opt,name=status,proto3" json:"status,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *EndStreamResponse) Reset() {
+	*x = EndStreamResponse{}
+	mi := &file_stream_stream_service_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EndStreamResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EndStreamResponse) ProtoMessage() {}
+
+func (x *EndStreamResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_stream_stream_service_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EndStreamResponse.ProtoReflect.Descriptor instead.
+func (*EndStreamResponse) Descriptor() ([]byte, []int) {
+	return file_stream_stream_service_proto_rawDescGZIP(), []int{20}
 }
 
 func (x *EndStreamResponse) GetStatus() *common.Status {
@@ -879,21 +1508,332 @@ type RecordingCompletedRequest struct {
 	sizeCache       protoimpl.SizeCache
 }
 
-func (x *RecordingCompletedRequest) Reset() {
-	*x = RecordingCompletedRequest{}
-	mi := &file_stream_stream_service_proto_msgTypes[13]
+func (x *RecordingCompletedRequest) Reset() {
+	*x = RecordingCompletedRequest{}
+	mi := &file_stream_stream_service_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RecordingCompletedRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RecordingCompletedRequest) ProtoMessage() {}
+
+func (x *RecordingCompletedRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_stream_stream_service_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RecordingCompletedRequest.ProtoReflect.Descriptor instead.
+func (*RecordingCompletedRequest) Descriptor() ([]byte, []int) {
+	return file_stream_stream_service_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *RecordingCompletedRequest) GetStreamId() string {
+	if x != nil {
+		return x.StreamId
+	}
+	return ""
+}
+
+func (x *RecordingCompletedRequest) GetRecordingPath() string {
+	if x != nil {
+		return x.RecordingPath
+	}
+	return ""
+}
+
+func (x *RecordingCompletedRequest) GetFileSizeBytes() int64 {
+	if x != nil {
+		return x.FileSizeBytes
+	}
+	return 0
+}
+
+func (x *RecordingCompletedRequest) GetDurationSeconds() int64 {
+	if x != nil {
+		return x.DurationSeconds
+	}
+	return 0
+}
+
+type RecordingCompletedResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Status        *common.Status         `protobuf:"bytes,1,opt,name=status,proto3" json:"status,omitempty"`
+	RecordingUrl  string                 `protobuf:"bytes,2,opt,name=recording_url,json=recordingUrl,proto3" json:"recording_url,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RecordingCompletedResponse) Reset() {
+	*x = RecordingCompletedResponse{}
+	mi := &file_stream_stream_service_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RecordingCompletedResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RecordingCompletedResponse) ProtoMessage() {}
+
+func (x *RecordingCompletedResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_stream_stream_service_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RecordingCompletedResponse.ProtoReflect.Descriptor instead.
+func (*RecordingCompletedResponse) Descriptor() ([]byte, []int) {
+	return file_stream_stream_service_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *RecordingCompletedResponse) GetStatus() *common.Status {
+	if x != nil {
+		return x.Status
+	}
+	return nil
+}
+
+func (x *RecordingCompletedResponse) GetRecordingUrl() string {
+	if x != nil {
+		return x.RecordingUrl
+	}
+	return ""
+}
+
+// Stream health (reported periodically by media server)
+type ReportStreamHealthRequest struct {
+	state                   protoimpl.MessageState `protogen:"open.v1"`
+	StreamId                string                 `protobuf:"bytes,1,opt,name=stream_id,json=streamId,proto3" json:"stream_id,omitempty"`
+	StreamKey               string                 `protobuf:"bytes,2,opt,name=stream_key,json=streamKey,proto3" json:"stream_key,omitempty"`
+	BitrateKbps             int32                  `protobuf:"varint,3,opt,name=bitrate_kbps,json=bitrateKbps,proto3" json:"bitrate_kbps,omitempty"`
+	Fps                     float64                `protobuf:"fixed64,4,opt,name=fps,proto3" json:"fps,omitempty"`
+	DroppedFrames           int64                  `protobuf:"varint,5,opt,name=dropped_frames,json=droppedFrames,proto3" json:"dropped_frames,omitempty"`
+	KeyframeIntervalSeconds float64                `protobuf:"fixed64,6,opt,name=keyframe_interval_seconds,json=keyframeIntervalSeconds,proto3" json:"keyframe_interval_seconds,omitempty"`
+	unknownFields           protoimpl.UnknownFields
+	sizeCache               protoimpl.SizeCache
+}
+
+func (x *ReportStreamHealthRequest) Reset() {
+	*x = ReportStreamHealthRequest{}
+	mi := &file_stream_stream_service_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReportStreamHealthRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReportStreamHealthRequest) ProtoMessage() {}
+
+func (x *ReportStreamHealthRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_stream_stream_service_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReportStreamHealthRequest.ProtoReflect.Descriptor instead.
+func (*ReportStreamHealthRequest) Descriptor() ([]byte, []int) {
+	return file_stream_stream_service_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *ReportStreamHealthRequest) GetStreamId() string {
+	if x != nil {
+		return x.StreamId
+	}
+	return ""
+}
+
+func (x *ReportStreamHealthRequest) GetStreamKey() string {
+	if x != nil {
+		return x.StreamKey
+	}
+	return ""
+}
+
+func (x *ReportStreamHealthRequest) GetBitrateKbps() int32 {
+	if x != nil {
+		return x.BitrateKbps
+	}
+	return 0
+}
+
+func (x *ReportStreamHealthRequest) GetFps() float64 {
+	if x != nil {
+		return x.Fps
+	}
+	return 0
+}
+
+func (x *ReportStreamHealthRequest) GetDroppedFrames() int64 {
+	if x != nil {
+		return x.DroppedFrames
+	}
+	return 0
+}
+
+func (x *ReportStreamHealthRequest) GetKeyframeIntervalSeconds() float64 {
+	if x != nil {
+		return x.KeyframeIntervalSeconds
+	}
+	return 0
+}
+
+type ReportStreamHealthResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Status        *common.Status         `protobuf:"bytes,1,opt,name=status,proto3" json:"status,omitempty"`
+	Health        *StreamHealth          `protobuf:"bytes,2,opt,name=health,proto3" json:"health,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReportStreamHealthResponse) Reset() {
+	*x = ReportStreamHealthResponse{}
+	mi := &file_stream_stream_service_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReportStreamHealthResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReportStreamHealthResponse) ProtoMessage() {}
+
+func (x *ReportStreamHealthResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_stream_stream_service_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReportStreamHealthResponse.ProtoReflect.Descriptor instead.
+func (*ReportStreamHealthResponse) Descriptor() ([]byte, []int) {
+	return file_stream_stream_service_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *ReportStreamHealthResponse) GetStatus() *common.Status {
+	if x != nil {
+		return x.Status
+	}
+	return nil
+}
+
+func (x *ReportStreamHealthResponse) GetHealth() *StreamHealth {
+	if x != nil {
+		return x.Health
+	}
+	return nil
+}
+
+// Stream key generation (called by user service). Generated keys are signed by this service
+// and validated without a user service round trip.
+type GenerateStreamKeyRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        int64                  `protobuf:"varint,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	TtlSeconds    int64                  `protobuf:"varint,2,opt,name=ttl_seconds,json=ttlSeconds,proto3" json:"ttl_seconds,omitempty"` // 0 uses the service default
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GenerateStreamKeyRequest) Reset() {
+	*x = GenerateStreamKeyRequest{}
+	mi := &file_stream_stream_service_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GenerateStreamKeyRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GenerateStreamKeyRequest) ProtoMessage() {}
+
+func (x *GenerateStreamKeyRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_stream_stream_service_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GenerateStreamKeyRequest.ProtoReflect.Descriptor instead.
+func (*GenerateStreamKeyRequest) Descriptor() ([]byte, []int) {
+	return file_stream_stream_service_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *GenerateStreamKeyRequest) GetUserId() int64 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+func (x *GenerateStreamKeyRequest) GetTtlSeconds() int64 {
+	if x != nil {
+		return x.TtlSeconds
+	}
+	return 0
+}
+
+type GenerateStreamKeyResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Status        *common.Status         `protobuf:"bytes,1,opt,name=status,proto3" json:"status,omitempty"`
+	StreamKey     string                 `protobuf:"bytes,2,opt,name=stream_key,json=streamKey,proto3" json:"stream_key,omitempty"`
+	KeyId         string                 `protobuf:"bytes,3,opt,name=key_id,json=keyId,proto3" json:"key_id,omitempty"`
+	ExpiresAt     *common.Timestamp      `protobuf:"bytes,4,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"` // unset for keys that never expire
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GenerateStreamKeyResponse) Reset() {
+	*x = GenerateStreamKeyResponse{}
+	mi := &file_stream_stream_service_proto_msgTypes[26]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *RecordingCompletedRequest) String() string {
+func (x *GenerateStreamKeyResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*RecordingCompletedRequest) ProtoMessage() {}
+func (*GenerateStreamKeyResponse) ProtoMessage() {}
 
-func (x *RecordingCompletedRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_stream_stream_service_proto_msgTypes[13]
+func (x *GenerateStreamKeyResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_stream_stream_service_proto_msgTypes[26]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -904,62 +1844,106 @@ func (x *RecordingCompletedRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use RecordingCompletedRequest.ProtoReflect.Descriptor instead.
-func (*RecordingCompletedRequest) Descriptor() ([]byte, []int) {
-	return file_stream_stream_service_proto_rawDescGZIP(), []int{13}
+// Deprecated: Use GenerateStreamKeyResponse.ProtoReflect.Descriptor instead.
+func (*GenerateStreamKeyResponse) Descriptor() ([]byte, []int) {
+	return file_stream_stream_service_proto_rawDescGZIP(), []int{26}
 }
 
-func (x *RecordingCompletedRequest) GetStreamId() string {
+func (x *GenerateStreamKeyResponse) GetStatus() *common.Status {
 	if x != nil {
-		return x.StreamId
+		return x.Status
+	}
+	return nil
+}
+
+func (x *GenerateStreamKeyResponse) GetStreamKey() string {
+	if x != nil {
+		return x.StreamKey
 	}
 	return ""
 }
 
-func (x *RecordingCompletedRequest) GetRecordingPath() string {
+func (x *GenerateStreamKeyResponse) GetKeyId() string {
 	if x != nil {
-		return x.RecordingPath
+		return x.KeyId
 	}
 	return ""
 }
 
-func (x *RecordingCompletedRequest) GetFileSizeBytes() int64 {
+func (x *GenerateStreamKeyResponse) GetExpiresAt() *common.Timestamp {
 	if x != nil {
-		return x.FileSizeBytes
+		return x.ExpiresAt
 	}
-	return 0
+	return nil
 }
 
-func (x *RecordingCompletedRequest) GetDurationSeconds() int64 {
+type RevokeStreamKeyRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	StreamKey     string                 `protobuf:"bytes,1,opt,name=stream_key,json=streamKey,proto3" json:"stream_key,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RevokeStreamKeyRequest) Reset() {
+	*x = RevokeStreamKeyRequest{}
+	mi := &file_stream_stream_service_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RevokeStreamKeyRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RevokeStreamKeyRequest) ProtoMessage() {}
+
+func (x *RevokeStreamKeyRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_stream_stream_service_proto_msgTypes[27]
 	if x != nil {
-		return x.DurationSeconds
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
 	}
-	return 0
+	return mi.MessageOf(x)
 }
 
-type RecordingCompletedResponse struct {
+// Deprecated: Use RevokeStreamKeyRequest.ProtoReflect.Descriptor instead.
+func (*RevokeStreamKeyRequest) Descriptor() ([]byte, []int) {
+	return file_stream_stream_service_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *RevokeStreamKeyRequest) GetStreamKey() string {
+	if x != nil {
+		return x.StreamKey
+	}
+	return ""
+}
+
+type RevokeStreamKeyResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Status        *common.Status         `protobuf:"bytes,1,opt,name=status,proto3" json:"status,omitempty"`
-	RecordingUrl  string                 `protobuf:"bytes,2,opt,name=recording_url,json=recordingUrl,proto3" json:"recording_url,omitempty"`
+	KeyId         string                 `protobuf:"bytes,2,opt,name=key_id,json=keyId,proto3" json:"key_id,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *RecordingCompletedResponse) Reset() {
-	*x = RecordingCompletedResponse{}
-	mi := &file_stream_stream_service_proto_msgTypes[14]
+func (x *RevokeStreamKeyResponse) Reset() {
+	*x = RevokeStreamKeyResponse{}
+	mi := &file_stream_stream_service_proto_msgTypes[28]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *RecordingCompletedResponse) String() string {
+func (x *RevokeStreamKeyResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*RecordingCompletedResponse) ProtoMessage() {}
+func (*RevokeStreamKeyResponse) ProtoMessage() {}
 
-func (x *RecordingCompletedResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_stream_stream_service_proto_msgTypes[14]
+func (x *RevokeStreamKeyResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_stream_stream_service_proto_msgTypes[28]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -970,21 +1954,21 @@ func (x *RecordingCompletedResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use RecordingCompletedResponse.ProtoReflect.Descriptor instead.
-func (*RecordingCompletedResponse) Descriptor() ([]byte, []int) {
-	return file_stream_stream_service_proto_rawDescGZIP(), []int{14}
+// Deprecated: Use RevokeStreamKeyResponse.ProtoReflect.Descriptor instead.
+func (*RevokeStreamKeyResponse) Descriptor() ([]byte, []int) {
+	return file_stream_stream_service_proto_rawDescGZIP(), []int{28}
 }
 
-func (x *RecordingCompletedResponse) GetStatus() *common.Status {
+func (x *RevokeStreamKeyResponse) GetStatus() *common.Status {
 	if x != nil {
 		return x.Status
 	}
 	return nil
 }
 
-func (x *RecordingCompletedResponse) GetRecordingUrl() string {
+func (x *RevokeStreamKeyResponse) GetKeyId() string {
 	if x != nil {
-		return x.RecordingUrl
+		return x.KeyId
 	}
 	return ""
 }
@@ -1006,13 +1990,19 @@ type Stream struct {
 	Metadata        *StreamMetadata        `protobuf:"bytes,12,opt,name=metadata,proto3" json:"metadata,omitempty"`
 	CreatedAt       *common.Timestamp      `protobuf:"bytes,13,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
 	UpdatedAt       *common.Timestamp      `protobuf:"bytes,14,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	Health          *StreamHealth          `protobuf:"bytes,15,opt,name=health,proto3" json:"health,omitempty"`
+	IsMature        bool                   `protobuf:"varint,16,opt,name=is_mature,json=isMature,proto3" json:"is_mature,omitempty"`
+	Visibility      string                 `protobuf:"bytes,17,opt,name=visibility,proto3" json:"visibility,omitempty"` // public, unlisted or private
+	Language        string                 `protobuf:"bytes,18,opt,name=language,proto3" json:"language,omitempty"`     // BCP-47, empty when the broadcaster never set it
+	Raid            *Raid                  `protobuf:"bytes,19,opt,name=raid,proto3" json:"raid,omitempty"`             // set once the stream sent its viewers on, players follow it when the stream ends
+	RaidsReceived   int32                  `protobuf:"varint,20,opt,name=raids_received,json=raidsReceived,proto3" json:"raids_received,omitempty"`
 	unknownFields   protoimpl.UnknownFields
 	sizeCache       protoimpl.SizeCache
 }
 
 func (x *Stream) Reset() {
 	*x = Stream{}
-	mi := &file_stream_stream_service_proto_msgTypes[15]
+	mi := &file_stream_stream_service_proto_msgTypes[29]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1024,7 +2014,7 @@ func (x *Stream) String() string {
 func (*Stream) ProtoMessage() {}
 
 func (x *Stream) ProtoReflect() protoreflect.Message {
-	mi := &file_stream_stream_service_proto_msgTypes[15]
+	mi := &file_stream_stream_service_proto_msgTypes[29]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1037,7 +2027,7 @@ func (x *Stream) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Stream.ProtoReflect.Descriptor instead.
 func (*Stream) Descriptor() ([]byte, []int) {
-	return file_stream_stream_service_proto_rawDescGZIP(), []int{15}
+	return file_stream_stream_service_proto_rawDescGZIP(), []int{29}
 }
 
 func (x *Stream) GetId() string {
@@ -1138,6 +2128,141 @@ func (x *Stream) GetUpdatedAt() *common.Timestamp {
 	return nil
 }
 
+func (x *Stream) GetHealth() *StreamHealth {
+	if x != nil {
+		return x.Health
+	}
+	return nil
+}
+
+func (x *Stream) GetIsMature() bool {
+	if x != nil {
+		return x.IsMature
+	}
+	return false
+}
+
+func (x *Stream) GetVisibility() string {
+	if x != nil {
+		return x.Visibility
+	}
+	return ""
+}
+
+func (x *Stream) GetLanguage() string {
+	if x != nil {
+		return x.Language
+	}
+	return ""
+}
+
+func (x *Stream) GetRaid() *Raid {
+	if x != nil {
+		return x.Raid
+	}
+	return nil
+}
+
+func (x *Stream) GetRaidsReceived() int32 {
+	if x != nil {
+		return x.RaidsReceived
+	}
+	return 0
+}
+
+// Raid sends a live stream's viewers over to another live stream
+type Raid struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	FromStreamId  string                 `protobuf:"bytes,2,opt,name=from_stream_id,json=fromStreamId,proto3" json:"from_stream_id,omitempty"`
+	FromUserId    int64                  `protobuf:"varint,3,opt,name=from_user_id,json=fromUserId,proto3" json:"from_user_id,omitempty"`
+	ToStreamId    string                 `protobuf:"bytes,4,opt,name=to_stream_id,json=toStreamId,proto3" json:"to_stream_id,omitempty"`
+	ToUserId      int64                  `protobuf:"varint,5,opt,name=to_user_id,json=toUserId,proto3" json:"to_user_id,omitempty"`
+	ViewerCount   int64                  `protobuf:"varint,6,opt,name=viewer_count,json=viewerCount,proto3" json:"viewer_count,omitempty"` // viewers of the raiding stream when it started
+	CreatedAt     *common.Timestamp      `protobuf:"bytes,7,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Raid) Reset() {
+	*x = Raid{}
+	mi := &file_stream_stream_service_proto_msgTypes[30]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Raid) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Raid) ProtoMessage() {}
+
+func (x *Raid) ProtoReflect() protoreflect.Message {
+	mi := &file_stream_stream_service_proto_msgTypes[30]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Raid.ProtoReflect.Descriptor instead.
+func (*Raid) Descriptor() ([]byte, []int) {
+	return file_stream_stream_service_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *Raid) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Raid) GetFromStreamId() string {
+	if x != nil {
+		return x.FromStreamId
+	}
+	return ""
+}
+
+func (x *Raid) GetFromUserId() int64 {
+	if x != nil {
+		return x.FromUserId
+	}
+	return 0
+}
+
+func (x *Raid) GetToStreamId() string {
+	if x != nil {
+		return x.ToStreamId
+	}
+	return ""
+}
+
+func (x *Raid) GetToUserId() int64 {
+	if x != nil {
+		return x.ToUserId
+	}
+	return 0
+}
+
+func (x *Raid) GetViewerCount() int64 {
+	if x != nil {
+		return x.ViewerCount
+	}
+	return 0
+}
+
+func (x *Raid) GetCreatedAt() *common.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
 type StreamMetadata struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Resolution    string                 `protobuf:"bytes,1,opt,name=resolution,proto3" json:"resolution,omitempty"`
@@ -1153,7 +2278,7 @@ type StreamMetadata struct {
 
 func (x *StreamMetadata) Reset() {
 	*x = StreamMetadata{}
-	mi := &file_stream_stream_service_proto_msgTypes[16]
+	mi := &file_stream_stream_service_proto_msgTypes[31]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1165,7 +2290,7 @@ func (x *StreamMetadata) String() string {
 func (*StreamMetadata) ProtoMessage() {}
 
 func (x *StreamMetadata) ProtoReflect() protoreflect.Message {
-	mi := &file_stream_stream_service_proto_msgTypes[16]
+	mi := &file_stream_stream_service_proto_msgTypes[31]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1178,7 +2303,7 @@ func (x *StreamMetadata) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use StreamMetadata.ProtoReflect.Descriptor instead.
 func (*StreamMetadata) Descriptor() ([]byte, []int) {
-	return file_stream_stream_service_proto_rawDescGZIP(), []int{16}
+	return file_stream_stream_service_proto_rawDescGZIP(), []int{31}
 }
 
 func (x *StreamMetadata) GetResolution() string {
@@ -1230,6 +2355,106 @@ func (x *StreamMetadata) GetCustomData() map[string]string {
 	return nil
 }
 
+type StreamHealth struct {
+	state                   protoimpl.MessageState `protogen:"open.v1"`
+	Status                  HealthStatus           `protobuf:"varint,1,opt,name=status,proto3,enum=stream.HealthStatus" json:"status,omitempty"`
+	Reasons                 []string               `protobuf:"bytes,2,rep,name=reasons,proto3" json:"reasons,omitempty"`
+	AvgBitrateKbps          int32                  `protobuf:"varint,3,opt,name=avg_bitrate_kbps,json=avgBitrateKbps,proto3" json:"avg_bitrate_kbps,omitempty"`
+	AvgFps                  float64                `protobuf:"fixed64,4,opt,name=avg_fps,json=avgFps,proto3" json:"avg_fps,omitempty"`
+	DroppedFrames           int64                  `protobuf:"varint,5,opt,name=dropped_frames,json=droppedFrames,proto3" json:"dropped_frames,omitempty"`
+	KeyframeIntervalSeconds float64                `protobuf:"fixed64,6,opt,name=keyframe_interval_seconds,json=keyframeIntervalSeconds,proto3" json:"keyframe_interval_seconds,omitempty"`
+	SampleCount             int32                  `protobuf:"varint,7,opt,name=sample_count,json=sampleCount,proto3" json:"sample_count,omitempty"`
+	UpdatedAt               *common.Timestamp      `protobuf:"bytes,8,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	unknownFields           protoimpl.UnknownFields
+	sizeCache               protoimpl.SizeCache
+}
+
+func (x *StreamHealth) Reset() {
+	*x = StreamHealth{}
+	mi := &file_stream_stream_service_proto_msgTypes[32]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StreamHealth) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StreamHealth) ProtoMessage() {}
+
+func (x *StreamHealth) ProtoReflect() protoreflect.Message {
+	mi := &file_stream_stream_service_proto_msgTypes[32]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StreamHealth.ProtoReflect.Descriptor instead.
+func (*StreamHealth) Descriptor() ([]byte, []int) {
+	return file_stream_stream_service_proto_rawDescGZIP(), []int{32}
+}
+
+func (x *StreamHealth) GetStatus() HealthStatus {
+	if x != nil {
+		return x.Status
+	}
+	return HealthStatus_HEALTH_UNKNOWN
+}
+
+func (x *StreamHealth) GetReasons() []string {
+	if x != nil {
+		return x.Reasons
+	}
+	return nil
+}
+
+func (x *StreamHealth) GetAvgBitrateKbps() int32 {
+	if x != nil {
+		return x.AvgBitrateKbps
+	}
+	return 0
+}
+
+func (x *StreamHealth) GetAvgFps() float64 {
+	if x != nil {
+		return x.AvgFps
+	}
+	return 0
+}
+
+func (x *StreamHealth) GetDroppedFrames() int64 {
+	if x != nil {
+		return x.DroppedFrames
+	}
+	return 0
+}
+
+func (x *StreamHealth) GetKeyframeIntervalSeconds() float64 {
+	if x != nil {
+		return x.KeyframeIntervalSeconds
+	}
+	return 0
+}
+
+func (x *StreamHealth) GetSampleCount() int32 {
+	if x != nil {
+		return x.SampleCount
+	}
+	return 0
+}
+
+func (x *StreamHealth) GetUpdatedAt() *common.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
 var File_stream_stream_service_proto protoreflect.FileDescriptor
 
 const file_stream_stream_service_proto_rawDesc = "" +
@@ -1240,13 +2465,15 @@ const file_stream_stream_service_proto_rawDesc = "" +
 	"stream_key\x18\x01 \x01(\tR\tstreamKey\x12\x1d\n" +
 	"\n" +
 	"ip_address\x18\x02 \x01(\tR\tipAddress\x12\x19\n" +
-	"\bapp_name\x18\x03 \x01(\tR\aappName\"\xd0\x01\n" +
+	"\bapp_name\x18\x03 \x01(\tR\aappName\"\xb3\x02\n" +
 	"\x19ValidateStreamKeyResponse\x12&\n" +
 	"\x06status\x18\x01 \x01(\v2\x0e.common.StatusR\x06status\x12\x19\n" +
 	"\bis_valid\x18\x02 \x01(\bR\aisValid\x12\x17\n" +
 	"\auser_id\x18\x03 \x01(\x03R\x06userId\x12\x1a\n" +
 	"\busername\x18\x04 \x01(\tR\busername\x12;\n" +
-	"\vpermissions\x18\x05 \x01(\v2\x19.stream.StreamPermissionsR\vpermissions\"\xa4\x01\n" +
+	"\vpermissions\x18\x05 \x01(\v2\x19.stream.StreamPermissionsR\vpermissions\x12>\n" +
+	"\x0equality_ladder\x18\x06 \x03(\v2\x17.stream.LadderRenditionR\rqualityLadder\x12!\n" +
+	"\fquality_tier\x18\a \x01(\tR\vqualityTier\"\xa4\x01\n" +
 	"\x11StreamPermissions\x12\x1d\n" +
 	"\n" +
 	"can_stream\x18\x01 \x01(\bR\tcanStream\x12\x1d\n" +
@@ -1254,14 +2481,21 @@ const file_stream_stream_service_proto_rawDesc = "" +
 	"can_record\x18\x02 \x01(\bR\tcanRecord\x12\x1f\n" +
 	"\vmax_bitrate\x18\x03 \x01(\x05R\n" +
 	"maxBitrate\x120\n" +
-	"\x14max_duration_minutes\x18\x04 \x01(\x05R\x12maxDurationMinutes\"\xb9\x01\n" +
+	"\x14max_duration_minutes\x18\x04 \x01(\x05R\x12maxDurationMinutes\"\x88\x01\n" +
+	"\x0fLadderRendition\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x14\n" +
+	"\x05width\x18\x02 \x01(\x05R\x05width\x12\x16\n" +
+	"\x06height\x18\x03 \x01(\x05R\x06height\x12!\n" +
+	"\fbitrate_kbps\x18\x04 \x01(\x05R\vbitrateKbps\x12\x10\n" +
+	"\x03fps\x18\x05 \x01(\x05R\x03fps\"\xd5\x01\n" +
 	"\x13CreateStreamRequest\x12\x17\n" +
 	"\auser_id\x18\x01 \x01(\x03R\x06userId\x12\x1d\n" +
 	"\n" +
 	"stream_key\x18\x02 \x01(\tR\tstreamKey\x12\x14\n" +
 	"\x05title\x18\x03 \x01(\tR\x05title\x12 \n" +
 	"\vdescription\x18\x04 \x01(\tR\vdescription\x122\n" +
-	"\bmetadata\x18\x05 \x01(\v2\x16.stream.StreamMetadataR\bmetadata\"\x83\x01\n" +
+	"\bmetadata\x18\x05 \x01(\v2\x16.stream.StreamMetadataR\bmetadata\x12\x1a\n" +
+	"\blanguage\x18\x06 \x01(\tR\blanguage\"\x83\x01\n" +
 	"\x14CreateStreamResponse\x12&\n" +
 	"\x06status\x18\x01 \x01(\v2\x0e.common.StatusR\x06status\x12\x1b\n" +
 	"\tstream_id\x18\x02 \x01(\tR\bstreamId\x12&\n" +
@@ -1279,7 +2513,31 @@ const file_stream_stream_service_proto_rawDesc = "" +
 	"\tstream_id\x18\x01 \x01(\tR\bstreamId\"c\n" +
 	"\x11GetStreamResponse\x12&\n" +
 	"\x06status\x18\x01 \x01(\v2\x0e.common.StatusR\x06status\x12&\n" +
-	"\x06stream\x18\x02 \x01(\v2\x0e.stream.StreamR\x06stream\"G\n" +
+	"\x06stream\x18\x02 \x01(\v2\x0e.stream.StreamR\x06stream\"6\n" +
+	"\x15GetStreamByKeyRequest\x12\x1d\n" +
+	"\n" +
+	"stream_key\x18\x01 \x01(\tR\tstreamKey\"\x99\x01\n" +
+	"\x16GetStreamByKeyResponse\x12&\n" +
+	"\x06status\x18\x01 \x01(\v2\x0e.common.StatusR\x06status\x12&\n" +
+	"\x06stream\x18\x02 \x01(\v2\x0e.stream.StreamR\x06stream\x12/\n" +
+	"\asession\x18\x03 \x01(\v2\x15.stream.StreamSessionR\asession\"\x9f\x02\n" +
+	"\rStreamSession\x12\x1b\n" +
+	"\tclient_id\x18\x01 \x01(\tR\bclientId\x12\x1b\n" +
+	"\tclient_ip\x18\x02 \x01(\tR\bclientIp\x12\x19\n" +
+	"\bapp_name\x18\x03 \x01(\tR\aappName\x12'\n" +
+	"\x0fingest_protocol\x18\x04 \x01(\tR\x0eingestProtocol\x120\n" +
+	"\n" +
+	"started_at\x18\x05 \x01(\v2\x11.common.TimestampR\tstartedAt\x12\"\n" +
+	"\freconnecting\x18\x06 \x01(\bR\freconnecting\x12:\n" +
+	"\x0fdisconnected_at\x18\a \x01(\v2\x11.common.TimestampR\x0edisconnectedAt\"7\n" +
+	"\x16GetStreamsBatchRequest\x12\x1d\n" +
+	"\n" +
+	"stream_ids\x18\x01 \x03(\tR\tstreamIds\"\x8c\x01\n" +
+	"\x17GetStreamsBatchResponse\x12&\n" +
+	"\x06status\x18\x01 \x01(\v2\x0e.common.StatusR\x06status\x12(\n" +
+	"\astreams\x18\x02 \x03(\v2\x0e.stream.StreamR\astreams\x12\x1f\n" +
+	"\vmissing_ids\x18\x03 \x03(\tR\n" +
+	"missingIds\"G\n" +
 	"\x17GetActiveStreamsRequest\x12\x14\n" +
 	"\x05limit\x18\x01 \x01(\x05R\x05limit\x12\x16\n" +
 	"\x06cursor\x18\x02 \x01(\tR\x06cursor\"\xae\x01\n" +
@@ -1289,6 +2547,24 @@ const file_stream_stream_service_proto_rawDesc = "" +
 	"\vnext_cursor\x18\x03 \x01(\tR\n" +
 	"nextCursor\x12\x1f\n" +
 	"\vtotal_count\x18\x04 \x01(\x05R\n" +
+	"totalCount\"\xe8\x01\n" +
+	"\x14SearchStreamsRequest\x12\x14\n" +
+	"\x05query\x18\x01 \x01(\tR\x05query\x12\x1a\n" +
+	"\bcategory\x18\x02 \x01(\tR\bcategory\x12\x1f\n" +
+	"\vmin_viewers\x18\x03 \x01(\x05R\n" +
+	"minViewers\x12\x1f\n" +
+	"\vmax_viewers\x18\x04 \x01(\x05R\n" +
+	"maxViewers\x12\x12\n" +
+	"\x04sort\x18\x05 \x01(\tR\x04sort\x12\x14\n" +
+	"\x05limit\x18\x06 \x01(\x05R\x05limit\x12\x16\n" +
+	"\x06cursor\x18\a \x01(\tR\x06cursor\x12\x1a\n" +
+	"\blanguage\x18\b \x01(\tR\blanguage\"\xab\x01\n" +
+	"\x15SearchStreamsResponse\x12&\n" +
+	"\x06status\x18\x01 \x01(\v2\x0e.common.StatusR\x06status\x12(\n" +
+	"\astreams\x18\x02 \x03(\v2\x0e.stream.StreamR\astreams\x12\x1f\n" +
+	"\vnext_cursor\x18\x03 \x01(\tR\n" +
+	"nextCursor\x12\x1f\n" +
+	"\vtotal_count\x18\x04 \x01(\x05R\n" +
 	"totalCount\"\x81\x01\n" +
 	"\x10EndStreamRequest\x12\x1b\n" +
 	"\tstream_id\x18\x01 \x01(\tR\bstreamId\x12)\n" +
@@ -1303,7 +2579,35 @@ const file_stream_stream_service_proto_rawDesc = "" +
 	"\x10duration_seconds\x18\x04 \x01(\x03R\x0fdurationSeconds\"i\n" +
 	"\x1aRecordingCompletedResponse\x12&\n" +
 	"\x06status\x18\x01 \x01(\v2\x0e.common.StatusR\x06status\x12#\n" +
-	"\rrecording_url\x18\x02 \x01(\tR\frecordingUrl\"\xa1\x04\n" +
+	"\rrecording_url\x18\x02 \x01(\tR\frecordingUrl\"\xef\x01\n" +
+	"\x19ReportStreamHealthRequest\x12\x1b\n" +
+	"\tstream_id\x18\x01 \x01(\tR\bstreamId\x12\x1d\n" +
+	"\n" +
+	"stream_key\x18\x02 \x01(\tR\tstreamKey\x12!\n" +
+	"\fbitrate_kbps\x18\x03 \x01(\x05R\vbitrateKbps\x12\x10\n" +
+	"\x03fps\x18\x04 \x01(\x01R\x03fps\x12%\n" +
+	"\x0edropped_frames\x18\x05 \x01(\x03R\rdroppedFrames\x12:\n" +
+	"\x19keyframe_interval_seconds\x18\x06 \x01(\x01R\x17keyframeIntervalSeconds\"r\n" +
+	"\x1aReportStreamHealthResponse\x12&\n" +
+	"\x06status\x18\x01 \x01(\v2\x0e.common.StatusR\x06status\x12,\n" +
+	"\x06health\x18\x02 \x01(\v2\x14.stream.StreamHealthR\x06health\"T\n" +
+	"\x18GenerateStreamKeyRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\x03R\x06userId\x12\x1f\n" +
+	"\vttl_seconds\x18\x02 \x01(\x03R\n" +
+	"ttlSeconds\"\xab\x01\n" +
+	"\x19GenerateStreamKeyResponse\x12&\n" +
+	"\x06status\x18\x01 \x01(\v2\x0e.common.StatusR\x06status\x12\x1d\n" +
+	"\n" +
+	"stream_key\x18\x02 \x01(\tR\tstreamKey\x12\x15\n" +
+	"\x06key_id\x18\x03 \x01(\tR\x05keyId\x120\n" +
+	"\n" +
+	"expires_at\x18\x04 \x01(\v2\x11.common.TimestampR\texpiresAt\"7\n" +
+	"\x16RevokeStreamKeyRequest\x12\x1d\n" +
+	"\n" +
+	"stream_key\x18\x01 \x01(\tR\tstreamKey\"X\n" +
+	"\x17RevokeStreamKeyResponse\x12&\n" +
+	"\x06status\x18\x01 \x01(\v2\x0e.common.StatusR\x06status\x12\x15\n" +
+	"\x06key_id\x18\x02 \x01(\tR\x05keyId\"\xf1\x05\n" +
 	"\x06Stream\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x12\x17\n" +
 	"\auser_id\x18\x02 \x01(\x03R\x06userId\x12\x1d\n" +
@@ -1323,7 +2627,27 @@ const file_stream_stream_service_proto_rawDesc = "" +
 	"\n" +
 	"created_at\x18\r \x01(\v2\x11.common.TimestampR\tcreatedAt\x120\n" +
 	"\n" +
-	"updated_at\x18\x0e \x01(\v2\x11.common.TimestampR\tupdatedAt\"\xb2\x02\n" +
+	"updated_at\x18\x0e \x01(\v2\x11.common.TimestampR\tupdatedAt\x12,\n" +
+	"\x06health\x18\x0f \x01(\v2\x14.stream.StreamHealthR\x06health\x12\x1b\n" +
+	"\tis_mature\x18\x10 \x01(\bR\bisMature\x12\x1e\n" +
+	"\n" +
+	"visibility\x18\x11 \x01(\tR\n" +
+	"visibility\x12\x1a\n" +
+	"\blanguage\x18\x12 \x01(\tR\blanguage\x12 \n" +
+	"\x04raid\x18\x13 \x01(\v2\f.stream.RaidR\x04raid\x12%\n" +
+	"\x0eraids_received\x18\x14 \x01(\x05R\rraidsReceived\"\xf3\x01\n" +
+	"\x04Raid\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12$\n" +
+	"\x0efrom_stream_id\x18\x02 \x01(\tR\ffromStreamId\x12 \n" +
+	"\ffrom_user_id\x18\x03 \x01(\x03R\n" +
+	"fromUserId\x12 \n" +
+	"\fto_stream_id\x18\x04 \x01(\tR\n" +
+	"toStreamId\x12\x1c\n" +
+	"\n" +
+	"to_user_id\x18\x05 \x01(\x03R\btoUserId\x12!\n" +
+	"\fviewer_count\x18\x06 \x01(\x03R\vviewerCount\x120\n" +
+	"\n" +
+	"created_at\x18\a \x01(\v2\x11.common.TimestampR\tcreatedAt\"\xb2\x02\n" +
 	"\x0eStreamMetadata\x12\x1e\n" +
 	"\n" +
 	"resolution\x18\x01 \x01(\tR\n" +
@@ -1337,21 +2661,42 @@ const file_stream_stream_service_proto_rawDesc = "" +
 	"customData\x1a=\n" +
 	"\x0fCustomDataEntry\x12\x10\n" +
 	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
-	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01*j\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\xd1\x02\n" +
+	"\fStreamHealth\x12,\n" +
+	"\x06status\x18\x01 \x01(\x0e2\x14.stream.HealthStatusR\x06status\x12\x18\n" +
+	"\areasons\x18\x02 \x03(\tR\areasons\x12(\n" +
+	"\x10avg_bitrate_kbps\x18\x03 \x01(\x05R\x0eavgBitrateKbps\x12\x17\n" +
+	"\aavg_fps\x18\x04 \x01(\x01R\x06avgFps\x12%\n" +
+	"\x0edropped_frames\x18\x05 \x01(\x03R\rdroppedFrames\x12:\n" +
+	"\x19keyframe_interval_seconds\x18\x06 \x01(\x01R\x17keyframeIntervalSeconds\x12!\n" +
+	"\fsample_count\x18\a \x01(\x05R\vsampleCount\x120\n" +
+	"\n" +
+	"updated_at\x18\b \x01(\v2\x11.common.TimestampR\tupdatedAt*j\n" +
 	"\fStreamStatus\x12\x12\n" +
 	"\x0eSTREAM_PENDING\x10\x00\x12\x0f\n" +
 	"\vSTREAM_LIVE\x10\x01\x12\x10\n" +
 	"\fSTREAM_ENDED\x10\x02\x12\x10\n" +
 	"\fSTREAM_ERROR\x10\x03\x12\x11\n" +
-	"\rSTREAM_PAUSED\x10\x042\xb7\x04\n" +
+	"\rSTREAM_PAUSED\x10\x04*]\n" +
+	"\fHealthStatus\x12\x12\n" +
+	"\x0eHEALTH_UNKNOWN\x10\x00\x12\x0f\n" +
+	"\vHEALTH_GOOD\x10\x01\x12\x13\n" +
+	"\x0fHEALTH_DEGRADED\x10\x02\x12\x13\n" +
+	"\x0fHEALTH_CRITICAL\x10\x032\xb5\b\n" +
 	"\rStreamService\x12X\n" +
 	"\x11ValidateStreamKey\x12 .stream.ValidateStreamKeyRequest\x1a!.stream.ValidateStreamKeyResponse\x12I\n" +
 	"\fCreateStream\x12\x1b.stream.CreateStreamRequest\x1a\x1c.stream.CreateStreamResponse\x12I\n" +
 	"\fUpdateStream\x12\x1b.stream.UpdateStreamRequest\x1a\x1c.stream.UpdateStreamResponse\x12@\n" +
-	"\tGetStream\x12\x18.stream.GetStreamRequest\x1a\x19.stream.GetStreamResponse\x12U\n" +
-	"\x10GetActiveStreams\x12\x1f.stream.GetActiveStreamsRequest\x1a .stream.GetActiveStreamsResponse\x12@\n" +
+	"\tGetStream\x12\x18.stream.GetStreamRequest\x1a\x19.stream.GetStreamResponse\x12O\n" +
+	"\x0eGetStreamByKey\x12\x1d.stream.GetStreamByKeyRequest\x1a\x1e.stream.GetStreamByKeyResponse\x12R\n" +
+	"\x0fGetStreamsBatch\x12\x1e.stream.GetStreamsBatchRequest\x1a\x1f.stream.GetStreamsBatchResponse\x12U\n" +
+	"\x10GetActiveStreams\x12\x1f.stream.GetActiveStreamsRequest\x1a .stream.GetActiveStreamsResponse\x12L\n" +
+	"\rSearchStreams\x12\x1c.stream.SearchStreamsRequest\x1a\x1d.stream.SearchStreamsResponse\x12@\n" +
 	"\tEndStream\x12\x18.stream.EndStreamRequest\x1a\x19.stream.EndStreamResponse\x12[\n" +
-	"\x12RecordingCompleted\x12!.stream.RecordingCompletedRequest\x1a\".stream.RecordingCompletedResponseB\xbb\x01\n" +
+	"\x12RecordingCompleted\x12!.stream.RecordingCompletedRequest\x1a\".stream.RecordingCompletedResponse\x12[\n" +
+	"\x12ReportStreamHealth\x12!.stream.ReportStreamHealthRequest\x1a\".stream.ReportStreamHealthResponse\x12X\n" +
+	"\x11GenerateStreamKey\x12 .stream.GenerateStreamKeyRequest\x1a!.stream.GenerateStreamKeyResponse\x12R\n" +
+	"\x0fRevokeStreamKey\x12\x1e.stream.RevokeStreamKeyRequest\x1a\x1f.stream.RevokeStreamKeyResponseB\xbb\x01\n" +
 	"\n" +
 	"com.streamB\x12StreamServiceProtoP\x01Zagithub.com/Saoudyahya/Live-Streaming-Platform-Architecture/services/chat-service/pkg/proto/stream\xa2\x02\x03SXX\xaa\x02\x06Stream\xca\x02\x06Stream\xe2\x02\x12Stream\\GPBMetadata\xea\x02\x06Streamb\x06proto3"
 
@@ -1367,73 +2712,122 @@ func file_stream_stream_service_proto_rawDescGZIP() []byte {
 	return file_stream_stream_service_proto_rawDescData
 }
 
-var file_stream_stream_service_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
-var file_stream_stream_service_proto_msgTypes = make([]protoimpl.MessageInfo, 18)
+var file_stream_stream_service_proto_enumTypes = make([]protoimpl.EnumInfo, 2)
+var file_stream_stream_service_proto_msgTypes = make([]protoimpl.MessageInfo, 34)
 var file_stream_stream_service_proto_goTypes = []any{
 	(StreamStatus)(0),                  // 0: stream.StreamStatus
-	(*ValidateStreamKeyRequest)(nil),   // 1: stream.ValidateStreamKeyRequest
-	(*ValidateStreamKeyResponse)(nil),  // 2: stream.ValidateStreamKeyResponse
-	(*StreamPermissions)(nil),          // 3: stream.StreamPermissions
-	(*CreateStreamRequest)(nil),        // 4: stream.CreateStreamRequest
-	(*CreateStreamResponse)(nil),       // 5: stream.CreateStreamResponse
-	(*UpdateStreamRequest)(nil),        // 6: stream.UpdateStreamRequest
-	(*UpdateStreamResponse)(nil),       // 7: stream.UpdateStreamResponse
-	(*GetStreamRequest)(nil),           // 8: stream.GetStreamRequest
-	(*GetStreamResponse)(nil),          // 9: stream.GetStreamResponse
-	(*GetActiveStreamsRequest)(nil),    // 10: stream.GetActiveStreamsRequest
-	(*GetActiveStreamsResponse)(nil),   // 11: stream.GetActiveStreamsResponse
-	(*EndStreamRequest)(nil),           // 12: stream.EndStreamRequest
-	(*EndStreamResponse)(nil),          // 13: stream.EndStreamResponse
-	(*RecordingCompletedRequest)(nil),  // 14: stream.RecordingCompletedRequest
-	(*RecordingCompletedResponse)(nil), // 15: stream.RecordingCompletedResponse
-	(*Stream)(nil),                     // 16: stream.Stream
-	(*StreamMetadata)(nil),             // 17: stream.StreamMetadata
-	nil,                                // 18: stream.StreamMetadata.CustomDataEntry
-	(*common.Status)(nil),              // 19: common.Status
-	(*common.Timestamp)(nil),           // 20: common.Timestamp
+	(HealthStatus)(0),                  // 1: stream.HealthStatus
+	(*ValidateStreamKeyRequest)(nil),   // 2: stream.ValidateStreamKeyRequest
+	(*ValidateStreamKeyResponse)(nil),  // 3: stream.ValidateStreamKeyResponse
+	(*StreamPermissions)(nil),          // 4: stream.StreamPermissions
+	(*LadderRendition)(nil),            // 5: stream.LadderRendition
+	(*CreateStreamRequest)(nil),        // 6: stream.CreateStreamRequest
+	(*CreateStreamResponse)(nil),       // 7: stream.CreateStreamResponse
+	(*UpdateStreamRequest)(nil),        // 8: stream.UpdateStreamRequest
+	(*UpdateStreamResponse)(nil),       // 9: stream.UpdateStreamResponse
+	(*GetStreamRequest)(nil),           // 10: stream.GetStreamRequest
+	(*GetStreamResponse)(nil),          // 11: stream.GetStreamResponse
+	(*GetStreamByKeyRequest)(nil),      // 12: stream.GetStreamByKeyRequest
+	(*GetStreamByKeyResponse)(nil),     // 13: stream.GetStreamByKeyResponse
+	(*StreamSession)(nil),              // 14: stream.StreamSession
+	(*GetStreamsBatchRequest)(nil),     // 15: stream.GetStreamsBatchRequest
+	(*GetStreamsBatchResponse)(nil),    // 16: stream.GetStreamsBatchResponse
+	(*GetActiveStreamsRequest)(nil),    // 17: stream.GetActiveStreamsRequest
+	(*GetActiveStreamsResponse)(nil),   // 18: stream.GetActiveStreamsResponse
+	(*SearchStreamsRequest)(nil),       // 19: stream.SearchStreamsRequest
+	(*SearchStreamsResponse)(nil),      // 20: stream.SearchStreamsResponse
+	(*EndStreamRequest)(nil),           // 21: stream.EndStreamRequest
+	(*EndStreamResponse)(nil),          // 22: stream.EndStreamResponse
+	(*RecordingCompletedRequest)(nil),  // 23: stream.RecordingCompletedRequest
+	(*RecordingCompletedResponse)(nil), // 24: stream.RecordingCompletedResponse
+	(*ReportStreamHealthRequest)(nil),  // 25: stream.ReportStreamHealthRequest
+	(*ReportStreamHealthResponse)(nil), // 26: stream.ReportStreamHealthResponse
+	(*GenerateStreamKeyRequest)(nil),   // 27: stream.GenerateStreamKeyRequest
+	(*GenerateStreamKeyResponse)(nil),  // 28: stream.GenerateStreamKeyResponse
+	(*RevokeStreamKeyRequest)(nil),     // 29: stream.RevokeStreamKeyRequest
+	(*RevokeStreamKeyResponse)(nil),    // 30: stream.RevokeStreamKeyResponse
+	(*Stream)(nil),                     // 31: stream.Stream
+	(*Raid)(nil),                       // 32: stream.Raid
+	(*StreamMetadata)(nil),             // 33: stream.StreamMetadata
+	(*StreamHealth)(nil),               // 34: stream.StreamHealth
+	nil,                                // 35: stream.StreamMetadata.CustomDataEntry
+	(*common.Status)(nil),              // 36: common.Status
+	(*common.Timestamp)(nil),           // 37: common.Timestamp
 }
 var file_stream_stream_service_proto_depIdxs = []int32{
-	19, // 0: stream.ValidateStreamKeyResponse.status:type_name -> common.Status
-	3,  // 1: stream.ValidateStreamKeyResponse.permissions:type_name -> stream.StreamPermissions
-	17, // 2: stream.CreateStreamRequest.metadata:type_name -> stream.StreamMetadata
-	19, // 3: stream.CreateStreamResponse.status:type_name -> common.Status
-	16, // 4: stream.CreateStreamResponse.stream:type_name -> stream.Stream
-	0,  // 5: stream.UpdateStreamRequest.status:type_name -> stream.StreamStatus
-	17, // 6: stream.UpdateStreamRequest.metadata:type_name -> stream.StreamMetadata
-	19, // 7: stream.UpdateStreamResponse.status:type_name -> common.Status
-	16, // 8: stream.UpdateStreamResponse.stream:type_name -> stream.Stream
-	19, // 9: stream.GetStreamResponse.status:type_name -> common.Status
-	16, // 10: stream.GetStreamResponse.stream:type_name -> stream.Stream
-	19, // 11: stream.GetActiveStreamsResponse.status:type_name -> common.Status
-	16, // 12: stream.GetActiveStreamsResponse.streams:type_name -> stream.Stream
-	19, // 13: stream.EndStreamResponse.status:type_name -> common.Status
-	19, // 14: stream.RecordingCompletedResponse.status:type_name -> common.Status
-	0,  // 15: stream.Stream.status:type_name -> stream.StreamStatus
-	20, // 16: stream.Stream.started_at:type_name -> common.Timestamp
-	20, // 17: stream.Stream.ended_at:type_name -> common.Timestamp
-	17, // 18: stream.Stream.metadata:type_name -> stream.StreamMetadata
-	20, // 19: stream.Stream.created_at:type_name -> common.Timestamp
-	20, // 20: stream.Stream.updated_at:type_name -> common.Timestamp
-	18, // 21: stream.StreamMetadata.custom_data:type_name -> stream.StreamMetadata.CustomDataEntry
-	1,  // 22: stream.StreamService.ValidateStreamKey:input_type -> stream.ValidateStreamKeyRequest
-	4,  // 23: stream.StreamService.CreateStream:input_type -> stream.CreateStreamRequest
-	6,  // 24: stream.StreamService.UpdateStream:input_type -> stream.UpdateStreamRequest
-	8,  // 25: stream.StreamService.GetStream:input_type -> stream.GetStreamRequest
-	10, // 26: stream.StreamService.GetActiveStreams:input_type -> stream.GetActiveStreamsRequest
-	12, // 27: stream.StreamService.EndStream:input_type -> stream.EndStreamRequest
-	14, // 28: stream.StreamService.RecordingCompleted:input_type -> stream.RecordingCompletedRequest
-	2,  // 29: stream.StreamService.ValidateStreamKey:output_type -> stream.ValidateStreamKeyResponse
-	5,  // 30: stream.StreamService.CreateStream:output_type -> stream.CreateStreamResponse
-	7,  // 31: stream.StreamService.UpdateStream:output_type -> stream.UpdateStreamResponse
-	9,  // 32: stream.StreamService.GetStream:output_type -> stream.GetStreamResponse
-	11, // 33: stream.StreamService.GetActiveStreams:output_type -> stream.GetActiveStreamsResponse
-	13, // 34: stream.StreamService.EndStream:output_type -> stream.EndStreamResponse
-	15, // 35: stream.StreamService.RecordingCompleted:output_type -> stream.RecordingCompletedResponse
-	29, // [29:36] is the sub-list for method output_type
-	22, // [22:29] is the sub-list for method input_type
-	22, // [22:22] is the sub-list for extension type_name
-	22, // [22:22] is the sub-list for extension extendee
-	0,  // [0:22] is the sub-list for field type_name
+	36, // 0: stream.ValidateStreamKeyResponse.status:type_name -> common.Status
+	4,  // 1: stream.ValidateStreamKeyResponse.permissions:type_name -> stream.StreamPermissions
+	5,  // 2: stream.ValidateStreamKeyResponse.quality_ladder:type_name -> stream.LadderRendition
+	33, // 3: stream.CreateStreamRequest.metadata:type_name -> stream.StreamMetadata
+	36, // 4: stream.CreateStreamResponse.status:type_name -> common.Status
+	31, // 5: stream.CreateStreamResponse.stream:type_name -> stream.Stream
+	0,  // 6: stream.UpdateStreamRequest.status:type_name -> stream.StreamStatus
+	33, // 7: stream.UpdateStreamRequest.metadata:type_name -> stream.StreamMetadata
+	36, // 8: stream.UpdateStreamResponse.status:type_name -> common.Status
+	31, // 9: stream.UpdateStreamResponse.stream:type_name -> stream.Stream
+	36, // 10: stream.GetStreamResponse.status:type_name -> common.Status
+	31, // 11: stream.GetStreamResponse.stream:type_name -> stream.Stream
+	36, // 12: stream.GetStreamByKeyResponse.status:type_name -> common.Status
+	31, // 13: stream.GetStreamByKeyResponse.stream:type_name -> stream.Stream
+	14, // 14: stream.GetStreamByKeyResponse.session:type_name -> stream.StreamSession
+	37, // 15: stream.StreamSession.started_at:type_name -> common.Timestamp
+	37, // 16: stream.StreamSession.disconnected_at:type_name -> common.Timestamp
+	36, // 17: stream.GetStreamsBatchResponse.status:type_name -> common.Status
+	31, // 18: stream.GetStreamsBatchResponse.streams:type_name -> stream.Stream
+	36, // 19: stream.GetActiveStreamsResponse.status:type_name -> common.Status
+	31, // 20: stream.GetActiveStreamsResponse.streams:type_name -> stream.Stream
+	36, // 21: stream.SearchStreamsResponse.status:type_name -> common.Status
+	31, // 22: stream.SearchStreamsResponse.streams:type_name -> stream.Stream
+	36, // 23: stream.EndStreamResponse.status:type_name -> common.Status
+	36, // 24: stream.RecordingCompletedResponse.status:type_name -> common.Status
+	36, // 25: stream.ReportStreamHealthResponse.status:type_name -> common.Status
+	34, // 26: stream.ReportStreamHealthResponse.health:type_name -> stream.StreamHealth
+	36, // 27: stream.GenerateStreamKeyResponse.status:type_name -> common.Status
+	37, // 28: stream.GenerateStreamKeyResponse.expires_at:type_name -> common.Timestamp
+	36, // 29: stream.RevokeStreamKeyResponse.status:type_name -> common.Status
+	0,  // 30: stream.Stream.status:type_name -> stream.StreamStatus
+	37, // 31: stream.Stream.started_at:type_name -> common.Timestamp
+	37, // 32: stream.Stream.ended_at:type_name -> common.Timestamp
+	33, // 33: stream.Stream.metadata:type_name -> stream.StreamMetadata
+	37, // 34: stream.Stream.created_at:type_name -> common.Timestamp
+	37, // 35: stream.Stream.updated_at:type_name -> common.Timestamp
+	34, // 36: stream.Stream.health:type_name -> stream.StreamHealth
+	32, // 37: stream.Stream.raid:type_name -> stream.Raid
+	37, // 38: stream.Raid.created_at:type_name -> common.Timestamp
+	35, // 39: stream.StreamMetadata.custom_data:type_name -> stream.StreamMetadata.CustomDataEntry
+	1,  // 40: stream.StreamHealth.status:type_name -> stream.HealthStatus
+	37, // 41: stream.StreamHealth.updated_at:type_name -> common.Timestamp
+	2,  // 42: stream.StreamService.ValidateStreamKey:input_type -> stream.ValidateStreamKeyRequest
+	6,  // 43: stream.StreamService.CreateStream:input_type -> stream.CreateStreamRequest
+	8,  // 44: stream.StreamService.UpdateStream:input_type -> stream.UpdateStreamRequest
+	10, // 45: stream.StreamService.GetStream:input_type -> stream.GetStreamRequest
+	12, // 46: stream.StreamService.GetStreamByKey:input_type -> stream.GetStreamByKeyRequest
+	15, // 47: stream.StreamService.GetStreamsBatch:input_type -> stream.GetStreamsBatchRequest
+	17, // 48: stream.StreamService.GetActiveStreams:input_type -> stream.GetActiveStreamsRequest
+	19, // 49: stream.StreamService.SearchStreams:input_type -> stream.SearchStreamsRequest
+	21, // 50: stream.StreamService.EndStream:input_type -> stream.EndStreamRequest
+	23, // 51: stream.StreamService.RecordingCompleted:input_type -> stream.RecordingCompletedRequest
+	25, // 52: stream.StreamService.ReportStreamHealth:input_type -> stream.ReportStreamHealthRequest
+	27, // 53: stream.StreamService.GenerateStreamKey:input_type -> stream.GenerateStreamKeyRequest
+	29, // 54: stream.StreamService.RevokeStreamKey:input_type -> stream.RevokeStreamKeyRequest
+	3,  // 55: stream.StreamService.ValidateStreamKey:output_type -> stream.ValidateStreamKeyResponse
+	7,  // 56: stream.StreamService.CreateStream:output_type -> stream.CreateStreamResponse
+	9,  // 57: stream.StreamService.UpdateStream:output_type -> stream.UpdateStreamResponse
+	11, // 58: stream.StreamService.GetStream:output_type -> stream.GetStreamResponse
+	13, // 59: stream.StreamService.GetStreamByKey:output_type -> stream.GetStreamByKeyResponse
+	16, // 60: stream.StreamService.GetStreamsBatch:output_type -> stream.GetStreamsBatchResponse
+	18, // 61: stream.StreamService.GetActiveStreams:output_type -> stream.GetActiveStreamsResponse
+	20, // 62: stream.StreamService.SearchStreams:output_type -> stream.SearchStreamsResponse
+	22, // 63: stream.StreamService.EndStream:output_type -> stream.EndStreamResponse
+	24, // 64: stream.StreamService.RecordingCompleted:output_type -> stream.RecordingCompletedResponse
+	26, // 65: stream.StreamService.ReportStreamHealth:output_type -> stream.ReportStreamHealthResponse
+	28, // 66: stream.StreamService.GenerateStreamKey:output_type -> stream.GenerateStreamKeyResponse
+	30, // 67: stream.StreamService.RevokeStreamKey:output_type -> stream.RevokeStreamKeyResponse
+	55, // [55:68] is the sub-list for method output_type
+	42, // [42:55] is the sub-list for method input_type
+	42, // [42:42] is the sub-list for extension type_name
+	42, // [42:42] is the sub-list for extension extendee
+	0,  // [0:42] is the sub-list for field type_name
 }
 
 func init() { file_stream_stream_service_proto_init() }
@@ -1446,8 +2840,8 @@ func file_stream_stream_service_proto_init() {
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_stream_stream_service_proto_rawDesc), len(file_stream_stream_service_proto_rawDesc)),
-			NumEnums:      1,
-			NumMessages:   18,
+			NumEnums:      2,
+			NumMessages:   34,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
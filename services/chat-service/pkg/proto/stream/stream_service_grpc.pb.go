@@ -25,9 +25,15 @@ const (
 	StreamService_CreateStream_FullMethodName       = "/stream.StreamService/CreateStream"
 	StreamService_UpdateStream_FullMethodName       = "/stream.StreamService/UpdateStream"
 	StreamService_GetStream_FullMethodName          = "/stream.StreamService/GetStream"
+	StreamService_GetStreamByKey_FullMethodName     = "/stream.StreamService/GetStreamByKey"
+	StreamService_GetStreamsBatch_FullMethodName    = "/stream.StreamService/GetStreamsBatch"
 	StreamService_GetActiveStreams_FullMethodName   = "/stream.StreamService/GetActiveStreams"
+	StreamService_SearchStreams_FullMethodName      = "/stream.StreamService/SearchStreams"
 	StreamService_EndStream_FullMethodName          = "/stream.StreamService/EndStream"
 	StreamService_RecordingCompleted_FullMethodName = "/stream.StreamService/RecordingCompleted"
+	StreamService_ReportStreamHealth_FullMethodName = "/stream.StreamService/ReportStreamHealth"
+	StreamService_GenerateStreamKey_FullMethodName  = "/stream.StreamService/GenerateStreamKey"
+	StreamService_RevokeStreamKey_FullMethodName    = "/stream.StreamService/RevokeStreamKey"
 )
 
 // StreamServiceClient is the client API for StreamService service.
@@ -38,9 +44,15 @@ type StreamServiceClient interface {
 	CreateStream(ctx context.Context, in *CreateStreamRequest, opts ...grpc.CallOption) (*CreateStreamResponse, error)
 	UpdateStream(ctx context.Context, in *UpdateStreamRequest, opts ...grpc.CallOption) (*UpdateStreamResponse, error)
 	GetStream(ctx context.Context, in *GetStreamRequest, opts ...grpc.CallOption) (*GetStreamResponse, error)
+	GetStreamByKey(ctx context.Context, in *GetStreamByKeyRequest, opts ...grpc.CallOption) (*GetStreamByKeyResponse, error)
+	GetStreamsBatch(ctx context.Context, in *GetStreamsBatchRequest, opts ...grpc.CallOption) (*GetStreamsBatchResponse, error)
 	GetActiveStreams(ctx context.Context, in *GetActiveStreamsRequest, opts ...grpc.CallOption) (*GetActiveStreamsResponse, error)
+	SearchStreams(ctx context.Context, in *SearchStreamsRequest, opts ...grpc.CallOption) (*SearchStreamsResponse, error)
 	EndStream(ctx context.Context, in *EndStreamRequest, opts ...grpc.CallOption) (*EndStreamResponse, error)
 	RecordingCompleted(ctx context.Context, in *RecordingCompletedRequest, opts ...grpc.CallOption) (*RecordingCompletedResponse, error)
+	ReportStreamHealth(ctx context.Context, in *ReportStreamHealthRequest, opts ...grpc.CallOption) (*ReportStreamHealthResponse, error)
+	GenerateStreamKey(ctx context.Context, in *GenerateStreamKeyRequest, opts ...grpc.CallOption) (*GenerateStreamKeyResponse, error)
+	RevokeStreamKey(ctx context.Context, in *RevokeStreamKeyRequest, opts ...grpc.CallOption) (*RevokeStreamKeyResponse, error)
 }
 
 type streamServiceClient struct {
@@ -91,6 +103,26 @@ func (c *streamServiceClient) GetStream(ctx context.Context, in *GetStreamReques
 	return out, nil
 }
 
+func (c *streamServiceClient) GetStreamByKey(ctx context.Context, in *GetStreamByKeyRequest, opts ...grpc.CallOption) (*GetStreamByKeyResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetStreamByKeyResponse)
+	err := c.cc.Invoke(ctx, StreamService_GetStreamByKey_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *streamServiceClient) GetStreamsBatch(ctx context.Context, in *GetStreamsBatchRequest, opts ...grpc.CallOption) (*GetStreamsBatchResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetStreamsBatchResponse)
+	err := c.cc.Invoke(ctx, StreamService_GetStreamsBatch_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *streamServiceClient) GetActiveStreams(ctx context.Context, in *GetActiveStreamsRequest, opts ...grpc.CallOption) (*GetActiveStreamsResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(GetActiveStreamsResponse)
@@ -101,6 +133,16 @@ func (c *streamServiceClient) GetActiveStreams(ctx context.Context, in *GetActiv
 	return out, nil
 }
 
+func (c *streamServiceClient) SearchStreams(ctx context.Context, in *SearchStreamsRequest, opts ...grpc.CallOption) (*SearchStreamsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SearchStreamsResponse)
+	err := c.cc.Invoke(ctx, StreamService_SearchStreams_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *streamServiceClient) EndStream(ctx context.Context, in *EndStreamRequest, opts ...grpc.CallOption) (*EndStreamResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(EndStreamResponse)
@@ -121,6 +163,36 @@ func (c *streamServiceClient) RecordingCompleted(ctx context.Context, in *Record
 	return out, nil
 }
 
+func (c *streamServiceClient) ReportStreamHealth(ctx context.Context, in *ReportStreamHealthRequest, opts ...grpc.CallOption) (*ReportStreamHealthResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ReportStreamHealthResponse)
+	err := c.cc.Invoke(ctx, StreamService_ReportStreamHealth_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *streamServiceClient) GenerateStreamKey(ctx context.Context, in *GenerateStreamKeyRequest, opts ...grpc.CallOption) (*GenerateStreamKeyResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GenerateStreamKeyResponse)
+	err := c.cc.Invoke(ctx, StreamService_GenerateStreamKey_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *streamServiceClient) RevokeStreamKey(ctx context.Context, in *RevokeStreamKeyRequest, opts ...grpc.CallOption) (*RevokeStreamKeyResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RevokeStreamKeyResponse)
+	err := c.cc.Invoke(ctx, StreamService_RevokeStreamKey_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // StreamServiceServer is the server API for StreamService service.
 // All implementations should embed UnimplementedStreamServiceServer
 // for forward compatibility.
@@ -129,9 +201,15 @@ type StreamServiceServer interface {
 	CreateStream(context.Context, *CreateStreamRequest) (*CreateStreamResponse, error)
 	UpdateStream(context.Context, *UpdateStreamRequest) (*UpdateStreamResponse, error)
 	GetStream(context.Context, *GetStreamRequest) (*GetStreamResponse, error)
+	GetStreamByKey(context.Context, *GetStreamByKeyRequest) (*GetStreamByKeyResponse, error)
+	GetStreamsBatch(context.Context, *GetStreamsBatchRequest) (*GetStreamsBatchResponse, error)
 	GetActiveStreams(context.Context, *GetActiveStreamsRequest) (*GetActiveStreamsResponse, error)
+	SearchStreams(context.Context, *SearchStreamsRequest) (*SearchStreamsResponse, error)
 	EndStream(context.Context, *EndStreamRequest) (*EndStreamResponse, error)
 	RecordingCompleted(context.Context, *RecordingCompletedRequest) (*RecordingCompletedResponse, error)
+	ReportStreamHealth(context.Context, *ReportStreamHealthRequest) (*ReportStreamHealthResponse, error)
+	GenerateStreamKey(context.Context, *GenerateStreamKeyRequest) (*GenerateStreamKeyResponse, error)
+	RevokeStreamKey(context.Context, *RevokeStreamKeyRequest) (*RevokeStreamKeyResponse, error)
 }
 
 // UnimplementedStreamServiceServer should be embedded to have
@@ -153,15 +231,33 @@ func (UnimplementedStreamServiceServer) UpdateStream(context.Context, *UpdateStr
 func (UnimplementedStreamServiceServer) GetStream(context.Context, *GetStreamRequest) (*GetStreamResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetStream not implemented")
 }
+func (UnimplementedStreamServiceServer) GetStreamByKey(context.Context, *GetStreamByKeyRequest) (*GetStreamByKeyResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetStreamByKey not implemented")
+}
+func (UnimplementedStreamServiceServer) GetStreamsBatch(context.Context, *GetStreamsBatchRequest) (*GetStreamsBatchResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetStreamsBatch not implemented")
+}
 func (UnimplementedStreamServiceServer) GetActiveStreams(context.Context, *GetActiveStreamsRequest) (*GetActiveStreamsResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetActiveStreams not implemented")
 }
+func (UnimplementedStreamServiceServer) SearchStreams(context.Context, *SearchStreamsRequest) (*SearchStreamsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SearchStreams not implemented")
+}
 func (UnimplementedStreamServiceServer) EndStream(context.Context, *EndStreamRequest) (*EndStreamResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method EndStream not implemented")
 }
 func (UnimplementedStreamServiceServer) RecordingCompleted(context.Context, *RecordingCompletedRequest) (*RecordingCompletedResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method RecordingCompleted not implemented")
 }
+func (UnimplementedStreamServiceServer) ReportStreamHealth(context.Context, *ReportStreamHealthRequest) (*ReportStreamHealthResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReportStreamHealth not implemented")
+}
+func (UnimplementedStreamServiceServer) GenerateStreamKey(context.Context, *GenerateStreamKeyRequest) (*GenerateStreamKeyResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GenerateStreamKey not implemented")
+}
+func (UnimplementedStreamServiceServer) RevokeStreamKey(context.Context, *RevokeStreamKeyRequest) (*RevokeStreamKeyResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RevokeStreamKey not implemented")
+}
 func (UnimplementedStreamServiceServer) testEmbeddedByValue() {}
 
 // UnsafeStreamServiceServer may be embedded to opt out of forward compatibility for this service.
@@ -254,6 +350,42 @@ func _StreamService_GetStream_Handler(srv interface{}, ctx context.Context, dec
 	return interceptor(ctx, in, info, handler)
 }
 
+func _StreamService_GetStreamByKey_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetStreamByKeyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StreamServiceServer).GetStreamByKey(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: StreamService_GetStreamByKey_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StreamServiceServer).GetStreamByKey(ctx, req.(*GetStreamByKeyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _StreamService_GetStreamsBatch_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetStreamsBatchRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StreamServiceServer).GetStreamsBatch(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: StreamService_GetStreamsBatch_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StreamServiceServer).GetStreamsBatch(ctx, req.(*GetStreamsBatchRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _StreamService_GetActiveStreams_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(GetActiveStreamsRequest)
 	if err := dec(in); err != nil {
@@ -272,6 +404,24 @@ func _StreamService_GetActiveStreams_Handler(srv interface{}, ctx context.Contex
 	return interceptor(ctx, in, info, handler)
 }
 
+func _StreamService_SearchStreams_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SearchStreamsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StreamServiceServer).SearchStreams(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: StreamService_SearchStreams_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StreamServiceServer).SearchStreams(ctx, req.(*SearchStreamsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _StreamService_EndStream_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(EndStreamRequest)
 	if err := dec(in); err != nil {
@@ -308,6 +458,60 @@ func _StreamService_RecordingCompleted_Handler(srv interface{}, ctx context.Cont
 	return interceptor(ctx, in, info, handler)
 }
 
+func _StreamService_ReportStreamHealth_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReportStreamHealthRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StreamServiceServer).ReportStreamHealth(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: StreamService_ReportStreamHealth_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StreamServiceServer).ReportStreamHealth(ctx, req.(*ReportStreamHealthRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _StreamService_GenerateStreamKey_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GenerateStreamKeyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StreamServiceServer).GenerateStreamKey(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: StreamService_GenerateStreamKey_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StreamServiceServer).GenerateStreamKey(ctx, req.(*GenerateStreamKeyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _StreamService_RevokeStreamKey_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RevokeStreamKeyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StreamServiceServer).RevokeStreamKey(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: StreamService_RevokeStreamKey_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StreamServiceServer).RevokeStreamKey(ctx, req.(*RevokeStreamKeyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // StreamService_ServiceDesc is the grpc.ServiceDesc for StreamService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -331,10 +535,22 @@ var StreamService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetStream",
 			Handler:    _StreamService_GetStream_Handler,
 		},
+		{
+			MethodName: "GetStreamByKey",
+			Handler:    _StreamService_GetStreamByKey_Handler,
+		},
+		{
+			MethodName: "GetStreamsBatch",
+			Handler:    _StreamService_GetStreamsBatch_Handler,
+		},
 		{
 			MethodName: "GetActiveStreams",
 			Handler:    _StreamService_GetActiveStreams_Handler,
 		},
+		{
+			MethodName: "SearchStreams",
+			Handler:    _StreamService_SearchStreams_Handler,
+		},
 		{
 			MethodName: "EndStream",
 			Handler:    _StreamService_EndStream_Handler,
@@ -343,6 +559,18 @@ var StreamService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "RecordingCompleted",
 			Handler:    _StreamService_RecordingCompleted_Handler,
 		},
+		{
+			MethodName: "ReportStreamHealth",
+			Handler:    _StreamService_ReportStreamHealth_Handler,
+		},
+		{
+			MethodName: "GenerateStreamKey",
+			Handler:    _StreamService_GenerateStreamKey_Handler,
+		},
+		{
+			MethodName: "RevokeStreamKey",
+			Handler:    _StreamService_RevokeStreamKey_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "stream/stream_service.proto",
@@ -3,11 +3,14 @@ package main
 
 import (
 	"context"
+	"encoding/json"
+	"flag"
 	"fmt"
-	"log"
+	"log/slog"
 	"net/http"
 	"os"
 	"os/signal"
+	"strings"
 	"sync"
 	"syscall"
 	"time"
@@ -16,11 +19,21 @@ import (
 	"google.golang.org/grpc"
 
 	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/services/stream-management-service/internal/config"
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/services/stream-management-service/internal/grpctls"
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/services/stream-management-service/internal/health"
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/services/stream-management-service/internal/logging"
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/services/stream-management-service/internal/migration"
 	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/services/stream-management-service/internal/models"
 	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/services/stream-management-service/internal/repository"
 	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/services/stream-management-service/internal/server"
 	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/services/stream-management-service/internal/service"
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/services/stream-management-service/internal/smoketest"
 	grpcClient "github.com/Saoudyahya/Live-Streaming-Platform-Architecture/services/stream-management-service/pkg/grpc"
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/services/stream-management-service/pkg/tracing"
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/shared/go/pkg/discovery"
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/shared/go/pkg/eventbus"
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/shared/go/pkg/identity"
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/shared/go/pkg/preflight"
 )
 
 var (
@@ -29,54 +42,228 @@ var (
 )
 
 func main() {
-	log.Printf("🚀 Starting Stream Management Service v%s (built %s)", Version, BuildTime)
+	// Parse command line flags
+	var (
+		plan       = flag.Bool("plan", false, "Print the AWS resources this service requires and exit")
+		planFormat = flag.String("plan-format", "json", "Output format for --plan: json or cloudformation")
+		backfill   = flag.Bool("backfill", false, "Upgrade all stored items to the current schema version and exit")
+		smokeTest  = flag.Bool("smoke-test", false, "Run a synthetic stream through a deployed instance, verify its side effects and exit")
+		migrate    = flag.Bool("migrate", false, "Apply pending DynamoDB schema migrations and exit")
+		cleanup    = flag.Bool("cleanup", false, "Delete the service's DynamoDB tables and exit (not in production)")
+	)
+	flag.Parse()
 
 	// Load configuration
 	cfg := config.Load()
-	log.Printf("📋 Configuration loaded: Environment=%s, Port=%s", cfg.Environment, cfg.Port)
+
+	if err := logging.Setup(cfg.LogFormat, cfg.LogLevel); err != nil {
+		fmt.Fprintf(os.Stderr, "❌ Failed to configure logging: %v\n", err)
+		os.Exit(1)
+	}
+
+	// Plan mode only describes the required infrastructure, it never touches AWS
+	if *plan {
+		output, err := migration.BuildPlan(cfg).Render(*planFormat)
+		if err != nil {
+			fatal("❌ Failed to render plan", "error", err)
+		}
+		fmt.Println(string(output))
+		return
+	}
+
+	if *migrate || *cleanup {
+		if err := runMigrations(cfg, *cleanup, *migrate); err != nil {
+			fatal("❌ DynamoDB migration failed", "error", err)
+		}
+		return
+	}
+
+	slog.Info("🚀 Starting Stream Management Service", "version", Version, "built", BuildTime)
+	slog.Info("📋 Configuration loaded", "environment", cfg.Environment, "port", cfg.Port, "log_level", cfg.LogLevel)
+
+	// Tracing comes first so every client created below is instrumented
+	tracer, err := tracing.Init(tracing.Config{
+		ServiceName: "stream-management-service",
+		Environment: cfg.Environment,
+		Endpoint:    cfg.TracingEndpoint,
+		SampleRatio: cfg.TracingSampleRatio,
+	})
+	if err != nil {
+		fatal("❌ Failed to initialize tracing", "error", err)
+	}
+	if cfg.TracingEndpoint != "" {
+		slog.Info("🔭 Exporting traces", "endpoint", cfg.TracingEndpoint, "sample_ratio", cfg.TracingSampleRatio)
+	}
+
+	// Tunables reloaded while the service runs override the environment from the start
+	dynamicConfigService, err := service.NewDynamicConfigService(cfg)
+	if err != nil {
+		fatal("❌ Failed to set up dynamic config", "error", err)
+	}
+	if err := dynamicConfigService.Load(context.Background()); err != nil {
+		slog.Warn("⚠️ Could not load dynamic config, using the environment until it reloads", "source", cfg.DynamicConfigSource, "error", err)
+	}
 
 	// Initialize repositories
-	log.Println("🔗 Initializing repositories...")
+	slog.Info("🔗 Initializing repositories...")
+
+	// Faults are injected into the clients created from here on, for resilience testing only
+	if cfg.FaultInjectionEnabled {
+		if cfg.Faults() == nil {
+			slog.Warn("⚠️ FAULT_INJECTION_ENABLED is ignored in production")
+		} else {
+			slog.Warn("💥 Fault injection is enabled", "configured_rules", len(cfg.Tunables().FaultRules))
+		}
+	}
+
+	// Local tables are migrated on start, deployed ones by running --migrate before a release
+	if cfg.Environment == "development" {
+		if err := runMigrations(cfg, false, true); err != nil {
+			slog.Warn("⚠️ Could not migrate DynamoDB tables", "error", err)
+		}
+	}
+
 	dynamoRepo := repository.NewDynamoDBRepository(cfg)
-	redisRepo := repository.NewRedisRepository(cfg)
-	log.Println("✅ Repositories initialized")
+	redisRepo, err := repository.NewRedisRepository(cfg)
+	if err != nil {
+		fatal("❌ Failed to set up Redis", "error", err)
+	}
+	slog.Info("✅ Repositories initialized", "redis_mode", cfg.RedisMode)
+
+	// Backfill finishes a data migration that reads have been applying lazily
+	if *backfill {
+		stats, err := dynamoRepo.BackfillStreams(context.Background(), 100, 200*time.Millisecond)
+		if err != nil {
+			fatal("❌ Backfill failed", "error", err)
+		}
+		if stats.Failed > 0 {
+			fatal("❌ Backfill left items on an old schema version, run it again", "failed", stats.Failed)
+		}
+		return
+	}
+
+	// The smoke test drives a deployed instance at SMOKE_TEST_URL and only reads the
+	// dependencies to verify what it did, so it can gate a deploy
+	if *smokeTest {
+		report := smoketest.NewRunner(cfg, dynamoRepo, redisRepo, service.NewStreamKeyService(cfg, redisRepo)).Run(context.Background())
+		output, _ := json.MarshalIndent(report, "", "  ")
+		fmt.Println(string(output))
+		if !report.Passed() {
+			fatal("❌ Smoke test failed", "stream_id", report.StreamID)
+		}
+		slog.Info("✅ Smoke test passed", "stream_id", report.StreamID)
+		return
+	}
+
+	// Users passed between services aren't signed without a secret, so the ones other
+	// services pass in aren't trusted either
+	if cfg.IdentitySecret == "" && cfg.Environment == "production" {
+		slog.Warn("⚠️ Identity propagation is unsigned in production, set IDENTITY_PROPAGATION_SECRET")
+	}
 
 	// Initialize gRPC client to User Service (with graceful fallback)
-	log.Printf("🔌 Attempting to connect to User Service at %s...", cfg.UserServiceGRPCAddr)
+	slog.Info("🔌 Attempting to connect to User Service...", "addr", cfg.UserServiceGRPCAddr)
 	var userClient *grpcClient.UserServiceClient
-	var err error
+
+	userCreds, err := grpctls.ClientCredentials(cfg)
+	if err != nil {
+		fatal("❌ Failed to set up gRPC TLS", "error", err)
+	}
 
 	// Try to connect to User Service with timeout
-	userClient, err = grpcClient.NewUserServiceClient(cfg.UserServiceGRPCAddr)
+	userClient, err = grpcClient.NewUserServiceClient(cfg.UserServiceGRPCAddr, userCreds, discovery.Config{
+		ConsulAddr:      cfg.ConsulAddr,
+		ConsulToken:     cfg.ConsulToken,
+		RefreshInterval: cfg.ServiceDiscoveryRefresh,
+		SubsetSize:      cfg.UserServiceSubsetSize,
+		HealthCheck:     cfg.UserServiceHealthCheck,
+	}, cfg.Caller(config.DependencyUserServiceGRPC), cfg.Caller(config.DependencyUserServiceHTTP), identity.NewPropagator(cfg.IdentitySecret), cfg.Faults())
 	if err != nil {
-		log.Printf("⚠️ Failed to connect to User Service gRPC: %v", err)
-		log.Println("⚠️ Continuing with fallback authentication (development mode)")
+		slog.Warn("⚠️ Failed to connect to User Service gRPC", "error", err)
+		slog.Warn("⚠️ Continuing with fallback authentication (development mode)")
 		userClient = nil
 	} else {
-		log.Println("✅ Connected to User Service gRPC")
+		slog.Info("✅ Connected to User Service gRPC")
+		userClient.UseValidationCache(redisRepo, cfg.StreamKeyCacheTTL, cfg.StreamKeyNegativeCacheTTL)
 	}
 
 	// Initialize services
-	log.Println("🔧 Initializing services...")
+	slog.Info("🔧 Initializing services...")
 	streamService := service.NewStreamService(cfg, dynamoRepo, redisRepo)
-	rtmpHandler := service.NewRTMPHandler(cfg, streamService, userClient)
-	log.Println("✅ Services initialized")
+	vodService := service.NewVODService(cfg, dynamoRepo)
+	clipService := service.NewClipService(cfg, dynamoRepo, streamService)
+	restreamService := service.NewRestreamService(cfg, dynamoRepo, streamService)
+	apiKeyService := service.NewAPIKeyService(cfg, dynamoRepo, redisRepo)
+	squadService := service.NewSquadService(cfg, redisRepo, streamService)
+	raidService := service.NewRaidService(cfg, streamService)
+	recordingService := service.NewRecordingService(cfg, streamService, vodService, redisRepo)
+	rateLimiter := service.NewRateLimiter(cfg, redisRepo)
+	premiereService := service.NewPremiereService(cfg, dynamoRepo, redisRepo, streamService)
+	vodPackager := service.NewVODPackager(cfg, dynamoRepo, streamService)
+	takedownService := service.NewTakedownService(cfg, dynamoRepo, streamService, vodPackager)
+	moderationService := service.NewModerationService(cfg, dynamoRepo, streamService)
+	fingerprintService := service.NewFingerprintService(cfg, dynamoRepo, streamService, vodPackager)
+	healthAlertService := service.NewHealthAlertService(cfg, redisRepo, streamService)
+	streamLimitService := service.NewStreamLimitService(cfg, redisRepo, streamService, healthAlertService)
+	streamService.OnHealthRecorded(healthAlertService.Evaluate)
+	streamService.OnHealthRecorded(streamLimitService.CheckBitrate)
+	qualityLadderService := service.NewQualityLadderService(cfg, dynamoRepo)
+	streamKeyService := service.NewStreamKeyService(cfg, redisRepo)
+	dashboardService := service.NewDashboardService(cfg, redisRepo, streamService)
+	opsDashboardService := service.NewOpsDashboardService(cfg, streamService, redisRepo)
+	userDataService := service.NewUserDataService(cfg, dynamoRepo, redisRepo)
+	softDeleteService := service.NewSoftDeleteService(cfg, dynamoRepo, redisRepo, streamService, userDataService)
+	deadLetterService := service.NewDeadLetterService(cfg, dynamoRepo)
+	faultInjectionService := service.NewFaultInjectionService(cfg)
+	jobService, err := service.NewJobService(cfg, streamService, softDeleteService)
+	if err != nil {
+		fatal("❌ Failed to schedule jobs", "error", err)
+	}
+	dynamicConfigService.OnChange(jobService.Reschedule)
+	slog.Info("✅ Services initialized")
+
+	// Verify dependencies up front instead of failing on the first request
+	report := preflight.Run(cfg.PreflightMode, buildPreflightChecks(cfg, dynamoRepo, redisRepo, streamService, clipService, premiereService, takedownService, fingerprintService, vodPackager, &userClient))
+	if err := report.Err(); err != nil {
+		if cfg.PreflightMode == preflight.ModeStrict {
+			fatal("❌ Preflight checks failed", "error", err)
+		}
+		slog.Warn("⚠️ Preflight checks failed", "error", err)
+		slog.Warn("⚠️ Continuing because PREFLIGHT_MODE is not strict")
+	}
+	if disabled := report.DisabledFeatures(); len(disabled) > 0 {
+		slog.Warn("🚫 Running with disabled features", "features", disabled)
+	}
+
+	ingestRouter := service.NewIngestRouter(cfg)
+	playbackAuthorizer := service.NewPlaybackAuthorizer(cfg, streamService, service.NewGeoLocator(cfg), userClient)
+	viewerAuth := service.NewViewerAuth(cfg, redisRepo, userClient)
+	if err := viewerAuth.VerifyKeys(); err != nil {
+		slog.Warn("⚠️ Could not load JWKS keys, retrying on the first request", "error", err)
+	}
+	ingestHandler := service.NewIngestHandler(cfg, streamService, vodService, recordingService, fingerprintService, vodPackager, qualityLadderService, streamKeyService, ingestRouter, userClient, playbackAuthorizer, viewerAuth)
+	if len(cfg.RTMPCallbackSecrets) == 0 && cfg.Environment != "development" {
+		slog.Warn("⚠️ RTMP_CALLBACK_SECRETS is empty, all media server callbacks will be rejected")
+	}
+	if cfg.Tunables().PlaybackTokenRequired && cfg.PlaybackTokenSecret == "" {
+		fatal("❌ PLAYBACK_TOKEN_REQUIRED needs PLAYBACK_TOKEN_SECRET")
+	}
 
 	// Start gRPC server
 	var grpcServer *grpc.Server
 	if cfg.Environment != "http-only" { // Allow disabling gRPC for testing
-		log.Println("🚀 Starting gRPC server...")
-		grpcServer, err = server.StartGRPCServer(cfg, streamService, userClient)
+		slog.Info("🚀 Starting gRPC server...")
+		grpcServer, err = server.StartGRPCServer(cfg, streamService, streamKeyService, qualityLadderService, userClient)
 		if err != nil {
-			log.Printf("⚠️ Failed to start gRPC server: %v", err)
-			log.Println("⚠️ Continuing with HTTP-only mode")
+			slog.Warn("⚠️ Failed to start gRPC server", "error", err)
+			slog.Warn("⚠️ Continuing with HTTP-only mode")
 		} else {
-			log.Println("✅ gRPC server started successfully")
+			slog.Info("✅ gRPC server started successfully")
 		}
 	}
 
 	// Setup HTTP server for RTMP callbacks and API
-	log.Println("🌐 Setting up HTTP server...")
+	slog.Info("🌐 Setting up HTTP server...")
 	if cfg.Environment == "production" {
 		gin.SetMode(gin.ReleaseMode)
 	}
@@ -85,21 +272,27 @@ func main() {
 
 	// Add middleware
 	router.Use(server.CORSMiddleware())
+	router.Use(server.TracingMiddleware())
+	router.Use(server.RequestIDMiddleware())
 	router.Use(server.LoggingMiddleware())
+	router.Use(opsDashboardService.CountRequests())
 	router.Use(gin.Recovery())
-
-	// Add request ID middleware
-	router.Use(func(c *gin.Context) {
-		c.Header("X-Request-ID", fmt.Sprintf("req_%d", time.Now().UnixNano()))
-		c.Next()
-	})
+	router.Use(service.RespondErrors())
 
 	// Health check endpoints
+	healthChecker := health.NewChecker(cfg.HealthCheckTimeout, cfg.HealthCheckCacheTTL, buildHealthChecks(dynamoRepo, redisRepo, userClient)...)
 	router.GET("/health", server.HealthCheck)
 	router.GET("/api/v1/health", server.HealthCheck)
+	router.GET("/livez", server.Livez)
+	router.GET("/readyz", server.Readyz(healthChecker))
+
+	// OpenAPI document of the public API, request bodies are validated against it
+	apiSpec := service.NewAPISpec(Version)
+	router.GET("/openapi.json", service.ServeAPISpec(apiSpec))
 
-	// Enhanced health check with gRPC status
+	// Enhanced health check with dependency and gRPC status
 	router.GET("/api/v1/health/detailed", func(c *gin.Context) {
+		checks := healthChecker.Run(c.Request.Context())
 		health := gin.H{
 			"status":      "healthy",
 			"service":     "stream-management",
@@ -109,10 +302,11 @@ func main() {
 			"environment": cfg.Environment,
 			"components": gin.H{
 				"http_server": "running",
-				"dynamodb":    "connected",
-				"redis":       "connected",
 			},
 		}
+		for _, result := range checks.Results {
+			health["components"].(gin.H)[result.Name] = result
+		}
 
 		// Check gRPC server status
 		if grpcServer != nil {
@@ -121,14 +315,29 @@ func main() {
 			health["components"].(gin.H)["grpc_server"] = "disabled"
 		}
 
-		// Check User Service connection
-		if userClient != nil {
-			if err := userClient.HealthCheck(); err != nil {
-				health["components"].(gin.H)["user_service"] = "disconnected"
-			} else {
-				health["components"].(gin.H)["user_service"] = "connected"
-			}
-		} else {
+		events := gin.H{"publisher": streamService.EventPublisherStats()}
+		if spooled, err := streamService.SpooledEventCount(); err == nil {
+			events["spooled"] = spooled
+		}
+		health["events"] = events
+		health["task_locks"] = streamService.TaskLockStats()
+		health["call_policies"] = cfg.CallerStats()
+		health["dynamodb_regions"] = dynamoRepo.RegionStatus()
+
+		// Startup preflight results
+		health["preflight"] = gin.H{
+			"mode":              report.Mode,
+			"disabled_features": report.DisabledFeatures(),
+			"results":           report.Results,
+		}
+		switch {
+		case !checks.Ready:
+			health["status"] = "unhealthy"
+		case checks.Degraded() || len(report.RequiredFailures()) > 0:
+			health["status"] = "degraded"
+		}
+
+		if userClient == nil {
 			health["components"].(gin.H)["user_service"] = "not_configured"
 		}
 
@@ -138,22 +347,114 @@ func main() {
 	// RTMP callback routes (used by media server)
 	rtmpRoutes := router.Group("/rtmp")
 	{
-		rtmpRoutes.POST("/auth", rtmpHandler.AuthenticateStream)
-		rtmpRoutes.POST("/started", rtmpHandler.StreamStarted)
-		rtmpRoutes.POST("/ended", rtmpHandler.StreamEnded)
-		rtmpRoutes.POST("/recorded", rtmpHandler.RecordingCompleted)
-		rtmpRoutes.GET("/health", rtmpHandler.HealthCheck)
-		rtmpRoutes.GET("/stream/:stream_key", rtmpHandler.GetStreamInfo)
+		// Liveness probe stays unsigned, everything after this must be signed by a media server
+		rtmpRoutes.GET("/health", ingestHandler.HealthCheck)
+		rtmpRoutes.Use(ingestHandler.VerifySignature(), service.AuditOrigin(service.AuditSourceRTMP))
+
+		rtmpRoutes.POST("/auth", ingestHandler.AuthenticateStream)
+		rtmpRoutes.POST("/started", ingestHandler.StreamStarted)
+		rtmpRoutes.POST("/ended", ingestHandler.StreamEnded)
+		rtmpRoutes.POST("/recorded", ingestHandler.RecordingCompleted)
+		rtmpRoutes.POST("/play", ingestHandler.PlayStream)
+		rtmpRoutes.POST("/play_done", ingestHandler.PlayDone)
+		rtmpRoutes.POST("/health", ingestHandler.StreamHealthReport)
+		rtmpRoutes.POST("/latency-marker", ingestHandler.LatencyMarker)
+		rtmpRoutes.POST("/forward", restreamService.ForwardTargets)
+		rtmpRoutes.GET("/stream/:stream_key", ingestHandler.GetStreamInfo)
 	}
 
-	// Stream management API routes
+	// CDN and auth_request checks of the HLS output, the token in the URL is the credential
+	router.GET("/playback/verify", playbackAuthorizer.VerifyPlayback)
+
+	// WebRTC ingest callbacks, signed like the RTMP ones
+	ingestRoutes := router.Group("/ingest")
+	ingestRoutes.Use(ingestHandler.VerifySignature())
+	{
+		ingestRoutes.POST("/whip/auth", ingestHandler.AuthenticateWHIP)
+	}
+
+	// Stream management API routes, deprecated in favor of v2 as routes move there
 	apiRoutes := router.Group("/api/v1")
+	apiRoutes.Use(server.Deprecation(router, cfg.APIV1DeprecatedAt, cfg.APIV1SunsetAt, "/api/v1", "/api/v2"))
+	apiRoutes.Use(apiKeyService.Authenticate(), viewerAuth.Identify(), rateLimiter.Limit("api"), service.AuditOrigin(service.AuditSourceAPI), service.ValidateRequests(apiSpec))
+	scope := apiKeyService.RequireScope
+	signedIn := viewerAuth.RequireViewer()
 	{
-		apiRoutes.GET("/streams", streamService.GetActiveStreams)
-		apiRoutes.GET("/streams/:id", streamService.GetStreamByID)
+		apiRoutes.GET("/streams", scope(models.ScopeStreamsRead), streamService.GetActiveStreams)
+		apiRoutes.GET("/directory", scope(models.ScopeStreamsRead), streamService.GetDirectory)
+		apiRoutes.GET("/categories", scope(models.ScopeStreamsRead), streamService.ListCategories)
+		apiRoutes.GET("/categories/:id/streams", scope(models.ScopeStreamsRead), streamService.GetCategoryStreams)
+		apiRoutes.GET("/streams/search", scope(models.ScopeStreamsRead), streamService.SearchStreams)
+		apiRoutes.GET("/streams/:id", scope(models.ScopeStreamsRead), streamService.GetStreamByID)
+		apiRoutes.PATCH("/streams/:id", signedIn, scope(models.ScopeStreamsWrite), streamService.UpdateStreamDetails)
+		apiRoutes.DELETE("/streams/:id", signedIn, scope(models.ScopeStreamsWrite), softDeleteService.DeleteStream)
+		apiRoutes.POST("/streams/:id/restore", signedIn, scope(models.ScopeStreamsWrite), softDeleteService.RestoreStream)
+		apiRoutes.GET("/users/:id/streams", signedIn, scope(models.ScopeStreamsRead), streamService.ListUserStreams)
+		apiRoutes.GET("/streams/:id/geo-restrictions", signedIn, scope(models.ScopeStreamsRead), streamService.GetGeoRestrictions)
+		apiRoutes.PUT("/streams/:id/geo-restrictions", signedIn, scope(models.ScopeStreamsWrite), streamService.UpdateGeoRestrictions)
+		apiRoutes.GET("/streams/:id/visibility", signedIn, scope(models.ScopeStreamsRead), streamService.GetStreamVisibility)
+		apiRoutes.PUT("/streams/:id/visibility", signedIn, scope(models.ScopeStreamsWrite), streamService.UpdateStreamVisibility)
+		apiRoutes.POST("/playback/authorize", scope(models.ScopeStreamsRead), rateLimiter.Limit("playback"), playbackAuthorizer.AuthorizePlayback)
+		apiRoutes.POST("/streams/:id/playback-token", scope(models.ScopeStreamsRead), rateLimiter.Limit("playback"), playbackAuthorizer.IssuePlaybackToken)
+		apiRoutes.POST("/streams/:id/markers", signedIn, scope(models.ScopeStreamsWrite), streamService.CreateMarker)
+		apiRoutes.GET("/streams/:id/markers", signedIn, scope(models.ScopeStreamsRead), streamService.GetMarkers)
+		apiRoutes.POST("/streams/:id/raid", signedIn, scope(models.ScopeStreamsWrite), raidService.StartRaid)
+		apiRoutes.GET("/streams/:id/audit", signedIn, scope(models.ScopeStreamsRead), streamService.GetStreamAudit)
+		apiRoutes.GET("/streams/:id/health", scope(models.ScopeStreamsRead), streamService.GetStreamHealth)
+		apiRoutes.POST("/streams/:id/heartbeat", scope(models.ScopeStreamsRead), streamService.ViewerHeartbeat)
+		apiRoutes.GET("/streams/:id/analytics", signedIn, scope(models.ScopeStatsRead), streamService.GetStreamAnalytics)
+		apiRoutes.GET("/users/:id/stream-analytics", signedIn, scope(models.ScopeStatsRead), streamService.CompareStreams)
+		apiRoutes.GET("/streams/:id/latency", scope(models.ScopeStatsRead), streamService.GetStreamLatency)
+		apiRoutes.POST("/streams/:id/latency", scope(models.ScopeLatencyWrite), streamService.ReportLatency)
+
+		// Creator dashboard, everything a broadcaster watches while live in one payload
+		apiRoutes.GET("/dashboard/:user_id", signedIn, scope(models.ScopeStreamsRead), dashboardService.GetCreatorDashboard)
+
+		// Ingest discovery, the nearest media servers for a publisher
+		apiRoutes.GET("/ingest/endpoints", ingestRouter.GetIngestEndpoints)
+
+		// VOD catalog
+		apiRoutes.GET("/vods", scope(models.ScopeVODsRead), vodService.ListVODs)
+		apiRoutes.GET("/vods/:id", scope(models.ScopeVODsRead), vodService.GetVODByID)
+		apiRoutes.GET("/users/:id/vods", scope(models.ScopeVODsRead), vodService.GetUserVODs)
+		apiRoutes.DELETE("/vods/:id", signedIn, scope(models.ScopeVODsWrite), softDeleteService.DeleteVOD)
+		apiRoutes.POST("/vods/:id/restore", signedIn, scope(models.ScopeVODsWrite), softDeleteService.RestoreVOD)
+		apiRoutes.POST("/vods/:id/premiere", signedIn, scope(models.ScopeVODsWrite), premiereService.SchedulePremiere)
+		apiRoutes.DELETE("/vods/:id/premiere", signedIn, scope(models.ScopeVODsWrite), premiereService.CancelPremiere)
+
+		// Clips
+		apiRoutes.POST("/streams/:id/clips", signedIn, scope(models.ScopeClipsWrite), clipService.CreateClip)
+		apiRoutes.GET("/streams/:id/clips", scope(models.ScopeClipsRead), clipService.GetStreamClips)
+		apiRoutes.GET("/clips/:id", scope(models.ScopeClipsRead), clipService.GetClipByID)
+
+		// Takedowns, for the creator of the claimed content
+		apiRoutes.GET("/takedowns/:id", signedIn, scope(models.ScopeStreamsRead), takedownService.GetCreatorTakedown)
+		apiRoutes.POST("/takedowns/:id/counter-notice", signedIn, scope(models.ScopeStreamsWrite), takedownService.SubmitCounterNotice)
+
+		// Restreaming
+		apiRoutes.GET("/users/:id/restream-targets", signedIn, scope(models.ScopeRestreamRead), restreamService.ListTargets)
+		apiRoutes.POST("/users/:id/restream-targets", signedIn, scope(models.ScopeRestreamWrite), restreamService.CreateTarget)
+		apiRoutes.PATCH("/restream-targets/:id", signedIn, scope(models.ScopeRestreamWrite), restreamService.UpdateTarget)
+		apiRoutes.DELETE("/restream-targets/:id", signedIn, scope(models.ScopeRestreamWrite), restreamService.DeleteTarget)
+
+		// A user's data, erased or exported by an async job
+		apiRoutes.DELETE("/users/:id/data", signedIn, scope(models.ScopeUserDataWrite), userDataService.EraseUserData)
+		apiRoutes.GET("/users/:id/export", signedIn, scope(models.ScopeUserDataRead), userDataService.ExportUserData)
+		apiRoutes.GET("/users/:id/data-jobs/:job_id", signedIn, scope(models.ScopeUserDataRead), userDataService.GetUserDataJob)
+
+		// Squads, several live streams watched together
+		apiRoutes.POST("/squads", signedIn, scope(models.ScopeStreamsWrite), squadService.CreateSquad)
+		apiRoutes.GET("/squads/:id", scope(models.ScopeStreamsRead), squadService.GetSquad)
+		apiRoutes.POST("/squads/:id/join", signedIn, scope(models.ScopeStreamsWrite), squadService.JoinSquad)
+		apiRoutes.POST("/squads/:id/leave", signedIn, scope(models.ScopeStreamsWrite), squadService.LeaveSquad)
+		apiRoutes.GET("/squads/:id/playback", scope(models.ScopeStreamsRead), squadService.GetSquadPlayback)
+
+		// Usage of the calling API key
+		apiRoutes.GET("/usage", apiKeyService.GetOwnUsage)
 
 		// Additional API endpoints
-		apiRoutes.GET("/stats", func(c *gin.Context) {
+		apiRoutes.GET("/stats/history", scope(models.ScopeStatsRead), streamService.GetStatsHistory)
+		apiRoutes.GET("/stats", scope(models.ScopeStatsRead), func(c *gin.Context) {
 			stats, err := streamService.GetPlatformStats()
 			if err != nil {
 				c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
@@ -162,7 +463,7 @@ func main() {
 			c.JSON(http.StatusOK, stats)
 		})
 
-		apiRoutes.GET("/user/:user_id/streams", func(c *gin.Context) {
+		apiRoutes.GET("/user/:user_id/streams", scope(models.ScopeStreamsRead), func(c *gin.Context) {
 			userID := c.Param("user_id")
 			// TODO: Convert userID to int64 and get streams
 			// For now, return placeholder
@@ -173,11 +474,9 @@ func main() {
 			})
 		})
 
-		apiRoutes.POST("/streams/:id/viewers", func(c *gin.Context) {
+		apiRoutes.POST("/streams/:id/viewers", signedIn, scope(models.ScopeStreamsWrite), func(c *gin.Context) {
 			streamID := c.Param("id")
-			var req struct {
-				ViewerCount int `json:"viewer_count"`
-			}
+			var req service.ViewerCountRequest
 			if err := c.ShouldBindJSON(&req); err != nil {
 				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 				return
@@ -203,6 +502,23 @@ func main() {
 					"RTMP authentication",
 					"Stream lifecycle management",
 					"Recording callbacks",
+					"Stream health",
+					"Stream health alerts",
+					"Latency probes",
+					"Stream classification",
+					"Distributed tracing",
+					"Follower counts",
+					"Squad streams",
+					"VOD catalog",
+					"Clips",
+					"Premieres",
+					"Takedowns",
+					"Audio fingerprinting",
+					"Stream termination and key bans",
+					"Stream audit log",
+					"Restreaming",
+					"API keys",
+					"Rate limiting",
 					"Session management",
 					"gRPC API",
 				},
@@ -210,6 +526,106 @@ func main() {
 		})
 	}
 
+	// API v2 serves the v1 handlers with paginated lists and typed errors
+	apiV2Routes := router.Group("/api/v2")
+	apiV2Routes.Use(service.UseAPIVersion(service.APIV2), apiKeyService.Authenticate(), viewerAuth.Identify(), rateLimiter.Limit("api"), service.AuditOrigin(service.AuditSourceAPI), service.ValidateRequests(apiSpec))
+	{
+		apiV2Routes.GET("/streams", scope(models.ScopeStreamsRead), streamService.GetActiveStreams)
+		apiV2Routes.GET("/streams/:id", scope(models.ScopeStreamsRead), streamService.GetStreamByID)
+
+		apiV2Routes.GET("/vods", scope(models.ScopeVODsRead), vodService.ListVODs)
+		apiV2Routes.GET("/vods/:id", scope(models.ScopeVODsRead), vodService.GetVODByID)
+		apiV2Routes.GET("/users/:id/vods", scope(models.ScopeVODsRead), vodService.GetUserVODs)
+	}
+
+	// Admin routes
+	adminRoutes := router.Group("/admin")
+	adminRoutes.Use(server.AdminAuthMiddleware(cfg.AdminToken, cfg.Environment), service.AuditOrigin(service.AuditSourceAdmin))
+	{
+		adminRoutes.POST("/api-keys", apiKeyService.CreateAPIKey)
+		adminRoutes.GET("/api-keys", apiKeyService.ListAPIKeys)
+		adminRoutes.DELETE("/api-keys/:id", apiKeyService.RevokeAPIKey)
+		adminRoutes.GET("/api-keys/:id/usage", apiKeyService.GetAPIKeyUsage)
+
+		adminRoutes.POST("/takedowns", takedownService.CreateTakedown)
+		adminRoutes.GET("/takedowns", takedownService.ListTakedowns)
+		adminRoutes.GET("/takedowns/:id", takedownService.GetTakedown)
+		adminRoutes.POST("/takedowns/:id/status", takedownService.UpdateTakedownStatus)
+		adminRoutes.POST("/vods/:id/fingerprint", fingerprintService.RescanVOD)
+		adminRoutes.POST("/vods/:id/package", vodPackager.RepackageVOD)
+
+		// Abuse response
+		adminRoutes.POST("/streams/:id/terminate", moderationService.TerminateStream)
+		adminRoutes.GET("/streams/:id/audit", streamService.GetStreamAuditAdmin)
+		adminRoutes.POST("/stream-keys/:key/ban", moderationService.BanStreamKey)
+		adminRoutes.GET("/stream-keys/:key/ban", moderationService.GetStreamKeyBan)
+		adminRoutes.DELETE("/stream-keys/:key/ban", moderationService.UnbanStreamKey)
+
+		// Directory curation
+		adminRoutes.PUT("/streams/:id/featured", streamService.FeatureStream)
+		adminRoutes.DELETE("/streams/:id/featured", streamService.UnfeatureStream)
+		adminRoutes.GET("/featured-categories", streamService.ListFeaturedCategories)
+		adminRoutes.PUT("/featured-categories/:category", streamService.FeatureCategory)
+		adminRoutes.DELETE("/featured-categories/:category", streamService.UnfeatureCategory)
+
+		// Incident annotations for analytics
+		adminRoutes.GET("/incidents", streamService.ListIncidents)
+		adminRoutes.POST("/incidents", streamService.CreateIncident)
+		adminRoutes.PUT("/incidents/:id", streamService.UpdateIncident)
+		adminRoutes.DELETE("/incidents/:id", streamService.DeleteIncident)
+
+		// Stream titles the content filter flagged
+		adminRoutes.GET("/content-reviews", streamService.ListContentReviews)
+		adminRoutes.POST("/content-reviews/:id", streamService.ResolveContentReview)
+
+		// Live transcoding ladders by user tier
+		adminRoutes.GET("/quality-ladders", qualityLadderService.ListQualityLadders)
+		adminRoutes.GET("/quality-ladders/:tier", qualityLadderService.GetQualityLadder)
+		adminRoutes.PUT("/quality-ladders/:tier", qualityLadderService.PutQualityLadder)
+		adminRoutes.DELETE("/quality-ladders/:tier", qualityLadderService.DeleteQualityLadder)
+		adminRoutes.PUT("/users/:id/tier", qualityLadderService.SetUserTier)
+		adminRoutes.GET("/users/:id/quality-ladder", qualityLadderService.GetUserQualityLadder)
+
+		// Live platform stats, stream starts and stops and error rates for ops dashboards
+		adminRoutes.GET("/dashboard/events", opsDashboardService.StreamEvents)
+
+		// Events consumers gave up on, to inspect and replay
+		adminRoutes.GET("/dead-letters", deadLetterService.ListDeadLetters)
+		adminRoutes.GET("/dead-letters/:id", deadLetterService.GetDeadLetter)
+		adminRoutes.POST("/dead-letters/:id/replay", deadLetterService.ReplayDeadLetter)
+		adminRoutes.DELETE("/dead-letters/:id", deadLetterService.DiscardDeadLetter)
+
+		// Tunables in effect and when they were last reloaded
+		adminRoutes.GET("/config", dynamicConfigService.GetConfig)
+
+		// Latency and errors injected into dependency calls, outside production only
+		adminRoutes.GET("/faults", faultInjectionService.GetFaults)
+		adminRoutes.PUT("/faults", faultInjectionService.SetFaults)
+		adminRoutes.DELETE("/faults", faultInjectionService.ClearFaults)
+
+		// Scheduled background jobs, to check on and run right away
+		adminRoutes.GET("/jobs", jobService.ListJobs)
+		adminRoutes.POST("/jobs/:name/run", jobService.RunJob)
+	}
+
+	// Recordings that didn't make it to S3, listed for admins next to the public API
+	recordingRoutes := router.Group("/api/v1/recordings")
+	recordingRoutes.Use(server.AdminAuthMiddleware(cfg.AdminToken, cfg.Environment))
+	{
+		recordingRoutes.GET("/failed", recordingService.ListFailedRecordings)
+	}
+
+	// StreamService RPCs as REST, generated from the proto for callers that can't speak gRPC
+	gatewayHandler, err := server.NewGatewayHandler(context.Background(), cfg, streamService, streamKeyService, qualityLadderService, userClient)
+	if err != nil {
+		fatal("❌ Failed to set up gRPC gateway", "error", err)
+	}
+	gatewayRoutes := router.Group("/rpc/v1")
+	gatewayRoutes.Use(server.AdminAuthMiddleware(cfg.AdminToken, cfg.Environment))
+	{
+		gatewayRoutes.Any("/*path", gin.WrapH(gatewayHandler))
+	}
+
 	// Debug routes (only in development)
 	if cfg.Environment == "development" {
 		debugRoutes := router.Group("/debug")
@@ -228,12 +644,11 @@ func main() {
 			})
 
 			debugRoutes.POST("/cleanup", func(c *gin.Context) {
-				err := streamService.CleanupExpiredStreams()
-				if err != nil {
-					c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+				if err := jobService.Trigger(service.JobCleanupExpiredStreams); err != nil {
+					c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
 					return
 				}
-				c.JSON(http.StatusOK, gin.H{"message": "Cleanup completed"})
+				c.JSON(http.StatusAccepted, gin.H{"message": "Cleanup started"})
 			})
 
 			// Test stream creation
@@ -251,7 +666,7 @@ func main() {
 				now := time.Now()
 				testStream.StartedAt = &now
 
-				streamID, err := streamService.CreateStream(testStream)
+				streamID, err := streamService.CreateStream(c.Request.Context(), testStream)
 				if err != nil {
 					c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 					return
@@ -296,76 +711,104 @@ func main() {
 	}
 
 	// Start background tasks
-	log.Println("⏰ Starting background tasks...")
+	slog.Info("⏰ Starting background tasks...")
 	var wg sync.WaitGroup
+	bgCtx, bgCancel := context.WithCancel(context.Background())
+	defer bgCancel()
 
-	// Cleanup task
-	wg.Add(1)
-	go func() {
-		defer wg.Done()
-		ticker := time.NewTicker(5 * time.Minute)
-		defer ticker.Stop()
+	// Clip workers
+	clipService.StartWorkers(bgCtx)
+	fingerprintService.StartWorkers(bgCtx)
+	vodPackager.StartWorkers(bgCtx)
 
-		for range ticker.C {
-			if err := streamService.CleanupExpiredStreams(); err != nil {
-				log.Printf("⚠️ Error in cleanup task: %v", err)
-			}
-		}
-	}()
+	// VODs replayed as live events
+	premiereService.StartScheduler(bgCtx)
+
+	// Follow cache fed by user service events
+	streamService.StartFollowConsumer(bgCtx, deadLetterService, dynamoRepo)
+
+	// Concurrent viewer samples for stream analytics
+	streamService.StartViewerSampler(bgCtx)
+
+	// Hourly and daily platform stats history
+	streamService.StartStatsAggregator(bgCtx)
+
+	// Scheduled jobs: streams whose status drifted from what the media server publishes are
+	// fixed, deleted streams and VODs past the time they can be restored are purged
+	jobService.Start(bgCtx)
+
+	// Drains events spooled while Kinesis was unavailable
+	streamService.StartEventReplayer(bgCtx)
+
+	// Relays the stream lifecycle events written to the outbox with their stream changes
+	streamService.StartOutboxRelay(bgCtx)
+
+	// Streams that reached the duration their publisher was authorized for are ended
+	streamLimitService.StartDurationEnforcer(bgCtx)
+
+	// Recordings on their way to S3, and those stuck or failed on the way
+	recordingService.StartUploadWorkers(bgCtx)
+	recordingService.StartRetryWorker(bgCtx)
+
+	// Feeds the ops dashboards connected to this replica
+	opsDashboardService.Start(bgCtx)
+
+	// User data erasures and exports
+	userDataService.StartWorker(bgCtx)
+
+	// Ends streams whose broadcaster didn't reconnect in time, also running without a grace
+	// period since a reload can set one
+	streamService.StartReconnectFinalizer(bgCtx)
+
+	// Reloads the tunables when the dynamic config changes
+	dynamicConfigService.Start(bgCtx)
 
 	// Start HTTP server in goroutine
 	wg.Add(1)
 	go func() {
 		defer wg.Done()
-		log.Printf("✅ Stream Management Service HTTP server started on port %s", port)
-		log.Printf("📡 RTMP callbacks: http://localhost:%s/rtmp/*", port)
-		log.Printf("🔌 API endpoints: http://localhost:%s/api/v1/*", port)
-		log.Printf("🏥 Health check: http://localhost:%s/health", port)
+		slog.Info("✅ Stream Management Service HTTP server started", "port", port)
+		slog.Info("📡 RTMP callbacks", "url", fmt.Sprintf("http://localhost:%s/rtmp/*", port))
+		slog.Info("🔌 API endpoints", "url", fmt.Sprintf("http://localhost:%s/api/v1/*", port))
+		slog.Info("🏥 Health check", "url", fmt.Sprintf("http://localhost:%s/health", port))
 
 		if cfg.Environment == "development" {
-			log.Printf("🐛 Debug endpoints: http://localhost:%s/debug/*", port)
-			log.Printf("🧪 Test stream creation: POST http://localhost:%s/debug/test-stream", port)
+			slog.Info("🐛 Debug endpoints", "url", fmt.Sprintf("http://localhost:%s/debug/*", port))
+			slog.Info("🧪 Test stream creation", "url", fmt.Sprintf("POST http://localhost:%s/debug/test-stream", port))
 		}
 
 		if grpcServer != nil {
-			log.Printf("🚀 gRPC server: grpcurl -plaintext localhost:9090 list")
+			slog.Info("🚀 gRPC server", "try", "grpcurl -plaintext localhost:9090 list")
 		}
 
 		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-			log.Fatalf("❌ Failed to start HTTP server: %v", err)
+			fatal("❌ Failed to start HTTP server", "error", err)
 		}
 	}()
 
 	// Setup graceful shutdown
-	log.Println("✅ All services started successfully")
-	log.Println("📋 Service Summary:")
-	log.Printf("   • HTTP Server: :%s", port)
+	slog.Info("✅ All services started successfully")
+	summary := []any{"http_port", port, "environment", cfg.Environment, "version", Version}
 	if grpcServer != nil {
-		log.Printf("   • gRPC Server: :9090")
+		summary = append(summary, "grpc_port", 9090)
 	}
 	if userClient != nil {
-		log.Printf("   • User Service: %s", cfg.UserServiceGRPCAddr)
-	}
-	log.Printf("   • Environment: %s", cfg.Environment)
-	log.Printf("   • Version: %s", Version)
-	log.Println("🎯 Ready to handle RTMP streams!")
-
-	log.Println("")
-	log.Println("📖 Quick Start Guide:")
-	log.Printf("   1. Start your User Service (optional)")
-	log.Printf("   2. Start SRS Media Server: docker-compose up -d")
-	log.Printf("   3. Configure OBS with: rtmp://localhost:1935/live/YOUR_STREAM_KEY")
-	log.Printf("   4. Test health: curl http://localhost:%s/health", port)
-	if grpcServer != nil {
-		log.Printf("   5. Test gRPC: grpcurl -plaintext localhost:9090 list")
+		summary = append(summary, "user_service", cfg.UserServiceGRPCAddr)
+	}
+	slog.Info("📋 Service summary", summary...)
+	slog.Info("🎯 Ready to handle RTMP streams!")
+
+	if cfg.Environment == "development" {
+		slog.Info("📖 Quick start: start SRS with docker-compose up -d, then point OBS at rtmp://localhost:1935/live/YOUR_STREAM_KEY")
 	}
-	log.Println("")
 
 	// Wait for interrupt signal to gracefully shutdown
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
 	<-quit
-	log.Println("🛑 Shutting down servers...")
+	slog.Info("🛑 Shutting down servers...")
+	healthChecker.SetDraining()
+	bgCancel()
 
 	// Graceful shutdown with timeout
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
@@ -373,23 +816,181 @@ func main() {
 
 	// Shutdown HTTP server
 	if err := srv.Shutdown(ctx); err != nil {
-		log.Printf("❌ HTTP server forced to shutdown: %v", err)
+		slog.Error("❌ HTTP server forced to shutdown", "error", err)
 	} else {
-		log.Println("✅ HTTP server stopped gracefully")
+		slog.Info("✅ HTTP server stopped gracefully")
 	}
 
 	// Shutdown gRPC server
 	if grpcServer != nil {
-		log.Println("🛑 Stopping gRPC server...")
+		slog.Info("🛑 Stopping gRPC server...")
 		grpcServer.GracefulStop()
-		log.Println("✅ gRPC server stopped gracefully")
+		slog.Info("✅ gRPC server stopped gracefully")
+	}
+
+	// Streams stay live while the media server keeps them, their sessions have to outlast
+	// the downtime
+	if err := streamService.PersistLiveSessions(ctx); err != nil {
+		slog.Warn("⚠️ Could not persist stream sessions", "error", err)
+	}
+
+	// Requests are done, send the events they queued
+	if err := streamService.FlushEvents(ctx); err != nil {
+		slog.Warn("⚠️ Could not flush events", "error", err)
+	} else {
+		slog.Info("✅ Events flushed")
 	}
 
 	// Close external connections
 	if userClient != nil {
 		userClient.Close()
-		log.Println("✅ User service connection closed")
+		slog.Info("✅ User service connection closed")
+	}
+
+	if err := tracer.Shutdown(ctx); err != nil {
+		slog.Warn("⚠️ Could not flush traces", "error", err)
 	}
 
-	log.Println("👋 Stream Management Service shut down complete")
+	slog.Info("👋 Stream Management Service shut down complete")
+}
+
+// runMigrations deletes the service's tables and/or applies the pending schema migrations.
+// Deleting is refused in production.
+func runMigrations(cfg *config.Config, cleanup, migrate bool) error {
+	client, err := repository.NewDynamoDBClient(cfg)
+	if err != nil {
+		return err
+	}
+	migrator := migration.NewMigrator(client.DynamoDB, cfg)
+
+	if cleanup {
+		if cfg.Environment == "production" {
+			return fmt.Errorf("refusing to delete tables in production")
+		}
+		if err := migrator.Cleanup(); err != nil {
+			return err
+		}
+	}
+	if migrate {
+		if _, err := migrator.Migrate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// fatal logs at error level and exits, slog has no Fatal
+func fatal(msg string, args ...any) {
+	slog.Error(msg, args...)
+	os.Exit(1)
+}
+
+// buildHealthChecks lists the dependencies checked by the readiness probe and the detailed
+// health endpoint. The service can't serve without DynamoDB and Redis; without the user
+// service it falls back to development stream key validation.
+func buildHealthChecks(dynamoRepo *repository.DynamoDBRepository, redisRepo *repository.RedisRepository, userClient *grpcClient.UserServiceClient) []health.Check {
+	checks := []health.Check{
+		{Name: "dynamodb", Critical: true, Run: dynamoRepo.HealthCheck},
+		{Name: "redis", Critical: true, Run: redisRepo.HealthCheck},
+	}
+	if userClient != nil {
+		checks = append(checks, health.Check{
+			Name: "user_service",
+			Run: func(ctx context.Context) error {
+				return userClient.HealthCheck()
+			},
+		})
+	}
+	return checks
+}
+
+// buildPreflightChecks lists the dependencies verified at startup. Required checks stop the
+// service in strict mode; optional ones switch off the feature that depends on them.
+func buildPreflightChecks(cfg *config.Config, dynamoRepo *repository.DynamoDBRepository, redisRepo *repository.RedisRepository,
+	streamService *service.StreamService, clipService *service.ClipService, premiereService *service.PremiereService,
+	takedownService *service.TakedownService, fingerprintService *service.FingerprintService, vodPackager *service.VODPackager,
+	userClient **grpcClient.UserServiceClient) []preflight.Check {
+	return []preflight.Check{
+		{
+			Name:     "dynamodb",
+			Feature:  "stream storage",
+			Required: true,
+			Hint:     "provision the tables listed by --plan and run --migrate, or set ENVIRONMENT=development to create them locally",
+			Run: func() error {
+				if problems := dynamoRepo.VerifyTables(cfg); len(problems) > 0 {
+					return fmt.Errorf("%s", strings.Join(problems, "; "))
+				}
+				return nil
+			},
+		},
+		{
+			Name:     "redis",
+			Feature:  "stream sessions",
+			Required: true,
+			Hint:     fmt.Sprintf("check that Redis is running at %s (REDIS_ADDR)", cfg.RedisAddr),
+			Run:      redisRepo.Ping,
+		},
+		eventBusCheck(cfg, streamService),
+		{
+			Name:    "s3",
+			Feature: "recording uploads",
+			Hint:    fmt.Sprintf("create bucket '%s' (S3_BUCKET_NAME) and grant the service write access", cfg.S3BucketName),
+			Run:     streamService.VerifyRecordingBucket,
+			Disable: streamService.DisableRecordingUploads,
+		},
+		{
+			Name:    "user-service",
+			Feature: "stream key validation",
+			Hint:    fmt.Sprintf("check that the user service is reachable at %s (USER_SERVICE_GRPC_ADDR)", cfg.UserServiceGRPCAddr),
+			Run: func() error {
+				if *userClient == nil {
+					return fmt.Errorf("no connection to user service")
+				}
+				return (*userClient).HealthCheck()
+			},
+			Disable: func(err error) {
+				slog.Warn("⚠️ Falling back to development stream key validation", "error", err)
+				if *userClient != nil {
+					(*userClient).Close()
+					*userClient = nil
+				}
+			},
+		},
+		{
+			Name:    "ffmpeg",
+			Feature: "clips, premieres, takedown muting, audio fingerprinting and VOD packaging",
+			Hint:    "install ffmpeg or point FFMPEG_PATH at the binary",
+			Run:     clipService.VerifyFFmpeg,
+			Disable: func(err error) {
+				clipService.Disable(err)
+				premiereService.Disable(err)
+				takedownService.DisableMuting(err)
+				fingerprintService.Disable(err)
+				vodPackager.Disable(err)
+			},
+		},
+	}
+}
+
+// eventBusCheck verifies the Kinesis stream, Kafka topics or JetStream stream events are
+// published to
+func eventBusCheck(cfg *config.Config, streamService *service.StreamService) preflight.Check {
+	check := preflight.Check{
+		Name:    "kinesis",
+		Feature: "event publishing",
+		Hint:    fmt.Sprintf("create Kinesis stream '%s' (KINESIS_STREAM_NAME) in %s", cfg.KinesisStreamName, cfg.AWSRegion),
+		Run:     streamService.VerifyEventStream,
+		Disable: streamService.DisableEventPublishing,
+	}
+	switch cfg.EventBus {
+	case service.EventBusKafka:
+		check.Name = "kafka"
+		check.Hint = fmt.Sprintf("check that the brokers at %s (KAFKA_BROKERS) are reachable and have topic '%s' (KAFKA_DEFAULT_TOPIC) and the KAFKA_TOPICS ones",
+			strings.Join(cfg.KafkaBrokers, ","), cfg.KafkaDefaultTopic)
+	case service.EventBusNATS:
+		check.Name = "nats"
+		check.Hint = fmt.Sprintf("check that NATS is reachable at %s (NATS_URL) and has JetStream stream '%s', or set NATS_CREATE_STREAM=true",
+			cfg.NATSURL, eventbus.StreamName(eventbus.DomainStream))
+	}
+	return check
 }
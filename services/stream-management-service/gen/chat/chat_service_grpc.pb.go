@@ -19,12 +19,21 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	ChatService_CreateChatroom_FullMethodName = "/chat.ChatService/CreateChatroom"
-	ChatService_JoinChatroom_FullMethodName   = "/chat.ChatService/JoinChatroom"
-	ChatService_LeaveChatroom_FullMethodName  = "/chat.ChatService/LeaveChatroom"
-	ChatService_SendMessage_FullMethodName    = "/chat.ChatService/SendMessage"
-	ChatService_GetMessages_FullMethodName    = "/chat.ChatService/GetMessages"
-	ChatService_GetChatrooms_FullMethodName   = "/chat.ChatService/GetChatrooms"
+	ChatService_CreateChatroom_FullMethodName   = "/chat.ChatService/CreateChatroom"
+	ChatService_JoinChatroom_FullMethodName     = "/chat.ChatService/JoinChatroom"
+	ChatService_LeaveChatroom_FullMethodName    = "/chat.ChatService/LeaveChatroom"
+	ChatService_SendMessage_FullMethodName      = "/chat.ChatService/SendMessage"
+	ChatService_GetMessages_FullMethodName      = "/chat.ChatService/GetMessages"
+	ChatService_GetChatrooms_FullMethodName     = "/chat.ChatService/GetChatrooms"
+	ChatService_PinMessage_FullMethodName       = "/chat.ChatService/PinMessage"
+	ChatService_GetRoomSnapshot_FullMethodName  = "/chat.ChatService/GetRoomSnapshot"
+	ChatService_GetTopChatters_FullMethodName   = "/chat.ChatService/GetTopChatters"
+	ChatService_GetChatActivity_FullMethodName  = "/chat.ChatService/GetChatActivity"
+	ChatService_GetTopEmotes_FullMethodName     = "/chat.ChatService/GetTopEmotes"
+	ChatService_PostVODComment_FullMethodName   = "/chat.ChatService/PostVODComment"
+	ChatService_EditVODComment_FullMethodName   = "/chat.ChatService/EditVODComment"
+	ChatService_DeleteVODComment_FullMethodName = "/chat.ChatService/DeleteVODComment"
+	ChatService_GetVODComments_FullMethodName   = "/chat.ChatService/GetVODComments"
 )
 
 // ChatServiceClient is the client API for ChatService service.
@@ -37,6 +46,15 @@ type ChatServiceClient interface {
 	SendMessage(ctx context.Context, in *SendMessageRequest, opts ...grpc.CallOption) (*SendMessageResponse, error)
 	GetMessages(ctx context.Context, in *GetMessagesRequest, opts ...grpc.CallOption) (*GetMessagesResponse, error)
 	GetChatrooms(ctx context.Context, in *GetChatroomsRequest, opts ...grpc.CallOption) (*GetChatroomsResponse, error)
+	PinMessage(ctx context.Context, in *PinMessageRequest, opts ...grpc.CallOption) (*PinMessageResponse, error)
+	GetRoomSnapshot(ctx context.Context, in *GetRoomSnapshotRequest, opts ...grpc.CallOption) (*GetRoomSnapshotResponse, error)
+	GetTopChatters(ctx context.Context, in *GetTopChattersRequest, opts ...grpc.CallOption) (*GetTopChattersResponse, error)
+	GetChatActivity(ctx context.Context, in *GetChatActivityRequest, opts ...grpc.CallOption) (*GetChatActivityResponse, error)
+	GetTopEmotes(ctx context.Context, in *GetTopEmotesRequest, opts ...grpc.CallOption) (*GetTopEmotesResponse, error)
+	PostVODComment(ctx context.Context, in *PostVODCommentRequest, opts ...grpc.CallOption) (*PostVODCommentResponse, error)
+	EditVODComment(ctx context.Context, in *EditVODCommentRequest, opts ...grpc.CallOption) (*EditVODCommentResponse, error)
+	DeleteVODComment(ctx context.Context, in *DeleteVODCommentRequest, opts ...grpc.CallOption) (*DeleteVODCommentResponse, error)
+	GetVODComments(ctx context.Context, in *GetVODCommentsRequest, opts ...grpc.CallOption) (*GetVODCommentsResponse, error)
 }
 
 type chatServiceClient struct {
@@ -107,6 +125,96 @@ func (c *chatServiceClient) GetChatrooms(ctx context.Context, in *GetChatroomsRe
 	return out, nil
 }
 
+func (c *chatServiceClient) PinMessage(ctx context.Context, in *PinMessageRequest, opts ...grpc.CallOption) (*PinMessageResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(PinMessageResponse)
+	err := c.cc.Invoke(ctx, ChatService_PinMessage_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *chatServiceClient) GetRoomSnapshot(ctx context.Context, in *GetRoomSnapshotRequest, opts ...grpc.CallOption) (*GetRoomSnapshotResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetRoomSnapshotResponse)
+	err := c.cc.Invoke(ctx, ChatService_GetRoomSnapshot_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *chatServiceClient) GetTopChatters(ctx context.Context, in *GetTopChattersRequest, opts ...grpc.CallOption) (*GetTopChattersResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetTopChattersResponse)
+	err := c.cc.Invoke(ctx, ChatService_GetTopChatters_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *chatServiceClient) GetChatActivity(ctx context.Context, in *GetChatActivityRequest, opts ...grpc.CallOption) (*GetChatActivityResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetChatActivityResponse)
+	err := c.cc.Invoke(ctx, ChatService_GetChatActivity_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *chatServiceClient) GetTopEmotes(ctx context.Context, in *GetTopEmotesRequest, opts ...grpc.CallOption) (*GetTopEmotesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetTopEmotesResponse)
+	err := c.cc.Invoke(ctx, ChatService_GetTopEmotes_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *chatServiceClient) PostVODComment(ctx context.Context, in *PostVODCommentRequest, opts ...grpc.CallOption) (*PostVODCommentResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(PostVODCommentResponse)
+	err := c.cc.Invoke(ctx, ChatService_PostVODComment_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *chatServiceClient) EditVODComment(ctx context.Context, in *EditVODCommentRequest, opts ...grpc.CallOption) (*EditVODCommentResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(EditVODCommentResponse)
+	err := c.cc.Invoke(ctx, ChatService_EditVODComment_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *chatServiceClient) DeleteVODComment(ctx context.Context, in *DeleteVODCommentRequest, opts ...grpc.CallOption) (*DeleteVODCommentResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DeleteVODCommentResponse)
+	err := c.cc.Invoke(ctx, ChatService_DeleteVODComment_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *chatServiceClient) GetVODComments(ctx context.Context, in *GetVODCommentsRequest, opts ...grpc.CallOption) (*GetVODCommentsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetVODCommentsResponse)
+	err := c.cc.Invoke(ctx, ChatService_GetVODComments_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // ChatServiceServer is the server API for ChatService service.
 // All implementations must embed UnimplementedChatServiceServer
 // for forward compatibility.
@@ -117,6 +225,15 @@ type ChatServiceServer interface {
 	SendMessage(context.Context, *SendMessageRequest) (*SendMessageResponse, error)
 	GetMessages(context.Context, *GetMessagesRequest) (*GetMessagesResponse, error)
 	GetChatrooms(context.Context, *GetChatroomsRequest) (*GetChatroomsResponse, error)
+	PinMessage(context.Context, *PinMessageRequest) (*PinMessageResponse, error)
+	GetRoomSnapshot(context.Context, *GetRoomSnapshotRequest) (*GetRoomSnapshotResponse, error)
+	GetTopChatters(context.Context, *GetTopChattersRequest) (*GetTopChattersResponse, error)
+	GetChatActivity(context.Context, *GetChatActivityRequest) (*GetChatActivityResponse, error)
+	GetTopEmotes(context.Context, *GetTopEmotesRequest) (*GetTopEmotesResponse, error)
+	PostVODComment(context.Context, *PostVODCommentRequest) (*PostVODCommentResponse, error)
+	EditVODComment(context.Context, *EditVODCommentRequest) (*EditVODCommentResponse, error)
+	DeleteVODComment(context.Context, *DeleteVODCommentRequest) (*DeleteVODCommentResponse, error)
+	GetVODComments(context.Context, *GetVODCommentsRequest) (*GetVODCommentsResponse, error)
 	mustEmbedUnimplementedChatServiceServer()
 }
 
@@ -145,6 +262,33 @@ func (UnimplementedChatServiceServer) GetMessages(context.Context, *GetMessagesR
 func (UnimplementedChatServiceServer) GetChatrooms(context.Context, *GetChatroomsRequest) (*GetChatroomsResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetChatrooms not implemented")
 }
+func (UnimplementedChatServiceServer) PinMessage(context.Context, *PinMessageRequest) (*PinMessageResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PinMessage not implemented")
+}
+func (UnimplementedChatServiceServer) GetRoomSnapshot(context.Context, *GetRoomSnapshotRequest) (*GetRoomSnapshotResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetRoomSnapshot not implemented")
+}
+func (UnimplementedChatServiceServer) GetTopChatters(context.Context, *GetTopChattersRequest) (*GetTopChattersResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetTopChatters not implemented")
+}
+func (UnimplementedChatServiceServer) GetChatActivity(context.Context, *GetChatActivityRequest) (*GetChatActivityResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetChatActivity not implemented")
+}
+func (UnimplementedChatServiceServer) GetTopEmotes(context.Context, *GetTopEmotesRequest) (*GetTopEmotesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetTopEmotes not implemented")
+}
+func (UnimplementedChatServiceServer) PostVODComment(context.Context, *PostVODCommentRequest) (*PostVODCommentResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PostVODComment not implemented")
+}
+func (UnimplementedChatServiceServer) EditVODComment(context.Context, *EditVODCommentRequest) (*EditVODCommentResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method EditVODComment not implemented")
+}
+func (UnimplementedChatServiceServer) DeleteVODComment(context.Context, *DeleteVODCommentRequest) (*DeleteVODCommentResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteVODComment not implemented")
+}
+func (UnimplementedChatServiceServer) GetVODComments(context.Context, *GetVODCommentsRequest) (*GetVODCommentsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetVODComments not implemented")
+}
 func (UnimplementedChatServiceServer) mustEmbedUnimplementedChatServiceServer() {}
 func (UnimplementedChatServiceServer) testEmbeddedByValue()                     {}
 
@@ -274,6 +418,168 @@ func _ChatService_GetChatrooms_Handler(srv interface{}, ctx context.Context, dec
 	return interceptor(ctx, in, info, handler)
 }
 
+func _ChatService_PinMessage_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PinMessageRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChatServiceServer).PinMessage(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ChatService_PinMessage_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChatServiceServer).PinMessage(ctx, req.(*PinMessageRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ChatService_GetRoomSnapshot_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetRoomSnapshotRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChatServiceServer).GetRoomSnapshot(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ChatService_GetRoomSnapshot_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChatServiceServer).GetRoomSnapshot(ctx, req.(*GetRoomSnapshotRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ChatService_GetTopChatters_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetTopChattersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChatServiceServer).GetTopChatters(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ChatService_GetTopChatters_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChatServiceServer).GetTopChatters(ctx, req.(*GetTopChattersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ChatService_GetChatActivity_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetChatActivityRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChatServiceServer).GetChatActivity(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ChatService_GetChatActivity_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChatServiceServer).GetChatActivity(ctx, req.(*GetChatActivityRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ChatService_GetTopEmotes_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetTopEmotesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChatServiceServer).GetTopEmotes(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ChatService_GetTopEmotes_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChatServiceServer).GetTopEmotes(ctx, req.(*GetTopEmotesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ChatService_PostVODComment_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PostVODCommentRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChatServiceServer).PostVODComment(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ChatService_PostVODComment_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChatServiceServer).PostVODComment(ctx, req.(*PostVODCommentRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ChatService_EditVODComment_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EditVODCommentRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChatServiceServer).EditVODComment(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ChatService_EditVODComment_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChatServiceServer).EditVODComment(ctx, req.(*EditVODCommentRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ChatService_DeleteVODComment_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteVODCommentRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChatServiceServer).DeleteVODComment(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ChatService_DeleteVODComment_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChatServiceServer).DeleteVODComment(ctx, req.(*DeleteVODCommentRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ChatService_GetVODComments_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetVODCommentsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChatServiceServer).GetVODComments(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ChatService_GetVODComments_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChatServiceServer).GetVODComments(ctx, req.(*GetVODCommentsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // ChatService_ServiceDesc is the grpc.ServiceDesc for ChatService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -305,6 +611,42 @@ var ChatService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetChatrooms",
 			Handler:    _ChatService_GetChatrooms_Handler,
 		},
+		{
+			MethodName: "PinMessage",
+			Handler:    _ChatService_PinMessage_Handler,
+		},
+		{
+			MethodName: "GetRoomSnapshot",
+			Handler:    _ChatService_GetRoomSnapshot_Handler,
+		},
+		{
+			MethodName: "GetTopChatters",
+			Handler:    _ChatService_GetTopChatters_Handler,
+		},
+		{
+			MethodName: "GetChatActivity",
+			Handler:    _ChatService_GetChatActivity_Handler,
+		},
+		{
+			MethodName: "GetTopEmotes",
+			Handler:    _ChatService_GetTopEmotes_Handler,
+		},
+		{
+			MethodName: "PostVODComment",
+			Handler:    _ChatService_PostVODComment_Handler,
+		},
+		{
+			MethodName: "EditVODComment",
+			Handler:    _ChatService_EditVODComment_Handler,
+		},
+		{
+			MethodName: "DeleteVODComment",
+			Handler:    _ChatService_DeleteVODComment_Handler,
+		},
+		{
+			MethodName: "GetVODComments",
+			Handler:    _ChatService_GetVODComments_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "chat/chat_service.proto",
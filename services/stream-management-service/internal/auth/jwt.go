@@ -0,0 +1,289 @@
+// services/stream-management-service/internal/auth/jwt.go
+package auth
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/hmac"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// clockSkew is how far token timestamps may be off from our clock
+const clockSkew = 30 * time.Second
+
+// minJWKSRefresh stops tokens with unknown key IDs from hammering the JWKS endpoint
+const minJWKSRefresh = time.Minute
+
+var ErrInvalidToken = errors.New("invalid token")
+
+// Claims are the JWT claims the user service puts in its access tokens
+type Claims struct {
+	Subject   string   `json:"sub"`
+	Email     string   `json:"email,omitempty"`
+	Username  string   `json:"username,omitempty"`
+	Roles     []string `json:"roles,omitempty"`
+	Type      string   `json:"type,omitempty"` // access or refresh
+	Issuer    string   `json:"iss,omitempty"`
+	ExpiresAt int64    `json:"exp"`
+	NotBefore int64    `json:"nbf,omitempty"`
+}
+
+// UserID returns the user the token was issued to
+func (c *Claims) UserID() (int64, error) {
+	userID, err := strconv.ParseInt(c.Subject, 10, 64)
+	if err != nil || userID <= 0 {
+		return 0, fmt.Errorf("%w: subject is not a user ID", ErrInvalidToken)
+	}
+	return userID, nil
+}
+
+// Verifier checks JWT access tokens signed with the user service's shared secret (HS256)
+// or with a key published on a JWKS endpoint (RS256, ES256)
+type Verifier struct {
+	secret          []byte
+	jwksURL         string
+	issuer          string
+	refreshInterval time.Duration
+	httpClient      *http.Client
+
+	mu        sync.RWMutex
+	keys      map[string]crypto.PublicKey
+	fetchedAt time.Time
+}
+
+func NewVerifier(secret, jwksURL, issuer string, refreshInterval time.Duration) *Verifier {
+	return &Verifier{
+		secret:          []byte(secret),
+		jwksURL:         jwksURL,
+		issuer:          issuer,
+		refreshInterval: refreshInterval,
+		httpClient:      &http.Client{Timeout: 5 * time.Second},
+		keys:            make(map[string]crypto.PublicKey),
+	}
+}
+
+// Enabled reports whether any verification key is configured
+func (v *Verifier) Enabled() bool {
+	return len(v.secret) > 0 || v.jwksURL != ""
+}
+
+type header struct {
+	Alg string `json:"alg"`
+	Kid string `json:"kid"`
+}
+
+// Verify checks the token's signature and lifetime and returns its claims. Refresh tokens
+// are rejected.
+func (v *Verifier) Verify(token string) (*Claims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("%w: malformed token", ErrInvalidToken)
+	}
+
+	var hdr header
+	if err := decodeSegment(parts[0], &hdr); err != nil {
+		return nil, fmt.Errorf("%w: bad header: %v", ErrInvalidToken, err)
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, fmt.Errorf("%w: bad signature encoding", ErrInvalidToken)
+	}
+
+	if err := v.verifySignature(hdr, parts[0]+"."+parts[1], signature); err != nil {
+		return nil, err
+	}
+
+	var claims Claims
+	if err := decodeSegment(parts[1], &claims); err != nil {
+		return nil, fmt.Errorf("%w: bad claims: %v", ErrInvalidToken, err)
+	}
+
+	now := time.Now()
+	if claims.ExpiresAt == 0 || now.After(time.Unix(claims.ExpiresAt, 0).Add(clockSkew)) {
+		return nil, fmt.Errorf("%w: token expired", ErrInvalidToken)
+	}
+	if claims.NotBefore != 0 && now.Add(clockSkew).Before(time.Unix(claims.NotBefore, 0)) {
+		return nil, fmt.Errorf("%w: token not valid yet", ErrInvalidToken)
+	}
+	if claims.Type != "" && claims.Type != "access" {
+		return nil, fmt.Errorf("%w: not an access token", ErrInvalidToken)
+	}
+	if v.issuer != "" && claims.Issuer != v.issuer {
+		return nil, fmt.Errorf("%w: unexpected issuer", ErrInvalidToken)
+	}
+
+	return &claims, nil
+}
+
+func (v *Verifier) verifySignature(hdr header, signed string, signature []byte) error {
+	switch hdr.Alg {
+	case "HS256":
+		if len(v.secret) == 0 {
+			return fmt.Errorf("%w: HS256 tokens are not accepted", ErrInvalidToken)
+		}
+		mac := hmac.New(sha256.New, v.secret)
+		mac.Write([]byte(signed))
+		if !hmac.Equal(signature, mac.Sum(nil)) {
+			return fmt.Errorf("%w: signature mismatch", ErrInvalidToken)
+		}
+		return nil
+
+	case "RS256", "ES256":
+		key, err := v.publicKey(hdr.Kid)
+		if err != nil {
+			return err
+		}
+		digest := sha256.Sum256([]byte(signed))
+
+		switch key := key.(type) {
+		case *rsa.PublicKey:
+			if hdr.Alg == "RS256" && rsa.VerifyPKCS1v15(key, crypto.SHA256, digest[:], signature) == nil {
+				return nil
+			}
+		case *ecdsa.PublicKey:
+			if hdr.Alg == "ES256" && len(signature) == 64 {
+				r := new(big.Int).SetBytes(signature[:32])
+				s := new(big.Int).SetBytes(signature[32:])
+				if ecdsa.Verify(key, digest[:], r, s) {
+					return nil
+				}
+			}
+		}
+		return fmt.Errorf("%w: signature mismatch", ErrInvalidToken)
+
+	default:
+		// "none" and anything else we don't know lands here
+		return fmt.Errorf("%w: unsupported algorithm %q", ErrInvalidToken, hdr.Alg)
+	}
+}
+
+// publicKey returns a JWKS key by ID, refetching the key set when it's stale or the key is
+// unknown, e.g. after the issuer rotated its keys
+func (v *Verifier) publicKey(kid string) (crypto.PublicKey, error) {
+	if v.jwksURL == "" {
+		return nil, fmt.Errorf("%w: no JWKS endpoint configured", ErrInvalidToken)
+	}
+
+	v.mu.RLock()
+	key, ok := v.keys[kid]
+	stale := time.Since(v.fetchedAt) > v.refreshInterval
+	recent := time.Since(v.fetchedAt) < minJWKSRefresh
+	v.mu.RUnlock()
+
+	if ok && !stale {
+		return key, nil
+	}
+	if !ok && recent {
+		return nil, fmt.Errorf("%w: unknown key %q", ErrInvalidToken, kid)
+	}
+
+	if err := v.refresh(); err != nil {
+		if ok {
+			// Keep using the key we have rather than failing every request
+			return key, nil
+		}
+		return nil, err
+	}
+
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+	if key, ok := v.keys[kid]; ok {
+		return key, nil
+	}
+	return nil, fmt.Errorf("%w: unknown key %q", ErrInvalidToken, kid)
+}
+
+type jwks struct {
+	Keys []struct {
+		Kty string `json:"kty"`
+		Kid string `json:"kid"`
+		N   string `json:"n"`
+		E   string `json:"e"`
+		Crv string `json:"crv"`
+		X   string `json:"x"`
+		Y   string `json:"y"`
+	} `json:"keys"`
+}
+
+// refresh fetches the key set from the JWKS endpoint
+func (v *Verifier) refresh() error {
+	resp, err := v.httpClient.Get(v.jwksURL)
+	if err != nil {
+		return fmt.Errorf("failed to fetch jwks: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed to fetch jwks: status %d", resp.StatusCode)
+	}
+
+	var set jwks
+	if err := json.NewDecoder(resp.Body).Decode(&set); err != nil {
+		return fmt.Errorf("failed to decode jwks: %w", err)
+	}
+
+	keys := make(map[string]crypto.PublicKey, len(set.Keys))
+	for _, k := range set.Keys {
+		switch k.Kty {
+		case "RSA":
+			n, errN := base64.RawURLEncoding.DecodeString(k.N)
+			e, errE := base64.RawURLEncoding.DecodeString(k.E)
+			if errN != nil || errE != nil {
+				continue
+			}
+			keys[k.Kid] = &rsa.PublicKey{
+				N: new(big.Int).SetBytes(n),
+				E: int(new(big.Int).SetBytes(e).Int64()),
+			}
+		case "EC":
+			if k.Crv != "P-256" {
+				continue
+			}
+			x, errX := base64.RawURLEncoding.DecodeString(k.X)
+			y, errY := base64.RawURLEncoding.DecodeString(k.Y)
+			if errX != nil || errY != nil {
+				continue
+			}
+			keys[k.Kid] = &ecdsa.PublicKey{
+				Curve: elliptic.P256(),
+				X:     new(big.Int).SetBytes(x),
+				Y:     new(big.Int).SetBytes(y),
+			}
+		}
+	}
+
+	v.mu.Lock()
+	v.keys = keys
+	v.fetchedAt = time.Now()
+	v.mu.Unlock()
+
+	return nil
+}
+
+// Refresh loads the key set up front so the first requests don't wait on the JWKS endpoint
+func (v *Verifier) Refresh() error {
+	if v.jwksURL == "" {
+		return nil
+	}
+	return v.refresh()
+}
+
+func decodeSegment(segment string, out interface{}) error {
+	raw, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(raw, out)
+}
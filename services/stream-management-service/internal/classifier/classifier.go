@@ -0,0 +1,188 @@
+// services/stream-management-service/internal/classifier/classifier.go
+package classifier
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// Input is what a stream is classified from
+type Input struct {
+	Title       string
+	Description string
+	// History holds the categories and tags of the channel's previous streams, newest first
+	History []HistoryEntry
+}
+
+type HistoryEntry struct {
+	Category string
+	Tags     []string
+}
+
+// Suggestion is the category and tags a classifier proposes for a stream
+type Suggestion struct {
+	Category   string   `json:"category" dynamodbav:"category"`
+	Tags       []string `json:"tags" dynamodbav:"tags"`
+	Confidence float64  `json:"confidence" dynamodbav:"confidence"` // 0-1
+	Reasons    []string `json:"reasons,omitempty" dynamodbav:"reasons,omitempty"`
+}
+
+// Classifier suggests a category and tags for a stream
+type Classifier interface {
+	Classify(input Input) Suggestion
+}
+
+// Weights of the signals combined by the keyword classifier
+const (
+	titleWeight   = 1.0
+	historyWeight = 0.6
+	maxTags       = 5
+)
+
+// KeywordClassifier matches title keywords against a fixed taxonomy and falls back
+// to what the channel usually streams
+type KeywordClassifier struct {
+	categories map[string][]string // category -> keywords
+	tags       map[string][]string // tag -> keywords
+}
+
+func NewKeywordClassifier() *KeywordClassifier {
+	return &KeywordClassifier{
+		categories: map[string][]string{
+			"gaming":        {"game", "gaming", "gameplay", "playthrough", "speedrun", "ranked", "minecraft", "fortnite", "valorant", "league", "elden", "zelda", "pokemon", "cod", "fps", "rpg", "mmo"},
+			"just-chatting": {"chat", "chatting", "talk", "q&a", "qa", "ama", "hangout", "podcast"},
+			"music":         {"music", "song", "songs", "guitar", "piano", "dj", "singing", "karaoke", "beats", "producing"},
+			"art":           {"art", "drawing", "painting", "sketch", "illustration", "animation", "3d", "blender"},
+			"science-tech":  {"coding", "programming", "code", "dev", "developer", "golang", "python", "javascript", "hacking", "electronics", "science"},
+			"sports":        {"sports", "football", "soccer", "basketball", "tennis", "fitness", "workout", "running"},
+			"irl":           {"irl", "travel", "cooking", "walk", "outdoors", "vlog", "food"},
+		},
+		tags: map[string][]string{
+			"speedrun":          {"speedrun", "any%", "wr"},
+			"competitive":       {"ranked", "tournament", "competitive", "scrims"},
+			"chill":             {"chill", "relaxing", "cozy", "lofi"},
+			"educational":       {"learn", "learning", "tutorial", "howto", "educational"},
+			"first-playthrough": {"first", "blind"},
+			"music":             {"music", "lofi", "beats"},
+			"english":           {"english", "eng"},
+			"subathon":          {"subathon"},
+		},
+	}
+}
+
+// Classify scores every category by title keywords plus the channel's history and returns the best one
+func (k *KeywordClassifier) Classify(input Input) Suggestion {
+	words := tokenize(input.Title + " " + input.Description)
+
+	scores := make(map[string]float64)
+	var reasons []string
+
+	for category, keywords := range k.categories {
+		if matched := matchKeywords(words, keywords); len(matched) > 0 {
+			scores[category] += titleWeight * float64(len(matched))
+			reasons = append(reasons, category+" keywords in title: "+strings.Join(matched, ", "))
+		}
+	}
+	sort.Strings(reasons)
+
+	// Channels tend to stream the same thing, weigh recent streams higher
+	if len(input.History) > 0 {
+		counts := make(map[string]float64)
+		var total float64
+		for i, entry := range input.History {
+			if entry.Category == "" {
+				continue
+			}
+			weight := 1.0 / float64(i+1)
+			counts[entry.Category] += weight
+			total += weight
+		}
+		for category, count := range counts {
+			scores[category] += historyWeight * count / total
+		}
+		if total > 0 {
+			reasons = append(reasons, "channel history")
+		}
+	}
+
+	suggestion := Suggestion{Reasons: reasons}
+
+	var best string
+	var bestScore, totalScore float64
+	for category, score := range scores {
+		totalScore += score
+		if score > bestScore || (score == bestScore && category < best) {
+			best, bestScore = category, score
+		}
+	}
+	if best != "" {
+		suggestion.Category = best
+		// Confidence is the share of the winning category, damped when there is little signal
+		suggestion.Confidence = (bestScore / totalScore) * min(totalScore, 1)
+	}
+
+	tagSet := make(map[string]bool)
+	for tag, keywords := range k.tags {
+		if len(matchKeywords(words, keywords)) > 0 {
+			tagSet[tag] = true
+		}
+	}
+	// Tags the channel uses on most of its streams carry over
+	tagCounts := make(map[string]int)
+	for _, entry := range input.History {
+		for _, tag := range entry.Tags {
+			tagCounts[tag]++
+		}
+	}
+	for tag, count := range tagCounts {
+		if count*2 > len(input.History) {
+			tagSet[tag] = true
+		}
+	}
+
+	for tag := range tagSet {
+		suggestion.Tags = append(suggestion.Tags, tag)
+	}
+	sort.Strings(suggestion.Tags)
+	if len(suggestion.Tags) > maxTags {
+		suggestion.Tags = suggestion.Tags[:maxTags]
+	}
+
+	return suggestion
+}
+
+// NormalizeTags lowercases tags, turns spaces into dashes and drops duplicates and empty tags
+func NormalizeTags(tags []string) []string {
+	seen := make(map[string]bool)
+	normalized := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		tag = strings.Join(strings.Fields(strings.ToLower(tag)), "-")
+		if tag == "" || seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		normalized = append(normalized, tag)
+	}
+	return normalized
+}
+
+func tokenize(text string) map[string]bool {
+	words := make(map[string]bool)
+	for _, word := range strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '&' && r != '%'
+	}) {
+		words[word] = true
+	}
+	return words
+}
+
+func matchKeywords(words map[string]bool, keywords []string) []string {
+	var matched []string
+	for _, keyword := range keywords {
+		if words[keyword] {
+			matched = append(matched, keyword)
+		}
+	}
+	return matched
+}
@@ -0,0 +1,134 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/services/stream-management-service/pkg/callpolicy"
+)
+
+// Dependencies with a call policy, each tuned with CALL_POLICY_<DEPENDENCY>
+const (
+	DependencyUserServiceGRPC = "user_service_grpc"
+	DependencyUserServiceHTTP = "user_service_http" // REST fallback and viewer ages
+	DependencySRS             = "srs"
+	DependencyChatService     = "chat_service"
+)
+
+// defaultCallPolicies keeps the chat service to one attempt, its squad, raid and alert
+// requests post messages that would show up twice
+var defaultCallPolicies = map[string]callpolicy.Policy{
+	DependencyUserServiceGRPC: {Timeout: 5 * time.Second, Retries: 2, Backoff: 100 * time.Millisecond, MaxBackoff: time.Second, RetryBudget: 0.2},
+	DependencyUserServiceHTTP: {Timeout: 10 * time.Second, Retries: 1, Backoff: 200 * time.Millisecond, MaxBackoff: time.Second, RetryBudget: 0.1},
+	DependencySRS:             {Timeout: 5 * time.Second, Retries: 2, Backoff: 100 * time.Millisecond, MaxBackoff: time.Second, RetryBudget: 0.2},
+	DependencyChatService:     {Timeout: 5 * time.Second},
+}
+
+// Caller returns the caller that applies a dependency's call policy, the same one for every
+// client of the dependency so they share its retry budget. The policy is read from the
+// tunables on each call.
+func (c *Config) Caller(dependency string) *callpolicy.Caller {
+	c.callersMu.Lock()
+	defer c.callersMu.Unlock()
+
+	if caller, ok := c.callers[dependency]; ok {
+		return caller
+	}
+	if c.callers == nil {
+		c.callers = make(map[string]*callpolicy.Caller)
+	}
+	caller := callpolicy.New(dependency, func() callpolicy.Policy {
+		return c.Tunables().CallPolicies[dependency]
+	})
+	c.callers[dependency] = caller
+	return caller
+}
+
+// CallerStats returns what the callers of each dependency did, sorted by dependency
+func (c *Config) CallerStats() []callpolicy.Stats {
+	c.callersMu.Lock()
+	defer c.callersMu.Unlock()
+
+	stats := make([]callpolicy.Stats, 0, len(c.callers))
+	for _, caller := range c.callers {
+		stats = append(stats, caller.Stats())
+	}
+	sort.Slice(stats, func(i, j int) bool { return stats[i].Dependency < stats[j].Dependency })
+	return stats
+}
+
+// callPolicies reads each dependency's policy from CALL_POLICY_<DEPENDENCY>, a comma
+// separated list of settings on top of its defaults, e.g.
+// "timeout=2s,retries=3,backoff=50ms,max_backoff=500ms,hedge_after=300ms,retry_budget=0.1"
+func (p *tunableParser) callPolicies() map[string]callpolicy.Policy {
+	policies := make(map[string]callpolicy.Policy, len(defaultCallPolicies))
+	for dependency, policy := range defaultCallPolicies {
+		key := "CALL_POLICY_" + strings.ToUpper(dependency)
+		if value, ok := p.lookup(key); ok {
+			for _, setting := range strings.Split(value, ",") {
+				if setting = strings.TrimSpace(setting); setting == "" {
+					continue
+				}
+				if err := setCallPolicy(&policy, setting); err != nil {
+					p.invalid(key, setting, err)
+				}
+			}
+		}
+		policies[dependency] = policy
+		p.values[key] = formatCallPolicy(policy)
+	}
+	return policies
+}
+
+// setCallPolicy applies one name=value setting to policy, leaving it as it was when the
+// setting is invalid
+func setCallPolicy(policy *callpolicy.Policy, setting string) error {
+	name, value, ok := strings.Cut(setting, "=")
+	if !ok || value == "" {
+		return errors.New("expected name=value")
+	}
+
+	if name == "retries" {
+		retries, err := strconv.Atoi(value)
+		if err != nil || retries < 0 {
+			return errors.New("retries must be a number that isn't negative")
+		}
+		policy.Retries = retries
+		return nil
+	}
+	if name == "retry_budget" {
+		budget, err := strconv.ParseFloat(value, 64)
+		if err != nil || budget < 0 {
+			return errors.New("retry_budget must be a share of calls that isn't negative")
+		}
+		policy.RetryBudget = budget
+		return nil
+	}
+
+	durations := map[string]*time.Duration{
+		"timeout":     &policy.Timeout,
+		"backoff":     &policy.Backoff,
+		"max_backoff": &policy.MaxBackoff,
+		"hedge_after": &policy.HedgeAfter,
+	}
+	target, ok := durations[name]
+	if !ok {
+		return fmt.Errorf("unknown setting %s", name)
+	}
+	duration, err := time.ParseDuration(value)
+	if err != nil || duration < 0 {
+		return fmt.Errorf("%s must be a duration that isn't negative", name)
+	}
+	*target = duration
+	return nil
+}
+
+func formatCallPolicy(policy callpolicy.Policy) string {
+	return fmt.Sprintf("timeout=%s,retries=%d,backoff=%s,max_backoff=%s,hedge_after=%s,retry_budget=%s",
+		policy.Timeout, policy.Retries, policy.Backoff, policy.MaxBackoff, policy.HedgeAfter,
+		strconv.FormatFloat(policy.RetryBudget, 'f', -1, 64))
+}
@@ -3,59 +3,663 @@ package config
 import (
 	"os"
 	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
+
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/services/stream-management-service/pkg/callpolicy"
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/services/stream-management-service/pkg/faults"
 )
 
+// RateLimit is a token bucket refilled with Rate requests per minute that holds up to Burst
+type RateLimit struct {
+	Rate  int
+	Burst int
+}
+
+// IngestRegion is where publishers of a region send their streams
+type IngestRegion struct {
+	RTMPURL string
+	SRTURL  string
+	WHIPURL string
+}
+
 type Config struct {
 	// Server
-	Port        string
-	Environment string
+	Port          string
+	Environment   string
+	PreflightMode string // strict, degrade or off
+
+	// Health checks of the dependencies behind /readyz and /api/v1/health/detailed
+	HealthCheckTimeout  time.Duration // a dependency slower than this is reported down
+	HealthCheckCacheTTL time.Duration // how long a health report is reused across probes
 
 	// External Services
-	UserServiceGRPCAddr string
+	UserServiceGRPCAddr string // host:port resolved through DNS, or consul:///<service>
+	ChatServiceURL      string // HTTP API of the chat service, used for squad chat routing, health alerts and raids
+	PlaybackBaseURL     string // media server HTTP root that serves HLS
+	PlaybackOriginURL   string // RTMP URL of the media server vhost streams are republished to under their playback ID
+
+	// Service discovery, calls are balanced round robin over the user service replicas
+	ConsulAddr              string // HTTP API of the Consul agent for consul:/// addresses
+	ConsulToken             string
+	ServiceDiscoveryRefresh time.Duration // how often DNS names are resolved again
+	UserServiceSubsetSize   int           // replicas connected to, 0 connects to all of them
+	UserServiceHealthCheck  bool          // skip replicas whose gRPC health service isn't serving
 
 	// AWS / DynamoDB
 	AWSRegion         string
 	DynamoDBTableName string
+	VODTableName      string
+	ClipTableName     string
+	RestreamTableName string
+	APIKeyTableName   string
+	TakedownTableName string
+	StreamKeyBanTable string
+	StatsTableName    string
+	AuditTableName    string
+	OutboxTableName   string
+	DeadLetterTable   string
+	DedupeTableName   string
+	MigrationsTable   string
 	DynamoDBEndpoint  string
 	KinesisStreamName string
 	S3BucketName      string
 
+	// DynamoDB global tables, reads go to AWSRegion first and fall back to the other regions
+	// in order
+	DynamoDBRegions     []string
+	DynamoDBWriteRegion string // region all writes go to, or "local" for AWSRegion
+
 	// Redis
-	RedisAddr     string
-	RedisPassword string
-	RedisDB       int
+	RedisMode             string   // standalone, sentinel or cluster
+	RedisAddr             string   // the server in standalone mode
+	RedisAddrs            []string // the sentinels, or the cluster nodes to discover the others from
+	RedisSentinelMaster   string   // name the sentinels monitor the master under
+	RedisPassword         string
+	RedisSentinelPassword string
+	RedisDB               int // not supported by cluster mode
+
+	// Redis connection pool, per node in cluster mode
+	RedisPoolSize        int           // 10 connections per CPU when 0
+	RedisMinIdleConns    int           // kept open to avoid dialing on a burst
+	RedisPoolTimeout     time.Duration // how long a command waits for a free connection
+	RedisDialTimeout     time.Duration
+	RedisReadTimeout     time.Duration
+	RedisWriteTimeout    time.Duration
+	RedisMaxRetries      int           // of a command failing on the network or during a failover
+	RedisMaxRetryBackoff time.Duration // longest wait between retries, it doubles from 8ms
+
+	// Clips
+	FFmpegPath      string
+	ClipWorkDir     string
+	ClipWorkers     int
+	MaxClipDuration time.Duration
+
+	// Premieres
+	PremiereIngestURL   string        // RTMP application premieres are relayed into
+	MaxPremiereLeadTime time.Duration // how far ahead a premiere can be scheduled
+
+	// Media server
+	SRSAPIURL string // HTTP API used to drop publishers and list what is being published
+
+	// StartupReconcile compares live streams with what SRS publishes when the service starts,
+	// ending the streams whose publisher left while it was down
+	StartupReconcile bool
+
+	// Ingest routing
+	IngestRegions        map[string]IngestRegion // region -> its media server URLs
+	IngestRegionCIDRs    map[string]string       // publisher network -> region
+	IngestCountryRegions map[string]string       // ISO country code -> region, for publishers outside the CIDRs
+	IngestDefaultRegion  string
+	MediaServerRegions   map[string]string // media server ID -> region it runs in
+
+	// Viewer geolocation, for viewers without a CDN country header
+	GeoIPCountryCIDRs map[string]string // viewer network -> ISO country code
+
+	// Audio fingerprinting of recordings
+	FingerprintProvider     string        // "http", off when empty
+	FingerprintURL          string        // endpoint of the http provider
+	FingerprintAPIKey       string        // bearer token for the http provider
+	FingerprintSampleLength time.Duration // length of each audio sample sent to the provider
+	FingerprintMinScore     float64       // matches below this confidence are ignored
+	FingerprintWorkers      int
+
+	// VOD packaging
+	VODHLSRenditions   map[string]string // extra renditions transcoded besides the source, name=WxH@kbps
+	VODSegmentDuration time.Duration     // target length of HLS segments
+	VODPackagerWorkers int
+
+	// Stream health
+	HealthWindowSize int           // number of samples kept per stream
+	HealthSampleTTL  time.Duration // how long samples outlive the last report
+
+	// Glass-to-glass latency probes
+	LatencyWindowSize int           // number of samples kept per stream
+	LatencySampleTTL  time.Duration // how long samples outlive the last report
+	LatencyMarkerTTL  time.Duration // how long a player can report against a marker
+	LatencyMaxDelay   time.Duration // longer measurements are treated as bogus
+
+	// Stream health alerts sent to the broadcaster
+	HealthAlertMinBitrateKbps int           // 0 disables the bitrate alert
+	HealthAlertMinFPS         float64       // 0 disables the frame rate alert
+	HealthAlertMaxDropRatio   float64       // dropped frames per expected frame, 0 disables the alert
+	HealthAlertCooldown       time.Duration // minimum time between two alerts of a kind for a stream
+
+	// RTMP callbacks
+	RTMPCallbackSecrets map[string]string // media server ID -> shared HMAC secret
+	RTMPSignatureMaxAge time.Duration     // how old a signed callback may be
+	CallbackIdempotency time.Duration     // how long a callback's response is replayed to retries
+
+	// SRT ingest
+	SRTDefaultLatency time.Duration // receiver latency when the publisher doesn't ask for one
+	SRTMinLatency     time.Duration // requested latencies are clamped to this range
+	SRTMaxLatency     time.Duration
+
+	// Retention
+	EndedStreamRetention time.Duration // how long ended streams are kept in DynamoDB, 0 keeps them forever
+	StreamSessionTTL     time.Duration // how long a publisher's session lives in Redis
+
+	// Stream keys generated by this service
+	StreamKeySecret string        // signs generated keys, generation is off when empty
+	StreamKeyTTL    time.Duration // default lifetime of a generated key, 0 never expires
+
+	// Playback tokens signed by this service
+	PlaybackTokenSecret string        // signs playback URLs, they are left unsigned when empty
+	PlaybackTokenTTL    time.Duration // how long a playback token is valid, players fetch a new one before
+
+	// User service stream key validation cache, a TTL of 0 doesn't cache that result
+	StreamKeyCacheTTL         time.Duration // how long a valid key is trusted without asking the user service
+	StreamKeyNegativeCacheTTL time.Duration // how long an invalid key is rejected without asking
+
+	// Classification
+	ClassificationMode          string  // off, suggest or auto
+	ClassificationMinConfidence float64 // auto mode only applies suggestions at least this confident
+
+	// Content filtering of stream titles, each rule source with its action: reject, sanitize or flag
+	ContentFilterWords       []string      // banned words, matched as whole words regardless of case
+	ContentFilterWordsFile   string        // more banned words, one per line
+	ContentFilterWordAction  string        // what banned words do
+	ContentFilterRegexFile   string        // name=pattern lines, patterns in Go regexp syntax
+	ContentFilterRegexAction string        // what regex rules do
+	ContentFilterAPIURL      string        // external moderation API, off when empty
+	ContentFilterAPIKey      string        // sent as a bearer token to the moderation API
+	ContentFilterAPIAction   string        // what the moderation API's verdicts do, sanitize flags instead
+	ContentFilterAPITimeout  time.Duration // writes don't wait longer on the moderation API
+	ContentReviewRetention   time.Duration // how long reviewed content is kept
+
+	// API versions, v1 responses announce their deprecation in favor of v2
+	APIV1DeprecatedAt time.Time // sent in the Deprecation header, just marked deprecated when zero
+	APIV1SunsetAt     time.Time // sent in the Sunset header when set
+
+	// API keys
+	AdminToken          string // protects /admin, e.g. API key issuance
+	DefaultMonthlyQuota int64  // requests per month for new API keys
+
+	// Publisher limits, handed to every authorized publisher and enforced while live. The
+	// user service's permissions take precedence once it sends them.
+	MaxBitrateKbps        int     // 0 disables the limit
+	MaxDurationMinutes    int     // 0 disables the limit
+	BitrateLimitTolerance float64 // share over the limit allowed, encoders overshoot briefly
+	BitrateLimitReports   int     // consecutive health reports over the limit before the publisher is dropped
+
+	// Admin dashboard
+	DashboardInterval time.Duration // how often dashboards get platform stats and error rates
+
+	// QualityLadder is the live transcoding ladder of tiers an admin didn't give one,
+	// name=WxH@kbps
+	QualityLadder map[string]string
+
+	// Recording uploads, failed ones are retried with the interval doubling each attempt
+	RecordingTimeout       time.Duration // how long a recording may stay pending or uploading before it counts as failed
+	RecordingRetryInterval time.Duration // wait before the first retry, also how often failed uploads are looked for
+	RecordingMaxAttempts   int           // uploads tried before a recording is left failed
+	RecordingUploadWorkers int           // concurrent uploads of reported recordings
+
+	// User data erasure and export jobs
+	UserDataJobRetention time.Duration // how long finished jobs, and the status of erasures, are kept
+	UserDataExportURLTTL time.Duration // how long a download link of an export works, at most 7 days
+
+	// Soft delete
+	SoftDeleteRetention time.Duration // how long deleted streams and VODs can be restored before they're purged
+
+	// Event publishing, events are queued and sent to the event bus in batches
+	EventBus           string        // kinesis, kafka or nats
+	EventQueueSize     int           // events buffered before new ones are dropped
+	EventBatchSize     int           // events per PutRecords call or Kafka write, at most 500
+	EventFlushInterval time.Duration // longest an event waits for its batch to fill
+	EventMaxRetries    int           // times throttled events are sent again
+
+	// Kafka event bus
+	KafkaBrokers      []string
+	KafkaClientID     string
+	KafkaDefaultTopic string            // topic of the event types KafkaTopics doesn't route
+	KafkaTopics       map[string]string // topic by event type
+
+	// NATS JetStream event bus, events go to the STREAM_EVENTS stream on platform.stream.<event type>
+	NATSURL             string        // comma separated servers of a cluster
+	NATSCredentialsFile string        // user credentials, for servers with decentralized auth
+	NATSCreateStream    bool          // create or update the stream at startup instead of expecting it
+	NATSStreamReplicas  int           // of a created stream
+	NATSStreamMaxAge    time.Duration // how long a created stream keeps events, forever when 0
+
+	// Events the bus can't take wait in a Redis stream, in development all of them do as
+	// nothing is replayed there
+	EventSpoolMaxLen    int64         // spooled events kept, the oldest are trimmed past it
+	EventReplayInterval time.Duration // how often the spool is drained into the bus
+
+	// Stream lifecycle events are written to the outbox table with the stream change and
+	// relayed to the bus from there
+	OutboxPollInterval time.Duration // how often pending outbox events are relayed
+	OutboxRetention    time.Duration // how long delivered outbox events are kept
+
+	// Events a consumer keeps failing on are dead-lettered, to be replayed by an admin
+	ConsumerMaxAttempts int           // times an event is handled before it is dead-lettered
+	DeadLetterRetention time.Duration // how long dead letters are kept
+
+	// Consumers remember the events they handled, so replayed events aren't handled twice
+	DedupeLease     time.Duration // how long a replica handling an event keeps others off it
+	DedupeRetention time.Duration // how long handled events are remembered, beyond the stream retention
+
+	// Background tasks that must run on one replica at a time hold a Redis lock while they
+	// run, renewed every third of the TTL. A replica that dies frees it after the TTL.
+	TaskLockTTL time.Duration
+
+	// Tunables reloaded while the service runs from a KEY=value file or the parameters under
+	// an SSM Parameter Store path, named like their environment variables
+	DynamicConfigSource   string        // file or ssm, off when empty
+	DynamicConfigPath     string        // the file, or the SSM path prefix
+	DynamicConfigInterval time.Duration // how often the source is checked for changes
+
+	// Follows
+	UserEventsStreamName string        // Kinesis stream the user service publishes follows to
+	FollowCacheTTL       time.Duration // how long a viewer's follows are kept after their last change
+	ViewerTokenTTL       time.Duration // how long a validated viewer token is trusted
+
+	// FollowHistoryRetention bounds how long follows are kept to count the followers a stream
+	// gained, it must outlast the longest stream
+	FollowHistoryRetention time.Duration
+
+	// Creator dashboard
+	DashboardRecentEvents int           // follows kept in each channel's recent event feed
+	DashboardTimeout      time.Duration // budget of each backend call, slower sections are left out
+
+	// Viewer analytics
+	ViewerHeartbeatTimeout time.Duration // viewers stop counting as watching this long after their last heartbeat
+	ViewerSampleInterval   time.Duration // how often concurrent viewers are sampled
+	ViewerDataTTL          time.Duration // how long heartbeat data outlives the last heartbeat
+	ChatServiceTimeout     time.Duration // budget of reads from the chat service's HTTP API
+
+	// Platform stats history
+	StatsSampleInterval  time.Duration // how often live streams and viewers are sampled
+	StatsHourlyRetention time.Duration // how long hourly rollups are kept, daily ones are kept forever
+
+	// Directory ranking, each weight multiplies a score between 0 and 1
+	DirectoryFeaturedWeight float64       // curated streams and streams in curated categories
+	DirectoryTrendingWeight float64       // audience size and recent growth
+	DirectoryFollowedWeight float64       // channels the viewer follows
+	DirectoryTrendingWindow time.Duration // growth is measured against the viewers this long ago
+	DirectoryLimit          int           // streams returned when a request doesn't ask for a number
+	DirectoryCacheFreshFor  time.Duration // the cached live directory is reloaded in the background once this old, 0 turns the cache off
+	DirectoryCacheStaleFor  time.Duration // how much longer a stale live directory may be served while it is reloaded
+
+	// Smoke test, run with --smoke-test against a deployed instance
+	SmokeTestURL         string        // root of the instance under test
+	SmokeTestUserID      int64         // user the synthetic stream is published as
+	SmokeTestMediaServer string        // media server ID the callbacks are signed as, defaults to any configured one
+	SmokeTestTimeout     time.Duration // budget of the whole run, including the reconnect grace period
+
+	// Viewer authentication, tokens are checked with the user service when neither is set
+	JWTSecret           string        // shared with the user service for HS256 tokens
+	JWKSURL             string        // JWKS endpoint for RS256/ES256 tokens
+	JWTIssuer           string        // required "iss" claim, not checked when empty
+	JWKSRefreshInterval time.Duration // how long fetched keys are used before refetching
+
+	// Identity propagation, the authenticated user is passed to other services signed with
+	// this secret, and users they pass in are only trusted when signed with it too
+	IdentitySecret string
+
+	// gRPC transport security, for the server and the user service client
+	GRPCTLSMode       string // off, tls or mtls
+	GRPCTLSCertFile   string // PEM certificate presented to peers
+	GRPCTLSKeyFile    string
+	GRPCTLSCAFile     string // PEM bundle peers are verified against, system roots when empty
+	GRPCTLSSecretID   string // Secrets Manager secret holding the PEMs, used instead of the files
+	GRPCTLSServerName string // name expected in the user service's certificate, defaults to its host
+
+	// Tracing
+	TracingEndpoint    string  // OTLP/HTTP collector, tracing is off when empty
+	TracingSampleRatio float64 // fraction of new traces recorded
+
+	// Logging
+	LogFormat string // json or console
+	LogLevel  string // debug, info, warn or error
 
 	// Timeouts
 	HTTPTimeout time.Duration
 	GRPCTimeout time.Duration
+
+	// Fault injection into DynamoDB, Redis and user service calls, never enabled in production.
+	// The faults are FAULT_INJECTION_<TARGET> tunables, admins can add more at /admin/faults.
+	FaultInjectionEnabled bool
+
+	tunables atomic.Pointer[Tunables]
+
+	callersMu sync.Mutex
+	callers   map[string]*callpolicy.Caller
+
+	faultsOnce sync.Once
+	faults     *faults.Injector
 }
 
 func Load() *Config {
-	return &Config{
+	cfg := &Config{
 		// Server - FIXED PORT
-		Port:        getEnv("PORT", "8084"), // Make sure this matches SRS callbacks
-		Environment: getEnv("ENVIRONMENT", "development"),
+		Port:          getEnv("PORT", "8084"), // Make sure this matches SRS callbacks
+		Environment:   getEnv("ENVIRONMENT", "development"),
+		PreflightMode: getEnv("PREFLIGHT_MODE", "degrade"),
+
+		// Health checks
+		HealthCheckTimeout:  getEnvAsDuration("HEALTH_CHECK_TIMEOUT", 2*time.Second),
+		HealthCheckCacheTTL: getEnvAsDuration("HEALTH_CHECK_CACHE_TTL", 2*time.Second),
 
 		// External Services
 		UserServiceGRPCAddr: getEnv("USER_SERVICE_GRPC_ADDR", "localhost:8082"),
+		ChatServiceURL:      getEnv("CHAT_SERVICE_URL", "http://localhost:8081"),
+		PlaybackBaseURL:     getEnv("PLAYBACK_BASE_URL", "http://localhost:8080"),
+		PlaybackOriginURL:   getEnv("PLAYBACK_ORIGIN_URL", "rtmp://127.0.0.1:1935?vhost=playback"),
+
+		ConsulAddr:              getEnv("CONSUL_HTTP_ADDR", "http://localhost:8500"),
+		ConsulToken:             getEnv("CONSUL_HTTP_TOKEN", ""),
+		ServiceDiscoveryRefresh: getEnvAsDuration("SERVICE_DISCOVERY_REFRESH", 30*time.Second),
+		UserServiceSubsetSize:   getEnvAsInt("USER_SERVICE_SUBSET_SIZE", 0),
+		UserServiceHealthCheck:  getEnv("USER_SERVICE_HEALTH_CHECK", "true") == "true",
 
 		// AWS / DynamoDB
 		AWSRegion:         getEnv("AWS_REGION", "us-east-1"),
 		DynamoDBTableName: getEnv("DYNAMODB_TABLE_NAME", "streams"),
+		VODTableName:      getEnv("DYNAMODB_VOD_TABLE_NAME", "vods"),
+		ClipTableName:     getEnv("DYNAMODB_CLIP_TABLE_NAME", "clips"),
+		RestreamTableName: getEnv("DYNAMODB_RESTREAM_TABLE_NAME", "restream-targets"),
+		APIKeyTableName:   getEnv("DYNAMODB_API_KEY_TABLE_NAME", "api-keys"),
+		TakedownTableName: getEnv("DYNAMODB_TAKEDOWN_TABLE_NAME", "takedowns"),
+		StreamKeyBanTable: getEnv("DYNAMODB_STREAM_KEY_BAN_TABLE_NAME", "stream-key-bans"),
+		StatsTableName:    getEnv("DYNAMODB_STATS_TABLE_NAME", "platform-stats"),
+		AuditTableName:    getEnv("DYNAMODB_AUDIT_TABLE_NAME", "stream-audit"),
+		OutboxTableName:   getEnv("DYNAMODB_OUTBOX_TABLE_NAME", "stream-outbox"),
+		DeadLetterTable:   getEnv("DYNAMODB_DEAD_LETTER_TABLE_NAME", "event-dead-letters"),
+		DedupeTableName:   getEnv("DYNAMODB_DEDUPE_TABLE_NAME", "event-dedupe"),
+		MigrationsTable:   getEnv("DYNAMODB_MIGRATIONS_TABLE_NAME", "stream-schema-migrations"),
 		DynamoDBEndpoint:  getEnv("DYNAMODB_ENDPOINT", "http://localhost:8002"),
 		KinesisStreamName: getEnv("KINESIS_STREAM_NAME", "stream-events"),
 		S3BucketName:      getEnv("S3_BUCKET_NAME", "stream-recordings"),
 
+		// DynamoDB global tables
+		DynamoDBRegions:     getEnvAsSlice("DYNAMODB_REGIONS"),
+		DynamoDBWriteRegion: getEnv("DYNAMODB_WRITE_REGION", "local"),
+
 		// Redis
-		RedisAddr:     getEnv("REDIS_ADDR", "localhost:6379"),
-		RedisPassword: getEnv("REDIS_PASSWORD", ""),
-		RedisDB:       getEnvAsInt("REDIS_DB", 0),
+		RedisMode:             getEnv("REDIS_MODE", "standalone"),
+		RedisAddr:             getEnv("REDIS_ADDR", "localhost:6379"),
+		RedisAddrs:            getEnvAsSlice("REDIS_ADDRS"),
+		RedisSentinelMaster:   getEnv("REDIS_SENTINEL_MASTER", "mymaster"),
+		RedisPassword:         getEnv("REDIS_PASSWORD", ""),
+		RedisSentinelPassword: getEnv("REDIS_SENTINEL_PASSWORD", ""),
+		RedisDB:               getEnvAsInt("REDIS_DB", 0),
+
+		RedisPoolSize:        getEnvAsInt("REDIS_POOL_SIZE", 0),
+		RedisMinIdleConns:    getEnvAsInt("REDIS_MIN_IDLE_CONNS", 5),
+		RedisPoolTimeout:     getEnvAsDuration("REDIS_POOL_TIMEOUT", 4*time.Second),
+		RedisDialTimeout:     getEnvAsDuration("REDIS_DIAL_TIMEOUT", 5*time.Second),
+		RedisReadTimeout:     getEnvAsDuration("REDIS_READ_TIMEOUT", 3*time.Second),
+		RedisWriteTimeout:    getEnvAsDuration("REDIS_WRITE_TIMEOUT", 3*time.Second),
+		RedisMaxRetries:      getEnvAsInt("REDIS_MAX_RETRIES", 5),
+		RedisMaxRetryBackoff: getEnvAsDuration("REDIS_MAX_RETRY_BACKOFF", 2*time.Second),
+
+		// Clips
+		FFmpegPath:      getEnv("FFMPEG_PATH", "ffmpeg"),
+		ClipWorkDir:     getEnv("CLIP_WORK_DIR", os.TempDir()),
+		ClipWorkers:     getEnvAsInt("CLIP_WORKERS", 2),
+		MaxClipDuration: getEnvAsDuration("MAX_CLIP_DURATION", 60*time.Second),
+
+		// Premieres
+		PremiereIngestURL:   getEnv("PREMIERE_INGEST_URL", "rtmp://localhost:1935/live"),
+		MaxPremiereLeadTime: getEnvAsDuration("MAX_PREMIERE_LEAD_TIME", 30*24*time.Hour),
+
+		// Media server
+		SRSAPIURL:        getEnv("SRS_API_URL", "http://localhost:1985"),
+		StartupReconcile: getEnv("STARTUP_RECONCILE", "true") == "true",
+
+		// Ingest routing, e.g. INGEST_REGIONS=us-east=rtmp://use.example.com/live|srt://use.example.com:10080|https://use.example.com/whip
+		// and INGEST_REGION_CIDRS=203.0.113.0/24=eu-west
+		IngestRegions: getEnvAsIngestRegions("INGEST_REGIONS", map[string]IngestRegion{
+			"local": {RTMPURL: "rtmp://localhost:1935/live", SRTURL: "srt://localhost:10080", WHIPURL: "http://localhost:1985/rtc/v1/whip/"},
+		}),
+		IngestRegionCIDRs:    getEnvAsMap("INGEST_REGION_CIDRS"),
+		IngestCountryRegions: getEnvAsMap("INGEST_COUNTRY_REGIONS"),
+		IngestDefaultRegion:  getEnv("INGEST_DEFAULT_REGION", "local"),
+		MediaServerRegions:   getEnvAsMap("MEDIA_SERVER_REGIONS"),
+
+		// Viewer geolocation, e.g. GEOIP_COUNTRY_CIDRS=203.0.113.0/24=FR
+		GeoIPCountryCIDRs: getEnvAsMap("GEOIP_COUNTRY_CIDRS"),
+
+		// Audio fingerprinting
+		FingerprintProvider:     getEnv("FINGERPRINT_PROVIDER", ""),
+		FingerprintURL:          getEnv("FINGERPRINT_URL", ""),
+		FingerprintAPIKey:       getEnv("FINGERPRINT_API_KEY", ""),
+		FingerprintSampleLength: getEnvAsDuration("FINGERPRINT_SAMPLE_LENGTH", 20*time.Second),
+		FingerprintMinScore:     getEnvAsFloat("FINGERPRINT_MIN_SCORE", 0.5),
+		FingerprintWorkers:      getEnvAsInt("FINGERPRINT_WORKERS", 1),
+
+		// VOD packaging
+		VODHLSRenditions:   getEnvAsMap("VOD_HLS_RENDITIONS"),
+		VODSegmentDuration: getEnvAsDuration("VOD_SEGMENT_DURATION", 6*time.Second),
+		VODPackagerWorkers: getEnvAsInt("VOD_PACKAGER_WORKERS", 1),
+
+		// Stream health
+		HealthWindowSize: getEnvAsInt("HEALTH_WINDOW_SIZE", 30),
+		HealthSampleTTL:  getEnvAsDuration("HEALTH_SAMPLE_TTL", 10*time.Minute),
+
+		// Latency probes
+		LatencyWindowSize: getEnvAsInt("LATENCY_WINDOW_SIZE", 500),
+		LatencySampleTTL:  getEnvAsDuration("LATENCY_SAMPLE_TTL", time.Hour),
+		LatencyMarkerTTL:  getEnvAsDuration("LATENCY_MARKER_TTL", 5*time.Minute),
+		LatencyMaxDelay:   getEnvAsDuration("LATENCY_MAX_DELAY", 2*time.Minute),
+
+		// Stream health alerts
+		HealthAlertMinBitrateKbps: getEnvAsInt("HEALTH_ALERT_MIN_BITRATE_KBPS", 1500),
+		HealthAlertMinFPS:         getEnvAsFloat("HEALTH_ALERT_MIN_FPS", 20),
+		HealthAlertMaxDropRatio:   getEnvAsFloat("HEALTH_ALERT_MAX_DROP_RATIO", 0.02),
+		HealthAlertCooldown:       getEnvAsDuration("HEALTH_ALERT_COOLDOWN", 5*time.Minute),
+
+		// RTMP callbacks, e.g. RTMP_CALLBACK_SECRETS=srs-1=secret1,srs-2=secret2
+		RTMPCallbackSecrets: getEnvAsMap("RTMP_CALLBACK_SECRETS"),
+		RTMPSignatureMaxAge: getEnvAsDuration("RTMP_SIGNATURE_MAX_AGE", 5*time.Minute),
+		CallbackIdempotency: getEnvAsDuration("RTMP_CALLBACK_IDEMPOTENCY_TTL", 10*time.Minute),
+
+		// SRT ingest
+		SRTDefaultLatency: getEnvAsDuration("SRT_DEFAULT_LATENCY", 120*time.Millisecond),
+		SRTMinLatency:     getEnvAsDuration("SRT_MIN_LATENCY", 20*time.Millisecond),
+		SRTMaxLatency:     getEnvAsDuration("SRT_MAX_LATENCY", 2*time.Second),
+
+		// Retention
+		EndedStreamRetention: getEnvAsDuration("ENDED_STREAM_RETENTION", 90*24*time.Hour),
+		StreamSessionTTL:     getEnvAsDuration("STREAM_SESSION_TTL", time.Hour),
+
+		// Stream key generation
+		StreamKeySecret: getEnv("STREAM_KEY_SECRET", ""),
+		StreamKeyTTL:    getEnvAsDuration("STREAM_KEY_TTL", 0),
+
+		PlaybackTokenSecret: getEnv("PLAYBACK_TOKEN_SECRET", ""),
+		PlaybackTokenTTL:    getEnvAsDuration("PLAYBACK_TOKEN_TTL", 15*time.Minute),
+
+		// Stream key validation cache
+		StreamKeyCacheTTL:         getEnvAsDuration("STREAM_KEY_CACHE_TTL", time.Minute),
+		StreamKeyNegativeCacheTTL: getEnvAsDuration("STREAM_KEY_NEGATIVE_CACHE_TTL", 10*time.Second),
+
+		// Classification
+		ClassificationMode:          getEnv("CLASSIFICATION_MODE", "suggest"),
+		ClassificationMinConfidence: getEnvAsFloat("CLASSIFICATION_MIN_CONFIDENCE", 0.6),
+
+		// Content filtering
+		ContentFilterWords:       getEnvAsSlice("CONTENT_FILTER_WORDS"),
+		ContentFilterWordsFile:   getEnv("CONTENT_FILTER_WORDS_FILE", ""),
+		ContentFilterWordAction:  getEnv("CONTENT_FILTER_WORD_ACTION", "sanitize"),
+		ContentFilterRegexFile:   getEnv("CONTENT_FILTER_REGEX_FILE", ""),
+		ContentFilterRegexAction: getEnv("CONTENT_FILTER_REGEX_ACTION", "reject"),
+		ContentFilterAPIURL:      getEnv("CONTENT_FILTER_API_URL", ""),
+		ContentFilterAPIKey:      getEnv("CONTENT_FILTER_API_KEY", ""),
+		ContentFilterAPIAction:   getEnv("CONTENT_FILTER_API_ACTION", "flag"),
+		ContentFilterAPITimeout:  getEnvAsDuration("CONTENT_FILTER_API_TIMEOUT", 2*time.Second),
+		ContentReviewRetention:   getEnvAsDuration("CONTENT_REVIEW_RETENTION", 90*24*time.Hour),
+
+		// API versions
+		APIV1DeprecatedAt: getEnvAsTime("API_V1_DEPRECATED_AT"),
+		APIV1SunsetAt:     getEnvAsTime("API_V1_SUNSET_AT"),
+
+		// API keys
+		AdminToken:          getEnv("ADMIN_API_TOKEN", ""),
+		DefaultMonthlyQuota: int64(getEnvAsInt("API_KEY_MONTHLY_QUOTA", 100000)),
+
+		// Publisher limits
+		MaxBitrateKbps:        getEnvAsInt("MAX_BITRATE_KBPS", 8000),
+		MaxDurationMinutes:    getEnvAsInt("MAX_DURATION_MINUTES", 240),
+		BitrateLimitTolerance: getEnvAsFloat("BITRATE_LIMIT_TOLERANCE", 0.1),
+		BitrateLimitReports:   getEnvAsInt("BITRATE_LIMIT_REPORTS", 10),
+
+		// Admin dashboard
+		DashboardInterval: getEnvAsDuration("DASHBOARD_INTERVAL", 5*time.Second),
+
+		QualityLadder: getEnvAsMapOr("QUALITY_LADDER", map[string]string{
+			"720p": "1280x720@3000",
+			"480p": "854x480@1500",
+			"360p": "640x360@800",
+		}),
+
+		// Recording uploads
+		RecordingTimeout:       getEnvAsDuration("RECORDING_TIMEOUT", 30*time.Minute),
+		RecordingRetryInterval: getEnvAsDuration("RECORDING_RETRY_INTERVAL", 5*time.Minute),
+		RecordingMaxAttempts:   getEnvAsInt("RECORDING_MAX_ATTEMPTS", 5),
+		RecordingUploadWorkers: getEnvAsInt("RECORDING_UPLOAD_WORKERS", 2),
+
+		// User data jobs
+		UserDataJobRetention: getEnvAsDuration("USER_DATA_JOB_RETENTION", 30*24*time.Hour),
+		UserDataExportURLTTL: getEnvAsDuration("USER_DATA_EXPORT_URL_TTL", 24*time.Hour),
+
+		// Soft delete
+		SoftDeleteRetention: getEnvAsDuration("SOFT_DELETE_RETENTION", 30*24*time.Hour),
+
+		// Event publishing
+		EventBus:           getEnv("EVENT_BUS", "kinesis"),
+		EventQueueSize:     getEnvAsInt("EVENT_QUEUE_SIZE", 10000),
+		EventBatchSize:     getEnvAsInt("EVENT_BATCH_SIZE", 500),
+		EventFlushInterval: getEnvAsDuration("EVENT_FLUSH_INTERVAL", 500*time.Millisecond),
+		EventMaxRetries:    getEnvAsInt("EVENT_MAX_RETRIES", 5),
+
+		KafkaBrokers:      getEnvAsSliceOr("KAFKA_BROKERS", []string{"localhost:9092"}),
+		KafkaClientID:     getEnv("KAFKA_CLIENT_ID", "stream-management-service"),
+		KafkaDefaultTopic: getEnv("KAFKA_DEFAULT_TOPIC", "stream-events"),
+		KafkaTopics:       getEnvAsMap("KAFKA_TOPICS"),
+
+		NATSURL:             getEnv("NATS_URL", "nats://localhost:4222"),
+		NATSCredentialsFile: getEnv("NATS_CREDENTIALS_FILE", ""),
+		NATSCreateStream:    getEnv("NATS_CREATE_STREAM", "false") == "true",
+		NATSStreamReplicas:  getEnvAsInt("NATS_STREAM_REPLICAS", 1),
+		NATSStreamMaxAge:    getEnvAsDuration("NATS_STREAM_MAX_AGE", 7*24*time.Hour),
+
+		EventSpoolMaxLen:    int64(getEnvAsInt("EVENT_SPOOL_MAX_LEN", 100000)),
+		EventReplayInterval: getEnvAsDuration("EVENT_REPLAY_INTERVAL", 30*time.Second),
+
+		OutboxPollInterval: getEnvAsDuration("OUTBOX_POLL_INTERVAL", time.Second),
+		OutboxRetention:    getEnvAsDuration("OUTBOX_RETENTION", 24*time.Hour),
+
+		ConsumerMaxAttempts: getEnvAsInt("CONSUMER_MAX_ATTEMPTS", 5),
+		DeadLetterRetention: getEnvAsDuration("DEAD_LETTER_RETENTION", 14*24*time.Hour),
+
+		DedupeLease:     getEnvAsDuration("EVENT_DEDUPE_LEASE", time.Minute),
+		DedupeRetention: getEnvAsDuration("EVENT_DEDUPE_RETENTION", 8*24*time.Hour),
+
+		TaskLockTTL: getEnvAsDuration("TASK_LOCK_TTL", 30*time.Second),
+
+		DynamicConfigSource:   getEnv("DYNAMIC_CONFIG_SOURCE", ""),
+		DynamicConfigPath:     getEnv("DYNAMIC_CONFIG_PATH", ""),
+		DynamicConfigInterval: getEnvAsDuration("DYNAMIC_CONFIG_INTERVAL", 30*time.Second),
+
+		// Follows
+		UserEventsStreamName: getEnv("USER_EVENTS_STREAM_NAME", "user-events"),
+		FollowCacheTTL:       getEnvAsDuration("FOLLOW_CACHE_TTL", 30*24*time.Hour),
+		ViewerTokenTTL:       getEnvAsDuration("VIEWER_TOKEN_TTL", 5*time.Minute),
+
+		FollowHistoryRetention: getEnvAsDuration("FOLLOW_HISTORY_RETENTION", 48*time.Hour),
+
+		// Creator dashboard
+		DashboardRecentEvents: getEnvAsInt("DASHBOARD_RECENT_EVENTS", 20),
+		DashboardTimeout:      getEnvAsDuration("DASHBOARD_TIMEOUT", 2*time.Second),
+
+		// Viewer analytics
+		ViewerHeartbeatTimeout: getEnvAsDuration("VIEWER_HEARTBEAT_TIMEOUT", time.Minute),
+		ViewerSampleInterval:   getEnvAsDuration("VIEWER_SAMPLE_INTERVAL", 30*time.Second),
+		ViewerDataTTL:          getEnvAsDuration("VIEWER_DATA_TTL", 48*time.Hour),
+		ChatServiceTimeout:     getEnvAsDuration("CHAT_SERVICE_TIMEOUT", 2*time.Second),
+
+		// Platform stats history
+		StatsSampleInterval:  getEnvAsDuration("STATS_SAMPLE_INTERVAL", time.Minute),
+		StatsHourlyRetention: getEnvAsDuration("STATS_HOURLY_RETENTION", 90*24*time.Hour),
+
+		// Directory ranking
+		DirectoryFeaturedWeight: getEnvAsFloat("DIRECTORY_FEATURED_WEIGHT", 3),
+		DirectoryTrendingWeight: getEnvAsFloat("DIRECTORY_TRENDING_WEIGHT", 1),
+		DirectoryFollowedWeight: getEnvAsFloat("DIRECTORY_FOLLOWED_WEIGHT", 2),
+		DirectoryTrendingWindow: getEnvAsDuration("DIRECTORY_TRENDING_WINDOW", 10*time.Minute),
+		DirectoryLimit:          getEnvAsInt("DIRECTORY_LIMIT", 50),
+		DirectoryCacheFreshFor:  getEnvAsDuration("DIRECTORY_CACHE_FRESH_FOR", 5*time.Second),
+		DirectoryCacheStaleFor:  getEnvAsDuration("DIRECTORY_CACHE_STALE_FOR", 5*time.Minute),
+
+		// Smoke test
+		SmokeTestURL:         getEnv("SMOKE_TEST_URL", "http://localhost:"+getEnv("PORT", "8084")),
+		SmokeTestUserID:      int64(getEnvAsInt("SMOKE_TEST_USER_ID", 0)),
+		SmokeTestMediaServer: getEnv("SMOKE_TEST_MEDIA_SERVER", ""),
+		SmokeTestTimeout:     getEnvAsDuration("SMOKE_TEST_TIMEOUT", 2*time.Minute),
+
+		// Viewer authentication
+		JWTSecret:           getEnv("JWT_SECRET_KEY", ""),
+		JWKSURL:             getEnv("JWT_JWKS_URL", ""),
+		JWTIssuer:           getEnv("JWT_ISSUER", ""),
+		JWKSRefreshInterval: getEnvAsDuration("JWKS_REFRESH_INTERVAL", 10*time.Minute),
+
+		// Identity propagation
+		IdentitySecret: getEnv("IDENTITY_PROPAGATION_SECRET", ""),
+
+		// gRPC transport security
+		GRPCTLSMode:       getEnv("GRPC_TLS_MODE", "off"),
+		GRPCTLSCertFile:   getEnv("GRPC_TLS_CERT_FILE", ""),
+		GRPCTLSKeyFile:    getEnv("GRPC_TLS_KEY_FILE", ""),
+		GRPCTLSCAFile:     getEnv("GRPC_TLS_CA_FILE", ""),
+		GRPCTLSSecretID:   getEnv("GRPC_TLS_SECRET_ID", ""),
+		GRPCTLSServerName: getEnv("GRPC_TLS_SERVER_NAME", ""),
+
+		// Tracing
+		TracingEndpoint:    getEnv("OTEL_EXPORTER_OTLP_ENDPOINT", ""),
+		TracingSampleRatio: getEnvAsFloat("OTEL_TRACES_SAMPLER_ARG", 1.0),
+
+		// Logging
+		LogFormat: getEnv("LOG_FORMAT", "console"),
+		LogLevel:  getEnv("LOG_LEVEL", "info"),
 
 		// Timeouts
 		HTTPTimeout: getEnvAsDuration("HTTP_TIMEOUT", 30*time.Second),
 		GRPCTimeout: getEnvAsDuration("GRPC_TIMEOUT", 10*time.Second),
+
+		// Fault injection
+		FaultInjectionEnabled: getEnv("FAULT_INJECTION_ENABLED", "false") == "true",
 	}
+
+	// Invalid tunables fall back to their defaults like the other settings, a reload with
+	// one is rejected instead
+	tunables, _ := ParseTunables(nil)
+	cfg.tunables.Store(tunables)
+	return cfg
 }
 
 func getEnv(key, defaultValue string) string {
@@ -74,6 +678,15 @@ func getEnvAsInt(key string, defaultValue int) int {
 	return defaultValue
 }
 
+func getEnvAsFloat(key string, defaultValue float64) float64 {
+	if value := os.Getenv(key); value != "" {
+		if floatValue, err := strconv.ParseFloat(value, 64); err == nil {
+			return floatValue
+		}
+	}
+	return defaultValue
+}
+
 func getEnvAsDuration(key string, defaultValue time.Duration) time.Duration {
 	if value := os.Getenv(key); value != "" {
 		if duration, err := time.ParseDuration(value); err == nil {
@@ -82,3 +695,76 @@ func getEnvAsDuration(key string, defaultValue time.Duration) time.Duration {
 	}
 	return defaultValue
 }
+
+// getEnvAsTime parses an RFC 3339 time, the zero time when unset or invalid
+func getEnvAsTime(key string) time.Time {
+	if value := os.Getenv(key); value != "" {
+		if parsed, err := time.Parse(time.RFC3339, value); err == nil {
+			return parsed
+		}
+	}
+	return time.Time{}
+}
+
+// getEnvAsSlice parses a comma separated list, leaving out empty entries
+func getEnvAsSlice(key string) []string {
+	var result []string
+	for _, entry := range strings.Split(os.Getenv(key), ",") {
+		if entry = strings.TrimSpace(entry); entry != "" {
+			result = append(result, entry)
+		}
+	}
+	return result
+}
+
+// getEnvAsSliceOr parses a comma separated list, defaults is used when it is empty
+func getEnvAsSliceOr(key string, defaults []string) []string {
+	if result := getEnvAsSlice(key); len(result) > 0 {
+		return result
+	}
+	return defaults
+}
+
+// getEnvAsMap parses a comma separated list of key=value pairs
+func getEnvAsMap(key string) map[string]string {
+	result := make(map[string]string)
+	for _, pair := range strings.Split(os.Getenv(key), ",") {
+		name, value, ok := strings.Cut(strings.TrimSpace(pair), "=")
+		if !ok || name == "" || value == "" {
+			continue
+		}
+		result[name] = value
+	}
+	return result
+}
+
+// getEnvAsMapOr is getEnvAsMap with defaults used when the variable has no entries
+func getEnvAsMapOr(key string, defaults map[string]string) map[string]string {
+	if result := getEnvAsMap(key); len(result) > 0 {
+		return result
+	}
+	return defaults
+}
+
+// getEnvAsIngestRegions parses a comma separated list of region=rtmp_url|srt_url|whip_url
+// entries, the SRT and WHIP URLs are optional. The defaults are used when the variable is unset.
+func getEnvAsIngestRegions(key string, defaults map[string]IngestRegion) map[string]IngestRegion {
+	entries := getEnvAsMap(key)
+	if len(entries) == 0 {
+		return defaults
+	}
+
+	regions := make(map[string]IngestRegion, len(entries))
+	for name, value := range entries {
+		urls := strings.Split(value, "|")
+		region := IngestRegion{RTMPURL: strings.TrimSpace(urls[0])}
+		if len(urls) > 1 {
+			region.SRTURL = strings.TrimSpace(urls[1])
+		}
+		if len(urls) > 2 {
+			region.WHIPURL = strings.TrimSpace(urls[2])
+		}
+		regions[name] = region
+	}
+	return regions
+}
@@ -0,0 +1,96 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/services/stream-management-service/pkg/faults"
+)
+
+// Faults returns the injector the clients of DynamoDB, Redis and the user service share, or
+// nil unless FAULT_INJECTION_ENABLED is set outside production
+func (c *Config) Faults() *faults.Injector {
+	c.faultsOnce.Do(func() {
+		if !c.FaultInjectionEnabled || c.Environment == "production" {
+			return
+		}
+		c.faults = faults.New(func() []faults.Rule {
+			return c.Tunables().FaultRules
+		})
+	})
+	return c.faults
+}
+
+// faultRules reads the fault of each target from FAULT_INJECTION_<TARGET>, a comma separated
+// list of settings, e.g. "latency=200ms,error_rate=0.1,operation=GetItem"
+func (p *tunableParser) faultRules() []faults.Rule {
+	var rules []faults.Rule
+	for _, target := range faults.Targets {
+		key := "FAULT_INJECTION_" + strings.ToUpper(target)
+		p.values[key] = ""
+
+		value, ok := p.lookup(key)
+		if !ok || value == "" {
+			continue
+		}
+		rule := faults.Rule{Target: target}
+		valid := true
+		for _, setting := range strings.Split(value, ",") {
+			if setting = strings.TrimSpace(setting); setting == "" {
+				continue
+			}
+			if err := setFaultRule(&rule, setting); err != nil {
+				p.invalid(key, setting, err)
+				valid = false
+			}
+		}
+		if err := rule.Validate(); valid && err != nil {
+			p.invalid(key, value, err)
+			valid = false
+		}
+		if !valid {
+			continue
+		}
+		rules = append(rules, rule)
+		p.values[key] = formatFaultRule(rule)
+	}
+	return rules
+}
+
+func setFaultRule(rule *faults.Rule, setting string) error {
+	name, value, ok := strings.Cut(setting, "=")
+	if !ok || value == "" {
+		return errors.New("expected name=value")
+	}
+
+	switch name {
+	case "operation":
+		rule.Operation = value
+	case "latency":
+		latency, err := time.ParseDuration(value)
+		if err != nil {
+			return errors.New("latency must be a duration")
+		}
+		rule.Latency = latency
+	case "error_rate":
+		rate, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return errors.New("error_rate must be a share of calls")
+		}
+		rule.ErrorRate = rate
+	default:
+		return fmt.Errorf("unknown setting %s", name)
+	}
+	return nil
+}
+
+func formatFaultRule(rule faults.Rule) string {
+	formatted := fmt.Sprintf("latency=%s,error_rate=%s", rule.Latency, strconv.FormatFloat(rule.ErrorRate, 'f', -1, 64))
+	if rule.Operation != "" {
+		formatted += ",operation=" + rule.Operation
+	}
+	return formatted
+}
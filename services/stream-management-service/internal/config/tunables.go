@@ -0,0 +1,257 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/robfig/cron/v3"
+
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/services/stream-management-service/pkg/callpolicy"
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/services/stream-management-service/pkg/faults"
+)
+
+// Tunables are the settings that can change while the service runs, see Watcher. Read them
+// with Config.Tunables where they are used instead of keeping them.
+type Tunables struct {
+	// Feature flags
+	APIKeysRequired       bool // reject REST API requests without an API key
+	PlaybackTokenRequired bool // the media server and CDN refuse playback without a token
+	FingerprintAutoMute   bool // mute matched ranges in the VOD renditions
+
+	// Rate limiting
+	RateLimits       map[string]RateLimit // by "<route group>.ip" and "<route group>.user"
+	DefaultRateLimit int                  // requests per minute for new API keys
+
+	ReconnectGracePeriod time.Duration // how long a dropped stream waits for the broadcaster, 0 ends it at once
+
+	// ReconcileInterval is how often streams are reconciled with SRS while the service runs,
+	// 0 leaves stale streams to the 12 hour cleanup
+	ReconcileInterval time.Duration
+
+	// Scheduled jobs, cron expressions or descriptors like @hourly. Each run waits up to
+	// JobJitter so replicas don't all reach for it at once.
+	CleanupSchedule         string // of the expired stream cleanup, scheduled when ReconcileInterval is 0
+	SoftDeletePurgeSchedule string
+	JobJitter               time.Duration
+
+	// CallPolicies bound and retry the calls to each dependency, see Config.Caller
+	CallPolicies map[string]callpolicy.Policy
+
+	// FaultRules are injected into dependency calls when fault injection is enabled, see
+	// Config.Faults
+	FaultRules []faults.Rule
+
+	Values     map[string]string // every tunable as it would be set in the environment
+	Overridden []string          // tunables set by the dynamic config source
+}
+
+// Tunables returns the tunables in effect
+func (c *Config) Tunables() *Tunables {
+	return c.tunables.Load()
+}
+
+// ReloadTunables puts overrides, keyed like the environment variables, on top of the
+// environment and makes them the tunables in effect. Nothing changes when one is invalid.
+func (c *Config) ReloadTunables(overrides map[string]string) (*Tunables, error) {
+	tunables, err := ParseTunables(overrides)
+	if err != nil {
+		return nil, err
+	}
+	if tunables.PlaybackTokenRequired && c.PlaybackTokenSecret == "" {
+		return nil, fmt.Errorf("PLAYBACK_TOKEN_REQUIRED needs PLAYBACK_TOKEN_SECRET")
+	}
+
+	c.tunables.Store(tunables)
+	return tunables, nil
+}
+
+// ParseTunables reads the tunables from overrides and the environment, in that order. The
+// returned tunables have defaults in place of invalid values, which are reported in the error.
+func ParseTunables(overrides map[string]string) (*Tunables, error) {
+	p := &tunableParser{overrides: overrides, values: make(map[string]string)}
+
+	t := &Tunables{
+		APIKeysRequired:       p.bool("API_KEYS_REQUIRED", false),
+		PlaybackTokenRequired: p.bool("PLAYBACK_TOKEN_REQUIRED", false),
+		FingerprintAutoMute:   p.bool("FINGERPRINT_AUTO_MUTE", false),
+
+		RateLimits: p.rateLimits("RATE_LIMITS", map[string]RateLimit{
+			"api.ip":   {Rate: 120, Burst: 30},
+			"api.user": {Rate: 600, Burst: 100},
+			// Playback authorization checks private stream passphrases, keep guessing slow
+			"playback.ip":   {Rate: 30, Burst: 10},
+			"playback.user": {Rate: 60, Burst: 20},
+		}),
+		DefaultRateLimit: p.int("API_KEY_RATE_LIMIT", 60),
+
+		ReconnectGracePeriod: p.duration("RECONNECT_GRACE_PERIOD", 30*time.Second),
+		ReconcileInterval:    p.duration("RECONCILE_INTERVAL", time.Minute),
+
+		CleanupSchedule:         p.schedule("CLEANUP_SCHEDULE", "*/5 * * * *"),
+		SoftDeletePurgeSchedule: p.schedule("SOFT_DELETE_PURGE_SCHEDULE", "@hourly"),
+		JobJitter:               p.duration("JOB_JITTER", 10*time.Second),
+
+		CallPolicies: p.callPolicies(),
+		FaultRules:   p.faultRules(),
+	}
+
+	for key := range overrides {
+		if _, ok := p.values[key]; !ok {
+			p.errs = append(p.errs, fmt.Errorf("%s can't be changed while the service runs", key))
+			continue
+		}
+		t.Overridden = append(t.Overridden, key)
+	}
+	sort.Strings(t.Overridden)
+	t.Values = p.values
+
+	return t, errors.Join(p.errs...)
+}
+
+// tunableParser reads tunables strictly, collecting what is invalid instead of ignoring it
+type tunableParser struct {
+	overrides map[string]string
+	values    map[string]string
+	errs      []error
+}
+
+func (p *tunableParser) lookup(key string) (string, bool) {
+	if value, ok := p.overrides[key]; ok {
+		return strings.TrimSpace(value), true
+	}
+	if value := os.Getenv(key); value != "" {
+		return value, true
+	}
+	return "", false
+}
+
+func (p *tunableParser) invalid(key, value string, err error) {
+	p.errs = append(p.errs, fmt.Errorf("invalid %s %q: %w", key, value, err))
+}
+
+func (p *tunableParser) bool(key string, defaultValue bool) bool {
+	result := defaultValue
+	if value, ok := p.lookup(key); ok {
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			p.invalid(key, value, err)
+		} else {
+			result = parsed
+		}
+	}
+	p.values[key] = strconv.FormatBool(result)
+	return result
+}
+
+func (p *tunableParser) int(key string, defaultValue int) int {
+	result := defaultValue
+	if value, ok := p.lookup(key); ok {
+		parsed, err := strconv.Atoi(value)
+		switch {
+		case err != nil:
+			p.invalid(key, value, err)
+		case parsed < 0:
+			p.invalid(key, value, errors.New("must not be negative"))
+		default:
+			result = parsed
+		}
+	}
+	p.values[key] = strconv.Itoa(result)
+	return result
+}
+
+func (p *tunableParser) duration(key string, defaultValue time.Duration) time.Duration {
+	result := defaultValue
+	if value, ok := p.lookup(key); ok {
+		parsed, err := time.ParseDuration(value)
+		switch {
+		case err != nil:
+			p.invalid(key, value, err)
+		case parsed < 0:
+			p.invalid(key, value, errors.New("must not be negative"))
+		default:
+			result = parsed
+		}
+	}
+	p.values[key] = result.String()
+	return result
+}
+
+// schedule reads a cron expression or descriptor
+func (p *tunableParser) schedule(key, defaultValue string) string {
+	result := defaultValue
+	if value, ok := p.lookup(key); ok {
+		if _, err := cron.ParseStandard(value); err != nil {
+			p.invalid(key, value, err)
+		} else {
+			result = value
+		}
+	}
+	p.values[key] = result
+	return result
+}
+
+// rateLimits parses a comma separated list of name=rate[/burst] pairs on top of the defaults,
+// e.g. "api.ip=60/20,api.user=300". A rate of 0 turns a limit off.
+func (p *tunableParser) rateLimits(key string, defaults map[string]RateLimit) map[string]RateLimit {
+	limits := make(map[string]RateLimit, len(defaults))
+	for name, limit := range defaults {
+		limits[name] = limit
+	}
+
+	if value, ok := p.lookup(key); ok {
+		for _, pair := range strings.Split(value, ",") {
+			if pair = strings.TrimSpace(pair); pair == "" {
+				continue
+			}
+			limit, err := parseRateLimit(pair)
+			if err != nil {
+				p.invalid(key, pair, err)
+				continue
+			}
+			name, _, _ := strings.Cut(pair, "=")
+			limits[name] = limit
+		}
+	}
+
+	names := make([]string, 0, len(limits))
+	for name := range limits {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	pairs := make([]string, len(names))
+	for i, name := range names {
+		pairs[i] = fmt.Sprintf("%s=%d/%d", name, limits[name].Rate, limits[name].Burst)
+	}
+	p.values[key] = strings.Join(pairs, ",")
+
+	return limits
+}
+
+func parseRateLimit(pair string) (RateLimit, error) {
+	name, value, ok := strings.Cut(pair, "=")
+	if !ok || name == "" || value == "" {
+		return RateLimit{}, errors.New("expected name=rate[/burst]")
+	}
+
+	rate, burst, hasBurst := strings.Cut(value, "/")
+	limit := RateLimit{}
+
+	var err error
+	if limit.Rate, err = strconv.Atoi(rate); err != nil || limit.Rate < 0 {
+		return RateLimit{}, errors.New("rate must be a number of requests per minute")
+	}
+	limit.Burst = limit.Rate
+	if hasBurst {
+		if limit.Burst, err = strconv.Atoi(burst); err != nil || limit.Burst < 1 {
+			return RateLimit{}, errors.New("burst must be at least 1")
+		}
+	}
+
+	return limit, nil
+}
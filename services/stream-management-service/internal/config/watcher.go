@@ -0,0 +1,173 @@
+package config
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"log/slog"
+	"maps"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Source is where a Watcher reloads tunables from, its values are keyed like the
+// environment variables they override
+type Source interface {
+	Name() string
+	Load(ctx context.Context) (map[string]string, error)
+}
+
+// FileSource reads KEY=value lines from a file, e.g. a mounted ConfigMap. Blank lines and
+// lines starting with # are skipped.
+type FileSource struct {
+	Path string
+}
+
+func (f FileSource) Name() string {
+	return "file:" + f.Path
+}
+
+func (f FileSource) Load(ctx context.Context) (map[string]string, error) {
+	file, err := os.Open(f.Path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open config file: %w", err)
+	}
+	defer file.Close()
+
+	values := make(map[string]string)
+	scanner := bufio.NewScanner(file)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		key, value, ok := strings.Cut(text, "=")
+		if !ok || strings.TrimSpace(key) == "" {
+			return nil, fmt.Errorf("%s:%d: expected KEY=value", f.Path, line)
+		}
+		values[strings.TrimSpace(key)] = strings.TrimSpace(value)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	return values, nil
+}
+
+// WatcherStatus is what a Watcher did since the service started
+type WatcherStatus struct {
+	Source     string     `json:"source"`
+	LastReload *time.Time `json:"last_reload,omitempty"` // when the tunables last changed
+	LastCheck  *time.Time `json:"last_check,omitempty"`
+	LastError  string     `json:"last_error,omitempty"` // why the last check wasn't applied, the tunables before it stay
+	Reloads    int64      `json:"reloads"`
+	Rejected   int64      `json:"rejected"`
+}
+
+// Watcher checks a Source for changed tunables and puts them in effect without a restart.
+// Tunables that don't validate are rejected as a whole.
+type Watcher struct {
+	config   *Config
+	source   Source
+	interval time.Duration
+
+	mu        sync.Mutex
+	current   map[string]string // the source's values in effect
+	rejected  map[string]string // the source's values last rejected, not tried again
+	status    WatcherStatus
+	listeners []func(*Tunables)
+}
+
+func NewWatcher(cfg *Config, source Source, interval time.Duration) *Watcher {
+	if interval <= 0 {
+		interval = 30 * time.Second
+	}
+	return &Watcher{
+		config:   cfg,
+		source:   source,
+		interval: interval,
+		status:   WatcherStatus{Source: source.Name()},
+	}
+}
+
+// OnChange calls fn with the new tunables after each reload, for what only reads them once,
+// e.g. job schedules
+func (w *Watcher) OnChange(fn func(*Tunables)) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.listeners = append(w.listeners, fn)
+}
+
+// Start checks the source every interval until ctx is done
+func (w *Watcher) Start(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(w.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if err := w.Check(ctx); err != nil {
+					slog.WarnContext(ctx, "⚠️ Could not reload config", "source", w.status.Source, "error", err)
+				}
+			}
+		}
+	}()
+}
+
+// Check loads the source and puts its values in effect when they changed
+func (w *Watcher) Check(ctx context.Context) error {
+	values, err := w.source.Load(ctx)
+
+	w.mu.Lock()
+	now := time.Now()
+	w.status.LastCheck = &now
+	if err == nil && w.current != nil && maps.Equal(values, w.current) {
+		w.status.LastError = ""
+		w.mu.Unlock()
+		return nil
+	}
+	if err == nil && w.rejected != nil && maps.Equal(values, w.rejected) {
+		w.mu.Unlock()
+		return nil
+	}
+
+	var tunables *Tunables
+	if err == nil {
+		tunables, err = w.config.ReloadTunables(values)
+	}
+	if err != nil {
+		w.status.LastError = err.Error()
+		if values != nil {
+			w.status.Rejected++
+			w.rejected = values
+		}
+		w.mu.Unlock()
+		return err
+	}
+
+	w.current = values
+	w.rejected = nil
+	w.status.LastError = ""
+	w.status.LastReload = &now
+	w.status.Reloads++
+	listeners := w.listeners
+	w.mu.Unlock()
+
+	slog.InfoContext(ctx, "🔧 Config reloaded", "source", w.status.Source, "overridden", tunables.Overridden)
+	for _, listener := range listeners {
+		listener(tunables)
+	}
+	return nil
+}
+
+// Status returns what the watcher did so far
+func (w *Watcher) Status() WatcherStatus {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.status
+}
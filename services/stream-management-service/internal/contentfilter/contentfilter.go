@@ -0,0 +1,150 @@
+// services/stream-management-service/internal/contentfilter/contentfilter.go
+package contentfilter
+
+import (
+	"context"
+	"log/slog"
+	"sort"
+	"strings"
+)
+
+// Action is what happens to text a rule matched
+type Action string
+
+const (
+	// ActionReject refuses the write, or falls back to a default for generated text
+	ActionReject Action = "reject"
+	// ActionSanitize masks what matched. Matches a rule can't locate in the text, like an
+	// external API's verdict, are flagged instead.
+	ActionSanitize Action = "sanitize"
+	// ActionFlag keeps the text and queues it for a moderator
+	ActionFlag Action = "flag"
+)
+
+func (a Action) Valid() bool {
+	switch a {
+	case ActionReject, ActionSanitize, ActionFlag:
+		return true
+	}
+	return false
+}
+
+// Match is something a rule found in the text
+type Match struct {
+	Rule   string `json:"rule" dynamodbav:"rule"`
+	Term   string `json:"term,omitempty" dynamodbav:"term,omitempty"`
+	Action Action `json:"action" dynamodbav:"action"`
+
+	// Start and End are the byte offsets of the match, -1 when the matcher can't tell
+	Start int `json:"-" dynamodbav:"-"`
+	End   int `json:"-" dynamodbav:"-"`
+}
+
+// Matcher finds objectionable text
+type Matcher interface {
+	Name() string
+	Match(ctx context.Context, text string) ([]Match, error)
+}
+
+// Rule is a matcher and what to do with its matches
+type Rule struct {
+	Matcher Matcher
+	Action  Action
+}
+
+// Result is the outcome of checking a text
+type Result struct {
+	// Text is what may be stored, masked where a sanitize rule matched
+	Text      string
+	Rejected  bool
+	Sanitized bool
+	Flagged   bool
+	Matches   []Match
+}
+
+// Rules returns the names of the rules that matched
+func (r *Result) Rules() []string {
+	seen := make(map[string]bool, len(r.Matches))
+	var rules []string
+	for _, match := range r.Matches {
+		if !seen[match.Rule] {
+			seen[match.Rule] = true
+			rules = append(rules, match.Rule)
+		}
+	}
+	return rules
+}
+
+// Filter checks text against its rules
+type Filter struct {
+	rules []Rule
+}
+
+func New(rules ...Rule) *Filter {
+	return &Filter{rules: rules}
+}
+
+// Enabled reports whether the filter has any rules
+func (f *Filter) Enabled() bool {
+	return len(f.rules) > 0
+}
+
+// Check runs text through every rule. A rule that fails, e.g. an external API that is down,
+// is skipped so writes aren't blocked on it.
+func (f *Filter) Check(ctx context.Context, text string) *Result {
+	result := &Result{Text: text}
+	if text == "" {
+		return result
+	}
+
+	var spans []Match
+	for _, rule := range f.rules {
+		matches, err := rule.Matcher.Match(ctx, text)
+		if err != nil {
+			slog.WarnContext(ctx, "⚠️ Content filter rule failed, skipping it", "rule", rule.Matcher.Name(), "error", err)
+			continue
+		}
+
+		for _, match := range matches {
+			match.Action = rule.Action
+			switch {
+			case rule.Action == ActionReject:
+				result.Rejected = true
+			case rule.Action == ActionSanitize && match.Start >= 0:
+				spans = append(spans, match)
+			default:
+				match.Action = ActionFlag
+				result.Flagged = true
+			}
+			result.Matches = append(result.Matches, match)
+		}
+	}
+
+	if len(spans) > 0 && !result.Rejected {
+		result.Text = mask(text, spans)
+		result.Sanitized = true
+	}
+	return result
+}
+
+// mask replaces every rune inside the spans with an asterisk
+func mask(text string, spans []Match) string {
+	sort.Slice(spans, func(i, j int) bool { return spans[i].Start < spans[j].Start })
+
+	var b strings.Builder
+	next := 0
+	for _, span := range spans {
+		start, end := span.Start, span.End
+		if start < next {
+			start = next
+		}
+		if end <= start || end > len(text) {
+			continue
+		}
+		b.WriteString(text[next:start])
+		b.WriteString(strings.Repeat("*", len([]rune(text[start:end]))))
+		next = end
+	}
+	b.WriteString(text[next:])
+	return b.String()
+}
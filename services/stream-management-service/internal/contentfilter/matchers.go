@@ -0,0 +1,155 @@
+// services/stream-management-service/internal/contentfilter/matchers.go
+package contentfilter
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// WordList matches banned words as whole words, regardless of case
+type WordList struct {
+	name  string
+	words map[string]bool
+}
+
+func NewWordList(name string, words []string) *WordList {
+	list := &WordList{name: name, words: make(map[string]bool, len(words))}
+	for _, word := range words {
+		if word = strings.ToLower(strings.TrimSpace(word)); word != "" {
+			list.words[word] = true
+		}
+	}
+	return list
+}
+
+func (w *WordList) Name() string {
+	return w.name
+}
+
+// Len returns how many words are banned
+func (w *WordList) Len() int {
+	return len(w.words)
+}
+
+func (w *WordList) Match(ctx context.Context, text string) ([]Match, error) {
+	var matches []Match
+	start := -1
+	for i, r := range text + " " {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			if start < 0 {
+				start = i
+			}
+			continue
+		}
+		if start < 0 {
+			continue
+		}
+		if word := text[start:i]; w.words[strings.ToLower(word)] {
+			matches = append(matches, Match{Rule: w.name, Term: word, Start: start, End: i})
+		}
+		start = -1
+	}
+	return matches, nil
+}
+
+// Regex matches a regular expression in Go syntax
+type Regex struct {
+	name    string
+	pattern *regexp.Regexp
+}
+
+func NewRegex(name, pattern string) (*Regex, error) {
+	compiled, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("invalid pattern for rule %s: %w", name, err)
+	}
+	return &Regex{name: name, pattern: compiled}, nil
+}
+
+func (r *Regex) Name() string {
+	return r.name
+}
+
+func (r *Regex) Match(ctx context.Context, text string) ([]Match, error) {
+	var matches []Match
+	for _, loc := range r.pattern.FindAllStringIndex(text, -1) {
+		if loc[1] > loc[0] {
+			matches = append(matches, Match{Rule: r.name, Term: text[loc[0]:loc[1]], Start: loc[0], End: loc[1]})
+		}
+	}
+	return matches, nil
+}
+
+// ModerationAPI asks an external moderation service about text. The service is sent
+// {"text": "..."} and answers {"flagged": bool, "categories": ["..."]}. Its verdict covers
+// the whole text, so it can't be sanitized.
+type ModerationAPI struct {
+	url    string
+	apiKey string
+	client *http.Client
+}
+
+func NewModerationAPI(url, apiKey string, timeout time.Duration) *ModerationAPI {
+	return &ModerationAPI{
+		url:    url,
+		apiKey: apiKey,
+		client: &http.Client{Timeout: timeout},
+	}
+}
+
+func (m *ModerationAPI) Name() string {
+	return "moderation_api"
+}
+
+type moderationResponse struct {
+	Flagged    bool     `json:"flagged"`
+	Categories []string `json:"categories"`
+}
+
+func (m *ModerationAPI) Match(ctx context.Context, text string) ([]Match, error) {
+	body, err := json.Marshal(map[string]string{"text": text})
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, m.url, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if m.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+m.apiKey)
+	}
+
+	resp, err := m.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("moderation API request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("moderation API returned status %d", resp.StatusCode)
+	}
+
+	var verdict moderationResponse
+	if err := json.NewDecoder(resp.Body).Decode(&verdict); err != nil {
+		return nil, fmt.Errorf("failed to decode moderation API response: %w", err)
+	}
+	if !verdict.Flagged {
+		return nil, nil
+	}
+
+	return []Match{{
+		Rule:  m.Name(),
+		Term:  strings.Join(verdict.Categories, ","),
+		Start: -1,
+		End:   -1,
+	}}, nil
+}
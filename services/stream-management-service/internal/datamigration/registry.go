@@ -0,0 +1,84 @@
+// services/stream-management-service/internal/datamigration/registry.go
+package datamigration
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+)
+
+// VersionAttribute holds the schema version of every migrated item
+const VersionAttribute = "schema_version"
+
+type Item = map[string]*dynamodb.AttributeValue
+
+// Migration upgrades an item from Version-1 to Version. Up must be idempotent and should only
+// add attributes that the previous release ignores, so old (blue) and new (green) instances
+// can share the table while a rollout is in progress.
+type Migration struct {
+	Version     int
+	Description string
+	Up          func(item Item) error
+}
+
+// Registry is the ordered list of migrations for one table
+type Registry struct {
+	table      string
+	migrations []Migration
+}
+
+// NewRegistry builds a registry. Versions must start at 1 and have no gaps.
+func NewRegistry(table string, migrations ...Migration) *Registry {
+	for i, m := range migrations {
+		if m.Version != i+1 {
+			panic(fmt.Sprintf("migration %q for table %s has version %d, expected %d", m.Description, table, m.Version, i+1))
+		}
+	}
+
+	return &Registry{
+		table:      table,
+		migrations: migrations,
+	}
+}
+
+func (r *Registry) Table() string {
+	return r.table
+}
+
+// Latest is the version items are stamped with when written by this release
+func (r *Registry) Latest() int {
+	return len(r.migrations)
+}
+
+// ItemVersion returns an item's schema version. Items written before versioning existed are version 0.
+func ItemVersion(item Item) int {
+	attr, ok := item[VersionAttribute]
+	if !ok || attr.N == nil {
+		return 0
+	}
+	version, err := strconv.Atoi(aws.StringValue(attr.N))
+	if err != nil {
+		return 0
+	}
+	return version
+}
+
+// Upgrade applies the pending migrations to an item in place. Items written by a newer
+// release are left untouched since their migrations are unknown here.
+func (r *Registry) Upgrade(item Item) (bool, error) {
+	version := ItemVersion(item)
+	if version >= r.Latest() {
+		return false, nil
+	}
+
+	for _, m := range r.migrations[version:] {
+		if err := m.Up(item); err != nil {
+			return false, fmt.Errorf("migration %d (%s) failed on table %s: %w", m.Version, m.Description, r.table, err)
+		}
+		item[VersionAttribute] = &dynamodb.AttributeValue{N: aws.String(strconv.Itoa(m.Version))}
+	}
+
+	return true, nil
+}
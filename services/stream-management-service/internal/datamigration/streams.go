@@ -0,0 +1,23 @@
+// services/stream-management-service/internal/datamigration/streams.go
+package datamigration
+
+import (
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+)
+
+// StreamMigrations lists the data migrations for the streams table, oldest first.
+// Append new migrations here; never edit or reorder released ones.
+func StreamMigrations(table string) *Registry {
+	return NewRegistry(table,
+		Migration{
+			Version:     1,
+			Description: "store missing metadata as an empty map",
+			Up: func(item Item) error {
+				if attr, ok := item["metadata"]; !ok || attr.NULL != nil {
+					item["metadata"] = &dynamodb.AttributeValue{M: map[string]*dynamodb.AttributeValue{}}
+				}
+				return nil
+			},
+		},
+	)
+}
@@ -0,0 +1,63 @@
+// services/stream-management-service/internal/fingerprint/http.go
+package fingerprint
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// HTTPProvider posts samples to a recognition service, e.g. an adapter in front of a
+// commercial fingerprinting API. The service answers {"matches": [...]} in the Match format.
+type HTTPProvider struct {
+	url        string
+	apiKey     string
+	httpClient *http.Client
+}
+
+func NewHTTPProvider(url, apiKey string) *HTTPProvider {
+	return &HTTPProvider{
+		url:        url,
+		apiKey:     apiKey,
+		httpClient: &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+func (p *HTTPProvider) Name() string {
+	return "http"
+}
+
+func (p *HTTPProvider) Identify(ctx context.Context, sample Sample) ([]Match, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.url, bytes.NewReader(sample.Audio))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "audio/wav")
+	req.Header.Set("X-Sample-Start", strconv.FormatInt(sample.Start, 10))
+	req.Header.Set("X-Sample-Duration", strconv.FormatInt(sample.Duration, 10))
+	if p.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+p.apiKey)
+	}
+
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call fingerprint provider: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fingerprint provider returned status %d", resp.StatusCode)
+	}
+
+	var body struct {
+		Matches []Match `json:"matches"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, fmt.Errorf("failed to decode fingerprint response: %w", err)
+	}
+	return body.Matches, nil
+}
@@ -0,0 +1,48 @@
+// services/stream-management-service/internal/fingerprint/provider.go
+package fingerprint
+
+import (
+	"context"
+	"fmt"
+)
+
+// Sample is a slice of a recording's audio, mono 16 kHz WAV
+type Sample struct {
+	Start    int64 // seconds from the start of the recording
+	Duration int64 // seconds
+	Audio    []byte
+}
+
+// Match is a copyrighted track recognised in a sample. Start and End are seconds relative
+// to the sample; a provider that can't tell where the track plays leaves both 0 and the
+// whole sample is considered matched.
+type Match struct {
+	Title  string  `json:"title"`
+	Artist string  `json:"artist"`
+	ISRC   string  `json:"isrc"`
+	Score  float64 `json:"score"` // confidence between 0 and 1
+	Start  float64 `json:"start"`
+	End    float64 `json:"end"`
+}
+
+// Provider recognises copyrighted music in audio samples
+type Provider interface {
+	Name() string
+	Identify(ctx context.Context, sample Sample) ([]Match, error)
+}
+
+// NewProvider returns the provider configured by FINGERPRINT_PROVIDER, or nil when
+// fingerprinting is off
+func NewProvider(name, url, apiKey string) (Provider, error) {
+	switch name {
+	case "", "none":
+		return nil, nil
+	case "http":
+		if url == "" {
+			return nil, fmt.Errorf("FINGERPRINT_URL is required for the http provider")
+		}
+		return NewHTTPProvider(url, apiKey), nil
+	default:
+		return nil, fmt.Errorf("unknown fingerprint provider %q", name)
+	}
+}
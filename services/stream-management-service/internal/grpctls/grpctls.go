@@ -0,0 +1,186 @@
+// services/stream-management-service/internal/grpctls/grpctls.go
+package grpctls
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/secretsmanager"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/services/stream-management-service/internal/config"
+)
+
+const (
+	ModeOff  = "off"
+	ModeTLS  = "tls"  // the server presents a certificate, clients verify it
+	ModeMTLS = "mtls" // both sides present certificates and verify each other
+)
+
+// material holds the PEM blocks certificates are built from
+type material struct {
+	Certificate   string `json:"certificate"`
+	PrivateKey    string `json:"private_key"`
+	CACertificate string `json:"ca_certificate"`
+}
+
+// Enabled reports whether gRPC traffic is encrypted
+func Enabled(cfg *config.Config) bool {
+	return cfg.GRPCTLSMode == ModeTLS || cfg.GRPCTLSMode == ModeMTLS
+}
+
+// ServerCredentials returns the transport credentials of the gRPC server, or nil when TLS is off
+func ServerCredentials(cfg *config.Config) (credentials.TransportCredentials, error) {
+	if err := validateMode(cfg.GRPCTLSMode); err != nil || !Enabled(cfg) {
+		return nil, err
+	}
+
+	m, err := load(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	cert, err := tls.X509KeyPair([]byte(m.Certificate), []byte(m.PrivateKey))
+	if err != nil {
+		return nil, fmt.Errorf("failed to load gRPC server certificate: %w", err)
+	}
+
+	tlsConfig := &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		MinVersion:   tls.VersionTLS12,
+	}
+
+	if cfg.GRPCTLSMode == ModeMTLS {
+		if m.CACertificate == "" {
+			return nil, fmt.Errorf("mtls needs a CA certificate to verify clients with")
+		}
+		pool, err := certPool(m.CACertificate)
+		if err != nil {
+			return nil, err
+		}
+		tlsConfig.ClientCAs = pool
+		tlsConfig.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+
+	return credentials.NewTLS(tlsConfig), nil
+}
+
+// ClientCredentials returns the transport credentials for dialing other services, plaintext
+// when TLS is off
+func ClientCredentials(cfg *config.Config) (credentials.TransportCredentials, error) {
+	if err := validateMode(cfg.GRPCTLSMode); err != nil {
+		return nil, err
+	}
+	if !Enabled(cfg) {
+		return insecure.NewCredentials(), nil
+	}
+
+	m, err := load(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	tlsConfig := &tls.Config{
+		ServerName: cfg.GRPCTLSServerName,
+		MinVersion: tls.VersionTLS12,
+	}
+
+	if m.CACertificate != "" {
+		pool, err := certPool(m.CACertificate)
+		if err != nil {
+			return nil, err
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	if cfg.GRPCTLSMode == ModeMTLS {
+		cert, err := tls.X509KeyPair([]byte(m.Certificate), []byte(m.PrivateKey))
+		if err != nil {
+			return nil, fmt.Errorf("failed to load gRPC client certificate: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	return credentials.NewTLS(tlsConfig), nil
+}
+
+func validateMode(mode string) error {
+	switch mode {
+	case "", ModeOff, ModeTLS, ModeMTLS:
+		return nil
+	}
+	return fmt.Errorf("unknown GRPC_TLS_MODE %q, expected off, tls or mtls", mode)
+}
+
+// load reads the certificates from Secrets Manager when a secret is configured, from files
+// otherwise
+func load(cfg *config.Config) (*material, error) {
+	if cfg.GRPCTLSSecretID != "" {
+		return loadSecret(cfg)
+	}
+
+	m := &material{}
+	files := []struct {
+		path string
+		dst  *string
+	}{
+		{cfg.GRPCTLSCertFile, &m.Certificate},
+		{cfg.GRPCTLSKeyFile, &m.PrivateKey},
+		{cfg.GRPCTLSCAFile, &m.CACertificate},
+	}
+	for _, file := range files {
+		if file.path == "" {
+			continue
+		}
+		data, err := os.ReadFile(file.path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", file.path, err)
+		}
+		*file.dst = string(data)
+	}
+
+	if m.Certificate == "" || m.PrivateKey == "" {
+		return nil, fmt.Errorf("GRPC_TLS_CERT_FILE and GRPC_TLS_KEY_FILE are required when GRPC_TLS_MODE is %s", cfg.GRPCTLSMode)
+	}
+	return m, nil
+}
+
+// loadSecret fetches a JSON secret with certificate, private_key and ca_certificate PEMs
+func loadSecret(cfg *config.Config) (*material, error) {
+	sess, err := session.NewSession(&aws.Config{
+		Region: aws.String(cfg.AWSRegion),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create AWS session: %w", err)
+	}
+
+	out, err := secretsmanager.New(sess).GetSecretValue(&secretsmanager.GetSecretValueInput{
+		SecretId: aws.String(cfg.GRPCTLSSecretID),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get secret %s: %w", cfg.GRPCTLSSecretID, err)
+	}
+
+	var m material
+	if err := json.Unmarshal([]byte(aws.StringValue(out.SecretString)), &m); err != nil {
+		return nil, fmt.Errorf("failed to parse secret %s: %w", cfg.GRPCTLSSecretID, err)
+	}
+	if m.Certificate == "" || m.PrivateKey == "" {
+		return nil, fmt.Errorf("secret %s has no certificate or private_key", cfg.GRPCTLSSecretID)
+	}
+	return &m, nil
+}
+
+func certPool(pem string) (*x509.CertPool, error) {
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM([]byte(pem)) {
+		return nil, fmt.Errorf("no certificates found in the CA bundle")
+	}
+	return pool, nil
+}
@@ -0,0 +1,150 @@
+// services/stream-management-service/internal/health/health.go
+package health
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+type Status string
+
+const (
+	StatusUp   Status = "up"
+	StatusDown Status = "down"
+)
+
+// Check is a dependency checked while the service runs, unlike the preflight checks that
+// only run at startup
+type Check struct {
+	Name     string // e.g. "dynamodb"
+	Critical bool   // the service isn't ready while it is down
+	Run      func(ctx context.Context) error
+}
+
+// Result is the outcome of a single check
+type Result struct {
+	Name      string `json:"name"`
+	Status    Status `json:"status"`
+	Critical  bool   `json:"critical"`
+	LatencyMs int64  `json:"latency_ms"`
+	Error     string `json:"error,omitempty"`
+}
+
+// Report collects the results of running every check
+type Report struct {
+	Ready     bool      `json:"ready"`
+	Draining  bool      `json:"draining,omitempty"`
+	CheckedAt time.Time `json:"checked_at"`
+	Results   []Result  `json:"checks"`
+}
+
+// Degraded reports whether a check that isn't critical is down
+func (r *Report) Degraded() bool {
+	for _, result := range r.Results {
+		if result.Status == StatusDown && !result.Critical {
+			return true
+		}
+	}
+	return false
+}
+
+// Checker runs the checks concurrently, each bounded by a timeout, and reuses the report for
+// cacheTTL so frequent probes don't turn into load on the dependencies
+type Checker struct {
+	checks   []Check
+	timeout  time.Duration
+	cacheTTL time.Duration
+	draining atomic.Bool
+
+	mu     sync.Mutex
+	last   *Report
+	lastAt time.Time
+}
+
+func NewChecker(timeout, cacheTTL time.Duration, checks ...Check) *Checker {
+	return &Checker{checks: checks, timeout: timeout, cacheTTL: cacheTTL}
+}
+
+// SetDraining makes the service report not ready from now on, so it is taken out of load
+// balancing before it stops
+func (c *Checker) SetDraining() {
+	c.draining.Store(true)
+}
+
+// Run returns the latest report, running the checks if it is older than the cache TTL
+func (c *Checker) Run(ctx context.Context) *Report {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.last == nil || time.Since(c.lastAt) >= c.cacheTTL {
+		// Probes that give up early mustn't leave a failed report cached for the others
+		c.last = c.run(context.WithoutCancel(ctx))
+		c.lastAt = time.Now()
+	}
+
+	report := *c.last
+	if c.draining.Load() {
+		report.Ready = false
+		report.Draining = true
+	}
+	return &report
+}
+
+func (c *Checker) run(ctx context.Context) *Report {
+	report := &Report{
+		Ready:     true,
+		CheckedAt: time.Now().UTC(),
+		Results:   make([]Result, len(c.checks)),
+	}
+
+	var wg sync.WaitGroup
+	for i, check := range c.checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			report.Results[i] = c.runCheck(ctx, check)
+		}()
+	}
+	wg.Wait()
+
+	for _, result := range report.Results {
+		if result.Status == StatusDown && result.Critical {
+			report.Ready = false
+		}
+	}
+	return report
+}
+
+// runCheck gives up on a check after the timeout even if it doesn't honor its context
+func (c *Checker) runCheck(ctx context.Context, check Check) Result {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	start := time.Now()
+	done := make(chan error, 1)
+	go func() {
+		done <- check.Run(ctx)
+	}()
+
+	var err error
+	select {
+	case err = <-done:
+	case <-ctx.Done():
+		err = fmt.Errorf("timed out after %s", c.timeout)
+	}
+
+	result := Result{
+		Name:      check.Name,
+		Status:    StatusUp,
+		Critical:  check.Critical,
+		LatencyMs: time.Since(start).Milliseconds(),
+	}
+	if err != nil {
+		result.Status = StatusDown
+		result.Error = err.Error()
+	}
+	return result
+}
@@ -0,0 +1,80 @@
+// services/stream-management-service/internal/logging/logging.go
+package logging
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/services/stream-management-service/pkg/tracing"
+)
+
+// Setup installs the process wide logger used through log/slog. format is json or
+// console, level is debug, info, warn or error.
+func Setup(format, level string) error {
+	var lvl slog.Level
+	if err := lvl.UnmarshalText([]byte(level)); err != nil {
+		return fmt.Errorf("invalid log level %q: %w", level, err)
+	}
+
+	options := &slog.HandlerOptions{Level: lvl}
+
+	var handler slog.Handler
+	switch strings.ToLower(format) {
+	case "json":
+		handler = slog.NewJSONHandler(os.Stderr, options)
+	case "console", "text", "":
+		handler = slog.NewTextHandler(os.Stderr, options)
+	default:
+		return fmt.Errorf("invalid log format %q, expected json or console", format)
+	}
+
+	slog.SetDefault(slog.New(&contextHandler{Handler: handler}))
+	return nil
+}
+
+type fieldsKey struct{}
+
+// With returns a context whose log lines carry the given key/value pairs, e.g.
+// request_id, stream_id or user_id. Use it with slog's *Context functions.
+func With(ctx context.Context, args ...any) context.Context {
+	fields, _ := ctx.Value(fieldsKey{}).([]slog.Attr)
+
+	record := slog.NewRecord(time.Time{}, 0, "", 0)
+	record.Add(args...)
+
+	merged := make([]slog.Attr, 0, len(fields)+record.NumAttrs())
+	merged = append(merged, fields...)
+	record.Attrs(func(attr slog.Attr) bool {
+		merged = append(merged, attr)
+		return true
+	})
+
+	return context.WithValue(ctx, fieldsKey{}, merged)
+}
+
+// contextHandler adds the fields stored by With and the current trace to every record
+type contextHandler struct {
+	slog.Handler
+}
+
+func (h *contextHandler) Handle(ctx context.Context, record slog.Record) error {
+	if fields, ok := ctx.Value(fieldsKey{}).([]slog.Attr); ok {
+		record.AddAttrs(fields...)
+	}
+	if traceID := tracing.TraceIDFromContext(ctx); traceID != "" {
+		record.AddAttrs(slog.String("trace_id", traceID))
+	}
+	return h.Handler.Handle(ctx, record)
+}
+
+func (h *contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &contextHandler{Handler: h.Handler.WithAttrs(attrs)}
+}
+
+func (h *contextHandler) WithGroup(name string) slog.Handler {
+	return &contextHandler{Handler: h.Handler.WithGroup(name)}
+}
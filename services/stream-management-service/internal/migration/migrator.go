@@ -0,0 +1,405 @@
+// services/stream-management-service/internal/migration/migrator.go
+package migration
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/services/stream-management-service/internal/config"
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/services/stream-management-service/internal/repository"
+)
+
+const (
+	tableActiveTimeout = 5 * time.Minute // GSI backfills on big tables take a while
+	tablePollInterval  = 2 * time.Second
+)
+
+// SchemaMigration is one versioned change to the service's tables. Up must be safe to run
+// again, a replica can die between applying a migration and recording it.
+type SchemaMigration struct {
+	Version     int
+	Description string
+	Up          func(m *Migrator) error
+}
+
+// schemaMigrations are applied in version order. Never change or remove one that shipped,
+// add a new version instead.
+var schemaMigrations = []SchemaMigration{
+	{
+		Version:     1,
+		Description: "create the service's tables",
+		Up:          (*Migrator).createMissingTables,
+	},
+	{
+		Version:     2,
+		Description: "add GSIs defined after their table was created",
+		Up:          (*Migrator).addMissingIndexes,
+	},
+	{
+		Version:     3,
+		Description: "enable TTL on expiring tables",
+		Up:          (*Migrator).enableTTL,
+	},
+	{
+		Version:     4,
+		Description: "add the recording status GSI to the streams table",
+		Up:          (*Migrator).addMissingIndexes,
+	},
+	{
+		Version:     5,
+		Description: "create the event outbox table and enable TTL on it",
+		Up:          (*Migrator).createMissingTablesWithTTL,
+	},
+	{
+		Version:     6,
+		Description: "create the dead letter table and enable TTL on it",
+		Up:          (*Migrator).createMissingTablesWithTTL,
+	},
+	{
+		Version:     7,
+		Description: "create the event dedupe table and enable TTL on it",
+		Up:          (*Migrator).createMissingTablesWithTTL,
+	},
+}
+
+// Migrator brings the service's DynamoDB tables to the schema in repository.TableDefinitions
+// and records the migrations it applied
+type Migrator struct {
+	client *dynamodb.DynamoDB
+	config *config.Config
+}
+
+func NewMigrator(client *dynamodb.DynamoDB, cfg *config.Config) *Migrator {
+	return &Migrator{client: client, config: cfg}
+}
+
+// Migrate applies the migrations that weren't recorded yet and returns how many it applied
+func (m *Migrator) Migrate() (int, error) {
+	if err := m.createTable(m.migrationsTableDefinition()); err != nil {
+		return 0, fmt.Errorf("failed to create migrations table: %w", err)
+	}
+
+	applied, err := m.appliedVersions()
+	if err != nil {
+		return 0, err
+	}
+
+	pending := make([]SchemaMigration, 0, len(schemaMigrations))
+	for _, migration := range schemaMigrations {
+		if !applied[migration.Version] {
+			pending = append(pending, migration)
+		}
+	}
+	sort.Slice(pending, func(i, j int) bool { return pending[i].Version < pending[j].Version })
+
+	if len(pending) == 0 {
+		slog.Info("✅ DynamoDB schema is up to date", "version", m.latestVersion())
+		return 0, nil
+	}
+
+	for _, migration := range pending {
+		slog.Info("🔨 Applying migration", "version", migration.Version, "description", migration.Description)
+		if err := migration.Up(m); err != nil {
+			return 0, fmt.Errorf("failed to apply migration %d (%s): %w", migration.Version, migration.Description, err)
+		}
+		if err := m.recordVersion(migration); err != nil {
+			return 0, err
+		}
+	}
+
+	slog.Info("🎉 DynamoDB migrations applied", "applied", len(pending), "version", m.latestVersion())
+	return len(pending), nil
+}
+
+// Cleanup deletes every table of the service, migrations included, and waits until they are gone
+func (m *Migrator) Cleanup() error {
+	slog.Warn("🧹 Deleting the service's DynamoDB tables")
+
+	var failed []string
+	for _, table := range repository.TableDefinitions(m.config) {
+		tableName := aws.StringValue(table.TableName)
+
+		_, err := m.client.DeleteTable(&dynamodb.DeleteTableInput{
+			TableName: aws.String(tableName),
+		})
+		if isNotFound(err) {
+			slog.Debug("📋 Table does not exist", "table", tableName)
+			continue
+		}
+		if err != nil {
+			slog.Warn("⚠️ Could not delete table", "table", tableName, "error", err)
+			failed = append(failed, tableName)
+			continue
+		}
+
+		if err := m.client.WaitUntilTableNotExists(&dynamodb.DescribeTableInput{
+			TableName: aws.String(tableName),
+		}); err != nil {
+			slog.Warn("⚠️ Could not wait for table deletion", "table", tableName, "error", err)
+			failed = append(failed, tableName)
+			continue
+		}
+		slog.Info("🗑️ Table deleted", "table", tableName)
+	}
+
+	if len(failed) > 0 {
+		return fmt.Errorf("failed to delete tables %v", failed)
+	}
+	return nil
+}
+
+// createMissingTables creates the tables from their definitions, GSIs included
+func (m *Migrator) createMissingTables() error {
+	for _, table := range repository.TableDefinitions(m.config) {
+		if err := m.createTable(table); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// createMissingTablesWithTTL creates the tables defined after version 1 and enables TTL on
+// them, which version 3 only did for the tables that existed then
+func (m *Migrator) createMissingTablesWithTTL() error {
+	if err := m.createMissingTables(); err != nil {
+		return err
+	}
+	return m.enableTTL()
+}
+
+// addMissingIndexes adds the GSIs a table's definition gained after it was created. DynamoDB
+// builds one GSI per UpdateTable call, so each is waited for before the next.
+func (m *Migrator) addMissingIndexes() error {
+	for _, table := range repository.TableDefinitions(m.config) {
+		tableName := aws.StringValue(table.TableName)
+
+		result, err := m.client.DescribeTable(&dynamodb.DescribeTableInput{
+			TableName: aws.String(tableName),
+		})
+		if err != nil {
+			return fmt.Errorf("failed to describe table %s: %w", tableName, err)
+		}
+
+		existing := make(map[string]bool, len(result.Table.GlobalSecondaryIndexes))
+		for _, index := range result.Table.GlobalSecondaryIndexes {
+			existing[aws.StringValue(index.IndexName)] = true
+		}
+
+		for _, index := range table.GlobalSecondaryIndexes {
+			indexName := aws.StringValue(index.IndexName)
+			if existing[indexName] {
+				continue
+			}
+
+			slog.Info("🔨 Adding GSI", "table", tableName, "index", indexName)
+			_, err := m.client.UpdateTable(&dynamodb.UpdateTableInput{
+				TableName:            aws.String(tableName),
+				AttributeDefinitions: indexAttributes(table, index),
+				GlobalSecondaryIndexUpdates: []*dynamodb.GlobalSecondaryIndexUpdate{
+					{
+						Create: &dynamodb.CreateGlobalSecondaryIndexAction{
+							IndexName:             index.IndexName,
+							KeySchema:             index.KeySchema,
+							Projection:            index.Projection,
+							ProvisionedThroughput: index.ProvisionedThroughput,
+						},
+					},
+				},
+			})
+			if err != nil {
+				return fmt.Errorf("failed to add GSI %s to table %s: %w", indexName, tableName, err)
+			}
+			if err := m.waitForTableActive(tableName); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// enableTTL turns on expiry by an attribute for the tables in repository.TTLAttributes
+func (m *Migrator) enableTTL() error {
+	for tableName, attribute := range repository.TTLAttributes(m.config) {
+		result, err := m.client.DescribeTimeToLive(&dynamodb.DescribeTimeToLiveInput{
+			TableName: aws.String(tableName),
+		})
+		if err != nil {
+			return fmt.Errorf("failed to describe TTL of table %s: %w", tableName, err)
+		}
+		if description := result.TimeToLiveDescription; description != nil {
+			switch aws.StringValue(description.TimeToLiveStatus) {
+			case dynamodb.TimeToLiveStatusEnabled, dynamodb.TimeToLiveStatusEnabling:
+				continue
+			}
+		}
+
+		_, err = m.client.UpdateTimeToLive(&dynamodb.UpdateTimeToLiveInput{
+			TableName: aws.String(tableName),
+			TimeToLiveSpecification: &dynamodb.TimeToLiveSpecification{
+				AttributeName: aws.String(attribute),
+				Enabled:       aws.Bool(true),
+			},
+		})
+		if err != nil {
+			return fmt.Errorf("failed to enable TTL on table %s: %w", tableName, err)
+		}
+		slog.Info("⏳ TTL enabled", "table", tableName, "attribute", attribute)
+	}
+	return nil
+}
+
+// createTable creates a table unless it exists and waits until it is active
+func (m *Migrator) createTable(input *dynamodb.CreateTableInput) error {
+	tableName := aws.StringValue(input.TableName)
+
+	_, err := m.client.DescribeTable(&dynamodb.DescribeTableInput{
+		TableName: aws.String(tableName),
+	})
+	if err == nil {
+		slog.Debug("📋 Table already exists", "table", tableName)
+		return nil
+	}
+	if !isNotFound(err) {
+		return fmt.Errorf("failed to describe table %s: %w", tableName, err)
+	}
+
+	slog.Info("🔨 Creating DynamoDB table", "table", tableName)
+	if _, err := m.client.CreateTable(input); err != nil {
+		var aerr awserr.Error
+		if errors.As(err, &aerr) && aerr.Code() == dynamodb.ErrCodeResourceInUseException {
+			// Another replica is creating it
+			return m.waitForTableActive(tableName)
+		}
+		return fmt.Errorf("failed to create table %s: %w", tableName, err)
+	}
+
+	return m.waitForTableActive(tableName)
+}
+
+// waitForTableActive waits until a table and all its GSIs are active
+func (m *Migrator) waitForTableActive(tableName string) error {
+	deadline := time.Now().Add(tableActiveTimeout)
+	for {
+		result, err := m.client.DescribeTable(&dynamodb.DescribeTableInput{
+			TableName: aws.String(tableName),
+		})
+		if err != nil && !isNotFound(err) {
+			return fmt.Errorf("failed to describe table %s: %w", tableName, err)
+		}
+		if err == nil && tableActive(result.Table) {
+			slog.Info("✅ DynamoDB table ready", "table", tableName)
+			return nil
+		}
+
+		if time.Now().After(deadline) {
+			return fmt.Errorf("table %s did not become active within %s", tableName, tableActiveTimeout)
+		}
+		slog.Info("⏳ Waiting for table to become active...", "table", tableName)
+		time.Sleep(tablePollInterval)
+	}
+}
+
+func (m *Migrator) appliedVersions() (map[int]bool, error) {
+	applied := make(map[int]bool)
+
+	err := m.client.ScanPages(&dynamodb.ScanInput{
+		TableName:                aws.String(m.config.MigrationsTable),
+		ProjectionExpression:     aws.String("#version"),
+		ExpressionAttributeNames: map[string]*string{"#version": aws.String("version")},
+		ConsistentRead:           aws.Bool(true),
+	}, func(page *dynamodb.ScanOutput, lastPage bool) bool {
+		for _, item := range page.Items {
+			if attr, ok := item["version"]; ok && attr.N != nil {
+				if version, err := strconv.Atoi(*attr.N); err == nil {
+					applied[version] = true
+				}
+			}
+		}
+		return true
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read applied migrations: %w", err)
+	}
+	return applied, nil
+}
+
+// recordVersion marks a migration as applied. A replica that raced us to it already did.
+func (m *Migrator) recordVersion(migration SchemaMigration) error {
+	_, err := m.client.PutItem(&dynamodb.PutItemInput{
+		TableName: aws.String(m.config.MigrationsTable),
+		Item: map[string]*dynamodb.AttributeValue{
+			"version":     {N: aws.String(strconv.Itoa(migration.Version))},
+			"description": {S: aws.String(migration.Description)},
+			"applied_at":  {S: aws.String(time.Now().UTC().Format(time.RFC3339))},
+		},
+		ConditionExpression:      aws.String("attribute_not_exists(#version)"),
+		ExpressionAttributeNames: map[string]*string{"#version": aws.String("version")},
+	})
+	var aerr awserr.Error
+	if errors.As(err, &aerr) && aerr.Code() == dynamodb.ErrCodeConditionalCheckFailedException {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to record migration %d: %w", migration.Version, err)
+	}
+	return nil
+}
+
+func (m *Migrator) migrationsTableDefinition() *dynamodb.CreateTableInput {
+	for _, table := range repository.TableDefinitions(m.config) {
+		if aws.StringValue(table.TableName) == m.config.MigrationsTable {
+			return table
+		}
+	}
+	panic("migrations table is missing from repository.TableDefinitions")
+}
+
+func (m *Migrator) latestVersion() int {
+	latest := 0
+	for _, migration := range schemaMigrations {
+		latest = max(latest, migration.Version)
+	}
+	return latest
+}
+
+func tableActive(table *dynamodb.TableDescription) bool {
+	if aws.StringValue(table.TableStatus) != dynamodb.TableStatusActive {
+		return false
+	}
+	for _, index := range table.GlobalSecondaryIndexes {
+		if aws.StringValue(index.IndexStatus) != dynamodb.IndexStatusActive {
+			return false
+		}
+	}
+	return true
+}
+
+// indexAttributes returns the definitions of the attributes an index is keyed by, which
+// UpdateTable needs for a new GSI
+func indexAttributes(table *dynamodb.CreateTableInput, index *dynamodb.GlobalSecondaryIndex) []*dynamodb.AttributeDefinition {
+	keys := make(map[string]bool, len(index.KeySchema))
+	for _, key := range index.KeySchema {
+		keys[aws.StringValue(key.AttributeName)] = true
+	}
+
+	var attributes []*dynamodb.AttributeDefinition
+	for _, attribute := range table.AttributeDefinitions {
+		if keys[aws.StringValue(attribute.AttributeName)] {
+			attributes = append(attributes, attribute)
+		}
+	}
+	return attributes
+}
+
+func isNotFound(err error) bool {
+	var aerr awserr.Error
+	return errors.As(err, &aerr) && aerr.Code() == dynamodb.ErrCodeResourceNotFoundException
+}
@@ -0,0 +1,227 @@
+// services/stream-management-service/internal/migration/plan.go
+package migration
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/services/stream-management-service/internal/config"
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/services/stream-management-service/internal/repository"
+)
+
+// Plan is a machine-readable description of the AWS resources the service expects,
+// so they can be provisioned outside the app (Terraform, CloudFormation, ...)
+type Plan struct {
+	Service        string         `json:"service"`
+	Region         string         `json:"region"`
+	Tables         []TableSpec    `json:"dynamodb_tables"`
+	KinesisStreams []KinesisSpec  `json:"kinesis_streams"`
+	S3Buckets      []S3BucketSpec `json:"s3_buckets"`
+}
+
+type TableSpec struct {
+	Name                   string          `json:"name"`
+	BillingMode            string          `json:"billing_mode"`
+	Attributes             []AttributeSpec `json:"attributes"`
+	HashKey                string          `json:"hash_key"`
+	RangeKey               string          `json:"range_key,omitempty"`
+	GlobalSecondaryIndexes []IndexSpec     `json:"global_secondary_indexes,omitempty"`
+	TTLAttribute           string          `json:"ttl_attribute,omitempty"`
+}
+
+type AttributeSpec struct {
+	Name string `json:"name"`
+	Type string `json:"type"` // S, N or B
+}
+
+type IndexSpec struct {
+	Name           string `json:"name"`
+	HashKey        string `json:"hash_key"`
+	RangeKey       string `json:"range_key,omitempty"`
+	ProjectionType string `json:"projection_type"`
+}
+
+type KinesisSpec struct {
+	Name       string `json:"name"`
+	ShardCount int    `json:"shard_count"`
+}
+
+type S3BucketSpec struct {
+	Name string `json:"name"`
+}
+
+// BuildPlan describes every DynamoDB table, Kinesis stream and S3 bucket the service uses
+func BuildPlan(cfg *config.Config) *Plan {
+	plan := &Plan{
+		Service: "stream-management",
+		Region:  cfg.AWSRegion,
+		KinesisStreams: []KinesisSpec{
+			{Name: cfg.KinesisStreamName, ShardCount: 1},
+		},
+		S3Buckets: []S3BucketSpec{
+			{Name: cfg.S3BucketName},
+		},
+	}
+
+	ttlAttributes := repository.TTLAttributes(cfg)
+	for _, table := range repository.TableDefinitions(cfg) {
+		spec := tableSpecFromInput(table)
+		spec.TTLAttribute = ttlAttributes[spec.Name]
+		plan.Tables = append(plan.Tables, spec)
+	}
+
+	return plan
+}
+
+// Render outputs the plan in the requested format: "json" or "cloudformation"
+func (p *Plan) Render(format string) ([]byte, error) {
+	switch strings.ToLower(format) {
+	case "", "json":
+		return json.MarshalIndent(p, "", "  ")
+	case "cloudformation", "cfn":
+		return json.MarshalIndent(p.cloudFormationTemplate(), "", "  ")
+	default:
+		return nil, fmt.Errorf("unknown plan format %q (expected json or cloudformation)", format)
+	}
+}
+
+func tableSpecFromInput(input *dynamodb.CreateTableInput) TableSpec {
+	spec := TableSpec{
+		Name:        aws.StringValue(input.TableName),
+		BillingMode: aws.StringValue(input.BillingMode),
+	}
+	spec.HashKey, spec.RangeKey = splitKeySchema(input.KeySchema)
+
+	for _, attr := range input.AttributeDefinitions {
+		spec.Attributes = append(spec.Attributes, AttributeSpec{
+			Name: aws.StringValue(attr.AttributeName),
+			Type: aws.StringValue(attr.AttributeType),
+		})
+	}
+
+	for _, index := range input.GlobalSecondaryIndexes {
+		indexSpec := IndexSpec{
+			Name:           aws.StringValue(index.IndexName),
+			ProjectionType: aws.StringValue(index.Projection.ProjectionType),
+		}
+		indexSpec.HashKey, indexSpec.RangeKey = splitKeySchema(index.KeySchema)
+		spec.GlobalSecondaryIndexes = append(spec.GlobalSecondaryIndexes, indexSpec)
+	}
+
+	return spec
+}
+
+func splitKeySchema(schema []*dynamodb.KeySchemaElement) (hashKey, rangeKey string) {
+	for _, key := range schema {
+		switch aws.StringValue(key.KeyType) {
+		case dynamodb.KeyTypeHash:
+			hashKey = aws.StringValue(key.AttributeName)
+		case dynamodb.KeyTypeRange:
+			rangeKey = aws.StringValue(key.AttributeName)
+		}
+	}
+	return hashKey, rangeKey
+}
+
+// cloudFormationTemplate converts the plan into a CloudFormation template
+func (p *Plan) cloudFormationTemplate() map[string]interface{} {
+	resources := make(map[string]interface{})
+
+	for _, table := range p.Tables {
+		var attributes []map[string]string
+		for _, attr := range table.Attributes {
+			attributes = append(attributes, map[string]string{
+				"AttributeName": attr.Name,
+				"AttributeType": attr.Type,
+			})
+		}
+
+		properties := map[string]interface{}{
+			"TableName":            table.Name,
+			"BillingMode":          table.BillingMode,
+			"AttributeDefinitions": attributes,
+			"KeySchema":            cfnKeySchema(table.HashKey, table.RangeKey),
+		}
+
+		var indexes []map[string]interface{}
+		for _, index := range table.GlobalSecondaryIndexes {
+			indexes = append(indexes, map[string]interface{}{
+				"IndexName":  index.Name,
+				"KeySchema":  cfnKeySchema(index.HashKey, index.RangeKey),
+				"Projection": map[string]string{"ProjectionType": index.ProjectionType},
+			})
+		}
+		if len(indexes) > 0 {
+			properties["GlobalSecondaryIndexes"] = indexes
+		}
+		if table.TTLAttribute != "" {
+			properties["TimeToLiveSpecification"] = map[string]interface{}{
+				"AttributeName": table.TTLAttribute,
+				"Enabled":       true,
+			}
+		}
+
+		resources[cfnLogicalID("Table", table.Name)] = map[string]interface{}{
+			"Type":       "AWS::DynamoDB::Table",
+			"Properties": properties,
+		}
+	}
+
+	for _, stream := range p.KinesisStreams {
+		resources[cfnLogicalID("Stream", stream.Name)] = map[string]interface{}{
+			"Type": "AWS::Kinesis::Stream",
+			"Properties": map[string]interface{}{
+				"Name":       stream.Name,
+				"ShardCount": stream.ShardCount,
+			},
+		}
+	}
+
+	for _, bucket := range p.S3Buckets {
+		resources[cfnLogicalID("Bucket", bucket.Name)] = map[string]interface{}{
+			"Type": "AWS::S3::Bucket",
+			"Properties": map[string]interface{}{
+				"BucketName": bucket.Name,
+			},
+		}
+	}
+
+	return map[string]interface{}{
+		"AWSTemplateFormatVersion": "2010-09-09",
+		"Description":              fmt.Sprintf("Resources required by the %s service", p.Service),
+		"Resources":                resources,
+	}
+}
+
+func cfnKeySchema(hashKey, rangeKey string) []map[string]string {
+	schema := []map[string]string{
+		{"AttributeName": hashKey, "KeyType": dynamodb.KeyTypeHash},
+	}
+	if rangeKey != "" {
+		schema = append(schema, map[string]string{"AttributeName": rangeKey, "KeyType": dynamodb.KeyTypeRange})
+	}
+	return schema
+}
+
+// cfnLogicalID builds an alphanumeric CloudFormation logical ID such as "StreamsTable"
+func cfnLogicalID(suffix, name string) string {
+	var id strings.Builder
+	upperNext := true
+	for _, r := range name {
+		if (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') {
+			if upperNext {
+				id.WriteString(strings.ToUpper(string(r)))
+			} else {
+				id.WriteRune(r)
+			}
+			upperNext = false
+			continue
+		}
+		upperNext = true
+	}
+	return id.String() + suffix
+}
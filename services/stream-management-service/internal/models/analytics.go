@@ -0,0 +1,66 @@
+// services/stream-management-service/internal/models/analytics.go
+package models
+
+import (
+	"time"
+)
+
+// StreamAnalytics summarises the audience of a stream. It is computed from viewer heartbeats
+// while live and stored on the stream once it ends.
+type StreamAnalytics struct {
+	PeakViewers     int64     `json:"peak_viewers" dynamodbav:"peak_viewers"`
+	AverageViewers  float64   `json:"average_viewers" dynamodbav:"average_viewers"`
+	UniqueViewers   int64     `json:"unique_viewers" dynamodbav:"unique_viewers"`
+	ChatMessages    int64     `json:"chat_messages" dynamodbav:"chat_messages"`
+	NewFollowers    int64     `json:"new_followers" dynamodbav:"new_followers"`
+	RaidsReceived   int64     `json:"raids_received" dynamodbav:"raids_received"`
+	RaidViewers     int64     `json:"raid_viewers" dynamodbav:"raid_viewers"`     // viewers brought in by raids
+	RaidedViewers   int64     `json:"raided_viewers" dynamodbav:"raided_viewers"` // viewers sent on by this stream's raid
+	DurationSeconds int64     `json:"duration_seconds" dynamodbav:"duration_seconds"`
+	Final           bool      `json:"final" dynamodbav:"final"` // false while the stream is live
+	ComputedAt      time.Time `json:"computed_at" dynamodbav:"computed_at"`
+
+	// Incidents that overlapped the stream, looked up when the analytics are read
+	Incidents []*Incident `json:"incidents,omitempty" dynamodbav:"-"`
+}
+
+// AnalyticsPeriod sums the analytics of the streams that started in a time range. Averages
+// are weighted by stream duration.
+type AnalyticsPeriod struct {
+	From                  time.Time                   `json:"from"`
+	To                    time.Time                   `json:"to"`
+	Streams               int                         `json:"streams"`
+	HoursStreamed         float64                     `json:"hours_streamed"`
+	AverageViewers        float64                     `json:"average_viewers"`
+	PeakViewers           int64                       `json:"peak_viewers"`
+	ChatMessagesPerMinute float64                     `json:"chat_messages_per_minute"`
+	NewFollowers          int64                       `json:"new_followers"`
+	ByCategory            map[string]*AnalyticsPeriod `json:"by_category,omitempty"`
+}
+
+// StreamAnalyticsEntry is one stream of a comparison
+type StreamAnalyticsEntry struct {
+	StreamID              string    `json:"stream_id"`
+	Title                 string    `json:"title"`
+	Category              string    `json:"category,omitempty"`
+	StartedAt             time.Time `json:"started_at"`
+	DurationSeconds       int64     `json:"duration_seconds"`
+	AverageViewers        float64   `json:"average_viewers"`
+	PeakViewers           int64     `json:"peak_viewers"`
+	ChatMessagesPerMinute float64   `json:"chat_messages_per_minute"`
+	NewFollowers          int64     `json:"new_followers"`
+}
+
+// StreamComparison compares a creator's streams of a period with the period before it.
+// Change holds the relative change of each metric, metrics that were 0 before are left out.
+type StreamComparison struct {
+	UserID   int64                  `json:"user_id"`
+	Days     int                    `json:"days"`
+	Current  *AnalyticsPeriod       `json:"current"`
+	Previous *AnalyticsPeriod       `json:"previous"`
+	Change   map[string]float64     `json:"change"`
+	Streams  []StreamAnalyticsEntry `json:"streams"`
+
+	// Incidents overlapping either period
+	Incidents []*Incident `json:"incidents"`
+}
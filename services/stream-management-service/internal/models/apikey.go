@@ -0,0 +1,73 @@
+// services/stream-management-service/internal/models/apikey.go
+package models
+
+import (
+	"time"
+)
+
+// Scopes an API key can be granted
+const (
+	ScopeStreamsRead   = "streams:read"
+	ScopeStreamsWrite  = "streams:write"
+	ScopeVODsRead      = "vods:read"
+	ScopeVODsWrite     = "vods:write"
+	ScopeClipsRead     = "clips:read"
+	ScopeClipsWrite    = "clips:write"
+	ScopeRestreamRead  = "restream:read"
+	ScopeRestreamWrite = "restream:write"
+	ScopeStatsRead     = "stats:read"
+	ScopeLatencyWrite  = "latency:write"   // reference players reporting glass-to-glass latency
+	ScopeUserDataRead  = "user_data:read"  // exporting a user's data
+	ScopeUserDataWrite = "user_data:write" // erasing a user's data
+	ScopeActAsAnyUser  = "users:act_as"    // changing any user's content, for trusted integrations like moderation tools
+)
+
+var KnownScopes = []string{
+	ScopeStreamsRead, ScopeStreamsWrite,
+	ScopeVODsRead, ScopeVODsWrite,
+	ScopeClipsRead, ScopeClipsWrite,
+	ScopeRestreamRead, ScopeRestreamWrite,
+	ScopeStatsRead, ScopeLatencyWrite,
+	ScopeUserDataRead, ScopeUserDataWrite,
+	ScopeActAsAnyUser,
+}
+
+// APIKey identifies a third-party integrator of the REST API. Only a hash of the secret is stored.
+type APIKey struct {
+	ID           string    `json:"id" dynamodbav:"id"`
+	Name         string    `json:"name" dynamodbav:"name"`
+	Owner        string    `json:"owner" dynamodbav:"owner"`                         // Contact of the integrator
+	UserID       int64     `json:"user_id,omitempty" dynamodbav:"user_id,omitempty"` // User whose content the key may change, 0 for none
+	SecretHash   string    `json:"-" dynamodbav:"secret_hash"`
+	Scopes       []string  `json:"scopes" dynamodbav:"scopes"`
+	RateLimit    int       `json:"rate_limit" dynamodbav:"rate_limit"`       // Requests per minute
+	MonthlyQuota int64     `json:"monthly_quota" dynamodbav:"monthly_quota"` // Requests per calendar month (UTC)
+	Revoked      bool      `json:"revoked" dynamodbav:"revoked"`
+	CreatedAt    time.Time `json:"created_at" dynamodbav:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at" dynamodbav:"updated_at"`
+}
+
+// ActsFor reports whether the key may change content owned by a user
+func (k *APIKey) ActsFor(userID int64) bool {
+	return (k.UserID != 0 && k.UserID == userID) || k.HasScope(ScopeActAsAnyUser)
+}
+
+// HasScope reports whether the key was granted a scope
+func (k *APIKey) HasScope(scope string) bool {
+	for _, s := range k.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// APIKeyUsage is the request count of a key for one month
+type APIKeyUsage struct {
+	KeyID        string           `json:"key_id"`
+	Month        string           `json:"month"` // YYYY-MM
+	Total        int64            `json:"total"`
+	MonthlyQuota int64            `json:"monthly_quota"`
+	Remaining    int64            `json:"remaining"`
+	Daily        map[string]int64 `json:"daily"` // YYYY-MM-DD -> requests
+}
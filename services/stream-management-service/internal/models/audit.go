@@ -0,0 +1,66 @@
+// services/stream-management-service/internal/models/audit.go
+package models
+
+import (
+	"time"
+)
+
+// Audited actions, named after what happened to the stream
+const (
+	AuditStreamCreated      = "stream.created"
+	AuditStreamEnded        = "stream.ended"
+	AuditStreamTerminated   = "stream.terminated"
+	AuditStreamReconnecting = "stream.reconnecting"
+	AuditStreamResumed      = "stream.resumed"
+	AuditStreamUpdated      = "stream.updated"
+	AuditRecordingCompleted = "recording.completed"
+	AuditRecordingUploaded  = "recording.uploaded"
+	AuditRecordingFailed    = "recording.failed"
+	AuditDetailsUpdated     = "details.updated"
+	AuditGeoUpdated         = "geo_restrictions.updated"
+	AuditVisibilityUpdated  = "visibility.updated"
+	AuditStreamFeatured     = "stream.featured"
+	AuditStreamUnfeatured   = "stream.unfeatured"
+	AuditStreamBlocked      = "stream.blocked"
+	AuditStreamUnblocked    = "stream.unblocked"
+	AuditRaidSent           = "raid.sent"
+	AuditRaidReceived       = "raid.received"
+	AuditStreamDeleted      = "stream.deleted"
+	AuditStreamRestored     = "stream.restored"
+	AuditVODDeleted         = "vod.deleted"
+	AuditVODRestored        = "vod.restored"
+	AuditContentRemoved     = "content.removed"
+)
+
+// AuditActorType is the kind of caller behind an audited change
+type AuditActorType string
+
+const (
+	AuditActorUser        AuditActorType = "user"
+	AuditActorAPIKey      AuditActorType = "api_key"
+	AuditActorAnonymous   AuditActorType = "anonymous"
+	AuditActorAdmin       AuditActorType = "admin"
+	AuditActorMediaServer AuditActorType = "media_server"
+	AuditActorService     AuditActorType = "service"
+	// AuditActorSystem is this service's own workers, e.g. the reconnect finalizer
+	AuditActorSystem AuditActorType = "system"
+)
+
+// AuditActor is who made an audited change. ID is empty when the caller is unknown.
+type AuditActor struct {
+	Type AuditActorType `json:"type" dynamodbav:"type"`
+	ID   string         `json:"id,omitempty" dynamodbav:"id,omitempty"`
+}
+
+// AuditRecord is one entry of a stream's append-only audit log. Records are never updated,
+// SortKey orders them by time and keeps records made in the same instant apart.
+type AuditRecord struct {
+	StreamID  string            `json:"stream_id" dynamodbav:"stream_id"`
+	SortKey   string            `json:"-" dynamodbav:"sort_key"`
+	ID        string            `json:"id" dynamodbav:"id"`
+	Action    string            `json:"action" dynamodbav:"action"`
+	Actor     AuditActor        `json:"actor" dynamodbav:"actor"`
+	Source    string            `json:"source" dynamodbav:"source"` // api, admin, rtmp, grpc or worker
+	Details   map[string]string `json:"details,omitempty" dynamodbav:"details,omitempty"`
+	CreatedAt time.Time         `json:"created_at" dynamodbav:"created_at"`
+}
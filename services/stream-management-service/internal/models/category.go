@@ -0,0 +1,16 @@
+// services/stream-management-service/internal/models/category.go
+package models
+
+// CategoryStats are the live aggregates of a category
+type CategoryStats struct {
+	Category     string `json:"category"`
+	LiveChannels int64  `json:"live_channels"`
+	Viewers      int64  `json:"viewers"`
+	Featured     bool   `json:"featured"`
+}
+
+// CategoryStream is a live stream of a category with the viewers it was last counted with
+type CategoryStream struct {
+	StreamID string
+	Viewers  int64
+}
@@ -0,0 +1,10 @@
+// services/stream-management-service/internal/models/chapter.go
+package models
+
+// Chapter marks where a broadcaster switched to another title or category, so viewers of
+// the VOD can jump between segments
+type Chapter struct {
+	Start    int64  `json:"start" dynamodbav:"start"` // seconds into the recording
+	Title    string `json:"title" dynamodbav:"title"`
+	Category string `json:"category,omitempty" dynamodbav:"category,omitempty"`
+}
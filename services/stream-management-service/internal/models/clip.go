@@ -0,0 +1,32 @@
+// services/stream-management-service/internal/models/clip.go
+package models
+
+import (
+	"time"
+)
+
+type ClipStatus string
+
+const (
+	ClipStatusPending    ClipStatus = "pending"
+	ClipStatusProcessing ClipStatus = "processing"
+	ClipStatusReady      ClipStatus = "ready"
+	ClipStatusFailed     ClipStatus = "failed"
+)
+
+// Clip is a short segment cut from a stream recording
+type Clip struct {
+	ID          string     `json:"id" dynamodbav:"id"`
+	StreamID    string     `json:"stream_id" dynamodbav:"stream_id"`
+	UserID      int64      `json:"user_id" dynamodbav:"user_id"`       // Streamer who owns the source stream
+	CreatedBy   int64      `json:"created_by" dynamodbav:"created_by"` // User who requested the clip
+	Title       string     `json:"title" dynamodbav:"title"`
+	StartOffset int64      `json:"start_offset" dynamodbav:"start_offset"` // seconds from stream start
+	EndOffset   int64      `json:"end_offset" dynamodbav:"end_offset"`     // seconds from stream start
+	Duration    int64      `json:"duration" dynamodbav:"duration"`         // seconds
+	Status      ClipStatus `json:"status" dynamodbav:"status"`
+	URL         string     `json:"url,omitempty" dynamodbav:"url,omitempty"`
+	Error       string     `json:"error,omitempty" dynamodbav:"error,omitempty"`
+	CreatedAt   time.Time  `json:"created_at" dynamodbav:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at" dynamodbav:"updated_at"`
+}
@@ -0,0 +1,33 @@
+// services/stream-management-service/internal/models/content_review.go
+package models
+
+import (
+	"time"
+
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/services/stream-management-service/internal/contentfilter"
+)
+
+type ContentReviewStatus string
+
+const (
+	ContentReviewPending ContentReviewStatus = "pending"
+	// ContentReviewApproved content stays as it was written
+	ContentReviewApproved ContentReviewStatus = "approved"
+	// ContentReviewRemoved content was replaced, a title with the default one
+	ContentReviewRemoved ContentReviewStatus = "removed"
+)
+
+// ContentReview is text a content filter rule flagged, queued for a moderator. It is stored
+// with the platform stats rollups and expires a while after it was reviewed.
+type ContentReview struct {
+	ID         string                `json:"id" dynamodbav:"bucket"`
+	StreamID   string                `json:"stream_id" dynamodbav:"stream_id"`
+	UserID     int64                 `json:"user_id" dynamodbav:"user_id"`
+	Field      string                `json:"field" dynamodbav:"field"` // e.g. title
+	Text       string                `json:"text" dynamodbav:"text"`
+	Matches    []contentfilter.Match `json:"matches" dynamodbav:"matches"`
+	Status     ContentReviewStatus   `json:"status" dynamodbav:"status"`
+	CreatedAt  time.Time             `json:"created_at" dynamodbav:"created_at"`
+	ReviewedAt *time.Time            `json:"reviewed_at,omitempty" dynamodbav:"reviewed_at,omitempty"`
+	ExpiresAt  int64                 `json:"-" dynamodbav:"expires_at,omitempty"`
+}
@@ -0,0 +1,45 @@
+// services/stream-management-service/internal/models/dashboard.go
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// ChannelEventType is what happened on a channel in its recent event feed
+type ChannelEventType string
+
+const (
+	ChannelEventFollow ChannelEventType = "follow"
+)
+
+// ChannelEvent is an entry of a channel's recent event feed, newest first
+type ChannelEvent struct {
+	Type   ChannelEventType `json:"type"`
+	UserID int64            `json:"user_id"`
+	At     time.Time        `json:"at"`
+}
+
+// ChatSummary is the chat activity of a stream as reported by the chat service
+type ChatSummary struct {
+	PeriodHours            int             `json:"period_hours"`
+	TotalMessages          int64           `json:"total_messages"`
+	TotalModerationActions int64           `json:"total_moderation_actions"`
+	Buckets                json.RawMessage `json:"buckets"`
+	TopChatters            json.RawMessage `json:"top_chatters"`
+}
+
+// CreatorDashboard is everything a broadcaster's dashboard shows, assembled from several
+// backends. Errors names the sections that could not be loaded, keyed by section.
+type CreatorDashboard struct {
+	UserID        int64             `json:"user_id"`
+	Live          bool              `json:"live"`
+	Stream        *Stream           `json:"stream,omitempty"`
+	ViewerCount   int               `json:"viewer_count"`
+	Health        *StreamHealth     `json:"health,omitempty"`
+	FollowerCount *int64            `json:"follower_count,omitempty"`
+	RecentEvents  []ChannelEvent    `json:"recent_events"`
+	Chat          *ChatSummary      `json:"chat,omitempty"`
+	Errors        map[string]string `json:"errors,omitempty"`
+	GeneratedAt   time.Time         `json:"generated_at"`
+}
@@ -0,0 +1,30 @@
+// services/stream-management-service/internal/models/dead_letter.go
+package models
+
+import (
+	"time"
+)
+
+type DeadLetterStatus string
+
+const (
+	DeadLetterStatusPending  DeadLetterStatus = "pending"  // waiting for an admin to look at it
+	DeadLetterStatusReplayed DeadLetterStatus = "replayed" // handled when an admin replayed it
+)
+
+// DeadLetter is an event record a consumer gave up on, kept until an admin replays or
+// discards it or it expires
+type DeadLetter struct {
+	ID          string           `json:"id" dynamodbav:"id"`
+	Consumer    string           `json:"consumer" dynamodbav:"consumer"`
+	EventID     string           `json:"event_id,omitempty" dynamodbav:"event_id,omitempty"` // empty for records that aren't envelopes
+	EventType   string           `json:"event_type,omitempty" dynamodbav:"event_type,omitempty"`
+	Reason      string           `json:"reason" dynamodbav:"reason"`
+	Record      string           `json:"record" dynamodbav:"record"`
+	Attempts    int              `json:"attempts" dynamodbav:"attempts"` // 0 when the record was rejected without handling it
+	Status      DeadLetterStatus `json:"status" dynamodbav:"status"`
+	FailedAt    time.Time        `json:"failed_at" dynamodbav:"failed_at"`
+	ReplayedAt  *time.Time       `json:"replayed_at,omitempty" dynamodbav:"replayed_at,omitempty"`
+	ReplayError string           `json:"replay_error,omitempty" dynamodbav:"replay_error,omitempty"` // of the last replay that failed
+	ExpiresAt   int64            `json:"-" dynamodbav:"expires_at"`
+}
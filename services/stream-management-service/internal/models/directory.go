@@ -0,0 +1,18 @@
+// services/stream-management-service/internal/models/directory.go
+package models
+
+// DirectoryReason is why a stream ranks where it does in the directory
+type DirectoryReason string
+
+const (
+	DirectoryReasonFeatured DirectoryReason = "featured"
+	DirectoryReasonTrending DirectoryReason = "trending"
+	DirectoryReasonFollowed DirectoryReason = "followed"
+)
+
+// DirectoryEntry is a live stream ranked for the directory
+type DirectoryEntry struct {
+	Stream  *Stream           `json:"stream"`
+	Score   float64           `json:"score"`
+	Reasons []DirectoryReason `json:"reasons"`
+}
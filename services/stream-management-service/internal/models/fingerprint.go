@@ -0,0 +1,14 @@
+// services/stream-management-service/internal/models/fingerprint.go
+package models
+
+// AudioMatch is a copyrighted track recognised in a recording
+type AudioMatch struct {
+	Start    int64   `json:"start" dynamodbav:"start"` // seconds from the start of the recording
+	End      int64   `json:"end" dynamodbav:"end"`
+	Title    string  `json:"title" dynamodbav:"title"`
+	Artist   string  `json:"artist,omitempty" dynamodbav:"artist,omitempty"`
+	ISRC     string  `json:"isrc,omitempty" dynamodbav:"isrc,omitempty"`
+	Score    float64 `json:"score" dynamodbav:"score"`
+	Provider string  `json:"provider" dynamodbav:"provider"`
+	Muted    bool    `json:"muted" dynamodbav:"muted"`
+}
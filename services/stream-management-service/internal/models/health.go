@@ -0,0 +1,59 @@
+// services/stream-management-service/internal/models/health.go
+package models
+
+import (
+	"time"
+)
+
+type HealthStatus string
+
+const (
+	HealthStatusUnknown  HealthStatus = "unknown"
+	HealthStatusGood     HealthStatus = "good"
+	HealthStatusDegraded HealthStatus = "degraded"
+	HealthStatusCritical HealthStatus = "critical"
+)
+
+// HealthSample is a single connection quality report from the media server
+type HealthSample struct {
+	BitrateKbps      int       `json:"bitrate_kbps"`
+	FPS              float64   `json:"fps"`
+	DroppedFrames    int64     `json:"dropped_frames"`    // since the previous sample
+	KeyframeInterval float64   `json:"keyframe_interval"` // seconds
+	Timestamp        time.Time `json:"timestamp"`
+}
+
+// StreamHealth summarises the recent samples of a live stream
+type StreamHealth struct {
+	Status           HealthStatus `json:"status"`
+	Reasons          []string     `json:"reasons,omitempty"`
+	AvgBitrateKbps   int          `json:"avg_bitrate_kbps"`
+	AvgFPS           float64      `json:"avg_fps"`
+	DroppedFrames    int64        `json:"dropped_frames"`    // across the window
+	KeyframeInterval float64      `json:"keyframe_interval"` // latest, in seconds
+	SampleCount      int          `json:"sample_count"`
+	UpdatedAt        time.Time    `json:"updated_at"`
+}
+
+type HealthAlertKind string
+
+const (
+	HealthAlertLowBitrate    HealthAlertKind = "low_bitrate"
+	HealthAlertDroppedFrames HealthAlertKind = "dropped_frames"
+	HealthAlertLowFrameRate  HealthAlertKind = "low_frame_rate"
+	// Limit alerts warn that the stream is about to be stopped for going over a permission
+	HealthAlertBitrateLimit  HealthAlertKind = "bitrate_limit"
+	HealthAlertDurationLimit HealthAlertKind = "duration_limit"
+)
+
+// HealthAlert warns a broadcaster that their ingest crossed a health threshold
+type HealthAlert struct {
+	StreamID  string          `json:"stream_id"`
+	UserID    int64           `json:"user_id"`
+	Kind      HealthAlertKind `json:"kind"`
+	Severity  HealthStatus    `json:"severity"` // degraded or critical
+	Message   string          `json:"message"`
+	Value     float64         `json:"value"`
+	Threshold float64         `json:"threshold"`
+	CreatedAt time.Time       `json:"created_at"`
+}
@@ -0,0 +1,48 @@
+// services/stream-management-service/internal/models/incident.go
+package models
+
+import (
+	"time"
+)
+
+type IncidentKind string
+
+const (
+	IncidentKindIngestOutage IncidentKind = "ingest_outage"
+	IncidentKindCDNIssue     IncidentKind = "cdn_issue"
+	IncidentKindChatOutage   IncidentKind = "chat_outage"
+	IncidentKindMaintenance  IncidentKind = "maintenance"
+	IncidentKindOther        IncidentKind = "other"
+)
+
+// Valid reports whether k is a known incident kind
+func (k IncidentKind) Valid() bool {
+	switch k {
+	case IncidentKindIngestOutage, IncidentKindCDNIssue, IncidentKindChatOutage, IncidentKindMaintenance, IncidentKindOther:
+		return true
+	}
+	return false
+}
+
+// Incident annotates a time range with an operational event, so dips in analytics can be
+// explained. It is stored with the platform stats rollups.
+type Incident struct {
+	Bucket      string       `json:"-" dynamodbav:"bucket"` // RFC 3339 start and ID, incidents sort by start
+	ID          string       `json:"id" dynamodbav:"id"`
+	Kind        IncidentKind `json:"kind" dynamodbav:"kind"`
+	Title       string       `json:"title" dynamodbav:"title"`
+	Description string       `json:"description,omitempty" dynamodbav:"description,omitempty"`
+	Regions     []string     `json:"regions,omitempty" dynamodbav:"regions,omitempty"` // empty affects every region
+	StartsAt    time.Time    `json:"starts_at" dynamodbav:"starts_at"`
+	EndsAt      *time.Time   `json:"ends_at,omitempty" dynamodbav:"ends_at,omitempty"` // nil while ongoing
+	CreatedAt   time.Time    `json:"created_at" dynamodbav:"created_at"`
+	UpdatedAt   time.Time    `json:"updated_at" dynamodbav:"updated_at"`
+}
+
+// Overlaps reports whether the incident covers part of [from, to]
+func (i *Incident) Overlaps(from, to time.Time) bool {
+	if i.StartsAt.After(to) {
+		return false
+	}
+	return i.EndsAt == nil || !i.EndsAt.Before(from)
+}
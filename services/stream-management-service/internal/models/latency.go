@@ -0,0 +1,35 @@
+// services/stream-management-service/internal/models/latency.go
+package models
+
+import (
+	"time"
+)
+
+// LatencyMarker is a timestamp the media server embeds in a stream at ingest (e.g. as ID3
+// timed metadata) so a player can tell how long the frame took to reach it
+type LatencyMarker struct {
+	ID       string    `json:"marker_id"`
+	StreamID string    `json:"stream_id"`
+	IngestAt time.Time `json:"ingest_at"`
+	Payload  string    `json:"payload"` // what the media server embeds
+}
+
+// LatencySample is one glass-to-glass measurement reported by a reference player
+type LatencySample struct {
+	MarkerID   string    `json:"marker_id"`
+	LatencyMs  int64     `json:"latency_ms"`
+	PlayerID   string    `json:"player_id,omitempty"`
+	ObservedAt time.Time `json:"observed_at"`
+}
+
+// StreamLatency summarises the recent latency samples of a stream
+type StreamLatency struct {
+	SampleCount int       `json:"sample_count"`
+	MinMs       int64     `json:"min_ms"`
+	P50Ms       int64     `json:"p50_ms"`
+	P90Ms       int64     `json:"p90_ms"`
+	P95Ms       int64     `json:"p95_ms"`
+	P99Ms       int64     `json:"p99_ms"`
+	MaxMs       int64     `json:"max_ms"`
+	UpdatedAt   time.Time `json:"updated_at,omitempty"`
+}
@@ -0,0 +1,19 @@
+// services/stream-management-service/internal/models/marker.go
+package models
+
+import (
+	"time"
+)
+
+// MaxStreamMarkers is how many markers a stream keeps
+const MaxStreamMarkers = 100
+
+// StreamMarker is a moment a streamer or their bot flagged during a broadcast, to come back
+// to for clips, highlights and VOD chapters
+type StreamMarker struct {
+	ID          string    `json:"id" dynamodbav:"id"`
+	Offset      int64     `json:"offset" dynamodbav:"offset"` // seconds into the recording
+	Description string    `json:"description,omitempty" dynamodbav:"description,omitempty"`
+	CreatedBy   int64     `json:"created_by,omitempty" dynamodbav:"created_by,omitempty"` // 0 for API keys
+	CreatedAt   time.Time `json:"created_at" dynamodbav:"created_at"`
+}
@@ -0,0 +1,32 @@
+// services/stream-management-service/internal/models/moderation.go
+package models
+
+import (
+	"time"
+)
+
+// Reasons a stream ended, recorded on the stream and in stream_ended events
+const (
+	EndReasonNormal           = "normal"
+	EndReasonReconnectTimeout = "reconnect_timeout"
+	EndReasonTerminated       = "terminated"
+	// EndReasonReconciled streams were found no longer published by the media server
+	EndReasonReconciled = "reconciled"
+	// Streams that went over their publisher permissions are ended by the service
+	EndReasonMaxBitrate  = "max_bitrate"
+	EndReasonMaxDuration = "max_duration"
+)
+
+// StreamKeyBan keeps a stream key from passing RTMP auth
+type StreamKeyBan struct {
+	StreamKey string     `json:"stream_key" dynamodbav:"stream_key"`
+	UserID    int64      `json:"user_id,omitempty" dynamodbav:"user_id,omitempty"`
+	Reason    string     `json:"reason" dynamodbav:"reason"`
+	CreatedAt time.Time  `json:"created_at" dynamodbav:"created_at"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty" dynamodbav:"expires_at,omitempty"` // permanent when nil
+}
+
+// Active reports whether the ban is still in force
+func (b *StreamKeyBan) Active(now time.Time) bool {
+	return b.ExpiresAt == nil || now.Before(*b.ExpiresAt)
+}
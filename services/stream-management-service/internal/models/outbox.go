@@ -0,0 +1,22 @@
+// services/stream-management-service/internal/models/outbox.go
+package models
+
+import (
+	"time"
+)
+
+// OutboxPending marks an outbox event that wasn't delivered yet
+const OutboxPending = "pending"
+
+// OutboxEvent is an event written in the same transaction as the stream change it reports, so
+// neither is stored without the other. The outbox relay delivers it to the event bus.
+type OutboxEvent struct {
+	ID          string     `json:"id" dynamodbav:"id"` // the envelope's event ID
+	EventType   string     `json:"event_type" dynamodbav:"event_type"`
+	StreamID    string     `json:"stream_id" dynamodbav:"stream_id"`
+	Record      string     `json:"record" dynamodbav:"record"`                     // the JSON encoded envelope
+	Status      string     `json:"status,omitempty" dynamodbav:"status,omitempty"` // OutboxPending until delivered, only pending events are in the pending index
+	CreatedAt   time.Time  `json:"created_at" dynamodbav:"created_at"`             // pending events are relayed oldest first
+	DeliveredAt *time.Time `json:"delivered_at,omitempty" dynamodbav:"delivered_at,omitempty"`
+	ExpiresAt   int64      `json:"-" dynamodbav:"expires_at,omitempty"` // set once delivered
+}
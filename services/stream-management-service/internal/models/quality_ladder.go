@@ -0,0 +1,36 @@
+// services/stream-management-service/internal/models/quality_ladder.go
+package models
+
+import (
+	"time"
+)
+
+// QualityTierDefault is the tier of users who weren't given one
+const QualityTierDefault = "default"
+
+// MaxLadderRenditions bounds the renditions a ladder may ask the transcoder for
+const MaxLadderRenditions = 8
+
+// QualityLadder is the renditions the live transcoder produces for the streams of a tier's
+// users, besides the source. Renditions are sorted by bitrate, highest first.
+type QualityLadder struct {
+	Tier       string            `json:"tier" dynamodbav:"bucket"`
+	Renditions []LadderRendition `json:"renditions" dynamodbav:"renditions"`
+	UpdatedAt  time.Time         `json:"updated_at" dynamodbav:"updated_at"`
+}
+
+// LadderRendition is one output of the live transcoder
+type LadderRendition struct {
+	Name        string `json:"name" dynamodbav:"name"` // e.g. "720p"
+	Width       int    `json:"width" dynamodbav:"width"`
+	Height      int    `json:"height" dynamodbav:"height"`
+	BitrateKbps int    `json:"bitrate_kbps" dynamodbav:"bitrate_kbps"`
+	FPS         int    `json:"fps,omitempty" dynamodbav:"fps,omitempty"` // the source frame rate when 0
+}
+
+// UserTier puts a user's streams on a tier's quality ladder
+type UserTier struct {
+	UserID    int64     `json:"user_id" dynamodbav:"user_id"`
+	Tier      string    `json:"tier" dynamodbav:"tier"`
+	UpdatedAt time.Time `json:"updated_at" dynamodbav:"updated_at"`
+}
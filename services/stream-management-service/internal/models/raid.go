@@ -0,0 +1,18 @@
+// services/stream-management-service/internal/models/raid.go
+package models
+
+import (
+	"time"
+)
+
+// Raid sends a live stream's viewers over to another live stream. The chat service announces
+// it in both rooms and players in the raiding room redirect to the target.
+type Raid struct {
+	ID           string    `json:"id" dynamodbav:"id"`
+	FromStreamID string    `json:"from_stream_id" dynamodbav:"from_stream_id"`
+	FromUserID   int64     `json:"from_user_id" dynamodbav:"from_user_id"`
+	ToStreamID   string    `json:"to_stream_id" dynamodbav:"to_stream_id"`
+	ToUserID     int64     `json:"to_user_id" dynamodbav:"to_user_id"`
+	ViewerCount  int       `json:"viewer_count" dynamodbav:"viewer_count"` // viewers of the raiding stream when it started
+	CreatedAt    time.Time `json:"created_at" dynamodbav:"created_at"`
+}
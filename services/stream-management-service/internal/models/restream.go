@@ -0,0 +1,35 @@
+// services/stream-management-service/internal/models/restream.go
+package models
+
+import (
+	"time"
+)
+
+// RestreamTarget is an external RTMP endpoint a streamer simulcasts to
+type RestreamTarget struct {
+	ID        string    `json:"id" dynamodbav:"id"`
+	UserID    int64     `json:"user_id" dynamodbav:"user_id"`
+	Name      string    `json:"name" dynamodbav:"name"`
+	Platform  string    `json:"platform" dynamodbav:"platform"` // youtube, twitch or custom
+	URL       string    `json:"url" dynamodbav:"url"`           // Ingest URL without the stream key
+	StreamKey string    `json:"-" dynamodbav:"stream_key"`      // Secret, never returned by the API
+	Enabled   bool      `json:"enabled" dynamodbav:"enabled"`
+	CreatedAt time.Time `json:"created_at" dynamodbav:"created_at"`
+	UpdatedAt time.Time `json:"updated_at" dynamodbav:"updated_at"`
+}
+
+type RestreamState string
+
+const (
+	RestreamStatePushing RestreamState = "pushing"
+	RestreamStateStopped RestreamState = "stopped"
+)
+
+// RestreamStatus tracks one restream target on a stream
+type RestreamStatus struct {
+	TargetID  string        `json:"target_id" dynamodbav:"target_id"`
+	Name      string        `json:"name" dynamodbav:"name"`
+	Platform  string        `json:"platform" dynamodbav:"platform"`
+	State     RestreamState `json:"state" dynamodbav:"state"`
+	UpdatedAt time.Time     `json:"updated_at" dynamodbav:"updated_at"`
+}
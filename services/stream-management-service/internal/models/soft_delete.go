@@ -0,0 +1,36 @@
+// services/stream-management-service/internal/models/soft_delete.go
+package models
+
+import (
+	"time"
+)
+
+// DeletedFilter picks which items a list returns by whether they're soft deleted
+type DeletedFilter string
+
+const (
+	// DeletedExclude leaves soft deleted items out, lists do unless asked otherwise
+	DeletedExclude DeletedFilter = "exclude"
+	DeletedInclude DeletedFilter = "include"
+	DeletedOnly    DeletedFilter = "only"
+)
+
+func (f DeletedFilter) Valid() bool {
+	switch f {
+	case DeletedExclude, DeletedInclude, DeletedOnly:
+		return true
+	}
+	return false
+}
+
+// Matches reports whether an item deleted at deletedAt, nil when it isn't, is listed. The
+// zero filter excludes deleted items.
+func (f DeletedFilter) Matches(deletedAt *time.Time) bool {
+	switch f {
+	case DeletedInclude:
+		return true
+	case DeletedOnly:
+		return deletedAt != nil
+	}
+	return deletedAt == nil
+}
@@ -0,0 +1,73 @@
+// services/stream-management-service/internal/models/squad.go
+package models
+
+import (
+	"time"
+)
+
+// MaxSquadSize is how many live streams a squad can show together
+const MaxSquadSize = 4
+
+type SquadChatMode string
+
+const (
+	// SquadChatMerged delivers every member's chat to all members' rooms
+	SquadChatMerged SquadChatMode = "merged"
+	// SquadChatSideBySide keeps each room separate, players show them next to each other
+	SquadChatSideBySide SquadChatMode = "side_by_side"
+)
+
+// Squad groups live streams that players show together. Squads only live as long as their streams.
+type Squad struct {
+	ID            string        `json:"id"`
+	Title         string        `json:"title"`
+	OwnerStreamID string        `json:"owner_stream_id"`
+	ChatMode      SquadChatMode `json:"chat_mode"`
+	Members       []SquadMember `json:"members"`
+	CreatedAt     time.Time     `json:"created_at"`
+	UpdatedAt     time.Time     `json:"updated_at"`
+}
+
+type SquadMember struct {
+	StreamID   string    `json:"stream_id"`
+	UserID     int64     `json:"user_id"`
+	ChatroomID string    `json:"chatroom_id"`
+	JoinedAt   time.Time `json:"joined_at"`
+}
+
+// Member returns the member for a stream, or nil if the stream isn't in the squad
+func (s *Squad) Member(streamID string) *SquadMember {
+	for i := range s.Members {
+		if s.Members[i].StreamID == streamID {
+			return &s.Members[i]
+		}
+	}
+	return nil
+}
+
+// ChatroomIDs lists the chat rooms of all members
+func (s *Squad) ChatroomIDs() []string {
+	ids := make([]string, 0, len(s.Members))
+	for _, member := range s.Members {
+		ids = append(ids, member.ChatroomID)
+	}
+	return ids
+}
+
+// SquadPlayback is everything a player needs to show a squad
+type SquadPlayback struct {
+	SquadID  string                `json:"squad_id"`
+	Title    string                `json:"title"`
+	ChatMode SquadChatMode         `json:"chat_mode"`
+	Streams  []SquadPlaybackStream `json:"streams"`
+}
+
+type SquadPlaybackStream struct {
+	StreamID   string       `json:"stream_id"`
+	UserID     int64        `json:"user_id"`
+	Title      string       `json:"title"`
+	Status     StreamStatus `json:"status"`
+	HLSURL     string       `json:"hls_url"`
+	ChatroomID string       `json:"chatroom_id"`
+	IsOwner    bool         `json:"is_owner"`
+}
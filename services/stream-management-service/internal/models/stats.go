@@ -0,0 +1,40 @@
+// services/stream-management-service/internal/models/stats.go
+package models
+
+import (
+	"time"
+)
+
+// StatsGranularity is the length of a platform stats bucket
+type StatsGranularity string
+
+const (
+	StatsGranularityHour StatsGranularity = "hour"
+	StatsGranularityDay  StatsGranularity = "day"
+)
+
+// Truncate returns the start of the bucket t falls in
+func (g StatsGranularity) Truncate(t time.Time) time.Time {
+	t = t.UTC()
+	if g == StatsGranularityDay {
+		return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	}
+	return t.Truncate(time.Hour)
+}
+
+// StatsRollup is the platform activity of one bucket. Live streams and viewers are sampled
+// periodically and summed, averages are computed when the rollup is read.
+type StatsRollup struct {
+	Granularity     StatsGranularity `json:"-" dynamodbav:"granularity"`
+	Bucket          string           `json:"-" dynamodbav:"bucket"` // RFC 3339 start of the bucket
+	Start           time.Time        `json:"start" dynamodbav:"-"`
+	Samples         int64            `json:"samples" dynamodbav:"samples"`
+	LiveStreamsSum  int64            `json:"-" dynamodbav:"live_streams_sum"`
+	LiveStreamsPeak int64            `json:"live_streams_peak" dynamodbav:"live_streams_peak"`
+	LiveStreamsAvg  float64          `json:"live_streams_avg" dynamodbav:"-"`
+	ViewersSum      int64            `json:"-" dynamodbav:"viewers_sum"`
+	ViewersPeak     int64            `json:"viewers_peak" dynamodbav:"viewers_peak"`
+	ViewersAvg      float64          `json:"viewers_avg" dynamodbav:"-"`
+	NewStreams      int64            `json:"new_streams" dynamodbav:"new_streams"`
+	ExpiresAt       int64            `json:"-" dynamodbav:"expires_at,omitempty"`
+}
@@ -10,24 +10,226 @@ type StreamStatus string
 const (
 	StreamStatusPending StreamStatus = "pending"
 	StreamStatusLive    StreamStatus = "live"
-	StreamStatusEnded   StreamStatus = "ended"
-	StreamStatusError   StreamStatus = "error"
+	// StreamStatusReconnecting means the broadcaster dropped and may still re-publish
+	StreamStatusReconnecting StreamStatus = "reconnecting"
+	StreamStatusEnded        StreamStatus = "ended"
+	StreamStatusError        StreamStatus = "error"
 )
 
+// RecordingStatus tracks a stream's recording from the end of the broadcast until it is
+// stored in S3
+type RecordingStatus string
+
+const (
+	// RecordingStatusPending means the media server hasn't reported the recording yet
+	RecordingStatusPending   RecordingStatus = "pending"
+	RecordingStatusUploading RecordingStatus = "uploading"
+	// RecordingStatusFailed recordings are uploaded again until they run out of attempts
+	RecordingStatusFailed RecordingStatus = "failed"
+	RecordingStatusReady  RecordingStatus = "ready"
+)
+
+// IngestProtocol is how the broadcaster publishes to the media server
+type IngestProtocol string
+
+const (
+	IngestProtocolRTMP IngestProtocol = "rtmp"
+	IngestProtocolSRT  IngestProtocol = "srt"
+	// IngestProtocolWebRTC is a browser publishing over WHIP
+	IngestProtocolWebRTC IngestProtocol = "webrtc"
+)
+
+// StreamVisibility is where a stream is listed and who may play it
+type StreamVisibility string
+
+const (
+	StreamVisibilityPublic StreamVisibility = "public"
+	// StreamVisibilityUnlisted streams play for anyone with the link but aren't listed
+	StreamVisibilityUnlisted StreamVisibility = "unlisted"
+	// StreamVisibilityPrivate streams aren't listed and only play for the broadcaster,
+	// invited viewers and viewers who know the passphrase
+	StreamVisibilityPrivate StreamVisibility = "private"
+)
+
+func (v StreamVisibility) Valid() bool {
+	switch v {
+	case StreamVisibilityPublic, StreamVisibilityUnlisted, StreamVisibilityPrivate:
+		return true
+	}
+	return false
+}
+
 type Stream struct {
 	ID           string            `json:"id" dynamodbav:"id"`
 	UserID       int64             `json:"user_id" dynamodbav:"user_id"`
-	StreamKey    string            `json:"stream_key" dynamodbav:"stream_key"`
+	StreamKey    string            `json:"stream_key,omitempty" dynamodbav:"stream_key"`
 	Title        string            `json:"title" dynamodbav:"title"`
 	Status       StreamStatus      `json:"status" dynamodbav:"status"`
 	StartedAt    *time.Time        `json:"started_at,omitempty" dynamodbav:"started_at,omitempty"`
 	EndedAt      *time.Time        `json:"ended_at,omitempty" dynamodbav:"ended_at,omitempty"`
 	Duration     int64             `json:"duration" dynamodbav:"duration"` // seconds
+	EndReason    string            `json:"end_reason,omitempty" dynamodbav:"end_reason,omitempty"`
 	ViewerCount  int               `json:"viewer_count" dynamodbav:"viewer_count"`
 	RecordingURL string            `json:"recording_url,omitempty" dynamodbav:"recording_url,omitempty"`
 	Metadata     map[string]string `json:"metadata" dynamodbav:"metadata"`
+	Category     string            `json:"category,omitempty" dynamodbav:"category,omitempty"`
+	Tags         []string          `json:"tags,omitempty" dynamodbav:"tags,omitempty"`
+	Language     string            `json:"language,omitempty" dynamodbav:"language,omitempty"` // BCP-47, e.g. en or pt-BR
 	CreatedAt    time.Time         `json:"created_at" dynamodbav:"created_at"`
 	UpdatedAt    time.Time         `json:"updated_at" dynamodbav:"updated_at"`
+
+	// Classification is the latest automatic suggestion. Once the broadcaster picks a
+	// category or tags themselves (ManualTags), suggestions are never auto-applied.
+	Classification *Classification `json:"classification,omitempty" dynamodbav:"classification,omitempty"`
+	ManualTags     bool            `json:"manual_tags,omitempty" dynamodbav:"manual_tags,omitempty"`
+
+	// Chapters are recorded when the title or category changes while live
+	Chapters []Chapter `json:"chapters,omitempty" dynamodbav:"chapters,omitempty"`
+
+	// Markers are moments the streamer flagged while live
+	Markers []StreamMarker `json:"markers,omitempty" dynamodbav:"markers,omitempty"`
+
+	// Featured is set by admins to promote the stream in the directory
+	Featured bool `json:"featured,omitempty" dynamodbav:"featured,omitempty"`
+
+	// IsMature is set by the broadcaster, only signed in adult viewers can play the stream
+	IsMature bool `json:"is_mature" dynamodbav:"is_mature,omitempty"`
+
+	// Visibility is empty for streams created before it existed, which are public. Who may
+	// play a private stream is kept apart in its StreamAccess.
+	Visibility StreamVisibility `json:"visibility,omitempty" dynamodbav:"visibility,omitempty"`
+
+	// Geo restrictions are ISO country codes. Viewers in BlockedRegions, or outside
+	// AllowedRegions when it is set, can't play the stream.
+	AllowedRegions []string `json:"allowed_regions,omitempty" dynamodbav:"allowed_regions,omitempty"`
+	BlockedRegions []string `json:"blocked_regions,omitempty" dynamodbav:"blocked_regions,omitempty"`
+
+	// Blocked is set while a takedown keeps the stream from being played
+	Blocked    bool   `json:"blocked,omitempty" dynamodbav:"blocked,omitempty"`
+	TakedownID string `json:"takedown_id,omitempty" dynamodbav:"takedown_id,omitempty"`
+
+	// AudioMatches is the copyrighted music found in the stream's recording
+	AudioMatches []AudioMatch `json:"audio_matches,omitempty" dynamodbav:"audio_matches,omitempty"`
+
+	// IngestProtocol is empty for streams recorded before SRT ingest, which were all RTMP.
+	// SRTLatencyMs is the receiver latency negotiated for SRT publishers.
+	IngestProtocol IngestProtocol `json:"ingest_protocol,omitempty" dynamodbav:"ingest_protocol,omitempty"`
+	SRTLatencyMs   int            `json:"srt_latency_ms,omitempty" dynamodbav:"srt_latency_ms,omitempty"`
+
+	// PlaybackID names the stream in its HLS and playback URLs, so viewers never learn the
+	// stream key. It is the playback ID of the session the stream was published in.
+	PlaybackID string `json:"playback_id,omitempty" dynamodbav:"playback_id,omitempty"`
+
+	// IngestRegion is the region of the media server the broadcaster published to
+	IngestRegion string `json:"ingest_region,omitempty" dynamodbav:"ingest_region,omitempty"`
+
+	// VODReady is set once the recording is packaged for playback. VODPlaybackURLs holds the
+	// master playlist as "master" and each rendition's playlist by name.
+	VODReady        bool              `json:"vod_ready,omitempty" dynamodbav:"vod_ready,omitempty"`
+	VODPlaybackURLs map[string]string `json:"vod_playback_urls,omitempty" dynamodbav:"vod_playback_urls,omitempty"`
+
+	// RecordingStatus is empty for streams that ended before it existed. RecordingPath is the
+	// file on the media server, kept so a failed upload can be retried at RecordingRetryAt.
+	RecordingStatus   RecordingStatus `json:"recording_status,omitempty" dynamodbav:"recording_status,omitempty"`
+	RecordingPath     string          `json:"-" dynamodbav:"recording_path,omitempty"`
+	RecordingAttempts int             `json:"recording_attempts,omitempty" dynamodbav:"recording_attempts,omitempty"`
+	RecordingError    string          `json:"recording_error,omitempty" dynamodbav:"recording_error,omitempty"`
+	RecordingRetryAt  *time.Time      `json:"recording_retry_at,omitempty" dynamodbav:"recording_retry_at,omitempty"`
+
+	// Analytics is the audience summary stored when the stream ends
+	Analytics *StreamAnalytics `json:"analytics,omitempty" dynamodbav:"analytics,omitempty"`
+
+	// Raid is the stream this one sent its viewers to. RaidsReceived and RaidViewers count the
+	// raids into this stream and the viewers they brought along.
+	Raid          *Raid `json:"raid,omitempty" dynamodbav:"raid,omitempty"`
+	RaidsReceived int   `json:"raids_received,omitempty" dynamodbav:"raids_received,omitempty"`
+	RaidViewers   int   `json:"raid_viewers,omitempty" dynamodbav:"raid_viewers,omitempty"`
+
+	// Restreams tracks the external platforms this stream is pushed to
+	Restreams []RestreamStatus `json:"restreams,omitempty" dynamodbav:"restreams,omitempty"`
+
+	// DeletedAt is set while the stream is soft deleted, until it's restored or purged
+	DeletedAt *time.Time `json:"deleted_at,omitempty" dynamodbav:"deleted_at,omitempty"`
+
+	// ExpiresAt is when DynamoDB deletes an ended stream (unix seconds), 0 keeps it
+	ExpiresAt int64 `json:"-" dynamodbav:"expires_at,omitempty"`
+
+	// SchemaVersion is the data migration version the item was written with
+	SchemaVersion int `json:"-" dynamodbav:"schema_version"`
+
+	// Health is computed from recent media server reports and never stored
+	Health *StreamHealth `json:"health,omitempty" dynamodbav:"-"`
+
+	// Follow info comes from the follow cache and is only attached to listings
+	FollowerCount      *int64 `json:"follower_count,omitempty" dynamodbav:"-"`
+	IsFollowedByViewer *bool  `json:"is_followed_by_viewer,omitempty" dynamodbav:"-"`
+}
+
+// Deleted reports whether the stream is soft deleted
+func (s *Stream) Deleted() bool {
+	return s.DeletedAt != nil
+}
+
+// RegionAllowed reports whether a viewer in country may play the stream. Viewers whose country
+// is unknown are only turned away by an allow list.
+func (s *Stream) RegionAllowed(country string) bool {
+	for _, blocked := range s.BlockedRegions {
+		if blocked == country {
+			return false
+		}
+	}
+	if len(s.AllowedRegions) == 0 {
+		return true
+	}
+	for _, allowed := range s.AllowedRegions {
+		if allowed == country {
+			return true
+		}
+	}
+	return false
+}
+
+// EffectiveVisibility is the stream's visibility, public when it was never set
+func (s *Stream) EffectiveVisibility() StreamVisibility {
+	if s.Visibility == "" {
+		return StreamVisibilityPublic
+	}
+	return s.Visibility
+}
+
+// Listed reports whether the stream may appear in listings such as the directory
+func (s *Stream) Listed() bool {
+	return s.EffectiveVisibility() == StreamVisibilityPublic
+}
+
+// Public returns a copy of the stream without what only its broadcaster may see: the stream
+// key, the broadcaster's IP, and the playback and recording URLs, which viewers only get from
+// playback authorization
+func (s *Stream) Public() *Stream {
+	public := *s
+	public.StreamKey = ""
+	public.PlaybackID = ""
+	public.RecordingURL = ""
+	public.RecordingError = ""
+	public.VODPlaybackURLs = nil
+	if s.Metadata != nil {
+		public.Metadata = make(map[string]string, len(s.Metadata))
+		for key, value := range s.Metadata {
+			if key != "client_ip" {
+				public.Metadata[key] = value
+			}
+		}
+	}
+	return &public
+}
+
+// PublicStreams returns the public copy of each stream
+func PublicStreams(streams []*Stream) []*Stream {
+	public := make([]*Stream, len(streams))
+	for i, stream := range streams {
+		public[i] = stream.Public()
+	}
+	return public
 }
 
 type StreamMetadata struct {
@@ -37,3 +239,13 @@ type StreamMetadata struct {
 	Codec      string `json:"codec"`
 	ClientIP   string `json:"client_ip"`
 }
+
+// Classification is a category and tags suggested for a stream
+type Classification struct {
+	Category   string    `json:"category" dynamodbav:"category"`
+	Tags       []string  `json:"tags" dynamodbav:"tags"`
+	Confidence float64   `json:"confidence" dynamodbav:"confidence"`
+	Reasons    []string  `json:"reasons,omitempty" dynamodbav:"reasons,omitempty"`
+	Applied    bool      `json:"applied" dynamodbav:"applied"`
+	UpdatedAt  time.Time `json:"updated_at" dynamodbav:"updated_at"`
+}
@@ -0,0 +1,25 @@
+// services/stream-management-service/internal/models/stream_access.go
+package models
+
+import (
+	"time"
+)
+
+// StreamAccess is who may play a private stream besides the broadcaster. It is stored apart
+// from the stream so it never reaches caches or API responses with it.
+type StreamAccess struct {
+	StreamID       string    `json:"stream_id" dynamodbav:"bucket"`
+	PassphraseHash string    `json:"-" dynamodbav:"passphrase_hash,omitempty"`
+	InvitedUserIDs []int64   `json:"invited_user_ids" dynamodbav:"invited_user_ids,omitempty"`
+	UpdatedAt      time.Time `json:"updated_at" dynamodbav:"updated_at"`
+}
+
+// Invited reports whether a viewer was invited to the stream
+func (a *StreamAccess) Invited(userID int64) bool {
+	for _, invited := range a.InvitedUserIDs {
+		if invited == userID {
+			return true
+		}
+	}
+	return false
+}
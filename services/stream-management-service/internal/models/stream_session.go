@@ -0,0 +1,118 @@
+// services/stream-management-service/internal/models/stream_session.go
+package models
+
+import (
+	"time"
+)
+
+// StreamSession is what the media server callbacks keep about a publisher in Redis, from the
+// auth callback until the stream ends. Times are unix seconds so sessions stored before the
+// struct existed still decode.
+type StreamSession struct {
+	UserID         int64                 `json:"user_id"`
+	Username       string                `json:"username,omitempty"`
+	StreamKey      string                `json:"stream_key"`
+	ClientIP       string                `json:"client_ip,omitempty"`
+	ClientID       string                `json:"client_id,omitempty"`
+	AppName        string                `json:"app_name,omitempty"`
+	StartedAt      int64                 `json:"started_at,omitempty"` // when the publisher was authorized
+	Permissions    *PublisherPermissions `json:"permissions,omitempty"`
+	IngestProtocol IngestProtocol        `json:"ingest_protocol,omitempty"`
+	SRTLatencyMs   int                   `json:"srt_latency_ms,omitempty"`
+
+	// PlaybackID is what viewers play the session's stream as, the media server republishes
+	// the stream under it. It is kept when the broadcaster reconnects.
+	PlaybackID string `json:"playback_id,omitempty"`
+
+	// Title and PremiereVODID are set for the relay of a premiere
+	Title         string `json:"title,omitempty"`
+	PremiereVODID string `json:"premiere_vod_id,omitempty"`
+
+	// Set by the publish callback once the stream exists. A segment is the time since the
+	// broadcaster last (re)connected.
+	StreamID         string `json:"stream_id,omitempty"`
+	StreamStartedAt  int64  `json:"stream_started_at,omitempty"`
+	SegmentStartedAt int64  `json:"segment_started_at,omitempty"`
+
+	// LiveSeconds adds up the segments before the last disconnect, DisconnectedAt is set
+	// while the stream waits for its broadcaster to reconnect
+	LiveSeconds    int64 `json:"live_seconds,omitempty"`
+	DisconnectedAt int64 `json:"disconnected_at,omitempty"`
+
+	// Restreams are kept here when the media server asks for them before the stream exists
+	Restreams []RestreamStatus `json:"restreams,omitempty"`
+
+	// When the session was last written by persistence and reconciliation, to know when the
+	// publisher was last seen
+	PersistedAt  int64 `json:"persisted_at,omitempty"`
+	ReconciledAt int64 `json:"reconciled_at,omitempty"`
+}
+
+// PublisherPermissions is what an authorized publisher may do, 0 limits are unlimited
+type PublisherPermissions struct {
+	CanStream          bool `json:"can_stream"`
+	CanRecord          bool `json:"can_record"`
+	MaxBitrate         int  `json:"max_bitrate"` // kbps
+	MaxDurationMinutes int  `json:"max_duration_minutes"`
+}
+
+// Started reports whether the publish callback created the session's stream
+func (s *StreamSession) Started() bool {
+	return s.StreamID != ""
+}
+
+// Reconnecting reports whether the session's stream is waiting for its broadcaster
+func (s *StreamSession) Reconnecting() bool {
+	return s.DisconnectedAt > 0
+}
+
+// IsPremiere reports whether the session is the relay of a premiere
+func (s *StreamSession) IsPremiere() bool {
+	return s.PremiereVODID != ""
+}
+
+// Protocol returns how the publisher connected, sessions from before other protocols were
+// supported are RTMP
+func (s *StreamSession) Protocol() IngestProtocol {
+	if s.IngestProtocol == "" {
+		return IngestProtocolRTMP
+	}
+	return s.IngestProtocol
+}
+
+// AuthorizedAt returns when the auth callback accepted the publisher
+func (s *StreamSession) AuthorizedAt() time.Time {
+	return unixTime(s.StartedAt)
+}
+
+// StreamStartTime returns when the stream started, zero before the publish callback
+func (s *StreamSession) StreamStartTime() time.Time {
+	return unixTime(s.StreamStartedAt)
+}
+
+// DisconnectedTime returns when the broadcaster dropped, zero unless reconnecting
+func (s *StreamSession) DisconnectedTime() time.Time {
+	return unixTime(s.DisconnectedAt)
+}
+
+// LastSeen returns the latest time persistence or reconciliation saw the session's publisher,
+// zero if neither did
+func (s *StreamSession) LastSeen() time.Time {
+	return unixTime(max(s.PersistedAt, s.ReconciledAt))
+}
+
+// ResetStream forgets the session's stream, so the next publish starts a new one
+func (s *StreamSession) ResetStream() {
+	s.StreamID = ""
+	s.StreamStartedAt = 0
+	s.SegmentStartedAt = 0
+	s.LiveSeconds = 0
+	s.DisconnectedAt = 0
+}
+
+func unixTime(seconds int64) time.Time {
+	if seconds <= 0 {
+		return time.Time{}
+	}
+	return time.Unix(seconds, 0)
+}
@@ -0,0 +1,112 @@
+// services/stream-management-service/internal/models/takedown.go
+package models
+
+import (
+	"time"
+)
+
+type TakedownTargetType string
+
+const (
+	TakedownTargetStream TakedownTargetType = "stream"
+	TakedownTargetVOD    TakedownTargetType = "vod"
+)
+
+type TakedownAction string
+
+const (
+	TakedownActionBlock TakedownAction = "block" // playback is blocked entirely
+	TakedownActionMute  TakedownAction = "mute"  // the claimed time ranges of a VOD are muted
+)
+
+type TakedownStatus string
+
+const (
+	TakedownStatusActive         TakedownStatus = "active"
+	TakedownStatusCounterNoticed TakedownStatus = "counter_noticed"
+	TakedownStatusReinstated     TakedownStatus = "reinstated"
+	TakedownStatusUpheld         TakedownStatus = "upheld"
+	TakedownStatusWithdrawn      TakedownStatus = "withdrawn"
+)
+
+type MuteStatus string
+
+const (
+	MuteStatusProcessing MuteStatus = "processing"
+	MuteStatusReady      MuteStatus = "ready"
+	MuteStatusFailed     MuteStatus = "failed"
+)
+
+// takedownTransitions lists the statuses a takedown can move to from each status
+var takedownTransitions = map[TakedownStatus][]TakedownStatus{
+	TakedownStatusActive:         {TakedownStatusCounterNoticed, TakedownStatusWithdrawn},
+	TakedownStatusCounterNoticed: {TakedownStatusReinstated, TakedownStatusUpheld, TakedownStatusWithdrawn},
+}
+
+// Takedown is a copyright claim against a stream or VOD
+type Takedown struct {
+	ID         string             `json:"id" dynamodbav:"id"`
+	TargetType TakedownTargetType `json:"target_type" dynamodbav:"target_type"`
+	TargetID   string             `json:"target_id" dynamodbav:"target_id"`
+	UserID     int64              `json:"user_id" dynamodbav:"user_id"` // creator of the claimed content
+	Action     TakedownAction     `json:"action" dynamodbav:"action"`
+	Status     TakedownStatus     `json:"status" dynamodbav:"status"`
+
+	Claimant        TakedownClaimant `json:"claimant" dynamodbav:"claimant"`
+	Reason          string           `json:"reason" dynamodbav:"reason"`
+	WorkDescription string           `json:"work_description" dynamodbav:"work_description"` // the copyrighted work claimed
+	EvidenceURLs    []string         `json:"evidence_urls,omitempty" dynamodbav:"evidence_urls,omitempty"`
+
+	// Mute takedowns only
+	MutedRanges        []TimeRange `json:"muted_ranges,omitempty" dynamodbav:"muted_ranges,omitempty"`
+	MuteStatus         MuteStatus  `json:"mute_status,omitempty" dynamodbav:"mute_status,omitempty"`
+	MuteError          string      `json:"mute_error,omitempty" dynamodbav:"mute_error,omitempty"`
+	OriginalRenditions []Rendition `json:"-" dynamodbav:"original_renditions,omitempty"` // restored if the takedown is lifted
+
+	CounterNotice *CounterNotice  `json:"counter_notice,omitempty" dynamodbav:"counter_notice,omitempty"`
+	History       []TakedownEvent `json:"history" dynamodbav:"history"`
+	CreatedAt     time.Time       `json:"created_at" dynamodbav:"created_at"`
+	UpdatedAt     time.Time       `json:"updated_at" dynamodbav:"updated_at"`
+}
+
+type TakedownClaimant struct {
+	Name         string `json:"name" dynamodbav:"name"`
+	Email        string `json:"email" dynamodbav:"email"`
+	Organization string `json:"organization,omitempty" dynamodbav:"organization,omitempty"`
+}
+
+// TimeRange is a span of a recording in seconds from its start
+type TimeRange struct {
+	Start int64 `json:"start" dynamodbav:"start"`
+	End   int64 `json:"end" dynamodbav:"end"`
+}
+
+// CounterNotice is the creator's response disputing the claim
+type CounterNotice struct {
+	Name        string    `json:"name" dynamodbav:"name"`
+	Email       string    `json:"email" dynamodbav:"email"`
+	Statement   string    `json:"statement" dynamodbav:"statement"`
+	SubmittedAt time.Time `json:"submitted_at" dynamodbav:"submitted_at"`
+}
+
+// TakedownEvent records a status change of a takedown
+type TakedownEvent struct {
+	Status TakedownStatus `json:"status" dynamodbav:"status"`
+	Note   string         `json:"note,omitempty" dynamodbav:"note,omitempty"`
+	At     time.Time      `json:"at" dynamodbav:"at"`
+}
+
+// CanTransition reports whether the takedown can move to a status
+func (t *Takedown) CanTransition(to TakedownStatus) bool {
+	for _, status := range takedownTransitions[t.Status] {
+		if status == to {
+			return true
+		}
+	}
+	return false
+}
+
+// Lifted reports whether the claimed content is available again
+func (t *Takedown) Lifted() bool {
+	return t.Status == TakedownStatusReinstated || t.Status == TakedownStatusWithdrawn
+}
@@ -0,0 +1,68 @@
+// services/stream-management-service/internal/models/user_data.go
+package models
+
+import (
+	"time"
+)
+
+// UserDataJobKind is what a user data job does with a user's data
+type UserDataJobKind string
+
+const (
+	UserDataJobErase  UserDataJobKind = "erase"
+	UserDataJobExport UserDataJobKind = "export"
+)
+
+type UserDataJobStatus string
+
+const (
+	UserDataJobPending   UserDataJobStatus = "pending"
+	UserDataJobRunning   UserDataJobStatus = "running"
+	UserDataJobCompleted UserDataJobStatus = "completed"
+	UserDataJobFailed    UserDataJobStatus = "failed"
+)
+
+// UserDataJob erases or exports everything this service keeps about a user. The data spans
+// DynamoDB, Redis and S3, so jobs run in the background and record their progress by store.
+type UserDataJob struct {
+	ID          string            `json:"id" dynamodbav:"bucket"`
+	UserID      int64             `json:"user_id" dynamodbav:"user_id"`
+	Kind        UserDataJobKind   `json:"kind" dynamodbav:"kind"`
+	Status      UserDataJobStatus `json:"status" dynamodbav:"status"`
+	Steps       []UserDataStep    `json:"steps" dynamodbav:"steps"`
+	Attempts    int               `json:"attempts" dynamodbav:"attempts"`
+	Error       string            `json:"error,omitempty" dynamodbav:"error,omitempty"`
+	ExportKey   string            `json:"-" dynamodbav:"export_key,omitempty"` // S3 key of a finished export
+	ExportURL   string            `json:"export_url,omitempty" dynamodbav:"-"` // presigned when the job is read
+	CreatedAt   time.Time         `json:"created_at" dynamodbav:"created_at"`
+	UpdatedAt   time.Time         `json:"updated_at" dynamodbav:"updated_at"`
+	CompletedAt *time.Time        `json:"completed_at,omitempty" dynamodbav:"completed_at,omitempty"`
+
+	// ExpiresAt is when DynamoDB deletes the job, in Unix seconds
+	ExpiresAt int64 `json:"-" dynamodbav:"expires_at,omitempty"`
+}
+
+// Done reports whether a job stopped running for good
+func (j *UserDataJob) Done() bool {
+	return j.Status == UserDataJobCompleted || j.Status == UserDataJobFailed
+}
+
+// UserDataStep is the progress of a job in one store
+type UserDataStep struct {
+	Name  string `json:"name" dynamodbav:"name"` // e.g. streams, vods, clips
+	Items int    `json:"items" dynamodbav:"items"`
+	Done  bool   `json:"done" dynamodbav:"done"`
+}
+
+// UserDataExport is the document a user's data is exported as
+type UserDataExport struct {
+	UserID           int64                     `json:"user_id"`
+	GeneratedAt      time.Time                 `json:"generated_at"`
+	Streams          []*Stream                 `json:"streams"`
+	AuditRecords     map[string][]*AuditRecord `json:"audit_records"` // by stream ID
+	VODs             []*VOD                    `json:"vods"`
+	Clips            []*Clip                   `json:"clips"` // of the user's streams and made by the user
+	RestreamTargets  []*RestreamTarget         `json:"restream_targets"`
+	QualityTier      string                    `json:"quality_tier"`
+	FollowedChannels []int64                   `json:"followed_channels"`
+}
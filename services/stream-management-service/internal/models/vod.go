@@ -0,0 +1,104 @@
+// services/stream-management-service/internal/models/vod.go
+package models
+
+import (
+	"time"
+)
+
+type VODVisibility string
+
+const (
+	VODVisibilityPublic   VODVisibility = "public"
+	VODVisibilityUnlisted VODVisibility = "unlisted"
+	VODVisibilityPrivate  VODVisibility = "private"
+)
+
+// VOD is a video-on-demand entry created from a completed stream recording
+type VOD struct {
+	ID         string            `json:"id" dynamodbav:"id"`
+	StreamID   string            `json:"stream_id" dynamodbav:"stream_id"`
+	UserID     int64             `json:"user_id" dynamodbav:"user_id"`
+	Title      string            `json:"title" dynamodbav:"title"`
+	Duration   int64             `json:"duration" dynamodbav:"duration"` // seconds
+	FileSize   int64             `json:"file_size" dynamodbav:"file_size"`
+	Renditions []Rendition       `json:"renditions" dynamodbav:"renditions"`
+	Visibility VODVisibility     `json:"visibility" dynamodbav:"visibility"`
+	Metadata   map[string]string `json:"metadata,omitempty" dynamodbav:"metadata,omitempty"`
+	Chapters   []Chapter         `json:"chapters,omitempty" dynamodbav:"chapters,omitempty"`
+	Markers    []StreamMarker    `json:"markers,omitempty" dynamodbav:"markers,omitempty"` // from the stream, to turn into chapters or highlights
+	CreatedAt  time.Time         `json:"created_at" dynamodbav:"created_at"`
+	UpdatedAt  time.Time         `json:"updated_at" dynamodbav:"updated_at"`
+
+	// Blocked is set while a takedown keeps the VOD from being played. A mute takedown
+	// only blocks it until the muted rendition is ready.
+	Blocked     bool        `json:"blocked,omitempty" dynamodbav:"blocked,omitempty"`
+	TakedownID  string      `json:"takedown_id,omitempty" dynamodbav:"takedown_id,omitempty"`
+	MutedRanges []TimeRange `json:"muted_ranges,omitempty" dynamodbav:"muted_ranges,omitempty"`
+
+	// AudioMatches is the copyrighted music found in the recording, see FingerprintService.
+	// UnmutedRenditions keeps the renditions from before matches were auto-muted.
+	AudioMatches      []AudioMatch `json:"audio_matches,omitempty" dynamodbav:"audio_matches,omitempty"`
+	AudioScannedAt    *time.Time   `json:"audio_scanned_at,omitempty" dynamodbav:"audio_scanned_at,omitempty"`
+	UnmutedRenditions []Rendition  `json:"-" dynamodbav:"unmuted_renditions,omitempty"`
+
+	// Premiere is the latest scheduled replay of the VOD as a live event
+	Premiere *Premiere `json:"premiere,omitempty" dynamodbav:"premiere,omitempty"`
+
+	// HLS is the source rendition packaged for playback, nil until packaging finished
+	HLS *HLSPackage `json:"hls,omitempty" dynamodbav:"hls,omitempty"`
+
+	// DeletedAt is set while the VOD is soft deleted, until it's restored or purged
+	DeletedAt *time.Time `json:"deleted_at,omitempty" dynamodbav:"deleted_at,omitempty"`
+}
+
+// Deleted reports whether the VOD is soft deleted
+func (v *VOD) Deleted() bool {
+	return v.DeletedAt != nil
+}
+
+// HLSPackage is a recording remuxed to HLS, with a master playlist over its renditions
+type HLSPackage struct {
+	ManifestURL string      `json:"manifest_url" dynamodbav:"manifest_url"`
+	Renditions  []Rendition `json:"renditions" dynamodbav:"renditions"` // URLs of the media playlists
+	Source      string      `json:"-" dynamodbav:"source"`              // rendition URL it was packaged from
+	PackagedAt  time.Time   `json:"packaged_at" dynamodbav:"packaged_at"`
+}
+
+type PremiereStatus string
+
+const (
+	PremiereStatusScheduled PremiereStatus = "scheduled"
+	PremiereStatusLive      PremiereStatus = "live"
+	PremiereStatusCompleted PremiereStatus = "completed"
+	PremiereStatusFailed    PremiereStatus = "failed"
+	PremiereStatusCancelled PremiereStatus = "cancelled"
+)
+
+// Premiere plays a VOD into ingest at a set time so it goes out as a normal live stream.
+// The VOD stays out of the catalog until the premiere is over.
+type Premiere struct {
+	Status      PremiereStatus `json:"status" dynamodbav:"status"`
+	Title       string         `json:"title" dynamodbav:"title"`
+	ScheduledAt time.Time      `json:"scheduled_at" dynamodbav:"scheduled_at"`
+	StartedAt   *time.Time     `json:"started_at,omitempty" dynamodbav:"started_at,omitempty"`
+	EndedAt     *time.Time     `json:"ended_at,omitempty" dynamodbav:"ended_at,omitempty"`
+	StreamKey   string         `json:"-" dynamodbav:"stream_key,omitempty"`
+	StreamID    string         `json:"stream_id,omitempty" dynamodbav:"stream_id,omitempty"` // the live stream while it plays
+	Error       string         `json:"error,omitempty" dynamodbav:"error,omitempty"`
+
+	// Visibility is restored on the VOD once the premiere is over
+	Visibility VODVisibility `json:"-" dynamodbav:"visibility"`
+}
+
+// Active reports whether the premiere is still waiting to start or playing
+func (p *Premiere) Active() bool {
+	return p != nil && (p.Status == PremiereStatusScheduled || p.Status == PremiereStatusLive)
+}
+
+// Rendition is a single playable variant of a VOD
+type Rendition struct {
+	Name       string `json:"name" dynamodbav:"name"` // e.g. "source", "720p"
+	URL        string `json:"url" dynamodbav:"url"`
+	Resolution string `json:"resolution,omitempty" dynamodbav:"resolution,omitempty"`
+	Bitrate    int    `json:"bitrate,omitempty" dynamodbav:"bitrate,omitempty"`
+}
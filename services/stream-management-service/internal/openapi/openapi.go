@@ -0,0 +1,254 @@
+// services/stream-management-service/internal/openapi/openapi.go
+package openapi
+
+import (
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// Document is the subset of an OpenAPI 3.0 document this service describes itself with
+type Document struct {
+	OpenAPI    string              `json:"openapi"`
+	Info       Info                `json:"info"`
+	Tags       []Tag               `json:"tags,omitempty"`
+	Paths      map[string]PathItem `json:"paths"`
+	Components Components          `json:"components"`
+}
+
+type Info struct {
+	Title       string `json:"title"`
+	Description string `json:"description,omitempty"`
+	Version     string `json:"version"`
+}
+
+type Tag struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+}
+
+// PathItem holds the operations of a path by lowercase HTTP method
+type PathItem map[string]*Operation
+
+type Operation struct {
+	OperationID string                `json:"operationId,omitempty"`
+	Summary     string                `json:"summary,omitempty"`
+	Tags        []string              `json:"tags,omitempty"`
+	Deprecated  bool                  `json:"deprecated,omitempty"`
+	Parameters  []Parameter           `json:"parameters,omitempty"`
+	RequestBody *RequestBody          `json:"requestBody,omitempty"`
+	Responses   map[string]Response   `json:"responses"`
+	Security    []map[string][]string `json:"security,omitempty"`
+}
+
+type Parameter struct {
+	Name        string  `json:"name"`
+	In          string  `json:"in"` // path, query or header
+	Description string  `json:"description,omitempty"`
+	Required    bool    `json:"required,omitempty"`
+	Schema      *Schema `json:"schema"`
+}
+
+type RequestBody struct {
+	Required bool                 `json:"required,omitempty"`
+	Content  map[string]MediaType `json:"content"`
+}
+
+type MediaType struct {
+	Schema *Schema `json:"schema"`
+}
+
+type Response struct {
+	Description string               `json:"description"`
+	Content     map[string]MediaType `json:"content,omitempty"`
+}
+
+type Components struct {
+	Schemas         map[string]*Schema        `json:"schemas,omitempty"`
+	SecuritySchemes map[string]SecurityScheme `json:"securitySchemes,omitempty"`
+}
+
+type SecurityScheme struct {
+	Type         string `json:"type"` // apiKey or http
+	Name         string `json:"name,omitempty"`
+	In           string `json:"in,omitempty"`
+	Scheme       string `json:"scheme,omitempty"`
+	BearerFormat string `json:"bearerFormat,omitempty"`
+	Description  string `json:"description,omitempty"`
+}
+
+const jsonContentType = "application/json"
+
+// Route describes an operation of the API. Path uses gin's syntax, its parameters are
+// documented as required path parameters.
+type Route struct {
+	Method     string
+	Path       string
+	Summary    string
+	Tag        string
+	Deprecated bool
+	Query      []Parameter
+	Body       any // a value of the request body type, validated by the middleware
+	Response   *Schema
+	Status     int      // of a successful response, 200 when zero
+	Security   []string // names of security schemes, any one of them is enough
+}
+
+// Spec is an OpenAPI document built from the routes added to it, together with the request
+// body schemas the validation middleware checks against
+type Spec struct {
+	doc    Document
+	bodies map[string]*Schema // by method and gin path
+	names  map[reflect.Type]string
+	enums  map[reflect.Type][]any
+	errors map[string]*Schema // error response schema by path prefix
+}
+
+func New(info Info) *Spec {
+	return &Spec{
+		doc: Document{
+			OpenAPI: "3.0.3",
+			Info:    info,
+			Paths:   make(map[string]PathItem),
+			Components: Components{
+				Schemas:         make(map[string]*Schema),
+				SecuritySchemes: make(map[string]SecurityScheme),
+			},
+		},
+		bodies: make(map[string]*Schema),
+		names:  make(map[reflect.Type]string),
+		enums:  make(map[reflect.Type][]any),
+		errors: make(map[string]*Schema),
+	}
+}
+
+// Document returns the OpenAPI document to serve
+func (s *Spec) Document() *Document {
+	return &s.doc
+}
+
+// Tag describes a group of operations
+func (s *Spec) Tag(name, description string) {
+	s.doc.Tags = append(s.doc.Tags, Tag{Name: name, Description: description})
+}
+
+// SecurityScheme declares a way of authenticating routes can refer to by name
+func (s *Spec) SecurityScheme(name string, scheme SecurityScheme) {
+	s.doc.Components.SecuritySchemes[name] = scheme
+}
+
+// Enum lists the values a string type takes, for the types whose values are only known as
+// constants
+func (s *Spec) Enum(value any, values ...any) {
+	s.enums[reflect.TypeOf(value)] = values
+}
+
+// ErrorResponse is the schema of the errors answered by the routes under prefix
+func (s *Spec) ErrorResponse(prefix string, schema *Schema) {
+	s.errors[prefix] = schema
+}
+
+// Add documents a route
+func (s *Spec) Add(route Route) {
+	path, params := openAPIPath(route.Path)
+	op := &Operation{
+		OperationID: operationID(route.Method, route.Path),
+		Summary:     route.Summary,
+		Deprecated:  route.Deprecated,
+		Parameters:  append(params, route.Query...),
+		Responses:   make(map[string]Response),
+	}
+	if route.Tag != "" {
+		op.Tags = []string{route.Tag}
+	}
+	for _, name := range route.Security {
+		op.Security = append(op.Security, map[string][]string{name: {}})
+	}
+
+	if route.Body != nil {
+		schema := s.SchemaOf(route.Body)
+		op.RequestBody = &RequestBody{
+			Required: len(s.resolve(schema).Required) > 0,
+			Content:  map[string]MediaType{jsonContentType: {Schema: schema}},
+		}
+		s.bodies[route.Method+" "+route.Path] = schema
+	}
+
+	status := route.Status
+	if status == 0 {
+		status = http.StatusOK
+	}
+	success := Response{Description: http.StatusText(status)}
+	if route.Response != nil {
+		success.Content = map[string]MediaType{jsonContentType: {Schema: route.Response}}
+	}
+	op.Responses[strconv.Itoa(status)] = success
+	if schema := s.errorSchema(route.Path); schema != nil {
+		op.Responses["default"] = Response{
+			Description: "Error",
+			Content:     map[string]MediaType{jsonContentType: {Schema: schema}},
+		}
+	}
+
+	item, ok := s.doc.Paths[path]
+	if !ok {
+		item = make(PathItem)
+		s.doc.Paths[path] = item
+	}
+	item[strings.ToLower(route.Method)] = op
+}
+
+// Documented reports whether a route was added, for the routes served but missing from the
+// document
+func (s *Spec) Documented(method, path string) bool {
+	openPath, _ := openAPIPath(path)
+	item, ok := s.doc.Paths[openPath]
+	if !ok {
+		return false
+	}
+	_, ok = item[strings.ToLower(method)]
+	return ok
+}
+
+// RequestBody returns the schema a route's request body must match, nil for routes that
+// don't take a body
+func (s *Spec) RequestBody(method, path string) *Schema {
+	return s.bodies[method+" "+path]
+}
+
+func (s *Spec) errorSchema(path string) *Schema {
+	var longest string
+	for prefix := range s.errors {
+		if strings.HasPrefix(path, prefix) && len(prefix) > len(longest) {
+			longest = prefix
+		}
+	}
+	return s.errors[longest]
+}
+
+// openAPIPath turns gin's :param and *param into {param}
+func openAPIPath(path string) (string, []Parameter) {
+	var params []Parameter
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		if strings.HasPrefix(segment, ":") || strings.HasPrefix(segment, "*") {
+			name := segment[1:]
+			segments[i] = "{" + name + "}"
+			params = append(params, Parameter{Name: name, In: "path", Required: true, Schema: &Schema{Type: "string"}})
+		}
+	}
+	return strings.Join(segments, "/"), params
+}
+
+// operationID derives a stable ID from the route, e.g. patchApiV1StreamsId
+func operationID(method, path string) string {
+	var b strings.Builder
+	b.WriteString(strings.ToLower(method))
+	for _, word := range strings.FieldsFunc(path, func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9')
+	}) {
+		b.WriteString(strings.ToUpper(word[:1]) + word[1:])
+	}
+	return b.String()
+}
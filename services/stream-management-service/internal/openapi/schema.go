@@ -0,0 +1,218 @@
+// services/stream-management-service/internal/openapi/schema.go
+package openapi
+
+import (
+	"encoding/json"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Schema is the subset of the OpenAPI schema object derived from Go types
+type Schema struct {
+	Ref                  string             `json:"$ref,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Description          string             `json:"description,omitempty"`
+	Nullable             bool               `json:"nullable,omitempty"`
+	Enum                 []any              `json:"enum,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+	MinLength            *int               `json:"minLength,omitempty"`
+	MaxLength            *int               `json:"maxLength,omitempty"`
+	MinItems             *int               `json:"minItems,omitempty"`
+	MaxItems             *int               `json:"maxItems,omitempty"`
+	Minimum              *float64           `json:"minimum,omitempty"`
+	Maximum              *float64           `json:"maximum,omitempty"`
+}
+
+const refPrefix = "#/components/schemas/"
+
+var (
+	timeType       = reflect.TypeOf(time.Time{})
+	rawMessageType = reflect.TypeOf(json.RawMessage{})
+)
+
+// Object is a schema for an object with the given properties
+func Object(properties map[string]*Schema, required ...string) *Schema {
+	return &Schema{Type: "object", Properties: properties, Required: required}
+}
+
+// ArrayOf is a schema for an array of items
+func ArrayOf(items *Schema) *Schema {
+	return &Schema{Type: "array", Items: items}
+}
+
+// Primitive is a schema for a JSON primitive, e.g. Primitive("integer")
+func Primitive(typ string) *Schema {
+	return &Schema{Type: typ}
+}
+
+// SchemaOf derives a schema from the type of value through its JSON encoding. Named structs
+// are added to the components and referred to. Fields tagged binding:"required" are required
+// and binding:"min=N,max=N" bound the length of strings and arrays or the value of numbers.
+func (s *Spec) SchemaOf(value any) *Schema {
+	return s.schemaOf(reflect.TypeOf(value))
+}
+
+// Component adds a schema to the components under name and returns a reference to it
+func (s *Spec) Component(name string, schema *Schema) *Schema {
+	s.doc.Components.Schemas[name] = schema
+	return &Schema{Ref: refPrefix + name}
+}
+
+func (s *Spec) schemaOf(t reflect.Type) *Schema {
+	if values, ok := s.enums[t]; ok {
+		return &Schema{Type: "string", Enum: values}
+	}
+
+	switch t {
+	case timeType:
+		return &Schema{Type: "string", Format: "date-time"}
+	case rawMessageType:
+		return &Schema{}
+	}
+
+	switch t.Kind() {
+	case reflect.Pointer:
+		schema := s.schemaOf(t.Elem())
+		if schema.Ref == "" {
+			schema.Nullable = true
+		}
+		return schema
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int8, reflect.Int16, reflect.Int32:
+		return &Schema{Type: "integer", Format: "int32"}
+	case reflect.Int, reflect.Int64:
+		return &Schema{Type: "integer", Format: "int64"}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		zero := 0.0
+		return &Schema{Type: "integer", Minimum: &zero}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number"}
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &Schema{Type: "string", Format: "byte"}
+		}
+		return &Schema{Type: "array", Items: s.schemaOf(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: s.schemaOf(t.Elem())}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return s.structSchema(t)
+		}
+		return s.structRef(t)
+	}
+	// Interfaces can hold anything
+	return &Schema{}
+}
+
+// structRef adds a named struct to the components once, a struct referring to itself is
+// referred to before its schema is complete
+func (s *Spec) structRef(t reflect.Type) *Schema {
+	if name, ok := s.names[t]; ok {
+		return &Schema{Ref: refPrefix + name}
+	}
+
+	name := t.Name()
+	if _, taken := s.doc.Components.Schemas[name]; taken {
+		pkg := t.PkgPath()[strings.LastIndex(t.PkgPath(), "/")+1:]
+		name = strings.ToUpper(pkg[:1]) + pkg[1:] + name
+	}
+	s.names[t] = name
+	s.doc.Components.Schemas[name] = &Schema{}
+	*s.doc.Components.Schemas[name] = *s.structSchema(t)
+	return &Schema{Ref: refPrefix + name}
+}
+
+func (s *Spec) structSchema(t reflect.Type) *Schema {
+	schema := &Schema{Type: "object", Properties: make(map[string]*Schema)}
+	s.addFields(schema, t)
+	return schema
+}
+
+func (s *Spec) addFields(schema *Schema, t reflect.Type) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, _, _ := strings.Cut(tag, ",")
+
+		if field.Anonymous && name == "" {
+			embedded := field.Type
+			if embedded.Kind() == reflect.Pointer {
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				s.addFields(schema, embedded)
+				continue
+			}
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		property := s.schemaOf(field.Type)
+		for _, rule := range strings.Split(field.Tag.Get("binding"), ",") {
+			key, value, _ := strings.Cut(rule, "=")
+			switch key {
+			case "required":
+				schema.Required = append(schema.Required, name)
+			case "min", "max":
+				if n, err := strconv.Atoi(value); err == nil {
+					property = bounded(property, key, n)
+				}
+			}
+		}
+		schema.Properties[name] = property
+	}
+}
+
+// bounded applies a binding min or max to the length of strings and arrays or the value of
+// numbers
+func bounded(schema *Schema, key string, n int) *Schema {
+	switch schema.Type {
+	case "string":
+		if key == "min" {
+			schema.MinLength = &n
+		} else {
+			schema.MaxLength = &n
+		}
+	case "array":
+		if key == "min" {
+			schema.MinItems = &n
+		} else {
+			schema.MaxItems = &n
+		}
+	case "integer", "number":
+		value := float64(n)
+		if key == "min" {
+			schema.Minimum = &value
+		} else {
+			schema.Maximum = &value
+		}
+	}
+	return schema
+}
+
+// resolve follows a reference to the schema in the components
+func (s *Spec) resolve(schema *Schema) *Schema {
+	for schema != nil && schema.Ref != "" {
+		schema = s.doc.Components.Schemas[strings.TrimPrefix(schema.Ref, refPrefix)]
+	}
+	if schema == nil {
+		return &Schema{}
+	}
+	return schema
+}
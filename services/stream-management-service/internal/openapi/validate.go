@@ -0,0 +1,186 @@
+// services/stream-management-service/internal/openapi/validate.go
+package openapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Violation is a part of a request body that doesn't match its schema. Field is the path
+// to it, e.g. tags[2], or body for the body as a whole.
+type Violation struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+const bodyField = "body"
+
+// Validate checks a JSON request body against a schema. An empty body only violates a
+// schema with required properties, handlers fill in the defaults. Properties the schema
+// doesn't know are ignored, like the JSON decoder does.
+func (s *Spec) Validate(schema *Schema, body []byte) []Violation {
+	if len(bytes.TrimSpace(body)) == 0 {
+		if len(s.resolve(schema).Required) > 0 {
+			return []Violation{{Field: bodyField, Message: "is required"}}
+		}
+		return nil
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+	var value any
+	if err := decoder.Decode(&value); err != nil {
+		return []Violation{{Field: bodyField, Message: "is not valid JSON: " + err.Error()}}
+	}
+	if _, err := decoder.Token(); err != io.EOF {
+		return []Violation{{Field: bodyField, Message: "has data after the JSON value"}}
+	}
+
+	var violations []Violation
+	s.validate(schema, value, "", &violations)
+	sort.Slice(violations, func(i, j int) bool {
+		return violations[i].Field < violations[j].Field
+	})
+	return violations
+}
+
+func (s *Spec) validate(schema *Schema, value any, field string, violations *[]Violation) {
+	schema = s.resolve(schema)
+	fail := func(format string, args ...any) {
+		name := field
+		if name == "" {
+			name = bodyField
+		}
+		*violations = append(*violations, Violation{Field: name, Message: fmt.Sprintf(format, args...)})
+	}
+
+	// null leaves the field unset, required ones are reported by their object
+	if value == nil || schema.Type == "" {
+		return
+	}
+
+	switch schema.Type {
+	case "string":
+		str, ok := value.(string)
+		if !ok {
+			fail("must be a string")
+			return
+		}
+		length := utf8.RuneCountInString(str)
+		if schema.MinLength != nil && length < *schema.MinLength {
+			fail("must be at least %d characters", *schema.MinLength)
+		}
+		if schema.MaxLength != nil && length > *schema.MaxLength {
+			fail("must be at most %d characters", *schema.MaxLength)
+		}
+		if schema.Format == "date-time" {
+			if _, err := time.Parse(time.RFC3339, str); err != nil {
+				fail("must be an RFC 3339 date-time")
+			}
+		}
+		if len(schema.Enum) > 0 && !inEnum(schema.Enum, str) {
+			fail("must be one of %s", enumList(schema.Enum))
+		}
+
+	case "integer", "number":
+		kind := "a number"
+		if schema.Type == "integer" {
+			kind = "an integer"
+		}
+		number, ok := value.(json.Number)
+		if !ok {
+			fail("must be %s", kind)
+			return
+		}
+		n, err := number.Float64()
+		if err != nil || schema.Type == "integer" && !isInteger(number) {
+			fail("must be %s", kind)
+			return
+		}
+		if schema.Minimum != nil && n < *schema.Minimum {
+			fail("must be at least %s", strconv.FormatFloat(*schema.Minimum, 'f', -1, 64))
+		}
+		if schema.Maximum != nil && n > *schema.Maximum {
+			fail("must be at most %s", strconv.FormatFloat(*schema.Maximum, 'f', -1, 64))
+		}
+
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			fail("must be a boolean")
+		}
+
+	case "array":
+		items, ok := value.([]any)
+		if !ok {
+			fail("must be an array")
+			return
+		}
+		if schema.MinItems != nil && len(items) < *schema.MinItems {
+			fail("must have at least %d items", *schema.MinItems)
+		}
+		if schema.MaxItems != nil && len(items) > *schema.MaxItems {
+			fail("must have at most %d items", *schema.MaxItems)
+		}
+		for i, item := range items {
+			s.validate(schema.Items, item, fmt.Sprintf("%s[%d]", field, i), violations)
+		}
+
+	case "object":
+		object, ok := value.(map[string]any)
+		if !ok {
+			fail("must be an object")
+			return
+		}
+		// Required like gin's binding, strings can't be empty either
+		for _, name := range schema.Required {
+			if object[name] == nil || object[name] == "" {
+				*violations = append(*violations, Violation{Field: join(field, name), Message: "is required"})
+			}
+		}
+		for name, property := range object {
+			if propertySchema, ok := schema.Properties[name]; ok {
+				s.validate(propertySchema, property, join(field, name), violations)
+			} else if schema.AdditionalProperties != nil {
+				s.validate(schema.AdditionalProperties, property, join(field, name), violations)
+			}
+		}
+	}
+}
+
+// isInteger reports whether a number decodes into an int64, written without a fraction or
+// exponent
+func isInteger(number json.Number) bool {
+	_, err := number.Int64()
+	return err == nil
+}
+
+func inEnum(enum []any, value string) bool {
+	for _, allowed := range enum {
+		if fmt.Sprint(allowed) == value {
+			return true
+		}
+	}
+	return false
+}
+
+func enumList(enum []any) string {
+	values := make([]string, len(enum))
+	for i, value := range enum {
+		values[i] = fmt.Sprint(value)
+	}
+	return strings.Join(values, ", ")
+}
+
+func join(field, name string) string {
+	if field == "" {
+		return name
+	}
+	return field + "." + name
+}
@@ -0,0 +1,90 @@
+// services/stream-management-service/internal/repository/apikey.go
+package repository
+
+import (
+	"fmt"
+	"log/slog"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/services/stream-management-service/internal/models"
+)
+
+// SaveAPIKey creates or replaces an API key
+func (r *DynamoDBRepository) SaveAPIKey(key *models.APIKey) error {
+	item, err := dynamodbattribute.MarshalMap(key)
+	if err != nil {
+		return fmt.Errorf("failed to marshal api key: %w", err)
+	}
+
+	_, err = r.client.PutItem(&dynamodb.PutItemInput{
+		TableName: aws.String(r.apiKeyTableName),
+		Item:      item,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to put api key: %w", err)
+	}
+
+	return nil
+}
+
+func (r *DynamoDBRepository) GetAPIKeyByID(keyID string) (*models.APIKey, error) {
+	result, err := r.client.GetItem(&dynamodb.GetItemInput{
+		TableName: aws.String(r.apiKeyTableName),
+		Key: map[string]*dynamodb.AttributeValue{
+			"id": {
+				S: aws.String(keyID),
+			},
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get api key: %w", err)
+	}
+
+	if result.Item == nil {
+		return nil, fmt.Errorf("api key not found")
+	}
+
+	var key models.APIKey
+	if err := dynamodbattribute.UnmarshalMap(result.Item, &key); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal api key: %w", err)
+	}
+
+	return &key, nil
+}
+
+// ListAPIKeys returns a page of API keys
+func (r *DynamoDBRepository) ListAPIKeys(limit int, cursor string) ([]*models.APIKey, string, error) {
+	startKey, err := decodeCursor(cursor)
+	if err != nil {
+		return nil, "", err
+	}
+
+	result, err := r.client.Scan(&dynamodb.ScanInput{
+		TableName:         aws.String(r.apiKeyTableName),
+		Limit:             aws.Int64(int64(limit)),
+		ExclusiveStartKey: startKey,
+	})
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to scan api keys: %w", err)
+	}
+
+	keys := make([]*models.APIKey, 0, len(result.Items))
+	for _, item := range result.Items {
+		var key models.APIKey
+		if err := dynamodbattribute.UnmarshalMap(item, &key); err != nil {
+			slog.Warn("⚠️ Failed to unmarshal api key", "error", err)
+			continue
+		}
+		keys = append(keys, &key)
+	}
+
+	nextCursor, err := encodeCursor(result.LastEvaluatedKey)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return keys, nextCursor, nil
+}
@@ -0,0 +1,117 @@
+// services/stream-management-service/internal/repository/audit.go
+package repository
+
+import (
+	"fmt"
+	"log/slog"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/services/stream-management-service/internal/models"
+)
+
+// AppendAuditRecord adds a record to a stream's audit log. Records are never overwritten.
+func (r *DynamoDBRepository) AppendAuditRecord(record *models.AuditRecord) error {
+	item, err := dynamodbattribute.MarshalMap(record)
+	if err != nil {
+		return fmt.Errorf("failed to marshal audit record: %w", err)
+	}
+
+	_, err = r.client.PutItem(&dynamodb.PutItemInput{
+		TableName:           aws.String(r.auditTableName),
+		Item:                item,
+		ConditionExpression: aws.String("attribute_not_exists(sort_key)"),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to put audit record: %w", err)
+	}
+
+	return nil
+}
+
+// GetAuditRecords returns a page of a stream's audit log, newest first
+func (r *DynamoDBRepository) GetAuditRecords(streamID string, limit int, cursor string) ([]*models.AuditRecord, string, error) {
+	startKey, err := decodeCursor(cursor)
+	if err != nil {
+		return nil, "", err
+	}
+
+	result, err := r.client.Query(&dynamodb.QueryInput{
+		TableName:              aws.String(r.auditTableName),
+		KeyConditionExpression: aws.String("stream_id = :stream_id"),
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+			":stream_id": {
+				S: aws.String(streamID),
+			},
+		},
+		ScanIndexForward:  aws.Bool(false),
+		Limit:             aws.Int64(int64(limit)),
+		ExclusiveStartKey: startKey,
+	})
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to query audit records: %w", err)
+	}
+
+	records := make([]*models.AuditRecord, 0, len(result.Items))
+	for _, item := range result.Items {
+		var record models.AuditRecord
+		if err := dynamodbattribute.UnmarshalMap(item, &record); err != nil {
+			slog.Warn("⚠️ Failed to unmarshal audit record", "error", err)
+			continue
+		}
+		records = append(records, &record)
+	}
+
+	nextCursor, err := encodeCursor(result.LastEvaluatedKey)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return records, nextCursor, nil
+}
+
+// DeleteAuditRecords removes a stream's whole audit log, returning how many records it held
+func (r *DynamoDBRepository) DeleteAuditRecords(streamID string) (int, error) {
+	var keys []map[string]*dynamodb.AttributeValue
+	err := r.client.QueryPages(&dynamodb.QueryInput{
+		TableName:              aws.String(r.auditTableName),
+		KeyConditionExpression: aws.String("stream_id = :stream_id"),
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+			":stream_id": {
+				S: aws.String(streamID),
+			},
+		},
+		ProjectionExpression: aws.String("stream_id, sort_key"),
+	}, func(page *dynamodb.QueryOutput, lastPage bool) bool {
+		keys = append(keys, page.Items...)
+		return true
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to query audit records: %w", err)
+	}
+
+	// BatchWriteItem takes at most 25 requests
+	for start := 0; start < len(keys); start += 25 {
+		end := min(start+25, len(keys))
+		requests := make([]*dynamodb.WriteRequest, 0, end-start)
+		for _, key := range keys[start:end] {
+			requests = append(requests, &dynamodb.WriteRequest{
+				DeleteRequest: &dynamodb.DeleteRequest{Key: key},
+			})
+		}
+
+		pending := map[string][]*dynamodb.WriteRequest{r.auditTableName: requests}
+		for len(pending) > 0 {
+			result, err := r.client.BatchWriteItem(&dynamodb.BatchWriteItemInput{RequestItems: pending})
+			if err != nil {
+				return 0, fmt.Errorf("failed to delete audit records: %w", err)
+			}
+			// DynamoDB returns the requests it didn't get to when throttled
+			pending = result.UnprocessedItems
+		}
+	}
+
+	return len(keys), nil
+}
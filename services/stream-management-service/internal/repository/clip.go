@@ -0,0 +1,144 @@
+// services/stream-management-service/internal/repository/clip.go
+package repository
+
+import (
+	"fmt"
+	"log/slog"
+	"strconv"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/services/stream-management-service/internal/models"
+)
+
+// SaveClip creates or replaces a clip
+func (r *DynamoDBRepository) SaveClip(clip *models.Clip) error {
+	item, err := dynamodbattribute.MarshalMap(clip)
+	if err != nil {
+		return fmt.Errorf("failed to marshal clip: %w", err)
+	}
+
+	_, err = r.client.PutItem(&dynamodb.PutItemInput{
+		TableName: aws.String(r.clipTableName),
+		Item:      item,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to put clip: %w", err)
+	}
+
+	return nil
+}
+
+func (r *DynamoDBRepository) GetClipByID(clipID string) (*models.Clip, error) {
+	result, err := r.client.GetItem(&dynamodb.GetItemInput{
+		TableName: aws.String(r.clipTableName),
+		Key: map[string]*dynamodb.AttributeValue{
+			"id": {
+				S: aws.String(clipID),
+			},
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get clip: %w", err)
+	}
+
+	if result.Item == nil {
+		return nil, fmt.Errorf("clip not found")
+	}
+
+	var clip models.Clip
+	if err := dynamodbattribute.UnmarshalMap(result.Item, &clip); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal clip: %w", err)
+	}
+
+	return &clip, nil
+}
+
+// GetClipsByStream returns a page of a stream's clips, newest first
+func (r *DynamoDBRepository) GetClipsByStream(streamID string, limit int, cursor string) ([]*models.Clip, string, error) {
+	startKey, err := decodeCursor(cursor)
+	if err != nil {
+		return nil, "", err
+	}
+
+	result, err := r.client.Query(&dynamodb.QueryInput{
+		TableName:              aws.String(r.clipTableName),
+		IndexName:              aws.String("stream-id-created-index"),
+		KeyConditionExpression: aws.String("stream_id = :stream_id"),
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+			":stream_id": {
+				S: aws.String(streamID),
+			},
+		},
+		ScanIndexForward:  aws.Bool(false),
+		Limit:             aws.Int64(int64(limit)),
+		ExclusiveStartKey: startKey,
+	})
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to query clips: %w", err)
+	}
+
+	clips := make([]*models.Clip, 0, len(result.Items))
+	for _, item := range result.Items {
+		var clip models.Clip
+		if err := dynamodbattribute.UnmarshalMap(item, &clip); err != nil {
+			slog.Warn("⚠️ Failed to unmarshal clip", "error", err)
+			continue
+		}
+		clips = append(clips, &clip)
+	}
+
+	nextCursor, err := encodeCursor(result.LastEvaluatedKey)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return clips, nextCursor, nil
+}
+
+// GetClipsByCreator returns every clip a user made. Clips aren't indexed by creator, so the
+// table is scanned; only meant for rare jobs like exporting a user's data.
+func (r *DynamoDBRepository) GetClipsByCreator(userID int64) ([]*models.Clip, error) {
+	var clips []*models.Clip
+	err := r.client.ScanPages(&dynamodb.ScanInput{
+		TableName:        aws.String(r.clipTableName),
+		FilterExpression: aws.String("created_by = :user_id"),
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+			":user_id": {
+				N: aws.String(strconv.FormatInt(userID, 10)),
+			},
+		},
+	}, func(page *dynamodb.ScanOutput, lastPage bool) bool {
+		for _, item := range page.Items {
+			var clip models.Clip
+			if err := dynamodbattribute.UnmarshalMap(item, &clip); err != nil {
+				slog.Warn("⚠️ Failed to unmarshal clip", "error", err)
+				continue
+			}
+			clips = append(clips, &clip)
+		}
+		return true
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan clips by creator: %w", err)
+	}
+
+	return clips, nil
+}
+
+// DeleteClip removes a clip from the table. Its file in S3 is deleted separately.
+func (r *DynamoDBRepository) DeleteClip(clipID string) error {
+	_, err := r.client.DeleteItem(&dynamodb.DeleteItemInput{
+		TableName: aws.String(r.clipTableName),
+		Key: map[string]*dynamodb.AttributeValue{
+			"id": {S: aws.String(clipID)},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to delete clip: %w", err)
+	}
+
+	return nil
+}
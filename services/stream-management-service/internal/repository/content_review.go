@@ -0,0 +1,98 @@
+// services/stream-management-service/internal/repository/content_review.go
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/services/stream-management-service/internal/models"
+)
+
+// contentReviewPartition is the stats table partition flagged content is stored in, one item
+// per review, expired by the table's TTL once reviewed
+const contentReviewPartition = "content_review"
+
+var ErrContentReviewNotFound = errors.New("content review not found")
+
+// SaveContentReview creates or replaces a content review
+func (r *DynamoDBRepository) SaveContentReview(review *models.ContentReview) error {
+	item, err := dynamodbattribute.MarshalMap(review)
+	if err != nil {
+		return fmt.Errorf("failed to marshal content review: %w", err)
+	}
+	item["granularity"] = &dynamodb.AttributeValue{S: aws.String(contentReviewPartition)}
+
+	_, err = r.client.PutItem(&dynamodb.PutItemInput{
+		TableName: aws.String(r.statsTableName),
+		Item:      item,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to put content review: %w", err)
+	}
+
+	return nil
+}
+
+// GetContentReview returns a content review, or ErrContentReviewNotFound
+func (r *DynamoDBRepository) GetContentReview(reviewID string) (*models.ContentReview, error) {
+	result, err := r.client.GetItem(&dynamodb.GetItemInput{
+		TableName: aws.String(r.statsTableName),
+		Key: map[string]*dynamodb.AttributeValue{
+			"granularity": {S: aws.String(contentReviewPartition)},
+			"bucket":      {S: aws.String(reviewID)},
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get content review: %w", err)
+	}
+	if result.Item == nil {
+		return nil, ErrContentReviewNotFound
+	}
+
+	var review models.ContentReview
+	if err := dynamodbattribute.UnmarshalMap(result.Item, &review); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal content review: %w", err)
+	}
+
+	return &review, nil
+}
+
+// GetContentReviews returns the content reviews in a status, or all of them when status is
+// empty
+func (r *DynamoDBRepository) GetContentReviews(status models.ContentReviewStatus) ([]*models.ContentReview, error) {
+	input := &dynamodb.QueryInput{
+		TableName:              aws.String(r.statsTableName),
+		KeyConditionExpression: aws.String("granularity = :partition"),
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+			":partition": {S: aws.String(contentReviewPartition)},
+		},
+	}
+	if status != "" {
+		input.FilterExpression = aws.String("#status = :status")
+		input.ExpressionAttributeNames = map[string]*string{"#status": aws.String("status")}
+		input.ExpressionAttributeValues[":status"] = &dynamodb.AttributeValue{S: aws.String(string(status))}
+	}
+
+	var reviews []*models.ContentReview
+	err := r.client.QueryPages(input, func(page *dynamodb.QueryOutput, lastPage bool) bool {
+		for _, item := range page.Items {
+			var review models.ContentReview
+			if err := dynamodbattribute.UnmarshalMap(item, &review); err != nil {
+				slog.Warn("⚠️ Failed to unmarshal content review", "error", err)
+				continue
+			}
+			reviews = append(reviews, &review)
+		}
+		return true
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to query content reviews: %w", err)
+	}
+
+	return reviews, nil
+}
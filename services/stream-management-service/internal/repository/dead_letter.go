@@ -0,0 +1,130 @@
+// services/stream-management-service/internal/repository/dead_letter.go
+package repository
+
+import (
+	"fmt"
+	"log/slog"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/services/stream-management-service/internal/models"
+)
+
+func (r *DynamoDBRepository) SaveDeadLetter(letter *models.DeadLetter) error {
+	item, err := dynamodbattribute.MarshalMap(letter)
+	if err != nil {
+		return fmt.Errorf("failed to marshal dead letter: %w", err)
+	}
+
+	_, err = r.client.PutItem(&dynamodb.PutItemInput{
+		TableName: aws.String(r.deadLetterTable),
+		Item:      item,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to put dead letter: %w", err)
+	}
+
+	return nil
+}
+
+func (r *DynamoDBRepository) GetDeadLetter(letterID string) (*models.DeadLetter, error) {
+	result, err := r.client.GetItem(&dynamodb.GetItemInput{
+		TableName: aws.String(r.deadLetterTable),
+		Key: map[string]*dynamodb.AttributeValue{
+			"id": {
+				S: aws.String(letterID),
+			},
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get dead letter: %w", err)
+	}
+
+	if result.Item == nil {
+		return nil, fmt.Errorf("dead letter not found")
+	}
+
+	var letter models.DeadLetter
+	if err := dynamodbattribute.UnmarshalMap(result.Item, &letter); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal dead letter: %w", err)
+	}
+
+	return &letter, nil
+}
+
+// ListDeadLetters returns a page of dead letters. A consumer's are listed newest first, an
+// empty consumer returns every consumer's in no particular order.
+func (r *DynamoDBRepository) ListDeadLetters(consumer string, limit int, cursor string) ([]*models.DeadLetter, string, error) {
+	startKey, err := decodeCursor(cursor)
+	if err != nil {
+		return nil, "", err
+	}
+
+	var items []map[string]*dynamodb.AttributeValue
+	var lastKey map[string]*dynamodb.AttributeValue
+
+	if consumer != "" {
+		result, err := r.client.Query(&dynamodb.QueryInput{
+			TableName:              aws.String(r.deadLetterTable),
+			IndexName:              aws.String("consumer-failed-index"),
+			KeyConditionExpression: aws.String("consumer = :consumer"),
+			ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+				":consumer": {
+					S: aws.String(consumer),
+				},
+			},
+			ScanIndexForward:  aws.Bool(false),
+			Limit:             aws.Int64(int64(limit)),
+			ExclusiveStartKey: startKey,
+		})
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to query dead letters: %w", err)
+		}
+		items, lastKey = result.Items, result.LastEvaluatedKey
+	} else {
+		result, err := r.client.Scan(&dynamodb.ScanInput{
+			TableName:         aws.String(r.deadLetterTable),
+			Limit:             aws.Int64(int64(limit)),
+			ExclusiveStartKey: startKey,
+		})
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to scan dead letters: %w", err)
+		}
+		items, lastKey = result.Items, result.LastEvaluatedKey
+	}
+
+	letters := make([]*models.DeadLetter, 0, len(items))
+	for _, item := range items {
+		var letter models.DeadLetter
+		if err := dynamodbattribute.UnmarshalMap(item, &letter); err != nil {
+			slog.Warn("⚠️ Failed to unmarshal dead letter", "error", err)
+			continue
+		}
+		letters = append(letters, &letter)
+	}
+
+	nextCursor, err := encodeCursor(lastKey)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return letters, nextCursor, nil
+}
+
+func (r *DynamoDBRepository) DeleteDeadLetter(letterID string) error {
+	_, err := r.client.DeleteItem(&dynamodb.DeleteItemInput{
+		TableName: aws.String(r.deadLetterTable),
+		Key: map[string]*dynamodb.AttributeValue{
+			"id": {
+				S: aws.String(letterID),
+			},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to delete dead letter: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,14 @@
+// services/stream-management-service/internal/repository/dedupe.go
+package repository
+
+import (
+	"time"
+
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/shared/go/pkg/events"
+)
+
+// EventDeduper remembers in the dedupe table which events consumer handled, claims of a
+// replica that stopped handling an event are taken over after lease
+func (r *DynamoDBRepository) EventDeduper(consumer string, lease, retention time.Duration) *events.DynamoDBDeduper {
+	return events.NewDynamoDBDeduper(r.client, r.dedupeTableName, consumer, lease, retention)
+}
@@ -2,14 +2,15 @@
 package repository
 
 import (
+	"context"
 	"fmt"
-	"log"
-	_ "os"
-	_ "time"
+	"log/slog"
+	"os"
+	"strconv"
+	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/credentials"
-	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/dynamodb"
 	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
 	_ "github.com/aws/aws-sdk-go/service/kinesis"
@@ -20,164 +21,103 @@ import (
 	_ "google.golang.org/grpc/credentials/insecure"
 
 	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/services/stream-management-service/internal/config"
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/services/stream-management-service/internal/datamigration"
 	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/services/stream-management-service/internal/models"
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/services/stream-management-service/pkg/tracing"
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/shared/go/pkg/globaltables"
+)
+
+const (
+	batchGetLimit   = 100 // keys per BatchGetItem request
+	batchGetRetries = 5   // attempts at unprocessed keys before giving up
 )
 
 type DynamoDBRepository struct {
-	client    *dynamodb.DynamoDB
-	tableName string
+	client            *globaltables.Client
+	tableName         string
+	vodTableName      string
+	clipTableName     string
+	restreamTableName string
+	apiKeyTableName   string
+	takedownTableName string
+	streamKeyBanTable string
+	statsTableName    string
+	auditTableName    string
+	outboxTableName   string
+	deadLetterTable   string
+	dedupeTableName   string
+
+	streamMigrations *datamigration.Registry
 }
 
 func NewDynamoDBRepository(cfg *config.Config) *DynamoDBRepository {
-	// Configure AWS session for local development
-	var sess *session.Session
-	var err error
-
-	if cfg.Environment == "development" || cfg.DynamoDBEndpoint != "" {
-		// Local DynamoDB configuration
-		log.Printf("🔧 Configuring for local DynamoDB at: %s", cfg.DynamoDBEndpoint)
-
-		sess, err = session.NewSession(&aws.Config{
-			Region:      aws.String(cfg.AWSRegion),
-			Endpoint:    aws.String(cfg.DynamoDBEndpoint),
-			Credentials: credentials.NewStaticCredentials("dummy", "dummy", ""),
-		})
-	} else {
-		// Production AWS configuration
-		sess, err = session.NewSession(&aws.Config{
-			Region: aws.String(cfg.AWSRegion),
-		})
-	}
-
+	dynamoClient, err := NewDynamoDBClient(cfg)
 	if err != nil {
-		log.Fatalf("❌ Failed to create AWS session: %v", err)
-	}
-
-	dynamoClient := dynamodb.New(sess)
-
-	// Create table if it doesn't exist (for local development)
-	if cfg.Environment == "development" {
-		if err := createTableIfNotExists(dynamoClient, cfg.DynamoDBTableName); err != nil {
-			log.Printf("⚠️ Warning: Could not create/verify table: %v", err)
-		} else {
-			log.Printf("✅ DynamoDB table '%s' ready", cfg.DynamoDBTableName)
-		}
+		slog.Error("❌ Failed to create AWS session", "error", err)
+		os.Exit(1)
 	}
 
 	return &DynamoDBRepository{
-		client:    dynamoClient,
-		tableName: cfg.DynamoDBTableName,
+		client:            dynamoClient,
+		tableName:         cfg.DynamoDBTableName,
+		vodTableName:      cfg.VODTableName,
+		clipTableName:     cfg.ClipTableName,
+		restreamTableName: cfg.RestreamTableName,
+		apiKeyTableName:   cfg.APIKeyTableName,
+		takedownTableName: cfg.TakedownTableName,
+		streamKeyBanTable: cfg.StreamKeyBanTable,
+		statsTableName:    cfg.StatsTableName,
+		auditTableName:    cfg.AuditTableName,
+		outboxTableName:   cfg.OutboxTableName,
+		deadLetterTable:   cfg.DeadLetterTable,
+		dedupeTableName:   cfg.DedupeTableName,
+
+		streamMigrations: datamigration.StreamMigrations(cfg.DynamoDBTableName),
 	}
 }
 
-// createTableIfNotExists creates the streams table if it doesn't exist
-func createTableIfNotExists(client *dynamodb.DynamoDB, tableName string) error {
-	// Check if table exists
-	_, err := client.DescribeTable(&dynamodb.DescribeTableInput{
-		TableName: aws.String(tableName),
-	})
-
-	if err == nil {
-		// Table exists
-		log.Printf("📋 Table '%s' already exists", tableName)
-		return nil
+// NewDynamoDBClient creates a traced DynamoDB client, pointed at the local endpoint in development.
+// In AWS it reads from the replica in AWSRegion first and writes to DynamoDBWriteRegion.
+func NewDynamoDBClient(cfg *config.Config) (*globaltables.Client, error) {
+	awsConfig := &aws.Config{
+		Region: aws.String(cfg.AWSRegion),
 	}
 
-	// Table doesn't exist, create it
-	log.Printf("🔨 Creating DynamoDB table: %s", tableName)
+	if cfg.Environment == "development" || cfg.DynamoDBEndpoint != "" {
+		// Local DynamoDB configuration
+		slog.Info("🔧 Configuring for local DynamoDB", "endpoint", cfg.DynamoDBEndpoint)
 
-	input := &dynamodb.CreateTableInput{
-		TableName: aws.String(tableName),
-		KeySchema: []*dynamodb.KeySchemaElement{
-			{
-				AttributeName: aws.String("id"),
-				KeyType:       aws.String("HASH"), // Partition key
-			},
-		},
-		AttributeDefinitions: []*dynamodb.AttributeDefinition{
-			{
-				AttributeName: aws.String("id"),
-				AttributeType: aws.String("S"), // String
-			},
-			{
-				AttributeName: aws.String("stream_key"),
-				AttributeType: aws.String("S"), // String
-			},
-			{
-				AttributeName: aws.String("status"),
-				AttributeType: aws.String("S"), // String
-			},
-			{
-				AttributeName: aws.String("user_id"),
-				AttributeType: aws.String("N"), // Number
-			},
-		},
-		BillingMode: aws.String("PAY_PER_REQUEST"), // On-demand pricing
-		GlobalSecondaryIndexes: []*dynamodb.GlobalSecondaryIndex{
-			// GSI for querying by stream_key
-			{
-				IndexName: aws.String("stream-key-index"),
-				KeySchema: []*dynamodb.KeySchemaElement{
-					{
-						AttributeName: aws.String("stream_key"),
-						KeyType:       aws.String("HASH"),
-					},
-				},
-				Projection: &dynamodb.Projection{
-					ProjectionType: aws.String("ALL"),
-				},
-			},
-			// GSI for querying by status
-			{
-				IndexName: aws.String("status-index"),
-				KeySchema: []*dynamodb.KeySchemaElement{
-					{
-						AttributeName: aws.String("status"),
-						KeyType:       aws.String("HASH"),
-					},
-				},
-				Projection: &dynamodb.Projection{
-					ProjectionType: aws.String("ALL"),
-				},
-			},
-			// GSI for querying by user_id
-			{
-				IndexName: aws.String("user-id-index"),
-				KeySchema: []*dynamodb.KeySchemaElement{
-					{
-						AttributeName: aws.String("user_id"),
-						KeyType:       aws.String("HASH"),
-					},
-				},
-				Projection: &dynamodb.Projection{
-					ProjectionType: aws.String("ALL"),
-				},
-			},
-		},
+		awsConfig.Endpoint = aws.String(cfg.DynamoDBEndpoint)
+		awsConfig.Credentials = credentials.NewStaticCredentials("dummy", "dummy", "")
 	}
 
-	result, err := client.CreateTable(input)
+	dynamoClient, err := globaltables.New(globaltables.Config{
+		LocalRegion: cfg.AWSRegion,
+		Regions:     cfg.DynamoDBRegions,
+		WriteRegion: cfg.DynamoDBWriteRegion,
+	}, awsConfig)
 	if err != nil {
-		return fmt.Errorf("failed to create table: %w", err)
+		return nil, fmt.Errorf("failed to create DynamoDB client: %w", err)
+	}
+	dynamoClient.Instrument(tracing.InstrumentAWS)
+	if injector := cfg.Faults(); injector != nil {
+		dynamoClient.Instrument(injector.InstrumentAWS)
 	}
 
-	log.Printf("✅ Table created successfully: %s", *result.TableDescription.TableName)
-
-	// Wait for table to be active
-	log.Printf("⏳ Waiting for table to become active...")
-	err = client.WaitUntilTableExists(&dynamodb.DescribeTableInput{
-		TableName: aws.String(tableName),
-	})
-
-	if err != nil {
-		return fmt.Errorf("failed to wait for table: %w", err)
+	if status := dynamoClient.Status(); len(status.ReadRegions) > 1 {
+		slog.Info("🌍 DynamoDB global tables", "write_region", status.WriteRegion, "read_regions", status.ReadRegions)
 	}
+	return dynamoClient, nil
+}
 
-	log.Printf("🎉 Table '%s' is now active and ready!", tableName)
-	return nil
+// RegionStatus tells which regions the repository reads from and writes to
+func (r *DynamoDBRepository) RegionStatus() globaltables.Status {
+	return r.client.Status()
 }
 
-func (r *DynamoDBRepository) CreateStream(stream *models.Stream) error {
+func (r *DynamoDBRepository) CreateStream(ctx context.Context, stream *models.Stream) error {
+	stream.SchemaVersion = r.streamMigrations.Latest()
+
 	item, err := dynamodbattribute.MarshalMap(stream)
 	if err != nil {
 		return fmt.Errorf("failed to marshal stream: %w", err)
@@ -188,12 +128,12 @@ func (r *DynamoDBRepository) CreateStream(stream *models.Stream) error {
 		Item:      item,
 	}
 
-	_, err = r.client.PutItem(input)
+	_, err = r.client.PutItemWithContext(ctx, input)
 	if err != nil {
 		return fmt.Errorf("failed to put item: %w", err)
 	}
 
-	log.Printf("✅ Stream created in DynamoDB: %s", stream.ID)
+	slog.DebugContext(ctx, "✅ Stream created in DynamoDB", "stream_id", stream.ID)
 	return nil
 }
 
@@ -217,7 +157,7 @@ func (r *DynamoDBRepository) GetStreamByID(streamID string) (*models.Stream, err
 	}
 
 	var stream models.Stream
-	err = dynamodbattribute.UnmarshalMap(result.Item, &stream)
+	err = r.unmarshalStream(result.Item, &stream)
 	if err != nil {
 		return nil, fmt.Errorf("failed to unmarshal stream: %w", err)
 	}
@@ -225,6 +165,57 @@ func (r *DynamoDBRepository) GetStreamByID(streamID string) (*models.Stream, err
 	return &stream, nil
 }
 
+// GetStreamsByIDs reads several streams with BatchGetItem. Streams that don't exist are left
+// out of the result.
+func (r *DynamoDBRepository) GetStreamsByIDs(streamIDs []string) ([]*models.Stream, error) {
+	var streams []*models.Stream
+
+	for start := 0; start < len(streamIDs); start += batchGetLimit {
+		end := start + batchGetLimit
+		if end > len(streamIDs) {
+			end = len(streamIDs)
+		}
+
+		keys := make([]map[string]*dynamodb.AttributeValue, 0, end-start)
+		for _, id := range streamIDs[start:end] {
+			keys = append(keys, map[string]*dynamodb.AttributeValue{
+				"id": {S: aws.String(id)},
+			})
+		}
+
+		requests := map[string]*dynamodb.KeysAndAttributes{
+			r.tableName: {Keys: keys},
+		}
+		for attempt := 0; len(requests) > 0; attempt++ {
+			if attempt > 0 {
+				if attempt > batchGetRetries {
+					return nil, fmt.Errorf("failed to get %d streams: throttled", len(requests[r.tableName].Keys))
+				}
+				time.Sleep(time.Duration(attempt*attempt) * 50 * time.Millisecond)
+			}
+
+			result, err := r.client.BatchGetItem(&dynamodb.BatchGetItemInput{RequestItems: requests})
+			if err != nil {
+				return nil, fmt.Errorf("failed to batch get streams: %w", err)
+			}
+
+			for _, item := range result.Responses[r.tableName] {
+				var stream models.Stream
+				if err := r.unmarshalStream(item, &stream); err != nil {
+					slog.Warn("⚠️ Failed to unmarshal stream", "error", err)
+					continue
+				}
+				streams = append(streams, &stream)
+			}
+
+			// DynamoDB returns the keys it didn't get to when throttled or over 16MB
+			requests = result.UnprocessedKeys
+		}
+	}
+
+	return streams, nil
+}
+
 func (r *DynamoDBRepository) GetStreamByStreamKey(streamKey string) (*models.Stream, error) {
 	// Use GSI for better performance
 	input := &dynamodb.QueryInput{
@@ -242,7 +233,7 @@ func (r *DynamoDBRepository) GetStreamByStreamKey(streamKey string) (*models.Str
 	result, err := r.client.Query(input)
 	if err != nil {
 		// Fallback to scan if GSI doesn't exist yet
-		log.Printf("⚠️ GSI query failed, falling back to scan: %v", err)
+		slog.Warn("⚠️ GSI query failed, falling back to scan", "error", err)
 		return r.getStreamByStreamKeyScan(streamKey)
 	}
 
@@ -251,7 +242,7 @@ func (r *DynamoDBRepository) GetStreamByStreamKey(streamKey string) (*models.Str
 	}
 
 	var stream models.Stream
-	err = dynamodbattribute.UnmarshalMap(result.Items[0], &stream)
+	err = r.unmarshalStream(result.Items[0], &stream)
 	if err != nil {
 		return nil, fmt.Errorf("failed to unmarshal stream: %w", err)
 	}
@@ -259,6 +250,39 @@ func (r *DynamoDBRepository) GetStreamByStreamKey(streamKey string) (*models.Str
 	return &stream, nil
 }
 
+// GetStreamsByRecordingStatus returns the streams whose recording is in a status
+func (r *DynamoDBRepository) GetStreamsByRecordingStatus(status models.RecordingStatus, deleted models.DeletedFilter) ([]*models.Stream, error) {
+	input := &dynamodb.QueryInput{
+		TableName:              aws.String(r.tableName),
+		IndexName:              aws.String("recording-status-index"),
+		KeyConditionExpression: aws.String("recording_status = :status"),
+		FilterExpression:       deletedFilterExpression(deleted, nil),
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+			":status": {
+				S: aws.String(string(status)),
+			},
+		},
+	}
+
+	var streams []*models.Stream
+	err := r.client.QueryPages(input, func(page *dynamodb.QueryOutput, lastPage bool) bool {
+		for _, item := range page.Items {
+			var stream models.Stream
+			if err := r.unmarshalStream(item, &stream); err != nil {
+				slog.Warn("⚠️ Failed to unmarshal stream", "error", err)
+				continue
+			}
+			streams = append(streams, &stream)
+		}
+		return true
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to query streams by recording status: %w", err)
+	}
+
+	return streams, nil
+}
+
 // Fallback scan method for when GSI is not available
 func (r *DynamoDBRepository) getStreamByStreamKeyScan(streamKey string) (*models.Stream, error) {
 	input := &dynamodb.ScanInput{
@@ -281,7 +305,7 @@ func (r *DynamoDBRepository) getStreamByStreamKeyScan(streamKey string) (*models
 	}
 
 	var stream models.Stream
-	err = dynamodbattribute.UnmarshalMap(result.Items[0], &stream)
+	err = r.unmarshalStream(result.Items[0], &stream)
 	if err != nil {
 		return nil, fmt.Errorf("failed to unmarshal stream: %w", err)
 	}
@@ -289,12 +313,13 @@ func (r *DynamoDBRepository) getStreamByStreamKeyScan(streamKey string) (*models
 	return &stream, nil
 }
 
-func (r *DynamoDBRepository) GetStreamsByStatus(status models.StreamStatus) ([]*models.Stream, error) {
+func (r *DynamoDBRepository) GetStreamsByStatus(status models.StreamStatus, deleted models.DeletedFilter) ([]*models.Stream, error) {
 	// Use GSI for better performance
 	input := &dynamodb.QueryInput{
 		TableName:              aws.String(r.tableName),
 		IndexName:              aws.String("status-index"),
 		KeyConditionExpression: aws.String("#status = :status"),
+		FilterExpression:       deletedFilterExpression(deleted, nil),
 		ExpressionAttributeNames: map[string]*string{
 			"#status": aws.String("status"),
 		},
@@ -308,16 +333,48 @@ func (r *DynamoDBRepository) GetStreamsByStatus(status models.StreamStatus) ([]*
 	result, err := r.client.Query(input)
 	if err != nil {
 		// Fallback to scan if GSI doesn't exist yet
-		log.Printf("⚠️ GSI query failed, falling back to scan: %v", err)
-		return r.getStreamsByStatusScan(status)
+		slog.Warn("⚠️ GSI query failed, falling back to scan", "error", err)
+		return r.getStreamsByStatusScan(status, deleted)
 	}
 
 	var streams []*models.Stream
 	for _, item := range result.Items {
 		var stream models.Stream
-		err = dynamodbattribute.UnmarshalMap(item, &stream)
+		err = r.unmarshalStream(item, &stream)
 		if err != nil {
-			log.Printf("⚠️ Failed to unmarshal stream: %v", err)
+			slog.Warn("⚠️ Failed to unmarshal stream", "error", err)
+			continue
+		}
+		streams = append(streams, &stream)
+	}
+
+	return streams, nil
+}
+
+// GetStreamsByUser returns up to limit streams of a user. The limit applies before deleted
+// streams are filtered, so fewer may come back.
+func (r *DynamoDBRepository) GetStreamsByUser(userID int64, limit int, deleted models.DeletedFilter) ([]*models.Stream, error) {
+	result, err := r.client.Query(&dynamodb.QueryInput{
+		TableName:              aws.String(r.tableName),
+		IndexName:              aws.String("user-id-index"),
+		KeyConditionExpression: aws.String("user_id = :user_id"),
+		FilterExpression:       deletedFilterExpression(deleted, nil),
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+			":user_id": {
+				N: aws.String(strconv.FormatInt(userID, 10)),
+			},
+		},
+		Limit: aws.Int64(int64(limit)),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to query streams by user: %w", err)
+	}
+
+	var streams []*models.Stream
+	for _, item := range result.Items {
+		var stream models.Stream
+		if err := r.unmarshalStream(item, &stream); err != nil {
+			slog.Warn("⚠️ Failed to unmarshal stream", "error", err)
 			continue
 		}
 		streams = append(streams, &stream)
@@ -326,11 +383,44 @@ func (r *DynamoDBRepository) GetStreamsByStatus(status models.StreamStatus) ([]*
 	return streams, nil
 }
 
+// GetAllStreamsByUser returns every stream of a user still in the table
+func (r *DynamoDBRepository) GetAllStreamsByUser(userID int64, deleted models.DeletedFilter) ([]*models.Stream, error) {
+	input := &dynamodb.QueryInput{
+		TableName:              aws.String(r.tableName),
+		IndexName:              aws.String("user-id-index"),
+		KeyConditionExpression: aws.String("user_id = :user_id"),
+		FilterExpression:       deletedFilterExpression(deleted, nil),
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+			":user_id": {
+				N: aws.String(strconv.FormatInt(userID, 10)),
+			},
+		},
+	}
+
+	var streams []*models.Stream
+	err := r.client.QueryPages(input, func(page *dynamodb.QueryOutput, lastPage bool) bool {
+		for _, item := range page.Items {
+			var stream models.Stream
+			if err := r.unmarshalStream(item, &stream); err != nil {
+				slog.Warn("⚠️ Failed to unmarshal stream", "error", err)
+				continue
+			}
+			streams = append(streams, &stream)
+		}
+		return true
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to query streams by user: %w", err)
+	}
+
+	return streams, nil
+}
+
 // Fallback scan method for when GSI is not available
-func (r *DynamoDBRepository) getStreamsByStatusScan(status models.StreamStatus) ([]*models.Stream, error) {
+func (r *DynamoDBRepository) getStreamsByStatusScan(status models.StreamStatus, deleted models.DeletedFilter) ([]*models.Stream, error) {
 	input := &dynamodb.ScanInput{
 		TableName:        aws.String(r.tableName),
-		FilterExpression: aws.String("#status = :status"),
+		FilterExpression: deletedFilterExpression(deleted, aws.String("#status = :status")),
 		ExpressionAttributeNames: map[string]*string{
 			"#status": aws.String("status"),
 		},
@@ -349,9 +439,9 @@ func (r *DynamoDBRepository) getStreamsByStatusScan(status models.StreamStatus)
 	var streams []*models.Stream
 	for _, item := range result.Items {
 		var stream models.Stream
-		err = dynamodbattribute.UnmarshalMap(item, &stream)
+		err = r.unmarshalStream(item, &stream)
 		if err != nil {
-			log.Printf("⚠️ Failed to unmarshal stream: %v", err)
+			slog.Warn("⚠️ Failed to unmarshal stream", "error", err)
 			continue
 		}
 		streams = append(streams, &stream)
@@ -360,7 +450,28 @@ func (r *DynamoDBRepository) getStreamsByStatusScan(status models.StreamStatus)
 	return streams, nil
 }
 
+// unmarshalStream upgrades an item written by an older release before decoding it,
+// saving the upgraded item so the work isn't repeated on the next read
+func (r *DynamoDBRepository) unmarshalStream(item map[string]*dynamodb.AttributeValue, stream *models.Stream) error {
+	if _, err := datamigration.UpgradeAndSave(r.client.DynamoDB, r.streamMigrations, item); err != nil {
+		slog.Warn("⚠️ Could not upgrade stream item", "error", err)
+	}
+	return dynamodbattribute.UnmarshalMap(item, stream)
+}
+
+// BackfillStreams upgrades every stream item to the current schema version
+func (r *DynamoDBRepository) BackfillStreams(ctx context.Context, pageSize int64, pause time.Duration) (*datamigration.BackfillStats, error) {
+	return datamigration.Backfill(ctx, r.client.DynamoDB, r.streamMigrations, pageSize, pause)
+}
+
+// UpdateStream replaces the whole stream item, the last write wins and concurrent changes
+// aren't detected. With global tables that holds across regions too: when regions write
+// locally (DYNAMODB_WRITE_REGION=local), two regions changing a stream within replication lag
+// keep whichever wrote last, so e.g. a status change can be undone by a title edit made
+// elsewhere from an older read. Writing every stream in one region rules that out.
 func (r *DynamoDBRepository) UpdateStream(stream *models.Stream) error {
+	stream.SchemaVersion = r.streamMigrations.Latest()
+
 	item, err := dynamodbattribute.MarshalMap(stream)
 	if err != nil {
 		return fmt.Errorf("failed to marshal stream: %w", err)
@@ -376,6 +487,21 @@ func (r *DynamoDBRepository) UpdateStream(stream *models.Stream) error {
 		return fmt.Errorf("failed to update item: %w", err)
 	}
 
-	log.Printf("✅ Stream updated in DynamoDB: %s", stream.ID)
+	slog.Debug("✅ Stream updated in DynamoDB", "stream_id", stream.ID)
+	return nil
+}
+
+// DeleteStream removes a stream from the table, e.g. when its user's data is erased
+func (r *DynamoDBRepository) DeleteStream(streamID string) error {
+	_, err := r.client.DeleteItem(&dynamodb.DeleteItemInput{
+		TableName: aws.String(r.tableName),
+		Key: map[string]*dynamodb.AttributeValue{
+			"id": {S: aws.String(streamID)},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to delete stream: %w", err)
+	}
+
 	return nil
 }
@@ -0,0 +1,149 @@
+// services/stream-management-service/internal/repository/incident.go
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/services/stream-management-service/internal/models"
+)
+
+// incidentPartition is the stats table partition incidents are stored in, next to the rollups
+const incidentPartition = "incident"
+
+var ErrIncidentNotFound = errors.New("incident not found")
+
+// SaveIncident creates or replaces an incident. An incident whose start moved is stored under
+// its new bucket and removed from the old one.
+func (r *DynamoDBRepository) SaveIncident(incident *models.Incident) error {
+	previous := incident.Bucket
+	incident.Bucket = incidentBucket(incident)
+
+	item, err := dynamodbattribute.MarshalMap(incident)
+	if err != nil {
+		return fmt.Errorf("failed to marshal incident: %w", err)
+	}
+	item["granularity"] = &dynamodb.AttributeValue{S: aws.String(incidentPartition)}
+
+	_, err = r.client.PutItem(&dynamodb.PutItemInput{
+		TableName: aws.String(r.statsTableName),
+		Item:      item,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to put incident: %w", err)
+	}
+
+	if previous != "" && previous != incident.Bucket {
+		if err := r.deleteIncidentBucket(previous); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// GetIncidentByID returns an incident, or ErrIncidentNotFound
+func (r *DynamoDBRepository) GetIncidentByID(incidentID string) (*models.Incident, error) {
+	var found *models.Incident
+	err := r.queryIncidents(&dynamodb.QueryInput{
+		KeyConditionExpression: aws.String("granularity = :partition"),
+		FilterExpression:       aws.String("id = :id"),
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+			":partition": {S: aws.String(incidentPartition)},
+			":id":        {S: aws.String(incidentID)},
+		},
+	}, func(incident *models.Incident) {
+		found = incident
+	})
+	if err != nil {
+		return nil, err
+	}
+	if found == nil {
+		return nil, ErrIncidentNotFound
+	}
+
+	return found, nil
+}
+
+// GetIncidents returns the incidents overlapping from to to, by start
+func (r *DynamoDBRepository) GetIncidents(from, to time.Time) ([]*models.Incident, error) {
+	var incidents []*models.Incident
+	err := r.queryIncidents(&dynamodb.QueryInput{
+		// Buckets start with the RFC 3339 start, so every incident that started by to sorts
+		// before its second followed by a character above '#'
+		KeyConditionExpression: aws.String("granularity = :partition AND #bucket < :to"),
+		ExpressionAttributeNames: map[string]*string{
+			"#bucket": aws.String("bucket"),
+		},
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+			":partition": {S: aws.String(incidentPartition)},
+			":to":        {S: aws.String(statsBucket(to.Truncate(time.Second)) + "$")},
+		},
+	}, func(incident *models.Incident) {
+		if incident.Overlaps(from, to) {
+			incidents = append(incidents, incident)
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return incidents, nil
+}
+
+// DeleteIncident deletes an incident, or returns ErrIncidentNotFound
+func (r *DynamoDBRepository) DeleteIncident(incidentID string) error {
+	incident, err := r.GetIncidentByID(incidentID)
+	if err != nil {
+		return err
+	}
+
+	return r.deleteIncidentBucket(incident.Bucket)
+}
+
+func (r *DynamoDBRepository) deleteIncidentBucket(bucket string) error {
+	_, err := r.client.DeleteItem(&dynamodb.DeleteItemInput{
+		TableName: aws.String(r.statsTableName),
+		Key: map[string]*dynamodb.AttributeValue{
+			"granularity": {S: aws.String(incidentPartition)},
+			"bucket":      {S: aws.String(bucket)},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to delete incident: %w", err)
+	}
+
+	return nil
+}
+
+func (r *DynamoDBRepository) queryIncidents(input *dynamodb.QueryInput, fn func(*models.Incident)) error {
+	input.TableName = aws.String(r.statsTableName)
+
+	err := r.client.QueryPages(input, func(page *dynamodb.QueryOutput, lastPage bool) bool {
+		for _, item := range page.Items {
+			var incident models.Incident
+			if err := dynamodbattribute.UnmarshalMap(item, &incident); err != nil {
+				slog.Warn("⚠️ Failed to unmarshal incident", "error", err)
+				continue
+			}
+			fn(&incident)
+		}
+		return true
+	})
+	if err != nil {
+		return fmt.Errorf("failed to query incidents: %w", err)
+	}
+
+	return nil
+}
+
+// incidentBucket sorts incidents by start, the ID keeps incidents starting together apart
+func incidentBucket(incident *models.Incident) string {
+	return statsBucket(incident.StartsAt.Truncate(time.Second)) + "#" + incident.ID
+}
@@ -0,0 +1,123 @@
+// services/stream-management-service/internal/repository/interfaces.go
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/services/stream-management-service/internal/models"
+)
+
+// StreamStore is the durable storage StreamService works with. DynamoDBRepository implements
+// it, the memory package has an in-memory one for tests.
+type StreamStore interface {
+	CreateStream(ctx context.Context, stream *models.Stream) error
+	UpdateStream(stream *models.Stream) error
+	PutStreamWithEvents(ctx context.Context, stream *models.Stream, events []*models.OutboxEvent) error
+	GetStreamByID(streamID string) (*models.Stream, error)
+	GetStreamByStreamKey(streamKey string) (*models.Stream, error)
+	GetStreamsByIDs(streamIDs []string) ([]*models.Stream, error)
+	GetStreamsByStatus(status models.StreamStatus, deleted models.DeletedFilter) ([]*models.Stream, error)
+	GetStreamsByRecordingStatus(status models.RecordingStatus, deleted models.DeletedFilter) ([]*models.Stream, error)
+	GetStreamsByUser(userID int64, limit int, deleted models.DeletedFilter) ([]*models.Stream, error)
+	GetAllStreamsByUser(userID int64, deleted models.DeletedFilter) ([]*models.Stream, error)
+
+	SaveStreamAccess(access *models.StreamAccess) error
+	GetStreamAccess(streamID string) (*models.StreamAccess, error)
+	GetStreamKeyBan(streamKey string) (*models.StreamKeyBan, error)
+
+	AppendAuditRecord(record *models.AuditRecord) error
+	GetAuditRecords(streamID string, limit int, cursor string) ([]*models.AuditRecord, string, error)
+
+	AddStatsSample(granularity models.StatsGranularity, start time.Time, liveStreams, viewers, expiresAt int64) error
+	AddStatsNewStream(granularity models.StatsGranularity, start time.Time, expiresAt int64) error
+	GetStatsRollups(granularity models.StatsGranularity, from, to time.Time) ([]*models.StatsRollup, error)
+
+	SaveIncident(incident *models.Incident) error
+	GetIncidentByID(incidentID string) (*models.Incident, error)
+	GetIncidents(from, to time.Time) ([]*models.Incident, error)
+	DeleteIncident(incidentID string) error
+
+	SaveContentReview(review *models.ContentReview) error
+	GetContentReview(reviewID string) (*models.ContentReview, error)
+	GetContentReviews(status models.ContentReviewStatus) ([]*models.ContentReview, error)
+
+	GetPendingOutboxEvents(limit int) ([]*models.OutboxEvent, error)
+	MarkOutboxEventsDelivered(ids []string, deliveredAt time.Time, expiresAt int64) error
+}
+
+// StreamCache is the short-lived state StreamService keeps in Redis: sessions, viewers,
+// samples, claims and locks between replicas and the event spool. RedisRepository implements
+// it, the memory package has an in-memory one for tests.
+type StreamCache interface {
+	SetStreamData(streamID, data string, expiration time.Duration) error
+	GetStreamData(streamID string) (string, error)
+	GetStreamDataBatch(streamIDs []string) (map[string]string, error)
+	SetStreamSession(streamKey, sessionData string, expiration time.Duration) error
+	GetStreamSession(streamKey string) (string, error)
+	DeleteStreamSession(streamKey string) error
+	SetPlaybackStreamKey(playbackID, streamKey string, expiration time.Duration) error
+	GetPlaybackStreamKey(playbackID string) (string, error)
+	DeleteStreamKeyValidation(streamKey string) error
+
+	AddReconnecting(streamKey string, deadline time.Time) error
+	RemoveReconnecting(streamKey string) (bool, error)
+	GetExpiredReconnecting(now time.Time) ([]string, error)
+
+	ClaimCallback(key string, ttl time.Duration) (bool, error)
+	GetCallbackResult(key string) (string, error)
+	SetCallbackResult(key, result string, ttl time.Duration) error
+	ReleaseCallback(key string) error
+	ClaimStatsSample(sample string, ttl time.Duration) (bool, error)
+	ClaimJobRun(job string, scheduledAt time.Time, ttl time.Duration) (bool, error)
+	ClaimEventReplay(ttl time.Duration) (bool, error)
+	ClaimOutboxRelay(ttl time.Duration) (bool, error)
+
+	AcquireLock(name, owner string, ttl time.Duration) (bool, error)
+	RenewLock(name, owner string, ttl time.Duration) (bool, error)
+	ReleaseLock(name, owner string) error
+	GetLockOwner(name string) (string, error)
+
+	SetLiveDirectory(data string, expiration time.Duration) error
+	GetLiveDirectory() (string, error)
+	ClaimLiveDirectoryRefresh(ttl time.Duration) (bool, error)
+
+	PushHealthSample(streamID, sample string, windowSize int, expiration time.Duration) error
+	GetHealthSamples(streamID string) ([]string, error)
+	SetLatencyMarker(streamID, markerID string, ingestAtMs int64, ttl time.Duration) error
+	GetLatencyMarker(streamID, markerID string) (int64, error)
+	PushLatencySample(streamID, sample string, windowSize int, expiration time.Duration) error
+	GetLatencySamples(streamID string) ([]string, error)
+
+	RecordViewerHeartbeat(streamID, viewerID string, at time.Time, expiration time.Duration) error
+	AddEdgeViewer(streamID, connectionID, viewerID string, at time.Time, expiration time.Duration) error
+	RemoveEdgeViewer(streamID, connectionID string) error
+	SampleViewers(streamID string, bucket int64, now, cutoff time.Time, expiration time.Duration) (int64, bool, error)
+	GetViewerSamples(streamID string) ([]int64, error)
+	GetViewerSampleSeries(streamID string) (map[int64]int64, error)
+	CountUniqueViewers(streamID string) (int64, error)
+
+	SetFollow(viewerID, channelID int64, following bool, followerCount int64, ttl time.Duration) error
+	GetFollowedChannels(viewerID int64) (map[int64]bool, error)
+	GetFollowerCounts(channelIDs []int64) (map[int64]int64, error)
+	RecordFollow(channelID, followerID int64, at time.Time, retention time.Duration) error
+	CountFollowsBetween(channelID int64, from, to time.Time) (int64, error)
+	PushChannelEvent(channelID int64, event string, feedSize int, expiration time.Duration) error
+	GetChannelEvents(channelID int64) ([]string, error)
+
+	TrackStreamCategory(streamID, category string, viewers int64) error
+	GetCategoryStats() ([]*models.CategoryStats, error)
+	GetCategoryStreams(category string) ([]models.CategoryStream, error)
+	SetCategoryFeatured(category string, featured bool) error
+	GetFeaturedCategories() ([]string, error)
+
+	SpoolEvents(events [][]byte, maxLen int64) error
+	GetSpooledEvents(count int64) ([]string, [][]byte, error)
+	DeleteSpooledEvents(ids []string) error
+	CountSpooledEvents() (int64, error)
+}
+
+var (
+	_ StreamStore = (*DynamoDBRepository)(nil)
+	_ StreamCache = (*RedisRepository)(nil)
+)
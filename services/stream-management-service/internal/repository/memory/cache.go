@@ -0,0 +1,774 @@
+// services/stream-management-service/internal/repository/memory/cache.go
+package memory
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/services/stream-management-service/internal/models"
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/services/stream-management-service/internal/repository"
+)
+
+// StreamCache is an in-memory repository.StreamCache laid out like the Redis keys it stands
+// in for. Keys expire by Now, which tests can replace to move time forward.
+type StreamCache struct {
+	Now func() time.Time
+
+	mu      sync.Mutex
+	strings map[string]string
+	lists   map[string][]string           // newest first, like LPUSH
+	zsets   map[string]map[string]float64 // member scores
+	sets    map[string]map[string]bool
+	hashes  map[string]map[string]string
+	expiry  map[string]time.Time
+
+	spool       []spooledEvent
+	spoolNextID int64
+}
+
+type spooledEvent struct {
+	id   string
+	data []byte
+}
+
+var _ repository.StreamCache = (*StreamCache)(nil)
+
+func NewStreamCache() *StreamCache {
+	return &StreamCache{
+		Now:     time.Now,
+		strings: make(map[string]string),
+		lists:   make(map[string][]string),
+		zsets:   make(map[string]map[string]float64),
+		sets:    make(map[string]map[string]bool),
+		hashes:  make(map[string]map[string]string),
+		expiry:  make(map[string]time.Time),
+	}
+}
+
+func (c *StreamCache) SetStreamData(streamID, data string, expiration time.Duration) error {
+	c.set("stream:"+streamID, data, expiration)
+	return nil
+}
+
+func (c *StreamCache) GetStreamData(streamID string) (string, error) {
+	data, ok := c.get("stream:" + streamID)
+	if !ok {
+		return "", fmt.Errorf("failed to get stream data: %w", redis.Nil)
+	}
+	return data, nil
+}
+
+// GetStreamDataBatch leaves out the streams that aren't cached
+func (c *StreamCache) GetStreamDataBatch(streamIDs []string) (map[string]string, error) {
+	data := make(map[string]string, len(streamIDs))
+	for _, id := range streamIDs {
+		if raw, ok := c.get("stream:" + id); ok {
+			data[id] = raw
+		}
+	}
+	return data, nil
+}
+
+func (c *StreamCache) SetStreamSession(streamKey, sessionData string, expiration time.Duration) error {
+	c.set("session:"+streamKey, sessionData, expiration)
+	return nil
+}
+
+func (c *StreamCache) GetStreamSession(streamKey string) (string, error) {
+	data, ok := c.get("session:" + streamKey)
+	if !ok {
+		return "", fmt.Errorf("failed to get stream session: %w", redis.Nil)
+	}
+	return data, nil
+}
+
+func (c *StreamCache) DeleteStreamSession(streamKey string) error {
+	c.del("session:" + streamKey)
+	return nil
+}
+
+func (c *StreamCache) SetPlaybackStreamKey(playbackID, streamKey string, expiration time.Duration) error {
+	c.set("playback:"+playbackID, streamKey, expiration)
+	return nil
+}
+
+func (c *StreamCache) GetPlaybackStreamKey(playbackID string) (string, error) {
+	streamKey, ok := c.get("playback:" + playbackID)
+	if !ok {
+		return "", fmt.Errorf("failed to get playback stream key: %w", redis.Nil)
+	}
+	return streamKey, nil
+}
+
+func (c *StreamCache) DeleteStreamKeyValidation(streamKey string) error {
+	c.del("stream_key_validation:" + streamKey)
+	return nil
+}
+
+func (c *StreamCache) AddReconnecting(streamKey string, deadline time.Time) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.zadd("reconnecting", streamKey, float64(deadline.Unix()))
+	return nil
+}
+
+func (c *StreamCache) RemoveReconnecting(streamKey string) (bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.zrem("reconnecting", streamKey), nil
+}
+
+func (c *StreamCache) GetExpiredReconnecting(now time.Time) ([]string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.zrangeByScore("reconnecting", float64(now.Unix())), nil
+}
+
+func (c *StreamCache) ClaimCallback(key string, ttl time.Duration) (bool, error) {
+	return c.setNX("rtmp_callback:"+key, ttl), nil
+}
+
+// GetCallbackResult returns an empty result while the callback is claimed but unfinished
+func (c *StreamCache) GetCallbackResult(key string) (string, error) {
+	result, _ := c.get("rtmp_callback:" + key)
+	return result, nil
+}
+
+func (c *StreamCache) SetCallbackResult(key, result string, ttl time.Duration) error {
+	c.set("rtmp_callback:"+key, result, ttl)
+	return nil
+}
+
+func (c *StreamCache) ReleaseCallback(key string) error {
+	c.del("rtmp_callback:" + key)
+	return nil
+}
+
+func (c *StreamCache) ClaimStatsSample(sample string, ttl time.Duration) (bool, error) {
+	return c.setNX("stats_sample:"+sample, ttl), nil
+}
+
+func (c *StreamCache) ClaimJobRun(job string, scheduledAt time.Time, ttl time.Duration) (bool, error) {
+	return c.setNX(fmt.Sprintf("job_run:%s:%d", job, scheduledAt.Unix()), ttl), nil
+}
+
+func (c *StreamCache) ClaimEventReplay(ttl time.Duration) (bool, error) {
+	return c.setNX("event_spool_replay", ttl), nil
+}
+
+func (c *StreamCache) ClaimOutboxRelay(ttl time.Duration) (bool, error) {
+	return c.setNX("outbox_relay", ttl), nil
+}
+
+func (c *StreamCache) AcquireLock(name, owner string, ttl time.Duration) (bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	key := "lock:" + name
+	c.purge(key)
+	if _, ok := c.strings[key]; ok {
+		return false, nil
+	}
+	c.strings[key] = owner
+	c.expire(key, ttl)
+	return true, nil
+}
+
+func (c *StreamCache) RenewLock(name, owner string, ttl time.Duration) (bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	key := "lock:" + name
+	c.purge(key)
+	if c.strings[key] != owner {
+		return false, nil
+	}
+	c.expire(key, ttl)
+	return true, nil
+}
+
+func (c *StreamCache) ReleaseLock(name, owner string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	key := "lock:" + name
+	c.purge(key)
+	if c.strings[key] == owner {
+		delete(c.strings, key)
+		delete(c.expiry, key)
+	}
+	return nil
+}
+
+func (c *StreamCache) GetLockOwner(name string) (string, error) {
+	owner, _ := c.get("lock:" + name)
+	return owner, nil
+}
+
+func (c *StreamCache) SetLiveDirectory(data string, expiration time.Duration) error {
+	c.set("live_directory", data, expiration)
+	return nil
+}
+
+func (c *StreamCache) GetLiveDirectory() (string, error) {
+	data, ok := c.get("live_directory")
+	if !ok {
+		return "", fmt.Errorf("failed to get live directory: %w", redis.Nil)
+	}
+	return data, nil
+}
+
+func (c *StreamCache) ClaimLiveDirectoryRefresh(ttl time.Duration) (bool, error) {
+	return c.setNX("live_directory_refresh", ttl), nil
+}
+
+func (c *StreamCache) PushHealthSample(streamID, sample string, windowSize int, expiration time.Duration) error {
+	c.push("health:"+streamID, sample, windowSize, expiration)
+	return nil
+}
+
+func (c *StreamCache) GetHealthSamples(streamID string) ([]string, error) {
+	return c.list("health:" + streamID), nil
+}
+
+func (c *StreamCache) SetLatencyMarker(streamID, markerID string, ingestAtMs int64, ttl time.Duration) error {
+	c.set("latency_marker:"+streamID+":"+markerID, strconv.FormatInt(ingestAtMs, 10), ttl)
+	return nil
+}
+
+// GetLatencyMarker returns 0 for markers that don't exist or expired
+func (c *StreamCache) GetLatencyMarker(streamID, markerID string) (int64, error) {
+	raw, ok := c.get("latency_marker:" + streamID + ":" + markerID)
+	if !ok {
+		return 0, nil
+	}
+	ingestAt, err := strconv.ParseInt(raw, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get latency marker: %w", err)
+	}
+	return ingestAt, nil
+}
+
+func (c *StreamCache) PushLatencySample(streamID, sample string, windowSize int, expiration time.Duration) error {
+	c.push("latency:"+streamID, sample, windowSize, expiration)
+	return nil
+}
+
+func (c *StreamCache) GetLatencySamples(streamID string) ([]string, error) {
+	return c.list("latency:" + streamID), nil
+}
+
+func (c *StreamCache) RecordViewerHeartbeat(streamID, viewerID string, at time.Time, expiration time.Duration) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.zadd("viewers:"+streamID, viewerID, float64(at.Unix()))
+	c.expire("viewers:"+streamID, expiration)
+	c.sadd("viewers_unique:"+streamID, viewerID)
+	c.expire("viewers_unique:"+streamID, expiration)
+	return nil
+}
+
+func (c *StreamCache) AddEdgeViewer(streamID, connectionID, viewerID string, at time.Time, expiration time.Duration) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.sadd("edge_viewers:"+streamID, connectionID)
+	c.expire("edge_viewers:"+streamID, expiration)
+	c.zadd("viewers:"+streamID, connectionID, float64(at.Unix()))
+	c.expire("viewers:"+streamID, expiration)
+	c.sadd("viewers_unique:"+streamID, viewerID)
+	c.expire("viewers_unique:"+streamID, expiration)
+	return nil
+}
+
+func (c *StreamCache) RemoveEdgeViewer(streamID, connectionID string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.srem("edge_viewers:"+streamID, connectionID)
+	c.zrem("viewers:"+streamID, connectionID)
+	return nil
+}
+
+// SampleViewers refreshes edge connections, drops viewers not seen since cutoff and stores
+// the count for the bucket unless it already has one
+func (c *StreamCache) SampleViewers(streamID string, bucket int64, now, cutoff time.Time, expiration time.Duration) (int64, bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	watchingKey := "viewers:" + streamID
+	for connection := range c.smembers("edge_viewers:" + streamID) {
+		c.zadd(watchingKey, connection, float64(now.Unix()))
+	}
+	for viewer, seen := range c.zsets[watchingKey] {
+		if seen < float64(cutoff.Unix()) {
+			c.zrem(watchingKey, viewer)
+		}
+	}
+	count := int64(len(c.zsets[watchingKey]))
+
+	samplesKey := "viewer_samples:" + streamID
+	field := strconv.FormatInt(bucket, 10)
+	_, exists := c.hgetall(samplesKey)[field]
+	if !exists {
+		c.hset(samplesKey, field, strconv.FormatInt(count, 10))
+	}
+	c.expire(samplesKey, expiration)
+
+	return count, !exists, nil
+}
+
+func (c *StreamCache) GetViewerSamples(streamID string) ([]int64, error) {
+	series, _ := c.GetViewerSampleSeries(streamID)
+
+	buckets := make([]int64, 0, len(series))
+	for bucket := range series {
+		buckets = append(buckets, bucket)
+	}
+	sort.Slice(buckets, func(i, j int) bool { return buckets[i] < buckets[j] })
+
+	samples := make([]int64, len(buckets))
+	for i, bucket := range buckets {
+		samples[i] = series[bucket]
+	}
+	return samples, nil
+}
+
+func (c *StreamCache) GetViewerSampleSeries(streamID string) (map[int64]int64, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	values := c.hgetall("viewer_samples:" + streamID)
+	series := make(map[int64]int64, len(values))
+	for field, value := range values {
+		bucket, err := strconv.ParseInt(field, 10, 64)
+		if err != nil {
+			continue
+		}
+		if sample, err := strconv.ParseInt(value, 10, 64); err == nil {
+			series[bucket] = sample
+		}
+	}
+	return series, nil
+}
+
+// CountUniqueViewers counts exactly, the HyperLogLog it stands in for estimates
+func (c *StreamCache) CountUniqueViewers(streamID string) (int64, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return int64(len(c.smembers("viewers_unique:" + streamID))), nil
+}
+
+// SetFollow records a follow change. A followerCount of 0 or more replaces the channel's
+// count, a negative one adjusts it by one.
+func (c *StreamCache) SetFollow(viewerID, channelID int64, following bool, followerCount int64, ttl time.Duration) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	key := fmt.Sprintf("following:%d", viewerID)
+	channel := strconv.FormatInt(channelID, 10)
+	if following {
+		c.sadd(key, channel)
+	} else {
+		c.srem(key, channel)
+	}
+	c.expire(key, ttl)
+
+	count, _ := strconv.ParseInt(c.hgetall("follower_counts")[channel], 10, 64)
+	switch {
+	case followerCount >= 0:
+		count = followerCount
+	case following:
+		count++
+	default:
+		count--
+	}
+	c.hset("follower_counts", channel, strconv.FormatInt(count, 10))
+	return nil
+}
+
+func (c *StreamCache) GetFollowedChannels(viewerID int64) (map[int64]bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	followed := make(map[int64]bool)
+	for member := range c.smembers(fmt.Sprintf("following:%d", viewerID)) {
+		if id, err := strconv.ParseInt(member, 10, 64); err == nil {
+			followed[id] = true
+		}
+	}
+	return followed, nil
+}
+
+func (c *StreamCache) GetFollowerCounts(channelIDs []int64) (map[int64]int64, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	stored := c.hgetall("follower_counts")
+	counts := make(map[int64]int64)
+	for _, id := range channelIDs {
+		raw, ok := stored[strconv.FormatInt(id, 10)]
+		if !ok {
+			continue
+		}
+		if count, err := strconv.ParseInt(raw, 10, 64); err == nil {
+			counts[id] = count
+		}
+	}
+	return counts, nil
+}
+
+// RecordFollow logs a follow and forgets the ones older than retention
+func (c *StreamCache) RecordFollow(channelID, followerID int64, at time.Time, retention time.Duration) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	key := fmt.Sprintf("follows:%d", channelID)
+	c.zadd(key, strconv.FormatInt(followerID, 10), float64(at.Unix()))
+	for follower, followedAt := range c.zsets[key] {
+		if followedAt < float64(at.Add(-retention).Unix()) {
+			c.zrem(key, follower)
+		}
+	}
+	c.expire(key, retention)
+	return nil
+}
+
+func (c *StreamCache) CountFollowsBetween(channelID int64, from, to time.Time) (int64, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	key := fmt.Sprintf("follows:%d", channelID)
+	c.purge(key)
+
+	count := int64(0)
+	for _, followedAt := range c.zsets[key] {
+		if followedAt >= float64(from.Unix()) && followedAt <= float64(to.Unix()) {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (c *StreamCache) PushChannelEvent(channelID int64, event string, feedSize int, expiration time.Duration) error {
+	c.push(fmt.Sprintf("channel_events:%d", channelID), event, feedSize, expiration)
+	return nil
+}
+
+func (c *StreamCache) GetChannelEvents(channelID int64) ([]string, error) {
+	return c.list(fmt.Sprintf("channel_events:%d", channelID)), nil
+}
+
+// TrackStreamCategory moves a stream and its viewers to a category, an empty category stops
+// counting it
+func (c *StreamCache) TrackStreamCategory(streamID, category string, viewers int64) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if old, ok := c.hgetall("stream_category")[streamID]; ok {
+		oldViewers, _ := strconv.ParseInt(c.hgetall("category_streams:" + old)[streamID], 10, 64)
+		c.hdel("category_streams:"+old, streamID)
+		c.hdel("stream_category", streamID)
+
+		channels := c.zincr("category_channels", old, -1)
+		c.zincr("category_viewers", old, -float64(oldViewers))
+		if channels <= 0 {
+			c.zrem("category_channels", old)
+			c.zrem("category_viewers", old)
+		}
+	}
+
+	if category != "" {
+		c.hset("stream_category", streamID, category)
+		c.hset("category_streams:"+category, streamID, strconv.FormatInt(viewers, 10))
+		c.zincr("category_channels", category, 1)
+		c.zincr("category_viewers", category, float64(viewers))
+	}
+	return nil
+}
+
+// GetCategoryStats returns the categories with live channels, fewest channels first
+func (c *StreamCache) GetCategoryStats() ([]*models.CategoryStats, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	stats := make([]*models.CategoryStats, 0, len(c.zsets["category_channels"]))
+	for category, channels := range c.zsets["category_channels"] {
+		stats = append(stats, &models.CategoryStats{
+			Category:     category,
+			LiveChannels: int64(channels),
+			Viewers:      int64(c.zsets["category_viewers"][category]),
+		})
+	}
+	sort.Slice(stats, func(i, j int) bool {
+		if stats[i].LiveChannels != stats[j].LiveChannels {
+			return stats[i].LiveChannels < stats[j].LiveChannels
+		}
+		return stats[i].Category < stats[j].Category
+	})
+	return stats, nil
+}
+
+func (c *StreamCache) GetCategoryStreams(category string) ([]models.CategoryStream, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entries := c.hgetall("category_streams:" + category)
+	streams := make([]models.CategoryStream, 0, len(entries))
+	for streamID, value := range entries {
+		viewers, _ := strconv.ParseInt(value, 10, 64)
+		streams = append(streams, models.CategoryStream{StreamID: streamID, Viewers: viewers})
+	}
+	return streams, nil
+}
+
+func (c *StreamCache) SetCategoryFeatured(category string, featured bool) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if featured {
+		c.sadd("featured_categories", category)
+	} else {
+		c.srem("featured_categories", category)
+	}
+	return nil
+}
+
+func (c *StreamCache) GetFeaturedCategories() ([]string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	categories := make([]string, 0)
+	for category := range c.smembers("featured_categories") {
+		categories = append(categories, category)
+	}
+	sort.Strings(categories)
+	return categories, nil
+}
+
+// SpoolEvents appends events to the spool, dropping the oldest beyond maxLen
+func (c *StreamCache) SpoolEvents(events [][]byte, maxLen int64) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, event := range events {
+		c.spoolNextID++
+		c.spool = append(c.spool, spooledEvent{
+			id:   fmt.Sprintf("%d-0", c.spoolNextID),
+			data: append([]byte(nil), event...),
+		})
+	}
+	if maxLen > 0 && int64(len(c.spool)) > maxLen {
+		c.spool = c.spool[int64(len(c.spool))-maxLen:]
+	}
+	return nil
+}
+
+func (c *StreamCache) GetSpooledEvents(count int64) ([]string, [][]byte, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	n := min(count, int64(len(c.spool)))
+	ids := make([]string, n)
+	events := make([][]byte, n)
+	for i := int64(0); i < n; i++ {
+		ids[i] = c.spool[i].id
+		events[i] = append([]byte(nil), c.spool[i].data...)
+	}
+	return ids, events, nil
+}
+
+func (c *StreamCache) DeleteSpooledEvents(ids []string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	remove := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		remove[id] = true
+	}
+	kept := c.spool[:0]
+	for _, event := range c.spool {
+		if !remove[event.id] {
+			kept = append(kept, event)
+		}
+	}
+	c.spool = kept
+	return nil
+}
+
+func (c *StreamCache) CountSpooledEvents() (int64, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return int64(len(c.spool)), nil
+}
+
+func (c *StreamCache) set(key, value string, expiration time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.strings[key] = value
+	delete(c.expiry, key)
+	c.expire(key, expiration)
+}
+
+func (c *StreamCache) setNX(key string, ttl time.Duration) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.purge(key)
+	if _, ok := c.strings[key]; ok {
+		return false
+	}
+	c.strings[key] = ""
+	c.expire(key, ttl)
+	return true
+}
+
+func (c *StreamCache) get(key string) (string, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.purge(key)
+	value, ok := c.strings[key]
+	return value, ok
+}
+
+func (c *StreamCache) del(key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.strings, key)
+	delete(c.expiry, key)
+}
+
+// push adds a value to the front of a list and trims it to windowSize
+func (c *StreamCache) push(key, value string, windowSize int, expiration time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.purge(key)
+	list := append([]string{value}, c.lists[key]...)
+	if len(list) > windowSize {
+		list = list[:windowSize]
+	}
+	c.lists[key] = list
+	c.expire(key, expiration)
+}
+
+func (c *StreamCache) list(key string) []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.purge(key)
+	return append([]string{}, c.lists[key]...)
+}
+
+// The helpers below expect c.mu to be held
+
+func (c *StreamCache) zadd(key, member string, score float64) {
+	c.purge(key)
+	if c.zsets[key] == nil {
+		c.zsets[key] = make(map[string]float64)
+	}
+	c.zsets[key][member] = score
+}
+
+func (c *StreamCache) zincr(key, member string, delta float64) float64 {
+	c.purge(key)
+	if c.zsets[key] == nil {
+		c.zsets[key] = make(map[string]float64)
+	}
+	c.zsets[key][member] += delta
+	return c.zsets[key][member]
+}
+
+func (c *StreamCache) zrem(key, member string) bool {
+	c.purge(key)
+	if _, ok := c.zsets[key][member]; !ok {
+		return false
+	}
+	delete(c.zsets[key], member)
+	return true
+}
+
+// zrangeByScore returns the members scored up to maxScore, lowest first
+func (c *StreamCache) zrangeByScore(key string, maxScore float64) []string {
+	c.purge(key)
+	members := make([]string, 0)
+	for member, score := range c.zsets[key] {
+		if score <= maxScore {
+			members = append(members, member)
+		}
+	}
+	sort.Slice(members, func(i, j int) bool {
+		si, sj := c.zsets[key][members[i]], c.zsets[key][members[j]]
+		if si != sj {
+			return si < sj
+		}
+		return members[i] < members[j]
+	})
+	return members
+}
+
+func (c *StreamCache) sadd(key, member string) {
+	c.purge(key)
+	if c.sets[key] == nil {
+		c.sets[key] = make(map[string]bool)
+	}
+	c.sets[key][member] = true
+}
+
+func (c *StreamCache) srem(key, member string) {
+	c.purge(key)
+	delete(c.sets[key], member)
+}
+
+func (c *StreamCache) smembers(key string) map[string]bool {
+	c.purge(key)
+	return c.sets[key]
+}
+
+func (c *StreamCache) hset(key, field, value string) {
+	c.purge(key)
+	if c.hashes[key] == nil {
+		c.hashes[key] = make(map[string]string)
+	}
+	c.hashes[key][field] = value
+}
+
+func (c *StreamCache) hdel(key, field string) {
+	c.purge(key)
+	delete(c.hashes[key], field)
+}
+
+func (c *StreamCache) hgetall(key string) map[string]string {
+	c.purge(key)
+	return c.hashes[key]
+}
+
+// expire sets when a key goes away, 0 keeps it
+func (c *StreamCache) expire(key string, ttl time.Duration) {
+	if ttl > 0 {
+		c.expiry[key] = c.Now().Add(ttl)
+	}
+}
+
+// purge drops a key whose time is up
+func (c *StreamCache) purge(key string) {
+	at, ok := c.expiry[key]
+	if !ok || c.Now().Before(at) {
+		return
+	}
+	delete(c.strings, key)
+	delete(c.lists, key)
+	delete(c.zsets, key)
+	delete(c.sets, key)
+	delete(c.hashes, key)
+	delete(c.expiry, key)
+}
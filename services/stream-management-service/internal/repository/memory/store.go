@@ -0,0 +1,536 @@
+// services/stream-management-service/internal/repository/memory/store.go
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/services/stream-management-service/internal/models"
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/services/stream-management-service/internal/repository"
+)
+
+type item = map[string]*dynamodb.AttributeValue
+
+// StreamStore is an in-memory repository.StreamStore. Items go through the same attribute
+// marshalling as DynamoDB, so callers get copies and the dynamodbav tags apply.
+type StreamStore struct {
+	mu        sync.Mutex
+	streams   map[string]item
+	order     []string // stream IDs in the order they were created
+	access    map[string]item
+	bans      map[string]item
+	audit     map[string][]item // per stream, oldest first
+	rollups   map[models.StatsGranularity]map[string]*models.StatsRollup
+	incidents map[string]item // by ID
+	reviews   map[string]item // content reviews by ID
+	outbox    map[string]item // outbox events by ID
+}
+
+var _ repository.StreamStore = (*StreamStore)(nil)
+
+func NewStreamStore() *StreamStore {
+	return &StreamStore{
+		streams:   make(map[string]item),
+		access:    make(map[string]item),
+		bans:      make(map[string]item),
+		audit:     make(map[string][]item),
+		rollups:   make(map[models.StatsGranularity]map[string]*models.StatsRollup),
+		incidents: make(map[string]item),
+		reviews:   make(map[string]item),
+		outbox:    make(map[string]item),
+	}
+}
+
+func (s *StreamStore) CreateStream(ctx context.Context, stream *models.Stream) error {
+	return s.putStream(stream)
+}
+
+func (s *StreamStore) UpdateStream(stream *models.Stream) error {
+	return s.putStream(stream)
+}
+
+// PutStreamWithEvents writes the stream and its events under one lock, nothing is written
+// when an event already exists
+func (s *StreamStore) PutStreamWithEvents(ctx context.Context, stream *models.Stream, events []*models.OutboxEvent) error {
+	storedEvents := make([]item, len(events))
+	for i, event := range events {
+		stored, err := dynamodbattribute.MarshalMap(event)
+		if err != nil {
+			return fmt.Errorf("failed to marshal outbox event: %w", err)
+		}
+		storedEvents[i] = stored
+	}
+
+	stored, err := dynamodbattribute.MarshalMap(stream)
+	if err != nil {
+		return fmt.Errorf("failed to marshal stream: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, event := range events {
+		if _, ok := s.outbox[event.ID]; ok {
+			return fmt.Errorf("outbox event %s already exists", event.ID)
+		}
+	}
+	if _, ok := s.streams[stream.ID]; !ok {
+		s.order = append(s.order, stream.ID)
+	}
+	s.streams[stream.ID] = stored
+	for i, event := range events {
+		s.outbox[event.ID] = storedEvents[i]
+	}
+	return nil
+}
+
+func (s *StreamStore) putStream(stream *models.Stream) error {
+	stored, err := dynamodbattribute.MarshalMap(stream)
+	if err != nil {
+		return fmt.Errorf("failed to marshal stream: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.streams[stream.ID]; !ok {
+		s.order = append(s.order, stream.ID)
+	}
+	s.streams[stream.ID] = stored
+	return nil
+}
+
+func (s *StreamStore) GetStreamByID(streamID string) (*models.Stream, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stored, ok := s.streams[streamID]
+	if !ok {
+		return nil, fmt.Errorf("stream not found")
+	}
+	return unmarshalStream(stored)
+}
+
+func (s *StreamStore) GetStreamByStreamKey(streamKey string) (*models.Stream, error) {
+	streams, err := s.filterStreams(func(stream *models.Stream) bool { return stream.StreamKey == streamKey })
+	if err != nil {
+		return nil, err
+	}
+	if len(streams) == 0 {
+		return nil, fmt.Errorf("stream not found")
+	}
+	return streams[0], nil
+}
+
+// GetStreamsByIDs leaves out the streams that don't exist, like BatchGetItem
+func (s *StreamStore) GetStreamsByIDs(streamIDs []string) ([]*models.Stream, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var streams []*models.Stream
+	for _, id := range streamIDs {
+		stored, ok := s.streams[id]
+		if !ok {
+			continue
+		}
+		stream, err := unmarshalStream(stored)
+		if err != nil {
+			return nil, err
+		}
+		streams = append(streams, stream)
+	}
+	return streams, nil
+}
+
+func (s *StreamStore) GetStreamsByStatus(status models.StreamStatus, deleted models.DeletedFilter) ([]*models.Stream, error) {
+	return s.filterStreams(func(stream *models.Stream) bool {
+		return stream.Status == status && deleted.Matches(stream.DeletedAt)
+	})
+}
+
+func (s *StreamStore) GetStreamsByRecordingStatus(status models.RecordingStatus, deleted models.DeletedFilter) ([]*models.Stream, error) {
+	return s.filterStreams(func(stream *models.Stream) bool {
+		return stream.RecordingStatus == status && deleted.Matches(stream.DeletedAt)
+	})
+}
+
+func (s *StreamStore) GetStreamsByUser(userID int64, limit int, deleted models.DeletedFilter) ([]*models.Stream, error) {
+	streams, err := s.GetAllStreamsByUser(userID, deleted)
+	if err != nil {
+		return nil, err
+	}
+	if limit > 0 && len(streams) > limit {
+		streams = streams[:limit]
+	}
+	return streams, nil
+}
+
+func (s *StreamStore) GetAllStreamsByUser(userID int64, deleted models.DeletedFilter) ([]*models.Stream, error) {
+	return s.filterStreams(func(stream *models.Stream) bool {
+		return stream.UserID == userID && deleted.Matches(stream.DeletedAt)
+	})
+}
+
+// filterStreams returns the matching streams in the order they were created
+func (s *StreamStore) filterStreams(match func(*models.Stream) bool) ([]*models.Stream, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var streams []*models.Stream
+	for _, id := range s.order {
+		stream, err := unmarshalStream(s.streams[id])
+		if err != nil {
+			return nil, err
+		}
+		if match(stream) {
+			streams = append(streams, stream)
+		}
+	}
+	return streams, nil
+}
+
+func (s *StreamStore) SaveStreamAccess(access *models.StreamAccess) error {
+	stored, err := dynamodbattribute.MarshalMap(access)
+	if err != nil {
+		return fmt.Errorf("failed to marshal stream access: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.access[access.StreamID] = stored
+	return nil
+}
+
+// GetStreamAccess returns an empty StreamAccess for streams nobody was given access to
+func (s *StreamStore) GetStreamAccess(streamID string) (*models.StreamAccess, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	access := &models.StreamAccess{StreamID: streamID}
+	stored, ok := s.access[streamID]
+	if !ok {
+		return access, nil
+	}
+	if err := dynamodbattribute.UnmarshalMap(stored, access); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal stream access: %w", err)
+	}
+	return access, nil
+}
+
+// SaveStreamKeyBan bans a stream key, the service itself only reads bans
+func (s *StreamStore) SaveStreamKeyBan(ban *models.StreamKeyBan) error {
+	stored, err := dynamodbattribute.MarshalMap(ban)
+	if err != nil {
+		return fmt.Errorf("failed to marshal stream key ban: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.bans[ban.StreamKey] = stored
+	return nil
+}
+
+func (s *StreamStore) GetStreamKeyBan(streamKey string) (*models.StreamKeyBan, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stored, ok := s.bans[streamKey]
+	if !ok {
+		return nil, repository.ErrStreamKeyNotBanned
+	}
+	var ban models.StreamKeyBan
+	if err := dynamodbattribute.UnmarshalMap(stored, &ban); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal stream key ban: %w", err)
+	}
+	return &ban, nil
+}
+
+// AppendAuditRecord refuses a record whose sort key is taken, like the conditional put
+func (s *StreamStore) AppendAuditRecord(record *models.AuditRecord) error {
+	stored, err := dynamodbattribute.MarshalMap(record)
+	if err != nil {
+		return fmt.Errorf("failed to marshal audit record: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	records := s.audit[record.StreamID]
+	at := sort.Search(len(records), func(i int) bool { return auditSortKey(records[i]) >= record.SortKey })
+	if at < len(records) && auditSortKey(records[at]) == record.SortKey {
+		return fmt.Errorf("failed to put audit record: sort key %s exists", record.SortKey)
+	}
+	records = append(records, nil)
+	copy(records[at+1:], records[at:])
+	records[at] = stored
+	s.audit[record.StreamID] = records
+	return nil
+}
+
+// GetAuditRecords pages newest first. The cursor is the sort key of the last record returned.
+func (s *StreamStore) GetAuditRecords(streamID string, limit int, cursor string) ([]*models.AuditRecord, string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stored := s.audit[streamID]
+	records := make([]*models.AuditRecord, 0, limit)
+	for i := len(stored) - 1; i >= 0; i-- {
+		if cursor != "" && auditSortKey(stored[i]) >= cursor {
+			continue
+		}
+		if len(records) == limit {
+			return records, records[len(records)-1].SortKey, nil
+		}
+
+		var record models.AuditRecord
+		if err := dynamodbattribute.UnmarshalMap(stored[i], &record); err != nil {
+			return nil, "", fmt.Errorf("failed to unmarshal audit record: %w", err)
+		}
+		records = append(records, &record)
+	}
+	return records, "", nil
+}
+
+func (s *StreamStore) AddStatsSample(granularity models.StatsGranularity, start time.Time, liveStreams, viewers, expiresAt int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	rollup := s.rollup(granularity, start, expiresAt)
+	rollup.Samples++
+	rollup.LiveStreamsSum += liveStreams
+	rollup.ViewersSum += viewers
+	rollup.LiveStreamsPeak = max(rollup.LiveStreamsPeak, liveStreams)
+	rollup.ViewersPeak = max(rollup.ViewersPeak, viewers)
+	return nil
+}
+
+func (s *StreamStore) AddStatsNewStream(granularity models.StatsGranularity, start time.Time, expiresAt int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.rollup(granularity, start, expiresAt).NewStreams++
+	return nil
+}
+
+// GetStatsRollups returns the buckets starting between from and to, oldest first, with their
+// averages computed
+func (s *StreamStore) GetStatsRollups(granularity models.StatsGranularity, from, to time.Time) ([]*models.StatsRollup, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	fromBucket, toBucket := statsBucket(from), statsBucket(to)
+	var rollups []*models.StatsRollup
+	for bucket, stored := range s.rollups[granularity] {
+		if bucket < fromBucket || bucket > toBucket {
+			continue
+		}
+		rollup := *stored
+		rollup.Start, _ = time.Parse(time.RFC3339, rollup.Bucket)
+		if rollup.Samples > 0 {
+			rollup.LiveStreamsAvg = float64(rollup.LiveStreamsSum) / float64(rollup.Samples)
+			rollup.ViewersAvg = float64(rollup.ViewersSum) / float64(rollup.Samples)
+		}
+		rollups = append(rollups, &rollup)
+	}
+	sort.Slice(rollups, func(i, j int) bool { return rollups[i].Bucket < rollups[j].Bucket })
+	return rollups, nil
+}
+
+func (s *StreamStore) rollup(granularity models.StatsGranularity, start time.Time, expiresAt int64) *models.StatsRollup {
+	buckets, ok := s.rollups[granularity]
+	if !ok {
+		buckets = make(map[string]*models.StatsRollup)
+		s.rollups[granularity] = buckets
+	}
+
+	bucket := statsBucket(start)
+	rollup, ok := buckets[bucket]
+	if !ok {
+		rollup = &models.StatsRollup{Granularity: granularity, Bucket: bucket}
+		buckets[bucket] = rollup
+	}
+	if expiresAt > 0 {
+		rollup.ExpiresAt = expiresAt
+	}
+	return rollup
+}
+
+func (s *StreamStore) SaveIncident(incident *models.Incident) error {
+	incident.Bucket = statsBucket(incident.StartsAt.Truncate(time.Second)) + "#" + incident.ID
+	stored, err := dynamodbattribute.MarshalMap(incident)
+	if err != nil {
+		return fmt.Errorf("failed to marshal incident: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.incidents[incident.ID] = stored
+	return nil
+}
+
+func (s *StreamStore) GetIncidentByID(incidentID string) (*models.Incident, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stored, ok := s.incidents[incidentID]
+	if !ok {
+		return nil, repository.ErrIncidentNotFound
+	}
+	return unmarshalIncident(stored)
+}
+
+// GetIncidents returns the incidents overlapping from to to, by start
+func (s *StreamStore) GetIncidents(from, to time.Time) ([]*models.Incident, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var incidents []*models.Incident
+	for _, stored := range s.incidents {
+		incident, err := unmarshalIncident(stored)
+		if err != nil {
+			return nil, err
+		}
+		if incident.Overlaps(from, to) {
+			incidents = append(incidents, incident)
+		}
+	}
+	sort.Slice(incidents, func(i, j int) bool { return incidents[i].Bucket < incidents[j].Bucket })
+	return incidents, nil
+}
+
+func (s *StreamStore) DeleteIncident(incidentID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.incidents[incidentID]; !ok {
+		return repository.ErrIncidentNotFound
+	}
+	delete(s.incidents, incidentID)
+	return nil
+}
+
+func (s *StreamStore) SaveContentReview(review *models.ContentReview) error {
+	stored, err := dynamodbattribute.MarshalMap(review)
+	if err != nil {
+		return fmt.Errorf("failed to marshal content review: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.reviews[review.ID] = stored
+	return nil
+}
+
+func (s *StreamStore) GetContentReview(reviewID string) (*models.ContentReview, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stored, ok := s.reviews[reviewID]
+	if !ok {
+		return nil, repository.ErrContentReviewNotFound
+	}
+	return unmarshalContentReview(stored)
+}
+
+func (s *StreamStore) GetContentReviews(status models.ContentReviewStatus) ([]*models.ContentReview, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var reviews []*models.ContentReview
+	for _, stored := range s.reviews {
+		review, err := unmarshalContentReview(stored)
+		if err != nil {
+			return nil, err
+		}
+		if status == "" || review.Status == status {
+			reviews = append(reviews, review)
+		}
+	}
+	return reviews, nil
+}
+
+func (s *StreamStore) GetPendingOutboxEvents(limit int) ([]*models.OutboxEvent, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var events []*models.OutboxEvent
+	for _, stored := range s.outbox {
+		var event models.OutboxEvent
+		if err := dynamodbattribute.UnmarshalMap(stored, &event); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal outbox event: %w", err)
+		}
+		if event.Status == models.OutboxPending {
+			events = append(events, &event)
+		}
+	}
+	sort.Slice(events, func(i, j int) bool { return events[i].CreatedAt.Before(events[j].CreatedAt) })
+	if len(events) > limit {
+		events = events[:limit]
+	}
+	return events, nil
+}
+
+func (s *StreamStore) MarkOutboxEventsDelivered(ids []string, deliveredAt time.Time, expiresAt int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, id := range ids {
+		stored, ok := s.outbox[id]
+		if !ok {
+			return fmt.Errorf("outbox event %s not found", id)
+		}
+		var event models.OutboxEvent
+		if err := dynamodbattribute.UnmarshalMap(stored, &event); err != nil {
+			return fmt.Errorf("failed to unmarshal outbox event: %w", err)
+		}
+		event.Status = ""
+		event.DeliveredAt = &deliveredAt
+		event.ExpiresAt = expiresAt
+		updated, err := dynamodbattribute.MarshalMap(&event)
+		if err != nil {
+			return fmt.Errorf("failed to marshal outbox event: %w", err)
+		}
+		s.outbox[id] = updated
+	}
+	return nil
+}
+
+func unmarshalStream(stored item) (*models.Stream, error) {
+	var stream models.Stream
+	if err := dynamodbattribute.UnmarshalMap(stored, &stream); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal stream: %w", err)
+	}
+	return &stream, nil
+}
+
+func unmarshalIncident(stored item) (*models.Incident, error) {
+	var incident models.Incident
+	if err := dynamodbattribute.UnmarshalMap(stored, &incident); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal incident: %w", err)
+	}
+	return &incident, nil
+}
+
+func unmarshalContentReview(stored item) (*models.ContentReview, error) {
+	var review models.ContentReview
+	if err := dynamodbattribute.UnmarshalMap(stored, &review); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal content review: %w", err)
+	}
+	return &review, nil
+}
+
+func auditSortKey(stored item) string {
+	if key, ok := stored["sort_key"]; ok && key.S != nil {
+		return *key.S
+	}
+	return ""
+}
+
+// statsBucket formats a bucket start the way the stats table sorts it
+func statsBucket(start time.Time) string {
+	return start.UTC().Format(time.RFC3339)
+}
@@ -0,0 +1,131 @@
+// services/stream-management-service/internal/repository/outbox.go
+package repository
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/services/stream-management-service/internal/models"
+)
+
+// outboxPendingIndex holds the outbox events that weren't delivered yet, oldest first.
+// Delivering an event removes its status, which takes it out of the index.
+const outboxPendingIndex = "status-created-index"
+
+// PutStreamWithEvents stores a stream and adds events about it to the outbox in one
+// transaction, either all of it is written or nothing is. With global tables that only holds
+// in the region it is written in, other regions may see the stream before its events.
+func (r *DynamoDBRepository) PutStreamWithEvents(ctx context.Context, stream *models.Stream, events []*models.OutboxEvent) error {
+	stream.SchemaVersion = r.streamMigrations.Latest()
+
+	item, err := dynamodbattribute.MarshalMap(stream)
+	if err != nil {
+		return fmt.Errorf("failed to marshal stream: %w", err)
+	}
+
+	// A transaction takes at most 100 items
+	if len(events) >= 100 {
+		return fmt.Errorf("too many outbox events: %d", len(events))
+	}
+
+	items := []*dynamodb.TransactWriteItem{
+		{Put: &dynamodb.Put{TableName: aws.String(r.tableName), Item: item}},
+	}
+	for _, event := range events {
+		eventItem, err := dynamodbattribute.MarshalMap(event)
+		if err != nil {
+			return fmt.Errorf("failed to marshal outbox event: %w", err)
+		}
+		items = append(items, &dynamodb.TransactWriteItem{
+			Put: &dynamodb.Put{
+				TableName:           aws.String(r.outboxTableName),
+				Item:                eventItem,
+				ConditionExpression: aws.String("attribute_not_exists(id)"),
+			},
+		})
+	}
+
+	_, err = r.client.TransactWriteItemsWithContext(ctx, &dynamodb.TransactWriteItemsInput{
+		TransactItems: items,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to write stream with outbox events: %w", err)
+	}
+
+	slog.Debug("✅ Stream and outbox events written to DynamoDB", "stream_id", stream.ID, "events", len(events))
+	return nil
+}
+
+// GetPendingOutboxEvents returns up to limit events that weren't delivered yet, oldest first
+func (r *DynamoDBRepository) GetPendingOutboxEvents(limit int) ([]*models.OutboxEvent, error) {
+	result, err := r.client.Query(&dynamodb.QueryInput{
+		TableName:              aws.String(r.outboxTableName),
+		IndexName:              aws.String(outboxPendingIndex),
+		KeyConditionExpression: aws.String("#status = :status"),
+		ExpressionAttributeNames: map[string]*string{
+			"#status": aws.String("status"),
+		},
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+			":status": {
+				S: aws.String(models.OutboxPending),
+			},
+		},
+		ScanIndexForward: aws.Bool(true),
+		Limit:            aws.Int64(int64(limit)),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to query pending outbox events: %w", err)
+	}
+
+	events := make([]*models.OutboxEvent, 0, len(result.Items))
+	for _, item := range result.Items {
+		var event models.OutboxEvent
+		if err := dynamodbattribute.UnmarshalMap(item, &event); err != nil {
+			slog.Warn("⚠️ Failed to unmarshal outbox event", "error", err)
+			continue
+		}
+		events = append(events, &event)
+	}
+
+	return events, nil
+}
+
+// MarkOutboxEventsDelivered takes events out of the pending index and has DynamoDB expire
+// them at expiresAt
+func (r *DynamoDBRepository) MarkOutboxEventsDelivered(ids []string, deliveredAt time.Time, expiresAt int64) error {
+	for _, id := range ids {
+		_, err := r.client.UpdateItem(&dynamodb.UpdateItemInput{
+			TableName: aws.String(r.outboxTableName),
+			Key: map[string]*dynamodb.AttributeValue{
+				"id": {
+					S: aws.String(id),
+				},
+			},
+			UpdateExpression:    aws.String("SET delivered_at = :delivered_at, expires_at = :expires_at REMOVE #status"),
+			ConditionExpression: aws.String("attribute_exists(id)"),
+			ExpressionAttributeNames: map[string]*string{
+				"#status": aws.String("status"),
+			},
+			ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+				":delivered_at": {
+					S: aws.String(deliveredAt.Format(time.RFC3339Nano)),
+				},
+				":expires_at": {
+					N: aws.String(strconv.FormatInt(expiresAt, 10)),
+				},
+			},
+		})
+		if err != nil {
+			return fmt.Errorf("failed to mark outbox event %s delivered: %w", id, err)
+		}
+	}
+
+	return nil
+}
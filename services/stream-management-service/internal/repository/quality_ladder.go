@@ -0,0 +1,180 @@
+// services/stream-management-service/internal/repository/quality_ladder.go
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/services/stream-management-service/internal/models"
+)
+
+const (
+	// qualityLadderPartition is the stats table partition quality ladders are stored in, one
+	// item per tier
+	qualityLadderPartition = "quality_ladder"
+	// userTierPartition is the stats table partition user tiers are stored in, one item per
+	// user that was given a tier
+	userTierPartition = "user_tier"
+)
+
+var ErrQualityLadderNotFound = errors.New("quality ladder not found")
+
+// SaveQualityLadder creates or replaces a tier's quality ladder
+func (r *DynamoDBRepository) SaveQualityLadder(ladder *models.QualityLadder) error {
+	item, err := dynamodbattribute.MarshalMap(ladder)
+	if err != nil {
+		return fmt.Errorf("failed to marshal quality ladder: %w", err)
+	}
+	item["granularity"] = &dynamodb.AttributeValue{S: aws.String(qualityLadderPartition)}
+
+	_, err = r.client.PutItem(&dynamodb.PutItemInput{
+		TableName: aws.String(r.statsTableName),
+		Item:      item,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to put quality ladder: %w", err)
+	}
+
+	return nil
+}
+
+// GetQualityLadder returns a tier's quality ladder, or ErrQualityLadderNotFound
+func (r *DynamoDBRepository) GetQualityLadder(tier string) (*models.QualityLadder, error) {
+	result, err := r.client.GetItem(&dynamodb.GetItemInput{
+		TableName: aws.String(r.statsTableName),
+		Key: map[string]*dynamodb.AttributeValue{
+			"granularity": {S: aws.String(qualityLadderPartition)},
+			"bucket":      {S: aws.String(tier)},
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get quality ladder: %w", err)
+	}
+	if result.Item == nil {
+		return nil, ErrQualityLadderNotFound
+	}
+
+	var ladder models.QualityLadder
+	if err := dynamodbattribute.UnmarshalMap(result.Item, &ladder); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal quality ladder: %w", err)
+	}
+
+	return &ladder, nil
+}
+
+// GetQualityLadders returns the stored quality ladders by tier
+func (r *DynamoDBRepository) GetQualityLadders() ([]*models.QualityLadder, error) {
+	var ladders []*models.QualityLadder
+	err := r.client.QueryPages(&dynamodb.QueryInput{
+		TableName:              aws.String(r.statsTableName),
+		KeyConditionExpression: aws.String("granularity = :partition"),
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+			":partition": {S: aws.String(qualityLadderPartition)},
+		},
+	}, func(page *dynamodb.QueryOutput, lastPage bool) bool {
+		for _, item := range page.Items {
+			var ladder models.QualityLadder
+			if err := dynamodbattribute.UnmarshalMap(item, &ladder); err != nil {
+				slog.Warn("⚠️ Failed to unmarshal quality ladder", "error", err)
+				continue
+			}
+			ladders = append(ladders, &ladder)
+		}
+		return true
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to query quality ladders: %w", err)
+	}
+
+	return ladders, nil
+}
+
+// DeleteQualityLadder deletes a tier's quality ladder, or returns ErrQualityLadderNotFound
+func (r *DynamoDBRepository) DeleteQualityLadder(tier string) error {
+	_, err := r.client.DeleteItem(&dynamodb.DeleteItemInput{
+		TableName: aws.String(r.statsTableName),
+		Key: map[string]*dynamodb.AttributeValue{
+			"granularity": {S: aws.String(qualityLadderPartition)},
+			"bucket":      {S: aws.String(tier)},
+		},
+		ConditionExpression: aws.String("attribute_exists(#bucket)"),
+		ExpressionAttributeNames: map[string]*string{
+			"#bucket": aws.String("bucket"),
+		},
+	})
+	if err != nil {
+		if aerr, ok := err.(awserr.Error); ok && aerr.Code() == dynamodb.ErrCodeConditionalCheckFailedException {
+			return ErrQualityLadderNotFound
+		}
+		return fmt.Errorf("failed to delete quality ladder: %w", err)
+	}
+
+	return nil
+}
+
+// SaveUserTier creates or replaces the tier a user's streams are transcoded for
+func (r *DynamoDBRepository) SaveUserTier(userTier *models.UserTier) error {
+	item, err := dynamodbattribute.MarshalMap(userTier)
+	if err != nil {
+		return fmt.Errorf("failed to marshal user tier: %w", err)
+	}
+	item["granularity"] = &dynamodb.AttributeValue{S: aws.String(userTierPartition)}
+	item["bucket"] = &dynamodb.AttributeValue{S: aws.String(strconv.FormatInt(userTier.UserID, 10))}
+
+	_, err = r.client.PutItem(&dynamodb.PutItemInput{
+		TableName: aws.String(r.statsTableName),
+		Item:      item,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to put user tier: %w", err)
+	}
+
+	return nil
+}
+
+// GetUserTier returns a user's tier. Users who weren't given one get the default tier.
+func (r *DynamoDBRepository) GetUserTier(userID int64) (*models.UserTier, error) {
+	result, err := r.client.GetItem(&dynamodb.GetItemInput{
+		TableName: aws.String(r.statsTableName),
+		Key: map[string]*dynamodb.AttributeValue{
+			"granularity": {S: aws.String(userTierPartition)},
+			"bucket":      {S: aws.String(strconv.FormatInt(userID, 10))},
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user tier: %w", err)
+	}
+
+	userTier := &models.UserTier{UserID: userID, Tier: models.QualityTierDefault}
+	if result.Item == nil {
+		return userTier, nil
+	}
+	if err := dynamodbattribute.UnmarshalMap(result.Item, userTier); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal user tier: %w", err)
+	}
+
+	return userTier, nil
+}
+
+// DeleteUserTier puts a user back on the default tier
+func (r *DynamoDBRepository) DeleteUserTier(userID int64) error {
+	_, err := r.client.DeleteItem(&dynamodb.DeleteItemInput{
+		TableName: aws.String(r.statsTableName),
+		Key: map[string]*dynamodb.AttributeValue{
+			"granularity": {S: aws.String(userTierPartition)},
+			"bucket":      {S: aws.String(strconv.FormatInt(userID, 10))},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to delete user tier: %w", err)
+	}
+
+	return nil
+}
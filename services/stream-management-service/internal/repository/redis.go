@@ -2,28 +2,69 @@
 package repository
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
 	"fmt"
+	"strconv"
 	"time"
 
 	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/services/stream-management-service/internal/config"
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/services/stream-management-service/internal/models"
 	"github.com/go-redis/redis/v8"
 	"golang.org/x/net/context"
 )
 
 type RedisRepository struct {
-	client *redis.Client
+	client  redis.UniversalClient
+	cluster bool
 }
 
-func NewRedisRepository(cfg *config.Config) *RedisRepository {
-	rdb := redis.NewClient(&redis.Options{
-		Addr:     cfg.RedisAddr,
-		Password: cfg.RedisPassword,
-		DB:       cfg.RedisDB,
-	})
+func NewRedisRepository(cfg *config.Config) (*RedisRepository, error) {
+	rdb, err := newRedisClient(cfg)
+	if err != nil {
+		return nil, err
+	}
+	if injector := cfg.Faults(); injector != nil {
+		rdb.AddHook(injector.RedisHook())
+	}
 
 	return &RedisRepository{
-		client: rdb,
+		client:  rdb,
+		cluster: cfg.RedisMode == RedisModeCluster,
+	}, nil
+}
+
+// Ping checks that Redis is reachable
+func (r *RedisRepository) Ping() error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := r.client.Ping(ctx).Err(); err != nil {
+		return fmt.Errorf("failed to ping redis: %w", err)
+	}
+
+	return nil
+}
+
+// HealthCheck pings Redis, bounded by ctx. In cluster mode every master has to answer, a
+// slot without a master fails the commands of its keys.
+func (r *RedisRepository) HealthCheck(ctx context.Context) error {
+	if cluster, ok := r.client.(*redis.ClusterClient); ok {
+		err := cluster.ForEachMaster(ctx, func(ctx context.Context, master *redis.Client) error {
+			return master.Ping(ctx).Err()
+		})
+		if err != nil {
+			return fmt.Errorf("failed to ping redis: %w", err)
+		}
+		return nil
+	}
+
+	if err := r.client.Ping(ctx).Err(); err != nil {
+		return fmt.Errorf("failed to ping redis: %w", err)
 	}
+
+	return nil
 }
 
 func (r *RedisRepository) SetStreamData(streamID, data string, expiration time.Duration) error {
@@ -62,6 +103,34 @@ func (r *RedisRepository) SetStreamSession(streamKey, sessionData string, expira
 	return nil
 }
 
+// GetStreamDataBatch returns the cached data of the given streams in one round trip. Streams
+// that aren't cached are left out. The keys are read with pipelined GETs rather than MGET,
+// which a cluster refuses for keys on different nodes.
+func (r *RedisRepository) GetStreamDataBatch(streamIDs []string) (map[string]string, error) {
+	ctx := context.Background()
+	data := make(map[string]string, len(streamIDs))
+	if len(streamIDs) == 0 {
+		return data, nil
+	}
+
+	pipe := r.client.Pipeline()
+	cmds := make([]*redis.StringCmd, len(streamIDs))
+	for i, id := range streamIDs {
+		cmds[i] = pipe.Get(ctx, fmt.Sprintf("stream:%s", id))
+	}
+	if _, err := pipe.Exec(ctx); err != nil && err != redis.Nil {
+		return nil, fmt.Errorf("failed to get stream data: %w", err)
+	}
+
+	for i, cmd := range cmds {
+		if raw, err := cmd.Result(); err == nil {
+			data[streamIDs[i]] = raw
+		}
+	}
+
+	return data, nil
+}
+
 func (r *RedisRepository) GetStreamSession(streamKey string) (string, error) {
 	ctx := context.Background()
 	key := fmt.Sprintf("session:%s", streamKey)
@@ -85,3 +154,1368 @@ func (r *RedisRepository) DeleteStreamSession(streamKey string) error {
 
 	return nil
 }
+
+// SetPlaybackStreamKey remembers which stream key a playback ID plays
+func (r *RedisRepository) SetPlaybackStreamKey(playbackID, streamKey string, expiration time.Duration) error {
+	ctx := context.Background()
+	key := fmt.Sprintf("playback:%s", playbackID)
+
+	if err := r.client.Set(ctx, key, streamKey, expiration).Err(); err != nil {
+		return fmt.Errorf("failed to set playback stream key: %w", err)
+	}
+	return nil
+}
+
+func (r *RedisRepository) GetPlaybackStreamKey(playbackID string) (string, error) {
+	ctx := context.Background()
+	key := fmt.Sprintf("playback:%s", playbackID)
+
+	streamKey, err := r.client.Get(ctx, key).Result()
+	if err != nil {
+		return "", fmt.Errorf("failed to get playback stream key: %w", err)
+	}
+	return streamKey, nil
+}
+
+// PushHealthSample adds a health sample to the front of a stream's rolling window
+func (r *RedisRepository) PushHealthSample(streamID, sample string, windowSize int, expiration time.Duration) error {
+	ctx := context.Background()
+	key := fmt.Sprintf("health:%s", streamID)
+
+	pipe := r.client.TxPipeline()
+	pipe.LPush(ctx, key, sample)
+	pipe.LTrim(ctx, key, 0, int64(windowSize-1))
+	pipe.Expire(ctx, key, expiration)
+
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("failed to push health sample: %w", err)
+	}
+
+	return nil
+}
+
+// GetHealthSamples returns a stream's health samples, newest first
+func (r *RedisRepository) GetHealthSamples(streamID string) ([]string, error) {
+	ctx := context.Background()
+	key := fmt.Sprintf("health:%s", streamID)
+
+	samples, err := r.client.LRange(ctx, key, 0, -1).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get health samples: %w", err)
+	}
+
+	return samples, nil
+}
+
+// AddReconnecting schedules a disconnected stream to be ended at the deadline
+func (r *RedisRepository) AddReconnecting(streamKey string, deadline time.Time) error {
+	ctx := context.Background()
+
+	err := r.client.ZAdd(ctx, "reconnecting", &redis.Z{
+		Score:  float64(deadline.Unix()),
+		Member: streamKey,
+	}).Err()
+	if err != nil {
+		return fmt.Errorf("failed to add reconnecting stream: %w", err)
+	}
+
+	return nil
+}
+
+// RemoveReconnecting removes a stream from the reconnect schedule. It returns false if the
+// stream wasn't scheduled, e.g. because another instance already claimed it.
+func (r *RedisRepository) RemoveReconnecting(streamKey string) (bool, error) {
+	ctx := context.Background()
+
+	removed, err := r.client.ZRem(ctx, "reconnecting", streamKey).Result()
+	if err != nil {
+		return false, fmt.Errorf("failed to remove reconnecting stream: %w", err)
+	}
+
+	return removed > 0, nil
+}
+
+// GetExpiredReconnecting returns the stream keys whose reconnect deadline has passed
+func (r *RedisRepository) GetExpiredReconnecting(now time.Time) ([]string, error) {
+	ctx := context.Background()
+
+	keys, err := r.client.ZRangeByScore(ctx, "reconnecting", &redis.ZRangeBy{
+		Min: "-inf",
+		Max: strconv.FormatInt(now.Unix(), 10),
+	}).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get expired reconnecting streams: %w", err)
+	}
+
+	return keys, nil
+}
+
+// SchedulePremiere queues a VOD premiere to start at the given time
+func (r *RedisRepository) SchedulePremiere(vodID string, at time.Time) error {
+	ctx := context.Background()
+
+	err := r.client.ZAdd(ctx, "premieres", &redis.Z{
+		Score:  float64(at.Unix()),
+		Member: vodID,
+	}).Err()
+	if err != nil {
+		return fmt.Errorf("failed to schedule premiere: %w", err)
+	}
+
+	return nil
+}
+
+// UnschedulePremiere removes a premiere from the schedule. It returns false if it wasn't
+// scheduled, e.g. because another instance already started it.
+func (r *RedisRepository) UnschedulePremiere(vodID string) (bool, error) {
+	ctx := context.Background()
+
+	removed, err := r.client.ZRem(ctx, "premieres", vodID).Result()
+	if err != nil {
+		return false, fmt.Errorf("failed to unschedule premiere: %w", err)
+	}
+
+	return removed > 0, nil
+}
+
+// GetDuePremieres returns the VODs whose premiere should have started by now
+func (r *RedisRepository) GetDuePremieres(now time.Time) ([]string, error) {
+	ctx := context.Background()
+
+	vodIDs, err := r.client.ZRangeByScore(ctx, "premieres", &redis.ZRangeBy{
+		Min: "-inf",
+		Max: strconv.FormatInt(now.Unix(), 10),
+	}).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get due premieres: %w", err)
+	}
+
+	return vodIDs, nil
+}
+
+// IncrementRateWindow counts a request in a fixed rate limit window and returns the count so far
+func (r *RedisRepository) IncrementRateWindow(key string, window time.Duration) (int64, error) {
+	ctx := context.Background()
+
+	pipe := r.client.TxPipeline()
+	count := pipe.Incr(ctx, key)
+	pipe.Expire(ctx, key, window)
+
+	if _, err := pipe.Exec(ctx); err != nil {
+		return 0, fmt.Errorf("failed to increment rate window: %w", err)
+	}
+
+	return count.Val(), nil
+}
+
+// takeTokenScript refills a token bucket for the time since it was last used and takes one
+// token if there is one. It returns whether the token was taken, the tokens left and the
+// milliseconds until the next token.
+var takeTokenScript = redis.NewScript(`
+local rate = tonumber(ARGV[1])
+local burst = tonumber(ARGV[2])
+local now = tonumber(ARGV[3])
+
+local bucket = redis.call('HMGET', KEYS[1], 'tokens', 'updated_at')
+local tokens = tonumber(bucket[1]) or burst
+local updated = tonumber(bucket[2]) or now
+tokens = math.min(burst, tokens + math.max(0, now - updated) * rate)
+
+local allowed = 0
+local wait = 0
+if tokens >= 1 then
+	tokens = tokens - 1
+	allowed = 1
+else
+	wait = math.ceil((1 - tokens) / rate)
+end
+
+redis.call('HSET', KEYS[1], 'tokens', tostring(tokens), 'updated_at', now)
+redis.call('PEXPIRE', KEYS[1], math.ceil(burst / rate) + 1000)
+return {allowed, math.floor(tokens), wait}
+`)
+
+// TakeRateToken takes a request from a token bucket refilled with ratePerMinute tokens a minute
+// and holding up to burst. When the bucket is empty it returns how long until the next token.
+func (r *RedisRepository) TakeRateToken(key string, ratePerMinute, burst int) (bool, int64, time.Duration, error) {
+	ctx := context.Background()
+
+	perMilli := float64(ratePerMinute) / float64(time.Minute.Milliseconds())
+	result, err := takeTokenScript.Run(ctx, r.client, []string{"ratelimit:" + key},
+		perMilli, burst, time.Now().UnixMilli()).Int64Slice()
+	if err != nil {
+		return false, 0, 0, fmt.Errorf("failed to take rate limit token: %w", err)
+	}
+
+	return result[0] == 1, result[1], time.Duration(result[2]) * time.Millisecond, nil
+}
+
+// IncrementAPIKeyUsage counts a request against a key's monthly usage and returns the month's total
+func (r *RedisRepository) IncrementAPIKeyUsage(keyID, month, day string, delta int64) (int64, error) {
+	ctx := context.Background()
+	key := fmt.Sprintf("apikey:usage:%s:%s", keyID, month)
+
+	pipe := r.client.TxPipeline()
+	total := pipe.HIncrBy(ctx, key, "total", delta)
+	pipe.HIncrBy(ctx, key, day, delta)
+	pipe.Expire(ctx, key, 400*24*time.Hour) // keep a year of reports
+
+	if _, err := pipe.Exec(ctx); err != nil {
+		return 0, fmt.Errorf("failed to increment api key usage: %w", err)
+	}
+
+	return total.Val(), nil
+}
+
+// GetAPIKeyUsage returns a key's usage counters for a month
+func (r *RedisRepository) GetAPIKeyUsage(keyID, month string) (map[string]string, error) {
+	ctx := context.Background()
+	key := fmt.Sprintf("apikey:usage:%s:%s", keyID, month)
+
+	usage, err := r.client.HGetAll(ctx, key).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get api key usage: %w", err)
+	}
+
+	return usage, nil
+}
+
+// SetFollow records that a viewer follows (or stopped following) a channel and updates the
+// channel's follower count. A negative count means the event didn't carry one.
+func (r *RedisRepository) SetFollow(viewerID, channelID int64, following bool, followerCount int64, ttl time.Duration) error {
+	ctx := context.Background()
+	key := fmt.Sprintf("following:%d", viewerID)
+	channel := strconv.FormatInt(channelID, 10)
+
+	pipe := r.client.TxPipeline()
+	if following {
+		pipe.SAdd(ctx, key, channel)
+	} else {
+		pipe.SRem(ctx, key, channel)
+	}
+	pipe.Expire(ctx, key, ttl)
+
+	switch {
+	case followerCount >= 0:
+		pipe.HSet(ctx, "follower_counts", channel, followerCount)
+	case following:
+		pipe.HIncrBy(ctx, "follower_counts", channel, 1)
+	default:
+		pipe.HIncrBy(ctx, "follower_counts", channel, -1)
+	}
+
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("failed to set follow: %w", err)
+	}
+
+	return nil
+}
+
+// PushChannelEvent adds an event to the front of a channel's recent event feed
+func (r *RedisRepository) PushChannelEvent(channelID int64, event string, feedSize int, expiration time.Duration) error {
+	ctx := context.Background()
+	key := fmt.Sprintf("channel_events:%d", channelID)
+
+	pipe := r.client.TxPipeline()
+	pipe.LPush(ctx, key, event)
+	pipe.LTrim(ctx, key, 0, int64(feedSize-1))
+	pipe.Expire(ctx, key, expiration)
+
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("failed to push channel event: %w", err)
+	}
+
+	return nil
+}
+
+// GetChannelEvents returns a channel's recent events, newest first
+func (r *RedisRepository) GetChannelEvents(channelID int64) ([]string, error) {
+	ctx := context.Background()
+	key := fmt.Sprintf("channel_events:%d", channelID)
+
+	events, err := r.client.LRange(ctx, key, 0, -1).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get channel events: %w", err)
+	}
+
+	return events, nil
+}
+
+// RecordFollow adds a follow to a channel's follow history and drops follows older than
+// retention
+func (r *RedisRepository) RecordFollow(channelID, followerID int64, at time.Time, retention time.Duration) error {
+	ctx := context.Background()
+	key := fmt.Sprintf("follows:%d", channelID)
+
+	pipe := r.client.TxPipeline()
+	pipe.ZAdd(ctx, key, &redis.Z{Score: float64(at.Unix()), Member: strconv.FormatInt(followerID, 10)})
+	pipe.ZRemRangeByScore(ctx, key, "-inf", "("+strconv.FormatInt(at.Add(-retention).Unix(), 10))
+	pipe.Expire(ctx, key, retention)
+
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("failed to record follow: %w", err)
+	}
+
+	return nil
+}
+
+// CountFollowsBetween returns how many viewers of a channel's follow history followed it
+// between from and to
+func (r *RedisRepository) CountFollowsBetween(channelID int64, from, to time.Time) (int64, error) {
+	ctx := context.Background()
+
+	count, err := r.client.ZCount(ctx, fmt.Sprintf("follows:%d", channelID),
+		strconv.FormatInt(from.Unix(), 10), strconv.FormatInt(to.Unix(), 10)).Result()
+	if err != nil {
+		return 0, fmt.Errorf("failed to count follows: %w", err)
+	}
+
+	return count, nil
+}
+
+// GetFollowerCounts returns the cached follower counts of the given channels. Channels
+// without a cached count are left out.
+func (r *RedisRepository) GetFollowerCounts(channelIDs []int64) (map[int64]int64, error) {
+	ctx := context.Background()
+	counts := make(map[int64]int64)
+	if len(channelIDs) == 0 {
+		return counts, nil
+	}
+
+	fields := make([]string, len(channelIDs))
+	for i, id := range channelIDs {
+		fields[i] = strconv.FormatInt(id, 10)
+	}
+
+	values, err := r.client.HMGet(ctx, "follower_counts", fields...).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get follower counts: %w", err)
+	}
+
+	for i, value := range values {
+		raw, ok := value.(string)
+		if !ok {
+			continue
+		}
+		if count, err := strconv.ParseInt(raw, 10, 64); err == nil {
+			counts[channelIDs[i]] = count
+		}
+	}
+
+	return counts, nil
+}
+
+// GetFollowedChannels returns the channels a viewer follows
+func (r *RedisRepository) GetFollowedChannels(viewerID int64) (map[int64]bool, error) {
+	ctx := context.Background()
+
+	members, err := r.client.SMembers(ctx, fmt.Sprintf("following:%d", viewerID)).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get followed channels: %w", err)
+	}
+
+	followed := make(map[int64]bool, len(members))
+	for _, member := range members {
+		if id, err := strconv.ParseInt(member, 10, 64); err == nil {
+			followed[id] = true
+		}
+	}
+
+	return followed, nil
+}
+
+// CacheViewerToken remembers that a viewer's token was validated by the user service
+func (r *RedisRepository) CacheViewerToken(tokenHash string, viewerID int64, ttl time.Duration) error {
+	ctx := context.Background()
+
+	if err := r.client.Set(ctx, "viewer_token:"+tokenHash, viewerID, ttl).Err(); err != nil {
+		return fmt.Errorf("failed to cache viewer token: %w", err)
+	}
+
+	return nil
+}
+
+// GetViewerToken returns the viewer a cached token belongs to, or 0 if it isn't cached
+func (r *RedisRepository) GetViewerToken(tokenHash string) (int64, error) {
+	ctx := context.Background()
+
+	viewerID, err := r.client.Get(ctx, "viewer_token:"+tokenHash).Int64()
+	if err == redis.Nil {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to get viewer token: %w", err)
+	}
+
+	return viewerID, nil
+}
+
+// ErrSquadNotFound is returned for squads that don't exist or have expired
+var ErrSquadNotFound = errors.New("squad not found")
+
+// CreateSquad stores a new squad
+func (r *RedisRepository) CreateSquad(squadID, data string, ttl time.Duration) error {
+	ctx := context.Background()
+
+	if err := r.client.Set(ctx, "squad:"+squadID, data, ttl).Err(); err != nil {
+		return fmt.Errorf("failed to create squad: %w", err)
+	}
+
+	return nil
+}
+
+// GetSquad returns a stored squad
+func (r *RedisRepository) GetSquad(squadID string) (string, error) {
+	ctx := context.Background()
+
+	data, err := r.client.Get(ctx, "squad:"+squadID).Result()
+	if err == redis.Nil {
+		return "", ErrSquadNotFound
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to get squad: %w", err)
+	}
+
+	return data, nil
+}
+
+// UpdateSquad applies update to a squad atomically, retrying if another instance changed it
+// in the meantime. Returning "" from update deletes the squad.
+func (r *RedisRepository) UpdateSquad(squadID string, ttl time.Duration, update func(data string) (string, error)) error {
+	ctx := context.Background()
+	key := "squad:" + squadID
+
+	for attempt := 0; attempt < 5; attempt++ {
+		err := r.client.Watch(ctx, func(tx *redis.Tx) error {
+			data, err := tx.Get(ctx, key).Result()
+			if err == redis.Nil {
+				return ErrSquadNotFound
+			}
+			if err != nil {
+				return err
+			}
+
+			updated, err := update(data)
+			if err != nil {
+				return err
+			}
+
+			_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+				if updated == "" {
+					pipe.Del(ctx, key)
+				} else {
+					pipe.Set(ctx, key, updated, ttl)
+				}
+				return nil
+			})
+			return err
+		}, key)
+
+		if err != redis.TxFailedErr {
+			return err
+		}
+	}
+
+	return fmt.Errorf("failed to update squad %s: too many concurrent changes", squadID)
+}
+
+// ClaimStreamSquad records that a stream belongs to a squad. It fails if the stream is
+// already in another squad.
+func (r *RedisRepository) ClaimStreamSquad(streamID, squadID string, ttl time.Duration) (bool, error) {
+	ctx := context.Background()
+
+	claimed, err := r.client.SetNX(ctx, "stream_squad:"+streamID, squadID, ttl).Result()
+	if err != nil {
+		return false, fmt.Errorf("failed to claim stream for squad: %w", err)
+	}
+
+	return claimed, nil
+}
+
+// GetStreamSquad returns the squad a stream belongs to, or "" if it's not in one
+func (r *RedisRepository) GetStreamSquad(streamID string) (string, error) {
+	ctx := context.Background()
+
+	squadID, err := r.client.Get(ctx, "stream_squad:"+streamID).Result()
+	if err == redis.Nil {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to get stream squad: %w", err)
+	}
+
+	return squadID, nil
+}
+
+// ReleaseStreamSquad removes a stream from its squad
+func (r *RedisRepository) ReleaseStreamSquad(streamID string) error {
+	ctx := context.Background()
+
+	if err := r.client.Del(ctx, "stream_squad:"+streamID).Err(); err != nil {
+		return fmt.Errorf("failed to release stream squad: %w", err)
+	}
+
+	return nil
+}
+
+// ClaimStatsSample marks a platform stats sample as taken, so only one replica records it
+func (r *RedisRepository) ClaimStatsSample(sample string, ttl time.Duration) (bool, error) {
+	ctx := context.Background()
+
+	claimed, err := r.client.SetNX(ctx, "stats_sample:"+sample, "", ttl).Result()
+	if err != nil {
+		return false, fmt.Errorf("failed to claim stats sample: %w", err)
+	}
+
+	return claimed, nil
+}
+
+// ClaimJobRun makes this replica the one running the run of a scheduled job due at
+// scheduledAt, replicas waking up for it together would otherwise all run it
+func (r *RedisRepository) ClaimJobRun(job string, scheduledAt time.Time, ttl time.Duration) (bool, error) {
+	ctx := context.Background()
+
+	key := fmt.Sprintf("job_run:%s:%d", job, scheduledAt.Unix())
+	claimed, err := r.client.SetNX(ctx, key, "", ttl).Result()
+	if err != nil {
+		return false, fmt.Errorf("failed to claim job run: %w", err)
+	}
+
+	return claimed, nil
+}
+
+// SetLiveDirectory caches the live directory, every live stream, for all replicas
+func (r *RedisRepository) SetLiveDirectory(data string, expiration time.Duration) error {
+	ctx := context.Background()
+
+	if err := r.client.Set(ctx, "live_directory", data, expiration).Err(); err != nil {
+		return fmt.Errorf("failed to set live directory: %w", err)
+	}
+
+	return nil
+}
+
+func (r *RedisRepository) GetLiveDirectory() (string, error) {
+	ctx := context.Background()
+
+	data, err := r.client.Get(ctx, "live_directory").Result()
+	if err != nil {
+		return "", fmt.Errorf("failed to get live directory: %w", err)
+	}
+
+	return data, nil
+}
+
+// ClaimLiveDirectoryRefresh makes this replica the one reloading a stale live directory for
+// ttl, so replicas serving it at the same time don't all query DynamoDB
+func (r *RedisRepository) ClaimLiveDirectoryRefresh(ttl time.Duration) (bool, error) {
+	ctx := context.Background()
+
+	claimed, err := r.client.SetNX(ctx, "live_directory_refresh", "", ttl).Result()
+	if err != nil {
+		return false, fmt.Errorf("failed to claim live directory refresh: %w", err)
+	}
+
+	return claimed, nil
+}
+
+// ClaimRecordingRetry makes this replica the one retrying failed recording uploads for ttl, so
+// a recording isn't uploaded by two replicas at once
+func (r *RedisRepository) ClaimRecordingRetry(ttl time.Duration) (bool, error) {
+	ctx := context.Background()
+
+	claimed, err := r.client.SetNX(ctx, "recording_retry", "", ttl).Result()
+	if err != nil {
+		return false, fmt.Errorf("failed to claim recording retry: %w", err)
+	}
+
+	return claimed, nil
+}
+
+// ClaimStreamLimit makes this replica the one stopping a stream that went over its limits
+func (r *RedisRepository) ClaimStreamLimit(streamID string, ttl time.Duration) (bool, error) {
+	ctx := context.Background()
+
+	claimed, err := r.client.SetNX(ctx, "stream_limit:"+streamID, "", ttl).Result()
+	if err != nil {
+		return false, fmt.Errorf("failed to claim stream limit: %w", err)
+	}
+
+	return claimed, nil
+}
+
+// ClaimCallback marks a media server callback as being handled. It fails if the callback was
+// already claimed, i.e. this is a retry.
+func (r *RedisRepository) ClaimCallback(key string, ttl time.Duration) (bool, error) {
+	ctx := context.Background()
+
+	claimed, err := r.client.SetNX(ctx, "rtmp_callback:"+key, "", ttl).Result()
+	if err != nil {
+		return false, fmt.Errorf("failed to claim callback: %w", err)
+	}
+
+	return claimed, nil
+}
+
+// GetCallbackResult returns the stored response to a callback, or "" while it's still being handled
+func (r *RedisRepository) GetCallbackResult(key string) (string, error) {
+	ctx := context.Background()
+
+	result, err := r.client.Get(ctx, "rtmp_callback:"+key).Result()
+	if err == redis.Nil {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to get callback result: %w", err)
+	}
+
+	return result, nil
+}
+
+// SetCallbackResult stores the response to a callback so retries get the same answer
+func (r *RedisRepository) SetCallbackResult(key, result string, ttl time.Duration) error {
+	ctx := context.Background()
+
+	if err := r.client.Set(ctx, "rtmp_callback:"+key, result, ttl).Err(); err != nil {
+		return fmt.Errorf("failed to store callback result: %w", err)
+	}
+
+	return nil
+}
+
+// ReleaseCallback forgets a callback that failed so a retry handles it again
+func (r *RedisRepository) ReleaseCallback(key string) error {
+	ctx := context.Background()
+
+	if err := r.client.Del(ctx, "rtmp_callback:"+key).Err(); err != nil {
+		return fmt.Errorf("failed to release callback: %w", err)
+	}
+
+	return nil
+}
+
+// ClaimHealthAlert reports whether an alert of a kind may be sent for a stream, at most once per cooldown
+func (r *RedisRepository) ClaimHealthAlert(streamID, kind string, cooldown time.Duration) (bool, error) {
+	ctx := context.Background()
+
+	claimed, err := r.client.SetNX(ctx, "health_alert:"+streamID+":"+kind, time.Now().Unix(), cooldown).Result()
+	if err != nil {
+		return false, fmt.Errorf("failed to claim health alert: %w", err)
+	}
+
+	return claimed, nil
+}
+
+// SetLatencyMarker remembers when a latency marker was embedded in a stream
+func (r *RedisRepository) SetLatencyMarker(streamID, markerID string, ingestAtMs int64, ttl time.Duration) error {
+	ctx := context.Background()
+
+	if err := r.client.Set(ctx, "latency_marker:"+streamID+":"+markerID, ingestAtMs, ttl).Err(); err != nil {
+		return fmt.Errorf("failed to set latency marker: %w", err)
+	}
+
+	return nil
+}
+
+// GetLatencyMarker returns when a marker was embedded (unix ms), or 0 if it's unknown or expired
+func (r *RedisRepository) GetLatencyMarker(streamID, markerID string) (int64, error) {
+	ctx := context.Background()
+
+	ingestAt, err := r.client.Get(ctx, "latency_marker:"+streamID+":"+markerID).Int64()
+	if err == redis.Nil {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to get latency marker: %w", err)
+	}
+
+	return ingestAt, nil
+}
+
+// PushLatencySample adds a latency sample to the front of a stream's rolling window
+func (r *RedisRepository) PushLatencySample(streamID, sample string, windowSize int, expiration time.Duration) error {
+	ctx := context.Background()
+	key := fmt.Sprintf("latency:%s", streamID)
+
+	pipe := r.client.TxPipeline()
+	pipe.LPush(ctx, key, sample)
+	pipe.LTrim(ctx, key, 0, int64(windowSize-1))
+	pipe.Expire(ctx, key, expiration)
+
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("failed to push latency sample: %w", err)
+	}
+
+	return nil
+}
+
+// GetLatencySamples returns a stream's latency samples, newest first
+func (r *RedisRepository) GetLatencySamples(streamID string) ([]string, error) {
+	ctx := context.Background()
+	key := fmt.Sprintf("latency:%s", streamID)
+
+	samples, err := r.client.LRange(ctx, key, 0, -1).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get latency samples: %w", err)
+	}
+
+	return samples, nil
+}
+
+// RevokeStreamKey adds a generated stream key to the revocation list. A ttl of 0 keeps it
+// there for good, for keys that never expire.
+func (r *RedisRepository) RevokeStreamKey(keyID string, ttl time.Duration) error {
+	ctx := context.Background()
+
+	if err := r.client.Set(ctx, "stream_key_revoked:"+keyID, time.Now().Unix(), ttl).Err(); err != nil {
+		return fmt.Errorf("failed to revoke stream key: %w", err)
+	}
+
+	return nil
+}
+
+// IsStreamKeyRevoked reports whether a generated stream key is on the revocation list
+func (r *RedisRepository) IsStreamKeyRevoked(keyID string) (bool, error) {
+	ctx := context.Background()
+
+	count, err := r.client.Exists(ctx, "stream_key_revoked:"+keyID).Result()
+	if err != nil {
+		return false, fmt.Errorf("failed to check stream key revocation: %w", err)
+	}
+
+	return count > 0, nil
+}
+
+// GetStreamKeyValidation returns the cached user service result for a stream key, or false
+// when there is none
+func (r *RedisRepository) GetStreamKeyValidation(streamKey string) (string, bool, error) {
+	ctx := context.Background()
+
+	value, err := r.client.Get(ctx, streamKeyValidationKey(streamKey)).Result()
+	if err == redis.Nil {
+		return "", false, nil
+	}
+	if err != nil {
+		return "", false, fmt.Errorf("failed to get stream key validation: %w", err)
+	}
+
+	return value, true, nil
+}
+
+// SetStreamKeyValidation caches the user service result for a stream key
+func (r *RedisRepository) SetStreamKeyValidation(streamKey, value string, ttl time.Duration) error {
+	ctx := context.Background()
+
+	if err := r.client.Set(ctx, streamKeyValidationKey(streamKey), value, ttl).Err(); err != nil {
+		return fmt.Errorf("failed to cache stream key validation: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteStreamKeyValidation drops the cached result for a stream key
+func (r *RedisRepository) DeleteStreamKeyValidation(streamKey string) error {
+	ctx := context.Background()
+
+	if err := r.client.Del(ctx, streamKeyValidationKey(streamKey)).Err(); err != nil {
+		return fmt.Errorf("failed to delete stream key validation: %w", err)
+	}
+
+	return nil
+}
+
+// streamKeyValidationKey hashes the stream key so keys aren't readable from Redis
+func streamKeyValidationKey(streamKey string) string {
+	sum := sha256.Sum256([]byte(streamKey))
+	return "stream_key_validation:" + hex.EncodeToString(sum[:])
+}
+
+// RecordViewerHeartbeat marks a viewer as watching a stream and counts them as a unique viewer
+func (r *RedisRepository) RecordViewerHeartbeat(streamID, viewerID string, at time.Time, expiration time.Duration) error {
+	ctx := context.Background()
+	watchingKey := r.viewerKey("viewers:", streamID)
+	uniqueKey := fmt.Sprintf("viewers_unique:%s", streamID)
+
+	pipe := r.client.TxPipeline()
+	pipe.ZAdd(ctx, watchingKey, &redis.Z{Score: float64(at.Unix()), Member: viewerID})
+	pipe.Expire(ctx, watchingKey, expiration)
+	pipe.PFAdd(ctx, uniqueKey, viewerID)
+	pipe.Expire(ctx, uniqueKey, expiration)
+
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("failed to record viewer heartbeat: %w", err)
+	}
+
+	return nil
+}
+
+// AddEdgeViewer counts a viewer playing from the media server. Players there don't send
+// heartbeats, the connection is counted as watching until RemoveEdgeViewer.
+func (r *RedisRepository) AddEdgeViewer(streamID, connectionID, viewerID string, at time.Time, expiration time.Duration) error {
+	ctx := context.Background()
+	edgeKey := r.viewerKey("edge_viewers:", streamID)
+	watchingKey := r.viewerKey("viewers:", streamID)
+	uniqueKey := fmt.Sprintf("viewers_unique:%s", streamID)
+
+	pipe := r.client.TxPipeline()
+	pipe.SAdd(ctx, edgeKey, connectionID)
+	pipe.Expire(ctx, edgeKey, expiration)
+	pipe.ZAdd(ctx, watchingKey, &redis.Z{Score: float64(at.Unix()), Member: connectionID})
+	pipe.Expire(ctx, watchingKey, expiration)
+	pipe.PFAdd(ctx, uniqueKey, viewerID)
+	pipe.Expire(ctx, uniqueKey, expiration)
+
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("failed to add edge viewer: %w", err)
+	}
+
+	return nil
+}
+
+// RemoveEdgeViewer stops counting a connection that stopped playing
+func (r *RedisRepository) RemoveEdgeViewer(streamID, connectionID string) error {
+	ctx := context.Background()
+
+	pipe := r.client.TxPipeline()
+	pipe.SRem(ctx, r.viewerKey("edge_viewers:", streamID), connectionID)
+	pipe.ZRem(ctx, r.viewerKey("viewers:", streamID), connectionID)
+
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("failed to remove edge viewer: %w", err)
+	}
+
+	return nil
+}
+
+// sampleViewersScript refreshes the edge viewers, drops the viewers whose last heartbeat is
+// older than the cutoff, counts the rest and stores the count as the sample of a bucket unless
+// another replica already did. It returns the count and whether this call stored it.
+var sampleViewersScript = redis.NewScript(`
+for _, connection in ipairs(redis.call('SMEMBERS', KEYS[3])) do
+  redis.call('ZADD', KEYS[1], ARGV[4], connection)
+end
+redis.call('ZREMRANGEBYSCORE', KEYS[1], '-inf', '(' .. ARGV[1])
+local count = redis.call('ZCARD', KEYS[1])
+local stored = redis.call('HSETNX', KEYS[2], ARGV[2], count)
+redis.call('PEXPIRE', KEYS[2], ARGV[3])
+return {count, stored}
+`)
+
+// SampleViewers counts the viewers of a stream heard from since cutoff, and the edge viewers
+// still connected at now, and records the count as the stream's sample for bucket, once
+// across replicas
+func (r *RedisRepository) SampleViewers(streamID string, bucket int64, now, cutoff time.Time, expiration time.Duration) (int64, bool, error) {
+	ctx := context.Background()
+
+	result, err := sampleViewersScript.Run(ctx, r.client,
+		[]string{r.viewerKey("viewers:", streamID), r.viewerKey("viewer_samples:", streamID), r.viewerKey("edge_viewers:", streamID)},
+		cutoff.Unix(), bucket, expiration.Milliseconds(), now.Unix()).Int64Slice()
+	if err != nil {
+		return 0, false, fmt.Errorf("failed to sample viewers: %w", err)
+	}
+
+	return result[0], result[1] == 1, nil
+}
+
+// GetViewerSamples returns the concurrent viewer samples of a stream, in no particular order
+func (r *RedisRepository) GetViewerSamples(streamID string) ([]int64, error) {
+	ctx := context.Background()
+
+	values, err := r.client.HVals(ctx, r.viewerKey("viewer_samples:", streamID)).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get viewer samples: %w", err)
+	}
+
+	samples := make([]int64, 0, len(values))
+	for _, value := range values {
+		if sample, err := strconv.ParseInt(value, 10, 64); err == nil {
+			samples = append(samples, sample)
+		}
+	}
+
+	return samples, nil
+}
+
+// GetViewerSampleSeries returns the viewer samples of a stream by sample bucket
+func (r *RedisRepository) GetViewerSampleSeries(streamID string) (map[int64]int64, error) {
+	ctx := context.Background()
+
+	values, err := r.client.HGetAll(ctx, r.viewerKey("viewer_samples:", streamID)).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get viewer samples: %w", err)
+	}
+
+	series := make(map[int64]int64, len(values))
+	for field, value := range values {
+		bucket, err := strconv.ParseInt(field, 10, 64)
+		if err != nil {
+			continue
+		}
+		if sample, err := strconv.ParseInt(value, 10, 64); err == nil {
+			series[bucket] = sample
+		}
+	}
+
+	return series, nil
+}
+
+// SetCategoryFeatured adds a category to, or removes it from, the curated categories
+func (r *RedisRepository) SetCategoryFeatured(category string, featured bool) error {
+	ctx := context.Background()
+
+	var err error
+	if featured {
+		err = r.client.SAdd(ctx, "featured_categories", category).Err()
+	} else {
+		err = r.client.SRem(ctx, "featured_categories", category).Err()
+	}
+	if err != nil {
+		return fmt.Errorf("failed to update featured categories: %w", err)
+	}
+
+	return nil
+}
+
+// GetFeaturedCategories returns the curated categories
+func (r *RedisRepository) GetFeaturedCategories() ([]string, error) {
+	ctx := context.Background()
+
+	categories, err := r.client.SMembers(ctx, "featured_categories").Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get featured categories: %w", err)
+	}
+
+	return categories, nil
+}
+
+// CountUniqueViewers returns the approximate number of distinct viewers of a stream
+func (r *RedisRepository) CountUniqueViewers(streamID string) (int64, error) {
+	ctx := context.Background()
+
+	count, err := r.client.PFCount(ctx, fmt.Sprintf("viewers_unique:%s", streamID)).Result()
+	if err != nil {
+		return 0, fmt.Errorf("failed to count unique viewers: %w", err)
+	}
+
+	return count, nil
+}
+
+// trackCategoryScript moves a stream out of the category it was counted in and, if a category
+// is given, into that one with its viewers, keeping the channel and viewer totals of both.
+// Categories without live channels are dropped.
+var trackCategoryScript = redis.NewScript(`
+local prefix = ARGV[4]
+local old = redis.call('HGET', KEYS[1], ARGV[1])
+if old then
+	local viewers = tonumber(redis.call('HGET', prefix .. old, ARGV[1])) or 0
+	redis.call('HDEL', prefix .. old, ARGV[1])
+	redis.call('HDEL', KEYS[1], ARGV[1])
+	local channels = tonumber(redis.call('ZINCRBY', KEYS[2], -1, old))
+	redis.call('ZINCRBY', KEYS[3], -viewers, old)
+	if channels <= 0 then
+		redis.call('ZREM', KEYS[2], old)
+		redis.call('ZREM', KEYS[3], old)
+	end
+end
+if ARGV[2] ~= '' then
+	redis.call('HSET', KEYS[1], ARGV[1], ARGV[2])
+	redis.call('HSET', prefix .. ARGV[2], ARGV[1], ARGV[3])
+	redis.call('ZINCRBY', KEYS[2], 1, ARGV[2])
+	redis.call('ZINCRBY', KEYS[3], ARGV[3], ARGV[2])
+end
+return 1
+`)
+
+// TrackStreamCategory counts a live stream and its viewers in a category, an empty category
+// stops counting it
+func (r *RedisRepository) TrackStreamCategory(streamID, category string, viewers int64) error {
+	ctx := context.Background()
+
+	err := trackCategoryScript.Run(ctx, r.client,
+		[]string{r.categoryKey("stream_category"), r.categoryKey("category_channels"), r.categoryKey("category_viewers")},
+		streamID, category, viewers, r.categoryKey("category_streams:")).Err()
+	if err != nil {
+		return fmt.Errorf("failed to track stream category: %w", err)
+	}
+
+	return nil
+}
+
+// GetCategoryStats returns the live aggregates of every category with live channels
+func (r *RedisRepository) GetCategoryStats() ([]*models.CategoryStats, error) {
+	ctx := context.Background()
+
+	channels, err := r.client.ZRangeWithScores(ctx, r.categoryKey("category_channels"), 0, -1).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get category channels: %w", err)
+	}
+	viewers, err := r.client.ZRangeWithScores(ctx, r.categoryKey("category_viewers"), 0, -1).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get category viewers: %w", err)
+	}
+
+	viewersByCategory := make(map[string]int64, len(viewers))
+	for _, z := range viewers {
+		viewersByCategory[z.Member.(string)] = int64(z.Score)
+	}
+
+	stats := make([]*models.CategoryStats, 0, len(channels))
+	for _, z := range channels {
+		category := z.Member.(string)
+		stats = append(stats, &models.CategoryStats{
+			Category:     category,
+			LiveChannels: int64(z.Score),
+			Viewers:      viewersByCategory[category],
+		})
+	}
+
+	return stats, nil
+}
+
+// GetCategoryStreams returns the live streams counted in a category, in no particular order
+func (r *RedisRepository) GetCategoryStreams(category string) ([]models.CategoryStream, error) {
+	ctx := context.Background()
+
+	entries, err := r.client.HGetAll(ctx, r.categoryKey("category_streams:")+category).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get category streams: %w", err)
+	}
+
+	streams := make([]models.CategoryStream, 0, len(entries))
+	for streamID, value := range entries {
+		viewers, _ := strconv.ParseInt(value, 10, 64)
+		streams = append(streams, models.CategoryStream{StreamID: streamID, Viewers: viewers})
+	}
+
+	return streams, nil
+}
+
+// eventSpoolKey is the Redis stream events wait in while Kinesis can't take them
+const eventSpoolKey = "event_spool"
+
+// SpoolEvents appends events to the spool. Past maxLen the oldest ones are trimmed.
+func (r *RedisRepository) SpoolEvents(events [][]byte, maxLen int64) error {
+	ctx := context.Background()
+
+	pipe := r.client.Pipeline()
+	for _, event := range events {
+		pipe.XAdd(ctx, &redis.XAddArgs{
+			Stream: eventSpoolKey,
+			MaxLen: maxLen,
+			Approx: true,
+			Values: map[string]interface{}{"data": event},
+		})
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("failed to spool events: %w", err)
+	}
+
+	return nil
+}
+
+// GetSpooledEvents returns the oldest spooled events and their entry IDs
+func (r *RedisRepository) GetSpooledEvents(count int64) ([]string, [][]byte, error) {
+	ctx := context.Background()
+
+	messages, err := r.client.XRangeN(ctx, eventSpoolKey, "-", "+", count).Result()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read spooled events: %w", err)
+	}
+
+	ids := make([]string, 0, len(messages))
+	events := make([][]byte, 0, len(messages))
+	for _, message := range messages {
+		data, _ := message.Values["data"].(string)
+		ids = append(ids, message.ID)
+		events = append(events, []byte(data))
+	}
+
+	return ids, events, nil
+}
+
+// DeleteSpooledEvents removes replayed events from the spool
+func (r *RedisRepository) DeleteSpooledEvents(ids []string) error {
+	ctx := context.Background()
+
+	if len(ids) == 0 {
+		return nil
+	}
+	if err := r.client.XDel(ctx, eventSpoolKey, ids...).Err(); err != nil {
+		return fmt.Errorf("failed to delete spooled events: %w", err)
+	}
+
+	return nil
+}
+
+// CountSpooledEvents returns how many events wait in the spool
+func (r *RedisRepository) CountSpooledEvents() (int64, error) {
+	ctx := context.Background()
+
+	count, err := r.client.XLen(ctx, eventSpoolKey).Result()
+	if err != nil {
+		return 0, fmt.Errorf("failed to count spooled events: %w", err)
+	}
+
+	return count, nil
+}
+
+// ClaimEventReplay makes this replica the one replaying the spool for ttl, so events aren't
+// sent twice
+func (r *RedisRepository) ClaimEventReplay(ttl time.Duration) (bool, error) {
+	ctx := context.Background()
+
+	claimed, err := r.client.SetNX(ctx, "event_spool_replay", "", ttl).Result()
+	if err != nil {
+		return false, fmt.Errorf("failed to claim event replay: %w", err)
+	}
+
+	return claimed, nil
+}
+
+// ClaimOutboxRelay makes this replica the one relaying the outbox for ttl, so events aren't
+// sent twice
+func (r *RedisRepository) ClaimOutboxRelay(ttl time.Duration) (bool, error) {
+	ctx := context.Background()
+
+	claimed, err := r.client.SetNX(ctx, "outbox_relay", "", ttl).Result()
+	if err != nil {
+		return false, fmt.Errorf("failed to claim outbox relay: %w", err)
+	}
+
+	return claimed, nil
+}
+
+// lockKey is the key a lock is held under, its value is the owner
+func lockKey(name string) string {
+	return "lock:" + name
+}
+
+// AcquireLock takes a lock for owner for ttl unless someone holds it already
+func (r *RedisRepository) AcquireLock(name, owner string, ttl time.Duration) (bool, error) {
+	ctx := context.Background()
+
+	acquired, err := r.client.SetNX(ctx, lockKey(name), owner, ttl).Result()
+	if err != nil {
+		return false, fmt.Errorf("failed to acquire lock %s: %w", name, err)
+	}
+
+	return acquired, nil
+}
+
+// renewLockScript extends a lock's TTL if it is still held by the owner
+var renewLockScript = redis.NewScript(`
+if redis.call('GET', KEYS[1]) == ARGV[1] then
+	return redis.call('PEXPIRE', KEYS[1], ARGV[2])
+end
+return 0
+`)
+
+// RenewLock extends a lock owner holds to ttl from now. It returns false when owner lost it,
+// because it expired or someone else took it since.
+func (r *RedisRepository) RenewLock(name, owner string, ttl time.Duration) (bool, error) {
+	ctx := context.Background()
+
+	renewed, err := renewLockScript.Run(ctx, r.client, []string{lockKey(name)}, owner, ttl.Milliseconds()).Int64()
+	if err != nil {
+		return false, fmt.Errorf("failed to renew lock %s: %w", name, err)
+	}
+
+	return renewed == 1, nil
+}
+
+// releaseLockScript deletes a lock if it is still held by the owner
+var releaseLockScript = redis.NewScript(`
+if redis.call('GET', KEYS[1]) == ARGV[1] then
+	return redis.call('DEL', KEYS[1])
+end
+return 0
+`)
+
+// ReleaseLock frees a lock owner holds, a lock someone else holds by now is left alone
+func (r *RedisRepository) ReleaseLock(name, owner string) error {
+	ctx := context.Background()
+
+	if err := releaseLockScript.Run(ctx, r.client, []string{lockKey(name)}, owner).Err(); err != nil {
+		return fmt.Errorf("failed to release lock %s: %w", name, err)
+	}
+
+	return nil
+}
+
+// GetLockOwner returns who holds a lock, empty when it is free
+func (r *RedisRepository) GetLockOwner(name string) (string, error) {
+	ctx := context.Background()
+
+	owner, err := r.client.Get(ctx, lockKey(name)).Result()
+	if err == redis.Nil {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to get owner of lock %s: %w", name, err)
+	}
+
+	return owner, nil
+}
+
+// dashboardEventsChannel carries the events shown on admin dashboards between replicas
+const dashboardEventsChannel = "dashboard:events"
+
+// PublishDashboardEvent sends an event to the admin dashboards connected to every replica
+func (r *RedisRepository) PublishDashboardEvent(payload string) error {
+	ctx := context.Background()
+
+	if err := r.client.Publish(ctx, dashboardEventsChannel, payload).Err(); err != nil {
+		return fmt.Errorf("failed to publish dashboard event: %w", err)
+	}
+
+	return nil
+}
+
+// SubscribeDashboardEvents returns the dashboard events published from now until ctx is done.
+// The subscription reconnects by itself when Redis goes away.
+func (r *RedisRepository) SubscribeDashboardEvents(ctx context.Context) <-chan string {
+	pubsub := r.client.Subscribe(ctx, dashboardEventsChannel)
+	payloads := make(chan string)
+
+	go func() {
+		defer close(payloads)
+		defer pubsub.Close()
+
+		messages := pubsub.Channel()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case message, ok := <-messages:
+				if !ok {
+					return
+				}
+				select {
+				case payloads <- message.Payload:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+
+	return payloads
+}
+
+// AddRequestCounts adds a replica's HTTP request counts to the shared ones of a time bucket
+func (r *RedisRepository) AddRequestCounts(bucket int64, requests, clientErrors, serverErrors int64, expiration time.Duration) error {
+	ctx := context.Background()
+	key := fmt.Sprintf("dashboard:requests:%d", bucket)
+
+	pipe := r.client.TxPipeline()
+	pipe.HIncrBy(ctx, key, "requests", requests)
+	pipe.HIncrBy(ctx, key, "client_errors", clientErrors)
+	pipe.HIncrBy(ctx, key, "server_errors", serverErrors)
+	pipe.Expire(ctx, key, expiration)
+
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("failed to add request counts: %w", err)
+	}
+
+	return nil
+}
+
+// GetRequestCounts returns the HTTP request counts of every replica summed over time buckets
+func (r *RedisRepository) GetRequestCounts(buckets []int64) (requests, clientErrors, serverErrors int64, err error) {
+	ctx := context.Background()
+
+	pipe := r.client.Pipeline()
+	cmds := make([]*redis.StringStringMapCmd, 0, len(buckets))
+	for _, bucket := range buckets {
+		cmds = append(cmds, pipe.HGetAll(ctx, fmt.Sprintf("dashboard:requests:%d", bucket)))
+	}
+	if _, err := pipe.Exec(ctx); err != nil && err != redis.Nil {
+		return 0, 0, 0, fmt.Errorf("failed to get request counts: %w", err)
+	}
+
+	for _, cmd := range cmds {
+		counts := cmd.Val()
+		value, _ := strconv.ParseInt(counts["requests"], 10, 64)
+		requests += value
+		value, _ = strconv.ParseInt(counts["client_errors"], 10, 64)
+		clientErrors += value
+		value, _ = strconv.ParseInt(counts["server_errors"], 10, 64)
+		serverErrors += value
+	}
+
+	return requests, clientErrors, serverErrors, nil
+}
+
+// DeleteStreamState removes what Redis keeps about a stream: its cached data, health and
+// latency samples, viewers and squad
+func (r *RedisRepository) DeleteStreamState(streamID string) error {
+	ctx := context.Background()
+
+	keys := []string{
+		fmt.Sprintf("stream:%s", streamID),
+		fmt.Sprintf("health:%s", streamID),
+		fmt.Sprintf("latency:%s", streamID),
+		r.viewerKey("viewers:", streamID),
+		fmt.Sprintf("viewers_unique:%s", streamID),
+		r.viewerKey("viewer_samples:", streamID),
+		r.viewerKey("edge_viewers:", streamID),
+		"stream_squad:" + streamID,
+	}
+
+	// One DEL per key, a cluster refuses a DEL of keys on different nodes
+	pipe := r.client.TxPipeline()
+	for _, key := range keys {
+		pipe.Del(ctx, key)
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("failed to delete stream state: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteUserState removes what Redis keeps about a user: the channels they follow, their
+// channel's follow history, event feed and follower count, and their follows in the follow
+// history of the channels they followed
+func (r *RedisRepository) DeleteUserState(userID int64, followedChannels []int64) error {
+	ctx := context.Background()
+	user := strconv.FormatInt(userID, 10)
+
+	pipe := r.client.TxPipeline()
+	pipe.Del(ctx, fmt.Sprintf("following:%d", userID))
+	pipe.Del(ctx, fmt.Sprintf("follows:%d", userID))
+	pipe.Del(ctx, fmt.Sprintf("channel_events:%d", userID))
+	pipe.HDel(ctx, "follower_counts", user)
+	for _, channelID := range followedChannels {
+		pipe.ZRem(ctx, fmt.Sprintf("follows:%d", channelID), user)
+	}
+
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("failed to delete user state: %w", err)
+	}
+
+	return nil
+}
+
+// ClaimUserDataJob makes this replica the one running a user data job for ttl
+func (r *RedisRepository) ClaimUserDataJob(jobID string, ttl time.Duration) (bool, error) {
+	ctx := context.Background()
+
+	claimed, err := r.client.SetNX(ctx, "user_data_job:"+jobID, "", ttl).Result()
+	if err != nil {
+		return false, fmt.Errorf("failed to claim user data job: %w", err)
+	}
+
+	return claimed, nil
+}
+
+// ReleaseUserDataJob lets another run of a user data job start right away
+func (r *RedisRepository) ReleaseUserDataJob(jobID string) error {
+	ctx := context.Background()
+
+	if err := r.client.Del(ctx, "user_data_job:"+jobID).Err(); err != nil {
+		return fmt.Errorf("failed to release user data job: %w", err)
+	}
+
+	return nil
+}
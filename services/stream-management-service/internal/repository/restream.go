@@ -0,0 +1,102 @@
+// services/stream-management-service/internal/repository/restream.go
+package repository
+
+import (
+	"fmt"
+	"log/slog"
+	"strconv"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/services/stream-management-service/internal/models"
+)
+
+// SaveRestreamTarget creates or replaces a restream target
+func (r *DynamoDBRepository) SaveRestreamTarget(target *models.RestreamTarget) error {
+	item, err := dynamodbattribute.MarshalMap(target)
+	if err != nil {
+		return fmt.Errorf("failed to marshal restream target: %w", err)
+	}
+
+	_, err = r.client.PutItem(&dynamodb.PutItemInput{
+		TableName: aws.String(r.restreamTableName),
+		Item:      item,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to put restream target: %w", err)
+	}
+
+	return nil
+}
+
+func (r *DynamoDBRepository) GetRestreamTargetByID(targetID string) (*models.RestreamTarget, error) {
+	result, err := r.client.GetItem(&dynamodb.GetItemInput{
+		TableName: aws.String(r.restreamTableName),
+		Key: map[string]*dynamodb.AttributeValue{
+			"id": {
+				S: aws.String(targetID),
+			},
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get restream target: %w", err)
+	}
+
+	if result.Item == nil {
+		return nil, fmt.Errorf("restream target not found")
+	}
+
+	var target models.RestreamTarget
+	if err := dynamodbattribute.UnmarshalMap(result.Item, &target); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal restream target: %w", err)
+	}
+
+	return &target, nil
+}
+
+// GetRestreamTargetsByUser returns all restream targets of a user
+func (r *DynamoDBRepository) GetRestreamTargetsByUser(userID int64) ([]*models.RestreamTarget, error) {
+	result, err := r.client.Query(&dynamodb.QueryInput{
+		TableName:              aws.String(r.restreamTableName),
+		IndexName:              aws.String("user-id-index"),
+		KeyConditionExpression: aws.String("user_id = :user_id"),
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+			":user_id": {
+				N: aws.String(strconv.FormatInt(userID, 10)),
+			},
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to query restream targets: %w", err)
+	}
+
+	targets := make([]*models.RestreamTarget, 0, len(result.Items))
+	for _, item := range result.Items {
+		var target models.RestreamTarget
+		if err := dynamodbattribute.UnmarshalMap(item, &target); err != nil {
+			slog.Warn("⚠️ Failed to unmarshal restream target", "error", err)
+			continue
+		}
+		targets = append(targets, &target)
+	}
+
+	return targets, nil
+}
+
+func (r *DynamoDBRepository) DeleteRestreamTarget(targetID string) error {
+	_, err := r.client.DeleteItem(&dynamodb.DeleteItemInput{
+		TableName: aws.String(r.restreamTableName),
+		Key: map[string]*dynamodb.AttributeValue{
+			"id": {
+				S: aws.String(targetID),
+			},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to delete restream target: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,623 @@
+// services/stream-management-service/internal/repository/schema.go
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/services/stream-management-service/internal/config"
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/shared/go/pkg/events"
+)
+
+// TableDefinitions returns the DynamoDB tables this service needs. It is the single
+// source of truth used both for local table creation and for the provisioning plan.
+func TableDefinitions(cfg *config.Config) []*dynamodb.CreateTableInput {
+	return []*dynamodb.CreateTableInput{
+		streamsTableDefinition(cfg.DynamoDBTableName),
+		vodTableDefinition(cfg.VODTableName),
+		clipTableDefinition(cfg.ClipTableName),
+		restreamTableDefinition(cfg.RestreamTableName),
+		apiKeyTableDefinition(cfg.APIKeyTableName),
+		takedownTableDefinition(cfg.TakedownTableName),
+		streamKeyBanTableDefinition(cfg.StreamKeyBanTable),
+		statsTableDefinition(cfg.StatsTableName),
+		auditTableDefinition(cfg.AuditTableName),
+		outboxTableDefinition(cfg.OutboxTableName),
+		deadLetterTableDefinition(cfg.DeadLetterTable),
+		events.DedupeTableDefinition(cfg.DedupeTableName),
+		migrationsTableDefinition(cfg.MigrationsTable),
+	}
+}
+
+// TTLAttributes maps tables to the attribute DynamoDB expires their items by
+func TTLAttributes(cfg *config.Config) map[string]string {
+	return map[string]string{
+		cfg.DynamoDBTableName: "expires_at",
+		cfg.StatsTableName:    "expires_at",
+		cfg.OutboxTableName:   "expires_at",
+		cfg.DeadLetterTable:   "expires_at",
+		cfg.DedupeTableName:   events.DedupeTTLAttribute,
+	}
+}
+
+func streamsTableDefinition(tableName string) *dynamodb.CreateTableInput {
+	return &dynamodb.CreateTableInput{
+		TableName: aws.String(tableName),
+		KeySchema: []*dynamodb.KeySchemaElement{
+			{
+				AttributeName: aws.String("id"),
+				KeyType:       aws.String("HASH"), // Partition key
+			},
+		},
+		AttributeDefinitions: []*dynamodb.AttributeDefinition{
+			{
+				AttributeName: aws.String("id"),
+				AttributeType: aws.String("S"), // String
+			},
+			{
+				AttributeName: aws.String("stream_key"),
+				AttributeType: aws.String("S"), // String
+			},
+			{
+				AttributeName: aws.String("status"),
+				AttributeType: aws.String("S"), // String
+			},
+			{
+				AttributeName: aws.String("user_id"),
+				AttributeType: aws.String("N"), // Number
+			},
+			{
+				AttributeName: aws.String("recording_status"),
+				AttributeType: aws.String("S"), // String
+			},
+		},
+		BillingMode: aws.String("PAY_PER_REQUEST"), // On-demand pricing
+		GlobalSecondaryIndexes: []*dynamodb.GlobalSecondaryIndex{
+			// GSI for querying by stream_key
+			{
+				IndexName: aws.String("stream-key-index"),
+				KeySchema: []*dynamodb.KeySchemaElement{
+					{
+						AttributeName: aws.String("stream_key"),
+						KeyType:       aws.String("HASH"),
+					},
+				},
+				Projection: &dynamodb.Projection{
+					ProjectionType: aws.String("ALL"),
+				},
+			},
+			// GSI for querying by status
+			{
+				IndexName: aws.String("status-index"),
+				KeySchema: []*dynamodb.KeySchemaElement{
+					{
+						AttributeName: aws.String("status"),
+						KeyType:       aws.String("HASH"),
+					},
+				},
+				Projection: &dynamodb.Projection{
+					ProjectionType: aws.String("ALL"),
+				},
+			},
+			// GSI for querying by user_id
+			{
+				IndexName: aws.String("user-id-index"),
+				KeySchema: []*dynamodb.KeySchemaElement{
+					{
+						AttributeName: aws.String("user_id"),
+						KeyType:       aws.String("HASH"),
+					},
+				},
+				Projection: &dynamodb.Projection{
+					ProjectionType: aws.String("ALL"),
+				},
+			},
+			// GSI for finding recordings that still need uploading, streams that never
+			// recorded aren't in it
+			{
+				IndexName: aws.String("recording-status-index"),
+				KeySchema: []*dynamodb.KeySchemaElement{
+					{
+						AttributeName: aws.String("recording_status"),
+						KeyType:       aws.String("HASH"),
+					},
+				},
+				Projection: &dynamodb.Projection{
+					ProjectionType: aws.String("ALL"),
+				},
+			},
+		},
+	}
+}
+
+func vodTableDefinition(tableName string) *dynamodb.CreateTableInput {
+	return &dynamodb.CreateTableInput{
+		TableName: aws.String(tableName),
+		KeySchema: []*dynamodb.KeySchemaElement{
+			{
+				AttributeName: aws.String("id"),
+				KeyType:       aws.String("HASH"),
+			},
+		},
+		AttributeDefinitions: []*dynamodb.AttributeDefinition{
+			{
+				AttributeName: aws.String("id"),
+				AttributeType: aws.String("S"),
+			},
+			{
+				AttributeName: aws.String("user_id"),
+				AttributeType: aws.String("N"),
+			},
+			{
+				AttributeName: aws.String("visibility"),
+				AttributeType: aws.String("S"),
+			},
+			{
+				AttributeName: aws.String("created_at"),
+				AttributeType: aws.String("S"),
+			},
+		},
+		BillingMode: aws.String("PAY_PER_REQUEST"),
+		GlobalSecondaryIndexes: []*dynamodb.GlobalSecondaryIndex{
+			// GSI for listing a user's VODs, newest first
+			{
+				IndexName: aws.String("user-id-created-index"),
+				KeySchema: []*dynamodb.KeySchemaElement{
+					{
+						AttributeName: aws.String("user_id"),
+						KeyType:       aws.String("HASH"),
+					},
+					{
+						AttributeName: aws.String("created_at"),
+						KeyType:       aws.String("RANGE"),
+					},
+				},
+				Projection: &dynamodb.Projection{
+					ProjectionType: aws.String("ALL"),
+				},
+			},
+			// GSI for the public catalog, newest first
+			{
+				IndexName: aws.String("visibility-created-index"),
+				KeySchema: []*dynamodb.KeySchemaElement{
+					{
+						AttributeName: aws.String("visibility"),
+						KeyType:       aws.String("HASH"),
+					},
+					{
+						AttributeName: aws.String("created_at"),
+						KeyType:       aws.String("RANGE"),
+					},
+				},
+				Projection: &dynamodb.Projection{
+					ProjectionType: aws.String("ALL"),
+				},
+			},
+		},
+	}
+}
+
+func clipTableDefinition(tableName string) *dynamodb.CreateTableInput {
+	return &dynamodb.CreateTableInput{
+		TableName: aws.String(tableName),
+		KeySchema: []*dynamodb.KeySchemaElement{
+			{
+				AttributeName: aws.String("id"),
+				KeyType:       aws.String("HASH"),
+			},
+		},
+		AttributeDefinitions: []*dynamodb.AttributeDefinition{
+			{
+				AttributeName: aws.String("id"),
+				AttributeType: aws.String("S"),
+			},
+			{
+				AttributeName: aws.String("stream_id"),
+				AttributeType: aws.String("S"),
+			},
+			{
+				AttributeName: aws.String("created_at"),
+				AttributeType: aws.String("S"),
+			},
+		},
+		BillingMode: aws.String("PAY_PER_REQUEST"),
+		GlobalSecondaryIndexes: []*dynamodb.GlobalSecondaryIndex{
+			// GSI for listing a stream's clips, newest first
+			{
+				IndexName: aws.String("stream-id-created-index"),
+				KeySchema: []*dynamodb.KeySchemaElement{
+					{
+						AttributeName: aws.String("stream_id"),
+						KeyType:       aws.String("HASH"),
+					},
+					{
+						AttributeName: aws.String("created_at"),
+						KeyType:       aws.String("RANGE"),
+					},
+				},
+				Projection: &dynamodb.Projection{
+					ProjectionType: aws.String("ALL"),
+				},
+			},
+		},
+	}
+}
+
+// HealthCheck describes the streams table, bounded by ctx, and fails unless it is active.
+// Unlike VerifyTables it is cheap enough to run on every readiness probe.
+func (r *DynamoDBRepository) HealthCheck(ctx context.Context) error {
+	result, err := r.client.DescribeTableWithContext(ctx, &dynamodb.DescribeTableInput{
+		TableName: aws.String(r.tableName),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to describe table '%s': %w", r.tableName, err)
+	}
+	if status := aws.StringValue(result.Table.TableStatus); status != dynamodb.TableStatusActive {
+		return fmt.Errorf("table '%s' is %s, expected ACTIVE", r.tableName, status)
+	}
+
+	return nil
+}
+
+// VerifyTables checks that every table in TableDefinitions exists, is active and
+// carries the expected key schema and GSIs. It returns one entry per problem found.
+func (r *DynamoDBRepository) VerifyTables(cfg *config.Config) []string {
+	var problems []string
+
+	for _, expected := range TableDefinitions(cfg) {
+		tableName := aws.StringValue(expected.TableName)
+
+		result, err := r.client.DescribeTable(&dynamodb.DescribeTableInput{
+			TableName: aws.String(tableName),
+		})
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("table '%s' is missing or not accessible: %v", tableName, err))
+			continue
+		}
+
+		table := result.Table
+		if status := aws.StringValue(table.TableStatus); status != dynamodb.TableStatusActive {
+			problems = append(problems, fmt.Sprintf("table '%s' is %s, expected ACTIVE", tableName, status))
+		}
+
+		if !sameKeySchema(expected.KeySchema, table.KeySchema) {
+			problems = append(problems, fmt.Sprintf("table '%s' has key schema %s, expected %s",
+				tableName, describeKeySchema(table.KeySchema), describeKeySchema(expected.KeySchema)))
+		}
+
+		actualIndexes := make(map[string]*dynamodb.GlobalSecondaryIndexDescription)
+		for _, index := range table.GlobalSecondaryIndexes {
+			actualIndexes[aws.StringValue(index.IndexName)] = index
+		}
+
+		for _, index := range expected.GlobalSecondaryIndexes {
+			indexName := aws.StringValue(index.IndexName)
+			actual, ok := actualIndexes[indexName]
+			if !ok {
+				problems = append(problems, fmt.Sprintf("table '%s' is missing GSI '%s' (%s)",
+					tableName, indexName, describeKeySchema(index.KeySchema)))
+				continue
+			}
+			if !sameKeySchema(index.KeySchema, actual.KeySchema) {
+				problems = append(problems, fmt.Sprintf("GSI '%s' on table '%s' has key schema %s, expected %s",
+					indexName, tableName, describeKeySchema(actual.KeySchema), describeKeySchema(index.KeySchema)))
+			}
+		}
+	}
+
+	return problems
+}
+
+func sameKeySchema(expected, actual []*dynamodb.KeySchemaElement) bool {
+	if len(expected) != len(actual) {
+		return false
+	}
+	for i := range expected {
+		if aws.StringValue(expected[i].AttributeName) != aws.StringValue(actual[i].AttributeName) ||
+			aws.StringValue(expected[i].KeyType) != aws.StringValue(actual[i].KeyType) {
+			return false
+		}
+	}
+	return true
+}
+
+func describeKeySchema(schema []*dynamodb.KeySchemaElement) string {
+	desc := ""
+	for i, key := range schema {
+		if i > 0 {
+			desc += ", "
+		}
+		desc += fmt.Sprintf("%s %s", aws.StringValue(key.AttributeName), aws.StringValue(key.KeyType))
+	}
+	return "[" + desc + "]"
+}
+
+func restreamTableDefinition(tableName string) *dynamodb.CreateTableInput {
+	return &dynamodb.CreateTableInput{
+		TableName: aws.String(tableName),
+		KeySchema: []*dynamodb.KeySchemaElement{
+			{
+				AttributeName: aws.String("id"),
+				KeyType:       aws.String("HASH"),
+			},
+		},
+		AttributeDefinitions: []*dynamodb.AttributeDefinition{
+			{
+				AttributeName: aws.String("id"),
+				AttributeType: aws.String("S"),
+			},
+			{
+				AttributeName: aws.String("user_id"),
+				AttributeType: aws.String("N"),
+			},
+		},
+		BillingMode: aws.String("PAY_PER_REQUEST"),
+		GlobalSecondaryIndexes: []*dynamodb.GlobalSecondaryIndex{
+			// GSI for listing a user's restream targets
+			{
+				IndexName: aws.String("user-id-index"),
+				KeySchema: []*dynamodb.KeySchemaElement{
+					{
+						AttributeName: aws.String("user_id"),
+						KeyType:       aws.String("HASH"),
+					},
+				},
+				Projection: &dynamodb.Projection{
+					ProjectionType: aws.String("ALL"),
+				},
+			},
+		},
+	}
+}
+
+func takedownTableDefinition(tableName string) *dynamodb.CreateTableInput {
+	return &dynamodb.CreateTableInput{
+		TableName: aws.String(tableName),
+		KeySchema: []*dynamodb.KeySchemaElement{
+			{
+				AttributeName: aws.String("id"),
+				KeyType:       aws.String("HASH"),
+			},
+		},
+		AttributeDefinitions: []*dynamodb.AttributeDefinition{
+			{
+				AttributeName: aws.String("id"),
+				AttributeType: aws.String("S"),
+			},
+			{
+				AttributeName: aws.String("status"),
+				AttributeType: aws.String("S"),
+			},
+		},
+		BillingMode: aws.String("PAY_PER_REQUEST"),
+		GlobalSecondaryIndexes: []*dynamodb.GlobalSecondaryIndex{
+			// GSI for the review queue, e.g. every counter-noticed takedown
+			{
+				IndexName: aws.String("status-index"),
+				KeySchema: []*dynamodb.KeySchemaElement{
+					{
+						AttributeName: aws.String("status"),
+						KeyType:       aws.String("HASH"),
+					},
+				},
+				Projection: &dynamodb.Projection{
+					ProjectionType: aws.String("ALL"),
+				},
+			},
+		},
+	}
+}
+
+func streamKeyBanTableDefinition(tableName string) *dynamodb.CreateTableInput {
+	return &dynamodb.CreateTableInput{
+		TableName: aws.String(tableName),
+		KeySchema: []*dynamodb.KeySchemaElement{
+			{
+				AttributeName: aws.String("stream_key"),
+				KeyType:       aws.String("HASH"),
+			},
+		},
+		AttributeDefinitions: []*dynamodb.AttributeDefinition{
+			{
+				AttributeName: aws.String("stream_key"),
+				AttributeType: aws.String("S"),
+			},
+		},
+		BillingMode: aws.String("PAY_PER_REQUEST"),
+	}
+}
+
+// statsTableDefinition holds platform stats rollups, one partition per granularity sorted by
+// bucket start. Incident annotations and private stream access are kept in partitions of
+// their own.
+func statsTableDefinition(tableName string) *dynamodb.CreateTableInput {
+	return &dynamodb.CreateTableInput{
+		TableName: aws.String(tableName),
+		KeySchema: []*dynamodb.KeySchemaElement{
+			{
+				AttributeName: aws.String("granularity"),
+				KeyType:       aws.String("HASH"),
+			},
+			{
+				AttributeName: aws.String("bucket"),
+				KeyType:       aws.String("RANGE"),
+			},
+		},
+		AttributeDefinitions: []*dynamodb.AttributeDefinition{
+			{
+				AttributeName: aws.String("granularity"),
+				AttributeType: aws.String("S"),
+			},
+			{
+				AttributeName: aws.String("bucket"),
+				AttributeType: aws.String("S"),
+			},
+		},
+		BillingMode: aws.String("PAY_PER_REQUEST"),
+	}
+}
+
+// auditTableDefinition holds each stream's audit log, sorted by when the change was made
+func auditTableDefinition(tableName string) *dynamodb.CreateTableInput {
+	return &dynamodb.CreateTableInput{
+		TableName: aws.String(tableName),
+		KeySchema: []*dynamodb.KeySchemaElement{
+			{
+				AttributeName: aws.String("stream_id"),
+				KeyType:       aws.String("HASH"),
+			},
+			{
+				AttributeName: aws.String("sort_key"),
+				KeyType:       aws.String("RANGE"),
+			},
+		},
+		AttributeDefinitions: []*dynamodb.AttributeDefinition{
+			{
+				AttributeName: aws.String("stream_id"),
+				AttributeType: aws.String("S"),
+			},
+			{
+				AttributeName: aws.String("sort_key"),
+				AttributeType: aws.String("S"),
+			},
+		},
+		BillingMode: aws.String("PAY_PER_REQUEST"),
+	}
+}
+
+// outboxTableDefinition holds the events written with stream changes until they are relayed
+// to the event bus. The pending index is sparse, delivered events drop out of it.
+func outboxTableDefinition(tableName string) *dynamodb.CreateTableInput {
+	return &dynamodb.CreateTableInput{
+		TableName: aws.String(tableName),
+		KeySchema: []*dynamodb.KeySchemaElement{
+			{
+				AttributeName: aws.String("id"),
+				KeyType:       aws.String("HASH"),
+			},
+		},
+		AttributeDefinitions: []*dynamodb.AttributeDefinition{
+			{
+				AttributeName: aws.String("id"),
+				AttributeType: aws.String("S"),
+			},
+			{
+				AttributeName: aws.String("status"),
+				AttributeType: aws.String("S"),
+			},
+			{
+				AttributeName: aws.String("created_at"),
+				AttributeType: aws.String("S"),
+			},
+		},
+		BillingMode: aws.String("PAY_PER_REQUEST"),
+		GlobalSecondaryIndexes: []*dynamodb.GlobalSecondaryIndex{
+			// GSI for relaying pending events, oldest first
+			{
+				IndexName: aws.String(outboxPendingIndex),
+				KeySchema: []*dynamodb.KeySchemaElement{
+					{
+						AttributeName: aws.String("status"),
+						KeyType:       aws.String("HASH"),
+					},
+					{
+						AttributeName: aws.String("created_at"),
+						KeyType:       aws.String("RANGE"),
+					},
+				},
+				Projection: &dynamodb.Projection{
+					ProjectionType: aws.String("ALL"),
+				},
+			},
+		},
+	}
+}
+
+// deadLetterTableDefinition holds the event records consumers gave up on
+func deadLetterTableDefinition(tableName string) *dynamodb.CreateTableInput {
+	return &dynamodb.CreateTableInput{
+		TableName: aws.String(tableName),
+		KeySchema: []*dynamodb.KeySchemaElement{
+			{
+				AttributeName: aws.String("id"),
+				KeyType:       aws.String("HASH"),
+			},
+		},
+		AttributeDefinitions: []*dynamodb.AttributeDefinition{
+			{
+				AttributeName: aws.String("id"),
+				AttributeType: aws.String("S"),
+			},
+			{
+				AttributeName: aws.String("consumer"),
+				AttributeType: aws.String("S"),
+			},
+			{
+				AttributeName: aws.String("failed_at"),
+				AttributeType: aws.String("S"),
+			},
+		},
+		BillingMode: aws.String("PAY_PER_REQUEST"),
+		GlobalSecondaryIndexes: []*dynamodb.GlobalSecondaryIndex{
+			// GSI for listing a consumer's dead letters, newest first
+			{
+				IndexName: aws.String("consumer-failed-index"),
+				KeySchema: []*dynamodb.KeySchemaElement{
+					{
+						AttributeName: aws.String("consumer"),
+						KeyType:       aws.String("HASH"),
+					},
+					{
+						AttributeName: aws.String("failed_at"),
+						KeyType:       aws.String("RANGE"),
+					},
+				},
+				Projection: &dynamodb.Projection{
+					ProjectionType: aws.String("ALL"),
+				},
+			},
+		},
+	}
+}
+
+// migrationsTableDefinition records which schema migrations were applied, keyed by version
+func migrationsTableDefinition(tableName string) *dynamodb.CreateTableInput {
+	return &dynamodb.CreateTableInput{
+		TableName: aws.String(tableName),
+		KeySchema: []*dynamodb.KeySchemaElement{
+			{
+				AttributeName: aws.String("version"),
+				KeyType:       aws.String("HASH"),
+			},
+		},
+		AttributeDefinitions: []*dynamodb.AttributeDefinition{
+			{
+				AttributeName: aws.String("version"),
+				AttributeType: aws.String("N"),
+			},
+		},
+		BillingMode: aws.String("PAY_PER_REQUEST"),
+	}
+}
+
+func apiKeyTableDefinition(tableName string) *dynamodb.CreateTableInput {
+	return &dynamodb.CreateTableInput{
+		TableName: aws.String(tableName),
+		KeySchema: []*dynamodb.KeySchemaElement{
+			{
+				AttributeName: aws.String("id"),
+				KeyType:       aws.String("HASH"),
+			},
+		},
+		AttributeDefinitions: []*dynamodb.AttributeDefinition{
+			{
+				AttributeName: aws.String("id"),
+				AttributeType: aws.String("S"),
+			},
+		},
+		BillingMode: aws.String("PAY_PER_REQUEST"),
+	}
+}
@@ -0,0 +1,81 @@
+// services/stream-management-service/internal/repository/soft_delete.go
+package repository
+
+import (
+	"fmt"
+	"log/slog"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/services/stream-management-service/internal/models"
+)
+
+// deletedFilterExpression adds the condition picking soft deleted items or not to a query's
+// filter expression, which may be nil
+func deletedFilterExpression(deleted models.DeletedFilter, filter *string) *string {
+	var condition string
+	switch deleted {
+	case models.DeletedInclude:
+		return filter
+	case models.DeletedOnly:
+		condition = "attribute_exists(deleted_at)"
+	default:
+		condition = "attribute_not_exists(deleted_at)"
+	}
+
+	if filter != nil && *filter != "" {
+		condition = "(" + *filter + ") AND " + condition
+	}
+	return aws.String(condition)
+}
+
+// GetDeletedStreams returns every soft deleted stream. Deleted streams aren't indexed, so the
+// table is scanned; only meant for the purge job.
+func (r *DynamoDBRepository) GetDeletedStreams() ([]*models.Stream, error) {
+	var streams []*models.Stream
+	err := r.client.ScanPages(&dynamodb.ScanInput{
+		TableName:        aws.String(r.tableName),
+		FilterExpression: deletedFilterExpression(models.DeletedOnly, nil),
+	}, func(page *dynamodb.ScanOutput, lastPage bool) bool {
+		for _, item := range page.Items {
+			var stream models.Stream
+			if err := r.unmarshalStream(item, &stream); err != nil {
+				slog.Warn("⚠️ Failed to unmarshal stream", "error", err)
+				continue
+			}
+			streams = append(streams, &stream)
+		}
+		return true
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan deleted streams: %w", err)
+	}
+
+	return streams, nil
+}
+
+// GetDeletedVODs returns every soft deleted VOD, scanning the table like GetDeletedStreams
+func (r *DynamoDBRepository) GetDeletedVODs() ([]*models.VOD, error) {
+	var vods []*models.VOD
+	err := r.client.ScanPages(&dynamodb.ScanInput{
+		TableName:        aws.String(r.vodTableName),
+		FilterExpression: deletedFilterExpression(models.DeletedOnly, nil),
+	}, func(page *dynamodb.ScanOutput, lastPage bool) bool {
+		for _, item := range page.Items {
+			var vod models.VOD
+			if err := dynamodbattribute.UnmarshalMap(item, &vod); err != nil {
+				slog.Warn("⚠️ Failed to unmarshal vod", "error", err)
+				continue
+			}
+			vods = append(vods, &vod)
+		}
+		return true
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan deleted vods: %w", err)
+	}
+
+	return vods, nil
+}
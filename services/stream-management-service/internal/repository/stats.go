@@ -0,0 +1,148 @@
+// services/stream-management-service/internal/repository/stats.go
+package repository
+
+import (
+	"fmt"
+	"log/slog"
+	"strconv"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/services/stream-management-service/internal/models"
+)
+
+// AddStatsSample adds a sample of live streams and viewers to a bucket and raises its peaks.
+// expiresAt is when DynamoDB deletes the bucket (unix seconds), 0 keeps it.
+func (r *DynamoDBRepository) AddStatsSample(granularity models.StatsGranularity, start time.Time, liveStreams, viewers, expiresAt int64) error {
+	key := statsKey(granularity, start)
+
+	update := "ADD samples :one, live_streams_sum :streams, viewers_sum :viewers"
+	values := map[string]*dynamodb.AttributeValue{
+		":one":     {N: aws.String("1")},
+		":streams": {N: aws.String(strconv.FormatInt(liveStreams, 10))},
+		":viewers": {N: aws.String(strconv.FormatInt(viewers, 10))},
+	}
+	if expiresAt > 0 {
+		update += " SET expires_at = :expires_at"
+		values[":expires_at"] = &dynamodb.AttributeValue{N: aws.String(strconv.FormatInt(expiresAt, 10))}
+	}
+
+	_, err := r.client.UpdateItem(&dynamodb.UpdateItemInput{
+		TableName:                 aws.String(r.statsTableName),
+		Key:                       key,
+		UpdateExpression:          aws.String(update),
+		ExpressionAttributeValues: values,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to add stats sample: %w", err)
+	}
+
+	if err := r.raiseStatsPeak(key, "live_streams_peak", liveStreams); err != nil {
+		return err
+	}
+	return r.raiseStatsPeak(key, "viewers_peak", viewers)
+}
+
+// AddStatsNewStream counts a stream that started in a bucket
+func (r *DynamoDBRepository) AddStatsNewStream(granularity models.StatsGranularity, start time.Time, expiresAt int64) error {
+	update := "ADD new_streams :one"
+	values := map[string]*dynamodb.AttributeValue{
+		":one": {N: aws.String("1")},
+	}
+	if expiresAt > 0 {
+		update += " SET expires_at = :expires_at"
+		values[":expires_at"] = &dynamodb.AttributeValue{N: aws.String(strconv.FormatInt(expiresAt, 10))}
+	}
+
+	_, err := r.client.UpdateItem(&dynamodb.UpdateItemInput{
+		TableName:                 aws.String(r.statsTableName),
+		Key:                       statsKey(granularity, start),
+		UpdateExpression:          aws.String(update),
+		ExpressionAttributeValues: values,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to count new stream: %w", err)
+	}
+
+	return nil
+}
+
+// GetStatsRollups returns the buckets of a granularity starting between from and to, oldest
+// first. Averages are computed from the sums.
+func (r *DynamoDBRepository) GetStatsRollups(granularity models.StatsGranularity, from, to time.Time) ([]*models.StatsRollup, error) {
+	input := &dynamodb.QueryInput{
+		TableName:              aws.String(r.statsTableName),
+		KeyConditionExpression: aws.String("granularity = :granularity AND #bucket BETWEEN :from AND :to"),
+		ExpressionAttributeNames: map[string]*string{
+			"#bucket": aws.String("bucket"),
+		},
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+			":granularity": {S: aws.String(string(granularity))},
+			":from":        {S: aws.String(statsBucket(from))},
+			":to":          {S: aws.String(statsBucket(to))},
+		},
+	}
+
+	var rollups []*models.StatsRollup
+	err := r.client.QueryPages(input, func(page *dynamodb.QueryOutput, lastPage bool) bool {
+		for _, item := range page.Items {
+			var rollup models.StatsRollup
+			if err := dynamodbattribute.UnmarshalMap(item, &rollup); err != nil {
+				slog.Warn("⚠️ Failed to unmarshal stats rollup", "error", err)
+				continue
+			}
+			rollup.Start, _ = time.Parse(time.RFC3339, rollup.Bucket)
+			if rollup.Samples > 0 {
+				rollup.LiveStreamsAvg = float64(rollup.LiveStreamsSum) / float64(rollup.Samples)
+				rollup.ViewersAvg = float64(rollup.ViewersSum) / float64(rollup.Samples)
+			}
+			rollups = append(rollups, &rollup)
+		}
+		return true
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to query stats rollups: %w", err)
+	}
+
+	return rollups, nil
+}
+
+// raiseStatsPeak sets a peak attribute to value if it is higher than the stored one
+func (r *DynamoDBRepository) raiseStatsPeak(key map[string]*dynamodb.AttributeValue, attribute string, value int64) error {
+	_, err := r.client.UpdateItem(&dynamodb.UpdateItemInput{
+		TableName:           aws.String(r.statsTableName),
+		Key:                 key,
+		UpdateExpression:    aws.String("SET #peak = :value"),
+		ConditionExpression: aws.String("attribute_not_exists(#peak) OR #peak < :value"),
+		ExpressionAttributeNames: map[string]*string{
+			"#peak": aws.String(attribute),
+		},
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+			":value": {N: aws.String(strconv.FormatInt(value, 10))},
+		},
+	})
+	if err != nil {
+		if aerr, ok := err.(awserr.Error); ok && aerr.Code() == dynamodb.ErrCodeConditionalCheckFailedException {
+			return nil // the stored peak is higher
+		}
+		return fmt.Errorf("failed to raise %s: %w", attribute, err)
+	}
+
+	return nil
+}
+
+func statsKey(granularity models.StatsGranularity, start time.Time) map[string]*dynamodb.AttributeValue {
+	return map[string]*dynamodb.AttributeValue{
+		"granularity": {S: aws.String(string(granularity))},
+		"bucket":      {S: aws.String(statsBucket(start))},
+	}
+}
+
+// statsBucket formats a bucket start so buckets sort chronologically
+func statsBucket(start time.Time) string {
+	return start.UTC().Format(time.RFC3339)
+}
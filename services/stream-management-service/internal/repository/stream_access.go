@@ -0,0 +1,77 @@
+// services/stream-management-service/internal/repository/stream_access.go
+package repository
+
+import (
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/services/stream-management-service/internal/models"
+)
+
+// streamAccessPartition is the stats table partition private stream access is stored in, one
+// item per stream
+const streamAccessPartition = "stream_access"
+
+// SaveStreamAccess creates or replaces who may play a private stream
+func (r *DynamoDBRepository) SaveStreamAccess(access *models.StreamAccess) error {
+	item, err := dynamodbattribute.MarshalMap(access)
+	if err != nil {
+		return fmt.Errorf("failed to marshal stream access: %w", err)
+	}
+	item["granularity"] = &dynamodb.AttributeValue{S: aws.String(streamAccessPartition)}
+
+	_, err = r.client.PutItem(&dynamodb.PutItemInput{
+		TableName: aws.String(r.statsTableName),
+		Item:      item,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to put stream access: %w", err)
+	}
+
+	return nil
+}
+
+// GetStreamAccess returns who may play a private stream. Streams nobody was given access to
+// get an empty StreamAccess.
+func (r *DynamoDBRepository) GetStreamAccess(streamID string) (*models.StreamAccess, error) {
+	result, err := r.client.GetItem(&dynamodb.GetItemInput{
+		TableName: aws.String(r.statsTableName),
+		Key: map[string]*dynamodb.AttributeValue{
+			"granularity": {S: aws.String(streamAccessPartition)},
+			"bucket":      {S: aws.String(streamID)},
+		},
+		ConsistentRead: aws.Bool(true),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get stream access: %w", err)
+	}
+
+	access := &models.StreamAccess{StreamID: streamID}
+	if result.Item == nil {
+		return access, nil
+	}
+	if err := dynamodbattribute.UnmarshalMap(result.Item, access); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal stream access: %w", err)
+	}
+
+	return access, nil
+}
+
+// DeleteStreamAccess removes who may play a private stream
+func (r *DynamoDBRepository) DeleteStreamAccess(streamID string) error {
+	_, err := r.client.DeleteItem(&dynamodb.DeleteItemInput{
+		TableName: aws.String(r.statsTableName),
+		Key: map[string]*dynamodb.AttributeValue{
+			"granularity": {S: aws.String(streamAccessPartition)},
+			"bucket":      {S: aws.String(streamID)},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to delete stream access: %w", err)
+	}
+
+	return nil
+}
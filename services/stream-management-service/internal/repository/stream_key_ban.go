@@ -0,0 +1,75 @@
+// services/stream-management-service/internal/repository/stream_key_ban.go
+package repository
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/services/stream-management-service/internal/models"
+)
+
+// ErrStreamKeyNotBanned is returned for stream keys without a ban
+var ErrStreamKeyNotBanned = errors.New("stream key is not banned")
+
+// SaveStreamKeyBan creates or replaces the ban of a stream key
+func (r *DynamoDBRepository) SaveStreamKeyBan(ban *models.StreamKeyBan) error {
+	item, err := dynamodbattribute.MarshalMap(ban)
+	if err != nil {
+		return fmt.Errorf("failed to marshal stream key ban: %w", err)
+	}
+
+	_, err = r.client.PutItem(&dynamodb.PutItemInput{
+		TableName: aws.String(r.streamKeyBanTable),
+		Item:      item,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to put stream key ban: %w", err)
+	}
+
+	return nil
+}
+
+func (r *DynamoDBRepository) GetStreamKeyBan(streamKey string) (*models.StreamKeyBan, error) {
+	result, err := r.client.GetItem(&dynamodb.GetItemInput{
+		TableName: aws.String(r.streamKeyBanTable),
+		Key: map[string]*dynamodb.AttributeValue{
+			"stream_key": {
+				S: aws.String(streamKey),
+			},
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get stream key ban: %w", err)
+	}
+
+	if result.Item == nil {
+		return nil, ErrStreamKeyNotBanned
+	}
+
+	var ban models.StreamKeyBan
+	if err := dynamodbattribute.UnmarshalMap(result.Item, &ban); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal stream key ban: %w", err)
+	}
+
+	return &ban, nil
+}
+
+func (r *DynamoDBRepository) DeleteStreamKeyBan(streamKey string) error {
+	_, err := r.client.DeleteItem(&dynamodb.DeleteItemInput{
+		TableName: aws.String(r.streamKeyBanTable),
+		Key: map[string]*dynamodb.AttributeValue{
+			"stream_key": {
+				S: aws.String(streamKey),
+			},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to delete stream key ban: %w", err)
+	}
+
+	return nil
+}
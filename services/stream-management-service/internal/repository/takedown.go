@@ -0,0 +1,116 @@
+// services/stream-management-service/internal/repository/takedown.go
+package repository
+
+import (
+	"fmt"
+	"log/slog"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/services/stream-management-service/internal/models"
+)
+
+// SaveTakedown creates or replaces a takedown
+func (r *DynamoDBRepository) SaveTakedown(takedown *models.Takedown) error {
+	item, err := dynamodbattribute.MarshalMap(takedown)
+	if err != nil {
+		return fmt.Errorf("failed to marshal takedown: %w", err)
+	}
+
+	_, err = r.client.PutItem(&dynamodb.PutItemInput{
+		TableName: aws.String(r.takedownTableName),
+		Item:      item,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to put takedown: %w", err)
+	}
+
+	return nil
+}
+
+func (r *DynamoDBRepository) GetTakedownByID(takedownID string) (*models.Takedown, error) {
+	result, err := r.client.GetItem(&dynamodb.GetItemInput{
+		TableName: aws.String(r.takedownTableName),
+		Key: map[string]*dynamodb.AttributeValue{
+			"id": {
+				S: aws.String(takedownID),
+			},
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get takedown: %w", err)
+	}
+
+	if result.Item == nil {
+		return nil, fmt.Errorf("takedown not found")
+	}
+
+	var takedown models.Takedown
+	if err := dynamodbattribute.UnmarshalMap(result.Item, &takedown); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal takedown: %w", err)
+	}
+
+	return &takedown, nil
+}
+
+// ListTakedowns returns a page of takedowns. An empty status returns takedowns of every status.
+func (r *DynamoDBRepository) ListTakedowns(status models.TakedownStatus, limit int, cursor string) ([]*models.Takedown, string, error) {
+	startKey, err := decodeCursor(cursor)
+	if err != nil {
+		return nil, "", err
+	}
+
+	var items []map[string]*dynamodb.AttributeValue
+	var lastKey map[string]*dynamodb.AttributeValue
+
+	if status != "" {
+		result, err := r.client.Query(&dynamodb.QueryInput{
+			TableName:              aws.String(r.takedownTableName),
+			IndexName:              aws.String("status-index"),
+			KeyConditionExpression: aws.String("#status = :status"),
+			ExpressionAttributeNames: map[string]*string{
+				"#status": aws.String("status"),
+			},
+			ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+				":status": {
+					S: aws.String(string(status)),
+				},
+			},
+			Limit:             aws.Int64(int64(limit)),
+			ExclusiveStartKey: startKey,
+		})
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to query takedowns: %w", err)
+		}
+		items, lastKey = result.Items, result.LastEvaluatedKey
+	} else {
+		result, err := r.client.Scan(&dynamodb.ScanInput{
+			TableName:         aws.String(r.takedownTableName),
+			Limit:             aws.Int64(int64(limit)),
+			ExclusiveStartKey: startKey,
+		})
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to scan takedowns: %w", err)
+		}
+		items, lastKey = result.Items, result.LastEvaluatedKey
+	}
+
+	takedowns := make([]*models.Takedown, 0, len(items))
+	for _, item := range items {
+		var takedown models.Takedown
+		if err := dynamodbattribute.UnmarshalMap(item, &takedown); err != nil {
+			slog.Warn("⚠️ Failed to unmarshal takedown", "error", err)
+			continue
+		}
+		takedowns = append(takedowns, &takedown)
+	}
+
+	nextCursor, err := encodeCursor(lastKey)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return takedowns, nextCursor, nil
+}
@@ -0,0 +1,99 @@
+// services/stream-management-service/internal/repository/user_data_job.go
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/services/stream-management-service/internal/models"
+)
+
+// userDataJobPartition is the stats table partition user data jobs are stored in, one item
+// per job, expired by the table's TTL
+const userDataJobPartition = "user_data_job"
+
+var ErrUserDataJobNotFound = errors.New("user data job not found")
+
+// SaveUserDataJob creates or replaces a user data job
+func (r *DynamoDBRepository) SaveUserDataJob(job *models.UserDataJob) error {
+	item, err := dynamodbattribute.MarshalMap(job)
+	if err != nil {
+		return fmt.Errorf("failed to marshal user data job: %w", err)
+	}
+	item["granularity"] = &dynamodb.AttributeValue{S: aws.String(userDataJobPartition)}
+
+	_, err = r.client.PutItem(&dynamodb.PutItemInput{
+		TableName: aws.String(r.statsTableName),
+		Item:      item,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to put user data job: %w", err)
+	}
+
+	return nil
+}
+
+// GetUserDataJob returns a user data job, or ErrUserDataJobNotFound
+func (r *DynamoDBRepository) GetUserDataJob(jobID string) (*models.UserDataJob, error) {
+	result, err := r.client.GetItem(&dynamodb.GetItemInput{
+		TableName: aws.String(r.statsTableName),
+		Key: map[string]*dynamodb.AttributeValue{
+			"granularity": {S: aws.String(userDataJobPartition)},
+			"bucket":      {S: aws.String(jobID)},
+		},
+		ConsistentRead: aws.Bool(true),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user data job: %w", err)
+	}
+	if result.Item == nil {
+		return nil, ErrUserDataJobNotFound
+	}
+
+	var job models.UserDataJob
+	if err := dynamodbattribute.UnmarshalMap(result.Item, &job); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal user data job: %w", err)
+	}
+
+	return &job, nil
+}
+
+// GetUserDataJobs returns the jobs of a user, or of every user when userID is 0. Jobs that
+// finished stay until they expire.
+func (r *DynamoDBRepository) GetUserDataJobs(userID int64) ([]*models.UserDataJob, error) {
+	input := &dynamodb.QueryInput{
+		TableName:              aws.String(r.statsTableName),
+		KeyConditionExpression: aws.String("granularity = :partition"),
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+			":partition": {S: aws.String(userDataJobPartition)},
+		},
+	}
+	if userID != 0 {
+		input.FilterExpression = aws.String("user_id = :user_id")
+		input.ExpressionAttributeValues[":user_id"] = &dynamodb.AttributeValue{N: aws.String(strconv.FormatInt(userID, 10))}
+	}
+
+	var jobs []*models.UserDataJob
+	err := r.client.QueryPages(input, func(page *dynamodb.QueryOutput, lastPage bool) bool {
+		for _, item := range page.Items {
+			var job models.UserDataJob
+			if err := dynamodbattribute.UnmarshalMap(item, &job); err != nil {
+				slog.Warn("⚠️ Failed to unmarshal user data job", "error", err)
+				continue
+			}
+			jobs = append(jobs, &job)
+		}
+		return true
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to query user data jobs: %w", err)
+	}
+
+	return jobs, nil
+}
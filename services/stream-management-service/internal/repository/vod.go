@@ -0,0 +1,222 @@
+// services/stream-management-service/internal/repository/vod.go
+package repository
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/services/stream-management-service/internal/models"
+)
+
+func (r *DynamoDBRepository) CreateVOD(vod *models.VOD) error {
+	item, err := dynamodbattribute.MarshalMap(vod)
+	if err != nil {
+		return fmt.Errorf("failed to marshal vod: %w", err)
+	}
+
+	_, err = r.client.PutItem(&dynamodb.PutItemInput{
+		TableName: aws.String(r.vodTableName),
+		Item:      item,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to put vod: %w", err)
+	}
+
+	slog.Debug("✅ VOD created in DynamoDB", "vod_id", vod.ID, "stream_id", vod.StreamID)
+	return nil
+}
+
+// SaveVOD replaces a VOD, e.g. after its premiere changed
+func (r *DynamoDBRepository) SaveVOD(vod *models.VOD) error {
+	item, err := dynamodbattribute.MarshalMap(vod)
+	if err != nil {
+		return fmt.Errorf("failed to marshal vod: %w", err)
+	}
+
+	_, err = r.client.PutItem(&dynamodb.PutItemInput{
+		TableName: aws.String(r.vodTableName),
+		Item:      item,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to put vod: %w", err)
+	}
+
+	return nil
+}
+
+func (r *DynamoDBRepository) GetVODByID(vodID string) (*models.VOD, error) {
+	result, err := r.client.GetItem(&dynamodb.GetItemInput{
+		TableName: aws.String(r.vodTableName),
+		Key: map[string]*dynamodb.AttributeValue{
+			"id": {
+				S: aws.String(vodID),
+			},
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get vod: %w", err)
+	}
+
+	if result.Item == nil {
+		return nil, fmt.Errorf("vod not found")
+	}
+
+	var vod models.VOD
+	if err := dynamodbattribute.UnmarshalMap(result.Item, &vod); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal vod: %w", err)
+	}
+
+	return &vod, nil
+}
+
+// ListVODs returns a page of VODs with the given visibility, newest first
+func (r *DynamoDBRepository) ListVODs(visibility models.VODVisibility, deleted models.DeletedFilter, limit int, cursor string) ([]*models.VOD, string, error) {
+	startKey, err := decodeCursor(cursor)
+	if err != nil {
+		return nil, "", err
+	}
+
+	input := &dynamodb.QueryInput{
+		TableName:              aws.String(r.vodTableName),
+		IndexName:              aws.String("visibility-created-index"),
+		KeyConditionExpression: aws.String("#visibility = :visibility"),
+		FilterExpression:       deletedFilterExpression(deleted, nil),
+		ExpressionAttributeNames: map[string]*string{
+			"#visibility": aws.String("visibility"),
+		},
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+			":visibility": {
+				S: aws.String(string(visibility)),
+			},
+		},
+		ScanIndexForward:  aws.Bool(false),
+		Limit:             aws.Int64(int64(limit)),
+		ExclusiveStartKey: startKey,
+	}
+
+	result, err := r.client.Query(input)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to query vods: %w", err)
+	}
+
+	return unmarshalVODPage(result)
+}
+
+// GetVODsByUser returns a page of a user's VODs, newest first. An empty
+// visibility returns VODs of every visibility.
+func (r *DynamoDBRepository) GetVODsByUser(userID int64, visibility models.VODVisibility, deleted models.DeletedFilter, limit int, cursor string) ([]*models.VOD, string, error) {
+	startKey, err := decodeCursor(cursor)
+	if err != nil {
+		return nil, "", err
+	}
+
+	input := &dynamodb.QueryInput{
+		TableName:              aws.String(r.vodTableName),
+		IndexName:              aws.String("user-id-created-index"),
+		KeyConditionExpression: aws.String("user_id = :user_id"),
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+			":user_id": {
+				N: aws.String(strconv.FormatInt(userID, 10)),
+			},
+		},
+		ScanIndexForward:  aws.Bool(false),
+		Limit:             aws.Int64(int64(limit)),
+		ExclusiveStartKey: startKey,
+	}
+
+	if visibility != "" {
+		input.FilterExpression = aws.String("#visibility = :visibility")
+		input.ExpressionAttributeNames = map[string]*string{
+			"#visibility": aws.String("visibility"),
+		}
+		input.ExpressionAttributeValues[":visibility"] = &dynamodb.AttributeValue{
+			S: aws.String(string(visibility)),
+		}
+	}
+	input.FilterExpression = deletedFilterExpression(deleted, input.FilterExpression)
+
+	result, err := r.client.Query(input)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to query user vods: %w", err)
+	}
+
+	return unmarshalVODPage(result)
+}
+
+func unmarshalVODPage(result *dynamodb.QueryOutput) ([]*models.VOD, string, error) {
+	vods := make([]*models.VOD, 0, len(result.Items))
+	for _, item := range result.Items {
+		var vod models.VOD
+		if err := dynamodbattribute.UnmarshalMap(item, &vod); err != nil {
+			slog.Warn("⚠️ Failed to unmarshal vod", "error", err)
+			continue
+		}
+		vods = append(vods, &vod)
+	}
+
+	nextCursor, err := encodeCursor(result.LastEvaluatedKey)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return vods, nextCursor, nil
+}
+
+// encodeCursor turns a DynamoDB LastEvaluatedKey into an opaque pagination cursor
+func encodeCursor(key map[string]*dynamodb.AttributeValue) (string, error) {
+	if len(key) == 0 {
+		return "", nil
+	}
+
+	data, err := json.Marshal(key)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode cursor: %w", err)
+	}
+
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+// ErrInvalidCursor means a pagination cursor isn't one a list handed out
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// decodeCursor turns an opaque pagination cursor back into an ExclusiveStartKey
+func decodeCursor(cursor string) (map[string]*dynamodb.AttributeValue, error) {
+	if cursor == "" {
+		return nil, nil
+	}
+
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidCursor, err)
+	}
+
+	var key map[string]*dynamodb.AttributeValue
+	if err := json.Unmarshal(data, &key); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidCursor, err)
+	}
+
+	return key, nil
+}
+
+// DeleteVOD removes a VOD from the table. Its files in S3 are deleted separately.
+func (r *DynamoDBRepository) DeleteVOD(vodID string) error {
+	_, err := r.client.DeleteItem(&dynamodb.DeleteItemInput{
+		TableName: aws.String(r.vodTableName),
+		Key: map[string]*dynamodb.AttributeValue{
+			"id": {S: aws.String(vodID)},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to delete vod: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,78 @@
+// services/stream-management-service/internal/server/gateway.go
+package server
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+
+	commonpb "github.com/Saoudyahya/Live-Streaming-Platform-Architecture/services/stream-management-service/gen/common"
+	streampb "github.com/Saoudyahya/Live-Streaming-Platform-Architecture/services/stream-management-service/gen/stream"
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/services/stream-management-service/internal/config"
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/services/stream-management-service/internal/models"
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/services/stream-management-service/internal/service"
+	grpcClient "github.com/Saoudyahya/Live-Streaming-Platform-Architecture/services/stream-management-service/pkg/grpc"
+)
+
+// NewGatewayHandler serves the StreamService RPCs as REST under /rpc/v1, routed by the
+// grpc-gateway code generated from proto/stream/stream_service_http.yaml. Requests are handed
+// to the gRPC handlers in process, so both surfaces share one implementation and one
+// conversion between models and messages.
+func NewGatewayHandler(ctx context.Context, cfg *config.Config, streamService *service.StreamService, streamKeys *service.StreamKeyService, ladders *service.QualityLadderService, userClient *grpcClient.UserServiceClient) (http.Handler, error) {
+	mux := runtime.NewServeMux(
+		runtime.WithMarshalerOption(runtime.MIMEWildcard, &runtime.JSONPb{
+			MarshalOptions:   protojson.MarshalOptions{UseProtoNames: true, EmitUnpopulated: true},
+			UnmarshalOptions: protojson.UnmarshalOptions{DiscardUnknown: true},
+		}),
+		runtime.WithForwardResponseOption(gatewayResponseStatus),
+	)
+
+	streamServer := NewStreamGRPCServer(cfg, streamService, streamKeys, ladders, userClient)
+	if err := streampb.RegisterStreamServiceHandlerServer(ctx, mux, streamServer); err != nil {
+		return nil, fmt.Errorf("failed to register stream service gateway: %w", err)
+	}
+
+	return gatewayAuditOrigin(mux), nil
+}
+
+// statusResponse is a response carrying its outcome in a common.Status, like every
+// StreamService response
+type statusResponse interface {
+	GetStatus() *commonpb.Status
+}
+
+// gatewayResponseStatus turns the gRPC code in a failed response's status into the matching
+// HTTP status; the gRPC handlers answer failures as regular responses
+func gatewayResponseStatus(ctx context.Context, w http.ResponseWriter, resp proto.Message) error {
+	response, ok := resp.(statusResponse)
+	if !ok || response.GetStatus() == nil || response.GetStatus().GetSuccess() {
+		return nil
+	}
+
+	code := codes.Code(response.GetStatus().GetCode())
+	if code == codes.OK {
+		return nil
+	}
+	// Headers are set by now, the marshaled response is written right after
+	w.WriteHeader(runtime.HTTPStatusFromCode(code))
+	return nil
+}
+
+// gatewayAuditOrigin attributes what gateway requests change to the calling service, as the
+// gRPC audit interceptor does for gRPC calls
+func gatewayAuditOrigin(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		caller := r.Header.Get("X-Caller")
+		if caller == "" {
+			caller = r.UserAgent()
+		}
+
+		actor := models.AuditActor{Type: models.AuditActorService, ID: caller}
+		next.ServeHTTP(w, r.WithContext(service.WithAuditActor(r.Context(), actor, service.AuditSourceGRPC)))
+	})
+}
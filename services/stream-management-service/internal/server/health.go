@@ -0,0 +1,34 @@
+// services/stream-management-service/internal/server/health.go
+package server
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/services/stream-management-service/internal/health"
+)
+
+// Livez is the Kubernetes liveness probe. It only tells that the process serves requests;
+// dependencies being down is no reason to restart it.
+func Livez(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"status":    "ok",
+		"timestamp": time.Now().Unix(),
+	})
+}
+
+// Readyz is the Kubernetes readiness probe, failing while a critical dependency is down or
+// the service is shutting down
+func Readyz(checker *health.Checker) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		report := checker.Run(c.Request.Context())
+
+		status := http.StatusOK
+		if !report.Ready {
+			status = http.StatusServiceUnavailable
+		}
+		c.JSON(status, report)
+	}
+}
@@ -2,19 +2,29 @@
 package server
 
 import (
-	"fmt"
-	_ "log"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"log/slog"
+	"net/http"
 	"time"
 
 	"github.com/gin-gonic/gin"
+
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/services/stream-management-service/internal/logging"
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/services/stream-management-service/pkg/tracing"
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/shared/go/pkg/identity"
 )
 
+// RequestIDHeader carries the ID that correlates log lines across services
+const RequestIDHeader = identity.HeaderRequestID
+
 func CORSMiddleware() gin.HandlerFunc {
 	return gin.HandlerFunc(func(c *gin.Context) {
 		c.Writer.Header().Set("Access-Control-Allow-Origin", "*")
 		c.Writer.Header().Set("Access-Control-Allow-Credentials", "true")
-		c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, accept, origin, Cache-Control, X-Requested-With")
-		c.Writer.Header().Set("Access-Control-Allow-Methods", "POST, OPTIONS, GET, PUT, DELETE")
+		c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, X-API-Key, X-User-ID, X-Request-ID, traceparent, accept, origin, Cache-Control, X-Requested-With")
+		c.Writer.Header().Set("Access-Control-Allow-Methods", "POST, OPTIONS, GET, PUT, PATCH, DELETE")
 
 		if c.Request.Method == "OPTIONS" {
 			c.AbortWithStatus(204)
@@ -25,21 +35,91 @@ func CORSMiddleware() gin.HandlerFunc {
 	})
 }
 
+// TracingMiddleware continues the caller's trace, or starts one, for every request. Handlers
+// pass c.Request.Context() on so the spans of the calls they make join the trace.
+func TracingMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := tracing.Extract(c.Request.Context(), c.Request.Header)
+
+		route := c.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+		ctx, span := tracing.Start(ctx, c.Request.Method+" "+route, tracing.SpanKindServer)
+		defer span.End()
+
+		span.SetAttribute("http.method", c.Request.Method)
+		span.SetAttribute("http.route", route)
+		span.SetAttribute("http.target", c.Request.URL.Path)
+		span.SetAttribute("net.peer.ip", c.ClientIP())
+
+		c.Request = c.Request.WithContext(ctx)
+		c.Header("X-Trace-ID", span.SpanContext().TraceID.String())
+
+		c.Next()
+
+		status := c.Writer.Status()
+		span.SetAttribute("http.status_code", status)
+		if status >= http.StatusInternalServerError {
+			span.SetStatus(tracing.StatusError, http.StatusText(status))
+		}
+	}
+}
+
+// RequestIDMiddleware keeps the caller's X-Request-ID, or assigns one, and attaches it to
+// the request context so every log line written for the request, and every service called for
+// it, carries it.
+func RequestIDMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requestID := c.GetHeader(RequestIDHeader)
+		if requestID == "" || len(requestID) > 128 {
+			requestID = NewRequestID()
+		}
+
+		c.Header(RequestIDHeader, requestID)
+		ctx := logging.With(c.Request.Context(), "request_id", requestID)
+		c.Request = c.Request.WithContext(identity.WithRequestID(ctx, requestID))
+
+		c.Next()
+	}
+}
+
+// NewRequestID returns a random 16 byte hex ID
+func NewRequestID() string {
+	id := make([]byte, 16)
+	_, _ = rand.Read(id)
+	return hex.EncodeToString(id)
+}
+
 func LoggingMiddleware() gin.HandlerFunc {
-	return gin.LoggerWithFormatter(func(param gin.LogFormatterParams) string {
-		return fmt.Sprintf("🌐 %s - [%s] \"%s %s %s\" %d %s \"%s\" \"%s\" %s\n",
-			param.ClientIP,
-			param.TimeStamp.Format("02/Jan/2006:15:04:05 -0700"),
-			param.Method,
-			param.Path,
-			param.Request.Proto,
-			param.StatusCode,
-			param.Latency,
-			param.Request.UserAgent(),
-			param.Request.Referer(),
-			param.ErrorMessage,
-		)
-	})
+	return func(c *gin.Context) {
+		start := time.Now()
+
+		c.Next()
+
+		status := c.Writer.Status()
+		level := slog.LevelInfo
+		switch {
+		case status >= http.StatusInternalServerError:
+			level = slog.LevelError
+		case status >= http.StatusBadRequest:
+			level = slog.LevelWarn
+		}
+
+		attrs := []any{
+			"method", c.Request.Method,
+			"path", c.Request.URL.Path,
+			"status", status,
+			"latency", time.Since(start),
+			"client_ip", c.ClientIP(),
+			"user_agent", c.Request.UserAgent(),
+		}
+		if errs := c.Errors.ByType(gin.ErrorTypePrivate).String(); errs != "" {
+			attrs = append(attrs, "error", errs)
+		}
+
+		slog.Log(c.Request.Context(), level, "🌐 HTTP request", attrs...)
+	}
 }
 
 func HealthCheck(c *gin.Context) {
@@ -50,3 +130,25 @@ func HealthCheck(c *gin.Context) {
 		"version":   "1.0.0",
 	})
 }
+
+// AdminAuthMiddleware protects admin routes with a shared token. Without a token
+// configured, admin routes are only reachable in development.
+func AdminAuthMiddleware(token, environment string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if token == "" {
+			if environment == "development" {
+				c.Next()
+				return
+			}
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "Admin API is disabled"})
+			return
+		}
+
+		if subtle.ConstantTimeCompare([]byte(c.GetHeader("X-Admin-Token")), []byte(token)) != 1 {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Invalid admin token"})
+			return
+		}
+
+		c.Next()
+	}
+}
@@ -0,0 +1,29 @@
+// services/stream-management-service/internal/server/status.go
+package server
+
+import (
+	"google.golang.org/grpc/codes"
+
+	commonpb "github.com/Saoudyahya/Live-Streaming-Platform-Architecture/services/stream-management-service/gen/common"
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/shared/go/pkg/apperrors"
+)
+
+// okStatus is the status of a call that succeeded
+func okStatus(message string) *commonpb.Status {
+	return &commonpb.Status{
+		Code:    int32(codes.OK),
+		Message: message,
+		Success: true,
+	}
+}
+
+// errorStatus is the status of a call that failed with err, coded the way the shared error
+// model answers it. The causes of internal errors aren't shown, log them where they happen.
+func errorStatus(err error) *commonpb.Status {
+	appErr := apperrors.As(err)
+	return &commonpb.Status{
+		Code:    int32(appErr.Code.GRPCCode()),
+		Message: appErr.Message,
+		Success: false,
+	}
+}
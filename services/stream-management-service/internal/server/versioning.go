@@ -0,0 +1,51 @@
+// services/stream-management-service/internal/server/versioning.go
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Deprecation marks the responses of an API version that has a successor with the
+// Deprecation (RFC 9745) and, when a date is set, Sunset (RFC 8594) headers. Routes the
+// successor already serves link to their replacement, the others to the successor's root.
+func Deprecation(router *gin.Engine, deprecatedAt, sunsetAt time.Time, prefix, successorPrefix string) gin.HandlerFunc {
+	deprecation := "true"
+	if !deprecatedAt.IsZero() {
+		deprecation = fmt.Sprintf("@%d", deprecatedAt.Unix())
+	}
+
+	// Routes are only all registered once the first request comes in
+	var once sync.Once
+	successors := make(map[string]bool)
+	successorRoutes := func() map[string]bool {
+		once.Do(func() {
+			for _, route := range router.Routes() {
+				if strings.HasPrefix(route.Path, successorPrefix+"/") {
+					successors[route.Method+" "+route.Path] = true
+				}
+			}
+		})
+		return successors
+	}
+
+	return func(c *gin.Context) {
+		c.Header("Deprecation", deprecation)
+		if !sunsetAt.IsZero() {
+			c.Header("Sunset", sunsetAt.UTC().Format(http.TimeFormat))
+		}
+
+		successor := successorPrefix
+		if path, ok := strings.CutPrefix(c.FullPath(), prefix); ok && successorRoutes()[c.Request.Method+" "+successorPrefix+path] {
+			successor = successorPrefix + strings.TrimPrefix(c.Request.URL.Path, prefix)
+		}
+		c.Header("Link", fmt.Sprintf("<%s>; rel=\"successor-version\"", successor))
+
+		c.Next()
+	}
+}
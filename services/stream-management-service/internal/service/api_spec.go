@@ -0,0 +1,262 @@
+// services/stream-management-service/internal/service/api_spec.go
+package service
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/services/stream-management-service/internal/models"
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/services/stream-management-service/internal/openapi"
+)
+
+// maxValidatedBodySize bounds the bodies read for validation, every API body is far smaller
+const maxValidatedBodySize = 1 << 20
+
+// NewAPISpec describes the public REST API as an OpenAPI 3 document. Request bodies are
+// derived from the request types the handlers bind and checked by ValidateRequests, so the
+// document can't drift from what the handlers accept.
+func NewAPISpec(version string) *openapi.Spec {
+	spec := openapi.New(openapi.Info{
+		Title:       "Stream Management API",
+		Description: "Live streams, VODs and clips. v1 is deprecated in favor of v2, which pages lists and answers typed errors.",
+		Version:     version,
+	})
+
+	spec.SecurityScheme("apiKey", openapi.SecurityScheme{
+		Type:        "apiKey",
+		In:          "header",
+		Name:        APIKeyHeader,
+		Description: "Optional unless API keys are required, scopes limit what a key may call",
+	})
+	spec.SecurityScheme("viewer", openapi.SecurityScheme{
+		Type:         "http",
+		Scheme:       "bearer",
+		BearerFormat: "JWT",
+		Description:  "A signed in viewer, needed for the routes acting on their own streams and data",
+	})
+
+	spec.Enum(models.StreamStatus(""), models.StreamStatusPending, models.StreamStatusLive, models.StreamStatusReconnecting, models.StreamStatusEnded, models.StreamStatusError)
+	spec.Enum(models.StreamVisibility(""), models.StreamVisibilityPublic, models.StreamVisibilityUnlisted, models.StreamVisibilityPrivate)
+	spec.Enum(models.VODVisibility(""), models.VODVisibilityPublic, models.VODVisibilityUnlisted, models.VODVisibilityPrivate)
+	spec.Enum(ErrorCode(""), ErrCodeInvalidRequest, ErrCodeUnauthorized, ErrCodeForbidden, ErrCodeNotFound, ErrCodeConflict, ErrCodeFailedPrecondition, ErrCodeRateLimited, ErrCodeContentBlocked, ErrCodeTimeout, ErrCodeInternal, ErrCodeUnavailable)
+
+	spec.ErrorResponse("/api/v1", spec.Component("ErrorV1", openapi.Object(map[string]*openapi.Schema{
+		"error": openapi.Primitive("string"),
+	}, "error")))
+	spec.ErrorResponse("/api/v2", spec.Component("ErrorV2", openapi.Object(map[string]*openapi.Schema{
+		"error": spec.SchemaOf(APIError{}),
+	}, "error")))
+
+	spec.Tag("streams", "Live streams and their settings")
+	spec.Tag("playback", "Playback authorization and viewer analytics")
+	spec.Tag("vods", "Recordings of past streams")
+	spec.Tag("clips", "Clips cut from streams")
+	spec.Tag("restream", "Forwarding streams to other platforms")
+	spec.Tag("squads", "Live streams watched together")
+	spec.Tag("users", "A user's data")
+
+	for _, route := range apiV1Routes(spec) {
+		route.Path = "/api/v1" + route.Path
+		route.Deprecated = true
+		spec.Add(route)
+	}
+	for _, route := range apiV2Routes(spec) {
+		route.Path = "/api/v2" + route.Path
+		spec.Add(route)
+	}
+	return spec
+}
+
+var (
+	limitParam   = openapi.Parameter{Name: "limit", In: "query", Description: "Page size", Schema: openapi.Primitive("integer")}
+	cursorParam  = openapi.Parameter{Name: "cursor", In: "query", Description: "next_cursor of the previous page", Schema: openapi.Primitive("string")}
+	deletedParam = openapi.Parameter{Name: "deleted", In: "query", Description: "Soft deleted items, for their owner", Schema: &openapi.Schema{
+		Type: "string",
+		Enum: []any{models.DeletedExclude, models.DeletedInclude, models.DeletedOnly},
+	}}
+)
+
+// anyAuth is for routes open to API keys and anonymous viewers, signedIn for the ones a
+// viewer must be signed in for
+var (
+	anyAuth  = []string{"apiKey"}
+	signedIn = []string{"viewer", "apiKey"}
+)
+
+func apiV1Routes(spec *openapi.Spec) []openapi.Route {
+	stream := spec.SchemaOf(models.Stream{})
+	vod := spec.SchemaOf(models.VOD{})
+	clip := spec.SchemaOf(models.Clip{})
+	v1List := func(key string, items *openapi.Schema) *openapi.Schema {
+		return openapi.Object(map[string]*openapi.Schema{
+			key:           openapi.ArrayOf(items),
+			"count":       openapi.Primitive("integer"),
+			"next_cursor": openapi.Primitive("string"),
+		})
+	}
+
+	return []openapi.Route{
+		{Method: http.MethodGet, Path: "/streams", Tag: "streams", Summary: "List every live stream", Security: anyAuth, Response: v1List("streams", stream)},
+		{Method: http.MethodGet, Path: "/directory", Tag: "streams", Summary: "Browse the live directory", Security: anyAuth,
+			Query: []openapi.Parameter{limitParam, {Name: "language", In: "query", Schema: openapi.Primitive("string")}}},
+		{Method: http.MethodGet, Path: "/categories", Tag: "streams", Summary: "List categories with live streams", Security: anyAuth,
+			Query: []openapi.Parameter{{Name: "sort", In: "query", Schema: openapi.Primitive("string")}, limitParam, cursorParam}},
+		{Method: http.MethodGet, Path: "/categories/:id/streams", Tag: "streams", Summary: "List the live streams of a category", Security: anyAuth,
+			Query: []openapi.Parameter{{Name: "sort", In: "query", Schema: openapi.Primitive("string")}, limitParam, cursorParam}},
+		{Method: http.MethodGet, Path: "/streams/search", Tag: "streams", Summary: "Search live streams", Security: anyAuth,
+			Query: []openapi.Parameter{
+				{Name: "q", In: "query", Schema: openapi.Primitive("string")},
+				{Name: "category", In: "query", Schema: openapi.Primitive("string")},
+				{Name: "language", In: "query", Schema: openapi.Primitive("string")},
+				{Name: "min_viewers", In: "query", Schema: openapi.Primitive("integer")},
+				{Name: "max_viewers", In: "query", Schema: openapi.Primitive("integer")},
+				{Name: "sort", In: "query", Schema: &openapi.Schema{Type: "string", Enum: []any{"viewers", "recent"}}},
+				limitParam, cursorParam,
+			}},
+		{Method: http.MethodGet, Path: "/streams/:id", Tag: "streams", Summary: "Get a stream", Security: anyAuth, Response: stream},
+		{Method: http.MethodPatch, Path: "/streams/:id", Tag: "streams", Summary: "Update a stream's title, category, tags and language", Security: signedIn,
+			Body: UpdateStreamDetailsRequest{}, Response: stream},
+		{Method: http.MethodDelete, Path: "/streams/:id", Tag: "streams", Summary: "Soft delete a stream", Security: signedIn},
+		{Method: http.MethodPost, Path: "/streams/:id/restore", Tag: "streams", Summary: "Restore a soft deleted stream", Security: signedIn},
+		{Method: http.MethodGet, Path: "/users/:id/streams", Tag: "streams", Summary: "List a user's streams", Security: signedIn,
+			Query: []openapi.Parameter{deletedParam, limitParam, cursorParam}},
+		{Method: http.MethodGet, Path: "/streams/:id/geo-restrictions", Tag: "streams", Summary: "Get a stream's geo restrictions", Security: signedIn},
+		{Method: http.MethodPut, Path: "/streams/:id/geo-restrictions", Tag: "streams", Summary: "Set a stream's geo restrictions", Security: signedIn, Body: GeoRestrictionsRequest{}},
+		{Method: http.MethodGet, Path: "/streams/:id/visibility", Tag: "streams", Summary: "Get a stream's visibility", Security: signedIn},
+		{Method: http.MethodPut, Path: "/streams/:id/visibility", Tag: "streams", Summary: "Set a stream's visibility", Security: signedIn, Body: StreamVisibilityRequest{}},
+		{Method: http.MethodPost, Path: "/playback/authorize", Tag: "playback", Summary: "Authorize playback of a stream", Security: anyAuth, Body: PlaybackAuthorizeRequest{}},
+		{Method: http.MethodPost, Path: "/streams/:id/playback-token", Tag: "playback", Summary: "Issue a playback token", Security: anyAuth, Body: PlaybackTokenRequest{}},
+		{Method: http.MethodPost, Path: "/streams/:id/markers", Tag: "streams", Summary: "Mark the current position of a live stream", Security: signedIn,
+			Body: CreateMarkerRequest{}, Status: http.StatusCreated},
+		{Method: http.MethodGet, Path: "/streams/:id/markers", Tag: "streams", Summary: "List a stream's markers", Security: signedIn},
+		{Method: http.MethodPost, Path: "/streams/:id/raid", Tag: "streams", Summary: "Send a stream's viewers to another live stream", Security: signedIn,
+			Body: StartRaidRequest{}, Status: http.StatusCreated},
+		{Method: http.MethodGet, Path: "/streams/:id/audit", Tag: "streams", Summary: "List what happened to a stream", Security: signedIn},
+		{Method: http.MethodGet, Path: "/streams/:id/health", Tag: "streams", Summary: "Get a stream's ingest health", Security: anyAuth},
+		{Method: http.MethodPost, Path: "/streams/:id/heartbeat", Tag: "playback", Summary: "Count a viewer as watching", Security: anyAuth,
+			Body: heartbeatRequest{}, Status: http.StatusNoContent},
+		{Method: http.MethodGet, Path: "/streams/:id/analytics", Tag: "streams", Summary: "Get a stream's viewer analytics", Security: signedIn},
+		{Method: http.MethodGet, Path: "/users/:id/stream-analytics", Tag: "streams", Summary: "Compare a user's recent streams", Security: signedIn,
+			Query: []openapi.Parameter{{Name: "days", In: "query", Schema: openapi.Primitive("integer")}}},
+		{Method: http.MethodGet, Path: "/streams/:id/latency", Tag: "playback", Summary: "Get a stream's glass-to-glass latency", Security: anyAuth},
+		{Method: http.MethodPost, Path: "/streams/:id/latency", Tag: "playback", Summary: "Report when a latency marker was shown", Security: anyAuth,
+			Body: LatencyReportRequest{}, Status: http.StatusCreated},
+		{Method: http.MethodGet, Path: "/dashboard/:user_id", Tag: "streams", Summary: "Get a broadcaster's live dashboard", Security: signedIn},
+		{Method: http.MethodGet, Path: "/ingest/endpoints", Tag: "streams", Summary: "List the nearest ingest endpoints",
+			Query: []openapi.Parameter{{Name: "ip", In: "query", Schema: openapi.Primitive("string")}, {Name: "country", In: "query", Schema: openapi.Primitive("string")}}},
+
+		{Method: http.MethodGet, Path: "/vods", Tag: "vods", Summary: "List VODs", Security: anyAuth,
+			Query: []openapi.Parameter{limitParam, cursorParam}, Response: v1List("vods", vod)},
+		{Method: http.MethodGet, Path: "/vods/:id", Tag: "vods", Summary: "Get a VOD", Security: anyAuth, Response: vod},
+		{Method: http.MethodGet, Path: "/users/:id/vods", Tag: "vods", Summary: "List a user's VODs", Security: anyAuth,
+			Query: []openapi.Parameter{deletedParam, limitParam, cursorParam}, Response: v1List("vods", vod)},
+		{Method: http.MethodDelete, Path: "/vods/:id", Tag: "vods", Summary: "Soft delete a VOD", Security: signedIn},
+		{Method: http.MethodPost, Path: "/vods/:id/restore", Tag: "vods", Summary: "Restore a soft deleted VOD", Security: signedIn},
+		{Method: http.MethodPost, Path: "/vods/:id/premiere", Tag: "vods", Summary: "Schedule a VOD's premiere", Security: signedIn,
+			Body: SchedulePremiereRequest{}, Status: http.StatusCreated, Response: vod},
+		{Method: http.MethodDelete, Path: "/vods/:id/premiere", Tag: "vods", Summary: "Cancel a VOD's premiere", Security: signedIn},
+
+		{Method: http.MethodPost, Path: "/streams/:id/clips", Tag: "clips", Summary: "Clip a stream", Security: signedIn,
+			Body: CreateClipRequest{}, Status: http.StatusAccepted, Response: clip},
+		{Method: http.MethodGet, Path: "/streams/:id/clips", Tag: "clips", Summary: "List a stream's clips", Security: anyAuth},
+		{Method: http.MethodGet, Path: "/clips/:id", Tag: "clips", Summary: "Get a clip", Security: anyAuth, Response: clip},
+
+		{Method: http.MethodGet, Path: "/takedowns/:id", Tag: "vods", Summary: "Get a takedown of the caller's content", Security: signedIn},
+		{Method: http.MethodPost, Path: "/takedowns/:id/counter-notice", Tag: "vods", Summary: "Dispute a takedown", Security: signedIn, Body: CounterNoticeRequest{}},
+
+		{Method: http.MethodGet, Path: "/users/:id/restream-targets", Tag: "restream", Summary: "List a user's restream targets", Security: signedIn},
+		{Method: http.MethodPost, Path: "/users/:id/restream-targets", Tag: "restream", Summary: "Add a restream target", Security: signedIn,
+			Body: RestreamTargetRequest{}, Status: http.StatusCreated},
+		{Method: http.MethodPatch, Path: "/restream-targets/:id", Tag: "restream", Summary: "Update a restream target", Security: signedIn, Body: RestreamTargetRequest{}},
+		{Method: http.MethodDelete, Path: "/restream-targets/:id", Tag: "restream", Summary: "Remove a restream target", Security: signedIn},
+
+		{Method: http.MethodDelete, Path: "/users/:id/data", Tag: "users", Summary: "Erase a user's data", Security: signedIn, Status: http.StatusAccepted},
+		{Method: http.MethodGet, Path: "/users/:id/export", Tag: "users", Summary: "Export a user's data", Security: signedIn, Status: http.StatusAccepted},
+		{Method: http.MethodGet, Path: "/users/:id/data-jobs/:job_id", Tag: "users", Summary: "Get an erasure or export job", Security: signedIn},
+
+		{Method: http.MethodPost, Path: "/squads", Tag: "squads", Summary: "Start a squad", Security: signedIn, Body: CreateSquadRequest{}, Status: http.StatusCreated},
+		{Method: http.MethodGet, Path: "/squads/:id", Tag: "squads", Summary: "Get a squad", Security: anyAuth},
+		{Method: http.MethodPost, Path: "/squads/:id/join", Tag: "squads", Summary: "Join a squad", Security: signedIn, Body: SquadMemberRequest{}},
+		{Method: http.MethodPost, Path: "/squads/:id/leave", Tag: "squads", Summary: "Leave a squad", Security: signedIn, Body: SquadMemberRequest{}},
+		{Method: http.MethodGet, Path: "/squads/:id/playback", Tag: "squads", Summary: "Get the playback of a squad's streams", Security: anyAuth},
+
+		{Method: http.MethodGet, Path: "/usage", Tag: "users", Summary: "Get the calling API key's usage", Security: anyAuth,
+			Query: []openapi.Parameter{{Name: "month", In: "query", Description: "YYYY-MM", Schema: openapi.Primitive("string")}}},
+		{Method: http.MethodGet, Path: "/stats", Tag: "streams", Summary: "Get platform stats", Security: anyAuth},
+		{Method: http.MethodGet, Path: "/stats/history", Tag: "streams", Summary: "Get platform stats over time", Security: anyAuth,
+			Query: []openapi.Parameter{
+				{Name: "granularity", In: "query", Schema: &openapi.Schema{Type: "string", Enum: []any{models.StatsGranularityHour, models.StatsGranularityDay}}},
+				{Name: "from", In: "query", Schema: &openapi.Schema{Type: "string", Format: "date-time"}},
+				{Name: "to", In: "query", Schema: &openapi.Schema{Type: "string", Format: "date-time"}},
+			}},
+		{Method: http.MethodPost, Path: "/streams/:id/viewers", Tag: "streams", Summary: "Set a stream's viewer count", Security: anyAuth, Body: ViewerCountRequest{}},
+	}
+}
+
+func apiV2Routes(spec *openapi.Spec) []openapi.Route {
+	stream := spec.SchemaOf(models.Stream{})
+	vod := spec.SchemaOf(models.VOD{})
+	page := spec.SchemaOf(Pagination{})
+	v2List := func(items *openapi.Schema) *openapi.Schema {
+		return openapi.Object(map[string]*openapi.Schema{
+			"data":       openapi.ArrayOf(items),
+			"pagination": page,
+		})
+	}
+
+	return []openapi.Route{
+		{Method: http.MethodGet, Path: "/streams", Tag: "streams", Summary: "List live streams, most watched first", Security: anyAuth,
+			Query: []openapi.Parameter{limitParam, cursorParam}, Response: v2List(stream)},
+		{Method: http.MethodGet, Path: "/streams/:id", Tag: "streams", Summary: "Get a stream", Security: anyAuth, Response: stream},
+		{Method: http.MethodGet, Path: "/vods", Tag: "vods", Summary: "List VODs", Security: anyAuth,
+			Query: []openapi.Parameter{limitParam, cursorParam}, Response: v2List(vod)},
+		{Method: http.MethodGet, Path: "/vods/:id", Tag: "vods", Summary: "Get a VOD", Security: anyAuth, Response: vod},
+		{Method: http.MethodGet, Path: "/users/:id/vods", Tag: "vods", Summary: "List a user's VODs", Security: anyAuth,
+			Query: []openapi.Parameter{deletedParam, limitParam, cursorParam}, Response: v2List(vod)},
+	}
+}
+
+// ServeAPISpec handles GET /openapi.json
+func ServeAPISpec(spec *openapi.Spec) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.JSON(http.StatusOK, spec.Document())
+	}
+}
+
+// ValidateRequests rejects request bodies that don't match the route's schema in the spec,
+// listing every violation by field. Routes without a body schema are let through.
+func ValidateRequests(spec *openapi.Spec) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		schema := spec.RequestBody(c.Request.Method, c.FullPath())
+		if schema == nil || c.Request.Body == nil {
+			c.Next()
+			return
+		}
+
+		body, err := io.ReadAll(io.LimitReader(c.Request.Body, maxValidatedBodySize+1))
+		if err != nil {
+			abortWithError(c, http.StatusBadRequest, ErrCodeInvalidRequest, "Could not read request body")
+			return
+		}
+		if len(body) > maxValidatedBodySize {
+			abortWithError(c, http.StatusRequestEntityTooLarge, ErrCodeInvalidRequest, "Request body too large")
+			return
+		}
+		// The handler binds the body again
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		if violations := spec.Validate(schema, body); len(violations) > 0 {
+			details := make(map[string]string, len(violations))
+			for _, violation := range violations {
+				details[violation.Field] = violation.Message
+			}
+			c.Abort()
+			respondErrorDetails(c, http.StatusBadRequest, ErrCodeInvalidRequest, "Invalid request body", details)
+			return
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,130 @@
+// services/stream-management-service/internal/service/api_version.go
+package service
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/shared/go/pkg/apperrors"
+)
+
+// APIVersion is the major version of the REST API a request came in on. Handlers are shared
+// between versions and only differ in the shape of what they answer.
+type APIVersion int
+
+const (
+	APIV1 APIVersion = 1
+	// APIV2 answers lists paginated under data and errors as typed APIError objects
+	APIV2 APIVersion = 2
+)
+
+const apiVersionContextKey = "api_version"
+
+// ErrorCode is the machine readable kind of a v2 error, stable across message changes. The
+// codes are the shared error model's, so the gRPC API answers the same errors alike.
+type ErrorCode = apperrors.Code
+
+const (
+	ErrCodeInvalidRequest     = apperrors.CodeInvalidRequest
+	ErrCodeUnauthorized       = apperrors.CodeUnauthorized
+	ErrCodeForbidden          = apperrors.CodeForbidden
+	ErrCodeNotFound           = apperrors.CodeNotFound
+	ErrCodeConflict           = apperrors.CodeConflict
+	ErrCodeFailedPrecondition = apperrors.CodeFailedPrecondition
+	ErrCodeRateLimited        = apperrors.CodeRateLimited
+	ErrCodeContentBlocked     = apperrors.CodeContentBlocked
+	ErrCodeTimeout            = apperrors.CodeTimeout
+	ErrCodeInternal           = apperrors.CodeInternal
+	ErrCodeUnavailable        = apperrors.CodeUnavailable
+)
+
+// APIError is the error body of v2 responses, v1 only has the message
+type APIError = apperrors.Body
+
+// Pagination describes a page of a v2 list. NextCursor is empty on the last page.
+type Pagination struct {
+	Count      int    `json:"count"`
+	NextCursor string `json:"next_cursor,omitempty"`
+	HasMore    bool   `json:"has_more"`
+}
+
+// UseAPIVersion tags the requests of a route group with the API version it serves
+func UseAPIVersion(version APIVersion) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Set(apiVersionContextKey, version)
+		c.Next()
+	}
+}
+
+// RequestAPIVersion returns the API version of a request, v1 for routes outside a versioned
+// group
+func RequestAPIVersion(c *gin.Context) APIVersion {
+	if value, ok := c.Get(apiVersionContextKey); ok {
+		return value.(APIVersion)
+	}
+	return APIV1
+}
+
+// respondError answers an error in the shape of the request's API version
+func respondError(c *gin.Context, status int, code ErrorCode, message string) {
+	respondErrorDetails(c, status, code, message, nil)
+}
+
+// respondErrorDetails is respondError with details, which v1 has next to the message
+func respondErrorDetails(c *gin.Context, status int, code ErrorCode, message string, details map[string]string) {
+	if RequestAPIVersion(c) >= APIV2 {
+		c.JSON(status, gin.H{"error": APIError{Code: code, Message: message, Details: details}})
+		return
+	}
+
+	body := gin.H{"error": message}
+	for key, value := range details {
+		body[key] = value
+	}
+	c.JSON(status, body)
+}
+
+// respondAppError answers a domain error with the status and code of its kind. Other errors
+// are internal and answered without telling what they were. Either way the error is logged.
+func respondAppError(c *gin.Context, err error) {
+	apperrors.Log(c.Request.Context(), err)
+	appErr := apperrors.As(err)
+	respondErrorDetails(c, appErr.Code.HTTPStatus(), appErr.Code, appErr.Message, appErr.Details)
+}
+
+// RespondErrors answers the last error a handler added with c.Error when it didn't answer
+// itself, so handlers can leave the response shape to respondAppError
+func RespondErrors() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+		if c.Writer.Written() || len(c.Errors) == 0 {
+			return
+		}
+		respondAppError(c, c.Errors.Last().Err)
+	}
+}
+
+// abortWithError is respondError for middleware, no later handler runs
+func abortWithError(c *gin.Context, status int, code ErrorCode, message string) {
+	c.Abort()
+	respondError(c, status, code, message)
+}
+
+// respondList answers a page of a list. v1 has the items under key next to count and
+// next_cursor, v2 under data with the page described in pagination. Fields are added to
+// both, e.g. the user a list belongs to.
+func respondList(c *gin.Context, status int, key string, items any, count int, nextCursor string, fields gin.H) {
+	body := gin.H{}
+	for name, value := range fields {
+		body[name] = value
+	}
+
+	if RequestAPIVersion(c) >= APIV2 {
+		body["data"] = items
+		body["pagination"] = Pagination{Count: count, NextCursor: nextCursor, HasMore: nextCursor != ""}
+	} else {
+		body[key] = items
+		body["count"] = count
+		body["next_cursor"] = nextCursor
+	}
+	c.JSON(status, body)
+}
@@ -0,0 +1,384 @@
+// services/stream-management-service/internal/service/apikey_service.go
+package service
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/services/stream-management-service/internal/config"
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/services/stream-management-service/internal/models"
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/services/stream-management-service/internal/repository"
+)
+
+const (
+	APIKeyHeader = "X-API-Key"
+
+	apiKeyPrefix = "lsp_"
+	// apiKeyCacheTTL bounds how long a revoked key keeps working on an instance
+	apiKeyCacheTTL = time.Minute
+	// apiKeyContextKey holds the authenticated key in the gin context
+	apiKeyContextKey = "api_key"
+)
+
+type APIKeyService struct {
+	config     *config.Config
+	dynamoRepo *repository.DynamoDBRepository
+	redisRepo  *repository.RedisRepository
+
+	cacheMu sync.Mutex
+	cache   map[string]cachedAPIKey
+}
+
+type cachedAPIKey struct {
+	key       *models.APIKey
+	expiresAt time.Time
+}
+
+type CreateAPIKeyRequest struct {
+	Name         string   `json:"name"`
+	Owner        string   `json:"owner"`
+	UserID       int64    `json:"user_id"`
+	Scopes       []string `json:"scopes"`
+	RateLimit    int      `json:"rate_limit"`
+	MonthlyQuota int64    `json:"monthly_quota"`
+}
+
+func NewAPIKeyService(cfg *config.Config, dynamoRepo *repository.DynamoDBRepository, redisRepo *repository.RedisRepository) *APIKeyService {
+	return &APIKeyService{
+		config:     cfg,
+		dynamoRepo: dynamoRepo,
+		redisRepo:  redisRepo,
+		cache:      make(map[string]cachedAPIKey),
+	}
+}
+
+// Authenticate checks the API key of a request and enforces its rate limit and monthly quota.
+// Requests without a key are let through unless API_KEYS_REQUIRED is set, so first-party
+// clients keep working.
+func (as *APIKeyService) Authenticate() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		raw := c.GetHeader(APIKeyHeader)
+		if raw == "" {
+			if as.config.Tunables().APIKeysRequired {
+				abortWithError(c, http.StatusUnauthorized, ErrCodeUnauthorized, "API key required")
+				return
+			}
+			c.Next()
+			return
+		}
+
+		key, err := as.lookup(raw)
+		if err != nil {
+			abortWithError(c, http.StatusUnauthorized, ErrCodeUnauthorized, "Invalid API key")
+			return
+		}
+
+		if !as.allow(c, key) {
+			return
+		}
+
+		c.Set(apiKeyContextKey, key)
+		c.Next()
+	}
+}
+
+// RequireScope rejects requests whose API key wasn't granted a scope
+func (as *APIKeyService) RequireScope(scope string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		value, ok := c.Get(apiKeyContextKey)
+		if !ok {
+			// Authenticate already rejected keyless requests if keys are required
+			c.Next()
+			return
+		}
+
+		if key := value.(*models.APIKey); !key.HasScope(scope) {
+			abortWithError(c, http.StatusForbidden, ErrCodeForbidden, fmt.Sprintf("API key is missing the %s scope", scope))
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// requestAPIKey returns the key a request was made with
+func requestAPIKey(c *gin.Context) (*models.APIKey, bool) {
+	value, ok := c.Get(apiKeyContextKey)
+	if !ok {
+		return nil, false
+	}
+	return value.(*models.APIKey), true
+}
+
+// allow enforces the per-minute rate limit and the monthly quota, writing the limit headers
+func (as *APIKeyService) allow(c *gin.Context, key *models.APIKey) bool {
+	now := time.Now().UTC()
+
+	window := fmt.Sprintf("apikey:rate:%s:%d", key.ID, now.Unix()/60)
+	count, err := as.redisRepo.IncrementRateWindow(window, 2*time.Minute)
+	if err != nil {
+		// Fail open, Redis being down shouldn't take the API with it
+		slog.WarnContext(c.Request.Context(), "⚠️ Could not check rate limit of API key", "api_key_id", key.ID, "error", err)
+		return true
+	}
+
+	c.Header("X-RateLimit-Limit", strconv.Itoa(key.RateLimit))
+	c.Header("X-RateLimit-Remaining", strconv.FormatInt(max(int64(key.RateLimit)-count, 0), 10))
+	if count > int64(key.RateLimit) {
+		c.Header("Retry-After", strconv.Itoa(60-now.Second()))
+		abortWithError(c, http.StatusTooManyRequests, ErrCodeRateLimited, "Rate limit exceeded")
+		return false
+	}
+
+	month, day := now.Format("2006-01"), now.Format("2006-01-02")
+	total, err := as.redisRepo.IncrementAPIKeyUsage(key.ID, month, day, 1)
+	if err != nil {
+		slog.WarnContext(c.Request.Context(), "⚠️ Could not record usage of API key", "api_key_id", key.ID, "error", err)
+		return true
+	}
+
+	c.Header("X-Quota-Limit", strconv.FormatInt(key.MonthlyQuota, 10))
+	c.Header("X-Quota-Remaining", strconv.FormatInt(max(key.MonthlyQuota-total, 0), 10))
+	if total > key.MonthlyQuota {
+		// Rejected requests don't count against the quota
+		as.redisRepo.IncrementAPIKeyUsage(key.ID, month, day, -1)
+		abortWithError(c, http.StatusTooManyRequests, ErrCodeRateLimited, "Monthly quota exceeded")
+		return false
+	}
+
+	return true
+}
+
+// lookup resolves a raw key of the form lsp_<id>.<secret>
+func (as *APIKeyService) lookup(raw string) (*models.APIKey, error) {
+	keyID, secret, ok := strings.Cut(strings.TrimPrefix(raw, apiKeyPrefix), ".")
+	if !ok || !strings.HasPrefix(raw, apiKeyPrefix) {
+		return nil, fmt.Errorf("malformed api key")
+	}
+
+	key, err := as.getCached(keyID)
+	if err != nil {
+		return nil, err
+	}
+
+	if key.Revoked || subtle.ConstantTimeCompare([]byte(hashAPIKeySecret(secret)), []byte(key.SecretHash)) != 1 {
+		return nil, fmt.Errorf("invalid api key")
+	}
+
+	return key, nil
+}
+
+func (as *APIKeyService) getCached(keyID string) (*models.APIKey, error) {
+	as.cacheMu.Lock()
+	cached, ok := as.cache[keyID]
+	as.cacheMu.Unlock()
+	if ok && time.Now().Before(cached.expiresAt) {
+		return cached.key, nil
+	}
+
+	key, err := as.dynamoRepo.GetAPIKeyByID(keyID)
+	if err != nil {
+		return nil, err
+	}
+
+	as.cacheMu.Lock()
+	as.cache[keyID] = cachedAPIKey{key: key, expiresAt: time.Now().Add(apiKeyCacheTTL)}
+	as.cacheMu.Unlock()
+
+	return key, nil
+}
+
+// CreateAPIKey handles POST /admin/api-keys. The secret is only returned in this response.
+func (as *APIKeyService) CreateAPIKey(c *gin.Context) {
+	var req CreateAPIKeyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if req.Name == "" || req.Owner == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "name and owner are required"})
+		return
+	}
+	if req.UserID < 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "user_id must be a user ID"})
+		return
+	}
+	for _, scope := range req.Scopes {
+		if !isKnownScope(scope) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("unknown scope %q", scope)})
+			return
+		}
+	}
+	if req.RateLimit <= 0 {
+		req.RateLimit = as.config.Tunables().DefaultRateLimit
+	}
+	if req.MonthlyQuota <= 0 {
+		req.MonthlyQuota = as.config.DefaultMonthlyQuota
+	}
+
+	keyID, secret := randomHex(8), randomHex(24)
+
+	now := time.Now()
+	key := &models.APIKey{
+		ID:           keyID,
+		Name:         req.Name,
+		Owner:        req.Owner,
+		UserID:       req.UserID,
+		SecretHash:   hashAPIKeySecret(secret),
+		Scopes:       req.Scopes,
+		RateLimit:    req.RateLimit,
+		MonthlyQuota: req.MonthlyQuota,
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}
+
+	if err := as.dynamoRepo.SaveAPIKey(key); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not create API key"})
+		return
+	}
+
+	slog.InfoContext(c.Request.Context(), "🔑 API key issued", "api_key_id", key.ID, "owner", key.Owner, "user_id", key.UserID, "scopes", key.Scopes)
+	c.JSON(http.StatusCreated, gin.H{
+		"api_key": key,
+		"secret":  apiKeyPrefix + keyID + "." + secret,
+	})
+}
+
+// ListAPIKeys handles GET /admin/api-keys
+func (as *APIKeyService) ListAPIKeys(c *gin.Context) {
+	limit, cursor := parsePagination(c)
+
+	keys, nextCursor, err := as.dynamoRepo.ListAPIKeys(limit, cursor)
+	if err != nil {
+		respondPageError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"api_keys":    keys,
+		"count":       len(keys),
+		"next_cursor": nextCursor,
+	})
+}
+
+// RevokeAPIKey handles DELETE /admin/api-keys/:id
+func (as *APIKeyService) RevokeAPIKey(c *gin.Context) {
+	key, err := as.dynamoRepo.GetAPIKeyByID(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "API key not found"})
+		return
+	}
+
+	key.Revoked = true
+	key.UpdatedAt = time.Now()
+	if err := as.dynamoRepo.SaveAPIKey(key); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not revoke API key"})
+		return
+	}
+
+	as.cacheMu.Lock()
+	delete(as.cache, key.ID)
+	as.cacheMu.Unlock()
+
+	slog.InfoContext(c.Request.Context(), "🔒 API key revoked", "api_key_id", key.ID)
+	c.JSON(http.StatusOK, gin.H{"message": "API key revoked"})
+}
+
+// GetAPIKeyUsage handles GET /admin/api-keys/:id/usage?month=YYYY-MM
+func (as *APIKeyService) GetAPIKeyUsage(c *gin.Context) {
+	key, err := as.dynamoRepo.GetAPIKeyByID(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "API key not found"})
+		return
+	}
+
+	as.respondUsage(c, key)
+}
+
+// GetOwnUsage handles GET /api/v1/usage and reports the usage of the calling API key
+func (as *APIKeyService) GetOwnUsage(c *gin.Context) {
+	value, ok := c.Get(apiKeyContextKey)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "API key required"})
+		return
+	}
+
+	as.respondUsage(c, value.(*models.APIKey))
+}
+
+func (as *APIKeyService) respondUsage(c *gin.Context, key *models.APIKey) {
+	month := c.DefaultQuery("month", time.Now().UTC().Format("2006-01"))
+	if _, err := time.Parse("2006-01", month); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "month must be formatted as YYYY-MM"})
+		return
+	}
+
+	usage, err := as.GetUsageInternal(key, month)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not get API key usage"})
+		return
+	}
+
+	c.JSON(http.StatusOK, usage)
+}
+
+// GetUsageInternal builds the usage report of a key for a month
+func (as *APIKeyService) GetUsageInternal(key *models.APIKey, month string) (*models.APIKeyUsage, error) {
+	counters, err := as.redisRepo.GetAPIKeyUsage(key.ID, month)
+	if err != nil {
+		return nil, err
+	}
+
+	usage := &models.APIKeyUsage{
+		KeyID:        key.ID,
+		Month:        month,
+		MonthlyQuota: key.MonthlyQuota,
+		Daily:        make(map[string]int64),
+	}
+	for field, value := range counters {
+		count, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			continue
+		}
+		if field == "total" {
+			usage.Total = count
+		} else {
+			usage.Daily[field] = count
+		}
+	}
+	usage.Remaining = max(key.MonthlyQuota-usage.Total, 0)
+
+	return usage, nil
+}
+
+func isKnownScope(scope string) bool {
+	for _, known := range models.KnownScopes {
+		if scope == known {
+			return true
+		}
+	}
+	return false
+}
+
+func hashAPIKeySecret(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
+
+func randomHex(n int) string {
+	bytes := make([]byte, n)
+	rand.Read(bytes)
+	return hex.EncodeToString(bytes)
+}
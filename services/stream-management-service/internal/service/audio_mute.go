@@ -0,0 +1,80 @@
+// services/stream-management-service/internal/service/audio_mute.go
+package service
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/services/stream-management-service/internal/config"
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/services/stream-management-service/internal/models"
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/services/stream-management-service/pkg/aws"
+)
+
+// muteRecording silences time ranges of a VOD's best rendition, uploads the result as
+// vods/<vod>/<name>.mp4 and returns it as the VOD's new source rendition
+func muteRecording(cfg *config.Config, s3Client *aws.S3Client, vod *models.VOD, ranges []models.TimeRange, name string) (models.Rendition, error) {
+	source := sourceURL(vod.Renditions)
+	if source == "" {
+		return models.Rendition{}, fmt.Errorf("vod has no rendition to mute")
+	}
+
+	outputPath := filepath.Join(cfg.ClipWorkDir, name+".mp4")
+	if err := muteAudio(cfg.FFmpegPath, source, outputPath, ranges); err != nil {
+		return models.Rendition{}, err
+	}
+
+	key := fmt.Sprintf("vods/%s/%s.mp4", vod.ID, name)
+	url, err := s3Client.UploadRecording(outputPath, key)
+	if err != nil {
+		return models.Rendition{}, err
+	}
+
+	// Mock uploads point at the local file, so only remove it once it's really in S3
+	if !strings.HasPrefix(url, "file://") {
+		os.Remove(outputPath)
+	}
+
+	muted := models.Rendition{Name: "source", URL: url}
+	for _, rendition := range vod.Renditions {
+		if rendition.URL == source {
+			muted.Resolution = rendition.Resolution
+			muted.Bitrate = rendition.Bitrate
+		}
+	}
+	return muted, nil
+}
+
+// muteAudio re-encodes the audio with the ranges silenced, the video is copied as is
+func muteAudio(ffmpegPath, source, outputPath string, ranges []models.TimeRange) error {
+	source = strings.TrimPrefix(source, "file://")
+
+	between := make([]string, len(ranges))
+	for i, r := range ranges {
+		between[i] = fmt.Sprintf("between(t,%d,%d)", r.Start, r.End)
+	}
+	filter := fmt.Sprintf("volume=enable='%s':volume=0", strings.Join(between, "+"))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Minute)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, ffmpegPath,
+		"-y",
+		"-i", source,
+		"-c:v", "copy",
+		"-af", filter,
+		"-c:a", "aac",
+		"-movflags", "+faststart",
+		outputPath,
+	)
+
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("ffmpeg failed: %w: %s", err, lastLine(string(output)))
+	}
+
+	return nil
+}
@@ -0,0 +1,145 @@
+// services/stream-management-service/internal/service/audit.go
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/services/stream-management-service/internal/models"
+)
+
+// Sources an audited change can arrive through
+const (
+	AuditSourceAPI    = "api"
+	AuditSourceAdmin  = "admin"
+	AuditSourceRTMP   = "rtmp"
+	AuditSourceGRPC   = "grpc"
+	AuditSourceWorker = "worker"
+)
+
+// AdminUserHeader names the operator behind an admin request. The admin token is shared, so
+// it is only as trustworthy as whoever holds the token.
+const AdminUserHeader = "X-Admin-User"
+
+type auditOriginKey struct{}
+
+type auditOrigin struct {
+	actor  models.AuditActor
+	source string
+}
+
+// WithAuditActor returns a context whose audited changes are attributed to actor, arriving
+// through source
+func WithAuditActor(ctx context.Context, actor models.AuditActor, source string) context.Context {
+	return context.WithValue(ctx, auditOriginKey{}, auditOrigin{actor: actor, source: source})
+}
+
+// auditOriginFrom returns who is behind ctx. Changes made without a caller are this
+// service's own workers.
+func auditOriginFrom(ctx context.Context) auditOrigin {
+	if origin, ok := ctx.Value(auditOriginKey{}).(auditOrigin); ok {
+		return origin
+	}
+	return auditOrigin{actor: models.AuditActor{Type: models.AuditActorSystem}, source: AuditSourceWorker}
+}
+
+// AuditOrigin attributes the changes a route group makes to its caller. On API routes it
+// must run after the API key and viewer middleware.
+func AuditOrigin(source string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var actor models.AuditActor
+		switch source {
+		case AuditSourceAdmin:
+			actor = models.AuditActor{Type: models.AuditActorAdmin, ID: c.GetHeader(AdminUserHeader)}
+		case AuditSourceRTMP:
+			actor = models.AuditActor{Type: models.AuditActorMediaServer, ID: c.GetHeader(MediaServerHeader)}
+		default:
+			actor = apiActor(c)
+		}
+
+		c.Request = c.Request.WithContext(WithAuditActor(c.Request.Context(), actor, source))
+		c.Next()
+	}
+}
+
+// apiActor is the signed in viewer, else the API key, of an API request
+func apiActor(c *gin.Context) models.AuditActor {
+	if viewerID := ViewerID(c); viewerID != 0 {
+		return models.AuditActor{Type: models.AuditActorUser, ID: strconv.FormatInt(viewerID, 10)}
+	}
+	if value, ok := c.Get(apiKeyContextKey); ok {
+		return models.AuditActor{Type: models.AuditActorAPIKey, ID: value.(*models.APIKey).ID}
+	}
+	return models.AuditActor{Type: models.AuditActorAnonymous}
+}
+
+// Audit appends a record to a stream's audit log. The change already happened, so a record
+// that can't be written is logged rather than failing it.
+func (s *StreamService) Audit(ctx context.Context, streamID, action string, details map[string]string) {
+	origin := auditOriginFrom(ctx)
+	now := time.Now().UTC()
+	id := generateAuditID()
+
+	record := &models.AuditRecord{
+		StreamID:  streamID,
+		SortKey:   now.Format(time.RFC3339Nano) + "#" + id,
+		ID:        id,
+		Action:    action,
+		Actor:     origin.actor,
+		Source:    origin.source,
+		Details:   details,
+		CreatedAt: now,
+	}
+	if err := s.dynamoRepo.AppendAuditRecord(record); err != nil {
+		slog.WarnContext(ctx, "⚠️ Could not write audit record", "stream_id", streamID, "action", action, "error", err)
+	}
+}
+
+// GetStreamAudit handles GET /api/v1/streams/:id/audit
+func (s *StreamService) GetStreamAudit(c *gin.Context) {
+	stream, err := s.GetStreamByIDInternal(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Stream not found"})
+		return
+	}
+	if !authorizeOwner(c, stream.UserID) {
+		return
+	}
+
+	s.respondAudit(c, stream.ID)
+}
+
+// GetStreamAuditAdmin handles GET /admin/streams/:id/audit, which also covers streams that
+// were deleted since
+func (s *StreamService) GetStreamAuditAdmin(c *gin.Context) {
+	s.respondAudit(c, c.Param("id"))
+}
+
+func (s *StreamService) respondAudit(c *gin.Context, streamID string) {
+	limit, cursor := parsePagination(c)
+
+	records, nextCursor, err := s.dynamoRepo.GetAuditRecords(streamID, limit, cursor)
+	if err != nil {
+		respondPageError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"records":     records,
+		"count":       len(records),
+		"next_cursor": nextCursor,
+	})
+}
+
+func generateAuditID() string {
+	bytes := make([]byte, 8)
+	rand.Read(bytes)
+	return "aud_" + hex.EncodeToString(bytes)
+}
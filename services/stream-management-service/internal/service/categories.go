@@ -0,0 +1,196 @@
+// services/stream-management-service/internal/service/categories.go
+package service
+
+import (
+	"log/slog"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/services/stream-management-service/internal/classifier"
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/services/stream-management-service/internal/models"
+)
+
+// trackCategory keeps the category aggregates in step with a stream that was just written.
+// Only live streams that are listed and aren't blocked count towards their category.
+func (s *StreamService) trackCategory(stream *models.Stream) {
+	category := ""
+	if stream.Status == models.StreamStatusLive && !stream.Blocked && stream.Listed() {
+		category = stream.Category
+	}
+
+	if err := s.redisRepo.TrackStreamCategory(stream.ID, category, int64(stream.ViewerCount)); err != nil {
+		slog.Warn("⚠️ Could not update category aggregates", "stream_id", stream.ID, "error", err)
+	}
+}
+
+// ListCategories handles GET /api/v1/categories?sort=viewers|channels|name with the live
+// categories and their aggregates. Pages are read with limit and cursor.
+func (s *StreamService) ListCategories(c *gin.Context) {
+	sortBy := c.DefaultQuery("sort", "viewers")
+	if sortBy != "viewers" && sortBy != "channels" && sortBy != "name" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "sort must be viewers, channels or name"})
+		return
+	}
+	limit, offset, ok := parseOffsetPagination(c)
+	if !ok {
+		return
+	}
+
+	stats, err := s.redisRepo.GetCategoryStats()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not get categories"})
+		return
+	}
+
+	featured, err := s.redisRepo.GetFeaturedCategories()
+	if err != nil {
+		slog.WarnContext(c.Request.Context(), "⚠️ Could not get featured categories", "error", err)
+	}
+	featuredCategories := make(map[string]bool, len(featured))
+	for _, category := range featured {
+		featuredCategories[category] = true
+	}
+	for _, category := range stats {
+		category.Featured = featuredCategories[category.Category]
+	}
+
+	sort.SliceStable(stats, func(i, j int) bool {
+		a, b := stats[i], stats[j]
+		switch {
+		case sortBy == "viewers" && a.Viewers != b.Viewers:
+			return a.Viewers > b.Viewers
+		case sortBy == "channels" && a.LiveChannels != b.LiveChannels:
+			return a.LiveChannels > b.LiveChannels
+		}
+		return a.Category < b.Category
+	})
+
+	page, nextCursor := paginate(len(stats), limit, offset)
+	c.JSON(http.StatusOK, gin.H{
+		"categories":  stats[page.start:page.end],
+		"count":       page.end - page.start,
+		"total":       len(stats),
+		"next_cursor": nextCursor,
+	})
+}
+
+// GetCategoryStreams handles GET /api/v1/categories/:id/streams?sort=viewers|recent with the
+// live streams of a category. Pages are read with limit and cursor.
+func (s *StreamService) GetCategoryStreams(c *gin.Context) {
+	normalized := classifier.NormalizeTags([]string{c.Param("id")})
+	if len(normalized) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid category"})
+		return
+	}
+	category := normalized[0]
+
+	sortBy := c.DefaultQuery("sort", "viewers")
+	if sortBy != "viewers" && sortBy != "recent" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "sort must be viewers or recent"})
+		return
+	}
+	limit, offset, ok := parseOffsetPagination(c)
+	if !ok {
+		return
+	}
+
+	entries, err := s.redisRepo.GetCategoryStreams(category)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not get category streams"})
+		return
+	}
+
+	var streams []*models.Stream
+	if sortBy == "viewers" {
+		// The aggregates already know the viewers, only the page has to be loaded
+		sort.Slice(entries, func(i, j int) bool {
+			if entries[i].Viewers != entries[j].Viewers {
+				return entries[i].Viewers > entries[j].Viewers
+			}
+			return entries[i].StreamID < entries[j].StreamID
+		})
+		page, _ := paginate(len(entries), limit, offset)
+		streams = s.loadCategoryStreams(entries[page.start:page.end])
+	} else {
+		streams = s.loadCategoryStreams(entries)
+		sort.Slice(streams, func(i, j int) bool {
+			return startedAt(streams[i]).After(startedAt(streams[j]))
+		})
+		page, _ := paginate(len(streams), limit, offset)
+		streams = streams[page.start:page.end]
+	}
+	streams = withoutBlockedStreams(streams)
+	s.AttachFollowInfo(streams, ViewerID(c))
+
+	_, nextCursor := paginate(len(entries), limit, offset)
+	c.JSON(http.StatusOK, gin.H{
+		"category":    category,
+		"streams":     models.PublicStreams(streams),
+		"count":       len(streams),
+		"total":       len(entries),
+		"next_cursor": nextCursor,
+	})
+}
+
+// loadCategoryStreams loads the streams of category entries, skipping the ones that are gone
+// or no longer live since they were counted
+func (s *StreamService) loadCategoryStreams(entries []models.CategoryStream) []*models.Stream {
+	streams := make([]*models.Stream, 0, len(entries))
+	for _, entry := range entries {
+		stream, err := s.GetStreamByIDInternal(entry.StreamID)
+		if err != nil {
+			slog.Warn("⚠️ Could not load category stream", "stream_id", entry.StreamID, "error", err)
+			continue
+		}
+		if stream.Status != models.StreamStatusLive {
+			continue
+		}
+		streams = append(streams, stream)
+	}
+	return streams
+}
+
+// startedAt is when a stream went live, streams that never reported it count from creation
+func startedAt(stream *models.Stream) time.Time {
+	if stream.StartedAt != nil {
+		return *stream.StartedAt
+	}
+	return stream.CreatedAt
+}
+
+type pageBounds struct {
+	start, end int
+}
+
+// paginate returns the bounds of the page at offset and the cursor of the next one, empty on
+// the last page
+func paginate(total, limit, offset int) (pageBounds, string) {
+	if offset > total {
+		offset = total
+	}
+	end := offset + limit
+	if end >= total {
+		return pageBounds{offset, total}, ""
+	}
+	return pageBounds{offset, end}, strconv.Itoa(end)
+}
+
+// parseOffsetPagination reads limit and a cursor holding the offset of the page. Live lists
+// change between pages, so a stream or category can move across a page boundary.
+func parseOffsetPagination(c *gin.Context) (int, int, bool) {
+	limit, cursor := parsePagination(c)
+	if cursor == "" {
+		return limit, 0, true
+	}
+
+	offset, err := strconv.Atoi(cursor)
+	if err != nil || offset < 0 {
+		respondError(c, http.StatusBadRequest, ErrCodeInvalidRequest, "Invalid cursor")
+		return 0, 0, false
+	}
+	return limit, offset, true
+}
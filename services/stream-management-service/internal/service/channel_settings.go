@@ -0,0 +1,31 @@
+// services/stream-management-service/internal/service/channel_settings.go
+package service
+
+import (
+	"log/slog"
+	"sort"
+
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/services/stream-management-service/internal/models"
+)
+
+// InheritChannelSettings carries the mature flag and language of the channel's previous stream
+// over to a new one, so a broadcaster doesn't go live unflagged or untargeted before they get
+// to set them again
+func (s *StreamService) InheritChannelSettings(stream *models.Stream) {
+	streams, err := s.dynamoRepo.GetStreamsByUser(stream.UserID, classificationHistorySize, models.DeletedExclude)
+	if err != nil {
+		slog.Warn("⚠️ Could not load previous stream for the channel settings", "user_id", stream.UserID, "error", err)
+		return
+	}
+	if len(streams) == 0 {
+		return
+	}
+
+	sort.Slice(streams, func(i, j int) bool {
+		return streams[i].CreatedAt.After(streams[j].CreatedAt)
+	})
+	stream.IsMature = streams[0].IsMature
+	if stream.Language == "" {
+		stream.Language = streams[0].Language
+	}
+}
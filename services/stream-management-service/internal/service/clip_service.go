@@ -0,0 +1,340 @@
+// services/stream-management-service/internal/service/clip_service.go
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/services/stream-management-service/internal/config"
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/services/stream-management-service/internal/models"
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/services/stream-management-service/internal/repository"
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/services/stream-management-service/pkg/aws"
+)
+
+// clipQueueSize bounds the number of clips waiting to be cut
+const clipQueueSize = 100
+
+type ClipService struct {
+	config        *config.Config
+	dynamoRepo    *repository.DynamoDBRepository
+	streamService *StreamService
+	s3Client      *aws.S3Client
+	jobs          chan string
+	disabled      error // set when clipping can't work, e.g. ffmpeg is missing
+}
+
+type CreateClipRequest struct {
+	Title       string `json:"title"`
+	StartOffset int64  `json:"start_offset"` // seconds from stream start
+	EndOffset   int64  `json:"end_offset"`   // seconds from stream start
+	MarkerID    string `json:"marker_id"`    // clips around a stream marker when no offsets are given
+}
+
+func NewClipService(cfg *config.Config, dynamoRepo *repository.DynamoDBRepository, streamService *StreamService) *ClipService {
+	return &ClipService{
+		config:        cfg,
+		dynamoRepo:    dynamoRepo,
+		streamService: streamService,
+		s3Client:      aws.NewS3Client(cfg.AWSRegion, cfg.S3BucketName),
+		jobs:          make(chan string, clipQueueSize),
+	}
+}
+
+// StartWorkers starts the background workers that cut queued clips
+func (cs *ClipService) StartWorkers(ctx context.Context) {
+	workers := cs.config.ClipWorkers
+	if workers < 1 {
+		workers = 1
+	}
+
+	for i := 0; i < workers; i++ {
+		go func() {
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case clipID := <-cs.jobs:
+					cs.processClip(clipID)
+				}
+			}
+		}()
+	}
+
+	slog.Info("✂️ Started clip workers", "workers", workers)
+}
+
+// VerifyFFmpeg checks that the ffmpeg binary used to cut clips is available
+func (cs *ClipService) VerifyFFmpeg() error {
+	if _, err := exec.LookPath(cs.config.FFmpegPath); err != nil {
+		return fmt.Errorf("ffmpeg not found at %q: %w", cs.config.FFmpegPath, err)
+	}
+	return nil
+}
+
+// Disable rejects new clip requests
+func (cs *ClipService) Disable(reason error) {
+	slog.Warn("🚫 Clip creation disabled", "reason", reason)
+	cs.disabled = reason
+}
+
+// CreateClip handles POST /api/v1/streams/:id/clips
+func (cs *ClipService) CreateClip(c *gin.Context) {
+	if cs.disabled != nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Clip creation is currently unavailable"})
+		return
+	}
+
+	streamID := c.Param("id")
+
+	var req CreateClipRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	stream, ok := cs.clipSource(c, streamID, "Stream not found")
+	if !ok {
+		return
+	}
+
+	if req.MarkerID != "" && req.StartOffset == 0 && req.EndOffset == 0 {
+		marker, ok := findMarker(stream, req.MarkerID)
+		if !ok {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Marker not found"})
+			return
+		}
+		req.StartOffset = max(marker.Offset-markerClipLead, 0)
+		req.EndOffset = marker.Offset + markerClipTail
+		if stream.Duration > 0 {
+			req.EndOffset = min(req.EndOffset, stream.Duration)
+		}
+		if req.Title == "" {
+			req.Title = marker.Description
+		}
+	}
+
+	if err := cs.validateClipRequest(stream, &req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	title := req.Title
+	if title == "" {
+		title = fmt.Sprintf("Clip from %s", stream.Title)
+	}
+
+	now := time.Now()
+	clip := &models.Clip{
+		ID:          generateClipID(),
+		StreamID:    stream.ID,
+		UserID:      stream.UserID,
+		CreatedBy:   principalID(c),
+		Title:       title,
+		StartOffset: req.StartOffset,
+		EndOffset:   req.EndOffset,
+		Duration:    req.EndOffset - req.StartOffset,
+		Status:      models.ClipStatusPending,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+
+	if err := cs.dynamoRepo.SaveClip(clip); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not create clip"})
+		return
+	}
+
+	select {
+	case cs.jobs <- clip.ID:
+	default:
+		cs.failClip(clip, fmt.Errorf("clip queue is full"))
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Clip queue is full, try again later"})
+		return
+	}
+
+	slog.InfoContext(c.Request.Context(), "✂️ Clip queued", "clip_id", clip.ID, "stream_id", stream.ID, "start", clip.StartOffset, "end", clip.EndOffset)
+	c.JSON(http.StatusAccepted, clip)
+}
+
+// GetStreamClips handles GET /api/v1/streams/:id/clips
+func (cs *ClipService) GetStreamClips(c *gin.Context) {
+	limit, cursor := parsePagination(c)
+
+	if _, ok := cs.clipSource(c, c.Param("id"), "Stream not found"); !ok {
+		return
+	}
+
+	clips, nextCursor, err := cs.dynamoRepo.GetClipsByStream(c.Param("id"), limit, cursor)
+	if err != nil {
+		respondPageError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"clips":       clips,
+		"count":       len(clips),
+		"next_cursor": nextCursor,
+	})
+}
+
+// GetClipByID handles GET /api/v1/clips/:id
+func (cs *ClipService) GetClipByID(c *gin.Context) {
+	clip, err := cs.dynamoRepo.GetClipByID(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Clip not found"})
+		return
+	}
+	if _, ok := cs.clipSource(c, clip.StreamID, "Clip not found"); !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, clip)
+}
+
+// clipSource loads the stream clips are cut from, answering the request when the caller may
+// not clip it or see its clips: the stream is deleted or private to someone else, or a takedown
+// blocks it or its VOD
+func (cs *ClipService) clipSource(c *gin.Context, streamID, notFound string) (*models.Stream, bool) {
+	stream, err := cs.streamService.GetStreamByIDInternal(streamID)
+	if err != nil || stream.Deleted() || (stream.EffectiveVisibility() == models.StreamVisibilityPrivate && !isOwner(c, stream.UserID)) {
+		c.JSON(http.StatusNotFound, gin.H{"error": notFound})
+		return nil, false
+	}
+	if stream.Blocked {
+		respondTakedown(c, stream.TakedownID)
+		return nil, false
+	}
+	// Clips are cut from the recording the VOD was made of, so a muted VOD hides them too
+	if vod, err := cs.dynamoRepo.GetVODByID("vod_" + stream.ID); err == nil && vod.TakedownID != "" {
+		respondTakedown(c, vod.TakedownID)
+		return nil, false
+	}
+	return stream, true
+}
+
+func (cs *ClipService) validateClipRequest(stream *models.Stream, req *CreateClipRequest) error {
+	if stream.RecordingURL == "" {
+		return fmt.Errorf("stream has no recording to clip from")
+	}
+	if req.StartOffset < 0 || req.EndOffset <= req.StartOffset {
+		return fmt.Errorf("end_offset must be greater than start_offset and both must be positive")
+	}
+	if time.Duration(req.EndOffset-req.StartOffset)*time.Second > cs.config.MaxClipDuration {
+		return fmt.Errorf("clips can be at most %s long", cs.config.MaxClipDuration)
+	}
+	if stream.Duration > 0 && req.EndOffset > stream.Duration {
+		return fmt.Errorf("end_offset is past the end of the recording (%ds)", stream.Duration)
+	}
+	return nil
+}
+
+// processClip cuts a queued clip out of its stream recording and uploads it to S3
+func (cs *ClipService) processClip(clipID string) {
+	clip, err := cs.dynamoRepo.GetClipByID(clipID)
+	if err != nil {
+		slog.Error("❌ Could not load clip", "clip_id", clipID, "error", err)
+		return
+	}
+
+	clip.Status = models.ClipStatusProcessing
+	clip.UpdatedAt = time.Now()
+	if err := cs.dynamoRepo.SaveClip(clip); err != nil {
+		slog.Warn("⚠️ Could not mark clip as processing", "clip_id", clip.ID, "error", err)
+	}
+
+	stream, err := cs.streamService.GetStreamByIDInternal(clip.StreamID)
+	if err != nil {
+		cs.failClip(clip, fmt.Errorf("stream not found: %w", err))
+		return
+	}
+	if stream.Blocked {
+		cs.failClip(clip, fmt.Errorf("stream was taken down"))
+		return
+	}
+
+	outputPath := filepath.Join(cs.config.ClipWorkDir, clip.ID+".mp4")
+	if err := cs.cutClip(stream.RecordingURL, outputPath, clip.StartOffset, clip.Duration); err != nil {
+		cs.failClip(clip, err)
+		return
+	}
+
+	key := fmt.Sprintf("clips/%s/%s.mp4", clip.StreamID, clip.ID)
+	url, err := cs.s3Client.UploadRecording(outputPath, key)
+	if err != nil {
+		cs.failClip(clip, err)
+		return
+	}
+
+	// Mock uploads point at the local file, so only remove it once it's really in S3
+	if !strings.HasPrefix(url, "file://") {
+		os.Remove(outputPath)
+	}
+
+	clip.Status = models.ClipStatusReady
+	clip.URL = url
+	clip.UpdatedAt = time.Now()
+	if err := cs.dynamoRepo.SaveClip(clip); err != nil {
+		slog.Error("❌ Could not save finished clip", "clip_id", clip.ID, "error", err)
+		return
+	}
+
+	slog.Info("✅ Clip ready", "clip_id", clip.ID, "stream_id", clip.StreamID, "url", url)
+}
+
+// cutClip runs ffmpeg to copy a segment of the recording without re-encoding
+func (cs *ClipService) cutClip(source, outputPath string, startOffset, duration int64) error {
+	source = strings.TrimPrefix(source, "file://")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, cs.config.FFmpegPath,
+		"-y",
+		"-ss", strconv.FormatInt(startOffset, 10),
+		"-i", source,
+		"-t", strconv.FormatInt(duration, 10),
+		"-c", "copy",
+		"-movflags", "+faststart",
+		outputPath,
+	)
+
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("ffmpeg failed: %w: %s", err, lastLine(string(output)))
+	}
+
+	return nil
+}
+
+func (cs *ClipService) failClip(clip *models.Clip, cause error) {
+	slog.Error("❌ Clip failed", "clip_id", clip.ID, "stream_id", clip.StreamID, "error", cause)
+
+	clip.Status = models.ClipStatusFailed
+	clip.Error = cause.Error()
+	clip.UpdatedAt = time.Now()
+	if err := cs.dynamoRepo.SaveClip(clip); err != nil {
+		slog.Warn("⚠️ Could not mark clip as failed", "clip_id", clip.ID, "error", err)
+	}
+}
+
+func generateClipID() string {
+	bytes := make([]byte, 8)
+	rand.Read(bytes)
+	return "clip_" + hex.EncodeToString(bytes)
+}
+
+// lastLine returns the last non-empty line of command output, which is where ffmpeg reports errors
+func lastLine(output string) string {
+	lines := strings.Split(strings.TrimSpace(output), "\n")
+	return lines[len(lines)-1]
+}
@@ -0,0 +1,275 @@
+// services/stream-management-service/internal/service/content_filter.go
+package service
+
+import (
+	"bufio"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/services/stream-management-service/internal/config"
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/services/stream-management-service/internal/contentfilter"
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/services/stream-management-service/internal/models"
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/services/stream-management-service/internal/repository"
+)
+
+type ResolveContentReviewRequest struct {
+	Decision string `json:"decision" binding:"required"` // approve or remove
+}
+
+// newContentFilter builds the title filter from the configured banned words, regex rules and
+// moderation API. A rule source that can't be loaded is left out rather than failing startup.
+func newContentFilter(cfg *config.Config) *contentfilter.Filter {
+	var rules []contentfilter.Rule
+
+	words := append([]string{}, cfg.ContentFilterWords...)
+	if cfg.ContentFilterWordsFile != "" {
+		lines, err := readRuleLines(cfg.ContentFilterWordsFile)
+		if err != nil {
+			slog.Warn("⚠️ Could not read banned words file", "path", cfg.ContentFilterWordsFile, "error", err)
+		}
+		words = append(words, lines...)
+	}
+	if list := contentfilter.NewWordList("banned_words", words); list.Len() > 0 {
+		rules = append(rules, contentfilter.Rule{Matcher: list, Action: filterAction("word", cfg.ContentFilterWordAction, contentfilter.ActionSanitize)})
+	}
+
+	if cfg.ContentFilterRegexFile != "" {
+		lines, err := readRuleLines(cfg.ContentFilterRegexFile)
+		if err != nil {
+			slog.Warn("⚠️ Could not read content filter regex file", "path", cfg.ContentFilterRegexFile, "error", err)
+		}
+		action := filterAction("regex", cfg.ContentFilterRegexAction, contentfilter.ActionReject)
+		for _, line := range lines {
+			name, pattern, ok := strings.Cut(line, "=")
+			if !ok || strings.TrimSpace(name) == "" {
+				slog.Warn("⚠️ Ignoring content filter regex, expected name=pattern", "line", line)
+				continue
+			}
+			matcher, err := contentfilter.NewRegex(strings.TrimSpace(name), strings.TrimSpace(pattern))
+			if err != nil {
+				slog.Warn("⚠️ Ignoring content filter regex", "error", err)
+				continue
+			}
+			rules = append(rules, contentfilter.Rule{Matcher: matcher, Action: action})
+		}
+	}
+
+	if cfg.ContentFilterAPIURL != "" {
+		rules = append(rules, contentfilter.Rule{
+			Matcher: contentfilter.NewModerationAPI(cfg.ContentFilterAPIURL, cfg.ContentFilterAPIKey, cfg.ContentFilterAPITimeout),
+			Action:  filterAction("api", cfg.ContentFilterAPIAction, contentfilter.ActionFlag),
+		})
+	}
+
+	if len(rules) > 0 {
+		slog.Info("🧹 Content filter enabled", "rules", len(rules))
+	}
+	return contentfilter.New(rules...)
+}
+
+// filterAction parses a configured action, falling back to the default when it isn't one
+func filterAction(source, value string, fallback contentfilter.Action) contentfilter.Action {
+	action := contentfilter.Action(strings.ToLower(strings.TrimSpace(value)))
+	if !action.Valid() {
+		slog.Warn("⚠️ Invalid content filter action, using the default", "source", source, "action", value, "default", fallback)
+		return fallback
+	}
+	return action
+}
+
+// readRuleLines returns the non-empty lines of a rule file, skipping # comments
+func readRuleLines(path string) ([]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var lines []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		lines = append(lines, line)
+	}
+	return lines, scanner.Err()
+}
+
+// defaultStreamTitle is the title streams get when they start without one, and what a
+// rejected or removed title is replaced with
+func defaultStreamTitle(startedAt time.Time) string {
+	return fmt.Sprintf("Live Stream - %s", startedAt.Format("2006-01-02 15:04"))
+}
+
+// FilterTitle runs a stream title through the content filter. Callers refuse rejected titles
+// or fall back to a default, store result.Text and queue flagged ones with QueueContentReview.
+func (s *StreamService) FilterTitle(ctx context.Context, title string) *contentfilter.Result {
+	result := s.contentFilter.Check(ctx, title)
+	if len(result.Matches) > 0 {
+		slog.InfoContext(ctx, "🧹 Title matched content filter", "rules", result.Rules(), "rejected", result.Rejected, "sanitized", result.Sanitized, "flagged", result.Flagged)
+	}
+	return result
+}
+
+// respondContentRejected answers a write whose text a reject rule matched
+func respondContentRejected(c *gin.Context, field string, result *contentfilter.Result) {
+	c.JSON(http.StatusUnprocessableEntity, gin.H{
+		"error": fmt.Sprintf("%s is not allowed", field),
+		"rules": result.Rules(),
+	})
+}
+
+// QueueContentReview queues text a flag rule matched for a moderator. It does nothing when
+// nothing was flagged; a review that can't be saved is logged, the write it came from stands.
+func (s *StreamService) QueueContentReview(ctx context.Context, stream *models.Stream, field string, result *contentfilter.Result) {
+	if result == nil || !result.Flagged {
+		return
+	}
+
+	review := &models.ContentReview{
+		ID:        generateContentReviewID(),
+		StreamID:  stream.ID,
+		UserID:    stream.UserID,
+		Field:     field,
+		Text:      result.Text,
+		Matches:   result.Matches,
+		Status:    models.ContentReviewPending,
+		CreatedAt: time.Now().UTC(),
+	}
+	if err := s.dynamoRepo.SaveContentReview(review); err != nil {
+		slog.ErrorContext(ctx, "❌ Could not queue content review", "stream_id", stream.ID, "field", field, "error", err)
+		return
+	}
+
+	slog.InfoContext(ctx, "🚩 Content queued for review", "review_id", review.ID, "stream_id", stream.ID, "field", field, "rules", result.Rules())
+}
+
+// ListContentReviews handles GET /admin/content-reviews?status=pending|approved|removed|all,
+// pending by default, newest first
+func (s *StreamService) ListContentReviews(c *gin.Context) {
+	status := models.ContentReviewStatus(c.DefaultQuery("status", string(models.ContentReviewPending)))
+	switch status {
+	case models.ContentReviewPending, models.ContentReviewApproved, models.ContentReviewRemoved:
+	case "all":
+		status = ""
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "status must be pending, approved, removed or all"})
+		return
+	}
+
+	reviews, err := s.dynamoRepo.GetContentReviews(status)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not load content reviews"})
+		return
+	}
+	if reviews == nil {
+		reviews = []*models.ContentReview{}
+	}
+	sort.Slice(reviews, func(i, j int) bool { return reviews[i].CreatedAt.After(reviews[j].CreatedAt) })
+
+	c.JSON(http.StatusOK, gin.H{"reviews": reviews, "count": len(reviews)})
+}
+
+// ResolveContentReview handles POST /admin/content-reviews/:id. Approving keeps the text,
+// removing replaces a title with the default one if the stream still has it.
+func (s *StreamService) ResolveContentReview(c *gin.Context) {
+	var req ResolveContentReviewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var status models.ContentReviewStatus
+	switch req.Decision {
+	case "approve":
+		status = models.ContentReviewApproved
+	case "remove":
+		status = models.ContentReviewRemoved
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "decision must be approve or remove"})
+		return
+	}
+
+	review, err := s.dynamoRepo.GetContentReview(c.Param("id"))
+	if err != nil {
+		if errors.Is(err, repository.ErrContentReviewNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Content review not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not load content review"})
+		return
+	}
+	if review.Status != models.ContentReviewPending {
+		c.JSON(http.StatusConflict, gin.H{"error": "Content review already resolved"})
+		return
+	}
+
+	ctx := c.Request.Context()
+	if status == models.ContentReviewRemoved {
+		if err := s.removeReviewedContent(ctx, review); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not remove content"})
+			return
+		}
+	}
+
+	now := time.Now().UTC()
+	review.Status = status
+	review.ReviewedAt = &now
+	review.ExpiresAt = now.Add(s.config.ContentReviewRetention).Unix()
+	if err := s.dynamoRepo.SaveContentReview(review); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not save content review"})
+		return
+	}
+
+	slog.InfoContext(ctx, "🚩 Content review resolved", "review_id", review.ID, "stream_id", review.StreamID, "status", status)
+	c.JSON(http.StatusOK, review)
+}
+
+// removeReviewedContent replaces a flagged title with the default one. Streams that are gone
+// or were renamed since have nothing left to remove.
+func (s *StreamService) removeReviewedContent(ctx context.Context, review *models.ContentReview) error {
+	if review.Field != "title" {
+		return nil
+	}
+
+	stream, err := s.GetStreamByIDInternal(review.StreamID)
+	if err != nil || stream.Title != review.Text {
+		return nil
+	}
+
+	startedAt := stream.CreatedAt
+	if stream.StartedAt != nil {
+		startedAt = *stream.StartedAt
+	}
+	stream.Title = defaultStreamTitle(startedAt)
+	stream.UpdatedAt = time.Now()
+	if err := s.UpdateStreamInternal(stream); err != nil {
+		return err
+	}
+
+	s.Audit(ctx, stream.ID, models.AuditContentRemoved, map[string]string{
+		"review_id": review.ID,
+		"field":     review.Field,
+		"title":     stream.Title,
+	})
+	return nil
+}
+
+func generateContentReviewID() string {
+	bytes := make([]byte, 8)
+	rand.Read(bytes)
+	return "cr_" + hex.EncodeToString(bytes)
+}
@@ -0,0 +1,192 @@
+// services/stream-management-service/internal/service/creator_dashboard.go
+package service
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/services/stream-management-service/internal/config"
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/services/stream-management-service/internal/models"
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/services/stream-management-service/internal/repository"
+)
+
+// DashboardService assembles the creator dashboard from the stream store, the follow cache
+// and the chat service in one request. Sections are loaded in parallel, one that fails or
+// runs out of time is reported in the payload instead of failing the dashboard.
+type DashboardService struct {
+	config        *config.Config
+	redisRepo     *repository.RedisRepository
+	streamService *StreamService
+}
+
+// dashboardSection loads one part of the dashboard. It returns how to apply the result, so
+// sections that finish after the deadline never touch the payload.
+type dashboardSection struct {
+	name string
+	load func(ctx context.Context) (func(*models.CreatorDashboard), error)
+}
+
+type dashboardResult struct {
+	name  string
+	apply func(*models.CreatorDashboard)
+	err   error
+}
+
+func NewDashboardService(cfg *config.Config, redisRepo *repository.RedisRepository, streamService *StreamService) *DashboardService {
+	return &DashboardService{
+		config:        cfg,
+		redisRepo:     redisRepo,
+		streamService: streamService,
+	}
+}
+
+// GetCreatorDashboard handles GET /api/v1/dashboard/:user_id
+func (ds *DashboardService) GetCreatorDashboard(c *gin.Context) {
+	userID, err := strconv.ParseInt(c.Param("user_id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+	if !authorizeOwner(c, userID) {
+		return
+	}
+
+	ctx := c.Request.Context()
+	dashboard := &models.CreatorDashboard{
+		UserID:       userID,
+		RecentEvents: []models.ChannelEvent{},
+		Errors:       make(map[string]string),
+	}
+
+	ds.loadSections(ctx, dashboard, []dashboardSection{
+		{name: "stream", load: ds.loadStream(userID)},
+		{name: "followers", load: ds.loadFollowerCount(userID)},
+		{name: "recent_events", load: ds.loadRecentEvents(userID)},
+	})
+
+	// Health and chat belong to the live stream
+	if dashboard.Live {
+		ds.loadSections(ctx, dashboard, []dashboardSection{
+			{name: "health", load: ds.loadHealth(dashboard.Stream.ID)},
+			{name: "chat", load: ds.loadChat(dashboard.Stream)},
+		})
+	}
+
+	dashboard.GeneratedAt = time.Now().UTC()
+	if len(dashboard.Errors) == 0 {
+		dashboard.Errors = nil
+	}
+	c.JSON(http.StatusOK, dashboard)
+}
+
+// loadSections runs sections in parallel until they finish or the dashboard timeout passes
+func (ds *DashboardService) loadSections(ctx context.Context, dashboard *models.CreatorDashboard, sections []dashboardSection) {
+	ctx, cancel := context.WithTimeout(ctx, ds.config.DashboardTimeout)
+	defer cancel()
+
+	// Buffered so sections finishing after the deadline don't block
+	results := make(chan dashboardResult, len(sections))
+	for _, section := range sections {
+		go func(section dashboardSection) {
+			apply, err := section.load(ctx)
+			results <- dashboardResult{name: section.name, apply: apply, err: err}
+		}(section)
+	}
+
+	pending := make(map[string]bool, len(sections))
+	for _, section := range sections {
+		pending[section.name] = true
+	}
+
+	for len(pending) > 0 {
+		select {
+		case result := <-results:
+			delete(pending, result.name)
+			if result.err != nil {
+				slog.WarnContext(ctx, "⚠️ Could not load dashboard section", "user_id", dashboard.UserID, "section", result.name, "error", result.err)
+				dashboard.Errors[result.name] = "unavailable"
+				continue
+			}
+			result.apply(dashboard)
+		case <-ctx.Done():
+			for name := range pending {
+				slog.WarnContext(ctx, "⚠️ Dashboard section timed out", "user_id", dashboard.UserID, "section", name)
+				dashboard.Errors[name] = "timed out"
+			}
+			return
+		}
+	}
+}
+
+func (ds *DashboardService) loadStream(userID int64) func(context.Context) (func(*models.CreatorDashboard), error) {
+	return func(ctx context.Context) (func(*models.CreatorDashboard), error) {
+		streams, err := ds.streamService.GetUserStreams(userID, 1)
+		if err != nil {
+			return nil, err
+		}
+		return func(dashboard *models.CreatorDashboard) {
+			if len(streams) == 0 {
+				return
+			}
+			dashboard.Stream = streams[0]
+			dashboard.Live = streams[0].Status == models.StreamStatusLive
+			dashboard.ViewerCount = streams[0].ViewerCount
+		}, nil
+	}
+}
+
+func (ds *DashboardService) loadFollowerCount(userID int64) func(context.Context) (func(*models.CreatorDashboard), error) {
+	return func(ctx context.Context) (func(*models.CreatorDashboard), error) {
+		counts, err := ds.redisRepo.GetFollowerCounts([]int64{userID})
+		if err != nil {
+			return nil, err
+		}
+		return func(dashboard *models.CreatorDashboard) {
+			if count, ok := counts[userID]; ok {
+				dashboard.FollowerCount = &count
+			}
+		}, nil
+	}
+}
+
+func (ds *DashboardService) loadRecentEvents(userID int64) func(context.Context) (func(*models.CreatorDashboard), error) {
+	return func(ctx context.Context) (func(*models.CreatorDashboard), error) {
+		events, err := ds.streamService.GetChannelEventsInternal(userID)
+		if err != nil {
+			return nil, err
+		}
+		return func(dashboard *models.CreatorDashboard) {
+			dashboard.RecentEvents = events
+		}, nil
+	}
+}
+
+func (ds *DashboardService) loadHealth(streamID string) func(context.Context) (func(*models.CreatorDashboard), error) {
+	return func(ctx context.Context) (func(*models.CreatorDashboard), error) {
+		health, err := ds.streamService.GetStreamHealthInternal(streamID)
+		if err != nil {
+			return nil, err
+		}
+		return func(dashboard *models.CreatorDashboard) {
+			dashboard.Health = health
+		}, nil
+	}
+}
+
+// loadChat asks the chat service for the activity of the stream's room since it started
+func (ds *DashboardService) loadChat(stream *models.Stream) func(context.Context) (func(*models.CreatorDashboard), error) {
+	return func(ctx context.Context) (func(*models.CreatorDashboard), error) {
+		summary, err := ds.streamService.GetChatSummary(ctx, stream)
+		if err != nil {
+			return nil, err
+		}
+		return func(dashboard *models.CreatorDashboard) {
+			dashboard.Chat = summary
+		}, nil
+	}
+}
@@ -0,0 +1,162 @@
+// services/stream-management-service/internal/service/dead_letters.go
+package service
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/services/stream-management-service/internal/config"
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/services/stream-management-service/internal/models"
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/services/stream-management-service/internal/repository"
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/shared/go/pkg/events"
+)
+
+// DeadLetterService keeps the event records consumers gave up on and lets admins inspect,
+// replay and discard them. It is the events.DeadLetterSink of the consumers.
+type DeadLetterService struct {
+	config     *config.Config
+	dynamoRepo *repository.DynamoDBRepository
+
+	mu        sync.RWMutex
+	replayers map[string]func(record []byte) error // by consumer
+}
+
+var _ events.DeadLetterSink = (*DeadLetterService)(nil)
+
+func NewDeadLetterService(cfg *config.Config, dynamoRepo *repository.DynamoDBRepository) *DeadLetterService {
+	return &DeadLetterService{
+		config:     cfg,
+		dynamoRepo: dynamoRepo,
+		replayers:  make(map[string]func(record []byte) error),
+	}
+}
+
+// Register sets how a consumer's dead letters are replayed, usually events.Consumer.Replay
+// with the consumer's handler
+func (ds *DeadLetterService) Register(consumer string, replay func(record []byte) error) {
+	ds.mu.Lock()
+	defer ds.mu.Unlock()
+	ds.replayers[consumer] = replay
+}
+
+// DeadLetter stores a record a consumer gave up on
+func (ds *DeadLetterService) DeadLetter(letter events.DeadLetter) error {
+	failedAt := time.Unix(letter.FailedAt, 0).UTC()
+	stored := &models.DeadLetter{
+		ID:        generateDeadLetterID(),
+		Consumer:  letter.Consumer,
+		EventID:   letter.EventID,
+		EventType: letter.EventType,
+		Reason:    letter.Reason,
+		Record:    letter.Record,
+		Attempts:  letter.Attempts,
+		Status:    models.DeadLetterStatusPending,
+		FailedAt:  failedAt,
+		ExpiresAt: failedAt.Add(ds.config.DeadLetterRetention).Unix(),
+	}
+	if err := ds.dynamoRepo.SaveDeadLetter(stored); err != nil {
+		return err
+	}
+
+	slog.Warn("☠️ Event dead-lettered", "dead_letter_id", stored.ID, "consumer", stored.Consumer, "event_type", stored.EventType, "event_id", stored.EventID)
+	return nil
+}
+
+// ListDeadLetters handles GET /admin/dead-letters, filtered by ?consumer=
+func (ds *DeadLetterService) ListDeadLetters(c *gin.Context) {
+	limit, cursor := parsePagination(c)
+
+	letters, nextCursor, err := ds.dynamoRepo.ListDeadLetters(c.Query("consumer"), limit, cursor)
+	if err != nil {
+		respondPageError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"dead_letters": letters,
+		"count":        len(letters),
+		"next_cursor":  nextCursor,
+	})
+}
+
+// GetDeadLetter handles GET /admin/dead-letters/:id
+func (ds *DeadLetterService) GetDeadLetter(c *gin.Context) {
+	letter, err := ds.dynamoRepo.GetDeadLetter(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Dead letter not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, letter)
+}
+
+// ReplayDeadLetter handles POST /admin/dead-letters/:id/replay. The record is handed to its
+// consumer once more, a failure is recorded on the dead letter and it stays pending.
+func (ds *DeadLetterService) ReplayDeadLetter(c *gin.Context) {
+	letter, err := ds.dynamoRepo.GetDeadLetter(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Dead letter not found"})
+		return
+	}
+	if letter.Status == models.DeadLetterStatusReplayed {
+		c.JSON(http.StatusConflict, gin.H{"error": "Dead letter was already replayed"})
+		return
+	}
+
+	ds.mu.RLock()
+	replay, ok := ds.replayers[letter.Consumer]
+	ds.mu.RUnlock()
+	if !ok {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": "No consumer " + letter.Consumer + " runs on this service"})
+		return
+	}
+
+	if replayErr := replay([]byte(letter.Record)); replayErr != nil {
+		letter.ReplayError = replayErr.Error()
+		if err := ds.dynamoRepo.SaveDeadLetter(letter); err != nil {
+			slog.WarnContext(c.Request.Context(), "⚠️ Could not record failed replay", "dead_letter_id", letter.ID, "error", err)
+		}
+		c.JSON(http.StatusBadGateway, gin.H{"error": "Replay failed: " + replayErr.Error(), "dead_letter": letter})
+		return
+	}
+
+	now := time.Now()
+	letter.Status = models.DeadLetterStatusReplayed
+	letter.ReplayedAt = &now
+	letter.ReplayError = ""
+	if err := ds.dynamoRepo.SaveDeadLetter(letter); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Replayed, but could not update the dead letter"})
+		return
+	}
+
+	slog.InfoContext(c.Request.Context(), "✅ Dead letter replayed", "dead_letter_id", letter.ID, "consumer", letter.Consumer, "event_id", letter.EventID)
+	c.JSON(http.StatusOK, letter)
+}
+
+// DiscardDeadLetter handles DELETE /admin/dead-letters/:id
+func (ds *DeadLetterService) DiscardDeadLetter(c *gin.Context) {
+	letterID := c.Param("id")
+	if _, err := ds.dynamoRepo.GetDeadLetter(letterID); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Dead letter not found"})
+		return
+	}
+
+	if err := ds.dynamoRepo.DeleteDeadLetter(letterID); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not discard dead letter"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Dead letter discarded"})
+}
+
+func generateDeadLetterID() string {
+	bytes := make([]byte, 8)
+	rand.Read(bytes)
+	return "dlq_" + hex.EncodeToString(bytes)
+}
@@ -0,0 +1,235 @@
+// services/stream-management-service/internal/service/directory.go
+package service
+
+import (
+	"fmt"
+	"log/slog"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/services/stream-management-service/internal/classifier"
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/services/stream-management-service/internal/models"
+)
+
+const maxDirectoryLimit = 100
+
+// trendingThreshold is the trending score from which a stream is listed as trending
+const trendingThreshold = 0.5
+
+// GetDirectory handles GET /api/v1/directory?limit=N&language=. Live streams are ranked by a weighted
+// blend of curation, trending and, for signed in viewers, follows, so the homepage has more
+// than the biggest channels even before there is much audience to go by.
+func (s *StreamService) GetDirectory(c *gin.Context) {
+	limit := s.config.DirectoryLimit
+	if value := c.Query("limit"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 1 || parsed > maxDirectoryLimit {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("limit must be between 1 and %d", maxDirectoryLimit)})
+			return
+		}
+		limit = parsed
+	}
+	language, err := NormalizeLanguage(c.Query("language"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	streams, err := s.GetActiveStreamsInternal()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not get active streams"})
+		return
+	}
+	streams = withoutUnlistedStreams(withoutBlockedStreams(streams))
+	if language != "" {
+		streams = withLanguage(streams, language)
+	}
+	s.AttachFollowInfo(streams, ViewerID(c))
+
+	categories, err := s.redisRepo.GetFeaturedCategories()
+	if err != nil {
+		slog.WarnContext(c.Request.Context(), "⚠️ Could not get featured categories", "error", err)
+	}
+	if categories == nil {
+		categories = []string{}
+	}
+	featuredCategories := make(map[string]bool, len(categories))
+	for _, category := range categories {
+		featuredCategories[category] = true
+	}
+
+	entries := s.rankDirectory(streams, featuredCategories)
+	if len(entries) > limit {
+		entries = entries[:limit]
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"streams":             entries,
+		"count":               len(entries),
+		"featured_categories": categories,
+	})
+}
+
+func (s *StreamService) rankDirectory(streams []*models.Stream, featuredCategories map[string]bool) []models.DirectoryEntry {
+	maxViewers := 0
+	for _, stream := range streams {
+		if stream.ViewerCount > maxViewers {
+			maxViewers = stream.ViewerCount
+		}
+	}
+
+	entries := make([]models.DirectoryEntry, 0, len(streams))
+	for _, stream := range streams {
+		entry := models.DirectoryEntry{Stream: stream.Public(), Reasons: []models.DirectoryReason{}}
+
+		if stream.Featured || featuredCategories[stream.Category] {
+			entry.Score += s.config.DirectoryFeaturedWeight
+			entry.Reasons = append(entry.Reasons, models.DirectoryReasonFeatured)
+		}
+		if stream.IsFollowedByViewer != nil && *stream.IsFollowedByViewer {
+			entry.Score += s.config.DirectoryFollowedWeight
+			entry.Reasons = append(entry.Reasons, models.DirectoryReasonFollowed)
+		}
+		if trending := s.trendingScore(stream, maxViewers); trending > 0 {
+			entry.Score += s.config.DirectoryTrendingWeight * trending
+			if trending >= trendingThreshold {
+				entry.Reasons = append(entry.Reasons, models.DirectoryReasonTrending)
+			}
+		}
+
+		entries = append(entries, entry)
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		if entries[i].Score != entries[j].Score {
+			return entries[i].Score > entries[j].Score
+		}
+		return entries[i].Stream.ViewerCount > entries[j].Stream.ViewerCount
+	})
+	return entries
+}
+
+// trendingScore averages the stream's audience, on a log scale against the biggest live
+// stream, with how much it grew over the trending window. Streams younger than the window
+// count as fully grown once they have viewers, so new streams get a chance.
+func (s *StreamService) trendingScore(stream *models.Stream, maxViewers int) float64 {
+	if maxViewers == 0 {
+		return 0
+	}
+	audience := math.Log1p(float64(stream.ViewerCount)) / math.Log1p(float64(maxViewers))
+
+	series, err := s.redisRepo.GetViewerSampleSeries(stream.ID)
+	if err != nil {
+		slog.Warn("⚠️ Could not get viewer samples for trending", "stream_id", stream.ID, "error", err)
+		return audience / 2
+	}
+
+	var latest int64 = -1
+	for bucket := range series {
+		if bucket > latest {
+			latest = bucket
+		}
+	}
+	if latest < 0 {
+		return audience / 2
+	}
+
+	windowBuckets := int64(1)
+	if s.config.ViewerSampleInterval > 0 {
+		windowBuckets = int64(s.config.DirectoryTrendingWindow / s.config.ViewerSampleInterval)
+	}
+	earlier, found := int64(0), false
+	earlierBucket := int64(-1)
+	for bucket, sample := range series {
+		if bucket <= latest-windowBuckets && bucket > earlierBucket {
+			earlier, earlierBucket, found = sample, bucket, true
+		}
+	}
+
+	growth := 0.0
+	switch {
+	case !found && series[latest] > 0:
+		growth = 1
+	case found:
+		growth = float64(series[latest]-earlier) / math.Max(float64(earlier), 1)
+	}
+	growth = math.Max(0, math.Min(growth, 1))
+
+	return (audience + growth) / 2
+}
+
+// FeatureStream handles PUT /admin/streams/:id/featured
+func (s *StreamService) FeatureStream(c *gin.Context) {
+	s.setStreamFeatured(c, true)
+}
+
+// UnfeatureStream handles DELETE /admin/streams/:id/featured
+func (s *StreamService) UnfeatureStream(c *gin.Context) {
+	s.setStreamFeatured(c, false)
+}
+
+func (s *StreamService) setStreamFeatured(c *gin.Context, featured bool) {
+	stream, err := s.GetStreamByIDInternal(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Stream not found"})
+		return
+	}
+
+	stream.Featured = featured
+	if err := s.UpdateStreamInternal(stream); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not update stream"})
+		return
+	}
+	action := models.AuditStreamUnfeatured
+	if featured {
+		action = models.AuditStreamFeatured
+	}
+	s.Audit(c.Request.Context(), stream.ID, action, nil)
+
+	slog.InfoContext(c.Request.Context(), "⭐ Stream curation changed", "stream_id", stream.ID, "featured", featured)
+	c.JSON(http.StatusOK, stream)
+}
+
+// ListFeaturedCategories handles GET /admin/featured-categories
+func (s *StreamService) ListFeaturedCategories(c *gin.Context) {
+	categories, err := s.redisRepo.GetFeaturedCategories()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not get featured categories"})
+		return
+	}
+	sort.Strings(categories)
+
+	c.JSON(http.StatusOK, gin.H{"categories": categories})
+}
+
+// FeatureCategory handles PUT /admin/featured-categories/:category
+func (s *StreamService) FeatureCategory(c *gin.Context) {
+	s.setCategoryFeatured(c, true)
+}
+
+// UnfeatureCategory handles DELETE /admin/featured-categories/:category
+func (s *StreamService) UnfeatureCategory(c *gin.Context) {
+	s.setCategoryFeatured(c, false)
+}
+
+func (s *StreamService) setCategoryFeatured(c *gin.Context, featured bool) {
+	// Categories are stored normalized, as the broadcaster and the classifier set them
+	normalized := classifier.NormalizeTags([]string{c.Param("category")})
+	if len(normalized) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid category"})
+		return
+	}
+	category := normalized[0]
+
+	if err := s.redisRepo.SetCategoryFeatured(category, featured); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not update featured categories"})
+		return
+	}
+
+	slog.InfoContext(c.Request.Context(), "⭐ Category curation changed", "category", category, "featured", featured)
+	c.JSON(http.StatusOK, gin.H{"category": category, "featured": featured})
+}
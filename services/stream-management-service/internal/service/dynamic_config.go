@@ -0,0 +1,91 @@
+// services/stream-management-service/internal/service/dynamic_config.go
+package service
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/services/stream-management-service/internal/config"
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/services/stream-management-service/pkg/aws"
+)
+
+const (
+	DynamicConfigFile = "file"
+	DynamicConfigSSM  = "ssm"
+)
+
+// DynamicConfigService reloads the tunables from DYNAMIC_CONFIG_SOURCE while the service runs
+// and shows admins the ones in effect
+type DynamicConfigService struct {
+	config  *config.Config
+	watcher *config.Watcher // nil when the tunables only come from the environment
+}
+
+func NewDynamicConfigService(cfg *config.Config) (*DynamicConfigService, error) {
+	var source config.Source
+	switch cfg.DynamicConfigSource {
+	case "":
+		return &DynamicConfigService{config: cfg}, nil
+
+	case DynamicConfigFile:
+		source = config.FileSource{Path: cfg.DynamicConfigPath}
+
+	case DynamicConfigSSM:
+		parameters, err := aws.NewSSMParameters(cfg.AWSRegion, cfg.DynamicConfigPath)
+		if err != nil {
+			return nil, err
+		}
+		source = parameters
+
+	default:
+		return nil, fmt.Errorf("unknown DYNAMIC_CONFIG_SOURCE %q, expected file or ssm", cfg.DynamicConfigSource)
+	}
+
+	if cfg.DynamicConfigPath == "" {
+		return nil, fmt.Errorf("DYNAMIC_CONFIG_PATH is required when DYNAMIC_CONFIG_SOURCE is %s", cfg.DynamicConfigSource)
+	}
+
+	return &DynamicConfigService{
+		config:  cfg,
+		watcher: config.NewWatcher(cfg, source, cfg.DynamicConfigInterval),
+	}, nil
+}
+
+// Load puts the source's tunables in effect once, before the services read them
+func (dc *DynamicConfigService) Load(ctx context.Context) error {
+	if dc.watcher == nil {
+		return nil
+	}
+	return dc.watcher.Check(ctx)
+}
+
+// OnChange calls fn with the new tunables after each reload
+func (dc *DynamicConfigService) OnChange(fn func(*config.Tunables)) {
+	if dc.watcher != nil {
+		dc.watcher.OnChange(fn)
+	}
+}
+
+// Start reloads the tunables whenever the source changes, until ctx is done
+func (dc *DynamicConfigService) Start(ctx context.Context) {
+	if dc.watcher != nil {
+		dc.watcher.Start(ctx)
+	}
+}
+
+// GetConfig handles GET /admin/config, the tunables in effect and when they were reloaded
+func (dc *DynamicConfigService) GetConfig(c *gin.Context) {
+	tunables := dc.config.Tunables()
+	response := gin.H{
+		"environment": dc.config.Environment,
+		"tunables":    tunables.Values,
+		"overridden":  tunables.Overridden,
+	}
+	if dc.watcher != nil {
+		response["reload"] = dc.watcher.Status()
+	}
+	c.JSON(http.StatusOK, response)
+}
@@ -0,0 +1,222 @@
+// services/stream-management-service/internal/service/edge_playback.go
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/services/stream-management-service/internal/logging"
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/services/stream-management-service/internal/models"
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/services/stream-management-service/internal/playbacktoken"
+)
+
+// RTMPPlayRequest is the media server's on_play and on_play_done callback. The query of the
+// URL the player connected with, e.g. ?token=...&user_id=..., arrives as fields.
+type RTMPPlayRequest struct {
+	Name       string `json:"name" form:"name"`           // Playback ID being played
+	IP         string `json:"addr" form:"addr"`           // Viewer IP
+	App        string `json:"app" form:"app"`             // Application name
+	ClientID   string `json:"client_id" form:"client_id"` // Media server's ID of the viewer connection
+	Token      string `json:"token" form:"token"`         // Playback token, or viewer token, empty for anonymous viewers
+	UserID     string `json:"user_id" form:"user_id"`     // User the token was issued to
+	Passphrase string `json:"passphrase" form:"passphrase"`
+}
+
+// PlayStream handles POST /rtmp/play. Viewers connecting to the media server present a
+// playback token, or go through the same rules as playback authorization when tokens aren't
+// required. They are counted as watching until /rtmp/play_done.
+func (h *IngestHandler) PlayStream(c *gin.Context) {
+	ctx := c.Request.Context()
+	var req RTMPPlayRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		if err := c.ShouldBind(&req); err != nil {
+			slog.WarnContext(ctx, "❌ Error parsing play request", "error", err)
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request format"})
+			return
+		}
+	}
+	if req.ClientID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "client_id is required"})
+		return
+	}
+
+	playbackID := h.extractStreamKey(req.Name)
+	ctx = logging.With(ctx, "playback_id", playbackID, "client_id", req.ClientID)
+
+	callback, ok := h.beginCallback(c, playbackID, req.ClientID, callbackPlay)
+	if !ok {
+		return
+	}
+	defer h.finishCallback(c, callback)
+
+	stream, streamKey, ok := h.playedStream(c, playbackID)
+	if !ok {
+		return
+	}
+	ctx = logging.With(ctx, "stream_id", stream.ID)
+
+	// A banned key's stream is being shut down, nobody joins it in the meantime
+	ban, err := h.streamService.GetActiveStreamKeyBan(streamKey)
+	if err != nil {
+		slog.ErrorContext(ctx, "❌ Error checking stream key ban", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not authorize playback"})
+		return
+	}
+	if ban != nil {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Stream is not playable", "code": "STREAM_KEY_BANNED"})
+		return
+	}
+
+	viewer := PlaybackViewer{
+		Country:    h.playback.geo.CountryForIP(req.IP),
+		Passphrase: req.Passphrase,
+	}
+	switch {
+	case playbacktoken.IsToken(req.Token):
+		// The token was issued once playback was authorized, only a takedown since matters
+		claims, err := h.playback.ParsePlaybackToken(req.Token, stream.ID)
+		if err != nil {
+			slog.InfoContext(ctx, "🚫 Edge playback refused", "error", err)
+			c.JSON(http.StatusForbidden, gin.H{"error": "Invalid playback token", "code": "INVALID_PLAYBACK_TOKEN"})
+			return
+		}
+		viewer.UserID = claims.ViewerID
+		if stream.Blocked || stream.Deleted() {
+			respondPlaybackError(c, ErrNotPlayable, stream, viewer)
+			return
+		}
+	case h.playback.TokensRequired():
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "A playback token is required", "code": "PLAYBACK_TOKEN_REQUIRED"})
+		return
+	default:
+		if !h.authorizeEdgeViewer(ctx, c, stream, &viewer, req) {
+			return
+		}
+	}
+	if viewer.UserID != 0 {
+		ctx = logging.With(ctx, "viewer_id", viewer.UserID)
+	}
+
+	serverID := c.GetHeader(MediaServerHeader)
+	connectionID := edgeConnectionID(serverID, req.ClientID)
+	uniqueID := connectionID
+	if viewer.UserID != 0 {
+		uniqueID = "u:" + strconv.FormatInt(viewer.UserID, 10)
+	}
+	if err := h.streamService.redisRepo.AddEdgeViewer(stream.ID, connectionID, uniqueID, time.Now(), h.config.ViewerDataTTL); err != nil {
+		slog.WarnContext(ctx, "⚠️ Could not count edge viewer", "error", err)
+	}
+
+	event := map[string]interface{}{
+		"stream_id":     stream.ID,
+		"user_id":       viewer.UserID,
+		"connection_id": connectionID,
+		"country":       viewer.Country,
+		"media_server":  serverID,
+	}
+	if err := h.streamService.PublishEvent("viewer_joined", event); err != nil {
+		slog.WarnContext(ctx, "⚠️ Could not publish viewer joined event", "error", err)
+	}
+
+	slog.InfoContext(ctx, "👀 Edge viewer joined", "country", viewer.Country)
+	h.respondCallback(c, callback, http.StatusOK, gin.H{
+		"message":   "Playback authorized",
+		"stream_id": stream.ID,
+	})
+}
+
+// PlayDone handles POST /rtmp/play_done when a viewer connection closes
+func (h *IngestHandler) PlayDone(c *gin.Context) {
+	ctx := c.Request.Context()
+	var req RTMPPlayRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		if err := c.ShouldBind(&req); err != nil {
+			slog.WarnContext(ctx, "❌ Error parsing play done request", "error", err)
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request format"})
+			return
+		}
+	}
+	if req.ClientID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "client_id is required"})
+		return
+	}
+
+	playbackID := h.extractStreamKey(req.Name)
+	ctx = logging.With(ctx, "playback_id", playbackID, "client_id", req.ClientID)
+
+	// Once the stream ended there is nobody left to stop counting
+	streamKey, err := h.streamService.StreamKeyForPlayback(playbackID)
+	if err != nil {
+		c.JSON(http.StatusOK, gin.H{"message": "Stream is not live"})
+		return
+	}
+	session, err := h.streamService.GetStreamSession(streamKey)
+	if err != nil {
+		c.JSON(http.StatusOK, gin.H{"message": "Stream is not live"})
+		return
+	}
+	if !session.Started() {
+		c.JSON(http.StatusOK, gin.H{"message": "Stream is not live"})
+		return
+	}
+	streamID := session.StreamID
+
+	connectionID := edgeConnectionID(c.GetHeader(MediaServerHeader), req.ClientID)
+	if err := h.streamService.redisRepo.RemoveEdgeViewer(streamID, connectionID); err != nil {
+		slog.WarnContext(ctx, "⚠️ Could not remove edge viewer", "stream_id", streamID, "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not remove viewer"})
+		return
+	}
+
+	slog.DebugContext(ctx, "👋 Edge viewer left", "stream_id", streamID)
+	c.JSON(http.StatusOK, gin.H{"message": "Viewer left", "stream_id": streamID})
+}
+
+// authorizeEdgeViewer identifies a viewer from their viewer token and runs the playback
+// authorization rules. It answers the callback when the viewer is refused.
+func (h *IngestHandler) authorizeEdgeViewer(ctx context.Context, c *gin.Context, stream *models.Stream, viewer *PlaybackViewer, req RTMPPlayRequest) bool {
+	if req.Token != "" {
+		viewerID, err := h.viewerAuth.Verify(req.Token, req.UserID)
+		if err != nil {
+			slog.WarnContext(ctx, "⚠️ Could not validate viewer", "user_id", req.UserID, "error", err)
+			c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Could not validate viewer"})
+			return false
+		}
+		if viewerID == 0 {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid viewer token", "code": "INVALID_VIEWER_TOKEN"})
+			return false
+		}
+		viewer.UserID = viewerID
+	}
+
+	if _, err := h.playback.Authorize(ctx, stream, *viewer); err != nil {
+		slog.InfoContext(ctx, "🚫 Edge playback refused", "error", err)
+		respondPlaybackError(c, err, stream, *viewer)
+		return false
+	}
+	return true
+}
+
+// playedStream returns the live stream behind a playback ID and its stream key, answering 404
+// when there is none
+func (h *IngestHandler) playedStream(c *gin.Context, playbackID string) (*models.Stream, string, bool) {
+	stream, streamKey, err := h.playback.LiveStreamByPlaybackID(playbackID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Stream is not live", "code": "STREAM_NOT_LIVE"})
+		return nil, "", false
+	}
+	return stream, streamKey, true
+}
+
+// edgeConnectionID names a viewer connection, client IDs are only unique per media server
+func edgeConnectionID(serverID, clientID string) string {
+	return fmt.Sprintf("e:%s:%s", serverID, clientID)
+}
@@ -0,0 +1,52 @@
+// services/stream-management-service/internal/service/event_bus.go
+package service
+
+import (
+	"sync"
+
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/shared/go/pkg/events"
+)
+
+// EventBus hands the events this replica publishes to in-process subscribers, next to
+// Kinesis. Delivery is best effort, a subscriber that falls behind misses events rather than
+// holding up publishing.
+type EventBus struct {
+	mu          sync.RWMutex
+	subscribers map[chan *events.Envelope]struct{}
+}
+
+func NewEventBus() *EventBus {
+	return &EventBus{subscribers: make(map[chan *events.Envelope]struct{})}
+}
+
+// Subscribe returns a channel receiving every event published from now on, buffering up to
+// buffer of them, and the function that ends the subscription and closes the channel
+func (b *EventBus) Subscribe(buffer int) (<-chan *events.Envelope, func()) {
+	ch := make(chan *events.Envelope, buffer)
+
+	b.mu.Lock()
+	b.subscribers[ch] = struct{}{}
+	b.mu.Unlock()
+
+	var once sync.Once
+	return ch, func() {
+		once.Do(func() {
+			b.mu.Lock()
+			delete(b.subscribers, ch)
+			b.mu.Unlock()
+			close(ch)
+		})
+	}
+}
+
+func (b *EventBus) publish(envelope *events.Envelope) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	for ch := range b.subscribers {
+		select {
+		case ch <- envelope:
+		default: // subscriber is behind
+		}
+	}
+}
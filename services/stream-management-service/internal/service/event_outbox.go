@@ -0,0 +1,160 @@
+// services/stream-management-service/internal/service/event_outbox.go
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"time"
+
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/services/stream-management-service/internal/models"
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/shared/go/pkg/events"
+)
+
+const (
+	// outboxRelayBatch is how many outbox events go out in one delivery
+	outboxRelayBatch = 100
+	// outboxRelayBatches bounds one relay, so a replica holds the claim for a short while
+	outboxRelayBatches = 10
+)
+
+// StreamEvent is an event about a stream, written to the outbox in the same transaction as
+// the stream change it reports so a crash can't lose one without the other
+type StreamEvent struct {
+	Type string
+	Data map[string]interface{}
+}
+
+// putStreamWithEvents stores a stream together with its events in the outbox. The in-process
+// bus gets the events right away, the outbox relay sends them to the event bus.
+func (s *StreamService) putStreamWithEvents(ctx context.Context, stream *models.Stream, pending []StreamEvent) error {
+	now := time.Now()
+	outbox := make([]*models.OutboxEvent, 0, len(pending))
+	envelopes := make([]*events.Envelope, 0, len(pending))
+	for _, event := range pending {
+		// An event that doesn't match its schema is left out rather than failing the change
+		envelope, err := s.eventSchemas.NewEnvelope("stream-management-service", event.Type, event.Data)
+		if err != nil {
+			slog.WarnContext(ctx, "⚠️ Could not publish invalid event", "event_type", event.Type, "stream_id", stream.ID, "error", err)
+			continue
+		}
+		record, err := json.Marshal(envelope)
+		if err != nil {
+			slog.WarnContext(ctx, "⚠️ Could not marshal event", "event_type", event.Type, "stream_id", stream.ID, "error", err)
+			continue
+		}
+
+		outbox = append(outbox, &models.OutboxEvent{
+			ID:        envelope.EventID,
+			EventType: event.Type,
+			StreamID:  stream.ID,
+			Record:    string(record),
+			Status:    models.OutboxPending,
+			CreatedAt: now,
+		})
+		envelopes = append(envelopes, envelope)
+	}
+
+	if err := s.dynamoRepo.PutStreamWithEvents(ctx, stream, outbox); err != nil {
+		return err
+	}
+
+	for _, envelope := range envelopes {
+		s.eventBus.publish(envelope)
+	}
+	return nil
+}
+
+// StartOutboxRelay periodically delivers the events waiting in the outbox to the event bus
+func (s *StreamService) StartOutboxRelay(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(s.config.OutboxPollInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				s.relayOutbox(ctx)
+			}
+		}
+	}()
+}
+
+// relayOutbox sends the oldest pending outbox events, stopping at the first batch the bus
+// doesn't fully take. Events it did take are marked delivered either way. An event sent but
+// not marked goes out again, consumers see it at least once and dedupe by event ID.
+func (s *StreamService) relayOutbox(ctx context.Context) {
+	if s.eventsDisabled {
+		return
+	}
+
+	claimed, err := s.redisRepo.ClaimOutboxRelay(s.config.OutboxPollInterval)
+	if err != nil {
+		slog.WarnContext(ctx, "⚠️ Could not claim outbox relay", "error", err)
+		return
+	}
+	if !claimed {
+		return
+	}
+
+	relayed := 0
+	for i := 0; i < outboxRelayBatches && ctx.Err() == nil; i++ {
+		pending, err := s.dynamoRepo.GetPendingOutboxEvents(outboxRelayBatch)
+		if err != nil {
+			slog.WarnContext(ctx, "⚠️ Could not read pending outbox events", "error", err)
+			break
+		}
+		if len(pending) == 0 {
+			break
+		}
+
+		ids := make([]string, len(pending))
+		records := make([][]byte, len(pending))
+		for j, event := range pending {
+			ids[j] = event.ID
+			records[j] = []byte(event.Record)
+		}
+
+		failed, err := s.deliverOutbox(records)
+		delivered := withoutIndexes(ids, failed)
+		now := time.Now()
+		if markErr := s.dynamoRepo.MarkOutboxEventsDelivered(delivered, now, now.Add(s.config.OutboxRetention).Unix()); markErr != nil {
+			// They stay pending and go out again next time
+			slog.WarnContext(ctx, "⚠️ Could not mark outbox events delivered", "error", markErr)
+			break
+		}
+		relayed += len(delivered)
+
+		if err != nil {
+			slog.WarnContext(ctx, "⚠️ The event bus is not taking outbox events", "failed", len(failed), "error", err)
+			break
+		}
+		if len(pending) < outboxRelayBatch {
+			break
+		}
+	}
+
+	if relayed > 0 {
+		slog.DebugContext(ctx, "📤 Relayed outbox events", "events", relayed)
+	}
+}
+
+// deliverOutbox sends records to the event bus. The development stand-in delivers nothing, it
+// takes them into its queue like published events.
+func (s *StreamService) deliverOutbox(records [][]byte) ([]int, error) {
+	if !s.publisher.Mock() {
+		return s.publisher.Deliver(records)
+	}
+
+	var failed []int
+	var lastErr error
+	for i, record := range records {
+		if err := s.publisher.Publish(record); err != nil {
+			failed = append(failed, i)
+			lastErr = err
+		}
+	}
+	return failed, lastErr
+}
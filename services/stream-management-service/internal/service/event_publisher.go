@@ -0,0 +1,76 @@
+// services/stream-management-service/internal/service/event_publisher.go
+package service
+
+import (
+	"fmt"
+	"log/slog"
+
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/services/stream-management-service/internal/config"
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/services/stream-management-service/pkg/aws"
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/services/stream-management-service/pkg/kafka"
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/services/stream-management-service/pkg/nats"
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/shared/go/pkg/eventbus"
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/shared/go/pkg/events"
+)
+
+const (
+	EventBusKinesis = "kinesis"
+	EventBusKafka   = "kafka"
+	EventBusNATS    = "nats"
+)
+
+// newEventPublisher returns the event bus EVENT_BUS picks, they all queue the same way and
+// hand what they can't deliver to spool
+func newEventPublisher(cfg *config.Config, spool events.Spool) (events.Bus, error) {
+	switch cfg.EventBus {
+	case "", EventBusKinesis:
+		client := aws.NewKinesisClient(cfg.AWSRegion, cfg.KinesisStreamName)
+		return aws.NewKinesisPublisher(client, aws.PublisherConfig{
+			QueueSize:     cfg.EventQueueSize,
+			BatchSize:     cfg.EventBatchSize,
+			FlushInterval: cfg.EventFlushInterval,
+			MaxRetries:    cfg.EventMaxRetries,
+			Spool:         spool,
+		}), nil
+
+	case EventBusKafka:
+		if len(cfg.KafkaBrokers) == 0 || cfg.KafkaDefaultTopic == "" {
+			return nil, fmt.Errorf("KAFKA_BROKERS and KAFKA_DEFAULT_TOPIC are required when EVENT_BUS is %s", cfg.EventBus)
+		}
+		slog.Info("📡 Publishing events to Kafka", "brokers", cfg.KafkaBrokers, "default_topic", cfg.KafkaDefaultTopic, "routed_event_types", len(cfg.KafkaTopics))
+		return kafka.NewPublisher(kafka.Config{
+			Brokers:       cfg.KafkaBrokers,
+			ClientID:      cfg.KafkaClientID,
+			DefaultTopic:  cfg.KafkaDefaultTopic,
+			Topics:        cfg.KafkaTopics,
+			QueueSize:     cfg.EventQueueSize,
+			BatchSize:     cfg.EventBatchSize,
+			FlushInterval: cfg.EventFlushInterval,
+			MaxRetries:    cfg.EventMaxRetries,
+			Spool:         spool,
+		}), nil
+
+	case EventBusNATS:
+		slog.Info("📡 Publishing events to NATS JetStream", "url", cfg.NATSURL, "stream", eventbus.StreamName(eventbus.DomainStream))
+		publisher, err := nats.NewPublisher(nats.Config{
+			URL:             cfg.NATSURL,
+			Name:            "stream-management-service",
+			CredentialsFile: cfg.NATSCredentialsFile,
+			CreateStream:    cfg.NATSCreateStream,
+			StreamOptions: eventbus.StreamOptions{
+				Replicas: cfg.NATSStreamReplicas,
+				MaxAge:   cfg.NATSStreamMaxAge,
+			},
+			QueueSize:  cfg.EventQueueSize,
+			BatchSize:  cfg.EventBatchSize,
+			MaxRetries: cfg.EventMaxRetries,
+			Spool:      spool,
+		})
+		if err != nil {
+			return nil, err
+		}
+		return publisher, nil
+	}
+
+	return nil, fmt.Errorf("unknown EVENT_BUS %q, expected kinesis, kafka or nats", cfg.EventBus)
+}
@@ -0,0 +1,120 @@
+// services/stream-management-service/internal/service/event_spool.go
+package service
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/services/stream-management-service/internal/repository"
+)
+
+const (
+	// spoolReplayBatch is how many spooled events go out in one delivery
+	spoolReplayBatch = 500
+	// spoolReplayBatches bounds one replay, so a replica holds the claim for a short while
+	spoolReplayBatches = 20
+)
+
+// eventSpool keeps the events the bus couldn't take in Redis until they are replayed
+type eventSpool struct {
+	redisRepo repository.StreamCache
+	maxLen    int64
+}
+
+func (es *eventSpool) Spool(events [][]byte) error {
+	return es.redisRepo.SpoolEvents(events, es.maxLen)
+}
+
+// StartEventReplayer periodically drains spooled events into the bus once it takes them again
+func (s *StreamService) StartEventReplayer(ctx context.Context) {
+	if s.publisher.Mock() {
+		slog.InfoContext(ctx, "🔧 [MOCK] Not replaying spooled events in development")
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(s.config.EventReplayInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				s.replaySpooledEvents(ctx)
+			}
+		}
+	}()
+}
+
+// replaySpooledEvents sends the oldest spooled events, stopping at the first batch the bus
+// doesn't fully take. Events it did take are removed from the spool either way.
+func (s *StreamService) replaySpooledEvents(ctx context.Context) {
+	if s.eventsDisabled {
+		return
+	}
+
+	claimed, err := s.redisRepo.ClaimEventReplay(s.config.EventReplayInterval)
+	if err != nil {
+		slog.WarnContext(ctx, "⚠️ Could not claim event replay", "error", err)
+		return
+	}
+	if !claimed {
+		return
+	}
+
+	replayed := 0
+	for i := 0; i < spoolReplayBatches && ctx.Err() == nil; i++ {
+		ids, events, err := s.redisRepo.GetSpooledEvents(spoolReplayBatch)
+		if err != nil {
+			slog.WarnContext(ctx, "⚠️ Could not read spooled events", "error", err)
+			break
+		}
+		if len(events) == 0 {
+			break
+		}
+
+		failed, err := s.publisher.Deliver(events)
+		delivered := withoutIndexes(ids, failed)
+		if delErr := s.redisRepo.DeleteSpooledEvents(delivered); delErr != nil {
+			// They stay spooled and go out again next time
+			slog.WarnContext(ctx, "⚠️ Could not remove replayed events from the spool", "error", delErr)
+			break
+		}
+		replayed += len(delivered)
+
+		if err != nil {
+			slog.WarnContext(ctx, "⚠️ The event bus is still not taking spooled events", "failed", len(failed), "error", err)
+			break
+		}
+		if len(events) < spoolReplayBatch {
+			break
+		}
+	}
+
+	if replayed > 0 {
+		slog.InfoContext(ctx, "📤 Replayed spooled events", "events", replayed)
+	}
+}
+
+// SpooledEventCount returns how many events wait to be replayed
+func (s *StreamService) SpooledEventCount() (int64, error) {
+	return s.redisRepo.CountSpooledEvents()
+}
+
+// withoutIndexes returns the IDs that aren't at one of the given indexes
+func withoutIndexes(ids []string, indexes []int) []string {
+	skip := make(map[int]bool, len(indexes))
+	for _, index := range indexes {
+		skip[index] = true
+	}
+
+	kept := make([]string, 0, len(ids))
+	for i, id := range ids {
+		if !skip[i] {
+			kept = append(kept, id)
+		}
+	}
+	return kept
+}
@@ -0,0 +1,87 @@
+// services/stream-management-service/internal/service/fault_injection.go
+package service
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/services/stream-management-service/internal/config"
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/services/stream-management-service/pkg/faults"
+)
+
+// FaultInjectionService lets admins inject latency and errors into the service's DynamoDB,
+// Redis and user service calls, to exercise the fallbacks that otherwise only run in outages
+type FaultInjectionService struct {
+	config   *config.Config
+	injector *faults.Injector // nil when fault injection is off
+}
+
+type SetFaultsRequest struct {
+	Rules []faults.Rule `json:"rules"`
+}
+
+func NewFaultInjectionService(cfg *config.Config) *FaultInjectionService {
+	return &FaultInjectionService{config: cfg, injector: cfg.Faults()}
+}
+
+// GetFaults handles GET /admin/faults, the rules in effect and the calls they hit
+func (fs *FaultInjectionService) GetFaults(c *gin.Context) {
+	if fs.injector == nil {
+		c.JSON(http.StatusOK, gin.H{"enabled": false})
+		return
+	}
+
+	configured, set := fs.injector.Rules()
+	c.JSON(http.StatusOK, gin.H{
+		"enabled":    true,
+		"configured": configured,
+		"rules":      set,
+		"stats":      fs.injector.Stats(),
+	})
+}
+
+// SetFaults handles PUT /admin/faults, replacing the rules set by admins. The configured
+// rules stay in effect on top of them.
+func (fs *FaultInjectionService) SetFaults(c *gin.Context) {
+	if !fs.enabled(c) {
+		return
+	}
+
+	var req SetFaultsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := fs.injector.SetRules(req.Rules); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	slog.WarnContext(c.Request.Context(), "💥 Fault injection rules changed", "rules", len(req.Rules))
+	c.JSON(http.StatusOK, gin.H{"rules": req.Rules})
+}
+
+// ClearFaults handles DELETE /admin/faults, dropping the rules set by admins
+func (fs *FaultInjectionService) ClearFaults(c *gin.Context) {
+	if !fs.enabled(c) {
+		return
+	}
+
+	_ = fs.injector.SetRules(nil)
+	slog.InfoContext(c.Request.Context(), "✅ Fault injection rules cleared")
+	c.JSON(http.StatusOK, gin.H{"message": "Fault injection rules cleared"})
+}
+
+func (fs *FaultInjectionService) enabled(c *gin.Context) bool {
+	if fs.injector != nil {
+		return true
+	}
+	message := "Fault injection is disabled, set FAULT_INJECTION_ENABLED"
+	if fs.config.Environment == "production" {
+		message = "Fault injection is never enabled in production"
+	}
+	c.JSON(http.StatusConflict, gin.H{"error": message})
+	return false
+}
@@ -0,0 +1,353 @@
+// services/stream-management-service/internal/service/fingerprint_service.go
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"net/http"
+	"os/exec"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/services/stream-management-service/internal/config"
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/services/stream-management-service/internal/fingerprint"
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/services/stream-management-service/internal/models"
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/services/stream-management-service/internal/repository"
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/services/stream-management-service/pkg/aws"
+)
+
+const (
+	// fingerprintQueueSize bounds the number of recordings waiting to be scanned
+	fingerprintQueueSize = 100
+	// maxFingerprintSamples bounds the samples taken from one recording
+	maxFingerprintSamples = 2000
+	// wavHeaderSize is what ffmpeg writes for a sample without any audio
+	wavHeaderSize = 44
+)
+
+// FingerprintService scans recordings for copyrighted music once they become VODs, before
+// they are served, and records the matches on the VOD and its stream. With
+// FINGERPRINT_AUTO_MUTE the matched ranges are muted in the VOD renditions.
+type FingerprintService struct {
+	config        *config.Config
+	dynamoRepo    *repository.DynamoDBRepository
+	streamService *StreamService
+	s3Client      *aws.S3Client
+	packager      *VODPackager
+	provider      fingerprint.Provider
+	jobs          chan string
+	disabled      error // set when recordings can't be scanned
+}
+
+func NewFingerprintService(cfg *config.Config, dynamoRepo *repository.DynamoDBRepository, streamService *StreamService, packager *VODPackager) *FingerprintService {
+	fs := &FingerprintService{
+		config:        cfg,
+		dynamoRepo:    dynamoRepo,
+		streamService: streamService,
+		s3Client:      aws.NewS3Client(cfg.AWSRegion, cfg.S3BucketName),
+		packager:      packager,
+		jobs:          make(chan string, fingerprintQueueSize),
+	}
+
+	provider, err := fingerprint.NewProvider(cfg.FingerprintProvider, cfg.FingerprintURL, cfg.FingerprintAPIKey)
+	switch {
+	case err != nil:
+		fs.Disable(err)
+	case provider == nil:
+		fs.disabled = errors.New("FINGERPRINT_PROVIDER is not set")
+	default:
+		fs.provider = provider
+	}
+	return fs
+}
+
+// Disable stops scanning recordings
+func (fs *FingerprintService) Disable(reason error) {
+	if fs.disabled == nil {
+		slog.Warn("🚫 Audio fingerprinting disabled", "reason", reason)
+		fs.disabled = reason
+	}
+}
+
+// StartWorkers starts the background workers that scan queued recordings
+func (fs *FingerprintService) StartWorkers(ctx context.Context) {
+	if fs.disabled != nil {
+		return
+	}
+
+	workers := fs.config.FingerprintWorkers
+	if workers < 1 {
+		workers = 1
+	}
+
+	for i := 0; i < workers; i++ {
+		go func() {
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case vodID := <-fs.jobs:
+					if err := fs.scanVOD(ctx, vodID); err != nil {
+						slog.Error("❌ Audio fingerprinting failed", "vod_id", vodID, "error", err)
+					}
+				}
+			}
+		}()
+	}
+
+	slog.Info("🎵 Started fingerprint workers", "workers", workers, "provider", fs.provider.Name())
+}
+
+// Enqueue queues a VOD's recording to be scanned
+func (fs *FingerprintService) Enqueue(vodID string) error {
+	if fs.disabled != nil {
+		return nil
+	}
+
+	select {
+	case fs.jobs <- vodID:
+		return nil
+	default:
+		return fmt.Errorf("fingerprint queue is full")
+	}
+}
+
+// RescanVOD handles POST /admin/vods/:id/fingerprint
+func (fs *FingerprintService) RescanVOD(c *gin.Context) {
+	if fs.disabled != nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Audio fingerprinting is unavailable: " + fs.disabled.Error()})
+		return
+	}
+
+	vod, err := fs.dynamoRepo.GetVODByID(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "VOD not found"})
+		return
+	}
+
+	if err := fs.Enqueue(vod.ID); err != nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Fingerprint queue is full, try again later"})
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{"message": "VOD queued for fingerprinting", "vod_id": vod.ID})
+}
+
+// scanVOD sends the recording to the provider sample by sample and stores what it matched
+func (fs *FingerprintService) scanVOD(ctx context.Context, vodID string) error {
+	vod, err := fs.dynamoRepo.GetVODByID(vodID)
+	if err != nil {
+		return err
+	}
+
+	// Rescans look at the recording as it was before anything was muted
+	originals := vod.Renditions
+	if len(vod.UnmutedRenditions) > 0 {
+		originals = vod.UnmutedRenditions
+	}
+	source := sourceURL(originals)
+	if source == "" {
+		return fmt.Errorf("vod has no rendition to scan")
+	}
+
+	slog.Info("🎵 Scanning recording for copyrighted music", "vod_id", vod.ID)
+
+	length := int64(fs.config.FingerprintSampleLength.Seconds())
+	if length < 5 {
+		length = 5
+	}
+
+	var found []models.AudioMatch
+	for i := int64(0); i < maxFingerprintSamples; i++ {
+		start := i * length
+		if vod.Duration > 0 && start >= vod.Duration {
+			break
+		}
+
+		audio, err := fs.extractSample(ctx, source, start, length)
+		if err != nil {
+			return err
+		}
+		if len(audio) <= wavHeaderSize {
+			break // past the end of the recording
+		}
+
+		matches, err := fs.provider.Identify(ctx, fingerprint.Sample{Start: start, Duration: length, Audio: audio})
+		if err != nil {
+			return fmt.Errorf("sample at %ds: %w", start, err)
+		}
+		for _, match := range matches {
+			if match.Score < fs.config.FingerprintMinScore {
+				continue
+			}
+			found = append(found, toAudioMatch(match, start, length, fs.provider.Name()))
+		}
+	}
+
+	matches := mergeAudioMatches(found)
+
+	// The VOD may have changed during a long scan
+	if latest, err := fs.dynamoRepo.GetVODByID(vod.ID); err == nil {
+		vod = latest
+	}
+
+	muted := false
+	if fs.config.Tunables().FingerprintAutoMute && len(matches) > 0 {
+		if vod.TakedownID != "" {
+			slog.Info("ℹ️ Not auto-muting a VOD under takedown", "vod_id", vod.ID, "takedown_id", vod.TakedownID)
+		} else if err := fs.autoMute(vod, originals, matches); err != nil {
+			slog.Warn("⚠️ Could not auto-mute VOD", "vod_id", vod.ID, "error", err)
+		} else {
+			muted = true
+		}
+	}
+
+	now := time.Now()
+	vod.AudioMatches = matches
+	vod.AudioScannedAt = &now
+	vod.UpdatedAt = now
+	if err := fs.dynamoRepo.SaveVOD(vod); err != nil {
+		return err
+	}
+	if muted {
+		if err := fs.packager.Enqueue(vod.ID); err != nil {
+			slog.Warn("⚠️ Could not queue muted VOD for packaging", "vod_id", vod.ID, "error", err)
+		}
+	}
+
+	if vod.StreamID != "" {
+		if stream, err := fs.streamService.GetStreamByIDInternal(vod.StreamID); err == nil {
+			stream.AudioMatches = matches
+			if err := fs.streamService.UpdateStreamInternal(stream); err != nil {
+				slog.Warn("⚠️ Could not record audio matches on stream", "stream_id", stream.ID, "error", err)
+			}
+		}
+	}
+
+	slog.Info("✅ Recording scanned", "vod_id", vod.ID, "matches", len(matches), "muted", muted)
+	if len(matches) == 0 {
+		return nil
+	}
+
+	event := map[string]interface{}{
+		"vod_id":    vod.ID,
+		"stream_id": vod.StreamID,
+		"user_id":   vod.UserID,
+		"matches":   matches,
+		"muted":     muted,
+	}
+	if err := fs.streamService.PublishEvent("audio_matches_found", event); err != nil {
+		slog.Warn("⚠️ Could not publish audio matches event", "vod_id", vod.ID, "error", err)
+	}
+	return nil
+}
+
+// autoMute replaces the VOD's renditions with one where the matched ranges are silent,
+// keeping the originals so a later rescan or review can restore them
+func (fs *FingerprintService) autoMute(vod *models.VOD, originals []models.Rendition, matches []models.AudioMatch) error {
+	ranges := make([]models.TimeRange, len(matches))
+	for i, match := range matches {
+		ranges[i] = models.TimeRange{Start: match.Start, End: match.End}
+	}
+
+	unmuted := *vod
+	unmuted.Renditions = originals
+	rendition, err := muteRecording(fs.config, fs.s3Client, &unmuted, ranges, fmt.Sprintf("automuted_%d", time.Now().Unix()))
+	if err != nil {
+		return err
+	}
+
+	vod.UnmutedRenditions = originals
+	vod.Renditions = []models.Rendition{rendition}
+	vod.MutedRanges = ranges
+	for i := range matches {
+		matches[i].Muted = true
+	}
+
+	slog.Info("🔇 VOD auto-muted", "vod_id", vod.ID, "ranges", len(ranges))
+	return nil
+}
+
+// extractSample decodes a slice of the recording's audio to mono 16 kHz WAV
+func (fs *FingerprintService) extractSample(ctx context.Context, source string, start, length int64) ([]byte, error) {
+	source = strings.TrimPrefix(source, "file://")
+
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Minute)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, fs.config.FFmpegPath,
+		"-v", "error",
+		"-ss", strconv.FormatInt(start, 10),
+		"-t", strconv.FormatInt(length, 10),
+		"-i", source,
+		"-vn",
+		"-ac", "1",
+		"-ar", "16000",
+		"-f", "wav",
+		"pipe:1",
+	)
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("ffmpeg failed: %w: %s", err, lastLine(stderr.String()))
+	}
+
+	return stdout.Bytes(), nil
+}
+
+// toAudioMatch places a provider match on the recording's timeline
+func toAudioMatch(match fingerprint.Match, sampleStart, sampleLength int64, provider string) models.AudioMatch {
+	start, end := sampleStart, sampleStart+sampleLength
+	if match.End > match.Start {
+		start = sampleStart + int64(math.Floor(match.Start))
+		end = sampleStart + int64(math.Ceil(match.End))
+	}
+
+	return models.AudioMatch{
+		Start:    start,
+		End:      end,
+		Title:    match.Title,
+		Artist:   match.Artist,
+		ISRC:     match.ISRC,
+		Score:    match.Score,
+		Provider: provider,
+	}
+}
+
+// mergeAudioMatches joins matches of the same track in consecutive samples into one range
+func mergeAudioMatches(matches []models.AudioMatch) []models.AudioMatch {
+	sort.Slice(matches, func(i, j int) bool { return matches[i].Start < matches[j].Start })
+
+	merged := []models.AudioMatch{}
+	open := map[string]int{} // track -> index in merged of its latest range
+	for _, match := range matches {
+		track := match.ISRC
+		if track == "" {
+			track = match.Artist + "\x00" + match.Title
+		}
+
+		if i, ok := open[track]; ok && match.Start <= merged[i].End+1 {
+			if match.End > merged[i].End {
+				merged[i].End = match.End
+			}
+			if match.Score > merged[i].Score {
+				merged[i].Score = match.Score
+			}
+			continue
+		}
+
+		open[track] = len(merged)
+		merged = append(merged, match)
+	}
+	return merged
+}
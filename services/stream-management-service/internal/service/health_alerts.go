@@ -0,0 +1,224 @@
+// services/stream-management-service/internal/service/health_alerts.go
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/services/stream-management-service/internal/config"
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/services/stream-management-service/internal/models"
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/services/stream-management-service/internal/repository"
+)
+
+// healthAlertWindow is the number of recent samples a threshold must be crossed over, so a
+// single bad report doesn't alert
+const healthAlertWindow = 3
+
+// HealthAlertService warns broadcasters when their ingest health crosses a threshold. Alerts
+// are delivered through the chat service, which pushes them to the broadcaster's dashboard
+// socket and posts them in the stream's chat for moderators only.
+type HealthAlertService struct {
+	config        *config.Config
+	redisRepo     *repository.RedisRepository
+	streamService *StreamService
+	httpClient    *http.Client
+}
+
+func NewHealthAlertService(cfg *config.Config, redisRepo *repository.RedisRepository, streamService *StreamService) *HealthAlertService {
+	return &HealthAlertService{
+		config:        cfg,
+		redisRepo:     redisRepo,
+		streamService: streamService,
+		httpClient:    chatServiceClient(cfg),
+	}
+}
+
+// Evaluate checks the latest health samples of a live stream and sends the alerts they call for
+func (as *HealthAlertService) Evaluate(ctx context.Context, streamID string) {
+	samples, err := as.streamService.loadHealthSamples(streamID)
+	if err != nil {
+		slog.WarnContext(ctx, "⚠️ Could not load health samples for alerts", "stream_id", streamID, "error", err)
+		return
+	}
+
+	alerts := as.check(samples)
+	if len(alerts) == 0 {
+		return
+	}
+
+	stream, err := as.streamService.GetStreamByIDInternal(streamID)
+	if err != nil {
+		slog.WarnContext(ctx, "⚠️ Could not get stream for health alerts", "stream_id", streamID, "error", err)
+		return
+	}
+	if stream.Status != models.StreamStatusLive {
+		return
+	}
+
+	for _, alert := range alerts {
+		as.raise(ctx, stream, alert, as.config.HealthAlertCooldown)
+	}
+}
+
+// raise sends an alert unless one of its kind was sent for the stream within cooldown
+func (as *HealthAlertService) raise(ctx context.Context, stream *models.Stream, alert *models.HealthAlert, cooldown time.Duration) {
+	claimed, err := as.redisRepo.ClaimHealthAlert(stream.ID, string(alert.Kind), cooldown)
+	if err != nil {
+		slog.WarnContext(ctx, "⚠️ Could not claim health alert", "stream_id", stream.ID, "kind", alert.Kind, "error", err)
+		return
+	}
+	if !claimed {
+		return // sent recently
+	}
+
+	alert.StreamID = stream.ID
+	alert.UserID = stream.UserID
+	alert.CreatedAt = time.Now()
+	as.send(ctx, stream, alert)
+}
+
+// check compares the recent samples (newest first) against the alert thresholds
+func (as *HealthAlertService) check(samples []models.HealthSample) []*models.HealthAlert {
+	if len(samples) < healthAlertWindow {
+		return nil
+	}
+	window := samples[:healthAlertWindow]
+
+	var totalBitrate int
+	var totalFPS, expectedFrames, droppedFrames float64
+	for i, sample := range window {
+		totalBitrate += sample.BitrateKbps
+		totalFPS += sample.FPS
+
+		if i+1 < len(samples) {
+			interval := sample.Timestamp.Sub(samples[i+1].Timestamp).Seconds()
+			if interval > 0 {
+				expectedFrames += sample.FPS * interval
+				droppedFrames += float64(sample.DroppedFrames)
+			}
+		}
+	}
+	bitrate := totalBitrate / len(window)
+	fps := totalFPS / float64(len(window))
+
+	var alerts []*models.HealthAlert
+
+	if threshold := as.config.HealthAlertMinBitrateKbps; threshold > 0 && bitrate < threshold {
+		alerts = append(alerts, &models.HealthAlert{
+			Kind:      models.HealthAlertLowBitrate,
+			Severity:  severity(bitrate < criticalBitrateKbps),
+			Message:   fmt.Sprintf("Your bitrate dropped below %s (currently %s)", formatBitrate(threshold), formatBitrate(bitrate)),
+			Value:     float64(bitrate),
+			Threshold: float64(threshold),
+		})
+	}
+
+	if threshold := as.config.HealthAlertMinFPS; threshold > 0 && fps < threshold {
+		alerts = append(alerts, &models.HealthAlert{
+			Kind:      models.HealthAlertLowFrameRate,
+			Severity:  severity(fps < criticalFPS),
+			Message:   fmt.Sprintf("Your frame rate dropped below %.0f fps (currently %.1f fps)", threshold, fps),
+			Value:     fps,
+			Threshold: threshold,
+		})
+	}
+
+	if threshold := as.config.HealthAlertMaxDropRatio; threshold > 0 && expectedFrames > 0 {
+		if ratio := droppedFrames / expectedFrames; ratio > threshold {
+			alerts = append(alerts, &models.HealthAlert{
+				Kind:      models.HealthAlertDroppedFrames,
+				Severity:  severity(ratio > criticalDropRatio),
+				Message:   fmt.Sprintf("You are dropping %.1f%% of frames, check your network and encoder settings", ratio*100),
+				Value:     ratio,
+				Threshold: threshold,
+			})
+		}
+	}
+
+	return alerts
+}
+
+// send delivers an alert to the broadcaster and records it. A failed delivery never affects
+// the health report it came from.
+func (as *HealthAlertService) send(ctx context.Context, stream *models.Stream, alert *models.HealthAlert) {
+	slog.InfoContext(ctx, "📣 Stream health alert", "stream_id", stream.ID, "user_id", stream.UserID, "kind", alert.Kind, "severity", alert.Severity)
+
+	if err := as.deliver(ctx, stream, alert); err != nil {
+		slog.WarnContext(ctx, "⚠️ Could not deliver health alert to broadcaster", "stream_id", stream.ID, "kind", alert.Kind, "error", err)
+	}
+
+	event := map[string]interface{}{
+		"stream_id": alert.StreamID,
+		"user_id":   alert.UserID,
+		"kind":      alert.Kind,
+		"severity":  alert.Severity,
+		"message":   alert.Message,
+		"value":     alert.Value,
+		"threshold": alert.Threshold,
+	}
+	if err := as.streamService.PublishEvent("stream_health_alert", event); err != nil {
+		slog.WarnContext(ctx, "⚠️ Could not publish stream health alert event", "stream_id", stream.ID, "error", err)
+	}
+}
+
+// deliver posts the alert to the chat service. Over NATS the chat service consumes the
+// stream_health_alert event instead.
+func (as *HealthAlertService) deliver(ctx context.Context, stream *models.Stream, alert *models.HealthAlert) error {
+	if as.config.ChatServiceURL == "" || as.config.EventBus == EventBusNATS {
+		return nil
+	}
+
+	body, err := json.Marshal(map[string]interface{}{
+		"user_id":     strconv.FormatInt(stream.UserID, 10),
+		"chatroom_id": stream.ID, // a stream's chat room shares its ID
+		"kind":        alert.Kind,
+		"severity":    alert.Severity,
+		"message":     alert.Message,
+		"value":       alert.Value,
+		"threshold":   alert.Threshold,
+		"created_at":  alert.CreatedAt,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal health alert: %w", err)
+	}
+
+	url := fmt.Sprintf("%s/streams/%s/alerts", strings.TrimSuffix(as.config.ChatServiceURL, "/"), stream.ID)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to build health alert request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := as.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send health alert: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("chat service rejected health alert: status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+func severity(critical bool) models.HealthStatus {
+	if critical {
+		return models.HealthStatusCritical
+	}
+	return models.HealthStatusDegraded
+}
+
+// formatBitrate renders kbps the way broadcasters read it in their encoder, e.g. 1.5 Mbps
+func formatBitrate(kbps int) string {
+	if kbps < 1000 {
+		return fmt.Sprintf("%d kbps", kbps)
+	}
+	return strings.TrimSuffix(fmt.Sprintf("%.1f", float64(kbps)/1000), ".0") + " Mbps"
+}
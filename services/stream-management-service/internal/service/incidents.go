@@ -0,0 +1,188 @@
+// services/stream-management-service/internal/service/incidents.go
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/services/stream-management-service/internal/models"
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/services/stream-management-service/internal/repository"
+)
+
+// defaultIncidentRange is how far back incidents are listed when a request doesn't say
+const defaultIncidentRange = 30 * 24 * time.Hour
+
+type IncidentRequest struct {
+	Kind        models.IncidentKind `json:"kind" binding:"required"`
+	Title       string              `json:"title" binding:"required"`
+	Description string              `json:"description"`
+	Regions     []string            `json:"regions"`
+	StartsAt    time.Time           `json:"starts_at" binding:"required"`
+	EndsAt      *time.Time          `json:"ends_at"`
+}
+
+// CreateIncident handles POST /admin/incidents
+func (s *StreamService) CreateIncident(c *gin.Context) {
+	var req IncidentRequest
+	if !bindIncidentRequest(c, &req) {
+		return
+	}
+
+	now := time.Now().UTC()
+	incident := &models.Incident{
+		ID:        generateIncidentID(),
+		CreatedAt: now,
+	}
+	applyIncidentRequest(incident, &req, now)
+
+	if err := s.dynamoRepo.SaveIncident(incident); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not save incident"})
+		return
+	}
+
+	slog.InfoContext(c.Request.Context(), "📌 Incident annotated", "incident_id", incident.ID, "kind", incident.Kind, "starts_at", incident.StartsAt)
+	c.JSON(http.StatusCreated, incident)
+}
+
+// UpdateIncident handles PUT /admin/incidents/:id, e.g. to set when an ongoing incident ended
+func (s *StreamService) UpdateIncident(c *gin.Context) {
+	var req IncidentRequest
+	if !bindIncidentRequest(c, &req) {
+		return
+	}
+
+	incident, err := s.dynamoRepo.GetIncidentByID(c.Param("id"))
+	if err != nil {
+		respondIncidentError(c, err)
+		return
+	}
+	applyIncidentRequest(incident, &req, time.Now().UTC())
+
+	if err := s.dynamoRepo.SaveIncident(incident); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not save incident"})
+		return
+	}
+
+	slog.InfoContext(c.Request.Context(), "📌 Incident updated", "incident_id", incident.ID)
+	c.JSON(http.StatusOK, incident)
+}
+
+// DeleteIncident handles DELETE /admin/incidents/:id
+func (s *StreamService) DeleteIncident(c *gin.Context) {
+	if err := s.dynamoRepo.DeleteIncident(c.Param("id")); err != nil {
+		respondIncidentError(c, err)
+		return
+	}
+
+	slog.InfoContext(c.Request.Context(), "📌 Incident deleted", "incident_id", c.Param("id"))
+	c.Status(http.StatusNoContent)
+}
+
+// ListIncidents handles GET /admin/incidents?from=...&to=... with RFC 3339 times. to defaults
+// to now and from to 30 days before it.
+func (s *StreamService) ListIncidents(c *gin.Context) {
+	to := time.Now().UTC()
+	if value := c.Query("to"); value != "" {
+		parsed, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "to must be an RFC 3339 time"})
+			return
+		}
+		to = parsed
+	}
+	from := to.Add(-defaultIncidentRange)
+	if value := c.Query("from"); value != "" {
+		parsed, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "from must be an RFC 3339 time"})
+			return
+		}
+		from = parsed
+	}
+	if to.Before(from) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "from must be before to"})
+		return
+	}
+
+	incidents, err := s.dynamoRepo.GetIncidents(from, to)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not load incidents"})
+		return
+	}
+	if incidents == nil {
+		incidents = []*models.Incident{}
+	}
+
+	c.JSON(http.StatusOK, gin.H{"incidents": incidents, "count": len(incidents)})
+}
+
+// incidentsBetween returns the incidents overlapping from to to for analytics responses.
+// Analytics are still returned when incidents can't be read, just without annotations.
+func (s *StreamService) incidentsBetween(ctx context.Context, from, to time.Time) []*models.Incident {
+	incidents, err := s.dynamoRepo.GetIncidents(from, to)
+	if err != nil {
+		slog.WarnContext(ctx, "⚠️ Could not load incidents for analytics", "error", err)
+	}
+	if incidents == nil {
+		incidents = []*models.Incident{}
+	}
+	return incidents
+}
+
+func bindIncidentRequest(c *gin.Context, req *IncidentRequest) bool {
+	if err := c.ShouldBindJSON(req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return false
+	}
+	if !req.Kind.Valid() {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid incident kind"})
+		return false
+	}
+	if req.EndsAt != nil && req.EndsAt.Before(req.StartsAt) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ends_at must not be before starts_at"})
+		return false
+	}
+
+	regions, err := normalizeRegions(req.Regions)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return false
+	}
+	req.Regions = regions
+	return true
+}
+
+func applyIncidentRequest(incident *models.Incident, req *IncidentRequest, now time.Time) {
+	incident.Kind = req.Kind
+	incident.Title = req.Title
+	incident.Description = req.Description
+	incident.Regions = req.Regions
+	incident.StartsAt = req.StartsAt.UTC()
+	incident.EndsAt = nil
+	if req.EndsAt != nil {
+		endsAt := req.EndsAt.UTC()
+		incident.EndsAt = &endsAt
+	}
+	incident.UpdatedAt = now
+}
+
+func respondIncidentError(c *gin.Context, err error) {
+	if errors.Is(err, repository.ErrIncidentNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Incident not found"})
+		return
+	}
+	c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not load incident"})
+}
+
+func generateIncidentID() string {
+	bytes := make([]byte, 8)
+	rand.Read(bytes)
+	return "inc_" + hex.EncodeToString(bytes)
+}
@@ -0,0 +1,788 @@
+// services/stream-management-service/internal/service/ingest_handler.go
+// IngestHandler serves the media server callbacks of RTMP and SRT publishers
+
+package service
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/services/stream-management-service/internal/config"
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/services/stream-management-service/internal/logging"
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/services/stream-management-service/internal/models"
+	grpcClient "github.com/Saoudyahya/Live-Streaming-Platform-Architecture/services/stream-management-service/pkg/grpc"
+)
+
+type IngestHandler struct {
+	config        *config.Config
+	streamService *StreamService
+	vodService    *VODService
+	recordings    *RecordingService
+	fingerprints  *FingerprintService
+	packager      *VODPackager
+	ladders       *QualityLadderService
+	streamKeys    *StreamKeyService
+	ingestRouter  *IngestRouter
+	userClient    *grpcClient.UserServiceClient
+	playback      *PlaybackAuthorizer
+	viewerAuth    *ViewerAuth
+}
+
+type RTMPAuthRequest struct {
+	Name   string `json:"name" form:"name"`     // Stream key from media server
+	IP     string `json:"addr" form:"addr"`     // Client IP
+	App    string `json:"app" form:"app"`       // Application name
+	Swfurl string `json:"swfurl" form:"swfurl"` // SWF URL
+	Tcurl  string `json:"tcurl" form:"tcurl"`   // TC URL
+	Vhost  string `json:"vhost" form:"vhost"`   // Virtual host
+	// ClientID is the media server's ID of the publishing connection, used to drop it
+	ClientID string `json:"client_id" form:"client_id"`
+	IngestParams
+}
+
+type RTMPStreamRequest struct {
+	Name     string `json:"name" form:"name"`         // Stream key
+	IP       string `json:"addr" form:"addr"`         // Client IP
+	App      string `json:"app" form:"app"`           // Application name
+	Duration string `json:"duration" form:"duration"` // Duration in seconds (for ended streams)
+	File     string `json:"file" form:"file"`         // Recording file path
+	Size     string `json:"size" form:"size"`         // File size
+	// ClientID is the media server's ID of the publishing connection, retries of a callback
+	// carry the same one
+	ClientID string `json:"client_id" form:"client_id"`
+	IngestParams
+}
+
+// RTMPHealthRequest is a connection quality report sent periodically by the media server
+type RTMPHealthRequest struct {
+	Name             string  `json:"name" form:"name"`                           // Stream key
+	StreamID         string  `json:"stream_id" form:"stream_id"`                 // Optional, skips the session lookup
+	Bitrate          int     `json:"bitrate" form:"bitrate"`                     // kbps
+	FPS              float64 `json:"fps" form:"fps"`                             // Frames per second
+	DroppedFrames    int64   `json:"dropped_frames" form:"dropped_frames"`       // Since the previous report
+	KeyframeInterval float64 `json:"keyframe_interval" form:"keyframe_interval"` // Seconds between keyframes
+}
+
+func NewIngestHandler(cfg *config.Config, streamService *StreamService, vodService *VODService, recordings *RecordingService, fingerprints *FingerprintService, packager *VODPackager, ladders *QualityLadderService, streamKeys *StreamKeyService, ingestRouter *IngestRouter, userClient *grpcClient.UserServiceClient, playback *PlaybackAuthorizer, viewerAuth *ViewerAuth) *IngestHandler {
+	return &IngestHandler{
+		config:        cfg,
+		streamService: streamService,
+		vodService:    vodService,
+		recordings:    recordings,
+		fingerprints:  fingerprints,
+		packager:      packager,
+		ladders:       ladders,
+		streamKeys:    streamKeys,
+		ingestRouter:  ingestRouter,
+		userClient:    userClient,
+		playback:      playback,
+		viewerAuth:    viewerAuth,
+	}
+}
+
+func (h *IngestHandler) AuthenticateStream(c *gin.Context) {
+	ctx := c.Request.Context()
+	var req RTMPAuthRequest
+
+	// Try to bind JSON first, then form data
+	if err := c.ShouldBindJSON(&req); err != nil {
+		if err := c.ShouldBind(&req); err != nil {
+			slog.WarnContext(ctx, "❌ Error parsing auth request", "error", err)
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request format"})
+			return
+		}
+	}
+
+	protocol := req.protocol()
+	slog.InfoContext(ctx, "🔑 Ingest auth request", "protocol", protocol, "name", req.ingestName(req.Name), "client_ip", req.IP, "app", req.App)
+
+	// Extract stream key from name
+	streamKey := h.extractStreamKey(req.ingestName(req.Name))
+	ctx = logging.With(ctx, "stream_key", streamKey)
+	slog.DebugContext(ctx, "🔍 Extracted stream key")
+
+	// Viewers pulling over SRT go through the playback path, not here
+	if protocol == models.IngestProtocolSRT && req.srtMode(req.Name) != "publish" {
+		slog.WarnContext(ctx, "❌ SRT connection is not a publisher", "mode", req.srtMode(req.Name))
+		c.JSON(http.StatusForbidden, gin.H{
+			"error": "Only publishers are accepted",
+			"code":  "SRT_MODE_NOT_ALLOWED",
+		})
+		return
+	}
+
+	// Premieres are relayed by this service with a key it issued itself
+	if strings.HasPrefix(streamKey, premiereKeyPrefix) {
+		h.authorizePremiere(c, streamKey)
+		return
+	}
+
+	h.authorizePublisher(ctx, c, streamKey, protocol, req)
+}
+
+// authorizePublisher runs the stream key checks shared by every ingest protocol and stores the
+// publisher's session. It writes the response either way.
+func (h *IngestHandler) authorizePublisher(ctx context.Context, c *gin.Context, streamKey string, protocol models.IngestProtocol, req RTMPAuthRequest) {
+	ban, err := h.streamService.GetActiveStreamKeyBan(streamKey)
+	if err != nil {
+		slog.ErrorContext(ctx, "❌ Error checking stream key ban", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Internal server error",
+			"code":  "VALIDATION_FAILED",
+		})
+		return
+	}
+	if ban != nil {
+		slog.WarnContext(ctx, "🚫 Banned stream key", "reason", ban.Reason)
+		c.JSON(http.StatusForbidden, gin.H{
+			"error": "Stream key is banned",
+			"code":  "STREAM_KEY_BANNED",
+		})
+		return
+	}
+
+	// Validate stream key with app_name parameter
+	valid, userID, username, err := h.validateStreamKey(ctx, streamKey, req.IP, req.App)
+	if err != nil {
+		slog.ErrorContext(ctx, "❌ Error validating stream key", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Internal server error",
+			"code":  "VALIDATION_FAILED",
+		})
+		return
+	}
+
+	if !valid {
+		slog.WarnContext(ctx, "❌ Invalid stream key")
+		c.JSON(http.StatusForbidden, gin.H{
+			"error": "Invalid stream key",
+			"code":  "INVALID_STREAM_KEY",
+		})
+		return
+	}
+
+	ctx = logging.With(ctx, "user_id", userID)
+	slog.InfoContext(ctx, "✅ Stream authorized", "username", username)
+
+	// Store stream session info in Redis for quick access
+	session := &models.StreamSession{
+		UserID:         userID,
+		Username:       username,
+		StreamKey:      streamKey,
+		ClientIP:       req.IP,
+		ClientID:       req.ClientID,
+		AppName:        req.App,
+		StartedAt:      time.Now().Unix(),
+		Permissions:    h.streamService.PublisherPermissions(),
+		IngestProtocol: protocol,
+	}
+	response := gin.H{
+		"authorized":  true,
+		"user_id":     userID,
+		"username":    username,
+		"protocol":    protocol,
+		"permissions": h.streamService.PublisherPermissions(),
+	}
+
+	// WHIP callbacks identify the publisher by the session it gets back
+	if protocol == models.IngestProtocolWebRTC {
+		response["session_id"] = req.ClientID
+	}
+
+	// The media server applies the latency it gets back to the SRT socket
+	if protocol == models.IngestProtocolSRT {
+		latencyMs := h.srtLatency(req.SRTLatency).Milliseconds()
+		session.SRTLatencyMs = int(latencyMs)
+		response["latency_ms"] = latencyMs
+	}
+
+	// The media server transcodes the renditions of the publisher's tier
+	ladder := h.ladders.LadderForUser(ctx, userID)
+	response["quality_tier"] = ladder.Tier
+	response["quality_ladder"] = ladder.Renditions
+
+	// A broadcaster coming back within the grace period keeps their stream
+	h.streamService.CarryOverReconnectState(streamKey, session)
+
+	if err := h.streamService.StoreStreamSession(streamKey, session); err != nil {
+		slog.WarnContext(ctx, "⚠️ Could not store stream session", "error", err)
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// authorizePremiere accepts a premiere relay whose session was stored when it started
+func (h *IngestHandler) authorizePremiere(c *gin.Context, streamKey string) {
+	session, err := h.streamService.GetStreamSession(streamKey)
+	if err != nil || !session.IsPremiere() {
+		slog.WarnContext(c.Request.Context(), "❌ Unknown premiere stream key")
+		c.JSON(http.StatusForbidden, gin.H{
+			"error": "Invalid stream key",
+			"code":  "INVALID_STREAM_KEY",
+		})
+		return
+	}
+
+	slog.InfoContext(c.Request.Context(), "✅ Premiere relay authorized", "vod_id", session.PremiereVODID)
+	c.JSON(http.StatusOK, gin.H{
+		"authorized": true,
+		"user_id":    session.UserID,
+		"premiere":   true,
+	})
+}
+
+func (h *IngestHandler) validateStreamKey(ctx context.Context, streamKey, ipAddress, appName string) (bool, int64, string, error) {
+	slog.DebugContext(ctx, "🔑 Validating stream key", "client_ip", ipAddress, "app", appName)
+
+	// Keys this service generated are checked locally
+	if h.streamKeys.Handles(streamKey) {
+		userID, valid, err := h.streamKeys.Validate(streamKey)
+		return valid, userID, "", err
+	}
+
+	// Try gRPC validation first if client is available
+	if h.userClient != nil {
+		slog.DebugContext(ctx, "🔌 Attempting gRPC validation")
+
+		// Create the request with all parameters including app_name
+		request := map[string]interface{}{
+			"stream_key": streamKey,
+			"ip_address": ipAddress,
+			"app_name":   appName,
+		}
+
+		// Call the gRPC validation
+		valid, userID, username, err := h.userClient.ValidateStreamKey(ctx, request)
+		if err == nil {
+			slog.DebugContext(ctx, "✅ gRPC validation successful")
+			return valid, userID, username, nil
+		}
+
+		slog.WarnContext(ctx, "⚠️ gRPC validation failed, falling back to HTTP", "error", err)
+	} else {
+		slog.WarnContext(ctx, "⚠️ No gRPC client available, using HTTP fallback")
+	}
+
+	// Fallback to HTTP validation
+	return h.validateStreamKeyHTTP(ctx, streamKey, ipAddress)
+}
+
+// HTTP fallback method to validate stream key with User Service REST API
+func (h *IngestHandler) validateStreamKeyHTTP(ctx context.Context, streamKey, ipAddress string) (bool, int64, string, error) {
+	slog.DebugContext(ctx, "🌐 HTTP validation for stream key")
+
+	// This will be handled by the gRPC client's HTTP fallback
+	// We create a request map and let the client handle it
+	request := map[string]interface{}{
+		"stream_key": streamKey,
+		"ip_address": ipAddress,
+	}
+
+	// Use the gRPC client's HTTP fallback if available
+	if h.userClient != nil {
+		return h.userClient.ValidateStreamKey(ctx, request)
+	}
+
+	// Final fallback for development
+
+	slog.WarnContext(ctx, "❌ Development validation failed for stream key")
+	return false, 0, "", nil
+}
+
+func (h *IngestHandler) StreamStarted(c *gin.Context) {
+	ctx := c.Request.Context()
+	var req RTMPStreamRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		if err := c.ShouldBind(&req); err != nil {
+			slog.WarnContext(ctx, "❌ Error parsing stream started request", "error", err)
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request format"})
+			return
+		}
+	}
+
+	streamKey := h.extractStreamKey(req.ingestName(req.Name))
+	ctx = logging.With(ctx, "stream_key", streamKey)
+	slog.InfoContext(ctx, "🔴 Stream STARTED", "name", req.ingestName(req.Name), "client_ip", req.IP)
+
+	callback, ok := h.beginCallback(c, streamKey, req.ClientID, callbackStarted)
+	if !ok {
+		return
+	}
+	defer h.finishCallback(c, callback)
+
+	// Get session info from Redis
+	session, err := h.streamService.GetStreamSession(streamKey)
+	if err != nil {
+		slog.ErrorContext(ctx, "❌ Could not get stream session", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Session not found"})
+		return
+	}
+
+	userID := session.UserID
+	if userID == 0 {
+		slog.ErrorContext(ctx, "❌ Invalid user_id in session")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Invalid session"})
+		return
+	}
+	ctx = logging.With(ctx, "user_id", userID)
+
+	if session.Reconnecting() {
+		streamID, err := h.streamService.ResumeStream(ctx, streamKey, session)
+		if err == nil {
+			h.respondCallback(c, callback, http.StatusOK, gin.H{
+				"message":   "Stream resumed",
+				"stream_id": streamID,
+				"status":    "live",
+			})
+			return
+		}
+
+		slog.WarnContext(ctx, "⚠️ Could not resume stream, starting a new one", "error", err)
+		session.ResetStream()
+	}
+
+	// The auth callback knows the protocol even when the media server leaves it out here
+	protocol := session.Protocol()
+	if req.Protocol != "" || req.SRTStreamID != "" {
+		protocol = req.protocol()
+	}
+
+	// Create stream record
+	stream := &models.Stream{
+		UserID:    userID,
+		StreamKey: streamKey,
+		Title:     defaultStreamTitle(time.Now()),
+		Status:    models.StreamStatusLive,
+		Metadata: map[string]string{
+			"client_ip":       req.IP,
+			"app_name":        req.App,
+			"session_started": time.Now().Format(time.RFC3339),
+			"rtmp_app":        req.App,
+			"ingest_protocol": string(protocol),
+		},
+		IngestProtocol: protocol,
+		IngestRegion:   h.ingestRegion(c, req.IP),
+		PlaybackID:     session.PlaybackID,
+		// Set when the media server asked for restream targets before the stream existed
+		Restreams: session.Restreams,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+
+	now := time.Now()
+	stream.StartedAt = &now
+	if protocol == models.IngestProtocolSRT {
+		stream.SRTLatencyMs = session.SRTLatencyMs
+	}
+
+	vodID := session.PremiereVODID
+	if vodID != "" {
+		if session.Title != "" {
+			stream.Title = session.Title
+		}
+		stream.Metadata["premiere_vod_id"] = vodID
+	}
+
+	// Premiere titles were filtered when scheduled, but the rules may have changed since
+	titleFilter := h.streamService.FilterTitle(ctx, stream.Title)
+	if titleFilter.Rejected {
+		stream.Title = defaultStreamTitle(now)
+	} else {
+		stream.Title = titleFilter.Text
+	}
+
+	h.streamService.ClassifyStream(stream)
+	h.streamService.InheritChannelSettings(stream)
+
+	// The stream started event is written to the outbox with the stream
+	started := StreamEvent{
+		Type: "stream_started",
+		Data: map[string]interface{}{
+			"user_id":   userID,
+			"is_mature": stream.IsMature,
+			"language":  stream.Language,
+			"metadata": map[string]interface{}{
+				"stream_key":      streamKey,
+				"client_ip":       req.IP,
+				"app_name":        req.App,
+				"ingest_protocol": string(protocol),
+				"ingest_region":   stream.IngestRegion,
+			},
+		},
+	}
+	if vodID != "" {
+		started.Data["metadata"].(map[string]interface{})["premiere_vod_id"] = vodID
+	}
+
+	streamID, err := h.streamService.CreateStream(ctx, stream, started)
+	if err != nil {
+		slog.ErrorContext(ctx, "❌ Error creating stream", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not create stream"})
+		return
+	}
+
+	ctx = logging.With(ctx, "stream_id", streamID)
+	slog.InfoContext(ctx, "✅ Stream created")
+	h.streamService.QueueContentReview(ctx, stream, "title", titleFilter)
+
+	// Update session with stream ID
+	session.StreamID = streamID
+	session.StreamStartedAt = time.Now().Unix()
+	session.SegmentStartedAt = session.StreamStartedAt
+	if err := h.streamService.StoreStreamSession(streamKey, session); err != nil {
+		slog.WarnContext(ctx, "⚠️ Could not store stream session", "error", err)
+	}
+
+	if vodID != "" {
+		if err := h.vodService.AttachPremiereStream(vodID, streamID); err != nil {
+			slog.WarnContext(ctx, "⚠️ Could not attach stream to premiere", "vod_id", vodID, "error", err)
+		}
+	}
+
+	h.respondCallback(c, callback, http.StatusOK, gin.H{
+		"message":   "Stream started",
+		"stream_id": streamID,
+		"status":    "live",
+	})
+}
+
+func (h *IngestHandler) StreamEnded(c *gin.Context) {
+	ctx := c.Request.Context()
+	var req RTMPStreamRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		if err := c.ShouldBind(&req); err != nil {
+			slog.WarnContext(ctx, "❌ Error parsing stream ended request", "error", err)
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request format"})
+			return
+		}
+	}
+
+	streamKey := h.extractStreamKey(req.ingestName(req.Name))
+	ctx = logging.With(ctx, "stream_key", streamKey)
+	slog.InfoContext(ctx, "🔴 Stream ENDED", "name", req.Name, "duration", req.Duration)
+
+	callback, ok := h.beginCallback(c, streamKey, req.ClientID, callbackEnded)
+	if !ok {
+		return
+	}
+	defer h.finishCallback(c, callback)
+
+	// Get session info to find stream ID
+	session, err := h.streamService.GetStreamSession(streamKey)
+	if err != nil {
+		slog.ErrorContext(ctx, "❌ Could not get stream session", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Session not found"})
+		return
+	}
+
+	streamID := session.StreamID
+	if !session.Started() {
+		slog.ErrorContext(ctx, "❌ No stream ID in session")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Stream ID not found in session"})
+		return
+	}
+	ctx = logging.With(ctx, "stream_id", streamID)
+
+	// Parse duration
+	durationSec := int64(0)
+	if req.Duration != "" {
+		if d, err := strconv.ParseInt(req.Duration, 10, 64); err == nil {
+			durationSec = d
+		}
+	}
+
+	// Give the broadcaster a chance to reconnect before ending the stream, a premiere relay
+	// that stopped is over
+	gracePeriod := h.config.Tunables().ReconnectGracePeriod
+	if gracePeriod > 0 && !session.IsPremiere() {
+		err := h.streamService.MarkStreamReconnecting(ctx, streamKey, session, durationSec)
+		if err == nil {
+			h.respondCallback(c, callback, http.StatusOK, gin.H{
+				"message":      "Stream disconnected, waiting for reconnect",
+				"stream_id":    streamID,
+				"grace_period": int64(gracePeriod.Seconds()),
+				"status":       "reconnecting",
+			})
+			return
+		}
+		if !errors.Is(err, errStreamEnded) {
+			slog.WarnContext(ctx, "⚠️ Could not keep stream open for reconnect, ending it", "error", err)
+		}
+	}
+
+	// End stream
+	err = h.streamService.EndStream(ctx, streamKey, req.Duration)
+	if err != nil {
+		slog.ErrorContext(ctx, "❌ Error ending stream", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not end stream"})
+		return
+	}
+
+	// Clean up session
+	if err := h.streamService.CleanupStreamSession(streamKey); err != nil {
+		slog.WarnContext(ctx, "⚠️ Could not cleanup stream session", "error", err)
+	}
+
+	slog.InfoContext(ctx, "✅ Stream ended successfully")
+
+	h.respondCallback(c, callback, http.StatusOK, gin.H{
+		"message":   "Stream ended",
+		"stream_id": streamID,
+		"duration":  durationSec,
+		"status":    "ended",
+	})
+}
+
+func (h *IngestHandler) RecordingCompleted(c *gin.Context) {
+	ctx := c.Request.Context()
+	var req RTMPStreamRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		if err := c.ShouldBind(&req); err != nil {
+			slog.WarnContext(ctx, "❌ Error parsing recording completed request", "error", err)
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request format"})
+			return
+		}
+	}
+
+	streamKey := h.extractStreamKey(req.ingestName(req.Name))
+	ctx = logging.With(ctx, "stream_key", streamKey)
+	slog.InfoContext(ctx, "📹 Recording COMPLETED", "name", req.Name, "file", req.File)
+
+	// A connection can record several files, each is reported once
+	callback, ok := h.beginCallback(c, streamKey, req.ClientID, callbackRecorded+":"+req.File)
+	if !ok {
+		return
+	}
+	defer h.finishCallback(c, callback)
+
+	// Update stream with recording info
+	stream, err := h.streamService.UpdateStreamRecording(ctx, streamKey, req.File)
+	if err != nil {
+		slog.ErrorContext(ctx, "❌ Error updating stream recording", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not update recording info"})
+		return
+	}
+
+	ctx = logging.With(ctx, "stream_id", stream.ID, "user_id", stream.UserID)
+	slog.InfoContext(ctx, "✅ Recording updated successfully")
+
+	// Parse file size if provided
+	fileSize := int64(0)
+	if req.Size != "" {
+		if s, err := strconv.ParseInt(req.Size, 10, 64); err == nil {
+			fileSize = s
+		}
+	}
+
+	// Parse duration if provided
+	durationSec := int64(0)
+	if req.Duration != "" {
+		if d, err := strconv.ParseInt(req.Duration, 10, 64); err == nil {
+			durationSec = d
+		}
+	}
+
+	// Add the recording to the VOD catalog, a premiere already is a VOD
+	vodID := stream.Metadata["premiere_vod_id"]
+	if vodID == "" {
+		vod, err := h.vodService.CreateVODFromRecording(stream, fileSize, durationSec)
+		if err != nil {
+			slog.WarnContext(ctx, "⚠️ Could not create VOD", "error", err)
+		} else {
+			vodID = vod.ID
+			if err := h.fingerprints.Enqueue(vod.ID); err != nil {
+				slog.WarnContext(ctx, "⚠️ Could not queue VOD for fingerprinting", "vod_id", vod.ID, "error", err)
+			}
+			if err := h.packager.Enqueue(vod.ID); err != nil {
+				slog.WarnContext(ctx, "⚠️ Could not queue VOD for packaging", "vod_id", vod.ID, "error", err)
+			}
+		}
+	}
+
+	// Uploaded in the background, the VOD points at the upload once it is in S3
+	if err := h.recordings.EnqueueUpload(ctx, stream); err != nil {
+		slog.WarnContext(ctx, "⚠️ Could not queue recording for upload", "error", err)
+	}
+
+	// Publish recording completed event
+	event := map[string]interface{}{
+		"stream_id":      stream.ID,
+		"stream_key":     streamKey,
+		"recording_path": req.File,
+		"recording_url":  stream.RecordingURL,
+		"vod_id":         vodID,
+		"file_size":      fileSize,
+		"duration":       durationSec,
+	}
+
+	if err := h.streamService.PublishEvent("recording_completed", event); err != nil {
+		slog.WarnContext(ctx, "⚠️ Could not publish recording completed event", "error", err)
+	}
+
+	h.respondCallback(c, callback, http.StatusOK, gin.H{
+		"message":       "Recording completed",
+		"recording_url": stream.RecordingURL,
+		"vod_id":        vodID,
+		"file_size":     fileSize,
+		"status":        "completed",
+	})
+}
+
+// StreamHealthReport handles POST /rtmp/health with stream quality metrics from the media server
+func (h *IngestHandler) StreamHealthReport(c *gin.Context) {
+	ctx := c.Request.Context()
+	var req RTMPHealthRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		if err := c.ShouldBind(&req); err != nil {
+			slog.WarnContext(ctx, "❌ Error parsing health report", "error", err)
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request format"})
+			return
+		}
+	}
+
+	streamID, err := h.streamService.ResolveLiveStreamID(req.StreamID, h.extractStreamKey(req.Name))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	health, err := h.streamService.RecordStreamHealth(ctx, streamID, models.HealthSample{
+		BitrateKbps:      req.Bitrate,
+		FPS:              req.FPS,
+		DroppedFrames:    req.DroppedFrames,
+		KeyframeInterval: req.KeyframeInterval,
+		Timestamp:        time.Now(),
+	})
+	if err != nil {
+		slog.ErrorContext(ctx, "❌ Error recording stream health", "stream_id", streamID, "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not record stream health"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"stream_id": streamID,
+		"health":    health,
+	})
+}
+
+// RTMPLatencyMarkerRequest asks for a latency marker to embed in a stream at ingest
+type RTMPLatencyMarkerRequest struct {
+	Name     string `json:"name" form:"name"`           // Stream key
+	StreamID string `json:"stream_id" form:"stream_id"` // Optional, skips the session lookup
+	MarkerID string `json:"marker_id" form:"marker_id"` // Optional, generated when empty
+	IngestAt int64  `json:"ingest_at" form:"ingest_at"` // Unix ms the marked frame was received, defaults to now
+}
+
+// LatencyMarker handles POST /rtmp/latency-marker. The media server embeds the returned payload
+// as timed metadata and reference players report the delay at which they see it.
+func (h *IngestHandler) LatencyMarker(c *gin.Context) {
+	ctx := c.Request.Context()
+	var req RTMPLatencyMarkerRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		if err := c.ShouldBind(&req); err != nil {
+			slog.WarnContext(ctx, "❌ Error parsing latency marker request", "error", err)
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request format"})
+			return
+		}
+	}
+
+	streamID, err := h.streamService.ResolveLiveStreamID(req.StreamID, h.extractStreamKey(req.Name))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	var ingestAt time.Time
+	if req.IngestAt > 0 {
+		ingestAt = time.UnixMilli(req.IngestAt)
+	}
+
+	marker, err := h.streamService.CreateLatencyMarker(streamID, req.MarkerID, ingestAt)
+	if err != nil {
+		slog.ErrorContext(ctx, "❌ Error creating latency marker", "stream_id", streamID, "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not create latency marker"})
+		return
+	}
+
+	c.JSON(http.StatusOK, marker)
+}
+
+func (h *IngestHandler) GetStreamInfo(c *gin.Context) {
+	streamKey := c.Param("stream_key")
+	if streamKey == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Stream key required"})
+		return
+	}
+
+	// Get session info
+	session, err := h.streamService.GetStreamSession(streamKey)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Stream session not found"})
+		return
+	}
+
+	// Try to get stream details if stream ID is available
+	if session.Started() {
+		c.JSON(http.StatusOK, gin.H{
+			"stream_id": session.StreamID,
+			"session":   session,
+			"status":    "active",
+		})
+		return
+	}
+
+	// Fallback to session data only
+	c.JSON(http.StatusOK, gin.H{
+		"session": session,
+		"status":  "session_only",
+	})
+}
+
+func (h *IngestHandler) HealthCheck(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"status":    "healthy",
+		"service":   "stream-management",
+		"timestamp": time.Now().Unix(),
+		"version":   "1.0.0",
+		"rtmp":      "ready",
+		"srt":       "ready",
+	})
+}
+
+// ingestRegion returns the region of the media server sending a callback, or the region the
+// publisher would have been routed to when the server isn't mapped
+func (h *IngestHandler) ingestRegion(c *gin.Context, publisherIP string) string {
+	if region := h.ingestRouter.ServerRegion(c.GetHeader(MediaServerHeader)); region != "" {
+		return region
+	}
+	region, _ := h.ingestRouter.Route(publisherIP, "")
+	return region
+}
+
+// extractStreamKey takes the key out of an RTMP name or an SRT streamid, both end in it
+func (h *IngestHandler) extractStreamKey(name string) string {
+	streamKey := strings.TrimSpace(name)
+	if strings.HasPrefix(streamKey, srtAccessControlPrefix) {
+		streamKey = parseSRTStreamID(streamKey).Resource
+	}
+	streamKey = strings.TrimPrefix(streamKey, "/")
+
+	if strings.Contains(streamKey, "/") {
+		parts := strings.Split(streamKey, "/")
+		streamKey = parts[len(parts)-1]
+	}
+
+	return streamKey
+}
@@ -0,0 +1,142 @@
+// services/stream-management-service/internal/service/ingest_routing.go
+package service
+
+import (
+	"log/slog"
+	"net"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/services/stream-management-service/internal/config"
+)
+
+// CountryHeader is set by CloudFront to the viewer's ISO country code
+const CountryHeader = "CloudFront-Viewer-Country"
+
+// IngestRouter sends publishers to the media servers of the region nearest to them. The region
+// comes from the most specific configured network containing the publisher's IP, then from
+// their country, then the default region.
+type IngestRouter struct {
+	config   *config.Config
+	networks []regionNetwork // most specific first
+}
+
+type regionNetwork struct {
+	network *net.IPNet
+	region  string
+}
+
+// IngestEndpoint is where a publisher should send their stream
+type IngestEndpoint struct {
+	Region  string `json:"region"`
+	RTMPURL string `json:"rtmp_url"`
+	SRTURL  string `json:"srt_url,omitempty"`
+	WHIPURL string `json:"whip_url,omitempty"`
+}
+
+func NewIngestRouter(cfg *config.Config) *IngestRouter {
+	router := &IngestRouter{config: cfg}
+
+	for cidr, region := range cfg.IngestRegionCIDRs {
+		_, network, err := net.ParseCIDR(strings.TrimSpace(cidr))
+		if err != nil {
+			slog.Warn("⚠️ Ignoring invalid ingest region network", "cidr", cidr, "error", err)
+			continue
+		}
+		if _, ok := cfg.IngestRegions[region]; !ok {
+			slog.Warn("⚠️ Ignoring ingest region network of an unknown region", "cidr", cidr, "region", region)
+			continue
+		}
+		router.networks = append(router.networks, regionNetwork{network: network, region: region})
+	}
+
+	sort.Slice(router.networks, func(i, j int) bool {
+		iOnes, _ := router.networks[i].network.Mask.Size()
+		jOnes, _ := router.networks[j].network.Mask.Size()
+		return iOnes > jOnes
+	})
+
+	return router
+}
+
+// Route returns the region for a publisher and how it was picked: ip, country or default
+func (r *IngestRouter) Route(ip, country string) (string, string) {
+	if parsed := net.ParseIP(strings.TrimSpace(ip)); parsed != nil {
+		for _, network := range r.networks {
+			if network.network.Contains(parsed) {
+				return network.region, "ip"
+			}
+		}
+	}
+
+	if region, ok := r.config.IngestCountryRegions[strings.ToUpper(strings.TrimSpace(country))]; ok {
+		if _, known := r.config.IngestRegions[region]; known {
+			return region, "country"
+		}
+	}
+
+	return r.config.IngestDefaultRegion, "default"
+}
+
+// ServerRegion returns the region a media server runs in, empty if it isn't mapped
+func (r *IngestRouter) ServerRegion(serverID string) string {
+	return r.config.MediaServerRegions[serverID]
+}
+
+// Endpoint returns the URLs of a region
+func (r *IngestRouter) Endpoint(region string) (IngestEndpoint, bool) {
+	urls, ok := r.config.IngestRegions[region]
+	if !ok {
+		return IngestEndpoint{}, false
+	}
+	return IngestEndpoint{
+		Region:  region,
+		RTMPURL: urls.RTMPURL,
+		SRTURL:  urls.SRTURL,
+		WHIPURL: urls.WHIPURL,
+	}, true
+}
+
+// GetIngestEndpoints handles GET /api/v1/ingest/endpoints?ip=..., the caller's IP is used
+// when none is given. The other regions are listed as fallbacks.
+func (r *IngestRouter) GetIngestEndpoints(c *gin.Context) {
+	ip := c.Query("ip")
+	if ip == "" {
+		ip = c.ClientIP()
+	} else if net.ParseIP(ip) == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ip"})
+		return
+	}
+
+	country := c.Query("country")
+	if country == "" {
+		country = c.GetHeader(CountryHeader)
+	}
+
+	region, matchedBy := r.Route(ip, country)
+	endpoint, ok := r.Endpoint(region)
+	if !ok {
+		slog.ErrorContext(c.Request.Context(), "❌ Ingest region has no endpoints", "region", region)
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "No ingest endpoint available"})
+		return
+	}
+
+	alternatives := make([]IngestEndpoint, 0, len(r.config.IngestRegions)-1)
+	for name := range r.config.IngestRegions {
+		if name == region {
+			continue
+		}
+		alternative, _ := r.Endpoint(name)
+		alternatives = append(alternatives, alternative)
+	}
+	sort.Slice(alternatives, func(i, j int) bool { return alternatives[i].Region < alternatives[j].Region })
+
+	c.JSON(http.StatusOK, gin.H{
+		"endpoint":     endpoint,
+		"matched_by":   matchedBy,
+		"alternatives": alternatives,
+	})
+}
@@ -0,0 +1,126 @@
+// services/stream-management-service/internal/service/jobs.go
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/services/stream-management-service/internal/config"
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/services/stream-management-service/pkg/scheduler"
+)
+
+// Jobs of the scheduler, also the names of their task locks
+const (
+	JobCleanupExpiredStreams = "cleanup_expired_streams"
+	JobReconcileStreams      = "reconcile_streams"
+	JobPurgeSoftDeleted      = "purge_soft_deleted"
+)
+
+// JobService runs the periodic background jobs on one replica per run and lets admins list
+// and trigger them
+type JobService struct {
+	config    *config.Config
+	scheduler *scheduler.Scheduler
+}
+
+func NewJobService(cfg *config.Config, streamService *StreamService, softDeleteService *SoftDeleteService) (*JobService, error) {
+	sched := scheduler.New(streamService.taskLocks)
+
+	jobs := []scheduler.Job{
+		{Name: JobCleanupExpiredStreams, Run: streamService.CleanupExpiredStreams},
+		{Name: JobReconcileStreams, Run: streamService.ReconcileStreams},
+		{Name: JobPurgeSoftDeleted, Run: softDeleteService.PurgeDeleted},
+	}
+	tunables := cfg.Tunables()
+	schedules := jobSchedules(tunables)
+	for _, job := range jobs {
+		job.Schedule = schedules[job.Name]
+		job.Jitter = tunables.JobJitter
+		job.Singleton = true
+		if err := sched.Add(job); err != nil {
+			return nil, err
+		}
+	}
+
+	return &JobService{
+		config:    cfg,
+		scheduler: sched,
+	}, nil
+}
+
+// Reschedule moves the jobs to the schedules of reloaded tunables
+func (js *JobService) Reschedule(tunables *config.Tunables) {
+	for name, schedule := range jobSchedules(tunables) {
+		if err := js.scheduler.Reschedule(name, schedule, tunables.JobJitter); err != nil {
+			slog.Warn("⚠️ Could not reschedule job", "job", name, "error", err)
+		}
+	}
+}
+
+// jobSchedules returns the schedule of each job. Reconciliation ends stale streams too, the
+// cleanup is only scheduled without it.
+func jobSchedules(tunables *config.Tunables) map[string]string {
+	schedules := map[string]string{
+		JobCleanupExpiredStreams: tunables.CleanupSchedule,
+		JobReconcileStreams:      "",
+		JobPurgeSoftDeleted:      tunables.SoftDeletePurgeSchedule,
+	}
+	if tunables.ReconcileInterval > 0 {
+		schedules[JobCleanupExpiredStreams] = ""
+		schedules[JobReconcileStreams] = fmt.Sprintf("@every %s", tunables.ReconcileInterval)
+	}
+	return schedules
+}
+
+// Start runs the jobs on their schedules until ctx is done, and reconciles streams with the
+// media server right away when StartupReconcile is set
+func (js *JobService) Start(ctx context.Context) {
+	js.scheduler.Start(ctx)
+
+	if js.config.StartupReconcile {
+		if err := js.scheduler.Trigger(JobReconcileStreams); err != nil {
+			slog.WarnContext(ctx, "⚠️ Could not reconcile streams with the media server", "error", err)
+		}
+	}
+}
+
+// Trigger runs a job right away in the background
+func (js *JobService) Trigger(name string) error {
+	return js.scheduler.Trigger(name)
+}
+
+// ListJobs handles GET /admin/jobs
+func (js *JobService) ListJobs(c *gin.Context) {
+	jobs := js.scheduler.Jobs()
+	c.JSON(http.StatusOK, gin.H{
+		"jobs":  jobs,
+		"count": len(jobs),
+	})
+}
+
+// RunJob handles POST /admin/jobs/:name/run. The job runs in the background, its outcome
+// shows up in the job list.
+func (js *JobService) RunJob(c *gin.Context) {
+	name := c.Param("name")
+
+	err := js.scheduler.Trigger(name)
+	switch {
+	case errors.Is(err, scheduler.ErrUnknownJob):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Job not found"})
+		return
+	case errors.Is(err, scheduler.ErrJobRunning):
+		c.JSON(http.StatusConflict, gin.H{"error": "Job is already running on this replica"})
+		return
+	case err != nil:
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": err.Error()})
+		return
+	}
+
+	slog.InfoContext(c.Request.Context(), "▶️ Job triggered", "job", name)
+	c.JSON(http.StatusAccepted, gin.H{"message": "Job started", "job": name})
+}
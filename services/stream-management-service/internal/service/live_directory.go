@@ -0,0 +1,145 @@
+// services/stream-management-service/internal/service/live_directory.go
+package service
+
+import (
+	"encoding/json"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/services/stream-management-service/internal/models"
+)
+
+// liveDirectoryEntry is the live directory as cached in Redis
+type liveDirectoryEntry struct {
+	Streams     []*models.Stream `json:"streams"`
+	RefreshedAt time.Time        `json:"refreshed_at"`
+}
+
+// liveDirectoryRefresher runs one reload of the cached live directory at a time in this
+// replica. Streams that change while one runs are kept for the next, which is started as
+// soon as it finishes.
+type liveDirectoryRefresher struct {
+	mu      sync.Mutex
+	running bool
+	changed map[string]*models.Stream
+}
+
+// GetActiveStreamsInternal returns every live stream from the directory cached in Redis. A
+// stale directory is still returned while it is reloaded in the background; DynamoDB is only
+// read on the request path when nothing is cached.
+func (s *StreamService) GetActiveStreamsInternal() ([]*models.Stream, error) {
+	if s.config.DirectoryCacheFreshFor <= 0 {
+		return s.dynamoRepo.GetStreamsByStatus(models.StreamStatusLive, models.DeletedExclude)
+	}
+
+	data, err := s.redisRepo.GetLiveDirectory()
+	if err == nil {
+		var entry liveDirectoryEntry
+		if json.Unmarshal([]byte(data), &entry) == nil {
+			if time.Since(entry.RefreshedAt) > s.config.DirectoryCacheFreshFor {
+				s.revalidateLiveDirectory()
+			}
+			return entry.Streams, nil
+		}
+	}
+
+	return s.loadLiveDirectory(nil)
+}
+
+// revalidateLiveDirectory reloads a stale directory unless another replica already is
+func (s *StreamService) revalidateLiveDirectory() {
+	claimed, err := s.redisRepo.ClaimLiveDirectoryRefresh(s.config.DirectoryCacheFreshFor)
+	if err != nil {
+		slog.Warn("⚠️ Could not claim live directory refresh", "error", err)
+		return
+	}
+	if claimed {
+		s.refreshLiveDirectory(nil)
+	}
+}
+
+// invalidateLiveDirectory reloads the cached directory after a stream started, ended or was
+// updated. The stream as just written is applied on top of what DynamoDB returns, its status
+// index may not have caught up yet.
+func (s *StreamService) invalidateLiveDirectory(stream *models.Stream) {
+	if s.config.DirectoryCacheFreshFor <= 0 {
+		return
+	}
+	copied := *stream
+	s.refreshLiveDirectory(&copied)
+}
+
+func (s *StreamService) refreshLiveDirectory(changed *models.Stream) {
+	r := &s.liveDirectory
+	r.mu.Lock()
+	if changed != nil {
+		if r.changed == nil {
+			r.changed = make(map[string]*models.Stream)
+		}
+		r.changed[changed.ID] = changed
+	}
+	if r.running {
+		r.mu.Unlock()
+		return
+	}
+	r.running = true
+	r.mu.Unlock()
+
+	go func() {
+		for {
+			r.mu.Lock()
+			changed := r.changed
+			r.changed = nil
+			r.mu.Unlock()
+
+			if _, err := s.loadLiveDirectory(changed); err != nil {
+				slog.Warn("⚠️ Could not refresh live directory", "error", err)
+			}
+
+			r.mu.Lock()
+			if len(r.changed) == 0 {
+				r.running = false
+				r.mu.Unlock()
+				return
+			}
+			r.mu.Unlock()
+		}
+	}()
+}
+
+// loadLiveDirectory reads the live streams from DynamoDB, applies the streams changed since
+// the read was requested and caches the result
+func (s *StreamService) loadLiveDirectory(changed map[string]*models.Stream) ([]*models.Stream, error) {
+	refreshedAt := time.Now()
+	streams, err := s.dynamoRepo.GetStreamsByStatus(models.StreamStatusLive, models.DeletedExclude)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(changed) > 0 {
+		live := make([]*models.Stream, 0, len(streams)+len(changed))
+		for _, stream := range streams {
+			if _, ok := changed[stream.ID]; !ok {
+				live = append(live, stream)
+			}
+		}
+		for _, stream := range changed {
+			if stream.Status == models.StreamStatusLive && !stream.Deleted() {
+				live = append(live, stream)
+			}
+		}
+		streams = live
+	}
+
+	entry, err := json.Marshal(liveDirectoryEntry{Streams: streams, RefreshedAt: refreshedAt})
+	if err != nil {
+		return nil, err
+	}
+	expiration := s.config.DirectoryCacheFreshFor + s.config.DirectoryCacheStaleFor
+	if err := s.redisRepo.SetLiveDirectory(string(entry), expiration); err != nil {
+		slog.Warn("⚠️ Could not cache live directory", "error", err)
+	}
+
+	return streams, nil
+}
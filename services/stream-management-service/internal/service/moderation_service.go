@@ -0,0 +1,193 @@
+// services/stream-management-service/internal/service/moderation_service.go
+package service
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/services/stream-management-service/internal/config"
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/services/stream-management-service/internal/models"
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/services/stream-management-service/internal/repository"
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/services/stream-management-service/pkg/srs"
+)
+
+// ModerationService lets admins stop abusive broadcasts
+type ModerationService struct {
+	config        *config.Config
+	dynamoRepo    *repository.DynamoDBRepository
+	streamService *StreamService
+	srsClient     *srs.Client
+}
+
+type TerminateStreamRequest struct {
+	Reason string `json:"reason" binding:"required"`
+}
+
+type BanStreamKeyRequest struct {
+	Reason   string `json:"reason" binding:"required"`
+	Duration string `json:"duration"` // e.g. "72h", permanent when empty
+}
+
+func NewModerationService(cfg *config.Config, dynamoRepo *repository.DynamoDBRepository, streamService *StreamService) *ModerationService {
+	return &ModerationService{
+		config:        cfg,
+		dynamoRepo:    dynamoRepo,
+		streamService: streamService,
+		srsClient:     srs.NewClient(cfg.SRSAPIURL, cfg.Caller(config.DependencySRS).Transport(nil)),
+	}
+}
+
+// TerminateStream handles POST /admin/streams/:id/terminate
+func (ms *ModerationService) TerminateStream(c *gin.Context) {
+	var req TerminateStreamRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	stream, err := ms.streamService.GetStreamByIDInternal(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Stream not found"})
+		return
+	}
+	if stream.Status == models.StreamStatusEnded {
+		c.JSON(http.StatusConflict, gin.H{"error": "Stream has already ended"})
+		return
+	}
+
+	dropped, err := ms.terminate(c.Request.Context(), stream, req.Reason)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not terminate stream"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"stream":            stream,
+		"publisher_dropped": dropped,
+	})
+}
+
+// BanStreamKey handles POST /admin/stream-keys/:key/ban. The key fails RTMP auth from now on
+// and a stream that is live on it is terminated.
+func (ms *ModerationService) BanStreamKey(c *gin.Context) {
+	var req BanStreamKeyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	now := time.Now()
+	ban := &models.StreamKeyBan{
+		StreamKey: c.Param("key"),
+		Reason:    req.Reason,
+		CreatedAt: now,
+	}
+	if req.Duration != "" {
+		duration, err := time.ParseDuration(req.Duration)
+		if err != nil || duration <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "duration must be a positive duration such as 72h"})
+			return
+		}
+		expiresAt := now.Add(duration)
+		ban.ExpiresAt = &expiresAt
+	}
+
+	stream, err := ms.dynamoRepo.GetStreamByStreamKey(ban.StreamKey)
+	if err == nil {
+		ban.UserID = stream.UserID
+	}
+
+	if err := ms.dynamoRepo.SaveStreamKeyBan(ban); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not ban stream key"})
+		return
+	}
+	ms.streamService.InvalidateStreamKeyValidation(ban.StreamKey)
+
+	ctx := c.Request.Context()
+	slog.InfoContext(ctx, "🚫 Stream key banned", "stream_key", ban.StreamKey, "user_id", ban.UserID, "reason", ban.Reason, "expires_at", ban.ExpiresAt)
+
+	response := gin.H{"ban": ban}
+	if stream != nil && stream.Status != models.StreamStatusEnded {
+		dropped, err := ms.terminate(ctx, stream, "stream key banned: "+req.Reason)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Stream key banned but the live stream could not be terminated", "ban": ban})
+			return
+		}
+		response["terminated_stream_id"] = stream.ID
+		response["publisher_dropped"] = dropped
+	}
+
+	c.JSON(http.StatusCreated, response)
+}
+
+// GetStreamKeyBan handles GET /admin/stream-keys/:key/ban
+func (ms *ModerationService) GetStreamKeyBan(c *gin.Context) {
+	ban, err := ms.dynamoRepo.GetStreamKeyBan(c.Param("key"))
+	if errors.Is(err, repository.ErrStreamKeyNotBanned) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Stream key is not banned"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not get stream key ban"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"ban":    ban,
+		"active": ban.Active(time.Now()),
+	})
+}
+
+// UnbanStreamKey handles DELETE /admin/stream-keys/:key/ban
+func (ms *ModerationService) UnbanStreamKey(c *gin.Context) {
+	streamKey := c.Param("key")
+	if err := ms.dynamoRepo.DeleteStreamKeyBan(streamKey); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not unban stream key"})
+		return
+	}
+	ms.streamService.InvalidateStreamKeyValidation(streamKey)
+
+	slog.InfoContext(c.Request.Context(), "✅ Stream key unbanned", "stream_key", streamKey)
+	c.JSON(http.StatusOK, gin.H{"message": "Stream key unbanned"})
+}
+
+// terminate ends the stream and drops its publisher from the media server. It reports
+// whether the publisher was dropped, the stream is ended either way.
+func (ms *ModerationService) terminate(ctx context.Context, stream *models.Stream, reason string) (bool, error) {
+	live := stream.Status == models.StreamStatusLive
+	clientID := ""
+	if session, err := ms.streamService.GetStreamSession(stream.StreamKey); err == nil {
+		clientID = session.ClientID
+	}
+
+	if stream.Metadata == nil {
+		stream.Metadata = map[string]string{}
+	}
+	stream.Metadata["termination_reason"] = reason
+
+	// End it first so the media server's unpublish callback finds it ended instead of
+	// holding it open for a reconnect
+	if err := ms.streamService.TerminateStream(ctx, stream); err != nil {
+		slog.ErrorContext(ctx, "❌ Could not terminate stream", "stream_id", stream.ID, "error", err)
+		return false, err
+	}
+	slog.InfoContext(ctx, "🛑 Stream terminated", "stream_id", stream.ID, "user_id", stream.UserID, "reason", reason)
+
+	if !live {
+		return false, nil
+	}
+
+	app := stream.Metadata["app_name"]
+	if err := ms.srsClient.KickPublisher(ctx, app, stream.StreamKey, clientID); err != nil {
+		if !errors.Is(err, srs.ErrNotPublishing) {
+			slog.WarnContext(ctx, "⚠️ Could not drop publisher from the media server", "stream_id", stream.ID, "error", err)
+		}
+		return false, nil
+	}
+	return true, nil
+}
@@ -0,0 +1,304 @@
+// services/stream-management-service/internal/service/ops_dashboard.go
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/services/stream-management-service/internal/config"
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/services/stream-management-service/internal/repository"
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/shared/go/pkg/events"
+)
+
+const (
+	// maxDashboardClients bounds the dashboards connected to one replica
+	maxDashboardClients = 20
+	// dashboardClientBuffer is how many frames a dashboard may fall behind before missing some
+	dashboardClientBuffer = 64
+	// requestCountBucket is the resolution request counts are shared between replicas at
+	requestCountBucket = 10 * time.Second
+	// errorRateWindow is how far back error rates are computed over
+	errorRateWindow = time.Minute
+)
+
+// dashboardEventTypes are the events forwarded to dashboards as they happen
+var dashboardEventTypes = map[string]bool{
+	"stream_started": true,
+	"stream_ended":   true,
+	"stream_cleanup": true,
+}
+
+type dashboardFrame struct {
+	event string
+	data  interface{}
+}
+
+// OpsDashboardService streams the platform's state to ops dashboards over Server-Sent Events:
+// stream starts and stops as they're published on the event bus, and platform stats and HTTP
+// error rates every DashboardInterval. Events go through Redis so a dashboard sees those of
+// every replica, request counts are summed there too.
+type OpsDashboardService struct {
+	config        *config.Config
+	streamService *StreamService
+	redisRepo     *repository.RedisRepository
+
+	// HTTP requests answered since the counts were last added to Redis
+	requests     atomic.Int64
+	clientErrors atomic.Int64
+	serverErrors atomic.Int64
+
+	mu      sync.RWMutex
+	clients map[chan dashboardFrame]struct{}
+	// closed on shutdown, ending the streams so they don't hold the HTTP server open
+	stopped chan struct{}
+}
+
+func NewOpsDashboardService(cfg *config.Config, streamService *StreamService, redisRepo *repository.RedisRepository) *OpsDashboardService {
+	return &OpsDashboardService{
+		config:        cfg,
+		streamService: streamService,
+		redisRepo:     redisRepo,
+		clients:       make(map[chan dashboardFrame]struct{}),
+		stopped:       make(chan struct{}),
+	}
+}
+
+// CountRequests counts the HTTP requests this replica answers for the dashboard error rates
+func (ods *OpsDashboardService) CountRequests() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+
+		ods.requests.Add(1)
+		switch status := c.Writer.Status(); {
+		case status >= http.StatusInternalServerError:
+			ods.serverErrors.Add(1)
+		case status >= http.StatusBadRequest:
+			ods.clientErrors.Add(1)
+		}
+	}
+}
+
+// Start relays this replica's events to the dashboards of every replica, and periodically
+// shares its request counts and sends stats to the dashboards connected to it
+func (ods *OpsDashboardService) Start(ctx context.Context) {
+	go ods.relayEvents(ctx)
+	go ods.receiveEvents(ctx)
+
+	go func() {
+		ticker := time.NewTicker(ods.config.DashboardInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				close(ods.stopped)
+				return
+			case <-ticker.C:
+				ods.flushRequestCounts(ctx)
+				if ods.clientCount() > 0 {
+					ods.broadcastStats(ctx)
+				}
+			}
+		}
+	}()
+}
+
+// relayEvents publishes the dashboard events of the bus to Redis. Events Redis doesn't take
+// still reach the dashboards connected to this replica.
+func (ods *OpsDashboardService) relayEvents(ctx context.Context) {
+	envelopes, unsubscribe := ods.streamService.EventBus().Subscribe(dashboardClientBuffer)
+	defer unsubscribe()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case envelope := <-envelopes:
+			if !dashboardEventTypes[envelope.EventType] {
+				continue
+			}
+			payload, err := json.Marshal(envelope)
+			if err != nil {
+				continue
+			}
+			if err := ods.redisRepo.PublishDashboardEvent(string(payload)); err != nil {
+				slog.WarnContext(ctx, "⚠️ Could not relay dashboard event", "event_type", envelope.EventType, "error", err)
+				ods.broadcast(dashboardFrame{event: envelope.EventType, data: envelope})
+			}
+		}
+	}
+}
+
+// receiveEvents forwards the dashboard events of every replica to the dashboards connected
+// to this one
+func (ods *OpsDashboardService) receiveEvents(ctx context.Context) {
+	for payload := range ods.redisRepo.SubscribeDashboardEvents(ctx) {
+		var envelope events.Envelope
+		if err := json.Unmarshal([]byte(payload), &envelope); err != nil {
+			slog.WarnContext(ctx, "⚠️ Ignoring malformed dashboard event", "error", err)
+			continue
+		}
+		ods.broadcast(dashboardFrame{event: envelope.EventType, data: &envelope})
+	}
+}
+
+// flushRequestCounts adds the requests answered since the last flush to the shared counts
+func (ods *OpsDashboardService) flushRequestCounts(ctx context.Context) {
+	requests := ods.requests.Swap(0)
+	clientErrors := ods.clientErrors.Swap(0)
+	serverErrors := ods.serverErrors.Swap(0)
+	if requests == 0 {
+		return
+	}
+
+	bucket := time.Now().Unix() / int64(requestCountBucket.Seconds())
+	if err := ods.redisRepo.AddRequestCounts(bucket, requests, clientErrors, serverErrors, 2*errorRateWindow); err != nil {
+		slog.WarnContext(ctx, "⚠️ Could not share request counts", "error", err)
+		// Counted again with the next flush
+		ods.requests.Add(requests)
+		ods.clientErrors.Add(clientErrors)
+		ods.serverErrors.Add(serverErrors)
+	}
+}
+
+func (ods *OpsDashboardService) broadcastStats(ctx context.Context) {
+	for _, frame := range ods.statsFrames(ctx) {
+		ods.broadcast(frame)
+	}
+}
+
+// statsFrames returns the platform stats and error rates as they are now. Either is left out
+// when it can't be read.
+func (ods *OpsDashboardService) statsFrames(ctx context.Context) []dashboardFrame {
+	now := time.Now()
+	var frames []dashboardFrame
+
+	liveStreams, totalViewers, err := ods.streamService.platformSnapshot()
+	if err != nil {
+		slog.WarnContext(ctx, "⚠️ Could not get platform stats for dashboards", "error", err)
+	} else {
+		frames = append(frames, dashboardFrame{event: "stats", data: gin.H{
+			"live_streams":  liveStreams,
+			"total_viewers": totalViewers,
+			"events":        ods.streamService.EventPublisherStats(),
+			"timestamp":     now.Unix(),
+		}})
+	}
+
+	bucketSeconds := int64(requestCountBucket.Seconds())
+	current := now.Unix() / bucketSeconds
+	buckets := make([]int64, 0, int64(errorRateWindow.Seconds())/bucketSeconds)
+	for bucket := current; bucket > current-int64(errorRateWindow.Seconds())/bucketSeconds; bucket-- {
+		buckets = append(buckets, bucket)
+	}
+
+	requests, clientErrors, serverErrors, err := ods.redisRepo.GetRequestCounts(buckets)
+	if err != nil {
+		slog.WarnContext(ctx, "⚠️ Could not get request counts for dashboards", "error", err)
+	} else {
+		errorRate := 0.0
+		if requests > 0 {
+			errorRate = float64(serverErrors) / float64(requests)
+		}
+		frames = append(frames, dashboardFrame{event: "error_rate", data: gin.H{
+			"window_seconds": int(errorRateWindow.Seconds()),
+			"requests":       requests,
+			"client_errors":  clientErrors,
+			"server_errors":  serverErrors,
+			"error_rate":     errorRate,
+			"timestamp":      now.Unix(),
+		}})
+	}
+
+	return frames
+}
+
+// StreamEvents handles GET /admin/dashboard/events, a Server-Sent Events stream of "stats",
+// "error_rate" and stream lifecycle events, each event's data being JSON. The current stats
+// are sent right away.
+func (ods *OpsDashboardService) StreamEvents(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	frames, ok := ods.subscribe()
+	if !ok {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Too many dashboards connected"})
+		return
+	}
+	defer ods.unsubscribe(frames)
+
+	// The stream outlives the server's write timeout
+	if err := http.NewResponseController(c.Writer).SetWriteDeadline(time.Time{}); err != nil {
+		slog.DebugContext(ctx, "Could not clear write deadline for dashboard", "error", err)
+	}
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+
+	slog.InfoContext(ctx, "📊 Dashboard connected")
+	for _, frame := range ods.statsFrames(ctx) {
+		c.SSEvent(frame.event, frame.data)
+	}
+	c.Writer.Flush()
+
+	c.Stream(func(w io.Writer) bool {
+		select {
+		case <-ctx.Done():
+			return false
+		case <-ods.stopped:
+			return false
+		case frame := <-frames:
+			c.SSEvent(frame.event, frame.data)
+			return true
+		}
+	})
+	slog.InfoContext(ctx, "📊 Dashboard disconnected")
+}
+
+func (ods *OpsDashboardService) subscribe() (chan dashboardFrame, bool) {
+	ods.mu.Lock()
+	defer ods.mu.Unlock()
+
+	if len(ods.clients) >= maxDashboardClients {
+		return nil, false
+	}
+	frames := make(chan dashboardFrame, dashboardClientBuffer)
+	ods.clients[frames] = struct{}{}
+	return frames, true
+}
+
+func (ods *OpsDashboardService) unsubscribe(frames chan dashboardFrame) {
+	ods.mu.Lock()
+	defer ods.mu.Unlock()
+
+	delete(ods.clients, frames)
+}
+
+func (ods *OpsDashboardService) clientCount() int {
+	ods.mu.RLock()
+	defer ods.mu.RUnlock()
+
+	return len(ods.clients)
+}
+
+// broadcast sends a frame to every connected dashboard, dashboards that fell behind miss it
+func (ods *OpsDashboardService) broadcast(frame dashboardFrame) {
+	ods.mu.RLock()
+	defer ods.mu.RUnlock()
+
+	for frames := range ods.clients {
+		select {
+		case frames <- frame:
+		default:
+		}
+	}
+}
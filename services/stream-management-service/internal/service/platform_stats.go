@@ -0,0 +1,172 @@
+// services/stream-management-service/internal/service/platform_stats.go
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/services/stream-management-service/internal/models"
+)
+
+// statsGranularities are the rollups every sample and new stream is added to
+var statsGranularities = []models.StatsGranularity{models.StatsGranularityHour, models.StatsGranularityDay}
+
+// maxStatsBuckets bounds how many buckets a history request reads
+var maxStatsBuckets = map[models.StatsGranularity]int{
+	models.StatsGranularityHour: 31 * 24,
+	models.StatsGranularityDay:  366,
+}
+
+// defaultStatsRange is the history returned when a request doesn't say where it starts
+var defaultStatsRange = map[models.StatsGranularity]time.Duration{
+	models.StatsGranularityHour: 24 * time.Hour,
+	models.StatsGranularityDay:  30 * 24 * time.Hour,
+}
+
+// StartStatsAggregator samples live streams and viewers into the hourly and daily rollups each
+// sample interval until ctx is done. Replicas share the samples, each interval is taken once.
+func (s *StreamService) StartStatsAggregator(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(s.config.StatsSampleInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case now := <-ticker.C:
+				s.sampleStats(now)
+			}
+		}
+	}()
+}
+
+func (s *StreamService) sampleStats(now time.Time) {
+	sample := strconv.FormatInt(now.UnixNano()/int64(s.config.StatsSampleInterval), 10)
+	claimed, err := s.redisRepo.ClaimStatsSample(sample, s.config.StatsSampleInterval)
+	if err != nil {
+		slog.Warn("⚠️ Could not claim stats sample", "error", err)
+		return
+	}
+	if !claimed {
+		return
+	}
+
+	liveStreams, viewers, err := s.platformSnapshot()
+	if err != nil {
+		slog.Warn("⚠️ Could not get platform stats for rollups", "error", err)
+		return
+	}
+
+	for _, granularity := range statsGranularities {
+		if err := s.dynamoRepo.AddStatsSample(granularity, granularity.Truncate(now), int64(liveStreams), int64(viewers), s.statsExpiry(granularity, now)); err != nil {
+			slog.Warn("⚠️ Could not add stats sample", "granularity", granularity, "error", err)
+		}
+	}
+}
+
+// platformSnapshot returns the number of live streams and their viewers
+func (s *StreamService) platformSnapshot() (int, int, error) {
+	liveStreams, err := s.GetActiveStreamsInternal()
+	if err != nil {
+		return 0, 0, err
+	}
+
+	viewers := 0
+	for _, stream := range liveStreams {
+		viewers += stream.ViewerCount
+	}
+	return len(liveStreams), viewers, nil
+}
+
+// countNewStream adds a stream that just started to the rollups
+func (s *StreamService) countNewStream(at time.Time) {
+	for _, granularity := range statsGranularities {
+		if err := s.dynamoRepo.AddStatsNewStream(granularity, granularity.Truncate(at), s.statsExpiry(granularity, at)); err != nil {
+			slog.Warn("⚠️ Could not count new stream in stats", "granularity", granularity, "error", err)
+		}
+	}
+}
+
+// statsExpiry returns when a rollup expires, hourly rollups are only kept for their retention
+func (s *StreamService) statsExpiry(granularity models.StatsGranularity, at time.Time) int64 {
+	if granularity != models.StatsGranularityHour || s.config.StatsHourlyRetention <= 0 {
+		return 0
+	}
+	return at.Add(s.config.StatsHourlyRetention).Unix()
+}
+
+// GetStatsHistory handles GET /api/v1/stats/history?granularity=hour&from=...&to=... with
+// RFC 3339 times. to defaults to now and from to a day (hourly) or 30 days (daily) before it.
+func (s *StreamService) GetStatsHistory(c *gin.Context) {
+	granularity := models.StatsGranularity(c.DefaultQuery("granularity", string(models.StatsGranularityHour)))
+	maxBuckets, ok := maxStatsBuckets[granularity]
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "granularity must be hour or day"})
+		return
+	}
+
+	to := time.Now().UTC()
+	if value := c.Query("to"); value != "" {
+		parsed, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "to must be an RFC 3339 time"})
+			return
+		}
+		to = parsed
+	}
+	from := to.Add(-defaultStatsRange[granularity])
+	if value := c.Query("from"); value != "" {
+		parsed, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "from must be an RFC 3339 time"})
+			return
+		}
+		from = parsed
+	}
+
+	from, to = granularity.Truncate(from), granularity.Truncate(to)
+	if to.Before(from) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "from must be before to"})
+		return
+	}
+	if buckets := bucketsBetween(granularity, from, to); buckets > maxBuckets {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("at most %d %s buckets can be read at once", maxBuckets, granularity)})
+		return
+	}
+
+	rollups, err := s.dynamoRepo.GetStatsRollups(granularity, from, to)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not load stats history"})
+		return
+	}
+	if rollups == nil {
+		rollups = []*models.StatsRollup{}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"granularity": granularity,
+		"from":        from,
+		"to":          to,
+		"points":      rollups,
+		"incidents":   s.incidentsBetween(c.Request.Context(), from, to.Add(bucketLength(granularity))),
+	})
+}
+
+// bucketsBetween counts the buckets from the one starting at from to the one starting at to
+func bucketsBetween(granularity models.StatsGranularity, from, to time.Time) int {
+	return int(to.Sub(from)/bucketLength(granularity)) + 1
+}
+
+func bucketLength(granularity models.StatsGranularity) time.Duration {
+	if granularity == models.StatsGranularityDay {
+		return 24 * time.Hour
+	}
+	return time.Hour
+}
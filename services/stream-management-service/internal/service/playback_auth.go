@@ -0,0 +1,382 @@
+// services/stream-management-service/internal/service/playback_auth.go
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/services/stream-management-service/internal/config"
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/services/stream-management-service/internal/models"
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/services/stream-management-service/internal/playbacktoken"
+	grpcClient "github.com/Saoudyahya/Live-Streaming-Platform-Architecture/services/stream-management-service/pkg/grpc"
+)
+
+var (
+	// ErrGeoRestricted is returned when a stream isn't available in the viewer's country
+	ErrGeoRestricted = errors.New("stream is not available in your region")
+	// ErrNotPlayable is returned for streams that are neither live nor available as a VOD
+	ErrNotPlayable = errors.New("stream is not playable")
+	// ErrAgeRestricted is returned for mature streams to anonymous viewers and to viewers
+	// who aren't known to be adults
+	ErrAgeRestricted = errors.New("stream is only available to adult viewers")
+	// ErrAgeCheckUnavailable is returned when the user service can't tell a viewer's age
+	ErrAgeCheckUnavailable = errors.New("viewer age can't be checked right now")
+	// ErrPrivateStream is returned for private streams the viewer wasn't invited to
+	ErrPrivateStream = errors.New("stream is private")
+	// ErrPassphraseRequired is returned for private streams that take a passphrase when the
+	// viewer didn't give one
+	ErrPassphraseRequired = errors.New("stream requires a passphrase")
+	// ErrInvalidPassphrase is returned when the viewer gave the wrong passphrase
+	ErrInvalidPassphrase = errors.New("invalid passphrase")
+	// ErrPlaybackTokenInvalid is returned for playback tokens that are forged, expired or
+	// issued for another stream
+	ErrPlaybackTokenInvalid = errors.New("invalid playback token")
+)
+
+// OriginalURIHeader carries the URI of the request a CDN or auth_request subrequest verifies
+const OriginalURIHeader = "X-Original-URI"
+
+// PlaybackViewer is who asks to play a stream
+type PlaybackViewer struct {
+	UserID     int64  // 0 for anonymous viewers
+	Country    string // ISO country code, empty when unknown
+	Passphrase string // given for private streams, empty otherwise
+}
+
+type PlaybackAuthorizeRequest struct {
+	StreamID   string `json:"stream_id" binding:"required"`
+	Passphrase string `json:"passphrase"`
+}
+
+type PlaybackTokenRequest struct {
+	Passphrase string `json:"passphrase"`
+}
+
+// PlaybackAuthorizer decides whether a viewer may play a stream. Every rule a stream can
+// restrict playback with is checked here, before any playback URL is handed out.
+type PlaybackAuthorizer struct {
+	config        *config.Config
+	streamService *StreamService
+	geo           *GeoLocator
+	userClient    *grpcClient.UserServiceClient
+	tokens        *playbacktoken.Signer
+}
+
+func NewPlaybackAuthorizer(cfg *config.Config, streamService *StreamService, geo *GeoLocator, userClient *grpcClient.UserServiceClient) *PlaybackAuthorizer {
+	return &PlaybackAuthorizer{
+		config:        cfg,
+		streamService: streamService,
+		geo:           geo,
+		userClient:    userClient,
+		tokens:        playbacktoken.NewSigner(cfg.PlaybackTokenSecret),
+	}
+}
+
+// Authorize returns the URL a viewer may play a stream from
+func (pa *PlaybackAuthorizer) Authorize(ctx context.Context, stream *models.Stream, viewer PlaybackViewer) (string, error) {
+	if stream.Blocked || stream.Deleted() {
+		return "", ErrNotPlayable
+	}
+	if stream.EffectiveVisibility() == models.StreamVisibilityPrivate {
+		if err := pa.checkPrivateAccess(stream, viewer); err != nil {
+			return "", err
+		}
+	}
+	if !stream.RegionAllowed(viewer.Country) {
+		return "", ErrGeoRestricted
+	}
+	if stream.IsMature {
+		if err := pa.checkAge(ctx, stream, viewer); err != nil {
+			return "", err
+		}
+	}
+
+	switch {
+	case (stream.Status == models.StreamStatusLive || stream.Status == models.StreamStatusReconnecting) && stream.PlaybackID != "":
+		return liveHLSURL(pa.config.PlaybackBaseURL, stream), nil
+	case stream.VODReady && stream.VODPlaybackURLs["master"] != "":
+		return stream.VODPlaybackURLs["master"], nil
+	default:
+		return "", ErrNotPlayable
+	}
+}
+
+// checkPrivateAccess lets the broadcaster, invited viewers and viewers with the passphrase
+// play a private stream
+func (pa *PlaybackAuthorizer) checkPrivateAccess(stream *models.Stream, viewer PlaybackViewer) error {
+	if viewer.UserID != 0 && viewer.UserID == stream.UserID {
+		return nil
+	}
+
+	access, err := pa.streamService.dynamoRepo.GetStreamAccess(stream.ID)
+	if err != nil {
+		return fmt.Errorf("failed to load stream access: %w", err)
+	}
+	if viewer.UserID != 0 && access.Invited(viewer.UserID) {
+		return nil
+	}
+
+	switch {
+	case access.PassphraseHash == "":
+		return ErrPrivateStream
+	case viewer.Passphrase == "":
+		return ErrPassphraseRequired
+	case !checkPassphrase(access.PassphraseHash, viewer.Passphrase):
+		return ErrInvalidPassphrase
+	}
+	return nil
+}
+
+// checkAge lets adults and the broadcaster play a mature stream. Viewers whose age can't be
+// checked are refused, mature content is never played by default.
+func (pa *PlaybackAuthorizer) checkAge(ctx context.Context, stream *models.Stream, viewer PlaybackViewer) error {
+	if viewer.UserID == 0 {
+		return ErrAgeRestricted
+	}
+	if viewer.UserID == stream.UserID {
+		return nil
+	}
+	if pa.userClient == nil {
+		return ErrAgeCheckUnavailable
+	}
+
+	age, err := pa.userClient.GetViewerAge(ctx, viewer.UserID)
+	if err != nil {
+		slog.WarnContext(ctx, "⚠️ Could not check viewer age", "user_id", viewer.UserID, "error", err)
+		return ErrAgeCheckUnavailable
+	}
+	if !age.IsAdult {
+		return ErrAgeRestricted
+	}
+	return nil
+}
+
+// AuthorizePlayback handles POST /api/v1/playback/authorize
+func (pa *PlaybackAuthorizer) AuthorizePlayback(c *gin.Context) {
+	var req PlaybackAuthorizeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	stream, err := pa.streamService.GetStreamByIDInternal(req.StreamID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Stream not found"})
+		return
+	}
+
+	viewer := PlaybackViewer{
+		UserID:     ViewerID(c),
+		Country:    pa.geo.Country(c),
+		Passphrase: req.Passphrase,
+	}
+
+	playbackURL, err := pa.Authorize(c.Request.Context(), stream, viewer)
+	if err != nil {
+		respondPlaybackError(c, err, stream, viewer)
+		return
+	}
+
+	response := gin.H{
+		"authorized":   true,
+		"stream_id":    stream.ID,
+		"playback_url": playbackURL,
+	}
+	if pa.tokens.Enabled() {
+		token, claims, err := pa.tokens.Issue(stream.ID, viewer.UserID, pa.config.PlaybackTokenTTL)
+		if err != nil {
+			slog.ErrorContext(c.Request.Context(), "❌ Could not issue playback token", "stream_id", stream.ID, "error", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not authorize playback"})
+			return
+		}
+		response["playback_url"] = withPlaybackToken(playbackURL, token)
+		response["token"] = token
+		response["expires_at"] = claims.ExpiresAt
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// IssuePlaybackToken handles POST /api/v1/streams/:id/playback-token. The token is only
+// issued to viewers allowed to play the stream, and the playback URL comes signed with it.
+func (pa *PlaybackAuthorizer) IssuePlaybackToken(c *gin.Context) {
+	var req PlaybackTokenRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+	if !pa.tokens.Enabled() {
+		c.JSON(http.StatusNotImplemented, gin.H{"error": "Playback tokens are not configured"})
+		return
+	}
+
+	stream, err := pa.streamService.GetStreamByIDInternal(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Stream not found"})
+		return
+	}
+
+	viewer := PlaybackViewer{
+		UserID:     ViewerID(c),
+		Country:    pa.geo.Country(c),
+		Passphrase: req.Passphrase,
+	}
+	playbackURL, err := pa.Authorize(c.Request.Context(), stream, viewer)
+	if err != nil {
+		respondPlaybackError(c, err, stream, viewer)
+		return
+	}
+
+	token, claims, err := pa.tokens.Issue(stream.ID, viewer.UserID, pa.config.PlaybackTokenTTL)
+	if err != nil {
+		slog.ErrorContext(c.Request.Context(), "❌ Could not issue playback token", "stream_id", stream.ID, "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not issue playback token"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"token":        token,
+		"stream_id":    stream.ID,
+		"expires_at":   claims.ExpiresAt,
+		"playback_url": withPlaybackToken(playbackURL, token),
+	})
+}
+
+// VerifyPlayback handles GET /playback/verify for a CDN or nginx auth_request in front of
+// the HLS output. The request being verified comes as X-Original-URI, or as uri in the query,
+// and carries its token as ?token=. It answers 204 when the token is valid for the stream in
+// the path, 403 otherwise.
+func (pa *PlaybackAuthorizer) VerifyPlayback(c *gin.Context) {
+	original := c.GetHeader(OriginalURIHeader)
+	if original == "" {
+		original = c.Query("uri")
+	}
+	uri, err := url.ParseRequestURI(original)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "The URI to verify is missing"})
+		return
+	}
+
+	// The HLS output is served as /<app>/<playback ID>.m3u8 with <playback ID>-<seq>.ts
+	// segments next to it
+	playbackID := strings.TrimSuffix(path.Base(uri.Path), path.Ext(uri.Path))
+	if i := strings.LastIndex(playbackID, "-"); i > 0 && path.Ext(uri.Path) == ".ts" {
+		if _, err := strconv.Atoi(playbackID[i+1:]); err == nil {
+			playbackID = playbackID[:i]
+		}
+	}
+
+	stream, _, err := pa.LiveStreamByPlaybackID(playbackID)
+	if err != nil {
+		c.AbortWithStatus(http.StatusForbidden)
+		return
+	}
+	if _, err := pa.ParsePlaybackToken(uri.Query().Get("token"), stream.ID); err != nil {
+		slog.DebugContext(c.Request.Context(), "🔒 CDN playback refused", "stream_id", stream.ID, "error", err)
+		c.AbortWithStatus(http.StatusForbidden)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// TokensRequired reports whether playback has to present a playback token
+func (pa *PlaybackAuthorizer) TokensRequired() bool {
+	return pa.config.Tunables().PlaybackTokenRequired
+}
+
+// ParsePlaybackToken returns the claims of a playback token valid for a stream
+func (pa *PlaybackAuthorizer) ParsePlaybackToken(token, streamID string) (*playbacktoken.Claims, error) {
+	claims, err := pa.tokens.Parse(token)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrPlaybackTokenInvalid, err)
+	}
+	if claims.StreamID != streamID {
+		return nil, fmt.Errorf("%w: issued for another stream", ErrPlaybackTokenInvalid)
+	}
+	return claims, nil
+}
+
+// LiveStreamByPlaybackID returns the stream currently played under a playback ID, with the
+// stream key it is published with
+func (pa *PlaybackAuthorizer) LiveStreamByPlaybackID(playbackID string) (*models.Stream, string, error) {
+	streamKey, err := pa.streamService.StreamKeyForPlayback(playbackID)
+	if err != nil {
+		return nil, "", err
+	}
+	session, err := pa.streamService.GetStreamSession(streamKey)
+	if err != nil {
+		return nil, "", err
+	}
+	// The key was published again since, under another playback ID
+	if session.PlaybackID != playbackID {
+		return nil, "", fmt.Errorf("playback ID is no longer published")
+	}
+	if !session.Started() {
+		return nil, "", fmt.Errorf("stream has not started")
+	}
+	stream, err := pa.streamService.GetStreamByIDInternal(session.StreamID)
+	if err != nil {
+		return nil, "", err
+	}
+	return stream, streamKey, nil
+}
+
+// withPlaybackToken adds a playback token to the query of a playback URL
+func withPlaybackToken(playbackURL, token string) string {
+	parsed, err := url.Parse(playbackURL)
+	if err != nil {
+		return playbackURL
+	}
+	query := parsed.Query()
+	query.Set("token", token)
+	parsed.RawQuery = query.Encode()
+	return parsed.String()
+}
+
+// respondPlaybackError answers a request whose playback Authorize refused
+func respondPlaybackError(c *gin.Context, err error, stream *models.Stream, viewer PlaybackViewer) {
+	switch {
+	case errors.Is(err, ErrPrivateStream):
+		c.JSON(http.StatusForbidden, gin.H{
+			"error":            err.Error(),
+			"code":             "PRIVATE_STREAM",
+			"sign_in_required": viewer.UserID == 0,
+		})
+	case errors.Is(err, ErrPassphraseRequired):
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error(), "code": "PASSPHRASE_REQUIRED"})
+	case errors.Is(err, ErrInvalidPassphrase):
+		slog.InfoContext(c.Request.Context(), "🔒 Playback refused for a wrong passphrase", "stream_id", stream.ID, "user_id", viewer.UserID)
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error(), "code": "INVALID_PASSPHRASE"})
+	case errors.Is(err, ErrGeoRestricted):
+		slog.InfoContext(c.Request.Context(), "🌍 Playback refused by geo restrictions", "stream_id", stream.ID, "country", viewer.Country)
+		c.JSON(http.StatusForbidden, gin.H{
+			"error":   err.Error(),
+			"code":    "GEO_RESTRICTED",
+			"country": viewer.Country,
+		})
+	case errors.Is(err, ErrAgeRestricted):
+		c.JSON(http.StatusForbidden, gin.H{
+			"error":            err.Error(),
+			"code":             "AGE_RESTRICTED",
+			"sign_in_required": viewer.UserID == 0,
+		})
+	case errors.Is(err, ErrAgeCheckUnavailable):
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": err.Error(), "code": "AGE_CHECK_UNAVAILABLE"})
+	case errors.Is(err, ErrNotPlayable):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error(), "code": "NOT_PLAYABLE"})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not authorize playback"})
+	}
+}
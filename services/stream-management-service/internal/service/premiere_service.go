@@ -0,0 +1,323 @@
+// services/stream-management-service/internal/service/premiere_service.go
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/services/stream-management-service/internal/config"
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/services/stream-management-service/internal/models"
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/services/stream-management-service/internal/repository"
+)
+
+// premiereKeyPrefix marks the one-off stream keys premieres are relayed with, the media
+// server's auth callback accepts them without asking the user service
+const premiereKeyPrefix = "premiere_"
+
+type PremiereService struct {
+	config        *config.Config
+	dynamoRepo    *repository.DynamoDBRepository
+	redisRepo     *repository.RedisRepository
+	streamService *StreamService
+	disabled      error // set when premieres can't be relayed, e.g. ffmpeg is missing
+}
+
+type SchedulePremiereRequest struct {
+	ScheduledAt time.Time `json:"scheduled_at" binding:"required"`
+	Title       string    `json:"title"`
+}
+
+func NewPremiereService(cfg *config.Config, dynamoRepo *repository.DynamoDBRepository, redisRepo *repository.RedisRepository, streamService *StreamService) *PremiereService {
+	return &PremiereService{
+		config:        cfg,
+		dynamoRepo:    dynamoRepo,
+		redisRepo:     redisRepo,
+		streamService: streamService,
+	}
+}
+
+// Disable rejects new premieres
+func (ps *PremiereService) Disable(reason error) {
+	slog.Warn("🚫 Premieres disabled", "reason", reason)
+	ps.disabled = reason
+}
+
+// SchedulePremiere handles POST /api/v1/vods/:id/premiere
+func (ps *PremiereService) SchedulePremiere(c *gin.Context) {
+	if ps.disabled != nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Premieres are currently unavailable"})
+		return
+	}
+
+	var req SchedulePremiereRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	vod, err := ps.dynamoRepo.GetVODByID(c.Param("id"))
+	if err != nil || vod.Deleted() {
+		c.JSON(http.StatusNotFound, gin.H{"error": "VOD not found"})
+		return
+	}
+	if !authorizeOwner(c, vod.UserID) {
+		return
+	}
+
+	if vod.Blocked {
+		respondTakedown(c, vod.TakedownID)
+		return
+	}
+	if vod.Premiere.Active() {
+		c.JSON(http.StatusConflict, gin.H{"error": "VOD already has a premiere scheduled"})
+		return
+	}
+	if sourceURL(vod.Renditions) == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "VOD has no playable rendition"})
+		return
+	}
+
+	now := time.Now()
+	if !req.ScheduledAt.After(now) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "scheduled_at must be in the future"})
+		return
+	}
+	if req.ScheduledAt.After(now.Add(ps.config.MaxPremiereLeadTime)) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("premieres can be scheduled at most %s ahead", ps.config.MaxPremiereLeadTime)})
+		return
+	}
+
+	title := req.Title
+	if title == "" {
+		title = vod.Title
+	}
+	// Flagged titles are queued for review once the premiere stream exists
+	titleFilter := ps.streamService.FilterTitle(c.Request.Context(), title)
+	if titleFilter.Rejected {
+		respondContentRejected(c, "title", titleFilter)
+		return
+	}
+	title = titleFilter.Text
+
+	vod.Premiere = &models.Premiere{
+		Status:      models.PremiereStatusScheduled,
+		Title:       title,
+		ScheduledAt: req.ScheduledAt.UTC(),
+		Visibility:  vod.Visibility,
+	}
+	// Keep it out of the catalog until it has premiered, private VODs stay private
+	if vod.Visibility == models.VODVisibilityPublic {
+		vod.Visibility = models.VODVisibilityUnlisted
+	}
+	vod.UpdatedAt = now
+
+	if err := ps.redisRepo.SchedulePremiere(vod.ID, req.ScheduledAt); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not schedule premiere"})
+		return
+	}
+	if err := ps.dynamoRepo.SaveVOD(vod); err != nil {
+		ps.redisRepo.UnschedulePremiere(vod.ID)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not schedule premiere"})
+		return
+	}
+
+	slog.InfoContext(c.Request.Context(), "🎬 Premiere scheduled", "vod_id", vod.ID, "user_id", vod.UserID, "scheduled_at", vod.Premiere.ScheduledAt)
+	c.JSON(http.StatusCreated, vod)
+}
+
+// CancelPremiere handles DELETE /api/v1/vods/:id/premiere. Premieres that already started
+// run to the end.
+func (ps *PremiereService) CancelPremiere(c *gin.Context) {
+	vod, err := ps.dynamoRepo.GetVODByID(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "VOD not found"})
+		return
+	}
+	if !authorizeOwner(c, vod.UserID) {
+		return
+	}
+
+	if vod.Premiere == nil || vod.Premiere.Status != models.PremiereStatusScheduled {
+		c.JSON(http.StatusConflict, gin.H{"error": "VOD has no scheduled premiere"})
+		return
+	}
+
+	claimed, err := ps.redisRepo.UnschedulePremiere(vod.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not cancel premiere"})
+		return
+	}
+	if !claimed {
+		c.JSON(http.StatusConflict, gin.H{"error": "Premiere is already starting"})
+		return
+	}
+
+	if err := ps.finishPremiere(vod, models.PremiereStatusCancelled, nil); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not cancel premiere"})
+		return
+	}
+
+	c.JSON(http.StatusOK, vod)
+}
+
+// StartScheduler periodically starts the premieres that are due
+func (ps *PremiereService) StartScheduler(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(5 * time.Second)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if err := ps.startDuePremieres(ctx); err != nil {
+					slog.WarnContext(ctx, "⚠️ Error starting premieres", "error", err)
+				}
+			}
+		}
+	}()
+}
+
+func (ps *PremiereService) startDuePremieres(ctx context.Context) error {
+	vodIDs, err := ps.redisRepo.GetDuePremieres(time.Now())
+	if err != nil {
+		return err
+	}
+
+	for _, vodID := range vodIDs {
+		// Only the instance that removes it from the schedule plays it
+		claimed, err := ps.redisRepo.UnschedulePremiere(vodID)
+		if err != nil || !claimed {
+			continue
+		}
+		go ps.runPremiere(ctx, vodID)
+	}
+
+	return nil
+}
+
+// runPremiere relays the VOD into ingest under a one-off stream key. The media server's
+// callbacks then create and end the live stream like for any broadcaster.
+func (ps *PremiereService) runPremiere(ctx context.Context, vodID string) {
+	vod, err := ps.dynamoRepo.GetVODByID(vodID)
+	if err != nil {
+		slog.ErrorContext(ctx, "❌ Could not load premiere VOD", "vod_id", vodID, "error", err)
+		return
+	}
+	if vod.Premiere == nil || vod.Premiere.Status != models.PremiereStatusScheduled {
+		return
+	}
+
+	streamKey := generatePremiereKey()
+	session := &models.StreamSession{
+		UserID:        vod.UserID,
+		StreamKey:     streamKey,
+		AppName:       "live",
+		StartedAt:     time.Now().Unix(),
+		Title:         vod.Premiere.Title,
+		PremiereVODID: vod.ID,
+	}
+	if err := ps.streamService.StoreStreamSession(streamKey, session); err != nil {
+		ps.finishPremiere(vod, models.PremiereStatusFailed, fmt.Errorf("could not store stream session: %w", err))
+		return
+	}
+
+	now := time.Now()
+	vod.Premiere.Status = models.PremiereStatusLive
+	vod.Premiere.StartedAt = &now
+	vod.Premiere.StreamKey = streamKey
+	vod.UpdatedAt = now
+	if err := ps.dynamoRepo.SaveVOD(vod); err != nil {
+		slog.WarnContext(ctx, "⚠️ Could not mark premiere as live", "vod_id", vod.ID, "error", err)
+	}
+
+	slog.InfoContext(ctx, "🎬 Premiere started", "vod_id", vod.ID, "user_id", vod.UserID)
+	relayErr := ps.relay(ctx, sourceURL(vod.Renditions), streamKey)
+
+	// The stream may have been attached to the VOD while it played
+	if latest, err := ps.dynamoRepo.GetVODByID(vod.ID); err == nil && latest.Premiere != nil {
+		vod = latest
+	}
+
+	status := models.PremiereStatusCompleted
+	if relayErr != nil {
+		status = models.PremiereStatusFailed
+	}
+	ps.finishPremiere(vod, status, relayErr)
+}
+
+// relay plays the source at its native rate into the ingest application without re-encoding
+func (ps *PremiereService) relay(ctx context.Context, source, streamKey string) error {
+	source = strings.TrimPrefix(source, "file://")
+	target := strings.TrimSuffix(ps.config.PremiereIngestURL, "/") + "/" + streamKey
+
+	cmd := exec.CommandContext(ctx, ps.config.FFmpegPath,
+		"-re",
+		"-i", source,
+		"-c", "copy",
+		"-f", "flv",
+		target,
+	)
+
+	if output, err := cmd.CombinedOutput(); err != nil {
+		if ctx.Err() != nil {
+			return fmt.Errorf("service shut down during the premiere")
+		}
+		return fmt.Errorf("ffmpeg failed: %w: %s", err, lastLine(string(output)))
+	}
+
+	return nil
+}
+
+// finishPremiere records how the premiere ended and turns the VOD back into a regular one
+func (ps *PremiereService) finishPremiere(vod *models.VOD, status models.PremiereStatus, cause error) error {
+	now := time.Now()
+	vod.Premiere.Status = status
+	vod.Premiere.EndedAt = &now
+	if cause != nil {
+		vod.Premiere.Error = cause.Error()
+	}
+	vod.Visibility = vod.Premiere.Visibility
+	vod.UpdatedAt = now
+
+	if err := ps.dynamoRepo.SaveVOD(vod); err != nil {
+		slog.Error("❌ Could not finish premiere", "vod_id", vod.ID, "status", status, "error", err)
+		return err
+	}
+
+	if cause != nil {
+		slog.Warn("⚠️ Premiere ended", "vod_id", vod.ID, "status", status, "error", cause)
+	} else {
+		slog.Info("✅ Premiere ended", "vod_id", vod.ID, "status", status)
+	}
+	return nil
+}
+
+// sourceURL returns the URL of the best rendition, the original recording when there is one
+func sourceURL(renditions []models.Rendition) string {
+	for _, rendition := range renditions {
+		if rendition.Name == "source" {
+			return rendition.URL
+		}
+	}
+	if len(renditions) > 0 {
+		return renditions[0].URL
+	}
+	return ""
+}
+
+func generatePremiereKey() string {
+	bytes := make([]byte, 16)
+	rand.Read(bytes)
+	return premiereKeyPrefix + hex.EncodeToString(bytes)
+}
@@ -0,0 +1,303 @@
+// services/stream-management-service/internal/service/quality_ladders.go
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"regexp"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/services/stream-management-service/internal/config"
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/services/stream-management-service/internal/models"
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/services/stream-management-service/internal/repository"
+)
+
+// maxLadderFPS bounds the frame rate a rendition may ask for
+const maxLadderFPS = 120
+
+var qualityTierName = regexp.MustCompile(`^[a-z0-9_-]{1,32}$`)
+
+// QualityLadderService decides which renditions the live transcoder produces for a publisher.
+// Admins give users a tier and tiers a ladder, users without a tier get the default tier and
+// tiers without a ladder the default tier's, or the configured ladder when it has none.
+type QualityLadderService struct {
+	config        *config.Config
+	dynamoRepo    *repository.DynamoDBRepository
+	defaultLadder []models.LadderRendition
+}
+
+type QualityLadderRequest struct {
+	Renditions []models.LadderRendition `json:"renditions"`
+}
+
+type UserTierRequest struct {
+	Tier string `json:"tier" binding:"required"`
+}
+
+func NewQualityLadderService(cfg *config.Config, dynamoRepo *repository.DynamoDBRepository) *QualityLadderService {
+	ls := &QualityLadderService{
+		config:     cfg,
+		dynamoRepo: dynamoRepo,
+	}
+
+	for name, spec := range cfg.QualityLadder {
+		rendition, err := parseHLSRendition(name, spec)
+		if err != nil {
+			slog.Warn("⚠️ Ignoring invalid quality ladder rendition", "name", name, "spec", spec, "error", err)
+			continue
+		}
+		ls.defaultLadder = append(ls.defaultLadder, models.LadderRendition{
+			Name:        rendition.name,
+			Width:       rendition.width,
+			Height:      rendition.height,
+			BitrateKbps: rendition.bitrate,
+		})
+	}
+	sortLadder(ls.defaultLadder)
+
+	return ls
+}
+
+// LadderForUser returns the quality ladder of a user's tier. It doesn't fail, a ladder that
+// can't be read falls back to the configured one so publishing isn't blocked on it.
+func (ls *QualityLadderService) LadderForUser(ctx context.Context, userID int64) *models.QualityLadder {
+	tier := models.QualityTierDefault
+	userTier, err := ls.dynamoRepo.GetUserTier(userID)
+	if err != nil {
+		slog.WarnContext(ctx, "⚠️ Could not get user tier, using the default", "user_id", userID, "error", err)
+	} else {
+		tier = userTier.Tier
+	}
+
+	ladder, err := ls.ladderForTier(tier)
+	if err != nil {
+		slog.WarnContext(ctx, "⚠️ Could not get quality ladder, using the configured one", "tier", tier, "error", err)
+		return ls.configuredLadder(tier)
+	}
+	return ladder
+}
+
+// ladderForTier returns a tier's stored ladder, falling back to the default tier's and then
+// the configured one. The ladder returned keeps the tier asked for.
+func (ls *QualityLadderService) ladderForTier(tier string) (*models.QualityLadder, error) {
+	for _, candidate := range []string{tier, models.QualityTierDefault} {
+		ladder, err := ls.dynamoRepo.GetQualityLadder(candidate)
+		if errors.Is(err, repository.ErrQualityLadderNotFound) {
+			if candidate == models.QualityTierDefault {
+				break
+			}
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		ladder.Tier = tier
+		return ladder, nil
+	}
+	return ls.configuredLadder(tier), nil
+}
+
+func (ls *QualityLadderService) configuredLadder(tier string) *models.QualityLadder {
+	renditions := make([]models.LadderRendition, len(ls.defaultLadder))
+	copy(renditions, ls.defaultLadder)
+	return &models.QualityLadder{Tier: tier, Renditions: renditions}
+}
+
+// ListQualityLadders handles GET /admin/quality-ladders. The default tier is always listed,
+// with the configured ladder until an admin stores one.
+func (ls *QualityLadderService) ListQualityLadders(c *gin.Context) {
+	ladders, err := ls.dynamoRepo.GetQualityLadders()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not load quality ladders"})
+		return
+	}
+
+	hasDefault := false
+	for _, ladder := range ladders {
+		if ladder.Tier == models.QualityTierDefault {
+			hasDefault = true
+		}
+	}
+	if !hasDefault {
+		ladders = append(ladders, ls.configuredLadder(models.QualityTierDefault))
+	}
+	sort.Slice(ladders, func(i, j int) bool { return ladders[i].Tier < ladders[j].Tier })
+
+	c.JSON(http.StatusOK, gin.H{"ladders": ladders, "count": len(ladders)})
+}
+
+// GetQualityLadder handles GET /admin/quality-ladders/:tier, returning the ladder the tier's
+// users get
+func (ls *QualityLadderService) GetQualityLadder(c *gin.Context) {
+	tier := c.Param("tier")
+	if !qualityTierName.MatchString(tier) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid tier"})
+		return
+	}
+
+	ladder, err := ls.ladderForTier(tier)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not load quality ladder"})
+		return
+	}
+
+	c.JSON(http.StatusOK, ladder)
+}
+
+// PutQualityLadder handles PUT /admin/quality-ladders/:tier, creating or replacing a tier's
+// ladder. An empty ladder only passes the source through. Publishers get the new ladder the
+// next time they connect.
+func (ls *QualityLadderService) PutQualityLadder(c *gin.Context) {
+	tier := c.Param("tier")
+	if !qualityTierName.MatchString(tier) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Tier must be 1 to 32 lowercase letters, digits, - or _"})
+		return
+	}
+
+	var req QualityLadderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := validateLadder(req.Renditions); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if req.Renditions == nil {
+		req.Renditions = []models.LadderRendition{}
+	}
+	sortLadder(req.Renditions)
+
+	ladder := &models.QualityLadder{
+		Tier:       tier,
+		Renditions: req.Renditions,
+		UpdatedAt:  time.Now().UTC(),
+	}
+	if err := ls.dynamoRepo.SaveQualityLadder(ladder); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not save quality ladder"})
+		return
+	}
+
+	slog.InfoContext(c.Request.Context(), "🎚️ Quality ladder saved", "tier", tier, "renditions", len(ladder.Renditions))
+	c.JSON(http.StatusOK, ladder)
+}
+
+// DeleteQualityLadder handles DELETE /admin/quality-ladders/:tier. The tier's users get the
+// default ladder, deleting the default ladder restores the configured one.
+func (ls *QualityLadderService) DeleteQualityLadder(c *gin.Context) {
+	tier := c.Param("tier")
+	if err := ls.dynamoRepo.DeleteQualityLadder(tier); err != nil {
+		if errors.Is(err, repository.ErrQualityLadderNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Quality ladder not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not delete quality ladder"})
+		return
+	}
+
+	slog.InfoContext(c.Request.Context(), "🎚️ Quality ladder deleted", "tier", tier)
+	c.Status(http.StatusNoContent)
+}
+
+// SetUserTier handles PUT /admin/users/:id/tier. The tier needs a ladder, so a typo doesn't
+// quietly put a user on the default one.
+func (ls *QualityLadderService) SetUserTier(c *gin.Context) {
+	userID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	var req UserTierRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if !qualityTierName.MatchString(req.Tier) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid tier"})
+		return
+	}
+
+	if req.Tier == models.QualityTierDefault {
+		if err := ls.dynamoRepo.DeleteUserTier(userID); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not save user tier"})
+			return
+		}
+		slog.InfoContext(c.Request.Context(), "🎚️ User tier reset", "user_id", userID)
+		c.JSON(http.StatusOK, &models.UserTier{UserID: userID, Tier: models.QualityTierDefault})
+		return
+	}
+
+	if _, err := ls.dynamoRepo.GetQualityLadder(req.Tier); err != nil {
+		if errors.Is(err, repository.ErrQualityLadderNotFound) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Tier has no quality ladder"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not load quality ladder"})
+		return
+	}
+
+	userTier := &models.UserTier{
+		UserID:    userID,
+		Tier:      req.Tier,
+		UpdatedAt: time.Now().UTC(),
+	}
+	if err := ls.dynamoRepo.SaveUserTier(userTier); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not save user tier"})
+		return
+	}
+
+	slog.InfoContext(c.Request.Context(), "🎚️ User tier set", "user_id", userID, "tier", userTier.Tier)
+	c.JSON(http.StatusOK, userTier)
+}
+
+// GetUserQualityLadder handles GET /admin/users/:id/quality-ladder, the ladder the user's
+// next stream is transcoded with
+func (ls *QualityLadderService) GetUserQualityLadder(c *gin.Context) {
+	userID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	c.JSON(http.StatusOK, ls.LadderForUser(c.Request.Context(), userID))
+}
+
+// validateLadder checks renditions an admin sent. Dimensions must be even for the encoder's
+// chroma subsampling.
+func validateLadder(renditions []models.LadderRendition) error {
+	if len(renditions) > models.MaxLadderRenditions {
+		return fmt.Errorf("a ladder has at most %d renditions", models.MaxLadderRenditions)
+	}
+
+	names := make(map[string]bool, len(renditions))
+	for _, rendition := range renditions {
+		spec := fmt.Sprintf("%dx%d@%d", rendition.Width, rendition.Height, rendition.BitrateKbps)
+		if _, err := parseHLSRendition(rendition.Name, spec); err != nil {
+			return fmt.Errorf("rendition %q: %w", rendition.Name, err)
+		}
+		if rendition.Width%2 != 0 || rendition.Height%2 != 0 {
+			return fmt.Errorf("rendition %q: width and height must be even", rendition.Name)
+		}
+		if rendition.FPS < 0 || rendition.FPS > maxLadderFPS {
+			return fmt.Errorf("rendition %q: fps must be between 0 and %d", rendition.Name, maxLadderFPS)
+		}
+		if names[rendition.Name] {
+			return fmt.Errorf("rendition %q is listed twice", rendition.Name)
+		}
+		names[rendition.Name] = true
+	}
+	return nil
+}
+
+// sortLadder orders renditions by bitrate, highest first
+func sortLadder(renditions []models.LadderRendition) {
+	sort.Slice(renditions, func(i, j int) bool { return renditions[i].BitrateKbps > renditions[j].BitrateKbps })
+}
@@ -0,0 +1,195 @@
+// services/stream-management-service/internal/service/raid_service.go
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/services/stream-management-service/internal/config"
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/services/stream-management-service/internal/models"
+)
+
+// RaidService lets a broadcaster send their viewers to another live stream. The raid is kept
+// on both streams, published as a raid event and handed to the chat service, which announces
+// it in both rooms and tells players in the raiding room to switch over.
+type RaidService struct {
+	config        *config.Config
+	streamService *StreamService
+	httpClient    *http.Client
+}
+
+type StartRaidRequest struct {
+	TargetStreamID string `json:"target_stream_id" binding:"required"`
+}
+
+func NewRaidService(cfg *config.Config, streamService *StreamService) *RaidService {
+	return &RaidService{
+		config:        cfg,
+		streamService: streamService,
+		httpClient:    chatServiceClient(cfg),
+	}
+}
+
+// StartRaid handles POST /api/v1/streams/:id/raid. A stream raids once, the target has to be
+// a different listed live stream.
+func (rs *RaidService) StartRaid(c *gin.Context) {
+	var req StartRaidRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	source, err := rs.streamService.GetStreamByIDInternal(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Stream not found"})
+		return
+	}
+	if !authorizeOwner(c, source.UserID) {
+		return
+	}
+	if source.Status != models.StreamStatusLive {
+		c.JSON(http.StatusConflict, gin.H{"error": "Only live streams can raid"})
+		return
+	}
+	if source.Raid != nil {
+		c.JSON(http.StatusConflict, gin.H{"error": "Stream has already raided", "raid": source.Raid})
+		return
+	}
+	if req.TargetStreamID == source.ID {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "A stream can't raid itself"})
+		return
+	}
+
+	target, err := rs.streamService.GetStreamByIDInternal(req.TargetStreamID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Target stream not found"})
+		return
+	}
+	// Raided viewers land on the target without a link or invite
+	if target.Status != models.StreamStatusLive || !target.Listed() || target.Blocked {
+		c.JSON(http.StatusConflict, gin.H{"error": "Target stream can't be raided"})
+		return
+	}
+
+	raid := &models.Raid{
+		ID:           generateRaidID(),
+		FromStreamID: source.ID,
+		FromUserID:   source.UserID,
+		ToStreamID:   target.ID,
+		ToUserID:     target.UserID,
+		ViewerCount:  source.ViewerCount,
+		CreatedAt:    time.Now().UTC(),
+	}
+
+	source.Raid = raid
+	source.UpdatedAt = time.Now()
+	if err := rs.streamService.UpdateStreamInternal(source); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not start raid"})
+		return
+	}
+
+	target.RaidsReceived++
+	target.RaidViewers += raid.ViewerCount
+	target.UpdatedAt = time.Now()
+	if err := rs.streamService.UpdateStreamInternal(target); err != nil {
+		slog.WarnContext(c.Request.Context(), "⚠️ Could not count raid on target stream", "raid_id", raid.ID, "stream_id", target.ID, "error", err)
+	}
+
+	ctx := c.Request.Context()
+	details := map[string]string{
+		"raid_id":      raid.ID,
+		"viewer_count": strconv.Itoa(raid.ViewerCount),
+	}
+	rs.streamService.Audit(ctx, source.ID, models.AuditRaidSent, withDetail(details, "to_stream_id", target.ID))
+	rs.streamService.Audit(ctx, target.ID, models.AuditRaidReceived, withDetail(details, "from_stream_id", source.ID))
+
+	event := map[string]interface{}{
+		"raid_id":        raid.ID,
+		"from_stream_id": raid.FromStreamID,
+		"from_user_id":   raid.FromUserID,
+		"from_title":     source.Title,
+		"to_stream_id":   raid.ToStreamID,
+		"to_user_id":     raid.ToUserID,
+		"to_title":       target.Title,
+		"viewer_count":   raid.ViewerCount,
+	}
+	if err := rs.streamService.PublishEvent("raid", event); err != nil {
+		slog.WarnContext(ctx, "⚠️ Could not publish raid event", "raid_id", raid.ID, "error", err)
+	}
+
+	if err := rs.announce(ctx, raid, source, target); err != nil {
+		slog.WarnContext(ctx, "⚠️ Could not announce raid in chat", "raid_id", raid.ID, "error", err)
+	}
+
+	slog.InfoContext(ctx, "🚀 Raid started", "raid_id", raid.ID, "from_stream_id", source.ID, "to_stream_id", target.ID, "viewers", raid.ViewerCount)
+	c.JSON(http.StatusCreated, raid)
+}
+
+// announce posts the raid to the chat service. A stream's chat room shares its ID. Over NATS
+// the chat service consumes the raid event instead.
+func (rs *RaidService) announce(ctx context.Context, raid *models.Raid, source, target *models.Stream) error {
+	if rs.config.ChatServiceURL == "" || rs.config.EventBus == EventBusNATS {
+		return nil
+	}
+
+	body, err := json.Marshal(map[string]interface{}{
+		"raid_id":          raid.ID,
+		"from_chatroom_id": source.ID,
+		"from_user_id":     strconv.FormatInt(source.UserID, 10),
+		"from_title":       source.Title,
+		"to_stream_id":     target.ID,
+		"to_chatroom_id":   target.ID,
+		"to_user_id":       strconv.FormatInt(target.UserID, 10),
+		"to_title":         target.Title,
+		"viewer_count":     raid.ViewerCount,
+		"created_at":       raid.CreatedAt,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal raid: %w", err)
+	}
+
+	url := fmt.Sprintf("%s/streams/%s/raid", strings.TrimSuffix(rs.config.ChatServiceURL, "/"), source.ID)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to build raid request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := rs.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send raid: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("chat service rejected raid: status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// withDetail copies audit details with one more entry
+func withDetail(details map[string]string, key, value string) map[string]string {
+	copied := make(map[string]string, len(details)+1)
+	for k, v := range details {
+		copied[k] = v
+	}
+	copied[key] = value
+	return copied
+}
+
+func generateRaidID() string {
+	bytes := make([]byte, 8)
+	rand.Read(bytes)
+	return "raid_" + hex.EncodeToString(bytes)
+}
@@ -0,0 +1,237 @@
+// services/stream-management-service/internal/service/recording_service.go
+package service
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/services/stream-management-service/internal/config"
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/services/stream-management-service/internal/models"
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/services/stream-management-service/internal/repository"
+)
+
+// uploadQueueSize bounds how many recordings wait for an upload worker
+const uploadQueueSize = 100
+
+// RecordingService moves recordings to S3. Its upload workers take the recordings the media
+// server reports, and its retry worker fails recordings stuck pending or uploading and uploads
+// failed ones again, pointing their VOD at the upload.
+type RecordingService struct {
+	config        *config.Config
+	streamService *StreamService
+	vodService    *VODService
+	redisRepo     *repository.RedisRepository
+	uploads       chan *models.Stream
+}
+
+func NewRecordingService(cfg *config.Config, streamService *StreamService, vodService *VODService, redisRepo *repository.RedisRepository) *RecordingService {
+	return &RecordingService{
+		config:        cfg,
+		streamService: streamService,
+		vodService:    vodService,
+		redisRepo:     redisRepo,
+		uploads:       make(chan *models.Stream, uploadQueueSize),
+	}
+}
+
+// StartUploadWorkers starts the background workers that upload queued recordings
+func (rs *RecordingService) StartUploadWorkers(ctx context.Context) {
+	if rs.streamService.RecordingUploadsDisabled() {
+		return
+	}
+
+	workers := rs.config.RecordingUploadWorkers
+	if workers < 1 {
+		workers = 1
+	}
+
+	for i := 0; i < workers; i++ {
+		go func() {
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case stream := <-rs.uploads:
+					if _, err := rs.upload(ctx, stream); err != nil {
+						slog.ErrorContext(ctx, "❌ Recording upload failed", "stream_id", stream.ID, "error", err)
+					}
+				}
+			}
+		}()
+	}
+}
+
+// EnqueueUpload queues a recording left uploading by UpdateStreamRecording. When the queue is
+// full the recording is failed with a retry scheduled, for the retry worker to upload.
+func (rs *RecordingService) EnqueueUpload(ctx context.Context, stream *models.Stream) error {
+	if stream.RecordingStatus != models.RecordingStatusUploading {
+		return nil
+	}
+
+	select {
+	case rs.uploads <- stream:
+		return nil
+	default:
+		return rs.streamService.DeferRecording(ctx, stream, "upload queue is full")
+	}
+}
+
+// upload uploads a recording and points its VOD at the upload, reporting whether it made it
+// to S3
+func (rs *RecordingService) upload(ctx context.Context, stream *models.Stream) (bool, error) {
+	if err := rs.streamService.UploadRecording(ctx, stream); err != nil {
+		return false, err
+	}
+	if stream.RecordingStatus != models.RecordingStatusReady {
+		return false, nil
+	}
+
+	if err := rs.vodService.UseUploadedRecording(stream); err != nil {
+		slog.WarnContext(ctx, "⚠️ Could not point VOD at uploaded recording", "stream_id", stream.ID, "error", err)
+	}
+	return true, nil
+}
+
+// StartRetryWorker periodically expires stuck recordings and retries failed uploads
+func (rs *RecordingService) StartRetryWorker(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(rs.config.RecordingRetryInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				rs.retryRecordings(ctx)
+			}
+		}
+	}()
+}
+
+func (rs *RecordingService) retryRecordings(ctx context.Context) {
+	if rs.streamService.RecordingUploadsDisabled() {
+		return
+	}
+
+	claimed, err := rs.redisRepo.ClaimRecordingRetry(rs.config.RecordingRetryInterval)
+	if err != nil {
+		slog.WarnContext(ctx, "⚠️ Could not claim recording retry", "error", err)
+		return
+	}
+	if !claimed {
+		return
+	}
+
+	now := time.Now()
+	expired := rs.expireStuckRecordings(ctx, now)
+
+	failed, err := rs.streamService.GetStreamsByRecordingStatus(models.RecordingStatusFailed)
+	if err != nil {
+		slog.WarnContext(ctx, "⚠️ Could not get failed recordings", "error", err)
+		return
+	}
+
+	retried, uploaded := 0, 0
+	for _, stream := range failed {
+		if ctx.Err() != nil {
+			return
+		}
+		if stream.RecordingRetryAt == nil || stream.RecordingRetryAt.After(now) {
+			continue
+		}
+
+		retried++
+		ok, err := rs.upload(ctx, stream)
+		if err != nil {
+			slog.WarnContext(ctx, "⚠️ Could not retry recording upload", "stream_id", stream.ID, "error", err)
+			continue
+		}
+		if ok {
+			uploaded++
+		}
+	}
+
+	if expired > 0 || retried > 0 {
+		slog.InfoContext(ctx, "📹 Recording uploads retried", "expired", expired, "retried", retried, "uploaded", uploaded)
+	}
+}
+
+// expireStuckRecordings fails the recordings still pending or uploading RecordingTimeout after
+// the stream ended or the upload started, returning how many it failed
+func (rs *RecordingService) expireStuckRecordings(ctx context.Context, now time.Time) int {
+	cutoff := now.Add(-rs.config.RecordingTimeout)
+
+	expired := 0
+	for _, status := range []models.RecordingStatus{models.RecordingStatusPending, models.RecordingStatusUploading} {
+		streams, err := rs.streamService.GetStreamsByRecordingStatus(status)
+		if err != nil {
+			slog.WarnContext(ctx, "⚠️ Could not get recordings", "recording_status", status, "error", err)
+			continue
+		}
+
+		for _, stream := range streams {
+			since := stream.UpdatedAt
+			if status == models.RecordingStatusPending && stream.EndedAt != nil {
+				since = *stream.EndedAt
+			}
+			if since.After(cutoff) {
+				continue
+			}
+
+			if err := rs.streamService.ExpireRecording(ctx, stream); err != nil {
+				slog.WarnContext(ctx, "⚠️ Could not expire recording", "stream_id", stream.ID, "error", err)
+				continue
+			}
+			expired++
+		}
+	}
+	return expired
+}
+
+// ListFailedRecordings handles GET /api/v1/recordings/failed for admins, most recently failed
+// first. Recordings with a retry_at are still being retried, the others need a look.
+func (rs *RecordingService) ListFailedRecordings(c *gin.Context) {
+	limit, offset, ok := parseOffsetPagination(c)
+	if !ok {
+		return
+	}
+
+	streams, err := rs.streamService.GetStreamsByRecordingStatus(models.RecordingStatusFailed)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not get failed recordings"})
+		return
+	}
+	sort.Slice(streams, func(i, j int) bool {
+		return streams[i].UpdatedAt.After(streams[j].UpdatedAt)
+	})
+
+	recordings := make([]gin.H, 0, len(streams))
+	for _, stream := range streams {
+		recordings = append(recordings, gin.H{
+			"stream_id":      stream.ID,
+			"user_id":        stream.UserID,
+			"title":          stream.Title,
+			"ended_at":       stream.EndedAt,
+			"recording_path": stream.RecordingPath,
+			"recording_url":  stream.RecordingURL,
+			"attempts":       stream.RecordingAttempts,
+			"error":          stream.RecordingError,
+			"retry_at":       stream.RecordingRetryAt,
+			"failed_at":      stream.UpdatedAt,
+		})
+	}
+
+	page, nextCursor := paginate(len(recordings), limit, offset)
+	c.JSON(http.StatusOK, gin.H{
+		"recordings":  recordings[page.start:page.end],
+		"count":       page.end - page.start,
+		"total":       len(recordings),
+		"next_cursor": nextCursor,
+	})
+}
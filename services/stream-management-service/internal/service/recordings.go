@@ -0,0 +1,129 @@
+// services/stream-management-service/internal/service/recordings.go
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/services/stream-management-service/internal/models"
+)
+
+// UploadRecording uploads a stream's recording to S3 and stores the outcome. A failed upload
+// leaves the recording failed with its next attempt scheduled, an error is only returned when
+// the stream can't be saved.
+func (s *StreamService) UploadRecording(ctx context.Context, stream *models.Stream) error {
+	// Saved first, so an upload the replica never finishes is noticed and retried
+	stream.RecordingStatus = models.RecordingStatusUploading
+	stream.RecordingAttempts++
+	stream.RecordingRetryAt = nil
+	stream.UpdatedAt = time.Now()
+	if err := s.saveRecording(stream); err != nil {
+		return err
+	}
+
+	key := fmt.Sprintf("recordings/%s/%s", stream.ID, filepath.Base(stream.RecordingPath))
+	url, err := s.s3Client.UploadRecording(stream.RecordingPath, key)
+	now := time.Now()
+	if err != nil {
+		slog.WarnContext(ctx, "⚠️ Could not upload recording to S3, keeping local path", "stream_id", stream.ID, "attempt", stream.RecordingAttempts, "error", err)
+		s.failRecording(stream, err.Error(), now)
+		s.Audit(ctx, stream.ID, models.AuditRecordingFailed, map[string]string{
+			"error":    stream.RecordingError,
+			"attempts": strconv.Itoa(stream.RecordingAttempts),
+		})
+	} else {
+		stream.RecordingStatus = models.RecordingStatusReady
+		stream.RecordingURL = url
+		stream.RecordingError = ""
+		s.Audit(ctx, stream.ID, models.AuditRecordingUploaded, map[string]string{
+			"recording_url": url,
+			"attempts":      strconv.Itoa(stream.RecordingAttempts),
+		})
+	}
+
+	stream.UpdatedAt = now
+	return s.saveRecording(stream)
+}
+
+// DeferRecording fails a recording that couldn't be queued for upload, so the retry worker
+// uploads it later
+func (s *StreamService) DeferRecording(ctx context.Context, stream *models.Stream, reason string) error {
+	now := time.Now()
+	s.failRecording(stream, reason, now)
+	stream.UpdatedAt = now
+	if err := s.saveRecording(stream); err != nil {
+		return err
+	}
+
+	s.Audit(ctx, stream.ID, models.AuditRecordingFailed, map[string]string{
+		"error":    reason,
+		"attempts": strconv.Itoa(stream.RecordingAttempts),
+	})
+	return nil
+}
+
+// ExpireRecording fails a recording stuck pending or uploading, because the media server never
+// reported it or the replica uploading it went away. Only a reported recording can be retried.
+func (s *StreamService) ExpireRecording(ctx context.Context, stream *models.Stream) error {
+	reason := "recording was never reported by the media server"
+	if stream.RecordingStatus == models.RecordingStatusUploading {
+		reason = "upload did not finish"
+	}
+
+	now := time.Now()
+	s.failRecording(stream, reason, now)
+	stream.UpdatedAt = now
+	if err := s.saveRecording(stream); err != nil {
+		return err
+	}
+
+	s.Audit(ctx, stream.ID, models.AuditRecordingFailed, map[string]string{
+		"error":    reason,
+		"attempts": strconv.Itoa(stream.RecordingAttempts),
+	})
+	return nil
+}
+
+// GetStreamsByRecordingStatus returns the streams whose recording is in a status. Deleted
+// streams are included, their recording is still uploaded in case they're restored.
+func (s *StreamService) GetStreamsByRecordingStatus(status models.RecordingStatus) ([]*models.Stream, error) {
+	return s.dynamoRepo.GetStreamsByRecordingStatus(status, models.DeletedInclude)
+}
+
+// RecordingUploadsDisabled reports whether the startup preflight switched S3 uploads off
+func (s *StreamService) RecordingUploadsDisabled() bool {
+	return s.uploadsDisabled
+}
+
+// failRecording marks a recording failed and schedules another upload while attempts are left,
+// waiting twice as long after each one
+func (s *StreamService) failRecording(stream *models.Stream, reason string, now time.Time) {
+	stream.RecordingStatus = models.RecordingStatusFailed
+	stream.RecordingError = reason
+	stream.RecordingRetryAt = nil
+
+	if stream.RecordingPath == "" || stream.RecordingAttempts >= s.config.RecordingMaxAttempts {
+		return
+	}
+	delay := s.config.RecordingRetryInterval
+	for i := 1; i < stream.RecordingAttempts; i++ {
+		delay *= 2
+	}
+	retryAt := now.Add(delay)
+	stream.RecordingRetryAt = &retryAt
+}
+
+func (s *StreamService) saveRecording(stream *models.Stream) error {
+	if err := s.dynamoRepo.UpdateStream(stream); err != nil {
+		return fmt.Errorf("failed to update stream recording: %w", err)
+	}
+
+	streamJSON, _ := json.Marshal(stream)
+	s.redisRepo.SetStreamData(stream.ID, string(streamJSON), time.Hour)
+	return nil
+}
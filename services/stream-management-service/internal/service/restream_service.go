@@ -0,0 +1,344 @@
+// services/stream-management-service/internal/service/restream_service.go
+package service
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/services/stream-management-service/internal/config"
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/services/stream-management-service/internal/models"
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/services/stream-management-service/internal/repository"
+)
+
+// maxRestreamTargets bounds how many platforms a streamer can push to at once
+const maxRestreamTargets = 5
+
+type RestreamService struct {
+	config        *config.Config
+	dynamoRepo    *repository.DynamoDBRepository
+	streamService *StreamService
+}
+
+type RestreamTargetRequest struct {
+	Name      string `json:"name"`
+	Platform  string `json:"platform"`
+	URL       string `json:"url"`
+	StreamKey string `json:"stream_key"`
+	Enabled   *bool  `json:"enabled"`
+}
+
+// SRSForwardRequest is sent by SRS to its forward backend when a stream is published
+type SRSForwardRequest struct {
+	Action string `json:"action"`
+	App    string `json:"app"`
+	Stream string `json:"stream"` // Stream key
+	Param  string `json:"param"`
+}
+
+func NewRestreamService(cfg *config.Config, dynamoRepo *repository.DynamoDBRepository, streamService *StreamService) *RestreamService {
+	return &RestreamService{
+		config:        cfg,
+		dynamoRepo:    dynamoRepo,
+		streamService: streamService,
+	}
+}
+
+// CreateTarget handles POST /api/v1/users/:id/restream-targets
+func (rs *RestreamService) CreateTarget(c *gin.Context) {
+	userID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+	if !authorizeOwner(c, userID) {
+		return
+	}
+
+	var req RestreamTargetRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := validateRestreamTarget(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	existing, err := rs.dynamoRepo.GetRestreamTargetsByUser(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not load restream targets"})
+		return
+	}
+	if len(existing) >= maxRestreamTargets {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("at most %d restream targets are allowed", maxRestreamTargets)})
+		return
+	}
+
+	now := time.Now()
+	target := &models.RestreamTarget{
+		ID:        generateRestreamTargetID(),
+		UserID:    userID,
+		Name:      req.Name,
+		Platform:  req.Platform,
+		URL:       strings.TrimSuffix(req.URL, "/"),
+		StreamKey: req.StreamKey,
+		Enabled:   req.Enabled == nil || *req.Enabled,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+
+	if err := rs.dynamoRepo.SaveRestreamTarget(target); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not create restream target"})
+		return
+	}
+
+	slog.InfoContext(c.Request.Context(), "📡 Restream target added", "target_id", target.ID, "platform", target.Platform, "user_id", userID)
+	c.JSON(http.StatusCreated, target)
+}
+
+// ListTargets handles GET /api/v1/users/:id/restream-targets
+func (rs *RestreamService) ListTargets(c *gin.Context) {
+	userID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+	if !authorizeOwner(c, userID) {
+		return
+	}
+
+	targets, err := rs.dynamoRepo.GetRestreamTargetsByUser(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not load restream targets"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"user_id": userID,
+		"targets": targets,
+		"count":   len(targets),
+	})
+}
+
+// UpdateTarget handles PATCH /api/v1/restream-targets/:id
+func (rs *RestreamService) UpdateTarget(c *gin.Context) {
+	target, err := rs.dynamoRepo.GetRestreamTargetByID(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Restream target not found"})
+		return
+	}
+	if !authorizeOwner(c, target.UserID) {
+		return
+	}
+
+	var req RestreamTargetRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Only overwrite the fields that were sent
+	if req.Name == "" {
+		req.Name = target.Name
+	}
+	if req.Platform == "" {
+		req.Platform = target.Platform
+	}
+	if req.URL == "" {
+		req.URL = target.URL
+	}
+	if req.StreamKey == "" {
+		req.StreamKey = target.StreamKey
+	}
+	if err := validateRestreamTarget(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	target.Name = req.Name
+	target.Platform = req.Platform
+	target.URL = strings.TrimSuffix(req.URL, "/")
+	target.StreamKey = req.StreamKey
+	if req.Enabled != nil {
+		target.Enabled = *req.Enabled
+	}
+	target.UpdatedAt = time.Now()
+
+	if err := rs.dynamoRepo.SaveRestreamTarget(target); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not update restream target"})
+		return
+	}
+
+	c.JSON(http.StatusOK, target)
+}
+
+// DeleteTarget handles DELETE /api/v1/restream-targets/:id
+func (rs *RestreamService) DeleteTarget(c *gin.Context) {
+	target, err := rs.dynamoRepo.GetRestreamTargetByID(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Restream target not found"})
+		return
+	}
+	if !authorizeOwner(c, target.UserID) {
+		return
+	}
+
+	if err := rs.dynamoRepo.DeleteRestreamTarget(target.ID); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not delete restream target"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Restream target deleted"})
+}
+
+// ForwardTargets handles POST /rtmp/forward, the SRS forward backend. SRS calls it when a
+// stream is published and pushes the stream to every URL in the response.
+func (rs *RestreamService) ForwardTargets(c *gin.Context) {
+	var req SRSForwardRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"code": 1, "error": "Invalid request format"})
+		return
+	}
+
+	streamKey := strings.TrimPrefix(req.Stream, "/")
+	urls, err := rs.startRestreams(streamKey)
+	if err != nil {
+		// Never block the stream itself because restreaming failed
+		slog.WarnContext(c.Request.Context(), "⚠️ Could not start restreams", "stream_key", streamKey, "error", err)
+		urls = []string{}
+	}
+
+	// Viewers play the copy pushed to the playback vhost, which never sees the stream key
+	playbackURL, err := rs.playbackOriginURL(req.App, streamKey)
+	if err != nil {
+		slog.WarnContext(c.Request.Context(), "⚠️ Could not forward stream for playback", "stream_key", streamKey, "error", err)
+	} else {
+		urls = append([]string{playbackURL}, urls...)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code": 0,
+		"data": gin.H{
+			"urls": urls,
+		},
+	})
+}
+
+// startRestreams returns the push URLs of a streamer's enabled targets and records them as pushing
+func (rs *RestreamService) startRestreams(streamKey string) ([]string, error) {
+	session, err := rs.streamService.GetStreamSession(streamKey)
+	if err != nil {
+		return nil, fmt.Errorf("no session for stream key: %w", err)
+	}
+
+	targets, err := rs.dynamoRepo.GetRestreamTargetsByUser(session.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	urls := []string{}
+	statuses := []models.RestreamStatus{}
+	for _, target := range targets {
+		if !target.Enabled {
+			continue
+		}
+		urls = append(urls, target.URL+"/"+target.StreamKey)
+		statuses = append(statuses, models.RestreamStatus{
+			TargetID:  target.ID,
+			Name:      target.Name,
+			Platform:  target.Platform,
+			State:     models.RestreamStatePushing,
+			UpdatedAt: now,
+		})
+	}
+
+	// SRS may ask before the stream record exists, so the session carries the statuses until it does
+	session.Restreams = statuses
+	if err := rs.streamService.StoreStreamSession(streamKey, session); err != nil {
+		slog.Warn("⚠️ Could not store restreams in session", "stream_key", streamKey, "error", err)
+	}
+
+	if session.Started() {
+		if stream, err := rs.streamService.GetStreamByIDInternal(session.StreamID); err == nil {
+			stream.Restreams = statuses
+			stream.UpdatedAt = now
+			if err := rs.streamService.UpdateStreamInternal(stream); err != nil {
+				slog.Warn("⚠️ Could not update restreams of stream", "stream_id", stream.ID, "error", err)
+			}
+		}
+	}
+
+	if len(urls) > 0 {
+		slog.Info("📡 Restreaming to targets", "stream_key", streamKey, "targets", len(urls))
+	}
+	return urls, nil
+}
+
+// playbackOriginURL is where the media server republishes a stream under its playback ID
+func (rs *RestreamService) playbackOriginURL(app, streamKey string) (string, error) {
+	session, err := rs.streamService.GetStreamSession(streamKey)
+	if err != nil {
+		return "", fmt.Errorf("no session for stream key: %w", err)
+	}
+	origin, err := url.Parse(rs.config.PlaybackOriginURL)
+	if err != nil {
+		return "", fmt.Errorf("invalid playback origin: %w", err)
+	}
+	if app == "" {
+		app = "live"
+	}
+	origin.Path = path.Join("/", origin.Path, app, session.PlaybackID)
+	return origin.String(), nil
+}
+
+// stopRestreams marks every restream of a stream as stopped
+func stopRestreams(stream *models.Stream, now time.Time) {
+	for i := range stream.Restreams {
+		if stream.Restreams[i].State != models.RestreamStateStopped {
+			stream.Restreams[i].State = models.RestreamStateStopped
+			stream.Restreams[i].UpdatedAt = now
+		}
+	}
+}
+
+func validateRestreamTarget(req *RestreamTargetRequest) error {
+	if req.Name == "" {
+		return fmt.Errorf("name is required")
+	}
+
+	switch req.Platform {
+	case "youtube", "twitch", "custom":
+	case "":
+		req.Platform = "custom"
+	default:
+		return fmt.Errorf("platform must be youtube, twitch or custom")
+	}
+
+	u, err := url.Parse(req.URL)
+	if err != nil || u.Host == "" || (u.Scheme != "rtmp" && u.Scheme != "rtmps") {
+		return fmt.Errorf("url must be an rtmp:// or rtmps:// ingest URL")
+	}
+
+	if req.StreamKey == "" || strings.ContainsAny(req.StreamKey, "/?# ") {
+		return fmt.Errorf("stream_key is required and must not contain '/', '?', '#' or spaces")
+	}
+
+	return nil
+}
+
+func generateRestreamTargetID() string {
+	bytes := make([]byte, 8)
+	rand.Read(bytes)
+	return "rst_" + hex.EncodeToString(bytes)
+}
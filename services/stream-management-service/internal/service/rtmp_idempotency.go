@@ -0,0 +1,108 @@
+// services/stream-management-service/internal/service/rtmp_idempotency.go
+package service
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Lifecycle callbacks that are only handled once per publishing or viewer connection
+const (
+	callbackStarted  = "started"
+	callbackEnded    = "ended"
+	callbackRecorded = "recorded"
+	callbackPlay     = "play"
+)
+
+// callbackResult is the response to a callback, replayed when the media server retries it
+type callbackResult struct {
+	Status int             `json:"status"`
+	Body   json.RawMessage `json:"body"`
+}
+
+// callbackKey identifies one lifecycle callback of one publishing connection
+func callbackKey(streamKey, clientID, event string) string {
+	return strings.Join([]string{streamKey, clientID, event}, ":")
+}
+
+// beginCallback claims a lifecycle callback before it's handled. For a retry it answers with
+// the response to the original and returns false. The returned key is "" when the callback
+// can't be deduplicated, e.g. the media server didn't send its client ID.
+func (h *IngestHandler) beginCallback(c *gin.Context, streamKey, clientID, event string) (string, bool) {
+	ctx := c.Request.Context()
+	if clientID == "" || h.config.CallbackIdempotency <= 0 {
+		return "", true
+	}
+
+	key := callbackKey(streamKey, clientID, event)
+	claimed, err := h.streamService.redisRepo.ClaimCallback(key, h.config.CallbackIdempotency)
+	if err != nil {
+		// Handling it twice is better than refusing the broadcaster
+		slog.WarnContext(ctx, "⚠️ Could not claim RTMP callback, handling it without deduplication", "event", event, "error", err)
+		return "", true
+	}
+	if claimed {
+		return key, true
+	}
+
+	data, err := h.streamService.redisRepo.GetCallbackResult(key)
+	if err != nil {
+		slog.ErrorContext(ctx, "❌ Could not get response to retried RTMP callback", "event", event, "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not check callback"})
+		return "", false
+	}
+	if data == "" {
+		slog.InfoContext(ctx, "⏳ RTMP callback retried while still being handled", "event", event)
+		c.JSON(http.StatusConflict, gin.H{
+			"error": "Callback is already being handled",
+			"code":  "CALLBACK_IN_PROGRESS",
+		})
+		return "", false
+	}
+
+	var result callbackResult
+	if err := json.Unmarshal([]byte(data), &result); err != nil {
+		slog.WarnContext(ctx, "⚠️ Could not decode stored callback response, handling it again", "event", event, "error", err)
+		return key, true
+	}
+
+	slog.InfoContext(ctx, "🔁 Replaying response to retried RTMP callback", "event", event, "client_id", clientID)
+	c.Header("Idempotent-Replayed", "true")
+	c.Data(result.Status, "application/json; charset=utf-8", result.Body)
+	return "", false
+}
+
+// respondCallback answers a claimed callback and stores the response for retries
+func (h *IngestHandler) respondCallback(c *gin.Context, key string, status int, body gin.H) {
+	if key != "" {
+		result, err := json.Marshal(body)
+		if err == nil {
+			result, err = json.Marshal(callbackResult{Status: status, Body: result})
+		}
+		if err == nil {
+			err = h.streamService.redisRepo.SetCallbackResult(key, string(result), h.config.CallbackIdempotency)
+		}
+		if err != nil {
+			slog.WarnContext(c.Request.Context(), "⚠️ Could not store RTMP callback response", "error", err)
+			h.streamService.redisRepo.ReleaseCallback(key)
+		}
+	}
+
+	c.JSON(status, body)
+}
+
+// finishCallback releases a claimed callback that wasn't answered successfully, so the media
+// server's retry is handled again instead of waiting for a response that never comes
+func (h *IngestHandler) finishCallback(c *gin.Context, key string) {
+	if key == "" || (c.Writer.Written() && c.Writer.Status() < http.StatusBadRequest) {
+		return
+	}
+
+	if err := h.streamService.redisRepo.ReleaseCallback(key); err != nil {
+		slog.WarnContext(c.Request.Context(), "⚠️ Could not release RTMP callback", "error", err)
+	}
+}
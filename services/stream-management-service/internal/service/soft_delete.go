@@ -0,0 +1,315 @@
+// services/stream-management-service/internal/service/soft_delete.go
+package service
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/services/stream-management-service/internal/config"
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/services/stream-management-service/internal/models"
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/services/stream-management-service/internal/repository"
+)
+
+// SoftDeleteService deletes streams and VODs so they can still be restored, for support cases
+// and second thoughts. Deleted items are left out of lists and don't play, and are purged
+// for good once SoftDeleteRetention passed.
+type SoftDeleteService struct {
+	config        *config.Config
+	dynamoRepo    *repository.DynamoDBRepository
+	redisRepo     *repository.RedisRepository
+	streamService *StreamService
+	userData      *UserDataService
+}
+
+func NewSoftDeleteService(cfg *config.Config, dynamoRepo *repository.DynamoDBRepository, redisRepo *repository.RedisRepository, streamService *StreamService, userData *UserDataService) *SoftDeleteService {
+	return &SoftDeleteService{
+		config:        cfg,
+		dynamoRepo:    dynamoRepo,
+		redisRepo:     redisRepo,
+		streamService: streamService,
+		userData:      userData,
+	}
+}
+
+// DeleteStream handles DELETE /api/v1/streams/:id. The stream's VOD is deleted with it, and
+// restored with it too.
+func (sds *SoftDeleteService) DeleteStream(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	stream, err := sds.streamService.GetStreamByIDInternal(c.Param("id"))
+	if err != nil || stream.Deleted() {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Stream not found"})
+		return
+	}
+	if !authorizeOwner(c, stream.UserID) {
+		return
+	}
+	if stream.Status == models.StreamStatusLive || stream.Status == models.StreamStatusReconnecting {
+		c.JSON(http.StatusConflict, gin.H{"error": "End the stream before deleting it"})
+		return
+	}
+
+	vod, err := sds.dynamoRepo.GetVODByID("vod_" + stream.ID)
+	if err != nil || vod.Deleted() {
+		vod = nil
+	}
+	if vod != nil && vod.Premiere.Active() {
+		c.JSON(http.StatusConflict, gin.H{"error": "Cancel the premiere of the stream's VOD before deleting it"})
+		return
+	}
+
+	now := time.Now().UTC()
+	if vod != nil {
+		vod.DeletedAt = &now
+		vod.UpdatedAt = now
+		if err := sds.dynamoRepo.SaveVOD(vod); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not delete the stream's VOD"})
+			return
+		}
+	}
+
+	stream.DeletedAt = &now
+	stream.UpdatedAt = now
+	if err := sds.streamService.UpdateStreamInternal(stream); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not delete stream"})
+		return
+	}
+
+	details := map[string]string{}
+	if vod != nil {
+		details["vod_id"] = vod.ID
+	}
+	sds.streamService.Audit(ctx, stream.ID, models.AuditStreamDeleted, details)
+
+	slog.InfoContext(ctx, "🗑️ Stream deleted", "stream_id", stream.ID, "user_id", stream.UserID, "vod_deleted", vod != nil)
+	c.JSON(http.StatusOK, gin.H{
+		"stream":   stream,
+		"purge_at": now.Add(sds.config.SoftDeleteRetention),
+	})
+}
+
+// RestoreStream handles POST /api/v1/streams/:id/restore, until the stream is purged. A VOD
+// deleted along with the stream is restored too, one deleted on its own before isn't.
+func (sds *SoftDeleteService) RestoreStream(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	stream, err := sds.streamService.GetStreamByIDInternal(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Stream not found"})
+		return
+	}
+	if !authorizeOwner(c, stream.UserID) {
+		return
+	}
+	if !stream.Deleted() {
+		c.JSON(http.StatusConflict, gin.H{"error": "Stream is not deleted"})
+		return
+	}
+
+	now := time.Now().UTC()
+	vod, err := sds.dynamoRepo.GetVODByID("vod_" + stream.ID)
+	if err == nil && vod.Deleted() && vod.DeletedAt.Equal(*stream.DeletedAt) {
+		vod.DeletedAt = nil
+		vod.UpdatedAt = now
+		if err := sds.dynamoRepo.SaveVOD(vod); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not restore the stream's VOD"})
+			return
+		}
+	} else {
+		vod = nil
+	}
+
+	stream.DeletedAt = nil
+	stream.UpdatedAt = now
+	if err := sds.streamService.UpdateStreamInternal(stream); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not restore stream"})
+		return
+	}
+
+	details := map[string]string{}
+	if vod != nil {
+		details["vod_id"] = vod.ID
+	}
+	sds.streamService.Audit(ctx, stream.ID, models.AuditStreamRestored, details)
+
+	slog.InfoContext(ctx, "♻️ Stream restored", "stream_id", stream.ID, "user_id", stream.UserID, "vod_restored", vod != nil)
+	c.JSON(http.StatusOK, stream)
+}
+
+// DeleteVOD handles DELETE /api/v1/vods/:id
+func (sds *SoftDeleteService) DeleteVOD(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	vod, err := sds.dynamoRepo.GetVODByID(c.Param("id"))
+	if err != nil || vod.Deleted() {
+		c.JSON(http.StatusNotFound, gin.H{"error": "VOD not found"})
+		return
+	}
+	if !authorizeOwner(c, vod.UserID) {
+		return
+	}
+	if vod.Premiere.Active() {
+		c.JSON(http.StatusConflict, gin.H{"error": "Cancel the premiere before deleting the VOD"})
+		return
+	}
+
+	now := time.Now().UTC()
+	vod.DeletedAt = &now
+	vod.UpdatedAt = now
+	if err := sds.dynamoRepo.SaveVOD(vod); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not delete VOD"})
+		return
+	}
+	sds.streamService.Audit(ctx, vod.StreamID, models.AuditVODDeleted, map[string]string{"vod_id": vod.ID})
+
+	slog.InfoContext(ctx, "🗑️ VOD deleted", "vod_id", vod.ID, "user_id", vod.UserID)
+	c.JSON(http.StatusOK, gin.H{
+		"vod":      vod,
+		"purge_at": now.Add(sds.config.SoftDeleteRetention),
+	})
+}
+
+// RestoreVOD handles POST /api/v1/vods/:id/restore. A VOD whose stream is deleted plays from
+// the stream's recording, so the stream has to be restored instead.
+func (sds *SoftDeleteService) RestoreVOD(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	vod, err := sds.dynamoRepo.GetVODByID(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "VOD not found"})
+		return
+	}
+	if !authorizeOwner(c, vod.UserID) {
+		return
+	}
+	if !vod.Deleted() {
+		c.JSON(http.StatusConflict, gin.H{"error": "VOD is not deleted"})
+		return
+	}
+	if stream, err := sds.streamService.GetStreamByIDInternal(vod.StreamID); err == nil && stream.Deleted() {
+		c.JSON(http.StatusConflict, gin.H{"error": "The VOD's stream is deleted, restore the stream instead"})
+		return
+	}
+
+	vod.DeletedAt = nil
+	vod.UpdatedAt = time.Now().UTC()
+	if err := sds.dynamoRepo.SaveVOD(vod); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not restore VOD"})
+		return
+	}
+	sds.streamService.Audit(ctx, vod.StreamID, models.AuditVODRestored, map[string]string{"vod_id": vod.ID})
+
+	slog.InfoContext(ctx, "♻️ VOD restored", "vod_id", vod.ID, "user_id", vod.UserID)
+	c.JSON(http.StatusOK, vod)
+}
+
+// ListUserStreams handles GET /api/v1/users/:id/streams for the owner, newest first. Deleted
+// streams are left out unless asked for with ?deleted=include or only, to restore them.
+func (s *StreamService) ListUserStreams(c *gin.Context) {
+	userID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+	if !authorizeOwner(c, userID) {
+		return
+	}
+	deleted, ok := parseDeletedFilter(c)
+	if !ok {
+		return
+	}
+	limit, offset, ok := parseOffsetPagination(c)
+	if !ok {
+		return
+	}
+
+	streams, err := s.dynamoRepo.GetAllStreamsByUser(userID, deleted)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not load streams"})
+		return
+	}
+	sort.Slice(streams, func(i, j int) bool { return streams[i].CreatedAt.After(streams[j].CreatedAt) })
+
+	page, nextCursor := paginate(len(streams), limit, offset)
+	c.JSON(http.StatusOK, gin.H{
+		"user_id":     userID,
+		"streams":     streams[page.start:page.end],
+		"count":       page.end - page.start,
+		"total":       len(streams),
+		"next_cursor": nextCursor,
+	})
+}
+
+// PurgeDeleted deletes for good the streams and VODs deleted longer than SoftDeleteRetention
+// ago. The job scheduler runs it on one replica at a time.
+func (sds *SoftDeleteService) PurgeDeleted(ctx context.Context) error {
+	cutoff := time.Now().Add(-sds.config.SoftDeleteRetention)
+	purgedVODs, purgedStreams := 0, 0
+
+	vods, err := sds.dynamoRepo.GetDeletedVODs()
+	if err != nil {
+		slog.WarnContext(ctx, "⚠️ Could not get deleted VODs", "error", err)
+	}
+	for _, vod := range vods {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if vod.DeletedAt.After(cutoff) {
+			continue
+		}
+		if err := sds.userData.purgeVOD(vod); err != nil {
+			slog.WarnContext(ctx, "⚠️ Could not purge VOD", "vod_id", vod.ID, "error", err)
+			continue
+		}
+		purgedVODs++
+	}
+
+	streams, err := sds.dynamoRepo.GetDeletedStreams()
+	if err != nil {
+		slog.WarnContext(ctx, "⚠️ Could not get deleted streams", "error", err)
+	}
+	for _, stream := range streams {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if stream.DeletedAt.After(cutoff) {
+			continue
+		}
+		if err := sds.userData.purgeStream(ctx, stream); err != nil {
+			slog.WarnContext(ctx, "⚠️ Could not purge stream", "stream_id", stream.ID, "error", err)
+			continue
+		}
+		purgedStreams++
+	}
+
+	if purgedVODs > 0 || purgedStreams > 0 {
+		slog.InfoContext(ctx, "🗑️ Deleted streams and VODs purged", "streams", purgedStreams, "vods", purgedVODs)
+	}
+	return nil
+}
+
+// parseDeletedFilter reads the deleted query parameter of a list, excluding deleted items by
+// default
+func parseDeletedFilter(c *gin.Context) (models.DeletedFilter, bool) {
+	deleted := models.DeletedFilter(c.DefaultQuery("deleted", string(models.DeletedExclude)))
+	if !deleted.Valid() {
+		respondError(c, http.StatusBadRequest, ErrCodeInvalidRequest, "deleted must be exclude, include or only")
+		return "", false
+	}
+	return deleted, true
+}
+
+// authorizeSignedInOwner is authorizeOwner for routes anonymous viewers may call otherwise
+func authorizeSignedInOwner(c *gin.Context, ownerID int64) bool {
+	if _, ok := c.Get(apiKeyContextKey); !ok && ViewerID(c) == 0 {
+		respondError(c, http.StatusUnauthorized, ErrCodeUnauthorized, "Authentication required")
+		return false
+	}
+	return authorizeOwner(c, ownerID)
+}
@@ -0,0 +1,453 @@
+// services/stream-management-service/internal/service/squad_service.go
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/services/stream-management-service/internal/config"
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/services/stream-management-service/internal/models"
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/services/stream-management-service/internal/repository"
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/shared/go/pkg/apperrors"
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/shared/go/pkg/identity"
+)
+
+// squadTTL bounds how long a squad outlives its last change
+const squadTTL = 12 * time.Hour
+
+var (
+	errSquadFull  = apperrors.Newf(apperrors.CodeConflict, "a squad can have at most %d streams", models.MaxSquadSize)
+	errNotInSquad = apperrors.InvalidRequest("stream is not in this squad")
+)
+
+type SquadService struct {
+	config        *config.Config
+	redisRepo     *repository.RedisRepository
+	streamService *StreamService
+	httpClient    *http.Client
+}
+
+type CreateSquadRequest struct {
+	StreamID   string `json:"stream_id" binding:"required"`
+	Title      string `json:"title"`
+	ChatMode   string `json:"chat_mode"`   // merged or side_by_side
+	ChatroomID string `json:"chatroom_id"` // defaults to the stream ID
+}
+
+type SquadMemberRequest struct {
+	StreamID   string `json:"stream_id" binding:"required"`
+	ChatroomID string `json:"chatroom_id"`
+}
+
+func NewSquadService(cfg *config.Config, redisRepo *repository.RedisRepository, streamService *StreamService) *SquadService {
+	return &SquadService{
+		config:        cfg,
+		redisRepo:     redisRepo,
+		streamService: streamService,
+		httpClient:    chatServiceClient(cfg),
+	}
+}
+
+// CreateSquad handles POST /api/v1/squads
+func (ss *SquadService) CreateSquad(c *gin.Context) {
+	var req CreateSquadRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	chatMode := models.SquadChatMode(req.ChatMode)
+	switch chatMode {
+	case models.SquadChatMerged, models.SquadChatSideBySide:
+	case "":
+		chatMode = models.SquadChatSideBySide
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "chat_mode must be merged or side_by_side"})
+		return
+	}
+
+	member, err := ss.liveMember(req.StreamID, req.ChatroomID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if !authorizeOwner(c, member.UserID) {
+		return
+	}
+
+	now := time.Now()
+	squad := &models.Squad{
+		ID:            generateSquadID(),
+		Title:         req.Title,
+		OwnerStreamID: member.StreamID,
+		ChatMode:      chatMode,
+		Members:       []models.SquadMember{*member},
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+
+	claimed, err := ss.redisRepo.ClaimStreamSquad(member.StreamID, squad.ID, squadTTL)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not create squad"})
+		return
+	}
+	if !claimed {
+		c.JSON(http.StatusConflict, gin.H{"error": "Stream is already in a squad"})
+		return
+	}
+
+	data, _ := json.Marshal(squad)
+	if err := ss.redisRepo.CreateSquad(squad.ID, string(data), squadTTL); err != nil {
+		ss.redisRepo.ReleaseStreamSquad(member.StreamID)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not create squad"})
+		return
+	}
+
+	ss.syncChatRoute(c.Request.Context(), squad)
+
+	slog.InfoContext(c.Request.Context(), "👥 Squad created", "squad_id", squad.ID, "stream_id", member.StreamID)
+	c.JSON(http.StatusCreated, squad)
+}
+
+// GetSquad handles GET /api/v1/squads/:id
+func (ss *SquadService) GetSquad(c *gin.Context) {
+	squad, err := ss.getSquad(c.Param("id"))
+	if err != nil {
+		ss.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, squad)
+}
+
+// JoinSquad handles POST /api/v1/squads/:id/join
+func (ss *SquadService) JoinSquad(c *gin.Context) {
+	squadID := c.Param("id")
+
+	var req SquadMemberRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	member, err := ss.liveMember(req.StreamID, req.ChatroomID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if !authorizeOwner(c, member.UserID) {
+		return
+	}
+
+	claimed, err := ss.redisRepo.ClaimStreamSquad(member.StreamID, squadID, squadTTL)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not join squad"})
+		return
+	}
+	if !claimed {
+		c.JSON(http.StatusConflict, gin.H{"error": "Stream is already in a squad"})
+		return
+	}
+
+	squad, err := ss.updateSquad(squadID, func(squad *models.Squad) error {
+		if len(squad.Members) >= models.MaxSquadSize {
+			return errSquadFull
+		}
+		squad.Members = append(squad.Members, *member)
+		return nil
+	})
+	if err != nil {
+		ss.redisRepo.ReleaseStreamSquad(member.StreamID)
+		ss.respondError(c, err)
+		return
+	}
+
+	ss.syncChatRoute(c.Request.Context(), squad)
+
+	slog.InfoContext(c.Request.Context(), "👥 Stream joined squad", "squad_id", squad.ID, "stream_id", member.StreamID, "members", len(squad.Members))
+	c.JSON(http.StatusOK, squad)
+}
+
+// LeaveSquad handles POST /api/v1/squads/:id/leave. Streams leave on their own or are removed
+// by the squad owner, the squad is disbanded when its last stream leaves.
+func (ss *SquadService) LeaveSquad(c *gin.Context) {
+	var req SquadMemberRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	current, err := ss.getSquad(c.Param("id"))
+	if err != nil {
+		ss.respondError(c, err)
+		return
+	}
+	leaving := current.Member(req.StreamID)
+	if leaving == nil {
+		ss.respondError(c, errNotInSquad)
+		return
+	}
+	if owner := current.Member(current.OwnerStreamID); owner == nil || principalID(c) != owner.UserID {
+		if !authorizeOwner(c, leaving.UserID) {
+			return
+		}
+	}
+
+	squad, err := ss.removeMembers(c.Request.Context(), c.Param("id"), map[string]bool{req.StreamID: true})
+	if err != nil {
+		ss.respondError(c, err)
+		return
+	}
+
+	if squad == nil {
+		c.JSON(http.StatusOK, gin.H{"message": "Squad disbanded"})
+		return
+	}
+	c.JSON(http.StatusOK, squad)
+}
+
+// GetSquadPlayback handles GET /api/v1/squads/:id/playback, the combined playback info for players
+func (ss *SquadService) GetSquadPlayback(c *gin.Context) {
+	squad, err := ss.getSquad(c.Param("id"))
+	if err != nil {
+		ss.respondError(c, err)
+		return
+	}
+
+	playback := &models.SquadPlayback{
+		SquadID:  squad.ID,
+		Title:    squad.Title,
+		ChatMode: squad.ChatMode,
+		Streams:  []models.SquadPlaybackStream{},
+	}
+
+	ended := map[string]bool{}
+	for _, member := range squad.Members {
+		stream, err := ss.streamService.GetStreamByIDInternal(member.StreamID)
+		if err != nil || !isLive(stream) {
+			ended[member.StreamID] = true
+			continue
+		}
+		// Taken down and private streams stay in the squad in case that changes, private
+		// streams are only played through playback authorization
+		if stream.Blocked || stream.EffectiveVisibility() == models.StreamVisibilityPrivate {
+			continue
+		}
+
+		playback.Streams = append(playback.Streams, models.SquadPlaybackStream{
+			StreamID:   stream.ID,
+			UserID:     stream.UserID,
+			Title:      stream.Title,
+			Status:     stream.Status,
+			HLSURL:     ss.hlsURL(stream),
+			ChatroomID: member.ChatroomID,
+			IsOwner:    member.StreamID == squad.OwnerStreamID,
+		})
+	}
+
+	// Streams that ended since they joined drop out of the squad
+	if len(ended) > 0 {
+		if _, err := ss.removeMembers(c.Request.Context(), squad.ID, ended); err != nil && !errors.Is(err, repository.ErrSquadNotFound) {
+			slog.WarnContext(c.Request.Context(), "⚠️ Could not remove ended streams from squad", "squad_id", squad.ID, "error", err)
+		}
+	}
+
+	c.JSON(http.StatusOK, playback)
+}
+
+// liveMember builds the squad member for a stream, which must be live
+func (ss *SquadService) liveMember(streamID, chatroomID string) (*models.SquadMember, error) {
+	stream, err := ss.streamService.GetStreamByIDInternal(streamID)
+	if err != nil {
+		return nil, fmt.Errorf("stream not found")
+	}
+	if !isLive(stream) {
+		return nil, fmt.Errorf("only live streams can be in a squad")
+	}
+
+	if chatroomID == "" {
+		chatroomID = stream.ID
+	}
+
+	return &models.SquadMember{
+		StreamID:   stream.ID,
+		UserID:     stream.UserID,
+		ChatroomID: chatroomID,
+		JoinedAt:   time.Now(),
+	}, nil
+}
+
+// removeMembers takes streams out of a squad and returns what's left, or nil if it was disbanded
+func (ss *SquadService) removeMembers(ctx context.Context, squadID string, streamIDs map[string]bool) (*models.Squad, error) {
+	var removed []string
+	squad, err := ss.updateSquad(squadID, func(squad *models.Squad) error {
+		removed = removed[:0]
+		remaining := squad.Members[:0]
+		for _, member := range squad.Members {
+			if streamIDs[member.StreamID] {
+				removed = append(removed, member.StreamID)
+				continue
+			}
+			remaining = append(remaining, member)
+		}
+		if len(removed) == 0 {
+			return errNotInSquad
+		}
+
+		squad.Members = remaining
+		if len(remaining) > 0 && streamIDs[squad.OwnerStreamID] {
+			squad.OwnerStreamID = remaining[0].StreamID
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, streamID := range removed {
+		if err := ss.redisRepo.ReleaseStreamSquad(streamID); err != nil {
+			slog.Warn("⚠️ Could not release stream from squad", "squad_id", squadID, "stream_id", streamID, "error", err)
+		}
+	}
+
+	if len(squad.Members) == 0 {
+		ss.deleteChatRoute(ctx, squadID)
+		slog.Info("👥 Squad disbanded", "squad_id", squadID)
+		return nil, nil
+	}
+
+	ss.syncChatRoute(ctx, squad)
+	return squad, nil
+}
+
+// updateSquad applies change to the stored squad. A squad left without members is deleted.
+func (ss *SquadService) updateSquad(squadID string, change func(*models.Squad) error) (*models.Squad, error) {
+	var squad *models.Squad
+	err := ss.redisRepo.UpdateSquad(squadID, squadTTL, func(data string) (string, error) {
+		squad = &models.Squad{}
+		if err := json.Unmarshal([]byte(data), squad); err != nil {
+			return "", fmt.Errorf("failed to unmarshal squad: %w", err)
+		}
+		if err := change(squad); err != nil {
+			return "", err
+		}
+		if len(squad.Members) == 0 {
+			return "", nil
+		}
+
+		squad.UpdatedAt = time.Now()
+		updated, err := json.Marshal(squad)
+		return string(updated), err
+	})
+	return squad, err
+}
+
+func (ss *SquadService) getSquad(squadID string) (*models.Squad, error) {
+	data, err := ss.redisRepo.GetSquad(squadID)
+	if err != nil {
+		return nil, err
+	}
+
+	var squad models.Squad
+	if err := json.Unmarshal([]byte(data), &squad); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal squad: %w", err)
+	}
+	return &squad, nil
+}
+
+func (ss *SquadService) respondError(c *gin.Context, err error) {
+	var appErr *apperrors.Error
+	switch {
+	case errors.Is(err, repository.ErrSquadNotFound):
+		err = apperrors.NotFound("Squad not found")
+	case !errors.As(err, &appErr):
+		err = apperrors.Internal(err, "Could not update squad")
+	}
+	respondAppError(c, err)
+}
+
+func (ss *SquadService) hlsURL(stream *models.Stream) string {
+	return liveHLSURL(ss.config.PlaybackBaseURL, stream)
+}
+
+// liveHLSURL follows the hls_m3u8_file layout of the media server, [app]/[stream].m3u8, where
+// the stream is republished under its playback ID. Streams from before playback IDs have no URL.
+func liveHLSURL(baseURL string, stream *models.Stream) string {
+	if stream.PlaybackID == "" {
+		return ""
+	}
+	app := stream.Metadata["app_name"]
+	if app == "" {
+		app = "live"
+	}
+	return fmt.Sprintf("%s/%s/%s.m3u8", strings.TrimSuffix(baseURL, "/"), app, stream.PlaybackID)
+}
+
+// syncChatRoute tells the chat service how to route messages between the squad's rooms
+func (ss *SquadService) syncChatRoute(ctx context.Context, squad *models.Squad) {
+	body, _ := json.Marshal(gin.H{
+		"mode":         squad.ChatMode,
+		"chatroom_ids": squad.ChatroomIDs(),
+	})
+	ss.callChatService(ctx, http.MethodPut, squad.ID, body)
+}
+
+func (ss *SquadService) deleteChatRoute(ctx context.Context, squadID string) {
+	ss.callChatService(ctx, http.MethodDelete, squadID, nil)
+}
+
+// callChatService never fails a squad change; chat falls back to per stream rooms
+func (ss *SquadService) callChatService(ctx context.Context, method, squadID string, body []byte) {
+	if ss.config.ChatServiceURL == "" {
+		return
+	}
+
+	url := fmt.Sprintf("%s/squads/%s/route", strings.TrimSuffix(ss.config.ChatServiceURL, "/"), squadID)
+	req, err := http.NewRequestWithContext(ctx, method, url, bytes.NewReader(body))
+	if err != nil {
+		slog.Warn("⚠️ Could not build chat route request", "squad_id", squadID, "error", err)
+		return
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := ss.httpClient.Do(req)
+	if err != nil {
+		slog.Warn("⚠️ Could not update chat route of squad", "squad_id", squadID, "error", err)
+		return
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		slog.Warn("⚠️ Chat service rejected route of squad", "squad_id", squadID, "status", resp.StatusCode)
+	}
+}
+
+// chatServiceClient calls the chat service under its call policy, passing along the user and
+// request the call is made for. Calls are signed as this service, the chat service only takes
+// pushes into its rooms from platform services.
+func chatServiceClient(cfg *config.Config) *http.Client {
+	identities := identity.NewPropagator(cfg.IdentitySecret)
+	transport := cfg.Caller(config.DependencyChatService).Transport(nil)
+	return &http.Client{Transport: identities.ServiceTransport("stream-management-service", identities.Transport(transport))}
+}
+
+func isLive(stream *models.Stream) bool {
+	return stream.Status == models.StreamStatusLive || stream.Status == models.StreamStatusReconnecting
+}
+
+func generateSquadID() string {
+	bytes := make([]byte, 8)
+	rand.Read(bytes)
+	return "sqd_" + hex.EncodeToString(bytes)
+}
@@ -0,0 +1,112 @@
+// services/stream-management-service/internal/service/srt_ingest.go
+package service
+
+import (
+	"strings"
+	"time"
+
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/services/stream-management-service/internal/models"
+)
+
+// srtAccessControlPrefix starts a streamid in the SRT access control syntax, e.g.
+// #!::r=live/KEY,m=publish,u=alice
+const srtAccessControlPrefix = "#!::"
+
+// IngestParams are the fields SRT media servers add to their callbacks. RTMP callbacks leave
+// them empty.
+type IngestParams struct {
+	Protocol    string `json:"protocol" form:"protocol"` // rtmp, srt or webrtc, guessed from streamid when empty
+	SRTStreamID string `json:"streamid" form:"streamid"` // SRT streamid the publisher connected with
+	SRTLatency  int    `json:"latency" form:"latency"`   // Receiver latency the publisher asked for, in ms
+	SRTMode     string `json:"mode" form:"mode"`         // publish or request, when not in the streamid
+}
+
+// srtStreamID is a parsed SRT streamid
+type srtStreamID struct {
+	Resource string // app/stream path, the stream key is its last segment
+	Mode     string // publish or request, empty when the publisher didn't say
+	User     string
+}
+
+// protocol returns the ingest protocol of a callback
+func (p IngestParams) protocol() models.IngestProtocol {
+	switch strings.ToLower(p.Protocol) {
+	case string(models.IngestProtocolSRT):
+		return models.IngestProtocolSRT
+	case string(models.IngestProtocolRTMP):
+		return models.IngestProtocolRTMP
+	case string(models.IngestProtocolWebRTC):
+		return models.IngestProtocolWebRTC
+	}
+	if p.SRTStreamID != "" {
+		return models.IngestProtocolSRT
+	}
+	return models.IngestProtocolRTMP
+}
+
+// parseSRTStreamID understands the access control syntax (#!::r=live/KEY,m=publish) as well
+// as a plain live/KEY path
+func parseSRTStreamID(streamID string) srtStreamID {
+	streamID = strings.TrimSpace(streamID)
+	if !strings.HasPrefix(streamID, srtAccessControlPrefix) {
+		return srtStreamID{Resource: streamID}
+	}
+
+	var parsed srtStreamID
+	for _, pair := range strings.Split(strings.TrimPrefix(streamID, srtAccessControlPrefix), ",") {
+		key, value, ok := strings.Cut(pair, "=")
+		if !ok {
+			continue
+		}
+		switch strings.TrimSpace(key) {
+		case "r":
+			parsed.Resource = strings.TrimSpace(value)
+		case "m":
+			parsed.Mode = strings.ToLower(strings.TrimSpace(value))
+		case "u":
+			parsed.User = strings.TrimSpace(value)
+		}
+	}
+	return parsed
+}
+
+// ingestName returns the stream name of a callback: the SRT streamid for SRT publishers, the
+// RTMP name otherwise. extractStreamKey takes the key out of either.
+func (p IngestParams) ingestName(name string) string {
+	if p.SRTStreamID != "" && p.protocol() == models.IngestProtocolSRT {
+		return p.SRTStreamID
+	}
+	return name
+}
+
+// srtMode returns whether the SRT peer publishes or plays, publish when it didn't say since
+// most encoders leave it out
+func (p IngestParams) srtMode(name string) string {
+	mode := parseSRTStreamID(p.SRTStreamID).Mode
+	if mode == "" && strings.HasPrefix(strings.TrimSpace(name), srtAccessControlPrefix) {
+		mode = parseSRTStreamID(name).Mode
+	}
+	if mode == "" {
+		mode = strings.ToLower(p.SRTMode)
+	}
+	if mode == "" {
+		mode = "publish"
+	}
+	return mode
+}
+
+// srtLatency clamps the latency a publisher asked for to the configured range
+func (h *IngestHandler) srtLatency(requestedMs int) time.Duration {
+	if requestedMs <= 0 {
+		return h.config.SRTDefaultLatency
+	}
+
+	latency := time.Duration(requestedMs) * time.Millisecond
+	if latency < h.config.SRTMinLatency {
+		return h.config.SRTMinLatency
+	}
+	if h.config.SRTMaxLatency > 0 && latency > h.config.SRTMaxLatency {
+		return h.config.SRTMaxLatency
+	}
+	return latency
+}
@@ -0,0 +1,254 @@
+// services/stream-management-service/internal/service/stream_analytics.go
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/services/stream-management-service/internal/models"
+)
+
+// maxChatPeriodHours is the longest period the chat service summarises
+const maxChatPeriodHours = 720
+
+type heartbeatRequest struct {
+	SessionID string `json:"session_id" binding:"max=128"` // identifies anonymous viewers
+}
+
+// ViewerHeartbeat handles POST /api/v1/streams/:id/heartbeat. Players send one while playing,
+// at least every VIEWER_HEARTBEAT_TIMEOUT. Signed in viewers are counted by user ID, anonymous
+// ones by the session ID their player generated.
+func (s *StreamService) ViewerHeartbeat(c *gin.Context) {
+	var req heartbeatRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	viewerID := ""
+	if id := ViewerID(c); id != 0 {
+		viewerID = "u:" + strconv.FormatInt(id, 10)
+	} else if req.SessionID != "" {
+		viewerID = "s:" + req.SessionID
+	} else {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "session_id is required for anonymous viewers"})
+		return
+	}
+
+	stream, err := s.GetStreamByIDInternal(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Stream not found"})
+		return
+	}
+	if stream.Status != models.StreamStatusLive {
+		c.JSON(http.StatusConflict, gin.H{"error": "Stream is not live"})
+		return
+	}
+
+	if err := s.redisRepo.RecordViewerHeartbeat(stream.ID, viewerID, time.Now(), s.config.ViewerDataTTL); err != nil {
+		slog.WarnContext(c.Request.Context(), "⚠️ Could not record viewer heartbeat", "stream_id", stream.ID, "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not record heartbeat"})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// StartViewerSampler samples the concurrent viewers of every live stream each sample interval
+// until ctx is done. Replicas share the samples, each interval is recorded once.
+func (s *StreamService) StartViewerSampler(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(s.config.ViewerSampleInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case now := <-ticker.C:
+				s.sampleViewers(now)
+			}
+		}
+	}()
+}
+
+func (s *StreamService) sampleViewers(now time.Time) {
+	streams, err := s.GetActiveStreamsInternal()
+	if err != nil {
+		slog.Warn("⚠️ Could not list live streams for viewer sampling", "error", err)
+		return
+	}
+
+	bucket := now.UnixNano() / int64(s.config.ViewerSampleInterval)
+	cutoff := now.Add(-s.config.ViewerHeartbeatTimeout)
+	for _, stream := range streams {
+		if _, _, err := s.redisRepo.SampleViewers(stream.ID, bucket, now, cutoff, s.config.ViewerDataTTL); err != nil {
+			slog.Warn("⚠️ Could not sample viewers", "stream_id", stream.ID, "error", err)
+		}
+	}
+}
+
+// GetStreamAnalytics handles GET /api/v1/streams/:id/analytics. Ended streams return the
+// summary stored when they ended, live streams one computed now.
+func (s *StreamService) GetStreamAnalytics(c *gin.Context) {
+	stream, err := s.GetStreamByIDInternal(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Stream not found"})
+		return
+	}
+	if !authorizeOwner(c, stream.UserID) {
+		return
+	}
+
+	if stream.Analytics != nil {
+		stream.Analytics.Incidents = s.streamIncidents(c.Request.Context(), stream)
+		c.JSON(http.StatusOK, stream.Analytics)
+		return
+	}
+	if stream.Status != models.StreamStatusLive && stream.Status != models.StreamStatusReconnecting {
+		c.JSON(http.StatusNotFound, gin.H{"error": "No analytics recorded for this stream"})
+		return
+	}
+
+	analytics, err := s.computeAnalytics(c.Request.Context(), stream, false)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not compute stream analytics"})
+		return
+	}
+	analytics.Incidents = s.streamIncidents(c.Request.Context(), stream)
+	c.JSON(http.StatusOK, analytics)
+}
+
+// streamIncidents returns the incidents that overlapped a stream, up to now while it is live
+func (s *StreamService) streamIncidents(ctx context.Context, stream *models.Stream) []*models.Incident {
+	from := startedAt(stream)
+	to := time.Now().UTC()
+	if stream.EndedAt != nil {
+		to = *stream.EndedAt
+	}
+	return s.incidentsBetween(ctx, from, to)
+}
+
+// FinalizeStreamAnalytics attaches the audience summary to a stream that is ending, before it
+// is saved. A stream without analytics is still ended.
+func (s *StreamService) FinalizeStreamAnalytics(stream *models.Stream) {
+	analytics, err := s.computeAnalytics(context.Background(), stream, true)
+	if err != nil {
+		slog.Warn("⚠️ Could not compute stream analytics", "stream_id", stream.ID, "error", err)
+		return
+	}
+	stream.Analytics = analytics
+}
+
+// computeAnalytics summarises a stream's viewer samples, follows, raids and chat. Follower
+// and chat counts are left at 0 when they can't be read.
+func (s *StreamService) computeAnalytics(ctx context.Context, stream *models.Stream, final bool) (*models.StreamAnalytics, error) {
+	samples, err := s.redisRepo.GetViewerSamples(stream.ID)
+	if err != nil {
+		return nil, err
+	}
+	unique, err := s.redisRepo.CountUniqueViewers(stream.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	analytics := &models.StreamAnalytics{
+		UniqueViewers: unique,
+		RaidsReceived: int64(stream.RaidsReceived),
+		RaidViewers:   int64(stream.RaidViewers),
+		Final:         final,
+		ComputedAt:    time.Now().UTC(),
+	}
+	if stream.Raid != nil {
+		analytics.RaidedViewers = int64(stream.Raid.ViewerCount)
+	}
+
+	var total int64
+	for _, sample := range samples {
+		total += sample
+		if sample > analytics.PeakViewers {
+			analytics.PeakViewers = sample
+		}
+	}
+	if len(samples) > 0 {
+		analytics.AverageViewers = float64(total) / float64(len(samples))
+	}
+
+	switch {
+	case final:
+		analytics.DurationSeconds = stream.Duration
+	case stream.StartedAt != nil:
+		analytics.DurationSeconds = int64(time.Since(*stream.StartedAt).Seconds())
+	}
+
+	if stream.StartedAt != nil {
+		end := time.Now()
+		if stream.EndedAt != nil {
+			end = *stream.EndedAt
+		}
+		if analytics.NewFollowers, err = s.redisRepo.CountFollowsBetween(stream.UserID, *stream.StartedAt, end); err != nil {
+			slog.WarnContext(ctx, "⚠️ Could not count new followers for analytics", "stream_id", stream.ID, "error", err)
+		}
+	}
+
+	if summary, err := s.GetChatSummary(ctx, stream); err != nil {
+		slog.WarnContext(ctx, "⚠️ Could not get chat activity for analytics", "stream_id", stream.ID, "error", err)
+	} else {
+		analytics.ChatMessages = summary.TotalMessages
+	}
+
+	return analytics, nil
+}
+
+// GetChatSummary asks the chat service for the activity of a stream's room. The chat service
+// counts by hour, so the summary covers every hour the stream was live in.
+func (s *StreamService) GetChatSummary(ctx context.Context, stream *models.Stream) (*models.ChatSummary, error) {
+	if s.config.ChatServiceURL == "" {
+		return nil, errors.New("chat service is not configured")
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, s.config.ChatServiceTimeout)
+	defer cancel()
+
+	hours := 1
+	if stream.StartedAt != nil {
+		now := time.Now().UTC().Truncate(time.Hour)
+		hours = int(now.Sub(stream.StartedAt.UTC().Truncate(time.Hour))/time.Hour) + 1
+	}
+	if hours > maxChatPeriodHours {
+		hours = maxChatPeriodHours
+	}
+
+	// A stream's chat room shares its ID
+	url := fmt.Sprintf("%s/chatrooms/%s/activity?period_hours=%d", strings.TrimSuffix(s.config.ChatServiceURL, "/"), stream.ID, hours)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build chat activity request: %w", err)
+	}
+
+	// Signed as this service, the chat service only shows a room's activity to its owner
+	resp, err := s.chatClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get chat activity: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("chat service returned status %d", resp.StatusCode)
+	}
+
+	var summary models.ChatSummary
+	if err := json.NewDecoder(resp.Body).Decode(&summary); err != nil {
+		return nil, fmt.Errorf("failed to decode chat activity: %w", err)
+	}
+	return &summary, nil
+}
@@ -0,0 +1,82 @@
+// services/stream-management-service/internal/service/stream_chapters.go
+package service
+
+import (
+	"log/slog"
+	"time"
+
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/services/stream-management-service/internal/models"
+)
+
+// minChapterLength folds changes made in quick succession, like fixing a typo in the new
+// title, into one chapter
+const minChapterLength = 60 // seconds
+
+// recordChapter starts a chapter at the current position of a live stream's recording. The
+// first change also records the chapter the stream started with.
+func (s *StreamService) recordChapter(stream *models.Stream, previousTitle, previousCategory string) {
+	if !isLive(stream) {
+		return
+	}
+
+	if len(stream.Chapters) == 0 {
+		stream.Chapters = []models.Chapter{{Start: 0, Title: previousTitle, Category: previousCategory}}
+	}
+
+	chapter := models.Chapter{
+		Start:    s.recordingOffset(stream),
+		Title:    stream.Title,
+		Category: stream.Category,
+	}
+
+	last := &stream.Chapters[len(stream.Chapters)-1]
+	if chapter.Start-last.Start < minChapterLength {
+		chapter.Start = last.Start
+		*last = chapter
+	} else {
+		stream.Chapters = append(stream.Chapters, chapter)
+	}
+
+	slog.Info("📑 Stream chapter recorded", "stream_id", stream.ID, "start", chapter.Start, "title", chapter.Title, "category", chapter.Category)
+}
+
+// recordingOffset returns how many seconds of a live stream have been recorded so far. Time
+// spent waiting for a reconnect isn't in the recording.
+func (s *StreamService) recordingOffset(stream *models.Stream) int64 {
+	now := time.Now().Unix()
+
+	if session, err := s.GetStreamSession(stream.StreamKey); err == nil {
+		if session.SegmentStartedAt > 0 {
+			offset := session.LiveSeconds
+			if stream.Status == models.StreamStatusLive {
+				offset += now - session.SegmentStartedAt
+			}
+			return offset
+		}
+	}
+
+	if stream.StartedAt == nil {
+		return 0
+	}
+	return now - stream.StartedAt.Unix()
+}
+
+// recordingChapters returns the chapters that fall inside a recording, or none when the
+// stream never changed
+func recordingChapters(chapters []models.Chapter, durationSec int64) []models.Chapter {
+	if len(chapters) < 2 {
+		return nil
+	}
+
+	inside := make([]models.Chapter, 0, len(chapters))
+	for _, chapter := range chapters {
+		if durationSec > 0 && chapter.Start >= durationSec {
+			break
+		}
+		inside = append(inside, chapter)
+	}
+	if len(inside) < 2 {
+		return nil
+	}
+	return inside
+}
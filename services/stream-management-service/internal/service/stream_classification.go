@@ -0,0 +1,186 @@
+// services/stream-management-service/internal/service/stream_classification.go
+package service
+
+import (
+	"log/slog"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/services/stream-management-service/internal/classifier"
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/services/stream-management-service/internal/contentfilter"
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/services/stream-management-service/internal/models"
+)
+
+// classificationHistorySize is how many previous streams of a channel feed the classifier
+const classificationHistorySize = 20
+
+type UpdateStreamDetailsRequest struct {
+	Title    *string   `json:"title"`
+	Category *string   `json:"category"`
+	Tags     *[]string `json:"tags"`
+	IsMature *bool     `json:"is_mature"`
+	Language *string   `json:"language"`
+}
+
+// ClassifyStream suggests a category and tags for a stream. In auto mode a confident
+// suggestion is applied, unless the broadcaster has picked them by hand.
+func (s *StreamService) ClassifyStream(stream *models.Stream) {
+	if s.config.ClassificationMode == "off" {
+		return
+	}
+
+	suggestion := s.classifier.Classify(classifier.Input{
+		Title:   stream.Title,
+		History: s.channelHistory(stream),
+	})
+	if suggestion.Category == "" && len(suggestion.Tags) == 0 {
+		return
+	}
+
+	stream.Classification = &models.Classification{
+		Category:   suggestion.Category,
+		Tags:       suggestion.Tags,
+		Confidence: suggestion.Confidence,
+		Reasons:    suggestion.Reasons,
+		UpdatedAt:  time.Now(),
+	}
+
+	if s.config.ClassificationMode == "auto" && !stream.ManualTags && suggestion.Confidence >= s.config.ClassificationMinConfidence {
+		stream.Category = suggestion.Category
+		stream.Tags = suggestion.Tags
+		stream.Classification.Applied = true
+		slog.Info("🏷️ Stream classified", "stream_id", stream.ID, "category", stream.Category, "tags", stream.Tags, "confidence", suggestion.Confidence)
+	}
+}
+
+// channelHistory returns the categories and tags of the channel's previous streams, newest first
+func (s *StreamService) channelHistory(stream *models.Stream) []classifier.HistoryEntry {
+	streams, err := s.dynamoRepo.GetStreamsByUser(stream.UserID, classificationHistorySize, models.DeletedExclude)
+	if err != nil {
+		slog.Warn("⚠️ Could not load channel history for classification", "user_id", stream.UserID, "error", err)
+		return nil
+	}
+
+	sort.Slice(streams, func(i, j int) bool {
+		return streams[i].CreatedAt.After(streams[j].CreatedAt)
+	})
+
+	history := make([]classifier.HistoryEntry, 0, len(streams))
+	for _, previous := range streams {
+		if previous.ID == stream.ID || previous.Category == "" {
+			continue
+		}
+		history = append(history, classifier.HistoryEntry{
+			Category: previous.Category,
+			Tags:     previous.Tags,
+		})
+	}
+
+	return history
+}
+
+// UpdateStreamDetails handles PATCH /api/v1/streams/:id. A category or tags sent by the
+// broadcaster override the classifier for the rest of the stream.
+func (s *StreamService) UpdateStreamDetails(c *gin.Context) {
+	var req UpdateStreamDetailsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	stream, err := s.GetStreamByIDInternal(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Stream not found"})
+		return
+	}
+	if !authorizeOwner(c, stream.UserID) {
+		return
+	}
+
+	previousTitle, previousCategory := stream.Title, stream.Category
+	titleChanged := req.Title != nil && *req.Title != stream.Title
+	if req.Title != nil && *req.Title == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "title must not be empty"})
+		return
+	}
+	// An unchanged title was filtered when it was set
+	var titleFilter *contentfilter.Result
+	if titleChanged {
+		titleFilter = s.FilterTitle(c.Request.Context(), *req.Title)
+		if titleFilter.Rejected {
+			respondContentRejected(c, "title", titleFilter)
+			return
+		}
+		stream.Title = titleFilter.Text
+	}
+
+	if req.Category != nil {
+		category := classifier.NormalizeTags([]string{*req.Category})
+		stream.Category = ""
+		if len(category) > 0 {
+			stream.Category = category[0]
+		}
+		stream.ManualTags = true
+	}
+	if req.Tags != nil {
+		stream.Tags = classifier.NormalizeTags(*req.Tags)
+		stream.ManualTags = true
+	}
+
+	if req.Language != nil {
+		language, err := NormalizeLanguage(*req.Language)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		stream.Language = language
+	}
+
+	if req.IsMature != nil && *req.IsMature != stream.IsMature {
+		stream.IsMature = *req.IsMature
+		slog.InfoContext(c.Request.Context(), "🔞 Stream mature flag changed", "stream_id", stream.ID, "is_mature", stream.IsMature)
+	}
+
+	if titleChanged {
+		s.ClassifyStream(stream)
+	}
+	if stream.Title != previousTitle || stream.Category != previousCategory {
+		s.recordChapter(stream, previousTitle, previousCategory)
+	}
+
+	stream.UpdatedAt = time.Now()
+	if err := s.UpdateStreamInternal(stream); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not update stream"})
+		return
+	}
+	s.Audit(c.Request.Context(), stream.ID, models.AuditDetailsUpdated, detailsChanges(req, stream))
+	s.QueueContentReview(c.Request.Context(), stream, "title", titleFilter)
+
+	c.JSON(http.StatusOK, stream)
+}
+
+// detailsChanges is what a details update set, for the audit log
+func detailsChanges(req UpdateStreamDetailsRequest, stream *models.Stream) map[string]string {
+	changes := make(map[string]string)
+	if req.Title != nil {
+		changes["title"] = stream.Title
+	}
+	if req.Category != nil {
+		changes["category"] = stream.Category
+	}
+	if req.Tags != nil {
+		changes["tags"] = strings.Join(stream.Tags, ",")
+	}
+	if req.Language != nil {
+		changes["language"] = stream.Language
+	}
+	if req.IsMature != nil {
+		changes["is_mature"] = strconv.FormatBool(stream.IsMature)
+	}
+	return changes
+}
@@ -0,0 +1,179 @@
+// services/stream-management-service/internal/service/stream_comparison.go
+package service
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/services/stream-management-service/internal/models"
+)
+
+const (
+	defaultComparisonDays = 7
+	maxComparisonDays     = 90
+)
+
+// periodTotals accumulates the streams of an analytics period
+type periodTotals struct {
+	period         *models.AnalyticsPeriod
+	seconds        int64
+	viewerSeconds  float64
+	chatMessages   int64
+	withCategories bool
+	categories     map[string]*periodTotals
+}
+
+func newPeriodTotals(from, to time.Time, withCategories bool) *periodTotals {
+	totals := &periodTotals{
+		period:         &models.AnalyticsPeriod{From: from, To: to},
+		withCategories: withCategories,
+	}
+	if withCategories {
+		totals.categories = make(map[string]*periodTotals)
+	}
+	return totals
+}
+
+// CompareStreams handles GET /api/v1/users/:id/stream-analytics?days=N. It compares the
+// streams that started in the last N days with the N days before, from the analytics stored
+// when each stream ended. Streams without analytics, e.g. still live, are left out.
+func (s *StreamService) CompareStreams(c *gin.Context) {
+	userID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+	if !authorizeOwner(c, userID) {
+		return
+	}
+
+	days := defaultComparisonDays
+	if value := c.Query("days"); value != "" {
+		days, err = strconv.Atoi(value)
+		if err != nil || days < 1 || days > maxComparisonDays {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("days must be between 1 and %d", maxComparisonDays)})
+			return
+		}
+	}
+
+	streams, err := s.dynamoRepo.GetAllStreamsByUser(userID, models.DeletedExclude)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not load streams"})
+		return
+	}
+
+	comparison := compareStreams(userID, days, streams, time.Now().UTC())
+	comparison.Incidents = s.incidentsBetween(c.Request.Context(), comparison.Previous.From, comparison.Current.To)
+	c.JSON(http.StatusOK, comparison)
+}
+
+func compareStreams(userID int64, days int, streams []*models.Stream, now time.Time) *models.StreamComparison {
+	length := time.Duration(days) * 24 * time.Hour
+	current := newPeriodTotals(now.Add(-length), now, true)
+	previous := newPeriodTotals(now.Add(-2*length), now.Add(-length), true)
+
+	comparison := &models.StreamComparison{
+		UserID:  userID,
+		Days:    days,
+		Streams: []models.StreamAnalyticsEntry{},
+	}
+
+	for _, stream := range streams {
+		if stream.Analytics == nil || !stream.Analytics.Final || stream.StartedAt == nil {
+			continue
+		}
+
+		switch startedAt := stream.StartedAt.UTC(); {
+		case !startedAt.Before(current.period.From) && startedAt.Before(current.period.To):
+			current.add(stream)
+			comparison.Streams = append(comparison.Streams, models.StreamAnalyticsEntry{
+				StreamID:              stream.ID,
+				Title:                 stream.Title,
+				Category:              stream.Category,
+				StartedAt:             startedAt,
+				DurationSeconds:       stream.Analytics.DurationSeconds,
+				AverageViewers:        stream.Analytics.AverageViewers,
+				PeakViewers:           stream.Analytics.PeakViewers,
+				ChatMessagesPerMinute: perMinute(stream.Analytics.ChatMessages, stream.Analytics.DurationSeconds),
+				NewFollowers:          stream.Analytics.NewFollowers,
+			})
+		case !startedAt.Before(previous.period.From) && startedAt.Before(previous.period.To):
+			previous.add(stream)
+		}
+	}
+
+	sort.Slice(comparison.Streams, func(i, j int) bool {
+		return comparison.Streams[i].StartedAt.After(comparison.Streams[j].StartedAt)
+	})
+
+	comparison.Current = current.finish()
+	comparison.Previous = previous.finish()
+	comparison.Change = make(map[string]float64)
+	relativeChange(comparison.Change, "average_viewers", comparison.Previous.AverageViewers, comparison.Current.AverageViewers)
+	relativeChange(comparison.Change, "peak_viewers", float64(comparison.Previous.PeakViewers), float64(comparison.Current.PeakViewers))
+	relativeChange(comparison.Change, "chat_messages_per_minute", comparison.Previous.ChatMessagesPerMinute, comparison.Current.ChatMessagesPerMinute)
+	relativeChange(comparison.Change, "new_followers", float64(comparison.Previous.NewFollowers), float64(comparison.Current.NewFollowers))
+	return comparison
+}
+
+func (t *periodTotals) add(stream *models.Stream) {
+	analytics := stream.Analytics
+
+	t.period.Streams++
+	t.seconds += analytics.DurationSeconds
+	t.viewerSeconds += analytics.AverageViewers * float64(analytics.DurationSeconds)
+	t.chatMessages += analytics.ChatMessages
+	t.period.NewFollowers += analytics.NewFollowers
+	if analytics.PeakViewers > t.period.PeakViewers {
+		t.period.PeakViewers = analytics.PeakViewers
+	}
+
+	if t.withCategories {
+		category := stream.Category
+		if category == "" {
+			category = "uncategorized"
+		}
+		totals := t.categories[category]
+		if totals == nil {
+			totals = newPeriodTotals(t.period.From, t.period.To, false)
+			t.categories[category] = totals
+		}
+		totals.add(stream)
+	}
+}
+
+func (t *periodTotals) finish() *models.AnalyticsPeriod {
+	t.period.HoursStreamed = float64(t.seconds) / 3600
+	if t.seconds > 0 {
+		t.period.AverageViewers = t.viewerSeconds / float64(t.seconds)
+	}
+	t.period.ChatMessagesPerMinute = perMinute(t.chatMessages, t.seconds)
+
+	if len(t.categories) > 0 {
+		t.period.ByCategory = make(map[string]*models.AnalyticsPeriod, len(t.categories))
+		for category, totals := range t.categories {
+			t.period.ByCategory[category] = totals.finish()
+		}
+	}
+	return t.period
+}
+
+func perMinute(count, seconds int64) float64 {
+	if seconds <= 0 {
+		return 0
+	}
+	return float64(count) / (float64(seconds) / 60)
+}
+
+// relativeChange records how much a metric changed, e.g. 0.25 for a 25% increase
+func relativeChange(change map[string]float64, metric string, before, after float64) {
+	if before == 0 {
+		return
+	}
+	change[metric] = (after - before) / before
+}
@@ -0,0 +1,157 @@
+// services/stream-management-service/internal/service/stream_follows.go
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/services/stream-management-service/internal/models"
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/services/stream-management-service/internal/repository"
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/services/stream-management-service/pkg/aws"
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/shared/go/pkg/events"
+)
+
+type followEvent struct {
+	FollowerID    int64  `json:"follower_id"`
+	ChannelID     int64  `json:"channel_id"`
+	FollowerCount *int64 `json:"follower_count"`
+}
+
+type streamKeyRotatedEvent struct {
+	UserID       int64  `json:"user_id"`
+	OldStreamKey string `json:"old_stream_key"`
+}
+
+// followConsumer names the consumer of user service events, e.g. for its dead letters
+const followConsumer = "follows"
+
+// StartFollowConsumer keeps the follow and stream key validation caches up to date from user
+// service events. Events it keeps failing on go to deadLetters, those it handled are remembered
+// in the dedupe table so a replayed shard doesn't record follows twice.
+func (s *StreamService) StartFollowConsumer(ctx context.Context, deadLetters *DeadLetterService, dynamoRepo *repository.DynamoDBRepository) {
+	reader := aws.NewKinesisReader(s.config.AWSRegion, s.config.UserEventsStreamName)
+	consumer := events.NewConsumer(s.eventSchemas, followConsumer, deadLetters).
+		WithMaxAttempts(s.config.ConsumerMaxAttempts).
+		WithDeduper(dynamoRepo.EventDeduper(followConsumer, s.config.DedupeLease, s.config.DedupeRetention))
+	deadLetters.Register(followConsumer, func(record []byte) error {
+		return consumer.Replay(record, s.handleUserEvent)
+	})
+
+	go func() {
+		err := reader.Run(ctx, func(record []byte) error {
+			return consumer.Handle(record, s.handleUserEvent)
+		})
+		if err != nil {
+			slog.ErrorContext(ctx, "❌ Follow consumer stopped", "error", err)
+		}
+	}()
+}
+
+func (s *StreamService) handleUserEvent(envelope *events.Envelope) error {
+	var following bool
+	switch envelope.EventType {
+	case "stream_key_rotated":
+		var event streamKeyRotatedEvent
+		if err := json.Unmarshal(envelope.Data, &event); err != nil {
+			return fmt.Errorf("failed to unmarshal %s event: %w", envelope.EventType, err)
+		}
+		s.InvalidateStreamKeyValidation(event.OldStreamKey)
+		return nil
+	case "user_followed":
+		following = true
+	case "user_unfollowed":
+		following = false
+	default:
+		return nil
+	}
+
+	var event followEvent
+	if err := json.Unmarshal(envelope.Data, &event); err != nil {
+		return fmt.Errorf("failed to unmarshal %s event: %w", envelope.EventType, err)
+	}
+
+	followerCount := int64(-1)
+	if event.FollowerCount != nil {
+		followerCount = *event.FollowerCount
+	}
+
+	if err := s.redisRepo.SetFollow(event.FollowerID, event.ChannelID, following, followerCount, s.config.FollowCacheTTL); err != nil {
+		return err
+	}
+	if !following {
+		return nil
+	}
+
+	// Follows are kept for stream analytics, which count the ones gained while live
+	followedAt := time.Unix(envelope.Timestamp, 0).UTC()
+	if err := s.redisRepo.RecordFollow(event.ChannelID, event.FollowerID, followedAt, s.config.FollowHistoryRetention); err != nil {
+		return err
+	}
+
+	// New followers show up in the channel's dashboard feed
+	feedEvent, err := json.Marshal(models.ChannelEvent{
+		Type:   models.ChannelEventFollow,
+		UserID: event.FollowerID,
+		At:     followedAt,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal channel event: %w", err)
+	}
+	return s.redisRepo.PushChannelEvent(event.ChannelID, string(feedEvent), s.config.DashboardRecentEvents, s.config.FollowCacheTTL)
+}
+
+// GetChannelEventsInternal returns a channel's recent events, newest first
+func (s *StreamService) GetChannelEventsInternal(channelID int64) ([]models.ChannelEvent, error) {
+	raw, err := s.redisRepo.GetChannelEvents(channelID)
+	if err != nil {
+		return nil, err
+	}
+
+	events := make([]models.ChannelEvent, 0, len(raw))
+	for _, entry := range raw {
+		var event models.ChannelEvent
+		if err := json.Unmarshal([]byte(entry), &event); err != nil {
+			continue // Skip invalid entries
+		}
+		events = append(events, event)
+	}
+	return events, nil
+}
+
+// AttachFollowInfo adds follower counts to streams and, for a signed in viewer, whether
+// they follow each channel. Missing cache data leaves the fields out rather than failing.
+func (s *StreamService) AttachFollowInfo(streams []*models.Stream, viewerID int64) {
+	if len(streams) == 0 {
+		return
+	}
+
+	channelIDs := make([]int64, 0, len(streams))
+	for _, stream := range streams {
+		channelIDs = append(channelIDs, stream.UserID)
+	}
+
+	counts, err := s.redisRepo.GetFollowerCounts(channelIDs)
+	if err != nil {
+		slog.Warn("⚠️ Could not get follower counts", "error", err)
+	}
+
+	var followed map[int64]bool
+	if viewerID != 0 {
+		if followed, err = s.redisRepo.GetFollowedChannels(viewerID); err != nil {
+			slog.Warn("⚠️ Could not get channels followed by viewer", "user_id", viewerID, "error", err)
+		}
+	}
+
+	for _, stream := range streams {
+		if count, ok := counts[stream.UserID]; ok {
+			stream.FollowerCount = &count
+		}
+		if followed != nil {
+			isFollowed := followed[stream.UserID]
+			stream.IsFollowedByViewer = &isFollowed
+		}
+	}
+}
@@ -0,0 +1,174 @@
+// services/stream-management-service/internal/service/stream_geo.go
+package service
+
+import (
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/services/stream-management-service/internal/config"
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/services/stream-management-service/internal/models"
+)
+
+var countryCode = regexp.MustCompile(`^[A-Z]{2}$`)
+
+// GeoRestrictionsRequest replaces a stream's geo restrictions, an empty list lifts that rule
+type GeoRestrictionsRequest struct {
+	AllowedRegions []string `json:"allowed_regions"`
+	BlockedRegions []string `json:"blocked_regions"`
+}
+
+// GeoLocator finds the country of a viewer, from the CDN's country header first, then from
+// the most specific configured network containing their IP
+type GeoLocator struct {
+	networks []countryNetwork // most specific first
+}
+
+type countryNetwork struct {
+	network *net.IPNet
+	country string
+}
+
+func NewGeoLocator(cfg *config.Config) *GeoLocator {
+	locator := &GeoLocator{}
+
+	for cidr, country := range cfg.GeoIPCountryCIDRs {
+		_, network, err := net.ParseCIDR(strings.TrimSpace(cidr))
+		if err != nil {
+			slog.Warn("⚠️ Ignoring invalid GeoIP network", "cidr", cidr, "error", err)
+			continue
+		}
+		locator.networks = append(locator.networks, countryNetwork{network: network, country: strings.ToUpper(strings.TrimSpace(country))})
+	}
+
+	sort.Slice(locator.networks, func(i, j int) bool {
+		iOnes, _ := locator.networks[i].network.Mask.Size()
+		jOnes, _ := locator.networks[j].network.Mask.Size()
+		return iOnes > jOnes
+	})
+
+	return locator
+}
+
+// Country returns the viewer's ISO country code, empty when it is unknown
+func (g *GeoLocator) Country(c *gin.Context) string {
+	if country := strings.ToUpper(strings.TrimSpace(c.GetHeader(CountryHeader))); countryCode.MatchString(country) {
+		return country
+	}
+
+	return g.CountryForIP(c.ClientIP())
+}
+
+// CountryForIP returns the country of the most specific configured network containing ip,
+// empty when it is unknown
+func (g *GeoLocator) CountryForIP(ip string) string {
+	if parsed := net.ParseIP(ip); parsed != nil {
+		for _, network := range g.networks {
+			if network.network.Contains(parsed) {
+				return network.country
+			}
+		}
+	}
+	return ""
+}
+
+// GetGeoRestrictions handles GET /api/v1/streams/:id/geo-restrictions
+func (s *StreamService) GetGeoRestrictions(c *gin.Context) {
+	stream, err := s.GetStreamByIDInternal(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Stream not found"})
+		return
+	}
+	if !authorizeOwner(c, stream.UserID) {
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"allowed_regions": nonNilRegions(stream.AllowedRegions),
+		"blocked_regions": nonNilRegions(stream.BlockedRegions),
+	})
+}
+
+// UpdateGeoRestrictions handles PUT /api/v1/streams/:id/geo-restrictions. The rules apply to
+// playback authorized from now on, viewers already watching aren't dropped.
+func (s *StreamService) UpdateGeoRestrictions(c *gin.Context) {
+	var req GeoRestrictionsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	allowed, err := normalizeRegions(req.AllowedRegions)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("allowed_regions: %v", err)})
+		return
+	}
+	blocked, err := normalizeRegions(req.BlockedRegions)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("blocked_regions: %v", err)})
+		return
+	}
+
+	stream, err := s.GetStreamByIDInternal(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Stream not found"})
+		return
+	}
+	if !authorizeOwner(c, stream.UserID) {
+		return
+	}
+
+	stream.AllowedRegions = allowed
+	stream.BlockedRegions = blocked
+	stream.UpdatedAt = time.Now()
+	if err := s.UpdateStreamInternal(stream); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not update stream"})
+		return
+	}
+	s.Audit(c.Request.Context(), stream.ID, models.AuditGeoUpdated, map[string]string{
+		"allowed_regions": strings.Join(allowed, ","),
+		"blocked_regions": strings.Join(blocked, ","),
+	})
+
+	slog.InfoContext(c.Request.Context(), "🌍 Geo restrictions updated", "stream_id", stream.ID, "allowed", allowed, "blocked", blocked)
+	c.JSON(http.StatusOK, gin.H{
+		"allowed_regions": nonNilRegions(allowed),
+		"blocked_regions": nonNilRegions(blocked),
+	})
+}
+
+// normalizeRegions uppercases and deduplicates country codes, sorted
+func normalizeRegions(regions []string) ([]string, error) {
+	seen := make(map[string]bool, len(regions))
+	normalized := make([]string, 0, len(regions))
+	for _, region := range regions {
+		region = strings.ToUpper(strings.TrimSpace(region))
+		if !countryCode.MatchString(region) {
+			return nil, fmt.Errorf("%q is not an ISO 3166-1 alpha-2 country code", region)
+		}
+		if !seen[region] {
+			seen[region] = true
+			normalized = append(normalized, region)
+		}
+	}
+	sort.Strings(normalized)
+
+	if len(normalized) == 0 {
+		return nil, nil
+	}
+	return normalized, nil
+}
+
+func nonNilRegions(regions []string) []string {
+	if regions == nil {
+		return []string{}
+	}
+	return regions
+}
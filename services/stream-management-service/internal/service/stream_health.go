@@ -0,0 +1,230 @@
+// services/stream-management-service/internal/service/stream_health.go
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/services/stream-management-service/internal/models"
+)
+
+// Thresholds used to classify a stream's connection quality
+const (
+	degradedFPS = 24.0
+	criticalFPS = 15.0
+
+	degradedBitrateKbps = 1000
+	criticalBitrateKbps = 300
+
+	degradedDropRatio = 0.01 // dropped frames per expected frame
+	criticalDropRatio = 0.05
+
+	degradedKeyframeInterval = 4.0 // seconds, longer intervals delay HLS segments
+	criticalKeyframeInterval = 10.0
+)
+
+// ResolveLiveStreamID returns streamID if set, otherwise looks up the live stream for a stream key
+func (s *StreamService) ResolveLiveStreamID(streamID, streamKey string) (string, error) {
+	if streamID != "" {
+		return streamID, nil
+	}
+	if streamKey == "" {
+		return "", fmt.Errorf("stream_id or stream key is required")
+	}
+
+	session, err := s.GetStreamSession(streamKey)
+	if err != nil {
+		return "", fmt.Errorf("no live session for stream key: %w", err)
+	}
+
+	if !session.Started() {
+		return "", fmt.Errorf("stream has not started yet")
+	}
+
+	return session.StreamID, nil
+}
+
+// OnHealthRecorded adds a check, such as alerting or the bitrate limit, that runs after every
+// health sample, whether the media server reported it over HTTP or gRPC
+func (s *StreamService) OnHealthRecorded(check func(ctx context.Context, streamID string)) {
+	s.healthChecks = append(s.healthChecks, check)
+}
+
+// RecordStreamHealth stores a health sample, runs the health checks on it in the background
+// and returns the updated health of the stream
+func (s *StreamService) RecordStreamHealth(ctx context.Context, streamID string, sample models.HealthSample) (*models.StreamHealth, error) {
+	if sample.Timestamp.IsZero() {
+		sample.Timestamp = time.Now()
+	}
+
+	sampleJSON, err := json.Marshal(sample)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal health sample: %w", err)
+	}
+
+	if err := s.redisRepo.PushHealthSample(streamID, string(sampleJSON), s.config.HealthWindowSize, s.config.HealthSampleTTL); err != nil {
+		return nil, err
+	}
+
+	samples, err := s.loadHealthSamples(streamID)
+	if err != nil {
+		return nil, err
+	}
+
+	health := evaluateHealth(samples)
+
+	// Let downstream consumers know when the quality changes
+	if len(samples) > 1 {
+		previous := evaluateHealth(samples[1:])
+		if previous.Status != health.Status {
+			slog.Info("🩺 Stream health changed", "stream_id", streamID, "from", previous.Status, "to", health.Status, "reasons", health.Reasons)
+
+			event := map[string]interface{}{
+				"stream_id":       streamID,
+				"previous_status": previous.Status,
+				"status":          health.Status,
+				"reasons":         health.Reasons,
+			}
+			if err := s.PublishEvent("stream_health_changed", event); err != nil {
+				slog.Warn("⚠️ Could not publish stream health event", "stream_id", streamID, "error", err)
+			}
+		}
+	}
+
+	// Alerting talks to the chat service, don't hold up the reporter for it
+	for _, check := range s.healthChecks {
+		go check(context.WithoutCancel(ctx), streamID)
+	}
+
+	return health, nil
+}
+
+// GetStreamHealthInternal returns the current health of a stream
+func (s *StreamService) GetStreamHealthInternal(streamID string) (*models.StreamHealth, error) {
+	samples, err := s.loadHealthSamples(streamID)
+	if err != nil {
+		return nil, err
+	}
+	return evaluateHealth(samples), nil
+}
+
+// GetStreamHealth handles GET /api/v1/streams/:id/health
+func (s *StreamService) GetStreamHealth(c *gin.Context) {
+	health, err := s.GetStreamHealthInternal(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not get stream health"})
+		return
+	}
+
+	c.JSON(http.StatusOK, health)
+}
+
+// AttachStreamHealth adds the current health to a live stream
+func (s *StreamService) AttachStreamHealth(stream *models.Stream) {
+	if stream.Status != models.StreamStatusLive {
+		return
+	}
+	if health, err := s.GetStreamHealthInternal(stream.ID); err == nil {
+		stream.Health = health
+	}
+}
+
+func (s *StreamService) loadHealthSamples(streamID string) ([]models.HealthSample, error) {
+	raw, err := s.redisRepo.GetHealthSamples(streamID)
+	if err != nil {
+		return nil, err
+	}
+
+	samples := make([]models.HealthSample, 0, len(raw))
+	for _, item := range raw {
+		var sample models.HealthSample
+		if err := json.Unmarshal([]byte(item), &sample); err != nil {
+			slog.Warn("⚠️ Failed to unmarshal health sample", "error", err)
+			continue
+		}
+		samples = append(samples, sample)
+	}
+
+	return samples, nil
+}
+
+// evaluateHealth classifies a window of samples (newest first). The worst signal wins.
+func evaluateHealth(samples []models.HealthSample) *models.StreamHealth {
+	health := &models.StreamHealth{
+		Status:      models.HealthStatusUnknown,
+		SampleCount: len(samples),
+	}
+	if len(samples) == 0 {
+		return health
+	}
+
+	var totalBitrate int
+	var totalFPS, expectedFrames, droppedFrames float64
+	for i, sample := range samples {
+		totalBitrate += sample.BitrateKbps
+		totalFPS += sample.FPS
+		health.DroppedFrames += sample.DroppedFrames
+
+		// Compare drops against the frames expected since the previous (older) sample
+		if i+1 < len(samples) {
+			interval := sample.Timestamp.Sub(samples[i+1].Timestamp).Seconds()
+			if interval > 0 {
+				expectedFrames += sample.FPS * interval
+				droppedFrames += float64(sample.DroppedFrames)
+			}
+		}
+	}
+
+	latest := samples[0]
+	health.AvgBitrateKbps = totalBitrate / len(samples)
+	health.AvgFPS = totalFPS / float64(len(samples))
+	health.KeyframeInterval = latest.KeyframeInterval
+	health.UpdatedAt = latest.Timestamp
+	health.Status = models.HealthStatusGood
+
+	flag := func(status models.HealthStatus, reason string) {
+		health.Reasons = append(health.Reasons, reason)
+		if status == models.HealthStatusCritical || health.Status == models.HealthStatusGood {
+			health.Status = status
+		}
+	}
+
+	switch {
+	case health.AvgBitrateKbps < criticalBitrateKbps:
+		flag(models.HealthStatusCritical, fmt.Sprintf("bitrate very low (%d kbps)", health.AvgBitrateKbps))
+	case health.AvgBitrateKbps < degradedBitrateKbps:
+		flag(models.HealthStatusDegraded, fmt.Sprintf("bitrate low (%d kbps)", health.AvgBitrateKbps))
+	}
+
+	switch {
+	case health.AvgFPS < criticalFPS:
+		flag(models.HealthStatusCritical, fmt.Sprintf("frame rate very low (%.1f fps)", health.AvgFPS))
+	case health.AvgFPS < degradedFPS:
+		flag(models.HealthStatusDegraded, fmt.Sprintf("frame rate low (%.1f fps)", health.AvgFPS))
+	}
+
+	if expectedFrames > 0 {
+		dropRatio := droppedFrames / expectedFrames
+		switch {
+		case dropRatio > criticalDropRatio:
+			flag(models.HealthStatusCritical, fmt.Sprintf("dropping %.1f%% of frames", dropRatio*100))
+		case dropRatio > degradedDropRatio:
+			flag(models.HealthStatusDegraded, fmt.Sprintf("dropping %.1f%% of frames", dropRatio*100))
+		}
+	}
+
+	switch {
+	case latest.KeyframeInterval > criticalKeyframeInterval:
+		flag(models.HealthStatusCritical, fmt.Sprintf("keyframe interval too long (%.1fs)", latest.KeyframeInterval))
+	case latest.KeyframeInterval > degradedKeyframeInterval:
+		flag(models.HealthStatusDegraded, fmt.Sprintf("keyframe interval long (%.1fs)", latest.KeyframeInterval))
+	}
+
+	return health
+}
@@ -0,0 +1,97 @@
+// services/stream-management-service/internal/service/stream_key_service.go
+package service
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/services/stream-management-service/internal/config"
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/services/stream-management-service/internal/repository"
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/services/stream-management-service/internal/streamkey"
+)
+
+// ErrStreamKeyRevoked is returned for generated keys on the revocation list
+var ErrStreamKeyRevoked = errors.New("stream key revoked")
+
+// StreamKeyService issues signed stream keys for the user service and validates them locally,
+// so publishing doesn't depend on a user service round trip. Revoked keys are kept in Redis
+// until they would have expired anyway.
+type StreamKeyService struct {
+	config    *config.Config
+	redisRepo *repository.RedisRepository
+	generator *streamkey.Generator
+}
+
+func NewStreamKeyService(cfg *config.Config, redisRepo *repository.RedisRepository) *StreamKeyService {
+	return &StreamKeyService{
+		config:    cfg,
+		redisRepo: redisRepo,
+		generator: streamkey.NewGenerator(cfg.StreamKeySecret),
+	}
+}
+
+// Handles reports whether a key is one this service generated and can validate
+func (ks *StreamKeyService) Handles(key string) bool {
+	return ks.generator.Enabled() && streamkey.IsGenerated(key)
+}
+
+// Generate issues a key for a user. A ttl of 0 uses STREAM_KEY_TTL.
+func (ks *StreamKeyService) Generate(userID int64, ttl time.Duration) (string, *streamkey.Claims, error) {
+	if ttl <= 0 {
+		ttl = ks.config.StreamKeyTTL
+	}
+
+	key, claims, err := ks.generator.Generate(userID, ttl)
+	if err != nil {
+		return "", nil, err
+	}
+
+	slog.Info("🔑 Stream key generated", "user_id", userID, "key_id", claims.Nonce, "expires_at", claims.ExpiresAt)
+	return key, claims, nil
+}
+
+// Validate returns the user a generated key belongs to, or false for invalid, expired and
+// revoked keys. Errors are only returned when the revocation list can't be checked.
+func (ks *StreamKeyService) Validate(key string) (int64, bool, error) {
+	claims, err := ks.generator.Parse(key)
+	if err != nil {
+		slog.Warn("❌ Generated stream key rejected", "reason", err)
+		return 0, false, nil
+	}
+
+	revoked, err := ks.redisRepo.IsStreamKeyRevoked(claims.Nonce)
+	if err != nil {
+		return 0, false, err
+	}
+	if revoked {
+		slog.Warn("❌ Generated stream key rejected", "reason", ErrStreamKeyRevoked, "key_id", claims.Nonce)
+		return 0, false, nil
+	}
+
+	return claims.UserID, true, nil
+}
+
+// Revoke puts a generated key on the revocation list and returns its ID
+func (ks *StreamKeyService) Revoke(key string) (string, error) {
+	claims, err := ks.generator.Parse(key)
+	if errors.Is(err, streamkey.ErrExpired) {
+		return claims.Nonce, nil // nothing left to revoke
+	}
+	if err != nil {
+		return "", err
+	}
+
+	ttl := time.Duration(0)
+	if claims.ExpiresAt != nil {
+		ttl = time.Until(*claims.ExpiresAt)
+	}
+
+	if err := ks.redisRepo.RevokeStreamKey(claims.Nonce, ttl); err != nil {
+		return "", fmt.Errorf("failed to revoke key %s: %w", claims.Nonce, err)
+	}
+
+	slog.Info("🚫 Stream key revoked", "user_id", claims.UserID, "key_id", claims.Nonce)
+	return claims.Nonce, nil
+}
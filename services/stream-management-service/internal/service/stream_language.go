@@ -0,0 +1,46 @@
+// services/stream-management-service/internal/service/stream_language.go
+package service
+
+import (
+	"fmt"
+	"strings"
+
+	"golang.org/x/text/language"
+
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/services/stream-management-service/internal/models"
+)
+
+// NormalizeLanguage validates a BCP-47 language tag and returns its canonical form, e.g.
+// "pt-br" becomes "pt-BR". An empty value clears the language.
+func NormalizeLanguage(value string) (string, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return "", nil
+	}
+
+	tag, err := language.Parse(value)
+	if err != nil {
+		return "", fmt.Errorf("language must be a BCP-47 tag such as en or pt-BR")
+	}
+	return tag.String(), nil
+}
+
+// matchesLanguage reports whether a stream's language falls under a normalized filter. A
+// filter without a region also matches the regional variants, "en" matches "en-US".
+func matchesLanguage(streamLanguage, filter string) bool {
+	if filter == "" {
+		return true
+	}
+	return streamLanguage == filter || strings.HasPrefix(streamLanguage, filter+"-")
+}
+
+// withLanguage keeps the streams matching a normalized language filter
+func withLanguage(streams []*models.Stream, filter string) []*models.Stream {
+	matching := make([]*models.Stream, 0, len(streams))
+	for _, stream := range streams {
+		if matchesLanguage(stream.Language, filter) {
+			matching = append(matching, stream)
+		}
+	}
+	return matching
+}
@@ -0,0 +1,205 @@
+// services/stream-management-service/internal/service/stream_latency.go
+package service
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/services/stream-management-service/internal/models"
+)
+
+// latencyMarkerPrefix starts every marker payload so players can tell them apart from other
+// timed metadata
+const latencyMarkerPrefix = "lsp-latency"
+
+// ErrUnknownLatencyMarker is returned for reports against markers that expired or were never issued
+var ErrUnknownLatencyMarker = errors.New("unknown or expired latency marker")
+
+type LatencyReportRequest struct {
+	MarkerID   string `json:"marker_id" binding:"required"`
+	ObservedAt int64  `json:"observed_at"` // unix ms when the marked frame was shown, defaults to now
+	PlayerID   string `json:"player_id"`
+}
+
+// CreateLatencyMarker issues a marker for the media server to embed in a stream at ingest
+func (s *StreamService) CreateLatencyMarker(streamID, markerID string, ingestAt time.Time) (*models.LatencyMarker, error) {
+	if markerID == "" {
+		markerID = generateMarkerID()
+	}
+	if ingestAt.IsZero() {
+		ingestAt = time.Now()
+	}
+
+	if err := s.redisRepo.SetLatencyMarker(streamID, markerID, ingestAt.UnixMilli(), s.config.LatencyMarkerTTL); err != nil {
+		return nil, err
+	}
+
+	return &models.LatencyMarker{
+		ID:       markerID,
+		StreamID: streamID,
+		IngestAt: ingestAt,
+		Payload:  fmt.Sprintf("%s:%s:%s:%d", latencyMarkerPrefix, streamID, markerID, ingestAt.UnixMilli()),
+	}, nil
+}
+
+// RecordLatencySample measures a player's delay against the marker it saw and stores it
+func (s *StreamService) RecordLatencySample(streamID, markerID, playerID string, observedAt time.Time) (*models.LatencySample, error) {
+	ingestAt, err := s.redisRepo.GetLatencyMarker(streamID, markerID)
+	if err != nil {
+		return nil, err
+	}
+	if ingestAt == 0 {
+		return nil, ErrUnknownLatencyMarker
+	}
+
+	latency := observedAt.UnixMilli() - ingestAt
+	if latency < 0 || latency > s.config.LatencyMaxDelay.Milliseconds() {
+		return nil, fmt.Errorf("latency of %dms is out of range, check the player's clock", latency)
+	}
+
+	sample := &models.LatencySample{
+		MarkerID:   markerID,
+		LatencyMs:  latency,
+		PlayerID:   playerID,
+		ObservedAt: observedAt,
+	}
+
+	sampleJSON, err := json.Marshal(sample)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal latency sample: %w", err)
+	}
+	if err := s.redisRepo.PushLatencySample(streamID, string(sampleJSON), s.config.LatencyWindowSize, s.config.LatencySampleTTL); err != nil {
+		return nil, err
+	}
+
+	return sample, nil
+}
+
+// ReportLatency handles POST /api/v1/streams/:id/latency, a reference player's beacon
+func (s *StreamService) ReportLatency(c *gin.Context) {
+	var req LatencyReportRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	observedAt := time.Now()
+	if req.ObservedAt > 0 {
+		observedAt = time.UnixMilli(req.ObservedAt)
+	}
+
+	sample, err := s.RecordLatencySample(c.Param("id"), req.MarkerID, req.PlayerID, observedAt)
+	if errors.Is(err, ErrUnknownLatencyMarker) {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		slog.WarnContext(c.Request.Context(), "⚠️ Could not record latency sample", "stream_id", c.Param("id"), "error", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, sample)
+}
+
+// GetStreamLatencyInternal returns the latency percentiles of a stream's recent samples
+func (s *StreamService) GetStreamLatencyInternal(streamID string) (*models.StreamLatency, error) {
+	raw, err := s.redisRepo.GetLatencySamples(streamID)
+	if err != nil {
+		return nil, err
+	}
+
+	samples := make([]models.LatencySample, 0, len(raw))
+	for _, item := range raw {
+		var sample models.LatencySample
+		if err := json.Unmarshal([]byte(item), &sample); err != nil {
+			slog.Warn("⚠️ Failed to unmarshal latency sample", "error", err)
+			continue
+		}
+		samples = append(samples, sample)
+	}
+
+	return summarizeLatency(samples), nil
+}
+
+// GetStreamLatency handles GET /api/v1/streams/:id/latency
+func (s *StreamService) GetStreamLatency(c *gin.Context) {
+	latency, err := s.GetStreamLatencyInternal(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not get stream latency"})
+		return
+	}
+
+	c.JSON(http.StatusOK, latency)
+}
+
+// publishLatencySummary records the latency percentiles of a stream that ended in analytics
+func (s *StreamService) publishLatencySummary(stream *models.Stream) {
+	latency, err := s.GetStreamLatencyInternal(stream.ID)
+	if err != nil || latency.SampleCount == 0 {
+		return
+	}
+
+	event := map[string]interface{}{
+		"stream_id":    stream.ID,
+		"user_id":      stream.UserID,
+		"sample_count": latency.SampleCount,
+		"min_ms":       latency.MinMs,
+		"p50_ms":       latency.P50Ms,
+		"p90_ms":       latency.P90Ms,
+		"p95_ms":       latency.P95Ms,
+		"p99_ms":       latency.P99Ms,
+		"max_ms":       latency.MaxMs,
+	}
+	if err := s.PublishEvent("stream_latency_summary", event); err != nil {
+		slog.Warn("⚠️ Could not publish stream latency summary", "stream_id", stream.ID, "error", err)
+	}
+}
+
+// summarizeLatency computes nearest-rank percentiles of a window of samples (newest first)
+func summarizeLatency(samples []models.LatencySample) *models.StreamLatency {
+	latency := &models.StreamLatency{SampleCount: len(samples)}
+	if len(samples) == 0 {
+		return latency
+	}
+
+	values := make([]int64, len(samples))
+	for i, sample := range samples {
+		values[i] = sample.LatencyMs
+	}
+	sort.Slice(values, func(i, j int) bool { return values[i] < values[j] })
+
+	percentile := func(p float64) int64 {
+		rank := int(math.Ceil(p / 100 * float64(len(values))))
+		if rank < 1 {
+			rank = 1
+		}
+		return values[rank-1]
+	}
+
+	latency.MinMs = values[0]
+	latency.P50Ms = percentile(50)
+	latency.P90Ms = percentile(90)
+	latency.P95Ms = percentile(95)
+	latency.P99Ms = percentile(99)
+	latency.MaxMs = values[len(values)-1]
+	latency.UpdatedAt = samples[0].ObservedAt
+
+	return latency
+}
+
+func generateMarkerID() string {
+	bytes := make([]byte, 8)
+	rand.Read(bytes)
+	return hex.EncodeToString(bytes)
+}
@@ -0,0 +1,209 @@
+// services/stream-management-service/internal/service/stream_limits.go
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/services/stream-management-service/internal/config"
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/services/stream-management-service/internal/models"
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/services/stream-management-service/internal/repository"
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/services/stream-management-service/pkg/srs"
+)
+
+const (
+	// limitCheckInterval is how often live streams are checked against their duration limit
+	limitCheckInterval = time.Minute
+	// durationWarningLead is how long before the duration limit the broadcaster is warned
+	durationWarningLead = 10 * time.Minute
+)
+
+// StreamLimitService enforces the bitrate and duration a publisher was authorized with.
+// Broadcasters get a health alert first, streams still over a limit are ended and their
+// publisher is dropped from the media server.
+type StreamLimitService struct {
+	config        *config.Config
+	redisRepo     *repository.RedisRepository
+	streamService *StreamService
+	healthAlerts  *HealthAlertService
+	srsClient     *srs.Client
+}
+
+func NewStreamLimitService(cfg *config.Config, redisRepo *repository.RedisRepository, streamService *StreamService, healthAlerts *HealthAlertService) *StreamLimitService {
+	return &StreamLimitService{
+		config:        cfg,
+		redisRepo:     redisRepo,
+		streamService: streamService,
+		healthAlerts:  healthAlerts,
+		srsClient:     srs.NewClient(cfg.SRSAPIURL, cfg.Caller(config.DependencySRS).Transport(nil)),
+	}
+}
+
+// PublisherPermissions returns what an authorized publisher may do, as stored in its session
+// and returned to the media server
+func (s *StreamService) PublisherPermissions() *models.PublisherPermissions {
+	return &models.PublisherPermissions{
+		CanStream:          true,
+		CanRecord:          true,
+		MaxBitrate:         s.config.MaxBitrateKbps,
+		MaxDurationMinutes: s.config.MaxDurationMinutes,
+	}
+}
+
+// publisherLimits returns the bitrate in kbps and the duration a publisher was authorized
+// with, 0 when unlimited. Sessions from before permissions were stored get the defaults.
+func (s *StreamService) publisherLimits(session *models.StreamSession) (int, time.Duration) {
+	maxBitrate, maxMinutes := s.config.MaxBitrateKbps, s.config.MaxDurationMinutes
+	if session.Permissions != nil {
+		maxBitrate, maxMinutes = session.Permissions.MaxBitrate, session.Permissions.MaxDurationMinutes
+	}
+	return maxBitrate, time.Duration(maxMinutes) * time.Minute
+}
+
+// CheckBitrate compares the latest health reports of a live stream with its bitrate limit.
+// A few reports over it warn the broadcaster, BitrateLimitReports in a row end the stream.
+func (ls *StreamLimitService) CheckBitrate(ctx context.Context, streamID string) {
+	stream, session, ok := ls.enforceable(ctx, streamID)
+	if !ok {
+		return
+	}
+	maxBitrate, _ := ls.streamService.publisherLimits(session)
+	if maxBitrate <= 0 {
+		return
+	}
+
+	samples, err := ls.streamService.loadHealthSamples(streamID)
+	if err != nil {
+		slog.WarnContext(ctx, "⚠️ Could not load health samples for the bitrate limit", "stream_id", streamID, "error", err)
+		return
+	}
+
+	allowed := int(float64(maxBitrate) * (1 + ls.config.BitrateLimitTolerance))
+	over := 0
+	for _, sample := range samples {
+		if sample.BitrateKbps <= allowed {
+			break
+		}
+		over++
+	}
+
+	switch {
+	case over >= ls.config.BitrateLimitReports:
+		ls.stop(ctx, stream, session, models.EndReasonMaxBitrate)
+	case over >= healthAlertWindow:
+		bitrate := samples[0].BitrateKbps
+		ls.healthAlerts.raise(ctx, stream, &models.HealthAlert{
+			Kind:      models.HealthAlertBitrateLimit,
+			Severity:  models.HealthStatusCritical,
+			Message:   fmt.Sprintf("Your bitrate of %s is over the %s limit, lower it or your stream will be ended", formatBitrate(bitrate), formatBitrate(maxBitrate)),
+			Value:     float64(bitrate),
+			Threshold: float64(maxBitrate),
+		}, ls.config.HealthAlertCooldown)
+	}
+}
+
+// StartDurationEnforcer periodically ends live streams that reached their duration limit,
+// warning their broadcaster shortly before
+func (ls *StreamLimitService) StartDurationEnforcer(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(limitCheckInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				ls.checkDurations(ctx)
+			}
+		}
+	}()
+}
+
+func (ls *StreamLimitService) checkDurations(ctx context.Context) {
+	streams, err := ls.streamService.GetActiveStreamsInternal()
+	if err != nil {
+		slog.WarnContext(ctx, "⚠️ Could not get live streams for the duration limit", "error", err)
+		return
+	}
+
+	now := time.Now()
+	for _, live := range streams {
+		if ctx.Err() != nil {
+			return
+		}
+		if live.StartedAt == nil {
+			continue
+		}
+
+		stream, session, ok := ls.enforceable(ctx, live.ID)
+		if !ok {
+			continue
+		}
+		_, maxDuration := ls.streamService.publisherLimits(session)
+		if maxDuration <= 0 {
+			continue
+		}
+
+		remaining := stream.StartedAt.Add(maxDuration).Sub(now)
+		switch {
+		case remaining <= 0:
+			ls.stop(ctx, stream, session, models.EndReasonMaxDuration)
+		case remaining <= durationWarningLead:
+			minutes := int(remaining.Round(time.Minute).Minutes())
+			ls.healthAlerts.raise(ctx, stream, &models.HealthAlert{
+				Kind:      models.HealthAlertDurationLimit,
+				Severity:  models.HealthStatusDegraded,
+				Message:   fmt.Sprintf("Your stream reaches its %d minute limit in %d minutes and will be ended then", int(maxDuration.Minutes()), minutes),
+				Value:     now.Sub(*stream.StartedAt).Minutes(),
+				Threshold: maxDuration.Minutes(),
+			}, durationWarningLead)
+		}
+	}
+}
+
+// enforceable returns a live stream and its session if its limits are enforced. Premieres are
+// relayed by this service and aren't held to a broadcaster's limits.
+func (ls *StreamLimitService) enforceable(ctx context.Context, streamID string) (*models.Stream, *models.StreamSession, bool) {
+	stream, err := ls.streamService.GetStreamByIDInternal(streamID)
+	if err != nil {
+		slog.WarnContext(ctx, "⚠️ Could not get stream for its limits", "stream_id", streamID, "error", err)
+		return nil, nil, false
+	}
+	if stream.Status != models.StreamStatusLive || stream.Metadata["premiere_vod_id"] != "" {
+		return nil, nil, false
+	}
+
+	session, err := ls.streamService.GetStreamSession(stream.StreamKey)
+	if err != nil {
+		session = &models.StreamSession{}
+	}
+	return stream, session, true
+}
+
+// stop ends a stream that went over a limit and drops its publisher from the media server
+func (ls *StreamLimitService) stop(ctx context.Context, stream *models.Stream, session *models.StreamSession, reason string) {
+	claimed, err := ls.redisRepo.ClaimStreamLimit(stream.ID, limitCheckInterval)
+	if err != nil {
+		slog.WarnContext(ctx, "⚠️ Could not claim stream limit", "stream_id", stream.ID, "error", err)
+		return
+	}
+	if !claimed {
+		return // being stopped by another replica
+	}
+
+	// Ended first so the media server's unpublish callback finds it ended instead of
+	// holding it open for a reconnect
+	if err := ls.streamService.StopStream(ctx, stream, reason); err != nil {
+		slog.ErrorContext(ctx, "❌ Could not end stream over its limit", "stream_id", stream.ID, "end_reason", reason, "error", err)
+		return
+	}
+	slog.InfoContext(ctx, "🛑 Stream ended for going over its limit", "stream_id", stream.ID, "user_id", stream.UserID, "end_reason", reason)
+
+	if err := ls.srsClient.KickPublisher(ctx, stream.Metadata["app_name"], stream.StreamKey, session.ClientID); err != nil && !errors.Is(err, srs.ErrNotPublishing) {
+		slog.WarnContext(ctx, "⚠️ Could not drop publisher from the media server", "stream_id", stream.ID, "error", err)
+	}
+}
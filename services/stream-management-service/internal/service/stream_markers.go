@@ -0,0 +1,128 @@
+// services/stream-management-service/internal/service/stream_markers.go
+package service
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+	"unicode/utf8"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/services/stream-management-service/internal/models"
+)
+
+const (
+	maxMarkerDescription = 140
+
+	// A clip made from a marker starts a little before it, the moment is usually noticed late
+	markerClipLead = 30 // seconds
+	markerClipTail = 30 // seconds
+)
+
+type CreateMarkerRequest struct {
+	Description string `json:"description"`
+}
+
+// CreateMarker handles POST /api/v1/streams/:id/markers. Markers are dropped at the current
+// position of a live stream's recording, by the streamer or a bot with an API key.
+func (s *StreamService) CreateMarker(c *gin.Context) {
+	var req CreateMarkerRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+	if utf8.RuneCountInString(req.Description) > maxMarkerDescription {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("description must be at most %d characters", maxMarkerDescription)})
+		return
+	}
+
+	stream, err := s.GetStreamByIDInternal(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Stream not found"})
+		return
+	}
+	if !authorizeOwner(c, stream.UserID) {
+		return
+	}
+	if !isLive(stream) {
+		c.JSON(http.StatusConflict, gin.H{"error": "Markers can only be added while the stream is live"})
+		return
+	}
+	if len(stream.Markers) >= models.MaxStreamMarkers {
+		c.JSON(http.StatusConflict, gin.H{"error": fmt.Sprintf("A stream can have at most %d markers", models.MaxStreamMarkers)})
+		return
+	}
+
+	marker := models.StreamMarker{
+		ID:          generateStreamMarkerID(),
+		Offset:      s.recordingOffset(stream),
+		Description: req.Description,
+		CreatedBy:   principalID(c),
+		CreatedAt:   time.Now().UTC(),
+	}
+	stream.Markers = append(stream.Markers, marker)
+	stream.UpdatedAt = time.Now()
+	if err := s.UpdateStreamInternal(stream); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not add marker"})
+		return
+	}
+
+	slog.InfoContext(c.Request.Context(), "📍 Stream marker added", "stream_id", stream.ID, "marker_id", marker.ID, "offset", marker.Offset)
+	c.JSON(http.StatusCreated, marker)
+}
+
+// GetMarkers handles GET /api/v1/streams/:id/markers
+func (s *StreamService) GetMarkers(c *gin.Context) {
+	stream, err := s.GetStreamByIDInternal(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Stream not found"})
+		return
+	}
+	if !authorizeOwner(c, stream.UserID) {
+		return
+	}
+
+	markers := stream.Markers
+	if markers == nil {
+		markers = []models.StreamMarker{}
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"stream_id": stream.ID,
+		"markers":   markers,
+		"count":     len(markers),
+	})
+}
+
+// findMarker returns a stream's marker by ID
+func findMarker(stream *models.Stream, markerID string) (*models.StreamMarker, bool) {
+	for i := range stream.Markers {
+		if stream.Markers[i].ID == markerID {
+			return &stream.Markers[i], true
+		}
+	}
+	return nil, false
+}
+
+// recordingMarkers returns the markers that fall inside a recording
+func recordingMarkers(markers []models.StreamMarker, durationSec int64) []models.StreamMarker {
+	var inside []models.StreamMarker
+	for _, marker := range markers {
+		if durationSec > 0 && marker.Offset >= durationSec {
+			continue
+		}
+		inside = append(inside, marker)
+	}
+	return inside
+}
+
+func generateStreamMarkerID() string {
+	bytes := make([]byte, 8)
+	rand.Read(bytes)
+	return "mkr_" + hex.EncodeToString(bytes)
+}
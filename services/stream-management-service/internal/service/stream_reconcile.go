@@ -0,0 +1,289 @@
+// services/stream-management-service/internal/service/stream_reconcile.go
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/services/stream-management-service/internal/models"
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/services/stream-management-service/pkg/srs"
+)
+
+// reconcileSettleTime skips streams that changed just before SRS was asked, their publisher
+// may not be listed yet, and publishers authorized just before, their stream may not be
+// created yet
+const reconcileSettleTime = time.Minute
+
+// PersistLiveSessions keeps the sessions of open streams until the service is back, so their
+// unpublish callbacks still find them and startup reconciliation knows when they were last
+// seen. Sessions that expired are rebuilt from the stream.
+func (s *StreamService) PersistLiveSessions(ctx context.Context) error {
+	streams, err := s.openStreams()
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	persisted := 0
+	for _, stream := range streams {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		session, err := s.GetStreamSession(stream.StreamKey)
+		if err != nil {
+			session = rebuildSession(stream)
+		}
+		session.PersistedAt = now.Unix()
+
+		if err := s.storeSession(stream.StreamKey, session, 0); err != nil {
+			slog.WarnContext(ctx, "⚠️ Could not persist stream session", "stream_id", stream.ID, "error", err)
+			continue
+		}
+		persisted++
+	}
+
+	slog.InfoContext(ctx, "💾 Persisted sessions of open streams", "streams", persisted)
+	return nil
+}
+
+// ReconcileStreams fixes the streams whose status drifted from the media server, because the
+// service was down or a callback got lost. Live streams nobody publishes anymore are ended,
+// published ones get their session back and authorized publishers without a stream get one.
+// Reconnecting streams are left to the reconnect finalizer. The job scheduler runs it on one
+// replica at a time.
+func (s *StreamService) ReconcileStreams(ctx context.Context) error {
+	listedAt := time.Now()
+	publishers, err := s.srsClient.ListPublishers(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list media server publishers: %w", err)
+	}
+	published := make(map[string]srs.Publisher, len(publishers))
+	for _, publisher := range publishers {
+		published[publisher.Stream] = publisher
+	}
+
+	streams, err := s.openStreams()
+	if err != nil {
+		return err
+	}
+
+	ended, restored := 0, 0
+	for _, stream := range streams {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if stream.UpdatedAt.After(listedAt.Add(-reconcileSettleTime)) {
+			continue
+		}
+
+		publisher, live := published[stream.StreamKey]
+		delete(published, stream.StreamKey)
+
+		switch {
+		case live:
+			if err := s.restoreSession(ctx, stream, publisher); err != nil {
+				slog.WarnContext(ctx, "⚠️ Could not restore stream session", "stream_id", stream.ID, "error", err)
+				continue
+			}
+			restored++
+		case stream.Status == models.StreamStatusLive:
+			if err := s.endUnpublishedStream(ctx, stream); err != nil {
+				slog.WarnContext(ctx, "⚠️ Could not end unpublished stream", "stream_id", stream.ID, "error", err)
+				continue
+			}
+			ended++
+		}
+	}
+
+	// Publishers whose publish callback got lost get their stream from the session the auth
+	// callback left. Without one there's nobody to give the stream to.
+	created := 0
+	for streamKey, publisher := range published {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		session, err := s.GetStreamSession(streamKey)
+		if err != nil || !adoptableSession(session, listedAt) {
+			slog.WarnContext(ctx, "⚠️ Media server publishes a stream that isn't live", "stream_key", streamKey, "client_id", publisher.ClientID)
+			continue
+		}
+		if err := s.adoptPublisher(ctx, publisher, session); err != nil {
+			slog.WarnContext(ctx, "⚠️ Could not create stream for publisher", "stream_key", streamKey, "error", err)
+			continue
+		}
+		created++
+	}
+
+	log := slog.DebugContext
+	if ended > 0 || restored > 0 || created > 0 {
+		log = slog.InfoContext
+	}
+	log(ctx, "🔄 Streams reconciled with the media server", "publishers", len(publishers), "ended", ended, "restored", restored, "created", created)
+	return nil
+}
+
+// adoptableSession reports whether a session belongs to a publisher the auth callback accepted
+// a while ago and that never got a stream. Premiere relays are started by the premiere service.
+func adoptableSession(session *models.StreamSession, listedAt time.Time) bool {
+	if session.UserID == 0 || session.IsPremiere() || session.Started() {
+		return false
+	}
+	return session.AuthorizedAt().Before(listedAt.Add(-reconcileSettleTime))
+}
+
+// adoptPublisher creates the live stream the publish callback would have, from what the auth
+// callback stored in the session
+func (s *StreamService) adoptPublisher(ctx context.Context, publisher srs.Publisher, session *models.StreamSession) error {
+	protocol := session.Protocol()
+	appName, clientIP := session.AppName, session.ClientIP
+
+	now := time.Now()
+	stream := &models.Stream{
+		UserID:    session.UserID,
+		StreamKey: publisher.Stream,
+		Title:     fmt.Sprintf("Live Stream - %s", now.Format("2006-01-02 15:04")),
+		Status:    models.StreamStatusLive,
+		StartedAt: &now,
+		Metadata: map[string]string{
+			"client_ip":       clientIP,
+			"app_name":        appName,
+			"session_started": now.Format(time.RFC3339),
+			"rtmp_app":        appName,
+			"ingest_protocol": string(protocol),
+			"reconciled":      "true",
+		},
+		IngestProtocol: protocol,
+		PlaybackID:     session.PlaybackID,
+		Restreams:      session.Restreams,
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}
+	if protocol == models.IngestProtocolSRT {
+		stream.SRTLatencyMs = session.SRTLatencyMs
+	}
+
+	s.ClassifyStream(stream)
+	s.InheritChannelSettings(stream)
+
+	started := StreamEvent{
+		Type: "stream_started",
+		Data: map[string]interface{}{
+			"user_id":   stream.UserID,
+			"is_mature": stream.IsMature,
+			"language":  stream.Language,
+			"metadata": map[string]interface{}{
+				"stream_key":      stream.StreamKey,
+				"client_ip":       clientIP,
+				"app_name":        appName,
+				"ingest_protocol": string(protocol),
+			},
+		},
+	}
+	streamID, err := s.CreateStream(ctx, stream, started)
+	if err != nil {
+		return err
+	}
+
+	session.StreamID = streamID
+	session.ClientID = publisher.ClientID
+	session.StreamStartedAt = now.Unix()
+	session.SegmentStartedAt = now.Unix()
+	if err := s.storeSession(stream.StreamKey, session, s.config.StreamSessionTTL); err != nil {
+		slog.WarnContext(ctx, "⚠️ Could not store stream session", "stream_id", streamID, "error", err)
+	}
+
+	slog.InfoContext(ctx, "✅ Stream created for publisher without one", "stream_id", streamID, "stream_key", stream.StreamKey)
+	return nil
+}
+
+// restoreSession gives a stream that is still published its session back with the usual TTL.
+// A stream left reconnecting although its broadcaster is back is resumed.
+func (s *StreamService) restoreSession(ctx context.Context, stream *models.Stream, publisher srs.Publisher) error {
+	session, err := s.GetStreamSession(stream.StreamKey)
+	if err != nil {
+		session = rebuildSession(stream)
+	}
+	session.PersistedAt = 0
+	session.ClientID = publisher.ClientID
+	session.ReconciledAt = time.Now().Unix()
+
+	if stream.Status == models.StreamStatusReconnecting && session.Reconnecting() {
+		_, err := s.ResumeStream(ctx, stream.StreamKey, session)
+		return err
+	}
+	return s.storeSession(stream.StreamKey, session, s.config.StreamSessionTTL)
+}
+
+// endUnpublishedStream ends a live stream whose publisher left without the service hearing of
+// it. It is counted until it was last seen, the service can't know when it really ended.
+func (s *StreamService) endUnpublishedStream(ctx context.Context, stream *models.Stream) error {
+	lastSeen := stream.UpdatedAt
+	session, err := s.GetStreamSession(stream.StreamKey)
+	if err == nil && session.LastSeen().After(lastSeen) {
+		lastSeen = session.LastSeen()
+	}
+
+	durationSec := int64(0)
+	if stream.StartedAt != nil && lastSeen.After(*stream.StartedAt) {
+		durationSec = int64(lastSeen.Sub(*stream.StartedAt).Seconds())
+	}
+
+	if err := s.endStream(ctx, stream, durationSec, models.EndReasonReconciled); err != nil {
+		return err
+	}
+	if err := s.CleanupStreamSession(stream.StreamKey); err != nil {
+		slog.WarnContext(ctx, "⚠️ Could not cleanup stream session", "stream_id", stream.ID, "error", err)
+	}
+	return nil
+}
+
+// openStreams returns the streams that are live or waiting for their broadcaster
+func (s *StreamService) openStreams() ([]*models.Stream, error) {
+	live, err := s.dynamoRepo.GetStreamsByStatus(models.StreamStatusLive, models.DeletedExclude)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get live streams: %w", err)
+	}
+	reconnecting, err := s.dynamoRepo.GetStreamsByStatus(models.StreamStatusReconnecting, models.DeletedExclude)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get reconnecting streams: %w", err)
+	}
+	return append(live, reconnecting...), nil
+}
+
+func (s *StreamService) storeSession(streamKey string, session *models.StreamSession, ttl time.Duration) error {
+	// Sessions get their playback ID the first time they are stored
+	if session.PlaybackID == "" {
+		session.PlaybackID = generatePlaybackID()
+	}
+
+	sessionJSON, err := json.Marshal(session)
+	if err != nil {
+		return err
+	}
+	if err := s.redisRepo.SetStreamSession(streamKey, string(sessionJSON), ttl); err != nil {
+		return err
+	}
+	return s.redisRepo.SetPlaybackStreamKey(session.PlaybackID, streamKey, ttl)
+}
+
+// rebuildSession recreates what the auth and publish callbacks put in a session from the stream
+func rebuildSession(stream *models.Stream) *models.StreamSession {
+	session := &models.StreamSession{
+		UserID:         stream.UserID,
+		StreamKey:      stream.StreamKey,
+		StreamID:       stream.ID,
+		AppName:        stream.Metadata["app_name"],
+		IngestProtocol: stream.IngestProtocol,
+		PlaybackID:     stream.PlaybackID,
+	}
+	if stream.StartedAt != nil {
+		session.StreamStartedAt = stream.StartedAt.Unix()
+		session.SegmentStartedAt = stream.StartedAt.Unix()
+	}
+	return session
+}
@@ -0,0 +1,219 @@
+// services/stream-management-service/internal/service/stream_reconnect.go
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"time"
+
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/services/stream-management-service/internal/models"
+)
+
+// errStreamEnded is returned when a disconnected stream was already ended, e.g. by an admin
+var errStreamEnded = errors.New("stream has already ended")
+
+// CarryOverReconnectState copies the open stream from the previous session into a new one,
+// so re-authenticating within the grace period resumes the stream instead of starting another
+func (s *StreamService) CarryOverReconnectState(streamKey string, session *models.StreamSession) {
+	previous, err := s.GetStreamSession(streamKey)
+	if err != nil || !previous.Reconnecting() {
+		return
+	}
+
+	session.StreamID = previous.StreamID
+	session.PlaybackID = previous.PlaybackID
+	session.StreamStartedAt = previous.StreamStartedAt
+	session.SegmentStartedAt = previous.SegmentStartedAt
+	session.LiveSeconds = previous.LiveSeconds
+	session.DisconnectedAt = previous.DisconnectedAt
+}
+
+// MarkStreamReconnecting keeps a disconnected stream open for the grace period instead of ending it
+func (s *StreamService) MarkStreamReconnecting(ctx context.Context, streamKey string, session *models.StreamSession, segmentSeconds int64) error {
+	stream, err := s.GetStreamByIDInternal(session.StreamID)
+	if err != nil {
+		return fmt.Errorf("stream not found: %w", err)
+	}
+	// Terminated by an admin while the publisher was still connected
+	if stream.Status == models.StreamStatusEnded {
+		return errStreamEnded
+	}
+
+	now := time.Now()
+
+	// Media servers don't always report how long the publish lasted
+	if segmentSeconds <= 0 {
+		segmentStart := session.SegmentStartedAt
+		if segmentStart == 0 {
+			segmentStart = session.StreamStartedAt
+		}
+		if segmentStart > 0 {
+			segmentSeconds = now.Unix() - segmentStart
+		}
+	}
+
+	session.LiveSeconds += segmentSeconds
+	session.DisconnectedAt = now.Unix()
+	if err := s.StoreStreamSession(streamKey, session); err != nil {
+		return err
+	}
+
+	stream.Status = models.StreamStatusReconnecting
+	stream.UpdatedAt = now
+	stopRestreams(stream, now) // the media server stops pushing with the source
+	if err := s.UpdateStreamInternal(stream); err != nil {
+		return err
+	}
+
+	gracePeriod := s.config.Tunables().ReconnectGracePeriod
+	if err := s.redisRepo.AddReconnecting(streamKey, now.Add(gracePeriod)); err != nil {
+		return err
+	}
+	s.Audit(ctx, stream.ID, models.AuditStreamReconnecting, map[string]string{
+		"grace_period": gracePeriod.String(),
+	})
+
+	slog.Info("🔌 Stream disconnected, waiting for the broadcaster to reconnect", "stream_id", stream.ID, "grace_period", gracePeriod)
+	return nil
+}
+
+// ResumeStream puts a reconnecting stream back live
+func (s *StreamService) ResumeStream(ctx context.Context, streamKey string, session *models.StreamSession) (string, error) {
+	claimed, err := s.redisRepo.RemoveReconnecting(streamKey)
+	if err != nil {
+		return "", err
+	}
+	if !claimed {
+		return "", fmt.Errorf("reconnect grace period has expired")
+	}
+
+	stream, err := s.GetStreamByIDInternal(session.StreamID)
+	if err != nil {
+		return "", fmt.Errorf("stream not found: %w", err)
+	}
+
+	now := time.Now()
+	stream.Status = models.StreamStatusLive
+	stream.UpdatedAt = now
+	if err := s.UpdateStreamInternal(stream); err != nil {
+		return "", err
+	}
+	s.Audit(ctx, stream.ID, models.AuditStreamResumed, nil)
+
+	session.DisconnectedAt = 0
+	session.SegmentStartedAt = now.Unix()
+	if err := s.StoreStreamSession(streamKey, session); err != nil {
+		slog.Warn("⚠️ Could not update stream session", "stream_id", stream.ID, "error", err)
+	}
+
+	slog.Info("🔁 Stream resumed after reconnect", "stream_id", stream.ID)
+	return stream.ID, nil
+}
+
+// StartReconnectFinalizer periodically ends streams whose grace period ran out
+func (s *StreamService) StartReconnectFinalizer(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(5 * time.Second)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if err := s.FinalizeExpiredReconnects(ctx); err != nil {
+					slog.WarnContext(ctx, "⚠️ Error finalizing reconnecting streams", "error", err)
+				}
+			}
+		}
+	}()
+}
+
+// FinalizeExpiredReconnects ends every stream whose broadcaster didn't come back in time
+func (s *StreamService) FinalizeExpiredReconnects(ctx context.Context) error {
+	streamKeys, err := s.redisRepo.GetExpiredReconnecting(time.Now())
+	if err != nil {
+		return err
+	}
+
+	for _, streamKey := range streamKeys {
+		// Only the instance that removes the entry finalizes the stream
+		claimed, err := s.redisRepo.RemoveReconnecting(streamKey)
+		if err != nil || !claimed {
+			continue
+		}
+
+		if err := s.finalizeDisconnectedStream(ctx, streamKey); err != nil {
+			slog.Warn("⚠️ Could not finalize stream", "stream_key", streamKey, "error", err)
+		}
+	}
+
+	return nil
+}
+
+func (s *StreamService) finalizeDisconnectedStream(ctx context.Context, streamKey string) error {
+	var stream *models.Stream
+	var duration int64
+	endedAt := time.Now()
+
+	session, err := s.GetStreamSession(streamKey)
+	if err == nil {
+		if stream, err = s.GetStreamByIDInternal(session.StreamID); err != nil {
+			return fmt.Errorf("stream not found: %w", err)
+		}
+		duration = session.LiveSeconds
+		if session.Reconnecting() {
+			endedAt = session.DisconnectedTime()
+		}
+	} else {
+		// The session expired, fall back to what the stream record knows
+		if stream, err = s.dynamoRepo.GetStreamByStreamKey(streamKey); err != nil {
+			return fmt.Errorf("stream not found: %w", err)
+		}
+		endedAt = stream.UpdatedAt
+		if stream.StartedAt != nil {
+			duration = int64(endedAt.Sub(*stream.StartedAt).Seconds())
+		}
+	}
+
+	if stream.Status != models.StreamStatusReconnecting {
+		return nil
+	}
+
+	stream.Status = models.StreamStatusEnded
+	stream.EndedAt = &endedAt
+	stream.Duration = duration
+	stream.EndReason = models.EndReasonReconnectTimeout
+	stream.UpdatedAt = time.Now()
+	stopRestreams(stream, stream.UpdatedAt)
+	s.FinalizeStreamAnalytics(stream)
+	ended := StreamEvent{
+		Type: "stream_ended",
+		Data: map[string]interface{}{
+			"stream_id": stream.ID,
+			"user_id":   stream.UserID,
+			"duration":  duration,
+			"metadata": map[string]interface{}{
+				"stream_key": streamKey,
+				"end_reason": models.EndReasonReconnectTimeout,
+			},
+		},
+	}
+	if err := s.UpdateStreamInternal(stream, ended); err != nil {
+		return err
+	}
+	s.Audit(ctx, stream.ID, models.AuditStreamEnded, map[string]string{
+		"end_reason": stream.EndReason,
+		"duration":   strconv.FormatInt(duration, 10),
+	})
+
+	if err := s.CleanupStreamSession(streamKey); err != nil {
+		slog.Warn("⚠️ Could not cleanup stream session", "stream_id", stream.ID, "error", err)
+	}
+
+	slog.Info("✅ Stream ended after the broadcaster didn't reconnect", "stream_id", stream.ID, "duration", duration)
+	return nil
+}
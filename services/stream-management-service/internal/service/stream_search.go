@@ -0,0 +1,198 @@
+// services/stream-management-service/internal/service/stream_search.go
+package service
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/services/stream-management-service/internal/classifier"
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/services/stream-management-service/internal/models"
+)
+
+// Search sort orders
+const (
+	SearchSortViewers = "viewers"
+	SearchSortRecent  = "recent"
+)
+
+// StreamSearch filters and orders the listed live streams
+type StreamSearch struct {
+	Query      string // matched against title, category and tags
+	Category   string
+	Language   string // BCP-47, "en" also matches "en-US"
+	MinViewers int
+	MaxViewers int // 0 for no upper bound
+	Sort       string
+	Limit      int
+	Offset     int
+}
+
+// StreamSearchResult is a page of search results
+type StreamSearchResult struct {
+	Streams    []*models.Stream
+	Total      int
+	NextCursor string
+}
+
+// Normalize fills in the defaults and checks the search, categories are matched normalized
+func (q *StreamSearch) Normalize() error {
+	q.Query = strings.TrimSpace(q.Query)
+	if q.Category != "" {
+		normalized := classifier.NormalizeTags([]string{q.Category})
+		if len(normalized) == 0 {
+			return fmt.Errorf("invalid category")
+		}
+		q.Category = normalized[0]
+	}
+	language, err := NormalizeLanguage(q.Language)
+	if err != nil {
+		return err
+	}
+	q.Language = language
+	if q.MinViewers < 0 || q.MaxViewers < 0 {
+		return fmt.Errorf("viewer bounds must not be negative")
+	}
+	if q.MaxViewers > 0 && q.MaxViewers < q.MinViewers {
+		return fmt.Errorf("max_viewers must not be below min_viewers")
+	}
+	if q.Sort == "" {
+		q.Sort = SearchSortViewers
+	}
+	if q.Sort != SearchSortViewers && q.Sort != SearchSortRecent {
+		return fmt.Errorf("sort must be viewers or recent")
+	}
+	if q.Limit <= 0 {
+		q.Limit = defaultPageLimit
+	}
+	q.Limit = min(q.Limit, maxPageLimit)
+	if q.Offset < 0 {
+		return fmt.Errorf("invalid cursor")
+	}
+	return nil
+}
+
+// SearchStreams handles GET /api/v1/streams/search?q=&category=&language=&min_viewers=&max_viewers=&sort=viewers|recent
+// over the listed live streams. Pages are read with limit and cursor.
+func (s *StreamService) SearchStreams(c *gin.Context) {
+	limit, offset, ok := parseOffsetPagination(c)
+	if !ok {
+		return
+	}
+	search := StreamSearch{
+		Query:    c.Query("q"),
+		Category: c.Query("category"),
+		Language: c.Query("language"),
+		Sort:     c.Query("sort"),
+		Limit:    limit,
+		Offset:   offset,
+	}
+
+	for param, bound := range map[string]*int{"min_viewers": &search.MinViewers, "max_viewers": &search.MaxViewers} {
+		value := c.Query(param)
+		if value == "" {
+			continue
+		}
+		parsed, err := strconv.Atoi(value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("%s must be a number", param)})
+			return
+		}
+		*bound = parsed
+	}
+
+	if err := search.Normalize(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	result, err := s.SearchStreamsInternal(search)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not search streams"})
+		return
+	}
+	s.AttachFollowInfo(result.Streams, ViewerID(c))
+
+	c.JSON(http.StatusOK, gin.H{
+		"streams":     models.PublicStreams(result.Streams),
+		"count":       len(result.Streams),
+		"total":       result.Total,
+		"next_cursor": result.NextCursor,
+	})
+}
+
+// SearchStreamsInternal runs a normalized search. A category narrows the candidates to the
+// category aggregates before any stream is loaded, other filters apply to the live streams.
+func (s *StreamService) SearchStreamsInternal(search StreamSearch) (*StreamSearchResult, error) {
+	var streams []*models.Stream
+	if search.Category != "" {
+		entries, err := s.redisRepo.GetCategoryStreams(search.Category)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get category streams: %w", err)
+		}
+		ids := make([]string, len(entries))
+		for i, entry := range entries {
+			ids[i] = entry.StreamID
+		}
+		if streams, err = s.dynamoRepo.GetStreamsByIDs(ids); err != nil {
+			return nil, fmt.Errorf("failed to get streams: %w", err)
+		}
+	} else {
+		var err error
+		if streams, err = s.GetActiveStreamsInternal(); err != nil {
+			return nil, fmt.Errorf("failed to get active streams: %w", err)
+		}
+	}
+	streams = withoutUnlistedStreams(withoutBlockedStreams(streams))
+
+	query := strings.ToLower(search.Query)
+	matches := make([]*models.Stream, 0, len(streams))
+	for _, stream := range streams {
+		switch {
+		case stream.Status != models.StreamStatusLive:
+		case search.Category != "" && stream.Category != search.Category:
+		case !matchesLanguage(stream.Language, search.Language):
+		case stream.ViewerCount < search.MinViewers:
+		case search.MaxViewers > 0 && stream.ViewerCount > search.MaxViewers:
+		case query != "" && !matchesQuery(stream, query):
+		default:
+			matches = append(matches, stream)
+		}
+	}
+
+	sort.Slice(matches, func(i, j int) bool {
+		a, b := matches[i], matches[j]
+		switch {
+		case search.Sort == SearchSortViewers && a.ViewerCount != b.ViewerCount:
+			return a.ViewerCount > b.ViewerCount
+		case search.Sort == SearchSortRecent && !startedAt(a).Equal(startedAt(b)):
+			return startedAt(a).After(startedAt(b))
+		}
+		return a.ID < b.ID
+	})
+
+	page, nextCursor := paginate(len(matches), search.Limit, search.Offset)
+	return &StreamSearchResult{
+		Streams:    matches[page.start:page.end],
+		Total:      len(matches),
+		NextCursor: nextCursor,
+	}, nil
+}
+
+// matchesQuery reports whether a lowercased query is part of the stream's title, category or
+// one of its tags
+func matchesQuery(stream *models.Stream, query string) bool {
+	if strings.Contains(strings.ToLower(stream.Title), query) || strings.Contains(stream.Category, query) {
+		return true
+	}
+	for _, tag := range stream.Tags {
+		if strings.Contains(tag, query) {
+			return true
+		}
+	}
+	return false
+}
@@ -2,91 +2,201 @@
 package service
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
-	_ "log"
+	"log/slog"
+	"net/http"
+	"os"
+	"sort"
 	"strconv"
 	"time"
 
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/services/stream-management-service/internal/classifier"
 	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/services/stream-management-service/internal/config"
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/services/stream-management-service/internal/contentfilter"
 	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/services/stream-management-service/internal/models"
 	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/services/stream-management-service/internal/repository"
 	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/services/stream-management-service/pkg/aws"
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/services/stream-management-service/pkg/srs"
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/services/stream-management-service/pkg/tracing"
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/shared/go/pkg/events"
 	"github.com/gin-gonic/gin"
 )
 
 type StreamService struct {
 	config        *config.Config
-	dynamoRepo    *repository.DynamoDBRepository
-	redisRepo     *repository.RedisRepository
-	kinesisClient *aws.KinesisClient
+	dynamoRepo    repository.StreamStore
+	redisRepo     repository.StreamCache
+	publisher     events.Bus
 	s3Client      *aws.S3Client
+	srsClient     *srs.Client
+	chatClient    *http.Client
+	eventSchemas  *events.Registry
+	eventBus      *EventBus
+	classifier    classifier.Classifier
+	contentFilter *contentfilter.Filter
+	liveDirectory liveDirectoryRefresher
+	taskLocks     *TaskLocker
+
+	// healthChecks run after every recorded health sample, they are added at startup
+	healthChecks []func(ctx context.Context, streamID string)
+
+	// Features switched off by the startup preflight
+	eventsDisabled  bool
+	uploadsDisabled bool
 }
 
-func NewStreamService(cfg *config.Config, dynamoRepo *repository.DynamoDBRepository, redisRepo *repository.RedisRepository) *StreamService {
+func NewStreamService(cfg *config.Config, dynamoRepo repository.StreamStore, redisRepo repository.StreamCache) *StreamService {
+	eventSchemas, err := events.NewRegistry()
+	if err != nil {
+		slog.Error("❌ Failed to load event schemas", "error", err)
+		os.Exit(1)
+	}
+
+	publisher, err := newEventPublisher(cfg, &eventSpool{redisRepo: redisRepo, maxLen: cfg.EventSpoolMaxLen})
+	if err != nil {
+		slog.Error("❌ Failed to set up event publishing", "error", err)
+		os.Exit(1)
+	}
+
 	return &StreamService{
 		config:        cfg,
 		dynamoRepo:    dynamoRepo,
 		redisRepo:     redisRepo,
-		kinesisClient: aws.NewKinesisClient(cfg.AWSRegion, cfg.KinesisStreamName),
+		publisher:     publisher,
 		s3Client:      aws.NewS3Client(cfg.AWSRegion, cfg.S3BucketName),
+		srsClient:     srs.NewClient(cfg.SRSAPIURL, cfg.Caller(config.DependencySRS).Transport(nil)),
+		chatClient:    chatServiceClient(cfg),
+		eventSchemas:  eventSchemas,
+		eventBus:      NewEventBus(),
+		classifier:    classifier.NewKeywordClassifier(),
+		contentFilter: newContentFilter(cfg),
+		taskLocks:     NewTaskLocker(redisRepo, cfg.TaskLockTTL),
 	}
 }
 
-func (s *StreamService) CreateStream(stream *models.Stream) (string, error) {
+// CreateStream stores a new stream under a generated ID. Events about it are written to the
+// outbox with it, their stream_id is set to the new ID.
+func (s *StreamService) CreateStream(ctx context.Context, stream *models.Stream, events ...StreamEvent) (string, error) {
+	ctx, span := tracing.Start(ctx, "StreamService.CreateStream", tracing.SpanKindInternal)
+	defer span.End()
+
 	// Generate unique stream ID
 	stream.ID = s.generateStreamID()
+	span.SetAttribute("stream.id", stream.ID)
+	span.SetAttribute("user.id", stream.UserID)
+	for _, event := range events {
+		event.Data["stream_id"] = stream.ID
+	}
 
 	// Store in DynamoDB
-	err := s.dynamoRepo.CreateStream(stream)
+	var err error
+	if len(events) > 0 {
+		err = s.putStreamWithEvents(ctx, stream, events)
+	} else {
+		err = s.dynamoRepo.CreateStream(ctx, stream)
+	}
 	if err != nil {
+		span.RecordError(err)
 		return "", fmt.Errorf("failed to create stream in DynamoDB: %w", err)
 	}
 
 	// Cache in Redis
 	streamJSON, _ := json.Marshal(stream)
 	s.redisRepo.SetStreamData(stream.ID, string(streamJSON), 24*time.Hour)
+	s.countNewStream(stream.CreatedAt)
+	s.trackCategory(stream)
+	s.invalidateLiveDirectory(stream)
+	s.Audit(ctx, stream.ID, models.AuditStreamCreated, map[string]string{
+		"status":     string(stream.Status),
+		"title":      stream.Title,
+		"stream_key": stream.StreamKey,
+	})
 
 	return stream.ID, nil
 }
 
+// GetStreamByID handles GET /streams/:id. Only the broadcaster sees the whole stream, everyone
+// else gets its public copy and private streams are not found for them.
 func (s *StreamService) GetStreamByID(c *gin.Context) {
 	streamID := c.Param("id")
 
 	// Try Redis first
+	var stream *models.Stream
 	streamData, err := s.redisRepo.GetStreamData(streamID)
 	if err == nil && streamData != "" {
-		var stream models.Stream
-		if json.Unmarshal([]byte(streamData), &stream) == nil {
-			c.JSON(200, stream)
-			return
+		var cached models.Stream
+		if json.Unmarshal([]byte(streamData), &cached) == nil {
+			stream = &cached
 		}
 	}
 
 	// Fallback to DynamoDB
-	stream, err := s.dynamoRepo.GetStreamByID(streamID)
-	if err != nil {
-		c.JSON(404, gin.H{"error": "Stream not found"})
+	if stream == nil {
+		stream, err = s.dynamoRepo.GetStreamByID(streamID)
+		if err != nil {
+			respondError(c, http.StatusNotFound, ErrCodeNotFound, "Stream not found")
+			return
+		}
+	}
+
+	owner := isOwner(c, stream.UserID)
+	if stream.Deleted() || (!owner && stream.EffectiveVisibility() == models.StreamVisibilityPrivate) {
+		respondError(c, http.StatusNotFound, ErrCodeNotFound, "Stream not found")
+		return
+	}
+	if stream.Blocked {
+		respondTakedown(c, stream.TakedownID)
 		return
 	}
 
+	s.AttachStreamHealth(stream)
+	if !owner {
+		stream = stream.Public()
+	}
 	c.JSON(200, stream)
 }
 
+// GetActiveStreams handles GET /api/v1/streams with every live stream, and GET /api/v2/streams
+// with them a page at a time, most watched first
 func (s *StreamService) GetActiveStreams(c *gin.Context) {
-	streams, err := s.dynamoRepo.GetStreamsByStatus(models.StreamStatusLive)
+	paginated := RequestAPIVersion(c) >= APIV2
+	var limit, offset int
+	if paginated {
+		var ok bool
+		if limit, offset, ok = parseOffsetPagination(c); !ok {
+			return
+		}
+	}
+
+	streams, err := s.GetActiveStreamsInternal()
 	if err != nil {
-		c.JSON(500, gin.H{"error": "Could not get active streams"})
+		respondError(c, http.StatusInternalServerError, ErrCodeInternal, "Could not get active streams")
 		return
 	}
+	streams = withoutUnlistedStreams(withoutBlockedStreams(streams))
+
+	var nextCursor string
+	if paginated {
+		sort.Slice(streams, func(i, j int) bool {
+			if streams[i].ViewerCount != streams[j].ViewerCount {
+				return streams[i].ViewerCount > streams[j].ViewerCount
+			}
+			return streams[i].ID < streams[j].ID
+		})
+		var page pageBounds
+		page, nextCursor = paginate(len(streams), limit, offset)
+		streams = streams[page.start:page.end]
+	}
 
-	c.JSON(200, gin.H{
-		"streams": streams,
-		"count":   len(streams),
-	})
+	s.AttachFollowInfo(streams, ViewerID(c))
+
+	respondList(c, http.StatusOK, "streams", models.PublicStreams(streams), len(streams), nextCursor, nil)
 }
 
-func (s *StreamService) EndStream(streamKey string, duration string) error {
+func (s *StreamService) EndStream(ctx context.Context, streamKey string, duration string) error {
 	// Find stream by stream key
 	stream, err := s.dynamoRepo.GetStreamByStreamKey(streamKey)
 	if err != nil {
@@ -101,87 +211,256 @@ func (s *StreamService) EndStream(streamKey string, duration string) error {
 		}
 	}
 
+	return s.endStream(ctx, stream, durationSec, models.EndReasonNormal)
+}
+
+// GetActiveStreamKeyBan returns the ban in force for a stream key, or nil if it isn't banned
+func (s *StreamService) GetActiveStreamKeyBan(streamKey string) (*models.StreamKeyBan, error) {
+	ban, err := s.dynamoRepo.GetStreamKeyBan(streamKey)
+	if errors.Is(err, repository.ErrStreamKeyNotBanned) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !ban.Active(time.Now()) {
+		return nil, nil
+	}
+	return ban, nil
+}
+
+// InvalidateStreamKeyValidation drops the user service result cached for a stream key, so the
+// next publish on it is validated again
+func (s *StreamService) InvalidateStreamKeyValidation(streamKey string) {
+	if err := s.redisRepo.DeleteStreamKeyValidation(streamKey); err != nil {
+		slog.Warn("⚠️ Could not invalidate stream key validation", "error", err)
+	}
+}
+
+// TerminateStream ends a stream on an admin's behalf. The publisher has to be dropped from
+// the media server separately.
+func (s *StreamService) TerminateStream(ctx context.Context, stream *models.Stream) error {
+	return s.StopStream(ctx, stream, models.EndReasonTerminated)
+}
+
+// StopStream ends a stream before its broadcaster did, for reason. The publisher has to be
+// dropped from the media server separately.
+func (s *StreamService) StopStream(ctx context.Context, stream *models.Stream, reason string) error {
+	durationSec := int64(0)
+	if stream.StartedAt != nil {
+		durationSec = int64(time.Since(*stream.StartedAt).Seconds())
+	}
+	reconnecting := stream.Status == models.StreamStatusReconnecting
+
+	if err := s.endStream(ctx, stream, durationSec, reason); err != nil {
+		return err
+	}
+
+	// A stream waiting for its broadcaster has no publisher left to report the end, so
+	// nothing must be resumed from its session
+	if reconnecting {
+		if _, err := s.redisRepo.RemoveReconnecting(stream.StreamKey); err != nil {
+			slog.Warn("⚠️ Could not drop reconnect deadline", "stream_id", stream.ID, "error", err)
+		}
+		if err := s.CleanupStreamSession(stream.StreamKey); err != nil {
+			slog.Warn("⚠️ Could not cleanup stream session", "stream_id", stream.ID, "error", err)
+		}
+	}
+	return nil
+}
+
+func (s *StreamService) endStream(ctx context.Context, stream *models.Stream, durationSec int64, reason string) error {
+	// A repeated callback must not end the stream (and count its minutes) twice
+	if stream.Status == models.StreamStatusEnded {
+		slog.Info("ℹ️ Stream already ended", "stream_id", stream.ID)
+		return nil
+	}
+
 	// Update stream
 	now := time.Now()
 	stream.Status = models.StreamStatusEnded
 	stream.EndedAt = &now
 	stream.Duration = durationSec
+	stream.EndReason = reason
 	stream.UpdatedAt = now
-
-	// Update in DynamoDB
-	err = s.dynamoRepo.UpdateStream(stream)
-	if err != nil {
+	stopRestreams(stream, now)
+	// The media server reports the recording once it has closed the file
+	if stream.RecordingStatus == "" {
+		stream.RecordingStatus = models.RecordingStatusPending
+	}
+	s.applyRetention(stream)
+	s.FinalizeStreamAnalytics(stream)
+
+	// Update in DynamoDB, with the stream ended event in the outbox
+	ended := StreamEvent{
+		Type: "stream_ended",
+		Data: map[string]interface{}{
+			"stream_id": stream.ID,
+			"user_id":   stream.UserID,
+			"duration":  durationSec,
+			"metadata": map[string]interface{}{
+				"stream_key": stream.StreamKey,
+				"end_reason": reason,
+			},
+		},
+	}
+	if err := s.putStreamWithEvents(ctx, stream, []StreamEvent{ended}); err != nil {
 		return fmt.Errorf("failed to update stream: %w", err)
 	}
 
 	// Update cache
 	streamJSON, _ := json.Marshal(stream)
 	s.redisRepo.SetStreamData(stream.ID, string(streamJSON), time.Hour)
+	s.trackCategory(stream)
+	s.invalidateLiveDirectory(stream)
 
-	// Publish stream ended event
-	event := map[string]interface{}{
-		"event_type": "stream_ended",
-		"stream_id":  stream.ID,
-		"user_id":    stream.UserID,
-		"duration":   durationSec,
-		"timestamp":  time.Now().Unix(),
+	action, details := models.AuditStreamEnded, map[string]string{
+		"end_reason": reason,
+		"duration":   strconv.FormatInt(durationSec, 10),
 	}
-	s.PublishEvent(event)
+	if reason == models.EndReasonTerminated {
+		action = models.AuditStreamTerminated
+		details["termination_reason"] = stream.Metadata["termination_reason"]
+	}
+	s.Audit(ctx, stream.ID, action, details)
+	s.publishLatencySummary(stream)
 
 	return nil
 }
 
-func (s *StreamService) UpdateStreamRecording(streamKey string, filePath string) error {
+// UpdateStreamRecording stores the recording the media server reported for a stream. It is
+// left uploading for a RecordingService worker to move to S3, unless uploads are disabled.
+func (s *StreamService) UpdateStreamRecording(ctx context.Context, streamKey string, filePath string) (*models.Stream, error) {
 	// Find stream by stream key
 	stream, err := s.dynamoRepo.GetStreamByStreamKey(streamKey)
 	if err != nil {
-		return fmt.Errorf("stream not found: %w", err)
+		return nil, fmt.Errorf("stream not found: %w", err)
 	}
 
-	// Upload to S3 (optional, or just store the file path)
-	recordingURL := filePath // For now, just store the path
-	// TODO: Implement S3 upload if needed
-	// recordingURL, err = s.s3Client.UploadRecording(filePath)
-
-	// Update stream with recording URL
-	stream.RecordingURL = recordingURL
+	// The local path is the recording URL until the upload succeeds
+	stream.RecordingPath = filePath
+	stream.RecordingURL = filePath
+	stream.RecordingAttempts = 0
+	stream.RecordingError = ""
+	stream.RecordingRetryAt = nil
+	stream.RecordingStatus = models.RecordingStatusUploading
+	if s.uploadsDisabled {
+		stream.RecordingStatus = models.RecordingStatusReady
+	}
 	stream.UpdatedAt = time.Now()
 
-	err = s.dynamoRepo.UpdateStream(stream)
-	if err != nil {
-		return fmt.Errorf("failed to update stream recording: %w", err)
+	if err := s.saveRecording(stream); err != nil {
+		return nil, err
 	}
+	s.Audit(ctx, stream.ID, models.AuditRecordingCompleted, map[string]string{"recording_url": stream.RecordingURL})
 
-	// Update cache
-	streamJSON, _ := json.Marshal(stream)
-	s.redisRepo.SetStreamData(stream.ID, string(streamJSON), time.Hour)
+	return stream, nil
+}
 
-	return nil
+func (s *StreamService) StoreStreamSession(streamKey string, session *models.StreamSession) error {
+	return s.storeSession(streamKey, session, s.config.StreamSessionTTL)
 }
 
-func (s *StreamService) StoreStreamSession(streamKey string, sessionData map[string]interface{}) error {
-	sessionJSON, _ := json.Marshal(sessionData)
-	return s.redisRepo.SetStreamSession(streamKey, string(sessionJSON), time.Hour)
+// applyRetention sets when DynamoDB deletes an ended stream, and clears it for streams that
+// aren't ended
+func (s *StreamService) applyRetention(stream *models.Stream) {
+	if stream.Status != models.StreamStatusEnded || s.config.EndedStreamRetention <= 0 {
+		stream.ExpiresAt = 0
+		return
+	}
+	if stream.ExpiresAt != 0 {
+		return
+	}
+
+	endedAt := stream.UpdatedAt
+	if stream.EndedAt != nil {
+		endedAt = *stream.EndedAt
+	}
+	stream.ExpiresAt = endedAt.Add(s.config.EndedStreamRetention).Unix()
 }
 
-func (s *StreamService) GetStreamSession(streamKey string) (map[string]interface{}, error) {
+func (s *StreamService) GetStreamSession(streamKey string) (*models.StreamSession, error) {
 	sessionData, err := s.redisRepo.GetStreamSession(streamKey)
 	if err != nil {
 		return nil, err
 	}
 
-	var session map[string]interface{}
-	err = json.Unmarshal([]byte(sessionData), &session)
-	return session, err
+	var session models.StreamSession
+	if err := json.Unmarshal([]byte(sessionData), &session); err != nil {
+		return nil, fmt.Errorf("invalid stream session: %w", err)
+	}
+	return &session, nil
+}
+
+// StreamKeyForPlayback returns the stream key of the session a playback ID was given to
+func (s *StreamService) StreamKeyForPlayback(playbackID string) (string, error) {
+	return s.redisRepo.GetPlaybackStreamKey(playbackID)
 }
 
 func (s *StreamService) CleanupStreamSession(streamKey string) error {
 	return s.redisRepo.DeleteStreamSession(streamKey)
 }
 
-func (s *StreamService) PublishEvent(event map[string]interface{}) error {
-	eventJSON, _ := json.Marshal(event)
-	return s.kinesisClient.PutRecord(string(eventJSON))
+// PublishEvent validates an event against its schema and queues it for the event bus, it is
+// sent in the background with the next batch
+func (s *StreamService) PublishEvent(eventType string, data map[string]interface{}) error {
+	envelope, err := s.eventSchemas.NewEnvelope("stream-management-service", eventType, data)
+	if err != nil {
+		return fmt.Errorf("invalid %s event: %w", eventType, err)
+	}
+	s.eventBus.publish(envelope)
+
+	eventJSON, err := json.Marshal(envelope)
+	if err != nil {
+		return fmt.Errorf("failed to marshal event: %w", err)
+	}
+
+	// Kept for whichever replica finds the bus available again
+	if s.eventsDisabled {
+		slog.Debug("📡 [DISABLED] Spooling event, the event bus is unavailable", "event_type", eventType)
+		return s.publisher.SpoolRecord(eventJSON)
+	}
+	if err := s.publisher.Publish(eventJSON); err != nil {
+		return fmt.Errorf("failed to queue %s event: %w", eventType, err)
+	}
+	return nil
+}
+
+// EventBus returns the bus the events this replica publishes are handed to in-process
+func (s *StreamService) EventBus() *EventBus {
+	return s.eventBus
+}
+
+// EventPublisherStats returns the counters of the event queue
+func (s *StreamService) EventPublisherStats() events.PublisherStats {
+	return s.publisher.Stats()
+}
+
+// FlushEvents sends the queued events before shutdown
+func (s *StreamService) FlushEvents(ctx context.Context) error {
+	return s.publisher.Close(ctx)
+}
+
+// VerifyEventStream checks that the Kinesis stream or Kafka topics used for events exist
+func (s *StreamService) VerifyEventStream() error {
+	return s.publisher.Verify()
+}
+
+// VerifyRecordingBucket checks that the S3 bucket used for recordings exists
+func (s *StreamService) VerifyRecordingBucket() error {
+	return s.s3Client.VerifyBucket()
+}
+
+// DisableEventPublishing stops publishing events to the event bus
+func (s *StreamService) DisableEventPublishing(reason error) {
+	slog.Warn("🚫 Event publishing disabled", "reason", reason)
+	s.eventsDisabled = true
+}
+
+// DisableRecordingUploads keeps recordings on local disk instead of uploading them to S3
+func (s *StreamService) DisableRecordingUploads(reason error) {
+	slog.Warn("🚫 Recording uploads disabled", "reason", reason)
+	s.uploadsDisabled = true
 }
 
 //func (s *StreamService) generateStreamID() string {
@@ -2,11 +2,13 @@
 package service
 
 import (
+	"context"
 	"crypto/rand"
 	"encoding/hex"
 	"encoding/json"
 	"fmt"
-	"strings"
+	"log/slog"
+	"strconv"
 	"time"
 
 	_ "github.com/Saoudyahya/Live-Streaming-Platform-Architecture/services/stream-management-service/internal/config"
@@ -32,20 +34,58 @@ func (s *StreamService) GetStreamByIDInternal(streamID string) (*models.Stream,
 	return s.dynamoRepo.GetStreamByID(streamID)
 }
 
+// GetStreamsByIDsInternal gets several streams, from Redis where cached and with a single
+// DynamoDB batch read for the rest. Unknown IDs are missing from the result.
+func (s *StreamService) GetStreamsByIDsInternal(streamIDs []string) (map[string]*models.Stream, error) {
+	streams := make(map[string]*models.Stream, len(streamIDs))
+
+	cached, err := s.redisRepo.GetStreamDataBatch(streamIDs)
+	if err != nil {
+		slog.Warn("⚠️ Could not read cached streams", "error", err)
+	}
+	var uncached []string
+	for _, id := range streamIDs {
+		var stream models.Stream
+		if data, ok := cached[id]; ok && json.Unmarshal([]byte(data), &stream) == nil {
+			streams[id] = &stream
+			continue
+		}
+		uncached = append(uncached, id)
+	}
+
+	if len(uncached) == 0 {
+		return streams, nil
+	}
+
+	stored, err := s.dynamoRepo.GetStreamsByIDs(uncached)
+	if err != nil {
+		return nil, err
+	}
+	for _, stream := range stored {
+		streams[stream.ID] = stream
+	}
+
+	return streams, nil
+}
+
 // GetStreamByStreamKeyInternal gets a stream by stream key for internal use
 func (s *StreamService) GetStreamByStreamKeyInternal(streamKey string) (*models.Stream, error) {
 	return s.dynamoRepo.GetStreamByStreamKey(streamKey)
 }
 
-// GetActiveStreamsInternal gets active streams for internal use (used by gRPC server)
-func (s *StreamService) GetActiveStreamsInternal() ([]*models.Stream, error) {
-	return s.dynamoRepo.GetStreamsByStatus(models.StreamStatusLive)
-}
+// UpdateStreamInternal updates a stream for internal use (used by gRPC server). The stream is
+// written whole, so changes made since it was read are lost, and with global tables it may
+// have been read from a replica behind the write region (see DynamoDBRepository.UpdateStream).
+func (s *StreamService) UpdateStreamInternal(stream *models.Stream, events ...StreamEvent) error {
+	s.applyRetention(stream)
 
-// UpdateStreamInternal updates a stream for internal use (used by gRPC server)
-func (s *StreamService) UpdateStreamInternal(stream *models.Stream) error {
-	// Update in DynamoDB
-	err := s.dynamoRepo.UpdateStream(stream)
+	// Update in DynamoDB, with the events about the change in the outbox
+	var err error
+	if len(events) > 0 {
+		err = s.putStreamWithEvents(context.Background(), stream, events)
+	} else {
+		err = s.dynamoRepo.UpdateStream(stream)
+	}
 	if err != nil {
 		return fmt.Errorf("failed to update stream in DynamoDB: %w", err)
 	}
@@ -53,6 +93,8 @@ func (s *StreamService) UpdateStreamInternal(stream *models.Stream) error {
 	// Update cache
 	streamJSON, _ := json.Marshal(stream)
 	s.redisRepo.SetStreamData(stream.ID, string(streamJSON), 24*time.Hour)
+	s.trackCategory(stream)
+	s.invalidateLiveDirectory(stream)
 
 	return nil
 }
@@ -106,7 +148,7 @@ func (s *StreamService) GetStreamMetrics(streamID string) (map[string]interface{
 func (s *StreamService) GetUserStreams(userID int64, limit int) ([]*models.Stream, error) {
 	// This would require a GSI on user_id in DynamoDB
 	// For now, we'll scan (not efficient for production)
-	allStreams, err := s.dynamoRepo.GetStreamsByStatus(models.StreamStatusLive)
+	allStreams, err := s.dynamoRepo.GetStreamsByStatus(models.StreamStatusLive, models.DeletedExclude)
 	if err != nil {
 		return nil, err
 	}
@@ -126,6 +168,11 @@ func (s *StreamService) GetUserStreams(userID int64, limit int) ([]*models.Strea
 	return userStreams, nil
 }
 
+// ViewerCountRequest is the body of POST /api/v1/streams/:id/viewers
+type ViewerCountRequest struct {
+	ViewerCount int `json:"viewer_count"`
+}
+
 // UpdateViewerCount updates the viewer count for a stream
 func (s *StreamService) UpdateViewerCount(streamID string, viewerCount int) error {
 	stream, err := s.GetStreamByIDInternal(streamID)
@@ -176,18 +223,13 @@ func (s *StreamService) StopStreamRecording(streamID, recordingPath string) erro
 
 // GetPlatformStats gets platform-wide statistics
 func (s *StreamService) GetPlatformStats() (map[string]interface{}, error) {
-	liveStreams, err := s.GetActiveStreamsInternal()
+	liveStreams, totalViewers, err := s.platformSnapshot()
 	if err != nil {
 		return nil, err
 	}
 
-	totalViewers := 0
-	for _, stream := range liveStreams {
-		totalViewers += stream.ViewerCount
-	}
-
 	stats := map[string]interface{}{
-		"live_streams":  len(liveStreams),
+		"live_streams":  liveStreams,
 		"total_viewers": totalViewers,
 		"last_updated":  time.Now().Unix(),
 	}
@@ -199,8 +241,9 @@ func (s *StreamService) GetPlatformStats() (map[string]interface{}, error) {
 	return stats, nil
 }
 
-// CleanupExpiredStreams cleans up streams that have been stuck in "live" status
-func (s *StreamService) CleanupExpiredStreams() error {
+// CleanupExpiredStreams cleans up streams that have been stuck in "live" status. The job
+// scheduler runs it on one replica at a time.
+func (s *StreamService) CleanupExpiredStreams(ctx context.Context) error {
 	liveStreams, err := s.GetActiveStreamsInternal()
 	if err != nil {
 		return err
@@ -210,6 +253,11 @@ func (s *StreamService) CleanupExpiredStreams() error {
 	now := time.Now()
 
 	for _, stream := range liveStreams {
+		// The lock was lost, whoever has it now takes over
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
 		// Consider streams expired if they've been live for more than 12 hours without updates
 		if stream.StartedAt != nil && now.Sub(*stream.StartedAt) > 12*time.Hour {
 			if stream.UpdatedAt.Before(now.Add(-1 * time.Hour)) {
@@ -218,20 +266,23 @@ func (s *StreamService) CleanupExpiredStreams() error {
 				stream.EndedAt = &now
 				stream.Duration = int64(now.Sub(*stream.StartedAt).Seconds())
 				stream.UpdatedAt = now
+				s.FinalizeStreamAnalytics(stream)
 
 				if err := s.UpdateStreamInternal(stream); err != nil {
 					continue // Skip this one and continue
 				}
+				s.Audit(ctx, stream.ID, models.AuditStreamEnded, map[string]string{
+					"end_reason": "expired",
+					"duration":   strconv.FormatInt(stream.Duration, 10),
+				})
 
 				// Publish cleanup event
 				event := map[string]interface{}{
-					"event_type": "stream_cleanup",
-					"stream_id":  stream.ID,
-					"user_id":    stream.UserID,
-					"reason":     "expired",
-					"timestamp":  now.Unix(),
+					"stream_id": stream.ID,
+					"user_id":   stream.UserID,
+					"reason":    "expired",
 				}
-				s.PublishEvent(event)
+				s.PublishEvent("stream_cleanup", event)
 
 				expiredCount++
 			}
@@ -239,51 +290,15 @@ func (s *StreamService) CleanupExpiredStreams() error {
 	}
 
 	if expiredCount > 0 {
-		fmt.Printf("🧹 Cleaned up %d expired streams", expiredCount)
+		slog.InfoContext(ctx, "🧹 Cleaned up expired streams", "streams", expiredCount)
 	}
 
 	return nil
 }
 
-// SearchStreams searches for streams based on criteria
-func (s *StreamService) SearchStreams(query string, status models.StreamStatus, limit int) ([]*models.Stream, error) {
-	var streams []*models.Stream
-	var err error
-
-	if status != "" {
-		streams, err = s.dynamoRepo.GetStreamsByStatus(status)
-	} else {
-		// Get all live streams as default
-		streams, err = s.dynamoRepo.GetStreamsByStatus(models.StreamStatusLive)
-	}
-
-	if err != nil {
-		return nil, err
-	}
-
-	// If no query, return streams with limit
-	if query == "" {
-		if limit > 0 && len(streams) > limit {
-			return streams[:limit], nil
-		}
-		return streams, nil
-	}
-
-	// Simple text search in title and stream key
-	var filtered []*models.Stream
-	query = strings.ToLower(query)
-
-	for _, stream := range streams {
-		if strings.Contains(strings.ToLower(stream.Title), query) ||
-			strings.Contains(strings.ToLower(stream.StreamKey), query) {
-			filtered = append(filtered, stream)
-			if limit > 0 && len(filtered) >= limit {
-				break
-			}
-		}
-	}
-
-	return filtered, nil
+// TaskLockStats returns the lock counters of the background tasks run on this replica
+func (s *StreamService) TaskLockStats() []TaskLockStats {
+	return s.taskLocks.Stats()
 }
 
 // Helper method to generate stream IDs
@@ -292,3 +307,10 @@ func (s *StreamService) generateStreamID() string {
 	rand.Read(bytes)
 	return "stream_" + hex.EncodeToString(bytes)[:16]
 }
+
+// generatePlaybackID is random so a playback ID can't be traced back to its stream key
+func generatePlaybackID() string {
+	bytes := make([]byte, 16)
+	rand.Read(bytes)
+	return "pb_" + hex.EncodeToString(bytes)
+}
@@ -0,0 +1,281 @@
+// services/stream-management-service/internal/service/stream_service_test.go
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/services/stream-management-service/internal/config"
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/services/stream-management-service/internal/models"
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/services/stream-management-service/internal/repository/memory"
+)
+
+const testStreamKey = "sk_test_lifecycle"
+
+// newTestStreamService runs the stream service on the in-memory repositories, with Kinesis
+// and S3 in mock mode
+func newTestStreamService(t *testing.T) (*StreamService, *memory.StreamStore, *memory.StreamCache) {
+	t.Helper()
+	t.Setenv("ENVIRONMENT", "development")
+
+	cfg := config.Load()
+	if _, err := cfg.ReloadTunables(map[string]string{"RECONNECT_GRACE_PERIOD": "30s"}); err != nil {
+		t.Fatalf("ReloadTunables: %v", err)
+	}
+
+	store := memory.NewStreamStore()
+	cache := memory.NewStreamCache()
+	return NewStreamService(cfg, store, cache), store, cache
+}
+
+// startTestStream goes live the way the started callback does
+func startTestStream(t *testing.T, s *StreamService) (*models.Stream, *models.StreamSession) {
+	t.Helper()
+
+	now := time.Now()
+	stream := &models.Stream{
+		UserID:    42,
+		StreamKey: testStreamKey,
+		Title:     defaultStreamTitle(now),
+		Status:    models.StreamStatusLive,
+		Metadata:  map[string]string{},
+		StartedAt: &now,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	started := StreamEvent{
+		Type: "stream_started",
+		Data: map[string]interface{}{"user_id": stream.UserID},
+	}
+
+	streamID, err := s.CreateStream(context.Background(), stream, started)
+	if err != nil {
+		t.Fatalf("CreateStream: %v", err)
+	}
+
+	session := &models.StreamSession{
+		UserID:           stream.UserID,
+		StreamKey:        testStreamKey,
+		StartedAt:        now.Unix(),
+		StreamID:         streamID,
+		StreamStartedAt:  now.Unix(),
+		SegmentStartedAt: now.Unix(),
+	}
+	if err := s.StoreStreamSession(testStreamKey, session); err != nil {
+		t.Fatalf("StoreStreamSession: %v", err)
+	}
+	return stream, session
+}
+
+func getTestStream(t *testing.T, store *memory.StreamStore, streamID string) *models.Stream {
+	t.Helper()
+
+	stream, err := store.GetStreamByID(streamID)
+	if err != nil {
+		t.Fatalf("GetStreamByID(%s): %v", streamID, err)
+	}
+	return stream
+}
+
+// pendingEventTypes lists the event types waiting in the outbox for a stream
+func pendingEventTypes(t *testing.T, store *memory.StreamStore, streamID string) []string {
+	t.Helper()
+
+	pending, err := store.GetPendingOutboxEvents(100)
+	if err != nil {
+		t.Fatalf("GetPendingOutboxEvents: %v", err)
+	}
+	var types []string
+	for _, event := range pending {
+		if event.StreamID == streamID {
+			types = append(types, event.EventType)
+		}
+	}
+	return types
+}
+
+func TestStreamStart(t *testing.T) {
+	s, store, _ := newTestStreamService(t)
+	stream, _ := startTestStream(t, s)
+
+	stored := getTestStream(t, store, stream.ID)
+	if stored.Status != models.StreamStatusLive {
+		t.Errorf("status = %s, want %s", stored.Status, models.StreamStatusLive)
+	}
+	if stored.StartedAt == nil {
+		t.Error("StartedAt is not set")
+	}
+
+	byKey, err := store.GetStreamByStreamKey(testStreamKey)
+	if err != nil || byKey.ID != stream.ID {
+		t.Errorf("GetStreamByStreamKey = %v, %v, want stream %s", byKey, err, stream.ID)
+	}
+
+	if types := pendingEventTypes(t, store, stream.ID); len(types) != 1 || types[0] != "stream_started" {
+		t.Errorf("outbox events = %v, want [stream_started]", types)
+	}
+}
+
+func TestStreamSessionPlaybackID(t *testing.T) {
+	s, _, _ := newTestStreamService(t)
+	_, session := startTestStream(t, s)
+
+	if session.PlaybackID == "" || strings.Contains(session.PlaybackID, testStreamKey) {
+		t.Fatalf("playback ID = %q, want one apart from the stream key", session.PlaybackID)
+	}
+	streamKey, err := s.StreamKeyForPlayback(session.PlaybackID)
+	if err != nil || streamKey != testStreamKey {
+		t.Errorf("StreamKeyForPlayback = %q, %v, want %q", streamKey, err, testStreamKey)
+	}
+}
+
+func TestStreamReconnectWithinGracePeriod(t *testing.T) {
+	s, store, _ := newTestStreamService(t)
+	ctx := context.Background()
+	stream, session := startTestStream(t, s)
+
+	if err := s.MarkStreamReconnecting(ctx, testStreamKey, session, 60); err != nil {
+		t.Fatalf("MarkStreamReconnecting: %v", err)
+	}
+	if status := getTestStream(t, store, stream.ID).Status; status != models.StreamStatusReconnecting {
+		t.Fatalf("status after disconnect = %s, want %s", status, models.StreamStatusReconnecting)
+	}
+
+	// The broadcaster authenticates again with a fresh session
+	reconnected := &models.StreamSession{UserID: stream.UserID, StreamKey: testStreamKey, StartedAt: time.Now().Unix()}
+	s.CarryOverReconnectState(testStreamKey, reconnected)
+	if reconnected.StreamID != stream.ID {
+		t.Fatalf("carried over stream %q, want %q", reconnected.StreamID, stream.ID)
+	}
+	if reconnected.LiveSeconds != 60 {
+		t.Errorf("carried over %d live seconds, want 60", reconnected.LiveSeconds)
+	}
+	if reconnected.PlaybackID == "" || reconnected.PlaybackID != session.PlaybackID {
+		t.Errorf("carried over playback ID %q, want %q", reconnected.PlaybackID, session.PlaybackID)
+	}
+
+	resumedID, err := s.ResumeStream(ctx, testStreamKey, reconnected)
+	if err != nil {
+		t.Fatalf("ResumeStream: %v", err)
+	}
+	if resumedID != stream.ID {
+		t.Errorf("resumed stream %q, want %q", resumedID, stream.ID)
+	}
+	if status := getTestStream(t, store, stream.ID).Status; status != models.StreamStatusLive {
+		t.Errorf("status after reconnect = %s, want %s", status, models.StreamStatusLive)
+	}
+
+	// Nothing is left for the finalizer once the broadcaster is back
+	if err := s.FinalizeExpiredReconnects(ctx); err != nil {
+		t.Fatalf("FinalizeExpiredReconnects: %v", err)
+	}
+	if status := getTestStream(t, store, stream.ID).Status; status != models.StreamStatusLive {
+		t.Errorf("status after finalizing = %s, want %s", status, models.StreamStatusLive)
+	}
+}
+
+func TestStreamReconnectAfterGracePeriod(t *testing.T) {
+	s, store, cache := newTestStreamService(t)
+	ctx := context.Background()
+	stream, session := startTestStream(t, s)
+
+	if err := s.MarkStreamReconnecting(ctx, testStreamKey, session, 60); err != nil {
+		t.Fatalf("MarkStreamReconnecting: %v", err)
+	}
+	// Let the grace period run out
+	if err := cache.AddReconnecting(testStreamKey, time.Now().Add(-time.Second)); err != nil {
+		t.Fatalf("AddReconnecting: %v", err)
+	}
+
+	if err := s.FinalizeExpiredReconnects(ctx); err != nil {
+		t.Fatalf("FinalizeExpiredReconnects: %v", err)
+	}
+	ended := getTestStream(t, store, stream.ID)
+	if ended.Status != models.StreamStatusEnded {
+		t.Fatalf("status = %s, want %s", ended.Status, models.StreamStatusEnded)
+	}
+	if ended.EndReason != models.EndReasonReconnectTimeout {
+		t.Errorf("end reason = %s, want %s", ended.EndReason, models.EndReasonReconnectTimeout)
+	}
+	if ended.Duration != 60 {
+		t.Errorf("duration = %d, want the 60 seconds live before the disconnect", ended.Duration)
+	}
+
+	if _, err := s.ResumeStream(ctx, testStreamKey, session); err == nil {
+		t.Error("ResumeStream succeeded after the grace period")
+	}
+}
+
+func TestStreamEnd(t *testing.T) {
+	s, store, _ := newTestStreamService(t)
+	ctx := context.Background()
+	stream, _ := startTestStream(t, s)
+
+	if err := s.EndStream(ctx, testStreamKey, "120"); err != nil {
+		t.Fatalf("EndStream: %v", err)
+	}
+	ended := getTestStream(t, store, stream.ID)
+	if ended.Status != models.StreamStatusEnded {
+		t.Fatalf("status = %s, want %s", ended.Status, models.StreamStatusEnded)
+	}
+	if ended.Duration != 120 || ended.EndReason != models.EndReasonNormal || ended.EndedAt == nil {
+		t.Errorf("ended with duration %d, reason %s, ended at %v", ended.Duration, ended.EndReason, ended.EndedAt)
+	}
+	if ended.RecordingStatus != models.RecordingStatusPending {
+		t.Errorf("recording status = %s, want %s", ended.RecordingStatus, models.RecordingStatusPending)
+	}
+
+	// A repeated callback doesn't end the stream again
+	if err := s.EndStream(ctx, testStreamKey, "999"); err != nil {
+		t.Fatalf("repeated EndStream: %v", err)
+	}
+	if duration := getTestStream(t, store, stream.ID).Duration; duration != 120 {
+		t.Errorf("duration after repeated end = %d, want 120", duration)
+	}
+
+	types := pendingEventTypes(t, store, stream.ID)
+	if len(types) != 2 || types[1] != "stream_ended" {
+		t.Errorf("outbox events = %v, want [stream_started stream_ended]", types)
+	}
+}
+
+func TestStreamRecordingReady(t *testing.T) {
+	s, store, _ := newTestStreamService(t)
+	ctx := context.Background()
+	stream, _ := startTestStream(t, s)
+
+	if err := s.EndStream(ctx, testStreamKey, "120"); err != nil {
+		t.Fatalf("EndStream: %v", err)
+	}
+
+	const file = "/recordings/sk_test_lifecycle.flv"
+	updated, err := s.UpdateStreamRecording(ctx, testStreamKey, file)
+	if err != nil {
+		t.Fatalf("UpdateStreamRecording: %v", err)
+	}
+	if updated.ID != stream.ID {
+		t.Errorf("updated stream %q, want %q", updated.ID, stream.ID)
+	}
+
+	// The upload is left to a recording upload worker
+	queued := getTestStream(t, store, stream.ID)
+	if queued.RecordingStatus != models.RecordingStatusUploading || queued.RecordingAttempts != 0 {
+		t.Errorf("recording status = %s after %d attempts, want %s before any", queued.RecordingStatus, queued.RecordingAttempts, models.RecordingStatusUploading)
+	}
+
+	if err := s.UploadRecording(ctx, updated); err != nil {
+		t.Fatalf("UploadRecording: %v", err)
+	}
+	recorded := getTestStream(t, store, stream.ID)
+	if recorded.RecordingStatus != models.RecordingStatusReady {
+		t.Errorf("recording status = %s, want %s", recorded.RecordingStatus, models.RecordingStatusReady)
+	}
+	if recorded.RecordingPath != file || recorded.RecordingURL == "" {
+		t.Errorf("recording path %q, url %q", recorded.RecordingPath, recorded.RecordingURL)
+	}
+	if recorded.RecordingAttempts != 1 {
+		t.Errorf("recording attempts = %d, want 1", recorded.RecordingAttempts)
+	}
+}
@@ -0,0 +1,208 @@
+// services/stream-management-service/internal/service/stream_visibility.go
+package service
+
+import (
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/services/stream-management-service/internal/models"
+)
+
+const (
+	minPassphraseLength = 4
+	maxPassphraseLength = 128
+	maxStreamInvites    = 1000
+
+	// passphraseIterations follows the OWASP recommendation for PBKDF2-HMAC-SHA256
+	passphraseIterations = 600000
+)
+
+// StreamVisibilityRequest changes a stream's visibility. The passphrase and invites are only
+// replaced when given and only apply while the stream is private, an empty passphrase
+// removes it.
+type StreamVisibilityRequest struct {
+	Visibility     models.StreamVisibility `json:"visibility" binding:"required"`
+	Passphrase     *string                 `json:"passphrase"`
+	InvitedUserIDs *[]int64                `json:"invited_user_ids"`
+}
+
+// GetStreamVisibility handles GET /api/v1/streams/:id/visibility
+func (s *StreamService) GetStreamVisibility(c *gin.Context) {
+	stream, err := s.GetStreamByIDInternal(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Stream not found"})
+		return
+	}
+	if !authorizeOwner(c, stream.UserID) {
+		return
+	}
+
+	access, err := s.dynamoRepo.GetStreamAccess(stream.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not load stream access"})
+		return
+	}
+
+	c.JSON(http.StatusOK, visibilityResponse(stream, access))
+}
+
+// UpdateStreamVisibility handles PUT /api/v1/streams/:id/visibility. Like geo restrictions it
+// applies to playback authorized from now on, viewers already watching aren't dropped.
+func (s *StreamService) UpdateStreamVisibility(c *gin.Context) {
+	var req StreamVisibilityRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if !req.Visibility.Valid() {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "visibility must be public, unlisted or private"})
+		return
+	}
+	if req.Passphrase != nil && *req.Passphrase != "" {
+		if length := len(*req.Passphrase); length < minPassphraseLength || length > maxPassphraseLength {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("passphrase must be %d to %d characters", minPassphraseLength, maxPassphraseLength)})
+			return
+		}
+	}
+	if req.InvitedUserIDs != nil && len(*req.InvitedUserIDs) > maxStreamInvites {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("at most %d viewers can be invited", maxStreamInvites)})
+		return
+	}
+
+	stream, err := s.GetStreamByIDInternal(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Stream not found"})
+		return
+	}
+	if !authorizeOwner(c, stream.UserID) {
+		return
+	}
+
+	access, err := s.dynamoRepo.GetStreamAccess(stream.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not load stream access"})
+		return
+	}
+
+	// Access is saved before the stream turns private, so invited viewers are never turned away
+	if req.Passphrase != nil || req.InvitedUserIDs != nil {
+		if req.Passphrase != nil {
+			access.PassphraseHash = ""
+			if *req.Passphrase != "" {
+				access.PassphraseHash = hashPassphrase(*req.Passphrase)
+			}
+		}
+		if req.InvitedUserIDs != nil {
+			access.InvitedUserIDs = normalizeInvites(*req.InvitedUserIDs, stream.UserID)
+		}
+		access.UpdatedAt = time.Now().UTC()
+
+		if err := s.dynamoRepo.SaveStreamAccess(access); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not save stream access"})
+			return
+		}
+	}
+
+	if req.Visibility != stream.EffectiveVisibility() {
+		stream.Visibility = req.Visibility
+		stream.UpdatedAt = time.Now()
+		if err := s.UpdateStreamInternal(stream); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not update stream"})
+			return
+		}
+	}
+
+	// Only whether a passphrase is set is recorded, never the passphrase
+	s.Audit(c.Request.Context(), stream.ID, models.AuditVisibilityUpdated, map[string]string{
+		"visibility":     string(stream.EffectiveVisibility()),
+		"passphrase_set": strconv.FormatBool(access.PassphraseHash != ""),
+		"invited":        strconv.Itoa(len(access.InvitedUserIDs)),
+	})
+
+	slog.InfoContext(c.Request.Context(), "🔒 Stream visibility updated", "stream_id", stream.ID, "visibility", req.Visibility,
+		"passphrase_set", access.PassphraseHash != "", "invited", len(access.InvitedUserIDs))
+	c.JSON(http.StatusOK, visibilityResponse(stream, access))
+}
+
+func visibilityResponse(stream *models.Stream, access *models.StreamAccess) gin.H {
+	invited := access.InvitedUserIDs
+	if invited == nil {
+		invited = []int64{}
+	}
+	return gin.H{
+		"visibility":       stream.EffectiveVisibility(),
+		"passphrase_set":   access.PassphraseHash != "",
+		"invited_user_ids": invited,
+	}
+}
+
+// normalizeInvites drops duplicates and the broadcaster, who can always play their stream
+func normalizeInvites(userIDs []int64, ownerID int64) []int64 {
+	seen := make(map[int64]bool, len(userIDs))
+	var invites []int64
+	for _, userID := range userIDs {
+		if userID <= 0 || userID == ownerID || seen[userID] {
+			continue
+		}
+		seen[userID] = true
+		invites = append(invites, userID)
+	}
+	return invites
+}
+
+// withoutUnlistedStreams keeps the streams that may be listed, unlisted and private streams
+// are only reached by their ID
+func withoutUnlistedStreams(streams []*models.Stream) []*models.Stream {
+	listed := streams[:0]
+	for _, stream := range streams {
+		if stream.Listed() {
+			listed = append(listed, stream)
+		}
+	}
+	return listed
+}
+
+// hashPassphrase returns a salted PBKDF2 hash of a passphrase as iterations$salt$key
+func hashPassphrase(passphrase string) string {
+	salt := make([]byte, 16)
+	rand.Read(salt)
+	key, _ := pbkdf2.Key(sha256.New, passphrase, salt, passphraseIterations, sha256.Size)
+	return fmt.Sprintf("%d$%s$%s", passphraseIterations, hex.EncodeToString(salt), hex.EncodeToString(key))
+}
+
+// checkPassphrase reports whether a passphrase matches a hash from hashPassphrase
+func checkPassphrase(hash, passphrase string) bool {
+	parts := strings.Split(hash, "$")
+	if len(parts) != 3 {
+		return false
+	}
+	iterations, err := strconv.Atoi(parts[0])
+	if err != nil || iterations <= 0 {
+		return false
+	}
+	salt, err := hex.DecodeString(parts[1])
+	if err != nil {
+		return false
+	}
+	expected, err := hex.DecodeString(parts[2])
+	if err != nil {
+		return false
+	}
+
+	key, err := pbkdf2.Key(sha256.New, passphrase, salt, iterations, len(expected))
+	if err != nil {
+		return false
+	}
+	return subtle.ConstantTimeCompare(key, expected) == 1
+}
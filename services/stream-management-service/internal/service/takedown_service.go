@@ -0,0 +1,521 @@
+// services/stream-management-service/internal/service/takedown_service.go
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/services/stream-management-service/internal/config"
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/services/stream-management-service/internal/models"
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/services/stream-management-service/internal/repository"
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/services/stream-management-service/pkg/aws"
+)
+
+type TakedownService struct {
+	config        *config.Config
+	dynamoRepo    *repository.DynamoDBRepository
+	streamService *StreamService
+	s3Client      *aws.S3Client
+	packager      *VODPackager
+	muteDisabled  error // set when recordings can't be muted, e.g. ffmpeg is missing
+}
+
+type CreateTakedownRequest struct {
+	TargetType      models.TakedownTargetType `json:"target_type" binding:"required"`
+	TargetID        string                    `json:"target_id" binding:"required"`
+	Action          models.TakedownAction     `json:"action"`
+	Claimant        models.TakedownClaimant   `json:"claimant"`
+	Reason          string                    `json:"reason" binding:"required"`
+	WorkDescription string                    `json:"work_description"`
+	EvidenceURLs    []string                  `json:"evidence_urls"`
+	MutedRanges     []models.TimeRange        `json:"muted_ranges"`
+}
+
+type UpdateTakedownStatusRequest struct {
+	Status models.TakedownStatus `json:"status" binding:"required"`
+	Note   string                `json:"note"`
+}
+
+type CounterNoticeRequest struct {
+	Name      string `json:"name" binding:"required"`
+	Email     string `json:"email" binding:"required"`
+	Statement string `json:"statement" binding:"required"`
+}
+
+func NewTakedownService(cfg *config.Config, dynamoRepo *repository.DynamoDBRepository, streamService *StreamService, packager *VODPackager) *TakedownService {
+	return &TakedownService{
+		config:        cfg,
+		dynamoRepo:    dynamoRepo,
+		streamService: streamService,
+		s3Client:      aws.NewS3Client(cfg.AWSRegion, cfg.S3BucketName),
+		packager:      packager,
+	}
+}
+
+// DisableMuting rejects new mute takedowns, content can still be blocked
+func (ts *TakedownService) DisableMuting(reason error) {
+	slog.Warn("🚫 Takedown muting disabled", "reason", reason)
+	ts.muteDisabled = reason
+}
+
+// CreateTakedown handles POST /admin/takedowns. Playback is blocked right away, mute
+// takedowns unblock the VOD once the claimed ranges have been muted.
+func (ts *TakedownService) CreateTakedown(c *gin.Context) {
+	var req CreateTakedownRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if req.Action == "" {
+		req.Action = models.TakedownActionBlock
+	}
+	if req.Claimant.Name == "" || req.Claimant.Email == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "claimant name and email are required"})
+		return
+	}
+
+	now := time.Now()
+	takedown := &models.Takedown{
+		ID:              generateTakedownID(),
+		TargetType:      req.TargetType,
+		TargetID:        req.TargetID,
+		Action:          req.Action,
+		Status:          models.TakedownStatusActive,
+		Claimant:        req.Claimant,
+		Reason:          req.Reason,
+		WorkDescription: req.WorkDescription,
+		EvidenceURLs:    req.EvidenceURLs,
+		History:         []models.TakedownEvent{{Status: models.TakedownStatusActive, At: now}},
+		CreatedAt:       now,
+		UpdatedAt:       now,
+	}
+
+	switch req.Action {
+	case models.TakedownActionBlock:
+	case models.TakedownActionMute:
+		if req.TargetType != models.TakedownTargetVOD {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "only VODs can be muted"})
+			return
+		}
+		if ts.muteDisabled != nil {
+			c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Muting is currently unavailable, block the VOD instead"})
+			return
+		}
+		takedown.MutedRanges = req.MutedRanges
+		takedown.MuteStatus = models.MuteStatusProcessing
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "action must be block or mute"})
+		return
+	}
+
+	switch req.TargetType {
+	case models.TakedownTargetStream:
+		stream, err := ts.streamService.GetStreamByIDInternal(req.TargetID)
+		if err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Stream not found"})
+			return
+		}
+		if stream.TakedownID != "" {
+			c.JSON(http.StatusConflict, gin.H{"error": "Stream is already taken down", "takedown_id": stream.TakedownID})
+			return
+		}
+		takedown.UserID = stream.UserID
+	case models.TakedownTargetVOD:
+		vod, err := ts.dynamoRepo.GetVODByID(req.TargetID)
+		if err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "VOD not found"})
+			return
+		}
+		// Muted VODs keep their takedown ID so lifting it restores the original
+		if vod.TakedownID != "" {
+			c.JSON(http.StatusConflict, gin.H{"error": "VOD is already taken down", "takedown_id": vod.TakedownID})
+			return
+		}
+		if req.Action == models.TakedownActionMute {
+			if err := validateMutedRanges(req.MutedRanges, vod.Duration); err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+				return
+			}
+		}
+		takedown.UserID = vod.UserID
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "target_type must be stream or vod"})
+		return
+	}
+
+	if err := ts.dynamoRepo.SaveTakedown(takedown); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not create takedown"})
+		return
+	}
+	if err := ts.setBlocked(c.Request.Context(), takedown, true); err != nil {
+		slog.ErrorContext(c.Request.Context(), "❌ Could not block taken down content", "takedown_id", takedown.ID, "target_id", takedown.TargetID, "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Takedown recorded but the content could not be blocked", "takedown_id": takedown.ID})
+		return
+	}
+
+	if takedown.Action == models.TakedownActionMute {
+		go ts.muteVOD(takedown.ID)
+	}
+
+	event := map[string]interface{}{
+		"takedown_id":  takedown.ID,
+		"target_type":  takedown.TargetType,
+		"target_id":    takedown.TargetID,
+		"user_id":      takedown.UserID,
+		"action":       takedown.Action,
+		"reason":       takedown.Reason,
+		"muted_ranges": takedown.MutedRanges,
+	}
+	if err := ts.streamService.PublishEvent("takedown_issued", event); err != nil {
+		slog.WarnContext(c.Request.Context(), "⚠️ Could not publish takedown event", "takedown_id", takedown.ID, "error", err)
+	}
+
+	slog.InfoContext(c.Request.Context(), "⚖️ Takedown issued", "takedown_id", takedown.ID, "target_type", takedown.TargetType, "target_id", takedown.TargetID, "action", takedown.Action)
+	c.JSON(http.StatusCreated, takedown)
+}
+
+// ListTakedowns handles GET /admin/takedowns, optionally filtered by ?status=
+func (ts *TakedownService) ListTakedowns(c *gin.Context) {
+	limit, cursor := parsePagination(c)
+
+	takedowns, nextCursor, err := ts.dynamoRepo.ListTakedowns(models.TakedownStatus(c.Query("status")), limit, cursor)
+	if err != nil {
+		respondPageError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"takedowns":   takedowns,
+		"count":       len(takedowns),
+		"next_cursor": nextCursor,
+	})
+}
+
+// GetTakedown handles GET /admin/takedowns/:id
+func (ts *TakedownService) GetTakedown(c *gin.Context) {
+	takedown, err := ts.dynamoRepo.GetTakedownByID(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Takedown not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, takedown)
+}
+
+// UpdateTakedownStatus handles POST /admin/takedowns/:id/status. Reinstating or withdrawing
+// a takedown makes the content playable again.
+func (ts *TakedownService) UpdateTakedownStatus(c *gin.Context) {
+	var req UpdateTakedownStatusRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	takedown, err := ts.dynamoRepo.GetTakedownByID(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Takedown not found"})
+		return
+	}
+
+	if err := ts.transition(c.Request.Context(), takedown, req.Status, req.Note); err != nil {
+		ts.respondTransitionError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, takedown)
+}
+
+// GetCreatorTakedown handles GET /api/v1/takedowns/:id for the creator of the claimed content
+func (ts *TakedownService) GetCreatorTakedown(c *gin.Context) {
+	takedown, ok := ts.creatorTakedown(c)
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, takedown)
+}
+
+// SubmitCounterNotice handles POST /api/v1/takedowns/:id/counter-notice. The content stays
+// blocked until an admin reinstates it.
+func (ts *TakedownService) SubmitCounterNotice(c *gin.Context) {
+	var req CounterNoticeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	takedown, ok := ts.creatorTakedown(c)
+	if !ok {
+		return
+	}
+
+	notice := &models.CounterNotice{
+		Name:        req.Name,
+		Email:       req.Email,
+		Statement:   req.Statement,
+		SubmittedAt: time.Now(),
+	}
+	previous := takedown.CounterNotice
+	takedown.CounterNotice = notice
+
+	if err := ts.transition(c.Request.Context(), takedown, models.TakedownStatusCounterNoticed, ""); err != nil {
+		takedown.CounterNotice = previous
+		ts.respondTransitionError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, takedown)
+}
+
+// creatorTakedown loads the takedown of the request, which must be against the viewer's content
+func (ts *TakedownService) creatorTakedown(c *gin.Context) (*models.Takedown, bool) {
+	viewerID := ViewerID(c)
+	if viewerID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Authentication required"})
+		return nil, false
+	}
+
+	takedown, err := ts.dynamoRepo.GetTakedownByID(c.Param("id"))
+	if err != nil || takedown.UserID != viewerID {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Takedown not found"})
+		return nil, false
+	}
+
+	return takedown, true
+}
+
+var errInvalidTransition = errors.New("invalid takedown status transition")
+
+// transition moves a takedown to a status, lifts the block when it's reinstated or withdrawn
+// and lets the creator know
+func (ts *TakedownService) transition(ctx context.Context, takedown *models.Takedown, status models.TakedownStatus, note string) error {
+	if !takedown.CanTransition(status) {
+		return fmt.Errorf("%w: %s to %s", errInvalidTransition, takedown.Status, status)
+	}
+
+	previous := takedown.Status
+	now := time.Now()
+	takedown.Status = status
+	takedown.History = append(takedown.History, models.TakedownEvent{Status: status, Note: note, At: now})
+	takedown.UpdatedAt = now
+
+	if err := ts.dynamoRepo.SaveTakedown(takedown); err != nil {
+		return err
+	}
+
+	if takedown.Lifted() {
+		if err := ts.setBlocked(ctx, takedown, false); err != nil {
+			slog.ErrorContext(ctx, "❌ Could not lift takedown", "takedown_id", takedown.ID, "target_id", takedown.TargetID, "error", err)
+			return err
+		}
+	}
+
+	event := map[string]interface{}{
+		"takedown_id":     takedown.ID,
+		"target_type":     takedown.TargetType,
+		"target_id":       takedown.TargetID,
+		"user_id":         takedown.UserID,
+		"previous_status": previous,
+		"status":          status,
+		"note":            note,
+	}
+	if err := ts.streamService.PublishEvent("takedown_status_changed", event); err != nil {
+		slog.WarnContext(ctx, "⚠️ Could not publish takedown event", "takedown_id", takedown.ID, "error", err)
+	}
+
+	slog.InfoContext(ctx, "⚖️ Takedown status changed", "takedown_id", takedown.ID, "from", previous, "to", status)
+	return nil
+}
+
+func (ts *TakedownService) respondTransitionError(c *gin.Context, err error) {
+	if errors.Is(err, errInvalidTransition) {
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not update takedown"})
+}
+
+// setBlocked blocks or unblocks the content a takedown targets. A stream's VOD goes with it,
+// and lifting a mute takedown restores the original renditions.
+func (ts *TakedownService) setBlocked(ctx context.Context, takedown *models.Takedown, blocked bool) error {
+	vodID := takedown.TargetID
+	if takedown.TargetType == models.TakedownTargetStream {
+		stream, err := ts.streamService.GetStreamByIDInternal(takedown.TargetID)
+		if err != nil {
+			return err
+		}
+		if blocked || stream.TakedownID == takedown.ID {
+			applyBlock(&stream.Blocked, &stream.TakedownID, takedown.ID, blocked)
+			if err := ts.streamService.UpdateStreamInternal(stream); err != nil {
+				return err
+			}
+			action := models.AuditStreamUnblocked
+			if blocked {
+				action = models.AuditStreamBlocked
+			}
+			ts.streamService.Audit(ctx, stream.ID, action, map[string]string{"takedown_id": takedown.ID})
+		}
+		vodID = "vod_" + stream.ID
+	}
+
+	vod, err := ts.dynamoRepo.GetVODByID(vodID)
+	if err != nil {
+		if takedown.TargetType == models.TakedownTargetStream {
+			return nil // not recorded, or the recording isn't a VOD yet
+		}
+		return err
+	}
+	if (blocked && vod.TakedownID != "") || (!blocked && vod.TakedownID != takedown.ID) {
+		return nil // the VOD has a takedown of its own
+	}
+
+	applyBlock(&vod.Blocked, &vod.TakedownID, takedown.ID, blocked)
+	restored := !blocked && len(takedown.OriginalRenditions) > 0
+	if restored {
+		vod.Renditions = takedown.OriginalRenditions
+		vod.MutedRanges = nil
+	}
+	vod.UpdatedAt = time.Now()
+	if err := ts.dynamoRepo.SaveVOD(vod); err != nil {
+		return err
+	}
+
+	if restored {
+		ts.repackage(vod.ID)
+	}
+	return nil
+}
+
+// repackage queues a VOD whose source rendition changed, so its HLS playback follows
+func (ts *TakedownService) repackage(vodID string) {
+	if err := ts.packager.Enqueue(vodID); err != nil {
+		slog.Warn("⚠️ Could not queue VOD for packaging", "vod_id", vodID, "error", err)
+	}
+}
+
+func applyBlock(blockedField *bool, takedownField *string, takedownID string, blocked bool) {
+	*blockedField = blocked
+	if blocked {
+		*takedownField = takedownID
+	} else {
+		*takedownField = ""
+	}
+}
+
+// muteVOD silences the claimed ranges of a VOD's recording and swaps it in, unblocking the VOD
+func (ts *TakedownService) muteVOD(takedownID string) {
+	takedown, err := ts.dynamoRepo.GetTakedownByID(takedownID)
+	if err != nil {
+		slog.Error("❌ Could not load takedown", "takedown_id", takedownID, "error", err)
+		return
+	}
+
+	vod, err := ts.dynamoRepo.GetVODByID(takedown.TargetID)
+	if err != nil {
+		ts.failMute(takedown, fmt.Errorf("vod not found: %w", err))
+		return
+	}
+
+	muted, err := muteRecording(ts.config, ts.s3Client, vod, takedown.MutedRanges, "muted_"+takedown.ID)
+	if err != nil {
+		ts.failMute(takedown, err)
+		return
+	}
+
+	// The takedown may have been lifted while muting
+	if latest, err := ts.dynamoRepo.GetTakedownByID(takedown.ID); err == nil {
+		takedown = latest
+	}
+	if takedown.Lifted() {
+		return
+	}
+
+	takedown.OriginalRenditions = vod.Renditions
+	takedown.MuteStatus = models.MuteStatusReady
+	takedown.UpdatedAt = time.Now()
+	if err := ts.dynamoRepo.SaveTakedown(takedown); err != nil {
+		slog.Error("❌ Could not save muted takedown", "takedown_id", takedown.ID, "error", err)
+		return
+	}
+
+	vod.Renditions = []models.Rendition{muted}
+	vod.MutedRanges = takedown.MutedRanges
+	vod.Blocked = false
+	vod.TakedownID = takedown.ID
+	vod.UpdatedAt = time.Now()
+	if err := ts.dynamoRepo.SaveVOD(vod); err != nil {
+		slog.Error("❌ Could not save muted VOD", "takedown_id", takedown.ID, "vod_id", vod.ID, "error", err)
+		return
+	}
+
+	ts.repackage(vod.ID)
+
+	slog.Info("🔇 VOD muted", "takedown_id", takedown.ID, "vod_id", vod.ID, "ranges", len(takedown.MutedRanges))
+}
+
+// failMute leaves the VOD blocked, an admin can still lift or uphold the takedown
+func (ts *TakedownService) failMute(takedown *models.Takedown, cause error) {
+	slog.Error("❌ Muting VOD failed", "takedown_id", takedown.ID, "vod_id", takedown.TargetID, "error", cause)
+
+	takedown.MuteStatus = models.MuteStatusFailed
+	takedown.MuteError = cause.Error()
+	takedown.UpdatedAt = time.Now()
+	if err := ts.dynamoRepo.SaveTakedown(takedown); err != nil {
+		slog.Warn("⚠️ Could not mark takedown mute as failed", "takedown_id", takedown.ID, "error", err)
+	}
+}
+
+func validateMutedRanges(ranges []models.TimeRange, duration int64) error {
+	if len(ranges) == 0 {
+		return fmt.Errorf("muted_ranges are required to mute a VOD")
+	}
+	for _, r := range ranges {
+		if r.Start < 0 || r.End <= r.Start {
+			return fmt.Errorf("muted range %d-%d must end after it starts and both must be positive", r.Start, r.End)
+		}
+		if duration > 0 && r.Start >= duration {
+			return fmt.Errorf("muted range %d-%d starts past the end of the VOD (%ds)", r.Start, r.End, duration)
+		}
+	}
+	return nil
+}
+
+// respondTakedown answers requests for content a takedown blocks
+func respondTakedown(c *gin.Context, takedownID string) {
+	respondErrorDetails(c, http.StatusUnavailableForLegalReasons, ErrCodeContentBlocked, "Content unavailable due to a copyright claim",
+		map[string]string{"takedown_id": takedownID})
+}
+
+func withoutBlockedStreams(streams []*models.Stream) []*models.Stream {
+	visible := streams[:0]
+	for _, stream := range streams {
+		if !stream.Blocked {
+			visible = append(visible, stream)
+		}
+	}
+	return visible
+}
+
+func withoutBlockedVODs(vods []*models.VOD) []*models.VOD {
+	visible := vods[:0]
+	for _, vod := range vods {
+		if !vod.Blocked {
+			visible = append(visible, vod)
+		}
+	}
+	return visible
+}
+
+func generateTakedownID() string {
+	bytes := make([]byte, 8)
+	rand.Read(bytes)
+	return "td_" + hex.EncodeToString(bytes)
+}
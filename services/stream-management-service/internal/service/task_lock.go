@@ -0,0 +1,177 @@
+// services/stream-management-service/internal/service/task_lock.go
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/services/stream-management-service/internal/repository"
+)
+
+// TaskLockStats counts what happened to a background task's lock on this replica since it
+// started
+type TaskLockStats struct {
+	Task      string `json:"task"`
+	Held      bool   `json:"held"`            // this replica runs the task right now
+	Owner     string `json:"owner,omitempty"` // replica that held the lock when it was last tried
+	Acquired  int64  `json:"acquired"`
+	Contended int64  `json:"contended"` // runs skipped because another replica held the lock
+	Lost      int64  `json:"lost"`      // runs cut short because the lock couldn't be renewed
+	Errors    int64  `json:"errors"`    // runs skipped because Redis couldn't be asked
+}
+
+// TaskLocker runs background tasks on one replica at a time. A task holds a Redis lock for
+// as long as it runs, renewed before it expires, and is cancelled if the lock is lost, e.g.
+// to a Redis failover or a pause longer than the TTL.
+type TaskLocker struct {
+	cache repository.StreamCache
+	owner string
+	ttl   time.Duration
+
+	mu    sync.Mutex
+	stats map[string]*TaskLockStats
+}
+
+func NewTaskLocker(cache repository.StreamCache, ttl time.Duration) *TaskLocker {
+	if ttl <= 0 {
+		ttl = 30 * time.Second
+	}
+	return &TaskLocker{
+		cache: cache,
+		owner: lockOwnerID(),
+		ttl:   ttl,
+		stats: make(map[string]*TaskLockStats),
+	}
+}
+
+// Owner returns the name this replica holds locks under
+func (l *TaskLocker) Owner() string {
+	return l.owner
+}
+
+// Run runs fn under the task's lock and reports whether it ran. It doesn't when another
+// replica holds the lock, or Redis couldn't be asked, in which case the error is returned.
+func (l *TaskLocker) Run(ctx context.Context, task string, fn func(ctx context.Context) error) (bool, error) {
+	name := "task:" + task
+
+	acquired, err := l.cache.AcquireLock(name, l.owner, l.ttl)
+	if err != nil {
+		l.update(task, func(stats *TaskLockStats) { stats.Errors++ })
+		return false, err
+	}
+	if !acquired {
+		owner, err := l.cache.GetLockOwner(name)
+		if err != nil {
+			owner = ""
+		}
+		l.update(task, func(stats *TaskLockStats) {
+			stats.Contended++
+			stats.Owner = owner
+		})
+		slog.DebugContext(ctx, "🔒 Task is running on another replica", "task", task, "lock_owner", owner)
+		return false, nil
+	}
+
+	l.update(task, func(stats *TaskLockStats) {
+		stats.Acquired++
+		stats.Held = true
+		stats.Owner = l.owner
+	})
+	slog.InfoContext(ctx, "🔒 Acquired task lock", "task", task, "lock_owner", l.owner)
+
+	runCtx, cancel := context.WithCancel(ctx)
+	renewed := make(chan struct{})
+	go func() {
+		defer close(renewed)
+		l.renew(runCtx, task, name, cancel)
+	}()
+
+	err = fn(runCtx)
+	cancel()
+	<-renewed
+
+	if releaseErr := l.cache.ReleaseLock(name, l.owner); releaseErr != nil {
+		// It expires after the TTL
+		slog.WarnContext(ctx, "⚠️ Could not release task lock", "task", task, "error", releaseErr)
+	}
+	l.update(task, func(stats *TaskLockStats) { stats.Held = false })
+	slog.DebugContext(ctx, "🔓 Released task lock", "task", task, "lock_owner", l.owner)
+
+	return true, err
+}
+
+// ClaimRun reserves the run of a scheduled job due at scheduledAt for this replica, which
+// makes TaskLocker the coordinator of the job scheduler
+func (l *TaskLocker) ClaimRun(job string, scheduledAt time.Time, ttl time.Duration) (bool, error) {
+	return l.cache.ClaimJobRun(job, scheduledAt, ttl)
+}
+
+// renew extends the lock a third of the TTL at a time until ctx is done, cancelling the task
+// once the lock can't be renewed
+func (l *TaskLocker) renew(ctx context.Context, task, name string, cancel context.CancelFunc) {
+	ticker := time.NewTicker(l.ttl / 3)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			renewed, err := l.cache.RenewLock(name, l.owner, l.ttl)
+			if err == nil && renewed {
+				continue
+			}
+			if err == nil {
+				err = fmt.Errorf("lock is held by someone else or expired")
+			}
+			l.update(task, func(stats *TaskLockStats) { stats.Lost++ })
+			slog.WarnContext(ctx, "⚠️ Lost task lock, stopping the task", "task", task, "lock_owner", l.owner, "error", err)
+			cancel()
+			return
+		}
+	}
+}
+
+// Stats returns the lock counters of every task tried on this replica, by task name
+func (l *TaskLocker) Stats() []TaskLockStats {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	stats := make([]TaskLockStats, 0, len(l.stats))
+	for _, task := range l.stats {
+		stats = append(stats, *task)
+	}
+	sort.Slice(stats, func(i, j int) bool { return stats[i].Task < stats[j].Task })
+	return stats
+}
+
+func (l *TaskLocker) update(task string, change func(stats *TaskLockStats)) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	stats, ok := l.stats[task]
+	if !ok {
+		stats = &TaskLockStats{Task: task}
+		l.stats[task] = stats
+	}
+	change(stats)
+}
+
+// lockOwnerID names this replica in the locks it holds, the host name tells operators which
+// pod it is and the random suffix keeps restarted replicas apart
+func lockOwnerID() string {
+	host, err := os.Hostname()
+	if err != nil {
+		host = "unknown"
+	}
+	bytes := make([]byte, 4)
+	rand.Read(bytes)
+	return fmt.Sprintf("%s-%d-%s", host, os.Getpid(), hex.EncodeToString(bytes))
+}
@@ -0,0 +1,647 @@
+// services/stream-management-service/internal/service/user_data_service.go
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/services/stream-management-service/internal/config"
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/services/stream-management-service/internal/models"
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/services/stream-management-service/internal/repository"
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/services/stream-management-service/pkg/aws"
+)
+
+const (
+	// userDataJobPollInterval is how often replicas look for user data jobs to run
+	userDataJobPollInterval = 10 * time.Second
+	// userDataJobClaimTTL is how long a replica has a job to itself. A replica that went away
+	// mid-job leaves it to another one after this.
+	userDataJobClaimTTL = 30 * time.Minute
+	// userDataJobMaxAttempts is how many runs a job gets before it's left failed
+	userDataJobMaxAttempts = 5
+	// userDataPageSize is the page size VODs, clips and audit records are read with
+	userDataPageSize = 100
+)
+
+// errUserLive holds an erasure back until the user's stream ended, so the end of the stream
+// doesn't write new data behind it
+var errUserLive = errors.New("waiting for the user's live stream to end")
+
+// userDataStore is what a user data job does in one store. A store without an export func is
+// only erased.
+type userDataStore struct {
+	name   string
+	erase  func(ctx context.Context, userID int64) (int, error)
+	export func(ctx context.Context, userID int64, export *models.UserDataExport) (int, error)
+}
+
+// UserDataService erases and exports a user's data on request: streams with their audit logs
+// and Redis state, recordings, VODs and clips in S3, restream targets, quality tier and
+// follows. Takedowns and stream key bans are kept, they're records of the platform's own
+// obligations. Jobs are stored in the stats table and run by whichever replica claims them.
+type UserDataService struct {
+	config     *config.Config
+	dynamoRepo *repository.DynamoDBRepository
+	redisRepo  *repository.RedisRepository
+	s3Client   *aws.S3Client
+	stores     []userDataStore
+}
+
+func NewUserDataService(cfg *config.Config, dynamoRepo *repository.DynamoDBRepository, redisRepo *repository.RedisRepository) *UserDataService {
+	uds := &UserDataService{
+		config:     cfg,
+		dynamoRepo: dynamoRepo,
+		redisRepo:  redisRepo,
+		s3Client:   aws.NewS3Client(cfg.AWSRegion, cfg.S3BucketName),
+	}
+
+	// Streams go first, an erasure waits there while the user is live
+	uds.stores = []userDataStore{
+		{name: "streams", erase: uds.eraseStreams, export: uds.exportStreams},
+		{name: "vods", erase: uds.eraseVODs, export: uds.exportVODs},
+		{name: "clips", erase: uds.eraseClips, export: uds.exportClips},
+		{name: "restream_targets", erase: uds.eraseRestreamTargets, export: uds.exportRestreamTargets},
+		{name: "quality_tier", erase: uds.eraseQualityTier, export: uds.exportQualityTier},
+		{name: "follows", erase: uds.eraseFollows, export: uds.exportFollows},
+		{name: "exports", erase: uds.eraseExports},
+	}
+	return uds
+}
+
+// EraseUserData handles DELETE /api/v1/users/:id/data, starting the erasure of everything
+// this service keeps about a user. It answers 202 with the job to follow.
+func (uds *UserDataService) EraseUserData(c *gin.Context) {
+	uds.startJob(c, models.UserDataJobErase)
+}
+
+// ExportUserData handles GET /api/v1/users/:id/export, starting an export of a user's data.
+// It answers 202 with the job, which has a download link once completed.
+func (uds *UserDataService) ExportUserData(c *gin.Context) {
+	uds.startJob(c, models.UserDataJobExport)
+}
+
+// GetUserDataJob handles GET /api/v1/users/:id/data-jobs/:job_id
+func (uds *UserDataService) GetUserDataJob(c *gin.Context) {
+	userID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+	if !authorizeOwner(c, userID) {
+		return
+	}
+
+	job, err := uds.dynamoRepo.GetUserDataJob(c.Param("job_id"))
+	if err != nil || job.UserID != userID {
+		if err == nil || errors.Is(err, repository.ErrUserDataJobNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Job not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not load job"})
+		return
+	}
+
+	uds.presignExport(c.Request.Context(), job)
+	c.JSON(http.StatusOK, job)
+}
+
+// startJob queues a job for a user, or returns the one of the same kind still running
+func (uds *UserDataService) startJob(c *gin.Context, kind models.UserDataJobKind) {
+	ctx := c.Request.Context()
+
+	userID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+	if !authorizeOwner(c, userID) {
+		return
+	}
+
+	jobs, err := uds.dynamoRepo.GetUserDataJobs(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not load jobs"})
+		return
+	}
+	for _, job := range jobs {
+		if job.Kind == kind && !job.Done() {
+			c.JSON(http.StatusAccepted, job)
+			return
+		}
+	}
+
+	now := time.Now().UTC()
+	job := &models.UserDataJob{
+		ID:        generateUserDataJobID(),
+		UserID:    userID,
+		Kind:      kind,
+		Status:    models.UserDataJobPending,
+		Steps:     []models.UserDataStep{},
+		CreatedAt: now,
+		UpdatedAt: now,
+		ExpiresAt: now.Add(uds.config.UserDataJobRetention).Unix(),
+	}
+	for _, store := range uds.stores {
+		if kind == models.UserDataJobErase || store.export != nil {
+			job.Steps = append(job.Steps, models.UserDataStep{Name: store.name})
+		}
+	}
+
+	if err := uds.dynamoRepo.SaveUserDataJob(job); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not save job"})
+		return
+	}
+
+	slog.InfoContext(ctx, "🗂️ User data job queued", "job_id", job.ID, "user_id", userID, "kind", kind)
+	c.JSON(http.StatusAccepted, job)
+}
+
+// StartWorker periodically runs the user data jobs that aren't done
+func (uds *UserDataService) StartWorker(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(userDataJobPollInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				uds.runJobs(ctx)
+			}
+		}
+	}()
+}
+
+func (uds *UserDataService) runJobs(ctx context.Context) {
+	jobs, err := uds.dynamoRepo.GetUserDataJobs(0)
+	if err != nil {
+		slog.WarnContext(ctx, "⚠️ Could not get user data jobs", "error", err)
+		return
+	}
+
+	for _, job := range jobs {
+		if ctx.Err() != nil {
+			return
+		}
+		if job.Done() {
+			continue
+		}
+
+		claimed, err := uds.redisRepo.ClaimUserDataJob(job.ID, userDataJobClaimTTL)
+		if err != nil {
+			slog.WarnContext(ctx, "⚠️ Could not claim user data job", "job_id", job.ID, "error", err)
+			continue
+		}
+		if !claimed {
+			continue // running on another replica
+		}
+
+		uds.runJob(ctx, job)
+		if err := uds.redisRepo.ReleaseUserDataJob(job.ID); err != nil {
+			slog.WarnContext(ctx, "⚠️ Could not release user data job", "job_id", job.ID, "error", err)
+		}
+	}
+}
+
+// runJob runs the steps of a job, saving its progress after each. An erasure picks up where
+// its last run stopped, an export is gathered again from the start.
+func (uds *UserDataService) runJob(ctx context.Context, job *models.UserDataJob) {
+	job.Status = models.UserDataJobRunning
+	job.UpdatedAt = time.Now().UTC()
+	if err := uds.dynamoRepo.SaveUserDataJob(job); err != nil {
+		slog.WarnContext(ctx, "⚠️ Could not save user data job", "job_id", job.ID, "error", err)
+		return
+	}
+
+	var export *models.UserDataExport
+	if job.Kind == models.UserDataJobExport {
+		export = &models.UserDataExport{
+			UserID:       job.UserID,
+			GeneratedAt:  time.Now().UTC(),
+			AuditRecords: map[string][]*models.AuditRecord{},
+		}
+	}
+
+	err := uds.runSteps(ctx, job, export)
+	if err == nil && export != nil {
+		err = uds.uploadExport(job, export)
+	}
+
+	now := time.Now().UTC()
+	job.UpdatedAt = now
+	switch {
+	case err == nil:
+		job.Status = models.UserDataJobCompleted
+		job.Error = ""
+		job.CompletedAt = &now
+		slog.InfoContext(ctx, "🗂️ User data job completed", "job_id", job.ID, "user_id", job.UserID, "kind", job.Kind)
+	case errors.Is(err, errUserLive):
+		// Not a failure, tried again on the next poll
+		job.Status = models.UserDataJobPending
+		job.Error = err.Error()
+	default:
+		job.Attempts++
+		job.Error = err.Error()
+		job.Status = models.UserDataJobPending
+		if job.Attempts >= userDataJobMaxAttempts {
+			job.Status = models.UserDataJobFailed
+		}
+		slog.WarnContext(ctx, "⚠️ User data job failed", "job_id", job.ID, "user_id", job.UserID, "kind", job.Kind, "attempts", job.Attempts, "error", err)
+	}
+
+	if err := uds.dynamoRepo.SaveUserDataJob(job); err != nil {
+		slog.WarnContext(ctx, "⚠️ Could not save user data job", "job_id", job.ID, "error", err)
+	}
+}
+
+func (uds *UserDataService) runSteps(ctx context.Context, job *models.UserDataJob, export *models.UserDataExport) error {
+	for i := range job.Steps {
+		step := &job.Steps[i]
+		if step.Done && export == nil {
+			continue
+		}
+
+		store, ok := uds.store(step.Name)
+		if !ok {
+			return fmt.Errorf("unknown step %s", step.Name)
+		}
+
+		var items int
+		var err error
+		if export != nil {
+			items, err = store.export(ctx, job.UserID, export)
+		} else {
+			items, err = store.erase(ctx, job.UserID)
+		}
+		if err != nil {
+			return fmt.Errorf("%s: %w", step.Name, err)
+		}
+
+		step.Items = items
+		step.Done = true
+		job.UpdatedAt = time.Now().UTC()
+		if err := uds.dynamoRepo.SaveUserDataJob(job); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (uds *UserDataService) store(name string) (userDataStore, bool) {
+	for _, store := range uds.stores {
+		if store.name == name {
+			return store, true
+		}
+	}
+	return userDataStore{}, false
+}
+
+// uploadExport stores a finished export in S3, next to the user's other exports
+func (uds *UserDataService) uploadExport(job *models.UserDataJob, export *models.UserDataExport) error {
+	document, err := json.MarshalIndent(export, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal export: %w", err)
+	}
+
+	key := fmt.Sprintf("%s%s.json", userExportPrefix(job.UserID), job.ID)
+	if err := uds.s3Client.PutObject(key, document, "application/json"); err != nil {
+		return err
+	}
+	job.ExportKey = key
+	return nil
+}
+
+// presignExport gives a completed export a download link that works for UserDataExportURLTTL
+func (uds *UserDataService) presignExport(ctx context.Context, job *models.UserDataJob) {
+	if job.Status != models.UserDataJobCompleted || job.ExportKey == "" {
+		return
+	}
+
+	url, err := uds.s3Client.PresignGetURL(job.ExportKey, uds.config.UserDataExportURLTTL)
+	if err != nil {
+		slog.WarnContext(ctx, "⚠️ Could not presign export URL", "job_id", job.ID, "error", err)
+		return
+	}
+	job.ExportURL = url
+}
+
+func (uds *UserDataService) eraseStreams(ctx context.Context, userID int64) (int, error) {
+	streams, err := uds.dynamoRepo.GetAllStreamsByUser(userID, models.DeletedInclude)
+	if err != nil {
+		return 0, err
+	}
+	for _, stream := range streams {
+		if stream.Status == models.StreamStatusLive || stream.Status == models.StreamStatusReconnecting {
+			return 0, errUserLive
+		}
+	}
+
+	for _, stream := range streams {
+		if ctx.Err() != nil {
+			return 0, ctx.Err()
+		}
+
+		// The stream key is shared by the user's streams, its session only goes with all of them
+		if stream.StreamKey != "" {
+			if err := uds.redisRepo.DeleteStreamSession(stream.StreamKey); err != nil {
+				return 0, err
+			}
+			if err := uds.redisRepo.DeleteStreamKeyValidation(stream.StreamKey); err != nil {
+				return 0, err
+			}
+		}
+		if err := uds.purgeStream(ctx, stream); err != nil {
+			return 0, err
+		}
+	}
+	return len(streams), nil
+}
+
+// purgeStream deletes an ended stream for good: its recording and clips in S3, the recording
+// left on the media server, its clips, audit log, access and Redis state
+func (uds *UserDataService) purgeStream(ctx context.Context, stream *models.Stream) error {
+	for _, prefix := range []string{"recordings/" + stream.ID + "/", "clips/" + stream.ID + "/"} {
+		if _, err := uds.s3Client.DeletePrefix(prefix); err != nil {
+			return err
+		}
+	}
+	if stream.RecordingPath != "" {
+		if err := os.Remove(stream.RecordingPath); err != nil && !os.IsNotExist(err) {
+			slog.WarnContext(ctx, "⚠️ Could not delete local recording", "stream_id", stream.ID, "error", err)
+		}
+	}
+
+	clips, err := uds.streamClips(stream.ID)
+	if err != nil {
+		return err
+	}
+	for _, clip := range clips {
+		if err := uds.dynamoRepo.DeleteClip(clip.ID); err != nil {
+			return err
+		}
+	}
+
+	if _, err := uds.dynamoRepo.DeleteAuditRecords(stream.ID); err != nil {
+		return err
+	}
+	if err := uds.dynamoRepo.DeleteStreamAccess(stream.ID); err != nil {
+		return err
+	}
+	if err := uds.redisRepo.DeleteStreamState(stream.ID); err != nil {
+		return err
+	}
+
+	// Deleted last, so a run that stopped halfway still finds the stream next time
+	return uds.dynamoRepo.DeleteStream(stream.ID)
+}
+
+func (uds *UserDataService) exportStreams(ctx context.Context, userID int64, export *models.UserDataExport) (int, error) {
+	streams, err := uds.dynamoRepo.GetAllStreamsByUser(userID, models.DeletedInclude)
+	if err != nil {
+		return 0, err
+	}
+	export.Streams = streams
+
+	for _, stream := range streams {
+		if ctx.Err() != nil {
+			return 0, ctx.Err()
+		}
+
+		var records []*models.AuditRecord
+		cursor := ""
+		for {
+			page, next, err := uds.dynamoRepo.GetAuditRecords(stream.ID, userDataPageSize, cursor)
+			if err != nil {
+				return 0, err
+			}
+			records = append(records, page...)
+			if next == "" {
+				break
+			}
+			cursor = next
+		}
+		if len(records) > 0 {
+			export.AuditRecords[stream.ID] = records
+		}
+	}
+	return len(streams), nil
+}
+
+func (uds *UserDataService) eraseVODs(ctx context.Context, userID int64) (int, error) {
+	vods, err := uds.userVODs(userID)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, vod := range vods {
+		if ctx.Err() != nil {
+			return 0, ctx.Err()
+		}
+		if err := uds.purgeVOD(vod); err != nil {
+			return 0, err
+		}
+	}
+	return len(vods), nil
+}
+
+// purgeVOD deletes a VOD and its packaged files for good
+func (uds *UserDataService) purgeVOD(vod *models.VOD) error {
+	if _, err := uds.s3Client.DeletePrefix("vods/" + vod.ID + "/"); err != nil {
+		return err
+	}
+	return uds.dynamoRepo.DeleteVOD(vod.ID)
+}
+
+func (uds *UserDataService) exportVODs(ctx context.Context, userID int64, export *models.UserDataExport) (int, error) {
+	vods, err := uds.userVODs(userID)
+	if err != nil {
+		return 0, err
+	}
+	export.VODs = vods
+	return len(vods), nil
+}
+
+// eraseClips erases the clips the user made of other channels, the clips of their own streams
+// went with the streams
+func (uds *UserDataService) eraseClips(ctx context.Context, userID int64) (int, error) {
+	clips, err := uds.dynamoRepo.GetClipsByCreator(userID)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, clip := range clips {
+		if ctx.Err() != nil {
+			return 0, ctx.Err()
+		}
+		if _, err := uds.s3Client.DeletePrefix(fmt.Sprintf("clips/%s/%s.", clip.StreamID, clip.ID)); err != nil {
+			return 0, err
+		}
+		if err := uds.dynamoRepo.DeleteClip(clip.ID); err != nil {
+			return 0, err
+		}
+	}
+	return len(clips), nil
+}
+
+// exportClips exports the clips of the user's streams and the clips the user made
+func (uds *UserDataService) exportClips(ctx context.Context, userID int64, export *models.UserDataExport) (int, error) {
+	clips, err := uds.dynamoRepo.GetClipsByCreator(userID)
+	if err != nil {
+		return 0, err
+	}
+	seen := make(map[string]bool, len(clips))
+	for _, clip := range clips {
+		seen[clip.ID] = true
+	}
+
+	for _, stream := range export.Streams {
+		if ctx.Err() != nil {
+			return 0, ctx.Err()
+		}
+		streamClips, err := uds.streamClips(stream.ID)
+		if err != nil {
+			return 0, err
+		}
+		for _, clip := range streamClips {
+			if !seen[clip.ID] {
+				seen[clip.ID] = true
+				clips = append(clips, clip)
+			}
+		}
+	}
+
+	export.Clips = clips
+	return len(clips), nil
+}
+
+func (uds *UserDataService) eraseRestreamTargets(ctx context.Context, userID int64) (int, error) {
+	targets, err := uds.dynamoRepo.GetRestreamTargetsByUser(userID)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, target := range targets {
+		if err := uds.dynamoRepo.DeleteRestreamTarget(target.ID); err != nil {
+			return 0, err
+		}
+	}
+	return len(targets), nil
+}
+
+// exportRestreamTargets exports the user's restream targets, without their stream keys
+func (uds *UserDataService) exportRestreamTargets(ctx context.Context, userID int64, export *models.UserDataExport) (int, error) {
+	targets, err := uds.dynamoRepo.GetRestreamTargetsByUser(userID)
+	if err != nil {
+		return 0, err
+	}
+	export.RestreamTargets = targets
+	return len(targets), nil
+}
+
+func (uds *UserDataService) eraseQualityTier(ctx context.Context, userID int64) (int, error) {
+	if err := uds.dynamoRepo.DeleteUserTier(userID); err != nil {
+		return 0, err
+	}
+	return 1, nil
+}
+
+func (uds *UserDataService) exportQualityTier(ctx context.Context, userID int64, export *models.UserDataExport) (int, error) {
+	userTier, err := uds.dynamoRepo.GetUserTier(userID)
+	if err != nil {
+		return 0, err
+	}
+	export.QualityTier = userTier.Tier
+	return 1, nil
+}
+
+func (uds *UserDataService) eraseFollows(ctx context.Context, userID int64) (int, error) {
+	followed, err := uds.followedChannels(userID)
+	if err != nil {
+		return 0, err
+	}
+	if err := uds.redisRepo.DeleteUserState(userID, followed); err != nil {
+		return 0, err
+	}
+	return len(followed), nil
+}
+
+func (uds *UserDataService) exportFollows(ctx context.Context, userID int64, export *models.UserDataExport) (int, error) {
+	followed, err := uds.followedChannels(userID)
+	if err != nil {
+		return 0, err
+	}
+	export.FollowedChannels = followed
+	return len(followed), nil
+}
+
+// eraseExports deletes the user's earlier exports from S3
+func (uds *UserDataService) eraseExports(ctx context.Context, userID int64) (int, error) {
+	return uds.s3Client.DeletePrefix(userExportPrefix(userID))
+}
+
+func (uds *UserDataService) userVODs(userID int64) ([]*models.VOD, error) {
+	var vods []*models.VOD
+	cursor := ""
+	for {
+		page, next, err := uds.dynamoRepo.GetVODsByUser(userID, "", models.DeletedInclude, userDataPageSize, cursor)
+		if err != nil {
+			return nil, err
+		}
+		vods = append(vods, page...)
+		if next == "" {
+			return vods, nil
+		}
+		cursor = next
+	}
+}
+
+func (uds *UserDataService) streamClips(streamID string) ([]*models.Clip, error) {
+	var clips []*models.Clip
+	cursor := ""
+	for {
+		page, next, err := uds.dynamoRepo.GetClipsByStream(streamID, userDataPageSize, cursor)
+		if err != nil {
+			return nil, err
+		}
+		clips = append(clips, page...)
+		if next == "" {
+			return clips, nil
+		}
+		cursor = next
+	}
+}
+
+func (uds *UserDataService) followedChannels(userID int64) ([]int64, error) {
+	followed, err := uds.redisRepo.GetFollowedChannels(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	channels := make([]int64, 0, len(followed))
+	for channelID := range followed {
+		channels = append(channels, channelID)
+	}
+	return channels, nil
+}
+
+func userExportPrefix(userID int64) string {
+	return fmt.Sprintf("exports/%d/", userID)
+}
+
+func generateUserDataJobID() string {
+	bytes := make([]byte, 8)
+	rand.Read(bytes)
+	return "udj_" + hex.EncodeToString(bytes)
+}
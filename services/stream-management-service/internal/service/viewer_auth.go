@@ -0,0 +1,266 @@
+// services/stream-management-service/internal/service/viewer_auth.go
+package service
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/services/stream-management-service/internal/auth"
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/services/stream-management-service/internal/config"
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/services/stream-management-service/internal/logging"
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/services/stream-management-service/internal/models"
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/services/stream-management-service/internal/repository"
+	grpcClient "github.com/Saoudyahya/Live-Streaming-Platform-Architecture/services/stream-management-service/pkg/grpc"
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/shared/go/pkg/identity"
+)
+
+const (
+	// ViewerIDHeader names the user a viewer token was issued to
+	ViewerIDHeader = "X-User-ID"
+
+	viewerContextKey = "viewer_id"
+)
+
+// ViewerAuth identifies the end user behind a request from their user service token
+type ViewerAuth struct {
+	config     *config.Config
+	redisRepo  *repository.RedisRepository
+	userClient *grpcClient.UserServiceClient
+	verifier   *auth.Verifier
+	identities *identity.Propagator
+}
+
+// viewer is the end user a token was issued to, a zero ID when it isn't valid
+type viewer struct {
+	id       int64
+	username string // only known from JWTs
+	roles    []string
+}
+
+func NewViewerAuth(cfg *config.Config, redisRepo *repository.RedisRepository, userClient *grpcClient.UserServiceClient) *ViewerAuth {
+	return &ViewerAuth{
+		config:     cfg,
+		redisRepo:  redisRepo,
+		userClient: userClient,
+		verifier:   auth.NewVerifier(cfg.JWTSecret, cfg.JWKSURL, cfg.JWTIssuer, cfg.JWKSRefreshInterval),
+		identities: identity.NewPropagator(cfg.IdentitySecret),
+	}
+}
+
+// VerifyKeys loads the JWKS key set, if one is configured
+func (va *ViewerAuth) VerifyKeys() error {
+	return va.verifier.Refresh()
+}
+
+// Identify attaches the viewer to requests carrying "Authorization: Bearer <token>".
+// Tokens are verified as JWTs when JWT_SECRET_KEY or JWT_JWKS_URL is set, otherwise they
+// are checked with the user service and X-User-ID must name the user. Anonymous requests
+// pass through, invalid credentials are rejected. Users another service already authenticated
+// are taken from the identity it signed, when IDENTITY_PROPAGATION_SECRET is set. The viewer
+// is put in the request context for the services called on its behalf.
+func (va *ViewerAuth) Identify() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if propagated, ok := va.propagated(c.Request.Header); ok {
+			va.attach(c, propagated)
+			c.Next()
+			return
+		}
+
+		token, ok := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer ")
+		userID := c.GetHeader(ViewerIDHeader)
+		if !ok || token == "" {
+			c.Next()
+			return
+		}
+
+		if !va.verifier.Enabled() && userID == "" {
+			c.Next()
+			return
+		}
+
+		viewer, err := va.verify(token, userID)
+		if err != nil {
+			slog.WarnContext(c.Request.Context(), "⚠️ Could not validate viewer", "user_id", userID, "error", err)
+			abortWithError(c, http.StatusServiceUnavailable, ErrCodeUnavailable, "Could not validate viewer")
+			return
+		}
+		if viewer.id == 0 {
+			abortWithError(c, http.StatusUnauthorized, ErrCodeUnauthorized, "Invalid viewer token")
+			return
+		}
+
+		va.attach(c, viewer)
+		c.Next()
+	}
+}
+
+// propagated returns the user a request was signed for by another service. Unsigned users
+// are never trusted here, anyone could send the headers.
+func (va *ViewerAuth) propagated(h http.Header) (viewer, bool) {
+	if !va.identities.Signed() {
+		return viewer{}, false
+	}
+	id := va.identities.Extract(h)
+	if !id.Authenticated() {
+		return viewer{}, false
+	}
+	viewerID, err := strconv.ParseInt(id.UserID, 10, 64)
+	if err != nil || viewerID <= 0 {
+		return viewer{}, false
+	}
+	return viewer{id: viewerID, username: id.Username, roles: id.Roles}, true
+}
+
+func (va *ViewerAuth) attach(c *gin.Context, v viewer) {
+	ctx := logging.With(c.Request.Context(), "viewer_id", v.id)
+	ctx = identity.WithUser(ctx, strconv.FormatInt(v.id, 10), v.username, v.roles)
+	c.Set(viewerContextKey, v.id)
+	c.Request = c.Request.WithContext(ctx)
+}
+
+// Verify returns the user a viewer token was issued to, or 0 if it isn't valid. Without JWT
+// keys the token is checked with the user service and userID must name the user.
+func (va *ViewerAuth) Verify(token, userID string) (int64, error) {
+	v, err := va.verify(token, userID)
+	return v.id, err
+}
+
+func (va *ViewerAuth) verify(token, userID string) (viewer, error) {
+	if va.verifier.Enabled() {
+		return va.verifyJWT(token, userID)
+	}
+	if userID == "" {
+		return viewer{}, nil
+	}
+	viewerID, err := va.validate(userID, token)
+	return viewer{id: viewerID}, err
+}
+
+// verifyJWT returns the user a JWT was issued to, a zero viewer if it isn't valid. X-User-ID
+// is optional but has to match the token when sent.
+func (va *ViewerAuth) verifyJWT(token, userID string) (viewer, error) {
+	claims, err := va.verifier.Verify(token)
+	if err != nil {
+		if errors.Is(err, auth.ErrInvalidToken) {
+			slog.Debug("🔒 Rejected viewer token", "error", err)
+			return viewer{}, nil
+		}
+		return viewer{}, err
+	}
+
+	viewerID, err := claims.UserID()
+	if err != nil {
+		return viewer{}, nil
+	}
+	if userID != "" && userID != strconv.FormatInt(viewerID, 10) {
+		return viewer{}, nil
+	}
+	return viewer{id: viewerID, username: claims.Username, roles: claims.Roles}, nil
+}
+
+// RequireViewer rejects anonymous requests. API keys count as authenticated when they act
+// for a user, keys of integrators that only read don't. Anonymous requests are let through
+// in development when no JWT keys are configured.
+func (va *ViewerAuth) RequireViewer() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if key, ok := requestAPIKey(c); ok {
+			if key.UserID == 0 && !key.HasScope(models.ScopeActAsAnyUser) {
+				abortWithError(c, http.StatusForbidden, ErrCodeForbidden, "API key doesn't act for a user")
+				return
+			}
+			c.Next()
+			return
+		}
+		if ViewerID(c) != 0 {
+			c.Next()
+			return
+		}
+		if va.config.Environment == "development" && !va.verifier.Enabled() {
+			c.Next()
+			return
+		}
+
+		abortWithError(c, http.StatusUnauthorized, ErrCodeUnauthorized, "Authentication required")
+	}
+}
+
+// authorizeOwner answers 403 unless the viewer owns the resource. API keys may change the
+// content of the user they were issued for, or anyone's with the users:act_as scope.
+// Anonymous requests only get here in development.
+func authorizeOwner(c *gin.Context, ownerID int64) bool {
+	if key, ok := requestAPIKey(c); ok {
+		if key.ActsFor(ownerID) {
+			return true
+		}
+		respondError(c, http.StatusForbidden, ErrCodeForbidden, "API key can only change its user's content")
+		return false
+	}
+	viewerID := ViewerID(c)
+	if viewerID == 0 || viewerID == ownerID {
+		return true
+	}
+
+	respondError(c, http.StatusForbidden, ErrCodeForbidden, "You can only change your own content")
+	return false
+}
+
+// isOwner reports whether the caller is signed in as ownerID or holds one of their API keys.
+// Unlike authorizeOwner it never answers the request, and anonymous callers aren't the owner.
+func isOwner(c *gin.Context, ownerID int64) bool {
+	if key, ok := requestAPIKey(c); ok {
+		return key.ActsFor(ownerID)
+	}
+	viewerID := ViewerID(c)
+	return viewerID != 0 && viewerID == ownerID
+}
+
+// principalID returns the user a request acts as, the signed in viewer or the user its API
+// key was issued for, 0 for neither
+func principalID(c *gin.Context) int64 {
+	if viewerID := ViewerID(c); viewerID != 0 {
+		return viewerID
+	}
+	if key, ok := requestAPIKey(c); ok {
+		return key.UserID
+	}
+	return 0
+}
+
+// validate returns the viewer's ID, or 0 if the user service rejects the token
+func (va *ViewerAuth) validate(userID, token string) (int64, error) {
+	tokenHash := hashAPIKeySecret(userID + ":" + token)
+	if viewerID, err := va.redisRepo.GetViewerToken(tokenHash); err == nil && viewerID != 0 {
+		return viewerID, nil
+	}
+
+	viewerID, err := strconv.ParseInt(userID, 10, 64)
+	if err != nil {
+		return 0, nil
+	}
+
+	if va.userClient == nil {
+		return 0, nil
+	}
+	valid, _, err := va.userClient.ValidateUser(userID, token)
+	if err != nil {
+		return 0, err
+	}
+	if !valid {
+		return 0, nil
+	}
+
+	if err := va.redisRepo.CacheViewerToken(tokenHash, viewerID, va.config.ViewerTokenTTL); err != nil {
+		slog.Warn("⚠️ Could not cache viewer token", "user_id", viewerID, "error", err)
+	}
+	return viewerID, nil
+}
+
+// ViewerID returns the authenticated viewer of a request, or 0 for anonymous viewers
+func ViewerID(c *gin.Context) int64 {
+	return c.GetInt64(viewerContextKey)
+}
@@ -0,0 +1,396 @@
+// services/stream-management-service/internal/service/vod_packager.go
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/services/stream-management-service/internal/config"
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/services/stream-management-service/internal/models"
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/services/stream-management-service/internal/repository"
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/services/stream-management-service/pkg/aws"
+)
+
+const (
+	// packageQueueSize bounds the number of VODs waiting to be packaged
+	packageQueueSize = 100
+	// fallbackSourceBitrate is advertised for a source whose bitrate is unknown, in kbps
+	fallbackSourceBitrate = 6000
+	// hlsAudioBitrate is the audio bitrate of transcoded renditions, in kbps
+	hlsAudioBitrate = 128
+)
+
+// VODPackager remuxes recordings to HLS once they become VODs, so viewers get adaptive
+// playback instead of the raw recording. The source rendition is remuxed without
+// re-encoding, the renditions of VOD_HLS_RENDITIONS are transcoded next to it. A VOD is
+// packaged again whenever its source changes, e.g. after muting.
+type VODPackager struct {
+	config        *config.Config
+	dynamoRepo    *repository.DynamoDBRepository
+	streamService *StreamService
+	s3Client      *aws.S3Client
+	ladder        []hlsRendition
+	jobs          chan string
+	disabled      error // set when recordings can't be packaged
+}
+
+// hlsRendition is a transcoded rendition of the packaging ladder
+type hlsRendition struct {
+	name    string
+	width   int
+	height  int
+	bitrate int // video kbps
+}
+
+func NewVODPackager(cfg *config.Config, dynamoRepo *repository.DynamoDBRepository, streamService *StreamService) *VODPackager {
+	vp := &VODPackager{
+		config:        cfg,
+		dynamoRepo:    dynamoRepo,
+		streamService: streamService,
+		s3Client:      aws.NewS3Client(cfg.AWSRegion, cfg.S3BucketName),
+		jobs:          make(chan string, packageQueueSize),
+	}
+
+	for name, spec := range cfg.VODHLSRenditions {
+		rendition, err := parseHLSRendition(name, spec)
+		if err != nil {
+			slog.Warn("⚠️ Ignoring invalid VOD rendition", "name", name, "spec", spec, "error", err)
+			continue
+		}
+		vp.ladder = append(vp.ladder, rendition)
+	}
+	sort.Slice(vp.ladder, func(i, j int) bool { return vp.ladder[i].bitrate > vp.ladder[j].bitrate })
+
+	return vp
+}
+
+// Disable stops packaging recordings, viewers get the raw recording
+func (vp *VODPackager) Disable(reason error) {
+	if vp.disabled == nil {
+		slog.Warn("🚫 VOD packaging disabled", "reason", reason)
+		vp.disabled = reason
+	}
+}
+
+// StartWorkers starts the background workers that package queued VODs
+func (vp *VODPackager) StartWorkers(ctx context.Context) {
+	if vp.disabled != nil {
+		return
+	}
+
+	workers := vp.config.VODPackagerWorkers
+	if workers < 1 {
+		workers = 1
+	}
+
+	for i := 0; i < workers; i++ {
+		go func() {
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case vodID := <-vp.jobs:
+					if err := vp.packageVOD(ctx, vodID); err != nil {
+						slog.Error("❌ VOD packaging failed", "vod_id", vodID, "error", err)
+					}
+				}
+			}
+		}()
+	}
+
+	slog.Info("📦 Started VOD packaging workers", "workers", workers, "renditions", len(vp.ladder)+1)
+}
+
+// Enqueue queues a VOD to be packaged
+func (vp *VODPackager) Enqueue(vodID string) error {
+	if vp.disabled != nil {
+		return nil
+	}
+
+	select {
+	case vp.jobs <- vodID:
+		return nil
+	default:
+		return fmt.Errorf("packaging queue is full")
+	}
+}
+
+// RepackageVOD handles POST /admin/vods/:id/package
+func (vp *VODPackager) RepackageVOD(c *gin.Context) {
+	if vp.disabled != nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "VOD packaging is unavailable: " + vp.disabled.Error()})
+		return
+	}
+
+	vod, err := vp.dynamoRepo.GetVODByID(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "VOD not found"})
+		return
+	}
+
+	if err := vp.Enqueue(vod.ID); err != nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Packaging queue is full, try again later"})
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{"message": "VOD queued for packaging", "vod_id": vod.ID})
+}
+
+// packageVOD writes the HLS renditions and master playlist of a VOD to S3 and points the VOD
+// and its stream at them
+func (vp *VODPackager) packageVOD(ctx context.Context, vodID string) error {
+	vod, err := vp.dynamoRepo.GetVODByID(vodID)
+	if err != nil {
+		return err
+	}
+
+	source := sourceURL(vod.Renditions)
+	if source == "" {
+		return fmt.Errorf("vod has no rendition to package")
+	}
+	if vod.HLS != nil && vod.HLS.Source == source {
+		return nil // already packaged
+	}
+
+	slog.Info("📦 Packaging recording to HLS", "vod_id", vod.ID)
+
+	packagedAt := time.Now()
+	workDir := filepath.Join(vp.config.ClipWorkDir, fmt.Sprintf("hls_%s_%d", vod.ID, packagedAt.Unix()))
+	if err := os.MkdirAll(workDir, 0o755); err != nil {
+		return fmt.Errorf("failed to create work dir: %w", err)
+	}
+
+	renditions := []models.Rendition{vp.sourceRendition(vod, source)}
+	if err := vp.remux(ctx, source, filepath.Join(workDir, "source")); err != nil {
+		os.RemoveAll(workDir)
+		return err
+	}
+	for _, rung := range vp.ladder {
+		if err := vp.transcode(ctx, source, filepath.Join(workDir, rung.name), rung); err != nil {
+			os.RemoveAll(workDir)
+			return fmt.Errorf("rendition %s: %w", rung.name, err)
+		}
+		renditions = append(renditions, models.Rendition{
+			Name:       rung.name,
+			Resolution: fmt.Sprintf("%dx%d", rung.width, rung.height),
+			Bitrate:    rung.bitrate,
+		})
+	}
+
+	if err := writeMasterPlaylist(filepath.Join(workDir, "master.m3u8"), renditions); err != nil {
+		os.RemoveAll(workDir)
+		return err
+	}
+
+	hls, err := vp.upload(workDir, fmt.Sprintf("vods/%s/hls/%d", vod.ID, packagedAt.Unix()), renditions)
+	if err != nil {
+		os.RemoveAll(workDir)
+		return err
+	}
+	hls.Source = source
+	hls.PackagedAt = packagedAt
+
+	// Mock uploads point at the local files, so only remove them once they're really in S3
+	if !strings.HasPrefix(hls.ManifestURL, "file://") {
+		os.RemoveAll(workDir)
+	}
+
+	// The VOD may have been muted during a long packaging, its new source is queued already
+	if latest, err := vp.dynamoRepo.GetVODByID(vod.ID); err == nil {
+		vod = latest
+	}
+	if sourceURL(vod.Renditions) != source {
+		slog.Info("ℹ️ VOD source changed while packaging, dropping the package", "vod_id", vod.ID)
+		return nil
+	}
+
+	vod.HLS = hls
+	vod.UpdatedAt = time.Now()
+	if err := vp.dynamoRepo.SaveVOD(vod); err != nil {
+		return err
+	}
+
+	if vod.StreamID != "" {
+		if stream, err := vp.streamService.GetStreamByIDInternal(vod.StreamID); err == nil {
+			stream.VODReady = true
+			stream.VODPlaybackURLs = map[string]string{"master": hls.ManifestURL}
+			for _, rendition := range hls.Renditions {
+				stream.VODPlaybackURLs[rendition.Name] = rendition.URL
+			}
+			if err := vp.streamService.UpdateStreamInternal(stream); err != nil {
+				slog.Warn("⚠️ Could not record VOD playback on stream", "stream_id", stream.ID, "error", err)
+			}
+		}
+	}
+
+	event := map[string]interface{}{
+		"vod_id":       vod.ID,
+		"stream_id":    vod.StreamID,
+		"user_id":      vod.UserID,
+		"manifest_url": hls.ManifestURL,
+		"renditions":   len(hls.Renditions),
+	}
+	if err := vp.streamService.PublishEvent("vod_ready", event); err != nil {
+		slog.Warn("⚠️ Could not publish VOD ready event", "vod_id", vod.ID, "error", err)
+	}
+
+	slog.Info("✅ VOD packaged", "vod_id", vod.ID, "renditions", len(hls.Renditions), "manifest_url", hls.ManifestURL)
+	return nil
+}
+
+// sourceRendition describes the remuxed source in the master playlist
+func (vp *VODPackager) sourceRendition(vod *models.VOD, source string) models.Rendition {
+	rendition := models.Rendition{Name: "source"}
+	for _, r := range vod.Renditions {
+		if r.URL == source {
+			rendition.Resolution = r.Resolution
+			rendition.Bitrate = r.Bitrate
+		}
+	}
+
+	// Estimate the bitrate from the file when the media server didn't report it
+	if rendition.Bitrate == 0 && vod.FileSize > 0 && vod.Duration > 0 {
+		rendition.Bitrate = int(vod.FileSize * 8 / 1000 / vod.Duration)
+	}
+	if rendition.Bitrate == 0 {
+		rendition.Bitrate = fallbackSourceBitrate
+	}
+	return rendition
+}
+
+// remux copies the recording's streams into HLS segments without re-encoding
+func (vp *VODPackager) remux(ctx context.Context, source, dir string) error {
+	return vp.runFFmpeg(ctx, source, dir, "-c", "copy")
+}
+
+// transcode encodes one rendition of the ladder
+func (vp *VODPackager) transcode(ctx context.Context, source, dir string, rung hlsRendition) error {
+	return vp.runFFmpeg(ctx, source, dir,
+		"-vf", fmt.Sprintf("scale=%d:%d:force_original_aspect_ratio=decrease:force_divisible_by=2", rung.width, rung.height),
+		"-c:v", "libx264",
+		"-preset", "veryfast",
+		"-b:v", fmt.Sprintf("%dk", rung.bitrate),
+		"-maxrate", fmt.Sprintf("%dk", rung.bitrate*107/100),
+		"-bufsize", fmt.Sprintf("%dk", rung.bitrate*3/2),
+		"-c:a", "aac",
+		"-b:a", fmt.Sprintf("%dk", hlsAudioBitrate),
+	)
+}
+
+// runFFmpeg writes dir/index.m3u8 and its segments from the source with the given codec options
+func (vp *VODPackager) runFFmpeg(ctx context.Context, source, dir string, codecArgs ...string) error {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("failed to create rendition dir: %w", err)
+	}
+	source = strings.TrimPrefix(source, "file://")
+
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Hour)
+	defer cancel()
+
+	args := []string{"-y", "-i", source}
+	args = append(args, codecArgs...)
+	args = append(args,
+		"-f", "hls",
+		"-hls_time", strconv.Itoa(int(vp.config.VODSegmentDuration.Seconds())),
+		"-hls_playlist_type", "vod",
+		"-hls_segment_filename", filepath.Join(dir, "segment_%05d.ts"),
+		filepath.Join(dir, "index.m3u8"),
+	)
+
+	cmd := exec.CommandContext(ctx, vp.config.FFmpegPath, args...)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("ffmpeg failed: %w: %s", err, lastLine(string(output)))
+	}
+	return nil
+}
+
+// upload sends every file of the package to S3 under prefix. Media playlists and segments go
+// first, so the master playlist never points at missing files.
+func (vp *VODPackager) upload(workDir, prefix string, renditions []models.Rendition) (*models.HLSPackage, error) {
+	hls := &models.HLSPackage{}
+
+	for _, rendition := range renditions {
+		dir := filepath.Join(workDir, rendition.Name)
+		segments, err := filepath.Glob(filepath.Join(dir, "*.ts"))
+		if err != nil {
+			return nil, fmt.Errorf("failed to list segments: %w", err)
+		}
+		for _, segment := range segments {
+			key := fmt.Sprintf("%s/%s/%s", prefix, rendition.Name, filepath.Base(segment))
+			if _, err := vp.s3Client.UploadFile(segment, key, "video/mp2t"); err != nil {
+				return nil, err
+			}
+		}
+
+		url, err := vp.s3Client.UploadFile(filepath.Join(dir, "index.m3u8"), fmt.Sprintf("%s/%s/index.m3u8", prefix, rendition.Name), "application/vnd.apple.mpegurl")
+		if err != nil {
+			return nil, err
+		}
+		rendition.URL = url
+		hls.Renditions = append(hls.Renditions, rendition)
+	}
+
+	url, err := vp.s3Client.UploadFile(filepath.Join(workDir, "master.m3u8"), prefix+"/master.m3u8", "application/vnd.apple.mpegurl")
+	if err != nil {
+		return nil, err
+	}
+	hls.ManifestURL = url
+	return hls, nil
+}
+
+// writeMasterPlaylist lists the renditions' media playlists, relative to the master playlist
+func writeMasterPlaylist(path string, renditions []models.Rendition) error {
+	var playlist strings.Builder
+	playlist.WriteString("#EXTM3U\n#EXT-X-VERSION:3\n")
+	for _, rendition := range renditions {
+		fmt.Fprintf(&playlist, "#EXT-X-STREAM-INF:BANDWIDTH=%d", rendition.Bitrate*1000)
+		if rendition.Resolution != "" {
+			fmt.Fprintf(&playlist, ",RESOLUTION=%s", rendition.Resolution)
+		}
+		fmt.Fprintf(&playlist, ",NAME=%q\n%s/index.m3u8\n", rendition.Name, rendition.Name)
+	}
+
+	if err := os.WriteFile(path, []byte(playlist.String()), 0o644); err != nil {
+		return fmt.Errorf("failed to write master playlist: %w", err)
+	}
+	return nil
+}
+
+// parseHLSRendition reads a ladder rendition written as WxH@kbps, e.g. 1280x720@2800
+func parseHLSRendition(name, spec string) (hlsRendition, error) {
+	size, bitrate, ok := strings.Cut(strings.TrimSpace(spec), "@")
+	if !ok {
+		return hlsRendition{}, fmt.Errorf("expected WxH@kbps")
+	}
+	width, height, ok := strings.Cut(size, "x")
+	if !ok {
+		return hlsRendition{}, fmt.Errorf("expected WxH@kbps")
+	}
+
+	rendition := hlsRendition{name: strings.TrimSpace(name)}
+	var err error
+	if rendition.width, err = strconv.Atoi(width); err != nil || rendition.width <= 0 {
+		return hlsRendition{}, fmt.Errorf("invalid width %q", width)
+	}
+	if rendition.height, err = strconv.Atoi(height); err != nil || rendition.height <= 0 {
+		return hlsRendition{}, fmt.Errorf("invalid height %q", height)
+	}
+	if rendition.bitrate, err = strconv.Atoi(bitrate); err != nil || rendition.bitrate <= 0 {
+		return hlsRendition{}, fmt.Errorf("invalid bitrate %q", bitrate)
+	}
+	if rendition.name == "" || rendition.name == "source" || strings.ContainsAny(rendition.name, "/ ") {
+		return hlsRendition{}, fmt.Errorf("invalid name")
+	}
+	return rendition, nil
+}
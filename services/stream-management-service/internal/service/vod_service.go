@@ -0,0 +1,209 @@
+// services/stream-management-service/internal/service/vod_service.go
+package service
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/services/stream-management-service/internal/config"
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/services/stream-management-service/internal/models"
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/services/stream-management-service/internal/repository"
+)
+
+const (
+	defaultPageLimit = 20
+	maxPageLimit     = 100
+)
+
+type VODService struct {
+	config     *config.Config
+	dynamoRepo *repository.DynamoDBRepository
+}
+
+func NewVODService(cfg *config.Config, dynamoRepo *repository.DynamoDBRepository) *VODService {
+	return &VODService{
+		config:     cfg,
+		dynamoRepo: dynamoRepo,
+	}
+}
+
+// CreateVODFromRecording creates a public VOD entry for a stream whose recording has been uploaded
+func (v *VODService) CreateVODFromRecording(stream *models.Stream, fileSize, durationSec int64) (*models.VOD, error) {
+	if stream.RecordingURL == "" {
+		return nil, fmt.Errorf("stream %s has no recording", stream.ID)
+	}
+
+	// Media servers don't always report the recording length, use the stream duration instead
+	if durationSec == 0 {
+		durationSec = stream.Duration
+	}
+
+	rendition := models.Rendition{
+		Name:       "source",
+		URL:        stream.RecordingURL,
+		Resolution: stream.Metadata["resolution"],
+	}
+	if bitrate, err := strconv.Atoi(stream.Metadata["bitrate"]); err == nil {
+		rendition.Bitrate = bitrate
+	}
+
+	now := time.Now()
+	vod := &models.VOD{
+		ID:         "vod_" + stream.ID,
+		StreamID:   stream.ID,
+		UserID:     stream.UserID,
+		Title:      stream.Title,
+		Duration:   durationSec,
+		FileSize:   fileSize,
+		Renditions: []models.Rendition{rendition},
+		Visibility: models.VODVisibilityPublic,
+		Metadata: map[string]string{
+			"stream_key": stream.StreamKey,
+		},
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	vod.Chapters = recordingChapters(stream.Chapters, durationSec)
+	vod.Markers = recordingMarkers(stream.Markers, durationSec)
+	// A takedown of the stream covers its recording too
+	if stream.Blocked {
+		vod.Blocked = true
+		vod.TakedownID = stream.TakedownID
+	}
+
+	if err := v.dynamoRepo.CreateVOD(vod); err != nil {
+		return nil, fmt.Errorf("failed to create vod: %w", err)
+	}
+
+	slog.Info("🎞️ VOD created from stream", "vod_id", vod.ID, "stream_id", stream.ID)
+	return vod, nil
+}
+
+// AttachPremiereStream links a premiering VOD to the live stream it is playing as
+func (v *VODService) AttachPremiereStream(vodID, streamID string) error {
+	vod, err := v.dynamoRepo.GetVODByID(vodID)
+	if err != nil {
+		return err
+	}
+	if vod.Premiere == nil {
+		return fmt.Errorf("vod %s has no premiere", vodID)
+	}
+
+	vod.Premiere.StreamID = streamID
+	vod.UpdatedAt = time.Now()
+	return v.dynamoRepo.SaveVOD(vod)
+}
+
+// UseUploadedRecording points the source rendition of a stream's VOD at its recording once a
+// retried upload moved it to S3. A premiere's VOD already has its own files.
+func (v *VODService) UseUploadedRecording(stream *models.Stream) error {
+	if stream.Metadata["premiere_vod_id"] != "" {
+		return nil
+	}
+
+	vod, err := v.dynamoRepo.GetVODByID("vod_" + stream.ID)
+	if err != nil {
+		return err
+	}
+	for i := range vod.Renditions {
+		if vod.Renditions[i].Name == "source" {
+			vod.Renditions[i].URL = stream.RecordingURL
+		}
+	}
+
+	vod.UpdatedAt = time.Now()
+	return v.dynamoRepo.SaveVOD(vod)
+}
+
+// ListVODs handles GET /api/v1/vods and /api/v2/vods and returns the public catalog
+func (v *VODService) ListVODs(c *gin.Context) {
+	limit, cursor := parsePagination(c)
+
+	vods, nextCursor, err := v.dynamoRepo.ListVODs(models.VODVisibilityPublic, models.DeletedExclude, limit, cursor)
+	if err != nil {
+		respondPageError(c, err)
+		return
+	}
+	vods = withoutBlockedVODs(vods)
+
+	respondList(c, http.StatusOK, "vods", vods, len(vods), nextCursor, nil)
+}
+
+// GetUserVODs handles GET /api/v1/users/:id/vods and /api/v2/users/:id/vods and returns a
+// user's public VODs. The owner can ask for deleted VODs with ?deleted=include or only, of
+// every visibility.
+func (v *VODService) GetUserVODs(c *gin.Context) {
+	userID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, ErrCodeInvalidRequest, "Invalid user ID")
+		return
+	}
+	deleted, ok := parseDeletedFilter(c)
+	if !ok {
+		return
+	}
+
+	visibility := models.VODVisibilityPublic
+	if deleted != models.DeletedExclude {
+		if !authorizeSignedInOwner(c, userID) {
+			return
+		}
+		visibility = ""
+	}
+
+	limit, cursor := parsePagination(c)
+
+	vods, nextCursor, err := v.dynamoRepo.GetVODsByUser(userID, visibility, deleted, limit, cursor)
+	if err != nil {
+		respondPageError(c, err)
+		return
+	}
+	vods = withoutBlockedVODs(vods)
+
+	respondList(c, http.StatusOK, "vods", vods, len(vods), nextCursor, gin.H{"user_id": userID})
+}
+
+// GetVODByID handles GET /api/v1/vods/:id and /api/v2/vods/:id. Private VODs are not exposed.
+func (v *VODService) GetVODByID(c *gin.Context) {
+	vod, err := v.dynamoRepo.GetVODByID(c.Param("id"))
+	if err != nil || vod.Visibility == models.VODVisibilityPrivate || vod.Deleted() {
+		respondError(c, http.StatusNotFound, ErrCodeNotFound, "VOD not found")
+		return
+	}
+	if vod.Blocked {
+		respondTakedown(c, vod.TakedownID)
+		return
+	}
+
+	c.JSON(http.StatusOK, vod)
+}
+
+// parsePagination reads the limit and cursor query parameters
+func parsePagination(c *gin.Context) (int, string) {
+	limit := defaultPageLimit
+	if l, err := strconv.Atoi(c.Query("limit")); err == nil && l > 0 {
+		limit = l
+	}
+	if limit > maxPageLimit {
+		limit = maxPageLimit
+	}
+
+	return limit, c.Query("cursor")
+}
+
+// respondPageError answers a list that couldn't be read, a bad request only when its cursor
+// was invalid
+func respondPageError(c *gin.Context, err error) {
+	if errors.Is(err, repository.ErrInvalidCursor) {
+		respondError(c, http.StatusBadRequest, ErrCodeInvalidRequest, "Invalid cursor")
+		return
+	}
+	slog.ErrorContext(c.Request.Context(), "❌ Could not list page", "path", c.FullPath(), "error", err)
+	respondError(c, http.StatusInternalServerError, ErrCodeInternal, "Could not list page")
+}
@@ -0,0 +1,87 @@
+// services/stream-management-service/internal/service/whip_ingest.go
+package service
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/services/stream-management-service/internal/logging"
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/services/stream-management-service/internal/models"
+)
+
+// WHIPAuthRequest is sent by the media server when a browser POSTs its WHIP offer. Browsers
+// send the stream key as the offer's bearer token.
+type WHIPAuthRequest struct {
+	Token string `json:"token" form:"token"` // Stream key, taken from the Authorization header when empty
+	IP    string `json:"addr" form:"addr"`   // Client IP
+	App   string `json:"app" form:"app"`     // Application name
+	// SessionID is the WHIP resource the media server created for the publisher, later
+	// callbacks pass it as client_id. One is generated when empty.
+	SessionID string `json:"session_id" form:"session_id"`
+}
+
+// AuthenticateWHIP authorizes a WebRTC publisher with the same stream key checks as RTMP. The
+// media server then reports the stream with the usual started and ended callbacks, which
+// record it as a webrtc stream from the session stored here.
+func (h *IngestHandler) AuthenticateWHIP(c *gin.Context) {
+	ctx := c.Request.Context()
+	var req WHIPAuthRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		if err := c.ShouldBind(&req); err != nil {
+			slog.WarnContext(ctx, "❌ Error parsing WHIP auth request", "error", err)
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request format"})
+			return
+		}
+	}
+
+	token := strings.TrimSpace(req.Token)
+	if token == "" {
+		token = strings.TrimSpace(strings.TrimPrefix(c.GetHeader("Authorization"), "Bearer "))
+	}
+	if token == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "Stream key required",
+			"code":  "MISSING_STREAM_KEY",
+		})
+		return
+	}
+
+	streamKey := h.extractStreamKey(token)
+	ctx = logging.With(ctx, "stream_key", streamKey)
+	slog.InfoContext(ctx, "🔑 WHIP auth request", "client_ip", req.IP, "app", req.App)
+
+	// Premieres are relayed over RTMP by this service, never published from a browser
+	if strings.HasPrefix(streamKey, premiereKeyPrefix) {
+		c.JSON(http.StatusForbidden, gin.H{
+			"error": "Invalid stream key",
+			"code":  "INVALID_STREAM_KEY",
+		})
+		return
+	}
+
+	if req.SessionID == "" {
+		req.SessionID = generateWHIPSessionID()
+	}
+	if req.App == "" {
+		req.App = "whip"
+	}
+
+	h.authorizePublisher(ctx, c, streamKey, models.IngestProtocolWebRTC, RTMPAuthRequest{
+		Name:     streamKey,
+		IP:       req.IP,
+		App:      req.App,
+		ClientID: req.SessionID,
+	})
+}
+
+func generateWHIPSessionID() string {
+	bytes := make([]byte, 16)
+	rand.Read(bytes)
+	return hex.EncodeToString(bytes)
+}
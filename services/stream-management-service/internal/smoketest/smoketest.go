@@ -0,0 +1,446 @@
+// services/stream-management-service/internal/smoketest/smoketest.go
+package smoketest
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/services/stream-management-service/internal/config"
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/services/stream-management-service/internal/models"
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/services/stream-management-service/internal/repository"
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/services/stream-management-service/internal/service"
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/services/stream-management-service/pkg/aws"
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/shared/go/pkg/events"
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/shared/go/pkg/identity"
+)
+
+// pollInterval is how often side effects that happen in the background are checked again
+const pollInterval = time.Second
+
+// readerWarmup gives the event reader time to position itself before anything is published
+const readerWarmup = 2 * time.Second
+
+// StepResult is the outcome of one step of the lifecycle
+type StepResult struct {
+	Name     string `json:"name"`
+	OK       bool   `json:"ok"`
+	Skipped  bool   `json:"skipped,omitempty"`
+	Error    string `json:"error,omitempty"`
+	Duration int64  `json:"duration_ms"`
+}
+
+// Report is the outcome of a smoke test run
+type Report struct {
+	TargetURL string       `json:"target_url"`
+	StreamID  string       `json:"stream_id,omitempty"`
+	Steps     []StepResult `json:"steps"`
+}
+
+// Passed reports whether every step that ran succeeded
+func (r *Report) Passed() bool {
+	for _, step := range r.Steps {
+		if !step.OK && !step.Skipped {
+			return false
+		}
+	}
+	return len(r.Steps) > 0
+}
+
+// errSkipped marks a step whose side effect can't be observed in this environment
+type errSkipped struct{ reason string }
+
+func (e errSkipped) Error() string { return e.reason }
+
+// Runner publishes a synthetic stream to a deployed instance through the same signed
+// callbacks a media server sends, and checks every side effect in the real dependencies
+type Runner struct {
+	config     *config.Config
+	dynamoRepo *repository.DynamoDBRepository
+	redisRepo  *repository.RedisRepository
+	streamKeys *service.StreamKeyService
+	http       *http.Client
+	chatHTTP   *http.Client // signed as this service, chat room activity is only shown to owners and services
+
+	streamKey string
+	streamID  string
+
+	mu     sync.Mutex
+	events map[string]bool // event types seen for the synthetic stream
+}
+
+func NewRunner(cfg *config.Config, dynamoRepo *repository.DynamoDBRepository, redisRepo *repository.RedisRepository, streamKeys *service.StreamKeyService) *Runner {
+	return &Runner{
+		config:     cfg,
+		dynamoRepo: dynamoRepo,
+		redisRepo:  redisRepo,
+		streamKeys: streamKeys,
+		http:       &http.Client{Timeout: 10 * time.Second},
+		chatHTTP: &http.Client{
+			Timeout:   10 * time.Second,
+			Transport: identity.NewPropagator(cfg.IdentitySecret).ServiceTransport("stream-management-service", nil),
+		},
+		events: make(map[string]bool),
+	}
+}
+
+// Run goes through auth, start, metrics, end and recording. A step that fails stops the
+// run, the steps after it depend on its side effects.
+func (r *Runner) Run(ctx context.Context) *Report {
+	ctx, cancel := context.WithTimeout(ctx, r.config.SmokeTestTimeout)
+	defer cancel()
+
+	report := &Report{TargetURL: r.config.SmokeTestURL}
+	slog.InfoContext(ctx, "💨 Running smoke test", "target", r.config.SmokeTestURL, "user_id", r.config.SmokeTestUserID)
+
+	eventsObserved := r.watchEvents(ctx)
+
+	steps := []struct {
+		name string
+		run  func(context.Context) error
+	}{
+		{"auth", r.authenticate},
+		{"start", r.start},
+		{"metrics", r.reportMetrics},
+		{"end", r.end},
+		{"recording", r.record},
+		{"events", func(ctx context.Context) error {
+			return r.checkEvents(ctx, eventsObserved, "stream_started", "stream_ended", "recording_completed")
+		}},
+		{"chatroom", r.checkChatroom},
+	}
+
+	for _, step := range steps {
+		start := time.Now()
+		err := step.run(ctx)
+
+		result := StepResult{Name: step.name, OK: err == nil, Duration: time.Since(start).Milliseconds()}
+		if skipped, ok := err.(errSkipped); ok {
+			result.Skipped = true
+			result.Error = skipped.reason
+			slog.WarnContext(ctx, "⏭️ Smoke test step skipped", "step", step.name, "reason", skipped.reason)
+		} else if err != nil {
+			result.Error = err.Error()
+			slog.ErrorContext(ctx, "❌ Smoke test step failed", "step", step.name, "error", err)
+		} else {
+			slog.InfoContext(ctx, "✅ Smoke test step passed", "step", step.name, "duration_ms", result.Duration)
+		}
+		report.Steps = append(report.Steps, result)
+
+		if !result.OK && !result.Skipped {
+			break
+		}
+	}
+
+	report.StreamID = r.streamID
+	r.cleanup()
+	return report
+}
+
+func (r *Runner) authenticate(ctx context.Context) error {
+	if r.config.SmokeTestUserID == 0 {
+		return fmt.Errorf("SMOKE_TEST_USER_ID is not set")
+	}
+
+	key, _, err := r.streamKeys.Generate(r.config.SmokeTestUserID, r.config.SmokeTestTimeout)
+	if err != nil {
+		return fmt.Errorf("failed to generate stream key, is STREAM_KEY_SECRET set: %w", err)
+	}
+	r.streamKey = key
+
+	if _, err := r.callback(ctx, "/rtmp/auth", r.publishRequest(nil)); err != nil {
+		return err
+	}
+
+	if _, err := r.redisRepo.GetStreamSession(r.streamKey); err != nil {
+		return fmt.Errorf("no Redis session after auth: %w", err)
+	}
+	return nil
+}
+
+func (r *Runner) start(ctx context.Context) error {
+	response, err := r.callback(ctx, "/rtmp/started", r.publishRequest(nil))
+	if err != nil {
+		return err
+	}
+	streamID, _ := response["stream_id"].(string)
+	if streamID == "" {
+		return fmt.Errorf("start callback returned no stream_id")
+	}
+	r.streamID = streamID
+
+	stream, err := r.dynamoRepo.GetStreamByID(streamID)
+	if err != nil {
+		return fmt.Errorf("no DynamoDB record for stream %s: %w", streamID, err)
+	}
+	if stream.Status != models.StreamStatusLive {
+		return fmt.Errorf("stream is %s instead of live", stream.Status)
+	}
+
+	session, err := r.session()
+	if err != nil {
+		return err
+	}
+	if session.StreamID != streamID {
+		return fmt.Errorf("Redis session doesn't point at stream %s", streamID)
+	}
+	return nil
+}
+
+func (r *Runner) reportMetrics(ctx context.Context) error {
+	_, err := r.callback(ctx, "/rtmp/health", map[string]interface{}{
+		"name":              r.streamKey,
+		"stream_id":         r.streamID,
+		"bitrate":           3000,
+		"fps":               30.0,
+		"dropped_frames":    0,
+		"keyframe_interval": 2.0,
+	})
+	if err != nil {
+		return err
+	}
+
+	samples, err := r.redisRepo.GetHealthSamples(r.streamID)
+	if err != nil {
+		return fmt.Errorf("failed to read health samples: %w", err)
+	}
+	if len(samples) == 0 {
+		return fmt.Errorf("health report wasn't recorded")
+	}
+	return nil
+}
+
+// end disconnects the synthetic publisher. With a reconnect grace period the stream is only
+// ended once it runs out, so this waits for it.
+func (r *Runner) end(ctx context.Context) error {
+	if _, err := r.callback(ctx, "/rtmp/ended", r.publishRequest(map[string]interface{}{"duration": "5"})); err != nil {
+		return err
+	}
+
+	return r.poll(ctx, func() error {
+		stream, err := r.dynamoRepo.GetStreamByID(r.streamID)
+		if err != nil {
+			return err
+		}
+		if stream.Status != models.StreamStatusEnded {
+			return fmt.Errorf("stream is still %s", stream.Status)
+		}
+		if _, err := r.redisRepo.GetStreamSession(r.streamKey); err == nil {
+			return fmt.Errorf("Redis session outlived the stream")
+		}
+		return nil
+	})
+}
+
+func (r *Runner) record(ctx context.Context) error {
+	file := fmt.Sprintf("/tmp/smoke-test-%s.flv", r.streamID)
+	response, err := r.callback(ctx, "/rtmp/recorded", r.publishRequest(map[string]interface{}{
+		"file":     file,
+		"size":     "0",
+		"duration": "5",
+	}))
+	if err != nil {
+		return err
+	}
+	if vodID, _ := response["vod_id"].(string); vodID == "" {
+		return fmt.Errorf("recording callback created no VOD")
+	}
+
+	stream, err := r.dynamoRepo.GetStreamByID(r.streamID)
+	if err != nil {
+		return err
+	}
+	if stream.RecordingURL == "" {
+		return fmt.Errorf("stream has no recording URL")
+	}
+	return nil
+}
+
+func (r *Runner) checkEvents(ctx context.Context, observed bool, eventTypes ...string) error {
+	if !observed {
+		return errSkipped{reason: "events aren't published to Kinesis in this environment"}
+	}
+
+	return r.poll(ctx, func() error {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+
+		var missing []string
+		for _, eventType := range eventTypes {
+			if !r.events[eventType] {
+				missing = append(missing, eventType)
+			}
+		}
+		if len(missing) > 0 {
+			return fmt.Errorf("no %s event seen", strings.Join(missing, ", "))
+		}
+		return nil
+	})
+}
+
+// checkChatroom makes sure the chat service serves the synthetic stream's chat room, which
+// shares the stream's ID
+func (r *Runner) checkChatroom(ctx context.Context) error {
+	url := fmt.Sprintf("%s/chatrooms/%s/activity?period_hours=1", strings.TrimSuffix(r.config.ChatServiceURL, "/"), r.streamID)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := r.chatHTTP.Do(req)
+	if err != nil {
+		return fmt.Errorf("chat service unreachable: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("chat service answered %d for the stream's chat room", resp.StatusCode)
+	}
+	return nil
+}
+
+// watchEvents collects the event types published for the synthetic stream. It returns false
+// when events can't be read here.
+func (r *Runner) watchEvents(ctx context.Context) bool {
+	reader := aws.NewKinesisReader(r.config.AWSRegion, r.config.KinesisStreamName)
+	if reader.Mock() {
+		return false
+	}
+
+	go func() {
+		err := reader.Run(ctx, func(record []byte) error {
+			var envelope events.Envelope
+			if err := json.Unmarshal(record, &envelope); err != nil {
+				return nil // not ours to judge
+			}
+			var data struct {
+				StreamID string `json:"stream_id"`
+			}
+			if json.Unmarshal(envelope.Data, &data) != nil || data.StreamID == "" {
+				return nil
+			}
+
+			r.mu.Lock()
+			if data.StreamID == r.streamID {
+				r.events[envelope.EventType] = true
+			}
+			r.mu.Unlock()
+			return nil
+		})
+		if err != nil {
+			slog.WarnContext(ctx, "⚠️ Smoke test event reader stopped", "error", err)
+		}
+	}()
+
+	select {
+	case <-time.After(readerWarmup):
+	case <-ctx.Done():
+	}
+	return true
+}
+
+// callback sends a media server callback signed like the media server would and returns the
+// decoded response
+func (r *Runner) callback(ctx context.Context, path string, body map[string]interface{}) (map[string]interface{}, error) {
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, strings.TrimSuffix(r.config.SmokeTestURL, "/")+path, bytes.NewReader(payload))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	if serverID, secret, ok := r.mediaServer(); ok {
+		timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+		req.Header.Set(service.MediaServerHeader, serverID)
+		req.Header.Set(service.TimestampHeader, timestamp)
+		req.Header.Set(service.SignatureHeader, service.SignRTMPCallback(secret, http.MethodPost, req.URL.RequestURI(), timestamp, payload))
+	}
+
+	resp, err := r.http.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("POST %s failed: %w", path, err)
+	}
+	defer resp.Body.Close()
+
+	var response map[string]interface{}
+	json.NewDecoder(resp.Body).Decode(&response)
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("POST %s answered %d: %v", path, resp.StatusCode, response["error"])
+	}
+	return response, nil
+}
+
+// mediaServer returns the media server the callbacks are signed as
+func (r *Runner) mediaServer() (string, string, bool) {
+	if serverID := r.config.SmokeTestMediaServer; serverID != "" {
+		secret, ok := r.config.RTMPCallbackSecrets[serverID]
+		return serverID, secret, ok
+	}
+	for serverID, secret := range r.config.RTMPCallbackSecrets {
+		return serverID, secret, true
+	}
+	return "", "", false
+}
+
+func (r *Runner) publishRequest(extra map[string]interface{}) map[string]interface{} {
+	request := map[string]interface{}{
+		"name":      r.streamKey,
+		"addr":      "127.0.0.1",
+		"app":       "live",
+		"client_id": "smoke-test",
+	}
+	for field, value := range extra {
+		request[field] = value
+	}
+	return request
+}
+
+func (r *Runner) session() (*models.StreamSession, error) {
+	data, err := r.redisRepo.GetStreamSession(r.streamKey)
+	if err != nil {
+		return nil, fmt.Errorf("no Redis session: %w", err)
+	}
+
+	var session models.StreamSession
+	if err := json.Unmarshal([]byte(data), &session); err != nil {
+		return nil, fmt.Errorf("failed to parse Redis session: %w", err)
+	}
+	return &session, nil
+}
+
+// poll retries check until it passes or ctx is done, returning its last error
+func (r *Runner) poll(ctx context.Context, check func() error) error {
+	for {
+		err := check()
+		if err == nil {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(pollInterval):
+		}
+	}
+}
+
+// cleanup revokes the synthetic stream key so it can't be published with again
+func (r *Runner) cleanup() {
+	if r.streamKey == "" {
+		return
+	}
+	if _, err := r.streamKeys.Revoke(r.streamKey); err != nil {
+		slog.Warn("⚠️ Could not revoke smoke test stream key", "error", err)
+	}
+}
@@ -0,0 +1,120 @@
+// services/stream-management-service/internal/streamkey/streamkey.go
+package streamkey
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Prefix starts every generated key so they can be told apart from keys issued by the user service
+const Prefix = "lsk"
+
+// signatureLength is the number of hex characters of the HMAC kept in a key
+const signatureLength = 32
+
+var (
+	ErrMalformed    = errors.New("malformed stream key")
+	ErrBadSignature = errors.New("stream key signature mismatch")
+	ErrExpired      = errors.New("stream key expired")
+)
+
+// Claims is what a generated key carries. Nonce identifies the key, e.g. to revoke it.
+type Claims struct {
+	UserID    int64
+	Nonce     string
+	ExpiresAt *time.Time // nil for keys that never expire
+}
+
+// Generator signs stream keys with a secret so they can be validated without asking the
+// user service. Keys look like lsk_<user id>_<expiry>_<nonce>_<signature>, which is safe to
+// put in an RTMP URL.
+type Generator struct {
+	secret []byte
+}
+
+func NewGenerator(secret string) *Generator {
+	return &Generator{secret: []byte(secret)}
+}
+
+// Enabled reports whether a signing secret is configured
+func (g *Generator) Enabled() bool {
+	return len(g.secret) > 0
+}
+
+// IsGenerated reports whether a key has the format of generated keys, without checking it
+func IsGenerated(key string) bool {
+	return strings.HasPrefix(key, Prefix+"_")
+}
+
+// Generate issues a key for a user. A ttl of 0 makes a key that never expires.
+func (g *Generator) Generate(userID int64, ttl time.Duration) (string, *Claims, error) {
+	if !g.Enabled() {
+		return "", nil, fmt.Errorf("stream key generation is not configured")
+	}
+	if userID <= 0 {
+		return "", nil, fmt.Errorf("invalid user ID %d", userID)
+	}
+
+	nonce := make([]byte, 8)
+	if _, err := rand.Read(nonce); err != nil {
+		return "", nil, fmt.Errorf("failed to generate nonce: %w", err)
+	}
+
+	claims := &Claims{UserID: userID, Nonce: hex.EncodeToString(nonce)}
+	expiry := int64(0)
+	if ttl > 0 {
+		expiresAt := time.Now().Add(ttl).Truncate(time.Second)
+		claims.ExpiresAt = &expiresAt
+		expiry = expiresAt.Unix()
+	}
+
+	payload := fmt.Sprintf("%s_%d_%d_%s", Prefix, userID, expiry, claims.Nonce)
+	return payload + "_" + g.sign(payload), claims, nil
+}
+
+// Parse checks a key's signature and expiry and returns its claims. It doesn't know about
+// revoked keys.
+func (g *Generator) Parse(key string) (*Claims, error) {
+	parts := strings.Split(key, "_")
+	if len(parts) != 5 || parts[0] != Prefix {
+		return nil, ErrMalformed
+	}
+
+	payload := strings.Join(parts[:4], "_")
+	if !hmac.Equal([]byte(parts[4]), []byte(g.sign(payload))) {
+		return nil, ErrBadSignature
+	}
+
+	userID, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil || userID <= 0 {
+		return nil, ErrMalformed
+	}
+	expiry, err := strconv.ParseInt(parts[2], 10, 64)
+	if err != nil || expiry < 0 {
+		return nil, ErrMalformed
+	}
+
+	claims := &Claims{UserID: userID, Nonce: parts[3]}
+	if expiry > 0 {
+		expiresAt := time.Unix(expiry, 0)
+		claims.ExpiresAt = &expiresAt
+		if time.Now().After(expiresAt) {
+			return claims, ErrExpired
+		}
+	}
+
+	return claims, nil
+}
+
+func (g *Generator) sign(payload string) string {
+	mac := hmac.New(sha256.New, g.secret)
+	mac.Write([]byte(payload))
+	return hex.EncodeToString(mac.Sum(nil))[:signatureLength]
+}
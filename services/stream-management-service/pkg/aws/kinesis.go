@@ -49,7 +49,7 @@ package aws
 
 import (
 	"fmt"
-	"log"
+	"log/slog"
 	"os"
 
 	"github.com/aws/aws-sdk-go/aws"
@@ -70,7 +70,7 @@ func NewKinesisClient(region, streamName string) *KinesisClient {
 	mockMode := env == "development" || env == ""
 
 	if mockMode {
-		log.Printf("🔧 Kinesis client running in mock mode (development)")
+		slog.Info("🔧 Kinesis client running in mock mode (development)")
 		return &KinesisClient{
 			client:     nil,
 			streamName: streamName,
@@ -93,7 +93,7 @@ func NewKinesisClient(region, streamName string) *KinesisClient {
 func (k *KinesisClient) PutRecord(data string) error {
 	if k.mockMode {
 		// Mock mode - just log the event
-		log.Printf("📡 [MOCK] Kinesis event: %s", data)
+		slog.Debug("📡 [MOCK] Kinesis event", "data", data)
 		return nil
 	}
 
@@ -109,6 +109,27 @@ func (k *KinesisClient) PutRecord(data string) error {
 		return fmt.Errorf("failed to put record to Kinesis: %w", err)
 	}
 
-	log.Printf("✅ Event published to Kinesis: %s", *result.SequenceNumber)
+	slog.Debug("✅ Event published to Kinesis", "sequence_number", *result.SequenceNumber)
+	return nil
+}
+
+// VerifyStream checks that the configured Kinesis stream exists and is active
+func (k *KinesisClient) VerifyStream() error {
+	if k.mockMode {
+		return nil
+	}
+
+	result, err := k.client.DescribeStreamSummary(&kinesis.DescribeStreamSummaryInput{
+		StreamName: aws.String(k.streamName),
+	})
+	if err != nil {
+		return fmt.Errorf("kinesis stream '%s' is missing or not accessible: %w", k.streamName, err)
+	}
+
+	status := aws.StringValue(result.StreamDescriptionSummary.StreamStatus)
+	if status != kinesis.StreamStatusActive && status != kinesis.StreamStatusUpdating {
+		return fmt.Errorf("kinesis stream '%s' is %s, expected ACTIVE", k.streamName, status)
+	}
+
 	return nil
 }
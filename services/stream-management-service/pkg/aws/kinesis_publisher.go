@@ -0,0 +1,332 @@
+// services/stream-management-service/pkg/aws/kinesis_publisher.go
+package aws
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/kinesis"
+
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/shared/go/pkg/events"
+)
+
+// PutRecords limits
+const (
+	maxBatchRecords = 500
+	maxBatchBytes   = 5 << 20
+	maxRecordBytes  = 1 << 20
+
+	publisherPartitionKey = "default"
+	publisherMaxBackoff   = 5 * time.Second
+)
+
+var (
+	// ErrPublisherQueueFull is returned when events arrive faster than Kinesis takes them
+	ErrPublisherQueueFull = errors.New("event queue is full")
+	ErrPublisherClosed    = errors.New("event publisher is closed")
+	ErrRecordTooLarge     = errors.New("record is larger than 1 MiB")
+)
+
+// PublisherConfig sizes a KinesisPublisher's queue and batches
+type PublisherConfig struct {
+	QueueSize     int           // records buffered before new ones are dropped
+	BatchSize     int           // records per PutRecords call, at most 500
+	FlushInterval time.Duration // longest a record waits for its batch to fill
+	MaxRetries    int           // times records Kinesis throttled or failed are sent again
+
+	// Spool takes the records that were dropped or given up on, and everything in mock mode.
+	// Without one they are lost.
+	Spool events.Spool
+}
+
+// KinesisPublisher buffers records in a bounded queue and writes them with PutRecords in the
+// background, so publishing never waits on Kinesis. Records are sent in order, but one that
+// is retried can land after records queued behind it.
+type KinesisPublisher struct {
+	client *KinesisClient
+	config PublisherConfig
+
+	mu     sync.RWMutex // guards closed against sends on the closed queue
+	closed bool
+	queue  chan []byte
+	done   chan struct{}
+
+	published atomic.Int64
+	retried   atomic.Int64
+	failed    atomic.Int64
+	dropped   atomic.Int64
+	spooled   atomic.Int64
+}
+
+func NewKinesisPublisher(client *KinesisClient, cfg PublisherConfig) *KinesisPublisher {
+	if cfg.BatchSize <= 0 || cfg.BatchSize > maxBatchRecords {
+		cfg.BatchSize = maxBatchRecords
+	}
+	if cfg.QueueSize < cfg.BatchSize {
+		cfg.QueueSize = cfg.BatchSize
+	}
+	if cfg.FlushInterval <= 0 {
+		cfg.FlushInterval = time.Second
+	}
+
+	p := &KinesisPublisher{
+		client: client,
+		config: cfg,
+		queue:  make(chan []byte, cfg.QueueSize),
+		done:   make(chan struct{}),
+	}
+	go p.run()
+	return p
+}
+
+// Publish queues a record without waiting for it to be sent
+func (p *KinesisPublisher) Publish(data []byte) error {
+	if len(data)+len(publisherPartitionKey) > maxRecordBytes {
+		return ErrRecordTooLarge
+	}
+
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	if p.closed {
+		return ErrPublisherClosed
+	}
+
+	select {
+	case p.queue <- data:
+		return nil
+	default:
+		if dropped := p.dropped.Add(1); dropped == 1 || dropped%1000 == 0 {
+			slog.Warn("⚠️ Event queue is full, spooling events", "queue_size", p.config.QueueSize, "dropped", dropped)
+		}
+		if p.spool([][]byte{data}) {
+			return nil
+		}
+		return ErrPublisherQueueFull
+	}
+}
+
+// SpoolRecord hands a record straight to the spool, for when Kinesis is known to be down
+func (p *KinesisPublisher) SpoolRecord(data []byte) error {
+	if !p.spool([][]byte{data}) {
+		return fmt.Errorf("failed to spool record")
+	}
+	return nil
+}
+
+// Mock reports whether the publisher is a development stand-in that sends nothing
+func (p *KinesisPublisher) Mock() bool {
+	return p.client.mockMode
+}
+
+// Deliver sends records right away with a single PutRecords call, bypassing the queue. It
+// returns the indexes of the records Kinesis didn't take.
+func (p *KinesisPublisher) Deliver(records [][]byte) ([]int, error) {
+	if p.client.mockMode {
+		return nil, fmt.Errorf("kinesis client is in mock mode")
+	}
+
+	entries := make([]*kinesis.PutRecordsRequestEntry, len(records))
+	for i, data := range records {
+		entries[i] = &kinesis.PutRecordsRequestEntry{
+			Data:         data,
+			PartitionKey: aws.String(publisherPartitionKey),
+		}
+	}
+
+	failed, err := p.putRecords(entries)
+	p.published.Add(int64(len(records) - len(failed)))
+	return failed, err
+}
+
+// Verify checks that the Kinesis stream events are published to exists
+func (p *KinesisPublisher) Verify() error {
+	return p.client.VerifyStream()
+}
+
+// Stats returns the publisher's counters
+func (p *KinesisPublisher) Stats() events.PublisherStats {
+	return events.PublisherStats{
+		Backend:   "kinesis",
+		Queued:    len(p.queue),
+		Published: p.published.Load(),
+		Retried:   p.retried.Load(),
+		Failed:    p.failed.Load(),
+		Dropped:   p.dropped.Load(),
+		Spooled:   p.spooled.Load(),
+	}
+}
+
+// Close stops taking records and waits until the queued ones are sent, or ctx is done
+func (p *KinesisPublisher) Close(ctx context.Context) error {
+	p.mu.Lock()
+	if !p.closed {
+		p.closed = true
+		close(p.queue)
+	}
+	p.mu.Unlock()
+
+	select {
+	case <-p.done:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("failed to flush %d queued events: %w", len(p.queue), ctx.Err())
+	}
+}
+
+// run collects records into batches, sending a batch once it is full or FlushInterval after
+// its first record
+func (p *KinesisPublisher) run() {
+	defer close(p.done)
+
+	var batch []*kinesis.PutRecordsRequestEntry
+	batchBytes := 0
+	timer := time.NewTimer(p.config.FlushInterval)
+	timer.Stop()
+
+	flush := func() {
+		timer.Stop()
+		if len(batch) > 0 {
+			p.send(batch)
+		}
+		batch, batchBytes = nil, 0
+	}
+
+	for {
+		select {
+		case data, ok := <-p.queue:
+			if !ok {
+				flush()
+				return
+			}
+			size := len(data) + len(publisherPartitionKey)
+			if batchBytes+size > maxBatchBytes {
+				flush()
+			}
+			if len(batch) == 0 {
+				timer.Reset(p.config.FlushInterval)
+			}
+			batch = append(batch, &kinesis.PutRecordsRequestEntry{
+				Data:         data,
+				PartitionKey: aws.String(publisherPartitionKey),
+			})
+			batchBytes += size
+			if len(batch) >= p.config.BatchSize {
+				flush()
+			}
+		case <-timer.C:
+			flush()
+		}
+	}
+}
+
+// send writes a batch, retrying what Kinesis throttled or failed with exponential backoff
+func (p *KinesisPublisher) send(records []*kinesis.PutRecordsRequestEntry) {
+	if p.client.mockMode {
+		slog.Debug("📡 [MOCK] Kinesis batch", "records", len(records))
+		p.spool(recordData(records))
+		return
+	}
+
+	backoff := 100 * time.Millisecond
+	for attempt := 0; ; attempt++ {
+		failed, err := p.putRecords(records)
+		p.published.Add(int64(len(records) - len(failed)))
+		if len(failed) == 0 {
+			return
+		}
+
+		pending := make([]*kinesis.PutRecordsRequestEntry, len(failed))
+		for i, index := range failed {
+			pending[i] = records[index]
+		}
+
+		if attempt >= p.config.MaxRetries || !retryable(err) {
+			slog.Error("❌ Could not publish events to Kinesis", "records", len(pending), "attempts", attempt+1, "error", err)
+			p.spool(recordData(pending))
+			return
+		}
+
+		p.retried.Add(int64(len(pending)))
+		slog.Warn("⚠️ Retrying events Kinesis did not take", "records", len(pending), "attempt", attempt+1, "error", err)
+		time.Sleep(backoff)
+		backoff = min(backoff*2, publisherMaxBackoff)
+		records = pending
+	}
+}
+
+// putRecords sends one PutRecords call and returns the indexes of the records that have to
+// be sent again
+func (p *KinesisPublisher) putRecords(records []*kinesis.PutRecordsRequestEntry) ([]int, error) {
+	result, err := p.client.client.PutRecords(&kinesis.PutRecordsInput{
+		Records:    records,
+		StreamName: aws.String(p.client.streamName),
+	})
+	if err != nil {
+		failed := make([]int, len(records))
+		for i := range failed {
+			failed[i] = i
+		}
+		return failed, fmt.Errorf("failed to put records to Kinesis: %w", err)
+	}
+	if aws.Int64Value(result.FailedRecordCount) == 0 {
+		return nil, nil
+	}
+
+	// Results line up with the records sent, failed ones carry an error code
+	var failed []int
+	var lastErr error
+	for i, entry := range result.Records {
+		if entry.ErrorCode == nil {
+			continue
+		}
+		failed = append(failed, i)
+		lastErr = awserr.New(aws.StringValue(entry.ErrorCode), aws.StringValue(entry.ErrorMessage), nil)
+	}
+	return failed, lastErr
+}
+
+// spool hands records to the spool, counting them as failed when there is none or it
+// can't take them
+func (p *KinesisPublisher) spool(records [][]byte) bool {
+	if p.config.Spool != nil {
+		err := p.config.Spool.Spool(records)
+		if err == nil {
+			p.spooled.Add(int64(len(records)))
+			return true
+		}
+		slog.Error("❌ Could not spool events, they are lost", "records", len(records), "error", err)
+	}
+	p.failed.Add(int64(len(records)))
+	return false
+}
+
+func recordData(records []*kinesis.PutRecordsRequestEntry) [][]byte {
+	data := make([][]byte, len(records))
+	for i, record := range records {
+		data[i] = record.Data
+	}
+	return data
+}
+
+// retryable reports whether records that failed with err may go through when sent again,
+// which is the case for throttling and Kinesis' own failures but not e.g. a missing stream
+func retryable(err error) bool {
+	var aerr awserr.Error
+	if !errors.As(err, &aerr) {
+		return false
+	}
+	switch aerr.Code() {
+	case kinesis.ErrCodeProvisionedThroughputExceededException, kinesis.ErrCodeLimitExceededException,
+		kinesis.ErrCodeKMSThrottlingException, "InternalFailure":
+		return true
+	}
+	return request.IsErrorThrottle(aerr) || request.IsErrorRetryable(aerr)
+}
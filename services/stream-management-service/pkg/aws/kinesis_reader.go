@@ -0,0 +1,125 @@
+// services/stream-management-service/pkg/aws/kinesis_reader.go
+package aws
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/kinesis"
+)
+
+const (
+	readerPollInterval = time.Second
+	readerRetries      = 3
+)
+
+// KinesisReader tails every shard of a stream from the latest record
+type KinesisReader struct {
+	client     *kinesis.Kinesis
+	streamName string
+	mockMode   bool
+}
+
+func NewKinesisReader(region, streamName string) *KinesisReader {
+	env := os.Getenv("ENVIRONMENT")
+	if env == "development" || env == "" {
+		return &KinesisReader{streamName: streamName, mockMode: true}
+	}
+
+	sess := session.Must(session.NewSession(&aws.Config{
+		Region: aws.String(region),
+	}))
+
+	return &KinesisReader{
+		client:     kinesis.New(sess),
+		streamName: streamName,
+	}
+}
+
+// Mock reports whether the reader is a development stand-in that never reads anything
+func (r *KinesisReader) Mock() bool {
+	return r.mockMode
+}
+
+// Run reads records until ctx is cancelled. A record whose handler keeps failing is
+// logged and skipped so one bad record can't stall its shard.
+func (r *KinesisReader) Run(ctx context.Context, handle func([]byte) error) error {
+	if r.mockMode {
+		slog.InfoContext(ctx, "🔧 [MOCK] Not reading Kinesis stream in development", "kinesis_stream", r.streamName)
+		return nil
+	}
+
+	shards, err := r.client.ListShardsWithContext(ctx, &kinesis.ListShardsInput{
+		StreamName: aws.String(r.streamName),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to list shards of %s: %w", r.streamName, err)
+	}
+
+	var wg sync.WaitGroup
+	for _, shard := range shards.Shards {
+		wg.Add(1)
+		go func(shardID string) {
+			defer wg.Done()
+			if err := r.readShard(ctx, shardID, handle); err != nil && ctx.Err() == nil {
+				slog.ErrorContext(ctx, "❌ Stopped reading shard", "shard_id", shardID, "kinesis_stream", r.streamName, "error", err)
+			}
+		}(aws.StringValue(shard.ShardId))
+	}
+
+	wg.Wait()
+	return nil
+}
+
+func (r *KinesisReader) readShard(ctx context.Context, shardID string, handle func([]byte) error) error {
+	iterator, err := r.client.GetShardIteratorWithContext(ctx, &kinesis.GetShardIteratorInput{
+		StreamName:        aws.String(r.streamName),
+		ShardId:           aws.String(shardID),
+		ShardIteratorType: aws.String(kinesis.ShardIteratorTypeLatest),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to get shard iterator: %w", err)
+	}
+
+	next := iterator.ShardIterator
+	for next != nil {
+		result, err := r.client.GetRecordsWithContext(ctx, &kinesis.GetRecordsInput{
+			ShardIterator: next,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to get records: %w", err)
+		}
+
+		for _, record := range result.Records {
+			r.handleRecord(record, handle)
+		}
+		next = result.NextShardIterator
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(readerPollInterval):
+		}
+	}
+
+	// The shard was closed by a reshard, its children are picked up on the next start
+	return nil
+}
+
+func (r *KinesisReader) handleRecord(record *kinesis.Record, handle func([]byte) error) {
+	var err error
+	for attempt := 1; attempt <= readerRetries; attempt++ {
+		if err = handle(record.Data); err == nil {
+			return
+		}
+		time.Sleep(time.Duration(attempt) * 200 * time.Millisecond)
+	}
+	slog.Warn("⚠️ Skipping record after repeated failures",
+		"sequence_number", aws.StringValue(record.SequenceNumber), "kinesis_stream", r.streamName, "attempts", readerRetries, "error", err)
+}
@@ -50,14 +50,16 @@
 package aws
 
 import (
+	"bytes"
 	"fmt"
-	"log"
+	"log/slog"
 	"os"
 	"path/filepath"
+	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/session"
-	_ "github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3"
 	"github.com/aws/aws-sdk-go/service/s3/s3manager"
 )
 
@@ -73,7 +75,7 @@ func NewS3Client(region, bucketName string) *S3Client {
 	mockMode := env == "development" || env == ""
 
 	if mockMode {
-		log.Printf("🔧 S3 client running in mock mode (development)")
+		slog.Info("🔧 S3 client running in mock mode (development)")
 		return &S3Client{
 			uploader:   nil,
 			bucketName: bucketName,
@@ -94,11 +96,16 @@ func NewS3Client(region, bucketName string) *S3Client {
 }
 
 func (s *S3Client) UploadRecording(filePath, key string) (string, error) {
+	return s.UploadFile(filePath, key, "")
+}
+
+// UploadFile uploads a file with the given content type, S3 guesses it when empty
+func (s *S3Client) UploadFile(filePath, key, contentType string) (string, error) {
 	if s.mockMode {
 		// Mock mode - return a local file URL
 		absPath, _ := filepath.Abs(filePath)
 		mockURL := fmt.Sprintf("file://%s", absPath)
-		log.Printf("📁 [MOCK] S3 upload: %s -> %s", filePath, mockURL)
+		slog.Debug("📁 [MOCK] S3 upload", "file", filePath, "url", mockURL)
 		return mockURL, nil
 	}
 
@@ -109,14 +116,120 @@ func (s *S3Client) UploadRecording(filePath, key string) (string, error) {
 	}
 	defer file.Close()
 
-	result, err := s.uploader.Upload(&s3manager.UploadInput{
+	input := &s3manager.UploadInput{
 		Bucket: aws.String(s.bucketName),
 		Key:    aws.String(key),
 		Body:   file,
-	})
+	}
+	if contentType != "" {
+		input.ContentType = aws.String(contentType)
+	}
+
+	result, err := s.uploader.Upload(input)
 	if err != nil {
 		return "", fmt.Errorf("failed to upload to S3: %w", err)
 	}
 
 	return result.Location, nil
 }
+
+// VerifyBucket checks that the configured S3 bucket exists and is reachable
+func (s *S3Client) VerifyBucket() error {
+	if s.mockMode {
+		return nil
+	}
+
+	_, err := s.uploader.S3.HeadBucket(&s3.HeadBucketInput{
+		Bucket: aws.String(s.bucketName),
+	})
+	if err != nil {
+		return fmt.Errorf("s3 bucket '%s' is missing or not accessible: %w", s.bucketName, err)
+	}
+
+	return nil
+}
+
+// PutObject uploads data held in memory
+func (s *S3Client) PutObject(key string, body []byte, contentType string) error {
+	if s.mockMode {
+		slog.Debug("📁 [MOCK] S3 put", "key", key, "bytes", len(body))
+		return nil
+	}
+
+	_, err := s.uploader.Upload(&s3manager.UploadInput{
+		Bucket:      aws.String(s.bucketName),
+		Key:         aws.String(key),
+		Body:        bytes.NewReader(body),
+		ContentType: aws.String(contentType),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to upload to S3: %w", err)
+	}
+
+	return nil
+}
+
+// PresignGetURL returns a URL anyone can download an object with until ttl passed
+func (s *S3Client) PresignGetURL(key string, ttl time.Duration) (string, error) {
+	if s.mockMode {
+		return fmt.Sprintf("mock://%s/%s", s.bucketName, key), nil
+	}
+
+	req, _ := s.uploader.S3.GetObjectRequest(&s3.GetObjectInput{
+		Bucket: aws.String(s.bucketName),
+		Key:    aws.String(key),
+	})
+	url, err := req.Presign(ttl)
+	if err != nil {
+		return "", fmt.Errorf("failed to presign S3 URL: %w", err)
+	}
+
+	return url, nil
+}
+
+// DeletePrefix deletes every object whose key starts with prefix, returning how many
+func (s *S3Client) DeletePrefix(prefix string) (int, error) {
+	if s.mockMode {
+		slog.Debug("📁 [MOCK] S3 delete", "prefix", prefix)
+		return 0, nil
+	}
+
+	deleted := 0
+	var deleteErr error
+	err := s.uploader.S3.ListObjectsV2Pages(&s3.ListObjectsV2Input{
+		Bucket: aws.String(s.bucketName),
+		Prefix: aws.String(prefix),
+	}, func(page *s3.ListObjectsV2Output, lastPage bool) bool {
+		if len(page.Contents) == 0 {
+			return true
+		}
+
+		// A page holds at most 1000 keys, as many as DeleteObjects takes
+		objects := make([]*s3.ObjectIdentifier, 0, len(page.Contents))
+		for _, object := range page.Contents {
+			objects = append(objects, &s3.ObjectIdentifier{Key: object.Key})
+		}
+		result, err := s.uploader.S3.DeleteObjects(&s3.DeleteObjectsInput{
+			Bucket: aws.String(s.bucketName),
+			Delete: &s3.Delete{Objects: objects, Quiet: aws.Bool(true)},
+		})
+		if err != nil {
+			deleteErr = err
+			return false
+		}
+		if len(result.Errors) > 0 {
+			deleteErr = fmt.Errorf("%s: %s", aws.StringValue(result.Errors[0].Key), aws.StringValue(result.Errors[0].Message))
+			return false
+		}
+		deleted += len(objects)
+		return true
+	})
+	if err == nil {
+		err = deleteErr
+	}
+	if err != nil {
+		return deleted, fmt.Errorf("failed to delete S3 objects under %s: %w", prefix, err)
+	}
+
+	return deleted, nil
+}
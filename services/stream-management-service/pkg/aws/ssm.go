@@ -0,0 +1,57 @@
+// services/stream-management-service/pkg/aws/ssm.go
+package aws
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/ssm"
+)
+
+// SSMParameters reads the parameters under a Parameter Store path, keyed by the last segment
+// of their names, e.g. /stream-management/RATE_LIMITS is RATE_LIMITS
+type SSMParameters struct {
+	client *ssm.SSM
+	path   string
+}
+
+func NewSSMParameters(region, path string) (*SSMParameters, error) {
+	sess, err := session.NewSession(&aws.Config{
+		Region: aws.String(region),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create AWS session: %w", err)
+	}
+
+	return &SSMParameters{
+		client: ssm.New(sess),
+		path:   "/" + strings.Trim(path, "/"),
+	}, nil
+}
+
+func (p *SSMParameters) Name() string {
+	return "ssm:" + p.path
+}
+
+// Load returns the parameters under the path, SecureStrings decrypted
+func (p *SSMParameters) Load(ctx context.Context) (map[string]string, error) {
+	values := make(map[string]string)
+	err := p.client.GetParametersByPathPagesWithContext(ctx, &ssm.GetParametersByPathInput{
+		Path:           aws.String(p.path),
+		WithDecryption: aws.Bool(true),
+	}, func(page *ssm.GetParametersByPathOutput, lastPage bool) bool {
+		for _, parameter := range page.Parameters {
+			name := aws.StringValue(parameter.Name)
+			values[name[strings.LastIndex(name, "/")+1:]] = aws.StringValue(parameter.Value)
+		}
+		return true
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get parameters under %s: %w", p.path, err)
+	}
+
+	return values, nil
+}
@@ -0,0 +1,48 @@
+// services/stream-management-service/pkg/callpolicy/grpc.go
+package callpolicy
+
+import (
+	"context"
+	"sync"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// UnaryClientInterceptor applies the caller's policy to unary calls. Calls are retried when
+// the server couldn't be reached, the attempt timed out or the server aborted it.
+func (c *Caller) UnaryClientInterceptor() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		message, ok := reply.(proto.Message)
+		if !ok {
+			return c.Do(ctx, func(ctx context.Context) error {
+				return invoker(ctx, method, req, reply, cc, opts...)
+			}, retryableGRPC)
+		}
+
+		// Hedged attempts run at the same time, each decodes into its own reply and the
+		// winner's is copied into the caller's
+		var once sync.Once
+		return c.Do(ctx, func(ctx context.Context) error {
+			attemptReply := proto.Clone(message)
+			if err := invoker(ctx, method, req, attemptReply, cc, opts...); err != nil {
+				return err
+			}
+			once.Do(func() {
+				proto.Reset(message)
+				proto.Merge(message, attemptReply)
+			})
+			return nil
+		}, retryableGRPC)
+	}
+}
+
+func retryableGRPC(err error) bool {
+	switch status.Code(err) {
+	case codes.Unavailable, codes.DeadlineExceeded, codes.Aborted:
+		return true
+	}
+	return false
+}
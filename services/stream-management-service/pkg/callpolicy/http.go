@@ -0,0 +1,117 @@
+// services/stream-management-service/pkg/callpolicy/http.go
+package callpolicy
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+)
+
+// statusError is a response whose status is worth retrying
+type statusError struct {
+	code int
+}
+
+func (e *statusError) Error() string {
+	return fmt.Sprintf("status %d", e.code)
+}
+
+// Transport wraps base, http.DefaultTransport when nil, in the caller's policy. Requests are
+// retried on connection errors, timed out attempts and 502, 503 and 504 responses, so only
+// give a dependency retries when its requests are safe to send twice. Request bodies are
+// buffered to be sent again.
+//
+// The response body is read within the attempt, so the timeout covers it. That suits the
+// small JSON APIs the service calls, not streaming responses.
+func (c *Caller) Transport(base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &transport{caller: c, base: base}
+}
+
+type transport struct {
+	caller *Caller
+	base   http.RoundTripper
+}
+
+func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	// Every attempt sends a fresh copy of the body
+	getBody := req.GetBody
+	if req.Body != nil && req.Body != http.NoBody {
+		if getBody == nil {
+			body, err := io.ReadAll(req.Body)
+			if err != nil {
+				req.Body.Close()
+				return nil, err
+			}
+			getBody = func() (io.ReadCloser, error) {
+				return io.NopCloser(bytes.NewReader(body)), nil
+			}
+		}
+		req.Body.Close()
+	}
+
+	var (
+		mu     sync.Mutex
+		winner *http.Response
+		last   *http.Response // the last retryable response, returned when every attempt failed
+	)
+	err := t.caller.Do(req.Context(), func(ctx context.Context) error {
+		attemptReq := req.Clone(ctx)
+		if getBody != nil {
+			body, err := getBody()
+			if err != nil {
+				return err
+			}
+			attemptReq.Body = body
+		}
+
+		resp, err := t.base.RoundTrip(attemptReq)
+		if err != nil {
+			return err
+		}
+		body, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return err
+		}
+		resp.Body = io.NopCloser(bytes.NewReader(body))
+
+		mu.Lock()
+		defer mu.Unlock()
+		if retryableStatus(resp.StatusCode) {
+			last = resp
+			return &statusError{code: resp.StatusCode}
+		}
+		if winner == nil {
+			winner = resp
+		}
+		return nil
+	}, retryableHTTP)
+
+	mu.Lock()
+	defer mu.Unlock()
+	var status *statusError
+	switch {
+	case err == nil:
+		return winner, nil
+	case errors.As(err, &status):
+		return last, nil
+	}
+	return nil, err
+}
+
+func retryableStatus(code int) bool {
+	return code == http.StatusBadGateway || code == http.StatusServiceUnavailable || code == http.StatusGatewayTimeout
+}
+
+// retryableHTTP retries every failed attempt, Do gives up on its own once the caller's
+// context is done
+func retryableHTTP(error) bool {
+	return true
+}
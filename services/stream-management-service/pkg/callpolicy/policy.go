@@ -0,0 +1,230 @@
+// services/stream-management-service/pkg/callpolicy/policy.go
+package callpolicy
+
+import (
+	"context"
+	"math/rand"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// maxBudget caps the retries a quiet dependency saves up, so a burst of failures after a
+// calm stretch still can't multiply the load on it
+const maxBudget = 10
+
+// Policy is how calls to one dependency are bounded and retried
+type Policy struct {
+	Timeout    time.Duration // of each attempt, 0 leaves it to the caller's context
+	Retries    int           // attempts after the first for failures worth retrying
+	Backoff    time.Duration // before the first retry, doubled for each one after it
+	MaxBackoff time.Duration
+
+	// HedgeAfter sends another attempt when the one in flight hasn't answered by then, the
+	// first answer wins. Hedges count as retries. 0 never hedges.
+	HedgeAfter time.Duration
+
+	// RetryBudget is the share of calls that may be retried, e.g. 0.1 for one retry per ten
+	// calls, so retries stop adding load once a dependency is failing for everyone
+	RetryBudget float64
+}
+
+// Source returns the policy in effect. It is read on every call, so a reloaded policy
+// applies to the next one.
+type Source func() Policy
+
+// Stats counts what a Caller did
+type Stats struct {
+	Dependency string `json:"dependency"`
+	Calls      int64  `json:"calls"`
+	Failures   int64  `json:"failures"` // calls that failed after every attempt
+	Retries    int64  `json:"retries"`
+	Hedges     int64  `json:"hedges"`
+	Throttled  int64  `json:"throttled"` // retries and hedges skipped because the budget was spent
+}
+
+// Caller applies a dependency's policy to the calls made to it. Every client of the
+// dependency should share one, so they share its retry budget.
+type Caller struct {
+	dependency string
+	policy     Source
+
+	mu     sync.Mutex
+	budget float64 // retries that may be made right now
+
+	calls     atomic.Int64
+	failures  atomic.Int64
+	retries   atomic.Int64
+	hedges    atomic.Int64
+	throttled atomic.Int64
+}
+
+func New(dependency string, policy Source) *Caller {
+	return &Caller{
+		dependency: dependency,
+		policy:     policy,
+		budget:     maxBudget,
+	}
+}
+
+// Dependency returns the name of the dependency the caller calls
+func (c *Caller) Dependency() string {
+	return c.dependency
+}
+
+// Policy returns the policy in effect
+func (c *Caller) Policy() Policy {
+	return c.policy()
+}
+
+func (c *Caller) Stats() Stats {
+	return Stats{
+		Dependency: c.dependency,
+		Calls:      c.calls.Load(),
+		Failures:   c.failures.Load(),
+		Retries:    c.retries.Load(),
+		Hedges:     c.hedges.Load(),
+		Throttled:  c.throttled.Load(),
+	}
+}
+
+// Do calls attempt under the policy. Each attempt gets its own timeout, failures retryable
+// says are worth it are tried again after a backoff, and a hedged attempt is sent when one
+// is slow to answer. The first attempt that succeeds wins and the others are cancelled.
+// Do returns the last error when every attempt failed.
+func (c *Caller) Do(ctx context.Context, attempt func(ctx context.Context) error, retryable func(error) bool) error {
+	policy := c.policy()
+	c.calls.Add(1)
+	c.deposit(policy.RetryBudget)
+
+	callCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	results := make(chan error, policy.Retries+1)
+	attempts, inFlight := 0, 0
+	start := func() {
+		attempts++
+		inFlight++
+		go func() {
+			attemptCtx, cancelAttempt := callCtx, context.CancelFunc(func() {})
+			if policy.Timeout > 0 {
+				attemptCtx, cancelAttempt = context.WithTimeout(callCtx, policy.Timeout)
+			}
+			err := attempt(attemptCtx)
+			cancelAttempt()
+			results <- err
+		}()
+	}
+
+	backoff := policy.Backoff
+	var hedge, retry *time.Timer
+	defer func() {
+		stopTimer(hedge)
+		stopTimer(retry)
+	}()
+	armHedge := func() {
+		if policy.HedgeAfter > 0 && attempts <= policy.Retries {
+			hedge = time.NewTimer(policy.HedgeAfter)
+		}
+	}
+
+	start()
+	armHedge()
+
+	var lastErr error
+	for {
+		select {
+		case err := <-results:
+			inFlight--
+			if err == nil {
+				return nil
+			}
+			lastErr = err
+			if ctx.Err() != nil || !retryable(err) {
+				return c.fail(err)
+			}
+			if inFlight > 0 || retry != nil {
+				// A hedged attempt may still answer
+				continue
+			}
+			if attempts > policy.Retries || !c.withdraw() {
+				return c.fail(err)
+			}
+			stopTimer(hedge)
+			hedge = nil
+			retry = time.NewTimer(jitter(backoff))
+			if backoff *= 2; policy.MaxBackoff > 0 && backoff > policy.MaxBackoff {
+				backoff = policy.MaxBackoff
+			}
+
+		case <-timerC(retry):
+			retry = nil
+			c.retries.Add(1)
+			start()
+			armHedge()
+
+		case <-timerC(hedge):
+			hedge = nil
+			if attempts <= policy.Retries && c.withdraw() {
+				c.hedges.Add(1)
+				start()
+				armHedge()
+			}
+
+		case <-ctx.Done():
+			if lastErr == nil {
+				lastErr = ctx.Err()
+			}
+			return c.fail(lastErr)
+		}
+	}
+}
+
+func (c *Caller) fail(err error) error {
+	c.failures.Add(1)
+	return err
+}
+
+// deposit adds a call's share of retries to the budget
+func (c *Caller) deposit(share float64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.budget += share; c.budget > maxBudget {
+		c.budget = maxBudget
+	}
+}
+
+// withdraw takes a retry from the budget, false when it is spent
+func (c *Caller) withdraw() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.budget < 1 {
+		c.throttled.Add(1)
+		return false
+	}
+	c.budget--
+	return true
+}
+
+// jitter spreads a backoff over its second half, so clients that failed together don't
+// retry together
+func jitter(d time.Duration) time.Duration {
+	if d <= 0 {
+		return 0
+	}
+	return d/2 + time.Duration(rand.Int63n(int64(d/2)+1))
+}
+
+// timerC returns a timer's channel, nil for no timer so a select never picks it
+func timerC(t *time.Timer) <-chan time.Time {
+	if t == nil {
+		return nil
+	}
+	return t.C
+}
+
+func stopTimer(t *time.Timer) {
+	if t != nil {
+		t.Stop()
+	}
+}
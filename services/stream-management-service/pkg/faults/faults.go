@@ -0,0 +1,189 @@
+// services/stream-management-service/pkg/faults/faults.go
+package faults
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/rand"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Targets faults can be injected into
+const (
+	TargetDynamoDB = "dynamodb"
+	TargetRedis    = "redis"
+	TargetGRPC     = "grpc" // calls to the user service
+)
+
+var Targets = []string{TargetDynamoDB, TargetRedis, TargetGRPC}
+
+// MaxLatency bounds the latency a rule adds, so a typo can't hang every request
+const MaxLatency = 30 * time.Second
+
+// ErrInjected is what calls failed by a fault return, wrapped in the error the client would
+// get from its dependency
+var ErrInjected = errors.New("injected fault")
+
+// Rule slows down and fails calls to a target, to see how the service copes
+type Rule struct {
+	Target    string        `json:"target"`
+	Operation string        `json:"operation,omitempty"` // DynamoDB operation, Redis command or gRPC method, every call when empty
+	Latency   time.Duration `json:"-"`                   // added before the call, "latency" in JSON, e.g. "250ms"
+	ErrorRate float64       `json:"error_rate"`          // share of calls failed after the latency, 0 to 1
+}
+
+func (r Rule) MarshalJSON() ([]byte, error) {
+	type plain Rule
+	latency := ""
+	if r.Latency > 0 {
+		latency = r.Latency.String()
+	}
+	return json.Marshal(struct {
+		plain
+		Latency string `json:"latency,omitempty"`
+	}{plain(r), latency})
+}
+
+func (r *Rule) UnmarshalJSON(data []byte) error {
+	type plain Rule
+	var decoded struct {
+		plain
+		Latency string `json:"latency"`
+	}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return err
+	}
+	*r = Rule(decoded.plain)
+	if decoded.Latency != "" {
+		latency, err := time.ParseDuration(decoded.Latency)
+		if err != nil {
+			return fmt.Errorf("latency: %w", err)
+		}
+		r.Latency = latency
+	}
+	return nil
+}
+
+func (r Rule) Validate() error {
+	if !slices.Contains(Targets, r.Target) {
+		return fmt.Errorf("unknown target %q, expected one of %s", r.Target, strings.Join(Targets, ", "))
+	}
+	if r.Latency < 0 || r.Latency > MaxLatency {
+		return fmt.Errorf("latency must be between 0 and %s", MaxLatency)
+	}
+	if r.ErrorRate < 0 || r.ErrorRate > 1 {
+		return errors.New("error_rate must be between 0 and 1")
+	}
+	return nil
+}
+
+func (r Rule) matches(target, operation string) bool {
+	return r.Target == target && (r.Operation == "" || strings.EqualFold(r.Operation, operation))
+}
+
+// Stats counts the calls to a target faults were injected into
+type Stats struct {
+	Target  string `json:"target"`
+	Delayed int64  `json:"delayed"`
+	Failed  int64  `json:"failed"`
+}
+
+type counters struct {
+	delayed atomic.Int64
+	failed  atomic.Int64
+}
+
+// Injector injects the faults of its rules into the calls its hooks see. The rules come from
+// the config, read on each call, and from admins, who can change them while the service runs.
+// A nil Injector injects nothing.
+type Injector struct {
+	configured func() []Rule
+
+	mu    sync.RWMutex
+	rules []Rule // set by admins
+
+	counters map[string]*counters
+}
+
+func New(configured func() []Rule) *Injector {
+	i := &Injector{configured: configured, counters: make(map[string]*counters)}
+	for _, target := range Targets {
+		i.counters[target] = &counters{}
+	}
+	return i
+}
+
+// Rules returns the rules from the config and the ones admins set
+func (i *Injector) Rules() (configured, set []Rule) {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+	return i.configured(), slices.Clone(i.rules)
+}
+
+// SetRules replaces the rules admins set, the ones from the config stay in effect
+func (i *Injector) SetRules(rules []Rule) error {
+	for n, rule := range rules {
+		if err := rule.Validate(); err != nil {
+			return fmt.Errorf("rule %d: %w", n, err)
+		}
+	}
+
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	i.rules = slices.Clone(rules)
+	return nil
+}
+
+func (i *Injector) Stats() []Stats {
+	stats := make([]Stats, 0, len(Targets))
+	for _, target := range Targets {
+		c := i.counters[target]
+		stats = append(stats, Stats{Target: target, Delayed: c.delayed.Load(), Failed: c.failed.Load()})
+	}
+	return stats
+}
+
+// Inject applies the rules matching a call, returning an error wrapping ErrInjected when the
+// call should fail or ctx's error when it ends while the call is held back
+func (i *Injector) Inject(ctx context.Context, target, operation string) error {
+	if i == nil {
+		return nil
+	}
+
+	i.mu.RLock()
+	rules := slices.Concat(i.configured(), i.rules)
+	i.mu.RUnlock()
+
+	var latency time.Duration
+	failed := false
+	for _, rule := range rules {
+		if !rule.matches(target, operation) {
+			continue
+		}
+		latency += rule.Latency
+		failed = failed || rand.Float64() < rule.ErrorRate
+	}
+	latency = min(latency, MaxLatency)
+
+	if latency > 0 {
+		i.counters[target].delayed.Add(1)
+		timer := time.NewTimer(latency)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		}
+	}
+	if failed {
+		i.counters[target].failed.Add(1)
+		return fmt.Errorf("%w in %s %s", ErrInjected, target, operation)
+	}
+	return nil
+}
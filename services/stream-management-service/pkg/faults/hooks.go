@@ -0,0 +1,72 @@
+// services/stream-management-service/pkg/faults/hooks.go
+package faults
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"path"
+
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/go-redis/redis/v8"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// InstrumentAWS injects the DynamoDB faults into requests before they are sent. A failed
+// request gets a 503, which the SDK doesn't retry since it never reached the network.
+func (i *Injector) InstrumentAWS(handlers *request.Handlers) {
+	handlers.Validate.PushBackNamed(request.NamedHandler{
+		Name: "faults.Inject",
+		Fn: func(r *request.Request) {
+			if r.Error != nil {
+				return
+			}
+			if err := i.Inject(r.Context(), TargetDynamoDB, r.Operation.Name); err != nil {
+				r.Error = awserr.NewRequestFailure(awserr.New("ServiceUnavailable", err.Error(), err), http.StatusServiceUnavailable, "")
+			}
+		},
+	})
+}
+
+// RedisHook injects the Redis faults into commands, and into pipelines as the "pipeline"
+// operation, before they are sent
+func (i *Injector) RedisHook() redis.Hook {
+	return redisHook{injector: i}
+}
+
+type redisHook struct {
+	injector *Injector
+}
+
+func (h redisHook) BeforeProcess(ctx context.Context, cmd redis.Cmder) (context.Context, error) {
+	return ctx, h.injector.Inject(ctx, TargetRedis, cmd.Name())
+}
+
+func (h redisHook) AfterProcess(context.Context, redis.Cmder) error {
+	return nil
+}
+
+func (h redisHook) BeforeProcessPipeline(ctx context.Context, _ []redis.Cmder) (context.Context, error) {
+	return ctx, h.injector.Inject(ctx, TargetRedis, "pipeline")
+}
+
+func (h redisHook) AfterProcessPipeline(context.Context, []redis.Cmder) error {
+	return nil
+}
+
+// UnaryClientInterceptor injects the gRPC faults into calls, matched by method name without
+// the service, e.g. GetUser. Failed calls end with Unavailable.
+func (i *Injector) UnaryClientInterceptor() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		if err := i.Inject(ctx, TargetGRPC, path.Base(method)); err != nil {
+			if errors.Is(err, ErrInjected) {
+				return status.Error(codes.Unavailable, err.Error())
+			}
+			return status.FromContextError(err).Err()
+		}
+		return invoker(ctx, method, req, reply, cc, opts...)
+	}
+}
@@ -0,0 +1,357 @@
+// services/stream-management-service/pkg/kafka/publisher.go
+package kafka
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/shared/go/pkg/events"
+)
+
+const (
+	maxBatchRecords = 500
+	maxRecordBytes  = 1 << 20 // the broker's default message.max.bytes
+
+	writeTimeout      = 30 * time.Second // of one batch, across its partitions
+	publishMaxBackoff = 5 * time.Second
+)
+
+var (
+	// ErrQueueFull is returned when events arrive faster than the brokers take them
+	ErrQueueFull      = errors.New("event queue is full")
+	ErrClosed         = errors.New("event publisher is closed")
+	ErrRecordTooLarge = errors.New("record is larger than 1 MiB")
+)
+
+// Config routes and sizes a Publisher's batches
+type Config struct {
+	Brokers      []string
+	ClientID     string
+	DefaultTopic string            // topic of the event types Topics doesn't route
+	Topics       map[string]string // topic by event type
+
+	QueueSize     int           // records buffered before new ones are dropped
+	BatchSize     int           // records per write, at most 500
+	FlushInterval time.Duration // longest a record waits for its batch to fill
+	MaxRetries    int           // times records the brokers failed are sent again
+
+	// Spool takes the records that were dropped or given up on. Without one they are lost.
+	Spool events.Spool
+}
+
+// Publisher buffers event records in a bounded queue and writes them to Kafka in batches in
+// the background, like aws.KinesisPublisher does to Kinesis. Each event type goes to its
+// topic, keyed by the stream the event is about so a stream's events stay on one partition
+// and in order.
+type Publisher struct {
+	writer *kafka.Writer
+	client *kafka.Client
+	config Config
+
+	mu     sync.RWMutex // guards closed against sends on the closed queue
+	closed bool
+	queue  chan []byte
+	done   chan struct{}
+
+	published atomic.Int64
+	retried   atomic.Int64
+	failed    atomic.Int64
+	dropped   atomic.Int64
+	spooled   atomic.Int64
+}
+
+func NewPublisher(cfg Config) *Publisher {
+	if cfg.BatchSize <= 0 || cfg.BatchSize > maxBatchRecords {
+		cfg.BatchSize = maxBatchRecords
+	}
+	if cfg.QueueSize < cfg.BatchSize {
+		cfg.QueueSize = cfg.BatchSize
+	}
+	if cfg.FlushInterval <= 0 {
+		cfg.FlushInterval = time.Second
+	}
+
+	transport := &kafka.Transport{ClientID: cfg.ClientID}
+	p := &Publisher{
+		writer: &kafka.Writer{
+			Addr:      kafka.TCP(cfg.Brokers...),
+			Balancer:  &kafka.Hash{}, // unkeyed records are spread round robin
+			Transport: transport,
+			// Batches are collected here, the writer sends what it is given right away and
+			// leaves retrying to send
+			BatchSize:    cfg.BatchSize,
+			BatchBytes:   maxRecordBytes,
+			BatchTimeout: time.Millisecond,
+			MaxAttempts:  1,
+			RequiredAcks: kafka.RequireAll,
+		},
+		client: &kafka.Client{Addr: kafka.TCP(cfg.Brokers...), Transport: transport},
+		config: cfg,
+		queue:  make(chan []byte, cfg.QueueSize),
+		done:   make(chan struct{}),
+	}
+	go p.run()
+	return p
+}
+
+// Publish queues a record without waiting for it to be sent
+func (p *Publisher) Publish(data []byte) error {
+	if len(data) > maxRecordBytes {
+		return ErrRecordTooLarge
+	}
+
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	if p.closed {
+		return ErrClosed
+	}
+
+	select {
+	case p.queue <- data:
+		return nil
+	default:
+		if dropped := p.dropped.Add(1); dropped == 1 || dropped%1000 == 0 {
+			slog.Warn("⚠️ Event queue is full, spooling events", "queue_size", p.config.QueueSize, "dropped", dropped)
+		}
+		if p.spool([][]byte{data}) {
+			return nil
+		}
+		return ErrQueueFull
+	}
+}
+
+// SpoolRecord hands a record straight to the spool, for when the brokers are known to be down
+func (p *Publisher) SpoolRecord(data []byte) error {
+	if !p.spool([][]byte{data}) {
+		return fmt.Errorf("failed to spool record")
+	}
+	return nil
+}
+
+// Mock reports false, there is no development stand-in for Kafka
+func (p *Publisher) Mock() bool {
+	return false
+}
+
+// Deliver sends records right away with a single write, bypassing the queue. It returns the
+// indexes of the records the brokers didn't take.
+func (p *Publisher) Deliver(records [][]byte) ([]int, error) {
+	failed, err := p.write(p.messages(records))
+	p.published.Add(int64(len(records) - len(failed)))
+	return failed, err
+}
+
+// Verify checks that the brokers are reachable and have every topic events are routed to
+func (p *Publisher) Verify() error {
+	ctx, cancel := context.WithTimeout(context.Background(), writeTimeout)
+	defer cancel()
+
+	topics := []string{p.config.DefaultTopic}
+	for _, topic := range p.config.Topics {
+		topics = append(topics, topic)
+	}
+
+	metadata, err := p.client.Metadata(ctx, &kafka.MetadataRequest{Topics: topics})
+	if err != nil {
+		return fmt.Errorf("kafka brokers %v are not reachable: %w", p.config.Brokers, err)
+	}
+	for _, topic := range metadata.Topics {
+		if topic.Error != nil {
+			return fmt.Errorf("kafka topic '%s' is missing or not accessible: %w", topic.Name, topic.Error)
+		}
+	}
+
+	return nil
+}
+
+// Stats returns the publisher's counters
+func (p *Publisher) Stats() events.PublisherStats {
+	return events.PublisherStats{
+		Backend:   "kafka",
+		Queued:    len(p.queue),
+		Published: p.published.Load(),
+		Retried:   p.retried.Load(),
+		Failed:    p.failed.Load(),
+		Dropped:   p.dropped.Load(),
+		Spooled:   p.spooled.Load(),
+	}
+}
+
+// Close stops taking records and waits until the queued ones are sent, or ctx is done
+func (p *Publisher) Close(ctx context.Context) error {
+	p.mu.Lock()
+	if !p.closed {
+		p.closed = true
+		close(p.queue)
+	}
+	p.mu.Unlock()
+
+	select {
+	case <-p.done:
+		return p.writer.Close()
+	case <-ctx.Done():
+		return fmt.Errorf("failed to flush %d queued events: %w", len(p.queue), ctx.Err())
+	}
+}
+
+// run collects records into batches, sending a batch once it is full or FlushInterval after
+// its first record
+func (p *Publisher) run() {
+	defer close(p.done)
+
+	var batch [][]byte
+	timer := time.NewTimer(p.config.FlushInterval)
+	timer.Stop()
+
+	flush := func() {
+		timer.Stop()
+		if len(batch) > 0 {
+			p.send(p.messages(batch))
+		}
+		batch = nil
+	}
+
+	for {
+		select {
+		case data, ok := <-p.queue:
+			if !ok {
+				flush()
+				return
+			}
+			if len(batch) == 0 {
+				timer.Reset(p.config.FlushInterval)
+			}
+			batch = append(batch, data)
+			if len(batch) >= p.config.BatchSize {
+				flush()
+			}
+		case <-timer.C:
+			flush()
+		}
+	}
+}
+
+// send writes a batch, retrying what the brokers failed with exponential backoff
+func (p *Publisher) send(messages []kafka.Message) {
+	backoff := 100 * time.Millisecond
+	for attempt := 0; ; attempt++ {
+		failed, err := p.write(messages)
+		p.published.Add(int64(len(messages) - len(failed)))
+		if len(failed) == 0 {
+			return
+		}
+
+		pending := make([]kafka.Message, len(failed))
+		for i, index := range failed {
+			pending[i] = messages[index]
+		}
+
+		if attempt >= p.config.MaxRetries || !retryable(err) {
+			slog.Error("❌ Could not publish events to Kafka", "records", len(pending), "attempts", attempt+1, "error", err)
+			p.spool(messageData(pending))
+			return
+		}
+
+		p.retried.Add(int64(len(pending)))
+		slog.Warn("⚠️ Retrying events Kafka did not take", "records", len(pending), "attempt", attempt+1, "error", err)
+		time.Sleep(backoff)
+		backoff = min(backoff*2, publishMaxBackoff)
+		messages = pending
+	}
+}
+
+// write sends messages once and returns the indexes of the ones that have to be sent again
+func (p *Publisher) write(messages []kafka.Message) ([]int, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), writeTimeout)
+	defer cancel()
+
+	err := p.writer.WriteMessages(ctx, messages...)
+	if err == nil {
+		return nil, nil
+	}
+
+	// Write errors line up with the messages sent, the ones that went through are nil
+	var writeErrors kafka.WriteErrors
+	if errors.As(err, &writeErrors) && len(writeErrors) == len(messages) {
+		var failed []int
+		var lastErr error
+		for i, writeErr := range writeErrors {
+			if writeErr != nil {
+				failed = append(failed, i)
+				lastErr = writeErr
+			}
+		}
+		return failed, fmt.Errorf("failed to write messages to Kafka: %w", lastErr)
+	}
+
+	failed := make([]int, len(messages))
+	for i := range failed {
+		failed[i] = i
+	}
+	return failed, fmt.Errorf("failed to write messages to Kafka: %w", err)
+}
+
+// messages routes records to the topic of their event type, keyed by their stream
+func (p *Publisher) messages(records [][]byte) []kafka.Message {
+	messages := make([]kafka.Message, len(records))
+	for i, data := range records {
+		routing := events.RecordRouting(data)
+		topic, ok := p.config.Topics[routing.EventType]
+		if !ok {
+			topic = p.config.DefaultTopic
+		}
+
+		messages[i] = kafka.Message{
+			Topic:   topic,
+			Value:   data,
+			Headers: []kafka.Header{{Key: "event_type", Value: []byte(routing.EventType)}},
+		}
+		if routing.StreamID != "" {
+			messages[i].Key = []byte(routing.StreamID)
+		}
+	}
+	return messages
+}
+
+// spool hands records to the spool, counting them as failed when there is none or it
+// can't take them
+func (p *Publisher) spool(records [][]byte) bool {
+	if p.config.Spool != nil {
+		err := p.config.Spool.Spool(records)
+		if err == nil {
+			p.spooled.Add(int64(len(records)))
+			return true
+		}
+		slog.Error("❌ Could not spool events, they are lost", "records", len(records), "error", err)
+	}
+	p.failed.Add(int64(len(records)))
+	return false
+}
+
+func messageData(messages []kafka.Message) [][]byte {
+	data := make([][]byte, len(messages))
+	for i, message := range messages {
+		data[i] = message.Value
+	}
+	return data
+}
+
+// retryable reports whether messages that failed with err may go through when sent again,
+// which is the case for the errors Kafka marks temporary, e.g. a partition without a leader
+// during a broker restart, and for network failures
+func retryable(err error) bool {
+	var kerr kafka.Error
+	if errors.As(err, &kerr) {
+		return kerr.Temporary()
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr) || errors.Is(err, context.DeadlineExceeded)
+}
@@ -0,0 +1,354 @@
+// services/stream-management-service/pkg/nats/publisher.go
+package nats
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/shared/go/pkg/eventbus"
+	"github.com/Saoudyahya/Live-Streaming-Platform-Architecture/shared/go/pkg/events"
+)
+
+const (
+	maxBatchRecords = 500
+	maxRecordBytes  = 1 << 20 // the server's default max_payload
+
+	ackTimeout        = 10 * time.Second // of one publish, until JetStream stored it
+	publishMaxBackoff = 5 * time.Second
+)
+
+var (
+	// ErrQueueFull is returned when events arrive faster than JetStream takes them
+	ErrQueueFull      = errors.New("event queue is full")
+	ErrClosed         = errors.New("event publisher is closed")
+	ErrRecordTooLarge = errors.New("record is larger than 1 MiB")
+)
+
+// Config connects and sizes a Publisher
+type Config struct {
+	URL             string // comma separated servers of a cluster
+	Name            string // client name shown by the server
+	CredentialsFile string // user credentials, for servers with decentralized auth
+
+	// CreateStream creates the stream of the events, or updates it to StreamOptions, instead of
+	// expecting it to exist
+	CreateStream  bool
+	StreamOptions eventbus.StreamOptions
+
+	QueueSize  int // records buffered before new ones are dropped
+	BatchSize  int // records published before waiting for their acks, at most 500
+	MaxRetries int // times records JetStream failed are published again
+
+	// Spool takes the records that were dropped or given up on. Without one they are lost.
+	Spool events.Spool
+}
+
+// Publisher publishes event records to NATS JetStream, on the subject of their event type in
+// the stream domain. Unlike the Kinesis and Kafka publishers records aren't held back for a
+// batch to fill, whatever is queued goes out at once and acks are awaited a batch at a time,
+// which keeps the latency at a round trip. JetStream deduplicates records by event ID, a
+// record replayed from the spool after it was stored is kept once.
+type Publisher struct {
+	conn   *nats.Conn
+	js     jetstream.JetStream
+	config Config
+
+	mu     sync.RWMutex // guards closed against sends on the closed queue
+	closed bool
+	queue  chan []byte
+	done   chan struct{}
+
+	published atomic.Int64
+	retried   atomic.Int64
+	failed    atomic.Int64
+	dropped   atomic.Int64
+	spooled   atomic.Int64
+}
+
+func NewPublisher(cfg Config) (*Publisher, error) {
+	if cfg.BatchSize <= 0 || cfg.BatchSize > maxBatchRecords {
+		cfg.BatchSize = maxBatchRecords
+	}
+	if cfg.QueueSize < cfg.BatchSize {
+		cfg.QueueSize = cfg.BatchSize
+	}
+
+	// The connection keeps reconnecting, to a server that isn't up yet too. Publishing in the
+	// meantime is buffered by the client up to its reconnect buffer, then fails and spools.
+	opts := []nats.Option{
+		nats.Name(cfg.Name),
+		nats.RetryOnFailedConnect(true),
+		nats.MaxReconnects(-1),
+		nats.DisconnectErrHandler(func(_ *nats.Conn, err error) {
+			slog.Warn("⚠️ Disconnected from NATS", "error", err)
+		}),
+		nats.ReconnectHandler(func(conn *nats.Conn) {
+			slog.Info("🔌 Reconnected to NATS", "server", conn.ConnectedUrl())
+		}),
+	}
+	if cfg.CredentialsFile != "" {
+		opts = append(opts, nats.UserCredentials(cfg.CredentialsFile))
+	}
+
+	conn, err := nats.Connect(cfg.URL, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to NATS at %s: %w", cfg.URL, err)
+	}
+	js, err := jetstream.New(conn,
+		jetstream.WithPublishAsyncMaxPending(maxBatchRecords),
+		jetstream.WithPublishAsyncTimeout(ackTimeout),
+	)
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to set up JetStream: %w", err)
+	}
+
+	p := &Publisher{
+		conn:   conn,
+		js:     js,
+		config: cfg,
+		queue:  make(chan []byte, cfg.QueueSize),
+		done:   make(chan struct{}),
+	}
+	go p.run()
+	return p, nil
+}
+
+// Publish queues a record without waiting for it to be sent
+func (p *Publisher) Publish(data []byte) error {
+	if len(data) > maxRecordBytes {
+		return ErrRecordTooLarge
+	}
+
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	if p.closed {
+		return ErrClosed
+	}
+
+	select {
+	case p.queue <- data:
+		return nil
+	default:
+		if dropped := p.dropped.Add(1); dropped == 1 || dropped%1000 == 0 {
+			slog.Warn("⚠️ Event queue is full, spooling events", "queue_size", p.config.QueueSize, "dropped", dropped)
+		}
+		if p.spool([][]byte{data}) {
+			return nil
+		}
+		return ErrQueueFull
+	}
+}
+
+// SpoolRecord hands a record straight to the spool, for when JetStream is known to be down
+func (p *Publisher) SpoolRecord(data []byte) error {
+	if !p.spool([][]byte{data}) {
+		return fmt.Errorf("failed to spool record")
+	}
+	return nil
+}
+
+// Mock reports false, there is no development stand-in for NATS
+func (p *Publisher) Mock() bool {
+	return false
+}
+
+// Deliver publishes records right away, bypassing the queue. It returns the indexes of the
+// records JetStream didn't store.
+func (p *Publisher) Deliver(records [][]byte) ([]int, error) {
+	failed, err := p.publish(p.messages(records))
+	p.published.Add(int64(len(records) - len(failed)))
+	return failed, err
+}
+
+// Verify checks that JetStream has the stream events are published to, creating it when the
+// publisher is configured to
+func (p *Publisher) Verify() error {
+	ctx, cancel := context.WithTimeout(context.Background(), ackTimeout)
+	defer cancel()
+
+	if p.config.CreateStream {
+		_, err := eventbus.EnsureStream(ctx, p.js, eventbus.DomainStream, p.config.StreamOptions)
+		return err
+	}
+
+	name := eventbus.StreamName(eventbus.DomainStream)
+	if _, err := p.js.Stream(ctx, name); err != nil {
+		return fmt.Errorf("jetstream stream '%s' is missing or not accessible: %w", name, err)
+	}
+
+	return nil
+}
+
+// Stats returns the publisher's counters
+func (p *Publisher) Stats() events.PublisherStats {
+	return events.PublisherStats{
+		Backend:   "nats",
+		Queued:    len(p.queue),
+		Published: p.published.Load(),
+		Retried:   p.retried.Load(),
+		Failed:    p.failed.Load(),
+		Dropped:   p.dropped.Load(),
+		Spooled:   p.spooled.Load(),
+	}
+}
+
+// Close stops taking records and waits until the queued ones are sent, or ctx is done
+func (p *Publisher) Close(ctx context.Context) error {
+	p.mu.Lock()
+	if !p.closed {
+		p.closed = true
+		close(p.queue)
+	}
+	p.mu.Unlock()
+
+	select {
+	case <-p.done:
+		return p.conn.Drain()
+	case <-ctx.Done():
+		p.conn.Close()
+		return fmt.Errorf("failed to flush %d queued events: %w", len(p.queue), ctx.Err())
+	}
+}
+
+// run publishes what is queued, a batch at a time or as much as there is when the queue runs
+// empty
+func (p *Publisher) run() {
+	defer close(p.done)
+
+	var batch []*nats.Msg
+	for data := range p.queue {
+		batch = append(batch, p.messages([][]byte{data})...)
+		if len(batch) >= p.config.BatchSize || len(p.queue) == 0 {
+			p.send(batch)
+			batch = nil
+		}
+	}
+}
+
+// send publishes a batch, retrying what JetStream failed with exponential backoff
+func (p *Publisher) send(messages []*nats.Msg) {
+	backoff := 100 * time.Millisecond
+	for attempt := 0; ; attempt++ {
+		failed, err := p.publish(messages)
+		p.published.Add(int64(len(messages) - len(failed)))
+		if len(failed) == 0 {
+			return
+		}
+
+		pending := make([]*nats.Msg, len(failed))
+		for i, index := range failed {
+			pending[i] = messages[index]
+		}
+
+		if attempt >= p.config.MaxRetries || !retryable(err) {
+			slog.Error("❌ Could not publish events to NATS", "records", len(pending), "attempts", attempt+1, "error", err)
+			p.spool(messageData(pending))
+			return
+		}
+
+		p.retried.Add(int64(len(pending)))
+		slog.Warn("⚠️ Retrying events JetStream did not store", "records", len(pending), "attempt", attempt+1, "error", err)
+		time.Sleep(backoff)
+		backoff = min(backoff*2, publishMaxBackoff)
+		messages = pending
+	}
+}
+
+// publish sends messages asynchronously, waits for their acks and returns the indexes of the
+// ones that have to be sent again
+func (p *Publisher) publish(messages []*nats.Msg) ([]int, error) {
+	futures := make([]jetstream.PubAckFuture, len(messages))
+	var failed []int
+	var lastErr error
+	for i, msg := range messages {
+		future, err := p.js.PublishMsgAsync(msg)
+		if err != nil {
+			failed = append(failed, i)
+			lastErr = err
+			continue
+		}
+		futures[i] = future
+	}
+
+	for i, future := range futures {
+		if future == nil {
+			continue
+		}
+		select {
+		case <-future.Ok():
+		case err := <-future.Err():
+			failed = append(failed, i)
+			lastErr = err
+		}
+	}
+
+	if lastErr != nil {
+		return failed, fmt.Errorf("failed to publish messages to JetStream: %w", lastErr)
+	}
+	return nil, nil
+}
+
+// messages puts records on the subjects of their event types, with their event ID for
+// deduplication
+func (p *Publisher) messages(records [][]byte) []*nats.Msg {
+	messages := make([]*nats.Msg, len(records))
+	for i, data := range records {
+		routing := events.RecordRouting(data)
+		msg := nats.NewMsg(eventbus.StreamEventSubject(routing.EventType))
+		msg.Data = data
+		if routing.EventID != "" {
+			msg.Header.Set(eventbus.HeaderMsgID, routing.EventID)
+		}
+		if routing.StreamID != "" {
+			msg.Header.Set(eventbus.HeaderKey, routing.StreamID)
+		}
+		messages[i] = msg
+	}
+	return messages
+}
+
+// spool hands records to the spool, counting them as failed when there is none or it
+// can't take them
+func (p *Publisher) spool(records [][]byte) bool {
+	if p.config.Spool != nil {
+		err := p.config.Spool.Spool(records)
+		if err == nil {
+			p.spooled.Add(int64(len(records)))
+			return true
+		}
+		slog.Error("❌ Could not spool events, they are lost", "records", len(records), "error", err)
+	}
+	p.failed.Add(int64(len(records)))
+	return false
+}
+
+func messageData(messages []*nats.Msg) [][]byte {
+	data := make([][]byte, len(messages))
+	for i, msg := range messages {
+		data[i] = msg.Data
+	}
+	return data
+}
+
+// retryable reports whether messages that failed with err may be stored when sent again,
+// which is the case while a server restarts or the stream elects a leader but not e.g. when
+// no stream takes the subject
+func retryable(err error) bool {
+	switch {
+	case errors.Is(err, jetstream.ErrAsyncPublishTimeout),
+		errors.Is(err, jetstream.ErrTooManyStalledMsgs),
+		errors.Is(err, nats.ErrTimeout),
+		errors.Is(err, nats.ErrConnectionReconnecting):
+		return true
+	}
+	return false
+}
@@ -0,0 +1,340 @@
+// services/stream-management-service/pkg/scheduler/scheduler.go
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/rand/v2"
+	"sync"
+	"time"
+
+	"github.com/robfig/cron/v3"
+)
+
+var (
+	ErrUnknownJob = errors.New("unknown job")
+	ErrJobRunning = errors.New("job is already running on this replica")
+	ErrNotStarted = errors.New("scheduler isn't running")
+)
+
+// Outcomes of a job run
+const (
+	OutcomeSucceeded = "succeeded"
+	OutcomeFailed    = "failed"
+	OutcomeSkipped   = "skipped" // another replica had the run or the lock
+)
+
+// Coordinator keeps the replicas of a service from running the same singleton job together,
+// e.g. the service's Redis task locks
+type Coordinator interface {
+	// ClaimRun reserves the run of a job scheduled at scheduledAt for this replica, so only
+	// one replica runs it. It is false when another replica claimed it first.
+	ClaimRun(job string, scheduledAt time.Time, ttl time.Duration) (bool, error)
+	// Run runs fn under the job's lock and reports whether it ran. It doesn't when the job
+	// still runs on another replica.
+	Run(ctx context.Context, job string, fn func(ctx context.Context) error) (bool, error)
+}
+
+// Job is a task run on a schedule
+type Job struct {
+	Name string
+	// Schedule is a cron expression with five fields, or a descriptor like @hourly or
+	// @every 1m. An empty schedule runs the job only when it is triggered.
+	Schedule string
+	// Jitter delays each run by up to this much, so replicas don't all hit Redis at once
+	Jitter time.Duration
+	// Singleton jobs run on one replica per scheduled run, under the Coordinator's lock
+	Singleton bool
+	Run       func(ctx context.Context) error
+}
+
+// JobStatus is what a job did on this replica since the service started
+type JobStatus struct {
+	Name           string     `json:"name"`
+	Schedule       string     `json:"schedule,omitempty"`
+	Singleton      bool       `json:"singleton"`
+	Running        bool       `json:"running"`
+	NextRun        *time.Time `json:"next_run,omitempty"`
+	LastRun        *time.Time `json:"last_run,omitempty"`
+	LastDurationMs int64      `json:"last_duration_ms,omitempty"`
+	LastOutcome    string     `json:"last_outcome,omitempty"`
+	LastError      string     `json:"last_error,omitempty"`
+	Runs           int64      `json:"runs"`
+	Failures       int64      `json:"failures"`
+	Skipped        int64      `json:"skipped"`
+}
+
+type entry struct {
+	rescheduled chan struct{}
+
+	mu       sync.Mutex // guards the job's schedule and jitter too
+	job      Job
+	schedule cron.Schedule // nil for jobs that are only triggered
+	status   JobStatus
+}
+
+// Scheduler runs jobs on their schedules until its context is done
+type Scheduler struct {
+	coordinator Coordinator
+
+	mu   sync.Mutex
+	jobs map[string]*entry
+	list []*entry // in the order they were added
+	ctx  context.Context
+}
+
+func New(coordinator Coordinator) *Scheduler {
+	return &Scheduler{
+		coordinator: coordinator,
+		jobs:        make(map[string]*entry),
+	}
+}
+
+// Add registers a job, before the scheduler is started
+func (s *Scheduler) Add(job Job) error {
+	if job.Name == "" || job.Run == nil {
+		return fmt.Errorf("job needs a name and a function to run")
+	}
+	if job.Singleton && s.coordinator == nil {
+		return fmt.Errorf("singleton job %s needs a coordinator", job.Name)
+	}
+
+	schedule, err := parseSchedule(job.Schedule)
+	if err != nil {
+		return fmt.Errorf("invalid schedule %q of job %s: %w", job.Schedule, job.Name, err)
+	}
+	e := &entry{rescheduled: make(chan struct{}, 1), job: job, schedule: schedule}
+	e.status = JobStatus{Name: job.Name, Schedule: job.Schedule, Singleton: job.Singleton}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.jobs[job.Name]; ok {
+		return fmt.Errorf("job %s is already registered", job.Name)
+	}
+	s.jobs[job.Name] = e
+	s.list = append(s.list, e)
+	return nil
+}
+
+// Start runs the scheduled jobs until ctx is done
+func (s *Scheduler) Start(ctx context.Context) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.ctx = ctx
+	for _, e := range s.list {
+		go s.loop(ctx, e)
+	}
+	slog.Info("⏰ Job scheduler started", "jobs", len(s.list))
+}
+
+// Reschedule changes when a job runs, from its next run on. An empty schedule leaves the job
+// to be triggered.
+func (s *Scheduler) Reschedule(name, schedule string, jitter time.Duration) error {
+	s.mu.Lock()
+	e, ok := s.jobs[name]
+	s.mu.Unlock()
+	if !ok {
+		return ErrUnknownJob
+	}
+
+	parsed, err := parseSchedule(schedule)
+	if err != nil {
+		return fmt.Errorf("invalid schedule %q of job %s: %w", schedule, name, err)
+	}
+
+	e.mu.Lock()
+	changed := e.job.Schedule != schedule || e.job.Jitter != jitter
+	e.job.Schedule, e.job.Jitter, e.schedule = schedule, jitter, parsed
+	e.status.Schedule = schedule
+	e.mu.Unlock()
+
+	if changed {
+		select {
+		case e.rescheduled <- struct{}{}:
+		default:
+		}
+		slog.Info("⏰ Job rescheduled", "job", name, "schedule", schedule, "jitter", jitter)
+	}
+	return nil
+}
+
+// Trigger runs a job right away in the background, on this replica unless the job is a
+// singleton that is running on another one
+func (s *Scheduler) Trigger(name string) error {
+	s.mu.Lock()
+	e, ok := s.jobs[name]
+	ctx := s.ctx
+	s.mu.Unlock()
+
+	if !ok {
+		return ErrUnknownJob
+	}
+	if ctx == nil {
+		return ErrNotStarted
+	}
+	if !e.begin() {
+		return ErrJobRunning
+	}
+
+	go s.run(ctx, e, nil, time.Time{})
+	return nil
+}
+
+// Jobs returns the status of every job, in the order they were added
+func (s *Scheduler) Jobs() []JobStatus {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	jobs := make([]JobStatus, 0, len(s.list))
+	for _, e := range s.list {
+		e.mu.Lock()
+		jobs = append(jobs, e.status)
+		e.mu.Unlock()
+	}
+	return jobs
+}
+
+// loop waits for each scheduled run of a job, plus its jitter, and runs it. A job without a
+// schedule waits to be rescheduled.
+func (s *Scheduler) loop(ctx context.Context, e *entry) {
+	for {
+		e.mu.Lock()
+		schedule, jitter := e.schedule, e.job.Jitter
+		var next time.Time
+		if schedule != nil {
+			next = nextRun(schedule, time.Now())
+			e.status.NextRun = &next
+		} else {
+			e.status.NextRun = nil
+		}
+		e.mu.Unlock()
+
+		var timer *time.Timer
+		var due <-chan time.Time
+		if schedule != nil {
+			delay := time.Until(next)
+			if jitter > 0 {
+				delay += rand.N(jitter)
+			}
+			timer = time.NewTimer(delay)
+			due = timer.C
+		}
+
+		select {
+		case <-ctx.Done():
+			stopTimer(timer)
+			return
+		case <-e.rescheduled:
+			stopTimer(timer)
+			continue
+		case <-due:
+		}
+
+		// A triggered run that is still going takes the place of this one
+		if !e.begin() {
+			slog.WarnContext(ctx, "⚠️ Job is still running, skipping its scheduled run", "job", e.job.Name)
+			continue
+		}
+		s.run(ctx, e, schedule, next)
+	}
+}
+
+// run runs a job that begin marked running. Scheduled runs of a singleton job are claimed
+// first, a triggered run has no schedule and only needs the lock.
+func (s *Scheduler) run(ctx context.Context, e *entry, schedule cron.Schedule, scheduledAt time.Time) {
+	started := time.Now()
+	ran, err := s.execute(ctx, e, schedule, scheduledAt)
+	e.finish(started, ran, err)
+
+	switch {
+	case err != nil:
+		slog.WarnContext(ctx, "⚠️ Job failed", "job", e.job.Name, "duration", time.Since(started), "error", err)
+	case !ran:
+		slog.DebugContext(ctx, "⏭️ Job run was taken by another replica", "job", e.job.Name)
+	default:
+		slog.DebugContext(ctx, "✅ Job finished", "job", e.job.Name, "duration", time.Since(started))
+	}
+}
+
+func (s *Scheduler) execute(ctx context.Context, e *entry, schedule cron.Schedule, scheduledAt time.Time) (bool, error) {
+	if !e.job.Singleton {
+		return true, e.job.Run(ctx)
+	}
+
+	if schedule != nil {
+		// The claim outlives every replica's jitter, the next run has a key of its own
+		ttl := nextRun(schedule, scheduledAt).Sub(scheduledAt)
+		claimed, err := s.coordinator.ClaimRun(e.job.Name, scheduledAt, ttl)
+		if err != nil {
+			return false, fmt.Errorf("failed to claim job run: %w", err)
+		}
+		if !claimed {
+			return false, nil
+		}
+	}
+
+	return s.coordinator.Run(ctx, e.job.Name, e.job.Run)
+}
+
+// parseSchedule parses a cron expression or descriptor, nil when it is empty
+func parseSchedule(spec string) (cron.Schedule, error) {
+	if spec == "" {
+		return nil, nil
+	}
+	return cron.ParseStandard(spec)
+}
+
+// nextRun returns when a schedule runs after t. Intervals of @every are counted from a fixed
+// point in time instead of t, so every replica computes the same runs and only one can claim
+// each.
+func nextRun(schedule cron.Schedule, t time.Time) time.Time {
+	if every, ok := schedule.(cron.ConstantDelaySchedule); ok {
+		return t.Truncate(every.Delay).Add(every.Delay)
+	}
+	return schedule.Next(t)
+}
+
+func stopTimer(timer *time.Timer) {
+	if timer != nil {
+		timer.Stop()
+	}
+}
+
+// begin marks the job running, false when it already is
+func (e *entry) begin() bool {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.status.Running {
+		return false
+	}
+	e.status.Running = true
+	return true
+}
+
+func (e *entry) finish(started time.Time, ran bool, err error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.status.Running = false
+	e.status.LastRun = &started
+	e.status.LastDurationMs = time.Since(started).Milliseconds()
+	e.status.LastError = ""
+
+	switch {
+	case err != nil:
+		e.status.LastOutcome = OutcomeFailed
+		e.status.LastError = err.Error()
+		e.status.Failures++
+	case !ran:
+		e.status.LastOutcome = OutcomeSkipped
+		e.status.Skipped++
+		return
+	default:
+		e.status.LastOutcome = OutcomeSucceeded
+	}
+	e.status.Runs++
+}
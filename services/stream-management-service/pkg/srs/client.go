@@ -0,0 +1,122 @@
+// services/stream-management-service/pkg/srs/client.go
+package srs
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// ErrNotPublishing is returned when no client is publishing the stream
+var ErrNotPublishing = errors.New("stream is not being published")
+
+// Client talks to the SRS HTTP API
+type Client struct {
+	baseURL    string
+	httpClient *http.Client
+}
+
+// NewClient calls the SRS API at baseURL through transport, which bounds and retries the
+// calls. http.DefaultTransport is used when it's nil.
+func NewClient(baseURL string, transport http.RoundTripper) *Client {
+	return &Client{
+		baseURL:    strings.TrimSuffix(baseURL, "/"),
+		httpClient: &http.Client{Transport: transport},
+	}
+}
+
+type apiResponse struct {
+	Code    int `json:"code"`
+	Streams []struct {
+		Name    string `json:"name"`
+		App     string `json:"app"`
+		Publish struct {
+			Active bool   `json:"active"`
+			CID    string `json:"cid"`
+		} `json:"publish"`
+	} `json:"streams"`
+}
+
+// KickPublisher drops the client publishing app/stream. clientID is the client_id SRS sent
+// in the on_publish callback, the publisher is looked up when it's empty.
+func (c *Client) KickPublisher(ctx context.Context, app, stream, clientID string) error {
+	if clientID == "" {
+		var err error
+		if clientID, err = c.findPublisher(ctx, app, stream); err != nil {
+			return err
+		}
+	}
+
+	var resp apiResponse
+	if err := c.do(ctx, http.MethodDelete, "/api/v1/clients/"+url.PathEscape(clientID), &resp); err != nil {
+		return fmt.Errorf("failed to kick client %s: %w", clientID, err)
+	}
+	return nil
+}
+
+// Publisher is a client publishing a stream
+type Publisher struct {
+	App      string
+	Stream   string // the stream key
+	ClientID string
+}
+
+// ListPublishers returns the streams being published right now
+func (c *Client) ListPublishers(ctx context.Context) ([]Publisher, error) {
+	var resp apiResponse
+	if err := c.do(ctx, http.MethodGet, "/api/v1/streams/?start=0&count=1000", &resp); err != nil {
+		return nil, fmt.Errorf("failed to list streams: %w", err)
+	}
+
+	publishers := make([]Publisher, 0, len(resp.Streams))
+	for _, s := range resp.Streams {
+		if s.Publish.Active {
+			publishers = append(publishers, Publisher{App: s.App, Stream: s.Name, ClientID: s.Publish.CID})
+		}
+	}
+	return publishers, nil
+}
+
+// findPublisher returns the ID of the client publishing app/stream
+func (c *Client) findPublisher(ctx context.Context, app, stream string) (string, error) {
+	publishers, err := c.ListPublishers(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	for _, p := range publishers {
+		if p.Stream == stream && (app == "" || p.App == app) {
+			return p.ClientID, nil
+		}
+	}
+	return "", ErrNotPublishing
+}
+
+func (c *Client) do(ctx context.Context, method, path string, out *apiResponse) error {
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("srs returned status %d", resp.StatusCode)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode srs response: %w", err)
+	}
+	// SRS reports errors in the body with a non-zero code
+	if out.Code != 0 {
+		return fmt.Errorf("srs returned code %d", out.Code)
+	}
+	return nil
+}
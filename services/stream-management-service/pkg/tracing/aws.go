@@ -0,0 +1,49 @@
+// services/stream-management-service/pkg/tracing/aws.go
+package tracing
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go/aws/request"
+)
+
+type awsSpanKey struct{}
+
+// InstrumentAWS adds a client span to every AWS call made with a traced context
+// (the *WithContext API variants). Calls outside of a trace are left alone.
+func InstrumentAWS(handlers *request.Handlers) {
+	handlers.Validate.PushFrontNamed(request.NamedHandler{
+		Name: "tracing.StartSpan",
+		Fn: func(r *request.Request) {
+			if !SpanContextFromContext(r.Context()).IsValid() {
+				return
+			}
+
+			ctx, span := Start(r.Context(), r.ClientInfo.ServiceName+"."+r.Operation.Name, SpanKindClient)
+			span.SetAttribute("rpc.system", "aws-api")
+			span.SetAttribute("rpc.service", r.ClientInfo.ServiceName)
+			span.SetAttribute("rpc.method", r.Operation.Name)
+			r.SetContext(context.WithValue(ctx, awsSpanKey{}, span))
+		},
+	})
+
+	handlers.Complete.PushBackNamed(request.NamedHandler{
+		Name: "tracing.EndSpan",
+		Fn: func(r *request.Request) {
+			span, ok := r.Context().Value(awsSpanKey{}).(*Span)
+			if !ok {
+				return
+			}
+
+			if r.HTTPResponse != nil {
+				span.SetAttribute("http.status_code", r.HTTPResponse.StatusCode)
+			}
+			if r.RequestID != "" {
+				span.SetAttribute("aws.request_id", r.RequestID)
+			}
+			span.SetAttribute("aws.retry_count", r.RetryCount)
+			span.RecordError(r.Error)
+			span.End()
+		},
+	})
+}
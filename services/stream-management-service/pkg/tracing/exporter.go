@@ -0,0 +1,254 @@
+// services/stream-management-service/pkg/tracing/exporter.go
+package tracing
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	exportQueueSize = 2048
+	exportBatchSize = 256
+	exportInterval  = 5 * time.Second
+)
+
+// exporter batches finished spans and posts them to an OTLP/HTTP collector as JSON
+type exporter struct {
+	url      string
+	resource []otlpAttribute
+	client   *http.Client
+
+	queue chan *Span
+	flush chan chan struct{}
+	done  chan struct{}
+}
+
+func newExporter(cfg Config) (*exporter, error) {
+	if !strings.HasPrefix(cfg.Endpoint, "http://") && !strings.HasPrefix(cfg.Endpoint, "https://") {
+		return nil, fmt.Errorf("endpoint must be an http(s) URL, got %q", cfg.Endpoint)
+	}
+
+	resource := map[string]interface{}{
+		"service.name": cfg.ServiceName,
+	}
+	if cfg.Environment != "" {
+		resource["deployment.environment"] = cfg.Environment
+	}
+
+	e := &exporter{
+		url:      strings.TrimSuffix(cfg.Endpoint, "/") + "/v1/traces",
+		resource: toAttributes(resource),
+		client:   &http.Client{Timeout: 10 * time.Second},
+		queue:    make(chan *Span, exportQueueSize),
+		flush:    make(chan chan struct{}),
+		done:     make(chan struct{}),
+	}
+	go e.run()
+
+	return e, nil
+}
+
+// enqueue never blocks a request; spans are dropped when the collector can't keep up
+func (e *exporter) enqueue(span *Span) {
+	select {
+	case e.queue <- span:
+	default:
+	}
+}
+
+func (e *exporter) run() {
+	ticker := time.NewTicker(exportInterval)
+	defer ticker.Stop()
+
+	batch := make([]*Span, 0, exportBatchSize)
+	send := func() {
+		if len(batch) == 0 {
+			return
+		}
+		if err := e.export(batch); err != nil {
+			slog.Warn("⚠️ Could not export spans", "spans", len(batch), "error", err)
+		}
+		batch = batch[:0]
+	}
+
+	for {
+		select {
+		case span := <-e.queue:
+			batch = append(batch, span)
+			if len(batch) >= exportBatchSize {
+				send()
+			}
+		case <-ticker.C:
+			send()
+		case flushed := <-e.flush:
+			for drained := false; !drained; {
+				select {
+				case span := <-e.queue:
+					batch = append(batch, span)
+				default:
+					drained = true
+				}
+			}
+			send()
+			close(flushed)
+		case <-e.done:
+			return
+		}
+	}
+}
+
+func (e *exporter) shutdown(ctx context.Context) error {
+	flushed := make(chan struct{})
+	select {
+	case e.flush <- flushed:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	select {
+	case <-flushed:
+		close(e.done)
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (e *exporter) export(spans []*Span) error {
+	otlpSpans := make([]otlpSpan, 0, len(spans))
+	for _, span := range spans {
+		otlpSpans = append(otlpSpans, span.toOTLP())
+	}
+
+	payload := otlpRequest{
+		ResourceSpans: []otlpResourceSpans{{
+			Resource: otlpResource{Attributes: e.resource},
+			ScopeSpans: []otlpScopeSpans{{
+				Scope: otlpScope{Name: "stream-management-service/pkg/tracing"},
+				Spans: otlpSpans,
+			}},
+		}},
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal spans: %w", err)
+	}
+
+	resp, err := e.client.Post(e.url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to post spans: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("collector returned status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// OTLP/HTTP JSON encoding, see opentelemetry-proto's trace/v1 service definition
+
+type otlpRequest struct {
+	ResourceSpans []otlpResourceSpans `json:"resourceSpans"`
+}
+
+type otlpResourceSpans struct {
+	Resource   otlpResource     `json:"resource"`
+	ScopeSpans []otlpScopeSpans `json:"scopeSpans"`
+}
+
+type otlpResource struct {
+	Attributes []otlpAttribute `json:"attributes"`
+}
+
+type otlpScopeSpans struct {
+	Scope otlpScope  `json:"scope"`
+	Spans []otlpSpan `json:"spans"`
+}
+
+type otlpScope struct {
+	Name string `json:"name"`
+}
+
+type otlpSpan struct {
+	TraceID           string          `json:"traceId"`
+	SpanID            string          `json:"spanId"`
+	ParentSpanID      string          `json:"parentSpanId,omitempty"`
+	Name              string          `json:"name"`
+	Kind              SpanKind        `json:"kind"`
+	StartTimeUnixNano string          `json:"startTimeUnixNano"`
+	EndTimeUnixNano   string          `json:"endTimeUnixNano"`
+	Attributes        []otlpAttribute `json:"attributes,omitempty"`
+	Status            otlpStatus      `json:"status"`
+}
+
+type otlpStatus struct {
+	Code    StatusCode `json:"code"`
+	Message string     `json:"message,omitempty"`
+}
+
+type otlpAttribute struct {
+	Key   string    `json:"key"`
+	Value otlpValue `json:"value"`
+}
+
+type otlpValue struct {
+	StringValue *string  `json:"stringValue,omitempty"`
+	BoolValue   *bool    `json:"boolValue,omitempty"`
+	IntValue    *string  `json:"intValue,omitempty"` // int64 is a string in OTLP JSON
+	DoubleValue *float64 `json:"doubleValue,omitempty"`
+}
+
+func (s *Span) toOTLP() otlpSpan {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	span := otlpSpan{
+		TraceID:           s.context.TraceID.String(),
+		SpanID:            s.context.SpanID.String(),
+		Name:              s.name,
+		Kind:              s.kind,
+		StartTimeUnixNano: strconv.FormatInt(s.start.UnixNano(), 10),
+		EndTimeUnixNano:   strconv.FormatInt(s.end.UnixNano(), 10),
+		Attributes:        toAttributes(s.attributes),
+		Status:            otlpStatus{Code: s.status, Message: s.statusMsg},
+	}
+	if s.parentID != (SpanID{}) {
+		span.ParentSpanID = s.parentID.String()
+	}
+	return span
+}
+
+func toAttributes(values map[string]interface{}) []otlpAttribute {
+	attributes := make([]otlpAttribute, 0, len(values))
+	for key, value := range values {
+		var v otlpValue
+		switch typed := value.(type) {
+		case string:
+			v.StringValue = &typed
+		case bool:
+			v.BoolValue = &typed
+		case int:
+			s := strconv.Itoa(typed)
+			v.IntValue = &s
+		case int64:
+			s := strconv.FormatInt(typed, 10)
+			v.IntValue = &s
+		case float64:
+			v.DoubleValue = &typed
+		default:
+			s := fmt.Sprint(typed)
+			v.StringValue = &s
+		}
+		attributes = append(attributes, otlpAttribute{Key: key, Value: v})
+	}
+	return attributes
+}
@@ -0,0 +1,68 @@
+// services/stream-management-service/pkg/tracing/grpc.go
+package tracing
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// metadataCarrier adapts gRPC metadata to Carrier
+type metadataCarrier metadata.MD
+
+func (m metadataCarrier) Get(key string) string {
+	values := metadata.MD(m).Get(key)
+	if len(values) == 0 {
+		return ""
+	}
+	return values[0]
+}
+
+func (m metadataCarrier) Set(key, value string) {
+	metadata.MD(m).Set(key, value)
+}
+
+// UnaryClientInterceptor starts a client span per call and propagates it to the server
+func UnaryClientInterceptor() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		ctx, span := Start(ctx, method, SpanKindClient)
+		defer span.End()
+		span.SetAttribute("rpc.system", "grpc")
+		span.SetAttribute("rpc.method", method)
+
+		md, ok := metadata.FromOutgoingContext(ctx)
+		if ok {
+			md = md.Copy()
+		} else {
+			md = metadata.MD{}
+		}
+		Inject(ctx, metadataCarrier(md))
+		ctx = metadata.NewOutgoingContext(ctx, md)
+
+		err := invoker(ctx, method, req, reply, cc, opts...)
+		span.SetAttribute("rpc.grpc.status_code", int(status.Code(err)))
+		span.RecordError(err)
+		return err
+	}
+}
+
+// UnaryServerInterceptor continues the caller's trace in a server span
+func UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if md, ok := metadata.FromIncomingContext(ctx); ok {
+			ctx = Extract(ctx, metadataCarrier(md))
+		}
+
+		ctx, span := Start(ctx, info.FullMethod, SpanKindServer)
+		defer span.End()
+		span.SetAttribute("rpc.system", "grpc")
+		span.SetAttribute("rpc.method", info.FullMethod)
+
+		resp, err := handler(ctx, req)
+		span.SetAttribute("rpc.grpc.status_code", int(status.Code(err)))
+		span.RecordError(err)
+		return resp, err
+	}
+}
@@ -0,0 +1,214 @@
+// services/stream-management-service/pkg/tracing/tracing.go
+package tracing
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// SpanKind values match the OTLP protocol
+type SpanKind int
+
+const (
+	SpanKindInternal SpanKind = 1
+	SpanKindServer   SpanKind = 2
+	SpanKindClient   SpanKind = 3
+)
+
+// StatusCode values match the OTLP protocol
+type StatusCode int
+
+const (
+	StatusUnset StatusCode = 0
+	StatusOK    StatusCode = 1
+	StatusError StatusCode = 2
+)
+
+type (
+	TraceID [16]byte
+	SpanID  [8]byte
+)
+
+func (t TraceID) String() string { return hex.EncodeToString(t[:]) }
+func (s SpanID) String() string  { return hex.EncodeToString(s[:]) }
+
+// SpanContext identifies a span across process boundaries
+type SpanContext struct {
+	TraceID TraceID
+	SpanID  SpanID
+	Sampled bool
+}
+
+func (sc SpanContext) IsValid() bool {
+	return sc.TraceID != TraceID{} && sc.SpanID != SpanID{}
+}
+
+// Config configures the process wide tracer
+type Config struct {
+	ServiceName string
+	Environment string
+	Endpoint    string  // OTLP/HTTP collector, e.g. http://localhost:4318. Empty disables exporting.
+	SampleRatio float64 // fraction of new traces that are recorded
+}
+
+// Tracer creates spans and hands finished ones to the exporter
+type Tracer struct {
+	config   Config
+	exporter *exporter
+}
+
+// A tracer without an exporter still propagates trace context, it just records nothing
+var global = &Tracer{}
+
+// Init installs the process wide tracer. Call Shutdown on the returned tracer to flush spans.
+func Init(cfg Config) (*Tracer, error) {
+	tracer := &Tracer{config: cfg}
+	if cfg.Endpoint != "" {
+		exp, err := newExporter(cfg)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create OTLP exporter: %w", err)
+		}
+		tracer.exporter = exp
+	}
+
+	global = tracer
+	return tracer, nil
+}
+
+// Shutdown flushes pending spans
+func (t *Tracer) Shutdown(ctx context.Context) error {
+	if t.exporter == nil {
+		return nil
+	}
+	return t.exporter.shutdown(ctx)
+}
+
+// Span is a single timed operation in a trace
+type Span struct {
+	tracer   *Tracer
+	name     string
+	kind     SpanKind
+	context  SpanContext
+	parentID SpanID
+	start    time.Time
+
+	mu         sync.Mutex
+	end        time.Time
+	attributes map[string]interface{}
+	status     StatusCode
+	statusMsg  string
+	ended      bool
+}
+
+type spanKey struct{}
+
+// Start begins a span as a child of the span in ctx, or of the remote parent extracted into it
+func Start(ctx context.Context, name string, kind SpanKind) (context.Context, *Span) {
+	return global.Start(ctx, name, kind)
+}
+
+func (t *Tracer) Start(ctx context.Context, name string, kind SpanKind) (context.Context, *Span) {
+	span := &Span{
+		tracer:     t,
+		name:       name,
+		kind:       kind,
+		start:      time.Now(),
+		attributes: make(map[string]interface{}),
+	}
+
+	parent := SpanContextFromContext(ctx)
+	if parent.IsValid() {
+		span.context.TraceID = parent.TraceID
+		span.context.Sampled = parent.Sampled
+		span.parentID = parent.SpanID
+	} else {
+		rand.Read(span.context.TraceID[:])
+		span.context.Sampled = t.sample(span.context.TraceID)
+	}
+	rand.Read(span.context.SpanID[:])
+
+	return context.WithValue(ctx, spanKey{}, span), span
+}
+
+// sample decides on new traces from the trace ID, so every service agrees without coordination
+func (t *Tracer) sample(traceID TraceID) bool {
+	if t.exporter == nil || t.config.SampleRatio <= 0 {
+		return false
+	}
+	if t.config.SampleRatio >= 1 {
+		return true
+	}
+	bound := uint64(t.config.SampleRatio * (1 << 63))
+	return binary.BigEndian.Uint64(traceID[8:])>>1 < bound
+}
+
+// SpanFromContext returns the current span, or nil outside of a trace
+func SpanFromContext(ctx context.Context) *Span {
+	span, _ := ctx.Value(spanKey{}).(*Span)
+	return span
+}
+
+// SpanContextFromContext returns the current span's context, or the remote parent's
+func SpanContextFromContext(ctx context.Context) SpanContext {
+	if span := SpanFromContext(ctx); span != nil {
+		return span.context
+	}
+	sc, _ := ctx.Value(remoteKey{}).(SpanContext)
+	return sc
+}
+
+// TraceIDFromContext returns the hex trace ID for logs, or "" outside of a trace
+func TraceIDFromContext(ctx context.Context) string {
+	sc := SpanContextFromContext(ctx)
+	if !sc.IsValid() {
+		return ""
+	}
+	return sc.TraceID.String()
+}
+
+func (s *Span) SpanContext() SpanContext {
+	return s.context
+}
+
+// SetAttribute records a string, bool, integer or float value on the span
+func (s *Span) SetAttribute(key string, value interface{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.attributes[key] = value
+}
+
+// RecordError marks the span as failed
+func (s *Span) RecordError(err error) {
+	if err == nil {
+		return
+	}
+	s.SetStatus(StatusError, err.Error())
+}
+
+func (s *Span) SetStatus(code StatusCode, message string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.status = code
+	s.statusMsg = message
+}
+
+// End finishes the span and queues it for export if it's sampled
+func (s *Span) End() {
+	s.mu.Lock()
+	if s.ended {
+		s.mu.Unlock()
+		return
+	}
+	s.ended = true
+	s.end = time.Now()
+	s.mu.Unlock()
+
+	if s.context.Sampled && s.tracer.exporter != nil {
+		s.tracer.exporter.enqueue(s)
+	}
+}
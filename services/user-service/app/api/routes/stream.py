@@ -1,4 +1,5 @@
 # services/user-service/app/api/routes/stream.py
+from datetime import date
 from fastapi import APIRouter, Depends, HTTPException, status
 from sqlalchemy.orm import Session
 from pydantic import BaseModel
@@ -9,6 +10,9 @@ from app.api.dependencies import get_current_user
 
 router = APIRouter()
 
+# Viewers younger than this can't watch mature streams
+ADULT_AGE = 18
+
 
 class ValidateStreamKeyRequest(BaseModel):
     stream_key: str
@@ -67,6 +71,34 @@ async def validate_stream_key(
         )
 
 
+class ViewerAgeResponse(BaseModel):
+    user_id: int
+    age_known: bool
+    is_adult: bool
+
+
+@router.get("/viewer-age/{user_id}", response_model=ViewerAgeResponse)
+async def get_viewer_age(
+        user_id: int,
+        db: Session = Depends(get_db)
+):
+    """
+    Tell whether a viewer is old enough for mature streams, without exposing their birth date.
+    This endpoint is called by the Stream Management Service.
+    """
+    user = db.query(User).filter(User.id == user_id).first()
+    if not user:
+        raise HTTPException(status_code=status.HTTP_404_NOT_FOUND, detail="User not found")
+
+    if user.date_of_birth is None:
+        return ViewerAgeResponse(user_id=user.id, age_known=False, is_adult=False)
+
+    today = date.today()
+    born = user.date_of_birth
+    age = today.year - born.year - ((today.month, today.day) < (born.month, born.day))
+    return ViewerAgeResponse(user_id=user.id, age_known=True, is_adult=age >= ADULT_AGE)
+
+
 @router.get("/stream-key")
 async def get_my_stream_key(
         current_user: User = Depends(get_current_user)
@@ -1,5 +1,5 @@
 # app/models/user.py
-from sqlalchemy import Column, Integer, String, Boolean, DateTime, Text
+from sqlalchemy import Column, Integer, String, Boolean, Date, DateTime, Text
 from sqlalchemy.sql import func
 from app.config.database import Base
 
@@ -18,6 +18,7 @@ class User(Base):
     profile_image_url = Column(Text)
     bio = Column(Text)
     stream_key = Column(String(255), unique=True, index=True)
+    date_of_birth = Column(Date)  # optional, gates mature streams
 
     created_at = Column(DateTime(timezone=True), server_default=func.now())
     updated_at = Column(DateTime(timezone=True), onupdate=func.now())
@@ -1,6 +1,6 @@
 from pydantic import BaseModel, EmailStr, field_validator
 from typing import Optional
-from datetime import datetime
+from datetime import date, datetime
 
 
 class UserBase(BaseModel):
@@ -28,6 +28,14 @@ class UserUpdate(BaseModel):
     last_name: Optional[str] = None
     bio: Optional[str] = None
     profile_image_url: Optional[str] = None
+    date_of_birth: Optional[date] = None
+
+    @field_validator('date_of_birth')
+    @classmethod
+    def validate_date_of_birth(cls, v):
+        if v is not None and v > date.today():
+            raise ValueError('Date of birth must not be in the future')
+        return v
 
 
 class UserResponse(UserBase):
@@ -0,0 +1,22 @@
+module github.com/Saoudyahya/Live-Streaming-Platform-Architecture/shared/go
+
+go 1.24.2
+
+require (
+	github.com/aws/aws-sdk-go v1.55.8
+	github.com/nats-io/nats.go v1.48.0
+	google.golang.org/grpc v1.75.0
+)
+
+require (
+	github.com/jmespath/go-jmespath v0.4.0 // indirect
+	github.com/klauspost/compress v1.18.0 // indirect
+	github.com/nats-io/nkeys v0.4.11 // indirect
+	github.com/nats-io/nuid v1.0.1 // indirect
+	golang.org/x/crypto v0.39.0 // indirect
+	golang.org/x/net v0.41.0 // indirect
+	golang.org/x/sys v0.33.0 // indirect
+	golang.org/x/text v0.26.0 // indirect
+	google.golang.org/genproto/googleapis/rpc v0.0.0-20250707201910-8d1bb00bc6a7 // indirect
+	google.golang.org/protobuf v1.36.6 // indirect
+)
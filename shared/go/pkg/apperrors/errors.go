@@ -0,0 +1,150 @@
+// shared/go/pkg/apperrors/errors.go
+package apperrors
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"maps"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Code is the kind of an error, stable across message changes. It decides the gRPC code and
+// the HTTP status an error is answered with.
+type Code string
+
+const (
+	CodeInvalidRequest     Code = "invalid_request"
+	CodeUnauthorized       Code = "unauthorized"
+	CodeForbidden          Code = "forbidden"
+	CodeNotFound           Code = "not_found"
+	CodeConflict           Code = "conflict"
+	CodeFailedPrecondition Code = "failed_precondition" // the request is fine, the state it acts on isn't
+	CodeRateLimited        Code = "rate_limited"
+	CodeContentBlocked     Code = "content_blocked"
+	CodeTimeout            Code = "timeout"
+	CodeUnavailable        Code = "unavailable"
+	CodeInternal           Code = "internal"
+)
+
+// internalMessage stands in for the message of errors that aren't domain errors, whose own
+// message may tell more than clients should know
+const internalMessage = "Internal server error"
+
+// Error is a domain error, what went wrong in terms a client can act on. Its message and
+// details are shown to clients, its cause only logged.
+type Error struct {
+	Code    Code
+	Message string
+	Details map[string]string
+	Cause   error
+}
+
+func (e *Error) Error() string {
+	if e.Cause != nil {
+		return e.Message + ": " + e.Cause.Error()
+	}
+	return e.Message
+}
+
+func (e *Error) Unwrap() error {
+	return e.Cause
+}
+
+// WithDetail returns a copy of e with a detail added. Sentinel errors stay as they are, so
+// the copy no longer matches them with errors.Is.
+func (e *Error) WithDetail(key, value string) *Error {
+	copied := *e
+	copied.Details = maps.Clone(e.Details)
+	if copied.Details == nil {
+		copied.Details = make(map[string]string)
+	}
+	copied.Details[key] = value
+	return &copied
+}
+
+func New(code Code, message string) *Error {
+	return &Error{Code: code, Message: message}
+}
+
+func Newf(code Code, format string, args ...any) *Error {
+	return &Error{Code: code, Message: fmt.Sprintf(format, args...)}
+}
+
+// Wrap explains cause to clients as message, keeping cause for the logs
+func Wrap(cause error, code Code, message string) *Error {
+	return &Error{Code: code, Message: message, Cause: cause}
+}
+
+func InvalidRequest(message string) *Error {
+	return New(CodeInvalidRequest, message)
+}
+
+func Unauthorized(message string) *Error {
+	return New(CodeUnauthorized, message)
+}
+
+func Forbidden(message string) *Error {
+	return New(CodeForbidden, message)
+}
+
+func NotFound(message string) *Error {
+	return New(CodeNotFound, message)
+}
+
+func Conflict(message string) *Error {
+	return New(CodeConflict, message)
+}
+
+func FailedPrecondition(message string) *Error {
+	return New(CodeFailedPrecondition, message)
+}
+
+func Unavailable(cause error, message string) *Error {
+	return Wrap(cause, CodeUnavailable, message)
+}
+
+func Internal(cause error, message string) *Error {
+	return Wrap(cause, CodeInternal, message)
+}
+
+// As returns the domain error in err's chain. Other errors are classified by what they are:
+// gRPC status errors keep their code and message, a context running out of time is a
+// timeout and anything else is internal, with a message that tells nothing about it.
+func As(err error) *Error {
+	var appErr *Error
+	if errors.As(err, &appErr) {
+		return appErr
+	}
+
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		return Wrap(err, CodeTimeout, "Request timed out")
+	case errors.Is(err, context.Canceled):
+		return Wrap(err, CodeUnavailable, "Request was cancelled")
+	}
+	if s, ok := status.FromError(err); ok && s.Code() != codes.OK {
+		return Wrap(err, FromGRPCCode(s.Code()), s.Message())
+	}
+	return Internal(err, internalMessage)
+}
+
+// CodeOf returns the code of err, see As
+func CodeOf(err error) Code {
+	return As(err).Code
+}
+
+// Log records an error about to be answered. Internal and unavailable errors are logged with
+// their cause, the rest are the client's doing and only logged at debug.
+func Log(ctx context.Context, err error) {
+	appErr := As(err)
+	switch appErr.Code {
+	case CodeInternal, CodeUnavailable, CodeTimeout:
+		slog.ErrorContext(ctx, "❌ Request failed", "code", appErr.Code, "error", err)
+	default:
+		slog.DebugContext(ctx, "Request rejected", "code", appErr.Code, "error", err)
+	}
+}
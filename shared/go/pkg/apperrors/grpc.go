@@ -0,0 +1,75 @@
+// shared/go/pkg/apperrors/grpc.go
+package apperrors
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var grpcCodes = map[Code]codes.Code{
+	CodeInvalidRequest:     codes.InvalidArgument,
+	CodeUnauthorized:       codes.Unauthenticated,
+	CodeForbidden:          codes.PermissionDenied,
+	CodeNotFound:           codes.NotFound,
+	CodeConflict:           codes.AlreadyExists,
+	CodeFailedPrecondition: codes.FailedPrecondition,
+	CodeRateLimited:        codes.ResourceExhausted,
+	CodeContentBlocked:     codes.PermissionDenied,
+	CodeTimeout:            codes.DeadlineExceeded,
+	CodeUnavailable:        codes.Unavailable,
+	CodeInternal:           codes.Internal,
+}
+
+// GRPCCode returns the gRPC code errors of code are answered with
+func (c Code) GRPCCode() codes.Code {
+	if code, ok := grpcCodes[c]; ok {
+		return code
+	}
+	return codes.Internal
+}
+
+// FromGRPCCode returns the code of errors answered with a gRPC code, e.g. by another service
+func FromGRPCCode(code codes.Code) Code {
+	switch code {
+	case codes.InvalidArgument, codes.OutOfRange:
+		return CodeInvalidRequest
+	case codes.Unauthenticated:
+		return CodeUnauthorized
+	case codes.PermissionDenied:
+		return CodeForbidden
+	case codes.NotFound:
+		return CodeNotFound
+	case codes.AlreadyExists, codes.Aborted:
+		return CodeConflict
+	case codes.FailedPrecondition:
+		return CodeFailedPrecondition
+	case codes.ResourceExhausted:
+		return CodeRateLimited
+	case codes.DeadlineExceeded:
+		return CodeTimeout
+	case codes.Unavailable, codes.Canceled:
+		return CodeUnavailable
+	}
+	return CodeInternal
+}
+
+// GRPCStatus lets gRPC answer an Error returned by a handler with its code and message
+func (e *Error) GRPCStatus() *status.Status {
+	return status.New(e.Code.GRPCCode(), e.Message)
+}
+
+// UnaryServerInterceptor answers the errors handlers return with their code and message, so
+// the causes of internal errors are logged instead of sent to clients
+func UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		resp, err := handler(ctx, req)
+		if err == nil {
+			return resp, nil
+		}
+		Log(ctx, err)
+		return resp, As(err).GRPCStatus().Err()
+	}
+}
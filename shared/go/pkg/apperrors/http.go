@@ -0,0 +1,66 @@
+// shared/go/pkg/apperrors/http.go
+package apperrors
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+var httpStatuses = map[Code]int{
+	CodeInvalidRequest:     http.StatusBadRequest,
+	CodeUnauthorized:       http.StatusUnauthorized,
+	CodeForbidden:          http.StatusForbidden,
+	CodeNotFound:           http.StatusNotFound,
+	CodeConflict:           http.StatusConflict,
+	CodeFailedPrecondition: http.StatusConflict,
+	CodeRateLimited:        http.StatusTooManyRequests,
+	CodeContentBlocked:     http.StatusUnavailableForLegalReasons,
+	CodeTimeout:            http.StatusGatewayTimeout,
+	CodeUnavailable:        http.StatusServiceUnavailable,
+	CodeInternal:           http.StatusInternalServerError,
+}
+
+// HTTPStatus returns the HTTP status errors of code are answered with
+func (c Code) HTTPStatus() int {
+	if status, ok := httpStatuses[c]; ok {
+		return status
+	}
+	return http.StatusInternalServerError
+}
+
+// Body is an error as clients see it
+type Body struct {
+	Code    Code              `json:"code"`
+	Message string            `json:"message"`
+	Details map[string]string `json:"details,omitempty"`
+}
+
+// Envelope is the JSON body of an error response, {"error": {"code": ..., "message": ...}}
+type Envelope struct {
+	Error Body `json:"error"`
+}
+
+// Body returns what clients are shown of e
+func (e *Error) Body() Body {
+	return Body{Code: e.Code, Message: e.Message, Details: e.Details}
+}
+
+// WriteHTTP logs err and answers it in an Envelope with the status of its code
+func WriteHTTP(w http.ResponseWriter, r *http.Request, err error) {
+	Log(r.Context(), err)
+
+	appErr := As(err)
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(appErr.Code.HTTPStatus())
+	json.NewEncoder(w).Encode(Envelope{Error: appErr.Body()})
+}
+
+// HandlerFunc is an HTTP handler that returns its error instead of answering it, the error
+// is answered with WriteHTTP
+type HandlerFunc func(w http.ResponseWriter, r *http.Request) error
+
+func (f HandlerFunc) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if err := f(w, r); err != nil {
+		WriteHTTP(w, r, err)
+	}
+}
@@ -0,0 +1,123 @@
+// shared/go/pkg/discovery/consul.go
+package discovery
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc/resolver"
+)
+
+// consulWait is how long a blocking query waits for the replicas to change
+const consulWait = 5 * time.Minute
+
+// consulBuilder resolves consul:///service targets to the replicas whose health checks pass,
+// watching them with blocking queries. A tag and a datacenter can be asked for with
+// consul:///user-service?tag=grpc&dc=eu-west-1.
+type consulBuilder struct {
+	config Config
+}
+
+func (b *consulBuilder) Scheme() string {
+	return SchemeConsul
+}
+
+func (b *consulBuilder) Build(target resolver.Target, cc resolver.ClientConn, _ resolver.BuildOptions) (resolver.Resolver, error) {
+	service := target.Endpoint()
+	if service == "" {
+		return nil, fmt.Errorf("consul target needs a service name, e.g. consul:///user-service")
+	}
+	if b.config.ConsulAddr == "" {
+		return nil, fmt.Errorf("consul target %s needs the address of a Consul agent", service)
+	}
+
+	query := url.Values{"passing": {"true"}, "wait": {consulWait.String()}}
+	for _, key := range []string{"tag", "dc"} {
+		if value := target.URL.Query().Get(key); value != "" {
+			query.Set(key, value)
+		}
+	}
+
+	watcher := &consulWatcher{
+		client:  &http.Client{Timeout: consulWait + 30*time.Second},
+		baseURL: strings.TrimSuffix(b.config.ConsulAddr, "/") + "/v1/health/service/" + url.PathEscape(service),
+		query:   query,
+		token:   b.config.ConsulToken,
+	}
+	return newWatchResolver(cc, SchemeConsul+":///"+service, watcher.lookup, 0, b.config), nil
+}
+
+// consulWatcher asks Consul for a service's healthy replicas, each query returning once they
+// differ from what the one before saw
+type consulWatcher struct {
+	client  *http.Client
+	baseURL string
+	query   url.Values
+	token   string
+	index   uint64
+}
+
+type consulServiceEntry struct {
+	Node struct {
+		Address string
+	}
+	Service struct {
+		Address string
+		Port    int
+	}
+}
+
+func (w *consulWatcher) lookup(ctx context.Context) ([]string, error) {
+	query := url.Values{}
+	for key, values := range w.query {
+		query[key] = values
+	}
+	query.Set("index", strconv.FormatUint(w.index, 10))
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, w.baseURL+"?"+query.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+	if w.token != "" {
+		req.Header.Set("X-Consul-Token", w.token)
+	}
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query consul: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("consul returned status %d", resp.StatusCode)
+	}
+
+	var entries []consulServiceEntry
+	if err := json.NewDecoder(resp.Body).Decode(&entries); err != nil {
+		return nil, fmt.Errorf("failed to decode consul response: %w", err)
+	}
+
+	// An index that went backwards, e.g. after a Consul restart, starts over
+	index, _ := strconv.ParseUint(resp.Header.Get("X-Consul-Index"), 10, 64)
+	if index < w.index {
+		index = 0
+	}
+	w.index = index
+
+	addresses := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		host := entry.Service.Address
+		if host == "" {
+			host = entry.Node.Address
+		}
+		addresses = append(addresses, net.JoinHostPort(host, strconv.Itoa(entry.Service.Port)))
+	}
+	return addresses, nil
+}
@@ -0,0 +1,203 @@
+// shared/go/pkg/discovery/discovery.go
+package discovery
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"log/slog"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	_ "google.golang.org/grpc/health" // client side health checking
+	"google.golang.org/grpc/resolver"
+)
+
+const (
+	SchemeDNS    = "dns"
+	SchemeConsul = "consul"
+
+	defaultRefreshInterval = 30 * time.Second
+	errorBackoff           = 5 * time.Second
+)
+
+// Config sets how the replicas of a gRPC service are found and balanced
+type Config struct {
+	ConsulAddr  string // HTTP API of the Consul agent, for consul:/// addresses
+	ConsulToken string
+
+	// RefreshInterval is how often DNS names are resolved again, Consul pushes its changes
+	RefreshInterval time.Duration
+
+	// SubsetSize connects to at most this many replicas, picked by rendezvous hashing of
+	// ClientID so clients spread evenly and keep their subset as replicas come and go. 0 uses
+	// every replica.
+	SubsetSize int
+	ClientID   string // the host name when empty
+
+	// HealthCheck asks every replica's grpc.health.v1 service and sends nothing to those
+	// that aren't serving. Replicas without the health service count as serving.
+	HealthCheck bool
+}
+
+// Target turns a service address into a gRPC target. A host:port is resolved through DNS,
+// so every address a name has is used, e.g. a headless Kubernetes service. Addresses with
+// a scheme are used as they are, e.g. consul:///user-service?tag=grpc.
+func Target(address string) string {
+	if strings.Contains(address, "://") {
+		return address
+	}
+	return SchemeDNS + ":///" + address
+}
+
+// DialOptions returns the dial options that resolve targets with cfg and balance calls
+// round robin over the healthy replicas
+func DialOptions(cfg Config) []grpc.DialOption {
+	if cfg.RefreshInterval <= 0 {
+		cfg.RefreshInterval = defaultRefreshInterval
+	}
+	if cfg.ClientID == "" {
+		cfg.ClientID, _ = os.Hostname()
+	}
+
+	return []grpc.DialOption{
+		grpc.WithResolvers(&dnsBuilder{config: cfg}, &consulBuilder{config: cfg}),
+		grpc.WithDefaultServiceConfig(serviceConfig(cfg.HealthCheck)),
+	}
+}
+
+func serviceConfig(healthCheck bool) string {
+	if healthCheck {
+		return `{"loadBalancingConfig":[{"round_robin":{}}],"healthCheckConfig":{"serviceName":""}}`
+	}
+	return `{"loadBalancingConfig":[{"round_robin":{}}]}`
+}
+
+// lookupFunc returns the addresses of a service's replicas. It may block until they change,
+// the way Consul's blocking queries do.
+type lookupFunc func(ctx context.Context) ([]string, error)
+
+// watchResolver looks a service up again and again, every interval or when gRPC asks, and
+// hands the subset of addresses this client uses to gRPC
+type watchResolver struct {
+	cc       resolver.ClientConn
+	target   string
+	lookup   lookupFunc
+	interval time.Duration // between lookups, 0 when the lookup blocks
+	config   Config
+
+	ctx        context.Context
+	cancel     context.CancelFunc
+	resolveNow chan struct{}
+	done       sync.WaitGroup
+}
+
+func newWatchResolver(cc resolver.ClientConn, target string, lookup lookupFunc, interval time.Duration, cfg Config) *watchResolver {
+	ctx, cancel := context.WithCancel(context.Background())
+	r := &watchResolver{
+		cc:         cc,
+		target:     target,
+		lookup:     lookup,
+		interval:   interval,
+		config:     cfg,
+		ctx:        ctx,
+		cancel:     cancel,
+		resolveNow: make(chan struct{}, 1),
+	}
+	r.done.Add(1)
+	go r.run()
+	return r
+}
+
+func (r *watchResolver) ResolveNow(resolver.ResolveNowOptions) {
+	select {
+	case r.resolveNow <- struct{}{}:
+	default:
+	}
+}
+
+func (r *watchResolver) Close() {
+	r.cancel()
+	r.done.Wait()
+}
+
+func (r *watchResolver) run() {
+	defer r.done.Done()
+
+	var current []string
+	for {
+		addresses, err := r.lookup(r.ctx)
+		if r.ctx.Err() != nil {
+			return
+		}
+
+		wait := r.interval
+		switch {
+		case err != nil:
+			r.cc.ReportError(err)
+			wait = errorBackoff
+		case len(addresses) == 0:
+			r.cc.ReportError(fmt.Errorf("no healthy replicas of %s", r.target))
+			wait = errorBackoff
+		default:
+			addresses = subset(addresses, r.config.ClientID, r.config.SubsetSize)
+			if !equal(addresses, current) {
+				current = addresses
+				state := resolver.State{Addresses: make([]resolver.Address, len(addresses))}
+				for i, address := range addresses {
+					state.Addresses[i] = resolver.Address{Addr: address}
+				}
+				if err := r.cc.UpdateState(state); err != nil {
+					slog.Warn("⚠️ gRPC rejected resolved replicas", "target", r.target, "error", err)
+				}
+				slog.Info("🧭 Resolved service replicas", "target", r.target, "replicas", addresses)
+			}
+		}
+
+		if wait <= 0 {
+			continue
+		}
+		timer := time.NewTimer(wait)
+		select {
+		case <-r.ctx.Done():
+			timer.Stop()
+			return
+		case <-r.resolveNow:
+			timer.Stop()
+		case <-timer.C:
+		}
+	}
+}
+
+// subset picks the size addresses that score highest for clientID, sorted. Each address
+// keeps its score as others come and go, so a client's subset only changes where it must.
+func subset(addresses []string, clientID string, size int) []string {
+	picked := append([]string(nil), addresses...)
+	if size > 0 && len(picked) > size {
+		score := func(address string) uint64 {
+			hash := fnv.New64a()
+			hash.Write([]byte(clientID + "/" + address))
+			return hash.Sum64()
+		}
+		sort.Slice(picked, func(i, j int) bool { return score(picked[i]) > score(picked[j]) })
+		picked = picked[:size]
+	}
+	sort.Strings(picked)
+	return picked
+}
+
+func equal(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
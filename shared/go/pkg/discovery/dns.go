@@ -0,0 +1,42 @@
+// shared/go/pkg/discovery/dns.go
+package discovery
+
+import (
+	"context"
+	"fmt"
+	"net"
+
+	"google.golang.org/grpc/resolver"
+)
+
+// dnsBuilder resolves dns:///host:port targets every RefreshInterval, taking the place of
+// gRPC's own DNS resolver so the replicas can be subset
+type dnsBuilder struct {
+	config Config
+}
+
+func (b *dnsBuilder) Scheme() string {
+	return SchemeDNS
+}
+
+func (b *dnsBuilder) Build(target resolver.Target, cc resolver.ClientConn, _ resolver.BuildOptions) (resolver.Resolver, error) {
+	endpoint := target.Endpoint()
+	host, port, err := net.SplitHostPort(endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("invalid dns target %q, expected host:port: %w", endpoint, err)
+	}
+
+	lookup := func(ctx context.Context) ([]string, error) {
+		ips, err := net.DefaultResolver.LookupHost(ctx, host)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve %s: %w", host, err)
+		}
+		addresses := make([]string, len(ips))
+		for i, ip := range ips {
+			addresses[i] = net.JoinHostPort(ip, port)
+		}
+		return addresses, nil
+	}
+
+	return newWatchResolver(cc, endpoint, lookup, b.config.RefreshInterval, b.config), nil
+}
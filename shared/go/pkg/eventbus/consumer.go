@@ -0,0 +1,126 @@
+// shared/go/pkg/eventbus/consumer.go
+package eventbus
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/nats-io/nats.go/jetstream"
+)
+
+// ConsumerOptions describes a durable consumer of a domain's events. Replicas of a service
+// consuming under the same name share the events, each is handled by one of them.
+type ConsumerOptions struct {
+	Name       string          // durable name, e.g. chat-service-raids
+	EventTypes []string        // events to take, every event of the domain when empty
+	AckWait    time.Duration   // how long a handler has before the event is redelivered, 30s when 0
+	MaxDeliver int             // attempts before an event is given up on, 5 when 0
+	Backoff    []time.Duration // delay before each redelivery of a failed event, the last one repeats
+
+	// DeliverNew starts a consumer created now at the next event rather than the oldest kept
+	DeliverNew bool
+
+	// DeadLetter keeps an event that is given up on, with the error of its last attempt.
+	// Without one the event is only logged.
+	DeadLetter func(ctx context.Context, msg jetstream.Msg, attempts int, err error) error
+}
+
+// Handler handles an event. An error has the event redelivered after the backoff unless it
+// is Permanent.
+type Handler func(ctx context.Context, msg jetstream.Msg) error
+
+type permanentError struct{ err error }
+
+func (e permanentError) Error() string { return e.err.Error() }
+func (e permanentError) Unwrap() error { return e.err }
+
+// Permanent marks a handler error as one redelivering won't fix, e.g. an event that doesn't
+// decode, so the event is given up on right away
+func Permanent(err error) error {
+	return permanentError{err: err}
+}
+
+// Consume creates or updates a durable pull consumer on a domain's stream and hands it events
+// until ctx is done. Events are acknowledged once handler returns without an error.
+func Consume(ctx context.Context, js jetstream.JetStream, domain string, opts ConsumerOptions, handler Handler) error {
+	if opts.Name == "" {
+		return errors.New("consumer name is required")
+	}
+	if opts.AckWait <= 0 {
+		opts.AckWait = 30 * time.Second
+	}
+	if opts.MaxDeliver <= 0 {
+		opts.MaxDeliver = 5
+	}
+	if len(opts.Backoff) == 0 {
+		opts.Backoff = []time.Duration{time.Second, 5 * time.Second, 30 * time.Second}
+	}
+
+	cfg := jetstream.ConsumerConfig{
+		Durable:       opts.Name,
+		AckPolicy:     jetstream.AckExplicitPolicy,
+		AckWait:       opts.AckWait,
+		MaxDeliver:    opts.MaxDeliver,
+		DeliverPolicy: jetstream.DeliverAllPolicy,
+	}
+	if opts.DeliverNew {
+		cfg.DeliverPolicy = jetstream.DeliverNewPolicy
+	}
+	for _, eventType := range opts.EventTypes {
+		cfg.FilterSubjects = append(cfg.FilterSubjects, Subject(domain, eventType))
+	}
+
+	consumer, err := js.CreateOrUpdateConsumer(ctx, StreamName(domain), cfg)
+	if err != nil {
+		return fmt.Errorf("failed to create consumer %s: %w", opts.Name, err)
+	}
+
+	consumeCtx, err := consumer.Consume(func(msg jetstream.Msg) {
+		handle(ctx, msg, opts, handler)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to consume %s: %w", StreamName(domain), err)
+	}
+
+	<-ctx.Done()
+	consumeCtx.Drain()
+	<-consumeCtx.Closed()
+	return nil
+}
+
+// handle runs handler on one event and settles it: acknowledged when handled, given up on
+// when the error is permanent or the attempts are used up, redelivered after the backoff
+// otherwise
+func handle(ctx context.Context, msg jetstream.Msg, opts ConsumerOptions, handler Handler) {
+	err := handler(ctx, msg)
+	if err == nil {
+		if ackErr := msg.Ack(); ackErr != nil {
+			slog.Warn("⚠️ Could not acknowledge event", "subject", msg.Subject(), "error", ackErr)
+		}
+		return
+	}
+
+	attempt := 1
+	if metadata, metaErr := msg.Metadata(); metaErr == nil {
+		attempt = int(metadata.NumDelivered)
+	}
+
+	var permanent permanentError
+	if errors.As(err, &permanent) || attempt >= opts.MaxDeliver {
+		slog.Error("❌ Giving up on event", "consumer", opts.Name, "subject", msg.Subject(), "attempts", attempt, "error", err)
+		if opts.DeadLetter != nil {
+			if dlErr := opts.DeadLetter(ctx, msg, attempt, err); dlErr != nil {
+				slog.Error("❌ Could not dead-letter event, it is lost", "consumer", opts.Name, "subject", msg.Subject(), "error", dlErr)
+			}
+		}
+		msg.TermWithReason(err.Error())
+		return
+	}
+
+	delay := opts.Backoff[min(attempt, len(opts.Backoff))-1]
+	slog.Warn("⚠️ Could not handle event, redelivering it", "consumer", opts.Name, "subject", msg.Subject(), "attempt", attempt, "retry_in", delay, "error", err)
+	msg.NakWithDelay(delay)
+}
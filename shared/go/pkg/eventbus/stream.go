@@ -0,0 +1,44 @@
+// shared/go/pkg/eventbus/stream.go
+package eventbus
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/nats-io/nats.go/jetstream"
+)
+
+// StreamOptions sizes the JetStream stream of a domain
+type StreamOptions struct {
+	Replicas   int           // 1 when 0, use 3 on a production cluster
+	MaxAge     time.Duration // how long events are kept, forever when 0
+	Duplicates time.Duration // window events are deduplicated in by ID, 2 minutes when 0
+}
+
+// EnsureStream creates the stream keeping a domain's events, or updates it to opts. Every
+// event of the domain is stored on disk, the oldest are dropped past MaxAge.
+func EnsureStream(ctx context.Context, js jetstream.JetStream, domain string, opts StreamOptions) (jetstream.Stream, error) {
+	if opts.Replicas <= 0 {
+		opts.Replicas = 1
+	}
+	if opts.Duplicates <= 0 {
+		opts.Duplicates = 2 * time.Minute
+	}
+
+	stream, err := js.CreateOrUpdateStream(ctx, jetstream.StreamConfig{
+		Name:       StreamName(domain),
+		Subjects:   []string{DomainSubjects(domain)},
+		Storage:    jetstream.FileStorage,
+		Retention:  jetstream.LimitsPolicy,
+		Discard:    jetstream.DiscardOld,
+		Replicas:   opts.Replicas,
+		MaxAge:     opts.MaxAge,
+		Duplicates: opts.Duplicates,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create stream %s: %w", StreamName(domain), err)
+	}
+
+	return stream, nil
+}
@@ -0,0 +1,75 @@
+// shared/go/pkg/eventbus/subjects.go
+
+// Package eventbus is what the services publishing and consuming platform events over NATS
+// JetStream agree on: the subjects events are published on, the streams keeping them and how
+// consumers take them.
+package eventbus
+
+import (
+	"strings"
+)
+
+// Domains group the events of a service, each domain has its own JetStream stream
+const (
+	DomainStream = "stream" // published by the stream management service
+	DomainChat   = "chat"   // published by the chat service
+)
+
+const subjectRoot = "platform"
+
+// Headers set on every event message
+const (
+	// HeaderMsgID is JetStream's deduplication header, set to the event ID so an event
+	// published twice within the stream's duplicate window is stored once
+	HeaderMsgID = "Nats-Msg-Id"
+	// HeaderKey is what the event is about, e.g. the stream ID, when there is one thing
+	HeaderKey = "Platform-Key"
+)
+
+// Subject names the subject an event type is published on, platform.<domain>.<event type>,
+// e.g. platform.stream.stream_started
+func Subject(domain, eventType string) string {
+	return subjectRoot + "." + domain + "." + subjectToken(eventType)
+}
+
+// StreamEventSubject names the subject of an event of the stream management service
+func StreamEventSubject(eventType string) string {
+	return Subject(DomainStream, eventType)
+}
+
+// ChatEventSubject names the subject of an event of the chat service
+func ChatEventSubject(eventType string) string {
+	return Subject(DomainChat, eventType)
+}
+
+// DomainSubjects is the wildcard matching every event of a domain
+func DomainSubjects(domain string) string {
+	return subjectRoot + "." + domain + ".>"
+}
+
+// StreamName names the JetStream stream keeping a domain's events, e.g. STREAM_EVENTS
+func StreamName(domain string) string {
+	return strings.ToUpper(domain) + "_EVENTS"
+}
+
+// EventType returns the event type a subject was named after, empty for subjects outside
+// the conventions
+func EventType(subject string) string {
+	parts := strings.SplitN(subject, ".", 3)
+	if len(parts) != 3 || parts[0] != subjectRoot {
+		return ""
+	}
+	return parts[2]
+}
+
+// subjectToken keeps an event type to one subject token, dots and wildcards would change
+// what the subject matches
+func subjectToken(eventType string) string {
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case '.', '*', '>', ' ', '\t', '\r', '\n':
+			return '_'
+		}
+		return r
+	}, eventType)
+}
@@ -0,0 +1,65 @@
+// shared/go/pkg/events/bus.go
+package events
+
+import (
+	"context"
+	"encoding/json"
+)
+
+// Bus is the event stream published events go out on, e.g. aws.KinesisPublisher,
+// kafka.Publisher or nats.Publisher. Records are JSON encoded envelopes, a bus that routes
+// or partitions them reads what it needs with RecordRouting.
+type Bus interface {
+	// Publish queues a record without waiting for it to be sent
+	Publish(record []byte) error
+	// SpoolRecord hands a record straight to the spool, for when the bus is known to be down
+	SpoolRecord(record []byte) error
+	// Deliver sends records right away, bypassing the queue. It returns the indexes of the
+	// records the bus didn't take.
+	Deliver(records [][]byte) ([]int, error)
+	// Verify checks that what the bus publishes to exists
+	Verify() error
+	// Mock reports whether the bus is a development stand-in that sends nothing
+	Mock() bool
+	Stats() PublisherStats
+	// Close stops taking records and waits until the queued ones are sent, or ctx is done
+	Close(ctx context.Context) error
+}
+
+// Spool keeps records that couldn't be delivered until they can be replayed
+type Spool interface {
+	Spool(records [][]byte) error
+}
+
+// PublisherStats counts what a bus did since it started
+type PublisherStats struct {
+	Backend   string `json:"backend"`
+	Queued    int    `json:"queued"`
+	Published int64  `json:"published"`
+	Retried   int64  `json:"retried"`
+	Failed    int64  `json:"failed"`  // gave up on and lost
+	Dropped   int64  `json:"dropped"` // turned away by a full queue
+	Spooled   int64  `json:"spooled"` // handed to the spool for replay
+}
+
+// Routing is what a bus routes and partitions an event record by
+type Routing struct {
+	EventID   string
+	EventType string
+	StreamID  string // empty for events that aren't about one stream
+}
+
+// RecordRouting reads the routing of a record, empty for records that aren't envelopes
+func RecordRouting(record []byte) Routing {
+	var envelope struct {
+		EventID   string `json:"event_id"`
+		EventType string `json:"event_type"`
+		Data      struct {
+			StreamID string `json:"stream_id"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(record, &envelope); err != nil {
+		return Routing{}
+	}
+	return Routing{EventID: envelope.EventID, EventType: envelope.EventType, StreamID: envelope.Data.StreamID}
+}
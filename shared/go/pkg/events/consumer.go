@@ -0,0 +1,173 @@
+// shared/go/pkg/events/consumer.go
+package events
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+)
+
+const (
+	defaultMaxAttempts = 3
+	retryDelay         = 200 * time.Millisecond // grows with each attempt
+)
+
+// RecordWriter puts a record on a stream, e.g. aws.KinesisClient
+type RecordWriter interface {
+	PutRecord(data string) error
+}
+
+// DeadLetter is a record a consumer gave up on
+type DeadLetter struct {
+	Consumer  string `json:"consumer"`
+	EventID   string `json:"event_id,omitempty"` // empty for records that aren't envelopes
+	EventType string `json:"event_type,omitempty"`
+	Reason    string `json:"reason"`
+	Record    string `json:"record"`
+	Attempts  int    `json:"attempts"` // 0 when the record was rejected without handling it
+	FailedAt  int64  `json:"failed_at"`
+}
+
+// DeadLetterSink keeps the records consumers gave up on, so they can be inspected and replayed
+type DeadLetterSink interface {
+	DeadLetter(letter DeadLetter) error
+}
+
+// StreamDeadLetters puts dead letters on a stream as JSON
+type StreamDeadLetters struct {
+	Writer RecordWriter
+}
+
+func (s StreamDeadLetters) DeadLetter(letter DeadLetter) error {
+	data, err := json.Marshal(letter)
+	if err != nil {
+		return fmt.Errorf("failed to marshal dead letter: %w", err)
+	}
+	return s.Writer.PutRecord(string(data))
+}
+
+// Consumer validates incoming records before handing them to a handler
+type Consumer struct {
+	name        string
+	registry    *Registry
+	deadLetters DeadLetterSink
+	deduper     Deduper
+	maxAttempts int
+}
+
+// NewConsumer creates a consumer named after what it does with the events, e.g. follows,
+// which is what its dead letters are filed under
+func NewConsumer(registry *Registry, name string, deadLetters DeadLetterSink) *Consumer {
+	return &Consumer{
+		name:        name,
+		registry:    registry,
+		deadLetters: deadLetters,
+		maxAttempts: defaultMaxAttempts,
+	}
+}
+
+// WithDeduper makes the consumer skip events it has already handled, so replaying a
+// shard doesn't apply side effects such as watch minutes or notifications twice
+func (c *Consumer) WithDeduper(deduper Deduper) *Consumer {
+	c.deduper = deduper
+	return c
+}
+
+// WithMaxAttempts sets how many times the handler gets an event before it is dead-lettered
+func (c *Consumer) WithMaxAttempts(attempts int) *Consumer {
+	if attempts > 0 {
+		c.maxAttempts = attempts
+	}
+	return c
+}
+
+// Handle decodes a record and passes it to handler. Records this consumer can't understand
+// (bad envelope, unknown event type or schema version, invalid payload) are moved to the
+// dead letters right away so they don't block the shard. Handler errors are usually
+// transient, the handler is tried again with a growing delay and the record dead-lettered
+// once it has failed every attempt. Without dead letters the last error is returned.
+func (c *Consumer) Handle(record []byte, handler func(*Envelope) error) error {
+	envelope, err := c.registry.Decode(record)
+	if err != nil {
+		slog.Warn("☠️ Rejecting event record", "consumer", c.name, "reason", err)
+		if c.deadLetters == nil {
+			return nil
+		}
+		return c.deadLetter(record, nil, 0, err)
+	}
+
+	attempt := 1
+	for ; ; attempt++ {
+		err = c.handle(envelope, handler)
+		if err == nil || errors.Is(err, ErrEventInProgress) {
+			return err
+		}
+		if attempt >= c.maxAttempts {
+			break
+		}
+		time.Sleep(time.Duration(attempt) * retryDelay)
+	}
+
+	if c.deadLetters == nil {
+		return err
+	}
+	slog.Warn("☠️ Giving up on event", "consumer", c.name, "event_type", envelope.EventType, "event_id", envelope.EventID, "attempts", attempt, "error", err)
+	return c.deadLetter(record, envelope, attempt, err)
+}
+
+// Replay hands a dead-lettered record to handler once, for an admin retrying it. Unlike
+// Handle it returns every failure instead of dead-lettering the record again.
+func (c *Consumer) Replay(record []byte, handler func(*Envelope) error) error {
+	envelope, err := c.registry.Decode(record)
+	if err != nil {
+		return err
+	}
+	return c.handle(envelope, handler)
+}
+
+// handle runs handler on an event once, skipping it when the deduper says it was handled
+func (c *Consumer) handle(envelope *Envelope, handler func(*Envelope) error) error {
+	if c.deduper == nil {
+		return handler(envelope)
+	}
+
+	claimed, err := c.deduper.Claim(envelope.EventID)
+	if err != nil {
+		return err
+	}
+	if !claimed {
+		slog.Debug("🔁 Skipping duplicate event", "event_type", envelope.EventType, "event_id", envelope.EventID)
+		return nil
+	}
+
+	if err := handler(envelope); err != nil {
+		if releaseErr := c.deduper.Release(envelope.EventID); releaseErr != nil {
+			slog.Warn("⚠️ Could not release event", "event_id", envelope.EventID, "error", releaseErr)
+		}
+		return err
+	}
+
+	return c.deduper.Complete(envelope.EventID)
+}
+
+func (c *Consumer) deadLetter(record []byte, envelope *Envelope, attempts int, reason error) error {
+	letter := DeadLetter{
+		Consumer: c.name,
+		Reason:   reason.Error(),
+		Record:   string(record),
+		Attempts: attempts,
+		FailedAt: time.Now().Unix(),
+	}
+	if envelope != nil {
+		letter.EventID = envelope.EventID
+		letter.EventType = envelope.EventType
+	}
+
+	if err := c.deadLetters.DeadLetter(letter); err != nil {
+		return fmt.Errorf("failed to write dead letter: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,83 @@
+// shared/go/pkg/events/consumer_test.go
+package events
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+)
+
+func newTestRecord(t *testing.T, registry *Registry) []byte {
+	t.Helper()
+
+	envelope, err := registry.NewEnvelope("user-service", "user_followed", map[string]int64{"follower_id": 1, "channel_id": 2})
+	if err != nil {
+		t.Fatalf("NewEnvelope: %v", err)
+	}
+	record, err := json.Marshal(envelope)
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	return record
+}
+
+func newTestConsumer(t *testing.T) (*Consumer, *Registry) {
+	t.Helper()
+
+	registry, err := NewRegistry()
+	if err != nil {
+		t.Fatalf("NewRegistry: %v", err)
+	}
+	deduper := NewDynamoDBDeduper(newFakeDedupeTable(), "dedupe", "follows", time.Minute, time.Hour)
+	return NewConsumer(registry, "follows", nil).WithDeduper(deduper).WithMaxAttempts(1), registry
+}
+
+func TestConsumerSkipsReplayedEvents(t *testing.T) {
+	consumer, registry := newTestConsumer(t)
+	record := newTestRecord(t, registry)
+
+	handled := 0
+	handler := func(*Envelope) error {
+		handled++
+		return nil
+	}
+	for i := 0; i < 3; i++ {
+		if err := consumer.Handle(record, handler); err != nil {
+			t.Fatalf("Handle #%d: %v", i+1, err)
+		}
+	}
+	if handled != 1 {
+		t.Errorf("handled the event %d times, want once", handled)
+	}
+
+	// Another event is handled
+	if err := consumer.Handle(newTestRecord(t, registry), handler); err != nil {
+		t.Fatalf("Handle: %v", err)
+	}
+	if handled != 2 {
+		t.Errorf("handled %d events, want 2", handled)
+	}
+}
+
+func TestConsumerRetriesFailedEvents(t *testing.T) {
+	consumer, registry := newTestConsumer(t)
+	record := newTestRecord(t, registry)
+
+	failure := errors.New("user service unavailable")
+	if err := consumer.Handle(record, func(*Envelope) error { return failure }); !errors.Is(err, failure) {
+		t.Fatalf("Handle = %v, want %v", err, failure)
+	}
+
+	// The failed attempt released its claim, so the replay handles the event
+	handled := 0
+	if err := consumer.Handle(record, func(*Envelope) error {
+		handled++
+		return nil
+	}); err != nil {
+		t.Fatalf("replayed Handle: %v", err)
+	}
+	if handled != 1 {
+		t.Errorf("handled the replayed event %d times, want once", handled)
+	}
+}
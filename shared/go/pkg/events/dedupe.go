@@ -0,0 +1,170 @@
+// shared/go/pkg/events/dedupe.go
+package events
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbiface"
+)
+
+// DedupeTTLAttribute is the attribute DynamoDB expires the claims of a dedupe table by
+const DedupeTTLAttribute = "expires_at"
+
+// ErrEventInProgress means another worker is handling the event right now
+var ErrEventInProgress = errors.New("event is being processed by another worker")
+
+// Deduper remembers which events a consumer has already handled
+type Deduper interface {
+	// Claim reserves an event for this worker. It returns false if the event was already handled.
+	Claim(eventID string) (bool, error)
+	// Complete marks a claimed event as handled
+	Complete(eventID string) error
+	// Release gives up a claim so a retry can handle the event
+	Release(eventID string) error
+}
+
+// DynamoDBDeduper stores claims in a table keyed by "dedupe_key" (string), with "expires_at"
+// as its TTL attribute. Several consumers can share a table since keys are prefixed with the
+// consumer name.
+type DynamoDBDeduper struct {
+	client    dynamodbiface.DynamoDBAPI
+	tableName string
+	consumer  string
+	lease     time.Duration // how long a claim blocks other workers before it's considered abandoned
+	retention time.Duration // how long handled events are remembered, should exceed the stream retention
+}
+
+func NewDynamoDBDeduper(client dynamodbiface.DynamoDBAPI, tableName, consumer string, lease, retention time.Duration) *DynamoDBDeduper {
+	return &DynamoDBDeduper{
+		client:    client,
+		tableName: tableName,
+		consumer:  consumer,
+		lease:     lease,
+		retention: retention,
+	}
+}
+
+// DedupeTableDefinition is the table a DynamoDBDeduper stores its claims in, its TTL attribute
+// is DedupeTTLAttribute
+func DedupeTableDefinition(tableName string) *dynamodb.CreateTableInput {
+	return &dynamodb.CreateTableInput{
+		TableName: aws.String(tableName),
+		KeySchema: []*dynamodb.KeySchemaElement{
+			{
+				AttributeName: aws.String("dedupe_key"),
+				KeyType:       aws.String("HASH"),
+			},
+		},
+		AttributeDefinitions: []*dynamodb.AttributeDefinition{
+			{
+				AttributeName: aws.String("dedupe_key"),
+				AttributeType: aws.String("S"),
+			},
+		},
+		BillingMode: aws.String("PAY_PER_REQUEST"),
+	}
+}
+
+func (d *DynamoDBDeduper) Claim(eventID string) (bool, error) {
+	now := time.Now()
+
+	_, err := d.client.PutItem(&dynamodb.PutItemInput{
+		TableName: aws.String(d.tableName),
+		Item: map[string]*dynamodb.AttributeValue{
+			"dedupe_key":  {S: aws.String(d.key(eventID))},
+			"status":      {S: aws.String("processing")},
+			"lease_until": {N: aws.String(strconv.FormatInt(now.Add(d.lease).Unix(), 10))},
+			"expires_at":  {N: aws.String(strconv.FormatInt(now.Add(d.retention).Unix(), 10))},
+		},
+		// Take over claims left behind by a worker that died mid-event
+		ConditionExpression: aws.String("attribute_not_exists(dedupe_key) OR (#status = :processing AND lease_until < :now)"),
+		ExpressionAttributeNames: map[string]*string{
+			"#status": aws.String("status"),
+		},
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+			":processing": {S: aws.String("processing")},
+			":now":        {N: aws.String(strconv.FormatInt(now.Unix(), 10))},
+		},
+	})
+	if err == nil {
+		return true, nil
+	}
+
+	if aerr, ok := err.(awserr.Error); !ok || aerr.Code() != dynamodb.ErrCodeConditionalCheckFailedException {
+		return false, fmt.Errorf("failed to claim event: %w", err)
+	}
+
+	status, err := d.status(eventID)
+	if err != nil {
+		return false, err
+	}
+	if status == "processing" {
+		return false, ErrEventInProgress
+	}
+
+	return false, nil
+}
+
+func (d *DynamoDBDeduper) Complete(eventID string) error {
+	_, err := d.client.UpdateItem(&dynamodb.UpdateItemInput{
+		TableName: aws.String(d.tableName),
+		Key: map[string]*dynamodb.AttributeValue{
+			"dedupe_key": {S: aws.String(d.key(eventID))},
+		},
+		UpdateExpression: aws.String("SET #status = :done REMOVE lease_until"),
+		ExpressionAttributeNames: map[string]*string{
+			"#status": aws.String("status"),
+		},
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+			":done": {S: aws.String("done")},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to complete event: %w", err)
+	}
+
+	return nil
+}
+
+func (d *DynamoDBDeduper) Release(eventID string) error {
+	_, err := d.client.DeleteItem(&dynamodb.DeleteItemInput{
+		TableName: aws.String(d.tableName),
+		Key: map[string]*dynamodb.AttributeValue{
+			"dedupe_key": {S: aws.String(d.key(eventID))},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to release event: %w", err)
+	}
+
+	return nil
+}
+
+func (d *DynamoDBDeduper) status(eventID string) (string, error) {
+	result, err := d.client.GetItem(&dynamodb.GetItemInput{
+		TableName:      aws.String(d.tableName),
+		ConsistentRead: aws.Bool(true),
+		Key: map[string]*dynamodb.AttributeValue{
+			"dedupe_key": {S: aws.String(d.key(eventID))},
+		},
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to get event claim: %w", err)
+	}
+	if result.Item == nil || result.Item["status"] == nil {
+		// The claim was released in between, let the caller retry
+		return "processing", nil
+	}
+
+	return aws.StringValue(result.Item["status"].S), nil
+}
+
+func (d *DynamoDBDeduper) key(eventID string) string {
+	return d.consumer + "#" + eventID
+}
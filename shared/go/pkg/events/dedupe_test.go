@@ -0,0 +1,154 @@
+// shared/go/pkg/events/dedupe_test.go
+package events
+
+import (
+	"errors"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbiface"
+)
+
+// fakeDedupeTable keeps the items of a dedupe table in memory. PutItem applies the claim
+// condition of DynamoDBDeduper.Claim, other calls panic through the nil interface.
+type fakeDedupeTable struct {
+	dynamodbiface.DynamoDBAPI
+
+	mu    sync.Mutex
+	items map[string]map[string]*dynamodb.AttributeValue
+}
+
+func newFakeDedupeTable() *fakeDedupeTable {
+	return &fakeDedupeTable{items: make(map[string]map[string]*dynamodb.AttributeValue)}
+}
+
+func (f *fakeDedupeTable) PutItem(input *dynamodb.PutItemInput) (*dynamodb.PutItemOutput, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	key := aws.StringValue(input.Item["dedupe_key"].S)
+	if existing, ok := f.items[key]; ok {
+		abandoned := false
+		if lease := existing["lease_until"]; lease != nil && aws.StringValue(existing["status"].S) == "processing" {
+			now, _ := strconv.ParseInt(aws.StringValue(input.ExpressionAttributeValues[":now"].N), 10, 64)
+			leaseUntil, _ := strconv.ParseInt(aws.StringValue(lease.N), 10, 64)
+			abandoned = leaseUntil < now
+		}
+		if !abandoned {
+			return nil, awserr.New(dynamodb.ErrCodeConditionalCheckFailedException, "the conditional request failed", nil)
+		}
+	}
+
+	f.items[key] = input.Item
+	return &dynamodb.PutItemOutput{}, nil
+}
+
+func (f *fakeDedupeTable) UpdateItem(input *dynamodb.UpdateItemInput) (*dynamodb.UpdateItemOutput, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	key := aws.StringValue(input.Key["dedupe_key"].S)
+	item := f.items[key]
+	if item == nil {
+		item = map[string]*dynamodb.AttributeValue{"dedupe_key": input.Key["dedupe_key"]}
+		f.items[key] = item
+	}
+	item["status"] = input.ExpressionAttributeValues[":done"]
+	delete(item, "lease_until")
+	return &dynamodb.UpdateItemOutput{}, nil
+}
+
+func (f *fakeDedupeTable) DeleteItem(input *dynamodb.DeleteItemInput) (*dynamodb.DeleteItemOutput, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	delete(f.items, aws.StringValue(input.Key["dedupe_key"].S))
+	return &dynamodb.DeleteItemOutput{}, nil
+}
+
+func (f *fakeDedupeTable) GetItem(input *dynamodb.GetItemInput) (*dynamodb.GetItemOutput, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return &dynamodb.GetItemOutput{Item: f.items[aws.StringValue(input.Key["dedupe_key"].S)]}, nil
+}
+
+func claim(t *testing.T, deduper *DynamoDBDeduper, eventID string) (bool, error) {
+	t.Helper()
+	claimed, err := deduper.Claim(eventID)
+	if err != nil && !errors.Is(err, ErrEventInProgress) {
+		t.Fatalf("Claim(%s): %v", eventID, err)
+	}
+	return claimed, err
+}
+
+func TestDynamoDBDeduperClaimComplete(t *testing.T) {
+	deduper := NewDynamoDBDeduper(newFakeDedupeTable(), "dedupe", "follows", time.Minute, time.Hour)
+
+	if claimed, _ := claim(t, deduper, "evt_1"); !claimed {
+		t.Fatal("first claim was refused")
+	}
+	if claimed, err := claim(t, deduper, "evt_1"); claimed || !errors.Is(err, ErrEventInProgress) {
+		t.Errorf("claim while processing = %v, %v, want false, ErrEventInProgress", claimed, err)
+	}
+
+	if err := deduper.Complete("evt_1"); err != nil {
+		t.Fatalf("Complete: %v", err)
+	}
+	if claimed, err := claim(t, deduper, "evt_1"); claimed || err != nil {
+		t.Errorf("claim of a handled event = %v, %v, want false, nil", claimed, err)
+	}
+
+	if claimed, _ := claim(t, deduper, "evt_2"); !claimed {
+		t.Error("claim of another event was refused")
+	}
+}
+
+func TestDynamoDBDeduperRelease(t *testing.T) {
+	deduper := NewDynamoDBDeduper(newFakeDedupeTable(), "dedupe", "follows", time.Minute, time.Hour)
+
+	if claimed, _ := claim(t, deduper, "evt_1"); !claimed {
+		t.Fatal("first claim was refused")
+	}
+	if err := deduper.Release("evt_1"); err != nil {
+		t.Fatalf("Release: %v", err)
+	}
+	if claimed, _ := claim(t, deduper, "evt_1"); !claimed {
+		t.Error("claim after a release was refused")
+	}
+}
+
+func TestDynamoDBDeduperTakesOverAbandonedClaim(t *testing.T) {
+	table := newFakeDedupeTable()
+	// The lease is over as soon as the claim is made
+	abandoning := NewDynamoDBDeduper(table, "dedupe", "follows", -time.Minute, time.Hour)
+	if claimed, _ := claim(t, abandoning, "evt_1"); !claimed {
+		t.Fatal("first claim was refused")
+	}
+
+	deduper := NewDynamoDBDeduper(table, "dedupe", "follows", time.Minute, time.Hour)
+	if claimed, _ := claim(t, deduper, "evt_1"); !claimed {
+		t.Error("claim of an abandoned event was refused")
+	}
+}
+
+func TestDynamoDBDeduperKeysByConsumer(t *testing.T) {
+	table := newFakeDedupeTable()
+	follows := NewDynamoDBDeduper(table, "dedupe", "follows", time.Minute, time.Hour)
+	alerts := NewDynamoDBDeduper(table, "dedupe", "alerts", time.Minute, time.Hour)
+
+	if claimed, _ := claim(t, follows, "evt_1"); !claimed {
+		t.Fatal("first claim was refused")
+	}
+	if err := follows.Complete("evt_1"); err != nil {
+		t.Fatalf("Complete: %v", err)
+	}
+	if claimed, _ := claim(t, alerts, "evt_1"); !claimed {
+		t.Error("another consumer's claim of the event was refused")
+	}
+}
@@ -0,0 +1,77 @@
+// shared/go/pkg/events/envelope.go
+
+// Package events is the wire format of platform events and the schemas they are validated
+// against. Every record on the event stream is a JSON encoded Envelope whose Data is the
+// payload, never a bare payload:
+//
+//	{"event_id": "evt_...", "event_type": "raid", "schema_version": 1,
+//	 "source": "stream-management-service", "timestamp": 1705314600, "data": {...}}
+//
+// Publishers wrap payloads with Registry.NewEnvelope, consumers decode records with a
+// Consumer, which dead-letters those of unknown event types or schema versions.
+package events
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// Envelope wraps every event put on the event stream. Consumers pick the schema
+// to decode Data with from EventType and SchemaVersion.
+type Envelope struct {
+	EventID       string          `json:"event_id"`
+	EventType     string          `json:"event_type"`
+	SchemaVersion int             `json:"schema_version"`
+	Source        string          `json:"source"`
+	Timestamp     int64           `json:"timestamp"`
+	Data          json.RawMessage `json:"data"`
+}
+
+// NewEnvelope validates data against the latest schema of an event type and wraps it
+func (r *Registry) NewEnvelope(source, eventType string, data interface{}) (*Envelope, error) {
+	version, err := r.Latest(eventType)
+	if err != nil {
+		return nil, err
+	}
+
+	encoded, err := json.Marshal(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal %s event: %w", eventType, err)
+	}
+
+	if err := r.Validate(eventType, version, encoded); err != nil {
+		return nil, err
+	}
+
+	return &Envelope{
+		EventID:       newEventID(),
+		EventType:     eventType,
+		SchemaVersion: version,
+		Source:        source,
+		Timestamp:     time.Now().Unix(),
+		Data:          encoded,
+	}, nil
+}
+
+// Decode parses an event record and validates its payload against the schema version it was published with
+func (r *Registry) Decode(record []byte) (*Envelope, error) {
+	var envelope Envelope
+	if err := json.Unmarshal(record, &envelope); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal event envelope: %w", err)
+	}
+
+	if err := r.Validate(envelope.EventType, envelope.SchemaVersion, envelope.Data); err != nil {
+		return nil, err
+	}
+
+	return &envelope, nil
+}
+
+func newEventID() string {
+	bytes := make([]byte, 16)
+	rand.Read(bytes)
+	return "evt_" + hex.EncodeToString(bytes)
+}
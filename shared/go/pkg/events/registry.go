@@ -0,0 +1,199 @@
+// shared/go/pkg/events/registry.go
+package events
+
+import (
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+)
+
+// Schemas are JSON Schema documents named <event_type>.v<version>.json. Only the subset
+// needed for flat event payloads is supported: type, properties, required and
+// additionalProperties. Published versions must never change; add a new version instead.
+//
+//go:embed schemas/*.json
+var schemaFiles embed.FS
+
+var schemaFileName = regexp.MustCompile(`^([a-z0-9_]+)\.v([0-9]+)\.json$`)
+
+var (
+	ErrUnknownEventType     = errors.New("unknown event type")
+	ErrUnknownSchemaVersion = errors.New("unknown schema version")
+)
+
+type Schema struct {
+	ID                   string              `json:"$id"`
+	Title                string              `json:"title"`
+	Description          string              `json:"description"`
+	Type                 string              `json:"type"`
+	Properties           map[string]Property `json:"properties"`
+	Required             []string            `json:"required"`
+	AdditionalProperties *bool               `json:"additionalProperties"`
+
+	EventType string `json:"-"`
+	Version   int    `json:"-"`
+}
+
+type Property struct {
+	Type        string `json:"type"`
+	Description string `json:"description,omitempty"`
+}
+
+// Registry holds every known version of every event schema
+type Registry struct {
+	schemas map[string]map[int]*Schema
+	latest  map[string]int
+}
+
+// NewRegistry loads the schemas embedded in this package
+func NewRegistry() (*Registry, error) {
+	entries, err := schemaFiles.ReadDir("schemas")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read event schemas: %w", err)
+	}
+
+	r := &Registry{
+		schemas: make(map[string]map[int]*Schema),
+		latest:  make(map[string]int),
+	}
+
+	for _, entry := range entries {
+		match := schemaFileName.FindStringSubmatch(entry.Name())
+		if match == nil {
+			return nil, fmt.Errorf("event schema %s is not named <event_type>.v<version>.json", entry.Name())
+		}
+		version, _ := strconv.Atoi(match[2])
+
+		raw, err := schemaFiles.ReadFile(path.Join("schemas", entry.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read event schema %s: %w", entry.Name(), err)
+		}
+
+		var schema Schema
+		if err := json.Unmarshal(raw, &schema); err != nil {
+			return nil, fmt.Errorf("failed to parse event schema %s: %w", entry.Name(), err)
+		}
+		if schema.Type != "object" {
+			return nil, fmt.Errorf("event schema %s must describe an object", entry.Name())
+		}
+		schema.EventType = match[1]
+		schema.Version = version
+
+		if r.schemas[schema.EventType] == nil {
+			r.schemas[schema.EventType] = make(map[int]*Schema)
+		}
+		r.schemas[schema.EventType][version] = &schema
+		if version > r.latest[schema.EventType] {
+			r.latest[schema.EventType] = version
+		}
+	}
+
+	return r, nil
+}
+
+// EventTypes lists the known event types
+func (r *Registry) EventTypes() []string {
+	types := make([]string, 0, len(r.schemas))
+	for eventType := range r.schemas {
+		types = append(types, eventType)
+	}
+	sort.Strings(types)
+	return types
+}
+
+// Latest returns the version producers should publish for an event type
+func (r *Registry) Latest(eventType string) (int, error) {
+	version, ok := r.latest[eventType]
+	if !ok {
+		return 0, fmt.Errorf("%w: %s", ErrUnknownEventType, eventType)
+	}
+	return version, nil
+}
+
+func (r *Registry) Schema(eventType string, version int) (*Schema, error) {
+	versions, ok := r.schemas[eventType]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownEventType, eventType)
+	}
+	schema, ok := versions[version]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s v%d", ErrUnknownSchemaVersion, eventType, version)
+	}
+	return schema, nil
+}
+
+// Validate checks an encoded payload against a schema version
+func (r *Registry) Validate(eventType string, version int, data []byte) error {
+	schema, err := r.Schema(eventType, version)
+	if err != nil {
+		return err
+	}
+	return schema.Validate(data)
+}
+
+// Validate checks an encoded payload. Null fields are treated as absent.
+func (s *Schema) Validate(data []byte) error {
+	var payload map[string]interface{}
+	if err := json.Unmarshal(data, &payload); err != nil {
+		return fmt.Errorf("%s payload must be a JSON object: %w", s.ID, err)
+	}
+
+	for _, field := range s.Required {
+		if payload[field] == nil {
+			return fmt.Errorf("%s payload is missing required field %q", s.ID, field)
+		}
+	}
+
+	for field, value := range payload {
+		if value == nil {
+			continue
+		}
+
+		property, ok := s.Properties[field]
+		if !ok {
+			if s.AdditionalProperties != nil && !*s.AdditionalProperties {
+				return fmt.Errorf("%s payload has unknown field %q", s.ID, field)
+			}
+			continue
+		}
+
+		if !hasType(value, property.Type) {
+			return fmt.Errorf("%s payload field %q must be of type %s", s.ID, field, property.Type)
+		}
+	}
+
+	return nil
+}
+
+func hasType(value interface{}, jsonType string) bool {
+	switch jsonType {
+	case "", "any":
+		return true
+	case "string":
+		_, ok := value.(string)
+		return ok
+	case "boolean":
+		_, ok := value.(bool)
+		return ok
+	case "number":
+		_, ok := value.(float64)
+		return ok
+	case "integer":
+		number, ok := value.(float64)
+		return ok && number == math.Trunc(number)
+	case "object":
+		_, ok := value.(map[string]interface{})
+		return ok
+	case "array":
+		_, ok := value.([]interface{})
+		return ok
+	default:
+		return false
+	}
+}
@@ -0,0 +1,150 @@
+// shared/go/pkg/globaltables/client.go
+package globaltables
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"sync/atomic"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+)
+
+// WriteLocal makes every region write to its own replica
+const WriteLocal = "local"
+
+// Config says where the replicas of a service's DynamoDB global tables are
+type Config struct {
+	LocalRegion string   // region this instance runs in, reads go here first
+	Regions     []string // regions the tables are replicated to, the local one when empty
+	WriteRegion string   // region all writes go to, or WriteLocal
+}
+
+// Client talks to the replicas of DynamoDB global tables. It is used like a
+// *dynamodb.DynamoDB: writes and every other operation go to the write region, reads go to
+// the local replica and fall back to the other regions when it can't answer.
+//
+// Global tables resolve concurrent writes to an item in different regions by keeping the last
+// one, so with WriteLocal a write can silently undo one made elsewhere at the same time.
+// Writing to a single region avoids that at the price of cross region latency, and of writes
+// failing while that region is down until WriteRegion is changed. Reads from another region's
+// replica may miss writes made in the last second or so, strongly consistent reads are always
+// sent to the write region.
+type Client struct {
+	*dynamodb.DynamoDB // the write region's
+
+	writeRegion string
+	readers     []replica // the local region's first
+
+	fallbacks atomic.Int64
+}
+
+type replica struct {
+	region string
+	db     *dynamodb.DynamoDB
+}
+
+// New creates a client for the regions of cfg, each with base's settings. When base has an
+// endpoint, e.g. DynamoDB Local, there is only that one replica and every call goes to it.
+func New(cfg Config, base *aws.Config) (*Client, error) {
+	if cfg.LocalRegion == "" {
+		cfg.LocalRegion = aws.StringValue(base.Region)
+	}
+	if cfg.WriteRegion == "" || cfg.WriteRegion == WriteLocal {
+		cfg.WriteRegion = cfg.LocalRegion
+	}
+
+	// The local region is read first, the rest in the order they are listed
+	regions := []string{cfg.LocalRegion}
+	for _, region := range cfg.Regions {
+		if !slices.Contains(regions, region) {
+			regions = append(regions, region)
+		}
+	}
+	if !slices.Contains(regions, cfg.WriteRegion) {
+		return nil, fmt.Errorf("write region %s is not one of the table regions %v", cfg.WriteRegion, regions)
+	}
+	if aws.StringValue(base.Endpoint) != "" {
+		regions = regions[:1]
+		cfg.WriteRegion = cfg.LocalRegion
+	}
+
+	c := &Client{writeRegion: cfg.WriteRegion}
+	for _, region := range regions {
+		sess, err := session.NewSession(base.Copy(&aws.Config{Region: aws.String(region)}))
+		if err != nil {
+			return nil, fmt.Errorf("failed to create AWS session for %s: %w", region, err)
+		}
+		db := dynamodb.New(sess)
+		c.readers = append(c.readers, replica{region: region, db: db})
+		if region == cfg.WriteRegion {
+			c.DynamoDB = db
+		}
+	}
+	return c, nil
+}
+
+// Instrument lets fn add handlers to the client of every region, e.g. for tracing
+func (c *Client) Instrument(fn func(*request.Handlers)) {
+	for _, r := range c.readers {
+		fn(&r.db.Handlers)
+	}
+}
+
+// Status tells where a client reads and writes, shown on health endpoints
+type Status struct {
+	WriteRegion string   `json:"write_region"`
+	ReadRegions []string `json:"read_regions"` // in the order they are tried
+	Fallbacks   int64    `json:"fallbacks"`    // reads answered by a region other than the first
+}
+
+func (c *Client) Status() Status {
+	status := Status{WriteRegion: c.writeRegion, Fallbacks: c.fallbacks.Load()}
+	for _, r := range c.readers {
+		status.ReadRegions = append(status.ReadRegions, r.region)
+	}
+	return status
+}
+
+// read tries the replicas in order until one answers. Only failures another region could
+// do better on are retried elsewhere, a missing table or a bad request fail right away.
+func read[T any](ctx context.Context, c *Client, consistent bool, call func(db *dynamodb.DynamoDB) (T, error)) (T, error) {
+	if consistent {
+		return call(c.DynamoDB)
+	}
+
+	var result T
+	var err error
+	for i, r := range c.readers {
+		result, err = call(r.db)
+		if err == nil {
+			if i > 0 {
+				c.fallbacks.Add(1)
+			}
+			return result, nil
+		}
+		if !regionalFailure(ctx, err) || i == len(c.readers)-1 {
+			break
+		}
+		slog.WarnContext(ctx, "⚠️ DynamoDB read failed, trying next region", "region", r.region, "next_region", c.readers[i+1].region, "error", err)
+	}
+	return result, err
+}
+
+func regionalFailure(ctx context.Context, err error) bool {
+	var pe *pageError
+	if errors.As(err, &pe) || ctx.Err() != nil {
+		return false
+	}
+	var failure awserr.RequestFailure
+	if errors.As(err, &failure) && failure.StatusCode() >= 500 {
+		return true
+	}
+	return request.IsErrorRetryable(err) || request.IsErrorThrottle(err)
+}
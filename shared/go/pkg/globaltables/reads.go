@@ -0,0 +1,110 @@
+// shared/go/pkg/globaltables/reads.go
+package globaltables
+
+import (
+	"context"
+	"errors"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+)
+
+// The read operations of *dynamodb.DynamoDB, sent to the local replica first
+
+func (c *Client) GetItem(input *dynamodb.GetItemInput) (*dynamodb.GetItemOutput, error) {
+	return c.GetItemWithContext(context.Background(), input)
+}
+
+func (c *Client) GetItemWithContext(ctx aws.Context, input *dynamodb.GetItemInput, opts ...request.Option) (*dynamodb.GetItemOutput, error) {
+	return read(ctx, c, aws.BoolValue(input.ConsistentRead), func(db *dynamodb.DynamoDB) (*dynamodb.GetItemOutput, error) {
+		return db.GetItemWithContext(ctx, input, opts...)
+	})
+}
+
+func (c *Client) BatchGetItem(input *dynamodb.BatchGetItemInput) (*dynamodb.BatchGetItemOutput, error) {
+	return c.BatchGetItemWithContext(context.Background(), input)
+}
+
+func (c *Client) BatchGetItemWithContext(ctx aws.Context, input *dynamodb.BatchGetItemInput, opts ...request.Option) (*dynamodb.BatchGetItemOutput, error) {
+	consistent := false
+	for _, keys := range input.RequestItems {
+		consistent = consistent || aws.BoolValue(keys.ConsistentRead)
+	}
+	return read(ctx, c, consistent, func(db *dynamodb.DynamoDB) (*dynamodb.BatchGetItemOutput, error) {
+		return db.BatchGetItemWithContext(ctx, input, opts...)
+	})
+}
+
+func (c *Client) Query(input *dynamodb.QueryInput) (*dynamodb.QueryOutput, error) {
+	return c.QueryWithContext(context.Background(), input)
+}
+
+func (c *Client) QueryWithContext(ctx aws.Context, input *dynamodb.QueryInput, opts ...request.Option) (*dynamodb.QueryOutput, error) {
+	return read(ctx, c, aws.BoolValue(input.ConsistentRead), func(db *dynamodb.DynamoDB) (*dynamodb.QueryOutput, error) {
+		return db.QueryWithContext(ctx, input, opts...)
+	})
+}
+
+func (c *Client) QueryPages(input *dynamodb.QueryInput, fn func(*dynamodb.QueryOutput, bool) bool) error {
+	return c.QueryPagesWithContext(context.Background(), input, fn)
+}
+
+func (c *Client) QueryPagesWithContext(ctx aws.Context, input *dynamodb.QueryInput, fn func(*dynamodb.QueryOutput, bool) bool, opts ...request.Option) error {
+	return readPages(ctx, c, aws.BoolValue(input.ConsistentRead), func(db *dynamodb.DynamoDB, seen func()) error {
+		return db.QueryPagesWithContext(ctx, input, func(page *dynamodb.QueryOutput, last bool) bool {
+			seen()
+			return fn(page, last)
+		}, opts...)
+	})
+}
+
+func (c *Client) Scan(input *dynamodb.ScanInput) (*dynamodb.ScanOutput, error) {
+	return c.ScanWithContext(context.Background(), input)
+}
+
+func (c *Client) ScanWithContext(ctx aws.Context, input *dynamodb.ScanInput, opts ...request.Option) (*dynamodb.ScanOutput, error) {
+	return read(ctx, c, aws.BoolValue(input.ConsistentRead), func(db *dynamodb.DynamoDB) (*dynamodb.ScanOutput, error) {
+		return db.ScanWithContext(ctx, input, opts...)
+	})
+}
+
+func (c *Client) ScanPages(input *dynamodb.ScanInput, fn func(*dynamodb.ScanOutput, bool) bool) error {
+	return c.ScanPagesWithContext(context.Background(), input, fn)
+}
+
+func (c *Client) ScanPagesWithContext(ctx aws.Context, input *dynamodb.ScanInput, fn func(*dynamodb.ScanOutput, bool) bool, opts ...request.Option) error {
+	return readPages(ctx, c, aws.BoolValue(input.ConsistentRead), func(db *dynamodb.DynamoDB, seen func()) error {
+		return db.ScanPagesWithContext(ctx, input, func(page *dynamodb.ScanOutput, last bool) bool {
+			seen()
+			return fn(page, last)
+		}, opts...)
+	})
+}
+
+// readPages is read for paginated calls, which only move to another region before their first
+// page was handed out
+func readPages(ctx context.Context, c *Client, consistent bool, call func(db *dynamodb.DynamoDB, seen func()) error) error {
+	_, err := read(ctx, c, consistent, func(db *dynamodb.DynamoDB) (struct{}, error) {
+		started := false
+		err := call(db, func() { started = true })
+		if err != nil && started {
+			return struct{}{}, &pageError{err}
+		}
+		return struct{}{}, err
+	})
+	var pe *pageError
+	if errors.As(err, &pe) {
+		return pe.err
+	}
+	return err
+}
+
+// pageError is a failure after the first page, which read must not retry in another region
+type pageError struct {
+	err error
+}
+
+func (e *pageError) Error() string {
+	return e.err.Error()
+}
@@ -0,0 +1,46 @@
+// shared/go/pkg/identity/http.go
+package identity
+
+import (
+	"context"
+	"net/http"
+)
+
+// Inject sets the headers of the identity ctx carries on h
+func (p *Propagator) Inject(ctx context.Context, h http.Header) {
+	for key, value := range p.encode(FromContext(ctx)) {
+		h.Set(key, value)
+	}
+}
+
+// Extract reads the identity a calling service sent in h
+func (p *Propagator) Extract(h http.Header) Identity {
+	return p.decode(h.Get)
+}
+
+// Middleware puts the identity a request was sent with in its context
+func (p *Propagator) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		next.ServeHTTP(w, r.WithContext(NewContext(r.Context(), p.Extract(r.Header))))
+	})
+}
+
+// Transport wraps base, http.DefaultTransport when nil, sending the identity of each
+// request's context along with it
+func (p *Propagator) Transport(base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &transport{propagator: p, base: base}
+}
+
+type transport struct {
+	propagator *Propagator
+	base       http.RoundTripper
+}
+
+func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	t.propagator.Inject(req.Context(), req.Header)
+	return t.base.RoundTrip(req)
+}
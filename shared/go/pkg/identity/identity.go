@@ -0,0 +1,184 @@
+// shared/go/pkg/identity/identity.go
+package identity
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Header names the identity travels under. gRPC metadata uses them lower cased.
+const (
+	HeaderUserID    = "X-Identity-User-ID"
+	HeaderUsername  = "X-Identity-Username"
+	HeaderRoles     = "X-Identity-Roles"
+	HeaderIssuedAt  = "X-Identity-Issued-At"
+	HeaderSignature = "X-Identity-Signature"
+	HeaderRequestID = "X-Request-ID"
+)
+
+// Roles the platform grants users, carried in their tokens
+const (
+	RoleAdmin          = "admin"
+	RoleTrustAndSafety = "trust_and_safety"
+)
+
+// maxAge is how long a signed identity is trusted after it was issued, so one lifted from a
+// log can't be replayed for long
+const maxAge = 5 * time.Minute
+
+// Identity is who a request acts for, resolved once at the edge and passed along to every
+// service the request reaches so they don't resolve the user again
+type Identity struct {
+	UserID    string   `json:"user_id,omitempty"`
+	Username  string   `json:"username,omitempty"` // empty when the edge didn't know it
+	Roles     []string `json:"roles,omitempty"`
+	RequestID string   `json:"request_id,omitempty"`
+}
+
+// Authenticated reports whether the request acts for a user
+func (id Identity) Authenticated() bool {
+	return id.UserID != ""
+}
+
+func (id Identity) HasRole(role string) bool {
+	return slices.Contains(id.Roles, role)
+}
+
+// HasAnyRole reports whether the user was granted one of roles
+func (id Identity) HasAnyRole(roles ...string) bool {
+	for _, role := range roles {
+		if id.HasRole(role) {
+			return true
+		}
+	}
+	return false
+}
+
+type contextKey struct{}
+
+// NewContext returns ctx carrying id
+func NewContext(ctx context.Context, id Identity) context.Context {
+	return context.WithValue(ctx, contextKey{}, id)
+}
+
+// FromContext returns the identity ctx carries, the zero identity for anonymous requests
+func FromContext(ctx context.Context) Identity {
+	id, _ := ctx.Value(contextKey{}).(Identity)
+	return id
+}
+
+// WithRequestID returns ctx with the request ID of its identity set
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	id := FromContext(ctx)
+	id.RequestID = requestID
+	return NewContext(ctx, id)
+}
+
+// WithUser returns ctx acting for a user, keeping its request ID
+func WithUser(ctx context.Context, userID, username string, roles []string) context.Context {
+	id := FromContext(ctx)
+	id.UserID = userID
+	id.Username = username
+	id.Roles = roles
+	return NewContext(ctx, id)
+}
+
+// UserID returns the user ctx acts for, empty for anonymous requests
+func UserID(ctx context.Context) string {
+	return FromContext(ctx).UserID
+}
+
+// RequestID returns the ID of the request ctx belongs to
+func RequestID(ctx context.Context) string {
+	return FromContext(ctx).RequestID
+}
+
+// Propagator passes identities between services in HTTP headers and gRPC metadata. With a
+// secret the user is signed, and a service only trusts users signed with the same secret.
+// Without one every user passed in is trusted, which only suits development.
+type Propagator struct {
+	secret []byte
+	now    func() time.Time
+}
+
+func NewPropagator(secret string) *Propagator {
+	p := &Propagator{now: time.Now}
+	if secret != "" {
+		p.secret = []byte(secret)
+	}
+	return p
+}
+
+// Signed reports whether users are signed, and so whether one that came in from outside the
+// platform could have been forged
+func (p *Propagator) Signed() bool {
+	return p.secret != nil
+}
+
+// encode returns the values of the headers id is sent in
+func (p *Propagator) encode(id Identity) map[string]string {
+	values := make(map[string]string)
+	if id.RequestID != "" {
+		values[HeaderRequestID] = id.RequestID
+	}
+	if !id.Authenticated() {
+		return values
+	}
+
+	values[HeaderUserID] = id.UserID
+	if id.Username != "" {
+		values[HeaderUsername] = id.Username
+	}
+	if len(id.Roles) > 0 {
+		values[HeaderRoles] = strings.Join(id.Roles, ",")
+	}
+	if p.secret != nil {
+		issuedAt := strconv.FormatInt(p.now().Unix(), 10)
+		values[HeaderIssuedAt] = issuedAt
+		values[HeaderSignature] = p.sign(id, issuedAt)
+	}
+	return values
+}
+
+// decode reads an identity from header values. The request ID is always taken, the user only
+// when its signature holds.
+func (p *Propagator) decode(get func(key string) string) Identity {
+	id := Identity{RequestID: get(HeaderRequestID)}
+
+	user := Identity{
+		UserID:   get(HeaderUserID),
+		Username: get(HeaderUsername),
+	}
+	if !user.Authenticated() {
+		return id
+	}
+	if roles := get(HeaderRoles); roles != "" {
+		user.Roles = strings.Split(roles, ",")
+	}
+
+	if p.secret != nil {
+		issuedAt := get(HeaderIssuedAt)
+		issued, err := strconv.ParseInt(issuedAt, 10, 64)
+		if err != nil || p.now().Sub(time.Unix(issued, 0)).Abs() > maxAge {
+			return id
+		}
+		if !hmac.Equal([]byte(get(HeaderSignature)), []byte(p.sign(user, issuedAt))) {
+			return id
+		}
+	}
+
+	id.UserID, id.Username, id.Roles = user.UserID, user.Username, user.Roles
+	return id
+}
+
+func (p *Propagator) sign(id Identity, issuedAt string) string {
+	mac := hmac.New(sha256.New, p.secret)
+	mac.Write([]byte(strings.Join([]string{id.UserID, id.Username, strings.Join(id.Roles, ","), issuedAt}, "\n")))
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,132 @@
+// shared/go/pkg/preflight/preflight.go
+// Package preflight checks the dependencies of a service at startup. Depending on the mode a
+// failed check stops the service or disables the features that depend on it.
+package preflight
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+)
+
+const (
+	// ModeStrict stops the service when a required dependency is unavailable
+	ModeStrict = "strict"
+	// ModeDegrade keeps running and disables the features that depend on a failed check
+	ModeDegrade = "degrade"
+	// ModeOff skips the preflight entirely
+	ModeOff = "off"
+)
+
+// Check is a single startup dependency check
+type Check struct {
+	Name     string       // e.g. "dynamodb"
+	Feature  string       // what stops working if the check fails
+	Required bool         // whether the service can run at all without it
+	Hint     string       // how to fix a failure
+	Run      func() error // returns nil when the dependency is usable
+	Disable  func(error)  // called on failure of an optional check to switch the feature off
+}
+
+// Result is the outcome of a single check
+type Result struct {
+	Name     string `json:"name"`
+	Feature  string `json:"feature"`
+	Required bool   `json:"required"`
+	OK       bool   `json:"ok"`
+	Error    string `json:"error,omitempty"`
+	Hint     string `json:"hint,omitempty"`
+	Duration int64  `json:"duration_ms"`
+}
+
+// Report collects the results of a preflight run
+type Report struct {
+	Mode    string   `json:"mode"`
+	Results []Result `json:"results"`
+}
+
+// Run executes every check in order and disables the features of failed optional checks
+func Run(mode string, checks []Check) *Report {
+	report := &Report{Mode: mode}
+	if mode == ModeOff {
+		slog.Info("⏭️ Preflight checks disabled")
+		return report
+	}
+
+	slog.Info("🛫 Running preflight checks...", "checks", len(checks), "mode", mode)
+
+	for _, check := range checks {
+		start := time.Now()
+		err := check.Run()
+
+		result := Result{
+			Name:     check.Name,
+			Feature:  check.Feature,
+			Required: check.Required,
+			OK:       err == nil,
+			Duration: time.Since(start).Milliseconds(),
+		}
+
+		if err != nil {
+			result.Error = err.Error()
+			result.Hint = check.Hint
+
+			if check.Required {
+				slog.Error("❌ Preflight check failed", "check", check.Name, "error", err, "hint", check.Hint)
+			} else {
+				slog.Warn("⚠️ Preflight check failed, disabling feature", "check", check.Name, "feature", check.Feature, "error", err, "hint", check.Hint)
+				if check.Disable != nil {
+					check.Disable(err)
+				}
+			}
+		} else {
+			slog.Info("✅ Preflight check ok", "check", check.Name, "duration", time.Since(start).Round(time.Millisecond))
+		}
+
+		report.Results = append(report.Results, result)
+	}
+
+	return report
+}
+
+// RequiredFailures returns the failed checks the service cannot run without
+func (r *Report) RequiredFailures() []Result {
+	var failures []Result
+	for _, result := range r.Results {
+		if !result.OK && result.Required {
+			failures = append(failures, result)
+		}
+	}
+	return failures
+}
+
+// DisabledFeatures returns the features switched off because their check failed
+func (r *Report) DisabledFeatures() []string {
+	var features []string
+	for _, result := range r.Results {
+		if !result.OK && !result.Required {
+			features = append(features, result.Feature)
+		}
+	}
+	return features
+}
+
+// Err summarises required failures in a single actionable error, or returns nil
+func (r *Report) Err() error {
+	failures := r.RequiredFailures()
+	if len(failures) == 0 {
+		return nil
+	}
+
+	var lines []string
+	for _, failure := range failures {
+		line := fmt.Sprintf("%s: %s", failure.Name, failure.Error)
+		if failure.Hint != "" {
+			line += " (" + failure.Hint + ")"
+		}
+		lines = append(lines, line)
+	}
+
+	return fmt.Errorf("preflight failed for %d required dependencies:\n  - %s", len(failures), strings.Join(lines, "\n  - "))
+}